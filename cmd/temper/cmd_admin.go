@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/storage/migrate"
+)
+
+func cmdAdmin(args []string) error {
+	if len(args) < 1 {
+		fmt.Println(`Admin commands:
+
+  temper admin prune [--dry-run] [--sessions-days N] [--runs-days N]
+                                Delete history older than the retention policy
+  temper admin migrate [--dry-run] [--no-backup] [--json]
+                                Upgrade ~/.temper data to this version's format`)
+		return nil
+	}
+
+	switch args[0] {
+	case "prune":
+		return cmdAdminPrune(args[1:])
+	case "migrate":
+		return cmdAdminMigrate(args[1:])
+	default:
+		return fmt.Errorf("unknown admin command: %s", args[0])
+	}
+}
+
+func cmdAdminPrune(args []string) error {
+	fs := flag.NewFlagSet("admin prune", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "report what would be deleted without deleting")
+	sessionsDays := fs.Int("sessions-days", -1, "override retention.sessions_days (0 keeps sessions forever)")
+	runsDays := fs.Int("runs-days", -1, "override retention.runs_days (0 keeps runs forever)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	req := map[string]interface{}{"dry_run": *dryRun}
+	if *sessionsDays >= 0 {
+		req["sessions_days"] = *sessionsDays
+	}
+	if *runsDays >= 0 {
+		req["runs_days"] = *runsDays
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := daemonPost(daemonAddr+"/v1/admin/prune", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("prune: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "prune")
+	}
+
+	var report struct {
+		DryRun   bool     `json:"dry_run"`
+		Sessions []string `json:"sessions"`
+		Runs     int      `json:"runs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	verb := "Deleted"
+	if report.DryRun {
+		verb = "Would delete"
+	}
+	fmt.Printf("%s %d sessions and %d runs\n", verb, len(report.Sessions), report.Runs)
+	for _, id := range report.Sessions {
+		fmt.Printf("  session %s\n", id)
+	}
+	return nil
+}
+
+func cmdAdminMigrate(args []string) error {
+	fs := flag.NewFlagSet("admin migrate", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "show what would change without changing it")
+	noBackup := fs.Bool("no-backup", false, "skip the backup archive taken before data changes")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// The daemon holds the database open and writes sessions as it goes
+	if !*dryRun && isRunning() {
+		return fmt.Errorf("stop the daemon first ('temper stop'), then migrate")
+	}
+
+	cfg, err := config.LoadLocalConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	dir, err := config.TemperDir()
+	if err != nil {
+		return err
+	}
+
+	report, err := migrate.Run(dir, migrate.Options{Storage: cfg.Storage, DryRun: *dryRun, Backup: !*noBackup}, time.Now())
+	if err != nil {
+		if report != nil && report.Backup != "" {
+			fmt.Fprintf(os.Stderr, "Backup before the failed migration: %s\n", report.Backup)
+		}
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	if !report.Pending() {
+		fmt.Printf("Data is up to date (version %d)\n", report.To)
+		return nil
+	}
+	verb := "Migrated"
+	if report.DryRun {
+		verb = "Would migrate"
+	}
+	fmt.Printf("%s data from version %d to %d\n", verb, report.From, report.To)
+	if n := len(report.SchemaPending); n > 0 {
+		fmt.Printf("  apply %d database schema migrations\n", n)
+	}
+	for _, step := range report.Steps {
+		for _, change := range step.Changes {
+			fmt.Printf("  %d %s: %s\n", step.Version, step.Name, change)
+		}
+	}
+	if report.Backup != "" {
+		fmt.Printf("Backup: %s\n", report.Backup)
+	}
+	return nil
+}
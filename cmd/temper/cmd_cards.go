@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// card mirrors the fields of cards.Card the CLI shows
+type card struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Front     string    `json:"front"`
+	Back      string    `json:"back"`
+	Exercises []string  `json:"exercises"`
+	DueAt     time.Time `json:"due_at"`
+}
+
+// cmdCards manages flashcards made from session mistakes and explanations
+//
+//	temper cards                      # due and upcoming cards
+//	temper cards generate             # add cards from recent sessions
+//	temper cards review               # go through the due cards
+//	temper cards export -format apkg -out temper.apkg
+func cmdCards(args []string) error {
+	sub := ""
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	switch sub {
+	case "", "list":
+		return cmdCardsList()
+	case "generate":
+		return cmdCardsGenerate()
+	case "review":
+		return cmdCardsReview()
+	case "export":
+		return cmdCardsExport(args)
+	default:
+		return fmt.Errorf("unknown cards command: %s (valid: list, generate, review, export)", sub)
+	}
+}
+
+func fetchCards(dueOnly bool) ([]card, int, error) {
+	path := "/v1/cards"
+	if dueOnly {
+		path += "?due=true"
+	}
+	resp, err := daemonGet(daemonAddr + path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get cards: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := authError(resp); err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, 0, responseError(resp, "get cards")
+	}
+
+	var result struct {
+		Cards []card `json:"cards"`
+		Due   int    `json:"due"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, 0, fmt.Errorf("parse response: %w", err)
+	}
+	return result.Cards, result.Due, nil
+}
+
+func cmdCardsList() error {
+	cards, due, err := fetchCards(false)
+	if err != nil {
+		return err
+	}
+	if len(cards) == 0 {
+		fmt.Println("No cards yet. Run 'temper cards generate' after a few sessions.")
+		return nil
+	}
+
+	fmt.Printf("%d card(s), %d due\n\n", len(cards), due)
+	now := time.Now()
+	for _, c := range cards {
+		when := "due now"
+		if c.DueAt.After(now) {
+			when = "due " + c.DueAt.Local().Format("2006-01-02")
+		}
+		fmt.Printf("  %-8s %-12s %s\n", c.Kind, when, cardLabel(c.Front))
+	}
+	return nil
+}
+
+func cmdCardsGenerate() error {
+	resp, err := daemonPost(daemonAddr+"/v1/cards/generate", "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("generate cards: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "generate cards")
+	}
+
+	var result struct {
+		Added []card `json:"added"`
+		Total int    `json:"total"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	fmt.Printf("Added %d new card(s); %d in total from your sessions.\n", len(result.Added), result.Total)
+	for _, c := range result.Added {
+		fmt.Printf("  %-8s %s\n", c.Kind, cardLabel(c.Front))
+	}
+	return nil
+}
+
+// cmdCardsReview shows each due card's question, waits, shows the answer
+// and records whether it was remembered
+func cmdCardsReview() error {
+	cards, _, err := fetchCards(true)
+	if err != nil {
+		return err
+	}
+	if len(cards) == 0 {
+		fmt.Println("No cards due.")
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for i, c := range cards {
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(cards), c.Front)
+		fmt.Print("\nPress Enter to show the answer (q to stop) ")
+		line, err := reader.ReadString('\n')
+		if err != nil || strings.TrimSpace(line) == "q" {
+			return nil
+		}
+		fmt.Printf("\n%s\n\nDid you remember it? [y/n/q] ", c.Back)
+
+		line, err = reader.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		if err != nil || answer == "q" {
+			return nil
+		}
+		body, _ := json.Marshal(map[string]bool{"remembered": answer == "y" || answer == "yes"})
+		resp, err := daemonPost(daemonAddr+"/v1/cards/"+url.PathEscape(c.ID)+"/review", "application/json", bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("review card: %w", err)
+		}
+		if resp.StatusCode != 200 {
+			err := responseError(resp, "review card")
+			_ = resp.Body.Close()
+			return err
+		}
+		var next card
+		_ = json.NewDecoder(resp.Body).Decode(&next)
+		_ = resp.Body.Close()
+		fmt.Printf("Next review %s\n", next.DueAt.Local().Format("2006-01-02"))
+	}
+	return nil
+}
+
+func cmdCardsExport(args []string) error {
+	fs := flag.NewFlagSet("cards export", flag.ContinueOnError)
+	format := fs.String("format", "tsv", "tsv (Anki text import) or apkg (Anki package)")
+	out := fs.String("out", "", "output file (default: stdout for tsv, temper-cards.apkg for apkg)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" && *format == "apkg" {
+		*out = "temper-cards.apkg"
+	}
+
+	resp, err := daemonGet(daemonAddr + "/v1/cards/export?format=" + url.QueryEscape(*format))
+	if err != nil {
+		return fmt.Errorf("export cards: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "export cards")
+	}
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.OpenFile(*out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return fmt.Errorf("open %s: %w", *out, err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+	if *out != "" {
+		fmt.Fprintf(os.Stderr, "exported cards to %s; import it in Anki with File > Import\n", *out)
+	}
+	return nil
+}
+
+// cardLabel is the last line of a card's question: the error for mistake
+// cards, the question itself for concept cards
+func cardLabel(front string) string {
+	lines := strings.Split(strings.TrimSpace(front), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
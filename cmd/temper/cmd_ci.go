@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/felixgeelhaar/temper/internal/daemon"
+	"github.com/felixgeelhaar/temper/internal/spec"
+)
+
+func cmdCI(args []string) error {
+	if len(args) < 1 {
+		fmt.Println(`CI commands:
+
+  temper ci verify [--spec PATH] [--require-complete] [--workspace DIR] [--format json|text]
+                                Verify specs in a pipeline without a running daemon`)
+		return nil
+	}
+
+	switch args[0] {
+	case "verify":
+		return cmdCIVerify(args[1:])
+	default:
+		return fmt.Errorf("unknown ci command: %s", args[0])
+	}
+}
+
+// ciVerifyResult is what `temper ci verify` prints
+type ciVerifyResult struct {
+	Passed bool                 `json:"passed"`
+	Specs  []*spec.VerifyReport `json:"specs"`
+}
+
+// cmdCIVerify verifies specs for a pipeline: validity, drift from the
+// latest lock and criteria claimed without evidence. It serves the daemon
+// from this process for the length of the command, so no `temper start`,
+// Docker or LLM provider is needed, and exits non-zero when a spec fails.
+//
+//	temper ci verify --spec .specs/auth.yaml
+//	temper ci verify --require-complete --format text
+func cmdCIVerify(args []string) error {
+	fs := flag.NewFlagSet("ci verify", flag.ContinueOnError)
+	specPath := fs.String("spec", "", "spec to verify (default: every spec in the workspace)")
+	requireComplete := fs.Bool("require-complete", false, "also fail on criteria not yet satisfied")
+	workspace := fs.String("workspace", ".", "workspace holding the specs and .temper.yaml")
+	format := fs.String("format", "json", "output format: json or text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "json" && *format != "text" {
+		return fmt.Errorf("unknown format %q (use json or text)", *format)
+	}
+
+	root, err := filepath.Abs(*workspace)
+	if err != nil {
+		return fmt.Errorf("resolve workspace: %w", err)
+	}
+
+	// Keep stdout for the results and stderr for what went wrong
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+
+	eph, err := daemon.StartEphemeral(context.Background(), root)
+	if err != nil {
+		return fmt.Errorf("start embedded daemon: %w", err)
+	}
+	defer func() { _ = eph.Close() }()
+	daemonAddr = "http://" + eph.Addr
+	daemonAuthToken = eph.Token
+
+	body, _ := json.Marshal(map[string]any{
+		"spec_path":        *specPath,
+		"require_complete": *requireComplete,
+		"workspace_root":   root,
+	})
+	resp, err := daemonPost(daemonAddr+"/v1/specs/verify", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("verify specs: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "verify specs")
+	}
+
+	var result ciVerifyResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	if *format == "text" {
+		printCIVerify(os.Stdout, &result)
+	} else {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, r := range result.Specs {
+		if !r.Passed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d spec(s) failed verification", failed, len(result.Specs))
+	}
+	return nil
+}
+
+// printCIVerify writes one line per spec and criterion, for build logs
+func printCIVerify(w io.Writer, result *ciVerifyResult) {
+	if len(result.Specs) == 0 {
+		fmt.Fprintln(w, "No specs found")
+		return
+	}
+	for _, r := range result.Specs {
+		status := "PASS"
+		if !r.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(w, "%s %s\n", status, r.SpecPath)
+		for _, c := range r.Criteria {
+			fmt.Fprintf(w, "  [%s] %s %s\n", c.Status, c.ID, c.Description)
+		}
+		for _, f := range r.Failures {
+			fmt.Fprintf(w, "  - %s\n", f)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/spec"
+)
+
+func TestPrintCIVerify(t *testing.T) {
+	var buf bytes.Buffer
+	printCIVerify(&buf, &ciVerifyResult{Specs: []*spec.VerifyReport{{
+		SpecPath: ".specs/auth.yaml",
+		Failures: []string{"criterion ac-2 is marked satisfied without evidence"},
+		Criteria: []spec.CriterionResult{
+			{ID: "ac-1", Description: "Login works", Status: spec.CriterionVerified},
+			{ID: "ac-2", Description: "Logout works", Status: spec.CriterionUnverified},
+		},
+	}}})
+
+	out := buf.String()
+	for _, want := range []string{"FAIL .specs/auth.yaml", "[verified] ac-1", "[unverified] ac-2", "- criterion ac-2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+func cmdCohort(args []string) error {
+	if len(args) < 1 {
+		fmt.Println(`Cohort commands:
+
+  temper cohort list            List cohorts
+  temper cohort add <cohort> <export.jsonl>
+                                Import a member's "temper stats export" file
+  temper cohort leaderboard <cohort> [--sort completed|streak|velocity] [--anonymize]
+                                Rank the members who opted in
+  temper cohort assign <cohort> <exercise-id> [--spec path] [--track name] [--members id,...]
+                     [--due time] [--grace duration] [--late-policy flag|lock]
+                                Create a session for every member
+  temper cohort assignments <cohort>
+                                Show who has started and finished assigned work
+
+Members opt in when they export:
+
+  temper stats export -leaderboard -name ada -salt <cohort> -out ada.jsonl`)
+		return nil
+	}
+
+	switch args[0] {
+	case "list":
+		return cmdCohortList()
+	case "add":
+		return cmdCohortAdd(args[1:])
+	case "leaderboard":
+		return cmdCohortLeaderboard(args[1:])
+	case "assign":
+		return cmdCohortAssign(args[1:])
+	case "assignments":
+		return cmdCohortAssignments(args[1:])
+	default:
+		return fmt.Errorf("unknown cohort command: %s", args[0])
+	}
+}
+
+func cmdCohortList() error {
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	resp, err := daemonGet(daemonAddr + "/v1/cohorts")
+	if err != nil {
+		return fmt.Errorf("list cohorts: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "list cohorts")
+	}
+
+	var result struct {
+		Cohorts []string `json:"cohorts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if len(result.Cohorts) == 0 {
+		fmt.Println("No cohorts yet. Import an export with: temper cohort add <cohort> <file>")
+		return nil
+	}
+	for _, id := range result.Cohorts {
+		fmt.Println(id)
+	}
+	return nil
+}
+
+func cmdCohortAdd(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: temper cohort add <cohort> <export.jsonl>")
+	}
+	data, err := os.ReadFile(args[1])
+	if err != nil {
+		return fmt.Errorf("read export: %w", err)
+	}
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	resp, err := daemonPost(daemonAddr+"/v1/cohorts/"+url.PathEscape(args[0])+"/members",
+		"application/x-ndjson", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("add member: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 201 {
+		return responseError(resp, "add member")
+	}
+
+	var result struct {
+		ProfileID   string `json:"profile_id"`
+		Leaderboard bool   `json:"leaderboard"`
+		Attempts    int    `json:"attempts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	fmt.Printf("Added %s to %s (%d attempts)\n", result.ProfileID, args[0], result.Attempts)
+	if !result.Leaderboard {
+		fmt.Println("This member did not opt in to the leaderboard; their stats only count toward benchmarks.")
+	}
+	return nil
+}
+
+func cmdCohortLeaderboard(args []string) error {
+	fs := flag.NewFlagSet("cohort leaderboard", flag.ContinueOnError)
+	sortBy := fs.String("sort", "completed", "rank by completed, streak or velocity")
+	anonymize := fs.Bool("anonymize", false, "hide display names")
+
+	// Allow flags after the cohort: temper cohort leaderboard go-101 --sort streak
+	var id string
+	if len(args) > 0 && len(args[0]) > 0 && args[0][0] != '-' {
+		id, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if id == "" && fs.NArg() > 0 {
+		id = fs.Arg(0)
+	}
+	if id == "" {
+		return fmt.Errorf("usage: temper cohort leaderboard <cohort>")
+	}
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("sort", *sortBy)
+	if *anonymize {
+		params.Set("anonymize", "true")
+	}
+
+	resp, err := daemonGet(daemonAddr + "/v1/cohorts/" + url.PathEscape(id) + "/leaderboard?" + params.Encode())
+	if err != nil {
+		return fmt.Errorf("get leaderboard: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "get leaderboard")
+	}
+
+	type stats struct {
+		ExercisesCompleted int      `json:"exercises_completed"`
+		CurrentStreak      int      `json:"current_streak"`
+		BestStreak         int      `json:"best_streak"`
+		Velocity           *float64 `json:"velocity"`
+	}
+	var result struct {
+		Entries []struct {
+			Rank int    `json:"rank"`
+			Name string `json:"name"`
+			stats
+		} `json:"entries"`
+		Members   int `json:"members"`
+		Benchmark *struct {
+			You        stats              `json:"you"`
+			Median     stats              `json:"median"`
+			Percentile map[string]float64 `json:"percentile"`
+		} `json:"benchmark"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	fmt.Printf("%s leaderboard (%d of %d members opted in)\n\n", id, len(result.Entries), result.Members)
+	if len(result.Entries) > 0 {
+		fmt.Printf("%4s  %-20s %9s %8s %8s %9s\n", "RANK", "NAME", "COMPLETED", "STREAK", "BEST", "VELOCITY")
+		for _, e := range result.Entries {
+			fmt.Printf("%4d  %-20s %9d %8d %8d %9s\n",
+				e.Rank, e.Name, e.ExercisesCompleted, e.CurrentStreak, e.BestStreak, formatVelocity(e.Velocity))
+		}
+	}
+
+	if b := result.Benchmark; b != nil && result.Members > 0 {
+		fmt.Println("\nYou vs the cohort:")
+		fmt.Printf("  Completed:    %d (median %d, at or ahead of %.0f%%)\n",
+			b.You.ExercisesCompleted, b.Median.ExercisesCompleted, b.Percentile["completed"])
+		fmt.Printf("  Best streak:  %d (median %d, at or ahead of %.0f%%)\n",
+			b.You.BestStreak, b.Median.BestStreak, b.Percentile["streak"])
+		fmt.Printf("  Velocity:     %s (median %s)\n", formatVelocity(b.You.Velocity), formatVelocity(b.Median.Velocity))
+	}
+	return nil
+}
+
+func cmdCohortAssign(args []string) error {
+	fs := flag.NewFlagSet("cohort assign", flag.ContinueOnError)
+	specPath := fs.String("spec", "", "assign a spec instead of an exercise")
+	track := fs.String("track", "", "learning track for the sessions")
+	members := fs.String("members", "", "comma-separated profile IDs (default: everyone)")
+	due := fs.String("due", "", `deadline, RFC 3339 or local "2006-01-02 15:04"`)
+	grace := fs.Duration("grace", 0, "how long after the deadline runs still count as on time")
+	latePolicy := fs.String("late-policy", "", "after the deadline: flag runs late (default) or lock the sessions")
+
+	// Allow flags after the arguments: temper cohort assign go-101 go-v1/basics/hello --track practice
+	var positional []string
+	for len(args) > 0 && len(args[0]) > 0 && args[0][0] != '-' {
+		positional, args = append(positional, args[0]), args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	positional = append(positional, fs.Args()...)
+	if len(positional) < 1 || (len(positional) < 2) == (*specPath == "") {
+		return fmt.Errorf("usage: temper cohort assign <cohort> <exercise-id> | --spec <path>")
+	}
+
+	body := map[string]interface{}{}
+	if len(positional) > 1 {
+		body["exercise_id"] = positional[1]
+	}
+	if *specPath != "" {
+		body["spec_path"] = *specPath
+	}
+	if *track != "" {
+		body["track"] = *track
+	}
+	if *members != "" {
+		body["members"] = strings.Split(*members, ",")
+	}
+	if *due != "" {
+		dueAt, err := parseDue(*due)
+		if err != nil {
+			return err
+		}
+		body["deadline"] = map[string]interface{}{
+			"due":           dueAt,
+			"grace_seconds": int(grace.Seconds()),
+			"late_policy":   *latePolicy,
+		}
+	} else if *grace != 0 || *latePolicy != "" {
+		return fmt.Errorf("--grace and --late-policy need --due")
+	}
+	data, _ := json.Marshal(body)
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	resp, err := daemonPost(daemonAddr+"/v1/cohorts/"+url.PathEscape(positional[0])+"/assignments",
+		"application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("assign: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 && resp.StatusCode != 201 {
+		return responseError(resp, "assign")
+	}
+
+	var result struct {
+		Created int `json:"created"`
+		Results []struct {
+			Learner   string `json:"learner"`
+			Name      string `json:"name"`
+			SessionID string `json:"session_id"`
+			Status    string `json:"status"`
+			Error     string `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	fmt.Printf("Created %d of %d sessions in %s\n\n", result.Created, len(result.Results), positional[0])
+	fmt.Printf("%-20s %-9s %s\n", "MEMBER", "STATUS", "SESSION")
+	for _, r := range result.Results {
+		detail := r.SessionID
+		if r.Error != "" {
+			detail = r.Error
+		}
+		fmt.Printf("%-20s %-9s %s\n", memberName(r.Learner, r.Name), r.Status, detail)
+	}
+	return nil
+}
+
+func cmdCohortAssignments(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: temper cohort assignments <cohort>")
+	}
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	resp, err := daemonGet(daemonAddr + "/v1/cohorts/" + url.PathEscape(args[0]) + "/assignments")
+	if err != nil {
+		return fmt.Errorf("list assignments: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "list assignments")
+	}
+
+	var result struct {
+		Assignments []struct {
+			ExerciseID string    `json:"exercise_id"`
+			SpecPath   string    `json:"spec_path"`
+			AssignedAt time.Time `json:"assigned_at"`
+			Total      int       `json:"total"`
+			Started    int       `json:"started"`
+			Completed  int       `json:"completed"`
+			Late       int       `json:"late"`
+			Overdue    int       `json:"overdue"`
+			Deadline   *struct {
+				Due        time.Time `json:"due"`
+				LatePolicy string    `json:"late_policy"`
+			} `json:"deadline"`
+			Learners []struct {
+				Learner   string `json:"learner"`
+				Name      string `json:"name"`
+				Status    string `json:"status"`
+				Started   bool   `json:"started"`
+				RunCount  int    `json:"run_count"`
+				HintCount int    `json:"hint_count"`
+				Late      bool   `json:"late"`
+				Overdue   bool   `json:"overdue"`
+			} `json:"learners"`
+		} `json:"assignments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if len(result.Assignments) == 0 {
+		fmt.Println("Nothing assigned yet. Assign work with: temper cohort assign <cohort> <exercise-id>")
+		return nil
+	}
+	for i, a := range result.Assignments {
+		if i > 0 {
+			fmt.Println()
+		}
+		work := a.ExerciseID
+		if work == "" {
+			work = a.SpecPath
+		}
+		fmt.Printf("%s (assigned %s): %d of %d started, %d completed\n",
+			work, a.AssignedAt.Local().Format("2006-01-02"), a.Started, a.Total, a.Completed)
+		if a.Deadline != nil {
+			fmt.Printf("  due %s: %d late, %d overdue\n", a.Deadline.Due.Local().Format("2006-01-02 15:04"), a.Late, a.Overdue)
+		}
+		for _, l := range a.Learners {
+			state := l.Status
+			if !l.Started && l.Status == "active" {
+				state = "not started"
+			}
+			var flags []string
+			if l.Late {
+				flags = append(flags, "late")
+			}
+			if l.Overdue {
+				flags = append(flags, "overdue")
+			}
+			fmt.Printf("  %-20s %-12s %3d runs %3d hints %s\n", memberName(l.Learner, l.Name), state, l.RunCount, l.HintCount,
+				strings.Join(flags, ", "))
+		}
+	}
+	return nil
+}
+
+// parseDue reads a deadline given as RFC 3339 or as local time without
+// seconds
+func parseDue(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf(`invalid --due %q: use RFC 3339 or "2006-01-02 15:04"`, s)
+}
+
+// memberName shows a member's display name, or their profile ID
+func memberName(id, name string) string {
+	if name != "" {
+		return name
+	}
+	return id
+}
+
+// formatVelocity shows velocity as a signed percentage, or "-" before
+// there are enough completions to measure it
+func formatVelocity(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.0f%%", *v)
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// command describes a CLI command for completion and the command palette
+type command struct {
+	name    string
+	summary string
+	subs    []command
+	flags   []string
+	arg     string // "spec" or "exercise": complete the positional argument
+	palette bool   // runnable without arguments, offered by the palette
+}
+
+// valueFlags take a value, so the next word is not completed
+var valueFlags = map[string]bool{
+	"--feature": true, "--github": true, "--jira": true,
+	"--out": true, "--since": true, "--salt": true, "--format": true,
+	"--kind": true, "--limit": true,
+	"--sessions-days": true, "--runs-days": true,
+	"--provider": true, "--api-key-env": true, "--runner": true,
+	"--dir": true, "--go-version": true,
+	"--name": true, "--sort": true, "--interval": true,
+	"--intent": true, "--port": true, "--token": true, "--data-dir": true,
+	"--scope": true,
+}
+
+func specCommand(name, summary string, flags ...string) command {
+	return command{name: name, summary: summary, arg: "spec", flags: flags}
+}
+
+var commandTree = []command{
+	{name: "init", summary: "Initialize Temper (first-time setup)", palette: true,
+		flags: []string{"--non-interactive", "--provider", "--api-key-env", "--runner"}},
+	{name: "doctor", summary: "Check system requirements", palette: true},
+	{name: "config", summary: "Show current configuration", palette: true, subs: []command{
+		{name: "locale", summary: "Show or set the language for hints and CLI output", palette: true},
+	}},
+	{name: "provider", summary: "Manage LLM providers", subs: []command{
+		{name: "list", summary: "List configured providers", palette: true},
+		{name: "set-key", summary: "Set API key for a provider"},
+	}},
+	{name: "runner", summary: "Manage the runner image", subs: []command{
+		{name: "pull", summary: "Pull the runner image", flags: []string{"--pin"}, palette: true},
+		{name: "verify", summary: "Check the runner image's Go toolchain", palette: true},
+	}},
+	{name: "admin", summary: "Maintenance commands", subs: []command{
+		{name: "prune", summary: "Delete history past the retention policy", flags: []string{"--dry-run", "--sessions-days", "--runs-days"}},
+		{name: "migrate", summary: "Upgrade ~/.temper data to this version's format", flags: []string{"--dry-run", "--no-backup", "--json"}},
+	}},
+	{name: "profile", summary: "Manage the learning profile", subs: []command{
+		{name: "reset", summary: "Erase learning history, or a topic or date range of it", flags: []string{"--topic", "--since", "--until", "--dry-run", "--yes"}},
+	}},
+	{name: "devcontainer", summary: "Devcontainer setup", subs: []command{
+		{name: "generate", summary: "Write a devcontainer for VS Code and Codespaces",
+			flags: []string{"--dir", "--provider", "--api-key-env", "--go-version", "--force"}},
+	}},
+	{name: "start", summary: "Start the Temper daemon", palette: true, flags: []string{"--foreground", "--embedded"}},
+	{name: "stop", summary: "Stop the Temper daemon", palette: true},
+	{name: "status", summary: "Show daemon status", palette: true},
+	{name: "logs", summary: "View daemon logs", palette: true},
+	{name: "ui", summary: "Open the web dashboard", palette: true},
+	{name: "exercise", summary: "Browse exercises", subs: []command{
+		{name: "list", summary: "List all exercise packs", palette: true},
+		{name: "info", summary: "Show exercise details", arg: "exercise"},
+		{name: "solution", summary: "Show the reference solution", arg: "exercise", flags: []string{"--force"}},
+		{name: "seal", summary: "Encrypt solutions in exercise files"},
+		{name: "install", summary: "Install a pack from a URL or git repository"},
+		{name: "lint", summary: "Check a pack before publishing it"},
+		{name: "updates", summary: "List staged pack updates", flags: []string{"--check"}},
+		{name: "update", summary: "Apply a pack's staged update"},
+		{name: "rollback", summary: "Roll a pack back to its previous version"},
+	}},
+	{name: "spec", summary: "Manage product specs", subs: []command{
+		{name: "create", summary: "Create a new spec scaffold"},
+		{name: "import", summary: "Create a spec from an issue or ticket", flags: []string{"--github", "--jira", "--overwrite"}},
+		{name: "list", summary: "List specs in workspace", palette: true},
+		specCommand("validate", "Validate spec completeness"),
+		specCommand("review", "AI critique: ambiguity, gaps, conflicts"),
+		specCommand("status", "Show spec progress", "--feature"),
+		specCommand("sync", "Report satisfied criteria to the source issue"),
+		specCommand("plan", "Order features by their dependencies"),
+		specCommand("diagram", "Component diagram of the features", "--c4", "--write"),
+		specCommand("lock", "Generate SpecLock for drift detection"),
+		specCommand("drift", "Show drift from locked spec"),
+		specCommand("history", "Show how the spec changed between locks"),
+	}},
+	{name: "hook", summary: "Git hooks that check specs", subs: []command{
+		{name: "install", summary: "Install a pre-commit hook for spec drift and criteria", flags: []string{"--pre-push", "--force"}},
+		{name: "check", summary: "Check specs the way the hook does", flags: []string{"--all"}},
+	}},
+	{name: "ci", summary: "Pipeline commands", subs: []command{
+		{name: "verify", summary: "Verify specs without a running daemon", flags: []string{"--spec", "--require-complete", "--workspace", "--format"}},
+	}},
+	{name: "stats", summary: "Show learning statistics", palette: true, subs: []command{
+		{name: "overview", summary: "Learning statistics overview", palette: true},
+		{name: "skills", summary: "Skill progression by topic", palette: true},
+		{name: "errors", summary: "Common error patterns", palette: true},
+		{name: "trend", summary: "Hint dependency over time", palette: true},
+		{name: "exercises", summary: "Calibrate exercise difficulty", flags: []string{"--cohort", "--pack", "--min-attempts", "--miscalibrated"}},
+		{name: "pack", summary: "Summarize strengths and gaps across a pack", flags: []string{"--json"}},
+		{name: "achievements", summary: "Milestones earned and still to earn", flags: []string{"--json"}, palette: true},
+		{name: "export", summary: "Export anonymized attempts", flags: []string{"--out", "--since", "--salt", "--leaderboard", "--name"}},
+		{name: "publish", summary: "Render a shareable progress page", flags: []string{"--out", "--name", "--fragment"}},
+		{name: "snapshot", summary: "Write an anonymized skill snapshot", flags: []string{"--out"}},
+		{name: "compare", summary: "Compare skill snapshots across a study group", flags: []string{"--no-self", "--json"}},
+	}},
+	{name: "cohort", summary: "Cohort leaderboards and assignments", subs: []command{
+		{name: "list", summary: "List cohorts", palette: true},
+		{name: "add", summary: "Import a member's stats export"},
+		{name: "leaderboard", summary: "Rank a cohort's members", flags: []string{"--sort", "--anonymize"}},
+		{name: "assign", summary: "Create a session for every member", flags: []string{"--spec", "--track", "--members", "--due", "--grace", "--late-policy"}},
+		{name: "assignments", summary: "Show who has started assigned work"},
+	}},
+	{name: "history", summary: "Search past activity", subs: []command{
+		{name: "search", summary: "Search past sessions, run output and hints", flags: []string{"--kind", "--limit"}},
+	}},
+	{name: "cards", summary: "Flashcards from session mistakes", palette: true, subs: []command{
+		{name: "list", summary: "Show due and upcoming cards"},
+		{name: "generate", summary: "Add cards from recent sessions", palette: true},
+		{name: "review", summary: "Go through the due cards"},
+		{name: "export", summary: "Export cards for Anki", flags: []string{"--format", "--out"}},
+	}},
+	{name: "remind", summary: "Practice streak and due reviews", palette: true, subs: []command{
+		{name: "watch", summary: "Show practice reminders as desktop notifications", flags: []string{"--interval", "--once"}},
+	}},
+	{name: "prompt", summary: "Inspect pairing prompts", subs: []command{
+		{name: "preview", summary: "Show the prompt a request would send, without calling the LLM", flags: []string{"--intent", "--json"}, palette: true},
+	}},
+	{name: "review", summary: "Review a session's code", flags: []string{"--format", "--out"}},
+	{name: "replay", summary: "Re-ask a session's hints with another provider and diff them", flags: []string{"--provider", "--json", "--quiet"}},
+	{name: "token", summary: "Scoped tokens for dashboards and scripts", subs: []command{
+		{name: "create", summary: "Create a scoped token", flags: []string{"--scope", "--name"}},
+		{name: "list", summary: "List scoped tokens"},
+		{name: "revoke", summary: "Revoke a scoped token"},
+	}},
+	{name: "completion", summary: "Generate shell completion", subs: []command{
+		{name: "bash", summary: "Bash completion script"},
+		{name: "zsh", summary: "Zsh completion script"},
+		{name: "fish", summary: "Fish completion script"},
+	}},
+	{name: "mcp", summary: "Start MCP server (for Cursor integration)"},
+	{name: "mockd", summary: "Serve the daemon API with canned responses", flags: []string{"--port", "--token", "--data-dir"}},
+	{name: "help", summary: "Show help"},
+	{name: "version", summary: "Show version information"},
+}
+
+func findCommand(cmds []command, name string) *command {
+	for i := range cmds {
+		if cmds[i].name == name {
+			return &cmds[i]
+		}
+	}
+	return nil
+}
+
+func commandNames(cmds []command) []string {
+	names := make([]string, len(cmds))
+	for i, c := range cmds {
+		names[i] = c.name
+	}
+	return names
+}
+
+// cmdCompletion prints a completion script for the given shell
+func cmdCompletion(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: temper completion bash|zsh|fish")
+	}
+
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion)
+	case "zsh":
+		fmt.Print(zshCompletion)
+	case "fish":
+		fmt.Print(fishCompletion)
+	default:
+		return fmt.Errorf("unsupported shell: %s (want bash, zsh or fish)", args[0])
+	}
+	return nil
+}
+
+// The scripts hand the words typed so far to the hidden __complete command,
+// so the command tree lives in one place.
+const bashCompletion = `# bash completion for temper
+# Add to ~/.bashrc: source <(temper completion bash)
+_temper() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    COMPREPLY=($(compgen -W "$(temper __complete "${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null)" -- "$cur"))
+}
+complete -o default -F _temper temper
+`
+
+const zshCompletion = `#compdef temper
+# Add to ~/.zshrc after compinit: source <(temper completion zsh)
+_temper() {
+    local -a candidates
+    candidates=("${(@f)$(temper __complete "${(@)words[2,CURRENT-1]}" 2>/dev/null)}")
+    compadd -a candidates
+}
+compdef _temper temper
+`
+
+const fishCompletion = `# fish completion for temper
+# Save to ~/.config/fish/completions/temper.fish
+complete -c temper -f -a '(temper __complete (commandline -opc)[2..-1])'
+`
+
+// cmdComplete prints one completion candidate per line for the words
+// already typed. The shell filters them against the current word.
+func cmdComplete(words []string) error {
+	for _, candidate := range completeWords(words, completeArg) {
+		fmt.Println(candidate)
+	}
+	return nil
+}
+
+// completeWords walks the command tree along words. Positional arguments
+// are looked up with lookup, so tests can avoid the daemon and filesystem.
+func completeWords(words []string, lookup func(kind string) []string) []string {
+	if len(words) == 0 {
+		return commandNames(commandTree)
+	}
+	if valueFlags[words[len(words)-1]] {
+		return nil
+	}
+
+	cmd := findCommand(commandTree, words[0])
+	if cmd == nil {
+		return nil
+	}
+	if len(cmd.subs) > 0 {
+		if len(words) == 1 {
+			return commandNames(cmd.subs)
+		}
+		if cmd = findCommand(cmd.subs, words[1]); cmd == nil {
+			return nil
+		}
+	}
+
+	candidates := append([]string(nil), cmd.flags...)
+	if cmd.arg != "" {
+		candidates = append(candidates, lookup(cmd.arg)...)
+	}
+	return candidates
+}
+
+// completeArg lists candidates for a positional argument
+func completeArg(kind string) []string {
+	switch kind {
+	case "spec":
+		return localSpecPaths()
+	case "exercise":
+		return exerciseIDs()
+	default:
+		return nil
+	}
+}
+
+// localSpecPaths globs the workspace so spec paths complete without the daemon
+func localSpecPaths() []string {
+	var paths []string
+	for _, pattern := range []string{".specs/*.yaml", ".specs/*.yml"} {
+		matches, _ := filepath.Glob(pattern)
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// exerciseIDs asks a running daemon for every exercise ID. Completion
+// must stay quiet, so any failure yields no candidates.
+func exerciseIDs() []string {
+	if !isRunning() {
+		return nil
+	}
+
+	resp, err := daemonGet(daemonAddr + "/v1/exercises")
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var packs struct {
+		Packs []struct {
+			ID string `json:"id"`
+		} `json:"packs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&packs); err != nil {
+		return nil
+	}
+
+	var ids []string
+	for _, pack := range packs.Packs {
+		ids = append(ids, packExerciseIDs(pack.ID)...)
+	}
+	return ids
+}
+
+func packExerciseIDs(packID string) []string {
+	resp, err := daemonGet(daemonAddr + "/v1/exercises/" + packID)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var result struct {
+		Exercises []struct {
+			ID string `json:"id"`
+		} `json:"exercises"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(result.Exercises))
+	for _, ex := range result.Exercises {
+		ids = append(ids, ex.ID)
+	}
+	return ids
+}
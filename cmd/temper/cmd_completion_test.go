@@ -0,0 +1,56 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func fakeLookup(kind string) []string {
+	return []string{"<" + kind + ">"}
+}
+
+func TestCompleteWords(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  string
+	}{
+		{nil, "init"},
+		{[]string{"spec"}, "diagram"},
+		{[]string{"spec", "status"}, "--feature"},
+		{[]string{"spec", "status"}, "<spec>"},
+		{[]string{"spec", "diagram", ".specs/app.yaml"}, "--c4"},
+		{[]string{"exercise", "info"}, "<exercise>"},
+		{[]string{"completion"}, "fish"},
+	}
+	for _, tt := range tests {
+		got := completeWords(tt.words, fakeLookup)
+		if !slices.Contains(got, tt.want) {
+			t.Errorf("completeWords(%v) = %v, want %q among them", tt.words, got, tt.want)
+		}
+	}
+}
+
+func TestCompleteWords_Nothing(t *testing.T) {
+	for _, words := range [][]string{
+		{"spec", "status", "--feature"}, // value expected
+		{"bogus"},
+		{"spec", "bogus"},
+		{"spec", "list"},
+	} {
+		if got := completeWords(words, fakeLookup); len(got) != 0 {
+			t.Errorf("completeWords(%v) = %v, want none", words, got)
+		}
+	}
+}
+
+func TestCmdCompletion_Scripts(t *testing.T) {
+	for shell, script := range map[string]string{"bash": bashCompletion, "zsh": zshCompletion, "fish": fishCompletion} {
+		if !strings.Contains(script, "temper __complete") {
+			t.Errorf("%s script should delegate to __complete", shell)
+		}
+	}
+	if err := cmdCompletion([]string{"powershell"}); err == nil {
+		t.Error("unsupported shell should error")
+	}
+}
@@ -3,21 +3,36 @@ package main
 import (
 	"bufio"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/felixgeelhaar/temper/exercises"
 	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/daemon"
+	"github.com/felixgeelhaar/temper/internal/session"
 )
 
-// cmdStart starts the daemon in the background
-func cmdStart() error {
+// cmdStart starts the daemon, in the background unless --foreground.
+// --embedded runs the daemon inside this binary instead of temperd; it
+// is also the fallback when temperd cannot be found.
+func cmdStart(args []string) error {
+	fs := flag.NewFlagSet("start", flag.ContinueOnError)
+	foreground := fs.Bool("foreground", false, "run in this terminal instead of in the background")
+	embedded := fs.Bool("embedded", false, "run the daemon inside temper instead of temperd")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
 	// Check if already running
 	if isRunning() {
 		fmt.Println("✓ Daemon is already running")
@@ -30,14 +45,36 @@ func cmdStart() error {
 		return fmt.Errorf("setup temper directory: %w", err)
 	}
 
-	// Find temperd binary
-	temperdPath, err := findDaemonBinary()
-	if err != nil {
-		return fmt.Errorf("find daemon binary: %w", err)
+	// Find temperd binary, or fall back to the embedded daemon
+	var temperdPath string
+	if !*embedded {
+		temperdPath, err = findDaemonBinary()
+		if err != nil {
+			fmt.Println("temperd not found; running the daemon embedded in temper")
+			*embedded = true
+		}
+	}
+
+	if *foreground {
+		if *embedded {
+			return daemon.Run(daemon.RunOptions{Bundle: exercises.FS})
+		}
+		cmd := exec.Command(temperdPath)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
 	}
 
 	// Start daemon in background
 	cmd := exec.Command(temperdPath)
+	if *embedded {
+		self, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("locate temper binary: %w", err)
+		}
+		cmd = exec.Command(self, "start", "--foreground", "--embedded")
+	}
 	cmd.Dir = temperDir
 	cmd.Stdout = nil
 	cmd.Stderr = nil
@@ -53,6 +90,7 @@ func cmdStart() error {
 	fmt.Print("Starting daemon...")
 	for i := 0; i < 30; i++ {
 		time.Sleep(100 * time.Millisecond)
+		discoverDaemon()
 		if isRunning() {
 			fmt.Println(" ✓")
 			fmt.Printf("Daemon running at %s\n", daemonAddr)
@@ -72,23 +110,11 @@ func cmdStop() error {
 		return nil
 	}
 
-	// Read PID file
-	temperDir, err := config.TemperDir()
+	pid, _, err := readDaemonPID()
 	if err != nil {
 		return err
 	}
 
-	pidPath := filepath.Join(temperDir, pidFile)
-	data, err := os.ReadFile(pidPath)
-	if err != nil {
-		return fmt.Errorf("read PID file: %w", err)
-	}
-
-	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
-	if err != nil {
-		return fmt.Errorf("parse PID: %w", err)
-	}
-
 	// Send SIGTERM
 	process, err := os.FindProcess(pid)
 	if err != nil {
@@ -133,6 +159,22 @@ func cmdStatus() error {
 		Version      string   `json:"version"`
 		LLMProviders []string `json:"llm_providers"`
 		Runner       string   `json:"runner"`
+		Quotas       []struct {
+			Provider     string  `json:"provider"`
+			InputTokens  int     `json:"input_tokens"`
+			OutputTokens int     `json:"output_tokens"`
+			Cost         float64 `json:"cost"`
+			Level        string  `json:"level"`
+			Limits       struct {
+				SoftTokens int     `json:"soft_tokens"`
+				HardTokens int     `json:"hard_tokens"`
+				SoftCost   float64 `json:"soft_cost"`
+				HardCost   float64 `json:"hard_cost"`
+				AtHardCap  string  `json:"at_hard_cap"`
+			} `json:"limits"`
+		} `json:"quotas"`
+		PackUpdates  []packUpdate          `json:"pack_updates"`
+		Capabilities map[string]capability `json:"capabilities"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
@@ -145,9 +187,128 @@ func cmdStatus() error {
 	fmt.Printf("Providers: %s\n", strings.Join(status.LLMProviders, ", "))
 	fmt.Printf("Address:   %s\n", daemonAddr)
 
+	if len(status.Capabilities) > 0 {
+		fmt.Println("\nCapabilities:")
+		for _, feature := range []string{"runs", "pairing", "specs", "analytics"} {
+			c, ok := status.Capabilities[feature]
+			if !ok {
+				continue
+			}
+			if c.Reason != "" {
+				fmt.Printf("  %-10s %s (%s)\n", feature, c.Mode, c.Reason)
+			} else {
+				fmt.Printf("  %-10s %s\n", feature, c.Mode)
+			}
+		}
+	}
+
+	for _, q := range status.Quotas {
+		used := fmt.Sprintf("%d tokens", q.InputTokens+q.OutputTokens)
+		if q.Limits.SoftCost > 0 || q.Limits.HardCost > 0 {
+			used += fmt.Sprintf(", $%.2f", q.Cost)
+		}
+		switch q.Level {
+		case "soft":
+			fmt.Printf("\n⚠ %s is past its soft cap this month (%s)\n", q.Provider, used)
+		case "hard":
+			if q.Limits.AtHardCap == "local" {
+				fmt.Printf("\n⚠ %s reached its hard cap this month (%s); hints use the local model\n", q.Provider, used)
+			} else {
+				fmt.Printf("\n⚠ %s reached its hard cap this month (%s) and is blocked\n", q.Provider, used)
+			}
+		}
+	}
+
+	printPackUpdates(status.PackUpdates)
+	printDeadlines()
+	return nil
+}
+
+// printDeadlines lists the active sessions with an assignment deadline,
+// soonest first. Failures are left out: the daemon status is already shown.
+func printDeadlines() {
+	resp, err := daemonGet(daemonAddr + "/v1/sessions?status=active")
+	if err != nil {
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	var result struct {
+		Sessions []paletteSession `json:"sessions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return
+	}
+	var due []paletteSession
+	for _, sess := range result.Sessions {
+		if sess.Assignment != nil && sess.Assignment.Deadline != nil {
+			due = append(due, sess)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].Assignment.Deadline.Due.Before(due[j].Assignment.Deadline.Due)
+	})
+
+	now := time.Now()
+	fmt.Println("\nAssignments:")
+	for _, sess := range due {
+		work := sess.ExerciseID
+		if work == "" {
+			work = sess.SpecPath
+		}
+		fmt.Printf("  %-8s %-32s %s\n", shortID(sess.ID), work, formatDeadline(sess.Assignment.Deadline, now))
+	}
+}
+
+// formatDeadline shows when work is due and where the deadline stands
+func formatDeadline(d *session.Deadline, now time.Time) string {
+	due := d.Due.Local().Format("2006-01-02 15:04")
+	switch d.State(now) {
+	case session.DeadlineOpen:
+		return fmt.Sprintf("due %s (in %s)", due, d.Due.Sub(now).Round(time.Minute))
+	case session.DeadlineGrace:
+		return fmt.Sprintf("due %s (grace period ends in %s)", due, d.Cutoff().Sub(now).Round(time.Minute))
+	case session.DeadlineLocked:
+		return fmt.Sprintf("due %s (closed; runs are locked)", due)
+	default:
+		return fmt.Sprintf("due %s (past due; runs are marked late)", due)
+	}
+}
+
+// cmdUI opens the daemon's web dashboard in the browser
+func cmdUI() error {
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	uiURL := daemonAddr + "/ui/"
+	fmt.Printf("Dashboard: %s\n", uiURL)
+	if err := openBrowser(uiURL); err != nil {
+		fmt.Println("Open the address above in your browser.")
+	}
 	return nil
 }
 
+// openBrowser opens url with the platform's default handler
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
 // cmdLogs shows daemon logs
 func cmdLogs() error {
 	temperDir, err := config.TemperDir()
@@ -203,6 +364,83 @@ func isRunning() bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// hungGrace is how long a daemon may run without answering health checks
+// before it counts as hung; it covers a daemon that is still starting.
+const hungGrace = 10 * time.Second
+
+// requireDaemon makes sure the daemon answers. A daemon that crashed or
+// hung (its PID file is still there) is restarted instead of failing the
+// command; one that was stopped on purpose is left alone.
+func requireDaemon() error {
+	if isRunning() {
+		return nil
+	}
+
+	pid, age, err := readDaemonPID()
+	if err != nil {
+		return fmt.Errorf("daemon not running (run 'temper start' first)")
+	}
+
+	switch daemonState(pid, age, daemon.ProcessAlive) {
+	case "crashed":
+		fmt.Fprintln(os.Stderr, "Daemon crashed; restarting...")
+	case "hung":
+		fmt.Fprintln(os.Stderr, "Daemon is not responding; restarting...")
+		if process, err := os.FindProcess(pid); err == nil {
+			_ = process.Kill()
+		}
+	default:
+		return fmt.Errorf("daemon is starting, try again in a moment")
+	}
+
+	if err := cmdStart(nil); err != nil {
+		return fmt.Errorf("restart daemon: %w", err)
+	}
+	if !isRunning() {
+		return fmt.Errorf("daemon failed to restart (check logs with 'temper logs')")
+	}
+	return nil
+}
+
+// daemonState classifies a daemon that fails its health check from its
+// PID file: "crashed" when the process is gone, "hung" when it is alive
+// past hungGrace, and "starting" otherwise.
+func daemonState(pid int, pidAge time.Duration, alive func(int) bool) string {
+	switch {
+	case !alive(pid):
+		return "crashed"
+	case pidAge > hungGrace:
+		return "hung"
+	default:
+		return "starting"
+	}
+}
+
+// readDaemonPID returns the PID recorded by the daemon and how long ago it
+// was written
+func readDaemonPID() (int, time.Duration, error) {
+	temperDir, err := config.TemperDir()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pidPath := filepath.Join(temperDir, pidFile)
+	info, err := os.Stat(pidPath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("read PID file: %w", err)
+	}
+	data, err := os.ReadFile(pidPath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("read PID file: %w", err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, 0, fmt.Errorf("parse PID: %w", err)
+	}
+	return pid, time.Since(info.ModTime()), nil
+}
+
 // findDaemonBinary locates the temperd binary
 func findDaemonBinary() (string, error) {
 	// Check if temperd is in PATH
@@ -233,5 +471,5 @@ func findDaemonBinary() (string, error) {
 		}
 	}
 
-	return "", fmt.Errorf("temperd binary not found (build with 'go build ./cmd/temperd')")
+	return "", fmt.Errorf("temperd binary not found (build with 'go build ./cmd/temperd', or use 'temper start --embedded')")
 }
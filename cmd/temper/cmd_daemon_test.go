@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDaemonState(t *testing.T) {
+	alive := func(int) bool { return true }
+	dead := func(int) bool { return false }
+
+	tests := []struct {
+		name  string
+		age   time.Duration
+		alive func(int) bool
+		want  string
+	}{
+		{"process gone", time.Minute, dead, "crashed"},
+		{"gone while starting", time.Second, dead, "crashed"},
+		{"alive but silent", time.Minute, alive, "hung"},
+		{"still starting", time.Second, alive, "starting"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daemonState(1234, tt.age, tt.alive); got != tt.want {
+				t.Errorf("daemonState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
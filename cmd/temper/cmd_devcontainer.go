@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+)
+
+const temperModule = "github.com/felixgeelhaar/temper"
+
+// vscodeExtensionID is the marketplace ID of editors/vscode
+const vscodeExtensionID = "felixgeelhaar.temper"
+
+// devcontainerOptions shape the generated container
+type devcontainerOptions struct {
+	dir       string // output directory, usually .devcontainer
+	goVersion string // Go toolchain of the base image
+	version   string // temper release to install
+	provider  string // passed to temper init --provider
+	apiKeyEnv string // host variable forwarded for temper init --api-key-env
+	force     bool
+}
+
+// cmdDevcontainer manages devcontainer configuration
+func cmdDevcontainer(args []string) error {
+	if len(args) < 1 {
+		fmt.Println(`Devcontainer commands:
+
+  temper devcontainer generate      Write .devcontainer/ for VS Code and Codespaces
+    --dir <path>                    Output directory (default .devcontainer)
+    --provider <name>               LLM provider for temper init
+    --api-key-env <VAR>             Forward this variable as the provider's API key
+    --go-version <version>          Go toolchain in the container (default 1.25)
+    --force                         Overwrite existing files`)
+		return nil
+	}
+
+	switch args[0] {
+	case "generate":
+		return cmdDevcontainerGenerate(args[1:])
+	default:
+		return fmt.Errorf("unknown devcontainer command: %s", args[0])
+	}
+}
+
+func cmdDevcontainerGenerate(args []string) error {
+	opts := devcontainerOptions{version: Version}
+	fs := flag.NewFlagSet("devcontainer generate", flag.ContinueOnError)
+	fs.StringVar(&opts.dir, "dir", ".devcontainer", "output directory")
+	fs.StringVar(&opts.provider, "provider", "", "LLM provider for temper init")
+	fs.StringVar(&opts.apiKeyEnv, "api-key-env", "", "host variable holding the provider's API key")
+	fs.StringVar(&opts.goVersion, "go-version", "1.25", "Go toolchain in the container")
+	fs.BoolVar(&opts.force, "force", false, "overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// Reuse init's checks so the container never fails on first start
+	if err := (initOptions{provider: opts.provider, apiKeyEnv: opts.apiKeyEnv}).validate(); err != nil {
+		return err
+	}
+
+	files, err := devcontainerFiles(opts)
+	if err != nil {
+		return err
+	}
+
+	if !opts.force {
+		for name := range files {
+			path := filepath.Join(opts.dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+			}
+		}
+	}
+	if err := os.MkdirAll(opts.dir, 0755); err != nil {
+		return fmt.Errorf("create %s: %w", opts.dir, err)
+	}
+	for _, name := range []string{"devcontainer.json", "Dockerfile"} {
+		path := filepath.Join(opts.dir, name)
+		if err := os.WriteFile(path, files[name], 0644); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+		fmt.Printf("Wrote %s\n", path)
+	}
+
+	fmt.Println()
+	fmt.Println("Open the folder in VS Code and choose 'Reopen in Container',")
+	fmt.Println("or push it and create a Codespace. The daemon starts with the container.")
+	if opts.apiKeyEnv != "" {
+		fmt.Printf("Set %s on the host (or as a Codespaces secret) before the first build.\n", opts.apiKeyEnv)
+	}
+	return nil
+}
+
+// devcontainerFiles renders devcontainer.json and its Dockerfile
+func devcontainerFiles(opts devcontainerOptions) (map[string][]byte, error) {
+	version := opts.version
+	if version == "" || version == "dev" {
+		version = "latest"
+	}
+
+	dockerfile := fmt.Sprintf(`# Generated by 'temper devcontainer generate'
+FROM mcr.microsoft.com/devcontainers/go:1-%s-bookworm
+
+# temper and temperd; temperd needs cgo for SQLite, which this image provides
+RUN go install %s/cmd/temper@%s \
+    && go install %s/cmd/temperd@%s
+`, opts.goVersion, temperModule, version, temperModule, version)
+
+	initCmd := []string{"temper", "init", "--non-interactive"}
+	if opts.provider != "" {
+		initCmd = append(initCmd, "--provider", opts.provider)
+	}
+	var remoteEnv map[string]string
+	if opts.apiKeyEnv != "" {
+		initCmd = append(initCmd, "--api-key-env", opts.apiKeyEnv)
+		remoteEnv = map[string]string{opts.apiKeyEnv: "${localEnv:" + opts.apiKeyEnv + "}"}
+	}
+
+	type build struct {
+		Dockerfile string `json:"dockerfile"`
+	}
+	type vscode struct {
+		Extensions []string               `json:"extensions"`
+		Settings   map[string]interface{} `json:"settings"`
+	}
+	type customizations struct {
+		VSCode vscode `json:"vscode"`
+	}
+	manifest := struct {
+		Name     string                            `json:"name"`
+		Build    build                             `json:"build"`
+		Features map[string]map[string]interface{} `json:"features"`
+		// The daemon runs from ~/.temper; point it at the mounted workspace
+		ContainerEnv      map[string]string `json:"containerEnv"`
+		RemoteEnv         map[string]string `json:"remoteEnv,omitempty"`
+		PostCreateCommand string            `json:"postCreateCommand"`
+		PostStartCommand  string            `json:"postStartCommand"`
+		Customizations    customizations    `json:"customizations"`
+	}{
+		Name:  "Temper",
+		Build: build{Dockerfile: "Dockerfile"},
+		Features: map[string]map[string]interface{}{
+			// Runs share the host's Docker daemon; code is copied in, so
+			// no host paths are needed
+			"ghcr.io/devcontainers/features/docker-outside-of-docker:1": {},
+		},
+		ContainerEnv:      map[string]string{config.WorkspaceEnv: "${containerWorkspaceFolder}"},
+		RemoteEnv:         remoteEnv,
+		PostCreateCommand: strings.Join(initCmd, " ") + " && temper runner pull",
+		PostStartCommand:  "temper start",
+		Customizations: customizations{VSCode: vscode{
+			Extensions: []string{vscodeExtensionID, "golang.go"},
+			Settings: map[string]interface{}{
+				// The extension must run next to the daemon, inside the container,
+				// where it finds the daemon's address in ~/.temper/daemon.json
+				"remote.extensionKind": map[string][]string{vscodeExtensionID: {"workspace"}},
+			},
+		}},
+	}
+
+	// Keep "&&" readable instead of \u0026\u0026
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return nil, fmt.Errorf("render devcontainer.json: %w", err)
+	}
+
+	return map[string][]byte{
+		"devcontainer.json": data.Bytes(),
+		"Dockerfile":        []byte(dockerfile),
+	}, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDevcontainerFiles(t *testing.T) {
+	files, err := devcontainerFiles(devcontainerOptions{
+		goVersion: "1.25",
+		version:   "v0.9.0",
+		provider:  "claude",
+		apiKeyEnv: "ANTHROPIC_API_KEY",
+	})
+	if err != nil {
+		t.Fatalf("devcontainerFiles() error = %v", err)
+	}
+
+	var manifest struct {
+		Build struct {
+			Dockerfile string `json:"dockerfile"`
+		} `json:"build"`
+		ContainerEnv      map[string]string `json:"containerEnv"`
+		RemoteEnv         map[string]string `json:"remoteEnv"`
+		PostCreateCommand string            `json:"postCreateCommand"`
+		PostStartCommand  string            `json:"postStartCommand"`
+		Customizations    struct {
+			VSCode struct {
+				Extensions []string `json:"extensions"`
+			} `json:"vscode"`
+		} `json:"customizations"`
+	}
+	if err := json.Unmarshal(files["devcontainer.json"], &manifest); err != nil {
+		t.Fatalf("devcontainer.json is not valid JSON: %v", err)
+	}
+
+	if manifest.Build.Dockerfile != "Dockerfile" {
+		t.Errorf("dockerfile = %q", manifest.Build.Dockerfile)
+	}
+	if manifest.ContainerEnv["TEMPER_WORKSPACE"] != "${containerWorkspaceFolder}" {
+		t.Errorf("containerEnv = %v", manifest.ContainerEnv)
+	}
+	if manifest.RemoteEnv["ANTHROPIC_API_KEY"] != "${localEnv:ANTHROPIC_API_KEY}" {
+		t.Errorf("remoteEnv = %v", manifest.RemoteEnv)
+	}
+	if want := "temper init --non-interactive --provider claude --api-key-env ANTHROPIC_API_KEY && temper runner pull"; manifest.PostCreateCommand != want {
+		t.Errorf("postCreateCommand = %q, want %q", manifest.PostCreateCommand, want)
+	}
+	if !strings.Contains(string(files["devcontainer.json"]), "&& temper runner pull") {
+		t.Error("commands should not be HTML-escaped")
+	}
+	if manifest.PostStartCommand != "temper start" {
+		t.Errorf("postStartCommand = %q", manifest.PostStartCommand)
+	}
+	if len(manifest.Customizations.VSCode.Extensions) == 0 || manifest.Customizations.VSCode.Extensions[0] != vscodeExtensionID {
+		t.Errorf("extensions = %v", manifest.Customizations.VSCode.Extensions)
+	}
+
+	dockerfile := string(files["Dockerfile"])
+	for _, want := range []string{"go:1-1.25-bookworm", "cmd/temper@v0.9.0", "cmd/temperd@v0.9.0"} {
+		if !strings.Contains(dockerfile, want) {
+			t.Errorf("Dockerfile missing %q:\n%s", want, dockerfile)
+		}
+	}
+}
+
+func TestDevcontainerFiles_DevBuild(t *testing.T) {
+	files, err := devcontainerFiles(devcontainerOptions{goVersion: "1.25", version: "dev"})
+	if err != nil {
+		t.Fatalf("devcontainerFiles() error = %v", err)
+	}
+	if !strings.Contains(string(files["Dockerfile"]), "cmd/temperd@latest") {
+		t.Errorf("dev builds should install the latest release:\n%s", files["Dockerfile"])
+	}
+	if strings.Contains(string(files["devcontainer.json"]), "remoteEnv") {
+		t.Error("no key variable means no remoteEnv")
+	}
+}
@@ -13,7 +13,17 @@ func cmdExercise(args []string) error {
 		fmt.Println(`Exercise commands:
 
   temper exercise list              List all exercise packs
-  temper exercise info <pack/slug>  Show exercise details`)
+  temper exercise info <pack/slug>  Show exercise details
+  temper exercise solution <pack/slug> [--force]
+                                    Show the reference solution (after completing it)
+  temper exercise seal <file.yaml>...
+                                    Encrypt the solution section of exercise files
+  temper exercise install <url>     Install a pack from an archive URL or a git repository
+                                    (git+https://host/repo.git, optionally @tag or @commit)
+  temper exercise lint <pack-dir>   Check a pack the way install does
+  temper exercise updates [--check] List staged pack updates (--check looks now)
+  temper exercise update <pack>     Switch a pack to its staged update
+  temper exercise rollback <pack>   Switch a pack back to the version before its update`)
 		return nil
 	}
 
@@ -25,14 +35,35 @@ func cmdExercise(args []string) error {
 			return fmt.Errorf("exercise ID required (e.g., go-v1/hello-world)")
 		}
 		return cmdExerciseInfo(args[1])
+	case "solution":
+		return cmdExerciseSolution(args[1:])
+	case "seal":
+		return cmdExerciseSeal(args[1:])
+	case "install":
+		if len(args) < 2 {
+			return fmt.Errorf("pack URL required (e.g., git+https://git.example.com/acme/packs.git@v1.0.0)")
+		}
+		return cmdExerciseInstall(args[1])
+	case "lint":
+		if len(args) < 2 {
+			return fmt.Errorf("pack directory required (e.g., exercises/my-pack)")
+		}
+		return cmdExerciseLint(args[1])
+	case "updates":
+		return cmdExerciseUpdates(args[1:])
+	case "update", "rollback":
+		if len(args) < 2 {
+			return fmt.Errorf("pack ID required (e.g., acme-go)")
+		}
+		return cmdExerciseSwitch(args[0], args[1])
 	default:
 		return fmt.Errorf("unknown exercise command: %s", args[0])
 	}
 }
 
 func cmdExerciseList() error {
-	if !isRunning() {
-		return fmt.Errorf("daemon not running (run 'temper start' first)")
+	if err := requireDaemon(); err != nil {
+		return err
 	}
 
 	resp, err := daemonGet(daemonAddr + "/v1/exercises")
@@ -67,13 +98,13 @@ func cmdExerciseList() error {
 }
 
 func cmdExerciseInfo(id string) error {
-	if !isRunning() {
-		return fmt.Errorf("daemon not running (run 'temper start' first)")
+	if err := requireDaemon(); err != nil {
+		return err
 	}
 
 	parts := strings.Split(id, "/")
 	if len(parts) < 2 {
-		return fmt.Errorf("exercise ID must be in format: pack/category/slug (e.g., go-v1/basics/hello-world)")
+		return fmt.Errorf("exercise ID must be in format: [org/]pack/category/slug (e.g., go-v1/basics/hello-world)")
 	}
 
 	// Build URL: /v1/exercises/pack/category/slug
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/exercise"
+)
+
+// packUpdate is a pack version staged by the daemon's update check
+type packUpdate struct {
+	Pack        string    `json:"pack"`
+	URL         string    `json:"url"`
+	FromVersion string    `json:"from_version"`
+	ToVersion   string    `json:"to_version"`
+	StagedAt    time.Time `json:"staged_at"`
+}
+
+// packSource is a pack installed from a URL
+type packSource struct {
+	Pack     string `json:"pack"`
+	URL      string `json:"url"`
+	Version  string `json:"version"`
+	Previous *struct {
+		Version string `json:"version"`
+	} `json:"previous"`
+}
+
+func cmdExerciseInstall(url string) error {
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	body, _ := json.Marshal(map[string]string{"url": url})
+	resp, err := daemonPost(daemonAddr+"/v1/packs", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("install pack: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return responseError(resp, "install pack")
+	}
+	var src packSource
+	if err := json.NewDecoder(resp.Body).Decode(&src); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	fmt.Printf("✓ Installed %s %s from %s\n", src.Pack, src.Version, src.URL)
+	return nil
+}
+
+// cmdExerciseLint checks a pack directory the way installing it does, so
+// authors find problems before publishing. It needs no daemon.
+func cmdExerciseLint(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	base, id := filepath.Dir(abs), filepath.Base(abs)
+	// An org pack lives in its org's directory, which is part of its ID
+	if pack, err := exercise.NewLoader(base).LoadPack(id); err == nil && pack.ID == filepath.Base(base)+"/"+id {
+		base, id = filepath.Dir(base), pack.ID
+	}
+	problems := exercise.NewLoader(base).Lint(id)
+	if len(problems) == 0 {
+		fmt.Printf("✓ %s is ready to publish\n", dir)
+		return nil
+	}
+	fmt.Printf("%s has %d problem(s):\n", dir, len(problems))
+	for _, p := range problems {
+		fmt.Printf("  - %s\n", p)
+	}
+	return fmt.Errorf("pack lint failed")
+}
+
+// cmdExerciseUpdates lists the updates staged for installed packs. With
+// --check the daemon checks every source first instead of waiting for its
+// background check.
+func cmdExerciseUpdates(args []string) error {
+	fs := flag.NewFlagSet("exercise updates", flag.ContinueOnError)
+	check := fs.Bool("check", false, "check every pack source now")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	if *check {
+		resp, err := daemonPost(daemonAddr+"/v1/packs/check", "application/json", nil)
+		if err != nil {
+			return fmt.Errorf("check pack updates: %w", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if err := authError(resp); err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return responseError(resp, "check pack updates")
+		}
+		var result struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+		if result.Error != "" {
+			fmt.Printf("⚠ Some sources could not be checked:\n  %s\n\n", result.Error)
+		}
+	}
+
+	resp, err := daemonGet(daemonAddr + "/v1/packs")
+	if err != nil {
+		return fmt.Errorf("list pack sources: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "list pack sources")
+	}
+	var result struct {
+		Sources []struct {
+			packSource
+			Staged *struct {
+				Version string `json:"version"`
+			} `json:"staged"`
+		} `json:"sources"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if len(result.Sources) == 0 {
+		fmt.Println("No packs installed from a source. Install one with 'temper exercise install <url>'.")
+		return nil
+	}
+	for _, src := range result.Sources {
+		switch {
+		case src.Staged != nil:
+			fmt.Printf("  %-20s %s → %s ready; apply with 'temper exercise update %s'\n", src.Pack, src.Version, src.Staged.Version, src.Pack)
+		default:
+			fmt.Printf("  %-20s %s (up to date)\n", src.Pack, src.Version)
+		}
+		fmt.Printf("  %-20s from %s\n", "", src.URL)
+	}
+	return nil
+}
+
+// cmdExerciseSwitch applies a pack's staged update or rolls it back
+func cmdExerciseSwitch(action, pack string) error {
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	verb := "update pack"
+	if action == "rollback" {
+		verb = "roll back pack"
+	}
+	resp, err := daemonPost(daemonAddr+"/v1/packs/"+pack+"/"+action, "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", verb, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, verb)
+	}
+	var src packSource
+	if err := json.NewDecoder(resp.Body).Decode(&src); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	fmt.Printf("✓ %s is now at %s\n", src.Pack, src.Version)
+	if src.Previous != nil {
+		// Rolling back swaps the two versions, so it undoes either action
+		fmt.Printf("  Undo with 'temper exercise rollback %s' (back to %s)\n", src.Pack, src.Previous.Version)
+	}
+	return nil
+}
+
+// printPackUpdates tells the user about pack updates waiting to be
+// applied
+func printPackUpdates(updates []packUpdate) {
+	for _, u := range updates {
+		fmt.Printf("\n⬆ Pack %s %s is ready (installed: %s); apply with 'temper exercise update %s'\n", u.Pack, u.ToVersion, u.FromVersion, u.Pack)
+	}
+}
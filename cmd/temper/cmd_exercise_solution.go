@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/exercise"
+)
+
+// cmdExerciseSolution prints an exercise's reference solution. The daemon
+// only hands it out once the exercise is completed, unless --force says
+// the learner wants to see it anyway.
+//
+//	temper exercise solution go-v1/basics/hello-world
+//	temper exercise solution go-v1/basics/hello-world --force
+func cmdExerciseSolution(args []string) error {
+	fs := flag.NewFlagSet("exercise solution", flag.ContinueOnError)
+	force := fs.Bool("force", false, "show the solution before completing the exercise")
+
+	var positional []string
+	for len(args) > 0 && len(args[0]) > 0 && args[0][0] != '-' {
+		positional, args = append(positional, args[0]), args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	positional = append(positional, fs.Args()...)
+	if len(positional) != 1 || strings.Count(positional[0], "/") < 2 {
+		return fmt.Errorf("usage: temper exercise solution <pack/category/slug> [--force]")
+	}
+	id := positional[0]
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/solutions/%s", daemonAddr, id)
+	if *force {
+		url += "?force=true"
+	}
+	resp, err := daemonGet(url)
+	if err != nil {
+		return fmt.Errorf("get solution: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("%s isn't completed yet; finish it first, or rerun with --force to spoil it", id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "get solution")
+	}
+
+	var result struct {
+		ExerciseID string            `json:"exercise_id"`
+		Completed  bool              `json:"completed"`
+		Files      map[string]string `json:"files"`
+		Approach   *approachReport   `json:"approach"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	names := make([]string, 0, len(result.Files))
+	for name := range result.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("// %s\n%s", name, result.Files[name])
+		if !strings.HasSuffix(result.Files[name], "\n") {
+			fmt.Println()
+		}
+	}
+	if result.Approach != nil {
+		printApproach(result.Approach)
+	}
+	return nil
+}
+
+// approachComparison and approachReport mirror the daemon's comparison of
+// a finished solution with the exercise's reference and alternatives
+type approachComparison struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Similarity  float64  `json:"similarity"`
+	Untried     []string `json:"untried"`
+}
+
+type approachReport struct {
+	Idioms       []string             `json:"idioms"`
+	Reference    *approachComparison  `json:"reference"`
+	Alternatives []approachComparison `json:"alternatives"`
+}
+
+// printApproach walks through how the learner's solution compares, with
+// the idioms each other solution uses that theirs doesn't
+func printApproach(r *approachReport) {
+	fmt.Println("\nHow your solution compares:")
+	if len(r.Idioms) > 0 {
+		fmt.Printf("  You used: %s\n", strings.Join(r.Idioms, ", "))
+	}
+	comparisons := r.Alternatives
+	if r.Reference != nil {
+		comparisons = append([]approachComparison{*r.Reference}, comparisons...)
+	}
+	for _, c := range comparisons {
+		fmt.Printf("\n  %s: %.0f%% similar\n", c.Name, c.Similarity*100)
+		if c.Description != "" {
+			fmt.Printf("    %s\n", c.Description)
+		}
+		if len(c.Untried) > 0 {
+			fmt.Printf("    Idioms you haven't tried: %s\n", strings.Join(c.Untried, ", "))
+		}
+	}
+}
+
+// cmdExerciseSeal encrypts the solution section of exercise files in
+// place, so a pack's solutions aren't readable at a glance. The pack is
+// the nearest directory above each file with a pack.yaml.
+//
+//	temper exercise seal exercises/go-v1/basics/*.yaml
+func cmdExerciseSeal(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: temper exercise seal <file.yaml>...")
+	}
+	for _, path := range args {
+		packID, err := packOf(path)
+		if err != nil {
+			return err
+		}
+		n, err := exercise.SealSolutionFile(path, packID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if n == 0 {
+			fmt.Printf("%s: nothing to seal\n", path)
+			continue
+		}
+		fmt.Printf("%s: sealed %d solution file(s) for pack %s\n", path, n, packID)
+	}
+	return nil
+}
+
+// packOf returns the ID of the pack an exercise file belongs to: the name
+// of the nearest directory above it that holds a pack.yaml, which is how
+// the loader keys packs
+func packOf(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	for dir := filepath.Dir(abs); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "pack.yaml")); err == nil {
+			return filepath.Base(dir), nil
+		}
+	}
+	return "", fmt.Errorf("%s: no pack.yaml above it", path)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func cmdHistory(args []string) error {
+	if len(args) < 1 {
+		fmt.Println(`History commands:
+
+  temper history search <query> [--kind session|run|intervention] [--limit N]
+                                Search past sessions, run output and hints`)
+		return nil
+	}
+
+	switch args[0] {
+	case "search":
+		return cmdHistorySearch(args[1:])
+	default:
+		return fmt.Errorf("unknown history command: %s", args[0])
+	}
+}
+
+func cmdHistorySearch(args []string) error {
+	fs := flag.NewFlagSet("history search", flag.ContinueOnError)
+	kind := fs.String("kind", "", "restrict to session, run or intervention")
+	limit := fs.Int("limit", 20, "maximum number of results")
+
+	// Allow flags after the query: temper history search "nil map" --limit 5
+	var terms []string
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		args = fs.Args()
+		if len(args) > 0 {
+			terms = append(terms, args[0])
+			args = args[1:]
+		}
+	}
+	query := strings.TrimSpace(strings.Join(terms, " "))
+	if query == "" {
+		return fmt.Errorf("usage: temper history search <query>")
+	}
+	if strings.Contains(query, " ") && !strings.Contains(query, `"`) && len(terms) == 1 {
+		// A single quoted shell argument is meant as a phrase
+		query = `"` + query + `"`
+	}
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(*limit))
+	if *kind != "" {
+		params.Set("kind", *kind)
+	}
+
+	resp, err := daemonGet(daemonAddr + "/v1/search?" + params.Encode())
+	if err != nil {
+		return fmt.Errorf("search: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "search")
+	}
+
+	var result struct {
+		Hits []struct {
+			Kind       string    `json:"kind"`
+			SessionID  string    `json:"session_id"`
+			ExerciseID string    `json:"exercise_id"`
+			Field      string    `json:"field"`
+			Snippet    string    `json:"snippet"`
+			CreatedAt  time.Time `json:"created_at"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if len(result.Hits) == 0 {
+		fmt.Printf("No matches for %s\n", query)
+		return nil
+	}
+
+	for _, hit := range result.Hits {
+		where := hit.ExerciseID
+		if where == "" {
+			where = shortID(hit.SessionID)
+		}
+		fmt.Printf("%s  %-12s %s (%s)\n", hit.CreatedAt.Local().Format("2006-01-02 15:04"), hit.Kind, where, hit.Field)
+		fmt.Printf("    %s\n", hit.Snippet)
+	}
+	return nil
+}
+
+// shortID trims a UUID to its first block for display
+func shortID(id string) string {
+	if i := strings.Index(id, "-"); i > 0 {
+		return id[:i]
+	}
+	return id
+}
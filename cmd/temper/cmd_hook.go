@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/spec"
+)
+
+// hookMarker identifies hooks temper installed, so reinstalling replaces
+// them but never a hook someone else wrote
+const hookMarker = "# Installed by temper hook install"
+
+// hookBypass tells the committer how to get past a failed check
+const hookBypass = `To commit anyway, skip the hooks once with
+  git commit --no-verify      (or git push --no-verify)
+or set TEMPER_SKIP_HOOKS=1 for this shell.`
+
+func cmdHook(args []string) error {
+	if len(args) < 1 {
+		fmt.Println(`Hook commands:
+
+  temper hook install [--pre-push] [--force]
+                                Install a git hook that checks specs before committing
+  temper hook check [--all]     Check locked specs for drift and the staged specs
+                                for criteria claimed without evidence`)
+		return nil
+	}
+
+	switch args[0] {
+	case "install":
+		return cmdHookInstall(args[1:])
+	case "check":
+		return cmdHookCheck(args[1:])
+	default:
+		return fmt.Errorf("unknown hook command: %s", args[0])
+	}
+}
+
+// cmdHookInstall writes the pre-commit (or pre-push) hook of the current
+// repository. It honors core.hooksPath.
+//
+//	temper hook install
+//	temper hook install --pre-push
+func cmdHookInstall(args []string) error {
+	fs := flag.NewFlagSet("hook install", flag.ContinueOnError)
+	prePush := fs.Bool("pre-push", false, "check before pushing instead of before each commit")
+	force := fs.Bool("force", false, "replace an existing hook temper didn't install")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	name := "pre-commit"
+	if *prePush {
+		name = "pre-push"
+	}
+
+	out, err := exec.Command("git", "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return fmt.Errorf("not inside a git repository")
+	}
+	dir := strings.TrimSpace(string(out))
+	path := filepath.Join(dir, name)
+
+	if existing, err := os.ReadFile(path); err == nil && !*force && !bytes.Contains(existing, []byte(hookMarker)) {
+		return fmt.Errorf("%s already exists and wasn't installed by temper (use --force to replace it)", path)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(hookScript(name)), 0755); err != nil {
+		return fmt.Errorf("write hook: %w", err)
+	}
+
+	fmt.Printf("Installed %s\n", path)
+	fmt.Println("Commits are now checked for spec drift and unverified criteria.")
+	fmt.Println("Skip a check with --no-verify, or set TEMPER_SKIP_HOOKS=1.")
+	return nil
+}
+
+// hookScript returns the hook that runs temper hook check. A pre-push
+// hook checks every spec, since the pushed commits are already made.
+func hookScript(name string) string {
+	check := "temper hook check"
+	if name == "pre-push" {
+		check += " --all"
+	}
+	return fmt.Sprintf(`#!/bin/sh
+%s (%s).
+# Remove this file to uninstall.
+
+if [ -n "$TEMPER_SKIP_HOOKS" ]; then
+	exit 0
+fi
+if ! command -v temper >/dev/null 2>&1; then
+	echo "temper: not on PATH, skipping the spec check" >&2
+	exit 0
+fi
+exec %s
+`, hookMarker, name, check)
+}
+
+// cmdHookCheck asks the daemon to check the repository's specs. It exits
+// non-zero when the commit should be blocked. An unreachable daemon only
+// warns, so a stopped daemon never blocks work.
+func cmdHookCheck(args []string) error {
+	fs := flag.NewFlagSet("hook check", flag.ContinueOnError)
+	all := fs.Bool("all", false, "check every spec, not just the staged ones")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return fmt.Errorf("not inside a git repository")
+	}
+	root := strings.TrimSpace(string(out))
+
+	files := []string{}
+	if !*all {
+		out, err := exec.Command("git", "diff", "--cached", "--name-only", "--diff-filter=ACMR").Output()
+		if err != nil {
+			return fmt.Errorf("list staged files: %w", err)
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				files = append(files, line)
+			}
+		}
+		if len(files) == 0 {
+			return nil
+		}
+	}
+
+	if !isRunning() {
+		fmt.Fprintln(os.Stderr, "temper: daemon not running, skipping the spec check (run 'temper start')")
+		return nil
+	}
+
+	body, _ := json.Marshal(map[string]any{"files": files, "workspace_root": root})
+	resp, err := daemonPost(daemonAddr+"/v1/specs/check", "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "temper: daemon unreachable, skipping the spec check: %v\n", err)
+		return nil
+	}
+	defer resp.Body.Close()
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "check specs")
+	}
+
+	var report spec.CheckReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if report.OK {
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, "temper: spec check failed")
+	for _, issue := range report.Issues {
+		switch issue.Kind {
+		case spec.CheckUnverifiedCriterion:
+			fmt.Fprintf(os.Stderr, "  %s: criterion %s %s\n", issue.SpecPath, issue.CriterionID, issue.Detail)
+		default:
+			fmt.Fprintf(os.Stderr, "  %s: %s\n", issue.SpecPath, issue.Detail)
+		}
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, hookBypass)
+	return fmt.Errorf("%d spec issue(s)", len(report.Issues))
+}
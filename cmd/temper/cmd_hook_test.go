@@ -0,0 +1,19 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHookScript(t *testing.T) {
+	script := hookScript("pre-commit")
+	if !strings.HasPrefix(script, "#!/bin/sh\n") || !strings.Contains(script, hookMarker) {
+		t.Errorf("hook lacks shebang or marker:\n%s", script)
+	}
+	if !strings.Contains(script, "TEMPER_SKIP_HOOKS") || !strings.Contains(script, "exec temper hook check\n") {
+		t.Errorf("pre-commit hook should honor TEMPER_SKIP_HOOKS and check staged files:\n%s", script)
+	}
+	if !strings.Contains(hookScript("pre-push"), "temper hook check --all") {
+		t.Error("pre-push hook should check every spec")
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/felixgeelhaar/temper/internal/config"
 	"github.com/felixgeelhaar/temper/internal/exercise"
 	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/felixgeelhaar/temper/internal/locale"
 	mcpserver "github.com/felixgeelhaar/temper/internal/mcp"
 	"github.com/felixgeelhaar/temper/internal/pairing"
 	"github.com/felixgeelhaar/temper/internal/runner"
@@ -71,11 +72,13 @@ func cmdMCP() error {
 	// Initialize runner. Docker is required (per-language sandbox safety
 	// is only ensured by the container boundary).
 	dockerCfg := runner.DockerConfig{
-		BaseImage:  cfg.Runner.Docker.Image,
-		MemoryMB:   int64(cfg.Runner.Docker.MemoryMB),
-		CPULimit:   cfg.Runner.Docker.CPULimit,
-		NetworkOff: cfg.Runner.Docker.NetworkOff,
-		Timeout:    time.Duration(cfg.Runner.Docker.TimeoutSeconds) * time.Second,
+		BaseImage:      cfg.Runner.Docker.Image,
+		Platform:       cfg.Runner.Docker.Platform,
+		ToolchainImage: cfg.Runner.Docker.ToolchainImage,
+		MemoryMB:       int64(cfg.Runner.Docker.MemoryMB),
+		CPULimit:       cfg.Runner.Docker.CPULimit,
+		NetworkOff:     cfg.Runner.Docker.NetworkOff,
+		Timeout:        time.Duration(cfg.Runner.Docker.TimeoutSeconds) * time.Second,
 	}
 	executor, err := runner.NewDockerExecutor(dockerCfg)
 	if err != nil {
@@ -92,6 +95,7 @@ func cmdMCP() error {
 	// Create services
 	sessionService := session.NewService(sessionStore, loader, executor)
 	pairingService := pairing.NewService(registry, cfg.LLM.DefaultProvider)
+	pairingService.SetLocale(locale.Resolve(cfg.Locale))
 
 	// Create MCP server
 	mcpSrv := mcpserver.NewServer(mcpserver.Config{
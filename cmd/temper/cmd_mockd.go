@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/felixgeelhaar/temper/exercises"
+	"github.com/felixgeelhaar/temper/internal/daemon"
+)
+
+// cmdMockd serves the daemon API with canned responses for editor plugin
+// tests. It prints the address it listens on and runs until interrupted.
+func cmdMockd(args []string) error {
+	fs := flag.NewFlagSet("mockd", flag.ContinueOnError)
+	port := fs.Int("port", 0, "port to listen on (0 picks a free one)")
+	token := fs.String("token", "", "bearer token clients must send (default: none)")
+	dataDir := fs.String("data-dir", "", "keep sessions here instead of a temporary directory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return daemon.RunMock(daemon.MockOptions{
+		Bundle:  exercises.FS,
+		Port:    *port,
+		Token:   *token,
+		DataDir: *dataDir,
+		Ready: func(addr string) {
+			fmt.Printf("temper mockd listening on http://%s\n", addr)
+		},
+	})
+}
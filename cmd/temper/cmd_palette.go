@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// paletteRows caps how many matches are listed at once
+const paletteRows = 15
+
+// paletteItem is one pickable entry: a command, exercise, session or spec
+type paletteItem struct {
+	kind   string
+	label  string // matched against the filter
+	detail string
+	run    func() error
+}
+
+// stdinIsTerminal reports whether a person can answer the palette's prompt
+func stdinIsTerminal() bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// cmdPalette is what bare `temper` opens on a terminal: a fuzzy picker
+// over commands, exercises, sessions and specs.
+func cmdPalette() error {
+	return runPalette(os.Stdin, os.Stdout, paletteItems())
+}
+
+func paletteItems() []paletteItem {
+	var items []paletteItem
+	for _, c := range commandTree {
+		if c.palette {
+			items = append(items, commandItem(c.name, c.summary))
+		}
+		for _, sub := range c.subs {
+			if sub.palette {
+				items = append(items, commandItem(c.name+" "+sub.name, sub.summary))
+			}
+		}
+	}
+
+	for _, path := range localSpecPaths() {
+		items = append(items, paletteItem{
+			kind:  "spec",
+			label: path,
+			run:   func() error { return cmdSpecStatus(path) },
+		})
+	}
+
+	// Exercises and sessions come from the daemon
+	if !isRunning() {
+		return items
+	}
+	for _, id := range exerciseIDs() {
+		items = append(items, paletteItem{
+			kind:  "exercise",
+			label: id,
+			run:   func() error { return cmdExerciseInfo(id) },
+		})
+	}
+	items = append(items, sessionItems()...)
+	return items
+}
+
+func commandItem(name, summary string) paletteItem {
+	return paletteItem{
+		kind:   "command",
+		label:  name,
+		detail: summary,
+		run:    func() error { return run(strings.Fields(name)) },
+	}
+}
+
+type paletteSession struct {
+	ID         string    `json:"id"`
+	ExerciseID string    `json:"exercise_id"`
+	Intent     string    `json:"intent"`
+	SpecPath   string    `json:"spec_path"`
+	Status     string    `json:"status"`
+	RunCount   int       `json:"run_count"`
+	HintCount  int       `json:"hint_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	Assignment *session.Assignment `json:"assignment"`
+}
+
+func sessionItems() []paletteItem {
+	resp, err := daemonGet(daemonAddr + "/v1/sessions")
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var result struct {
+		Sessions []paletteSession `json:"sessions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil
+	}
+
+	items := make([]paletteItem, 0, len(result.Sessions))
+	for _, sess := range result.Sessions {
+		subject := sess.ExerciseID
+		if subject == "" {
+			subject = sess.SpecPath
+		}
+		items = append(items, paletteItem{
+			kind:   "session",
+			label:  shortID(sess.ID) + " " + subject,
+			detail: sess.Intent + ", " + sess.UpdatedAt.Format("2006-01-02 15:04"),
+			run:    func() error { printSession(sess); return nil },
+		})
+	}
+	return items
+}
+
+func printSession(sess paletteSession) {
+	fmt.Printf("Session: %s\n\n", sess.ID)
+	fmt.Printf("Intent:   %s\n", sess.Intent)
+	if sess.ExerciseID != "" {
+		fmt.Printf("Exercise: %s\n", sess.ExerciseID)
+	}
+	if sess.SpecPath != "" {
+		fmt.Printf("Spec:     %s\n", sess.SpecPath)
+	}
+	fmt.Printf("Status:   %s\n", sess.Status)
+	fmt.Printf("Runs:     %d | Hints: %d\n", sess.RunCount, sess.HintCount)
+	fmt.Printf("Started:  %s\n", sess.CreatedAt.Format("2006-01-02 15:04"))
+	if sess.Assignment != nil && sess.Assignment.Deadline != nil {
+		fmt.Printf("Due:      %s\n", formatDeadline(sess.Assignment.Deadline, time.Now()))
+	}
+	fmt.Printf("Active:   %s\n", sess.UpdatedAt.Format("2006-01-02 15:04"))
+}
+
+// runPalette shows the items matching the current filter. A number picks
+// a listed item, any other text becomes the new filter, and an empty line
+// (or end of input) quits.
+func runPalette(in io.Reader, out io.Writer, items []paletteItem) error {
+	if len(items) == 0 {
+		fmt.Fprintln(out, "Nothing to pick from. Run 'temper help' for commands.")
+		return nil
+	}
+
+	scanner := bufio.NewScanner(in)
+	query := ""
+	for {
+		matches := filterPalette(items, query)
+		if len(matches) > paletteRows {
+			matches = matches[:paletteRows]
+		}
+
+		fmt.Fprintln(out)
+		if len(matches) == 0 {
+			fmt.Fprintf(out, "No matches for %q\n", query)
+		}
+		for i, item := range matches {
+			line := fmt.Sprintf("%3d. %-9s %s", i+1, item.kind, item.label)
+			if item.detail != "" {
+				line += "  " + item.detail
+			}
+			fmt.Fprintln(out, line)
+		}
+		fmt.Fprint(out, "\nType to filter, a number to pick, Enter to quit\n> ")
+
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			return nil
+		}
+		if n, err := strconv.Atoi(line); err == nil && n >= 1 && n <= len(matches) {
+			fmt.Fprintln(out)
+			return matches[n-1].run()
+		}
+		query = line
+	}
+}
+
+// filterPalette returns the items matching query, best match first.
+// The kind is matched too, so "spec" narrows the list to specs.
+func filterPalette(items []paletteItem, query string) []paletteItem {
+	type scored struct {
+		item  paletteItem
+		score int
+	}
+	var hits []scored
+	for _, item := range items {
+		if score, ok := fuzzyScore(query, item.kind+" "+item.label); ok {
+			hits = append(hits, scored{item, score})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+
+	result := make([]paletteItem, len(hits))
+	for i, h := range hits {
+		result[i] = h.item
+	}
+	return result
+}
+
+// fuzzyScore reports whether query's characters appear in text in order,
+// ignoring case and spaces in the query. Runs of adjacent characters and
+// matches at word starts score higher.
+func fuzzyScore(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(strings.Join(strings.Fields(query), "")))
+	t := []rune(strings.ToLower(text))
+
+	score, qi, prev := 0, 0, -2
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		score++
+		if ti == prev+1 {
+			score += 3
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 2
+		}
+		prev = ti
+		qi++
+	}
+	return score, qi == len(q)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := fuzzyScore("spst", "command spec status"); !ok {
+		t.Error("subsequence should match")
+	}
+	if _, ok := fuzzyScore("zz", "command spec status"); ok {
+		t.Error("missing characters should not match")
+	}
+	if _, ok := fuzzyScore("", "anything"); !ok {
+		t.Error("empty query matches everything")
+	}
+
+	adjacent, _ := fuzzyScore("hello", "exercise go-v1/basics/hello-world")
+	scattered, _ := fuzzyScore("hello", "exercise go-v1/http/handlers-low")
+	if adjacent <= scattered {
+		t.Errorf("adjacent match scored %d, scattered %d", adjacent, scattered)
+	}
+}
+
+func TestRunPalette_FilterAndPick(t *testing.T) {
+	var picked string
+	item := func(kind, label string) paletteItem {
+		return paletteItem{kind: kind, label: label, run: func() error { picked = label; return nil }}
+	}
+	items := []paletteItem{
+		item("command", "status"),
+		item("spec", ".specs/auth.yaml"),
+		item("exercise", "go-v1/basics/hello-world"),
+	}
+
+	var out bytes.Buffer
+	if err := runPalette(strings.NewReader("hello\n1\n"), &out, items); err != nil {
+		t.Fatalf("runPalette() error = %v", err)
+	}
+	if picked != "go-v1/basics/hello-world" {
+		t.Errorf("picked %q, want the exercise", picked)
+	}
+
+	picked = ""
+	out.Reset()
+	if err := runPalette(strings.NewReader("nomatch\n\n"), &out, items); err != nil {
+		t.Fatalf("runPalette() error = %v", err)
+	}
+	if picked != "" {
+		t.Errorf("empty line should quit, picked %q", picked)
+	}
+	if !strings.Contains(out.String(), `No matches for "nomatch"`) {
+		t.Errorf("output should report no matches:\n%s", out.String())
+	}
+}
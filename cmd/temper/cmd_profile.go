@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+func cmdProfile(args []string) error {
+	if len(args) < 1 {
+		fmt.Println(`Profile commands:
+
+  temper profile reset [--topic T] [--since DATE] [--until DATE] [--dry-run] [--yes]
+                                Erase learning history, all of it or a topic or date range`)
+		return nil
+	}
+
+	switch args[0] {
+	case "reset":
+		return cmdProfileReset(args[1:])
+	default:
+		return fmt.Errorf("unknown profile command: %s", args[0])
+	}
+}
+
+// cmdProfileReset erases sessions with their runs, hints, analytics, audit
+// entries and caches. Config, API keys and tracks are kept.
+//
+//	temper profile reset                      # everything
+//	temper profile reset --topic go/basics    # one topic
+//	temper profile reset --since 2026-01-01 --until 2026-01-31
+func cmdProfileReset(args []string) error {
+	fs := flag.NewFlagSet("profile reset", flag.ContinueOnError)
+	topic := fs.String("topic", "", "erase only this topic, e.g. go/basics or go")
+	since := fs.String("since", "", "erase only sessions started on or after this day (YYYY-MM-DD)")
+	until := fs.String("until", "", "erase only sessions started on or before this day (YYYY-MM-DD)")
+	dryRun := fs.Bool("dry-run", false, "report what would be erased without erasing")
+	yes := fs.Bool("yes", false, "don't ask for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	all := *topic == "" && *since == "" && *until == ""
+	what := "ALL learning history: sessions, runs, hints, analytics, audit log, cards and achievements"
+	endpoint := daemonAddr + "/v1/profile"
+	params := url.Values{}
+	if !all {
+		var scope []string
+		if *topic != "" {
+			params.Set("topic", *topic)
+			scope = append(scope, "on "+*topic)
+		}
+		if *since != "" {
+			params.Set("since", *since)
+			scope = append(scope, "since "+*since)
+		}
+		if *until != "" {
+			params.Set("until", *until)
+			scope = append(scope, "until "+*until)
+		}
+		what = "learning history " + strings.Join(scope, ", ")
+		endpoint += "/history"
+	}
+	if *dryRun {
+		params.Set("dry_run", "true")
+	} else if !*yes {
+		fmt.Printf("This erases %s. Config and API keys are kept.\nContinue? [y/N] ", what)
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer := strings.ToLower(strings.TrimSpace(line)); answer != "y" && answer != "yes" {
+			fmt.Println("Nothing erased")
+			return nil
+		}
+	}
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	resp, err := daemonDelete(endpoint)
+	if err != nil {
+		return fmt.Errorf("reset profile: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "reset profile")
+	}
+
+	var report struct {
+		DryRun        bool     `json:"dry_run"`
+		Sessions      []string `json:"sessions"`
+		Runs          int      `json:"runs"`
+		Interventions int      `json:"interventions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	verb := "Erased"
+	if report.DryRun {
+		verb = "Would erase"
+	}
+	fmt.Printf("%s %d sessions, %d runs and %d hints\n", verb, len(report.Sessions), report.Runs, report.Interventions)
+	if all && !report.DryRun {
+		fmt.Println("Learning profile reset")
+	}
+	return nil
+}
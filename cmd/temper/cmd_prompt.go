@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// previewIntents are the pairing endpoints a prompt preview can dry-run
+var previewIntents = map[string]bool{
+	"hint": true, "review": true, "stuck": true, "next": true, "explain": true,
+}
+
+func cmdPrompt(args []string) error {
+	if len(args) < 1 {
+		fmt.Println(`Prompt commands:
+
+  temper prompt preview [session] [--intent hint|review|stuck|next|explain] [--json]
+                                Show the prompt, provider, model and token
+                                estimate a request would use, without calling the LLM`)
+		return nil
+	}
+
+	switch args[0] {
+	case "preview":
+		return cmdPromptPreview(args[1:])
+	default:
+		return fmt.Errorf("unknown prompt command: %s", args[0])
+	}
+}
+
+func cmdPromptPreview(args []string) error {
+	fs := flag.NewFlagSet("prompt preview", flag.ContinueOnError)
+	intent := fs.String("intent", "hint", "hint, review, stuck, next or explain")
+	asJSON := fs.Bool("json", false, "print the raw preview")
+
+	// Allow flags after the session: temper prompt preview 3f2a --intent review
+	var positional []string
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		args = fs.Args()
+		if len(args) > 0 {
+			positional = append(positional, args[0])
+			args = args[1:]
+		}
+	}
+	if len(positional) > 1 {
+		return fmt.Errorf("usage: temper prompt preview [session] [--intent hint|review|stuck|next|explain]")
+	}
+	if !previewIntents[*intent] {
+		return fmt.Errorf("unknown intent %q (want hint, review, stuck, next or explain)", *intent)
+	}
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	var prefix string
+	if len(positional) == 1 {
+		prefix = positional[0]
+	}
+	sessionID, err := resolveSession(prefix)
+	if err != nil {
+		return err
+	}
+
+	resp, err := daemonPost(daemonAddr+"/v1/sessions/"+sessionID+"/"+*intent+"?dry_run=true", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		return fmt.Errorf("preview: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "preview")
+	}
+
+	var result struct {
+		Preview json.RawMessage `json:"preview"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if *asJSON {
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, result.Preview, "", "  "); err != nil {
+			return fmt.Errorf("format preview: %w", err)
+		}
+		fmt.Println(pretty.String())
+		return nil
+	}
+
+	var preview struct {
+		Intent   string `json:"intent"`
+		Level    int    `json:"level"`
+		Type     string `json:"type"`
+		Provider string `json:"provider"`
+		Model    string `json:"model"`
+		Offline  bool   `json:"offline"`
+		System   string `json:"system"`
+		Prompt   string `json:"prompt"`
+		Tokens   struct {
+			System    int `json:"system"`
+			Prompt    int `json:"prompt"`
+			Input     int `json:"input"`
+			MaxOutput int `json:"max_output"`
+		} `json:"estimated_tokens"`
+		Redactions []struct {
+			Kind  string `json:"kind"`
+			Count int    `json:"count"`
+		} `json:"redactions"`
+	}
+	if err := json.Unmarshal(result.Preview, &preview); err != nil {
+		return fmt.Errorf("parse preview: %w", err)
+	}
+
+	fmt.Printf("Session:  %s\n", sessionID)
+	fmt.Printf("Intent:   %s (L%d %s)\n", preview.Intent, preview.Level, preview.Type)
+	if preview.Offline {
+		fmt.Println("Provider: none (offline: the exercise's hints would be served)")
+	} else {
+		fmt.Printf("Provider: %s\n", preview.Provider)
+		fmt.Printf("Model:    %s\n", orDash(preview.Model))
+	}
+	fmt.Printf("Tokens:   ~%d in (system %d, prompt %d), up to %d out\n",
+		preview.Tokens.Input, preview.Tokens.System, preview.Tokens.Prompt, preview.Tokens.MaxOutput)
+	if len(preview.Redactions) > 0 {
+		kinds := make([]string, len(preview.Redactions))
+		for i, r := range preview.Redactions {
+			kinds[i] = fmt.Sprintf("%s x%d", r.Kind, r.Count)
+		}
+		fmt.Printf("Redacted: %s\n", strings.Join(kinds, ", "))
+	}
+	fmt.Printf("\n--- system ---\n%s\n", strings.TrimSpace(preview.System))
+	fmt.Printf("\n--- prompt ---\n%s\n", strings.TrimSpace(preview.Prompt))
+	return nil
+}
+
+// resolveSession expands a session ID prefix, or picks the most recently
+// updated active session when prefix is empty
+func resolveSession(prefix string) (string, error) {
+	resp, err := daemonGet(daemonAddr + "/v1/sessions")
+	if err != nil {
+		return "", fmt.Errorf("list sessions: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return "", err
+	}
+	if resp.StatusCode != 200 {
+		return "", responseError(resp, "list sessions")
+	}
+
+	var result struct {
+		Sessions []paletteSession `json:"sessions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("parse response: %w", err)
+	}
+
+	if prefix == "" {
+		var latest *paletteSession
+		for i, sess := range result.Sessions {
+			if sess.Status != "active" {
+				continue
+			}
+			if latest == nil || sess.UpdatedAt.After(latest.UpdatedAt) {
+				latest = &result.Sessions[i]
+			}
+		}
+		if latest == nil {
+			return "", fmt.Errorf("no active session; pass a session ID")
+		}
+		return latest.ID, nil
+	}
+
+	var matches []string
+	for _, sess := range result.Sessions {
+		if strings.HasPrefix(sess.ID, prefix) {
+			matches = append(matches, sess.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no session matches %s", prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%s matches %d sessions; use more of the ID", prefix, len(matches))
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+type reminderStatus struct {
+	Enabled   bool `json:"enabled"`
+	Reminders []struct {
+		ID      string `json:"id"`
+		Title   string `json:"title"`
+		Message string `json:"message"`
+	} `json:"reminders"`
+	Streak         int  `json:"streak"`
+	PracticedToday bool `json:"practiced_today"`
+	DueReviews     []struct {
+		ExerciseID   string    `json:"exercise_id"`
+		DueAt        time.Time `json:"due_at"`
+		IntervalDays int       `json:"interval_days"`
+	} `json:"due_reviews"`
+}
+
+func cmdRemind(args []string) error {
+	if len(args) > 0 && args[0] == "watch" {
+		return cmdRemindWatch(args[1:])
+	}
+	if len(args) > 0 {
+		return fmt.Errorf("unknown remind command: %s (valid: watch)", args[0])
+	}
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+	status, err := fetchReminders()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status.PracticedToday:
+		fmt.Printf("Practiced today. Streak: %d day(s)\n", status.Streak)
+	case status.Streak > 0:
+		fmt.Printf("Not practiced yet today. Streak: %d day(s)\n", status.Streak)
+	default:
+		fmt.Println("No current streak.")
+	}
+
+	if len(status.DueReviews) == 0 {
+		fmt.Println("No reviews due.")
+	} else {
+		fmt.Printf("\n%d review(s) due:\n", len(status.DueReviews))
+		for _, r := range status.DueReviews {
+			fmt.Printf("  %-40s due %s\n", r.ExerciseID, r.DueAt.Local().Format("2006-01-02"))
+		}
+	}
+
+	if !status.Enabled {
+		fmt.Println("\nReminders are off. Set reminders.enabled and reminders.windows in ~/.temper/config.yaml.")
+	}
+	return nil
+}
+
+// cmdRemindWatch polls the daemon for reminders and shows each one as a
+// desktop notification
+func cmdRemindWatch(args []string) error {
+	fs := flag.NewFlagSet("remind watch", flag.ContinueOnError)
+	interval := fs.Duration("interval", time.Minute, "how often to check for reminders")
+	once := fs.Bool("once", false, "check once and exit")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	for {
+		status, err := fetchReminders()
+		if err != nil {
+			if *once {
+				return err
+			}
+			fmt.Printf("check reminders: %v\n", err)
+		} else {
+			if !status.Enabled && *once {
+				fmt.Println("Reminders are off; nothing to watch.")
+			}
+			for _, r := range status.Reminders {
+				if err := notify(r.Title, r.Message); err != nil {
+					// No notifier on this system: the terminal will do
+					fmt.Printf("[%s] %s: %s\n", time.Now().Format("15:04"), r.Title, r.Message)
+				}
+				if err := dismissReminder(r.ID); err != nil {
+					fmt.Printf("dismiss reminder: %v\n", err)
+				}
+			}
+		}
+
+		if *once {
+			return nil
+		}
+		time.Sleep(*interval)
+	}
+}
+
+func fetchReminders() (*reminderStatus, error) {
+	resp, err := daemonGet(daemonAddr + "/v1/reminders")
+	if err != nil {
+		return nil, fmt.Errorf("get reminders: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, responseError(resp, "get reminders")
+	}
+
+	var status reminderStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &status, nil
+}
+
+func dismissReminder(id string) error {
+	resp, err := daemonPost(daemonAddr+"/v1/reminders/"+id+"/dismiss", "application/json", nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 404 {
+		return responseError(resp, "dismiss reminder")
+	}
+	return nil
+}
+
+// notify shows a desktop notification with the platform's own tool
+func notify(title, message string) error {
+	name, args := notifyCommand(runtime.GOOS, title, message)
+	if name == "" {
+		return fmt.Errorf("no notifier for %s", runtime.GOOS)
+	}
+	return exec.Command(name, args...).Run()
+}
+
+// notifyCommand returns the command that shows a notification on goos, or
+// "" when there is none
+func notifyCommand(goos, title, message string) (string, []string) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s",
+			appleScriptString(message), appleScriptString(title))
+		return "osascript", []string{"-e", script}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{"--app-name=Temper", title, message}
+	case "windows":
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(10000, %s, %s, 'Info')
+Start-Sleep -Seconds 10
+$n.Dispose()`, powerShellString(title), powerShellString(message))
+		return "powershell", []string{"-NoProfile", "-Command", script}
+	}
+	return "", nil
+}
+
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
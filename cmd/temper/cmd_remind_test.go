@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNotifyCommand(t *testing.T) {
+	tests := []struct {
+		goos     string
+		wantName string
+		wantArg  string
+	}{
+		{"darwin", "osascript", `display notification "Keep \"it\" going" with title "Temper"`},
+		{"linux", "notify-send", "Keep \"it\" going"},
+		{"windows", "powershell", "ShowBalloonTip(10000, 'Temper', 'Keep \"it\" going', 'Info')"},
+		{"plan9", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			name, args := notifyCommand(tt.goos, "Temper", `Keep "it" going`)
+			if name != tt.wantName {
+				t.Fatalf("name = %q, want %q", name, tt.wantName)
+			}
+			if tt.wantArg != "" && !strings.Contains(strings.Join(args, " "), tt.wantArg) {
+				t.Errorf("args %q do not contain %q", args, tt.wantArg)
+			}
+		})
+	}
+}
+
+func TestPowerShellString(t *testing.T) {
+	if got := powerShellString("it's"); got != "'it''s'" {
+		t.Errorf("powerShellString = %s", got)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// cmdReplay re-asks a recorded session's interventions, with another
+// provider or after a prompt change, and shows how the answers differ
+func cmdReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	provider := fs.String("provider", "", "provider to replay with (default: the default provider)")
+	asJSON := fs.Bool("json", false, "print the raw report")
+	quiet := fs.Bool("quiet", false, "summarize without the content diffs")
+
+	// Allow flags after the session: temper replay 3f2a --provider ollama
+	var positional []string
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		args = fs.Args()
+		if len(args) > 0 {
+			positional = append(positional, args[0])
+			args = args[1:]
+		}
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: temper replay <session-id> [--provider NAME] [--json] [--quiet]")
+	}
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	// Finished sessions aren't listed, so a full ID is used as given
+	sessionID := positional[0]
+	if len(sessionID) != 36 {
+		var err error
+		if sessionID, err = resolveSession(sessionID); err != nil {
+			return err
+		}
+	}
+
+	// Replay needs the default provider; say up front why it can't be used
+	if *provider == "" {
+		if err := checkCapability("pairing", "replay", "offline"); err != nil {
+			return err
+		}
+	}
+
+	body, _ := json.Marshal(map[string]string{"provider": *provider})
+	resp, err := daemonPost(daemonAddr+"/v1/sessions/"+sessionID+"/replay", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "replay")
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if *asJSON {
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, raw, "", "  "); err != nil {
+			return fmt.Errorf("format report: %w", err)
+		}
+		fmt.Println(pretty.String())
+		return nil
+	}
+
+	var report struct {
+		Provider string `json:"provider"`
+		Results  []struct {
+			Intent   string `json:"intent"`
+			RunID    string `json:"run_id"`
+			Recorded struct {
+				Level int `json:"level"`
+			} `json:"recorded"`
+			Replayed *struct {
+				Level int `json:"level"`
+			} `json:"replayed"`
+			Error   string `json:"error"`
+			Changed bool   `json:"changed"`
+			Diff    *struct {
+				Additions int    `json:"additions"`
+				Deletions int    `json:"deletions"`
+				Patch     string `json:"patch"`
+			} `json:"diff"`
+		} `json:"results"`
+		Changed int `json:"changed"`
+		Failed  int `json:"failed"`
+	}
+	if err := json.Unmarshal(raw, &report); err != nil {
+		return fmt.Errorf("parse report: %w", err)
+	}
+
+	if len(report.Results) == 0 {
+		fmt.Println("The session has no interventions to replay.")
+		return nil
+	}
+	fmt.Printf("Replayed %d interventions from %s with %s\n\n", len(report.Results), shortID(sessionID), report.Provider)
+	for i, res := range report.Results {
+		run := "session code"
+		if res.RunID != "" {
+			run = "run " + shortID(res.RunID)
+		}
+		fmt.Printf("#%-3d %-8s L%d  %-13s ", i+1, res.Intent, res.Recorded.Level, run)
+		switch {
+		case res.Error != "":
+			fmt.Printf("failed: %s\n", res.Error)
+		case !res.Changed:
+			fmt.Println("unchanged")
+		default:
+			var notes []string
+			if res.Replayed.Level != res.Recorded.Level {
+				notes = append(notes, fmt.Sprintf("now L%d", res.Replayed.Level))
+			}
+			if res.Diff != nil {
+				notes = append(notes, fmt.Sprintf("+%d -%d", res.Diff.Additions, res.Diff.Deletions))
+			}
+			fmt.Printf("changed (%s)\n", strings.Join(notes, ", "))
+			if res.Diff != nil && !*quiet {
+				fmt.Println(indent(res.Diff.Patch, "     "))
+			}
+		}
+	}
+
+	fmt.Printf("\n%d changed, %d failed, %d unchanged\n",
+		report.Changed, report.Failed, len(report.Results)-report.Changed-report.Failed)
+	return nil
+}
+
+// indent prefixes every line of text
+func indent(text, prefix string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
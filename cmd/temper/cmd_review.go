@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// cmdReview asks the daemon to review a session's code.
+//
+// Usage:
+//
+//	temper review                          # review the latest active session
+//	temper review 3f2a --format sarif      # SARIF log for code scanning
+//	temper review --format sarif --out review.sarif
+func cmdReview(args []string) error {
+	fs := flag.NewFlagSet("review", flag.ContinueOnError)
+	format := fs.String("format", "text", "output format: text, json or sarif")
+	out := fs.String("out", "", "output file (default: stdout)")
+
+	// Allow flags after the session: temper review 3f2a --format sarif
+	var positional []string
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		args = fs.Args()
+		if len(args) > 0 {
+			positional = append(positional, args[0])
+			args = args[1:]
+		}
+	}
+	if len(positional) > 1 {
+		return fmt.Errorf("usage: temper review [session] [--format text|json|sarif] [--out FILE]")
+	}
+	if *format != "text" && *format != "json" && *format != "sarif" {
+		return fmt.Errorf("unknown format %q (use text, json or sarif)", *format)
+	}
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	var prefix string
+	if len(positional) == 1 {
+		prefix = positional[0]
+	}
+	sessionID, err := resolveSession(prefix)
+	if err != nil {
+		return err
+	}
+
+	if caps, err := daemonCapabilities(); err == nil && caps["pairing"].Mode == "offline" {
+		fmt.Fprintf(os.Stderr, "temper: no LLM available (%s)\n", caps["pairing"].Reason)
+	}
+
+	url := daemonAddr + "/v1/sessions/" + sessionID + "/review"
+	if *format == "sarif" {
+		url += "?format=sarif"
+	}
+	resp, err := daemonPost(url, "application/json", strings.NewReader("{}"))
+	if err != nil {
+		return fmt.Errorf("review: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "review")
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.OpenFile(*out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return fmt.Errorf("open %s: %w", *out, err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	if *format != "text" {
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, data, "", "  "); err != nil {
+			return fmt.Errorf("format review: %w", err)
+		}
+		pretty.WriteByte('\n')
+		_, err := pretty.WriteTo(w)
+		return err
+	}
+
+	var review struct {
+		Level   int    `json:"level"`
+		Type    string `json:"type"`
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(data, &review); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "Review (L%d %s)\n\n%s\n", review.Level, review.Type, strings.TrimSpace(review.Content))
+	return err
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+func cmdRunner(args []string) error {
+	if len(args) < 1 {
+		fmt.Println(`Runner commands:
+
+  temper runner pull [--pin]   Pull the runner image (--pin records its digest in config)
+  temper runner verify         Check the image's Go toolchain version`)
+		return nil
+	}
+
+	switch args[0] {
+	case "pull":
+		pin := len(args) > 1 && args[1] == "--pin"
+		return cmdRunnerPull(pin)
+	case "verify":
+		return cmdRunnerVerify()
+	default:
+		return fmt.Errorf("unknown runner command: %s", args[0])
+	}
+}
+
+func cmdRunnerPull(pin bool) error {
+	if err := checkDocker(); err != nil {
+		return err
+	}
+	cfg, err := config.LoadLocalConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	ref := runner.ImageRef(cfg.Runner.Docker.Image, cfg.Runner.Docker.ImageDigest)
+	args := []string{"pull", ref}
+	platform, err := runnerPlatform(cfg)
+	if err != nil {
+		return err
+	}
+	if platform != "" {
+		args = append(args, "--platform", platform)
+		fmt.Printf("Pulling %s for %s...\n", ref, platform)
+	} else {
+		fmt.Printf("Pulling %s...\n", ref)
+	}
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pull %s: %w", ref, err)
+	}
+
+	if pin {
+		digest, err := imageDigest(ref)
+		if err != nil {
+			return err
+		}
+		cfg.Runner.Docker.ImageDigest = digest
+		if err := config.SaveLocalConfig(cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		fmt.Printf("Pinned %s to %s\n", cfg.Runner.Docker.Image, digest)
+	}
+
+	if warning := checkRunnerPlatform(cfg); warning != "" {
+		fmt.Printf("⚠ %s\n", warning)
+	}
+	return checkRunnerToolchain(cfg)
+}
+
+func cmdRunnerVerify() error {
+	if err := checkDocker(); err != nil {
+		return err
+	}
+	cfg, err := config.LoadLocalConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	return checkRunnerToolchain(cfg)
+}
+
+// checkRunnerImage verifies the configured runner image is present locally
+// and ships the expected Go toolchain. Used by doctor and the runner
+// commands.
+func checkRunnerImage(cfg *config.LocalConfig) (string, error) {
+	ref := runner.ImageRef(cfg.Runner.Docker.Image, cfg.Runner.Docker.ImageDigest)
+	if err := exec.Command("docker", "image", "inspect", ref).Run(); err != nil {
+		return "", fmt.Errorf("%s not present (run 'temper runner pull')", ref)
+	}
+
+	out, err := exec.Command("docker", "run", "--rm", "--network", "none", ref, "go", "version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s: cannot run go version: %s", ref, strings.TrimSpace(string(out)))
+	}
+	actual := runner.ParseGoVersion(string(out))
+	if actual == "" {
+		return "", fmt.Errorf("%s: no Go toolchain found", ref)
+	}
+
+	expected := cfg.Runner.Docker.GoVersion
+	if expected == "" {
+		expected = runner.ExpectedGoVersion(cfg.Runner.Docker.Image)
+	}
+	if !runner.GoVersionMatches(actual, expected) {
+		return actual, fmt.Errorf("%s ships go%s, expected go%s", ref, actual, expected)
+	}
+	return actual, nil
+}
+
+// runnerPlatform returns the platform runner images are pulled for: the
+// configured one, or the Docker daemon's native platform. Empty when
+// neither is known, leaving the choice to Docker.
+func runnerPlatform(cfg *config.LocalConfig) (string, error) {
+	if cfg.Runner.Docker.Platform != "" {
+		p, err := runner.ParsePlatform(cfg.Runner.Docker.Platform)
+		if err != nil {
+			return "", err
+		}
+		return runner.PlatformString(p), nil
+	}
+	return runner.PlatformString(runner.NativePlatform(dockerArch())), nil
+}
+
+// dockerArch returns the Docker daemon's architecture, or "" when docker
+// can't be asked
+func dockerArch() string {
+	out, err := exec.Command("docker", "info", "--format", "{{.Architecture}}").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// checkRunnerPlatform warns when the local runner image is built for a
+// different architecture than Docker runs, so every run is emulated.
+// Returns "" when the image runs natively or either side is unknown.
+func checkRunnerPlatform(cfg *config.LocalConfig) string {
+	ref := runner.ImageRef(cfg.Runner.Docker.Image, cfg.Runner.Docker.ImageDigest)
+	out, err := exec.Command("docker", "image", "inspect", "--format", "{{.Architecture}}", ref).Output()
+	if err != nil {
+		return ""
+	}
+	imageArch := strings.TrimSpace(string(out))
+	daemonArch := dockerArch()
+	if !runner.Emulated(daemonArch, imageArch) {
+		return ""
+	}
+
+	where := "this machine"
+	if goruntime.GOOS == "darwin" && runner.NormalizeArch(daemonArch) == "arm64" {
+		where = "Apple Silicon"
+	}
+	return fmt.Sprintf("%s is built for %s but Docker runs %s on %s: runs are emulated and much slower. "+
+		"Run 'temper runner pull' to fetch the native variant, or use a multi-arch image.",
+		ref, runner.NormalizeArch(imageArch), runner.NormalizeArch(daemonArch), where)
+}
+
+func checkRunnerToolchain(cfg *config.LocalConfig) error {
+	version, err := checkRunnerImage(cfg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ runner image ships go%s\n", version)
+	return nil
+}
+
+// imageDigest returns the repo digest of a local image
+func imageDigest(ref string) (string, error) {
+	out, err := exec.Command("docker", "image", "inspect", "--format", "{{index .RepoDigests 0}}", ref).Output()
+	if err != nil {
+		return "", fmt.Errorf("inspect %s: %w", ref, err)
+	}
+	repoDigest := strings.TrimSpace(string(out))
+	i := strings.Index(repoDigest, "@")
+	if i < 0 {
+		return "", fmt.Errorf("%s has no repo digest", ref)
+	}
+	return repoDigest[i+1:], nil
+}
@@ -2,17 +2,110 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/felixgeelhaar/temper/exercises"
 	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/exercise"
+	"github.com/felixgeelhaar/temper/internal/locale"
+	"github.com/felixgeelhaar/temper/internal/runner"
 )
 
+// initOptions are the flags that let init run without prompts
+type initOptions struct {
+	nonInteractive bool
+	provider       string // default LLM provider
+	apiKeyEnv      string // environment variable holding the provider's API key
+	runner         string // code executor
+}
+
+func parseInitFlags(args []string) (initOptions, error) {
+	var opts initOptions
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	fs.BoolVar(&opts.nonInteractive, "non-interactive", false, "never prompt; fail instead of asking")
+	fs.StringVar(&opts.provider, "provider", "", "default LLM provider: claude, openai, ollama or auto")
+	fs.StringVar(&opts.apiKeyEnv, "api-key-env", "", "read the provider's API key from this environment variable")
+	fs.StringVar(&opts.runner, "runner", "", "code executor (docker)")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument: %s", fs.Arg(0))
+	}
+	return opts, opts.validate()
+}
+
+func (o initOptions) validate() error {
+	switch o.provider {
+	case "", "auto", "claude", "openai", "ollama":
+	default:
+		return fmt.Errorf("unknown provider %q (want claude, openai, ollama or auto)", o.provider)
+	}
+	if o.runner != "" && o.runner != "docker" {
+		return fmt.Errorf("unsupported runner %q (docker is the only executor)", o.runner)
+	}
+	if o.apiKeyEnv != "" && o.provider != "claude" && o.provider != "openai" {
+		return fmt.Errorf("--api-key-env needs --provider claude or openai")
+	}
+	return nil
+}
+
+func (o initOptions) hasChoices() bool {
+	return o.provider != "" || o.runner != "" || o.apiKeyEnv != ""
+}
+
+// applyInitOptions writes the provider and runner choices into cfg and
+// returns the API keys to store. It never prompts.
+func applyInitOptions(cfg *config.LocalConfig, opts initOptions, getenv func(string) string) (map[string]string, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	secrets := map[string]string{}
+	if opts.apiKeyEnv != "" {
+		key := strings.TrimSpace(getenv(opts.apiKeyEnv))
+		if key == "" {
+			return nil, fmt.Errorf("environment variable %s is empty or unset", opts.apiKeyEnv)
+		}
+		secrets[opts.provider] = key
+	}
+
+	if opts.provider != "" {
+		cfg.LLM.DefaultProvider = opts.provider
+		if p := cfg.LLM.Providers[opts.provider]; p != nil {
+			p.Enabled = true
+		}
+		// The default level models name Claude models, which other
+		// providers cannot serve; fall back to the provider's own model.
+		if opts.provider == "openai" || opts.provider == "ollama" {
+			cfg.LLM.LevelModels = nil
+		}
+	}
+	if opts.runner != "" {
+		cfg.Runner.Executor = opts.runner
+	}
+	return secrets, nil
+}
+
+// providerConfig returns the settings of a key-based provider, or nil
+func providerConfig(cfg *config.LocalConfig, name string) *config.ProviderConfig {
+	if cfg == nil || (name != "claude" && name != "openai") {
+		return nil
+	}
+	return cfg.LLM.Providers[name]
+}
+
 // cmdInit initializes Temper for first-time use
-func cmdInit() error {
+func cmdInit(args []string) error {
+	opts, err := parseInitFlags(args)
+	if err != nil {
+		return err
+	}
+
 	fmt.Println("Temper - First-Time Setup")
 	fmt.Println("==========================")
 	fmt.Println()
@@ -50,20 +143,11 @@ func cmdInit() error {
 	fmt.Print("Setting up exercise packs... ")
 	exercisesDest := filepath.Join(temperDir, "exercises")
 
-	// Check if exercises exist in current directory (dev mode)
-	if _, err := os.Stat("./exercises"); err == nil {
-		// Copy from local
-		if err := copyDir("./exercises", exercisesDest); err != nil {
-			fmt.Println("⚠ (manual copy required)")
-		} else {
-			fmt.Println("✓")
-		}
+	// Unpack the packs bundled in this binary
+	if _, err := exercise.ExtractBundle(exercises.FS, exercisesDest); err != nil {
+		fmt.Printf("⚠ %v\n", err)
 	} else {
-		// Create placeholder
-		goPackDir := filepath.Join(exercisesDest, "go-fundamentals")
-		if err := os.MkdirAll(goPackDir, 0755); err == nil {
-			fmt.Println("✓ (placeholder created)")
-		}
+		fmt.Println("✓")
 	}
 
 	// 4. Configure LLM provider.
@@ -73,7 +157,33 @@ func cmdInit() error {
 	fmt.Println("Temper supports: Claude (Anthropic), OpenAI, and Ollama (local)")
 	fmt.Println()
 
-	cfg, _ := config.LoadLocalConfig()
+	cfg, err := config.LoadLocalConfig()
+	if err != nil && opts.hasChoices() {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	// Flags settle the provider and runner before anything is asked
+	if opts.hasChoices() {
+		secrets, err := applyInitOptions(cfg, opts, os.Getenv)
+		if err != nil {
+			return err
+		}
+		if err := config.SaveLocalConfig(cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		if len(secrets) > 0 {
+			if err := config.SaveSecrets(secrets); err != nil {
+				return fmt.Errorf("save API key: %w", err)
+			}
+			if p := cfg.LLM.Providers[opts.provider]; p != nil {
+				p.APIKey = secrets[opts.provider]
+			}
+			fmt.Printf("%s API key read from $%s ✓\n", opts.provider, opts.apiKeyEnv)
+		}
+		if opts.provider != "" {
+			fmt.Printf("Default provider set to %s ✓\n", opts.provider)
+		}
+	}
 
 	// Probe Ollama at the configured (or default) URL. If reachable, we
 	// can offer a zero-friction first-run experience: no API key, no
@@ -90,9 +200,14 @@ func cmdInit() error {
 	}
 
 	// Claude is optional; offer to set the key but never require it.
-	if cfg != nil && cfg.LLM.Providers["claude"] != nil && cfg.LLM.Providers["claude"].APIKey != "" {
+	switch {
+	case cfg != nil && cfg.LLM.Providers["claude"] != nil && cfg.LLM.Providers["claude"].APIKey != "":
 		fmt.Println("Claude API key: already configured ✓")
-	} else {
+	case opts.nonInteractive || opts.provider != "":
+		if p := providerConfig(cfg, opts.provider); p != nil && p.APIKey == "" {
+			fmt.Printf("No %s API key configured (add one with 'temper provider set-key %s')\n", opts.provider, opts.provider)
+		}
+	default:
 		prompt := "Enter Claude API key (or press Enter to skip): "
 		if ollamaUp {
 			prompt = "Enter Claude API key for higher-quality models (or press Enter to use Ollama only): "
@@ -149,42 +264,6 @@ func cmdInit() error {
 	return nil
 }
 
-// copyDir copies a directory recursively
-func copyDir(src, dst string) error {
-	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Get relative path
-		relPath, err := filepath.Rel(src, path)
-		if err != nil {
-			return err
-		}
-		dstPath := filepath.Join(dst, relPath)
-
-		if info.IsDir() {
-			return os.MkdirAll(dstPath, info.Mode())
-		}
-
-		// Copy file
-		srcFile, err := os.Open(path)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = srcFile.Close() }()
-
-		dstFile, err := os.Create(dstPath)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = dstFile.Close() }()
-
-		_, err = io.Copy(dstFile, srcFile)
-		return err
-	})
-}
-
 // cmdDoctor checks system requirements
 func cmdDoctor() error {
 	fmt.Println("Checking system requirements...")
@@ -213,6 +292,14 @@ func cmdDoctor() error {
 		fmt.Printf("✓ %s\n", temperDir)
 	}
 
+	// Check exercise packs; the daemon unpacks the bundled ones on start
+	fmt.Print("Exercises: ")
+	if packs, err := exercise.NewLoader(filepath.Join(temperDir, "exercises")).LoadAllPacks(); err != nil || len(packs) == 0 {
+		fmt.Println("⚠ none unpacked yet (run 'temper init' or 'temper start')")
+	} else {
+		fmt.Printf("✓ %d packs\n", len(packs))
+	}
+
 	// Check config
 	fmt.Print("Config:    ")
 	cfg, err := config.LoadLocalConfig()
@@ -222,6 +309,27 @@ func cmdDoctor() error {
 	} else {
 		fmt.Println("✓ loaded")
 
+		// Check runner image (only meaningful when Docker is reachable)
+		if checkDocker() == nil {
+			fmt.Print("Runner:    ")
+			if version, err := checkRunnerImage(cfg); err != nil {
+				fmt.Printf("✗ %v\n", err)
+				allGood = false
+			} else {
+				fmt.Printf("✓ %s (go%s)\n", cfg.Runner.Docker.Image, version)
+			}
+
+			// Emulated images work, just slowly, so this only warns
+			fmt.Print("Platform:  ")
+			if warning := checkRunnerPlatform(cfg); warning != "" {
+				fmt.Printf("⚠ %s\n", warning)
+			} else if arch := dockerArch(); arch != "" {
+				fmt.Printf("✓ native (linux/%s)\n", runner.NormalizeArch(arch))
+			} else {
+				fmt.Println("⚠ unknown (docker info failed)")
+			}
+		}
+
 		// Check LLM providers
 		fmt.Println("\nLLM Providers:")
 		for name, provider := range cfg.LLM.Providers {
@@ -248,7 +356,7 @@ func cmdDoctor() error {
 	// Check daemon status
 	fmt.Print("\nDaemon:    ")
 	if isRunning() {
-		fmt.Println("✓ running")
+		fmt.Printf("✓ running at %s\n", daemonAddr)
 	} else {
 		fmt.Println("✗ not running (run 'temper start')")
 	}
@@ -264,7 +372,17 @@ func cmdDoctor() error {
 }
 
 // cmdConfig shows current configuration
-func cmdConfig() error {
+func cmdConfig(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "show":
+		case "locale":
+			return cmdConfigLocale(args[1:])
+		default:
+			return fmt.Errorf("unknown config command: %s (valid: show, locale)", args[0])
+		}
+	}
+
 	cfg, err := config.LoadLocalConfig()
 	if err != nil {
 		return fmt.Errorf("load config: %w", err)
@@ -276,6 +394,10 @@ func cmdConfig() error {
 	fmt.Printf("  bind: %s:%d\n", cfg.Daemon.Bind, cfg.Daemon.Port)
 	fmt.Printf("  log_level: %s\n", cfg.Daemon.LogLevel)
 
+	fmt.Println("\nStorage:")
+	fmt.Printf("  driver: %s\n", cfg.Storage.Driver)
+	fmt.Printf("  encrypt: %t\n", cfg.Storage.Encrypt)
+
 	fmt.Println("\nLLM:")
 	fmt.Printf("  default_provider: %s\n", cfg.LLM.DefaultProvider)
 	for name, provider := range cfg.LLM.Providers {
@@ -289,6 +411,8 @@ func cmdConfig() error {
 		}
 	}
 
+	fmt.Printf("\nLocale: %s\n", locale.Resolve(cfg.Locale))
+
 	fmt.Println("\nLearning Contract:")
 	fmt.Printf("  default_track: %s\n", cfg.Learning.DefaultTrack)
 	for name, track := range cfg.Learning.Tracks {
@@ -299,6 +423,12 @@ func cmdConfig() error {
 	fmt.Printf("  executor: %s\n", cfg.Runner.Executor)
 	if cfg.Runner.Executor == "docker" {
 		fmt.Printf("  image: %s\n", cfg.Runner.Docker.Image)
+		if cfg.Runner.Docker.ImageDigest != "" {
+			fmt.Printf("  digest: %s\n", cfg.Runner.Docker.ImageDigest)
+		}
+		if cfg.Runner.Docker.Isolation != "" {
+			fmt.Printf("  isolation: %s\n", cfg.Runner.Docker.Isolation)
+		}
 		fmt.Printf("  memory: %dMB\n", cfg.Runner.Docker.MemoryMB)
 		fmt.Printf("  timeout: %ds\n", cfg.Runner.Docker.TimeoutSeconds)
 	}
@@ -309,6 +439,39 @@ func cmdConfig() error {
 	return nil
 }
 
+// cmdConfigLocale shows the locale hints and CLI output use, or sets it
+func cmdConfigLocale(args []string) error {
+	cfg, err := config.LoadLocalConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	if len(args) == 0 {
+		fmt.Printf("Locale: %s\n", locale.Resolve(cfg.Locale))
+		if cfg.Locale != "" {
+			fmt.Printf("  configured: %s\n", cfg.Locale)
+		}
+		if env := os.Getenv(locale.Env); env != "" {
+			fmt.Printf("  %s: %s\n", locale.Env, env)
+		}
+		fmt.Printf("Supported: %s\n", strings.Join(locale.Supported(), ", "))
+		return nil
+	}
+
+	tag, err := locale.Normalize(args[0])
+	if err != nil {
+		return fmt.Errorf("%w (supported: %s)", err, strings.Join(locale.Supported(), ", "))
+	}
+	cfg.Locale = tag
+	if err := config.SaveLocalConfig(cfg); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+
+	fmt.Printf("✓ Locale set to %s (%s)\n", tag, locale.Name(tag))
+	fmt.Println("Restart the daemon for changes to take effect.")
+	return nil
+}
+
 // cmdProvider manages LLM provider API keys
 func cmdProvider(args []string) error {
 	if len(args) < 1 {
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+)
+
+func TestParseInitFlags(t *testing.T) {
+	opts, err := parseInitFlags([]string{"--non-interactive", "--provider", "claude", "--api-key-env", "ANTHROPIC_API_KEY", "--runner", "docker"})
+	if err != nil {
+		t.Fatalf("parseInitFlags() error = %v", err)
+	}
+	if !opts.nonInteractive || opts.provider != "claude" || opts.apiKeyEnv != "ANTHROPIC_API_KEY" || opts.runner != "docker" {
+		t.Errorf("opts = %+v", opts)
+	}
+
+	for _, args := range [][]string{
+		{"--provider", "gemini"},
+		{"--runner", "local"},
+		{"--api-key-env", "KEY"},                         // no provider to own the key
+		{"--provider", "ollama", "--api-key-env", "KEY"}, // ollama takes no key
+		{"extra"},
+	} {
+		if _, err := parseInitFlags(args); err == nil {
+			t.Errorf("parseInitFlags(%v) should fail", args)
+		}
+	}
+}
+
+func TestApplyInitOptions(t *testing.T) {
+	env := map[string]string{"ANTHROPIC_API_KEY": " sk-test \n"}
+	getenv := func(name string) string { return env[name] }
+
+	cfg := config.DefaultLocalConfig()
+	secrets, err := applyInitOptions(cfg, initOptions{provider: "claude", apiKeyEnv: "ANTHROPIC_API_KEY", runner: "docker"}, getenv)
+	if err != nil {
+		t.Fatalf("applyInitOptions() error = %v", err)
+	}
+	if secrets["claude"] != "sk-test" {
+		t.Errorf("secrets = %v", secrets)
+	}
+	if cfg.LLM.DefaultProvider != "claude" || cfg.Runner.Executor != "docker" {
+		t.Errorf("provider = %q, runner = %q", cfg.LLM.DefaultProvider, cfg.Runner.Executor)
+	}
+	if len(cfg.LLM.LevelModels) == 0 {
+		t.Error("claude keeps the default level models")
+	}
+
+	cfg = config.DefaultLocalConfig()
+	if _, err := applyInitOptions(cfg, initOptions{provider: "openai"}, getenv); err != nil {
+		t.Fatalf("applyInitOptions() error = %v", err)
+	}
+	if !cfg.LLM.Providers["openai"].Enabled {
+		t.Error("chosen provider should be enabled")
+	}
+	if cfg.LLM.LevelModels != nil {
+		t.Errorf("openai should drop the Claude level models, got %v", cfg.LLM.LevelModels)
+	}
+
+	if _, err := applyInitOptions(config.DefaultLocalConfig(), initOptions{provider: "openai", apiKeyEnv: "OPENAI_API_KEY"}, getenv); err == nil {
+		t.Error("unset key variable should fail")
+	}
+}
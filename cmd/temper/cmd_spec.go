@@ -1,10 +1,14 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
+	"time"
 )
 
 // cmdSpec manages product specifications (Specular format)
@@ -13,16 +17,28 @@ func cmdSpec(args []string) error {
 		fmt.Println(`Spec commands (Specular format):
 
   temper spec create <name>        Create a new spec scaffold
+  temper spec import --github <owner/repo#N> | --jira <KEY>
+                                   Create a spec from an issue or ticket
   temper spec list                 List specs in workspace
   temper spec validate <path>      Validate spec completeness
+  temper spec review <path>        AI critique: ambiguity, gaps, conflicts
   temper spec status <path>        Show spec progress
+    --feature <id>                 Criteria, tests, runs and files of one feature
+  temper spec sync <path>          Report satisfied criteria to the source issue
+  temper spec plan <path>          Order features by their dependencies
+  temper spec diagram <path>       Mermaid component diagram of the features
+    --c4                           Use C4 component syntax instead
+    --write                        Also save it next to the spec
   temper spec lock <path>          Generate SpecLock for drift detection
   temper spec drift <path>         Show drift from locked spec
+  temper spec history <path>       Show how the spec changed between locks
 
 Examples:
   temper spec create "User Authentication"
+  temper spec import --github acme/api#123
   temper spec validate .specs/auth.yaml
-  temper spec status .specs/auth.yaml`)
+  temper spec status .specs/auth.yaml
+  temper spec status .specs/auth.yaml --feature password-reset`)
 		return nil
 	}
 
@@ -32,6 +48,8 @@ Examples:
 			return fmt.Errorf("spec name required (e.g., temper spec create \"User Authentication\")")
 		}
 		return cmdSpecCreate(args[1])
+	case "import":
+		return cmdSpecImport(args[1:])
 	case "list":
 		return cmdSpecList()
 	case "validate":
@@ -39,11 +57,48 @@ Examples:
 			return fmt.Errorf("spec path required (e.g., temper spec validate .specs/auth.yaml)")
 		}
 		return cmdSpecValidate(args[1])
+	case "review":
+		if len(args) < 2 {
+			return fmt.Errorf("spec path required (e.g., temper spec review .specs/auth.yaml)")
+		}
+		return cmdSpecReview(args[1])
 	case "status":
 		if len(args) < 2 {
 			return fmt.Errorf("spec path required (e.g., temper spec status .specs/auth.yaml)")
 		}
+		if len(args) == 4 && args[2] == "--feature" {
+			return cmdSpecFeatureStatus(args[1], args[3])
+		}
+		if len(args) > 2 {
+			return fmt.Errorf("usage: temper spec status <path> [--feature <id>]")
+		}
 		return cmdSpecStatus(args[1])
+	case "sync":
+		if len(args) < 2 {
+			return fmt.Errorf("spec path required (e.g., temper spec sync .specs/auth.yaml)")
+		}
+		return cmdSpecSync(args[1])
+	case "plan":
+		if len(args) < 2 {
+			return fmt.Errorf("spec path required (e.g., temper spec plan .specs/auth.yaml)")
+		}
+		return cmdSpecPlan(args[1])
+	case "diagram":
+		if len(args) < 2 {
+			return fmt.Errorf("spec path required (e.g., temper spec diagram .specs/auth.yaml)")
+		}
+		format, write := "mermaid", false
+		for _, arg := range args[2:] {
+			switch arg {
+			case "--c4":
+				format = "c4"
+			case "--write":
+				write = true
+			default:
+				return fmt.Errorf("usage: temper spec diagram <path> [--c4] [--write]")
+			}
+		}
+		return cmdSpecDiagram(args[1], format, write)
 	case "lock":
 		if len(args) < 2 {
 			return fmt.Errorf("spec path required (e.g., temper spec lock .specs/auth.yaml)")
@@ -54,14 +109,19 @@ Examples:
 			return fmt.Errorf("spec path required (e.g., temper spec drift .specs/auth.yaml)")
 		}
 		return cmdSpecDrift(args[1])
+	case "history":
+		if len(args) < 2 {
+			return fmt.Errorf("spec path required (e.g., temper spec history .specs/auth.yaml)")
+		}
+		return cmdSpecHistory(args[1])
 	default:
 		return fmt.Errorf("unknown spec command: %s", args[0])
 	}
 }
 
 func cmdSpecCreate(name string) error {
-	if !isRunning() {
-		return fmt.Errorf("daemon not running (run 'temper start' first)")
+	if err := requireDaemon(); err != nil {
+		return err
 	}
 
 	body := fmt.Sprintf(`{"name": %q}`, name)
@@ -72,11 +132,7 @@ func cmdSpecCreate(name string) error {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusCreated {
-		var errResp struct {
-			Error string `json:"error"`
-		}
-		_ = json.NewDecoder(resp.Body).Decode(&errResp)
-		return fmt.Errorf("create spec failed: %s", errResp.Error)
+		return responseError(resp, "create spec")
 	}
 
 	var spec struct {
@@ -99,9 +155,92 @@ func cmdSpecCreate(name string) error {
 	return nil
 }
 
+func cmdSpecImport(args []string) error {
+	var req struct {
+		GitHub    string `json:"github,omitempty"`
+		Jira      string `json:"jira,omitempty"`
+		Overwrite bool   `json:"overwrite,omitempty"`
+	}
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--github", "--jira":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", args[i])
+			}
+			if args[i] == "--github" {
+				req.GitHub = args[i+1]
+			} else {
+				req.Jira = args[i+1]
+			}
+			i++
+		case "--overwrite":
+			req.Overwrite = true
+		default:
+			return fmt.Errorf("unknown flag: %s", args[i])
+		}
+	}
+	if (req.GitHub == "") == (req.Jira == "") {
+		return fmt.Errorf("usage: temper spec import --github owner/repo#123 | --jira PROJ-42 [--overwrite]")
+	}
+
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+	resp, err := daemonPost(daemonAddr+"/v1/specs/import", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("import spec: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return responseError(resp, "import spec")
+	}
+
+	var spec struct {
+		Name               string `json:"name"`
+		FilePath           string `json:"file_path"`
+		Source             string `json:"source"`
+		AcceptanceCriteria []any  `json:"acceptance_criteria"`
+		Features           []struct {
+			Priority string   `json:"priority"`
+			Labels   []string `json:"labels"`
+		} `json:"features"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	fmt.Printf("✓ Imported spec: %s\n", spec.Name)
+	fmt.Printf("  File:     .specs/%s\n", spec.FilePath)
+	if spec.Source != "" {
+		fmt.Printf("  Source:   %s\n", spec.Source)
+	}
+	fmt.Printf("  Criteria: %d\n", len(spec.AcceptanceCriteria))
+	if len(spec.Features) > 0 {
+		fmt.Printf("  Priority: %s\n", spec.Features[0].Priority)
+		if len(spec.Features[0].Labels) > 0 {
+			fmt.Printf("  Labels:   %s\n", strings.Join(spec.Features[0].Labels, ", "))
+		}
+	}
+	fmt.Println()
+	fmt.Println("Next steps:")
+	fmt.Println("  1. Review the scaffold; the issue is one feature, split it if needed")
+	fmt.Println("  2. Run 'temper spec validate .specs/" + spec.FilePath + "' to check completeness")
+
+	return nil
+}
+
 func cmdSpecList() error {
-	if !isRunning() {
-		return fmt.Errorf("daemon not running (run 'temper start' first)")
+	if err := requireDaemon(); err != nil {
+		return err
 	}
 
 	resp, err := daemonGet(daemonAddr + "/v1/specs")
@@ -147,8 +286,8 @@ func cmdSpecList() error {
 }
 
 func cmdSpecValidate(path string) error {
-	if !isRunning() {
-		return fmt.Errorf("daemon not running (run 'temper start' first)")
+	if err := requireDaemon(); err != nil {
+		return err
 	}
 
 	url := fmt.Sprintf("%s/v1/specs/%s/validate", daemonAddr, path)
@@ -195,9 +334,165 @@ func cmdSpecValidate(path string) error {
 	return nil
 }
 
+func cmdSpecReview(path string) error {
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	fmt.Println("Reviewing spec...")
+	resp, err := daemonPost(daemonAddr+"/v1/specs/review/"+path, "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("review spec: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("spec not found: %s", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "review spec")
+	}
+
+	var review struct {
+		Summary  string `json:"summary"`
+		Findings []struct {
+			Kind             string `json:"kind"`
+			Severity         string `json:"severity"`
+			Target           string `json:"target"`
+			Message          string `json:"message"`
+			SuggestedRewrite string `json:"suggested_rewrite"`
+		} `json:"findings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&review); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if review.Summary != "" {
+		fmt.Printf("\n%s\n", review.Summary)
+	}
+	if len(review.Findings) == 0 {
+		fmt.Println("\n✓ No findings")
+		return nil
+	}
+
+	fmt.Printf("\nFindings (%d):\n", len(review.Findings))
+	for _, f := range review.Findings {
+		fmt.Printf("\n  [%s] %s — %s\n", f.Severity, f.Target, strings.ReplaceAll(f.Kind, "_", " "))
+		fmt.Printf("    %s\n", f.Message)
+		if f.SuggestedRewrite != "" {
+			fmt.Printf("    Suggested: %s\n", f.SuggestedRewrite)
+		}
+	}
+	fmt.Println("\nSuggestions are not applied; edit the spec to adopt them.")
+	return nil
+}
+
+func cmdSpecPlan(path string) error {
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	resp, err := daemonGet(daemonAddr + "/v1/specs/plan/" + path)
+	if err != nil {
+		return fmt.Errorf("plan spec: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("spec not found: %s", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "plan spec")
+	}
+
+	var plan struct {
+		Steps []struct {
+			Order     int      `json:"order"`
+			Stage     int      `json:"stage"`
+			Spec      string   `json:"spec"`
+			FeatureID string   `json:"feature_id"`
+			Title     string   `json:"title"`
+			Priority  string   `json:"priority"`
+			DependsOn []string `json:"depends_on"`
+			External  bool     `json:"external"`
+		} `json:"steps"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&plan); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if len(plan.Steps) == 0 {
+		fmt.Println("No features to plan")
+		return nil
+	}
+
+	stage := -1
+	for _, step := range plan.Steps {
+		if step.Stage != stage {
+			stage = step.Stage
+			fmt.Printf("\nStage %d:\n", stage+1)
+		}
+		line := fmt.Sprintf("  %d. [%s] %s", step.Order, step.FeatureID, step.Title)
+		if step.Priority != "" {
+			line += fmt.Sprintf(" (%s)", step.Priority)
+		}
+		if step.External {
+			line += fmt.Sprintf(" — from %s", step.Spec)
+		}
+		fmt.Println(line)
+		if len(step.DependsOn) > 0 {
+			fmt.Printf("     after: %s\n", strings.Join(step.DependsOn, ", "))
+		}
+	}
+	return nil
+}
+
+func cmdSpecDiagram(path, format string, write bool) error {
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{"format": format, "write": write})
+	resp, err := daemonPost(daemonAddr+"/v1/specs/diagram/"+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("diagram spec: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("spec not found: %s", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "diagram spec")
+	}
+
+	var diagram struct {
+		Diagram string `json:"diagram"`
+		Path    string `json:"path"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&diagram); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	fmt.Print(diagram.Diagram)
+	if diagram.Path != "" {
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", diagram.Path)
+	}
+	return nil
+}
+
 func cmdSpecStatus(path string) error {
-	if !isRunning() {
-		return fmt.Errorf("daemon not running (run 'temper start' first)")
+	if err := requireDaemon(); err != nil {
+		return err
 	}
 
 	// Get spec details
@@ -275,9 +570,105 @@ func cmdSpecStatus(path string) error {
 	return nil
 }
 
+func cmdSpecFeatureStatus(path, featureID string) error {
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	resp, err := daemonGet(daemonAddr + "/v1/specs/features/" + url.PathEscape(featureID) + "/progress/" + path)
+	if err != nil {
+		return fmt.Errorf("get feature progress: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "feature progress")
+	}
+
+	var progress struct {
+		Feature struct {
+			ID       string `json:"id"`
+			Title    string `json:"title"`
+			Priority string `json:"priority"`
+		} `json:"feature"`
+		Criteria []struct {
+			ID          string `json:"id"`
+			Description string `json:"description"`
+			Satisfied   bool   `json:"satisfied"`
+		} `json:"criteria"`
+		SatisfiedCriteria int     `json:"satisfied_criteria"`
+		PercentComplete   float64 `json:"percent_complete"`
+		Tests             []struct {
+			Name   string    `json:"name"`
+			Passed bool      `json:"passed"`
+			RunAt  time.Time `json:"run_at"`
+		} `json:"tests"`
+		RecentRuns []struct {
+			ID        string    `json:"id"`
+			CreatedAt time.Time `json:"created_at"`
+			Passed    int       `json:"passed"`
+			Failed    int       `json:"failed"`
+		} `json:"recent_runs"`
+		TouchedFiles []string `json:"touched_files"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&progress); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	title := fmt.Sprintf("[%s] %s", progress.Feature.ID, progress.Feature.Title)
+	fmt.Println(title)
+	fmt.Println(strings.Repeat("=", len(title)))
+
+	fmt.Println("\nAcceptance Criteria:")
+	if len(progress.Criteria) == 0 {
+		fmt.Println("  (none linked; list the feature under a criterion's features)")
+	}
+	for _, ac := range progress.Criteria {
+		status := "⏳"
+		if ac.Satisfied {
+			status = "✓"
+		}
+		fmt.Printf("  %s [%s] %s\n", status, ac.ID, ac.Description)
+	}
+
+	fmt.Println("\nTests:")
+	if len(progress.Tests) == 0 {
+		fmt.Println("  (no runs with tests naming this feature)")
+	}
+	for _, t := range progress.Tests {
+		status := "✗"
+		if t.Passed {
+			status = "✓"
+		}
+		fmt.Printf("  %s %s (%s)\n", status, t.Name, t.RunAt.Local().Format("Jan 2 15:04"))
+	}
+
+	if len(progress.RecentRuns) > 0 {
+		fmt.Println("\nRecent Runs:")
+		for _, run := range progress.RecentRuns {
+			fmt.Printf("  %s  %s  %d passed, %d failed\n",
+				run.CreatedAt.Local().Format("Jan 2 15:04"), shortID(run.ID), run.Passed, run.Failed)
+		}
+	}
+
+	if len(progress.TouchedFiles) > 0 {
+		fmt.Println("\nTouched Files:")
+		for _, f := range progress.TouchedFiles {
+			fmt.Printf("  %s\n", f)
+		}
+	}
+
+	bar := renderProgressBar(progress.PercentComplete/100, 30)
+	fmt.Printf("\nProgress: %s %d/%d (%.0f%%)\n", bar, progress.SatisfiedCriteria, len(progress.Criteria), progress.PercentComplete)
+	return nil
+}
+
 func cmdSpecLock(path string) error {
-	if !isRunning() {
-		return fmt.Errorf("daemon not running (run 'temper start' first)")
+	if err := requireDaemon(); err != nil {
+		return err
 	}
 
 	url := fmt.Sprintf("%s/v1/specs/%s/lock", daemonAddr, path)
@@ -317,8 +708,8 @@ func cmdSpecLock(path string) error {
 }
 
 func cmdSpecDrift(path string) error {
-	if !isRunning() {
-		return fmt.Errorf("daemon not running (run 'temper start' first)")
+	if err := requireDaemon(); err != nil {
+		return err
 	}
 
 	url := fmt.Sprintf("%s/v1/specs/%s/drift", daemonAddr, path)
@@ -384,3 +775,117 @@ func cmdSpecDrift(path string) error {
 
 	return nil
 }
+
+func cmdSpecSync(path string) error {
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	resp, err := daemonPost(daemonAddr+"/v1/specs/sync/"+path, "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("sync spec: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "sync spec")
+	}
+
+	var result struct {
+		Key       string   `json:"key"`
+		Checked   []string `json:"checked"`
+		Commented []string `json:"commented"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if len(result.Checked)+len(result.Commented) == 0 {
+		fmt.Printf("%s is up to date\n", result.Key)
+		return nil
+	}
+	fmt.Printf("✓ Synced %s\n", result.Key)
+	if len(result.Checked) > 0 {
+		fmt.Printf("  Checked:   %s\n", strings.Join(result.Checked, ", "))
+	}
+	if len(result.Commented) > 0 {
+		fmt.Printf("  Commented: %s\n", strings.Join(result.Commented, ", "))
+	}
+	return nil
+}
+
+func cmdSpecHistory(path string) error {
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	resp, err := daemonGet(daemonAddr + "/v1/specs/history/" + path)
+	if err != nil {
+		return fmt.Errorf("get spec history: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("spec not found: %s", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "get spec history")
+	}
+
+	var result struct {
+		Locks []struct {
+			Lock struct {
+				Version  string    `json:"version"`
+				LockedAt time.Time `json:"locked_at"`
+			} `json:"lock"`
+			Changes struct {
+				HasDrift         bool     `json:"has_drift"`
+				VersionChanged   bool     `json:"version_changed"`
+				OldVersion       string   `json:"old_version"`
+				NewVersion       string   `json:"new_version"`
+				AddedFeatures    []string `json:"added_features"`
+				RemovedFeatures  []string `json:"removed_features"`
+				ModifiedFeatures []string `json:"modified_features"`
+			} `json:"changes"`
+		} `json:"locks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if len(result.Locks) == 0 {
+		fmt.Println("Spec has never been locked (run 'temper spec lock' to start its history)")
+		return nil
+	}
+
+	for _, rec := range result.Locks {
+		fmt.Printf("\n%s  v%s\n", rec.Lock.LockedAt.Local().Format("2006-01-02 15:04"), rec.Lock.Version)
+		c := rec.Changes
+		if !c.HasDrift {
+			fmt.Println("  (no changes)")
+			continue
+		}
+		if c.VersionChanged && c.OldVersion != "" {
+			fmt.Printf("  ~ version %s -> %s\n", c.OldVersion, c.NewVersion)
+		}
+		for _, id := range c.AddedFeatures {
+			fmt.Printf("  + %s\n", id)
+		}
+		for _, id := range c.RemovedFeatures {
+			fmt.Printf("  - %s\n", id)
+		}
+		for _, id := range c.ModifiedFeatures {
+			fmt.Printf("  ~ %s\n", id)
+		}
+		if len(c.AddedFeatures)+len(c.RemovedFeatures)+len(c.ModifiedFeatures) == 0 && !c.VersionChanged {
+			fmt.Println("  ~ spec content changed (no feature changes)")
+		}
+	}
+	return nil
+}
@@ -3,12 +3,14 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 )
 
 // cmdStats shows learning statistics
 func cmdStats(args []string) error {
-	if !isRunning() {
-		return fmt.Errorf("daemon not running (run 'temper start' first)")
+	if err := requireDaemon(); err != nil {
+		return err
 	}
 
 	subCmd := "overview"
@@ -25,10 +27,22 @@ func cmdStats(args []string) error {
 		return cmdStatsErrors()
 	case "trend":
 		return cmdStatsTrend()
+	case "exercises":
+		return cmdStatsExercises(args[1:])
 	case "export":
 		return cmdStatsExport(args[1:])
+	case "pack":
+		return cmdStatsPack(args[1:])
+	case "achievements":
+		return cmdStatsAchievements(args[1:])
+	case "publish":
+		return cmdStatsPublish(args[1:])
+	case "snapshot":
+		return cmdStatsSnapshot(args[1:])
+	case "compare":
+		return cmdStatsCompare(args[1:])
 	default:
-		return fmt.Errorf("unknown stats command: %s (valid: overview, skills, errors, trend, export)", subCmd)
+		return fmt.Errorf("unknown stats command: %s (valid: overview, skills, errors, trend, exercises, pack, achievements, export, publish, snapshot, compare)", subCmd)
 	}
 }
 
@@ -60,29 +74,44 @@ func cmdStatsOverview() error {
 		return fmt.Errorf("parse response: %w", err)
 	}
 
-	fmt.Println("Learning Statistics")
-	fmt.Println("==================")
-	fmt.Printf("Total Sessions:     %d\n", overview.TotalSessions)
-	fmt.Printf("Completed:          %d (%.1f%%)\n", overview.CompletedSessions, overview.CompletionRate*100)
-	fmt.Printf("Total Exercises:    %d\n", overview.TotalExercises)
-	fmt.Printf("Total Runs:         %d\n", overview.TotalRuns)
-	fmt.Printf("Total Hints:        %d\n", overview.TotalHints)
-	fmt.Printf("Hint Dependency:    %.1f%%\n", overview.HintDependency*100)
-	fmt.Printf("Avg Time to Green:  %s\n", overview.AvgTimeToGreen)
+	p := cliPrinter()
+	printHeading(p.T("stats.title"), "=")
+	rows := [][2]string{
+		{p.T("stats.sessions"), fmt.Sprintf("%d", overview.TotalSessions)},
+		{p.T("stats.completed"), fmt.Sprintf("%d (%.1f%%)", overview.CompletedSessions, overview.CompletionRate*100)},
+		{p.T("stats.exercises"), fmt.Sprintf("%d", overview.TotalExercises)},
+		{p.T("stats.runs"), fmt.Sprintf("%d", overview.TotalRuns)},
+		{p.T("stats.hints"), fmt.Sprintf("%d", overview.TotalHints)},
+		{p.T("stats.hint_dependency"), fmt.Sprintf("%.1f%%", overview.HintDependency*100)},
+		{p.T("stats.time_to_green"), overview.AvgTimeToGreen},
+	}
+	width := 0
+	for _, row := range rows {
+		width = max(width, utf8.RuneCountInString(row[0]))
+	}
+	for _, row := range rows {
+		fmt.Printf("%s:%s %s\n", row[0], strings.Repeat(" ", width-utf8.RuneCountInString(row[0])+1), row[1])
+	}
 
 	if len(overview.MostPracticedTopics) > 0 {
-		fmt.Println("\nMost Practiced Topics")
-		fmt.Println("---------------------")
+		fmt.Println()
+		printHeading(p.T("stats.topics"), "-")
 		for _, topic := range overview.MostPracticedTopics {
 			bar := renderProgressBar(topic.Level, 20)
-			fmt.Printf("%-20s %s %.0f%% (%d attempts) %s\n",
-				topic.Topic, bar, topic.Level*100, topic.Attempts, topic.Trend)
+			fmt.Printf("%-20s %s %.0f%% (%s) %s\n",
+				topic.Topic, bar, topic.Level*100, p.T("stats.attempts", topic.Attempts), topic.Trend)
 		}
 	}
 
 	return nil
 }
 
+// printHeading prints a title underlined to its width
+func printHeading(title, underline string) {
+	fmt.Println(title)
+	fmt.Println(strings.Repeat(underline, utf8.RuneCountInString(title)))
+}
+
 func cmdStatsSkills() error {
 	resp, err := daemonGet(daemonAddr + "/v1/analytics/skills")
 	if err != nil {
@@ -109,18 +138,18 @@ func cmdStatsSkills() error {
 		return fmt.Errorf("parse response: %w", err)
 	}
 
-	fmt.Println("Skills by Topic")
-	fmt.Println("===============")
+	p := cliPrinter()
+	printHeading(p.T("stats.skills_title"), "=")
 
 	if len(breakdown.Skills) == 0 {
-		fmt.Println("No skills tracked yet. Start practicing!")
+		fmt.Println(p.T("stats.no_skills"))
 		return nil
 	}
 
 	for topic, skill := range breakdown.Skills {
 		bar := renderProgressBar(skill.Level, 20)
-		fmt.Printf("%-20s %s %.0f%% (%d attempts) %s\n",
-			topic, bar, skill.Level*100, skill.Attempts, skill.Trend)
+		fmt.Printf("%-20s %s %.0f%% (%s) %s\n",
+			topic, bar, skill.Level*100, p.T("stats.attempts", skill.Attempts), skill.Trend)
 	}
 
 	if len(breakdown.Progression) > 0 {
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// cmdStatsAchievements lists the achievements, earned ones with when and
+// how.
+//
+//	temper stats achievements
+//	temper stats achievements -json
+func cmdStatsAchievements(args []string) error {
+	fs := flag.NewFlagSet("stats achievements", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the raw list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	resp, err := daemonGet(daemonAddr + "/v1/achievements")
+	if err != nil {
+		return fmt.Errorf("get achievements: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "get achievements")
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if *asJSON {
+		out, _ := json.MarshalIndent(raw, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
+
+	var list struct {
+		Achievements []struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			Unlocked    *struct {
+				Evidence string    `json:"evidence"`
+				At       time.Time `json:"unlocked_at"`
+			} `json:"unlocked"`
+		} `json:"achievements"`
+		Unlocked int `json:"unlocked"`
+		Total    int `json:"total"`
+	}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	printHeading(fmt.Sprintf("Achievements (%d of %d)", list.Unlocked, list.Total), "=")
+	for _, a := range list.Achievements {
+		if a.Unlocked == nil {
+			fmt.Printf("  [ ] %s: %s\n", a.Name, a.Description)
+			continue
+		}
+		fmt.Printf("  [x] %s: %s\n", a.Name, a.Description)
+		fmt.Printf("      %s, %s\n", a.Unlocked.At.Local().Format("2006-01-02"), a.Unlocked.Evidence)
+	}
+	return nil
+}
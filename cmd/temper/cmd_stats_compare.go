@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/profile"
+)
+
+// cmdStatsSnapshot writes an anonymized skill snapshot to share with a
+// study group: topic levels and totals, without IDs, code or history.
+//
+//	temper stats snapshot -out ada.json
+func cmdStatsSnapshot(args []string) error {
+	fs := flag.NewFlagSet("stats snapshot", flag.ContinueOnError)
+	out := fs.String("out", "", "output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var prof profile.StoredProfile
+	if err := daemonGetJSON("/v1/profile", "get profile", &prof); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(profile.NewSnapshot(&prof, time.Now()), "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if *out == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", *out, err)
+	}
+	fmt.Printf("Wrote skill snapshot to %s\n", *out)
+	return nil
+}
+
+// cmdStatsCompare shows how a study group's skills are distributed, from
+// the members' snapshots, and where your own profile falls within them.
+//
+//	temper stats compare ada.json bo.json cy.json
+//	temper stats compare -no-self -json group/*.json
+func cmdStatsCompare(args []string) error {
+	fs := flag.NewFlagSet("stats compare", flag.ContinueOnError)
+	noSelf := fs.Bool("no-self", false, "don't place your own profile in the distributions")
+	asJSON := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: temper stats compare [--no-self] [--json] <snapshot.json...>")
+	}
+
+	snaps := make([]profile.SkillSnapshot, 0, fs.NArg())
+	for _, path := range fs.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read snapshot: %w", err)
+		}
+		var snap profile.SkillSnapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		snaps = append(snaps, snap)
+	}
+
+	var you *profile.SkillSnapshot
+	if !*noSelf {
+		var prof profile.StoredProfile
+		if err := daemonGetJSON("/v1/profile", "get profile", &prof); err != nil {
+			return err
+		}
+		you = profile.NewSnapshot(&prof, time.Now())
+	}
+
+	cmp, err := profile.CompareSnapshots(snaps, you)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cmp)
+	}
+
+	fmt.Printf("Study group comparison (%d learners)\n", cmp.Learners)
+	fmt.Println("==================================")
+	fmt.Println()
+	fmt.Printf("  %-24s %6s %6s %6s %6s %6s\n", "", "p25", "median", "p75", "you", "pct")
+	printDistributionRow("exercises completed", cmp.Completed, "%6.0f")
+	printDistributionRow("hint dependency", cmp.HintDependency, "%5.0f%%")
+
+	if len(cmp.Topics) > 0 {
+		fmt.Println()
+		fmt.Printf("  %-24s %6s %6s %6s %6s %6s\n", "Skill level", "p25", "median", "p75", "you", "pct")
+		for _, topic := range cmp.Topics {
+			printDistributionRow(fmt.Sprintf("%s (%d)", topic.Topic, topic.Learners), topic.Distribution, "%5.0f%%")
+		}
+	}
+	if cmp.Withheld > 0 {
+		fmt.Printf("\n%d topic(s) withheld: practiced by fewer than %d learners.\n", cmp.Withheld, profile.MinCompareGroup)
+	}
+	return nil
+}
+
+// printDistributionRow prints one line of the comparison table. Formats
+// ending in %% show 0.0-1.0 values as percentages.
+func printDistributionRow(label string, d profile.Distribution, format string) {
+	scale := 1.0
+	if format[len(format)-1] == '%' {
+		scale = 100
+	}
+	cell := func(v float64) string { return fmt.Sprintf(format, v*scale) }
+
+	you, pct := fmt.Sprintf("%6s", "-"), fmt.Sprintf("%6s", "-")
+	if d.You != nil {
+		you = cell(*d.You)
+		pct = fmt.Sprintf("%6d", *d.Percentile)
+	}
+	fmt.Printf("  %-24s %s %s %s %s %s\n", label, cell(d.P25), cell(d.Median), cell(d.P75), you, pct)
+}
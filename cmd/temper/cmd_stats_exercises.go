@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// cmdStatsExercises shows per-exercise outcomes and the difficulty they
+// suggest, for pack authors checking their labels.
+//
+//	temper stats exercises                       # from your own history
+//	temper stats exercises -cohort go-101        # from a cohort's shared exports
+//	temper stats exercises -pack go-v1 -miscalibrated
+func cmdStatsExercises(args []string) error {
+	fs := flag.NewFlagSet("stats exercises", flag.ContinueOnError)
+	cohortID := fs.String("cohort", "", "calibrate from a cohort's shared exports")
+	pack := fs.String("pack", "", "only exercises from this pack")
+	minAttempts := fs.Int("min-attempts", 0, "attempts needed before suggesting a difficulty (default 5)")
+	miscalibrated := fs.Bool("miscalibrated", false, "only exercises whose label looks wrong")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	if *cohortID != "" {
+		params.Set("cohort", *cohortID)
+	}
+	if *pack != "" {
+		params.Set("pack", *pack)
+	}
+	if *minAttempts > 0 {
+		params.Set("min_attempts", strconv.Itoa(*minAttempts))
+	}
+	if *miscalibrated {
+		params.Set("miscalibrated", "true")
+	}
+
+	resp, err := daemonGet(daemonAddr + "/v1/analytics/exercises?" + params.Encode())
+	if err != nil {
+		return fmt.Errorf("get exercise calibration: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "get exercise calibration")
+	}
+
+	var result struct {
+		Source      string `json:"source"`
+		Learners    int    `json:"learners"`
+		MinAttempts int    `json:"min_attempts"`
+		Exercises   []struct {
+			ExerciseID          string  `json:"exercise_id"`
+			Label               string  `json:"label"`
+			Attempts            int     `json:"attempts"`
+			MedianTimeToGreenMs int64   `json:"median_time_to_green_ms"`
+			MedianHints         float64 `json:"median_hints"`
+			AbandonmentRate     float64 `json:"abandonment_rate"`
+			Suggested           string  `json:"suggested"`
+			Miscalibrated       bool    `json:"miscalibrated"`
+		} `json:"exercises"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	printHeading("Exercise Calibration", "=")
+	fmt.Printf("Source: %s (%d learner(s))\n\n", result.Source, result.Learners)
+	if len(result.Exercises) == 0 {
+		fmt.Println("No finished attempts yet.")
+		return nil
+	}
+
+	fmt.Printf("%-36s %8s %10s %6s %9s  %-12s %-12s\n",
+		"EXERCISE", "ATTEMPTS", "TO GREEN", "HINTS", "ABANDONED", "LABEL", "SUGGESTED")
+	flagged := 0
+	for _, ex := range result.Exercises {
+		toGreen := "-"
+		if ex.MedianTimeToGreenMs > 0 {
+			toGreen = (time.Duration(ex.MedianTimeToGreenMs) * time.Millisecond).Round(time.Second).String()
+		}
+		label, suggested := orDash(ex.Label), orDash(ex.Suggested)
+		if ex.Miscalibrated {
+			suggested += " *"
+			flagged++
+		}
+		fmt.Printf("%-36s %8d %10s %6.1f %8.0f%%  %-12s %-12s\n",
+			ex.ExerciseID, ex.Attempts, toGreen, ex.MedianHints, ex.AbandonmentRate*100, label, suggested)
+	}
+
+	fmt.Printf("\nSuggestions need %d finished attempts.", result.MinAttempts)
+	if flagged > 0 {
+		fmt.Printf(" %d exercise(s) marked * may be mislabelled.", flagged)
+	}
+	fmt.Println()
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
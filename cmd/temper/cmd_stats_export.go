@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
 	"os"
 	"strings"
 	"time"
@@ -22,17 +21,23 @@ import (
 //	temper stats export -out usage.jsonl     # writes to file
 //	temper stats export -since 2026-01-01    # only attempts after date
 //	temper stats export -salt my-cohort-id   # change anonymization salt
+//	temper stats export -leaderboard -name ada  # join cohort leaderboards as "ada"
 func cmdStatsExport(args []string) error {
 	fs := flag.NewFlagSet("stats export", flag.ContinueOnError)
 	out := fs.String("out", "", "output file (default: stdout)")
 	since := fs.String("since", "", "only export attempts after this date (YYYY-MM-DD)")
 	salt := fs.String("salt", "temper-default-cohort", "anonymization salt for hashed IDs")
+	leaderboard := fs.Bool("leaderboard", false, "opt in to cohort leaderboards")
+	name := fs.String("name", "", "display name on cohort leaderboards (requires -leaderboard)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	if *name != "" && !*leaderboard {
+		return fmt.Errorf("-name only applies with -leaderboard")
+	}
 
-	if !isRunning() {
-		return fmt.Errorf("daemon not running (run 'temper start' first)")
+	if err := requireDaemon(); err != nil {
+		return err
 	}
 
 	resp, err := daemonGet(daemonAddr + "/v1/profile")
@@ -45,8 +50,7 @@ func cmdStatsExport(args []string) error {
 		return err
 	}
 	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("get profile: status=%d body=%s", resp.StatusCode, string(body))
+		return responseError(resp, "get profile")
 	}
 
 	var profile struct {
@@ -97,7 +101,7 @@ func cmdStatsExport(args []string) error {
 
 	enc := json.NewEncoder(w)
 
-	if err := enc.Encode(map[string]any{
+	summary := map[string]any{
 		"event":               "summary",
 		"profile_id":          anonymize(profile.ID, *salt),
 		"total_sessions":      profile.TotalSessions,
@@ -107,7 +111,12 @@ func cmdStatsExport(args []string) error {
 		"topic_skills":        profile.TopicSkills,
 		"error_patterns":      profile.ErrorPatterns,
 		"exported_at":         time.Now().UTC(),
-	}); err != nil {
+		"leaderboard":         *leaderboard,
+	}
+	if *name != "" {
+		summary["display_name"] = *name
+	}
+	if err := enc.Encode(summary); err != nil {
 		return err
 	}
 
@@ -151,9 +160,13 @@ func anonymize(id, salt string) string {
 }
 
 // topicFromExerciseID mirrors profile.ExtractTopic without requiring the
-// internal package import. "go-v1/basics/hello-world" → "go/basics".
+// internal package import. "go-v1/basics/hello-world" → "go/basics", and
+// an org pack's "acme/go-tools/basics/hello-world" → "go/basics".
 func topicFromExerciseID(id string) string {
 	parts := strings.Split(id, "/")
+	if len(parts) == 4 {
+		parts = parts[1:]
+	}
 	if len(parts) < 2 {
 		return "general"
 	}
@@ -45,6 +45,7 @@ func TestTopicFromExerciseID(t *testing.T) {
 		{"go-v1/concurrency/channels", "go/concurrency"},
 		{"python-v1/testing/pytest", "python/testing"},
 		{"typescript-v1/advanced/promises", "typescript/advanced"},
+		{"acme/go-tools/basics/hello-world", "go/basics"},
 		{"rust-v1/advanced", "rust"},
 		{"single-segment", "general"},
 		{"", "general"},
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// cmdStatsPack synthesizes your reviews and errors across one pack into
+// strengths and gaps.
+//
+//	temper stats pack go-v1
+//	temper stats pack go-v1 -json
+func cmdStatsPack(args []string) error {
+	fs := flag.NewFlagSet("stats pack", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the raw summary")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: temper stats pack <pack> [--json]")
+	}
+	pack := fs.Arg(0)
+
+	resp, err := daemonPost(daemonAddr+"/v1/analytics/packs/"+url.PathEscape(pack)+"/summary", "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("summarize pack: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "summarize pack")
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if *asJSON {
+		out, _ := json.MarshalIndent(raw, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
+
+	type finding struct {
+		Topic     string   `json:"topic"`
+		Message   string   `json:"message"`
+		Exercises []string `json:"exercises"`
+	}
+	var summary struct {
+		Attempted int       `json:"attempted"`
+		Completed int       `json:"completed"`
+		Summary   string    `json:"summary"`
+		Strengths []finding `json:"strengths"`
+		Gaps      []finding `json:"gaps"`
+	}
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	printHeading("Pack Summary: "+pack, "=")
+	fmt.Printf("%d of %d attempted exercises completed\n", summary.Completed, summary.Attempted)
+	if summary.Summary != "" {
+		fmt.Printf("\n%s\n", summary.Summary)
+	}
+	for _, section := range []struct {
+		title    string
+		findings []finding
+	}{
+		{"Strengths", summary.Strengths},
+		{"Gaps", summary.Gaps},
+	} {
+		fmt.Println()
+		printHeading(section.title, "-")
+		if len(section.findings) == 0 {
+			fmt.Println("  none found")
+			continue
+		}
+		for _, f := range section.findings {
+			fmt.Printf("  %s: %s\n", f.Topic, f.Message)
+			if len(f.Exercises) > 0 {
+				fmt.Printf("    seen in %s\n", strings.Join(f.Exercises, ", "))
+			}
+		}
+	}
+	return nil
+}
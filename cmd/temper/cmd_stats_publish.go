@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/profile"
+	"github.com/felixgeelhaar/temper/internal/publish"
+)
+
+// cmdStatsPublish renders a static progress page: a skill radar, practice
+// streaks and completed packs. The page is built from the local profile
+// and has no scripts or external assets, so it can be hosted anywhere
+// or pasted into a portfolio with -fragment.
+//
+//	temper stats publish                       # writes progress.html
+//	temper stats publish -out site/temper.html -name "Ada L."
+//	temper stats publish -fragment -out progress-snippet.html
+func cmdStatsPublish(args []string) error {
+	fs := flag.NewFlagSet("stats publish", flag.ContinueOnError)
+	out := fs.String("out", "progress.html", "output file")
+	name := fs.String("name", "", "name shown on the page (default: anonymous)")
+	fragment := fs.Bool("fragment", false, "write a <div> to embed in another page instead of a full document")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var prof profile.StoredProfile
+	if err := daemonGetJSON("/v1/profile", "get profile", &prof); err != nil {
+		return err
+	}
+	var catalog struct {
+		Packs []struct {
+			ID        string `json:"id"`
+			Name      string `json:"name"`
+			Exercises []struct {
+				ID string `json:"id"`
+			} `json:"exercises"`
+		} `json:"packs"`
+	}
+	if err := daemonGetJSON("/v1/exercises?limit=500", "list exercises", &catalog); err != nil {
+		return err
+	}
+
+	packs := make([]publish.Pack, 0, len(catalog.Packs))
+	for _, p := range catalog.Packs {
+		pack := publish.Pack{ID: p.ID, Name: p.Name}
+		for _, ex := range p.Exercises {
+			pack.Exercises = append(pack.Exercises, ex.ID)
+		}
+		packs = append(packs, pack)
+	}
+
+	var buf bytes.Buffer
+	page := publish.Build(&prof, packs, *name, time.Now())
+	if err := publish.Render(&buf, page, *fragment); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("write %s: %w", *out, err)
+	}
+
+	fmt.Printf("Wrote progress page to %s (%d skills, %d packs)\n", *out, len(page.Skills), len(page.Packs))
+	return nil
+}
+
+// daemonGetJSON fetches a daemon endpoint and decodes its JSON body into v
+func daemonGetJSON(path, action string, v any) error {
+	resp, err := daemonGet(daemonAddr + path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, action)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// apiToken mirrors a scoped token as the daemon lists it
+type apiToken struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Scope     string    `json:"scope"`
+	CreatedAt time.Time `json:"created_at"`
+	Token     string    `json:"token,omitempty"` // only when created
+}
+
+// cmdToken manages scoped tokens for dashboards and scripts
+//
+//	temper token create --scope analytics --name grafana
+//	temper token list
+//	temper token revoke <id>
+func cmdToken(args []string) error {
+	sub := ""
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+	if sub == "" {
+		fmt.Println(`Token commands:
+
+  temper token create --scope analytics|sessions|admin [--name NAME]
+                                Create a scoped token (shown once)
+  temper token list             List scoped tokens
+  temper token revoke <id>      Revoke a token`)
+		return nil
+	}
+	if err := requireDaemon(); err != nil {
+		return err
+	}
+
+	switch sub {
+	case "create":
+		return cmdTokenCreate(args)
+	case "list":
+		return cmdTokenList()
+	case "revoke":
+		return cmdTokenRevoke(args)
+	default:
+		return fmt.Errorf("unknown token command: %s (valid: create, list, revoke)", sub)
+	}
+}
+
+func cmdTokenCreate(args []string) error {
+	fs := flag.NewFlagSet("token create", flag.ContinueOnError)
+	scope := fs.String("scope", "", "what the token may do: analytics (read-only), sessions or admin")
+	name := fs.String("name", "", "a label to tell tokens apart")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *scope == "" {
+		return fmt.Errorf("usage: temper token create --scope analytics|sessions|admin [--name NAME]")
+	}
+
+	body, _ := json.Marshal(map[string]string{"name": *name, "scope": *scope})
+	resp, err := daemonPost(daemonAddr+"/v1/admin/tokens", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 201 {
+		return responseError(resp, "create token")
+	}
+
+	var t apiToken
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Created %s token %s. It won't be shown again:\n", t.Scope, t.ID)
+	fmt.Println(t.Token)
+	return nil
+}
+
+func cmdTokenList() error {
+	resp, err := daemonGet(daemonAddr + "/v1/admin/tokens")
+	if err != nil {
+		return fmt.Errorf("list tokens: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "list tokens")
+	}
+
+	var result struct {
+		Tokens []apiToken `json:"tokens"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if len(result.Tokens) == 0 {
+		fmt.Println("No scoped tokens. Create one with `temper token create --scope analytics`.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSCOPE\tNAME\tCREATED")
+	for _, t := range result.Tokens {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.ID, t.Scope, t.Name, t.CreatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return tw.Flush()
+}
+
+func cmdTokenRevoke(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: temper token revoke <id>")
+	}
+
+	resp, err := daemonDelete(daemonAddr + "/v1/admin/tokens/" + url.PathEscape(args[0]))
+	if err != nil {
+		return fmt.Errorf("revoke token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := authError(resp); err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return responseError(resp, "revoke token")
+	}
+	fmt.Printf("Revoked token %s\n", args[0])
+	return nil
+}
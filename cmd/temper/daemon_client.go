@@ -1,9 +1,12 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/felixgeelhaar/temper/internal/config"
 )
@@ -52,6 +55,18 @@ func daemonPost(url, contentType string, body io.Reader) (*http.Response, error)
 	return http.DefaultClient.Do(req)
 }
 
+// daemonDelete issues an authenticated DELETE request to the daemon.
+func daemonDelete(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if t := daemonToken(); t != "" {
+		req.Header.Set("Authorization", "Bearer "+t)
+	}
+	return http.DefaultClient.Do(req)
+}
+
 // authError returns true if the response indicates the bearer token is
 // missing or wrong, with a CLI-friendly hint.
 func authError(resp *http.Response) error {
@@ -60,3 +75,104 @@ func authError(resp *http.Response) error {
 	}
 	return fmt.Errorf("daemon rejected the request as unauthorized. Run `temper init` to generate a token")
 }
+
+// apiError is the daemon's error envelope
+type apiError struct {
+	Status            int     `json:"status"`
+	Message           string  `json:"error"`
+	Code              string  `json:"error_code"`
+	Details           string  `json:"details"`
+	CorrelationID     string  `json:"correlation_id"`
+	CooldownRemaining float64 `json:"cooldown_remaining"`
+}
+
+func (e *apiError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Message)
+	if e.Details != "" && e.Details != e.Message {
+		b.WriteString(": " + e.Details)
+	}
+	if hint := e.hint(); hint != "" {
+		b.WriteString("\n  " + hint)
+	}
+	return b.String()
+}
+
+// hint suggests what to do about an error, keyed on its code
+func (e *apiError) hint() string {
+	switch e.Code {
+	case "UNAUTHORIZED":
+		return "Run `temper init` to generate a token."
+	case "LLM_UNAVAILABLE", "PROVIDER_NOT_FOUND":
+		return "Configure a provider with `temper provider set-key`."
+	case "COOLDOWN_ACTIVE":
+		return fmt.Sprintf("Try again in %.0f seconds.", e.CooldownRemaining)
+	case "QUOTA_EXHAUSTED":
+		return "Raise the provider's quota in ~/.temper/config.yaml, set at_hard_cap: local, or wait for next month."
+	case "HINT_BUDGET_EXHAUSTED":
+		return "Keep going on your own, or start a new session for a fresh budget."
+	case "SPEC_INVALID":
+		return "Run `temper spec validate` to see what is missing."
+	case "INTERNAL_ERROR":
+		if e.CorrelationID != "" {
+			return fmt.Sprintf("Search `temper logs` for request %s.", e.CorrelationID)
+		}
+	}
+	return ""
+}
+
+// responseError turns a failed daemon response into an error prefixed with
+// action. Bodies that are not an error envelope are shown raw.
+func responseError(resp *http.Response, action string) error {
+	data, _ := io.ReadAll(resp.Body)
+	var e apiError
+	if err := json.Unmarshal(data, &e); err != nil || e.Message == "" {
+		return fmt.Errorf("%s: status=%d body=%s", action, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return fmt.Errorf("%s: %w", action, &e)
+}
+
+// capability is a feature's mode in the daemon's capability matrix
+type capability struct {
+	Mode   string `json:"mode"`
+	Reason string `json:"reason"`
+}
+
+// daemonCapabilities returns what the daemon can do right now, keyed by
+// feature: runs, pairing, specs and analytics. Daemons that predate the
+// matrix return an empty map.
+func daemonCapabilities() (map[string]capability, error) {
+	resp, err := daemonGet(daemonAddr + "/v1/status")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp, "get status")
+	}
+	var status struct {
+		Capabilities map[string]capability `json:"capabilities"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("parse status: %w", err)
+	}
+	return status.Capabilities, nil
+}
+
+// checkCapability fails fast when feature is in one of the modes blocked,
+// rather than sending a request the daemon already knows will fail. When
+// the matrix can't be read the request goes ahead and reports for itself.
+func checkCapability(feature, action string, blocked ...string) error {
+	caps, err := daemonCapabilities()
+	if err != nil {
+		return nil
+	}
+	c, ok := caps[feature]
+	if !ok || !slices.Contains(blocked, c.Mode) {
+		return nil
+	}
+	if c.Reason == "" {
+		return fmt.Errorf("%s: %s is %s", action, feature, c.Mode)
+	}
+	return fmt.Errorf("%s: %s is %s (%s)", action, feature, c.Mode, c.Reason)
+}
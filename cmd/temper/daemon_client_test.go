@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func errorResponse(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestResponseError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   []string
+	}{
+		{
+			name:   "envelope with hint",
+			status: http.StatusTooManyRequests,
+			body:   `{"error":"cooldown active","error_code":"COOLDOWN_ACTIVE","status":429,"cooldown_remaining":42}`,
+			want:   []string{"hint: cooldown active", "Try again in 42 seconds."},
+		},
+		{
+			name:   "internal error points at the logs",
+			status: http.StatusInternalServerError,
+			body:   `{"error":"failed to load spec","error_code":"INTERNAL_ERROR","details":"disk full","correlation_id":"req-7"}`,
+			want:   []string{"hint: failed to load spec: disk full", "request req-7"},
+		},
+		{
+			name:   "not an envelope",
+			status: http.StatusBadGateway,
+			body:   "upstream down\n",
+			want:   []string{"hint: status=502 body=upstream down"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := responseError(errorResponse(tt.status, tt.body), "hint")
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q missing %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseError_Unwraps(t *testing.T) {
+	err := responseError(errorResponse(http.StatusNotFound, `{"error":"session not found","error_code":"SESSION_NOT_FOUND"}`), "get session")
+
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.Code != "SESSION_NOT_FOUND" {
+		t.Errorf("errors.As() = %v, want SESSION_NOT_FOUND", apiErr)
+	}
+}
+
+func TestCheckCapability(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"status":"running","capabilities":{"runs":{"mode":"unavailable","reason":"docker unreachable"},"pairing":{"mode":"streaming"}}}`)
+	}))
+	defer srv.Close()
+	defer func(addr string) { daemonAddr = addr }(daemonAddr)
+	daemonAddr = srv.URL
+
+	if err := checkCapability("runs", "verify", "unavailable"); err == nil || !strings.Contains(err.Error(), "docker unreachable") {
+		t.Errorf("checkCapability(runs) = %v, want the reason runs are unavailable", err)
+	}
+	if err := checkCapability("pairing", "replay", "offline"); err != nil {
+		t.Errorf("checkCapability(pairing) = %v, want nil while streaming", err)
+	}
+	if err := checkCapability("analytics", "stats", "unavailable"); err != nil {
+		t.Errorf("checkCapability(analytics) = %v, want nil when the daemon doesn't report it", err)
+	}
+}
@@ -1,65 +1,136 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/locale"
 )
 
 // Version is set at build time via ldflags
 var Version = "dev"
 
-const (
-	daemonAddr = "http://127.0.0.1:7432"
-	pidFile    = "temperd.pid"
-)
+const pidFile = "temperd.pid"
+
+// daemonAddr is the daemon's base URL, resolved by discoverDaemon
+var daemonAddr = "http://127.0.0.1:7432"
+
+// discoverDaemon points daemonAddr at the running daemon, which may not be
+// on the configured port
+func discoverDaemon() {
+	daemonAddr = config.DaemonURL()
+}
+
+// cliPrinter translates CLI output into the learner's locale
+func cliPrinter() *locale.Printer {
+	var configured string
+	if cfg, err := config.LoadLocalConfig(); err == nil {
+		configured = cfg.Locale
+	}
+	return locale.NewPrinter(locale.Resolve(configured))
+}
 
 func main() {
+	discoverDaemon()
 	if len(os.Args) < 2 {
+		if stdinIsTerminal() {
+			if err := cmdPalette(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
 		printUsage()
 		os.Exit(1)
 	}
 
-	var err error
-	switch os.Args[1] {
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, errUnknownCommand) {
+			fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", os.Args[1])
+			printUsage()
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+var errUnknownCommand = errors.New("unknown command")
+
+// run dispatches a command line without the program name
+func run(args []string) error {
+	switch args[0] {
 	case "init":
-		err = cmdInit()
+		return cmdInit(args[1:])
 	case "start":
-		err = cmdStart()
+		return cmdStart(args[1:])
 	case "stop":
-		err = cmdStop()
+		return cmdStop()
 	case "status":
-		err = cmdStatus()
+		return cmdStatus()
 	case "logs":
-		err = cmdLogs()
+		return cmdLogs()
+	case "ui":
+		return cmdUI()
 	case "doctor":
-		err = cmdDoctor()
+		return cmdDoctor()
 	case "config":
-		err = cmdConfig()
+		return cmdConfig(args[1:])
 	case "provider":
-		err = cmdProvider(os.Args[2:])
+		return cmdProvider(args[1:])
+	case "runner":
+		return cmdRunner(args[1:])
 	case "exercise":
-		err = cmdExercise(os.Args[2:])
+		return cmdExercise(args[1:])
 	case "spec":
-		err = cmdSpec(os.Args[2:])
+		return cmdSpec(args[1:])
 	case "stats":
-		err = cmdStats(os.Args[2:])
+		return cmdStats(args[1:])
+	case "prompt":
+		return cmdPrompt(args[1:])
+	case "review":
+		return cmdReview(args[1:])
+	case "history":
+		return cmdHistory(args[1:])
+	case "cohort":
+		return cmdCohort(args[1:])
+	case "cards":
+		return cmdCards(args[1:])
+	case "remind":
+		return cmdRemind(args[1:])
+	case "admin":
+		return cmdAdmin(args[1:])
+	case "token":
+		return cmdToken(args[1:])
+	case "profile":
+		return cmdProfile(args[1:])
+	case "hook":
+		return cmdHook(args[1:])
+	case "ci":
+		return cmdCI(args[1:])
 	case "mcp":
-		err = cmdMCP()
+		return cmdMCP()
+	case "mockd":
+		return cmdMockd(args[1:])
+	case "replay":
+		return cmdReplay(args[1:])
+	case "devcontainer":
+		return cmdDevcontainer(args[1:])
+	case "completion":
+		return cmdCompletion(args[1:])
+	case "__complete":
+		return cmdComplete(args[1:])
 	case "help", "-h", "--help":
 		printUsage()
 	case "version", "-v", "--version":
 		fmt.Printf("temper %s\n", Version)
 	default:
-		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", os.Args[1])
-		printUsage()
-		os.Exit(1)
-	}
-
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		return errUnknownCommand
 	}
+	return nil
 }
 
 func printUsage() {
@@ -67,22 +138,39 @@ func printUsage() {
 
 Usage:
   temper <command> [arguments]
+  temper                 Pick a command, exercise, session or spec interactively
 
 Setup Commands:
   init            Initialize Temper (first-time setup)
   doctor          Check system requirements
   config          Show current configuration
+  config locale   Show or set the language for hints and CLI output
   provider        Manage LLM providers
+  runner pull     Pull (and optionally pin) the runner image
+  runner verify   Check the runner image's Go toolchain
+  admin prune     Delete history past the retention policy (--dry-run to preview)
+  admin migrate   Upgrade ~/.temper data to this version's format (--dry-run to preview)
+  profile reset   Erase learning history, or a topic or date range of it (config is kept)
+  devcontainer generate
+                  Write a devcontainer for VS Code and Codespaces
 
 Daemon Commands:
-  start           Start the Temper daemon
+  start           Start the Temper daemon (--foreground, --embedded)
   stop            Stop the Temper daemon
   status          Show daemon status
   logs            View daemon logs
+  ui              Open the web dashboard
 
 Exercise Commands:
   exercise list   List available exercises
   exercise info   Show exercise details
+  exercise solution
+                  Show the reference solution once completed (--force before)
+  exercise seal   Encrypt solutions in exercise files
+  exercise install
+                  Install a pack from an archive URL or git+https:// repository
+  exercise updates
+                  List staged pack updates; apply with exercise update <pack>
 
 Spec Commands (Specular format):
   spec create     Create a new spec scaffold
@@ -90,15 +178,36 @@ Spec Commands (Specular format):
   spec validate   Validate spec completeness
   spec status     Show spec progress
   spec lock       Generate SpecLock for drift detection
+  hook install    Block commits when a locked spec drifted or criteria lack evidence
+  ci verify       Verify specs in a pipeline, without a running daemon
 
 Analytics Commands:
   stats           Show learning statistics (overview)
   stats skills    Show skill progression by topic
   stats errors    Show common error patterns
   stats trend     Show hint dependency over time
+  stats exercises Suggest difficulty labels from learner outcomes
+  stats pack      Summarize strengths and gaps across a pack
+  stats achievements  Milestones earned and still to earn
+  stats publish   Render a static progress page to share
+  stats snapshot  Write an anonymized skill snapshot for a study group
+  stats compare   Compare a study group's skill snapshots
+  history search  Search past sessions, run output and hints
+  cohort          Cohort leaderboards and assignments
+  remind          Show your practice streak and due reviews
+  remind watch    Show practice reminders as desktop notifications
+  cards           Flashcards from your mistakes (generate, review, export)
 
 Integration Commands:
   mcp             Start MCP server (for Cursor integration)
+  completion      Generate shell completion (bash, zsh, fish)
+  prompt preview  Show the prompt a hint would send, without calling the LLM
+  review          Review a session's code (--format sarif for code scanning)
+  mockd           Serve the daemon API with canned responses (plugin tests)
+  replay          Re-ask a session's hints with another provider and diff them
+  token create    Create a scoped token for a dashboard or script (--scope)
+  token list      List scoped tokens
+  token revoke    Revoke a scoped token
 
 Other:
   help            Show this help message
@@ -109,6 +218,8 @@ Examples:
   temper doctor                   # Check Docker, LLM providers
   temper provider set-key claude  # Configure Claude API key
   temper exercise list            # List exercises
+  temper history search "nil map" # When did I last hit this?
+  temper review --format sarif --out review.sarif  # For GitHub code scanning
   temper mcp                      # Start MCP server for Cursor`)
 }
 
@@ -8,16 +8,45 @@ M.config = {
 	host = "127.0.0.1",
 	port = 7432,
 	timeout = 30000, -- ms
+	-- Follow the address the daemon records in ~/.temper/daemon.json.
+	-- setup() turns this off when a port is configured explicitly.
+	discover = true,
 }
 
+-- Read the running daemon's address, which may not be the default port
+function M.discover()
+	local path = vim.fn.expand("~/.temper/daemon.json")
+	if vim.fn.filereadable(path) == 0 then
+		return nil
+	end
+	local ok, info = pcall(vim.fn.json_decode, table.concat(vim.fn.readfile(path), ""))
+	if not ok or type(info) ~= "table" or type(info.addr) ~= "string" then
+		return nil
+	end
+	local host, port = info.addr:match("^%[?(.-)%]?:(%d+)$")
+	if not host then
+		return nil
+	end
+	if host == "" or host == "0.0.0.0" or host == "::" then
+		host = "127.0.0.1"
+	end
+	return host, tonumber(port)
+end
+
 -- Build base URL
-local function base_url()
+function M.base_url()
+	if M.config.discover then
+		local host, port = M.discover()
+		if host then
+			return string.format("http://%s:%d", host, port)
+		end
+	end
 	return string.format("http://%s:%d", M.config.host, M.config.port)
 end
 
 -- Make HTTP request using curl (works on all platforms)
 local function request(method, path, body, callback)
-	local url = base_url() .. path
+	local url = M.base_url() .. path
 	local cmd = { "curl", "-s", "-X", method }
 
 	-- Add headers
@@ -72,6 +101,18 @@ function M.status(callback)
 	request("GET", "/v1/status", nil, callback)
 end
 
+-- Get the daemon's capability matrix: runs, pairing, specs and analytics,
+-- each with a mode and, when degraded, a reason. Older daemons report none.
+function M.capabilities(callback)
+	M.status(function(err, result)
+		if err then
+			callback(err, nil)
+			return
+		end
+		callback(nil, (result and result.capabilities) or {})
+	end)
+end
+
 -- List exercises
 function M.list_exercises(callback)
 	request("GET", "/v1/exercises", nil, callback)
@@ -84,9 +125,12 @@ function M.get_exercise(pack, slug, callback)
 end
 
 -- Create session
--- opts can have: exercise_id, spec_path, intent, track
+-- opts can have: exercise_id, spec_path, intent, track, workspace_root
+-- workspace_root defaults to the working directory so its .temper.yaml applies
 function M.create_session(opts, callback)
-	local body = {}
+	local body = {
+		workspace_root = opts.workspace_root or vim.fn.getcwd(),
+	}
 	if opts.exercise_id then
 		body.exercise_id = opts.exercise_id
 	end
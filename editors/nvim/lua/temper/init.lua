@@ -12,6 +12,8 @@ M.state = {
 	session_id = nil,
 	exercise_id = nil,
 	track = "practice",
+	-- What the daemon can do right now, refreshed when a session starts
+	capabilities = {},
 }
 
 -- Default configuration
@@ -47,6 +49,15 @@ end
 
 M.session_hint_text = session_hint
 
+-- Returns the capability for feature if the daemon reported it in mode
+local function capability_in(feature, mode)
+	local c = M.state.capabilities[feature]
+	if c and c.mode == mode then
+		return c
+	end
+	return nil
+end
+
 local function require_session()
 	if not M.state.session_id then
 		ui.notify("No active session. " .. session_hint(), vim.log.levels.WARN)
@@ -63,6 +74,7 @@ function M.setup(opts)
 	-- Update client config
 	client.config.host = M.config.host
 	client.config.port = M.config.port
+	client.config.discover = opts.port == nil
 
 	-- Update UI config
 	ui.config.panel_width = M.config.panel_width
@@ -81,7 +93,7 @@ function M.setup(opts)
 			vim.api.nvim_create_autocmd("BufWritePost", {
 				pattern = "*.go",
 				callback = function()
-					if M.state.session_id then
+					if M.state.session_id and not capability_in("runs", "unavailable") then
 						M.run()
 					end
 				end,
@@ -97,12 +109,7 @@ function M.setup(opts)
 			callback = function()
 				if M.state.session_id then
 					-- Synchronous cleanup so it completes before exit
-					local url = string.format(
-						"http://%s:%d/v1/sessions/%s",
-						M.config.host,
-						M.config.port,
-						M.state.session_id
-					)
+					local url = client.base_url() .. "/v1/sessions/" .. M.state.session_id
 					vim.fn.system({ "curl", "-s", "-X", "DELETE", "--max-time", "2", url })
 					M.state.session_id = nil
 					M.state.exercise_id = nil
@@ -405,6 +412,7 @@ function M._start_spec_session(spec_path)
 		M.state.spec_path = spec_path
 		ui.show_session(result)
 		ui.notify("Spec session started: " .. session_id:sub(1, 8))
+		M.refresh_capabilities()
 	end)
 end
 
@@ -433,6 +441,26 @@ function M._start_exercise_session(exercise_id)
 		M.state.exercise_id = exercise_id
 		ui.show_session(result)
 		ui.notify("Session started: " .. session_id:sub(1, 8))
+		M.refresh_capabilities()
+	end)
+end
+
+-- Refresh what the daemon can do, and say up front what won't work rather
+-- than letting each request fail
+function M.refresh_capabilities(callback)
+	client.capabilities(function(err, caps)
+		M.state.capabilities = (not err and caps) or {}
+		local runs = capability_in("runs", "unavailable")
+		if runs then
+			ui.notify("Runs are unavailable: " .. (runs.reason or "no runner"), vim.log.levels.WARN)
+		end
+		local pairing = capability_in("pairing", "offline")
+		if pairing then
+			ui.notify("Pairing is offline: " .. (pairing.reason or "no LLM provider"), vim.log.levels.WARN)
+		end
+		if callback then
+			callback(M.state.capabilities)
+		end
 	end)
 end
 
@@ -548,7 +576,11 @@ function M.request_intervention(intent, request_fn)
 	end
 
 	local code = get_buffer_code()
-	ui.show_loading("Requesting " .. intent .. "...")
+	if capability_in("pairing", "offline") then
+		ui.show_loading("Requesting " .. intent .. " (offline: static hints)...")
+	else
+		ui.show_loading("Requesting " .. intent .. "...")
+	end
 
 	request_fn(M.state.session_id, code, function(err, result)
 		if err then
@@ -575,6 +607,11 @@ function M.run()
 	if not require_session() then
 		return
 	end
+	local runs = capability_in("runs", "unavailable")
+	if runs then
+		ui.notify("Runs are unavailable: " .. (runs.reason or "no runner"), vim.log.levels.ERROR)
+		return
+	end
 
 	local code = get_buffer_code()
 	ui.show_loading("Running checks...")
@@ -685,7 +722,22 @@ end
 function M.health_check()
 	client.is_running(function(running)
 		if running then
-			ui.notify("Daemon is healthy", vim.log.levels.INFO)
+			client.capabilities(function(err, caps)
+				caps = (not err and caps) or {}
+				M.state.capabilities = caps
+				local degraded = {}
+				for _, feature in ipairs({ "runs", "pairing", "specs", "analytics" }) do
+					local c = caps[feature]
+					if c and c.reason then
+						table.insert(degraded, string.format("%s: %s (%s)", feature, c.mode, c.reason))
+					end
+				end
+				if #degraded == 0 then
+					ui.notify("Daemon is healthy", vim.log.levels.INFO)
+				else
+					ui.notify("Daemon is running, degraded:\n" .. table.concat(degraded, "\n"), vim.log.levels.WARN)
+				end
+			end)
 		else
 			ui.notify("Daemon is not running. Start with: temper start", vim.log.levels.ERROR)
 		end
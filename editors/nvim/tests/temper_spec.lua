@@ -174,6 +174,12 @@ describe("temper.client", function()
 			local actual = "http://" .. client.config.host .. ":" .. client.config.port
 			assert.equals(expected, actual)
 		end)
+
+		it("should use the configured address when discovery is off", function()
+			client.config.discover = false
+			client.config.port = 7500
+			assert.equals("http://127.0.0.1:7500", client.base_url())
+		end)
 	end)
 end)
 
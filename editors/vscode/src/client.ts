@@ -1,4 +1,8 @@
+import * as fs from 'fs';
 import * as http from 'http';
+import * as os from 'os';
+import * as path from 'path';
+import { EditEvent } from './edits';
 
 export interface Config {
     host: string;
@@ -11,12 +15,48 @@ export interface Session {
     code: Record<string, string>;
     policy: LearningPolicy;
     status: string;
+    language?: string;                   // what runs build and test the code as
+    languages?: Record<string, string>;  // per file
+    approach?: ApproachReport;           // set on completion
     run_count: number;
     hint_count: number;
     created_at: string;
     updated_at: string;
 }
 
+// How a finished solution compares with the exercise's reference and
+// alternative approaches
+export interface ApproachComparison {
+    name: string;
+    description?: string;
+    similarity: number;  // 0 to 1
+    shared?: string[];
+    untried?: string[];  // idioms it uses that the learner's code doesn't
+}
+
+export interface ApproachReport {
+    idioms: string[];
+    reference?: ApproachComparison;
+    alternatives?: ApproachComparison[];
+}
+
+// An import the formatter added to or removed from a file
+export interface ImportChange {
+    file: string;
+    path: string;
+    change: 'added' | 'removed';
+}
+
+/** A session's hint cooldown; a paused one has no end until the session resumes */
+export interface CooldownState {
+    active: boolean;
+    paused?: boolean;
+    remaining_seconds: number;
+    ends_at?: string;
+    cooldown_seconds: number;
+    next_level: number;
+}
+
 export interface LearningPolicy {
     max_level: number;
     patching_enabled: boolean;
@@ -24,6 +64,40 @@ export interface LearningPolicy {
     track: string;
 }
 
+/** Replaces a range of a file; lines and columns start at 1, columns count bytes */
+export interface TextEdit {
+    start_line: number;
+    start_column: number;
+    end_line: number;
+    end_column: number;
+    new_text: string;
+}
+
+/** A one-click fix for a compile error */
+export interface QuickFix {
+    kind: 'add_import' | 'remove_import' | 'blank_variable';
+    title: string;
+    file: string;
+    line: number;
+    message: string;
+    edits: TextEdit[];
+}
+
+/** Build or test output cut down to its start and end in the run record */
+export interface SpilledOutput {
+    stream: 'build' | 'test';
+    size: number;
+    stored: boolean;
+}
+
+/** A file a run collected, downloadable from the run */
+export interface RunArtifact {
+    name: string;
+    kind: 'binary' | 'coverage' | 'file';
+    path: string;
+    size: number;
+}
+
 export interface RunResult {
     id: string;
     session_id: string;
@@ -32,8 +106,25 @@ export interface RunResult {
         format_diff?: string;
         build_ok: boolean;
         build_output?: string;
+        quick_fixes?: QuickFix[];
         test_ok: boolean;
         test_output?: string;
+        /** Output too long for the run record; GET .../runs/{run}/output serves it whole */
+        spilled_output?: SpilledOutput[];
+        command?: {
+            name: string;
+            command: string;
+            ok: boolean;
+            exit_code: number;
+            output?: string;
+            duration: number;
+        };
+        artifacts?: {
+            ok: boolean;
+            output?: string;
+            files?: RunArtifact[];
+            missing?: string[];
+        };
         duration: number;
     };
 }
@@ -44,6 +135,39 @@ export interface Intervention {
     level: number;
     type: string;
     content: string;
+    diffs?: FileDiff[];     // L3+ code suggestions against the submitted files
+}
+
+/** One line of a suggestion diff */
+export interface DiffLine {
+    kind: 'context' | 'add' | 'remove';
+    old_line?: number;
+    new_line?: number;
+    text: string;
+}
+
+/** A suggested change to one file, as a unified patch and line by line */
+export interface FileDiff {
+    file: string;
+    change: string;         // added | modified
+    additions: number;
+    deletions: number;
+    patch: string;
+    lines?: DiffLine[];
+}
+
+/** A feature's mode in the daemon's capability matrix */
+export interface Capability {
+    mode: string;
+    reason?: string;
+}
+
+/** What the daemon can do right now; older daemons report nothing */
+export interface Capabilities {
+    runs?: Capability;      // docker | local | unavailable
+    pairing?: Capability;   // streaming | sync | offline
+    specs?: Capability;     // ok | unavailable
+    analytics?: Capability; // ok | degraded | unavailable
 }
 
 export interface ExercisePack {
@@ -63,18 +187,63 @@ export interface Exercise {
     test_code: Record<string, string>;
 }
 
+/**
+ * Reads the address a running daemon recorded in ~/.temper/daemon.json.
+ * The daemon may not be on the default port (port 0, or the port was taken).
+ */
+export function discoverDaemon(dir: string = path.join(os.homedir(), '.temper')): Config | undefined {
+    let addr: string;
+    try {
+        addr = JSON.parse(fs.readFileSync(path.join(dir, 'daemon.json'), 'utf8')).addr;
+    } catch {
+        return undefined;
+    }
+    const colon = typeof addr === 'string' ? addr.lastIndexOf(':') : -1;
+    if (colon === -1) {
+        return undefined;
+    }
+    let host = addr.slice(0, colon).replace(/^\[|\]$/g, '');
+    if (host === '0.0.0.0' || host === '::' || host === '') {
+        host = '127.0.0.1';
+    }
+    const port = Number(addr.slice(colon + 1));
+    return Number.isInteger(port) && port > 0 ? { host, port } : undefined;
+}
+
+/**
+ * A daemon error response. Switch on code, never on the message.
+ */
+export class TemperApiError extends Error {
+    constructor(
+        message: string,
+        public readonly status: number,
+        public readonly code?: string,
+        public readonly correlationId?: string,
+    ) {
+        super(message);
+        this.name = 'TemperApiError';
+    }
+}
+
 export class TemperClient {
     private config: Config;
+    private discover?: () => Config | undefined;
 
-    constructor(config: Config) {
+    /**
+     * With discover, each request goes to the address it returns, so a
+     * restarted daemon on a new port is followed; config is the fallback.
+     */
+    constructor(config: Config, discover?: () => Config | undefined) {
         this.config = config;
+        this.discover = discover;
     }
 
     private request<T>(method: string, path: string, body?: unknown): Promise<T> {
+        const target = this.discover?.() ?? this.config;
         return new Promise((resolve, reject) => {
             const options: http.RequestOptions = {
-                hostname: this.config.host,
-                port: this.config.port,
+                hostname: target.host,
+                port: target.port,
                 path: path,
                 method: method,
                 headers: {
@@ -93,7 +262,12 @@ export class TemperClient {
                     try {
                         const parsed = JSON.parse(data);
                         if (res.statusCode && res.statusCode >= 400) {
-                            reject(new Error(parsed.error || `Request failed with status ${res.statusCode}`));
+                            reject(new TemperApiError(
+                                parsed.error || `Request failed with status ${res.statusCode}`,
+                                res.statusCode,
+                                parsed.error_code,
+                                parsed.correlation_id,
+                            ));
                         } else {
                             resolve(parsed as T);
                         }
@@ -124,10 +298,15 @@ export class TemperClient {
         return this.request('GET', '/v1/health');
     }
 
-    async status(): Promise<{ status: string; version: string; llm_providers: string[]; runner: string }> {
+    async status(): Promise<{ status: string; version: string; llm_providers: string[]; runner: string; capabilities?: Capabilities }> {
         return this.request('GET', '/v1/status');
     }
 
+    async capabilities(): Promise<Capabilities> {
+        const status = await this.status();
+        return status.capabilities ?? {};
+    }
+
     async listExercises(): Promise<{ packs: ExercisePack[] }> {
         return this.request('GET', '/v1/exercises');
     }
@@ -136,11 +315,14 @@ export class TemperClient {
         return this.request('GET', `/v1/exercises/${pack}/${slug}`);
     }
 
-    async createSession(exerciseId: string, track?: string): Promise<Session> {
-        const body: { exercise_id: string; track?: string } = { exercise_id: exerciseId };
+    async createSession(exerciseId: string, track?: string, workspaceRoot?: string): Promise<Session> {
+        const body: { exercise_id: string; track?: string; workspace_root?: string } = { exercise_id: exerciseId };
         if (track) {
             body.track = track;
         }
+        if (workspaceRoot) {
+            body.workspace_root = workspaceRoot;
+        }
         return this.request('POST', '/v1/sessions', body);
     }
 
@@ -161,6 +343,11 @@ export class TemperClient {
         });
     }
 
+    /** Run one of the project's custom commands from .temper.yaml instead of format, build and test. */
+    async runCommand(sessionId: string, code: Record<string, string>, command: string): Promise<RunResult> {
+        return this.request('POST', `/v1/sessions/${sessionId}/runs`, { code, command });
+    }
+
     async hint(sessionId: string, code?: Record<string, string>): Promise<Intervention> {
         return this.request('POST', `/v1/sessions/${sessionId}/hint`, code ? { code } : {});
     }
@@ -181,10 +368,39 @@ export class TemperClient {
         return this.request('POST', `/v1/sessions/${sessionId}/explain`, code ? { code } : {});
     }
 
-    async format(sessionId: string, code: Record<string, string>): Promise<{ ok: boolean; formatted: Record<string, string> }> {
+    async format(sessionId: string, code: Record<string, string>): Promise<{ ok: boolean; formatted: Record<string, string>; formatter?: string; imports?: ImportChange[] }> {
         return this.request('POST', `/v1/sessions/${sessionId}/format`, { code });
     }
 
+    /** Whether the learner opted in to edit telemetry in the daemon config. */
+    async editTelemetryEnabled(): Promise<boolean> {
+        const config = await this.request<{ telemetry?: { edit_events?: boolean } }>('GET', '/v1/config');
+        return config.telemetry?.edit_events === true;
+    }
+
+    async recordEdits(sessionId: string, events: EditEvent[]): Promise<{ recorded: number }> {
+        return this.request('POST', `/v1/sessions/${sessionId}/edits`, { events });
+    }
+
+    /** Tell the daemon the session is open, so it isn't marked idle or expired. */
+    async heartbeat(sessionId: string): Promise<{ status: string; cooldown: CooldownState }> {
+        return this.request('POST', `/v1/sessions/${sessionId}/heartbeat`);
+    }
+
+    async exerciseVersion(sessionId: string): Promise<ExerciseVersionStatus> {
+        return this.request('GET', `/v1/sessions/${sessionId}/exercise-version`);
+    }
+
+    /** Re-base the session onto the exercise's current starter and tests. */
+    async migrateExercise(sessionId: string): Promise<ExerciseMigration> {
+        return this.request('POST', `/v1/sessions/${sessionId}/exercise-version/migrate`);
+    }
+
+    /** Keep the session on the exercise version it started from. */
+    async pinExercise(sessionId: string): Promise<ExerciseVersionStatus> {
+        return this.request('POST', `/v1/sessions/${sessionId}/exercise-version/pin`);
+    }
+
     async isRunning(): Promise<boolean> {
         try {
             const result = await this.health();
@@ -280,7 +496,39 @@ export interface SessionSummary {
     accomplishment?: string;
 }
 
+export interface ExerciseVersionStatus {
+    session_version?: string;
+    latest_version: string;
+    outdated: boolean;
+    pinned: boolean;
+    changed_files: string[];
+}
+
+export interface ExerciseMigration {
+    from?: string;
+    to: string;
+    updated: string[];
+    added: string[];
+    removed: string[];
+    /** Files the learner edited that the update also changed; their copy stays. */
+    kept: string[];
+    session: Session;
+}
+
 export interface DeleteSessionResponse {
     deleted: boolean;
     summary?: SessionSummary;
+    edits?: EditSummary;
+}
+
+/** Typed vs pasted code, present when edit telemetry is on. */
+export interface EditSummary {
+    typed_chars: number;
+    pasted_chars: number;
+    paste_ratio: number;
+    bursts: number;
+    pastes: number;
+    large_pastes: number;
+    undo_storms: number;
+    active_ms: number;
 }
@@ -0,0 +1,143 @@
+/**
+ * Coarse edit-pattern capture for the daemon's opt-in edit telemetry
+ * (telemetry.edit_events). Only sizes, counts and timings leave this
+ * module; the text typed or pasted is never kept.
+ */
+
+export type EditKind = 'typing' | 'paste' | 'undo_storm';
+
+export interface EditEvent {
+    kind: EditKind;
+    at: string;
+    file?: string;
+    chars: number;
+    lines?: number;
+    count?: number;
+    duration_ms?: number;
+}
+
+/** An insertion at least this long in one change is treated as a paste. */
+export const PASTE_MIN_CHARS = 20;
+/** A pause this long ends a typing burst. */
+export const BURST_IDLE_MS = 2000;
+/** This many undos within UNDO_WINDOW_MS make an undo storm. */
+export const UNDO_STORM_COUNT = 5;
+export const UNDO_WINDOW_MS = 5000;
+
+interface Burst {
+    file: string;
+    start: number;
+    last: number;
+    chars: number;
+    count: number;
+}
+
+interface UndoRun {
+    file: string;
+    start: number;
+    last: number;
+    chars: number;
+    count: number;
+}
+
+export class EditTracker {
+    private pending: EditEvent[] = [];
+    private burst?: Burst;
+    private undos?: UndoRun;
+
+    /**
+     * Records one content change. file should be a base name; now is
+     * injectable for tests.
+     */
+    change(file: string, text: string, rangeLength: number, undo: boolean, now: number = Date.now()): void {
+        this.expire(now);
+
+        if (undo) {
+            this.closeBurst();
+            if (!this.undos || this.undos.file !== file) {
+                this.closeUndos();
+                this.undos = { file, start: now, last: now, chars: 0, count: 0 };
+            }
+            this.undos.count++;
+            this.undos.chars += Math.max(text.length, rangeLength);
+            this.undos.last = now;
+            return;
+        }
+
+        if (text.length >= PASTE_MIN_CHARS) {
+            this.closeBurst();
+            this.pending.push({
+                kind: 'paste',
+                at: new Date(now).toISOString(),
+                file,
+                chars: text.length,
+                lines: text.split('\n').length,
+            });
+            return;
+        }
+
+        if (text.length === 0) {
+            return; // deletions are neither authored nor pasted code
+        }
+        if (!this.burst || this.burst.file !== file) {
+            this.closeBurst();
+            this.burst = { file, start: now, last: now, chars: 0, count: 0 };
+        }
+        this.burst.chars += text.length;
+        this.burst.count++;
+        this.burst.last = now;
+    }
+
+    /** Returns the finished events and forgets them. */
+    drain(now: number = Date.now(), final = false): EditEvent[] {
+        this.expire(now);
+        if (final) {
+            this.closeBurst();
+            this.closeUndos();
+        }
+        const events = this.pending;
+        this.pending = [];
+        return events;
+    }
+
+    private expire(now: number): void {
+        if (this.burst && now - this.burst.last >= BURST_IDLE_MS) {
+            this.closeBurst();
+        }
+        if (this.undos && now - this.undos.last >= UNDO_WINDOW_MS) {
+            this.closeUndos();
+        }
+    }
+
+    private closeBurst(): void {
+        const b = this.burst;
+        this.burst = undefined;
+        if (!b) {
+            return;
+        }
+        this.pending.push({
+            kind: 'typing',
+            at: new Date(b.start).toISOString(),
+            file: b.file,
+            chars: b.chars,
+            count: b.count,
+            duration_ms: b.last - b.start,
+        });
+    }
+
+    private closeUndos(): void {
+        const u = this.undos;
+        this.undos = undefined;
+        if (!u || u.count < UNDO_STORM_COUNT) {
+            return;
+        }
+        this.pending.push({
+            kind: 'undo_storm',
+            at: new Date(u.start).toISOString(),
+            file: u.file,
+            chars: u.chars,
+            count: u.count,
+            duration_ms: u.last - u.start,
+        });
+    }
+}
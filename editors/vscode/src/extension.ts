@@ -1,5 +1,7 @@
+import * as path from 'path';
 import * as vscode from 'vscode';
-import { TemperClient, Session, Intervention, RunResult, AuthoringSuggestion, SessionSummary } from './client';
+import { EditTracker } from './edits';
+import { TemperClient, TemperApiError, discoverDaemon, Session, Intervention, RunResult, AuthoringSuggestion, SessionSummary, EditSummary, Capabilities } from './client';
 
 // Global state
 let client: TemperClient;
@@ -8,6 +10,17 @@ let outputChannel: vscode.OutputChannel;
 let statusBarItem: vscode.StatusBarItem;
 let currentSuggestions: AuthoringSuggestion[] = [];
 let currentSpecPath: string | null = null;
+// Set only while a session runs and the daemon has edit telemetry on
+let editTracker: EditTracker | null = null;
+// What the daemon can do right now; commands that can't work are disabled
+let capabilities: Capabilities = {};
+
+// How often finished edit events are sent to the daemon
+const EDIT_FLUSH_MS = 30000;
+// How often the capability matrix is refreshed
+const CAPABILITY_REFRESH_MS = 60000;
+// How often an open session tells the daemon it's still being worked on
+const HEARTBEAT_MS = 60000;
 
 export function activate(context: vscode.ExtensionContext) {
     console.log('Temper extension activated');
@@ -59,31 +72,138 @@ export function activate(context: vscode.ExtensionContext) {
     context.subscriptions.push(
         vscode.workspace.onDidSaveTextDocument(doc => {
             const config = vscode.workspace.getConfiguration('temper');
-            if (config.get('autoRunOnSave') && currentSession && doc.languageId === 'go') {
+            if (config.get('autoRunOnSave') && currentSession && doc.languageId === 'go' && capabilities.runs?.mode !== 'unavailable') {
                 runChecks();
             }
         })
     );
 
+    // Opt-in edit telemetry: sizes and timings of edits, never their text
+    context.subscriptions.push(
+        vscode.workspace.onDidChangeTextDocument(e => {
+            if (!editTracker || !currentSession || e.document.uri.scheme !== 'file') {
+                return;
+            }
+            const file = path.basename(e.document.fileName);
+            const undo = e.reason === vscode.TextDocumentChangeReason.Undo;
+            for (const change of e.contentChanges) {
+                editTracker.change(file, change.text, change.rangeLength, undo);
+            }
+        })
+    );
+    const editFlush = setInterval(() => { flushEdits(false); }, EDIT_FLUSH_MS);
+    context.subscriptions.push({ dispose: () => clearInterval(editFlush) });
+
+    const capabilityRefresh = setInterval(() => { refreshCapabilities(); }, CAPABILITY_REFRESH_MS);
+    context.subscriptions.push({ dispose: () => clearInterval(capabilityRefresh) });
+
+    const heartbeat = setInterval(() => { sendHeartbeat(); }, HEARTBEAT_MS);
+    context.subscriptions.push({ dispose: () => clearInterval(heartbeat) });
+
     updateStatusBar();
+    refreshCapabilities();
+}
+
+/**
+ * Fetch the daemon's capability matrix and adjust the UI to it: Run Checks
+ * is disabled while runs are unavailable, and the status bar shows when
+ * hints come from the offline ladder.
+ */
+async function refreshCapabilities() {
+    try {
+        capabilities = await client.capabilities();
+    } catch {
+        capabilities = {};
+    }
+    await vscode.commands.executeCommand('setContext', 'temper.runsUnavailable', capabilities.runs?.mode === 'unavailable');
+    updateStatusBar();
+}
+
+function capabilityNotes(): string[] {
+    const notes: string[] = [];
+    for (const [feature, c] of Object.entries(capabilities)) {
+        if (c?.reason) {
+            notes.push(`${feature}: ${c.mode} (${c.reason})`);
+        }
+    }
+    return notes;
+}
+
+async function startEditTracking() {
+    editTracker = null;
+    try {
+        if (await client.editTelemetryEnabled()) {
+            editTracker = new EditTracker();
+        }
+    } catch {
+        // Older daemons don't report telemetry; leave it off
+    }
+}
+
+async function flushEdits(final: boolean) {
+    const tracker = editTracker;
+    if (!tracker || !currentSession) {
+        return;
+    }
+    if (final) {
+        editTracker = null;
+    }
+    const events = tracker.drain(Date.now(), final);
+    if (events.length === 0) {
+        return;
+    }
+    try {
+        await client.recordEdits(currentSession.id, events);
+    } catch (error) {
+        // Turned off in the daemon config since the session started
+        if (error instanceof TemperApiError && error.code === 'FORBIDDEN') {
+            editTracker = null;
+        }
+    }
+}
+
+/**
+ * Keep the session from going idle while the learner is at the editor. A
+ * session the daemon expired or someone ended is dropped.
+ */
+async function sendHeartbeat() {
+    if (!currentSession || !vscode.window.state.focused) {
+        return;
+    }
+    try {
+        await client.heartbeat(currentSession.id);
+    } catch (error) {
+        if (error instanceof TemperApiError && (error.code === 'SESSION_NOT_ACTIVE' || error.code === 'SESSION_NOT_FOUND')) {
+            currentSession = null;
+            updateStatusBar();
+            vscode.window.showInformationMessage('The Temper session has ended. Start a new one to continue.');
+        }
+    }
 }
 
 function initializeClient() {
     const config = vscode.workspace.getConfiguration('temper');
+    // An explicitly configured port wins over the daemon's discovery file
+    const port = config.inspect<number>('daemon.port');
+    const portSet = port?.globalValue !== undefined
+        || port?.workspaceValue !== undefined
+        || port?.workspaceFolderValue !== undefined;
     client = new TemperClient({
         host: config.get('daemon.host', '127.0.0.1'),
         port: config.get('daemon.port', 7432),
-    });
+    }, portSet ? undefined : () => discoverDaemon());
 }
 
 function updateStatusBar() {
+    const notes = capabilityNotes();
+    const offline = capabilities.pairing?.mode === 'offline' ? ' $(cloud-offline)' : '';
     if (currentSession) {
-        statusBarItem.text = `$(mortar-board) Temper: ${currentSession.exercise_id}`;
-        statusBarItem.tooltip = `Session: ${currentSession.id}\nRuns: ${currentSession.run_count}\nHints: ${currentSession.hint_count}`;
+        statusBarItem.text = `$(mortar-board) Temper: ${currentSession.exercise_id}${offline}`;
+        statusBarItem.tooltip = [`Session: ${currentSession.id}`, `Runs: ${currentSession.run_count}`, `Hints: ${currentSession.hint_count}`, ...notes].join('\n');
         statusBarItem.show();
     } else {
-        statusBarItem.text = '$(mortar-board) Temper';
-        statusBarItem.tooltip = 'No active session';
+        statusBarItem.text = `$(mortar-board) Temper${offline}`;
+        statusBarItem.tooltip = ['No active session', ...notes].join('\n');
         statusBarItem.show();
     }
 }
@@ -146,8 +266,11 @@ async function startSession() {
         const track = config.get<string>('learningTrack', 'practice');
 
         // Create session
-        currentSession = await client.createSession(fullExerciseId, track);
-        updateStatusBar();
+        // The workspace's .temper.yaml, if any, applies to the session
+        const workspaceRoot = vscode.workspace.workspaceFolders?.[0]?.uri.fsPath;
+        currentSession = await client.createSession(fullExerciseId, track, workspaceRoot);
+        await startEditTracking();
+        await refreshCapabilities();
 
         vscode.window.showInformationMessage(`Session started: ${currentSession.id.substring(0, 8)}`);
         outputChannel.appendLine(`Session started: ${currentSession.id}`);
@@ -167,6 +290,7 @@ async function stopSession() {
     }
 
     try {
+        await flushEdits(true);
         const result = await client.deleteSession(currentSession.id);
         const sessionId = currentSession.id;
         currentSession = null;
@@ -174,18 +298,24 @@ async function stopSession() {
 
         // Display session summary
         if (result.summary) {
-            showSessionSummary(result.summary, sessionId);
+            showSessionSummary(result.summary, sessionId, result.edits);
         } else {
             vscode.window.showInformationMessage(`Session ended: ${sessionId.substring(0, 8)}`);
             outputChannel.appendLine(`Session ended: ${sessionId}`);
         }
 
     } catch (error) {
+        // Already gone on the daemon (deleted elsewhere or pruned)
+        if (error instanceof TemperApiError && error.code === 'SESSION_NOT_FOUND') {
+            currentSession = null;
+            updateStatusBar();
+            return;
+        }
         vscode.window.showErrorMessage(`Failed to end session: ${error}`);
     }
 }
 
-function showSessionSummary(summary: SessionSummary, sessionId: string) {
+function showSessionSummary(summary: SessionSummary, sessionId: string, edits?: EditSummary) {
     outputChannel.clear();
     outputChannel.appendLine('=== Session Summary ===');
     outputChannel.appendLine('');
@@ -206,6 +336,10 @@ function showSessionSummary(summary: SessionSummary, sessionId: string) {
         outputChannel.appendLine(`Spec Progress: ${summary.spec_progress}`);
     }
 
+    if (edits) {
+        outputChannel.appendLine(`Typed: ${edits.typed_chars} chars, pasted: ${edits.pasted_chars} chars (${Math.round(edits.paste_ratio * 100)}% pasted)`);
+    }
+
     outputChannel.appendLine('');
     outputChannel.appendLine('---');
     outputChannel.appendLine('');
@@ -327,6 +461,10 @@ async function runChecks() {
         vscode.window.showWarningMessage('No active session. Use "Temper: Start Session" to begin.');
         return;
     }
+    if (capabilities.runs?.mode === 'unavailable') {
+        vscode.window.showWarningMessage(`Runs are unavailable: ${capabilities.runs.reason ?? 'no runner'}`);
+        return;
+    }
 
     try {
         const code = getActiveCode();
@@ -341,10 +479,56 @@ async function runChecks() {
         });
 
     } catch (error) {
+        if (error instanceof TemperApiError && error.code === 'EXERCISE_OUTDATED') {
+            if (await resolveOutdatedExercise()) {
+                await runChecks();
+            }
+            return;
+        }
         vscode.window.showErrorMessage(`Run failed: ${error}`);
     }
 }
 
+/**
+ * The exercise changed on disk since the session started. Ask whether to
+ * re-base onto the new version or stay on the old one; returns whether
+ * the session can run again.
+ */
+async function resolveOutdatedExercise(): Promise<boolean> {
+    const status = await client.exerciseVersion(currentSession!.id);
+    const choice = await vscode.window.showWarningMessage(
+        `This exercise was updated since your session started (${status.changed_files.join(', ')} changed).`,
+        'Migrate',
+        'Keep Old Version',
+    );
+    if (choice === 'Keep Old Version') {
+        await client.pinExercise(currentSession!.id);
+        return true;
+    }
+    if (choice !== 'Migrate') {
+        return false;
+    }
+
+    const migration = await client.migrateExercise(currentSession!.id);
+    currentSession = migration.session;
+    outputChannel.clear();
+    outputChannel.appendLine('=== Exercise Migrated ===');
+    outputChannel.appendLine('');
+    const lines: [string, string[]][] = [
+        ['Updated', migration.updated],
+        ['Added', migration.added],
+        ['Removed', migration.removed],
+        ['Kept your version', migration.kept],
+    ];
+    for (const [label, files] of lines) {
+        if (files.length > 0) {
+            outputChannel.appendLine(`${label}: ${files.join(', ')}`);
+        }
+    }
+    outputChannel.show();
+    return true;
+}
+
 function showRunResult(result: RunResult) {
     outputChannel.clear();
     outputChannel.appendLine('=== Run Results ===');
@@ -0,0 +1,49 @@
+import * as assert from 'assert';
+import { EditTracker, BURST_IDLE_MS, UNDO_STORM_COUNT } from '../edits';
+
+suite('EditTracker', () => {
+    test('groups keystrokes into one typing burst', () => {
+        const tracker = new EditTracker();
+        tracker.change('main.go', 'f', 0, false, 0);
+        tracker.change('main.go', 'u', 0, false, 100);
+        tracker.change('main.go', 'n', 0, false, 250);
+
+        assert.deepStrictEqual(tracker.drain(250), []);
+
+        const events = tracker.drain(250 + BURST_IDLE_MS);
+        assert.strictEqual(events.length, 1);
+        assert.strictEqual(events[0].kind, 'typing');
+        assert.strictEqual(events[0].chars, 3);
+        assert.strictEqual(events[0].count, 3);
+        assert.strictEqual(events[0].duration_ms, 250);
+    });
+
+    test('records large insertions as pastes without their text', () => {
+        const tracker = new EditTracker();
+        const pasted = 'func add(a, b int) int {\n\treturn a + b\n}\n';
+        tracker.change('main.go', pasted, 0, false, 0);
+
+        const events = tracker.drain(0);
+        assert.strictEqual(events.length, 1);
+        assert.strictEqual(events[0].kind, 'paste');
+        assert.strictEqual(events[0].chars, pasted.length);
+        assert.strictEqual(events[0].lines, 4);
+        assert.ok(!JSON.stringify(events).includes('return'));
+    });
+
+    test('reports an undo storm only past the threshold', () => {
+        const tracker = new EditTracker();
+        for (let i = 0; i < UNDO_STORM_COUNT - 1; i++) {
+            tracker.change('main.go', '', 1, true, i * 100);
+        }
+        assert.deepStrictEqual(tracker.drain(1000, true), []);
+
+        for (let i = 0; i < UNDO_STORM_COUNT; i++) {
+            tracker.change('main.go', '', 1, true, i * 100);
+        }
+        const events = tracker.drain(1000, true);
+        assert.strictEqual(events.length, 1);
+        assert.strictEqual(events[0].kind, 'undo_storm');
+        assert.strictEqual(events[0].count, UNDO_STORM_COUNT);
+    });
+});
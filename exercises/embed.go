@@ -0,0 +1,11 @@
+// Package exercises bundles the exercise packs into the binary, so a
+// single temper binary can serve them without an installed exercises/
+// directory.
+package exercises
+
+import "embed"
+
+// FS holds every pack as <pack>/pack.yaml and <pack>/<category>/<slug>.yaml
+//
+//go:embed */pack.yaml */*/*.yaml
+var FS embed.FS
@@ -0,0 +1,127 @@
+// Package achievement celebrates learning milestones: the first time the
+// tests pass without a hint, a week-long practice streak, an exercise
+// solved unaided, a goroutine leak tracked down. Each achievement unlocks
+// once and stays unlocked.
+package achievement
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Achievement IDs
+const (
+	FirstUnaidedGreen  = "first_unaided_green"
+	WeekStreak         = "week_streak"
+	ZeroHintExercise   = "zero_hint_exercise"
+	GoroutineLeakFixed = "goroutine_leak_fixed"
+)
+
+// streakDays is how long a practice streak must run for WeekStreak
+const streakDays = 7
+
+// Definition describes an achievement
+type Definition struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Unlock records when and where an achievement was earned
+type Unlock struct {
+	Definition
+	SessionID  string    `json:"session_id,omitempty"`
+	ExerciseID string    `json:"exercise_id,omitempty"`
+	Evidence   string    `json:"evidence"` // what earned it, for the learner
+	At         time.Time `json:"unlocked_at"`
+}
+
+// Activity is what just happened in a session: a run and what led up to it
+type Activity struct {
+	SessionID  string
+	ExerciseID string // empty outside training sessions
+	HintCount  int    // hints the session has had so far
+	Green      bool   // the run built and its tests passed
+	// Previous is the session's run before this one, nil for the first
+	Previous *PreviousRun
+	Streak   int // consecutive days practiced, today included
+	At       time.Time
+}
+
+// PreviousRun is the part of an earlier run the rules look at
+type PreviousRun struct {
+	Green  bool
+	Output string // build and test output
+}
+
+// Rule decides whether an activity earns an achievement. It returns the
+// evidence to show the learner.
+type Rule struct {
+	Definition
+	Check func(a Activity) (evidence string, ok bool)
+}
+
+// Rules returns the achievements and how each is earned
+func Rules() []Rule {
+	return []Rule{
+		{
+			Definition: Definition{
+				ID:          FirstUnaidedGreen,
+				Name:        "First Unaided Green",
+				Description: "Get the tests passing without asking for a hint",
+			},
+			Check: func(a Activity) (string, bool) {
+				return "tests passed with no hints in the session", a.Green && a.HintCount == 0
+			},
+		},
+		{
+			Definition: Definition{
+				ID:          WeekStreak,
+				Name:        "Seven-Day Streak",
+				Description: fmt.Sprintf("Practice %d days in a row", streakDays),
+			},
+			Check: func(a Activity) (string, bool) {
+				return fmt.Sprintf("%d days of practice in a row", a.Streak), a.Streak >= streakDays
+			},
+		},
+		{
+			Definition: Definition{
+				ID:          ZeroHintExercise,
+				Name:        "No Hints Needed",
+				Description: "Solve an exercise without a single hint",
+			},
+			Check: func(a Activity) (string, bool) {
+				return a.ExerciseID + " solved with no hints", a.Green && a.HintCount == 0 && a.ExerciseID != ""
+			},
+		},
+		{
+			Definition: Definition{
+				ID:          GoroutineLeakFixed,
+				Name:        "Leak Plugged",
+				Description: "Fix a goroutine leak the tests caught",
+			},
+			Check: func(a Activity) (string, bool) {
+				if !a.Green || a.Previous == nil || a.Previous.Green {
+					return "", false
+				}
+				line := leakReport(a.Previous.Output)
+				return "the run before reported: " + line, line != ""
+			},
+		},
+	}
+}
+
+// leakPattern matches the leak reports of goleak and similar checkers
+var leakPattern = regexp.MustCompile(`(?i)found unexpected goroutines|goroutine leak|leaked goroutine`)
+
+// leakReport returns the output line that reports a goroutine leak
+func leakReport(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if leakPattern.MatchString(line) {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
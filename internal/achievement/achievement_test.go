@@ -0,0 +1,136 @@
+package achievement
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRules(t *testing.T) {
+	leak := &PreviousRun{Output: "--- FAIL: TestWorker\n    leaks.go:78: found unexpected goroutines:\n"}
+
+	tests := []struct {
+		name     string
+		activity Activity
+		want     []string
+	}{
+		{
+			name:     "red run earns nothing",
+			activity: Activity{ExerciseID: "go-v1/basics/hello", Streak: 1},
+		},
+		{
+			name:     "green with hints earns nothing",
+			activity: Activity{ExerciseID: "go-v1/basics/hello", HintCount: 2, Green: true, Streak: 1},
+		},
+		{
+			name:     "green unaided outside an exercise",
+			activity: Activity{Green: true, Streak: 1},
+			want:     []string{FirstUnaidedGreen},
+		},
+		{
+			name:     "green unaided exercise",
+			activity: Activity{ExerciseID: "go-v1/basics/hello", Green: true, Streak: 1},
+			want:     []string{FirstUnaidedGreen, ZeroHintExercise},
+		},
+		{
+			name:     "week streak",
+			activity: Activity{Streak: 7},
+			want:     []string{WeekStreak},
+		},
+		{
+			name:     "leak fixed",
+			activity: Activity{HintCount: 1, Green: true, Previous: leak, Streak: 1},
+			want:     []string{GoroutineLeakFixed},
+		},
+		{
+			name:     "previous run failed without a leak",
+			activity: Activity{HintCount: 1, Green: true, Previous: &PreviousRun{Output: "FAIL"}, Streak: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, rule := range Rules() {
+				if _, ok := rule.Check(tt.activity); ok {
+					got = append(got, rule.ID)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("unlocked %v; want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("unlocked %v; want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLeakReport(t *testing.T) {
+	output := "=== RUN   TestPool\n    pool_test.go:12: found unexpected goroutines:\n        [Goroutine 7 in state chan receive]\n"
+	if got := leakReport(output); got != "pool_test.go:12: found unexpected goroutines:" {
+		t.Errorf("leakReport() = %q", got)
+	}
+	if got := leakReport("--- FAIL: TestPool\nexpected 3, got 2"); got != "" {
+		t.Errorf("leakReport() = %q; want no report", got)
+	}
+}
+
+func TestEngine(t *testing.T) {
+	engine, err := NewEngine(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	unlocked, err := engine.Evaluate(Activity{SessionID: "s1", Green: true, Streak: 1, At: at})
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(unlocked) != 1 || unlocked[0].ID != FirstUnaidedGreen || unlocked[0].SessionID != "s1" {
+		t.Fatalf("Evaluate() = %+v; want first unaided green for s1", unlocked)
+	}
+
+	unlocked, err = engine.Evaluate(Activity{SessionID: "s2", Green: true, Streak: 1, At: at.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(unlocked) != 0 {
+		t.Errorf("Evaluate() = %+v; an achievement unlocks only once", unlocked)
+	}
+
+	list, err := engine.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != len(Rules()) {
+		t.Fatalf("List() has %d achievements; want %d", len(list), len(Rules()))
+	}
+	for _, status := range list {
+		earned := status.Unlocked != nil
+		if earned != (status.ID == FirstUnaidedGreen) {
+			t.Errorf("%s earned = %v", status.ID, earned)
+		}
+	}
+	if u := list[0].Unlocked; u == nil || u.SessionID != "s1" || !u.At.Equal(at) {
+		t.Errorf("first unlock = %+v; want the one from s1", u)
+	}
+}
+
+func TestEngine_Forget(t *testing.T) {
+	engine, err := NewEngine(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	engine.Evaluate(Activity{SessionID: "s1", Green: true})
+	engine.Evaluate(Activity{SessionID: "s2", Streak: 7})
+
+	locked, err := engine.Forget(map[string]bool{"s1": true})
+	if err != nil || len(locked) != 1 || locked[0] != FirstUnaidedGreen {
+		t.Fatalf("Forget() = %v, %v; want first unaided green locked", locked, err)
+	}
+	if locked, _ := engine.Forget(nil); len(locked) != 1 || locked[0] != WeekStreak {
+		t.Errorf("Forget(nil) = %v; want the streak locked too", locked)
+	}
+}
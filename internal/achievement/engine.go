@@ -0,0 +1,111 @@
+package achievement
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/felixgeelhaar/temper/internal/storage/local"
+)
+
+const collectionAchievements = "achievements"
+
+// Status is an achievement and, once earned, its unlock
+type Status struct {
+	Definition
+	Unlocked *Unlock `json:"unlocked,omitempty"`
+}
+
+// Engine checks activity against the rules and keeps the unlocks, one
+// JSON file per achievement
+type Engine struct {
+	mu    sync.Mutex
+	store *local.Store
+	rules []Rule
+}
+
+// NewEngine creates an engine storing unlocks under basePath (usually
+// ~/.temper)
+func NewEngine(basePath string) (*Engine, error) {
+	store, err := local.NewStore(basePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Engine{store: store, rules: Rules()}, nil
+}
+
+// Evaluate checks an activity against every achievement not yet earned
+// and returns the ones it unlocks
+func (e *Engine) Evaluate(a Activity) ([]Unlock, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var unlocked []Unlock
+	for _, rule := range e.rules {
+		if e.store.Exists(collectionAchievements, rule.ID) {
+			continue
+		}
+		evidence, ok := rule.Check(a)
+		if !ok {
+			continue
+		}
+		u := Unlock{
+			Definition: rule.Definition,
+			SessionID:  a.SessionID,
+			ExerciseID: a.ExerciseID,
+			Evidence:   evidence,
+			At:         a.At,
+		}
+		if err := e.store.Save(collectionAchievements, u.ID, u); err != nil {
+			return unlocked, err
+		}
+		unlocked = append(unlocked, u)
+	}
+	return unlocked, nil
+}
+
+// List returns every achievement in rule order, with its unlock when
+// earned
+func (e *Engine) List() ([]Status, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	list := make([]Status, 0, len(e.rules))
+	for _, rule := range e.rules {
+		status := Status{Definition: rule.Definition}
+		var u Unlock
+		switch err := e.store.Load(collectionAchievements, rule.ID, &u); {
+		case err == nil:
+			status.Unlocked = &u
+		case !errors.Is(err, local.ErrNotFound):
+			return nil, err
+		}
+		list = append(list, status)
+	}
+	return list, nil
+}
+
+// Forget locks again the achievements earned in the given sessions, or
+// every achievement when sessionIDs is nil. It returns the ones it locked.
+func (e *Engine) Forget(sessionIDs map[string]bool) ([]string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	locked := []string{}
+	for _, rule := range e.rules {
+		var u Unlock
+		switch err := e.store.Load(collectionAchievements, rule.ID, &u); {
+		case errors.Is(err, local.ErrNotFound):
+			continue
+		case err != nil:
+			return locked, err
+		}
+		if sessionIDs != nil && !sessionIDs[u.SessionID] {
+			continue
+		}
+		if err := e.store.Delete(collectionAchievements, rule.ID); err != nil {
+			return locked, err
+		}
+		locked = append(locked, rule.ID)
+	}
+	return locked, nil
+}
@@ -0,0 +1,213 @@
+// Package analysis runs lightweight static checks over a learner's Go code
+// before the pairing engine asks the LLM for help. The findings are facts
+// (x is unused at line 12, err shadows an outer err) the hint can point at
+// instead of guessing from the source. Once an exercise is done, it also
+// compares the learner's approach with the author's solutions.
+//
+// Only the parser and the type checker are used, with imports left
+// unresolved, so analysis needs no toolchain and stays fast enough to run
+// on every hint.
+package analysis
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Checks a finding can come from
+const (
+	CheckSyntax      = "syntax"
+	CheckUnused      = "unused"
+	CheckShadow      = "shadow"
+	CheckPrintf      = "printf"
+	CheckSelfAssign  = "selfassign"
+	CheckUnreachable = "unreachable"
+)
+
+// MaxFindings bounds how many findings Analyze returns, so a file full
+// of errors doesn't crowd the rest of the prompt out
+const MaxFindings = 20
+
+// Finding is one fact about the code
+type Finding struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+// Analyze checks the Go files in code. Other files are ignored. Findings
+// are ordered by file and position.
+func Analyze(code map[string]string) []Finding {
+	fset := token.NewFileSet()
+	var findings []Finding
+
+	// Files type-check together per package clause, so a test file sees
+	// the declarations of the file it tests
+	packages := make(map[string][]*ast.File)
+	for _, name := range sortedGoFiles(code) {
+		file, err := parser.ParseFile(fset, name, code[name], parser.SkipObjectResolution)
+		if err != nil {
+			findings = append(findings, syntaxFindings(name, err)...)
+			continue
+		}
+		packages[file.Name.Name] = append(packages[file.Name.Name], file)
+	}
+
+	for _, files := range packages {
+		c := &checker{fset: fset}
+		c.typeCheck(files)
+		for _, file := range files {
+			c.inspect(file)
+		}
+		findings = append(findings, c.findings...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	if len(findings) > MaxFindings {
+		findings = findings[:MaxFindings]
+	}
+	return findings
+}
+
+func sortedGoFiles(code map[string]string) []string {
+	var names []string
+	for name := range code {
+		if filepath.Ext(name) == ".go" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func syntaxFindings(name string, err error) []Finding {
+	list, ok := err.(scanner.ErrorList)
+	if !ok {
+		return []Finding{{File: name, Check: CheckSyntax, Message: err.Error()}}
+	}
+	findings := make([]Finding, 0, len(list))
+	for _, e := range list {
+		findings = append(findings, Finding{
+			File:    name,
+			Line:    e.Pos.Line,
+			Column:  e.Pos.Column,
+			Check:   CheckSyntax,
+			Message: e.Msg,
+		})
+	}
+	return findings
+}
+
+type checker struct {
+	fset     *token.FileSet
+	info     *types.Info
+	findings []Finding
+}
+
+func (c *checker) report(pos token.Pos, check, message string) {
+	p := c.fset.Position(pos)
+	c.findings = append(c.findings, Finding{
+		File:    p.Filename,
+		Line:    p.Line,
+		Column:  p.Column,
+		Check:   check,
+		Message: message,
+	})
+}
+
+// unresolved stands in for every import. The learner's imports can't be
+// loaded without a toolchain; an empty package lets checking continue
+// and the errors it causes are dropped below.
+type unresolved struct{}
+
+func (unresolved) Import(path string) (*types.Package, error) {
+	pkg := types.NewPackage(path, importName(path))
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+// importName guesses a package's name from its path: the last element,
+// unless that's a major version suffix
+func importName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && majorVersion.MatchString(name) {
+		name = elems[len(elems)-2]
+	}
+	return name
+}
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// nameKnown reports whether an import's name is certainly its guessed
+// one. A wrong guess makes a used import look unused.
+func nameKnown(path string) bool {
+	name := importName(path)
+	return token.IsIdentifier(name) && !strings.Contains(path, ".")
+}
+
+// typeCheck keeps only the type errors that hold whatever the imports
+// contain: unused variables and imports
+func (c *checker) typeCheck(files []*ast.File) {
+	c.info = &types.Info{
+		Defs:   make(map[*ast.Ident]types.Object),
+		Uses:   make(map[*ast.Ident]types.Object),
+		Scopes: make(map[ast.Node]*types.Scope),
+	}
+	conf := types.Config{
+		Importer: unresolved{},
+		Error: func(err error) {
+			te, ok := err.(types.Error)
+			if !ok {
+				return
+			}
+			switch {
+			case strings.Contains(te.Msg, "declared and not used"):
+				c.report(te.Pos, CheckUnused, te.Msg)
+			case strings.Contains(te.Msg, "imported and not used"):
+				if path, err := strconv.Unquote(strings.Fields(te.Msg)[0]); err == nil && nameKnown(path) {
+					c.report(te.Pos, CheckUnused, te.Msg)
+				}
+			}
+		},
+	}
+	_, _ = conf.Check(files[0].Name.Name, c.fset, files, c.info)
+}
+
+func (c *checker) inspect(file *ast.File) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			c.checkShadow(n)
+			c.checkSelfAssign(n)
+		case *ast.CallExpr:
+			c.checkPrintf(n)
+		case *ast.BlockStmt:
+			c.checkUnreachable(n.List)
+		case *ast.CaseClause:
+			c.checkUnreachable(n.Body)
+		case *ast.CommClause:
+			c.checkUnreachable(n.Body)
+		}
+		return true
+	})
+}
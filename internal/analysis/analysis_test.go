@@ -0,0 +1,178 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func findingsFor(t *testing.T, src string) []Finding {
+	t.Helper()
+	return Analyze(map[string]string{"main.go": src})
+}
+
+func hasFinding(findings []Finding, check string, line int, substr string) bool {
+	for _, f := range findings {
+		if f.Check == check && f.Line == line && strings.Contains(f.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAnalyze_Unused(t *testing.T) {
+	findings := findingsFor(t, `package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	x := 1
+	fmt.Println(strings.ToUpper("hi"))
+}
+`)
+	if !hasFinding(findings, CheckUnused, 5, `"os" imported and not used`) {
+		t.Errorf("missing unused import, got %+v", findings)
+	}
+	if !hasFinding(findings, CheckUnused, 10, "declared and not used: x") {
+		t.Errorf("missing unused variable, got %+v", findings)
+	}
+	for _, f := range findings {
+		if strings.Contains(f.Message, "fmt") || strings.Contains(f.Message, "strings") {
+			t.Errorf("used import reported: %+v", f)
+		}
+	}
+}
+
+func TestAnalyze_ShadowInLoop(t *testing.T) {
+	findings := findingsFor(t, `package main
+
+func sum(xs []int) int {
+	total := 0
+	for _, x := range xs {
+		total := total + x
+		_ = total
+	}
+	return total
+}
+`)
+	if !hasFinding(findings, CheckShadow, 6, "total declared with := inside a loop shadows total declared at line 4") {
+		t.Errorf("missing shadow, got %+v", findings)
+	}
+}
+
+func TestAnalyze_PackageLevelNotShadowed(t *testing.T) {
+	findings := findingsFor(t, `package main
+
+var count int
+
+func main() {
+	count := 2
+	println(count)
+}
+`)
+	for _, f := range findings {
+		if f.Check == CheckShadow {
+			t.Errorf("package-level variable reported as shadowed: %+v", f)
+		}
+	}
+}
+
+func TestAnalyze_Printf(t *testing.T) {
+	findings := findingsFor(t, `package main
+
+import "fmt"
+
+func main() {
+	fmt.Printf("%s is %d%%\n", "x")
+	fmt.Printf("%*d\n", 3, 4)
+	_ = fmt.Sprintf("%v", 1)
+}
+`)
+	if !hasFinding(findings, CheckPrintf, 6, "2 verbs but 1 arguments") {
+		t.Errorf("missing printf mismatch, got %+v", findings)
+	}
+	for _, f := range findings {
+		if f.Check == CheckPrintf && f.Line != 6 {
+			t.Errorf("unexpected printf finding: %+v", f)
+		}
+	}
+}
+
+func TestAnalyze_SelfAssignAndUnreachable(t *testing.T) {
+	findings := findingsFor(t, `package main
+
+func f(x int) int {
+	x = x
+	return x
+	println("never")
+}
+`)
+	if !hasFinding(findings, CheckSelfAssign, 4, "x") {
+		t.Errorf("missing self-assignment, got %+v", findings)
+	}
+	if !hasFinding(findings, CheckUnreachable, 6, "after return") {
+		t.Errorf("missing unreachable code, got %+v", findings)
+	}
+}
+
+func TestAnalyze_SyntaxError(t *testing.T) {
+	findings := findingsFor(t, "package main\n\nfunc main() {\n\tx := \n}\n")
+	if len(findings) == 0 || findings[0].Check != CheckSyntax || findings[0].Line == 0 {
+		t.Errorf("findings = %+v, want a positioned syntax error", findings)
+	}
+}
+
+func TestAnalyze_TestFileSeesPackage(t *testing.T) {
+	findings := Analyze(map[string]string{
+		"main.go": "package main\n\nfunc Hello() string { return \"hi\" }\n",
+		"main_test.go": `package main
+
+import "testing"
+
+func TestHello(t *testing.T) {
+	if Hello() != "hi" {
+		t.Fail()
+	}
+}
+`,
+		"README.md": "not go",
+	})
+	if len(findings) != 0 {
+		t.Errorf("clean package has findings: %+v", findings)
+	}
+}
+
+func TestAnalyze_Bounded(t *testing.T) {
+	var src strings.Builder
+	src.WriteString("package main\n\nfunc main() {\n")
+	for i := 0; i < MaxFindings+5; i++ {
+		src.WriteString("\tv" + string(rune('a'+i)) + " := 1\n")
+	}
+	src.WriteString("}\n")
+	if got := len(findingsFor(t, src.String())); got != MaxFindings {
+		t.Errorf("len(findings) = %d, want %d", got, MaxFindings)
+	}
+}
+
+func TestAnalyze_UnusedImportNeedsKnownName(t *testing.T) {
+	findings := findingsFor(t, `package main
+
+import (
+	"math/rand/v2"
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	_ = rand.IntN(3)
+	_ = yaml.Marshal
+}
+`)
+	for _, f := range findings {
+		if f.Check == CheckUnused {
+			t.Errorf("used import reported: %+v", f)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Comparison sets a learner's solution against another one: the
+// reference, or an alternative approach the exercise author wrote
+type Comparison struct {
+	Name        string `json:"name"` // "reference", or the alternative's name
+	Description string `json:"description,omitempty"`
+
+	// Similarity is how alike the two are in structure, from 0 (nothing
+	// in common) to 1 (the same code up to names and literals)
+	Similarity float64 `json:"similarity"`
+
+	Shared  []string `json:"shared,omitempty"`  // idioms both use
+	Untried []string `json:"untried,omitempty"` // idioms it uses and the learner's code doesn't
+}
+
+// ApproachReport is how a finished solution compares with the ones the
+// exercise ships
+type ApproachReport struct {
+	Idioms       []string     `json:"idioms"` // the learner's
+	Reference    *Comparison  `json:"reference,omitempty"`
+	Alternatives []Comparison `json:"alternatives,omitempty"`
+}
+
+// Untried returns every idiom the reference or an alternative uses that
+// the learner's code doesn't, without repeats
+func (r *ApproachReport) Untried() []string {
+	seen := map[string]bool{}
+	var untried []string
+	comparisons := r.Alternatives
+	if r.Reference != nil {
+		comparisons = append([]Comparison{*r.Reference}, comparisons...)
+	}
+	for _, c := range comparisons {
+		for _, idiom := range c.Untried {
+			if !seen[idiom] {
+				seen[idiom] = true
+				untried = append(untried, idiom)
+			}
+		}
+	}
+	return untried
+}
+
+// shape is what CompareApproach looks at in a solution's Go files, test
+// files left out
+type shape struct {
+	edges  map[string]int // parent>child node kinds, with counts
+	idioms map[string]bool
+}
+
+// CompareApproach compares the learner's solution with other, which is
+// named name. Only the parser is used; files that don't parse are left
+// out, and ok is false when either side has no Go code to compare.
+func CompareApproach(name string, learner, other map[string]string) (c Comparison, ok bool) {
+	mine, theirs := parseShape(learner), parseShape(other)
+	if mine == nil || theirs == nil {
+		return Comparison{}, false
+	}
+	c = Comparison{Name: name, Similarity: similarity(mine.edges, theirs.edges)}
+	for idiom := range theirs.idioms {
+		if mine.idioms[idiom] {
+			c.Shared = append(c.Shared, idiom)
+		} else {
+			c.Untried = append(c.Untried, idiom)
+		}
+	}
+	sort.Strings(c.Shared)
+	sort.Strings(c.Untried)
+	return c, true
+}
+
+// Idioms lists the idioms the Go files in code use, test files left out
+func Idioms(code map[string]string) []string {
+	s := parseShape(code)
+	if s == nil {
+		return nil
+	}
+	idioms := make([]string, 0, len(s.idioms))
+	for idiom := range s.idioms {
+		idioms = append(idioms, idiom)
+	}
+	sort.Strings(idioms)
+	return idioms
+}
+
+func parseShape(code map[string]string) *shape {
+	fset := token.NewFileSet()
+	s := &shape{edges: map[string]int{}, idioms: map[string]bool{}}
+	parsed := false
+	for _, name := range sortedGoFiles(code) {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, code[name], parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		parsed = true
+		s.add(file)
+	}
+	if !parsed {
+		return nil
+	}
+	return s
+}
+
+func (s *shape) add(file *ast.File) {
+	imports := map[string]bool{}
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := importName(path)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		imports[name] = true
+	}
+
+	var stack []ast.Node
+	var funcName string // the top-level function being walked, for recursion
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		if len(stack) > 0 {
+			s.edges[kind(stack[len(stack)-1])+">"+kind(n)]++
+		}
+		stack = append(stack, n)
+
+		if fn, ok := n.(*ast.FuncDecl); ok {
+			funcName = ""
+			if fn.Recv == nil {
+				funcName = fn.Name.Name
+			}
+		}
+		s.idiom(n, imports, funcName)
+		return true
+	})
+}
+
+// idiom records the idiom n is, if any. The names are what a learner
+// would recognize: constructs, and the standard library calls they make.
+func (s *shape) idiom(n ast.Node, imports map[string]bool, funcName string) {
+	switch n := n.(type) {
+	case *ast.RangeStmt:
+		s.idioms["range loop"] = true
+	case *ast.ForStmt:
+		if n.Init != nil || n.Post != nil {
+			s.idioms["three-clause for loop"] = true
+		} else if n.Cond == nil {
+			s.idioms["infinite for loop"] = true
+		}
+	case *ast.SwitchStmt:
+		s.idioms["switch"] = true
+	case *ast.TypeSwitchStmt:
+		s.idioms["type switch"] = true
+	case *ast.SelectStmt:
+		s.idioms["select"] = true
+	case *ast.GoStmt:
+		s.idioms["goroutine"] = true
+	case *ast.DeferStmt:
+		s.idioms["defer"] = true
+	case *ast.ChanType:
+		s.idioms["channel"] = true
+	case *ast.MapType:
+		s.idioms["map"] = true
+	case *ast.FuncLit:
+		s.idioms["closure"] = true
+	case *ast.InterfaceType:
+		s.idioms["interface"] = true
+	case *ast.StructType:
+		s.idioms["struct"] = true
+	case *ast.FuncDecl:
+		if n.Recv != nil {
+			s.idioms["method"] = true
+		}
+		if n.Type.TypeParams != nil {
+			s.idioms["generics"] = true
+		}
+		if n.Type.Results != nil && n.Type.Results.NumFields() > 1 {
+			s.idioms["multiple return values"] = true
+		}
+	case *ast.TypeSpec:
+		if n.TypeParams != nil {
+			s.idioms["generics"] = true
+		}
+	case *ast.CallExpr:
+		switch fun := n.Fun.(type) {
+		case *ast.Ident:
+			switch {
+			case fun.Name == funcName:
+				s.idioms["recursion"] = true
+			case fun.Name == "append" || fun.Name == "make" || fun.Name == "copy":
+				s.idioms[fun.Name] = true
+			}
+		case *ast.SelectorExpr:
+			if pkg, ok := fun.X.(*ast.Ident); ok && imports[pkg.Name] {
+				s.idioms[fmt.Sprintf("%s.%s", pkg.Name, fun.Sel.Name)] = true
+				if pkg.Name == "fmt" && fun.Sel.Name == "Errorf" && wrapsError(n) {
+					s.idioms["error wrapping"] = true
+				}
+			}
+		}
+	}
+}
+
+// wrapsError reports whether a fmt.Errorf call wraps with %w
+func wrapsError(call *ast.CallExpr) bool {
+	if len(call.Args) == 0 {
+		return false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	return ok && lit.Kind == token.STRING && strings.Contains(lit.Value, "%w")
+}
+
+// kind names a node's type without the package: "RangeStmt"
+func kind(n ast.Node) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", n), "*ast.")
+}
+
+// similarity is the weighted Jaccard index of two edge counts. Names and
+// literals don't appear in the edges, so renaming changes nothing.
+func similarity(a, b map[string]int) float64 {
+	var shared, total int
+	for edge, n := range a {
+		m := b[edge]
+		shared += min(n, m)
+		total += max(n, m)
+	}
+	for edge, m := range b {
+		if _, ok := a[edge]; !ok {
+			total += m
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(shared)/float64(total)*100) / 100
+}
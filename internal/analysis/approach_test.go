@@ -0,0 +1,96 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+const rangeSum = `package main
+
+func Sum(nums []int) int {
+	total := 0
+	for _, n := range nums {
+		total += n
+	}
+	return total
+}
+`
+
+func TestCompareApproach(t *testing.T) {
+	renamed := map[string]string{"sum.go": `package main
+
+func Add(values []int) int {
+	acc := 0
+	for _, v := range values {
+		acc += v
+	}
+	return acc
+}
+`}
+	c, ok := CompareApproach("reference", renamed, map[string]string{"main.go": rangeSum})
+	if !ok || c.Similarity != 1 || len(c.Untried) != 0 {
+		t.Errorf("renamed copy = %+v, %v; want similarity 1 and nothing untried", c, ok)
+	}
+
+	indexLoop := map[string]string{"main.go": `package main
+
+func Sum(nums []int) int {
+	total := 0
+	for i := 0; i < len(nums); i++ {
+		total += nums[i]
+	}
+	return total
+}
+`, "main_test.go": "package main\n\nfunc helper() { go func() {}() }\n"}
+	c, ok = CompareApproach("reference", indexLoop, map[string]string{"main.go": rangeSum})
+	if !ok || c.Similarity <= 0 || c.Similarity >= 1 {
+		t.Errorf("index loop similarity = %v, %v; want between 0 and 1", c.Similarity, ok)
+	}
+	if !reflect.DeepEqual(c.Untried, []string{"range loop"}) {
+		t.Errorf("Untried = %v, want [range loop]", c.Untried)
+	}
+
+	if _, ok := CompareApproach("reference", map[string]string{"main.py": "print(1)"}, map[string]string{"main.go": rangeSum}); ok {
+		t.Error("CompareApproach() with no Go on one side should not compare")
+	}
+}
+
+func TestIdioms(t *testing.T) {
+	code := map[string]string{"main.go": `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Greeter struct{ name string }
+
+func (g Greeter) Greet() string { return strings.ToUpper(g.name) }
+
+func Fact(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return n * Fact(n-1)
+}
+
+func load(path string) error {
+	defer func() {}()
+	return fmt.Errorf("load %s: %w", path, nil)
+}
+`}
+	want := []string{"closure", "defer", "error wrapping", "fmt.Errorf", "method", "recursion", "strings.ToUpper", "struct"}
+	if got := Idioms(code); !reflect.DeepEqual(got, want) {
+		t.Errorf("Idioms() = %v, want %v", got, want)
+	}
+}
+
+func TestApproachReport_Untried(t *testing.T) {
+	r := &ApproachReport{
+		Reference:    &Comparison{Untried: []string{"range loop", "strings.Builder"}},
+		Alternatives: []Comparison{{Untried: []string{"recursion", "range loop"}}},
+	}
+	if got := r.Untried(); !reflect.DeepEqual(got, []string{"range loop", "strings.Builder", "recursion"}) {
+		t.Errorf("Untried() = %v", got)
+	}
+}
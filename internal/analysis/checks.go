@@ -0,0 +1,191 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// checkShadow reports a := that declares a name an enclosing block of the
+// same function already declares. Shadowing inside a loop body is called
+// out, since it's where an outer accumulator silently stops updating.
+func (c *checker) checkShadow(assign *ast.AssignStmt) {
+	if assign.Tok != token.DEFINE {
+		return
+	}
+	for _, lhs := range assign.Lhs {
+		ident, ok := lhs.(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			continue
+		}
+		obj, ok := c.info.Defs[ident].(*types.Var)
+		if !ok || obj.Parent() == nil || obj.Parent().Parent() == nil {
+			continue
+		}
+		_, outer := obj.Parent().Parent().LookupParent(ident.Name, ident.Pos())
+		shadowed, ok := outer.(*types.Var)
+		if !ok || shadowed.Parent() == nil || isPackageLevel(shadowed) {
+			continue
+		}
+		where := ""
+		if c.inLoop(obj.Parent(), shadowed.Parent()) {
+			where = " inside a loop"
+		}
+		declared := c.fset.Position(shadowed.Pos())
+		c.report(ident.Pos(), CheckShadow, fmt.Sprintf(
+			"%s declared with :=%s shadows %s declared at line %d", ident.Name, where, ident.Name, declared.Line))
+	}
+}
+
+func isPackageLevel(v *types.Var) bool {
+	return v.Pkg() != nil && v.Parent() == v.Pkg().Scope()
+}
+
+// inLoop reports whether a scope between inner and outer belongs to a for
+// or range statement
+func (c *checker) inLoop(inner, outer *types.Scope) bool {
+	loops := make(map[*types.Scope]bool)
+	for node, scope := range c.info.Scopes {
+		switch node.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			loops[scope] = true
+		}
+	}
+	for s := inner; s != nil && s != outer; s = s.Parent() {
+		if loops[s] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSelfAssign reports x = x
+func (c *checker) checkSelfAssign(assign *ast.AssignStmt) {
+	if assign.Tok != token.ASSIGN || len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+	for i, lhs := range assign.Lhs {
+		l, ok := lhs.(*ast.Ident)
+		if !ok || l.Name == "_" {
+			continue
+		}
+		if r, ok := assign.Rhs[i].(*ast.Ident); ok && r.Name == l.Name {
+			c.report(l.Pos(), CheckSelfAssign, fmt.Sprintf("self-assignment of %s to itself", l.Name))
+		}
+	}
+}
+
+// printfFuncs maps fmt's formatting functions to the index of their
+// format argument
+var printfFuncs = map[string]int{
+	"Printf":  0,
+	"Sprintf": 0,
+	"Errorf":  0,
+	"Fprintf": 1,
+	"Appendf": 1,
+}
+
+// checkPrintf reports a fmt formatting call whose verbs and arguments
+// don't line up. Formats with explicit argument indexes or * widths are
+// skipped.
+func (c *checker) checkPrintf(call *ast.CallExpr) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok || pkg.Name != "fmt" {
+		return
+	}
+	idx, ok := printfFuncs[sel.Sel.Name]
+	if !ok || len(call.Args) <= idx || call.Ellipsis.IsValid() {
+		return
+	}
+	lit, ok := call.Args[idx].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return
+	}
+	format, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return
+	}
+	verbs, ok := countVerbs(format)
+	if !ok {
+		return
+	}
+	args := len(call.Args) - idx - 1
+	if verbs != args {
+		c.report(call.Pos(), CheckPrintf, fmt.Sprintf(
+			"fmt.%s format has %d verbs but %d arguments", sel.Sel.Name, verbs, args))
+	}
+}
+
+// countVerbs counts the arguments a format consumes; ok is false when the
+// format uses features the count can't follow
+func countVerbs(format string) (n int, ok bool) {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		// flags, width and precision
+		for i < len(format) && strings.IndexByte("+-# 0123456789.", format[i]) >= 0 {
+			i++
+		}
+		if i >= len(format) {
+			return n, true
+		}
+		switch format[i] {
+		case '%':
+		case '*', '[':
+			return 0, false
+		default:
+			n++
+		}
+	}
+	return n, true
+}
+
+// checkUnreachable reports the first statement after a return, panic,
+// break, continue or goto in the same block
+func (c *checker) checkUnreachable(stmts []ast.Stmt) {
+	for i, stmt := range stmts[:max(len(stmts)-1, 0)] {
+		if !terminates(stmt) {
+			continue
+		}
+		next := stmts[i+1]
+		if _, ok := next.(*ast.LabeledStmt); ok {
+			return // a goto may land there
+		}
+		c.report(next.Pos(), CheckUnreachable, "unreachable code after "+describe(stmt))
+		return
+	}
+}
+
+func terminates(stmt ast.Stmt) bool {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt, *ast.BranchStmt:
+		return true
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		return ok && ident.Name == "panic"
+	}
+	return false
+}
+
+func describe(stmt ast.Stmt) string {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		return "return"
+	case *ast.BranchStmt:
+		return s.Tok.String()
+	}
+	return "panic"
+}
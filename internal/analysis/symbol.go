@@ -0,0 +1,308 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// ErrSymbolNotFound is returned when no identifier with the requested
+// name is at the requested position
+var ErrSymbolNotFound = errors.New("symbol not found at position")
+
+// Symbol kinds
+const (
+	KindFunc    = "func"
+	KindMethod  = "method"
+	KindVar     = "var"
+	KindParam   = "param"
+	KindField   = "field"
+	KindConst   = "const"
+	KindType    = "type"
+	KindPackage = "package"
+	KindLabel   = "label"
+	KindBuiltin = "builtin"
+	KindImport  = "imported" // declared in an imported package
+)
+
+// Bounds on how much of the code a symbol carries
+const (
+	MaxUsages           = 20
+	maxDeclarationLines = 40
+)
+
+// Location is a place in the code with its source text
+type Location struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Source string `json:"source"` // the declaration, or the usage's line
+}
+
+// Symbol is a resolved identifier: what it is, where it's declared and
+// where the code uses it
+type Symbol struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	// Type is the symbol's type as the checker sees it; empty when it
+	// comes from an imported package
+	Type string `json:"type,omitempty"`
+	// Package is the import path of an imported symbol
+	Package string `json:"package,omitempty"`
+	// Declaration is nil for imported and builtin symbols
+	Declaration *Location  `json:"declaration,omitempty"`
+	Usages      []Location `json:"usages,omitempty"`
+}
+
+// ResolveSymbol finds the identifier name on line of file and resolves it
+// against the rest of the code. column, 1-based, picks between several
+// occurrences on the line; 0 takes the first.
+func ResolveSymbol(code map[string]string, file string, line, column int, name string) (*Symbol, error) {
+	src, ok := code[file]
+	if !ok {
+		return nil, ErrSymbolNotFound
+	}
+
+	fset := token.NewFileSet()
+	target, err := parser.ParseFile(fset, file, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+	files := []*ast.File{target}
+	for _, other := range sortedGoFiles(code) {
+		if other == file {
+			continue
+		}
+		f, err := parser.ParseFile(fset, other, code[other], parser.SkipObjectResolution)
+		if err != nil || f.Name.Name != target.Name.Name {
+			continue // the symbol may still resolve without it
+		}
+		files = append(files, f)
+	}
+
+	c := &checker{fset: fset}
+	c.typeCheck(files)
+
+	ident, sel := findIdent(fset, target, line, column, name)
+	if ident == nil {
+		return nil, ErrSymbolNotFound
+	}
+
+	r := &resolver{fset: fset, code: code, info: c.info, files: files}
+	if obj := r.object(ident); obj != nil {
+		return r.local(obj), nil
+	}
+	if sel != nil {
+		if pkg, ok := r.info.Uses[sel.X.(*ast.Ident)].(*types.PkgName); ok {
+			return r.imported(pkg, ident.Name), nil
+		}
+	}
+	return nil, ErrSymbolNotFound
+}
+
+// findIdent returns the identifier at the position and, when it's the
+// selected name of x.Name, the selector
+func findIdent(fset *token.FileSet, file *ast.File, line, column int, name string) (*ast.Ident, *ast.SelectorExpr) {
+	var found *ast.Ident
+	ast.Inspect(file, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if found != nil || !ok || ident.Name != name {
+			return found == nil
+		}
+		start, end := fset.Position(ident.Pos()), fset.Position(ident.End())
+		if start.Line == line && (column == 0 || (column >= start.Column && column <= end.Column)) {
+			found = ident
+		}
+		return found == nil
+	})
+	if found == nil {
+		return nil, nil
+	}
+
+	var sel *ast.SelectorExpr
+	ast.Inspect(file, func(n ast.Node) bool {
+		if s, ok := n.(*ast.SelectorExpr); ok && s.Sel == found {
+			if _, ok := s.X.(*ast.Ident); ok {
+				sel = s
+			}
+		}
+		return sel == nil
+	})
+	return found, sel
+}
+
+type resolver struct {
+	fset  *token.FileSet
+	code  map[string]string
+	info  *types.Info
+	files []*ast.File
+}
+
+func (r *resolver) object(ident *ast.Ident) types.Object {
+	if obj := r.info.Defs[ident]; obj != nil {
+		return obj
+	}
+	return r.info.Uses[ident]
+}
+
+// local describes a symbol the checker resolved
+func (r *resolver) local(obj types.Object) *Symbol {
+	sym := &Symbol{Name: obj.Name(), Kind: kindOf(obj)}
+	switch obj := obj.(type) {
+	case *types.PkgName:
+		sym.Package = obj.Imported().Path()
+		sym.Usages = r.usages(func(ident *ast.Ident) bool { return r.info.Uses[ident] == obj })
+		return sym
+	case *types.Builtin:
+		sym.Usages = r.usages(func(ident *ast.Ident) bool { return r.info.Uses[ident] == obj })
+		return sym
+	}
+	if t := obj.Type(); t != nil && t != types.Typ[types.Invalid] {
+		sym.Type = types.TypeString(t, types.RelativeTo(obj.Pkg()))
+	}
+	if obj.Pos().IsValid() {
+		sym.Declaration = r.declaration(obj)
+	}
+	sym.Usages = r.usages(func(ident *ast.Ident) bool { return r.info.Uses[ident] == obj })
+	return sym
+}
+
+// imported describes pkg.name from a package the checker couldn't load;
+// usages are matched by name
+func (r *resolver) imported(pkg *types.PkgName, name string) *Symbol {
+	sym := &Symbol{Name: pkg.Name() + "." + name, Kind: KindImport, Package: pkg.Imported().Path()}
+	var matches []*ast.Ident
+	for _, file := range r.files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != name {
+				return true
+			}
+			if x, ok := sel.X.(*ast.Ident); ok && r.info.Uses[x] == pkg {
+				matches = append(matches, sel.Sel)
+			}
+			return true
+		})
+	}
+	sym.Usages = r.locations(matches)
+	return sym
+}
+
+func kindOf(obj types.Object) string {
+	switch obj := obj.(type) {
+	case *types.Func:
+		if sig, ok := obj.Type().(*types.Signature); ok && sig.Recv() != nil {
+			return KindMethod
+		}
+		return KindFunc
+	case *types.Var:
+		switch {
+		case obj.IsField():
+			return KindField
+		case obj.Kind() == types.ParamVar || obj.Kind() == types.RecvVar || obj.Kind() == types.ResultVar:
+			return KindParam
+		}
+		return KindVar
+	case *types.Const:
+		return KindConst
+	case *types.TypeName:
+		return KindType
+	case *types.PkgName:
+		return KindPackage
+	case *types.Label:
+		return KindLabel
+	}
+	return KindBuiltin
+}
+
+// declaration returns the source of the declaration that introduces obj:
+// the whole function or type declaration, or the line of anything else
+func (r *resolver) declaration(obj types.Object) *Location {
+	pos := r.fset.Position(obj.Pos())
+	loc := &Location{File: pos.Filename, Line: pos.Line, Column: pos.Column, Source: r.line(pos)}
+
+	for _, file := range r.files {
+		if r.fset.Position(file.Pos()).Filename != pos.Filename {
+			continue
+		}
+		for _, decl := range file.Decls {
+			var node ast.Node
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Name.Pos() == obj.Pos() {
+					node = decl
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Pos() == obj.Pos() {
+						node = ts
+					}
+				}
+			}
+			if node != nil {
+				loc.Source = r.span(node)
+				return loc
+			}
+		}
+	}
+	return loc
+}
+
+// usages lists the identifiers match accepts, in file order
+func (r *resolver) usages(match func(*ast.Ident) bool) []Location {
+	var idents []*ast.Ident
+	for ident := range r.info.Uses {
+		if match(ident) {
+			idents = append(idents, ident)
+		}
+	}
+	return r.locations(idents)
+}
+
+func (r *resolver) locations(idents []*ast.Ident) []Location {
+	locs := make([]Location, 0, len(idents))
+	for _, ident := range idents {
+		pos := r.fset.Position(ident.Pos())
+		locs = append(locs, Location{File: pos.Filename, Line: pos.Line, Column: pos.Column, Source: r.line(pos)})
+	}
+	sort.Slice(locs, func(i, j int) bool {
+		if locs[i].File != locs[j].File {
+			return locs[i].File < locs[j].File
+		}
+		if locs[i].Line != locs[j].Line {
+			return locs[i].Line < locs[j].Line
+		}
+		return locs[i].Column < locs[j].Column
+	})
+	if len(locs) > MaxUsages {
+		locs = locs[:MaxUsages]
+	}
+	return locs
+}
+
+func (r *resolver) line(pos token.Position) string {
+	lines := strings.Split(r.code[pos.Filename], "\n")
+	if pos.Line < 1 || pos.Line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[pos.Line-1])
+}
+
+// span returns a node's source, cut to maxDeclarationLines
+func (r *resolver) span(node ast.Node) string {
+	start, end := r.fset.Position(node.Pos()), r.fset.Position(node.End())
+	src := r.code[start.Filename]
+	if start.Offset < 0 || end.Offset > len(src) || start.Offset > end.Offset {
+		return r.line(start)
+	}
+	lines := strings.Split(src[start.Offset:end.Offset], "\n")
+	if len(lines) > maxDeclarationLines {
+		lines = append(lines[:maxDeclarationLines], "// ...")
+	}
+	return strings.Join(lines, "\n")
+}
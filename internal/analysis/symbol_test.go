@@ -0,0 +1,100 @@
+package analysis
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var symbolCode = map[string]string{
+	"main.go": `package main
+
+import "fmt"
+
+type Counter struct {
+	n int
+}
+
+func (c *Counter) Add(delta int) {
+	c.n += delta
+}
+
+func main() {
+	total := 0
+	c := &Counter{}
+	for i := 0; i < 3; i++ {
+		c.Add(i)
+		total += i
+	}
+	fmt.Println(total, c.n)
+	fmt.Println("done")
+}
+`,
+	"main_test.go": `package main
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	c := &Counter{}
+	c.Add(2)
+}
+`,
+}
+
+func TestResolveSymbol_Method(t *testing.T) {
+	sym, err := ResolveSymbol(symbolCode, "main.go", 17, 5, "Add")
+	if err != nil {
+		t.Fatalf("ResolveSymbol() error = %v", err)
+	}
+	if sym.Kind != KindMethod || sym.Type != "func(delta int)" {
+		t.Errorf("Kind, Type = %q, %q", sym.Kind, sym.Type)
+	}
+	if sym.Declaration == nil || sym.Declaration.Line != 9 || !strings.Contains(sym.Declaration.Source, "c.n += delta") {
+		t.Errorf("Declaration = %+v, want the whole method", sym.Declaration)
+	}
+	if len(sym.Usages) != 2 || sym.Usages[0].File != "main.go" || sym.Usages[1].File != "main_test.go" {
+		t.Errorf("Usages = %+v, want one per file", sym.Usages)
+	}
+}
+
+func TestResolveSymbol_Variable(t *testing.T) {
+	sym, err := ResolveSymbol(symbolCode, "main.go", 20, 0, "total")
+	if err != nil {
+		t.Fatalf("ResolveSymbol() error = %v", err)
+	}
+	if sym.Kind != KindVar || sym.Type != "int" || sym.Declaration.Source != "total := 0" {
+		t.Errorf("sym = %+v", sym)
+	}
+	if len(sym.Usages) != 2 {
+		t.Errorf("Usages = %+v, want the += and the Println", sym.Usages)
+	}
+}
+
+func TestResolveSymbol_Imported(t *testing.T) {
+	sym, err := ResolveSymbol(symbolCode, "main.go", 20, 6, "Println")
+	if err != nil {
+		t.Fatalf("ResolveSymbol() error = %v", err)
+	}
+	if sym.Kind != KindImport || sym.Name != "fmt.Println" || sym.Package != "fmt" || sym.Declaration != nil {
+		t.Errorf("sym = %+v", sym)
+	}
+	if len(sym.Usages) != 2 {
+		t.Errorf("Usages = %+v, want both calls", sym.Usages)
+	}
+}
+
+func TestResolveSymbol_NotFound(t *testing.T) {
+	for _, tc := range []struct {
+		file string
+		line int
+		name string
+	}{
+		{"main.go", 14, "missing"},
+		{"main.go", 3, "total"},
+		{"other.go", 1, "main"},
+	} {
+		if _, err := ResolveSymbol(symbolCode, tc.file, tc.line, 0, tc.name); !errors.Is(err, ErrSymbolNotFound) {
+			t.Errorf("ResolveSymbol(%s:%d %s) error = %v, want ErrSymbolNotFound", tc.file, tc.line, tc.name, err)
+		}
+	}
+}
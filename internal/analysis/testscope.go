@@ -0,0 +1,213 @@
+package analysis
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrTestNotFound is returned when the code declares no test function
+// with the requested name
+var ErrTestNotFound = errors.New("test function not found")
+
+// Bounds on how much code a test scope carries
+const (
+	MaxCallees    = 8
+	maxCallDepth  = 3
+	maxAssertions = 5
+)
+
+// Function is a function declaration in the learner's code
+type Function struct {
+	Name string `json:"name"`
+	Location
+}
+
+// TestScope is the code a failing test exercises: the test, the lines
+// its output blames and the learner's functions it reaches
+type TestScope struct {
+	Test Function `json:"test"`
+	// Assertions are the lines the failure output points at, e.g. the
+	// t.Errorf that reported it
+	Assertions []Location `json:"assertions,omitempty"`
+	// Callees are the functions the test calls, directly or through other
+	// functions of the package, nearest first
+	Callees []Function `json:"callees,omitempty"`
+}
+
+// outputLocation matches the file:line prefix go test puts on t.Log and
+// t.Error output
+var outputLocation = regexp.MustCompile(`([\w.-]+\.go):(\d+):`)
+
+// ScopeTest finds the test function behind test, which may name a
+// subtest, and follows its calls into the rest of the package. output is
+// the test's failure output; the lines it references become assertions.
+func ScopeTest(code map[string]string, test, output string) (*TestScope, error) {
+	name, _, _ := strings.Cut(test, "/")
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range sortedGoFiles(code) {
+		f, err := parser.ParseFile(fset, path, code[path], parser.SkipObjectResolution)
+		if err != nil {
+			continue // unparseable files can't contribute callees
+		}
+		files = append(files, f)
+	}
+
+	var testDecl *ast.FuncDecl
+	var pkgName string
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+				testDecl, pkgName = fn, f.Name.Name
+			}
+		}
+	}
+	if testDecl == nil {
+		return nil, ErrTestNotFound
+	}
+
+	var pkgFiles []*ast.File
+	for _, f := range files {
+		if f.Name.Name == pkgName {
+			pkgFiles = append(pkgFiles, f)
+		}
+	}
+	c := &checker{fset: fset}
+	c.typeCheck(pkgFiles)
+	r := &resolver{fset: fset, code: code, info: c.info, files: pkgFiles}
+
+	scope := &TestScope{Test: r.function(name, testDecl)}
+	scope.Assertions = r.assertions(output)
+	scope.Callees = r.callees(testDecl)
+	return scope, nil
+}
+
+func (r *resolver) function(name string, decl *ast.FuncDecl) Function {
+	pos := r.fset.Position(decl.Pos())
+	return Function{
+		Name:     name,
+		Location: Location{File: pos.Filename, Line: pos.Line, Column: pos.Column, Source: r.span(decl)},
+	}
+}
+
+// assertions returns the code lines the output references, once each
+func (r *resolver) assertions(output string) []Location {
+	var locs []Location
+	seen := make(map[string]bool)
+	for _, m := range outputLocation.FindAllStringSubmatch(output, -1) {
+		line, _ := strconv.Atoi(m[2])
+		file := r.fileNamed(m[1])
+		key := file + ":" + m[2]
+		if file == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		pos := token.Position{Filename: file, Line: line}
+		if src := r.line(pos); src != "" {
+			locs = append(locs, Location{File: file, Line: line, Source: src})
+		}
+		if len(locs) == maxAssertions {
+			break
+		}
+	}
+	return locs
+}
+
+// fileNamed maps the base name go test prints to the code's path
+func (r *resolver) fileNamed(base string) string {
+	for path := range r.code {
+		if path == base || strings.HasSuffix(path, "/"+base) {
+			return path
+		}
+	}
+	return ""
+}
+
+// callees walks the calls from decl breadth first, so the functions the
+// test calls directly come before the ones they call
+func (r *resolver) callees(decl *ast.FuncDecl) []Function {
+	decls := make(map[types.Object]*ast.FuncDecl)
+	for _, f := range r.files {
+		for _, d := range f.Decls {
+			if fn, ok := d.(*ast.FuncDecl); ok && fn.Body != nil {
+				if obj := r.info.Defs[fn.Name]; obj != nil {
+					decls[obj] = fn
+				}
+			}
+		}
+	}
+
+	visited := map[*ast.FuncDecl]bool{decl: true}
+	var found []Function
+	frontier := []*ast.FuncDecl{decl}
+	for depth := 0; depth < maxCallDepth && len(frontier) > 0; depth++ {
+		var next []*ast.FuncDecl
+		for _, fn := range frontier {
+			for _, callee := range r.calledBy(fn, decls) {
+				if visited[callee] {
+					continue
+				}
+				visited[callee] = true
+				found = append(found, r.function(funcName(callee), callee))
+				if len(found) == MaxCallees {
+					return found
+				}
+				next = append(next, callee)
+			}
+		}
+		frontier = next
+	}
+	return found
+}
+
+// calledBy returns the package's functions fn calls, in source order
+func (r *resolver) calledBy(fn *ast.FuncDecl, decls map[types.Object]*ast.FuncDecl) []*ast.FuncDecl {
+	var called []*ast.FuncDecl
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		var ident *ast.Ident
+		switch f := call.Fun.(type) {
+		case *ast.Ident:
+			ident = f
+		case *ast.SelectorExpr:
+			ident = f.Sel
+		}
+		if ident != nil {
+			if d, ok := decls[r.info.Uses[ident]]; ok {
+				called = append(called, d)
+			}
+		}
+		return true
+	})
+	sort.SliceStable(called, func(i, j int) bool { return called[i].Pos() < called[j].Pos() })
+	return called
+}
+
+// funcName returns Name, or Type.Name for a method
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	t := fn.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	if idx, ok := t.(*ast.IndexExpr); ok {
+		t = idx.X
+	}
+	if ident, ok := t.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
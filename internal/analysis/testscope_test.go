@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"errors"
+	"testing"
+)
+
+var scopeCode = map[string]string{
+	"stack.go": `package stack
+
+type Stack struct {
+	items []int
+}
+
+func (s *Stack) Push(v int) {
+	s.items = append(s.items, v)
+}
+
+func (s *Stack) Pop() int {
+	v := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return v
+}
+
+func Reverse(xs []int) []int {
+	s := &Stack{}
+	for _, x := range xs {
+		s.Push(x)
+	}
+	return drain(s, len(xs))
+}
+
+func drain(s *Stack, n int) []int {
+	out := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		out = append(out, s.Pop())
+	}
+	return out
+}
+
+func Unrelated() {}
+`,
+	"stack_test.go": `package stack
+
+import "testing"
+
+func TestReverse(t *testing.T) {
+	got := Reverse([]int{1, 2, 3})
+	if got[0] != 3 {
+		t.Errorf("got %v", got)
+	}
+}
+`,
+}
+
+func TestScopeTest(t *testing.T) {
+	scope, err := ScopeTest(scopeCode, "TestReverse/empty", "    stack_test.go:8: got [1 2 3]\n")
+	if err != nil {
+		t.Fatalf("ScopeTest() error = %v", err)
+	}
+	if scope.Test.Name != "TestReverse" || scope.Test.File != "stack_test.go" || scope.Test.Line != 5 {
+		t.Errorf("Test = %+v", scope.Test)
+	}
+	if len(scope.Assertions) != 1 || scope.Assertions[0].Line != 8 || scope.Assertions[0].Source != `t.Errorf("got %v", got)` {
+		t.Errorf("Assertions = %+v", scope.Assertions)
+	}
+
+	var names []string
+	for _, fn := range scope.Callees {
+		names = append(names, fn.Name)
+	}
+	want := []string{"Reverse", "Stack.Push", "drain", "Stack.Pop"}
+	if len(names) != len(want) {
+		t.Fatalf("Callees = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("Callees = %v, want %v", names, want)
+			break
+		}
+	}
+}
+
+func TestScopeTest_NotFound(t *testing.T) {
+	if _, err := ScopeTest(scopeCode, "TestMissing", ""); !errors.Is(err, ErrTestNotFound) {
+		t.Errorf("error = %v, want ErrTestNotFound", err)
+	}
+}
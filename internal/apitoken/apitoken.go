@@ -0,0 +1,221 @@
+// Package apitoken manages scoped tokens for integrations. The daemon's
+// own auth token has full control; a scoped token lets an external
+// dashboard or script in only as far as its scope allows. Tokens are
+// shown once when created and stored as hashes.
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/storage/local"
+)
+
+const collectionTokens = "tokens"
+
+// Prefix marks scoped tokens, so a leaked one is recognizable
+const Prefix = "tpr_"
+
+// Scopes a token can be created with
+const (
+	ScopeAnalytics = "analytics" // read-only analytics and profile
+	ScopeSessions  = "sessions"  // sessions, runs and hints
+	ScopeAdmin     = "admin"     // everything, like the daemon's own token
+)
+
+// Scopes lists the valid scopes, narrowest first
+var Scopes = []string{ScopeAnalytics, ScopeSessions, ScopeAdmin}
+
+// ErrNotFound is returned for an unknown token ID
+var ErrNotFound = errors.New("token not found")
+
+// ErrInvalidScope is returned for a scope not in Scopes
+var ErrInvalidScope = errors.New("invalid scope")
+
+// Token is a scoped token as stored; the secret itself is never kept
+type Token struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Scope     string    `json:"scope"`
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// statusPaths are readable with any scope, so a client can tell whether
+// the daemon is up and what it can do
+var statusPaths = []string{"/v1/status", "/v1/ready"}
+
+// scopeRoutes are the path prefixes each narrow scope reaches, and
+// whether only GET and HEAD are allowed there
+var scopeRoutes = map[string][]struct {
+	prefix   string
+	readOnly bool
+}{
+	ScopeAnalytics: {
+		{"/v1/analytics", true},
+		{"/v1/profile", true},
+		{"/v1/achievements", true},
+		{"/v1/cohorts", true},
+		{"/v1/metrics", true},
+	},
+	ScopeSessions: {
+		{"/v1/sessions", false},
+		{"/v1/patches", false},
+		{"/v1/exercises", true},
+		{"/v1/tracks", true},
+		{"/v1/concepts", true},
+	},
+}
+
+// Allows reports whether a token with scope may make the request
+func Allows(scope, method, path string) bool {
+	if scope == ScopeAdmin {
+		return true
+	}
+	read := method == "GET" || method == "HEAD"
+	for _, p := range statusPaths {
+		if read && path == p {
+			return true
+		}
+	}
+	for _, route := range scopeRoutes[scope] {
+		if (path == route.prefix || strings.HasPrefix(path, route.prefix+"/")) && (read || !route.readOnly) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidScope reports whether scope is one of Scopes
+func ValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Store keeps tokens as JSON files under tokens/, with an in-memory index
+// by hash for checking requests
+type Store struct {
+	mu     sync.RWMutex
+	store  *local.Store
+	byHash map[string]*Token
+	now    func() time.Time
+}
+
+// NewStore opens the tokens under basePath (usually ~/.temper)
+func NewStore(basePath string) (*Store, error) {
+	store, err := local.NewStore(basePath)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{store: store, byHash: map[string]*Token{}, now: time.Now}
+
+	ids, err := store.List(collectionTokens)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		var t Token
+		if err := store.Load(collectionTokens, id, &t); err != nil {
+			return nil, fmt.Errorf("load token %s: %w", id, err)
+		}
+		s.byHash[t.Hash] = &t
+	}
+	return s, nil
+}
+
+// Create makes a token with scope and returns it with its secret, which
+// is not stored and can't be shown again
+func (s *Store) Create(name, scope string) (*Token, string, error) {
+	if !ValidScope(scope) {
+		return nil, "", fmt.Errorf("%w %q (use %s)", ErrInvalidScope, scope, strings.Join(Scopes, ", "))
+	}
+	secret, err := randomHex(20)
+	if err != nil {
+		return nil, "", err
+	}
+	secret = Prefix + secret
+	id, err := randomHex(4)
+	if err != nil {
+		return nil, "", err
+	}
+
+	t := &Token{ID: id, Name: name, Scope: scope, Hash: hash(secret), CreatedAt: s.now().UTC()}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.store.Save(collectionTokens, t.ID, t); err != nil {
+		return nil, "", err
+	}
+	s.byHash[t.Hash] = t
+	return t, secret, nil
+}
+
+// List returns every token, oldest first
+func (s *Store) List() []*Token {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tokens := make([]*Token, 0, len(s.byHash))
+	for _, t := range s.byHash {
+		tokens = append(tokens, t)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].CreatedAt.Equal(tokens[j].CreatedAt) {
+			return tokens[i].ID < tokens[j].ID
+		}
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+	return tokens
+}
+
+// Revoke deletes the token with id; requests with it fail from then on
+func (s *Store) Revoke(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for h, t := range s.byHash {
+		if t.ID != id {
+			continue
+		}
+		if err := s.store.Delete(collectionTokens, id); err != nil && !errors.Is(err, local.ErrNotFound) {
+			return err
+		}
+		delete(s.byHash, h)
+		return nil
+	}
+	return ErrNotFound
+}
+
+// Lookup returns the token a secret belongs to. Secrets are compared by
+// hash, so the lookup's timing says nothing about the secret. A nil
+// store knows no tokens.
+func (s *Store) Lookup(secret string) (*Token, bool) {
+	if s == nil || !strings.HasPrefix(secret, Prefix) {
+		return nil, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.byHash[hash(secret)]
+	return t, ok
+}
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,78 @@
+package apitoken
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		scope, method, path string
+		want                bool
+	}{
+		{ScopeAnalytics, "GET", "/v1/analytics/overview", true},
+		{ScopeAnalytics, "GET", "/v1/status", true},
+		{ScopeAnalytics, "POST", "/v1/analytics/packs/go-v1/summary", false},
+		{ScopeAnalytics, "GET", "/v1/sessions", false},
+		{ScopeAnalytics, "DELETE", "/v1/profile", false},
+		{ScopeSessions, "POST", "/v1/sessions/abc/run", true},
+		{ScopeSessions, "GET", "/v1/exercises/go-v1", true},
+		{ScopeSessions, "PUT", "/v1/tracks/custom", false},
+		{ScopeSessions, "GET", "/v1/sessionsx", false},
+		{ScopeSessions, "POST", "/v1/admin/prune", false},
+		{ScopeSessions, "GET", "/v1/tokens", false},
+		{ScopeAdmin, "POST", "/v1/tokens", true},
+		{"unknown", "GET", "/v1/sessions", false},
+	}
+	for _, tt := range tests {
+		if got := Allows(tt.scope, tt.method, tt.path); got != tt.want {
+			t.Errorf("Allows(%q, %s %s) = %v, want %v", tt.scope, tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	if _, _, err := store.Create("ci", "root"); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("Create(root) error = %v, want ErrInvalidScope", err)
+	}
+
+	tok, secret, err := store.Create("grafana", ScopeAnalytics)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !strings.HasPrefix(secret, Prefix) || strings.Contains(tok.Hash, secret) {
+		t.Errorf("secret %q should carry the prefix and not be stored", secret)
+	}
+
+	// Reopened, the store still knows the token by its secret
+	store, err = NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore() reopen error = %v", err)
+	}
+	if got, ok := store.Lookup(secret); !ok || got.ID != tok.ID || got.Scope != ScopeAnalytics {
+		t.Errorf("Lookup() = %+v, %v; want %s", got, ok, tok.ID)
+	}
+	if _, ok := store.Lookup(secret + "x"); ok {
+		t.Error("Lookup() accepted a wrong secret")
+	}
+	if list := store.List(); len(list) != 1 || list[0].Name != "grafana" {
+		t.Errorf("List() = %+v, want the grafana token", list)
+	}
+
+	if err := store.Revoke(tok.ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, ok := store.Lookup(secret); ok {
+		t.Error("a revoked token still authenticates")
+	}
+	if err := store.Revoke(tok.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Revoke() twice error = %v, want ErrNotFound", err)
+	}
+}
@@ -106,3 +106,78 @@ func TestMetricsIsLeaf(t *testing.T) {
 		t.Errorf("internal/metrics must remain a leaf, but imports: %v", violations)
 	}
 }
+
+// TestVaultIsLeaf — storage encryption must not pull in the packages
+// whose data it protects.
+func TestVaultIsLeaf(t *testing.T) {
+	violations, err := AllowedInternalImports(
+		"github.com/felixgeelhaar/temper/internal/vault",
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("internal/vault must remain a leaf, but imports: %v", violations)
+	}
+}
+
+// TestRedactIsLeaf — the redaction layer sits in front of every LLM call
+// and must not depend on what it filters.
+func TestRedactIsLeaf(t *testing.T) {
+	violations, err := AllowedInternalImports(
+		"github.com/felixgeelhaar/temper/internal/redact",
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("internal/redact must remain a leaf, but imports: %v", violations)
+	}
+}
+
+// TestSpecImportDependsOnlyOnDomain keeps the issue tracker clients free
+// of daemon and spec storage concerns.
+func TestSpecImportDependsOnlyOnDomain(t *testing.T) {
+	violations, err := AllowedInternalImports(
+		"github.com/felixgeelhaar/temper/internal/specimport",
+		[]string{"github.com/felixgeelhaar/temper/internal/domain"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("internal/specimport may only import internal/domain, but imports: %v", violations)
+	}
+}
+
+// TestSARIFIsLeaf — the SARIF writer only knows its own finding type, so
+// any producer can report through it.
+func TestSARIFIsLeaf(t *testing.T) {
+	violations, err := AllowedInternalImports(
+		"github.com/felixgeelhaar/temper/internal/sarif",
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("internal/sarif must remain a leaf, but imports: %v", violations)
+	}
+}
+
+// TestGodocIsLeaf — doc lookups read GOROOT and nothing else; the pairing
+// service receives them as a plain function.
+func TestGodocIsLeaf(t *testing.T) {
+	violations, err := AllowedInternalImports(
+		"github.com/felixgeelhaar/temper/internal/godoc",
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("internal/godoc must remain a leaf, but imports: %v", violations)
+	}
+}
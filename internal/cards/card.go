@@ -0,0 +1,62 @@
+// Package cards turns what sessions explained to the learner into
+// question/answer flashcards and schedules them on the spaced-repetition
+// ladder the exercise review queue uses.
+package cards
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/profile"
+)
+
+// Kind says what a card was generated from
+type Kind string
+
+const (
+	// KindMistake cards ask what a recurring error means
+	KindMistake Kind = "mistake"
+	// KindConcept cards ask what a concept explained in a session is
+	KindConcept Kind = "concept"
+)
+
+// Card is a question/answer flashcard on the review ladder
+type Card struct {
+	ID        string   `json:"id"`
+	Kind      Kind     `json:"kind"`
+	Key       string   `json:"key"` // what the card is about, e.g. an error rule or concept ID
+	Front     string   `json:"front"`
+	Back      string   `json:"back"`
+	Exercises []string `json:"exercises,omitempty"` // where the learner met it
+	Seen      int      `json:"seen"`                // times it came up in sessions
+
+	Step         int        `json:"step"` // clean reviews in a row
+	DueAt        time.Time  `json:"due_at"`
+	LastReviewed *time.Time `json:"last_reviewed,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// cardID derives a stable ID from a card's kind and key, so generating
+// again finds the cards it made before
+func cardID(kind Kind, key string) string {
+	sum := sha256.Sum256([]byte(string(kind) + ":" + key))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Due reports whether the card should be reviewed at now
+func (c *Card) Due(now time.Time) bool {
+	return !c.DueAt.After(now)
+}
+
+// Review records an answer. A remembered card moves one step up the
+// ladder; a forgotten one drops back to the first step.
+func (c *Card) Review(remembered bool, now time.Time) {
+	if remembered {
+		c.Step++
+	} else {
+		c.Step = 0
+	}
+	c.LastReviewed = &now
+	c.DueAt = now.Add(profile.ReviewInterval(c.Step - 1))
+}
@@ -0,0 +1,198 @@
+package cards
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/concept"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func nilMapLesson(sessionID, runID string) session.Lesson {
+	return session.Lesson{
+		SessionID: sessionID, RunID: runID, ExerciseID: "go-v1/basics/maps",
+		Error: &domain.ErrorExplanation{
+			Original:    "main.go:12:3: assignment to entry in nil map",
+			Explanation: "The map was never created with make, so there is nowhere to store the entry.",
+			Rule:        "nil-map-write",
+		},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	idx := concept.NewIndex([]concept.Concept{
+		{ID: "closures", Name: "Closures", Summary: "A function value that captures variables from its scope.", Keywords: []string{"closure"}},
+	})
+	oneOff := session.Lesson{SessionID: "s1", RunID: "r1", Error: &domain.ErrorExplanation{Original: "x.go:1: undefined: y", Explanation: "y isn't declared."}}
+	lessons := []session.Lesson{
+		nilMapLesson("s1", "r1"),
+		nilMapLesson("s1", "r1"), // explained twice in one run
+		oneOff,
+		{SessionID: "s2", ExerciseID: "go-v1/funcs/counter", Explained: "A closure keeps the counter alive between calls."},
+	}
+	now := time.Now()
+
+	cards := Generate(lessons, idx, now)
+	if len(cards) != 1 || cards[0].Kind != KindConcept {
+		t.Fatalf("cards = %+v, want only the concept card while nil-map has one run", cards)
+	}
+
+	cards = Generate(append(lessons, nilMapLesson("s2", "r5")), idx, now)
+	if len(cards) != 2 {
+		t.Fatalf("got %d cards, want 2", len(cards))
+	}
+	conceptCard, mistake := cards[0], cards[1]
+	if conceptCard.Front != "What is Closures?" || conceptCard.Back != idx.List()[0].Summary {
+		t.Errorf("concept card = %+v", conceptCard)
+	}
+	if mistake.Key != "nil-map-write" || mistake.Seen != 2 || !mistake.Due(now) {
+		t.Errorf("mistake card = %+v", mistake)
+	}
+	if strings.Contains(mistake.Front, "main.go:12") || !strings.Contains(mistake.Front, "assignment to entry in nil map") {
+		t.Errorf("front = %q, want the error without its location", mistake.Front)
+	}
+	if again := Generate(append(lessons, nilMapLesson("s2", "r5")), idx, now.Add(time.Hour)); again[1].ID != mistake.ID {
+		t.Error("card IDs should be stable across generations")
+	}
+}
+
+func TestCard_Review(t *testing.T) {
+	now := time.Now()
+	c := &Card{DueAt: now}
+
+	c.Review(true, now)
+	if c.Step != 1 || !c.DueAt.Equal(now.Add(24*time.Hour)) {
+		t.Errorf("after one clean review: step %d, due in %v", c.Step, c.DueAt.Sub(now))
+	}
+	c.Review(true, now)
+	if c.DueAt.Sub(now) != 3*24*time.Hour {
+		t.Errorf("after two clean reviews due in %v, want 3 days", c.DueAt.Sub(now))
+	}
+	c.Review(false, now)
+	if c.Step != 0 || c.DueAt.Sub(now) != 24*time.Hour {
+		t.Errorf("forgotten card: step %d, due in %v", c.Step, c.DueAt.Sub(now))
+	}
+}
+
+func TestStore_Merge(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	generated := Generate([]session.Lesson{nilMapLesson("s1", "r1"), nilMapLesson("s2", "r2")}, nil, now)
+
+	added, err := store.Merge(generated)
+	if err != nil || len(added) != 1 {
+		t.Fatalf("Merge() = %d added, %v", len(added), err)
+	}
+	if _, err := store.Update(added[0].ID, func(c *Card) { c.Review(true, now) }); err != nil {
+		t.Fatal(err)
+	}
+
+	regenerated := Generate([]session.Lesson{nilMapLesson("s1", "r1"), nilMapLesson("s2", "r2"), nilMapLesson("s3", "r3")}, nil, now)
+	if added, _ := store.Merge(regenerated); len(added) != 0 {
+		t.Errorf("re-merge added %d cards, want 0", len(added))
+	}
+	cards, _ := store.List()
+	if len(cards) != 1 || cards[0].Step != 1 || cards[0].Seen != 3 {
+		t.Errorf("stored = %+v, want schedule kept and seen updated", cards[0])
+	}
+
+	if _, err := store.Update("missing", func(*Card) {}); err != ErrNotFound {
+		t.Errorf("Update(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestExport_TSV(t *testing.T) {
+	var buf bytes.Buffer
+	cards := []*Card{{Kind: KindMistake, Front: "What does this mean?\n\nx < y", Back: "Tab\there"}}
+	if err := Export(&buf, FormatTSV, cards); err != nil {
+		t.Fatal(err)
+	}
+	want := "#separator:tab\n#html:true\n#tags column:3\nWhat does this mean?<br><br>x &lt; y\tTab    here\ttemper temper::mistake\n"
+	if buf.String() != want {
+		t.Errorf("TSV =\n%q\nwant\n%q", buf.String(), want)
+	}
+	if err := Export(&buf, "csv", cards); err == nil {
+		t.Error("unknown format should fail")
+	}
+}
+
+func TestExport_APKG(t *testing.T) {
+	var buf bytes.Buffer
+	cards := []*Card{
+		{ID: "a1", Kind: KindMistake, Front: "Q1", Back: "A1"},
+		{ID: "b2", Kind: KindConcept, Front: "Q2", Back: "A2"},
+	}
+	if err := Export(&buf, FormatAPKG, cards); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("not a zip: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "collection.anki2")
+	for _, f := range zr.File {
+		if f.Name != "collection.anki2" {
+			continue
+		}
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var notes, cardRows int
+	var flds, guid string
+	if err := db.QueryRow("SELECT count(*) FROM notes").Scan(&notes); err != nil {
+		t.Fatalf("collection has no notes table: %v", err)
+	}
+	_ = db.QueryRow("SELECT count(*) FROM cards WHERE did = ?", ankiDeckID).Scan(&cardRows)
+	_ = db.QueryRow("SELECT flds, guid FROM notes ORDER BY id LIMIT 1").Scan(&flds, &guid)
+	if notes != 2 || cardRows != 2 || flds != "Q1\x1fA1" || guid != "a1" {
+		t.Errorf("notes = %d, cards = %d, first = %q/%q", notes, cardRows, flds, guid)
+	}
+}
+
+func TestStore_Forget(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Merge([]*Card{
+		{ID: "shared", Exercises: []string{"go-v1/basics/hello", "go-v1/maps/count"}},
+		{ID: "hello-only", Exercises: []string{"go-v1/basics/hello"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := store.Forget([]string{"go-v1/basics/hello"})
+	if err != nil || deleted != 1 {
+		t.Fatalf("Forget() = %d deleted, %v; want 1", deleted, err)
+	}
+	cards, _ := store.List()
+	if len(cards) != 1 || cards[0].ID != "shared" || len(cards[0].Exercises) != 1 {
+		t.Errorf("cards = %+v; want shared with one exercise left", cards)
+	}
+
+	if deleted, _ := store.Forget(nil); deleted != 1 {
+		t.Errorf("Forget(nil) deleted %d; want every card", deleted)
+	}
+}
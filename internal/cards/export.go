@@ -0,0 +1,222 @@
+package cards
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Export formats
+const (
+	FormatTSV  = "tsv"
+	FormatAPKG = "apkg"
+)
+
+// Fixed Anki IDs so importing a later export updates the same deck and
+// note type instead of adding new ones
+const (
+	ankiDeckID  = 1690000000001
+	ankiModelID = 1690000000002
+	ankiDeck    = "Temper"
+)
+
+// Export writes cards in format for Anki. Cards arrive as new cards;
+// Anki schedules them itself.
+func Export(w io.Writer, format string, cards []*Card) error {
+	switch format {
+	case FormatTSV:
+		return writeTSV(w, cards)
+	case FormatAPKG:
+		return writeAPKG(w, cards, time.Now())
+	default:
+		return fmt.Errorf("unknown export format %q (valid: tsv, apkg)", format)
+	}
+}
+
+// writeTSV writes Anki's text import format, with the header lines that
+// tell Anki the separator, that fields are HTML, and where the tags are
+func writeTSV(w io.Writer, cards []*Card) error {
+	if _, err := io.WriteString(w, "#separator:tab\n#html:true\n#tags column:3\n"); err != nil {
+		return err
+	}
+	for _, c := range cards {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", ankiField(c.Front), ankiField(c.Back), ankiTags(c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeAPKG writes an Anki package: a zip holding a collection database
+// with one Basic note per card, and an empty media map
+func writeAPKG(w io.Writer, cards []*Card, now time.Time) error {
+	dir, err := os.MkdirTemp("", "temper-anki-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "collection.anki2")
+	if err := writeCollection(path, cards, now); err != nil {
+		return fmt.Errorf("build collection: %w", err)
+	}
+	collection, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, entry := range []struct {
+		name string
+		data []byte
+	}{
+		{"collection.anki2", collection},
+		{"media", []byte("{}")},
+	} {
+		f, err := zw.Create(entry.name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(entry.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// ankiSchema is the collection schema (version 11) Anki imports
+const ankiSchema = `
+CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, scm integer not null, ver integer not null, dty integer not null, usn integer not null, ls integer not null, conf text not null, models text not null, decks text not null, dconf text not null, tags text not null);
+CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, mod integer not null, usn integer not null, tags text not null, flds text not null, sfld integer not null, csum integer not null, flags integer not null, data text not null);
+CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, ord integer not null, mod integer not null, usn integer not null, type integer not null, queue integer not null, due integer not null, ivl integer not null, factor integer not null, reps integer not null, lapses integer not null, left integer not null, odue integer not null, odid integer not null, flags integer not null, data text not null);
+CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, ease integer not null, ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null, type integer not null);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn on notes (usn);
+CREATE INDEX ix_cards_usn on cards (usn);
+CREATE INDEX ix_revlog_usn on revlog (usn);
+CREATE INDEX ix_cards_nid on cards (nid);
+CREATE INDEX ix_cards_sched on cards (did, queue, due);
+CREATE INDEX ix_revlog_cid on revlog (cid);
+CREATE INDEX ix_notes_csum on notes (csum);
+`
+
+func writeCollection(path string, cards []*Card, now time.Time) error {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if _, err := db.Exec(ankiSchema); err != nil {
+		return err
+	}
+
+	secs, ms := now.Unix(), now.UnixMilli()
+	conf, models, decks, dconf := ankiConfig(secs)
+	if _, err := db.Exec(`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		secs, ms, ms, conf, models, decks, dconf); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	for i, c := range cards {
+		front, back := ankiField(c.Front), ankiField(c.Back)
+		id := ms + int64(i) // note and card IDs are creation times in ms
+		if _, err := tx.Exec(`INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')`,
+			id, c.ID, ankiModelID, secs, " "+ankiTags(c)+" ", front+"\x1f"+back, stripHTML(front), checksum(stripHTML(front))); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+			id, id, ankiDeckID, secs, i+1); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ankiConfig returns the col row's conf, models, decks and dconf JSON
+func ankiConfig(secs int64) (conf, models, decks, dconf string) {
+	marshal := func(v any) string {
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+	deck := func(id int64, name string) map[string]any {
+		return map[string]any{
+			"id": id, "name": name, "desc": "", "conf": 1, "dyn": 0, "collapsed": false,
+			"extendNew": 10, "extendRev": 50, "mod": secs, "usn": -1,
+			"newToday": []int{0, 0}, "revToday": []int{0, 0}, "lrnToday": []int{0, 0}, "timeToday": []int{0, 0},
+		}
+	}
+	field := func(name string, ord int) map[string]any {
+		return map[string]any{"name": name, "ord": ord, "font": "Arial", "size": 20, "rtl": false, "sticky": false, "media": []string{}}
+	}
+
+	conf = marshal(map[string]any{
+		"activeDecks": []int64{1}, "curDeck": 1, "newSpread": 0, "collapseTime": 1200, "timeLim": 0,
+		"estTimes": true, "dueCounts": true, "curModel": nil, "nextPos": 1,
+		"sortType": "noteFld", "sortBackwards": false, "addToCur": true,
+	})
+	models = marshal(map[string]any{
+		fmt.Sprint(ankiModelID): map[string]any{
+			"id": ankiModelID, "name": "Temper Basic", "type": 0, "mod": secs, "usn": -1,
+			"sortf": 0, "did": ankiDeckID, "tags": []string{}, "vers": []int{},
+			"flds": []any{field("Front", 0), field("Back", 1)},
+			"tmpls": []any{map[string]any{
+				"name": "Card 1", "ord": 0, "did": nil, "bqfmt": "", "bafmt": "",
+				"qfmt": "{{Front}}", "afmt": "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}",
+			}},
+			"req":       []any{[]any{0, "all", []int{0}}},
+			"css":       ".card { font-family: arial; font-size: 20px; text-align: left; color: black; background-color: white; }",
+			"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+			"latexPost": "\\end{document}",
+		},
+	})
+	decks = marshal(map[string]any{"1": deck(1, "Default"), fmt.Sprint(ankiDeckID): deck(ankiDeckID, ankiDeck)})
+	dconf = marshal(map[string]any{"1": map[string]any{
+		"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true, "timer": 0, "replayq": true,
+		"new":   map[string]any{"bury": true, "delays": []int{1, 10}, "initialFactor": 2500, "ints": []int{1, 4, 7}, "order": 1, "perDay": 20, "separate": true},
+		"rev":   map[string]any{"bury": true, "ease4": 1.3, "fuzz": 0.05, "ivlFct": 1, "maxIvl": 36500, "minSpace": 1, "perDay": 100},
+		"lapse": map[string]any{"delays": []int{10}, "leechAction": 0, "leechFails": 8, "minInt": 1, "mult": 0},
+	}})
+	return conf, models, decks, dconf
+}
+
+// ankiField renders text as an Anki HTML field on one line
+func ankiField(text string) string {
+	text = html.EscapeString(strings.TrimSpace(text))
+	text = strings.ReplaceAll(text, "\t", "    ")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+func ankiTags(c *Card) string {
+	return "temper temper::" + string(c.Kind)
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+func stripHTML(s string) string {
+	return html.UnescapeString(htmlTag.ReplaceAllString(s, " "))
+}
+
+// checksum is Anki's duplicate check: the first 8 hex digits of the SHA-1
+// of the sort field
+func checksum(s string) int64 {
+	sum := sha1.Sum([]byte(s))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
@@ -0,0 +1,97 @@
+package cards
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/concept"
+	"github.com/felixgeelhaar/temper/internal/profile"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// MinRepeats is how many runs an error must show up in before it is worth
+// a card; a one-off typo isn't
+const MinRepeats = 2
+
+// location matches the "main.go:12:5: " prefix compilers put on errors
+var location = regexp.MustCompile(`^\S+\.\w+:\d+(:\d+)?:\s*`)
+
+// Generate makes cards from session lessons: one per error explained in
+// at least MinRepeats runs, and one per glossary concept an explanation
+// covered. Cards come out due at now, ordered by kind and key.
+func Generate(lessons []session.Lesson, concepts *concept.Index, now time.Time) []*Card {
+	byID := make(map[string]*Card)
+	runs := make(map[string]map[string]bool) // card ID → runs the error came up in
+
+	add := func(kind Kind, key, front, back, exerciseID string) *Card {
+		id := cardID(kind, key)
+		c := byID[id]
+		if c == nil {
+			c = &Card{ID: id, Kind: kind, Key: key, Front: front, Back: back, DueAt: now, CreatedAt: now}
+			byID[id] = c
+		}
+		if exerciseID != "" && !slices.Contains(c.Exercises, exerciseID) {
+			c.Exercises = append(c.Exercises, exerciseID)
+		}
+		return c
+	}
+
+	for _, l := range lessons {
+		switch {
+		case l.Error != nil && strings.TrimSpace(l.Error.Explanation) != "":
+			message := strings.TrimSpace(location.ReplaceAllString(l.Error.Original, ""))
+			if message == "" {
+				continue
+			}
+			c := add(KindMistake, mistakeKey(l.Error.Rule, message),
+				"What does this error mean, and what usually causes it?\n\n"+message,
+				l.Error.Explanation, l.ExerciseID)
+			if runs[c.ID] == nil {
+				runs[c.ID] = make(map[string]bool)
+			}
+			runs[c.ID][l.SessionID+"/"+l.RunID] = true
+			c.Seen = len(runs[c.ID])
+
+		case l.Explained != "":
+			for _, id := range concepts.Tag(l.Explained) {
+				con, ok := concepts.Get(id)
+				if !ok || con.Summary == "" {
+					continue
+				}
+				c := add(KindConcept, con.ID, "What is "+con.Name+"?", con.Summary, l.ExerciseID)
+				c.Seen++
+			}
+		}
+	}
+
+	cards := make([]*Card, 0, len(byID))
+	for _, c := range byID {
+		if c.Kind == KindMistake && c.Seen < MinRepeats {
+			continue
+		}
+		cards = append(cards, c)
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		if cards[i].Kind != cards[j].Kind {
+			return cards[i].Kind < cards[j].Kind
+		}
+		return cards[i].Key < cards[j].Key
+	})
+	return cards
+}
+
+// mistakeKey groups explanations of the same error: by the rule that
+// explained it, else by its normalized signature, else by its text
+func mistakeKey(rule, message string) string {
+	if rule != "" {
+		return rule
+	}
+	if sigs := profile.ExtractErrorPatterns(message, ""); len(sigs) > 0 {
+		sort.Strings(sigs)
+		return sigs[0]
+	}
+	return message
+}
@@ -0,0 +1,145 @@
+package cards
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/felixgeelhaar/temper/internal/storage/local"
+)
+
+const collectionCards = "cards"
+
+// ErrNotFound is returned for an unknown card ID
+var ErrNotFound = errors.New("card not found")
+
+// Store keeps cards as JSON files, one per card
+type Store struct {
+	mu    sync.Mutex
+	store *local.Store
+}
+
+// NewStore creates a store under basePath (usually ~/.temper)
+func NewStore(basePath string) (*Store, error) {
+	store, err := local.NewStore(basePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{store: store}, nil
+}
+
+// List returns every card, soonest due first
+func (s *Store) List() ([]*Card, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.store.List(collectionCards)
+	if err != nil {
+		return nil, err
+	}
+	cards := make([]*Card, 0, len(ids))
+	for _, id := range ids {
+		var c Card
+		if err := s.store.Load(collectionCards, id, &c); err != nil {
+			continue
+		}
+		cards = append(cards, &c)
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		if cards[i].DueAt.Equal(cards[j].DueAt) {
+			return cards[i].ID < cards[j].ID
+		}
+		return cards[i].DueAt.Before(cards[j].DueAt)
+	})
+	return cards, nil
+}
+
+// Merge adds generated cards the store doesn't have yet. Cards it has
+// keep their schedule; only how often they came up and where is updated.
+// It returns the cards that were new.
+func (s *Store) Merge(generated []*Card) ([]*Card, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	added := []*Card{}
+	for _, c := range generated {
+		var existing Card
+		err := s.store.Load(collectionCards, c.ID, &existing)
+		switch {
+		case err == nil:
+			existing.Seen, existing.Exercises = c.Seen, c.Exercises
+			c = &existing
+		case errors.Is(err, local.ErrNotFound):
+			added = append(added, c)
+		default:
+			return nil, err
+		}
+		if err := s.store.Save(collectionCards, c.ID, c); err != nil {
+			return nil, err
+		}
+	}
+	return added, nil
+}
+
+// Update applies fn to a stored card and saves it
+func (s *Store) Update(id string, fn func(c *Card)) (*Card, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var c Card
+	if err := s.store.Load(collectionCards, id, &c); err != nil {
+		if errors.Is(err, local.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	fn(&c)
+	if err := s.store.Save(collectionCards, c.ID, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Forget takes the given exercises off every card and deletes cards left
+// with none, or every card when exercises is nil. It returns how many
+// cards it deleted.
+func (s *Store) Forget(exercises []string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	forgotten := make(map[string]bool, len(exercises))
+	for _, id := range exercises {
+		forgotten[id] = true
+	}
+
+	ids, err := s.store.List(collectionCards)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, id := range ids {
+		var c Card
+		if err := s.store.Load(collectionCards, id, &c); err != nil {
+			continue
+		}
+		kept := c.Exercises[:0]
+		for _, ex := range c.Exercises {
+			if !forgotten[ex] {
+				kept = append(kept, ex)
+			}
+		}
+		switch {
+		case exercises == nil || (len(kept) == 0 && len(c.Exercises) > 0):
+			if err := s.store.Delete(collectionCards, id); err != nil && !errors.Is(err, local.ErrNotFound) {
+				return deleted, err
+			}
+			deleted++
+		case len(kept) < len(c.Exercises):
+			c.Exercises = kept
+			if err := s.store.Save(collectionCards, id, &c); err != nil {
+				return deleted, err
+			}
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,210 @@
+// Package chaos injects faults into the daemon's LLM providers, runner and
+// session store on demand, so editor plugins and end-to-end tests can
+// exercise failure paths deterministically. The daemon only turns it on
+// when built with -tags chaos.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+// ErrInjected marks every error a fault produces
+var ErrInjected = errors.New("injected fault")
+
+// Provider faults
+const (
+	ProviderError     = "error"     // the call fails
+	ProviderTimeout   = "timeout"   // the call fails as if its timeout ran out
+	ProviderMalformed = "malformed" // the call returns text no structured parser accepts
+)
+
+// MalformedContent is what a malformed provider fault returns
+const MalformedContent = "<<chaos: malformed output>>"
+
+// Faults is what to inject. The zero value injects nothing.
+type Faults struct {
+	// ProviderLatencyMs delays every LLM call before it reaches the
+	// provider; the caller's context still cancels the wait
+	ProviderLatencyMs int64  `json:"provider_latency_ms,omitempty"`
+	ProviderFault     string `json:"provider_fault,omitempty"` // error, timeout or malformed
+	RunnerError       bool   `json:"runner_error,omitempty"`   // format, build, test and debug runs fail
+	StoreError        bool   `json:"store_error,omitempty"`    // session store writes fail; reads work
+	// Remaining clears the faults after this many calls were hit by one;
+	// zero keeps them until cleared
+	Remaining int `json:"remaining,omitempty"`
+}
+
+// Validate rejects unknown provider faults and negative values
+func (f Faults) Validate() error {
+	switch f.ProviderFault {
+	case "", ProviderError, ProviderTimeout, ProviderMalformed:
+	default:
+		return fmt.Errorf("unknown provider_fault %q (want error, timeout or malformed)", f.ProviderFault)
+	}
+	if f.ProviderLatencyMs < 0 || f.Remaining < 0 {
+		return errors.New("provider_latency_ms and remaining must not be negative")
+	}
+	return nil
+}
+
+func (f Faults) active() bool {
+	return f.ProviderLatencyMs > 0 || f.ProviderFault != "" || f.RunnerError || f.StoreError
+}
+
+// State is the faults in effect and how often each point was hit
+type State struct {
+	Faults   Faults           `json:"faults"`
+	Injected map[string]int64 `json:"injected"` // by point: provider, runner, store
+}
+
+// Injector holds the faults in effect. A nil Injector injects nothing, so
+// its wrappers return what they are given.
+type Injector struct {
+	mu       sync.Mutex
+	faults   Faults
+	injected map[string]int64
+}
+
+// New returns an Injector with no faults set
+func New() *Injector {
+	return &Injector{injected: make(map[string]int64)}
+}
+
+// Set replaces the faults in effect
+func (i *Injector) Set(f Faults) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = f
+	return nil
+}
+
+// Clear removes every fault and resets the counts
+func (i *Injector) Clear() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = Faults{}
+	i.injected = make(map[string]int64)
+}
+
+// State reports the faults in effect
+func (i *Injector) State() State {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	injected := make(map[string]int64, len(i.injected))
+	for k, v := range i.injected {
+		injected[k] = v
+	}
+	return State{Faults: i.faults, Injected: injected}
+}
+
+// hit returns the faults that apply to one call at point, counting it
+// against Remaining when any does
+func (i *Injector) hit(point string, applies func(Faults) bool) (Faults, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	f := i.faults
+	if !f.active() || !applies(f) {
+		return Faults{}, false
+	}
+	i.injected[point]++
+	if f.Remaining > 0 {
+		i.faults.Remaining--
+		if i.faults.Remaining == 0 {
+			i.faults = Faults{}
+		}
+	}
+	return f, true
+}
+
+// delay waits out the injected latency unless ctx ends first
+func delay(ctx context.Context, ms int64) error {
+	if ms <= 0 {
+		return nil
+	}
+	t := time.NewTimer(time.Duration(ms) * time.Millisecond)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Provider wraps an LLM provider with the injector's provider faults
+func (i *Injector) Provider(p llm.Provider) llm.Provider {
+	if i == nil {
+		return p
+	}
+	return &provider{Provider: p, in: i}
+}
+
+type provider struct {
+	llm.Provider
+	in *Injector
+}
+
+// DefaultModel reports the wrapped provider's default model
+func (p *provider) DefaultModel() string {
+	return llm.DefaultModel(p.Provider)
+}
+
+// SupportsTools reports whether the wrapped provider takes tools
+func (p *provider) SupportsTools() bool {
+	return llm.SupportsTools(p.Provider)
+}
+
+// inject applies latency and reports the fault to return, if any
+func (p *provider) inject(ctx context.Context) (string, error) {
+	f, ok := p.in.hit("provider", func(f Faults) bool {
+		return f.ProviderLatencyMs > 0 || f.ProviderFault != ""
+	})
+	if !ok {
+		return "", nil
+	}
+	if err := delay(ctx, f.ProviderLatencyMs); err != nil {
+		return "", err
+	}
+	switch f.ProviderFault {
+	case ProviderError:
+		return "", fmt.Errorf("%w: provider %s unavailable", ErrInjected, p.Name())
+	case ProviderTimeout:
+		return "", fmt.Errorf("%w: %w", llm.ErrTimeout, ErrInjected)
+	}
+	return f.ProviderFault, nil
+}
+
+func (p *provider) Generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	fault, err := p.inject(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if fault == ProviderMalformed {
+		return &llm.Response{Content: MalformedContent, FinishReason: "stop"}, nil
+	}
+	return p.Provider.Generate(ctx, req)
+}
+
+func (p *provider) GenerateStream(ctx context.Context, req *llm.Request) (<-chan llm.StreamChunk, error) {
+	fault, err := p.inject(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if fault == ProviderMalformed {
+		ch := make(chan llm.StreamChunk, 2)
+		ch <- llm.StreamChunk{Content: MalformedContent}
+		ch <- llm.StreamChunk{Done: true}
+		close(ch)
+		return ch, nil
+	}
+	return p.Provider.GenerateStream(ctx, req)
+}
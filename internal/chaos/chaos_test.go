@@ -0,0 +1,139 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/felixgeelhaar/temper/internal/runner"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+type stubProvider struct{ calls int }
+
+func (p *stubProvider) Name() string            { return "stub" }
+func (p *stubProvider) SupportsStreaming() bool { return true }
+
+func (p *stubProvider) Generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	p.calls++
+	return &llm.Response{Content: "real"}, nil
+}
+
+func (p *stubProvider) GenerateStream(ctx context.Context, req *llm.Request) (<-chan llm.StreamChunk, error) {
+	p.calls++
+	ch := make(chan llm.StreamChunk, 1)
+	ch <- llm.StreamChunk{Done: true}
+	close(ch)
+	return ch, nil
+}
+
+type stubExecutor struct{ runner.Executor }
+
+func (stubExecutor) RunTests(ctx context.Context, code map[string]string, flags []string) (*runner.TestResult, error) {
+	return &runner.TestResult{OK: true}, nil
+}
+
+func TestNilInjectorWrapsNothing(t *testing.T) {
+	var in *Injector
+	p := &stubProvider{}
+	if in.Provider(p) != llm.Provider(p) {
+		t.Error("a nil injector should return the provider unchanged")
+	}
+	if e := (stubExecutor{}); in.Executor(e) != runner.Executor(e) {
+		t.Error("a nil injector should return the executor unchanged")
+	}
+}
+
+func TestProviderFaults(t *testing.T) {
+	in := New()
+	stub := &stubProvider{}
+	p := in.Provider(stub)
+	ctx := context.Background()
+
+	if resp, err := p.Generate(ctx, &llm.Request{}); err != nil || resp.Content != "real" {
+		t.Fatalf("no faults: %v %+v", err, resp)
+	}
+
+	for fault, check := range map[string]func(*llm.Response, error) bool{
+		ProviderError:     func(_ *llm.Response, err error) bool { return errors.Is(err, ErrInjected) },
+		ProviderTimeout:   func(_ *llm.Response, err error) bool { return errors.Is(err, llm.ErrTimeout) },
+		ProviderMalformed: func(r *llm.Response, err error) bool { return err == nil && r.Content == MalformedContent },
+	} {
+		if err := in.Set(Faults{ProviderFault: fault}); err != nil {
+			t.Fatal(err)
+		}
+		if resp, err := p.Generate(ctx, &llm.Request{}); !check(resp, err) {
+			t.Errorf("%s: unexpected %v %+v", fault, err, resp)
+		}
+	}
+	if stub.calls != 1 {
+		t.Errorf("faulty calls must not reach the provider, got %d calls", stub.calls)
+	}
+	if got := in.State().Injected["provider"]; got != 3 {
+		t.Errorf("injected provider faults = %d, want 3", got)
+	}
+}
+
+func TestProviderLatencyHonorsCancel(t *testing.T) {
+	in := New()
+	_ = in.Set(Faults{ProviderLatencyMs: int64(time.Minute / time.Millisecond)})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := in.Provider(&stubProvider{}).Generate(ctx, &llm.Request{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the caller's deadline, got %v", err)
+	}
+}
+
+func TestRemainingClearsFaults(t *testing.T) {
+	in := New()
+	_ = in.Set(Faults{RunnerError: true, Remaining: 2})
+	e := in.Executor(stubExecutor{})
+
+	for i := 0; i < 2; i++ {
+		if _, err := e.RunTests(context.Background(), nil, nil); !errors.Is(err, ErrInjected) {
+			t.Fatalf("run %d: expected injected failure, got %v", i, err)
+		}
+	}
+	if res, err := e.RunTests(context.Background(), nil, nil); err != nil || !res.OK {
+		t.Errorf("faults should have cleared: %v", err)
+	}
+	if state := in.State(); state.Faults != (Faults{}) || state.Injected["runner"] != 2 {
+		t.Errorf("unexpected state: %+v", state)
+	}
+}
+
+func TestStoreFaultFailsWritesOnly(t *testing.T) {
+	dir := t.TempDir()
+	inner, err := session.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := New()
+	s := in.Store(inner)
+
+	sess := &session.Session{ID: "s1", Status: session.StatusActive}
+	if err := s.Save(sess); err != nil {
+		t.Fatalf("save without faults: %v", err)
+	}
+	_ = in.Set(Faults{StoreError: true})
+	if err := s.Save(sess); !errors.Is(err, ErrInjected) {
+		t.Errorf("save: expected injected failure, got %v", err)
+	}
+	if _, err := s.Get("s1"); err != nil {
+		t.Errorf("reads should still work: %v", err)
+	}
+}
+
+func TestFaultsValidate(t *testing.T) {
+	in := New()
+	if err := in.Set(Faults{ProviderFault: "explode"}); err == nil {
+		t.Error("unknown provider fault should be rejected")
+	}
+	if err := in.Set(Faults{Remaining: -1}); err == nil {
+		t.Error("negative remaining should be rejected")
+	}
+}
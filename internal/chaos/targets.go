@@ -0,0 +1,199 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/runner"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// Executor wraps a runner executor with the injector's runner fault
+func (i *Injector) Executor(e runner.Executor) runner.Executor {
+	if i == nil {
+		return e
+	}
+	return &executor{Executor: e, in: i}
+}
+
+type executor struct {
+	runner.Executor
+	in *Injector
+}
+
+func (e *executor) fail() error {
+	if _, ok := e.in.hit("runner", func(f Faults) bool { return f.RunnerError }); ok {
+		return fmt.Errorf("%w: runner unavailable", ErrInjected)
+	}
+	return nil
+}
+
+func (e *executor) RunFormat(ctx context.Context, code map[string]string) (*runner.FormatResult, error) {
+	if err := e.fail(); err != nil {
+		return nil, err
+	}
+	return e.Executor.RunFormat(ctx, code)
+}
+
+func (e *executor) RunFormatFix(ctx context.Context, code map[string]string) (map[string]string, error) {
+	if err := e.fail(); err != nil {
+		return nil, err
+	}
+	return e.Executor.RunFormatFix(ctx, code)
+}
+
+func (e *executor) RunBuild(ctx context.Context, code map[string]string) (*runner.BuildResult, error) {
+	if err := e.fail(); err != nil {
+		return nil, err
+	}
+	return e.Executor.RunBuild(ctx, code)
+}
+
+func (e *executor) RunTests(ctx context.Context, code map[string]string, flags []string) (*runner.TestResult, error) {
+	if err := e.fail(); err != nil {
+		return nil, err
+	}
+	return e.Executor.RunTests(ctx, code, flags)
+}
+
+// RunTestsDebug keeps debugging available when the wrapped executor
+// supports it
+func (e *executor) RunTestsDebug(ctx context.Context, code map[string]string, flags []string) (*runner.DebugResult, error) {
+	dbg, ok := e.Executor.(runner.DebugRunner)
+	if !ok {
+		return nil, fmt.Errorf("executor does not support debugging")
+	}
+	if err := e.fail(); err != nil {
+		return nil, err
+	}
+	return dbg.RunTestsDebug(ctx, code, flags)
+}
+
+// RunProgram keeps stdin runs available when the wrapped executor
+// supports them
+func (e *executor) RunProgram(ctx context.Context, code map[string]string, stdin string) (*runner.ProgramResult, error) {
+	prog, ok := e.Executor.(runner.ProgramRunner)
+	if !ok {
+		return nil, fmt.Errorf("executor does not support program runs")
+	}
+	if err := e.fail(); err != nil {
+		return nil, err
+	}
+	return prog.RunProgram(ctx, code, stdin)
+}
+
+// RunServer keeps server checks available when the wrapped executor
+// supports them
+func (e *executor) RunServer(ctx context.Context, code map[string]string, check domain.ServerCheck) (*runner.ServerResult, error) {
+	srv, ok := e.Executor.(runner.ServerRunner)
+	if !ok {
+		return nil, fmt.Errorf("executor does not support server runs")
+	}
+	if err := e.fail(); err != nil {
+		return nil, err
+	}
+	return srv.RunServer(ctx, code, check)
+}
+
+// RunFuzz keeps fuzzing available when the wrapped executor supports it
+func (e *executor) RunFuzz(ctx context.Context, code map[string]string, check domain.FuzzCheck) (*runner.FuzzResult, error) {
+	fz, ok := e.Executor.(runner.FuzzRunner)
+	if !ok {
+		return nil, fmt.Errorf("executor does not support fuzzing")
+	}
+	if err := e.fail(); err != nil {
+		return nil, err
+	}
+	return fz.RunFuzz(ctx, code, check)
+}
+
+// RunArtifacts keeps artifact collection available when the wrapped
+// executor supports it
+func (e *executor) RunArtifacts(ctx context.Context, code map[string]string, specs []domain.ArtifactSpec) (*runner.ArtifactResult, error) {
+	ar, ok := e.Executor.(runner.ArtifactRunner)
+	if !ok {
+		return nil, fmt.Errorf("executor does not support artifacts")
+	}
+	if err := e.fail(); err != nil {
+		return nil, err
+	}
+	return ar.RunArtifacts(ctx, code, specs)
+}
+
+// RunCommand keeps custom commands available when the wrapped executor
+// supports them
+func (e *executor) RunCommand(ctx context.Context, code map[string]string, argv []string) (*runner.CommandResult, error) {
+	cr, ok := e.Executor.(runner.CommandRunner)
+	if !ok {
+		return nil, fmt.Errorf("executor does not support custom commands")
+	}
+	if err := e.fail(); err != nil {
+		return nil, err
+	}
+	return cr.RunCommand(ctx, code, argv)
+}
+
+// Store wraps a session store with the injector's store fault. Only
+// writes fail, so a test can still read what was saved before.
+func (i *Injector) Store(s session.SessionStore) session.SessionStore {
+	if i == nil {
+		return s
+	}
+	return &store{SessionStore: s, in: i}
+}
+
+type store struct {
+	session.SessionStore
+	in *Injector
+}
+
+func (s *store) fail(op string) error {
+	if _, ok := s.in.hit("store", func(f Faults) bool { return f.StoreError }); ok {
+		return fmt.Errorf("%w: store %s failed", ErrInjected, op)
+	}
+	return nil
+}
+
+func (s *store) Save(sess *session.Session) error {
+	if err := s.fail("save"); err != nil {
+		return err
+	}
+	return s.SessionStore.Save(sess)
+}
+
+func (s *store) Delete(id string) error {
+	if err := s.fail("delete"); err != nil {
+		return err
+	}
+	return s.SessionStore.Delete(id)
+}
+
+func (s *store) Tombstone(id string, at time.Time) error {
+	if err := s.fail("tombstone"); err != nil {
+		return err
+	}
+	return s.SessionStore.Tombstone(id, at)
+}
+
+func (s *store) SaveRun(run *session.Run) error {
+	if err := s.fail("save run"); err != nil {
+		return err
+	}
+	return s.SessionStore.SaveRun(run)
+}
+
+func (s *store) DeleteRun(sessionID, runID string) error {
+	if err := s.fail("delete run"); err != nil {
+		return err
+	}
+	return s.SessionStore.DeleteRun(sessionID, runID)
+}
+
+func (s *store) SaveIntervention(intervention *session.Intervention) error {
+	if err := s.fail("save intervention"); err != nil {
+		return err
+	}
+	return s.SessionStore.SaveIntervention(intervention)
+}
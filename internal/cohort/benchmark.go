@@ -0,0 +1,74 @@
+package cohort
+
+import "sort"
+
+// Benchmark places one learner against the whole cohort without naming
+// anyone. Every export counts, whether or not its member joined the
+// leaderboard.
+type Benchmark struct {
+	You    Stats `json:"you"`
+	Median Stats `json:"median"`
+	// Percentile is the share of the cohort (0-100) the learner is at or
+	// ahead of, per metric
+	Percentile map[string]float64 `json:"percentile"`
+}
+
+// Compare benchmarks attempts against members
+func Compare(attempts []Attempt, members []*Member) *Benchmark {
+	you := ComputeStats(attempts)
+	b := &Benchmark{You: you, Percentile: map[string]float64{}}
+	if len(members) == 0 {
+		return b
+	}
+
+	var completed, streaks []float64
+	var velocities []float64
+	for _, m := range members {
+		st := ComputeStats(m.Attempts)
+		completed = append(completed, float64(st.ExercisesCompleted))
+		streaks = append(streaks, float64(st.BestStreak))
+		if st.Velocity != nil {
+			velocities = append(velocities, *st.Velocity)
+		}
+	}
+
+	b.Median.ExercisesCompleted = int(median(completed))
+	b.Median.BestStreak = int(median(streaks))
+	b.Percentile[SortCompleted] = percentile(completed, float64(you.ExercisesCompleted))
+	b.Percentile[SortStreak] = percentile(streaks, float64(you.BestStreak))
+	if len(velocities) > 0 {
+		v := median(velocities)
+		b.Median.Velocity = &v
+		if you.Velocity != nil {
+			b.Percentile[SortVelocity] = percentile(velocities, *you.Velocity)
+		}
+	}
+	return b
+}
+
+// percentile returns the share of values at or below v
+func percentile(values []float64, v float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	n := 0
+	for _, x := range values {
+		if x <= v {
+			n++
+		}
+	}
+	return float64(n) / float64(len(values)) * 100
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
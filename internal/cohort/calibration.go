@@ -0,0 +1,173 @@
+package cohort
+
+import (
+	"sort"
+	"time"
+)
+
+// Difficulty labels, matching exercise packs
+const (
+	DifficultyBeginner     = "beginner"
+	DifficultyIntermediate = "intermediate"
+	DifficultyAdvanced     = "advanced"
+)
+
+var difficultyRank = map[string]int{
+	DifficultyBeginner:     0,
+	DifficultyIntermediate: 1,
+	DifficultyAdvanced:     2,
+}
+
+var difficultyByRank = []string{DifficultyBeginner, DifficultyIntermediate, DifficultyAdvanced}
+
+const (
+	// DefaultMinAttempts is how many finished attempts an exercise needs
+	// before a difficulty is suggested for it
+	DefaultMinAttempts = 5
+
+	// DefaultAbandonAfter is how long an unfinished attempt stays in
+	// progress before it counts as abandoned
+	DefaultAbandonAfter = 24 * time.Hour
+)
+
+// Thresholds separating the difficulty each outcome points to. An outcome
+// at or below the first bound reads as beginner, at or below the second as
+// intermediate, and above it as advanced.
+var (
+	timeToGreenBounds = [2]time.Duration{10 * time.Minute, 30 * time.Minute}
+	hintBounds        = [2]float64{0, 2}
+	abandonBounds     = [2]float64{0.15, 0.35}
+)
+
+// ExerciseCalibration is the observed outcome of one exercise against its
+// labelled difficulty
+type ExerciseCalibration struct {
+	ExerciseID          string  `json:"exercise_id"`
+	Label               string  `json:"label,omitempty"` // difficulty set by the pack
+	Attempts            int     `json:"attempts"`        // finished or abandoned
+	Completions         int     `json:"completions"`
+	MedianTimeToGreenMs int64   `json:"median_time_to_green_ms"`
+	MedianHints         float64 `json:"median_hints"`
+	AbandonmentRate     float64 `json:"abandonment_rate"`
+	// Suggested is left empty until the exercise has enough attempts
+	Suggested     string `json:"suggested,omitempty"`
+	Miscalibrated bool   `json:"miscalibrated"`
+}
+
+// CalibrationOptions tune a calibration report
+type CalibrationOptions struct {
+	MinAttempts  int           // defaults to DefaultMinAttempts
+	AbandonAfter time.Duration // defaults to DefaultAbandonAfter
+	Now          time.Time     // defaults to time.Now()
+}
+
+// Calibrate aggregates attempts per exercise and suggests a difficulty for
+// each from its median time to green, median hint count and abandonment
+// rate. labels maps exercise IDs to the difficulty their pack gives them;
+// exercises without a label are still reported. Attempts still in progress
+// are left out.
+func Calibrate(attempts []Attempt, labels map[string]string, opts CalibrationOptions) []ExerciseCalibration {
+	if opts.MinAttempts <= 0 {
+		opts.MinAttempts = DefaultMinAttempts
+	}
+	if opts.AbandonAfter <= 0 {
+		opts.AbandonAfter = DefaultAbandonAfter
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	type outcomes struct {
+		attempts, completions, abandoned int
+		times, hints                     []float64
+	}
+	byExercise := make(map[string]*outcomes)
+	for _, a := range attempts {
+		if a.ExerciseID == "" {
+			continue
+		}
+		inProgress := !a.Success && a.CompletedAt == nil && opts.Now.Sub(a.StartedAt) < opts.AbandonAfter
+		if inProgress {
+			continue
+		}
+		o := byExercise[a.ExerciseID]
+		if o == nil {
+			o = &outcomes{}
+			byExercise[a.ExerciseID] = o
+		}
+		o.attempts++
+		o.hints = append(o.hints, float64(a.HintCount))
+		if !a.Success {
+			o.abandoned++
+			continue
+		}
+		o.completions++
+		if d := timeToGreen(a); d > 0 {
+			o.times = append(o.times, float64(d.Milliseconds()))
+		}
+	}
+
+	report := make([]ExerciseCalibration, 0, len(byExercise))
+	for id, o := range byExercise {
+		c := ExerciseCalibration{
+			ExerciseID:          id,
+			Label:               labels[id],
+			Attempts:            o.attempts,
+			Completions:         o.completions,
+			MedianTimeToGreenMs: int64(median(o.times)),
+			MedianHints:         median(o.hints),
+			AbandonmentRate:     float64(o.abandoned) / float64(o.attempts),
+		}
+		if o.attempts >= opts.MinAttempts {
+			c.Suggested = suggestDifficulty(c, len(o.times) > 0)
+			_, labelled := difficultyRank[c.Label]
+			c.Miscalibrated = labelled && c.Suggested != c.Label
+		}
+		report = append(report, c)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].ExerciseID < report[j].ExerciseID
+	})
+	return report
+}
+
+// suggestDifficulty takes the middle of the levels the three outcomes point
+// to, so one outlier can't move an exercise on its own. Without a single
+// completion only hints and abandonment have a say, and both lean hard.
+func suggestDifficulty(c ExerciseCalibration, timed bool) string {
+	votes := []int{
+		level(c.MedianHints, hintBounds),
+		level(c.AbandonmentRate, abandonBounds),
+	}
+	if timed {
+		ms := [2]float64{float64(timeToGreenBounds[0].Milliseconds()), float64(timeToGreenBounds[1].Milliseconds())}
+		votes = append(votes, level(float64(c.MedianTimeToGreenMs), ms))
+	} else {
+		votes = append(votes, difficultyRank[DifficultyAdvanced])
+	}
+	sort.Ints(votes)
+	return difficultyByRank[votes[1]]
+}
+
+func level(v float64, bounds [2]float64) int {
+	switch {
+	case v <= bounds[0]:
+		return 0
+	case v <= bounds[1]:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// timeToGreen prefers the recorded duration and falls back to the
+// attempt's timestamps
+func timeToGreen(a Attempt) time.Duration {
+	if a.TimeToCompleteMs > 0 {
+		return time.Duration(a.TimeToCompleteMs) * time.Millisecond
+	}
+	if a.CompletedAt != nil {
+		return a.CompletedAt.Sub(a.StartedAt)
+	}
+	return 0
+}
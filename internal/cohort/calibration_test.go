@@ -0,0 +1,90 @@
+package cohort
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalibrate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	attempt := func(id string, minutes, hints int, success bool) Attempt {
+		start := now.Add(-48 * time.Hour)
+		done := start.Add(time.Duration(minutes) * time.Minute)
+		return Attempt{ExerciseID: id, StartedAt: start, CompletedAt: &done, HintCount: hints, Success: success}
+	}
+
+	var attempts []Attempt
+	// Labelled beginner, but slow, hint-heavy and often abandoned
+	for i := 0; i < 3; i++ {
+		attempts = append(attempts, attempt("go-v1/basics/hard", 45, 4, true))
+	}
+	attempts = append(attempts, attempt("go-v1/basics/hard", 20, 3, false), attempt("go-v1/basics/hard", 20, 5, false))
+	// Labelled advanced, but quick and hint-free
+	for i := 0; i < 5; i++ {
+		attempts = append(attempts, attempt("go-v1/basics/easy", 5, 0, true))
+	}
+	// Too few attempts to judge; one still in progress doesn't count
+	attempts = append(attempts,
+		attempt("go-v1/basics/new", 5, 0, true),
+		Attempt{ExerciseID: "go-v1/basics/new", StartedAt: now.Add(-time.Hour)},
+	)
+	// Abandoned without ever finishing
+	attempts = append(attempts, Attempt{ExerciseID: "go-v1/basics/new", StartedAt: now.Add(-72 * time.Hour)})
+
+	labels := map[string]string{
+		"go-v1/basics/hard": DifficultyBeginner,
+		"go-v1/basics/easy": DifficultyAdvanced,
+	}
+	report := Calibrate(attempts, labels, CalibrationOptions{Now: now})
+	if len(report) != 3 {
+		t.Fatalf("len(report) = %d; want 3", len(report))
+	}
+	byID := map[string]ExerciseCalibration{}
+	for _, c := range report {
+		byID[c.ExerciseID] = c
+	}
+
+	hard := byID["go-v1/basics/hard"]
+	if hard.Attempts != 5 || hard.Completions != 3 || hard.AbandonmentRate != 0.4 {
+		t.Errorf("hard outcomes = %+v", hard)
+	}
+	if hard.MedianTimeToGreenMs != (45*time.Minute).Milliseconds() || hard.MedianHints != 4 {
+		t.Errorf("hard medians = %d ms, %v hints", hard.MedianTimeToGreenMs, hard.MedianHints)
+	}
+	if hard.Suggested != DifficultyAdvanced || !hard.Miscalibrated {
+		t.Errorf("hard suggested %q (miscalibrated %v); want advanced", hard.Suggested, hard.Miscalibrated)
+	}
+
+	easy := byID["go-v1/basics/easy"]
+	if easy.Suggested != DifficultyBeginner || !easy.Miscalibrated {
+		t.Errorf("easy suggested %q (miscalibrated %v); want beginner", easy.Suggested, easy.Miscalibrated)
+	}
+
+	fresh := byID["go-v1/basics/new"]
+	if fresh.Attempts != 2 || fresh.AbandonmentRate != 0.5 {
+		t.Errorf("new outcomes = %+v; the in-progress attempt should be left out", fresh)
+	}
+	if fresh.Suggested != "" || fresh.Miscalibrated {
+		t.Errorf("new suggested %q below the minimum attempts", fresh.Suggested)
+	}
+}
+
+func TestSuggestDifficulty_MiddleVoteWins(t *testing.T) {
+	tests := []struct {
+		name string
+		c    ExerciseCalibration
+		want string
+	}{
+		{"one slow outlier", ExerciseCalibration{MedianTimeToGreenMs: time.Hour.Milliseconds()}, DifficultyBeginner},
+		{"mixed", ExerciseCalibration{MedianTimeToGreenMs: time.Hour.Milliseconds(), MedianHints: 1}, DifficultyIntermediate},
+		{"all hard", ExerciseCalibration{MedianTimeToGreenMs: time.Hour.Milliseconds(), MedianHints: 3, AbandonmentRate: 0.5}, DifficultyAdvanced},
+	}
+	for _, tt := range tests {
+		if got := suggestDifficulty(tt.c, true); got != tt.want {
+			t.Errorf("%s: suggestDifficulty() = %q; want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := suggestDifficulty(ExerciseCalibration{MedianHints: 1, AbandonmentRate: 1}, false); got != DifficultyAdvanced {
+		t.Errorf("never completed: suggestDifficulty() = %q; want advanced", got)
+	}
+}
@@ -0,0 +1,184 @@
+// Package cohort ranks the members of a learning cohort from the stats
+// exports they choose to share. Temper has no server, so a cohort is a
+// directory of `temper stats export` files: each member exports, passes the
+// file to whoever runs the leaderboard, and it is imported with Store.Add.
+package cohort
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound  = errors.New("cohort not found")
+	ErrInvalidID = errors.New("invalid cohort id")
+	ErrNoSummary = errors.New("export has no summary line")
+)
+
+var idPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,63}$`)
+
+// Attempt is one exercise attempt from a stats export
+type Attempt struct {
+	ExerciseID       string     `json:"exercise_id"`
+	StartedAt        time.Time  `json:"started_at"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	RunCount         int        `json:"run_count"`
+	HintCount        int        `json:"hint_count"`
+	TimeToCompleteMs int64      `json:"time_to_complete_ms,omitempty"`
+	Success          bool       `json:"success"`
+}
+
+// finishedAt orders attempts; unfinished attempts fall back to their start
+func (a Attempt) finishedAt() time.Time {
+	if a.CompletedAt != nil {
+		return *a.CompletedAt
+	}
+	return a.StartedAt
+}
+
+// Member is one learner's shared export
+type Member struct {
+	ProfileID   string // anonymized by the export
+	DisplayName string // optional name chosen for the leaderboard
+	Leaderboard bool   // opted in to being ranked by name
+	Attempts    []Attempt
+}
+
+// exportLine covers both line kinds of a stats export
+type exportLine struct {
+	Event       string `json:"event"`
+	ProfileID   string `json:"profile_id"`
+	DisplayName string `json:"display_name"`
+	Leaderboard bool   `json:"leaderboard"`
+	Attempt
+}
+
+// ReadExport parses a stats export (JSONL: one summary line, then one line
+// per attempt)
+func ReadExport(r io.Reader) (*Member, error) {
+	var m *Member
+	var attempts []Attempt
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var l exportLine
+		if err := json.Unmarshal(text, &l); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		switch l.Event {
+		case "summary":
+			m = &Member{
+				ProfileID:   l.ProfileID,
+				DisplayName: strings.TrimSpace(l.DisplayName),
+				Leaderboard: l.Leaderboard,
+			}
+		case "attempt":
+			attempts = append(attempts, l.Attempt)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if m == nil || m.ProfileID == "" {
+		return nil, ErrNoSummary
+	}
+	m.Attempts = attempts
+	return m, nil
+}
+
+// Store keeps each cohort's exports in a directory named after the cohort
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store rooted at dir (usually ~/.temper/cohorts)
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// List returns the IDs of every cohort with at least one member
+func (s *Store) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() && idPattern.MatchString(e.Name()) {
+			ids = append(ids, e.Name())
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Add imports a member's export into cohort id. A later export from the
+// same member replaces the earlier one.
+func (s *Store) Add(id string, export []byte) (*Member, error) {
+	if !idPattern.MatchString(id) {
+		return nil, ErrInvalidID
+	}
+	m, err := ReadExport(bytes.NewReader(export))
+	if err != nil {
+		return nil, err
+	}
+	if !idPattern.MatchString(m.ProfileID) {
+		return nil, fmt.Errorf("export has an invalid profile_id %q", m.ProfileID)
+	}
+
+	dir := filepath.Join(s.dir, id)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, m.ProfileID+".jsonl"), export, 0600); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Members loads every export in cohort id
+func (s *Store) Members(id string) ([]*Member, error) {
+	if !idPattern.MatchString(id) {
+		return nil, ErrInvalidID
+	}
+	paths, err := filepath.Glob(filepath.Join(s.dir, id, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, ErrNotFound
+	}
+
+	members := make([]*Member, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		m, err := ReadExport(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		members = append(members, m)
+	}
+	return members, nil
+}
@@ -0,0 +1,171 @@
+package cohort
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// export builds a stats export with one attempt per result: "g" is green
+// without hints, "h" is green with a hint, "f" failed. Each green attempt
+// takes runs[i] runs when runs is given.
+func export(profileID, name string, optIn bool, results string, runs ...int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `{"event":"summary","profile_id":%q,"display_name":%q,"leaderboard":%t}`+"\n", profileID, name, optIn)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, r := range results {
+		done := start.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+		hints, success := 0, r != 'f'
+		if r == 'h' {
+			hints = 1
+		}
+		n := 1
+		if i < len(runs) {
+			n = runs[i]
+		}
+		fmt.Fprintf(&b, `{"event":"attempt","exercise_id":"go-v1/basics/ex%d","started_at":%q,"completed_at":%q,"run_count":%d,"hint_count":%d,"success":%t}`+"\n",
+			i, done, done, n, hints, success)
+	}
+	return b.String()
+}
+
+func TestReadExport(t *testing.T) {
+	m, err := ReadExport(strings.NewReader(export("abc123", "ada", true, "ggh")))
+	if err != nil {
+		t.Fatalf("ReadExport: %v", err)
+	}
+	if m.ProfileID != "abc123" || m.DisplayName != "ada" || !m.Leaderboard || len(m.Attempts) != 3 {
+		t.Errorf("unexpected member: %+v", m)
+	}
+
+	if _, err := ReadExport(strings.NewReader(`{"event":"attempt"}`)); !errors.Is(err, ErrNoSummary) {
+		t.Errorf("expected ErrNoSummary, got %v", err)
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	st := ComputeStats(mustRead(t, export("a", "", true, "gggfgghg", 8, 6, 6, 1, 2, 2, 2, 2)).Attempts)
+
+	if st.ExercisesCompleted != 7 {
+		t.Errorf("completed = %d, want 7", st.ExercisesCompleted)
+	}
+	if st.BestStreak != 3 || st.CurrentStreak != 1 {
+		t.Errorf("streaks = %d best / %d current, want 3 / 1", st.BestStreak, st.CurrentStreak)
+	}
+	// Runs per completion: first three 8,6,6 (avg 6.67), last three 2,2,2 (avg 2)
+	if st.Velocity == nil || *st.Velocity < 69 || *st.Velocity > 71 {
+		t.Errorf("velocity = %v, want ~70", st.Velocity)
+	}
+
+	if st := ComputeStats(mustRead(t, export("b", "", true, "gg")).Attempts); st.Velocity != nil {
+		t.Errorf("velocity should wait for more completions, got %v", *st.Velocity)
+	}
+}
+
+func TestRank(t *testing.T) {
+	members := []*Member{
+		mustRead(t, export("p1", "ada", true, "gggg")),
+		mustRead(t, export("p2", "", true, "gghg")),
+		mustRead(t, export("p3", "grace", false, "gggggg")),
+		mustRead(t, export("p4", "linus", true, "ggg")),
+	}
+
+	board, err := Rank(members, Options{})
+	if err != nil {
+		t.Fatalf("Rank: %v", err)
+	}
+	if board.Members != 4 || len(board.Entries) != 3 {
+		t.Fatalf("members %d, entries %d; the member who did not opt in should be left out", board.Members, len(board.Entries))
+	}
+	names := []string{board.Entries[0].Name, board.Entries[1].Name, board.Entries[2].Name}
+	if names[0] != "ada" || names[1] != "anon-p2" || names[2] != "linus" {
+		t.Errorf("order = %v", names)
+	}
+
+	board, _ = Rank(members, Options{Anonymize: true})
+	for _, e := range board.Entries {
+		if !strings.HasPrefix(e.Name, "anon-") {
+			t.Errorf("anonymized board shows %q", e.Name)
+		}
+	}
+
+	if _, err := Rank(members, Options{Sort: "height"}); !errors.Is(err, ErrUnknownSort) {
+		t.Errorf("expected ErrUnknownSort, got %v", err)
+	}
+}
+
+func TestRank_TiesShareRank(t *testing.T) {
+	members := []*Member{
+		mustRead(t, export("p1", "a", true, "gg")),
+		mustRead(t, export("p2", "b", true, "gg")),
+		mustRead(t, export("p3", "c", true, "g")),
+	}
+	board, _ := Rank(members, Options{})
+	if board.Entries[0].Rank != 1 || board.Entries[1].Rank != 1 || board.Entries[2].Rank != 3 {
+		t.Errorf("ranks = %d %d %d, want 1 1 3", board.Entries[0].Rank, board.Entries[1].Rank, board.Entries[2].Rank)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	members := []*Member{
+		mustRead(t, export("p1", "", false, "g")),
+		mustRead(t, export("p2", "", true, "ggg")),
+		mustRead(t, export("p3", "", true, "ggggg")),
+	}
+	you := mustRead(t, export("me", "", false, "ggg")).Attempts
+
+	b := Compare(you, members)
+	if b.You.ExercisesCompleted != 3 || b.Median.ExercisesCompleted != 3 {
+		t.Errorf("you %d, median %d", b.You.ExercisesCompleted, b.Median.ExercisesCompleted)
+	}
+	if p := b.Percentile[SortCompleted]; p < 66 || p > 67 {
+		t.Errorf("percentile = %v, want ~66.7", p)
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	if ids, err := store.List(); err != nil || len(ids) != 0 {
+		t.Fatalf("List on empty store = %v, %v", ids, err)
+	}
+	if _, err := store.Members("go-101"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if _, err := store.Add("go-101", []byte(export("p1", "ada", true, "g"))); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	// A newer export from the same member replaces the old one
+	if _, err := store.Add("go-101", []byte(export("p1", "ada", true, "gg"))); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	members, err := store.Members("go-101")
+	if err != nil || len(members) != 1 || len(members[0].Attempts) != 2 {
+		t.Fatalf("Members = %+v, %v", members, err)
+	}
+	if ids, _ := store.List(); len(ids) != 1 || ids[0] != "go-101" {
+		t.Errorf("List = %v", ids)
+	}
+
+	for _, bad := range []string{"../etc", "", "a/b"} {
+		if _, err := store.Add(bad, []byte(export("p1", "", true, "g"))); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("Add(%q) = %v, want ErrInvalidID", bad, err)
+		}
+	}
+	if _, err := store.Add("go-101", []byte(export("../x", "", true, "g"))); err == nil {
+		t.Error("expected an export with a path in profile_id to be rejected")
+	}
+}
+
+func mustRead(t *testing.T, data string) *Member {
+	t.Helper()
+	m, err := ReadExport(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadExport: %v", err)
+	}
+	return m
+}
@@ -0,0 +1,197 @@
+package cohort
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Leaderboard sort keys
+const (
+	SortCompleted = "completed"
+	SortStreak    = "streak"
+	SortVelocity  = "velocity"
+)
+
+// ErrUnknownSort is returned for a sort key other than the ones above
+var ErrUnknownSort = errors.New("unknown leaderboard sort")
+
+// minVelocityCompletions is how many completed exercises a member needs
+// before velocity is measured; fewer halves are too noisy to compare
+const minVelocityCompletions = 4
+
+// Stats are the numbers a member is ranked on
+type Stats struct {
+	ExercisesCompleted int `json:"exercises_completed"`
+	// CurrentStreak and BestStreak count consecutive exercises finished
+	// green without a hint. A hinted or failed attempt ends a streak.
+	CurrentStreak int `json:"current_streak"`
+	BestStreak    int `json:"best_streak"`
+	// Velocity is how much faster the member reaches green now than when
+	// they started: the percentage drop in runs per completed exercise from
+	// the first half of their completions to the second. Nil until they
+	// have completed four exercises.
+	Velocity *float64 `json:"velocity,omitempty"`
+}
+
+// Entry is one ranked row of a leaderboard
+type Entry struct {
+	Rank int    `json:"rank"`
+	Name string `json:"name"`
+	Stats
+}
+
+// Options control how a leaderboard is built
+type Options struct {
+	// Anonymize replaces every display name with the member's anonymized
+	// profile ID, for showing the board to the whole group
+	Anonymize bool
+	// Sort is completed (default), streak or velocity
+	Sort string
+}
+
+// Board is a cohort leaderboard
+type Board struct {
+	Entries []Entry `json:"entries"`
+	// Members counts every export in the cohort, including members who
+	// shared stats for benchmarking without joining the leaderboard
+	Members int `json:"members"`
+}
+
+// ComputeStats derives a member's leaderboard numbers from their attempts
+func ComputeStats(attempts []Attempt) Stats {
+	finished := make([]Attempt, 0, len(attempts))
+	for _, a := range attempts {
+		if a.Success || a.CompletedAt != nil {
+			finished = append(finished, a)
+		}
+	}
+	sort.SliceStable(finished, func(i, j int) bool {
+		return finished[i].finishedAt().Before(finished[j].finishedAt())
+	})
+
+	var st Stats
+	var runs []int
+	streak := 0
+	for _, a := range finished {
+		if !a.Success {
+			streak = 0
+			continue
+		}
+		st.ExercisesCompleted++
+		runs = append(runs, a.RunCount)
+		if a.HintCount == 0 {
+			streak++
+			st.BestStreak = max(st.BestStreak, streak)
+		} else {
+			streak = 0
+		}
+	}
+	st.CurrentStreak = streak
+
+	if len(runs) >= minVelocityCompletions {
+		half := len(runs) / 2
+		before, after := mean(runs[:half]), mean(runs[len(runs)-half:])
+		if before > 0 {
+			v := (before - after) / before * 100
+			st.Velocity = &v
+		}
+	}
+	return st
+}
+
+// Rank builds the leaderboard from members who opted in
+func Rank(members []*Member, opts Options) (*Board, error) {
+	less, err := sortFunc(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &Board{Entries: []Entry{}, Members: len(members)}
+	for _, m := range members {
+		if !m.Leaderboard {
+			continue
+		}
+		name := m.DisplayName
+		if name == "" || opts.Anonymize {
+			name = "anon-" + m.ProfileID
+		}
+		board.Entries = append(board.Entries, Entry{Name: name, Stats: ComputeStats(m.Attempts)})
+	}
+
+	sort.SliceStable(board.Entries, func(i, j int) bool {
+		a, b := board.Entries[i], board.Entries[j]
+		if less(a.Stats, b.Stats) {
+			return true
+		}
+		if less(b.Stats, a.Stats) {
+			return false
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+
+	// Ties share a rank
+	for i := range board.Entries {
+		if i > 0 && !less(board.Entries[i-1].Stats, board.Entries[i].Stats) {
+			board.Entries[i].Rank = board.Entries[i-1].Rank
+			continue
+		}
+		board.Entries[i].Rank = i + 1
+	}
+	return board, nil
+}
+
+// sortFunc returns a "ranks higher than" comparison for a sort key. Each
+// key falls back to the others to break ties.
+func sortFunc(key string) (func(a, b Stats) bool, error) {
+	completed := func(a, b Stats) int { return a.ExercisesCompleted - b.ExercisesCompleted }
+	streak := func(a, b Stats) int { return a.BestStreak - b.BestStreak }
+	velocity := func(a, b Stats) int {
+		switch {
+		case velocityOf(a) > velocityOf(b):
+			return 1
+		case velocityOf(a) < velocityOf(b):
+			return -1
+		}
+		return 0
+	}
+
+	var order []func(a, b Stats) int
+	switch key {
+	case "", SortCompleted:
+		order = []func(a, b Stats) int{completed, streak, velocity}
+	case SortStreak:
+		order = []func(a, b Stats) int{streak, completed, velocity}
+	case SortVelocity:
+		order = []func(a, b Stats) int{velocity, completed, streak}
+	default:
+		return nil, ErrUnknownSort
+	}
+	return func(a, b Stats) bool {
+		for _, cmp := range order {
+			if c := cmp(a, b); c != 0 {
+				return c > 0
+			}
+		}
+		return false
+	}, nil
+}
+
+// velocityOf ranks members without a velocity below everyone with one
+func velocityOf(s Stats) float64 {
+	if s.Velocity == nil {
+		return -1e9
+	}
+	return *s.Velocity
+}
+
+func mean(xs []int) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	total := 0
+	for _, x := range xs {
+		total += x
+	}
+	return float64(total) / float64(len(xs))
+}
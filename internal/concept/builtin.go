@@ -0,0 +1,275 @@
+package concept
+
+// builtin is the glossary shipped with Temper. Summaries explain the idea
+// in a few sentences; they are reference material, not exercise answers.
+var builtin = []Concept{
+	{
+		ID:       "goroutines",
+		Name:     "Goroutines",
+		Language: "go",
+		Summary: "A goroutine is a function running concurrently with the rest of the program, started with the go keyword. " +
+			"Goroutines are cheap, but the program doesn't wait for them: main returning ends every goroutine, so work has to be waited for explicitly.",
+		Keywords: []string{"goroutine", "goroutines", "go statement", "go func"},
+		Tags:     []string{"goroutines", "concurrency"},
+		Related:  []string{"channels", "waitgroup", "mutex"},
+	},
+	{
+		ID:       "channels",
+		Name:     "Channels",
+		Language: "go",
+		Summary: "A channel passes values between goroutines and synchronizes them: an unbuffered send waits for a receiver. " +
+			"Closing a channel tells receivers no more values are coming; only the sender should close it.",
+		Keywords: []string{"channel", "channels", "chan", "unbuffered", "buffered channel"},
+		Tags:     []string{"channels", "concurrency"},
+		Related:  []string{"goroutines", "select", "deadlock"},
+	},
+	{
+		ID:       "select",
+		Name:     "Select",
+		Language: "go",
+		Summary: "select waits on several channel operations at once and runs whichever is ready first. " +
+			"A default case makes it non-blocking; a case on a timer or context adds a timeout.",
+		Keywords: []string{"select statement", "select block", "select case"},
+		Tags:     []string{"channels"},
+		Related:  []string{"channels", "context"},
+	},
+	{
+		ID:       "deadlock",
+		Name:     "Deadlocks",
+		Language: "go",
+		Summary: "A deadlock happens when every goroutine is blocked waiting on another, for example a send no one receives. " +
+			"Go detects the case where all goroutines are stuck and crashes with \"all goroutines are asleep\".",
+		Keywords: []string{"deadlock", "deadlocks", "all goroutines are asleep"},
+		Tags:     []string{"concurrency"},
+		Related:  []string{"channels", "mutex"},
+	},
+	{
+		ID:       "mutex",
+		Name:     "Mutexes and data races",
+		Language: "go",
+		Summary: "A data race is two goroutines touching the same memory at once with at least one writing. " +
+			"A sync.Mutex lets one goroutine at a time into a critical section; go test -race finds races the tests happen to hit.",
+		Keywords: []string{"mutex", "sync.Mutex", "RWMutex", "data race", "race condition"},
+		Tags:     []string{"concurrency"},
+		Related:  []string{"goroutines", "waitgroup"},
+	},
+	{
+		ID:       "waitgroup",
+		Name:     "WaitGroups",
+		Language: "go",
+		Summary:  "A sync.WaitGroup waits for a group of goroutines to finish: Add before starting each one, Done when it ends, Wait to block until all are done.",
+		Keywords: []string{"WaitGroup", "sync.WaitGroup", "wg.Wait", "wg.Done"},
+		Tags:     []string{"goroutines", "concurrency"},
+		Related:  []string{"goroutines"},
+	},
+	{
+		ID:       "context",
+		Name:     "Context",
+		Language: "go",
+		Summary: "A context.Context carries a deadline and a cancellation signal across API boundaries. " +
+			"Functions that may block take it as their first parameter and stop when ctx.Done() is closed.",
+		Keywords: []string{"context.Context", "ctx.Done", "context cancellation", "cancellation", "deadline"},
+		Tags:     []string{"context"},
+		Related:  []string{"goroutines", "select"},
+	},
+	{
+		ID:       "interfaces",
+		Name:     "Interfaces",
+		Language: "go",
+		Summary: "An interface is a set of method signatures. Any type with those methods satisfies it implicitly, without declaring so. " +
+			"Small interfaces, defined where they are used, keep code easy to test and change.",
+		Keywords: []string{"interface", "interfaces", "implements", "satisfies", "type assertion"},
+		Tags:     []string{"interfaces", "polymorphism"},
+		Related:  []string{"methods", "nil"},
+	},
+	{
+		ID:       "slices",
+		Name:     "Slices",
+		Language: "go",
+		Summary: "A slice is a view of part of an underlying array: a pointer, a length and a capacity. " +
+			"append may reuse the array or allocate a new one, so always use its result, and remember two slices can share elements.",
+		Keywords: []string{"slice", "slices", "append", "capacity"},
+		Tags:     []string{"slices", "collections"},
+		Related:  []string{"arrays", "range"},
+	},
+	{
+		ID:       "arrays",
+		Name:     "Arrays vs slices",
+		Language: "go",
+		Summary: "An array has a fixed length that is part of its type: [3]int and [4]int are different types, and arrays are copied when assigned or passed. " +
+			"Most Go code uses slices, which are flexible views onto arrays.",
+		Keywords: []string{"array", "arrays", "fixed-size", "fixed length"},
+		Tags:     []string{"slices", "collections"},
+		Related:  []string{"slices"},
+	},
+	{
+		ID:       "maps",
+		Name:     "Maps",
+		Language: "go",
+		Summary: "A map associates keys with values. Reading a missing key gives the zero value; the two-value form v, ok := m[k] tells you whether it was there. " +
+			"A nil map can be read but not written, and iteration order is random.",
+		Keywords: []string{"map", "maps", "map key", "hash map"},
+		Tags:     []string{"maps", "collections"},
+		Related:  []string{"zero-values", "range", "nil"},
+	},
+	{
+		ID:       "range",
+		Name:     "Range loops",
+		Language: "go",
+		Summary: "for range iterates over slices, arrays, strings, maps and channels, giving an index (or key) and a copy of each value. " +
+			"Over a string it yields runes, not bytes.",
+		Keywords: []string{"range loop", "for range", "range over", "for i, v := range"},
+		Tags:     []string{"basics", "collections"},
+		Related:  []string{"slices", "maps", "strings"},
+	},
+	{
+		ID:       "pointers",
+		Name:     "Pointers",
+		Language: "go",
+		Summary: "A pointer holds the address of a value. Passing a pointer lets a function change the caller's value; passing a value gives it a copy. " +
+			"Dereferencing a nil pointer crashes the program.",
+		Keywords: []string{"pointer", "pointers", "dereference", "address of"},
+		Tags:     []string{"pointers", "memory"},
+		Related:  []string{"methods", "structs", "nil"},
+	},
+	{
+		ID:       "structs",
+		Name:     "Structs",
+		Language: "go",
+		Summary: "A struct groups named fields into one type. Fields starting with a capital letter are visible outside the package; " +
+			"a struct literal can name its fields so the order doesn't matter.",
+		Keywords: []string{"struct", "structs", "struct field", "embedding"},
+		Tags:     []string{"structs"},
+		Related:  []string{"methods", "pointers"},
+	},
+	{
+		ID:       "methods",
+		Name:     "Methods and receivers",
+		Language: "go",
+		Summary: "A method is a function with a receiver. A value receiver works on a copy; a pointer receiver can modify the original. " +
+			"The receiver kind also decides which types satisfy an interface.",
+		Keywords: []string{"method", "methods", "receiver", "receivers", "pointer receiver", "value receiver"},
+		Tags:     []string{"methods", "receivers"},
+		Related:  []string{"structs", "pointers", "interfaces"},
+	},
+	{
+		ID:       "errors",
+		Name:     "Error handling",
+		Language: "go",
+		Summary: "Go functions report failure by returning an error as their last result, and callers check it right away. " +
+			"Wrap errors with fmt.Errorf and %w to add context, and inspect them with errors.Is and errors.As.",
+		Keywords: []string{"error handling", "err != nil", "errors.Is", "errors.As", "fmt.Errorf", "wrap the error", "sentinel error"},
+		Tags:     []string{"errors"},
+		Related:  []string{"nil", "defer"},
+	},
+	{
+		ID:       "defer",
+		Name:     "Defer",
+		Language: "go",
+		Summary: "defer schedules a call to run when the surrounding function returns, in last-in first-out order. " +
+			"It is the usual way to release resources; the deferred call's arguments are evaluated when the defer statement runs.",
+		Keywords: []string{"defer", "deferred"},
+		Tags:     []string{"errors"},
+		Related:  []string{"panic-recover", "errors"},
+	},
+	{
+		ID:       "panic-recover",
+		Name:     "Panic and recover",
+		Language: "go",
+		Summary: "A panic stops normal execution and unwinds the stack, running deferred calls. recover, called in a deferred function, stops the unwinding. " +
+			"Panics are for bugs and impossible states; expected failures return errors.",
+		Keywords: []string{"panic", "panics", "recover"},
+		Tags:     []string{"errors", "debugging"},
+		Related:  []string{"defer", "errors"},
+	},
+	{
+		ID:       "nil",
+		Name:     "Nil",
+		Language: "go",
+		Summary: "nil is the zero value of pointers, slices, maps, channels, functions and interfaces. Each behaves differently when nil: " +
+			"a nil slice works with append, a nil map panics on write, and an interface holding a nil pointer is not itself nil.",
+		Keywords: []string{"nil", "nil pointer", "nil map", "nil interface"},
+		Tags:     []string{"pointers"},
+		Related:  []string{"zero-values", "pointers", "interfaces"},
+	},
+	{
+		ID:       "zero-values",
+		Name:     "Zero values",
+		Language: "go",
+		Summary: "Every Go variable starts with its type's zero value: 0, \"\", false, nil, or a struct of zero fields. " +
+			"Well-designed types are useful at their zero value without a constructor.",
+		Keywords: []string{"zero value", "zero values", "zero-value"},
+		Tags:     []string{"variables", "types"},
+		Related:  []string{"nil", "structs"},
+	},
+	{
+		ID:       "closures",
+		Name:     "Closures",
+		Language: "go",
+		Summary: "A function literal can use variables from the scope around it; it captures the variables themselves, not copies. " +
+			"Closures make handy callbacks and generators.",
+		Keywords: []string{"closure", "closures", "anonymous function", "function literal"},
+		Tags:     []string{"functions"},
+		Related:  []string{"goroutines"},
+	},
+	{
+		ID:       "variadic",
+		Name:     "Variadic functions",
+		Language: "go",
+		Summary: "A variadic function takes any number of trailing arguments of one type, which arrive as a slice. " +
+			"To pass an existing slice, follow it with ... at the call.",
+		Keywords: []string{"variadic"},
+		Tags:     []string{"variadic"},
+		Related:  []string{"slices"},
+	},
+	{
+		ID:       "strings",
+		Name:     "Strings, bytes and runes",
+		Language: "go",
+		Summary: "A Go string is an immutable sequence of bytes, usually UTF-8. Indexing gives bytes; ranging gives runes (Unicode code points). " +
+			"Build strings in a loop with strings.Builder.",
+		Keywords: []string{"rune", "runes", "utf-8", "strings.Builder", "byte slice"},
+		Tags:     []string{"strings"},
+		Related:  []string{"slices", "range"},
+	},
+	{
+		ID:       "generics",
+		Name:     "Generics",
+		Language: "go",
+		Summary: "Type parameters let a function or type work with many types, constrained by an interface such as comparable or cmp.Ordered. " +
+			"Reach for them when the same logic would otherwise be copied per type.",
+		Keywords: []string{"generic", "generics", "type parameter", "type parameters", "constraint"},
+		Tags:     []string{"generics"},
+		Related:  []string{"interfaces"},
+	},
+	{
+		ID:       "table-tests",
+		Name:     "Table-driven tests",
+		Language: "go",
+		Summary: "A table-driven test lists cases as a slice of structs and runs the same checks over each, often with t.Run for a named subtest per case. " +
+			"Adding a case is one line.",
+		Keywords: []string{"table-driven", "table driven", "test table", "test cases", "t.Run", "subtest"},
+		Tags:     []string{"testing", "table-tests", "tdd"},
+		Related:  []string{"benchmarks"},
+	},
+	{
+		ID:       "benchmarks",
+		Name:     "Benchmarks",
+		Language: "go",
+		Summary: "A benchmark is a function BenchmarkX(b *testing.B) that runs the code under test b.N times; go test -bench reports time per operation. " +
+			"Measure before optimizing.",
+		Keywords: []string{"benchmark", "benchmarks", "testing.B", "b.N"},
+		Tags:     []string{"benchmarks", "performance"},
+		Related:  []string{"table-tests"},
+	},
+	{
+		ID:       "http-handlers",
+		Name:     "HTTP handlers",
+		Language: "go",
+		Summary: "An http.Handler responds to a request through its ServeHTTP(w, r) method; http.HandlerFunc turns a plain function into one. " +
+			"Write the status with w.WriteHeader before the body.",
+		Keywords: []string{"http.Handler", "HandlerFunc", "ServeHTTP", "http handler", "ResponseWriter"},
+		Tags:     []string{"http", "handlers"},
+		Related:  []string{"interfaces", "context"},
+	},
+}
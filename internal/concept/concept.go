@@ -0,0 +1,135 @@
+// Package concept indexes the programming concepts hints talk about, so
+// interventions can link to short explanations and to exercises that
+// practice them.
+package concept
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// maxTags caps the concepts tagged on one intervention; a hint that
+// touches more than a handful is better served by its top few
+const maxTags = 5
+
+// Concept is one entry in the glossary
+type Concept struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Summary  string   `json:"summary"`
+	Language string   `json:"language,omitempty"` // empty = language-neutral
+	Related  []string `json:"related,omitempty"`  // other concept IDs
+
+	// Keywords are the phrases that mark a text as being about the
+	// concept, matched case-insensitively on word boundaries
+	Keywords []string `json:"-"`
+
+	// Tags are the exercise tags that practice the concept; "slices"
+	// also matches a tag like "basics/slices"
+	Tags []string `json:"-"`
+}
+
+// Index looks up concepts and finds them in text
+type Index struct {
+	concepts []Concept
+	byID     map[string]int
+	patterns []*regexp.Regexp // parallel to concepts
+}
+
+// NewIndex indexes the given concepts, in the order given
+func NewIndex(concepts []Concept) *Index {
+	idx := &Index{
+		concepts: concepts,
+		byID:     make(map[string]int, len(concepts)),
+		patterns: make([]*regexp.Regexp, len(concepts)),
+	}
+	for i, c := range concepts {
+		idx.byID[c.ID] = i
+		if len(c.Keywords) == 0 {
+			continue
+		}
+		quoted := make([]string, len(c.Keywords))
+		for j, kw := range c.Keywords {
+			quoted[j] = regexp.QuoteMeta(kw)
+		}
+		idx.patterns[i] = regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+	}
+	return idx
+}
+
+// Default returns the index of built-in concepts
+func Default() *Index {
+	return NewIndex(builtin)
+}
+
+// Get returns the concept with the given ID
+func (idx *Index) Get(id string) (Concept, bool) {
+	i, ok := idx.byID[id]
+	if !ok {
+		return Concept{}, false
+	}
+	return idx.concepts[i], true
+}
+
+// List returns every concept
+func (idx *Index) List() []Concept {
+	return append([]Concept(nil), idx.concepts...)
+}
+
+// Tag returns the IDs of the concepts text mentions, first mentioned
+// first, at most maxTags. A nil index tags nothing.
+func (idx *Index) Tag(text string) []string {
+	if idx == nil || text == "" {
+		return nil
+	}
+
+	type hit struct {
+		id  string
+		pos int
+	}
+	var hits []hit
+	for i, re := range idx.patterns {
+		if re == nil {
+			continue
+		}
+		if loc := re.FindStringIndex(text); loc != nil {
+			hits = append(hits, hit{idx.concepts[i].ID, loc[0]})
+		}
+	}
+	sort.SliceStable(hits, func(a, b int) bool { return hits[a].pos < hits[b].pos })
+
+	ids := make([]string, 0, min(len(hits), maxTags))
+	for _, h := range hits[:min(len(hits), maxTags)] {
+		ids = append(ids, h.id)
+	}
+	return ids
+}
+
+// Exercises returns the exercises that practice a concept, by ID. An
+// exercise practices it when one of its tags, or the last segment of one,
+// is among the concept's tags and the languages agree.
+func (c Concept) Exercises(exercises []*domain.Exercise) []*domain.Exercise {
+	tags := make(map[string]bool, len(c.Tags))
+	for _, t := range c.Tags {
+		tags[strings.ToLower(t)] = true
+	}
+
+	var linked []*domain.Exercise
+	for _, ex := range exercises {
+		if c.Language != "" && ex.Language != "" && ex.Language != c.Language {
+			continue
+		}
+		for _, t := range ex.Tags {
+			t = strings.ToLower(t)
+			if tags[t] || tags[t[strings.LastIndex(t, "/")+1:]] {
+				linked = append(linked, ex)
+				break
+			}
+		}
+	}
+	sort.Slice(linked, func(i, j int) bool { return linked[i].ID < linked[j].ID })
+	return linked
+}
@@ -0,0 +1,106 @@
+package concept
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestDefault_Valid(t *testing.T) {
+	idx := Default()
+	seen := make(map[string]bool)
+	for _, c := range idx.List() {
+		if c.ID == "" || c.Name == "" || c.Summary == "" {
+			t.Errorf("concept %q is missing an ID, name or summary", c.ID)
+		}
+		if seen[c.ID] {
+			t.Errorf("concept %q is listed twice", c.ID)
+		}
+		seen[c.ID] = true
+		if len(c.Keywords) == 0 {
+			t.Errorf("concept %q has no keywords and can never be tagged", c.ID)
+		}
+	}
+	for _, c := range idx.List() {
+		for _, id := range c.Related {
+			if _, ok := idx.Get(id); !ok {
+				t.Errorf("concept %q relates to unknown concept %q", c.ID, id)
+			}
+		}
+	}
+}
+
+func TestIndex_Tag(t *testing.T) {
+	idx := Default()
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "first mentioned first",
+			text: "Think about what happens to the slice when the goroutine appends to it.",
+			want: []string{"slices", "goroutines"},
+		},
+		{
+			name: "case-insensitive",
+			text: "Does your type satisfy the Interface?",
+			want: []string{"interfaces"},
+		},
+		{
+			name: "word boundaries",
+			text: "Consider the roadmap of your loop.",
+			want: []string{},
+		},
+		{
+			name: "empty",
+			text: "",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idx.Tag(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tag() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndex_Tag_Capped(t *testing.T) {
+	text := "goroutine channel mutex interface slice map pointer struct"
+	if got := Default().Tag(text); len(got) != maxTags {
+		t.Errorf("Tag() = %v; want %d concepts", got, maxTags)
+	}
+}
+
+func TestIndex_Tag_Nil(t *testing.T) {
+	var idx *Index
+	if got := idx.Tag("goroutines"); got != nil {
+		t.Errorf("Tag() on nil index = %v; want nil", got)
+	}
+}
+
+func TestConcept_Exercises(t *testing.T) {
+	c, ok := Default().Get("slices")
+	if !ok {
+		t.Fatal("slices concept missing")
+	}
+
+	exercises := []*domain.Exercise{
+		{ID: "go-v1/basics/slices", Language: "go", Tags: []string{"basics/slices"}},
+		{ID: "go-v1/basics/hello", Language: "go", Tags: []string{"basics"}},
+		{ID: "go-v1/collections/dedupe", Language: "go", Tags: []string{"collections"}},
+		{ID: "python-v1/lists", Language: "python", Tags: []string{"slices"}},
+	}
+	var ids []string
+	for _, ex := range c.Exercises(exercises) {
+		ids = append(ids, ex.ID)
+	}
+	want := []string{"go-v1/basics/slices", "go-v1/collections/dedupe"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("Exercises() = %v; want %v", ids, want)
+	}
+}
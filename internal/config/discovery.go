@@ -0,0 +1,90 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// DiscoveryFile is written to ~/.temper by a running daemon so clients can
+// find it when it is not on the configured port
+const DiscoveryFile = "daemon.json"
+
+// Discovery records where a running daemon listens
+type Discovery struct {
+	Addr      string    `json:"addr"` // host:port actually bound
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// URL returns the daemon's base URL. A daemon bound to every interface
+// is reached over loopback.
+func (d *Discovery) URL() string {
+	host, port, err := net.SplitHostPort(d.Addr)
+	if err != nil {
+		return "http://" + d.Addr
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// WriteDiscovery records d in dir/daemon.json
+func WriteDiscovery(dir string, d *Discovery) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal discovery: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, DiscoveryFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("write discovery file: %w", err)
+	}
+	return nil
+}
+
+// ReadDiscovery loads dir/daemon.json. The file outlives a crashed daemon,
+// so callers should still check the daemon answers.
+func ReadDiscovery(dir string) (*Discovery, error) {
+	data, err := os.ReadFile(filepath.Join(dir, DiscoveryFile))
+	if err != nil {
+		return nil, err
+	}
+	var d Discovery
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("parse discovery file: %w", err)
+	}
+	if d.Addr == "" {
+		return nil, fmt.Errorf("discovery file has no address")
+	}
+	return &d, nil
+}
+
+// RemoveDiscovery deletes dir/daemon.json on clean shutdown
+func RemoveDiscovery(dir string) error {
+	err := os.Remove(filepath.Join(dir, DiscoveryFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// DaemonURL returns the base URL clients should use: the address in the
+// discovery file when a daemon wrote one, otherwise the configured port.
+func DaemonURL() string {
+	if dir, err := TemperDir(); err == nil {
+		if d, err := ReadDiscovery(dir); err == nil {
+			return d.URL()
+		}
+	}
+
+	bind, port := "127.0.0.1", 7432
+	if cfg, err := LoadLocalConfig(); err == nil && cfg.Daemon.Port != 0 {
+		bind, port = cfg.Daemon.Bind, cfg.Daemon.Port
+	}
+	d := Discovery{Addr: net.JoinHostPort(bind, strconv.Itoa(port))}
+	return d.URL()
+}
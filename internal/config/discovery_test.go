@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiscovery_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := ReadDiscovery(dir); !os.IsNotExist(err) {
+		t.Fatalf("ReadDiscovery() without file error = %v, want not exist", err)
+	}
+
+	want := &Discovery{Addr: "127.0.0.1:51234", PID: 42, StartedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	if err := WriteDiscovery(dir, want); err != nil {
+		t.Fatalf("WriteDiscovery() error = %v", err)
+	}
+	got, err := ReadDiscovery(dir)
+	if err != nil {
+		t.Fatalf("ReadDiscovery() error = %v", err)
+	}
+	if *got != *want {
+		t.Errorf("ReadDiscovery() = %+v, want %+v", got, want)
+	}
+	if got.URL() != "http://127.0.0.1:51234" {
+		t.Errorf("URL() = %q", got.URL())
+	}
+
+	unspecified := Discovery{Addr: "0.0.0.0:7432"}
+	if unspecified.URL() != "http://127.0.0.1:7432" {
+		t.Errorf("URL() for 0.0.0.0 = %q, want loopback", unspecified.URL())
+	}
+
+	if err := RemoveDiscovery(dir); err != nil {
+		t.Fatalf("RemoveDiscovery() error = %v", err)
+	}
+	if err := RemoveDiscovery(dir); err != nil {
+		t.Errorf("RemoveDiscovery() twice error = %v", err)
+	}
+}
+
+func TestReadDiscovery_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, DiscoveryFile), []byte(`{"pid": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadDiscovery(dir); err == nil {
+		t.Error("ReadDiscovery() should reject a file without an address")
+	}
+}
+
+func TestDaemonURL(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if got := DaemonURL(); got != "http://127.0.0.1:7432" {
+		t.Errorf("DaemonURL() without discovery = %q, want default", got)
+	}
+
+	dir := filepath.Join(home, ".temper")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteDiscovery(dir, &Discovery{Addr: "127.0.0.1:50000"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := DaemonURL(); got != "http://127.0.0.1:50000" {
+		t.Errorf("DaemonURL() = %q, want discovered address", got)
+	}
+}
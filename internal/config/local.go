@@ -6,23 +6,123 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // LocalConfig holds configuration for local daemon mode
 type LocalConfig struct {
-	Daemon   DaemonConfig   `yaml:"daemon"`
-	Storage  StorageConfig  `yaml:"storage"`
-	LLM      LLMConfig      `yaml:"llm"`
-	Learning LearningConfig `yaml:"learning_contract"`
-	Runner   RunnerConfig   `yaml:"runner"`
+	Daemon    DaemonConfig    `yaml:"daemon"`
+	Storage   StorageConfig   `yaml:"storage"`
+	LLM       LLMConfig       `yaml:"llm"`
+	Learning  LearningConfig  `yaml:"learning_contract"`
+	Runner    RunnerConfig    `yaml:"runner"`
+	Retention RetentionConfig `yaml:"retention"`
+	Sessions  SessionsConfig  `yaml:"sessions"`
+	Reminders RemindersConfig `yaml:"reminders"`
+	Telemetry TelemetryConfig `yaml:"telemetry"`
+	Packs     PacksConfig     `yaml:"packs"`
+
+	// Locale is the language hints, reviews and CLI output are written in,
+	// e.g. "de" or "pt-BR". Empty follows $TEMPER_LOCALE and then the
+	// system locale.
+	Locale string `yaml:"locale,omitempty"`
+
+	Integrations IntegrationsConfig `yaml:"integrations"`
+}
+
+// RetentionConfig bounds how long session history is kept. Zero keeps
+// records forever, and both are zero unless configured: pruning deletes
+// history for good, so it is opt-in.
+type RetentionConfig struct {
+	SessionsDays int `yaml:"sessions_days"` // delete sessions idle this long
+	RunsDays     int `yaml:"runs_days"`     // delete older runs (the latest run per session is kept)
+}
+
+// SessionsConfig decides when a session nobody works on goes idle and
+// when it is abandoned. Zero turns either off.
+type SessionsConfig struct {
+	IdleMinutes int `yaml:"idle_minutes"` // no heartbeat or change this long marks a session idle
+	ExpireHours int `yaml:"expire_hours"` // and this long abandons it
+}
+
+// RemindersConfig sets when the daemon nudges the learner to practice.
+// Reminders fire once per window, and only when nothing has been practiced
+// that day or spaced-repetition reviews are due.
+type RemindersConfig struct {
+	Enabled bool             `yaml:"enabled"`
+	Windows []PracticeWindow `yaml:"windows"`
+}
+
+// PacksConfig sets how often packs installed from a source are checked
+// for new versions. Updates are downloaded in the background but only
+// applied when the user says so. Zero turns checking off.
+type PacksConfig struct {
+	UpdateCheckHours int `yaml:"update_check_hours"`
+}
+
+// TelemetryConfig opts in to recording editor activity. Everything it
+// records stays in ~/.temper; nothing is sent anywhere.
+type TelemetryConfig struct {
+	// EditEvents records typing bursts, pastes and undo storms per session
+	// (sizes and timings only, never the text) so analytics can tell
+	// authored code from pasted code
+	EditEvents bool `yaml:"edit_events"`
+}
+
+// PracticeWindow is a daily stretch of local time set aside for practice
+type PracticeWindow struct {
+	Days  []string `yaml:"days,omitempty"` // mon..sun; empty = every day
+	Start string   `yaml:"start"`          // "18:00"
+	End   string   `yaml:"end"`            // "20:00"
+}
+
+// IntegrationsConfig holds issue tracker settings used by spec import.
+// Tokens are loaded from secrets.yaml.
+type IntegrationsConfig struct {
+	GitHub GitHubConfig `yaml:"github"`
+	Jira   JiraConfig   `yaml:"jira"`
+}
+
+// GitHubConfig holds GitHub API settings
+type GitHubConfig struct {
+	APIURL string `yaml:"api_url,omitempty"` // GitHub Enterprise API; empty = api.github.com
+	Token  string `yaml:"-"`                 // Loaded from secrets.yaml
+}
+
+// JiraConfig holds Jira API settings
+type JiraConfig struct {
+	URL      string `yaml:"url,omitempty"`   // e.g. https://acme.atlassian.net
+	Email    string `yaml:"email,omitempty"` // account the API token belongs to
+	APIToken string `yaml:"-"`               // Loaded from secrets.yaml
 }
 
 // StorageConfig holds storage backend settings
 type StorageConfig struct {
 	Driver string `yaml:"driver"` // "sqlite" or "json" (default: "sqlite")
 	Path   string `yaml:"path"`   // Database file path (for sqlite); empty = ~/.temper/temper.db
+
+	// Encrypt seals session code and LLM transcripts with a key kept in
+	// the OS keychain
+	Encrypt bool `yaml:"encrypt"`
+
+	// Archive is where retention copies sessions before pruning them;
+	// without one they are deleted outright
+	Archive ArchiveConfig `yaml:"archive,omitempty"`
+}
+
+// ArchiveConfig holds the object store pruned sessions are archived to
+type ArchiveConfig struct {
+	Driver   string `yaml:"driver,omitempty"`   // "dir" or "s3"; empty disables archiving
+	Path     string `yaml:"path,omitempty"`     // dir: archive directory
+	Endpoint string `yaml:"endpoint,omitempty"` // s3: e.g. https://s3.eu-central-1.amazonaws.com or a MinIO URL
+	Region   string `yaml:"region,omitempty"`   // s3: signing region (default: us-east-1)
+	Bucket   string `yaml:"bucket,omitempty"`   // s3: bucket name
+	Prefix   string `yaml:"prefix,omitempty"`   // prepended to every key, e.g. "temper/"
+
+	AccessKeyID     string `yaml:"-"` // Loaded from secrets.yaml
+	SecretAccessKey string `yaml:"-"` // Loaded from secrets.yaml
 }
 
 // DaemonConfig holds daemon server settings
@@ -31,6 +131,9 @@ type DaemonConfig struct {
 	Bind      string `yaml:"bind"`
 	LogLevel  string `yaml:"log_level"`
 	AuthToken string `yaml:"-"` // Loaded from secrets.yaml
+
+	// UI serves the read-only web dashboard at /ui
+	UI bool `yaml:"ui"`
 }
 
 // LLMConfig holds LLM provider settings
@@ -47,6 +150,45 @@ type LLMConfig struct {
 	// When a level is not in the map, the provider's configured Model is
 	// used. A "default" string key (yaml: "default") seeds unknown levels.
 	LevelModels map[string]string `yaml:"level_models,omitempty"`
+
+	// Redaction scrubs secrets and PII from prompts before they leave the
+	// machine
+	Redaction RedactionConfig `yaml:"redaction"`
+
+	// LocalOnly pins matching sessions to Ollama regardless of
+	// DefaultProvider
+	LocalOnly LocalOnlyConfig `yaml:"local_only"`
+
+	// DryRun answers every pairing request with the prompt it would send
+	// instead of calling the LLM. For debugging prompts; a single request
+	// can ask for the same with ?dry_run=true.
+	DryRun bool `yaml:"dry_run,omitempty"`
+
+	// DocLookup lets providers that support tool calls look up Go
+	// standard library documentation in the local GOROOT while writing
+	// a hint, instead of quoting APIs from memory
+	DocLookup bool `yaml:"doc_lookup"`
+}
+
+// LocalOnlyConfig lists the sessions that must not use cloud providers
+type LocalOnlyConfig struct {
+	Packs []string `yaml:"packs,omitempty"` // exercise pack IDs
+	Paths []string `yaml:"paths,omitempty"` // globs over spec, document and code file paths, e.g. "/home/me/work/**"
+}
+
+// RedactionConfig controls prompt redaction before LLM calls
+type RedactionConfig struct {
+	Enabled         bool               `yaml:"enabled"`
+	Detectors       []string           `yaml:"detectors,omitempty"`        // api_keys, emails, internal_hosts; empty = all
+	InternalDomains []string           `yaml:"internal_domains,omitempty"` // e.g. corp.example.com
+	Patterns        []RedactionPattern `yaml:"patterns,omitempty"`
+	IncludeLocal    bool               `yaml:"include_local"` // also redact prompts sent to Ollama
+}
+
+// RedactionPattern is a user-defined regex whose matches are redacted
+type RedactionPattern struct {
+	Name  string `yaml:"name"`
+	Regex string `yaml:"regex"`
 }
 
 // ProviderConfig holds settings for a single LLM provider
@@ -55,18 +197,62 @@ type ProviderConfig struct {
 	Model   string `yaml:"model"`
 	URL     string `yaml:"url,omitempty"` // For Ollama
 	APIKey  string `yaml:"-"`             // Loaded from secrets.yaml
+	// Timeout bounds each call to the provider, e.g. "45s"; streamed
+	// replies count from the request to the last chunk. Zero keeps the
+	// HTTP client limits (two minutes without streaming).
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Quota caps the provider's use per calendar month
+	Quota QuotaConfig `yaml:"quota,omitempty"`
+}
+
+// QuotaConfig caps a provider's monthly use. The daemon warns once the
+// provider passes a soft cap and, at a hard cap, blocks it or sends its
+// calls to the local Ollama model. Zero leaves a cap off; cost caps need
+// the provider's prices.
+type QuotaConfig struct {
+	SoftTokens  int     `yaml:"soft_tokens,omitempty"` // input plus output tokens
+	HardTokens  int     `yaml:"hard_tokens,omitempty"`
+	SoftCost    float64 `yaml:"soft_cost,omitempty"` // USD
+	HardCost    float64 `yaml:"hard_cost,omitempty"`
+	InputPrice  float64 `yaml:"input_price,omitempty"`  // USD per million input tokens
+	OutputPrice float64 `yaml:"output_price,omitempty"` // USD per million output tokens
+	AtHardCap   string  `yaml:"at_hard_cap,omitempty"`  // block (default) or local
 }
 
 // LearningConfig holds learning contract settings
 type LearningConfig struct {
 	DefaultTrack string                 `yaml:"default_track"`
 	Tracks       map[string]TrackConfig `yaml:"tracks"`
+	SkillModel   string                 `yaml:"skill_model,omitempty"` // heuristic (default) or elo
+
+	// AdaptiveDifficulty starts exercises in the pack author's harder or
+	// easier variant when the learner is clearly above or below them.
+	// Off unless configured.
+	AdaptiveDifficulty bool `yaml:"adaptive_difficulty"`
 }
 
 // TrackConfig holds settings for a learning track
 type TrackConfig struct {
-	MaxLevel        int `yaml:"max_level"`
-	CooldownSeconds int `yaml:"cooldown_seconds"`
+	MaxLevel        int          `yaml:"max_level"`
+	CooldownSeconds int          `yaml:"cooldown_seconds"`
+	TestFirst       bool         `yaml:"test_first"`       // feature sessions withhold implementation hints until a test fails
+	Stuck           StuckConfig  `yaml:"stuck,omitempty"`  // when to nudge a learner who seems stuck
+	Budget          BudgetConfig `yaml:"budget,omitempty"` // hint tokens a session can spend
+}
+
+// BudgetConfig gives a track's sessions a hint budget. Zero tokens means
+// no budget; empty costs use the defaults (L0 free, then 1, 1, 2, 3, 5).
+type BudgetConfig struct {
+	Tokens int   `yaml:"tokens,omitempty"`
+	Costs  []int `yaml:"costs,omitempty"` // per level from L0
+}
+
+// StuckConfig tunes automatic stuck detection for a track. Zero values
+// use the defaults (5 runs, 600 seconds).
+type StuckConfig struct {
+	Disabled    bool `yaml:"disabled,omitempty"`
+	FailingRuns int  `yaml:"failing_runs,omitempty"` // consecutive failing runs with only small changes
+	IdleSeconds int  `yaml:"idle_seconds,omitempty"` // quiet time after a failing run
 }
 
 // RunnerConfig holds code execution settings. Docker is the only
@@ -75,6 +261,21 @@ type TrackConfig struct {
 type RunnerConfig struct {
 	Executor string             `yaml:"executor"`
 	Docker   DockerRunnerConfig `yaml:"docker"`
+
+	// ExplainErrors attaches beginner-friendly explanations of compiler
+	// and test errors to session runs unless a run opts out
+	ExplainErrors bool `yaml:"explain_errors,omitempty"`
+
+	Commands CommandsConfig `yaml:"commands,omitempty"`
+}
+
+// CommandsConfig approves the custom commands projects define. A
+// project's .temper.yaml can name any command, so only those matching an
+// allowlist entry here run.
+type CommandsConfig struct {
+	// Allowlist holds command prefixes: "go vet" allows "go vet ./...",
+	// "make check" allows only that target
+	Allowlist []string `yaml:"allowlist,omitempty"`
 }
 
 // DockerRunnerConfig holds Docker executor settings
@@ -84,6 +285,23 @@ type DockerRunnerConfig struct {
 	CPULimit       float64 `yaml:"cpu_limit"`
 	TimeoutSeconds int     `yaml:"timeout_seconds"`
 	NetworkOff     bool    `yaml:"network_off"`
+	DebugImage     string  `yaml:"debug_image,omitempty"`     // image with dlv for debug runs; defaults to Image
+	ImageDigest    string  `yaml:"image_digest,omitempty"`    // pins Image to this digest (sha256:...)
+	GoVersion      string  `yaml:"go_version,omitempty"`      // expected toolchain; inferred from the image tag if empty
+	ToolchainImage string  `yaml:"toolchain_image,omitempty"` // image for packs pinning another Go version; {version} is replaced
+	Isolation      string  `yaml:"isolation,omitempty"`       // docker (default), gvisor or firecracker
+	Runtime        string  `yaml:"runtime,omitempty"`         // overrides the Docker runtime name chosen by isolation
+	Platform       string  `yaml:"platform,omitempty"`        // linux/amd64 or linux/arm64; empty = Docker's native platform
+
+	Dependencies DependencyConfig `yaml:"dependencies,omitempty"`
+}
+
+// DependencyConfig controls third-party Go modules in exercise code.
+// Imports outside the allowlist are reported as run violations.
+type DependencyConfig struct {
+	Mode      string   `yaml:"mode,omitempty"`      // none (default), cache or proxy
+	Allowlist []string `yaml:"allowlist,omitempty"` // module path prefixes
+	ProxyURL  string   `yaml:"proxy_url,omitempty"` // GOPROXY used in proxy mode
 }
 
 // SecretsConfig holds API keys and the daemon auth token loaded from secrets.yaml
@@ -94,6 +312,24 @@ type SecretsConfig struct {
 	Providers map[string]struct {
 		APIKey string `yaml:"api_key"`
 	} `yaml:"providers"`
+	Integrations struct {
+		GitHubToken  string `yaml:"github_token,omitempty"`
+		JiraAPIToken string `yaml:"jira_api_token,omitempty"`
+	} `yaml:"integrations,omitempty"`
+	Storage struct {
+		ArchiveAccessKeyID     string `yaml:"archive_access_key_id,omitempty"`
+		ArchiveSecretAccessKey string `yaml:"archive_secret_access_key,omitempty"`
+	} `yaml:"storage,omitempty"`
+}
+
+// WorkspaceEnv points the daemon at the project it serves. The daemon runs
+// from ~/.temper, so inside a devcontainer this is the mounted workspace.
+const WorkspaceEnv = "TEMPER_WORKSPACE"
+
+// WorkspaceRoot returns the directory the daemon resolves .specs/ and
+// exercises/ against: $TEMPER_WORKSPACE, or the working directory
+func WorkspaceRoot() string {
+	return getEnv(WorkspaceEnv, ".")
 }
 
 // TemperDir returns the path to ~/.temper
@@ -138,6 +374,7 @@ func DefaultLocalConfig() *LocalConfig {
 			Port:     7432,
 			Bind:     "127.0.0.1",
 			LogLevel: "info",
+			UI:       true,
 		},
 		Storage: StorageConfig{
 			Driver: "sqlite",
@@ -170,6 +407,7 @@ func DefaultLocalConfig() *LocalConfig {
 				"4": "claude-opus-4-7",
 				"5": "claude-opus-4-7",
 			},
+			DocLookup: true,
 		},
 		Learning: LearningConfig{
 			DefaultTrack: "practice",
@@ -198,6 +436,13 @@ func DefaultLocalConfig() *LocalConfig {
 				NetworkOff:     true,
 			},
 		},
+		Sessions: SessionsConfig{
+			IdleMinutes: 10,
+			ExpireHours: 168,
+		},
+		Packs: PacksConfig{
+			UpdateCheckHours: 24,
+		},
 	}
 }
 
@@ -259,6 +504,10 @@ func loadSecrets(dir string, cfg *LocalConfig) error {
 		}
 	}
 	cfg.Daemon.AuthToken = secrets.Daemon.AuthToken
+	cfg.Integrations.GitHub.Token = secrets.Integrations.GitHubToken
+	cfg.Integrations.Jira.APIToken = secrets.Integrations.JiraAPIToken
+	cfg.Storage.Archive.AccessKeyID = secrets.Storage.ArchiveAccessKeyID
+	cfg.Storage.Archive.SecretAccessKey = secrets.Storage.ArchiveSecretAccessKey
 
 	return nil
 }
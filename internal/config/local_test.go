@@ -25,6 +25,18 @@ func TestTemperDir(t *testing.T) {
 	}
 }
 
+func TestWorkspaceRoot(t *testing.T) {
+	t.Setenv(WorkspaceEnv, "")
+	if got := WorkspaceRoot(); got != "." {
+		t.Errorf("WorkspaceRoot() = %q, want working directory", got)
+	}
+
+	t.Setenv(WorkspaceEnv, "/workspaces/course")
+	if got := WorkspaceRoot(); got != "/workspaces/course" {
+		t.Errorf("WorkspaceRoot() = %q, want $%s", got, WorkspaceEnv)
+	}
+}
+
 func TestEnsureTemperDir(t *testing.T) {
 	// Use temp directory as HOME
 	tmpHome := t.TempDir()
@@ -85,6 +97,12 @@ func TestDefaultLocalConfig(t *testing.T) {
 	if cfg.Learning.DefaultTrack != "practice" {
 		t.Errorf("Learning.DefaultTrack = %q, want practice", cfg.Learning.DefaultTrack)
 	}
+	if cfg.Retention != (RetentionConfig{}) {
+		t.Errorf("Retention = %+v, want pruning off until configured", cfg.Retention)
+	}
+	if cfg.Learning.AdaptiveDifficulty {
+		t.Error("Learning.AdaptiveDifficulty should be off until configured")
+	}
 	if _, ok := cfg.Learning.Tracks["practice"]; !ok {
 		t.Error("Learning.Tracks should include practice")
 	}
@@ -202,6 +220,29 @@ func TestLoadSecrets(t *testing.T) {
 	}
 }
 
+func TestLoadSecrets_Integrations(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := DefaultLocalConfig()
+
+	secretsContent := `integrations:
+  github_token: ghp-test
+  jira_api_token: jira-test
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "secrets.yaml"), []byte(secretsContent), 0600); err != nil {
+		t.Fatalf("Failed to write secrets file: %v", err)
+	}
+
+	if err := loadSecrets(tmpDir, cfg); err != nil {
+		t.Fatalf("loadSecrets() error = %v", err)
+	}
+	if cfg.Integrations.GitHub.Token != "ghp-test" {
+		t.Errorf("GitHub token = %q, want ghp-test", cfg.Integrations.GitHub.Token)
+	}
+	if cfg.Integrations.Jira.APIToken != "jira-test" {
+		t.Errorf("Jira token = %q, want jira-test", cfg.Integrations.Jira.APIToken)
+	}
+}
+
 func TestLoadSecrets_NoSecretsFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	cfg := DefaultLocalConfig()
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigFile is the per-project config a session's workspace root
+// may carry. Its settings are layered over ~/.temper/config.yaml for
+// sessions started in that project.
+const ProjectConfigFile = ".temper.yaml"
+
+// ProjectConfig holds the settings a project can override. Empty fields
+// keep the global config.
+type ProjectConfig struct {
+	// Track is the learning track sessions in this project use when they
+	// don't ask for one
+	Track string `yaml:"track,omitempty"`
+
+	// SpecsDir is where the project keeps its specs, relative to the
+	// project root. Defaults to .specs.
+	SpecsDir string `yaml:"specs_dir,omitempty"`
+
+	LLM    ProjectLLMConfig    `yaml:"llm,omitempty"`
+	Runner ProjectRunnerConfig `yaml:"runner,omitempty"`
+
+	// Commands are custom recipes a run can ask for by name instead of
+	// format, build and test, e.g. {"check": "make check"}. One only runs
+	// when runner.commands.allowlist in the user's config approves it.
+	Commands map[string]string `yaml:"commands,omitempty"`
+}
+
+// ProjectLLMConfig routes the project's hints to a provider other than
+// the default, e.g. a local one for client code
+type ProjectLLMConfig struct {
+	Provider string `yaml:"provider,omitempty"`
+
+	// LevelModels names the model per intervention level, keyed like the
+	// global level_models ("L1", "2", ...). They are models of Provider,
+	// or of the default provider when Provider is empty.
+	LevelModels map[string]string `yaml:"level_models,omitempty"`
+}
+
+// ProjectRunnerConfig overrides the Docker runner for the project's runs
+type ProjectRunnerConfig struct {
+	Image          string `yaml:"image,omitempty"`
+	MemoryMB       int64  `yaml:"memory_mb,omitempty"`
+	TimeoutSeconds int    `yaml:"timeout_seconds,omitempty"`
+}
+
+// LoadProjectConfig reads root/.temper.yaml. It returns nil and no error
+// when the project has none.
+func LoadProjectConfig(root string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(filepath.Join(root, ProjectConfigFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", ProjectConfigFile, err)
+	}
+
+	var cfg ProjectConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", ProjectConfigFile, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", ProjectConfigFile, err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks the settings that can be checked without the global
+// config. Whether the track and provider exist is up to the daemon.
+func (c *ProjectConfig) Validate() error {
+	if c.SpecsDir != "" {
+		dir := filepath.Clean(c.SpecsDir)
+		if filepath.IsAbs(dir) || dir == "." || dir == ".." || strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("specs_dir %q must be a directory inside the project", c.SpecsDir)
+		}
+	}
+	for key := range c.LLM.LevelModels {
+		if !validLevelKey(key) {
+			return fmt.Errorf("llm.level_models: unknown level %q", key)
+		}
+	}
+	if c.Runner.MemoryMB < 0 {
+		return fmt.Errorf("runner.memory_mb must be non-negative")
+	}
+	if c.Runner.TimeoutSeconds < 0 {
+		return fmt.Errorf("runner.timeout_seconds must be non-negative")
+	}
+	for name, line := range c.Commands {
+		if !commandNameRegex.MatchString(name) {
+			return fmt.Errorf("commands: name %q must be lowercase letters, digits, - and _", name)
+		}
+		if strings.TrimSpace(line) == "" {
+			return fmt.Errorf("commands.%s is empty", name)
+		}
+	}
+	return nil
+}
+
+var commandNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// validLevelKey reports whether key names an intervention level, L0-L5
+// or 0-5
+func validLevelKey(key string) bool {
+	key = strings.TrimPrefix(key, "L")
+	return len(key) == 1 && key[0] >= '0' && key[0] <= '5'
+}
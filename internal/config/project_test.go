@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	cfg, err := LoadProjectConfig(root)
+	if err != nil || cfg != nil {
+		t.Fatalf("LoadProjectConfig(no file) = %+v, %v; want nil, nil", cfg, err)
+	}
+
+	content := `track: strict
+specs_dir: docs/specs
+llm:
+  provider: ollama
+  level_models:
+    L2: qwen2.5-coder:14b
+runner:
+  image: registry.local/go:1.23
+  memory_mb: 1024
+  timeout_seconds: 120
+commands:
+  check: make check
+`
+	if err := os.WriteFile(filepath.Join(root, ProjectConfigFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error = %v", err)
+	}
+	if cfg.Track != "strict" || cfg.SpecsDir != "docs/specs" {
+		t.Errorf("track, specs_dir = %q, %q", cfg.Track, cfg.SpecsDir)
+	}
+	if cfg.LLM.Provider != "ollama" || cfg.LLM.LevelModels["L2"] != "qwen2.5-coder:14b" {
+		t.Errorf("llm = %+v", cfg.LLM)
+	}
+	if cfg.Runner != (ProjectRunnerConfig{Image: "registry.local/go:1.23", MemoryMB: 1024, TimeoutSeconds: 120}) {
+		t.Errorf("runner = %+v", cfg.Runner)
+	}
+	if cfg.Commands["check"] != "make check" {
+		t.Errorf("commands = %+v", cfg.Commands)
+	}
+}
+
+func TestProjectConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ProjectConfig
+		want string
+	}{
+		{"empty", ProjectConfig{}, ""},
+		{"nested specs dir", ProjectConfig{SpecsDir: "docs/specs"}, ""},
+		{"absolute specs dir", ProjectConfig{SpecsDir: "/etc"}, "specs_dir"},
+		{"specs dir outside project", ProjectConfig{SpecsDir: "../other/.specs"}, "specs_dir"},
+		{"level keys", ProjectConfig{LLM: ProjectLLMConfig{LevelModels: map[string]string{"L0": "a", "5": "b"}}}, ""},
+		{"unknown level", ProjectConfig{LLM: ProjectLLMConfig{LevelModels: map[string]string{"L6": "a"}}}, "level_models"},
+		{"negative memory", ProjectConfig{Runner: ProjectRunnerConfig{MemoryMB: -1}}, "memory_mb"},
+		{"negative timeout", ProjectConfig{Runner: ProjectRunnerConfig{TimeoutSeconds: -5}}, "timeout_seconds"},
+		{"commands", ProjectConfig{Commands: map[string]string{"check": "make check", "go-vet": "go vet ./..."}}, ""},
+		{"command name", ProjectConfig{Commands: map[string]string{"Make Check": "make check"}}, "commands"},
+		{"empty command", ProjectConfig{Commands: map[string]string{"check": " "}}, "commands.check"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v; want one about %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadProjectConfig_Invalid(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ProjectConfigFile), []byte("runner:\n  memory_mb: -1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProjectConfig(root); err == nil || !strings.Contains(err.Error(), ProjectConfigFile) {
+		t.Errorf("LoadProjectConfig() error = %v; want it to name %s", err, ProjectConfigFile)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"github.com/felixgeelhaar/temper/internal/domain"
 	"github.com/felixgeelhaar/temper/internal/pairing"
 	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/felixgeelhaar/temper/internal/spec"
 )
 
 func setupAuthoringServer(t *testing.T) (*serverWithMocks, string) {
@@ -93,3 +95,115 @@ func TestAuthoringHint_Success(t *testing.T) {
 		t.Fatalf("status = %d; want %d", rec.Code, http.StatusOK)
 	}
 }
+
+func setupMultiFileAuthoringServer(t *testing.T) *serverWithMocks {
+	t.Helper()
+	m, _ := setupAuthoringServer(t)
+	specs := map[string]*domain.ProductSpec{
+		".specs/epic.yaml": {
+			Name: "Epic", FilePath: ".specs/epic.yaml",
+			Features: []domain.Feature{{ID: "checkout", Title: "Checkout", DependsOn: []string{".specs/billing.yaml#invoices"}}},
+		},
+		".specs/billing.yaml": {
+			Name: "Billing", FilePath: ".specs/billing.yaml",
+			Features: []domain.Feature{{ID: "checkout", Title: "Payments"}},
+		},
+	}
+	m.specs.loadFn = func(ctx context.Context, path string) (*domain.ProductSpec, error) {
+		return specs[path], nil
+	}
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{
+			ID:             id,
+			Intent:         session.IntentSpecAuthoring,
+			SpecPath:       ".specs/epic.yaml",
+			AuthoringSpecs: []string{".specs/epic.yaml", ".specs/billing.yaml"},
+			AuthoringDocs:  []string{"README.md"},
+			CreatedAt:      time.Now(),
+		}, nil
+	}
+	return m
+}
+
+func TestAuthoringSuggest_TargetsNamedFile(t *testing.T) {
+	m := setupMultiFileAuthoringServer(t)
+	var got pairing.AuthoringContext
+	m.pairing.suggestForSectionFn = func(ctx context.Context, authCtx pairing.AuthoringContext) ([]domain.AuthoringSuggestion, error) {
+		got = authCtx
+		return []domain.AuthoringSuggestion{}, nil
+	}
+
+	body := []byte(`{"section":"features","file":".specs/billing.yaml"}`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/session-1/authoring/suggest", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	m.server.router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if got.Spec == nil || got.Spec.Name != "Billing" {
+		t.Errorf("suggested for %v; want the billing spec", got.Spec)
+	}
+	if len(got.Siblings) != 1 || got.Siblings[0].Name != "Epic" {
+		t.Errorf("siblings = %v; want the epic spec", got.Siblings)
+	}
+}
+
+func TestAuthoringApply_UnknownFile(t *testing.T) {
+	m := setupMultiFileAuthoringServer(t)
+
+	body := []byte(`{"section":"goals","value":"x","file":".specs/other.yaml"}`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/session-1/authoring/apply", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	m.server.router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d; want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAuthoringCheck_CrossFileFindings(t *testing.T) {
+	m := setupMultiFileAuthoringServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/sessions/session-1/authoring/check", nil)
+	rec := httptest.NewRecorder()
+	m.server.router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var resp struct {
+		Files    []string             `json:"files"`
+		Findings []domain.SpecFinding `json:"findings"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	kinds := map[domain.SpecFindingKind]bool{}
+	for _, f := range resp.Findings {
+		kinds[f.Kind] = true
+	}
+	if !kinds[domain.FindingDuplicateFeature] || !kinds[domain.FindingBrokenReference] {
+		t.Errorf("findings = %+v; want a duplicate feature and a broken reference", resp.Findings)
+	}
+}
+
+func TestAuthoringAddFile(t *testing.T) {
+	m, _ := setupAuthoringServer(t)
+	m.sessions.addAuthoringSpecFn = func(ctx context.Context, id, specPath string) (*session.Session, error) {
+		if specPath == ".specs/missing.yaml" {
+			return nil, fmt.Errorf("load spec %s: %w", specPath, spec.ErrSpecNotFound)
+		}
+		return &session.Session{ID: id, SpecPath: "spec.yaml", AuthoringSpecs: []string{"spec.yaml", specPath}}, nil
+	}
+
+	for path, want := range map[string]int{".specs/billing.yaml": http.StatusOK, ".specs/missing.yaml": http.StatusNotFound} {
+		body := []byte(`{"spec_path":"` + path + `"}`)
+		req := httptest.NewRequest(http.MethodPost, "/v1/sessions/session-1/authoring/files", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		m.server.router.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("%s: status = %d; want %d", path, rec.Code, want)
+		}
+	}
+}
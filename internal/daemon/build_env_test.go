@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func TestHandleCreateSession_BuildEnv(t *testing.T) {
+	m := newServerWithMocks()
+	var got session.CreateRequest
+	m.sessions.createFn = func(ctx context.Context, req session.CreateRequest) (*session.Session, error) {
+		got = req
+		if err := req.BuildEnv.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", session.ErrInvalidBuildEnv, err)
+		}
+		return &session.Session{ID: "s1", Status: session.StatusActive}, nil
+	}
+
+	body := `{"intent":"greenfield","build_env":{"env":{"TZ":"Asia/Tokyo"},"build_tags":["integration"],"test_flags":["-count=1"]}}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if got.BuildEnv.Env["TZ"] != "Asia/Tokyo" || len(got.BuildEnv.BuildTags) != 1 || len(got.BuildEnv.TestFlags) != 1 {
+		t.Errorf("BuildEnv = %+v", got.BuildEnv)
+	}
+
+	body = `{"intent":"greenfield","build_env":{"env":{"GOPROXY":"https://example.com"}}}`
+	req = httptest.NewRequest(http.MethodPost, "/v1/sessions", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"build_env"`) {
+		t.Errorf("body should name build_env: %s", w.Body.String())
+	}
+}
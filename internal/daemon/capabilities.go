@@ -0,0 +1,118 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/fixture"
+	"github.com/felixgeelhaar/temper/internal/quota"
+)
+
+// Capability modes reported in /v1/status. Clients adjust their UI to
+// these instead of finding out one failed request at a time.
+const (
+	CapabilityDocker      = "docker"      // runs execute in containers
+	CapabilityLocal       = "local"       // runs execute in-process (mock mode)
+	CapabilityStreaming   = "streaming"   // hints stream token by token
+	CapabilitySync        = "sync"        // hints arrive whole
+	CapabilityOffline     = "offline"     // hints come from the exercise's static ladder
+	CapabilityOK          = "ok"          // working normally
+	CapabilityDegraded    = "degraded"    // working, but behind or partial
+	CapabilityUnavailable = "unavailable" // requests will fail
+)
+
+// capabilityTimeout bounds each live check, so a hung Docker daemon
+// doesn't hang the status endpoint
+const capabilityTimeout = 2 * time.Second
+
+// Capability is the mode a feature is working in, and why when it isn't
+// working normally
+type Capability struct {
+	Mode   string `json:"mode"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Capabilities is what the daemon can do right now
+type Capabilities struct {
+	Runs      Capability `json:"runs"`
+	Pairing   Capability `json:"pairing"`
+	Specs     Capability `json:"specs"`
+	Analytics Capability `json:"analytics"`
+}
+
+// pinger is implemented by executors that can check their backend is up
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// capabilities checks each feature live
+func (s *Server) capabilities(ctx context.Context) Capabilities {
+	return Capabilities{
+		Runs:      s.runsCapability(ctx),
+		Pairing:   s.pairingCapability(),
+		Specs:     s.specsCapability(),
+		Analytics: s.analyticsCapability(),
+	}
+}
+
+func (s *Server) runsCapability(ctx context.Context) Capability {
+	switch executor := s.runnerExecutor.(type) {
+	case nil:
+		return Capability{Mode: CapabilityUnavailable, Reason: "no runner configured"}
+	case fixture.Executor:
+		return Capability{Mode: CapabilityLocal, Reason: "mock runner"}
+	case pinger:
+		ctx, cancel := context.WithTimeout(ctx, capabilityTimeout)
+		defer cancel()
+		if err := executor.Ping(ctx); err != nil {
+			return Capability{Mode: CapabilityUnavailable, Reason: fmt.Sprintf("docker unreachable: %v", err)}
+		}
+		return Capability{Mode: CapabilityDocker}
+	default:
+		return Capability{Mode: CapabilityLocal}
+	}
+}
+
+func (s *Server) pairingCapability() Capability {
+	offline := func(reason string) Capability {
+		return Capability{Mode: CapabilityOffline, Reason: reason + "; hints come from the exercise's static hints"}
+	}
+	if s.llmRegistry == nil || len(s.llmRegistry.List()) == 0 {
+		return offline("no LLM provider configured")
+	}
+	provider, err := s.llmRegistry.Default()
+	if err != nil {
+		return offline("no default LLM provider")
+	}
+	// A provider blocked at its hard cap fails every call until next month
+	if s.quota != nil && s.quota.Level(provider.Name()) == quota.LevelHard &&
+		s.quota.Limits(provider.Name()).AtHardCap == quota.AtHardCapBlock {
+		return offline(provider.Name() + " reached its monthly hard cap")
+	}
+	if provider.SupportsStreaming() {
+		return Capability{Mode: CapabilityStreaming}
+	}
+	return Capability{Mode: CapabilitySync}
+}
+
+func (s *Server) specsCapability() Capability {
+	if s.specService == nil {
+		return Capability{Mode: CapabilityUnavailable, Reason: "spec service not configured"}
+	}
+	return Capability{Mode: CapabilityOK}
+}
+
+func (s *Server) analyticsCapability() Capability {
+	if s.profileService == nil {
+		return Capability{Mode: CapabilityUnavailable, Reason: "profile service not configured"}
+	}
+	// Updates the profile store rejected wait on disk; the numbers lag
+	// until they're applied
+	if q, ok := s.profileService.(interface{ QueuedUpdates() int }); ok {
+		if n := q.QueuedUpdates(); n > 0 {
+			return Capability{Mode: CapabilityDegraded, Reason: fmt.Sprintf("%d profile updates waiting to be retried", n)}
+		}
+	}
+	return Capability{Mode: CapabilityOK}
+}
@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/felixgeelhaar/temper/internal/quota"
+)
+
+type pingExecutor struct {
+	mockExecutor
+	err error
+}
+
+func (e *pingExecutor) Ping(ctx context.Context) error { return e.err }
+
+type queuedProfileService struct {
+	mockProfileService
+	queued int
+}
+
+func (p *queuedProfileService) QueuedUpdates() int { return p.queued }
+
+func TestCapabilities(t *testing.T) {
+	m := newServerWithMocks()
+
+	got := m.server.capabilities(context.Background())
+	if got.Runs.Mode != CapabilityLocal || got.Pairing.Mode != CapabilityOffline ||
+		got.Specs.Mode != CapabilityOK || got.Analytics.Mode != CapabilityOK {
+		t.Errorf("capabilities = %+v, want local runs, offline pairing, ok specs and analytics", got)
+	}
+
+	m.server.runnerExecutor = &pingExecutor{err: errors.New("connection refused")}
+	if got := m.server.runsCapability(context.Background()); got.Mode != CapabilityUnavailable || got.Reason == "" {
+		t.Errorf("runs with docker down = %+v, want unavailable with a reason", got)
+	}
+	m.server.runnerExecutor = &pingExecutor{}
+	if got := m.server.runsCapability(context.Background()); got.Mode != CapabilityDocker {
+		t.Errorf("runs with docker up = %+v, want docker", got)
+	}
+
+	m.server.profileService = &queuedProfileService{queued: 3}
+	if got := m.server.analyticsCapability(); got.Mode != CapabilityDegraded {
+		t.Errorf("analytics with queued updates = %+v, want degraded", got)
+	}
+}
+
+func TestCapabilities_Pairing(t *testing.T) {
+	m := newServerWithMocks()
+	m.registry.listFn = func() []string { return []string{"claude"} }
+	m.registry.defaultFn = func() (llm.Provider, error) { return &mockProvider{name: "claude"}, nil }
+
+	if got := m.server.pairingCapability(); got.Mode != CapabilitySync {
+		t.Errorf("pairing = %+v, want sync for a provider that doesn't stream", got)
+	}
+
+	meter, err := quota.NewMeter(t.TempDir(), map[string]quota.Limits{
+		"claude": {HardTokens: 100, AtHardCap: quota.AtHardCapBlock},
+	})
+	if err != nil {
+		t.Fatalf("NewMeter() error = %v", err)
+	}
+	if err := meter.Record("claude", 100, 10); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	m.server.quota = meter
+	if got := m.server.pairingCapability(); got.Mode != CapabilityOffline {
+		t.Errorf("pairing past a blocking hard cap = %+v, want offline", got)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !chaos
+
+package daemon
+
+import "github.com/felixgeelhaar/temper/internal/chaos"
+
+// newChaosInjector leaves fault injection out of regular builds
+func newChaosInjector() *chaos.Injector {
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build chaos
+
+package daemon
+
+import (
+	"log/slog"
+
+	"github.com/felixgeelhaar/temper/internal/chaos"
+)
+
+// newChaosInjector turns on fault injection in daemons built with -tags
+// chaos
+func newChaosInjector() *chaos.Injector {
+	slog.Warn("fault injection enabled: this daemon is for testing only")
+	return chaos.New()
+}
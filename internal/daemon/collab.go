@@ -0,0 +1,332 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClientHeader identifies the editor making a request in a shared
+// session. Writes to a session with participants must come from the driver.
+const ClientHeader = "X-Temper-Client"
+
+// Participant roles in a collaborative session
+const (
+	RoleDriver    = "driver"
+	RoleNavigator = "navigator"
+)
+
+// driverStaleAfter is how long a driver with no open event stream can go
+// without a request before a navigator may take the lock from them
+const driverStaleAfter = 2 * time.Minute
+
+var (
+	errNotParticipant = errors.New("client has not joined the session")
+	errDriverLocked   = errors.New("another participant is driving")
+)
+
+// Participant is one client attached to a collaborative session
+type Participant struct {
+	ClientID string    `json:"client_id"`
+	Name     string    `json:"name,omitempty"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// CollabState is who is in a shared session and who holds the driver lock.
+// Version changes with every update so event streams can tell.
+type CollabState struct {
+	SessionID    string        `json:"session_id"`
+	Driver       string        `json:"driver,omitempty"`
+	Participants []Participant `json:"participants"`
+	Version      int           `json:"version"`
+}
+
+type collabSession struct {
+	driver       string
+	participants map[string]*Participant
+	streams      map[string]int       // open event streams per client
+	seen         map[string]time.Time // last request per client
+	lastWriter   string
+	version      int
+}
+
+// collaboration tracks mob sessions: several clients attached to one
+// session, one of them driving. It is in memory only; a restarted daemon
+// starts every session solo again and clients rejoin.
+type collaboration struct {
+	mu       sync.Mutex
+	sessions map[string]*collabSession
+	seq      int // source of versions, so a session started over never reuses one
+	now      func() time.Time
+}
+
+func newCollaboration() *collaboration {
+	return &collaboration{
+		sessions: make(map[string]*collabSession),
+		now:      time.Now,
+	}
+}
+
+// join adds a client to the session, generating an ID if it has none. The
+// first participant drives; everyone after navigates.
+func (c *collaboration) join(sessionID, clientID, name string) (Participant, CollabState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cs := c.sessions[sessionID]
+	if cs == nil {
+		cs = &collabSession{
+			participants: make(map[string]*Participant),
+			streams:      make(map[string]int),
+			seen:         make(map[string]time.Time),
+		}
+		c.sessions[sessionID] = cs
+	}
+	if clientID == "" {
+		clientID = uuid.New().String()
+	}
+	p := cs.participants[clientID]
+	if p == nil {
+		p = &Participant{ClientID: clientID, Role: RoleNavigator, JoinedAt: c.now()}
+		cs.participants[clientID] = p
+	}
+	if name = strings.TrimSpace(name); name != "" {
+		p.Name = name
+	}
+	cs.seen[clientID] = c.now()
+	if cs.driver == "" {
+		cs.driver = clientID
+	}
+	cs.updateRoles()
+	c.seq++
+	cs.version = c.seq
+	return *p, cs.state(sessionID)
+}
+
+// leave removes a client. A departing driver hands the lock to the
+// longest-attached navigator.
+func (c *collaboration) leave(sessionID, clientID string) (CollabState, bool) {
+	if c == nil {
+		return CollabState{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cs := c.sessions[sessionID]
+	if cs == nil || cs.participants[clientID] == nil {
+		return CollabState{}, false
+	}
+	delete(cs.participants, clientID)
+	delete(cs.streams, clientID)
+	delete(cs.seen, clientID)
+	if len(cs.participants) == 0 {
+		delete(c.sessions, sessionID)
+		c.seq++
+		return CollabState{SessionID: sessionID, Participants: []Participant{}, Version: c.seq}, true
+	}
+	if cs.driver == clientID {
+		cs.driver = cs.ordered()[0].ClientID
+	}
+	cs.updateRoles()
+	c.seq++
+	cs.version = c.seq
+	return cs.state(sessionID), true
+}
+
+// handOver passes the driver lock. The driver can hand it to anyone in the
+// session; a navigator can only take it when nobody is driving or the
+// driver has gone quiet.
+func (c *collaboration) handOver(sessionID, from, to string) (CollabState, error) {
+	if c == nil {
+		return CollabState{}, errNotParticipant
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cs := c.sessions[sessionID]
+	if cs == nil || cs.participants[from] == nil {
+		return CollabState{}, errNotParticipant
+	}
+	if to == "" {
+		to = from
+	}
+	if cs.participants[to] == nil {
+		return CollabState{}, errNotParticipant
+	}
+	cs.seen[from] = c.now()
+	if cs.driver != "" && cs.driver != from && c.present(cs, cs.driver) {
+		return CollabState{}, errDriverLocked
+	}
+	cs.driver = to
+	cs.updateRoles()
+	c.seq++
+	cs.version = c.seq
+	return cs.state(sessionID), nil
+}
+
+// canWrite reports whether the client may change the session's code. Solo
+// sessions accept every write; shared ones only the driver's.
+func (c *collaboration) canWrite(sessionID, clientID string) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cs := c.sessions[sessionID]
+	if cs == nil {
+		return nil
+	}
+	if cs.participants[clientID] == nil {
+		return errNotParticipant
+	}
+	cs.seen[clientID] = c.now()
+	if cs.driver != clientID {
+		return errDriverLocked
+	}
+	return nil
+}
+
+// wrote records the client whose write changed the session's files, so the
+// workspace broadcast can say who made the change
+func (c *collaboration) wrote(sessionID, clientID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cs := c.sessions[sessionID]; cs != nil {
+		cs.lastWriter = clientID
+	}
+}
+
+// lastWriter returns the client behind the latest write, if any
+func (c *collaboration) lastWriter(sessionID string) string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cs := c.sessions[sessionID]; cs != nil {
+		return cs.lastWriter
+	}
+	return ""
+}
+
+// state returns the session's collaboration, or false for a solo session
+func (c *collaboration) state(sessionID string) (CollabState, bool) {
+	if c == nil {
+		return CollabState{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cs := c.sessions[sessionID]
+	if cs == nil {
+		return CollabState{}, false
+	}
+	return cs.state(sessionID), true
+}
+
+// attach counts an open event stream for a participant. It returns a
+// function to call when the stream closes: when a participant's last
+// stream goes away they leave the session, so a crashed editor doesn't
+// hold the driver lock.
+func (c *collaboration) attach(sessionID, clientID string) (detach func() bool) {
+	noop := func() bool { return false }
+	if c == nil || clientID == "" {
+		return noop
+	}
+	c.mu.Lock()
+	cs := c.sessions[sessionID]
+	if cs == nil || cs.participants[clientID] == nil {
+		c.mu.Unlock()
+		return noop
+	}
+	cs.streams[clientID]++
+	c.mu.Unlock()
+
+	return func() bool {
+		c.mu.Lock()
+		cs := c.sessions[sessionID]
+		if cs == nil || cs.streams[clientID] == 0 {
+			c.mu.Unlock()
+			return false
+		}
+		cs.streams[clientID]--
+		last := cs.streams[clientID] == 0
+		c.mu.Unlock()
+		if !last {
+			return false
+		}
+		_, left := c.leave(sessionID, clientID)
+		return left
+	}
+}
+
+// present reports whether a participant still looks connected: they have
+// an event stream open or made a request recently. Closing the last stream
+// already leaves, so this catches a driver who never opened one and whose
+// editor went away without leaving. c.mu must be held.
+func (c *collaboration) present(cs *collabSession, clientID string) bool {
+	if cs.streams[clientID] > 0 {
+		return true
+	}
+	return c.now().Sub(cs.seen[clientID]) < driverStaleAfter
+}
+
+// updateRoles marks the driver; c.mu must be held
+func (cs *collabSession) updateRoles() {
+	for id, p := range cs.participants {
+		if id == cs.driver {
+			p.Role = RoleDriver
+		} else {
+			p.Role = RoleNavigator
+		}
+	}
+}
+
+// ordered lists participants by join time
+func (cs *collabSession) ordered() []Participant {
+	out := make([]Participant, 0, len(cs.participants))
+	for _, p := range cs.participants {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].JoinedAt.Equal(out[j].JoinedAt) {
+			return out[i].ClientID < out[j].ClientID
+		}
+		return out[i].JoinedAt.Before(out[j].JoinedAt)
+	})
+	return out
+}
+
+func (cs *collabSession) state(sessionID string) CollabState {
+	return CollabState{
+		SessionID:    sessionID,
+		Driver:       cs.driver,
+		Participants: cs.ordered(),
+		Version:      cs.version,
+	}
+}
+
+// requireDriver writes a 409 and returns false when the request's client
+// may not change the session's code
+func (s *Server) requireDriver(w http.ResponseWriter, r *http.Request, sessionID string) bool {
+	switch err := s.collab.canWrite(sessionID, r.Header.Get(ClientHeader)); {
+	case errors.Is(err, errNotParticipant):
+		s.jsonErrorCode(w, http.StatusConflict, ErrCodeDriverLocked,
+			"session is shared; join it and take the driver lock before editing", nil)
+		return false
+	case errors.Is(err, errDriverLocked):
+		s.jsonErrorCode(w, http.StatusConflict, ErrCodeDriverLocked,
+			"another participant is driving; ask them to hand over", nil)
+		return false
+	}
+	return true
+}
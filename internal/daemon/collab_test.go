@@ -0,0 +1,148 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCollaboration_JoinAndDriverLock(t *testing.T) {
+	c := newCollaboration()
+	clock := time.Now()
+	c.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+
+	if err := c.canWrite("s1", ""); err != nil {
+		t.Fatalf("solo session should accept writes, got %v", err)
+	}
+
+	ada, _ := c.join("s1", "", "ada")
+	if ada.ClientID == "" || ada.Role != RoleDriver {
+		t.Fatalf("first participant should drive with a generated ID, got %+v", ada)
+	}
+	bob, state := c.join("s1", "bob", "bob")
+	if bob.Role != RoleNavigator || state.Driver != ada.ClientID || len(state.Participants) != 2 {
+		t.Fatalf("unexpected state after second join: %+v", state)
+	}
+
+	if err := c.canWrite("s1", ada.ClientID); err != nil {
+		t.Errorf("driver write rejected: %v", err)
+	}
+	if err := c.canWrite("s1", "bob"); !errors.Is(err, errDriverLocked) {
+		t.Errorf("navigator write = %v, want errDriverLocked", err)
+	}
+	if err := c.canWrite("s1", ""); !errors.Is(err, errNotParticipant) {
+		t.Errorf("anonymous write = %v, want errNotParticipant", err)
+	}
+
+	if _, err := c.handOver("s1", "bob", "bob"); !errors.Is(err, errDriverLocked) {
+		t.Errorf("navigator grabbing the lock = %v, want errDriverLocked", err)
+	}
+	state, err := c.handOver("s1", ada.ClientID, "bob")
+	if err != nil || state.Driver != "bob" {
+		t.Fatalf("hand over: %v %+v", err, state)
+	}
+	if err := c.canWrite("s1", "bob"); err != nil {
+		t.Errorf("new driver write rejected: %v", err)
+	}
+}
+
+func TestCollaboration_LeavePassesLock(t *testing.T) {
+	c := newCollaboration()
+	clock := time.Now()
+	c.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+
+	c.join("s1", "a", "")
+	c.join("s1", "b", "")
+	c.join("s1", "c", "")
+
+	state, ok := c.leave("s1", "a")
+	if !ok || state.Driver != "b" {
+		t.Fatalf("driver should pass to the longest-attached navigator, got %+v", state)
+	}
+	c.leave("s1", "b")
+	last, _ := c.leave("s1", "c")
+	if _, ok := c.state("s1"); ok {
+		t.Error("session should be solo again once everyone left")
+	}
+	if last.Version <= state.Version {
+		t.Errorf("versions must keep increasing: %d after %d", last.Version, state.Version)
+	}
+	if err := c.canWrite("s1", ""); err != nil {
+		t.Errorf("solo session should accept writes again, got %v", err)
+	}
+}
+
+func TestCollaboration_StreamPresence(t *testing.T) {
+	c := newCollaboration()
+	c.join("s1", "a", "")
+	c.join("s1", "b", "")
+
+	first := c.attach("s1", "a")
+	second := c.attach("s1", "a")
+	if first() {
+		t.Error("closing one of two streams should not leave")
+	}
+	if !second() {
+		t.Error("closing the last stream should leave")
+	}
+	state, _ := c.state("s1")
+	if state.Driver != "b" || len(state.Participants) != 1 {
+		t.Errorf("unexpected state after the driver's stream closed: %+v", state)
+	}
+
+	if c.attach("s1", "stranger")() {
+		t.Error("a stream from a non-participant should not affect the session")
+	}
+}
+
+func TestCollaboration_StaleDriverLosesLock(t *testing.T) {
+	c := newCollaboration()
+	clock := time.Now()
+	c.now = func() time.Time { return clock }
+
+	// The driver never opens an event stream and goes away without leaving
+	c.join("s1", "a", "")
+	c.join("s1", "b", "")
+
+	clock = clock.Add(driverStaleAfter / 2)
+	if _, err := c.handOver("s1", "b", ""); !errors.Is(err, errDriverLocked) {
+		t.Fatalf("taking the lock from an active driver = %v, want errDriverLocked", err)
+	}
+
+	// A write keeps the driver present
+	if err := c.canWrite("s1", "a"); err != nil {
+		t.Fatalf("driver write rejected: %v", err)
+	}
+	clock = clock.Add(driverStaleAfter - time.Second)
+	if _, err := c.handOver("s1", "b", ""); !errors.Is(err, errDriverLocked) {
+		t.Fatalf("taking the lock from a recently seen driver = %v, want errDriverLocked", err)
+	}
+
+	clock = clock.Add(2 * time.Second)
+	state, err := c.handOver("s1", "b", "")
+	if err != nil || state.Driver != "b" {
+		t.Fatalf("navigator should take the lock from a stale driver: %v %+v", err, state)
+	}
+	if len(state.Participants) != 2 {
+		t.Errorf("the stale driver should stay on as a navigator, got %+v", state.Participants)
+	}
+	if err := c.canWrite("s1", "a"); !errors.Is(err, errDriverLocked) {
+		t.Errorf("former driver write = %v, want errDriverLocked", err)
+	}
+}
+
+func TestCollaboration_StreamingDriverKeepsLock(t *testing.T) {
+	c := newCollaboration()
+	clock := time.Now()
+	c.now = func() time.Time { return clock }
+
+	c.join("s1", "a", "")
+	c.join("s1", "b", "")
+	detach := c.attach("s1", "a")
+	defer detach()
+
+	clock = clock.Add(2 * driverStaleAfter)
+	if _, err := c.handOver("s1", "b", ""); !errors.Is(err, errDriverLocked) {
+		t.Errorf("taking the lock from a driver with an open stream = %v, want errDriverLocked", err)
+	}
+}
@@ -0,0 +1,132 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/runner"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// crashLogLines is how much of temperd.log a crash report keeps
+const crashLogLines = 50
+
+// ErrAlreadyRunning is returned when another daemon owns the PID file
+var ErrAlreadyRunning = errors.New("daemon already running")
+
+// checkPreviousRun inspects the PID file left by an earlier daemon. A clean
+// shutdown removes it, so a leftover file whose process is gone (or no
+// longer answers health checks) means that daemon crashed.
+func checkPreviousRun(pidPath string, healthy func() bool) (prevPID int, crashed bool, err error) {
+	data, err := os.ReadFile(pidPath)
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("read pid file: %w", err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid == os.Getpid() {
+		// Unreadable or our own: nothing to recover from
+		return 0, false, nil
+	}
+	if ProcessAlive(pid) && healthy() {
+		return pid, false, fmt.Errorf("%w (pid %d)", ErrAlreadyRunning, pid)
+	}
+	return pid, true, nil
+}
+
+// writeCrashReport records a crashed daemon in logs/crash-<time>.log,
+// together with the end of its log, and returns the report's path.
+func writeCrashReport(temperDir string, prevPID int, detected time.Time) (string, error) {
+	logsDir := filepath.Join(temperDir, "logs")
+	lines, err := tailLines(filepath.Join(logsDir, "temperd.log"), crashLogLines)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("read daemon log: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "temperd crash report\n\n")
+	fmt.Fprintf(&b, "Previous PID: %d\n", prevPID)
+	fmt.Fprintf(&b, "Detected:     %s\n", detected.Format(time.RFC3339))
+	fmt.Fprintf(&b, "\nLast %d log lines:\n", len(lines))
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	path := filepath.Join(logsDir, "crash-"+detected.Format("20060102-150405")+".log")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return "", fmt.Errorf("write crash report: %w", err)
+	}
+	return path, nil
+}
+
+// tailLines returns the last n lines of the file at path
+func tailLines(path string, n int) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// healthCheck reports whether a daemon answers on addr
+func healthCheck(addr string) func() bool {
+	return func() bool {
+		client := &http.Client{Timeout: 2 * time.Second}
+		resp, err := client.Get("http://" + addr + "/v1/health")
+		if err != nil {
+			return false
+		}
+		defer func() { _ = resp.Body.Close() }()
+		return resp.StatusCode == http.StatusOK
+	}
+}
+
+// recoverAfterCrash runs once the server is built after a crash. Sessions
+// live in the store, so they are picked up as-is; runner containers the
+// crashed daemon left behind are removed.
+func (s *Server) recoverAfterCrash(ctx context.Context) {
+	if sessions, err := s.sessionService.List(ctx); err != nil {
+		slog.Warn("list sessions after crash", "error", err)
+	} else {
+		active := 0
+		for _, sess := range sessions {
+			if sess.Status == session.StatusActive {
+				active++
+			}
+		}
+		slog.Info("sessions recovered after crash", "active", active)
+	}
+
+	if docker, ok := s.runnerExecutor.(*runner.DockerExecutor); ok {
+		removed, err := docker.RemoveOrphans(ctx)
+		if err != nil {
+			slog.Warn("remove orphaned runner containers", "error", err)
+		} else if removed > 0 {
+			slog.Info("removed orphaned runner containers", "count", removed)
+		}
+	}
+}
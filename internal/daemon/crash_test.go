@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckPreviousRun(t *testing.T) {
+	dir := t.TempDir()
+	pidPath := filepath.Join(dir, pidFileName)
+	healthy := func() bool { return true }
+
+	if _, crashed, err := checkPreviousRun(pidPath, healthy); err != nil || crashed {
+		t.Fatalf("no pid file: crashed = %v, err = %v", crashed, err)
+	}
+
+	// The parent of the test process is alive and not us
+	live := os.Getppid()
+	writePID := func(pid int) {
+		t.Helper()
+		if err := os.WriteFile(pidPath, []byte(fmt.Sprintf("%d\n", pid)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writePID(live)
+	if _, _, err := checkPreviousRun(pidPath, healthy); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("live healthy daemon: err = %v, want ErrAlreadyRunning", err)
+	}
+	if pid, crashed, err := checkPreviousRun(pidPath, func() bool { return false }); err != nil || !crashed || pid != live {
+		t.Errorf("live unhealthy daemon: pid = %d, crashed = %v, err = %v", pid, crashed, err)
+	}
+
+	writePID(os.Getpid())
+	if _, crashed, err := checkPreviousRun(pidPath, healthy); err != nil || crashed {
+		t.Errorf("own pid: crashed = %v, err = %v", crashed, err)
+	}
+}
+
+func TestWriteCrashReport(t *testing.T) {
+	dir := t.TempDir()
+	logsDir := filepath.Join(dir, "logs")
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var log strings.Builder
+	for i := 1; i <= crashLogLines+10; i++ {
+		fmt.Fprintf(&log, "line %d\n", i)
+	}
+	if err := os.WriteFile(filepath.Join(logsDir, "temperd.log"), []byte(log.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	detected := time.Date(2026, 3, 1, 12, 30, 45, 0, time.UTC)
+	path, err := writeCrashReport(dir, 4242, detected)
+	if err != nil {
+		t.Fatalf("writeCrashReport() error = %v", err)
+	}
+	if want := filepath.Join(logsDir, "crash-20260301-123045.log"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(data)
+	for _, want := range []string{"Previous PID: 4242", "2026-03-01T12:30:45Z", "line 11\n", "line 60\n"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "line 10\n") {
+		t.Errorf("report should keep only the last %d lines", crashLogLines)
+	}
+}
+
+func TestWriteCrashReport_NoLog(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "logs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writeCrashReport(dir, 1, time.Now()); err != nil {
+		t.Errorf("writeCrashReport() without a log error = %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+)
+
+// Ephemeral is a daemon served from the calling process for the length of
+// one command, e.g. `temper ci verify` in a pipeline. It keeps its state in
+// a temporary directory, never reads ~/.temper and leaves no process
+// behind.
+type Ephemeral struct {
+	// Addr is the bound address, host:port
+	Addr string
+	// Token is the bearer token requests must send
+	Token string
+
+	server  *Server
+	dataDir string
+	done    chan error
+}
+
+// StartEphemeral serves the API for the specs in workspace on a free
+// loopback port. It needs neither LLM providers nor Docker, so LLM and
+// runner requests get the canned responses of temper mockd.
+func StartEphemeral(ctx context.Context, workspace string) (*Ephemeral, error) {
+	dataDir, err := os.MkdirTemp("", "temper-ephemeral-*")
+	if err != nil {
+		return nil, fmt.Errorf("create data dir: %w", err)
+	}
+
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		_ = os.RemoveAll(dataDir)
+		return nil, fmt.Errorf("generate token: %w", err)
+	}
+
+	cfg := config.DefaultLocalConfig()
+	cfg.Daemon.Bind = "127.0.0.1"
+	cfg.Daemon.Port = 0
+	cfg.Daemon.AuthToken = hex.EncodeToString(tokenBytes)
+
+	server, err := NewServer(ctx, ServerConfig{
+		Config:       cfg,
+		ExercisePath: filepath.Join(dataDir, "exercises"),
+		SpecsPath:    workspace,
+		DataDir:      dataDir,
+		Mock:         true,
+	})
+	if err != nil {
+		_ = os.RemoveAll(dataDir)
+		return nil, fmt.Errorf("create server: %w", err)
+	}
+	addr, err := server.Listen()
+	if err != nil {
+		_ = os.RemoveAll(dataDir)
+		return nil, err
+	}
+
+	e := &Ephemeral{
+		Addr:    addr,
+		Token:   cfg.Daemon.AuthToken,
+		server:  server,
+		dataDir: dataDir,
+		done:    make(chan error, 1),
+	}
+	go func() { e.done <- server.Start() }()
+	return e, nil
+}
+
+// Close stops the daemon and removes its data
+func (e *Ephemeral) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := e.server.Shutdown(ctx)
+	if serveErr := <-e.done; serveErr != nil && serveErr != http.ErrServerClosed && err == nil {
+		err = serveErr
+	}
+	_ = os.RemoveAll(e.dataDir)
+	return err
+}
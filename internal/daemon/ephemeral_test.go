@@ -0,0 +1,87 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const ephemeralTestSpec = `name: Auth
+version: 1.0.0
+goals:
+  - Implement user authentication
+features:
+  - id: login
+    title: Login
+    description: User can log in
+    priority: high
+    success_criteria:
+      - User can log in
+acceptance_criteria:
+  - id: ac-1
+    description: User can log in with valid credentials
+    satisfied: true
+`
+
+func TestEphemeral_Verify(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(workspace, ".specs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workspace, ".specs", "auth.yaml"), []byte(ephemeralTestSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := StartEphemeral(context.Background(), workspace)
+	if err != nil {
+		t.Fatalf("StartEphemeral() error = %v", err)
+	}
+
+	post := func(token string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, "http://"+e.Addr+"/v1/specs/verify", strings.NewReader(`{}`))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST /v1/specs/verify: %v", err)
+		}
+		return resp
+	}
+
+	resp := post("")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("without token: got %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	resp = post(e.Token)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var out struct {
+		Passed bool `json:"passed"`
+		Specs  []struct {
+			SpecPath string   `json:"spec_path"`
+			Failures []string `json:"failures"`
+		} `json:"specs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Passed || len(out.Specs) != 1 || len(out.Specs[0].Failures) != 1 {
+		t.Errorf("verify = %+v; want the criterion without evidence to fail", out)
+	}
+
+	if err := e.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, err := http.Get("http://" + e.Addr + "/v1/health"); err == nil {
+		t.Error("daemon still serving after Close()")
+	}
+}
@@ -1,6 +1,20 @@
 package daemon
 
-import "net/http"
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/cohort"
+	"github.com/felixgeelhaar/temper/internal/correlation"
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/felixgeelhaar/temper/internal/patch"
+	"github.com/felixgeelhaar/temper/internal/quota"
+	"github.com/felixgeelhaar/temper/internal/sandbox"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/felixgeelhaar/temper/internal/spec"
+)
 
 // Stable error codes returned in the daemon's JSON error responses.
 // Editor clients switch on these, never on message strings.
@@ -10,16 +24,24 @@ import "net/http"
 // clients.
 const (
 	// 400 Bad Request
-	ErrCodeBadRequest      = "BAD_REQUEST"
-	ErrCodeInvalidPayload  = "INVALID_PAYLOAD"
-	ErrCodeSpecInvalid     = "SPEC_INVALID"
-	ErrCodeFileTooLarge    = "FILE_TOO_LARGE"
-	ErrCodeTooManyFiles    = "TOO_MANY_FILES"
-	ErrCodePayloadTooLarge = "PAYLOAD_TOO_LARGE"
+	ErrCodeBadRequest       = "BAD_REQUEST"
+	ErrCodeInvalidPayload   = "INVALID_PAYLOAD"
+	ErrCodeSpecInvalid      = "SPEC_INVALID"
+	ErrCodeFileTooLarge     = "FILE_TOO_LARGE"
+	ErrCodeTooManyFiles     = "TOO_MANY_FILES"
+	ErrCodePayloadTooLarge  = "PAYLOAD_TOO_LARGE"
+	ErrCodeSessionNotActive = "SESSION_NOT_ACTIVE"
+	ErrCodeWrongSessionKind = "WRONG_SESSION_KIND"
+	ErrCodeInvalidPath      = "INVALID_PATH"
+	ErrCodeValidationFailed = "VALIDATION_FAILED"
 
 	// 401 Unauthorized / 403 Forbidden
-	ErrCodeUnauthorized = "UNAUTHORIZED"
-	ErrCodeForbidden    = "FORBIDDEN"
+	ErrCodeUnauthorized  = "UNAUTHORIZED"
+	ErrCodeForbidden     = "FORBIDDEN"
+	ErrCodeForbiddenHost = "FORBIDDEN_HOST"
+
+	ErrCodeHintBudgetExhausted = "HINT_BUDGET_EXHAUSTED"
+	ErrCodeDeadlinePassed      = "DEADLINE_PASSED"
 
 	// 404 Not Found
 	ErrCodeNotFound          = "NOT_FOUND"
@@ -30,11 +52,18 @@ const (
 	ErrCodeTrackNotFound     = "TRACK_NOT_FOUND"
 	ErrCodeSandboxNotFound   = "SANDBOX_NOT_FOUND"
 	ErrCodePatchNotFound     = "PATCH_NOT_FOUND"
+	ErrCodeProviderNotFound  = "PROVIDER_NOT_FOUND"
+	ErrCodeCohortNotFound    = "COHORT_NOT_FOUND"
 
 	// 409 Conflict
 	ErrCodeConflict          = "CONFLICT"
 	ErrCodeSessionConflict   = "SESSION_CONFLICT"
 	ErrCodeSandboxLimitHit   = "SANDBOX_LIMIT_REACHED"
+	ErrCodeWorkspaceConflict = "WORKSPACE_CONFLICT"
+	ErrCodePatchResolved     = "PATCH_RESOLVED"
+	ErrCodeSandboxNotReady   = "SANDBOX_NOT_READY"
+	ErrCodeDriverLocked      = "DRIVER_LOCKED"
+	ErrCodeExerciseOutdated  = "EXERCISE_OUTDATED"
 
 	// 410 Gone
 	ErrCodeSandboxExpired = "SANDBOX_EXPIRED"
@@ -44,7 +73,9 @@ const (
 	ErrCodeUnprocessable = "UNPROCESSABLE"
 
 	// 429 Too Many Requests
-	ErrCodeRateLimited = "RATE_LIMITED"
+	ErrCodeRateLimited    = "RATE_LIMITED"
+	ErrCodeCooldownActive = "COOLDOWN_ACTIVE"
+	ErrCodeQuotaExhausted = "QUOTA_EXHAUSTED"
 
 	// 500 Internal Server Error
 	ErrCodeInternal       = "INTERNAL_ERROR"
@@ -53,6 +84,9 @@ const (
 	// 503 Service Unavailable
 	ErrCodeServiceUnavailable = "SERVICE_UNAVAILABLE"
 	ErrCodeLLMUnavailable     = "LLM_UNAVAILABLE"
+
+	// 504 Gateway Timeout
+	ErrCodeLLMTimeout = "LLM_TIMEOUT"
 )
 
 // defaultErrorCodeForStatus maps an HTTP status to a fallback error code.
@@ -80,7 +114,109 @@ func defaultErrorCodeForStatus(status int) string {
 		return ErrCodeRateLimited
 	case http.StatusServiceUnavailable:
 		return ErrCodeServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return ErrCodeLLMTimeout
 	default:
 		return ErrCodeInternal
 	}
 }
+
+// writeError writes the error envelope every failed request gets:
+//
+//	{"error": message, "error_code": code, "status": status,
+//	 "details": err.Error(), "correlation_id": "..."}
+//
+// Validation failures add "fields", one entry per invalid field.
+//
+// A generic code is narrowed to the one registered for err, if any, and
+// correlation_id ties the response to the daemon log.
+func writeError(w http.ResponseWriter, status int, code, message string, err error) {
+	if code == "" {
+		code = defaultErrorCodeForStatus(status)
+	}
+	response := map[string]interface{}{
+		"error":      message,
+		"error_code": code,
+		"status":     status,
+	}
+	addCorrelationID(w, response)
+	if err != nil {
+		// If the underlying error already carries a payload-specific code,
+		// surface it as the authoritative code; payload validators set
+		// these.
+		if pe := asPayloadError(err); pe != nil {
+			response["error_code"] = pe.Code
+		} else if registered := errorCodeFor(err); registered != "" && code == defaultErrorCodeForStatus(status) {
+			response["error_code"] = registered
+		}
+		response["details"] = err.Error()
+
+		var ve *ValidationError
+		if errors.As(err, &ve) {
+			response["fields"] = ve.Fields
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("failed to encode error response", "error", err)
+	}
+}
+
+// addCorrelationID copies the request's correlation ID, set on the
+// response by correlationIDMiddleware, into an error body
+func addCorrelationID(w http.ResponseWriter, response map[string]interface{}) {
+	if id := w.Header().Get(correlation.HeaderName); id != "" {
+		response["correlation_id"] = id
+	}
+}
+
+// errorCodes maps domain errors to their codes. jsonError consults it, so a
+// handler passing the underlying error gets a specific code without naming
+// one.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{session.ErrSessionNotFound, ErrCodeSessionNotFound},
+	{session.ErrNotFound, ErrCodeSessionNotFound},
+	{session.ErrExerciseNotFound, ErrCodeExerciseNotFound},
+	{session.ErrCooldownActive, ErrCodeCooldownActive},
+	{session.ErrSessionNotActive, ErrCodeSessionNotActive},
+	{session.ErrSpecInvalid, ErrCodeSpecInvalid},
+	{session.ErrNotAuthoring, ErrCodeWrongSessionKind},
+	{session.ErrNotDebugging, ErrCodeWrongSessionKind},
+	{session.ErrWorkspaceConflict, ErrCodeWorkspaceConflict},
+	{session.ErrInvalidPath, ErrCodeInvalidPath},
+	{session.ErrExerciseOutdated, ErrCodeExerciseOutdated},
+	{session.ErrDeadlinePassed, ErrCodeDeadlinePassed},
+	{spec.ErrSpecNotFound, ErrCodeSpecNotFound},
+	{spec.ErrSpecInvalid, ErrCodeSpecInvalid},
+	{spec.ErrCriterionNotFound, ErrCodeCriterionNotFound},
+	{spec.ErrInvalidPath, ErrCodeInvalidPath},
+	{sandbox.ErrSandboxNotFound, ErrCodeSandboxNotFound},
+	{sandbox.ErrSandboxExpired, ErrCodeSandboxExpired},
+	{sandbox.ErrSandboxNotReady, ErrCodeSandboxNotReady},
+	{sandbox.ErrMaxSandboxes, ErrCodeSandboxLimitHit},
+	{sandbox.ErrSessionHasSandbox, ErrCodeConflict},
+	{patch.ErrPatchNotFound, ErrCodePatchNotFound},
+	{patch.ErrPatchExpired, ErrCodePatchExpired},
+	{patch.ErrPatchApplied, ErrCodePatchResolved},
+	{patch.ErrPatchRejected, ErrCodePatchResolved},
+	{llm.ErrProviderNotFound, ErrCodeProviderNotFound},
+	{llm.ErrNoDefaultProvider, ErrCodeLLMUnavailable},
+	{llm.ErrTimeout, ErrCodeLLMTimeout},
+	{quota.ErrExhausted, ErrCodeQuotaExhausted},
+	{cohort.ErrNotFound, ErrCodeCohortNotFound},
+}
+
+// errorCodeFor returns the code registered for err, or "" if none is
+func errorCodeFor(err error) string {
+	for _, e := range errorCodes {
+		if errors.Is(err, e.err) {
+			return e.code
+		}
+	}
+	return ""
+}
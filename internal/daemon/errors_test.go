@@ -3,9 +3,13 @@ package daemon
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/correlation"
+	"github.com/felixgeelhaar/temper/internal/session"
 )
 
 func TestDefaultErrorCodeForStatus(t *testing.T) {
@@ -100,3 +104,63 @@ func TestJsonErrorCode_PassesDetailsAlong(t *testing.T) {
 			body["error_code"], ErrCodeInternal)
 	}
 }
+
+func TestJsonError_RegisteredDomainError(t *testing.T) {
+	s := &Server{}
+	rec := httptest.NewRecorder()
+
+	wrapped := fmt.Errorf("load: %w", session.ErrCooldownActive)
+	s.jsonError(rec, http.StatusTooManyRequests, "slow down", wrapped)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body["error_code"] != ErrCodeCooldownActive {
+		t.Errorf("error_code = %v, want %s", body["error_code"], ErrCodeCooldownActive)
+	}
+
+	// An explicit, specific code is not overridden by the registry
+	rec = httptest.NewRecorder()
+	s.jsonErrorCode(rec, http.StatusNotFound, ErrCodeExerciseNotFound, "missing", session.ErrSessionNotFound)
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body["error_code"] != ErrCodeExerciseNotFound {
+		t.Errorf("error_code = %v, want %s", body["error_code"], ErrCodeExerciseNotFound)
+	}
+}
+
+func TestJsonError_IncludesCorrelationID(t *testing.T) {
+	handler := correlationIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		(&Server{}).jsonError(w, http.StatusBadRequest, "bad", nil)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/anything", nil)
+	req.Header.Set(correlation.HeaderName, "req-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body["correlation_id"] != "req-123" {
+		t.Errorf("correlation_id = %v, want req-123", body["correlation_id"])
+	}
+}
+
+func TestAuthMiddleware_ErrorEnvelope(t *testing.T) {
+	handler := authMiddleware("secret", nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/status", nil))
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body["error_code"] != ErrCodeUnauthorized || body["error"] != "missing bearer token" {
+		t.Errorf("body = %v, want UNAUTHORIZED envelope", body)
+	}
+}
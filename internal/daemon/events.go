@@ -0,0 +1,509 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/achievement"
+	"github.com/felixgeelhaar/temper/internal/exercise"
+	"github.com/felixgeelhaar/temper/internal/quota"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// eventsKeepAlive is how often an idle event stream sends a comment so
+// proxies and editors don't drop the connection
+const eventsKeepAlive = 30 * time.Second
+
+// sessionEvents tells open event streams that a session changed. Streams
+// reload the session themselves, so a notification carries no payload and
+// a missed one is harmless. Stuck nudges aren't part of the session, so
+// the latest one per session is kept here for streams to pick up, and so
+// is the latest intervention, which streams share with every participant,
+// and the latest lifecycle event.
+// Achievements unlocked in the session are kept the same way; each
+// unlocks once, so there are never more than a handful. Provider quota
+// alerts and staged pack updates concern every session and go to all
+// streams.
+type sessionEvents struct {
+	mu            sync.Mutex
+	subs          map[string]map[chan struct{}]struct{}
+	nudges        map[string]session.Nudge
+	interventions map[string]session.Intervention
+	lifecycles    map[string]session.LifecycleEvent
+	achievements  map[string][]achievement.Unlock
+	quotaAlerts   []quota.Alert
+	packUpdates   []exercise.Update
+}
+
+// maxQuotaAlerts bounds the quota alerts kept for streams to pick up, and
+// likewise the pack updates
+const maxQuotaAlerts = 10
+
+func newSessionEvents() *sessionEvents {
+	return &sessionEvents{
+		subs:          make(map[string]map[chan struct{}]struct{}),
+		nudges:        make(map[string]session.Nudge),
+		interventions: make(map[string]session.Intervention),
+		lifecycles:    make(map[string]session.LifecycleEvent),
+		achievements:  make(map[string][]achievement.Unlock),
+	}
+}
+
+// subscribe returns a channel notified when the session changes, and a
+// function that unsubscribes
+func (e *sessionEvents) subscribe(sessionID string) (<-chan struct{}, func()) {
+	if e == nil {
+		return nil, func() {}
+	}
+	ch := make(chan struct{}, 1)
+
+	e.mu.Lock()
+	if e.subs[sessionID] == nil {
+		e.subs[sessionID] = make(map[chan struct{}]struct{})
+	}
+	e.subs[sessionID][ch] = struct{}{}
+	e.mu.Unlock()
+
+	return ch, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		delete(e.subs[sessionID], ch)
+		if len(e.subs[sessionID]) == 0 {
+			delete(e.subs, sessionID)
+		}
+	}
+}
+
+// notify wakes every stream subscribed to the session
+func (e *sessionEvents) notify(sessionID string) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.wake(sessionID)
+}
+
+// nudge records a stuck nudge and wakes the session's streams. Nobody may
+// be listening; the nudge is then dropped when the next one replaces it.
+func (e *sessionEvents) nudge(n session.Nudge) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.nudges[n.SessionID] = n
+	e.wake(n.SessionID)
+}
+
+// latestNudge returns the session's most recent stuck nudge
+func (e *sessionEvents) latestNudge(sessionID string) (session.Nudge, bool) {
+	if e == nil {
+		return session.Nudge{}, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n, ok := e.nudges[sessionID]
+	return n, ok
+}
+
+// intervention records an intervention given in the session and wakes
+// its streams, so everyone attached to a shared session sees it
+func (e *sessionEvents) intervention(iv session.Intervention) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.interventions[iv.SessionID] = iv
+	e.wake(iv.SessionID)
+}
+
+// latestIntervention returns the session's most recent intervention
+func (e *sessionEvents) latestIntervention(sessionID string) (session.Intervention, bool) {
+	if e == nil {
+		return session.Intervention{}, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	iv, ok := e.interventions[sessionID]
+	return iv, ok
+}
+
+// lifecycle records a session going idle, resuming or expiring and wakes
+// its streams
+func (e *sessionEvents) lifecycle(ev session.LifecycleEvent) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lifecycles[ev.SessionID] = ev
+	e.wake(ev.SessionID)
+}
+
+// latestLifecycle returns the session's most recent lifecycle event
+func (e *sessionEvents) latestLifecycle(sessionID string) (session.LifecycleEvent, bool) {
+	if e == nil {
+		return session.LifecycleEvent{}, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ev, ok := e.lifecycles[sessionID]
+	return ev, ok
+}
+
+// achievement records an achievement unlocked in a session and wakes its
+// streams
+func (e *sessionEvents) achievement(u achievement.Unlock) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.achievements[u.SessionID] = append(e.achievements[u.SessionID], u)
+	e.wake(u.SessionID)
+}
+
+// quotaAlert records a provider reaching a usage cap and wakes every
+// stream
+func (e *sessionEvents) quotaAlert(a quota.Alert) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.quotaAlerts = append(e.quotaAlerts, a)
+	if len(e.quotaAlerts) > maxQuotaAlerts {
+		e.quotaAlerts = e.quotaAlerts[len(e.quotaAlerts)-maxQuotaAlerts:]
+	}
+	for sessionID := range e.subs {
+		e.wake(sessionID)
+	}
+}
+
+// quotaAlertsSince returns the quota alerts raised after t
+func (e *sessionEvents) quotaAlertsSince(t time.Time) []quota.Alert {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var since []quota.Alert
+	for _, a := range e.quotaAlerts {
+		if a.At.After(t) {
+			since = append(since, a)
+		}
+	}
+	return since
+}
+
+// packUpdate records a pack update staged in the background and wakes
+// every stream
+func (e *sessionEvents) packUpdate(u exercise.Update) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.packUpdates = append(e.packUpdates, u)
+	if len(e.packUpdates) > maxQuotaAlerts {
+		e.packUpdates = e.packUpdates[len(e.packUpdates)-maxQuotaAlerts:]
+	}
+	for sessionID := range e.subs {
+		e.wake(sessionID)
+	}
+}
+
+// packUpdatesSince returns the pack updates staged after t
+func (e *sessionEvents) packUpdatesSince(t time.Time) []exercise.Update {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var since []exercise.Update
+	for _, u := range e.packUpdates {
+		if u.StagedAt.After(t) {
+			since = append(since, u)
+		}
+	}
+	return since
+}
+
+// forget drops what is kept for the given sessions' streams, or for
+// every session when sessionIDs is nil
+func (e *sessionEvents) forget(sessionIDs map[string]bool) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for id := range e.nudges {
+		if sessionIDs == nil || sessionIDs[id] {
+			delete(e.nudges, id)
+		}
+	}
+	for id := range e.interventions {
+		if sessionIDs == nil || sessionIDs[id] {
+			delete(e.interventions, id)
+		}
+	}
+	for id := range e.lifecycles {
+		if sessionIDs == nil || sessionIDs[id] {
+			delete(e.lifecycles, id)
+		}
+	}
+	for id := range e.achievements {
+		if sessionIDs == nil || sessionIDs[id] {
+			delete(e.achievements, id)
+		}
+	}
+}
+
+// achievementsSince returns the session's achievements unlocked after t
+func (e *sessionEvents) achievementsSince(sessionID string, t time.Time) []achievement.Unlock {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var since []achievement.Unlock
+	for _, u := range e.achievements[sessionID] {
+		if u.At.After(t) {
+			since = append(since, u)
+		}
+	}
+	return since
+}
+
+// wake signals the session's subscribers; e.mu must be held
+func (e *sessionEvents) wake(sessionID string) {
+	for ch := range e.subs[sessionID] {
+		select {
+		case ch <- struct{}{}:
+		default: // already pending
+		}
+	}
+}
+
+// handleSessionEvents streams a session's cooldown as server-sent events:
+// "cooldown" with the current state on connect, "cooldown_started" when an
+// intervention starts a new one and "cooldown_finished" when it ends, so
+// editors can show a timer instead of waiting on a 429; "cooldown" is sent
+// again when the cooldown pauses or resumes with the session's idleness.
+// "nudge" carries a stuck nudge raised while the stream is open,
+// "lifecycle" the session going idle, resuming or expiring, "achievement"
+// an achievement the session's runs unlock, "quota" an LLM provider
+// reaching its monthly soft or hard cap and "pack_update" a new version
+// of an installed pack, staged and waiting for the user to apply it.
+//
+// For mob mode it also carries "intervention" for each new intervention,
+// "workspace" with the new manifest when the files change and "collab"
+// when participants or the driver change. A participant passes its client
+// ID as ?client= (or X-Temper-Client) and leaves when its last stream
+// closes.
+func (s *Server) handleSessionEvents(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	sess, err := s.sessionService.Get(r.Context(), id)
+	if err != nil {
+		if err == session.ErrSessionNotFound {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+			return
+		}
+		s.jsonError(w, http.StatusInternalServerError, "failed to get session", err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.jsonError(w, http.StatusInternalServerError, "streaming not supported", nil)
+		return
+	}
+
+	updates, unsubscribe := s.events.subscribe(id)
+	defer unsubscribe()
+
+	clientID := r.URL.Query().Get("client")
+	if clientID == "" {
+		clientID = r.Header.Get(ClientHeader)
+	}
+	detach := s.collab.attach(id, clientID)
+	defer func() {
+		if detach() {
+			s.events.notify(id)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	send := func(event string, payload interface{}) {
+		data, _ := json.Marshal(payload)
+		writeSSEEvent(w, event, string(data))
+		flusher.Flush()
+	}
+
+	var timer *time.Timer
+	var finished <-chan time.Time
+	arm := func(state session.CooldownState) {
+		if timer != nil {
+			timer.Stop()
+		}
+		finished = nil
+		if state.Active && !state.Paused {
+			timer = time.NewTimer(time.Until(*state.EndsAt))
+			finished = timer.C
+		}
+	}
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	state := sess.Cooldown()
+	send("cooldown", state)
+	arm(state)
+
+	// Only nudges and lifecycle events raised after connecting are sent
+	var nudgedAt, lifecycleAt time.Time
+	if n, ok := s.events.latestNudge(id); ok {
+		nudgedAt = n.At
+	}
+	if ev, ok := s.events.latestLifecycle(id); ok {
+		lifecycleAt = ev.At
+	}
+
+	// Likewise interventions and file changes; collaboration state is
+	// sent up front so a joining client knows who is driving
+	var intervenedAt time.Time
+	if iv, ok := s.events.latestIntervention(id); ok {
+		intervenedAt = iv.CreatedAt
+	}
+	achievedAt := time.Now()
+	alertedAt := achievedAt
+	stagedAt := achievedAt
+	workspace := session.ManifestOf(sess.Code).Version
+	collabVersion := 0
+	if cs, ok := s.collab.state(id); ok {
+		collabVersion = cs.Version
+		send("collab", cs)
+	}
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-updates:
+			if n, ok := s.events.latestNudge(id); ok && n.At.After(nudgedAt) {
+				nudgedAt = n.At
+				send("nudge", n)
+			}
+			if ev, ok := s.events.latestLifecycle(id); ok && ev.At.After(lifecycleAt) {
+				lifecycleAt = ev.At
+				send("lifecycle", ev)
+			}
+			if iv, ok := s.events.latestIntervention(id); ok && iv.CreatedAt.After(intervenedAt) {
+				intervenedAt = iv.CreatedAt
+				send("intervention", iv)
+			}
+			for _, u := range s.events.achievementsSince(id, achievedAt) {
+				achievedAt = u.At
+				send("achievement", u)
+			}
+			for _, a := range s.events.quotaAlertsSince(alertedAt) {
+				alertedAt = a.At
+				send("quota", a)
+			}
+			for _, u := range s.events.packUpdatesSince(stagedAt) {
+				stagedAt = u.StagedAt
+				send("pack_update", u)
+			}
+			sess, err := s.sessionService.Get(r.Context(), id)
+			if err != nil {
+				return // deleted
+			}
+			if manifest := session.ManifestOf(sess.Code); manifest.Version != workspace {
+				workspace = manifest.Version
+				send("workspace", map[string]interface{}{
+					"version": manifest.Version,
+					"files":   manifest.Files,
+					"by":      s.collab.lastWriter(id),
+				})
+			}
+			if cs, ok := s.collab.state(id); ok && cs.Version != collabVersion {
+				collabVersion = cs.Version
+				send("collab", cs)
+			} else if !ok && collabVersion != 0 {
+				collabVersion = 0
+				send("collab", CollabState{SessionID: id, Participants: []Participant{}})
+			}
+			next := sess.Cooldown()
+			switch {
+			case next.Paused != state.Paused:
+				send("cooldown", next)
+				arm(next)
+			case next.Active && !next.Paused && (!state.Active || !next.EndsAt.Equal(*state.EndsAt)):
+				send("cooldown_started", next)
+				arm(next)
+			}
+			state = next
+		case <-finished:
+			finished = nil
+			state = session.CooldownState{CooldownSeconds: state.CooldownSeconds, NextLevel: state.NextLevel}
+			send("cooldown_finished", state)
+		case <-keepAlive.C:
+			// A failed write means the client is gone; returning detaches
+			// it so a dead editor doesn't keep the driver lock
+			if _, err := w.Write([]byte(": keep-alive\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// handleGetCooldown returns the session's cooldown state
+func (s *Server) handleGetCooldown(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.sessionService.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if err == session.ErrSessionNotFound {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+			return
+		}
+		s.jsonError(w, http.StatusInternalServerError, "failed to get session", err)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, sess.Cooldown())
+}
+
+// handleHeartbeat records that an editor has the session open, resuming
+// it if it went idle. Editors send one every minute or so while the
+// learner works; the response is the session's cooldown, which resuming
+// may have restarted.
+func (s *Server) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.sessionService.Heartbeat(r.Context(), r.PathValue("id"))
+	if err != nil {
+		switch err {
+		case session.ErrSessionNotFound:
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+		case session.ErrSessionNotActive:
+			s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeSessionNotActive, "session is not active", nil)
+		default:
+			s.jsonError(w, http.StatusInternalServerError, "failed to record heartbeat", err)
+		}
+		return
+	}
+	s.events.notify(sess.ID)
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"status":   sess.Status,
+		"cooldown": sess.Cooldown(),
+	})
+}
@@ -0,0 +1,280 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func coolingSession(last time.Time) *session.Session {
+	return &session.Session{
+		ID:                 "sess-1",
+		Status:             session.StatusActive,
+		Policy:             domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet, CooldownSeconds: 1},
+		LastInterventionAt: &last,
+	}
+}
+
+func TestSessionEvents_Notify(t *testing.T) {
+	events := newSessionEvents()
+	ch, unsubscribe := events.subscribe("a")
+
+	events.notify("b")
+	select {
+	case <-ch:
+		t.Fatal("notified for another session")
+	default:
+	}
+
+	events.notify("a")
+	events.notify("a") // coalesced, must not block
+	select {
+	case <-ch:
+	default:
+		t.Fatal("expected a notification")
+	}
+
+	unsubscribe()
+	if len(events.subs) != 0 {
+		t.Errorf("unsubscribe should drop the session, got %v", events.subs)
+	}
+
+	var none *sessionEvents
+	none.notify("a") // nil hub is a no-op
+}
+
+func TestHandleGetCooldown(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		if id != "sess-1" {
+			return nil, session.ErrSessionNotFound
+		}
+		return coolingSession(time.Now()), nil
+	}
+
+	for _, path := range []string{"/v1/sessions/sess-1/cooldown", "/v1/sessions/sess-1"} {
+		w := httptest.NewRecorder()
+		m.server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", path, w.Code, w.Body.String())
+		}
+
+		var state session.CooldownState
+		body := w.Body.Bytes()
+		if path == "/v1/sessions/sess-1" {
+			var resp struct {
+				ID       string                `json:"id"`
+				Cooldown session.CooldownState `json:"cooldown"`
+			}
+			if err := json.Unmarshal(body, &resp); err != nil || resp.ID != "sess-1" {
+				t.Fatalf("decode session: %v %s", err, body)
+			}
+			state = resp.Cooldown
+		} else if err := json.Unmarshal(body, &state); err != nil {
+			t.Fatalf("decode cooldown: %v", err)
+		}
+
+		if !state.Active || state.RemainingSeconds <= 0 || state.EndsAt == nil || state.NextLevel != domain.L3ConstrainedSnippet {
+			t.Errorf("%s: unexpected cooldown %+v", path, state)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/sessions/nope/cooldown", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown session: status %d", w.Code)
+	}
+}
+
+func TestHandleSessionEvents_Countdown(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.events = newSessionEvents()
+
+	last := time.Now().Add(-700 * time.Millisecond)
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return coolingSession(last), nil
+	}
+
+	ts := httptest.NewServer(m.server.router)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v1/sessions/sess-1/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var events []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "event: ") {
+			continue
+		}
+		event := strings.TrimPrefix(line, "event: ")
+		events = append(events, event)
+
+		switch event {
+		case "cooldown":
+			// A new intervention restarts the cooldown
+			last = time.Now()
+			m.server.events.notify("sess-1")
+		case "cooldown_finished":
+			want := []string{"cooldown", "cooldown_started", "cooldown_finished"}
+			if strings.Join(events, ",") != strings.Join(want, ",") {
+				t.Errorf("events = %v, want %v", events, want)
+			}
+			return
+		}
+	}
+	t.Fatalf("stream ended without cooldown_finished: %v (%v)", events, scanner.Err())
+}
+
+func TestHandleSessionEvents_Nudge(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.events = newSessionEvents()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: "sess-1", Status: session.StatusActive}, nil
+	}
+
+	// Raised before connecting: not replayed
+	m.server.events.nudge(session.Nudge{SessionID: "sess-1", Reason: "old", At: time.Now().Add(-time.Minute)})
+
+	ts := httptest.NewServer(m.server.router)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v1/sessions/sess-1/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var event string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+			if event == "cooldown" {
+				m.server.events.nudge(session.Nudge{
+					SessionID: "sess-1",
+					Reason:    session.NudgeRepeatedFailures,
+					Message:   "Try re-reading the failure.",
+					At:        time.Now(),
+				})
+			}
+		case strings.HasPrefix(line, "data: ") && event == "nudge":
+			var nudge session.Nudge
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &nudge); err != nil {
+				t.Fatalf("decode nudge: %v", err)
+			}
+			if nudge.Reason != session.NudgeRepeatedFailures {
+				t.Errorf("reason = %q, want %q", nudge.Reason, session.NudgeRepeatedFailures)
+			}
+			return
+		}
+	}
+	t.Fatalf("stream ended without a nudge (%v)", scanner.Err())
+}
+
+func TestHandleHeartbeat(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.heartbeatFn = func(ctx context.Context, id string) (*session.Session, error) {
+		switch id {
+		case "sess-1":
+			return coolingSession(time.Now()), nil
+		case "done":
+			return nil, session.ErrSessionNotActive
+		}
+		return nil, session.ErrSessionNotFound
+	}
+
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/sessions/sess-1/heartbeat", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Status   session.Status        `json:"status"`
+		Cooldown session.CooldownState `json:"cooldown"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Status != session.StatusActive || !resp.Cooldown.Active {
+		t.Errorf("response = %+v (%v)", resp, err)
+	}
+
+	for id, want := range map[string]int{"done": http.StatusBadRequest, "nope": http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		m.server.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/sessions/"+id+"/heartbeat", nil))
+		if w.Code != want {
+			t.Errorf("%s: status %d, want %d", id, w.Code, want)
+		}
+	}
+}
+
+func TestHandleSessionEvents_Lifecycle(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.events = newSessionEvents()
+
+	var idle *time.Time
+	last := time.Now()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		sess := coolingSession(last)
+		sess.Policy.CooldownSeconds = 60
+		sess.IdleSince = idle
+		return sess, nil
+	}
+
+	ts := httptest.NewServer(m.server.router)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v1/sessions/sess-1/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var events []string
+	var event string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+			events = append(events, event)
+		case strings.HasPrefix(line, "data: ") && len(events) == 1:
+			// The session goes idle
+			now := time.Now()
+			idle = &now
+			m.server.events.lifecycle(session.LifecycleEvent{SessionID: "sess-1", Event: session.LifecycleIdle, At: now})
+		case strings.HasPrefix(line, "data: ") && event == "cooldown" && len(events) == 3:
+			var state session.CooldownState
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &state); err != nil {
+				t.Fatalf("decode cooldown: %v", err)
+			}
+			if want := "cooldown,lifecycle,cooldown"; strings.Join(events, ",") != want || !state.Paused {
+				t.Errorf("events = %v, cooldown %+v; want %s with the cooldown paused", events, state, want)
+			}
+			return
+		}
+	}
+	t.Fatalf("stream ended without a paused cooldown: %v (%v)", events, scanner.Err())
+}
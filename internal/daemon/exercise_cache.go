@@ -0,0 +1,116 @@
+package daemon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// IndexVersionHeader carries the exercise index version on exercise
+// responses. It changes whenever a pack on disk does, so an editor can
+// keep what it fetched until the version moves.
+const IndexVersionHeader = "X-Temper-Index-Version"
+
+// maxExerciseCacheEntries bounds the cached responses for one index
+// version; list queries can vary freely
+const maxExerciseCacheEntries = 256
+
+// exerciseCache keeps encoded exercise responses for the index version
+// they were built from. A new version drops them all. The zero value is
+// ready to use.
+type exerciseCache struct {
+	mu      sync.Mutex
+	version string
+	entries map[string]cachedContent
+}
+
+// cachedContent is an encoded response body and its ETag
+type cachedContent struct {
+	body []byte
+	etag string
+}
+
+func (c *exerciseCache) get(version, key string) (cachedContent, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version != version {
+		return cachedContent{}, false
+	}
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *exerciseCache) put(version, key string, body []byte) cachedContent {
+	sum := sha256.Sum256(body)
+	entry := cachedContent{body: body, etag: `"` + hex.EncodeToString(sum[:8]) + `"`}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version != version || len(c.entries) >= maxExerciseCacheEntries {
+		c.version = version
+		c.entries = make(map[string]cachedContent)
+	}
+	c.entries[key] = entry
+	return entry
+}
+
+// serveExerciseContent answers an exercise content request from the cache,
+// building the response with build on a miss. build writes its own error
+// responses and returns false after one; errors aren't cached. Responses
+// carry the index version and an ETag, and a request whose If-None-Match
+// holds the ETag gets 304 Not Modified.
+func (s *Server) serveExerciseContent(w http.ResponseWriter, r *http.Request, build func() (any, bool)) {
+	version, err := s.exerciseLoader.IndexVersion()
+	if err != nil {
+		// The loader fails the same way and build reports it
+		data, ok := build()
+		if ok {
+			s.jsonResponse(w, http.StatusOK, data)
+		}
+		return
+	}
+
+	key := r.URL.Path + "?" + r.URL.RawQuery
+	entry, ok := s.exerciseCache.get(version, key)
+	if !ok {
+		data, ok := build()
+		if !ok {
+			return
+		}
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(data); err != nil {
+			slog.Error("failed to encode response", "error", err)
+			s.jsonError(w, http.StatusInternalServerError, "failed to encode response", err)
+			return
+		}
+		entry = s.exerciseCache.put(version, key, buf.Bytes())
+	}
+
+	w.Header().Set(IndexVersionHeader, version)
+	w.Header().Set("ETag", entry.etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), entry.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(entry.body)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Weak
+// validators match too: the body is the same either way.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func getExercises(m *serverWithMocks, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestExerciseContent_ETag(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.exerciseLoader = writeCalibrationPack(t)
+
+	for _, path := range []string{"/v1/exercises", "/v1/exercises/go-v1", "/v1/exercises/go-v1/basics/hello"} {
+		first := getExercises(m, path, "")
+		if first.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", path, first.Code, first.Body.String())
+		}
+		etag := first.Header().Get("ETag")
+		version := first.Header().Get(IndexVersionHeader)
+		if etag == "" || version == "" {
+			t.Fatalf("GET %s headers = %v; want ETag and %s", path, first.Header(), IndexVersionHeader)
+		}
+
+		cached := getExercises(m, path, "")
+		if cached.Body.String() != first.Body.String() || cached.Header().Get("ETag") != etag {
+			t.Errorf("GET %s from the cache differs from the first response", path)
+		}
+
+		notModified := getExercises(m, path, `"other", W/`+etag)
+		if notModified.Code != http.StatusNotModified || notModified.Body.Len() != 0 {
+			t.Errorf("GET %s with a matching If-None-Match = %d; want 304 without a body", path, notModified.Code)
+		}
+		if getExercises(m, path, `"other"`).Code != http.StatusOK {
+			t.Errorf("GET %s with a stale If-None-Match should return the content", path)
+		}
+	}
+
+	// Errors aren't cached and carry no validators
+	missing := getExercises(m, "/v1/exercises/go-v1/basics/missing", "")
+	if missing.Code != http.StatusNotFound || missing.Header().Get("ETag") != "" {
+		t.Errorf("GET missing exercise = %d, ETag %q; want 404 without one", missing.Code, missing.Header().Get("ETag"))
+	}
+}
+
+func TestExerciseContent_PackUpdate(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.exerciseLoader = writeCalibrationPack(t)
+	path := "/v1/exercises/go-v1/basics/hello"
+
+	before := getExercises(m, path, "")
+	file := filepath.Join(m.server.exerciseLoader.BasePath(), "go-v1", "basics", "hello.yaml")
+	content := "id: basics/hello\ntitle: Hello, updated\ndifficulty: beginner\nstarter:\n  main.go: \"package main\\n\"\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	after := getExercises(m, path, before.Header().Get("ETag"))
+	if after.Code != http.StatusOK || !strings.Contains(after.Body.String(), "Hello, updated") {
+		t.Fatalf("GET after the pack changed = %d: %s; want the new exercise", after.Code, after.Body.String())
+	}
+	if after.Header().Get(IndexVersionHeader) == before.Header().Get(IndexVersionHeader) {
+		t.Error("index version unchanged after the pack changed")
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"x", "abc"`, true},
+		{`*`, true},
+		{`"abcd"`, false},
+		{``, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, `"abc"`); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v; want %v", tt.header, got, tt.want)
+		}
+	}
+}
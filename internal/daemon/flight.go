@@ -0,0 +1,141 @@
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// pairingFlights shares one pairing call among identical requests that
+// arrive while it runs: an editor retrying on a flaky connection, or the
+// editor and the CLI asking for the same hint at once. Only the first
+// request calls the LLM and records the intervention; the others wait for
+// it and get the same result. Nothing is kept once the call returns, so a
+// later request asks again.
+//
+// A call runs for as long as any of its requests waits on it. When the
+// last one hangs up, the call is cancelled, so nobody pays for an answer
+// nobody receives.
+type pairingFlights struct {
+	mu    sync.Mutex
+	calls map[string]*pairingFlight
+}
+
+type pairingFlight struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	waiters int // requests waiting on the call; guarded by the flights' mu
+	outcome *pairingOutcome
+	err     error
+}
+
+// pairingOutcome is what a pairing call produced, shared with duplicates
+type pairingOutcome struct {
+	intervention *domain.Intervention
+	hasPatch     bool
+	diffs        []session.FileDiff
+}
+
+// errPairingAborted is what requests get when the call they waited on
+// never returned
+var errPairingAborted = errors.New("pairing call aborted")
+
+func newPairingFlights() *pairingFlights {
+	return &pairingFlights{calls: make(map[string]*pairingFlight)}
+}
+
+// do runs fn, unless a call with the same key is already running, in
+// which case it waits for that call's outcome. shared reports whether
+// the outcome came from another request's call. fn's context is
+// cancelled once every request waiting on the call is done, and fn
+// should record nothing after that.
+func (f *pairingFlights) do(ctx context.Context, key string, fn func(ctx context.Context) (*pairingOutcome, error)) (outcome *pairingOutcome, shared bool, err error) {
+	if f == nil {
+		outcome, err = fn(ctx)
+		return outcome, false, err
+	}
+
+	f.mu.Lock()
+	call, shared := f.calls[key]
+	if !shared {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &pairingFlight{ctx: callCtx, cancel: cancel, done: make(chan struct{})}
+		f.calls[key] = call
+		go f.run(key, call, fn)
+	}
+	call.waiters++
+	f.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.outcome, shared, call.err
+	case <-ctx.Done():
+		f.leave(key, call)
+		return nil, shared, ctx.Err()
+	}
+}
+
+// run makes the call and hands its outcome to the requests waiting on it
+func (f *pairingFlights) run(key string, call *pairingFlight, fn func(ctx context.Context) (*pairingOutcome, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("pairing call panicked", "panic", r)
+			call.outcome, call.err = nil, errPairingAborted
+		}
+		f.mu.Lock()
+		if f.calls[key] == call {
+			delete(f.calls, key)
+		}
+		f.mu.Unlock()
+		call.cancel()
+		close(call.done)
+	}()
+	call.outcome, call.err = fn(call.ctx)
+}
+
+// leave detaches a request that hung up from its call, cancelling the
+// call when it was the last one waiting. A later request with the same
+// key starts a new call rather than joining the cancelled one.
+func (f *pairingFlights) leave(key string, call *pairingFlight) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	call.cancel()
+	if f.calls[key] == call {
+		delete(f.calls, key)
+	}
+}
+
+// pairingKey identifies what a pairing request asks for: the session,
+// intent, level, run and code. Requests with the same key get the same
+// answer.
+func pairingKey(req pairing.InterventionRequest) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d\x00%s\x00", req.SessionID, req.Intent, req.ExplicitLevel, req.Justification)
+	if req.RunID != nil {
+		fmt.Fprintf(h, "%s", req.RunID)
+	}
+
+	paths := make([]string, 0, len(req.Context.Code))
+	for path := range req.Context.Code {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(h, "\x00%s\x00%d\x00%s", path, len(req.Context.Code[path]), req.Context.Code[path])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
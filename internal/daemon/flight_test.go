@@ -0,0 +1,204 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+func TestPairingKey(t *testing.T) {
+	id := uuid.New()
+	req := func(intent domain.Intent, code map[string]string) pairing.InterventionRequest {
+		return pairing.InterventionRequest{SessionID: id, Intent: intent, Context: pairing.InterventionContext{Code: code}}
+	}
+	base := pairingKey(req(domain.IntentHint, map[string]string{"a.go": "x", "b.go": "y"}))
+
+	if got := pairingKey(req(domain.IntentHint, map[string]string{"b.go": "y", "a.go": "x"})); got != base {
+		t.Error("the same request should have the same key")
+	}
+	for name, other := range map[string]pairing.InterventionRequest{
+		"intent": req(domain.IntentReview, map[string]string{"a.go": "x", "b.go": "y"}),
+		"code":   req(domain.IntentHint, map[string]string{"a.go": "x", "b.go": "z"}),
+		"files":  req(domain.IntentHint, map[string]string{"a.go": "x\x00b.go\x00y"}),
+	} {
+		if pairingKey(other) == base {
+			t.Errorf("a different %s should have a different key", name)
+		}
+	}
+}
+
+func TestHandleHint_SharesConcurrentCalls(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.pairingFlights = newPairingFlights()
+	sessionID := uuid.New().String()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: id, Status: session.StatusActive, Policy: domain.DefaultPolicy(),
+			Code: map[string]string{"main.go": "package main"}}, nil
+	}
+
+	var calls, recorded atomic.Int32
+	release := make(chan struct{})
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		calls.Add(1)
+		<-release
+		return &domain.Intervention{ID: uuid.New(), Intent: req.Intent, Level: domain.L1CategoryHint, Content: "check the loop bound"}, nil
+	}
+	m.sessions.recordInterventionFn = func(ctx context.Context, iv *session.Intervention) error {
+		recorded.Add(1)
+		return nil
+	}
+
+	const requests = 3
+	ids := make([]string, requests)
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			m.server.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/hint", strings.NewReader(`{}`)))
+			var resp struct {
+				ID string `json:"id"`
+			}
+			if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &resp) != nil {
+				t.Errorf("request %d: status %d: %s", i, w.Code, w.Body.String())
+			}
+			ids[i] = resp.ID
+		}()
+	}
+
+	// Let every request join the first one's call before it returns
+	waitForWaiters(m.server.pairingFlights, requests)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 || recorded.Load() != 1 {
+		t.Errorf("LLM called %d times, recorded %d; want one of each", calls.Load(), recorded.Load())
+	}
+	for i := 1; i < requests; i++ {
+		if ids[i] != ids[0] {
+			t.Errorf("responses carry different interventions: %v", ids)
+		}
+	}
+
+	// Once the call returned, the next request asks again
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/hint", strings.NewReader(`{}`)))
+	if calls.Load() != 2 {
+		t.Errorf("a later request should make its own call, got %d calls", calls.Load())
+	}
+}
+
+// waitForWaiters waits until a call has n requests waiting on it
+func waitForWaiters(f *pairingFlights, n int) {
+	for waiting := 0; waiting != n; time.Sleep(time.Millisecond) {
+		waiting = 0
+		f.mu.Lock()
+		for _, call := range f.calls {
+			waiting = call.waiters
+		}
+		f.mu.Unlock()
+	}
+}
+
+// newHangingHintServer returns a server whose LLM answers once release is
+// closed or the call is cancelled, counting calls that saw a cancel and
+// interventions recorded
+func newHangingHintServer(t *testing.T, release chan struct{}) (m *serverWithMocks, sessionID string, cancelled, recorded *atomic.Int32) {
+	t.Helper()
+	m = newServerWithMocks()
+	m.server.pairingFlights = newPairingFlights()
+	sessionID = uuid.New().String()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: id, Status: session.StatusActive, Policy: domain.DefaultPolicy(),
+			Code: map[string]string{"main.go": "package main"}}, nil
+	}
+	cancelled, recorded = new(atomic.Int32), new(atomic.Int32)
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			cancelled.Add(1)
+			return nil, ctx.Err()
+		}
+		return &domain.Intervention{ID: uuid.New(), Intent: req.Intent, Level: domain.L1CategoryHint, Content: "check the loop bound"}, nil
+	}
+	m.sessions.recordInterventionFn = func(ctx context.Context, iv *session.Intervention) error {
+		recorded.Add(1)
+		return nil
+	}
+	return m, sessionID, cancelled, recorded
+}
+
+func TestHandleHint_SharedCallOutlivesOneWaiter(t *testing.T) {
+	release := make(chan struct{})
+	m, sessionID, cancelled, recorded := newHangingHintServer(t, release)
+
+	ctx, hangUp := context.WithCancel(context.Background())
+	left := make(chan struct{})
+	go func() {
+		defer close(left)
+		req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/hint", strings.NewReader(`{}`)).WithContext(ctx)
+		m.server.router.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+	stayed := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.server.router.ServeHTTP(stayed, httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/hint", strings.NewReader(`{}`)))
+	}()
+
+	waitForWaiters(m.server.pairingFlights, 2)
+	hangUp()
+	<-left
+	close(release)
+	<-done
+
+	if stayed.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 for the request still waiting: %s", stayed.Code, stayed.Body.String())
+	}
+	if cancelled.Load() != 0 || recorded.Load() != 1 {
+		t.Errorf("cancelled %d, recorded %d; want the call to finish and record once", cancelled.Load(), recorded.Load())
+	}
+}
+
+func TestHandleHint_CancelsWhenOnlyWaiterLeaves(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	m, sessionID, cancelled, recorded := newHangingHintServer(t, release)
+
+	ctx, hangUp := context.WithCancel(context.Background())
+	left := make(chan struct{})
+	go func() {
+		defer close(left)
+		req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/hint", strings.NewReader(`{}`)).WithContext(ctx)
+		m.server.router.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	waitForWaiters(m.server.pairingFlights, 1)
+	hangUp()
+	<-left
+
+	// The call is cancelled in the background; give it a moment
+	deadline := time.Now().Add(time.Second)
+	for cancelled.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if cancelled.Load() != 1 {
+		t.Error("the LLM call should be cancelled once its only caller hangs up")
+	}
+	if recorded.Load() != 0 {
+		t.Errorf("recorded %d interventions nobody received", recorded.Load())
+	}
+}
@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/achievement"
+	"github.com/felixgeelhaar/temper/internal/profile"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// Achievement handlers (milestones unlocked by the learner's runs)
+
+// checkAchievements runs a saved run past the achievement rules and sends
+// anything it unlocks to the session's event stream
+func (s *Server) checkAchievements(ctx context.Context, sess *session.Session, run *session.Run) {
+	if s.achievements == nil || run.Result == nil {
+		return
+	}
+
+	now := time.Now()
+	activity := achievement.Activity{
+		SessionID:  sess.ID,
+		ExerciseID: sess.ExerciseID,
+		HintCount:  sess.HintCount,
+		Green:      run.Result.BuildOK && run.Result.TestOK,
+		At:         now,
+	}
+	if runs, err := s.sessionService.GetRuns(ctx, sess.ID); err == nil {
+		activity.Previous = previousRun(runs, run)
+	}
+	if history, err := s.exerciseHistory(ctx); err == nil {
+		activity.Streak, _ = profile.PracticeStreak(history, now)
+	}
+
+	unlocked, err := s.achievements.Evaluate(activity)
+	if err != nil {
+		slog.Warn("check achievements", "session_id", sess.ID, "error", err)
+	}
+	for _, u := range unlocked {
+		slog.Info("achievement unlocked", "achievement", u.ID, "session_id", sess.ID)
+		s.events.achievement(u)
+	}
+}
+
+// previousRun returns the run of runs made just before run
+func previousRun(runs []*session.Run, run *session.Run) *achievement.PreviousRun {
+	var prev *session.Run
+	for _, r := range runs {
+		if r.ID == run.ID || !r.CreatedAt.Before(run.CreatedAt) || r.Result == nil {
+			continue
+		}
+		if prev == nil || r.CreatedAt.After(prev.CreatedAt) {
+			prev = r
+		}
+	}
+	if prev == nil {
+		return nil
+	}
+	return &achievement.PreviousRun{
+		Green:  prev.Result.BuildOK && prev.Result.TestOK,
+		Output: prev.Result.BuildOutput + "\n" + prev.Result.TestOutput,
+	}
+}
+
+// handleListAchievements returns every achievement, with when and where
+// it was unlocked once earned
+func (s *Server) handleListAchievements(w http.ResponseWriter, r *http.Request) {
+	if s.achievements == nil {
+		s.jsonError(w, http.StatusServiceUnavailable, "achievements not available", nil)
+		return
+	}
+	list, err := s.achievements.List()
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to list achievements", err)
+		return
+	}
+
+	unlocked := 0
+	for _, a := range list {
+		if a.Unlocked != nil {
+			unlocked++
+		}
+	}
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"achievements": list,
+		"unlocked":     unlocked,
+		"total":        len(list),
+	})
+}
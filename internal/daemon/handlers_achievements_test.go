@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/achievement"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func TestPreviousRun(t *testing.T) {
+	base := time.Now()
+	runs := []*session.Run{
+		{ID: "r1", CreatedAt: base, Result: &session.RunResult{BuildOK: true}},
+		{ID: "r2", CreatedAt: base.Add(time.Minute), Result: &session.RunResult{BuildOK: true, TestOutput: "goroutine leak"}},
+		{ID: "r3", CreatedAt: base.Add(2 * time.Minute), Result: &session.RunResult{BuildOK: true, TestOK: true}},
+	}
+
+	prev := previousRun(runs, runs[2])
+	if prev == nil || prev.Green || prev.Output != "\ngoroutine leak" {
+		t.Errorf("previousRun() = %+v; want the failing r2", prev)
+	}
+	if prev := previousRun(runs, runs[0]); prev != nil {
+		t.Errorf("previousRun() = %+v; want nil for the first run", prev)
+	}
+}
+
+func TestCheckAchievements(t *testing.T) {
+	m := newServerWithMocks()
+	engine, err := achievement.NewEngine(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	m.server.achievements = engine
+
+	sess := &session.Session{ID: "s1", Status: session.StatusActive}
+	run := &session.Run{ID: "r1", SessionID: "s1", CreatedAt: time.Now(), Result: &session.RunResult{BuildOK: true, TestOK: true}}
+	m.server.checkAchievements(context.Background(), sess, run)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/achievements", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Achievements []achievement.Status `json:"achievements"`
+		Unlocked     int                  `json:"unlocked"`
+		Total        int                  `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Unlocked != 1 || resp.Total != len(achievement.Rules()) {
+		t.Errorf("unlocked %d of %d; want 1 of %d", resp.Unlocked, resp.Total, len(achievement.Rules()))
+	}
+	if got := resp.Achievements[0]; got.Unlocked == nil || got.Unlocked.SessionID != "s1" {
+		t.Errorf("first achievement = %+v; want unlocked by s1", got)
+	}
+}
+
+func TestHandleListAchievements_Unavailable(t *testing.T) {
+	m := newServerWithMocks()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/achievements", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// handleRunArtifact serves a file a run collected for download, with
+// range requests. Coverage reports are HTML, so they're sandboxed rather
+// than run with the daemon's origin.
+func (s *Server) handleRunArtifact(w http.ResponseWriter, r *http.Request) {
+	artifact, file, err := s.sessionService.OpenArtifact(r.Context(), r.PathValue("id"), r.PathValue("run"), r.PathValue("path"))
+	switch {
+	case errors.Is(err, session.ErrSessionNotFound):
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+		return
+	case errors.Is(err, session.ErrRunNotFound):
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "run not found", nil)
+		return
+	case errors.Is(err, session.ErrArtifactNotFound):
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "artifact not found", nil)
+		return
+	case err != nil:
+		s.jsonError(w, http.StatusInternalServerError, "failed to open artifact", err)
+		return
+	}
+	defer file.Close()
+
+	contentType := "application/octet-stream"
+	if artifact.Kind != domain.ArtifactBinary {
+		if t := mime.TypeByExtension(path.Ext(artifact.Path)); t != "" {
+			contentType = t
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(artifact.Path)}))
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, artifact.Path, time.Time{}, file)
+}
@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+func TestHandleRunArtifact(t *testing.T) {
+	tests := []struct {
+		name        string
+		artifact    domain.RunArtifact
+		contentType string
+	}{
+		{"binary", domain.RunArtifact{Name: "cli", Kind: domain.ArtifactBinary, Path: "bin/cli"}, "application/octet-stream"},
+		{"coverage", domain.RunArtifact{Name: "coverage", Kind: domain.ArtifactCoverage, Path: "report/coverage.html"}, "text/html; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newServerWithMocks()
+			var gotPath string
+			m.sessions.openArtifactFn = func(ctx context.Context, id, runID, path string) (*domain.RunArtifact, io.ReadSeekCloser, error) {
+				gotPath = path
+				return &tt.artifact, &closingReader{Reader: strings.NewReader("contents")}, nil
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/sessions/"+uuid.New().String()+"/runs/run-1/artifacts/"+tt.artifact.Path, nil)
+			w := httptest.NewRecorder()
+			m.server.router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK || w.Body.String() != "contents" {
+				t.Fatalf("got %d %q", w.Code, w.Body.String())
+			}
+			if gotPath != tt.artifact.Path {
+				t.Errorf("path = %q, want %q", gotPath, tt.artifact.Path)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.contentType)
+			}
+			if cd := w.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment") {
+				t.Errorf("Content-Disposition = %q", cd)
+			}
+			if csp := w.Header().Get("Content-Security-Policy"); csp != "sandbox" {
+				t.Errorf("Content-Security-Policy = %q", csp)
+			}
+		})
+	}
+}
+
+func TestHandleRunArtifact_NotFound(t *testing.T) {
+	for _, err := range []error{session.ErrSessionNotFound, session.ErrRunNotFound, session.ErrArtifactNotFound} {
+		m := newServerWithMocks()
+		m.sessions.openArtifactFn = func(ctx context.Context, id, runID, path string) (*domain.RunArtifact, io.ReadSeekCloser, error) {
+			return nil, nil, err
+		}
+		req := httptest.NewRequest(http.MethodGet, "/v1/sessions/"+uuid.New().String()+"/runs/run-1/artifacts/bin/cli", nil)
+		w := httptest.NewRecorder()
+		m.server.router.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%v: expected %d, got %d", err, http.StatusNotFound, w.Code)
+		}
+	}
+}
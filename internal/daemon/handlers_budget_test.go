@@ -0,0 +1,71 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+func budgetSession(id string) *session.Session {
+	policy := domain.DefaultPolicy()
+	policy.Budget = domain.HintBudget{Tokens: 5}
+	return &session.Session{ID: id, Status: session.StatusActive, Policy: policy, BudgetSpent: 4}
+}
+
+func TestHandleGetSession_Budget(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return budgetSession(id), nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/sessions/"+uuid.New().String(), nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Budget session.BudgetState `json:"budget"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.Budget.Enabled || resp.Budget.Remaining != 1 || resp.Budget.NextLevel != domain.L2LocationConcept {
+		t.Errorf("budget = %+v; want 1 token left, next L2", resp.Budget)
+	}
+}
+
+func TestHandleHint_BudgetExhausted(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return budgetSession(id), nil
+	}
+	var got pairing.InterventionRequest
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		got = req
+		return nil, pairing.ErrHintBudgetExhausted
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+uuid.New().String()+"/hint", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d: %s", http.StatusForbidden, w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), ErrCodeHintBudgetExhausted) {
+		t.Errorf("body should carry %s: %s", ErrCodeHintBudgetExhausted, w.Body.String())
+	}
+	if got.BudgetSpent != 4 {
+		t.Errorf("BudgetSpent = %d; want the session's 4", got.BudgetSpent)
+	}
+}
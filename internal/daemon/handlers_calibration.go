@@ -0,0 +1,133 @@
+package daemon
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/felixgeelhaar/temper/internal/cohort"
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// handleAnalyticsExercises reports per-exercise outcomes and the difficulty
+// they suggest, so pack authors can correct labels that don't match how
+// learners actually fare.
+//
+//	?cohort=<id>          calibrate from a cohort's shared exports instead of the local profile
+//	?pack=<id>            only exercises from this pack
+//	?min_attempts=<n>     attempts needed before suggesting (default 5)
+//	?miscalibrated=true   only exercises whose suggestion differs from the label
+func (s *Server) handleAnalyticsExercises(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	opts := cohort.CalibrationOptions{}
+	if v := q.Get("min_attempts"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, "min_attempts must be a positive integer", nil)
+			return
+		}
+		opts.MinAttempts = n
+	}
+
+	source := "profile"
+	var attempts []cohort.Attempt
+	learners := 1
+	if id := q.Get("cohort"); id != "" {
+		if s.cohortStore == nil {
+			s.jsonError(w, http.StatusServiceUnavailable, "cohorts not available", nil)
+			return
+		}
+		members, err := s.cohortStore.Members(id)
+		if err != nil {
+			switch {
+			case errors.Is(err, cohort.ErrNotFound):
+				s.jsonErrorCode(w, http.StatusNotFound, ErrCodeCohortNotFound, "cohort not found", nil)
+			case errors.Is(err, cohort.ErrInvalidID):
+				s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, "invalid cohort id", nil)
+			default:
+				s.jsonError(w, http.StatusInternalServerError, "failed to load cohort", err)
+			}
+			return
+		}
+		for _, m := range members {
+			attempts = append(attempts, m.Attempts...)
+		}
+		source, learners = "cohort:"+id, len(members)
+	} else {
+		storedProfile, err := s.profileService.GetProfile(r.Context())
+		if err != nil {
+			s.jsonError(w, http.StatusInternalServerError, "failed to get profile", err)
+			return
+		}
+		attempts = profileAttempts(storedProfile)
+	}
+
+	pack := q.Get("pack")
+	if pack != "" {
+		filtered := attempts[:0:0]
+		for _, a := range attempts {
+			if id, _, _ := domain.SplitExerciseID(a.ExerciseID); id == pack {
+				filtered = append(filtered, a)
+			}
+		}
+		attempts = filtered
+	}
+
+	report := cohort.Calibrate(attempts, s.difficultyLabels(pack), opts)
+	if q.Get("miscalibrated") == "true" {
+		filtered := report[:0]
+		for _, c := range report {
+			if c.Miscalibrated {
+				filtered = append(filtered, c)
+			}
+		}
+		report = filtered
+	}
+
+	minAttempts := opts.MinAttempts
+	if minAttempts == 0 {
+		minAttempts = cohort.DefaultMinAttempts
+	}
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"source":       source,
+		"learners":     learners,
+		"min_attempts": minAttempts,
+		"exercises":    report,
+	})
+}
+
+// difficultyLabels maps exercise IDs to the difficulty their pack gives
+// them, for one pack or all of them. Packs that fail to load are skipped;
+// their exercises are reported without a label.
+func (s *Server) difficultyLabels(pack string) map[string]string {
+	labels := make(map[string]string)
+	if s.exerciseLoader == nil {
+		return labels
+	}
+
+	packIDs := []string{pack}
+	if pack == "" {
+		packs, err := s.exerciseLoader.LoadAllPacks()
+		if err != nil {
+			slog.Warn("calibration without difficulty labels", "error", err)
+			return labels
+		}
+		packIDs = packIDs[:0]
+		for _, p := range packs {
+			packIDs = append(packIDs, p.ID)
+		}
+	}
+	for _, id := range packIDs {
+		exercises, err := s.exerciseLoader.LoadPackExercises(id)
+		if err != nil {
+			slog.Warn("failed to load pack exercises", "pack", id, "error", err)
+			continue
+		}
+		for _, ex := range exercises {
+			labels[ex.ID] = string(ex.Difficulty)
+		}
+	}
+	return labels
+}
@@ -0,0 +1,120 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/cohort"
+	"github.com/felixgeelhaar/temper/internal/exercise"
+	"github.com/felixgeelhaar/temper/internal/profile"
+)
+
+// writeCalibrationPack writes a pack labelling basics/hello advanced
+func writeCalibrationPack(t *testing.T) *exercise.Loader {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go-v1/pack.yaml": "id: go-v1\nname: Go\nversion: \"1.0.0\"\nlanguage: go\nexercises:\n  - basics/hello\n",
+		"go-v1/basics/hello.yaml": "id: basics/hello\ntitle: Hello\ndifficulty: advanced\n" +
+			"starter:\n  main.go: \"package main\\n\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return exercise.NewLoader(dir)
+}
+
+func getCalibration(t *testing.T, m *serverWithMocks, query string) (int, calibrationResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/v1/analytics/exercises"+query, nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	var resp calibrationResponse
+	if w.Code == http.StatusOK {
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+	return w.Code, resp
+}
+
+type calibrationResponse struct {
+	Source      string                       `json:"source"`
+	Learners    int                          `json:"learners"`
+	MinAttempts int                          `json:"min_attempts"`
+	Exercises   []cohort.ExerciseCalibration `json:"exercises"`
+}
+
+func TestAnalyticsExercises_Profile(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.exerciseLoader = writeCalibrationPack(t)
+	start := time.Now().Add(-48 * time.Hour)
+	m.profiles.getProfileFn = func(ctx context.Context) (*profile.StoredProfile, error) {
+		var history []profile.ExerciseAttempt
+		for i := 0; i < 3; i++ {
+			done := start.Add(5 * time.Minute)
+			history = append(history,
+				profile.ExerciseAttempt{ExerciseID: "go-v1/basics/hello", StartedAt: start, CompletedAt: &done, Success: true},
+				profile.ExerciseAttempt{ExerciseID: "other/basics/maps", StartedAt: start, CompletedAt: &done, Success: true},
+			)
+		}
+		return &profile.StoredProfile{ExerciseHistory: history}, nil
+	}
+
+	status, resp := getCalibration(t, m, "?pack=go-v1&min_attempts=3")
+	if status != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, status)
+	}
+	if resp.Source != "profile" || resp.MinAttempts != 3 || len(resp.Exercises) != 1 {
+		t.Fatalf("unexpected report: %+v", resp)
+	}
+	hello := resp.Exercises[0]
+	if hello.Label != "advanced" || hello.Suggested != "beginner" || !hello.Miscalibrated {
+		t.Errorf("unexpected calibration: %+v", hello)
+	}
+
+	// Without enough attempts nothing is flagged
+	if _, resp := getCalibration(t, m, "?miscalibrated=true"); len(resp.Exercises) != 0 {
+		t.Errorf("expected no suggestions under the default minimum, got %+v", resp.Exercises)
+	}
+}
+
+func TestAnalyticsExercises_Cohort(t *testing.T) {
+	m := setupCohortServer(t)
+	for _, id := range []string{"a1", "b2"} {
+		export := strings.Replace(cohortExport, "a1b2c3", id, 1)
+		req := httptest.NewRequest(http.MethodPost, "/v1/cohorts/go-101/members", strings.NewReader(export))
+		w := httptest.NewRecorder()
+		m.server.router.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("add member: %d %s", w.Code, w.Body.String())
+		}
+	}
+
+	status, resp := getCalibration(t, m, "?cohort=go-101")
+	if status != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, status)
+	}
+	if resp.Source != "cohort:go-101" || resp.Learners != 2 || len(resp.Exercises) != 1 || resp.Exercises[0].Attempts != 2 {
+		t.Errorf("unexpected report: %+v", resp)
+	}
+
+	for _, query := range []string{"?cohort=nobody", "?min_attempts=0"} {
+		if status, _ := getCalibration(t, m, query); status == http.StatusOK {
+			t.Errorf("%s: expected an error, got %d", query, status)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package daemon
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/cards"
+)
+
+// Flashcard handlers (cards generated from session mistakes and
+// explanations, reviewed on the spaced-repetition ladder)
+
+// handleListCards returns the card queue, soonest due first
+//
+//	?due=true   only cards due now
+func (s *Server) handleListCards(w http.ResponseWriter, r *http.Request) {
+	if s.cardStore == nil {
+		s.jsonError(w, http.StatusServiceUnavailable, "flashcards not available", nil)
+		return
+	}
+	all, err := s.cardStore.List()
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to list cards", err)
+		return
+	}
+
+	now := time.Now()
+	due := 0
+	list := all[:0:0]
+	for _, c := range all {
+		if c.Due(now) {
+			due++
+		} else if r.URL.Query().Get("due") == "true" {
+			continue
+		}
+		list = append(list, c)
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"cards": list,
+		"due":   due,
+		"total": len(all),
+	})
+}
+
+// handleGenerateCards turns recurring mistakes and explained concepts
+// from every session into cards. Cards already in the queue keep their
+// schedule, so generating again only adds what's new.
+func (s *Server) handleGenerateCards(w http.ResponseWriter, r *http.Request) {
+	if s.cardStore == nil {
+		s.jsonError(w, http.StatusServiceUnavailable, "flashcards not available", nil)
+		return
+	}
+	lessons, err := s.sessionService.Lessons(r.Context())
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to collect session lessons", err)
+		return
+	}
+
+	generated := cards.Generate(lessons, s.concepts, time.Now())
+	added, err := s.cardStore.Merge(generated)
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to store cards", err)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"added": added,
+		"total": len(generated),
+	})
+}
+
+// handleReviewCard records whether the learner remembered a card and
+// schedules its next review
+func (s *Server) handleReviewCard(w http.ResponseWriter, r *http.Request) {
+	if s.cardStore == nil {
+		s.jsonError(w, http.StatusServiceUnavailable, "flashcards not available", nil)
+		return
+	}
+	var req struct {
+		Remembered bool `json:"remembered"`
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	card, err := s.cardStore.Update(r.PathValue("id"), func(c *cards.Card) {
+		c.Review(req.Remembered, time.Now())
+	})
+	if err != nil {
+		if errors.Is(err, cards.ErrNotFound) {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "card not found", nil)
+			return
+		}
+		s.jsonError(w, http.StatusInternalServerError, "failed to review card", err)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, card)
+}
+
+// handleExportCards writes every card for import into Anki
+//
+//	?format=tsv    text import, the default
+//	?format=apkg   Anki package
+func (s *Server) handleExportCards(w http.ResponseWriter, r *http.Request) {
+	if s.cardStore == nil {
+		s.jsonError(w, http.StatusServiceUnavailable, "flashcards not available", nil)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	contentType := "text/tab-separated-values; charset=utf-8"
+	switch format {
+	case "", cards.FormatTSV:
+		format = cards.FormatTSV
+	case cards.FormatAPKG:
+		contentType = "application/octet-stream"
+	default:
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, "format must be tsv or apkg", nil)
+		return
+	}
+
+	all, err := s.cardStore.List()
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to list cards", err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := cards.Export(&buf, format, all); err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to export cards", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="temper-cards.`+format+`"`)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// dueCards returns the cards due now for the review queue; none when
+// flashcards aren't available
+func (s *Server) dueCards(now time.Time) []*cards.Card {
+	due := []*cards.Card{}
+	if s.cardStore == nil {
+		return due
+	}
+	all, err := s.cardStore.List()
+	if err != nil {
+		slog.Warn("review queue without flashcards", "error", err)
+		return due
+	}
+	for _, c := range all {
+		if !c.Due(now) {
+			break
+		}
+		due = append(due, c)
+	}
+	return due
+}
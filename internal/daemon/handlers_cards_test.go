@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/cards"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/profile"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func TestMock_Cards(t *testing.T) {
+	m := newServerWithMocks()
+	store, err := cards.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.server.cardStore = store
+	m.profiles.getProfileFn = func(ctx context.Context) (*profile.StoredProfile, error) {
+		return &profile.StoredProfile{}, nil
+	}
+
+	nilMap := func(sessionID string) session.Lesson {
+		return session.Lesson{SessionID: sessionID, RunID: "r1", ExerciseID: "go-v1/basics/maps", Error: &domain.ErrorExplanation{
+			Original: "assignment to entry in nil map", Explanation: "Create the map with make first.", Rule: "nil-map-write",
+		}}
+	}
+	m.sessions.lessonsFn = func(ctx context.Context) ([]session.Lesson, error) {
+		return []session.Lesson{nilMap("s1"), nilMap("s2")}, nil
+	}
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		m.server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/v1/cards/generate", "")
+	var generated struct {
+		Added []cards.Card `json:"added"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &generated); err != nil || w.Code != http.StatusOK || len(generated.Added) != 1 {
+		t.Fatalf("generate = %d: %s", w.Code, w.Body.String())
+	}
+	id := generated.Added[0].ID
+
+	if w := do(http.MethodPost, "/v1/cards/generate", ""); !strings.Contains(w.Body.String(), `"added":[]`) {
+		t.Errorf("generating again should add nothing: %s", w.Body.String())
+	}
+
+	if w := do(http.MethodGet, "/v1/reviews", ""); !strings.Contains(w.Body.String(), id) {
+		t.Errorf("new card should be due in the review queue: %s", w.Body.String())
+	}
+
+	if w := do(http.MethodPost, "/v1/cards/"+id+"/review", `{"remembered": true}`); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"step":1`) {
+		t.Errorf("review = %d: %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodGet, "/v1/cards?due=true", ""); !strings.Contains(w.Body.String(), `"cards":[]`) {
+		t.Errorf("reviewed card should not be due: %s", w.Body.String())
+	}
+	if w := do(http.MethodPost, "/v1/cards/nope/review", `{"remembered": false}`); w.Code != http.StatusNotFound {
+		t.Errorf("unknown card = %d, want 404", w.Code)
+	}
+
+	w = do(http.MethodGet, "/v1/cards/export", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Create the map with make first.") {
+		t.Errorf("tsv export = %d: %s", w.Code, w.Body.String())
+	}
+	w = do(http.MethodGet, "/v1/cards/export?format=apkg", "")
+	if _, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len())); err != nil {
+		t.Errorf("apkg export is not a zip: %v", err)
+	}
+	if w := do(http.MethodGet, "/v1/cards/export?format=csv", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("csv export = %d, want 400", w.Code)
+	}
+}
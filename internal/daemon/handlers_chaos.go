@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/chaos"
+)
+
+// chaosAvailable answers 404 on daemons built without -tags chaos, so the
+// endpoints look like they don't exist there
+func (s *Server) chaosAvailable(w http.ResponseWriter) bool {
+	if s.chaos == nil {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "fault injection is not available; build the daemon with -tags chaos", nil)
+		return false
+	}
+	return true
+}
+
+// handleGetChaos reports the injected faults and how often each fired
+func (s *Server) handleGetChaos(w http.ResponseWriter, r *http.Request) {
+	if !s.chaosAvailable(w) {
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, s.chaos.State())
+}
+
+// handleSetChaos replaces the injected faults:
+//
+//	{"provider_latency_ms": 2000, "provider_fault": "error|timeout|malformed",
+//	 "runner_error": true, "store_error": true, "remaining": 1}
+func (s *Server) handleSetChaos(w http.ResponseWriter, r *http.Request) {
+	if !s.chaosAvailable(w) {
+		return
+	}
+	var faults chaos.Faults
+	if !s.decodeRequest(w, r, &faults) {
+		return
+	}
+	if err := s.chaos.Set(faults); err != nil {
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error(), nil)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, s.chaos.State())
+}
+
+// handleClearChaos removes every fault
+func (s *Server) handleClearChaos(w http.ResponseWriter, r *http.Request) {
+	if !s.chaosAvailable(w) {
+		return
+	}
+	s.chaos.Clear()
+	s.jsonResponse(w, http.StatusOK, s.chaos.State())
+}
@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/chaos"
+)
+
+func chaosRequest(m *serverWithMocks, method, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/v1/admin/chaos", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestChaos_UnavailableWithoutBuildTag(t *testing.T) {
+	m := newServerWithMocks()
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		if w := chaosRequest(m, method, `{}`); w.Code != http.StatusNotFound {
+			t.Errorf("%s: expected %d, got %d", method, http.StatusNotFound, w.Code)
+		}
+	}
+}
+
+func TestChaos_SetAndClear(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.chaos = chaos.New()
+
+	w := chaosRequest(m, http.MethodPut, `{"provider_fault":"timeout","runner_error":true,"remaining":3}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("set: %d %s", w.Code, w.Body.String())
+	}
+	var state chaos.State
+	if err := json.Unmarshal(w.Body.Bytes(), &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Faults.ProviderFault != chaos.ProviderTimeout || !state.Faults.RunnerError || state.Faults.Remaining != 3 {
+		t.Errorf("unexpected faults: %+v", state.Faults)
+	}
+
+	if w := chaosRequest(m, http.MethodPut, `{"provider_fault":"explode"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown fault: expected %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	chaosRequest(m, http.MethodDelete, "")
+	if got := m.server.chaos.State().Faults; got != (chaos.Faults{}) {
+		t.Errorf("faults should be cleared, got %+v", got)
+	}
+}
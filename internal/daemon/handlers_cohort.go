@@ -0,0 +1,377 @@
+package daemon
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/cohort"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/profile"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// maxCohortExportBytes bounds one member's stats export
+const maxCohortExportBytes = 8 * 1024 * 1024
+
+// Cohort handlers (opt-in leaderboards built from shared stats exports)
+
+func (s *Server) handleListCohorts(w http.ResponseWriter, r *http.Request) {
+	if s.cohortStore == nil {
+		s.jsonError(w, http.StatusServiceUnavailable, "cohorts not available", nil)
+		return
+	}
+
+	ids, err := s.cohortStore.List()
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to list cohorts", err)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"cohorts": ids,
+	})
+}
+
+// handleAddCohortMember imports a member's `temper stats export` output.
+// The body is the JSONL file as written.
+func (s *Server) handleAddCohortMember(w http.ResponseWriter, r *http.Request) {
+	if s.cohortStore == nil {
+		s.jsonError(w, http.StatusServiceUnavailable, "cohorts not available", nil)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCohortExportBytes))
+	if err != nil {
+		s.jsonError(w, http.StatusRequestEntityTooLarge, "stats export too large", err)
+		return
+	}
+
+	member, err := s.cohortStore.Add(r.PathValue("id"), data)
+	if err != nil {
+		if errors.Is(err, cohort.ErrInvalidID) {
+			s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, "invalid cohort id", nil)
+			return
+		}
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeInvalidPayload, "invalid stats export", err)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusCreated, map[string]interface{}{
+		"profile_id":  member.ProfileID,
+		"leaderboard": member.Leaderboard,
+		"attempts":    len(member.Attempts),
+	})
+}
+
+// handleCohortLeaderboard ranks the members who opted in and benchmarks
+// the local profile against everyone in the cohort.
+//
+//	?sort=completed|streak|velocity   ranking metric (default completed)
+//	?anonymize=true                   hide display names
+func (s *Server) handleCohortLeaderboard(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	members, ok := s.cohortMembers(w, id)
+	if !ok {
+		return
+	}
+
+	board, err := cohort.Rank(members, cohort.Options{
+		Anonymize: r.URL.Query().Get("anonymize") == "true",
+		Sort:      r.URL.Query().Get("sort"),
+	})
+	if err != nil {
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest,
+			"sort must be completed, streak or velocity", nil)
+		return
+	}
+
+	response := map[string]interface{}{
+		"cohort":  id,
+		"entries": board.Entries,
+		"members": board.Members,
+	}
+
+	// The benchmark is extra; a missing profile shouldn't hide the board
+	if storedProfile, err := s.profileService.GetProfile(r.Context()); err == nil {
+		response["benchmark"] = cohort.Compare(profileAttempts(storedProfile), members)
+	} else {
+		slog.Warn("cohort benchmark skipped", "cohort", id, "error", err)
+	}
+
+	s.jsonResponse(w, http.StatusOK, response)
+}
+
+// cohortMembers loads a cohort's members, answering the request itself
+// when it can't
+func (s *Server) cohortMembers(w http.ResponseWriter, id string) ([]*cohort.Member, bool) {
+	if s.cohortStore == nil {
+		s.jsonError(w, http.StatusServiceUnavailable, "cohorts not available", nil)
+		return nil, false
+	}
+	members, err := s.cohortStore.Members(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, cohort.ErrNotFound):
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeCohortNotFound, "cohort not found", nil)
+		case errors.Is(err, cohort.ErrInvalidID):
+			s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, "invalid cohort id", nil)
+		default:
+			s.jsonError(w, http.StatusInternalServerError, "failed to load cohort", err)
+		}
+		return nil, false
+	}
+	return members, true
+}
+
+// handleCreateAssignment creates a session for every member of a cohort
+// (or the listed ones) against one exercise or spec. Members who already
+// have a session for it keep theirs. The response reports each member.
+func (s *Server) handleCreateAssignment(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ExerciseID string   `json:"exercise_id,omitempty"`
+		SpecPath   string   `json:"spec_path,omitempty"`
+		Track      string   `json:"track,omitempty"`
+		HintBudget *int     `json:"hint_budget,omitempty"`
+		Members    []string `json:"members,omitempty"` // profile IDs; empty for the whole cohort
+
+		Deadline *session.Deadline `json:"deadline,omitempty"`
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+	if (req.ExerciseID == "") == (req.SpecPath == "") {
+		s.validationError(w, &ValidationError{Fields: []FieldError{
+			{Field: "exercise_id", Message: "exactly one of exercise_id or spec_path is required"},
+		}})
+		return
+	}
+	if req.HintBudget != nil && *req.HintBudget < 0 {
+		s.validationError(w, &ValidationError{Fields: []FieldError{
+			{Field: "hint_budget", Message: "hint budget must be non-negative"},
+		}})
+		return
+	}
+	if err := req.Deadline.Validate(); err != nil {
+		s.validationError(w, &ValidationError{Fields: []FieldError{
+			{Field: "deadline", Message: err.Error()},
+		}})
+		return
+	}
+
+	id := r.PathValue("id")
+	members, ok := s.cohortMembers(w, id)
+	if !ok {
+		return
+	}
+	learners, unknown := assignees(members, req.Members)
+	if len(unknown) > 0 {
+		s.validationError(w, &ValidationError{Fields: []FieldError{
+			{Field: "members", Message: "not in the cohort: " + strings.Join(unknown, ", ")},
+		}})
+		return
+	}
+
+	policy := s.trackPolicy(req.Track)
+	if req.HintBudget != nil {
+		if policy == nil {
+			p := domain.DefaultPolicy()
+			policy = &p
+		}
+		policy.Budget.Tokens = *req.HintBudget
+	}
+
+	results, err := s.sessionService.Assign(r.Context(), session.AssignRequest{
+		CreateRequest: session.CreateRequest{
+			ExerciseID: req.ExerciseID,
+			SpecPath:   req.SpecPath,
+			Policy:     policy,
+		},
+		Cohort:   id,
+		Learners: learners,
+		Deadline: req.Deadline,
+	})
+	if err != nil {
+		s.createSessionError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	created := 0
+	for _, res := range results {
+		if res.Status == session.AssignCreated {
+			created++
+		}
+	}
+	if created > 0 {
+		status = http.StatusCreated
+	}
+	s.jsonResponse(w, status, map[string]interface{}{
+		"cohort":      id,
+		"exercise_id": req.ExerciseID,
+		"spec_path":   req.SpecPath,
+		"deadline":    req.Deadline,
+		"created":     created,
+		"results":     results,
+	})
+}
+
+// assignees picks the members work is assigned to: those listed by
+// profile ID, or everyone. It also returns the listed IDs that aren't
+// members.
+func assignees(members []*cohort.Member, ids []string) ([]session.Learner, []string) {
+	byID := make(map[string]*cohort.Member, len(members))
+	for _, m := range members {
+		byID[m.ProfileID] = m
+	}
+	if len(ids) == 0 {
+		learners := make([]session.Learner, 0, len(members))
+		for _, m := range members {
+			learners = append(learners, session.Learner{ID: m.ProfileID, Name: m.DisplayName})
+		}
+		return learners, nil
+	}
+
+	var learners []session.Learner
+	var unknown []string
+	for _, id := range ids {
+		m, ok := byID[id]
+		if !ok {
+			unknown = append(unknown, id)
+			continue
+		}
+		learners = append(learners, session.Learner{ID: m.ProfileID, Name: m.DisplayName})
+	}
+	return learners, unknown
+}
+
+// assignmentProgress is one member's session for an assignment
+type assignmentProgress struct {
+	Learner      string         `json:"learner"`
+	Name         string         `json:"name,omitempty"`
+	SessionID    string         `json:"session_id"`
+	Status       session.Status `json:"status"`
+	Started      bool           `json:"started"` // ran code or asked for help
+	RunCount     int            `json:"run_count"`
+	HintCount    int            `json:"hint_count"`
+	LastActiveAt *time.Time     `json:"last_active_at,omitempty"`
+	Late         bool           `json:"late,omitempty"`    // ran code after the deadline
+	Overdue      bool           `json:"overdue,omitempty"` // the deadline passed before completing
+}
+
+// assignmentSummary groups the sessions of one exercise or spec
+type assignmentSummary struct {
+	ExerciseID string               `json:"exercise_id,omitempty"`
+	SpecPath   string               `json:"spec_path,omitempty"`
+	AssignedAt time.Time            `json:"assigned_at"`
+	Total      int                  `json:"total"`
+	Started    int                  `json:"started"`
+	Completed  int                  `json:"completed"`
+	Late       int                  `json:"late"`
+	Overdue    int                  `json:"overdue"`
+	Deadline   *session.Deadline    `json:"deadline,omitempty"` // the latest of the members' deadlines
+	Learners   []assignmentProgress `json:"learners"`
+}
+
+// handleListAssignments shows, per assigned exercise or spec, which
+// members have started, which have finished and who is past the deadline
+func (s *Server) handleListAssignments(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, ok := s.cohortMembers(w, id); !ok {
+		return
+	}
+
+	sessions, err := s.sessionService.Assignments(r.Context(), id)
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to list assignments", err)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"cohort":      id,
+		"assignments": summarizeAssignments(sessions, time.Now()),
+	})
+}
+
+// summarizeAssignments groups assigned sessions by their work, most
+// recently assigned first. Deadlines are judged at now.
+func summarizeAssignments(sessions []*session.Session, now time.Time) []*assignmentSummary {
+	byWork := make(map[string]*assignmentSummary)
+	var summaries []*assignmentSummary
+	for _, sess := range sessions {
+		key := sess.ExerciseID + "\x00" + sess.SpecPath
+		sum, ok := byWork[key]
+		if !ok {
+			sum = &assignmentSummary{ExerciseID: sess.ExerciseID, SpecPath: sess.SpecPath, AssignedAt: sess.Assignment.AssignedAt}
+			byWork[key] = sum
+			summaries = append(summaries, sum)
+		}
+		if sess.Assignment.AssignedAt.Before(sum.AssignedAt) {
+			sum.AssignedAt = sess.Assignment.AssignedAt
+		}
+		deadline := sess.Assignment.Deadline
+		if deadline != nil && (sum.Deadline == nil || deadline.Cutoff().After(sum.Deadline.Cutoff())) {
+			sum.Deadline = deadline
+		}
+
+		p := assignmentProgress{
+			Learner:   sess.Assignment.Learner,
+			Name:      sess.Assignment.Name,
+			SessionID: sess.ID,
+			Status:    sess.Status,
+			Started:   sess.RunCount > 0 || sess.HintCount > 0,
+			RunCount:  sess.RunCount,
+			HintCount: sess.HintCount,
+		}
+		for _, t := range []*time.Time{sess.LastRunAt, sess.LastInterventionAt} {
+			if t != nil && (p.LastActiveAt == nil || t.After(*p.LastActiveAt)) {
+				p.LastActiveAt = t
+			}
+		}
+		p.Late = sess.LastRunAt != nil && deadline.Passed(*sess.LastRunAt)
+		p.Overdue = sess.Status != session.StatusCompleted && deadline.Passed(now)
+		sum.Total++
+		if p.Started {
+			sum.Started++
+		}
+		if sess.Status == session.StatusCompleted {
+			sum.Completed++
+		}
+		if p.Late {
+			sum.Late++
+		}
+		if p.Overdue {
+			sum.Overdue++
+		}
+		sum.Learners = append(sum.Learners, p)
+	}
+
+	for _, sum := range summaries {
+		sort.Slice(sum.Learners, func(i, j int) bool { return sum.Learners[i].Learner < sum.Learners[j].Learner })
+	}
+	sort.SliceStable(summaries, func(i, j int) bool { return summaries[i].AssignedAt.After(summaries[j].AssignedAt) })
+	if summaries == nil {
+		summaries = []*assignmentSummary{}
+	}
+	return summaries
+}
+
+// profileAttempts converts the local exercise history to cohort attempts
+func profileAttempts(p *profile.StoredProfile) []cohort.Attempt {
+	attempts := make([]cohort.Attempt, 0, len(p.ExerciseHistory))
+	for _, a := range p.ExerciseHistory {
+		attempts = append(attempts, cohort.Attempt{
+			ExerciseID:       a.ExerciseID,
+			StartedAt:        a.StartedAt,
+			CompletedAt:      a.CompletedAt,
+			RunCount:         a.RunCount,
+			HintCount:        a.HintCount,
+			TimeToCompleteMs: a.TimeToCompleteMs,
+			Success:          a.Success,
+		})
+	}
+	return attempts
+}
@@ -0,0 +1,291 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/cohort"
+	"github.com/felixgeelhaar/temper/internal/profile"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+const cohortExport = `{"event":"summary","profile_id":"a1b2c3","display_name":"ada","leaderboard":true}
+{"event":"attempt","exercise_id":"go-v1/basics/hello","started_at":"2026-01-01T00:00:00Z","completed_at":"2026-01-01T00:10:00Z","run_count":2,"hint_count":0,"success":true}
+`
+
+func setupCohortServer(t *testing.T) *serverWithMocks {
+	t.Helper()
+	m := newServerWithMocks()
+	m.server.cohortStore = cohort.NewStore(t.TempDir())
+	return m
+}
+
+func TestCohort_AddAndLeaderboard(t *testing.T) {
+	m := setupCohortServer(t)
+	done := time.Now()
+	m.profiles.getProfileFn = func(ctx context.Context) (*profile.StoredProfile, error) {
+		return &profile.StoredProfile{ExerciseHistory: []profile.ExerciseAttempt{
+			{ExerciseID: "go-v1/basics/hello", CompletedAt: &done, Success: true},
+			{ExerciseID: "go-v1/basics/maps", CompletedAt: &done, Success: true},
+		}}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/cohorts/go-101/members", strings.NewReader(cohortExport))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add member: expected %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/cohorts/go-101/leaderboard?anonymize=true", nil)
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("leaderboard: expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Entries   []cohort.Entry    `json:"entries"`
+		Members   int               `json:"members"`
+		Benchmark *cohort.Benchmark `json:"benchmark"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Members != 1 || len(resp.Entries) != 1 {
+		t.Fatalf("unexpected board: %+v", resp)
+	}
+	if resp.Entries[0].Name != "anon-a1b2c3" || resp.Entries[0].ExercisesCompleted != 1 {
+		t.Errorf("unexpected entry: %+v", resp.Entries[0])
+	}
+	if resp.Benchmark == nil || resp.Benchmark.You.ExercisesCompleted != 2 {
+		t.Errorf("expected a benchmark for the local profile, got %+v", resp.Benchmark)
+	}
+}
+
+func TestCohort_Errors(t *testing.T) {
+	m := setupCohortServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"unknown cohort", http.MethodGet, "/v1/cohorts/nobody/leaderboard", "", http.StatusNotFound, ErrCodeCohortNotFound},
+		{"bad export", http.MethodPost, "/v1/cohorts/go-101/members", `{"event":"attempt"}`, http.StatusBadRequest, ErrCodeInvalidPayload},
+		{"bad id", http.MethodPost, "/v1/cohorts/..bad/members", cohortExport, http.StatusBadRequest, ErrCodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			m.server.router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			var resp map[string]interface{}
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			if resp["error_code"] != tt.code {
+				t.Errorf("error_code = %v, want %s", resp["error_code"], tt.code)
+			}
+		})
+	}
+}
+
+func TestCohort_CreateAssignment(t *testing.T) {
+	m := setupCohortServer(t)
+	for _, export := range []string{cohortExport, `{"event":"summary","profile_id":"d4e5f6"}` + "\n"} {
+		if _, err := m.server.cohortStore.Add("go-101", []byte(export)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got session.AssignRequest
+	m.sessions.assignFn = func(ctx context.Context, req session.AssignRequest) ([]session.AssignResult, error) {
+		got = req
+		results := make([]session.AssignResult, 0, len(req.Learners))
+		for _, l := range req.Learners {
+			results = append(results, session.AssignResult{Learner: l.ID, Name: l.Name, SessionID: "s-" + l.ID, Status: session.AssignCreated})
+		}
+		return results, nil
+	}
+
+	body := `{"exercise_id":"go-v1/basics/hello","hint_budget":500}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/cohorts/go-101/assignments", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Created int                    `json:"created"`
+		Results []session.AssignResult `json:"results"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Created != 2 || len(resp.Results) != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if got.Cohort != "go-101" || got.ExerciseID != "go-v1/basics/hello" || len(got.Learners) != 2 {
+		t.Errorf("unexpected assign request: %+v", got)
+	}
+	if got.Policy == nil || got.Policy.Budget.Tokens != 500 {
+		t.Errorf("Policy = %+v, want a 500 token budget", got.Policy)
+	}
+
+	// Listed members only
+	body = `{"exercise_id":"go-v1/basics/hello","members":["a1b2c3"]}`
+	req = httptest.NewRequest(http.MethodPost, "/v1/cohorts/go-101/assignments", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated || len(got.Learners) != 1 || got.Learners[0].Name != "ada" {
+		t.Errorf("members filter: got %d, learners %+v", w.Code, got.Learners)
+	}
+
+	// A deadline is passed through
+	body = `{"exercise_id":"go-v1/basics/hello","deadline":{"due":"2026-03-08T17:00:00Z","grace_seconds":600,"late_policy":"lock"}}`
+	req = httptest.NewRequest(http.MethodPost, "/v1/cohorts/go-101/assignments", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if d := got.Deadline; w.Code != http.StatusCreated || d == nil || d.GraceSeconds != 600 || !d.Locks() {
+		t.Errorf("deadline: got %d, deadline %+v", w.Code, got.Deadline)
+	}
+
+	for _, body := range []string{
+		`{"exercise_id":"go-v1/basics/hello","deadline":{"due":"2026-03-08T17:00:00Z","late_policy":"drop"}}`,
+		`{"exercise_id":"go-v1/basics/hello","deadline":{"grace_seconds":60}}`,
+		`{"exercise_id":"go-v1/basics/hello","members":["nobody"]}`,
+		`{"exercise_id":"go-v1/basics/hello","spec_path":"a.yaml"}`,
+		`{}`,
+	} {
+		req = httptest.NewRequest(http.MethodPost, "/v1/cohorts/go-101/assignments", strings.NewReader(body))
+		w = httptest.NewRecorder()
+		m.server.router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected %d, got %d: %s", body, http.StatusBadRequest, w.Code, w.Body.String())
+		}
+	}
+
+	m.sessions.assignFn = func(ctx context.Context, req session.AssignRequest) ([]session.AssignResult, error) {
+		return nil, session.ErrExerciseNotFound
+	}
+	req = httptest.NewRequest(http.MethodPost, "/v1/cohorts/go-101/assignments", strings.NewReader(`{"exercise_id":"go-v1/nope"}`))
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown exercise: expected %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestCohort_ListAssignments(t *testing.T) {
+	m := setupCohortServer(t)
+	if _, err := m.server.cohortStore.Add("go-101", []byte(cohortExport)); err != nil {
+		t.Fatal(err)
+	}
+
+	assigned := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	ran := assigned.Add(time.Hour)
+	m.sessions.assignmentsFn = func(ctx context.Context, cohortID string) ([]*session.Session, error) {
+		return []*session.Session{
+			{ID: "s1", ExerciseID: "go-v1/basics/hello", Status: session.StatusCompleted, RunCount: 3, LastRunAt: &ran,
+				Assignment: &session.Assignment{Cohort: cohortID, Learner: "b", AssignedAt: assigned}},
+			{ID: "s2", ExerciseID: "go-v1/basics/hello", Status: session.StatusActive,
+				Assignment: &session.Assignment{Cohort: cohortID, Learner: "a", Name: "ada", AssignedAt: assigned}},
+			{ID: "s3", SpecPath: "specs/api.yaml", Status: session.StatusActive, HintCount: 1,
+				Assignment: &session.Assignment{Cohort: cohortID, Learner: "a", AssignedAt: assigned.Add(24 * time.Hour)}},
+		}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/cohorts/go-101/assignments", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Assignments []assignmentSummary `json:"assignments"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Assignments) != 2 || resp.Assignments[0].SpecPath != "specs/api.yaml" {
+		t.Fatalf("expected the spec first, then the exercise: %+v", resp.Assignments)
+	}
+	hello := resp.Assignments[1]
+	if hello.Total != 2 || hello.Started != 1 || hello.Completed != 1 {
+		t.Errorf("unexpected counts: %+v", hello)
+	}
+	if hello.Learners[0].Learner != "a" || hello.Learners[0].Started || !hello.Learners[1].Started {
+		t.Errorf("unexpected learners: %+v", hello.Learners)
+	}
+	if at := hello.Learners[1].LastActiveAt; at == nil || !at.Equal(ran) {
+		t.Errorf("LastActiveAt = %v, want %v", at, ran)
+	}
+
+	if hello.Deadline != nil || hello.Late != 0 {
+		t.Errorf("unexpected deadline: %+v", hello)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/cohorts/nobody/assignments", nil)
+
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown cohort: expected %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestSummarizeAssignments_Deadline(t *testing.T) {
+	due := time.Date(2026, 3, 8, 17, 0, 0, 0, time.UTC)
+	before, after := due.Add(-time.Hour), due.Add(time.Hour)
+	deadline := &session.Deadline{Due: due, GraceSeconds: 60}
+	assignment := func(learner string) *session.Assignment {
+		return &session.Assignment{Cohort: "go-101", Learner: learner, Deadline: deadline}
+	}
+
+	sessions := []*session.Session{
+		{ID: "s1", ExerciseID: "hello", Status: session.StatusCompleted, RunCount: 1, LastRunAt: &before, Assignment: assignment("a")},
+		{ID: "s2", ExerciseID: "hello", Status: session.StatusCompleted, RunCount: 4, LastRunAt: &after, Assignment: assignment("b")},
+		{ID: "s3", ExerciseID: "hello", Status: session.StatusActive, Assignment: assignment("c")},
+	}
+
+	sum := summarizeAssignments(sessions, before)[0]
+	if sum.Deadline == nil || !sum.Deadline.Due.Equal(due) {
+		t.Fatalf("Deadline = %+v, want %v", sum.Deadline, due)
+	}
+	if sum.Late != 1 || sum.Overdue != 0 || !sum.Learners[1].Late {
+		t.Errorf("before the deadline: %+v", sum)
+	}
+
+	sum = summarizeAssignments(sessions, after)[0]
+	if sum.Overdue != 1 || !sum.Learners[2].Overdue || sum.Learners[0].Late {
+		t.Errorf("after the deadline: %+v", sum)
+	}
+}
+
+func TestCohort_RunAfterLockedDeadline(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.runCodeFn = func(ctx context.Context, sessionID string, req session.RunRequest) (*session.Run, error) {
+		return nil, session.ErrDeadlinePassed
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/s1/runs", strings.NewReader(`{"test":true}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), ErrCodeDeadlinePassed) {
+		t.Fatalf("expected %d %s, got %d: %s", http.StatusForbidden, ErrCodeDeadlinePassed, w.Code, w.Body.String())
+	}
+}
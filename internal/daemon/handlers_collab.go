@@ -0,0 +1,90 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Collaboration handlers (mob mode)
+//
+// Several editors can attach to one session. Each joins to get a client
+// ID, sends it as X-Temper-Client on later requests and keeps the session's
+// event stream open (?client=<id>) to stay in. Only the driver may change
+// code; the lock moves with POST .../collab/driver.
+
+func (s *Server) handleGetCollab(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.liveSession(w, r, id) {
+		return
+	}
+	state, ok := s.collab.state(id)
+	if !ok {
+		state = CollabState{SessionID: id, Participants: []Participant{}}
+	}
+	s.jsonResponse(w, http.StatusOK, state)
+}
+
+func (s *Server) handleJoinCollab(w http.ResponseWriter, r *http.Request) {
+	if s.collab == nil {
+		s.jsonError(w, http.StatusServiceUnavailable, "collaboration not available", nil)
+		return
+	}
+
+	var req struct {
+		ClientID string `json:"client_id,omitempty" validate:"max=64"` // rejoin with an earlier ID
+		Name     string `json:"name,omitempty" validate:"max=64"`
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	id := r.PathValue("id")
+	if !s.liveSession(w, r, id) {
+		return
+	}
+
+	me, state := s.collab.join(id, req.ClientID, req.Name)
+	s.events.notify(id)
+
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"client_id": me.ClientID,
+		"role":      me.Role,
+		"collab":    state,
+	})
+}
+
+func (s *Server) handleLeaveCollab(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	state, ok := s.collab.leave(id, r.Header.Get(ClientHeader))
+	if !ok {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "client has not joined the session", nil)
+		return
+	}
+	s.events.notify(id)
+	s.jsonResponse(w, http.StatusOK, state)
+}
+
+// handleHandOverDriver passes the driver lock from the requesting client
+// to client_id, or takes it when nobody is driving or the driver is stale
+func (s *Server) handleHandOverDriver(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ClientID string `json:"client_id,omitempty"` // new driver; empty = the requester
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	id := r.PathValue("id")
+	state, err := s.collab.handOver(id, r.Header.Get(ClientHeader), req.ClientID)
+	switch {
+	case errors.Is(err, errNotParticipant):
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "client has not joined the session", nil)
+		return
+	case errors.Is(err, errDriverLocked):
+		s.jsonErrorCode(w, http.StatusConflict, ErrCodeDriverLocked,
+			"another participant is driving; ask them to hand over", nil)
+		return
+	}
+	s.events.notify(id)
+	s.jsonResponse(w, http.StatusOK, state)
+}
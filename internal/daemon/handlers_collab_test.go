@@ -0,0 +1,201 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// setupCollabServer serves one active session whose files the workspace
+// push mock replaces
+func setupCollabServer(t *testing.T) *serverWithMocks {
+	t.Helper()
+	m := newServerWithMocks()
+	m.server.events = newSessionEvents()
+	m.server.collab = newCollaboration()
+
+	var mu sync.Mutex
+	code := map[string]string{"main.go": "package main\n"}
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		if id != "sess-1" {
+			return nil, session.ErrSessionNotFound
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return &session.Session{ID: id, Status: session.StatusActive, Code: code}, nil
+	}
+	m.sessions.pushWorkspaceFn = func(ctx context.Context, id string, push session.WorkspacePush) (*session.WorkspaceManifest, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		code = push.Files
+		manifest := session.ManifestOf(code)
+		return &manifest, nil
+	}
+	return m
+}
+
+func collabRequest(m *serverWithMocks, method, path, client, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if client != "" {
+		req.Header.Set(ClientHeader, client)
+	}
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCollab_DriverLock(t *testing.T) {
+	m := setupCollabServer(t)
+
+	w := collabRequest(m, http.MethodPost, "/v1/sessions/sess-1/collab/join", "", `{"name":"ada"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("join: %d %s", w.Code, w.Body.String())
+	}
+	var joined struct {
+		ClientID string `json:"client_id"`
+		Role     string `json:"role"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &joined)
+	if joined.ClientID == "" || joined.Role != RoleDriver {
+		t.Fatalf("first client should drive: %+v", joined)
+	}
+	collabRequest(m, http.MethodPost, "/v1/sessions/sess-1/collab/join", "", `{"client_id":"bob"}`)
+
+	push := `{"files":{"main.go":"package main\n\nfunc main() {}\n"}}`
+	for _, tt := range []struct {
+		client string
+		status int
+	}{
+		{"bob", http.StatusConflict},
+		{"", http.StatusConflict},
+		{joined.ClientID, http.StatusOK},
+	} {
+		w := collabRequest(m, http.MethodPatch, "/v1/sessions/sess-1/workspace", tt.client, push)
+		if w.Code != tt.status {
+			t.Errorf("push as %q: expected %d, got %d: %s", tt.client, tt.status, w.Code, w.Body.String())
+		}
+		if tt.status == http.StatusConflict && !strings.Contains(w.Body.String(), ErrCodeDriverLocked) {
+			t.Errorf("push as %q: expected %s, got %s", tt.client, ErrCodeDriverLocked, w.Body.String())
+		}
+	}
+
+	if w := collabRequest(m, http.MethodPost, "/v1/sessions/sess-1/collab/driver", "bob", `{}`); w.Code != http.StatusConflict {
+		t.Errorf("navigator taking a held lock: expected %d, got %d", http.StatusConflict, w.Code)
+	}
+	w = collabRequest(m, http.MethodPost, "/v1/sessions/sess-1/collab/driver", joined.ClientID, `{"client_id":"bob"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("hand over: %d %s", w.Code, w.Body.String())
+	}
+	if w := collabRequest(m, http.MethodPatch, "/v1/sessions/sess-1/workspace", "bob", push); w.Code != http.StatusOK {
+		t.Errorf("push by new driver: %d %s", w.Code, w.Body.String())
+	}
+
+	w = collabRequest(m, http.MethodGet, "/v1/sessions/sess-1/collab", "", "")
+	var state CollabState
+	if err := json.Unmarshal(w.Body.Bytes(), &state); err != nil || state.Driver != "bob" || len(state.Participants) != 2 {
+		t.Errorf("unexpected collab state: %v %+v", err, state)
+	}
+}
+
+func TestCollab_Broadcast(t *testing.T) {
+	m := setupCollabServer(t)
+	m.server.collab.join("sess-1", "ada", "")
+	m.server.collab.join("sess-1", "bob", "")
+
+	ts := httptest.NewServer(m.server.router)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v1/sessions/sess-1/events?client=bob", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var events []string
+	var event string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "event: ") {
+			event = strings.TrimPrefix(line, "event: ")
+			events = append(events, event)
+			continue
+		}
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		data := []byte(strings.TrimPrefix(line, "data: "))
+
+		switch event {
+		case "collab":
+			// The driver edits once the navigator is attached
+			w := collabRequest(m, http.MethodPatch, "/v1/sessions/sess-1/workspace", "ada",
+				`{"files":{"main.go":"package main\n\nfunc main() {}\n"}}`)
+			if w.Code != http.StatusOK {
+				t.Fatalf("push: %d %s", w.Code, w.Body.String())
+			}
+		case "workspace":
+			var ws struct {
+				By    string            `json:"by"`
+				Files map[string]string `json:"files"`
+			}
+			if err := json.Unmarshal(data, &ws); err != nil || ws.By != "ada" || ws.Files["main.go"] == "" {
+				t.Fatalf("unexpected workspace event: %v %s", err, data)
+			}
+			m.server.events.intervention(session.Intervention{
+				ID: "iv-1", SessionID: "sess-1", Content: "What does the test expect?", CreatedAt: time.Now(),
+			})
+		case "intervention":
+			var iv session.Intervention
+			if err := json.Unmarshal(data, &iv); err != nil || iv.ID != "iv-1" {
+				t.Fatalf("unexpected intervention event: %v %s", err, data)
+			}
+			want := "cooldown,collab,workspace,intervention"
+			if got := strings.Join(events, ","); got != want {
+				t.Errorf("events = %s, want %s", got, want)
+			}
+			return
+		}
+	}
+	t.Fatalf("stream ended early: %v (%v)", events, scanner.Err())
+}
+
+func TestCollab_StreamCloseLeaves(t *testing.T) {
+	m := setupCollabServer(t)
+	m.server.collab.join("sess-1", "ada", "")
+	m.server.collab.join("sess-1", "bob", "")
+
+	ts := httptest.NewServer(m.server.router)
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v1/sessions/sess-1/events?client=ada", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	// Wait for the stream to be established before dropping it
+	_, _ = bufio.NewReader(resp.Body).ReadString('\n')
+	cancel()
+	_ = resp.Body.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if state, _ := m.server.collab.state("sess-1"); state.Driver == "bob" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("the driver's lock should pass on when its stream closes")
+}
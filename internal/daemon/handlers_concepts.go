@@ -0,0 +1,85 @@
+package daemon
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// handleListConcepts lists the glossary
+func (s *Server) handleListConcepts(w http.ResponseWriter, r *http.Request) {
+	params, err := parseListParams(r.URL.Query(), conceptListFields, []string{"language"}, "")
+	if err != nil {
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error(), nil)
+		return
+	}
+
+	concepts, total := applyList(s.concepts.List(), params, conceptListFields)
+
+	resp := pageInfo(params, total)
+	resp["concepts"] = concepts
+	s.jsonResponse(w, http.StatusOK, resp)
+}
+
+// handleGetConcept returns a concept's explanation with the exercises that
+// practice it and short summaries of related concepts
+func (s *Server) handleGetConcept(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.concepts.Get(r.PathValue("id"))
+	if !ok {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "concept not found", nil)
+		return
+	}
+
+	exercises := make([]map[string]interface{}, 0)
+	for _, ex := range c.Exercises(s.allExercises()) {
+		exercises = append(exercises, map[string]interface{}{
+			"id":         ex.ID,
+			"title":      ex.Title,
+			"difficulty": ex.Difficulty,
+		})
+	}
+
+	related := make([]map[string]interface{}, 0, len(c.Related))
+	for _, id := range c.Related {
+		if rc, ok := s.concepts.Get(id); ok {
+			related = append(related, map[string]interface{}{
+				"id":      rc.ID,
+				"name":    rc.Name,
+				"summary": rc.Summary,
+			})
+		}
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"id":        c.ID,
+		"name":      c.Name,
+		"summary":   c.Summary,
+		"language":  c.Language,
+		"exercises": exercises,
+		"related":   related,
+	})
+}
+
+// allExercises loads every exercise in every pack. Packs that fail to load
+// are logged and skipped.
+func (s *Server) allExercises() []*domain.Exercise {
+	if s.exerciseLoader == nil {
+		return nil
+	}
+	packs, err := s.exerciseLoader.LoadAllPacks()
+	if err != nil {
+		slog.Warn("failed to load exercise packs", "error", err)
+		return nil
+	}
+	var exercises []*domain.Exercise
+	for _, pack := range packs {
+		packExercises, err := s.exerciseLoader.LoadPackExercises(pack.ID)
+		if err != nil {
+			slog.Warn("failed to load pack exercises", "pack", pack.ID, "error", err)
+			continue
+		}
+		exercises = append(exercises, packExercises...)
+	}
+	return exercises
+}
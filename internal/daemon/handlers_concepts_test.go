@@ -0,0 +1,120 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/exercise"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+func TestHandleGetConcept(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.exerciseLoader = exercise.NewLoader("../../exercises")
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/concepts/slices", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		ID        string `json:"id"`
+		Summary   string `json:"summary"`
+		Exercises []struct {
+			ID string `json:"id"`
+		} `json:"exercises"`
+		Related []struct {
+			ID string `json:"id"`
+		} `json:"related"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.ID != "slices" || resp.Summary == "" {
+		t.Errorf("unexpected concept: %+v", resp)
+	}
+	linked := false
+	for _, ex := range resp.Exercises {
+		if ex.ID == "go-v1/basics/slices" {
+			linked = true
+		}
+	}
+	if !linked {
+		t.Errorf("exercises = %+v; want go-v1/basics/slices linked", resp.Exercises)
+	}
+	if len(resp.Related) == 0 {
+		t.Error("want related concepts")
+	}
+}
+
+func TestHandleGetConcept_NotFound(t *testing.T) {
+	m := newServerWithMocks()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/concepts/nope", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+}
+
+func TestHandleListConcepts(t *testing.T) {
+	m := newServerWithMocks()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/concepts?language=go&limit=3", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Concepts []struct {
+			ID string `json:"id"`
+		} `json:"concepts"`
+		Total int `json:"total"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Concepts) != 3 || resp.Total <= 3 {
+		t.Errorf("got %d concepts of %d; want a page of 3", len(resp.Concepts), resp.Total)
+	}
+}
+
+func TestHandleHint_RecordsConcepts(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: id, Status: session.StatusActive, Policy: domain.DefaultPolicy(),
+			Code: map[string]string{"main.go": "package main"}}, nil
+	}
+	concepts := []string{"goroutines", "channels"}
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		return &domain.Intervention{ID: uuid.New(), Intent: req.Intent, Level: domain.L1CategoryHint,
+			Content: "a channel can hand results back", Concepts: concepts}, nil
+	}
+	var recorded *session.Intervention
+	m.sessions.recordInterventionFn = func(ctx context.Context, iv *session.Intervention) error {
+		recorded = iv
+		return nil
+	}
+
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/sessions/"+uuid.New().String()+"/hint", strings.NewReader(`{}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if recorded == nil || !reflect.DeepEqual(recorded.Concepts, concepts) {
+		t.Errorf("recorded intervention = %+v; want concepts %v", recorded, concepts)
+	}
+}
@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// escalationMinHints is how many hints a session needs before L4/L5 can
+// be requested
+const escalationMinHints = 2
+
+// contractLevel is one intervention level as the contract stands for a
+// session right now
+type contractLevel struct {
+	Level       domain.InterventionLevel `json:"level"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Available   bool                     `json:"available"`
+	Rule        string                   `json:"rule,omitempty"` // what holds the level back
+}
+
+// handleGetContract returns the live learning-contract state of a session:
+// the track's caps, the cooldown, test-first and escalation, and which
+// levels can be requested right now
+func (s *Server) handleGetContract(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.sessionService.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if err == session.ErrSessionNotFound {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+			return
+		}
+		s.jsonError(w, http.StatusInternalServerError, "failed to get session", err)
+		return
+	}
+
+	policy := sess.Policy
+	remaining := sess.CooldownRemaining()
+
+	var pairingCtx pairing.InterventionContext
+	s.attachFeatureContext(r.Context(), sess, &pairingCtx)
+	held := pairing.HeldBackFeature(pairing.InterventionRequest{Context: pairingCtx, Policy: policy})
+
+	testFirst := map[string]interface{}{
+		"enabled": policy.TestFirst,
+		"holding": held != nil,
+	}
+	if held != nil {
+		testFirst["feature"] = held.ID
+		testFirst["max_level"] = pairing.TestFirstCeiling
+	}
+
+	// The pairing handlers refuse every request while the cooldown runs
+	coolingDown := !sess.CanRequestIntervention(domain.L3ConstrainedSnippet)
+	escalationReady := sess.HintCount >= escalationMinHints
+	budget := sess.Budget()
+	levels := make([]contractLevel, 0, int(domain.L5FullSolution)+1)
+	for l := domain.L0Clarify; l <= domain.L5FullSolution; l++ {
+		cl := contractLevel{Level: l, Name: l.String(), Description: l.Description()}
+		switch {
+		case l >= domain.L4PartialSolution && !escalationReady:
+			cl.Rule = domain.RuleEscalation
+		case l > policy.MaxLevel && l < domain.L4PartialSolution:
+			cl.Rule = domain.RuleLevelCap
+		case held != nil && l > pairing.TestFirstCeiling:
+			cl.Rule = domain.RuleTestFirst
+		case budget.Enabled && policy.Budget.Cost(l) > budget.Remaining:
+			cl.Rule = domain.RuleBudget
+		case coolingDown:
+			cl.Rule = domain.RuleCooldown
+		}
+		cl.Available = cl.Rule == "" && sess.Status == session.StatusActive
+		levels = append(levels, cl)
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"session_id":           sess.ID,
+		"status":               sess.Status,
+		"track":                policy.Track,
+		"max_level":            policy.MaxLevel,
+		"patching_enabled":     policy.PatchingEnabled,
+		"cooldown_seconds":     policy.CooldownSeconds,
+		"cooldown_remaining":   remaining.Seconds(),
+		"last_intervention_at": sess.LastInterventionAt,
+		"test_first":           testFirst,
+		"budget":               budget,
+		"escalation": map[string]interface{}{
+			"available":      escalationReady && sess.Status == session.StatusActive,
+			"hints_required": escalationMinHints,
+			"hint_count":     sess.HintCount,
+		},
+		"levels": levels,
+	})
+}
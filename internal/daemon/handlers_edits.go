@@ -0,0 +1,93 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/editlog"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// Edit telemetry handlers (opt-in typing/paste patterns per session)
+
+// RecordEditsRequest is a batch of coarse edit events from an editor
+type RecordEditsRequest struct {
+	Events []editlog.Event `json:"events" validate:"required,max=500"`
+}
+
+// liveSession writes a 404 or 500 and returns false when the session
+// can't be loaded
+func (s *Server) liveSession(w http.ResponseWriter, r *http.Request, id string) bool {
+	if _, err := s.sessionService.Get(r.Context(), id); err != nil {
+		if err == session.ErrSessionNotFound {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+			return false
+		}
+		s.jsonError(w, http.StatusInternalServerError, "failed to get session", err)
+		return false
+	}
+	return true
+}
+
+// handleRecordEdits appends editor events to the session's local log. It
+// is refused unless telemetry.edit_events is on, so an editor can't record
+// anything the learner hasn't agreed to.
+func (s *Server) handleRecordEdits(w http.ResponseWriter, r *http.Request) {
+	if s.editLog == nil {
+		s.jsonErrorCode(w, http.StatusForbidden, ErrCodeForbidden,
+			"edit telemetry is off; set telemetry.edit_events: true in ~/.temper/config.yaml to opt in", nil)
+		return
+	}
+
+	var req RecordEditsRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	id := r.PathValue("id")
+	if !s.liveSession(w, r, id) {
+		return
+	}
+
+	n, err := s.editLog.Append(id, req.Events)
+	if err != nil {
+		if errors.Is(err, editlog.ErrInvalidSession) {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+			return
+		}
+		if errors.Is(err, editlog.ErrBatchTooLarge) {
+			s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error(), nil)
+			return
+		}
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeInvalidPayload, err.Error(), nil)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusAccepted, map[string]interface{}{
+		"recorded": n,
+	})
+}
+
+// handleGetEdits summarizes how much of the session's code was typed and
+// how much pasted
+func (s *Server) handleGetEdits(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.liveSession(w, r, id) {
+		return
+	}
+
+	events := []editlog.Event{}
+	if s.editLog != nil {
+		var err error
+		if events, err = s.editLog.Events(id); err != nil {
+			s.jsonError(w, http.StatusInternalServerError, "failed to read edit events", err)
+			return
+		}
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"enabled": s.editLog != nil,
+		"summary": editlog.Summarize(events),
+		"events":  len(events),
+	})
+}
@@ -0,0 +1,111 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/editlog"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func setupEditsServer(t *testing.T, enabled bool) *serverWithMocks {
+	t.Helper()
+	m := newServerWithMocks()
+	if enabled {
+		m.server.editLog = editlog.NewStore(t.TempDir())
+	}
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		if id != "sess-1" {
+			return nil, session.ErrSessionNotFound
+		}
+		return &session.Session{ID: id}, nil
+	}
+	return m
+}
+
+func TestEdits_RecordAndSummarize(t *testing.T) {
+	m := setupEditsServer(t, true)
+
+	body := `{"events":[
+		{"kind":"typing","chars":120,"count":130,"duration_ms":30000},
+		{"kind":"paste","chars":280,"lines":14}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/sess-1/edits", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("record: expected %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/sessions/sess-1/edits", nil)
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("summary: expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Enabled bool            `json:"enabled"`
+		Summary editlog.Summary `json:"summary"`
+		Events  int             `json:"events"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.Enabled || resp.Events != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.Summary.TypedChars != 120 || resp.Summary.PastedChars != 280 || resp.Summary.LargePastes != 1 {
+		t.Errorf("unexpected summary: %+v", resp.Summary)
+	}
+}
+
+func TestEdits_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		method  string
+		path    string
+		body    string
+		status  int
+		code    string
+	}{
+		{"disabled", false, http.MethodPost, "/v1/sessions/sess-1/edits", `{"events":[{"kind":"typing"}]}`, http.StatusForbidden, ErrCodeForbidden},
+		{"unknown session", true, http.MethodPost, "/v1/sessions/other/edits", `{"events":[{"kind":"typing"}]}`, http.StatusNotFound, ErrCodeSessionNotFound},
+		{"no events", true, http.MethodPost, "/v1/sessions/sess-1/edits", `{"events":[]}`, http.StatusBadRequest, ErrCodeValidationFailed},
+		{"unknown kind", true, http.MethodPost, "/v1/sessions/sess-1/edits", `{"events":[{"kind":"keystroke"}]}`, http.StatusBadRequest, ErrCodeInvalidPayload},
+		{"summary of unknown session", true, http.MethodGet, "/v1/sessions/other/edits", "", http.StatusNotFound, ErrCodeSessionNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := setupEditsServer(t, tt.enabled)
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			m.server.router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.code) {
+				t.Errorf("expected code %s in %s", tt.code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestEdits_SummaryWhenDisabled(t *testing.T) {
+	m := setupEditsServer(t, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/sessions/sess-1/edits", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"enabled":false`) {
+		t.Errorf("expected enabled=false, got %s", w.Body.String())
+	}
+}
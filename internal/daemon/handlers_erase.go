@@ -0,0 +1,130 @@
+package daemon
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// History erasure handlers (profile reset and selective deletion)
+
+// eraseDateLayout is the day format accepted besides RFC 3339
+const eraseDateLayout = "2006-01-02"
+
+// handleResetProfile erases every session and the learning profile with
+// everything derived from them. Config, tracks and keys stay.
+func (s *Server) handleResetProfile(w http.ResponseWriter, r *http.Request) {
+	s.erase(w, r, session.EraseScope{All: true})
+}
+
+// handleEraseHistory erases the sessions on a topic and/or in a date
+// range, given as ?topic=go/basics&since=2026-01-01&until=2026-01-31.
+// A day-only until includes that day.
+func (s *Server) handleEraseHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	scope := session.EraseScope{Topic: strings.Trim(strings.TrimSpace(q.Get("topic")), "/")}
+
+	var fields []FieldError
+	var err error
+	if scope.Since, err = parseEraseDate(q.Get("since"), false); err != nil {
+		fields = append(fields, FieldError{Field: "since", Message: "must be YYYY-MM-DD or RFC 3339"})
+	}
+	if scope.Until, err = parseEraseDate(q.Get("until"), true); err != nil {
+		fields = append(fields, FieldError{Field: "until", Message: "must be YYYY-MM-DD or RFC 3339"})
+	}
+	if len(fields) == 0 && !scope.Since.IsZero() && !scope.Until.IsZero() && !scope.Since.Before(scope.Until) {
+		fields = append(fields, FieldError{Field: "until", Message: "must be after since"})
+	}
+	if len(fields) == 0 && scope.Empty() {
+		fields = append(fields, FieldError{Field: "topic", Message: "give a topic, a date range or both; DELETE /v1/profile erases everything"})
+	}
+	if len(fields) > 0 {
+		s.validationError(w, &ValidationError{Fields: fields})
+		return
+	}
+
+	s.erase(w, r, scope)
+}
+
+// parseEraseDate parses a day or a timestamp; an empty value is the zero
+// time. A day read as an upper bound runs to the end of that day.
+func parseEraseDate(v string, upper bool) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation(eraseDateLayout, v, time.Local); err == nil {
+		if upper {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// erase deletes the sessions in scope and scrubs everything kept about
+// them outside the session store
+func (s *Server) erase(w http.ResponseWriter, r *http.Request, scope session.EraseScope) {
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	report, err := s.sessionService.Erase(r.Context(), scope, dryRun)
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "erase failed", err)
+		return
+	}
+	if !dryRun {
+		s.scrubErased(scope, report)
+	}
+	s.jsonResponse(w, http.StatusOK, report)
+}
+
+// scrubErased removes erased sessions from the patch audit log, edit logs,
+// flashcards, achievements and the response cache. Erasing everything
+// clears them outright, including entries of sessions pruned earlier.
+func (s *Server) scrubErased(scope session.EraseScope, report *session.EraseReport) {
+	var sessionIDs map[string]bool
+	var exercises []string
+	if !scope.All {
+		sessionIDs = make(map[string]bool, len(report.Sessions))
+		for _, id := range report.Sessions {
+			sessionIDs[id] = true
+		}
+		exercises = report.Exercises
+	}
+
+	if s.patchService != nil {
+		if logger := s.patchService.GetLogger(); logger != nil {
+			if _, err := logger.Forget(sessionIDs); err != nil {
+				slog.Warn("erase patch log entries", "error", err)
+			}
+		}
+	}
+	if s.editLog != nil {
+		if scope.All {
+			if _, err := s.editLog.Prune(time.Now().Add(time.Minute)); err != nil {
+				slog.Warn("erase edit events", "error", err)
+			}
+		}
+		for _, id := range report.Sessions {
+			if err := s.editLog.Delete(id); err != nil {
+				slog.Warn("erase edit events", "session_id", id, "error", err)
+			}
+		}
+	}
+	if s.cardStore != nil && (scope.All || len(exercises) > 0) {
+		if _, err := s.cardStore.Forget(exercises); err != nil {
+			slog.Warn("erase flashcards", "error", err)
+		}
+	}
+	if s.achievements != nil {
+		if _, err := s.achievements.Forget(sessionIDs); err != nil {
+			slog.Warn("erase achievements", "error", err)
+		}
+	}
+	if s.idempotency != nil {
+		s.idempotency.Forget(sessionIDs)
+	}
+	s.events.forget(sessionIDs)
+	slog.Info("erased history scrubbed", "all", scope.All, "sessions", len(report.Sessions))
+}
@@ -0,0 +1,103 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/achievement"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func TestHandleEraseHistory(t *testing.T) {
+	m := newServerWithMocks()
+	var got session.EraseScope
+	var gotDryRun bool
+	m.sessions.eraseFn = func(ctx context.Context, scope session.EraseScope, dryRun bool) (*session.EraseReport, error) {
+		got, gotDryRun = scope, dryRun
+		return &session.EraseReport{DryRun: dryRun, Sessions: []string{"s1"}, Runs: 2}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/profile/history?topic=go/basics&since=2026-01-01&until=2026-01-31&dry_run=true", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if got.All || got.Topic != "go/basics" || !gotDryRun {
+		t.Errorf("scope = %+v, dry run %v; want go/basics dry run", got, gotDryRun)
+	}
+	wantUntil := time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local)
+	if !got.Since.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)) || !got.Until.Equal(wantUntil) {
+		t.Errorf("range = %v..%v; want Jan 1 up to and including Jan 31", got.Since, got.Until)
+	}
+}
+
+func TestHandleEraseHistory_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		field string
+	}{
+		{"no scope", "", "topic"},
+		{"bad date", "?since=yesterday", "since"},
+		{"backwards range", "?since=2026-02-01&until=2026-01-01", "until"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newServerWithMocks()
+			req := httptest.NewRequest(http.MethodDelete, "/v1/profile/history"+tt.query, nil)
+			w := httptest.NewRecorder()
+			m.server.router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), `"`+tt.field+`"`) {
+				t.Errorf("body should name %s: %s", tt.field, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleResetProfile(t *testing.T) {
+	m := newServerWithMocks()
+	engine, err := achievement.NewEngine(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	engine.Evaluate(achievement.Activity{SessionID: "pruned-long-ago", Green: true})
+	m.server.achievements = engine
+	m.server.idempotency = NewIdempotencyCache()
+	m.server.idempotency.Store("s1", "key", nil, http.StatusOK, nil)
+
+	var got session.EraseScope
+	m.sessions.eraseFn = func(ctx context.Context, scope session.EraseScope, dryRun bool) (*session.EraseReport, error) {
+		got = scope
+		return &session.EraseReport{Sessions: []string{"s1"}}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/profile", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if !got.All {
+		t.Errorf("scope = %+v; want everything", got)
+	}
+	list, _ := engine.List()
+	for _, status := range list {
+		if status.Unlocked != nil {
+			t.Errorf("%s still unlocked after reset", status.ID)
+		}
+	}
+	if _, ok, _ := m.server.idempotency.Lookup("s1", "key", nil); ok {
+		t.Error("cached response should be forgotten")
+	}
+}
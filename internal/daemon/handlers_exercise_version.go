@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// Exercise version handlers
+//
+// A training session remembers the exercise version it started from. When
+// a pack update changes that exercise, runs answer 409 EXERCISE_OUTDATED
+// until the learner migrates (re-base onto the new starter and tests) or
+// pins the version they started with.
+
+func (s *Server) handleGetExerciseVersion(w http.ResponseWriter, r *http.Request) {
+	status, err := s.sessionService.ExerciseVersion(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.exerciseVersionError(w, err)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, status)
+}
+
+func (s *Server) handleMigrateExercise(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.requireDriver(w, r, id) {
+		return
+	}
+
+	migration, err := s.sessionService.MigrateExercise(r.Context(), id)
+	if err != nil {
+		s.exerciseVersionError(w, err)
+		return
+	}
+	s.collab.wrote(id, r.Header.Get(ClientHeader))
+	s.events.notify(id)
+
+	s.jsonResponse(w, http.StatusOK, migration)
+}
+
+func (s *Server) handlePinExercise(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.requireDriver(w, r, id) {
+		return
+	}
+
+	status, err := s.sessionService.PinExercise(r.Context(), id)
+	if err != nil {
+		s.exerciseVersionError(w, err)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, status)
+}
+
+func (s *Server) exerciseVersionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, session.ErrSessionNotFound):
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+	case errors.Is(err, session.ErrSessionNotActive):
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeSessionNotActive, "session is not active", nil)
+	case errors.Is(err, session.ErrExerciseNotFound):
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeExerciseNotFound,
+			"the session's exercise is no longer available", nil)
+	default:
+		s.jsonError(w, http.StatusInternalServerError, "failed to check exercise version", err)
+	}
+}
@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func TestExerciseVersion_RunOutdated(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.runCodeFn = func(ctx context.Context, sessionID string, req session.RunRequest) (*session.Run, error) {
+		return nil, session.ErrExerciseOutdated
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/s1/runs", bytes.NewReader([]byte(`{"test":true}`)))
+	rec := httptest.NewRecorder()
+	m.server.router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), ErrCodeExerciseOutdated) {
+		t.Fatalf("expected %d %s, got %d: %s", http.StatusConflict, ErrCodeExerciseOutdated, rec.Code, rec.Body.String())
+	}
+}
+
+func TestExerciseVersion_MigrateAndPin(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.events = newSessionEvents()
+	m.sessions.exerciseVersionFn = func(ctx context.Context, id string) (*session.ExerciseVersionStatus, error) {
+		if id != "s1" {
+			return nil, session.ErrSessionNotFound
+		}
+		return &session.ExerciseVersionStatus{SessionVersion: "v1", LatestVersion: "v2", Outdated: true, ChangedFiles: []string{"main_test.go"}}, nil
+	}
+	m.sessions.migrateExerciseFn = func(ctx context.Context, id string) (*session.ExerciseMigration, error) {
+		return &session.ExerciseMigration{From: "v1", To: "v2", Updated: []string{"main_test.go"}, Session: &session.Session{ID: id}}, nil
+	}
+	m.sessions.pinExerciseFn = func(ctx context.Context, id string) (*session.ExerciseVersionStatus, error) {
+		return nil, session.ErrSessionNotActive
+	}
+
+	tests := []struct {
+		method, path string
+		status       int
+		contains     string
+	}{
+		{http.MethodGet, "/v1/sessions/s1/exercise-version", http.StatusOK, `"outdated":true`},
+		{http.MethodGet, "/v1/sessions/nope/exercise-version", http.StatusNotFound, ErrCodeSessionNotFound},
+		{http.MethodPost, "/v1/sessions/s1/exercise-version/migrate", http.StatusOK, `"to":"v2"`},
+		{http.MethodPost, "/v1/sessions/s1/exercise-version/pin", http.StatusBadRequest, ErrCodeSessionNotActive},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		rec := httptest.NewRecorder()
+		m.server.router.ServeHTTP(rec, req)
+		if rec.Code != tt.status || !strings.Contains(rec.Body.String(), tt.contains) {
+			t.Errorf("%s %s: got %d %s; want %d containing %s", tt.method, tt.path, rec.Code, rec.Body.String(), tt.status, tt.contains)
+		}
+	}
+}
+
+func TestExerciseVersion_MigrateNeedsDriver(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.collab = newCollaboration()
+	m.server.collab.join("s1", "ada", "")
+	m.server.collab.join("s1", "bob", "")
+	m.sessions.migrateExerciseFn = func(ctx context.Context, id string) (*session.ExerciseMigration, error) {
+		t.Fatal("a navigator must not migrate the session")
+		return nil, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/s1/exercise-version/migrate", nil)
+	req.Header.Set(ClientHeader, "bob")
+	rec := httptest.NewRecorder()
+	m.server.router.ServeHTTP(rec, req)
+
+	var resp map[string]interface{}
+	_ = json.NewDecoder(rec.Body).Decode(&resp)
+	if rec.Code != http.StatusConflict || resp["error_code"] != ErrCodeDriverLocked {
+		t.Errorf("expected %d %s, got %d %v", http.StatusConflict, ErrCodeDriverLocked, rec.Code, resp)
+	}
+}
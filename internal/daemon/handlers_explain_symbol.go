@@ -0,0 +1,45 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/analysis"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+)
+
+// explainSymbolRequest names the identifier to explain. Line and column
+// are 1-based; without a column the first occurrence on the line is used.
+type explainSymbolRequest struct {
+	pairingRequest
+	File       string `json:"file" validate:"required"`
+	Line       int    `json:"line" validate:"required,min=1"`
+	Column     int    `json:"column,omitempty" validate:"min=1"`
+	Identifier string `json:"identifier" validate:"required"`
+}
+
+// handleExplainSymbol explains one identifier of the learner's code. The
+// symbol is resolved against the session's Go files, and the explanation
+// is scoped to its declaration and the places that use it.
+func (s *Server) handleExplainSymbol(w http.ResponseWriter, r *http.Request) {
+	var req explainSymbolRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	s.pair(w, r, domain.IntentExplain, req.pairingRequest, func(pairingCtx *pairing.InterventionContext) bool {
+		sym, err := analysis.ResolveSymbol(pairingCtx.Code, req.File, req.Line, req.Column, req.Identifier)
+		switch {
+		case errors.Is(err, analysis.ErrSymbolNotFound):
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "symbol not found: "+req.Identifier, nil)
+			return false
+		case err != nil:
+			s.jsonErrorCode(w, http.StatusUnprocessableEntity, ErrCodeUnprocessable, "cannot resolve symbols in "+req.File, err)
+			return false
+		}
+		pairingCtx.Symbol = sym
+		pairingCtx.CurrentFile, pairingCtx.CursorLine = req.File, req.Line
+		return true
+	})
+}
@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/analysis"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+const explainSymbolCode = "package main\n\nfunc double(n int) int {\n\treturn n * 2\n}\n\nfunc main() {\n\t_ = double(3)\n}\n"
+
+func setupExplainSymbolServer(t *testing.T) (*serverWithMocks, string, *pairing.InterventionRequest) {
+	t.Helper()
+	m := newServerWithMocks()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{
+			ID:     id,
+			Status: session.StatusActive,
+			Code:   map[string]string{"main.go": explainSymbolCode},
+		}, nil
+	}
+	var got pairing.InterventionRequest
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		got = req
+		return &domain.Intervention{
+			ID:      uuid.New(),
+			Intent:  req.Intent,
+			Level:   domain.L2LocationConcept,
+			Type:    domain.TypeExplain,
+			Content: "double returns twice its argument.",
+		}, nil
+	}
+	return m, uuid.New().String(), &got
+}
+
+func TestExplainSymbol(t *testing.T) {
+	m, sessionID, got := setupExplainSymbolServer(t)
+
+	body := `{"file": "main.go", "line": 8, "column": 7, "identifier": "double"}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/explain-symbol", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp["content"] != "double returns twice its argument." {
+		t.Errorf("content = %v", resp["content"])
+	}
+
+	if got.Intent != domain.IntentExplain {
+		t.Errorf("Intent = %q, want explain", got.Intent)
+	}
+	sym := got.Context.Symbol
+	if sym == nil || sym.Kind != analysis.KindFunc || sym.Declaration == nil || sym.Declaration.Line != 3 {
+		t.Fatalf("Symbol = %+v, want the func declared at line 3", sym)
+	}
+	if len(sym.Usages) != 1 || sym.Usages[0].Line != 8 {
+		t.Errorf("Usages = %+v", sym.Usages)
+	}
+	if got.Context.CurrentFile != "main.go" || got.Context.CursorLine != 8 {
+		t.Errorf("target = %s:%d", got.Context.CurrentFile, got.Context.CursorLine)
+	}
+}
+
+func TestExplainSymbol_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"missing identifier", `{"file": "main.go", "line": 8}`, http.StatusBadRequest},
+		{"not on line", `{"file": "main.go", "line": 1, "identifier": "double"}`, http.StatusNotFound},
+		{"unknown file", `{"file": "other.go", "line": 1, "identifier": "double"}`, http.StatusNotFound},
+		{"unparseable", `{"file": "main.go", "line": 1, "identifier": "x", "code": {"main.go": "package"}}`, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, sessionID, _ := setupExplainSymbolServer(t)
+			m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+				t.Error("an unresolved symbol must not call the LLM")
+				return nil, nil
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/explain-symbol", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			m.server.router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
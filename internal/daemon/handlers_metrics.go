@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/felixgeelhaar/temper/internal/llm"
 	"github.com/felixgeelhaar/temper/internal/pairing"
 )
 
@@ -28,4 +29,22 @@ func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
 			"clamp_violations_total %d\n",
 		pairing.ClampViolations(),
 	)
+	fmt.Fprintf(w,
+		"# HELP structured_reasks_total Malformed structured LLM responses sent back for correction.\n"+
+			"# TYPE structured_reasks_total counter\n"+
+			"structured_reasks_total %d\n",
+		pairing.StructuredReasks(),
+	)
+	fmt.Fprintf(w,
+		"# HELP llm_timeouts_total LLM calls cut off by their provider's timeout.\n"+
+			"# TYPE llm_timeouts_total counter\n"+
+			"llm_timeouts_total %d\n",
+		llm.Timeouts(),
+	)
+	fmt.Fprintf(w,
+		"# HELP requests_canceled_total Requests abandoned by the client before a response was written.\n"+
+			"# TYPE requests_canceled_total counter\n"+
+			"requests_canceled_total %d\n",
+		canceledRequests.Load(),
+	)
 }
@@ -2,13 +2,18 @@ package daemon
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/felixgeelhaar/temper/internal/config"
 	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
 	"github.com/felixgeelhaar/temper/internal/pairing"
 	"github.com/felixgeelhaar/temper/internal/profile"
 	"github.com/felixgeelhaar/temper/internal/session"
@@ -104,6 +109,163 @@ func TestMock_AnalyticsTrend_ServiceError(t *testing.T) {
 	}
 }
 
+func TestMock_Session_List(t *testing.T) {
+	m := newServerWithMocks()
+
+	now := time.Now()
+	m.sessions.listFn = func(ctx context.Context) ([]*session.Session, error) {
+		return []*session.Session{
+			{ID: "old", UpdatedAt: now.Add(-time.Hour)},
+			{ID: "new", UpdatedAt: now},
+		}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/sessions", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Sessions []session.Session `json:"sessions"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Sessions) != 2 || resp.Sessions[0].ID != "new" {
+		t.Errorf("sessions should be most recent first: %+v", resp.Sessions)
+	}
+}
+
+func TestMock_Session_ListPaged(t *testing.T) {
+	m := newServerWithMocks()
+
+	now := time.Now()
+	m.sessions.listFn = func(ctx context.Context) ([]*session.Session, error) {
+		return []*session.Session{
+			{ID: "a", Intent: session.IntentTraining, UpdatedAt: now.Add(-3 * time.Hour)},
+			{ID: "b", Intent: session.IntentGreenfield, UpdatedAt: now.Add(-2 * time.Hour)},
+			{ID: "c", Intent: session.IntentTraining, UpdatedAt: now.Add(-time.Hour)},
+			{ID: "d", Intent: session.IntentTraining, UpdatedAt: now},
+		}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/sessions?intent=training&sort=updated_at&limit=2&offset=1", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Sessions []session.Session `json:"sessions"`
+		Total    int               `json:"total"`
+		Limit    int               `json:"limit"`
+		Offset   int               `json:"offset"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Total != 3 || resp.Limit != 2 || resp.Offset != 1 {
+		t.Errorf("total/limit/offset = %d/%d/%d, want 3/2/1", resp.Total, resp.Limit, resp.Offset)
+	}
+	if len(resp.Sessions) != 2 || resp.Sessions[0].ID != "c" || resp.Sessions[1].ID != "d" {
+		t.Errorf("unexpected page: %+v", resp.Sessions)
+	}
+}
+
+func TestLatestDebugOutput_FromRunResult(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.getRunsFn = func(ctx context.Context, sessionID string) ([]*session.Run, error) {
+		return []*session.Run{{ID: "r1", CreatedAt: time.Now(), Result: &session.RunResult{
+			BuildOK: true,
+			Tests: []domain.TestResult{
+				{Name: "TestA", Passed: true},
+				{Name: "TestB"},
+				{Name: "TestC"},
+			},
+			Debug: &domain.DebugSnapshot{Reason: "test_failure"},
+		}}}, nil
+	}
+
+	out := m.server.latestDebugOutput(context.Background(), "s1", "")
+	if out == nil {
+		t.Fatal("latestDebugOutput() = nil for a run with a snapshot")
+	}
+	if !out.BuildOK || out.TestsPassed != 1 || out.TestsFailed != 2 || len(out.TestResults) != 3 {
+		t.Errorf("output = %+v; want the run's own build and test results", out)
+	}
+
+	m.sessions.getRunsFn = func(ctx context.Context, sessionID string) ([]*session.Run, error) {
+		return []*session.Run{{ID: "r1", Result: &session.RunResult{Debug: &domain.DebugSnapshot{Reason: "panic"}}}}, nil
+	}
+	if out := m.server.latestDebugOutput(context.Background(), "s1", "r1"); out.BuildOK || out.TestsFailed != 0 {
+		t.Errorf("output = %+v; a failed build must not be reported as built with a failing test", out)
+	}
+}
+
+func TestMock_Session_ListRuns(t *testing.T) {
+	m := newServerWithMocks()
+
+	now := time.Now()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		if id != "s1" {
+			return nil, session.ErrSessionNotFound
+		}
+		return &session.Session{ID: id}, nil
+	}
+	m.sessions.getRunsFn = func(ctx context.Context, sessionID string) ([]*session.Run, error) {
+		return []*session.Run{
+			{ID: "r2", CreatedAt: now, Code: map[string]string{"main.go": "package main\n\nfunc f() int { return 2 }\n"},
+				Result: &session.RunResult{BuildOK: true, TestOK: true}},
+			{ID: "r1", CreatedAt: now.Add(-time.Minute), Code: map[string]string{"main.go": "package main\n\nfunc f() int { return 1 }\n"},
+				Result: &session.RunResult{BuildOK: true}},
+		}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/sessions/s1/runs?status=passed", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Runs  []session.RunHistoryEntry `json:"runs"`
+		Total int                       `json:"total"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Total != 1 || len(resp.Runs) != 1 {
+		t.Fatalf("expected only the passing run, got %+v", resp.Runs)
+	}
+	run := resp.Runs[0]
+	if run.ID != "r2" || run.PreviousRunID != "r1" || run.Diff.Additions != 1 || run.Diff.Deletions != 1 {
+		t.Errorf("filtered run should still diff against its predecessor: %+v", run)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/sessions/missing/runs", nil)
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown session: expected %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestMock_Session_ListBadSort(t *testing.T) {
+	m := newServerWithMocks()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/sessions?sort=code", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+}
+
 func TestMock_Session_GetSuccess(t *testing.T) {
 	m := newServerWithMocks()
 
@@ -275,6 +437,44 @@ func TestMock_CreateRun_ServiceError(t *testing.T) {
 	}
 }
 
+func TestMock_CreateRun_Command(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"runs", nil, http.StatusOK},
+		{"unknown", fmt.Errorf("%w: lint", session.ErrUnknownCommand), http.StatusBadRequest},
+		{"not approved", fmt.Errorf("%w: rm -rf /", session.ErrCommandNotAllowed), http.StatusForbidden},
+		{"unsupported", session.ErrCommandUnsupported, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newServerWithMocks()
+			var got string
+			m.sessions.runCodeFn = func(ctx context.Context, sessionID string, req session.RunRequest) (*session.Run, error) {
+				got = req.Command
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &session.Run{ID: "run-1", Result: &session.RunResult{Command: &session.CommandRun{Name: req.Command, OK: true}}}, nil
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+uuid.New().String()+"/runs", strings.NewReader(`{"command":"check"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			m.server.router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+			if got != "check" {
+				t.Errorf("command = %q, want check", got)
+			}
+		})
+	}
+}
+
 // Patch handler tests
 
 func TestMock_PatchApply_SessionNotFound(t *testing.T) {
@@ -368,6 +568,341 @@ func TestMock_ValidateSpec_Error(t *testing.T) {
 	}
 }
 
+func TestMock_ReviewSpec(t *testing.T) {
+	m := newServerWithMocks()
+
+	m.specs.loadFn = func(ctx context.Context, path string) (*domain.ProductSpec, error) {
+		return &domain.ProductSpec{Name: "Auth", FilePath: path}, nil
+	}
+	m.pairing.reviewSpecFn = func(ctx context.Context, spec *domain.ProductSpec) (*domain.SpecReview, error) {
+		return &domain.SpecReview{
+			SpecPath: spec.FilePath,
+			Findings: []domain.SpecFinding{{Kind: domain.FindingUntestableGoal, Severity: "high", Target: "goals[0]", Message: "not measurable"}},
+		}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/specs/review/.specs/auth.yaml", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var review domain.SpecReview
+	if err := json.NewDecoder(w.Body).Decode(&review); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if review.SpecPath != ".specs/auth.yaml" || len(review.Findings) != 1 {
+		t.Errorf("review = %+v", review)
+	}
+}
+
+func TestMock_ReviewSpec_UnparseableResponse(t *testing.T) {
+	m := newServerWithMocks()
+
+	m.specs.loadFn = func(ctx context.Context, path string) (*domain.ProductSpec, error) {
+		return &domain.ProductSpec{Name: "Auth"}, nil
+	}
+	m.pairing.reviewSpecFn = func(ctx context.Context, spec *domain.ProductSpec) (*domain.SpecReview, error) {
+		return nil, pairing.ErrUnparseableReview
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/specs/review/.specs/auth.yaml", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("expected status %d, got %d: %s", http.StatusBadGateway, w.Code, w.Body.String())
+	}
+}
+
+func TestMock_SpecPlan(t *testing.T) {
+	m := newServerWithMocks()
+
+	m.specs.planFn = func(ctx context.Context, path string) (*domain.SpecPlan, error) {
+		return &domain.SpecPlan{SpecPath: path, Steps: []domain.PlanStep{{Order: 1, FeatureID: "auth"}}}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/specs/plan/.specs/app.yaml", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var plan domain.SpecPlan
+	if err := json.NewDecoder(w.Body).Decode(&plan); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(plan.Steps) != 1 || plan.Steps[0].FeatureID != "auth" {
+		t.Errorf("plan = %+v", plan)
+	}
+}
+
+func TestMock_SpecPlan_Cycle(t *testing.T) {
+	m := newServerWithMocks()
+
+	m.specs.planFn = func(ctx context.Context, path string) (*domain.SpecPlan, error) {
+		return nil, fmt.Errorf("%w: a -> b -> a", spec.ErrDependencyCycle)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/specs/plan/.specs/app.yaml", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d: %s", http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !strings.Contains(resp.Error, "a -> b -> a") {
+		t.Errorf("error should name the cycle: %q", resp.Error)
+	}
+}
+
+func TestMock_SpecDiagram(t *testing.T) {
+	m := newServerWithMocks()
+
+	var gotFormat domain.DiagramFormat
+	var gotWrite bool
+	m.specs.diagramFn = func(ctx context.Context, path string, format domain.DiagramFormat, write bool) (*domain.SpecDiagram, error) {
+		gotFormat, gotWrite = format, write
+		if format == "svg" {
+			return nil, fmt.Errorf("%w: %q", spec.ErrUnknownDiagramFormat, format)
+		}
+		return &domain.SpecDiagram{SpecPath: path, Format: domain.DiagramC4, Diagram: "C4Component\n", Path: ".specs/app.c4.mmd"}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/specs/diagram/.specs/app.yaml", strings.NewReader(`{"format":"c4","write":true}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if gotFormat != domain.DiagramC4 || !gotWrite {
+		t.Errorf("format = %q, write = %v", gotFormat, gotWrite)
+	}
+	var diagram domain.SpecDiagram
+	if err := json.NewDecoder(w.Body).Decode(&diagram); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if diagram.Path != ".specs/app.c4.mmd" || !strings.HasPrefix(diagram.Diagram, "C4Component") {
+		t.Errorf("diagram = %+v", diagram)
+	}
+
+	// No body falls back to the service default
+	req = httptest.NewRequest(http.MethodPost, "/v1/specs/diagram/.specs/app.yaml", nil)
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || gotFormat != "" || gotWrite {
+		t.Errorf("empty body: status %d, format = %q, write = %v", w.Code, gotFormat, gotWrite)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/specs/diagram/.specs/app.yaml", strings.NewReader(`{"format":"svg"}`))
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for unknown format, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestMock_SpecHistory(t *testing.T) {
+	m := newServerWithMocks()
+
+	m.specs.historyFn = func(ctx context.Context, path string) ([]spec.LockRecord, error) {
+		return []spec.LockRecord{{
+			SpecPath: ".specs/app.yaml",
+			Lock:     domain.SpecLock{Version: "1.1.0"},
+			Changes:  spec.DriftReport{HasDrift: true, AddedFeatures: []string{"logout"}},
+		}}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/specs/history/.specs/app.yaml", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Locks []spec.LockRecord `json:"locks"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Locks) != 1 || resp.Locks[0].Changes.AddedFeatures[0] != "logout" {
+		t.Errorf("locks = %+v", resp.Locks)
+	}
+}
+
+func TestMock_SpecHistory_NotFound(t *testing.T) {
+	m := newServerWithMocks()
+
+	m.specs.historyFn = func(ctx context.Context, path string) ([]spec.LockRecord, error) {
+		return nil, spec.ErrSpecNotFound
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/specs/history/missing.yaml", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+}
+
+func TestMock_ImportSpec_GitHub(t *testing.T) {
+	gh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/api/issues/7" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"title":"Export CSV","body":"Download reports.\n\n- [ ] Includes headers","html_url":"https://github.com/acme/api/issues/7","labels":[{"name":"P1"}]}`))
+	}))
+	defer gh.Close()
+
+	m := newServerWithMocks()
+	m.server.cfg = &config.LocalConfig{Integrations: config.IntegrationsConfig{
+		GitHub: config.GitHubConfig{APIURL: gh.URL},
+	}}
+	m.specs.loadFn = func(ctx context.Context, path string) (*domain.ProductSpec, error) {
+		return nil, spec.ErrSpecNotFound
+	}
+	var saved *domain.ProductSpec
+	m.specs.saveFn = func(ctx context.Context, s *domain.ProductSpec) error {
+		saved = s
+		return nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/specs/import", strings.NewReader(`{"github":"acme/api#7"}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if saved == nil || saved.FilePath != "acme-api-7-export-csv.yaml" {
+		t.Fatalf("saved = %+v", saved)
+	}
+	if len(saved.AcceptanceCriteria) != 1 || saved.Features[0].Priority != domain.PriorityHigh {
+		t.Errorf("saved spec = %+v", saved)
+	}
+}
+
+func TestMock_ImportSpec_Conflict(t *testing.T) {
+	gh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"title":"Export CSV"}`))
+	}))
+	defer gh.Close()
+
+	m := newServerWithMocks()
+	m.server.cfg = &config.LocalConfig{Integrations: config.IntegrationsConfig{
+		GitHub: config.GitHubConfig{APIURL: gh.URL},
+	}}
+	m.specs.loadFn = func(ctx context.Context, path string) (*domain.ProductSpec, error) {
+		return &domain.ProductSpec{FilePath: path}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/specs/import", strings.NewReader(`{"github":"acme/api#7"}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+}
+
+func TestMock_ImportSpec_BadRequest(t *testing.T) {
+	m := newServerWithMocks()
+
+	for _, body := range []string{`{}`, `{"github":"acme/api#1","jira":"SHOP-1"}`, `{"github":"not-a-ref"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/specs/import", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		m.server.router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d: %s", body, http.StatusBadRequest, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestMock_ImportSpec_JiraNotConfigured(t *testing.T) {
+	m := newServerWithMocks()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/specs/import", strings.NewReader(`{"jira":"SHOP-1"}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d: %s", http.StatusServiceUnavailable, w.Code, w.Body.String())
+	}
+}
+
+func TestMock_SyncSpec_Comment(t *testing.T) {
+	var comments int
+	gh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/acme/api/issues/7/comments" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		comments++
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer gh.Close()
+
+	m := newServerWithMocks()
+	m.server.cfg = &config.LocalConfig{Integrations: config.IntegrationsConfig{
+		GitHub: config.GitHubConfig{APIURL: gh.URL},
+	}}
+	m.specs.loadFn = func(ctx context.Context, path string) (*domain.ProductSpec, error) {
+		return &domain.ProductSpec{
+			FilePath: path,
+			Tracker:  &domain.TrackerLink{System: "github", Key: "acme/api#7", Sync: domain.TrackerSyncComment},
+			AcceptanceCriteria: []domain.AcceptanceCriterion{
+				{ID: "ac-001", Description: "Includes headers", Satisfied: true},
+			},
+		}, nil
+	}
+	var saved *domain.ProductSpec
+	m.specs.saveFn = func(ctx context.Context, s *domain.ProductSpec) error {
+		saved = s
+		return nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/specs/sync/.specs/export.yaml", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if comments != 1 {
+		t.Errorf("comments = %d, want 1", comments)
+	}
+	if saved == nil || len(saved.Tracker.Synced) != 1 || saved.Tracker.Synced[0] != "ac-001" {
+		t.Errorf("saved = %+v, want ac-001 recorded as synced", saved)
+	}
+}
+
+func TestMock_SyncSpec_Disabled(t *testing.T) {
+	m := newServerWithMocks()
+	m.specs.loadFn = func(ctx context.Context, path string) (*domain.ProductSpec, error) {
+		return &domain.ProductSpec{FilePath: path}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/specs/sync/.specs/export.yaml", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+}
+
 func TestMock_CreateSpec_Error(t *testing.T) {
 	m := newServerWithMocks()
 
@@ -480,3 +1015,269 @@ func TestMock_Format_Success(t *testing.T) {
 		t.Errorf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
 	}
 }
+
+func TestMock_RootCause(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{"completed", `{"answers":[{"file":"main.go","explanation":"integer division"}]}`, nil, http.StatusOK},
+		{"no answers", `{"answers":[]}`, nil, http.StatusBadRequest},
+		{"missing explanation", `{"answers":[{"file":"main.go"}]}`, nil, http.StatusBadRequest},
+		{"not debugging", `{"answers":[{"file":"main.go","explanation":"x"}]}`, session.ErrNotDebugging, http.StatusBadRequest},
+		{"session not found", `{"answers":[{"file":"main.go","explanation":"x"}]}`, session.ErrSessionNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newServerWithMocks()
+			m.sessions.submitRootCauseFn = func(ctx context.Context, id string, answers []domain.RootCauseAnswer) (*session.RootCauseResult, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &session.RootCauseResult{
+					Verdict:     domain.RootCauseVerdict{Identified: true, Found: []string{"bug"}},
+					TestsPassed: true,
+					Completed:   true,
+				}, nil
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+uuid.New().String()+"/root-cause", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			m.server.router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMock_PushWorkspace(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{"updated", `{"files":{"main.go":"package main"},"deleted":["old.go"]}`, nil, http.StatusOK},
+		{"stale base", `{"base_version":"abc","files":{"main.go":"package main"}}`, session.ErrWorkspaceConflict, http.StatusConflict},
+		{"bad path", `{"files":{"../x.go":"package x"}}`, session.ErrInvalidPath, http.StatusBadRequest},
+		{"session not found", `{"files":{}}`, session.ErrSessionNotFound, http.StatusNotFound},
+		{"merged files over the limit", `{"files":{"main.go":"package main"}}`, &PayloadError{Code: "TOO_MANY_FILES", Message: "too many files"}, http.StatusRequestEntityTooLarge},
+		{"invalid body", `{`, nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newServerWithMocks()
+			m.sessions.pushWorkspaceFn = func(ctx context.Context, id string, push session.WorkspacePush) (*session.WorkspaceManifest, error) {
+				if push.Validate == nil {
+					t.Error("push should validate the merged files")
+				}
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				manifest := session.ManifestOf(push.Files)
+				return &manifest, nil
+			}
+
+			req := httptest.NewRequest(http.MethodPatch, "/v1/sessions/"+uuid.New().String()+"/workspace", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			m.server.router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMock_PullWorkspace(t *testing.T) {
+	m := newServerWithMocks()
+	code := map[string]string{"main.go": "package main", "util.go": "package main // v2"}
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: id, Code: code}, nil
+	}
+
+	body := `{"have":{"main.go":"` + session.HashContent("package main") + `","gone.go":"x"}}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+uuid.New().String()+"/workspace/pull", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var delta session.WorkspaceDelta
+	if err := json.Unmarshal(w.Body.Bytes(), &delta); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if _, ok := delta.Files["main.go"]; ok {
+		t.Error("unchanged main.go should not be resent")
+	}
+	if delta.Files["util.go"] != code["util.go"] {
+		t.Errorf("util.go = %q; want full content", delta.Files["util.go"])
+	}
+	if len(delta.Deleted) != 1 || delta.Deleted[0] != "gone.go" {
+		t.Errorf("deleted = %v; want [gone.go]", delta.Deleted)
+	}
+}
+
+func TestMock_Search(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"ok", "?q=nil+map&kind=run&limit=5", http.StatusOK},
+		{"missing q", "", http.StatusBadRequest},
+		{"bad kind", "?q=x&kind=patch", http.StatusBadRequest},
+		{"bad limit", "?q=x&limit=-1", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newServerWithMocks()
+			var got session.SearchQuery
+			m.sessions.searchFn = func(ctx context.Context, q session.SearchQuery) ([]session.SearchHit, error) {
+				got = q
+				return []session.SearchHit{{Kind: session.SearchKindRun, Snippet: "nil map"}}, nil
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/search"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			m.server.router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK && (got.Text != "nil map" || got.Kind != "run" || got.Limit != 5) {
+				t.Errorf("query passed to service = %+v", got)
+			}
+		})
+	}
+}
+
+func TestMock_Prune(t *testing.T) {
+	m := newServerWithMocks()
+	var gotPolicy session.RetentionPolicy
+	var gotDryRun bool
+	m.sessions.pruneFn = func(ctx context.Context, policy session.RetentionPolicy, now time.Time, dryRun bool) (*session.PruneReport, error) {
+		gotPolicy, gotDryRun = policy, dryRun
+		return &session.PruneReport{DryRun: dryRun, Sessions: []string{"a"}, Runs: 3}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/admin/prune", strings.NewReader(`{"dry_run":true,"runs_days":7}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if !gotDryRun || gotPolicy.RunMaxAge != 7*24*time.Hour {
+		t.Errorf("prune called with dryRun=%v policy=%+v", gotDryRun, gotPolicy)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/admin/prune", strings.NewReader(`{"sessions_days":-1}`))
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("negative days: expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestMock_FeatureProgress(t *testing.T) {
+	m := newServerWithMocks()
+	m.specs.loadFn = func(ctx context.Context, path string) (*domain.ProductSpec, error) {
+		return &domain.ProductSpec{
+			FilePath: path,
+			Features: []domain.Feature{
+				{ID: "password-reset", Title: "Password reset"},
+				{ID: "login", Title: "Login"},
+			},
+			AcceptanceCriteria: []domain.AcceptanceCriterion{
+				{ID: "ac-001", Description: "Reset links expire", Satisfied: true, Features: []string{"password-reset"}},
+				{ID: "ac-002", Description: "Reset requires the old session to end", Features: []string{"password-reset"}},
+				{ID: "ac-003", Description: "Login locks after 5 attempts", Features: []string{"login"}},
+			},
+		}, nil
+	}
+	now := time.Now()
+	m.sessions.runsForSpecFn = func(ctx context.Context, specPath string) ([]*session.Run, error) {
+		return []*session.Run{
+			{ID: "run-2", SessionID: "s-1", CreatedAt: now, Result: &session.RunResult{Tests: []domain.TestResult{
+				{Name: "TestPasswordReset_Expiry", Passed: true},
+				{Name: "TestLogin", Passed: false},
+			}}},
+			{ID: "run-1", SessionID: "s-1", CreatedAt: now.Add(-time.Minute), Result: &session.RunResult{Tests: []domain.TestResult{
+				{Name: "TestPasswordReset_Expiry", Passed: false},
+				{Name: "TestPasswordReset_Session", Passed: false},
+			}}},
+			{ID: "run-0", SessionID: "s-1", CreatedAt: now.Add(-time.Hour), Result: &session.RunResult{Tests: []domain.TestResult{
+				{Name: "TestLogin", Passed: true},
+			}}},
+		}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/specs/features/password-reset/progress/.specs/auth.yaml", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var progress domain.FeatureProgress
+	if err := json.Unmarshal(w.Body.Bytes(), &progress); err != nil {
+		t.Fatal(err)
+	}
+	if len(progress.Criteria) != 2 || progress.SatisfiedCriteria != 1 {
+		t.Errorf("criteria = %d (%d satisfied), want 2 (1 satisfied)", len(progress.Criteria), progress.SatisfiedCriteria)
+	}
+	if len(progress.Tests) != 2 || !progress.Tests[0].Passed || progress.Tests[0].RunID != "run-2" {
+		t.Errorf("tests = %+v, want latest outcome of the two reset tests", progress.Tests)
+	}
+	if len(progress.RecentRuns) != 2 || progress.RecentRuns[1].Failed != 2 {
+		t.Errorf("recent runs = %+v, want run-2 and run-1", progress.RecentRuns)
+	}
+}
+
+func TestMock_FeatureProgress_UnknownFeature(t *testing.T) {
+	m := newServerWithMocks()
+	m.specs.loadFn = func(ctx context.Context, path string) (*domain.ProductSpec, error) {
+		return &domain.ProductSpec{FilePath: path, Features: []domain.Feature{{ID: "login"}}}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/specs/features/f-9/progress/.specs/auth.yaml", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestMock_Pairing_LLMTimeout(t *testing.T) {
+	m := newServerWithMocks()
+	sessionID := uuid.New().String()
+
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: sessionID, Status: session.StatusActive}, nil
+	}
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		return nil, fmt.Errorf("generate: %w", llm.ErrTimeout)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/hint", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusGatewayTimeout || !strings.Contains(w.Body.String(), ErrCodeLLMTimeout) {
+		t.Errorf("expected %d %s, got %d: %s", http.StatusGatewayTimeout, ErrCodeLLMTimeout, w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/pairing"
+)
+
+// handleSummarizePack synthesizes the reviews and errors of the learner's
+// sessions on one pack into strengths and gaps, for the weekly report and
+// instructor views. It calls the LLM on every request; clients cache it.
+func (s *Server) handleSummarizePack(w http.ResponseWriter, r *http.Request) {
+	pack := packParam(r)
+
+	activity, err := s.sessionService.PackActivity(r.Context(), pack)
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to collect pack activity", err)
+		return
+	}
+	if len(activity.Exercises) == 0 {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "no sessions on pack "+pack, nil)
+		return
+	}
+
+	summary, err := s.pairingService.SummarizePack(r.Context(), activity)
+	if err != nil {
+		if errors.Is(err, pairing.ErrUnparseablePackSummary) {
+			s.jsonErrorCode(w, http.StatusBadGateway, ErrCodeLLMUnavailable, "LLM returned an unreadable summary; try again", err)
+			return
+		}
+		s.pairingError(w, "failed to summarize pack", err)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, summary)
+}
@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func TestMock_SummarizePack(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.packActivityFn = func(ctx context.Context, pack string) (*session.PackActivity, error) {
+		if pack != "go-v1" {
+			return &session.PackActivity{Pack: pack}, nil
+		}
+		return &session.PackActivity{Pack: pack, Exercises: []session.ExerciseActivity{
+			{ExerciseID: "go-v1/basics/maps", Sessions: 1, Reviews: []string{"Initialize the map."}},
+		}}, nil
+	}
+
+	tests := []struct {
+		name       string
+		pack       string
+		summarize  func(ctx context.Context, a *session.PackActivity) (*domain.PackSummary, error)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "summary",
+			pack: "go-v1",
+			summarize: func(ctx context.Context, a *session.PackActivity) (*domain.PackSummary, error) {
+				return &domain.PackSummary{Pack: a.Pack, Attempted: len(a.Exercises), Gaps: []domain.PackFinding{{Topic: "maps", Message: "Nil map writes."}}}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"topic":"maps"`,
+		},
+		{
+			name:       "no sessions on pack",
+			pack:       "python-v1",
+			wantStatus: http.StatusNotFound,
+			wantBody:   ErrCodeNotFound,
+		},
+		{
+			name: "unreadable summary",
+			pack: "go-v1",
+			summarize: func(ctx context.Context, a *session.PackActivity) (*domain.PackSummary, error) {
+				return nil, fmt.Errorf("%w: eof", pairing.ErrUnparseablePackSummary)
+			},
+			wantStatus: http.StatusBadGateway,
+			wantBody:   ErrCodeLLMUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.pairing.summarizePackFn = tt.summarize
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/analytics/packs/"+tt.pack+"/summary", nil)
+			w := httptest.NewRecorder()
+			m.server.router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/exercise"
+)
+
+// Pack source handlers (packs installed from a URL and their updates)
+
+// handleListPackSources returns the packs installed from a source, with
+// any update staged for them
+func (s *Server) handleListPackSources(w http.ResponseWriter, r *http.Request) {
+	sources, err := s.packUpdates.Sources()
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to list pack sources", err)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"sources": sources,
+	})
+}
+
+// handleInstallPack fetches a pack from a URL, checks that it loads and
+// installs it
+func (s *Server) handleInstallPack(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL string `json:"url" validate:"required"`
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	src, err := s.packUpdates.Install(r.Context(), req.URL)
+	if err != nil {
+		if errors.Is(err, exercise.ErrPackExists) {
+			s.jsonErrorCode(w, http.StatusConflict, ErrCodeConflict, "pack already installed", err)
+			return
+		}
+		if errors.Is(err, exercise.ErrPackConflict) {
+			s.jsonErrorCode(w, http.StatusConflict, ErrCodeConflict, "pack conflicts with an installed pack", err)
+			return
+		}
+		s.jsonErrorCode(w, http.StatusUnprocessableEntity, ErrCodeUnprocessable, "failed to install pack", err)
+		return
+	}
+	s.jsonResponse(w, http.StatusCreated, src)
+}
+
+// handleCheckPackUpdates checks every source now instead of waiting for
+// the background check. Sources that fail don't stop the others.
+func (s *Server) handleCheckPackUpdates(w http.ResponseWriter, r *http.Request) {
+	staged, err := s.packUpdates.Check(r.Context())
+	if staged == nil {
+		staged = []exercise.Update{}
+	}
+	resp := map[string]interface{}{
+		"staged": staged,
+	}
+	if err != nil {
+		resp["error"] = err.Error()
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleApplyPackUpdate(w http.ResponseWriter, r *http.Request) {
+	src, err := s.packUpdates.Apply(packParam(r))
+	if err != nil {
+		s.packError(w, "failed to apply pack update", err)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, src)
+}
+
+func (s *Server) handleRollbackPack(w http.ResponseWriter, r *http.Request) {
+	src, err := s.packUpdates.Rollback(packParam(r))
+	if err != nil {
+		s.packError(w, "failed to roll back pack", err)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, src)
+}
+
+// packParam returns the pack a route names. Org packs have routes of
+// their own with an {org} segment: /v1/packs/acme/go-tools/update.
+func packParam(r *http.Request) string {
+	if org := r.PathValue("org"); org != "" {
+		return org + "/" + r.PathValue("pack")
+	}
+	return r.PathValue("pack")
+}
+
+// packError writes the response for an update or rollback that failed
+func (s *Server) packError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, exercise.ErrSourceNotFound):
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "pack was not installed from a source", err)
+	case errors.Is(err, exercise.ErrNoUpdate), errors.Is(err, exercise.ErrNoRollback):
+		s.jsonErrorCode(w, http.StatusConflict, ErrCodeConflict, err.Error(), nil)
+	default:
+		s.jsonError(w, http.StatusInternalServerError, msg, err)
+	}
+}
@@ -0,0 +1,171 @@
+package daemon
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/exercise"
+)
+
+// servePack serves a pack archive whose exercise title carries version
+func servePack(t *testing.T, version *string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		files := map[string]string{
+			"pack.yaml":         "id: acme-go\nname: Acme Go\nversion: " + *version + "\nlanguage: go\nexercises:\n  - basics/hello\n",
+			"basics/hello.yaml": "id: hello\ntitle: Hello\ndifficulty: beginner\nstarter:\n  main.go: package main\n",
+		}
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for name, content := range files {
+			_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+			_, _ = tw.Write([]byte(content))
+		}
+		_ = tw.Close()
+		_ = gz.Close()
+		w.Header().Set("ETag", `"`+*version+`"`)
+		_, _ = w.Write(buf.Bytes())
+	}))
+}
+
+func TestPackHandlers_InstallUpdateRollback(t *testing.T) {
+	server, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	updater, err := exercise.NewUpdater(filepath.Join(dir, "exercises"), filepath.Join(dir, "packs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.packUpdates = updater
+
+	version := "1.0.0"
+	registry := servePack(t, &version)
+	defer registry.Close()
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return w
+	}
+
+	install := `{"url":"` + registry.URL + `/acme-go.tar.gz"}`
+	if w := post("/v1/packs", install); w.Code != http.StatusCreated {
+		t.Fatalf("install: status %d: %s", w.Code, w.Body.String())
+	}
+	if w := post("/v1/packs", install); w.Code != http.StatusConflict {
+		t.Errorf("second install: status %d, want 409", w.Code)
+	}
+	if w := post("/v1/packs/acme-go/update", ""); w.Code != http.StatusConflict {
+		t.Errorf("update with nothing staged: status %d, want 409", w.Code)
+	}
+
+	version = "1.1.0"
+	w := post("/v1/packs/check", "")
+	var checked struct {
+		Staged []exercise.Update `json:"staged"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &checked); err != nil || len(checked.Staged) != 1 || checked.Staged[0].ToVersion != "1.1.0" {
+		t.Fatalf("check = %s (%v)", w.Body.String(), err)
+	}
+
+	// The status tells the CLI an update waits
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/status", nil))
+	var status struct {
+		PackUpdates []exercise.Update `json:"pack_updates"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil || len(status.PackUpdates) != 1 {
+		t.Errorf("status pack_updates = %+v (%v)", status.PackUpdates, err)
+	}
+
+	var src exercise.Source
+	w = post("/v1/packs/acme-go/update", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &src); err != nil || src.Version != "1.1.0" {
+		t.Fatalf("update: status %d: %s", w.Code, w.Body.String())
+	}
+	w = post("/v1/packs/acme-go/rollback", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &src); err != nil || src.Version != "1.0.0" {
+		t.Fatalf("rollback: status %d: %s", w.Code, w.Body.String())
+	}
+	if w := post("/v1/packs/unknown/rollback", ""); w.Code != http.StatusNotFound {
+		t.Errorf("rollback of an unknown pack: status %d, want 404", w.Code)
+	}
+}
+
+func TestExerciseHandlers_OrgPack(t *testing.T) {
+	server, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	packDir := filepath.Join(server.exerciseLoader.BasePath(), "acme", "go-v1")
+	if err := os.MkdirAll(filepath.Join(packDir, "basics"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"pack.yaml":         "id: go-v1\norg: acme\nname: Acme Go\nlanguage: go\nexercises:\n  - basics/hello\n",
+		"basics/hello.yaml": "id: hello\ntitle: Acme Hello\ndifficulty: beginner\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(packDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	var list struct {
+		PackID    string `json:"pack_id"`
+		Exercises []struct {
+			ID string `json:"id"`
+		} `json:"exercises"`
+	}
+	w := get("/v1/exercises/acme/go-v1")
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || list.PackID != "acme/go-v1" ||
+		len(list.Exercises) != 1 || list.Exercises[0].ID != "acme/go-v1/basics/hello" {
+		t.Fatalf("list: status %d: %s", w.Code, w.Body.String())
+	}
+
+	var ex struct {
+		ID    string
+		Title string
+	}
+	w = get("/v1/exercises/acme/go-v1/basics/hello")
+	if err := json.Unmarshal(w.Body.Bytes(), &ex); err != nil || ex.ID != "acme/go-v1/basics/hello" || ex.Title != "Acme Hello" {
+		t.Fatalf("get: status %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSessionEvents_PackUpdate(t *testing.T) {
+	events := newSessionEvents()
+	ch, unsubscribe := events.subscribe("a")
+	defer unsubscribe()
+
+	before := time.Now()
+	events.packUpdate(exercise.Update{Pack: "acme-go", ToVersion: "1.1.0", StagedAt: before.Add(time.Millisecond)})
+
+	select {
+	case <-ch:
+	default:
+		t.Error("stream was not woken")
+	}
+	if got := events.packUpdatesSince(before); len(got) != 1 || got[0].Pack != "acme-go" {
+		t.Errorf("packUpdatesSince() = %+v", got)
+	}
+	if got := events.packUpdatesSince(time.Now().Add(time.Minute)); len(got) != 0 {
+		t.Errorf("updates before the stream connected should be left out, got %+v", got)
+	}
+}
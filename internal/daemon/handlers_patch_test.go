@@ -81,7 +81,7 @@ func TestHandlePatchStats(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewLogger() error = %v", err)
 	}
-	m.patches.getLoggerFn = func() *patch.Logger { return logger }
+	m.patches.getLoggerFn = func() patch.AuditLog { return logger }
 
 	req := httptest.NewRequest(http.MethodGet, "/v1/patches/stats", nil)
 	rec := httptest.NewRecorder()
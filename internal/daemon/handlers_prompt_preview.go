@@ -0,0 +1,31 @@
+package daemon
+
+import (
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/pairing"
+)
+
+// dryRun reports whether a pairing request should return its prompt
+// instead of calling the LLM: asked for with ?dry_run=true, or always with
+// llm.dry_run in the config
+func (s *Server) dryRun(r *http.Request) bool {
+	if r.URL.Query().Get("dry_run") == "true" {
+		return true
+	}
+	return s.cfg != nil && s.cfg.LLM.DryRun
+}
+
+// respondPromptPreview answers a dry run. Nothing is generated, so nothing
+// is recorded and the cooldown isn't started.
+func (s *Server) respondPromptPreview(w http.ResponseWriter, r *http.Request, req pairing.InterventionRequest) {
+	preview, err := s.pairingService.Preview(r.Context(), req)
+	if err != nil {
+		s.pairingError(w, "failed to build prompt preview", err)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"dry_run": true,
+		"preview": preview,
+	})
+}
@@ -0,0 +1,102 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+func setupPreviewServer(t *testing.T) (*serverWithMocks, string) {
+	t.Helper()
+	m := newServerWithMocks()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{
+			ID:     id,
+			Status: session.StatusActive,
+			Code:   map[string]string{"main.go": "package main"},
+		}, nil
+	}
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		t.Error("a dry run must not call the LLM")
+		return nil, errors.New("unexpected call")
+	}
+	m.pairing.previewFn = func(ctx context.Context, req pairing.InterventionRequest) (*pairing.PromptPreview, error) {
+		return &pairing.PromptPreview{
+			Intent:   req.Intent,
+			Level:    domain.L1CategoryHint,
+			Provider: "claude",
+			Model:    "claude-sonnet",
+			Prompt:   "prompt for " + req.Context.Code["main.go"],
+			Tokens:   pairing.TokenEstimate{System: 10, Prompt: 5, Input: 15, MaxOutput: 1024},
+		}, nil
+	}
+	return m, uuid.New().String()
+}
+
+func TestPromptPreview_QueryParam(t *testing.T) {
+	m, sessionID := setupPreviewServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/hint?dry_run=true", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp struct {
+		DryRun  bool                  `json:"dry_run"`
+		Preview pairing.PromptPreview `json:"preview"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.DryRun || resp.Preview.Intent != domain.IntentHint || resp.Preview.Model != "claude-sonnet" {
+		t.Errorf("unexpected preview: %+v", resp)
+	}
+	if resp.Preview.Prompt != "prompt for package main" || resp.Preview.Tokens.Input != 15 {
+		t.Errorf("preview should carry the session code and estimate: %+v", resp.Preview)
+	}
+}
+
+func TestPromptPreview_ConfigFlag(t *testing.T) {
+	m, sessionID := setupPreviewServer(t)
+	m.server.cfg = &config.LocalConfig{LLM: config.LLMConfig{DryRun: true}}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/review", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp struct {
+		DryRun bool `json:"dry_run"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || !resp.DryRun {
+		t.Errorf("llm.dry_run should turn every request into a preview: %v %s", err, w.Body.String())
+	}
+}
+
+func TestPromptPreview_LocalOnlyError(t *testing.T) {
+	m, sessionID := setupPreviewServer(t)
+	m.pairing.previewFn = func(ctx context.Context, req pairing.InterventionRequest) (*pairing.PromptPreview, error) {
+		return nil, pairing.ErrNoLocalProvider
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/hint?dry_run=true", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code == http.StatusOK {
+		t.Errorf("expected an error, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,93 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/quota"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+func TestHandleStatus_Quotas(t *testing.T) {
+	server, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	meter, err := quota.NewMeter(t.TempDir(), map[string]quota.Limits{
+		"claude": {SoftTokens: 100, HardTokens: 1000, AtHardCap: quota.AtHardCapLocal},
+	})
+	if err != nil {
+		t.Fatalf("NewMeter() error = %v", err)
+	}
+	if err := meter.Record("claude", 120, 30); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	server.quota = meter
+
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/status", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Quotas []quota.Status `json:"quotas"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Quotas) != 1 || resp.Quotas[0].Provider != "claude" || resp.Quotas[0].Level != quota.LevelSoft || resp.Quotas[0].Limits.AtHardCap != quota.AtHardCapLocal {
+		t.Errorf("quotas = %+v, want claude past its soft cap", resp.Quotas)
+	}
+}
+
+func TestHandleHint_QuotaExhausted(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: id, Status: session.StatusActive, Policy: domain.DefaultPolicy()}, nil
+	}
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		return nil, fmt.Errorf("generate intervention: %w: claude reached its monthly hard cap", quota.ErrExhausted)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+uuid.New().String()+"/hint", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests || !strings.Contains(w.Body.String(), ErrCodeQuotaExhausted) {
+		t.Errorf("got %d %s, want 429 %s", w.Code, w.Body.String(), ErrCodeQuotaExhausted)
+	}
+}
+
+func TestSessionEvents_QuotaAlert(t *testing.T) {
+	events := newSessionEvents()
+	a, unsubA := events.subscribe("a")
+	defer unsubA()
+	b, unsubB := events.subscribe("b")
+	defer unsubB()
+
+	before := time.Now()
+	events.quotaAlert(quota.Alert{Provider: "claude", Level: quota.LevelSoft, At: before.Add(time.Millisecond)})
+
+	for name, ch := range map[string]<-chan struct{}{"a": a, "b": b} {
+		select {
+		case <-ch:
+		default:
+			t.Errorf("stream %s was not woken", name)
+		}
+	}
+	if got := events.quotaAlertsSince(before); len(got) != 1 || got[0].Provider != "claude" {
+		t.Errorf("quotaAlertsSince() = %+v", got)
+	}
+	if got := events.quotaAlertsSince(time.Now().Add(time.Minute)); len(got) != 0 {
+		t.Errorf("alerts before the stream connected should be left out, got %+v", got)
+	}
+}
@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/profile"
+	"github.com/felixgeelhaar/temper/internal/reminder"
+)
+
+// Reminder handlers (practice nudges and the spaced-repetition queue)
+
+// exerciseHistory feeds the reminder scheduler
+func (s *Server) exerciseHistory(ctx context.Context) ([]profile.ExerciseAttempt, error) {
+	p, err := s.profileService.GetProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.ExerciseHistory, nil
+}
+
+// handleListReminders returns the reminders waiting for a notifier, with
+// the current streak and due reviews so a client can show them on demand
+func (s *Server) handleListReminders(w http.ResponseWriter, r *http.Request) {
+	history, err := s.exerciseHistory(r.Context())
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to get profile", err)
+		return
+	}
+
+	now := time.Now()
+	streak, practicedToday := profile.PracticeStreak(history, now)
+	pending := []reminder.Reminder{}
+	if s.reminders != nil {
+		pending = s.reminders.Pending(now)
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"enabled":         s.reminders != nil,
+		"reminders":       pending,
+		"streak":          streak,
+		"practiced_today": practicedToday,
+		"due_reviews":     profile.DueReviews(history, now),
+	})
+}
+
+func (s *Server) handleDismissReminder(w http.ResponseWriter, r *http.Request) {
+	if s.reminders == nil || !s.reminders.Dismiss(r.PathValue("id")) {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "reminder not found", nil)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"dismissed": true,
+	})
+}
+
+// handleListReviews returns the spaced-repetition queue, split into what
+// is due now and what comes later, with the flashcards due now
+func (s *Server) handleListReviews(w http.ResponseWriter, r *http.Request) {
+	history, err := s.exerciseHistory(r.Context())
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to get profile", err)
+		return
+	}
+
+	now := time.Now()
+	due := []profile.ReviewItem{}
+	upcoming := []profile.ReviewItem{}
+	for _, item := range profile.ReviewQueue(history) {
+		if item.DueAt.After(now) {
+			upcoming = append(upcoming, item)
+		} else {
+			due = append(due, item)
+		}
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"due":      due,
+		"upcoming": upcoming,
+		"cards":    s.dueCards(now),
+	})
+}
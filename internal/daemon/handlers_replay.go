@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/replay"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+// handleReplay re-asks every recorded intervention of a session with the
+// code the learner had at the time and diffs the answers. Each is asked
+// at its recorded level so only the provider or prompt differs. Nothing
+// is recorded and no cooldown applies.
+func (s *Server) handleReplay(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Provider string `json:"provider,omitempty"` // default: the default provider
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	sess, err := s.sessionService.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if err == session.ErrSessionNotFound {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+			return
+		}
+		s.jsonError(w, http.StatusInternalServerError, "failed to get session", err)
+		return
+	}
+
+	// Name the provider even when it's the default, so a failing call is
+	// reported rather than answered with an offline hint
+	if req.Provider == "" {
+		provider, err := s.llmRegistry.Default()
+		if err != nil {
+			s.jsonErrorCode(w, http.StatusServiceUnavailable, ErrCodeLLMUnavailable, "no LLM provider configured", err)
+			return
+		}
+		req.Provider = provider.Name()
+	} else if _, err := s.llmRegistry.Get(req.Provider); err != nil {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeProviderNotFound, "provider not found: "+req.Provider, nil)
+		return
+	}
+
+	runs, err := s.sessionService.GetRuns(r.Context(), sess.ID)
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to get runs", err)
+		return
+	}
+	interventions, err := s.sessionService.GetInterventions(r.Context(), sess.ID)
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to get interventions", err)
+		return
+	}
+
+	ex := s.sessionExercise(sess)
+
+	// One LLM call per intervention outlasts the server's write timeout
+	// on long sessions; a client that hangs up still stops the loop
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	report := replay.Report{SessionID: sess.ID, Provider: req.Provider}
+	for _, step := range replay.Plan(sess, runs, interventions) {
+		if r.Context().Err() != nil {
+			return
+		}
+		pairingCtx := pairing.InterventionContext{Exercise: ex, Code: step.Code}
+		s.attachFeatureContext(r.Context(), sess, &pairingCtx)
+		if step.RunID != "" {
+			pairingCtx.RunOutput = s.latestDebugOutput(r.Context(), sess.ID, step.RunID)
+		}
+
+		level := step.Intervention.Level
+		policy := sess.Policy
+		if policy.MaxLevel < level {
+			policy.MaxLevel = level
+		}
+		policy.Budget = domain.HintBudget{} // replays re-ask what was already paid for
+		replayed, err := s.pairingService.Intervene(r.Context(), pairing.InterventionRequest{
+			SessionID:     uuid.MustParse(sess.ID),
+			Intent:        step.Intervention.Intent,
+			Context:       pairingCtx,
+			Policy:        policy,
+			ExplicitLevel: level,
+			Provider:      req.Provider,
+		})
+		report.Add(replay.Compare(step, replayed, err))
+	}
+
+	s.jsonResponse(w, http.StatusOK, report)
+}
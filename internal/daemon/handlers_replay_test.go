@@ -0,0 +1,99 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/replay"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+func TestMock_Replay(t *testing.T) {
+	m := newServerWithMocks()
+	sessionID := uuid.New().String()
+	t0 := time.Now().Add(-time.Hour)
+
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: sessionID, Status: session.StatusCompleted, Policy: domain.DefaultPolicy()}, nil
+	}
+	m.sessions.getRunsFn = func(ctx context.Context, id string) ([]*session.Run, error) {
+		return []*session.Run{{ID: "r1", CreatedAt: t0, Code: map[string]string{"main.go": "v1"}}}, nil
+	}
+	m.sessions.getInterventionsFn = func(ctx context.Context, id string) ([]*session.Intervention, error) {
+		return []*session.Intervention{
+			{ID: "a", Intent: domain.IntentHint, Level: domain.L2LocationConcept, Content: "old hint", CreatedAt: t0.Add(time.Minute)},
+			{ID: "b", Intent: domain.IntentStuck, Level: domain.L4PartialSolution, Content: "old outline", CreatedAt: t0.Add(2 * time.Minute)},
+		}, nil
+	}
+	m.registry.getFn = func(name string) (llm.Provider, error) {
+		if name != "ollama" {
+			return nil, llm.ErrProviderNotFound
+		}
+		return nil, nil
+	}
+
+	var asked []pairing.InterventionRequest
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		asked = append(asked, req)
+		if req.Intent == domain.IntentStuck {
+			return nil, errors.New("provider down")
+		}
+		return &domain.Intervention{Level: req.ExplicitLevel, Content: "new hint"}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/replay", strings.NewReader(`{"provider": "ollama"}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var report replay.Report
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Provider != "ollama" || report.Changed != 1 || report.Failed != 1 {
+		t.Errorf("report = %+v", report)
+	}
+
+	if len(asked) != 2 {
+		t.Fatalf("asked %d times, want 2", len(asked))
+	}
+	for _, r := range asked {
+		if r.Provider != "ollama" || r.Context.Code["main.go"] != "v1" {
+			t.Errorf("request = provider %q, code %v", r.Provider, r.Context.Code)
+		}
+	}
+	if asked[1].ExplicitLevel != domain.L4PartialSolution || asked[1].Policy.MaxLevel < domain.L4PartialSolution {
+		t.Errorf("escalation replayed at L%d with max L%d, want L4", asked[1].ExplicitLevel, asked[1].Policy.MaxLevel)
+	}
+}
+
+func TestMock_Replay_UnknownProvider(t *testing.T) {
+	m := newServerWithMocks()
+	sessionID := uuid.New().String()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: sessionID}, nil
+	}
+	m.registry.getFn = func(name string) (llm.Provider, error) {
+		return nil, llm.ErrProviderNotFound
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/replay", strings.NewReader(`{"provider": "nope"}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), ErrCodeProviderNotFound) {
+		t.Errorf("got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -3,12 +3,16 @@ package daemon
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/felixgeelhaar/temper/internal/runner"
+	"github.com/felixgeelhaar/temper/internal/session"
 )
 
 func TestHandleFormat_Success(t *testing.T) {
@@ -27,6 +31,30 @@ func TestHandleFormat_Success(t *testing.T) {
 	}
 }
 
+func TestHandleFormat_Language(t *testing.T) {
+	m := newServerWithMocks()
+
+	var got runner.Language
+	m.executor.runFormatFixFn = func(ctx context.Context, code map[string]string) (map[string]string, error) {
+		got = runner.LanguageFromContext(ctx)
+		return code, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/s1/format", bytes.NewReader([]byte(`{"code":{"app.py":"x=1"}}`)))
+	rec := httptest.NewRecorder()
+	m.server.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || got != runner.LanguagePython {
+		t.Errorf("status = %d, language = %q; want 200 and python detected", rec.Code, got)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/sessions/s1/format", bytes.NewReader([]byte(`{"code":{"a.cob":""},"language":"cobol"}`)))
+	rec = httptest.NewRecorder()
+	m.server.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unsupported language status = %d; want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
 func TestHandleFormat_Error(t *testing.T) {
 	m := newServerWithMocks()
 
@@ -70,3 +98,50 @@ func TestHandleCreateRun_RunnerError(t *testing.T) {
 		t.Fatalf("status = %d; want %d", rec.Code, http.StatusInternalServerError)
 	}
 }
+
+func TestHandleFormat_Formatter(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.exerciseLoader = writeCalibrationPack(t)
+	pack := filepath.Join(m.server.exerciseLoader.BasePath(), "go-v1", "pack.yaml")
+	f, err := os.OpenFile(pack, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("formatter: gofumpt\n")
+	_ = f.Close()
+
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: id, ExerciseID: "go-v1/basics/hello"}, nil
+	}
+	var got runner.Formatter
+	m.executor.runFormatFixFn = func(ctx context.Context, code map[string]string) (map[string]string, error) {
+		got = runner.FormatterFromContext(ctx)
+		return map[string]string{"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n"}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/s1/format",
+		bytes.NewReader([]byte(`{"code":{"main.go":"package main\nfunc main() { fmt.Println() }"}}`)))
+	rec := httptest.NewRecorder()
+	m.server.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || got != runner.FormatterGofumpt {
+		t.Fatalf("status = %d, formatter = %q; want 200 and the pack's gofumpt", rec.Code, got)
+	}
+	var resp struct {
+		Formatter string                `json:"formatter"`
+		Imports   []runner.ImportChange `json:"imports"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Formatter != "gofumpt" || len(resp.Imports) != 1 || resp.Imports[0].Path != "fmt" || resp.Imports[0].Change != "added" {
+		t.Errorf("response = %+v, want gofumpt and fmt added", resp)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/sessions/s1/format",
+		bytes.NewReader([]byte(`{"code":{"main.go":"package main"},"formatter":"black"}`)))
+	rec = httptest.NewRecorder()
+	m.server.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown formatter status = %d; want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,38 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// handleRunOutput serves the full build, test or command output of a run
+// as plain text, with range requests, so a client can page through output
+// the run record only keeps the start and end of
+func (s *Server) handleRunOutput(w http.ResponseWriter, r *http.Request) {
+	stream := r.URL.Query().Get("stream")
+	if stream == "" {
+		stream = session.StreamTest
+	}
+
+	run, output, err := s.sessionService.RunOutput(r.Context(), r.PathValue("id"), r.PathValue("run"), stream)
+	switch {
+	case errors.Is(err, session.ErrInvalidStream):
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeValidationFailed, "stream must be build, test or command", nil)
+		return
+	case errors.Is(err, session.ErrSessionNotFound):
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+		return
+	case errors.Is(err, session.ErrRunNotFound):
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "run not found", nil)
+		return
+	case err != nil:
+		s.jsonError(w, http.StatusInternalServerError, "failed to open run output", err)
+		return
+	}
+	defer output.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	http.ServeContent(w, r, stream+".log", run.CreatedAt, output)
+}
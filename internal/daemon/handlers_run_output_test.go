@@ -0,0 +1,99 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+type closingReader struct {
+	*strings.Reader
+	closed bool
+}
+
+func (r *closingReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestHandleRunOutput_Range(t *testing.T) {
+	m := newServerWithMocks()
+	sessionID := uuid.New().String()
+	output := &closingReader{Reader: strings.NewReader("line 1\nline 2\nline 3\n")}
+	var gotStream string
+	m.sessions.runOutputFn = func(ctx context.Context, id, runID, stream string) (*session.Run, io.ReadSeekCloser, error) {
+		gotStream = stream
+		return &session.Run{ID: runID, SessionID: id, CreatedAt: time.Now()}, output, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/sessions/"+sessionID+"/runs/run-1/output?stream=build", nil)
+	req.Header.Set("Range", "bytes=7-13")
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPartialContent {
+		t.Fatalf("expected %d, got %d: %s", http.StatusPartialContent, w.Code, w.Body.String())
+	}
+	if got := w.Body.String(); got != "line 2\n" {
+		t.Errorf("body = %q, want %q", got, "line 2\n")
+	}
+	if got := w.Header().Get("Content-Range"); got != "bytes 7-13/21" {
+		t.Errorf("Content-Range = %q", got)
+	}
+	if gotStream != session.StreamBuild || !output.closed {
+		t.Errorf("stream = %q, closed = %v", gotStream, output.closed)
+	}
+}
+
+func TestHandleRunOutput_DefaultsToTest(t *testing.T) {
+	m := newServerWithMocks()
+	var gotStream string
+	m.sessions.runOutputFn = func(ctx context.Context, id, runID, stream string) (*session.Run, io.ReadSeekCloser, error) {
+		gotStream = stream
+		return &session.Run{CreatedAt: time.Now()}, &closingReader{Reader: strings.NewReader("ok\n")}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/sessions/"+uuid.New().String()+"/runs/run-1/output", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != "ok\n" || gotStream != session.StreamTest {
+		t.Errorf("got %d %q for stream %q", w.Code, w.Body.String(), gotStream)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestHandleRunOutput_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"invalid stream", session.ErrInvalidStream, http.StatusBadRequest},
+		{"session not found", session.ErrSessionNotFound, http.StatusNotFound},
+		{"run not found", session.ErrRunNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newServerWithMocks()
+			m.sessions.runOutputFn = func(ctx context.Context, id, runID, stream string) (*session.Run, io.ReadSeekCloser, error) {
+				return nil, nil, tt.err
+			}
+			req := httptest.NewRequest(http.MethodGet, "/v1/sessions/"+uuid.New().String()+"/runs/run-1/output", nil)
+			w := httptest.NewRecorder()
+			m.server.router.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package daemon
 
 import (
-	"encoding/json"
 	"net/http"
 	"path"
 	"strings"
@@ -23,16 +22,13 @@ func (s *Server) handleCreateSandbox(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Language   string  `json:"language,omitempty"`
 		Image      string  `json:"image,omitempty"`
-		MemoryMB   int     `json:"memory_mb,omitempty"`
-		CPULimit   float64 `json:"cpu_limit,omitempty"`
+		MemoryMB   int     `json:"memory_mb,omitempty" validate:"min=0"`
+		CPULimit   float64 `json:"cpu_limit,omitempty" validate:"min=0"`
 		NetworkOff *bool   `json:"network_off,omitempty"`
 	}
 
-	if r.Body != nil && r.ContentLength > 0 {
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
-			return
-		}
+	if !s.decodeRequest(w, r, &req) {
+		return
 	}
 
 	cfg := sandbox.DefaultConfig()
@@ -51,6 +47,7 @@ func (s *Server) handleCreateSandbox(w http.ResponseWriter, r *http.Request) {
 	if req.NetworkOff != nil {
 		cfg.NetworkOff = *req.NetworkOff
 	}
+	cfg.Runtime = s.runnerRuntime
 
 	sb, err := s.SandboxManager.Create(r.Context(), sessionID, cfg)
 	if err != nil {
@@ -126,14 +123,13 @@ func (s *Server) handleSandboxExec(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.PathValue("id")
 
 	var req struct {
-		Cmd     []string          `json:"cmd"`
+		Cmd     []string          `json:"cmd" validate:"required"`
 		Code    map[string]string `json:"code,omitempty"`
-		Timeout int               `json:"timeout,omitempty"` // seconds
+		Timeout int               `json:"timeout,omitempty" validate:"min=0"` // seconds
 	}
 
 	r.Body = http.MaxBytesReader(w, r.Body, MaxRunBodyBytes)
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+	if !s.decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -146,11 +142,6 @@ func (s *Server) handleSandboxExec(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(req.Cmd) == 0 {
-		s.jsonError(w, http.StatusBadRequest, "cmd is required", nil)
-		return
-	}
-
 	cmdName := strings.TrimSpace(req.Cmd[0])
 	if cmdName == "" {
 		s.jsonError(w, http.StatusBadRequest, "cmd is required", nil)
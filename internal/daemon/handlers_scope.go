@@ -0,0 +1,35 @@
+package daemon
+
+import (
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/felixgeelhaar/temper/internal/spec"
+)
+
+// handleGetSessionSpecDrift reports drift of a feature guidance session's
+// spec against its lock. A session scoped to a monorepo package that keeps
+// its own specs is checked against that package's lock.
+func (s *Server) handleGetSessionSpecDrift(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.sessionService.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+		return
+	}
+	if sess.Intent != session.IntentFeatureGuidance || sess.SpecPath == "" || s.specService == nil {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSpecNotFound, "session has no spec", nil)
+		return
+	}
+
+	drift, err := s.specsFor(sess).GetDrift(r.Context(), sess.SpecPath)
+	if err != nil {
+		if err == spec.ErrSpecNotFound {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSpecNotFound, "spec not found", nil)
+			return
+		}
+		s.jsonError(w, http.StatusInternalServerError, "failed to get drift report", err)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, drift)
+}
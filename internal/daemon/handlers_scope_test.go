@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/felixgeelhaar/temper/internal/spec"
+	"github.com/google/uuid"
+)
+
+func scopedSession(id string) *session.Session {
+	return &session.Session{
+		ID:       id,
+		Status:   session.StatusActive,
+		Intent:   session.IntentFeatureGuidance,
+		SpecPath: "auth.yaml",
+		Scope:    "services/auth",
+		Policy:   domain.DefaultPolicy(),
+		Code: map[string]string{
+			"go.mod":                  "module example.com/mono",
+			"services/auth/login.go":  "package auth",
+			"services/billing/pay.go": "package billing",
+		},
+	}
+}
+
+func TestHandleHint_ScopedContext(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return scopedSession(id), nil
+	}
+	var got pairing.InterventionRequest
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		got = req
+		return &domain.Intervention{ID: uuid.New(), Level: domain.L1CategoryHint, Content: "Check the token expiry"}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+uuid.New().String()+"/hint", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if _, ok := got.Context.Code["services/billing/pay.go"]; ok {
+		t.Error("code outside the session's scope reached the hint context")
+	}
+	if _, ok := got.Context.Code["services/auth/login.go"]; !ok {
+		t.Error("scoped code missing from the hint context")
+	}
+}
+
+func TestHandleCreateSession_InvalidScope(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.createFn = func(ctx context.Context, req session.CreateRequest) (*session.Session, error) {
+		return nil, fmt.Errorf("%w: only feature guidance sessions can be scoped", session.ErrInvalidScope)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions", strings.NewReader(`{"intent":"greenfield","scope":"services/auth"}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"scope"`) {
+		t.Errorf("body should name scope: %s", w.Body.String())
+	}
+}
+
+func TestHandleGetSessionSpecDrift(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		if id == "greenfield" {
+			return &session.Session{ID: id, Intent: session.IntentGreenfield}, nil
+		}
+		return scopedSession(id), nil
+	}
+	var gotPath string
+	m.specs.getDriftFn = func(ctx context.Context, path string) (*spec.DriftReport, error) {
+		gotPath = path
+		return &spec.DriftReport{HasDrift: true, AddedFeatures: []string{"login"}}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/sessions/s1/spec/drift", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if gotPath != "auth.yaml" || !strings.Contains(w.Body.String(), "login") {
+		t.Errorf("drift for %q = %s; want the session spec's", gotPath, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/sessions/greenfield/spec/drift", nil)
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("greenfield session: expected %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
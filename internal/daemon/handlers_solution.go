@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/analysis"
+)
+
+// handleGetSolution returns an exercise's reference solution once the
+// learner has completed the exercise, or earlier with ?force=true for a
+// learner who has decided to look, along with how the learner's finished
+// solution compares. It lives outside /v1/exercises so tokens scoped to
+// sessions can't reach it.
+func (s *Server) handleGetSolution(w http.ResponseWriter, r *http.Request) {
+	ex, err := s.exerciseLoader.LoadExerciseByID(r.PathValue("pack") + "/" + r.PathValue("slug"))
+	if err != nil {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeExerciseNotFound, "exercise not found", err)
+		return
+	}
+	if len(ex.Solution) == 0 {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "exercise "+ex.ID+" has no reference solution", nil)
+		return
+	}
+
+	activity, err := s.sessionService.PackActivity(r.Context(), ex.PackID)
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to collect pack activity", err)
+		return
+	}
+	var completed bool
+	var approach *analysis.ApproachReport
+	for _, a := range activity.Exercises {
+		if a.ExerciseID == ex.ID {
+			completed, approach = a.Completed, a.Approach
+			break
+		}
+	}
+	if !completed && r.URL.Query().Get("force") != "true" {
+		s.jsonErrorCode(w, http.StatusForbidden, ErrCodeForbidden,
+			"complete "+ex.ID+" first, or pass force=true to see its solution anyway", nil)
+		return
+	}
+
+	resp := map[string]any{
+		"exercise_id": ex.ID,
+		"completed":   completed,
+		"files":       ex.Solution,
+	}
+	// How the learner's own solution compares, once there is one
+	if approach != nil {
+		resp["approach"] = approach
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/analysis"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func TestHandleGetSolution(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.exerciseLoader = writeCalibrationPack(t)
+	file := filepath.Join(m.server.exerciseLoader.BasePath(), "go-v1", "basics", "hello.yaml")
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		m.server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	if w := get("/v1/solutions/go-v1/basics/hello"); w.Code != http.StatusNotFound {
+		t.Errorf("no solution: status = %d, want 404", w.Code)
+	}
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("solution:\n  main.go: \"package main\\nfunc main() {}\\n\"\n"); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	completed := false
+	var approach *analysis.ApproachReport
+	m.sessions.packActivityFn = func(ctx context.Context, pack string) (*session.PackActivity, error) {
+		return &session.PackActivity{Pack: pack, Exercises: []session.ExerciseActivity{
+			{ExerciseID: "go-v1/basics/hello", Completed: completed, Approach: approach},
+		}}, nil
+	}
+
+	if w := get("/v1/solutions/go-v1/basics/hello"); w.Code != http.StatusForbidden {
+		t.Errorf("not completed: status = %d, want 403", w.Code)
+	}
+	if w := get("/v1/solutions/go-v1/basics/hello?force=true"); w.Code != http.StatusOK {
+		t.Errorf("forced: status = %d, want 200", w.Code)
+	}
+
+	completed = true
+	approach = &analysis.ApproachReport{Reference: &analysis.Comparison{Name: "reference", Similarity: 0.4, Untried: []string{"range loop"}}}
+	w := get("/v1/solutions/go-v1/basics/hello")
+	if w.Code != http.StatusOK {
+		t.Fatalf("completed: status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Completed bool                     `json:"completed"`
+		Files     map[string]string        `json:"files"`
+		Approach  *analysis.ApproachReport `json:"approach"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Completed || !strings.Contains(resp.Files["main.go"], "func main") || resp.Approach == nil {
+		t.Errorf("response = %+v, want the solution files and how the learner's compares", resp)
+	}
+
+	// The exercise itself never carries the solution
+	if w := get("/v1/exercises/go-v1/basics/hello"); strings.Contains(w.Body.String(), "func main") {
+		t.Errorf("GET exercise leaked the solution: %s", w.Body.String())
+	}
+}
@@ -0,0 +1,113 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/spec"
+)
+
+// handleCheckSpecs backs the git hooks: it reports locked specs that
+// drifted and criteria the committed files claim without evidence. It
+// reads only spec files, so it stays fast enough to run on every commit.
+func (s *Server) handleCheckSpecs(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		// Files are the committed paths, relative to the workspace root
+		Files []string `json:"files"`
+
+		// WorkspaceRoot checks the repository at this absolute path, with
+		// its .temper.yaml, instead of the daemon's workspace
+		WorkspaceRoot string `json:"workspace_root,omitempty"`
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	specs, ok := s.specsInRoot(w, req.WorkspaceRoot)
+	if !ok {
+		return
+	}
+
+	report, err := specs.Check(r.Context(), req.Files)
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to check specs", err)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, report)
+}
+
+// handleVerifySpecs backs `temper ci verify`: it verifies one spec, or
+// every spec in the workspace, and reports whether all of them passed
+func (s *Server) handleVerifySpecs(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		// SpecPath verifies only this spec
+		SpecPath string `json:"spec_path,omitempty"`
+
+		// RequireComplete fails specs with criteria not yet satisfied
+		RequireComplete bool `json:"require_complete,omitempty"`
+
+		WorkspaceRoot string `json:"workspace_root,omitempty"`
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	specs, ok := s.specsInRoot(w, req.WorkspaceRoot)
+	if !ok {
+		return
+	}
+
+	paths := []string{req.SpecPath}
+	if req.SpecPath == "" {
+		list, err := specs.List(r.Context())
+		if err != nil {
+			s.jsonError(w, http.StatusInternalServerError, "failed to list specs", err)
+			return
+		}
+		paths = paths[:0]
+		for _, sp := range list {
+			paths = append(paths, sp.FilePath)
+		}
+	}
+
+	passed := true
+	reports := make([]*spec.VerifyReport, 0, len(paths))
+	for _, path := range paths {
+		report, err := specs.Verify(r.Context(), path, req.RequireComplete)
+		if err != nil {
+			if errors.Is(err, spec.ErrSpecNotFound) {
+				s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSpecNotFound, "spec not found", nil)
+				return
+			}
+			s.jsonError(w, http.StatusInternalServerError, "failed to verify spec", err)
+			return
+		}
+		passed = passed && report.Passed
+		reports = append(reports, report)
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]any{
+		"passed": passed,
+		"specs":  reports,
+	})
+}
+
+// specsInRoot returns the spec service of the repository at root, or the
+// daemon's when root is empty. An invalid root is answered with a 400.
+func (s *Server) specsInRoot(w http.ResponseWriter, root string) (spec.SpecService, bool) {
+	if root == "" {
+		if s.specService == nil {
+			s.jsonErrorCode(w, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "spec service not available", nil)
+			return nil, false
+		}
+		return s.specService, true
+	}
+	if !validWorkspaceRoot(root) {
+		s.validationError(w, &ValidationError{Fields: []FieldError{
+			{Field: "workspace_root", Message: "must be an absolute path to an existing directory"},
+		}})
+		return nil, false
+	}
+	return s.projects.SpecService(root), true
+}
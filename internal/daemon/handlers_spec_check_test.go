@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/spec"
+)
+
+func TestHandleCheckSpecs(t *testing.T) {
+	m := newServerWithMocks()
+	var got []string
+	m.specs.checkFn = func(ctx context.Context, files []string) (*spec.CheckReport, error) {
+		got = files
+		return &spec.CheckReport{Issues: []spec.CheckIssue{
+			{SpecPath: ".specs/auth.yaml", Kind: spec.CheckDrift, Detail: "changed since it was locked"},
+		}}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/specs/check", strings.NewReader(`{"files":[".specs/auth.yaml","main.go"]}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if len(got) != 2 || got[0] != ".specs/auth.yaml" {
+		t.Errorf("files = %v; want the committed files", got)
+	}
+	var report spec.CheckReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.OK || len(report.Issues) != 1 || report.Issues[0].Kind != spec.CheckDrift {
+		t.Errorf("report = %+v; want the drift issue", report)
+	}
+}
+
+func TestHandleCheckSpecs_WorkspaceRoot(t *testing.T) {
+	root := writeProjectConfig(t, "specs_dir: docs/specs\n")
+	m := newServerWithMocks()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/specs/check", strings.NewReader(`{"workspace_root":"`+root+`"}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"ok":true`) {
+		t.Errorf("project without specs should pass: %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/specs/check", strings.NewReader(`{"workspace_root":"relative/dir"}`))
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "workspace_root") {
+		t.Errorf("relative root: got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleVerifySpecs(t *testing.T) {
+	m := newServerWithMocks()
+	m.specs.listFn = func(ctx context.Context) ([]*domain.ProductSpec, error) {
+		return []*domain.ProductSpec{{FilePath: ".specs/a.yaml"}, {FilePath: ".specs/b.yaml"}}, nil
+	}
+	var verified []string
+	m.specs.verifyFn = func(ctx context.Context, path string, requireComplete bool) (*spec.VerifyReport, error) {
+		verified = append(verified, path)
+		if !requireComplete {
+			t.Error("require_complete not passed on")
+		}
+		return &spec.VerifyReport{SpecPath: path, Passed: path == ".specs/a.yaml"}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/specs/verify", strings.NewReader(`{"require_complete":true}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if len(verified) != 2 {
+		t.Errorf("verified %v; want every spec", verified)
+	}
+	if !strings.Contains(w.Body.String(), `"passed":false`) {
+		t.Errorf("one failing spec should fail the run: %s", w.Body.String())
+	}
+
+	m.specs.verifyFn = func(ctx context.Context, path string, requireComplete bool) (*spec.VerifyReport, error) {
+		return nil, spec.ErrSpecNotFound
+	}
+	req = httptest.NewRequest(http.MethodPost, "/v1/specs/verify", strings.NewReader(`{"spec_path":".specs/gone.yaml"}`))
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing spec: got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/domain"
 	"github.com/felixgeelhaar/temper/internal/llm"
 	"github.com/felixgeelhaar/temper/internal/pairing"
 	"github.com/google/uuid"
@@ -1894,9 +1895,31 @@ func TestHandlers_DeleteSession_NotFound(t *testing.T) {
 
 	server.router.ServeHTTP(w, req)
 
-	// Handler returns 500 with "session not found" in details (not 404)
-	if w.Code != http.StatusInternalServerError && w.Code != http.StatusNotFound {
-		t.Errorf("expected status 500 or 404, got %d: %s", w.Code, w.Body.String())
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+}
+
+func TestHandlers_DeleteSession_Idempotent(t *testing.T) {
+	server, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	sessionID := createTestSession(t, server)
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodDelete, "/v1/sessions/"+sessionID, nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("delete #%d: expected status %d, got %d: %s", i+1, http.StatusOK, w.Code, w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/sessions/"+sessionID, nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("get after delete: expected status %d, got %d", http.StatusNotFound, w.Code)
 	}
 }
 
@@ -3067,8 +3090,7 @@ func TestHandlers_InvalidSessionID_Format(t *testing.T) {
 		expectedStatus []int
 	}{
 		{"get session", http.MethodGet, "/v1/sessions/not-a-uuid", "", []int{http.StatusBadRequest, http.StatusNotFound}},
-		// Delete returns 500 when session store fails to find the session
-		{"delete session", http.MethodDelete, "/v1/sessions/not-a-uuid", "", []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{"delete session", http.MethodDelete, "/v1/sessions/not-a-uuid", "", []int{http.StatusBadRequest, http.StatusNotFound}},
 		{"create run", http.MethodPost, "/v1/sessions/not-a-uuid/runs", `{}`, []int{http.StatusBadRequest, http.StatusNotFound}},
 		// Hint route is /v1/sessions/{id}/hint, not /pairing/hint
 		{"hint", http.MethodPost, "/v1/sessions/not-a-uuid/hint", `{}`, []int{http.StatusBadRequest, http.StatusNotFound}},
@@ -10209,13 +10231,19 @@ func TestMockLLM_Pairing_ResponseStructure(t *testing.T) {
 	}
 
 	// Verify complete intervention response structure
-	requiredFields := []string{"id", "intent", "level", "type", "content"}
+	requiredFields := []string{"id", "intent", "level", "type", "content", "rationale"}
 	for _, field := range requiredFields {
 		if _, ok := resp[field]; !ok {
 			t.Errorf("missing required field '%s' in response", field)
 		}
 	}
 
+	// Rationale names the contract rule behind the level
+	rationale, _ := resp["rationale"].(map[string]interface{})
+	if rationale["rule"] == "" || rationale["summary"] == "" || rationale["level"] != resp["level"] {
+		t.Errorf("unexpected rationale: %v", rationale)
+	}
+
 	// Verify content comes from mock
 	content, ok := resp["content"].(string)
 	if !ok || content == "" {
@@ -10299,6 +10327,54 @@ func TestMockLLM_HintThenCooldown_Returns429(t *testing.T) {
 	if w2.Code != http.StatusTooManyRequests {
 		t.Errorf("second hint: expected status %d, got %d: %s", http.StatusTooManyRequests, w2.Code, w2.Body.String())
 	}
+
+	var resp struct {
+		Rationale domain.LevelRationale `json:"rationale"`
+	}
+	if err := json.NewDecoder(w2.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Rationale.Rule != domain.RuleCooldown || resp.Rationale.CooldownRemaining <= 0 {
+		t.Errorf("cooldown rationale = %+v", resp.Rationale)
+	}
+
+	// The contract reports the cooldown too
+	req3 := httptest.NewRequest(http.MethodGet, "/v1/sessions/"+sessionID+"/contract", nil)
+	w3 := httptest.NewRecorder()
+	server.router.ServeHTTP(w3, req3)
+	if w3.Code != http.StatusOK {
+		t.Fatalf("contract: expected status %d, got %d: %s", http.StatusOK, w3.Code, w3.Body.String())
+	}
+	var contract struct {
+		CooldownRemaining float64 `json:"cooldown_remaining"`
+		Levels            []struct {
+			Level     int    `json:"level"`
+			Available bool   `json:"available"`
+			Rule      string `json:"rule"`
+		} `json:"levels"`
+	}
+	if err := json.NewDecoder(w3.Body).Decode(&contract); err != nil {
+		t.Fatalf("decode contract: %v", err)
+	}
+	if contract.CooldownRemaining <= 0 || len(contract.Levels) != 6 {
+		t.Fatalf("unexpected contract: %+v", contract)
+	}
+	if contract.Levels[1].Available || contract.Levels[1].Rule != domain.RuleCooldown || contract.Levels[5].Rule != domain.RuleEscalation {
+		t.Errorf("unexpected levels: %+v", contract.Levels)
+	}
+}
+
+func TestMockLLM_Contract_NotFound(t *testing.T) {
+	server, cleanup := setupTestServerWithMockLLM(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/sessions/nope/contract", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
 }
 
 func TestMockLLM_Pairing_WithCode_Validates(t *testing.T) {
@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// handleExplainTest explains why one failed test of a run failed. The
+// answer follows that test into the code it calls instead of spending the
+// prompt on the rest of the run, and is kept on the run, so asking again
+// costs nothing.
+func (s *Server) handleExplainTest(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Test string `json:"test" validate:"required"` // as reported in the run, subtests included
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	explanation, err := s.sessionService.ExplainTest(r.Context(), r.PathValue("id"), r.PathValue("run"), req.Test)
+	switch {
+	case errors.Is(err, session.ErrSessionNotFound):
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+	case errors.Is(err, session.ErrRunNotFound):
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "run not found", nil)
+	case errors.Is(err, session.ErrTestNotFailed):
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "no failed test named "+req.Test+" in this run", nil)
+	case errors.Is(err, session.ErrNoTestExplainer):
+		s.jsonErrorCode(w, http.StatusServiceUnavailable, ErrCodeLLMUnavailable, "no LLM provider configured", err)
+	case err != nil:
+		s.pairingError(w, "failed to explain test", err)
+	default:
+		s.jsonResponse(w, http.StatusOK, explanation)
+	}
+}
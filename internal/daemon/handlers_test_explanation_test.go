@@ -0,0 +1,71 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+func TestExplainTest(t *testing.T) {
+	m := newServerWithMocks()
+	sessionID := uuid.New().String()
+	var gotRun, gotTest string
+	m.sessions.explainTestFn = func(ctx context.Context, id, runID, test string) (*domain.TestExplanation, error) {
+		gotRun, gotTest = runID, test
+		return &domain.TestExplanation{Test: test, Explanation: "Sum skips the last element.", Functions: []string{"Sum"}}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/runs/run-1/explain-test",
+		strings.NewReader(`{"test": "TestSum/two_items"}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp domain.TestExplanation
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Explanation != "Sum skips the last element." || gotRun != "run-1" || gotTest != "TestSum/two_items" {
+		t.Errorf("resp = %+v, run %q, test %q", resp, gotRun, gotTest)
+	}
+}
+
+func TestExplainTest_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"missing test", `{}`, nil, http.StatusBadRequest},
+		{"session not found", `{"test": "TestSum"}`, session.ErrSessionNotFound, http.StatusNotFound},
+		{"run not found", `{"test": "TestSum"}`, session.ErrRunNotFound, http.StatusNotFound},
+		{"test passed", `{"test": "TestSum"}`, session.ErrTestNotFailed, http.StatusNotFound},
+		{"no explainer", `{"test": "TestSum"}`, session.ErrNoTestExplainer, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newServerWithMocks()
+			m.sessions.explainTestFn = func(ctx context.Context, id, runID, test string) (*domain.TestExplanation, error) {
+				return nil, tt.err
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+uuid.New().String()+"/runs/run-1/explain-test", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			m.server.router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
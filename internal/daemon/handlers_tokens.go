@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/apitoken"
+)
+
+// Scoped token handlers. They live under /v1/admin, which no narrow scope
+// reaches, so only the daemon's own token or an admin token manages them.
+
+func (s *Server) handleCreateToken(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name  string `json:"name"`
+		Scope string `json:"scope" validate:"required"`
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+	if s.tokens == nil {
+		s.jsonErrorCode(w, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "token store not configured", nil)
+		return
+	}
+
+	token, secret, err := s.tokens.Create(req.Name, req.Scope)
+	if errors.Is(err, apitoken.ErrInvalidScope) {
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to create token", err)
+		return
+	}
+	s.jsonResponse(w, http.StatusCreated, map[string]interface{}{
+		"id":         token.ID,
+		"name":       token.Name,
+		"scope":      token.Scope,
+		"created_at": token.CreatedAt,
+		"token":      secret, // shown this once
+	})
+}
+
+func (s *Server) handleListTokens(w http.ResponseWriter, r *http.Request) {
+	result := []map[string]interface{}{}
+	if s.tokens != nil {
+		for _, t := range s.tokens.List() {
+			result = append(result, map[string]interface{}{
+				"id":         t.ID,
+				"name":       t.Name,
+				"scope":      t.Scope,
+				"created_at": t.CreatedAt,
+			})
+		}
+	}
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"tokens": result,
+	})
+}
+
+func (s *Server) handleRevokeToken(w http.ResponseWriter, r *http.Request) {
+	if s.tokens == nil {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "token not found", nil)
+		return
+	}
+	err := s.tokens.Revoke(r.PathValue("id"))
+	if errors.Is(err, apitoken.ErrNotFound) {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "token not found", nil)
+		return
+	}
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to revoke token", err)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"revoked": true,
+	})
+}
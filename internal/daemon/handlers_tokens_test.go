@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/apitoken"
+)
+
+func TestAuthMiddleware_ScopedTokens(t *testing.T) {
+	tokens, err := apitoken.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	_, analytics, err := tokens.Create("dashboard", apitoken.ScopeAnalytics)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	revoked, sessions, err := tokens.Create("script", apitoken.ScopeSessions)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	handler := authMiddleware("secret-token", tokens)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	do := func(token, method, path string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	tests := []struct {
+		name, token, method, path string
+		want                      int
+	}{
+		{"analytics reads analytics", analytics, http.MethodGet, "/v1/analytics/overview", http.StatusOK},
+		{"analytics can't touch sessions", analytics, http.MethodGet, "/v1/sessions", http.StatusForbidden},
+		{"analytics can't reset the profile", analytics, http.MethodDelete, "/v1/profile", http.StatusForbidden},
+		{"sessions runs code", sessions, http.MethodPost, "/v1/sessions/abc/run", http.StatusOK},
+		{"sessions can't mint tokens", sessions, http.MethodPost, "/v1/admin/tokens", http.StatusForbidden},
+		{"daemon token does everything", "secret-token", http.MethodPost, "/v1/admin/tokens", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := do(tt.token, tt.method, tt.path); got != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+
+	if err := tokens.Revoke(revoked.ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if got := do(sessions, http.MethodGet, "/v1/sessions"); got != http.StatusUnauthorized {
+		t.Errorf("revoked token got %d, want %d", got, http.StatusUnauthorized)
+	}
+}
+
+func TestHandleTokens(t *testing.T) {
+	m := newServerWithMocks()
+	tokens, err := apitoken.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	m.server.tokens = tokens
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		m.server.router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	if w := serve(http.MethodPost, "/v1/admin/tokens", `{"scope":"root"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("create with a bad scope = %d, want 400: %s", w.Code, w.Body.String())
+	}
+
+	w := serve(http.MethodPost, "/v1/admin/tokens", `{"name":"grafana","scope":"analytics"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		ID    string `json:"id"`
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || !strings.HasPrefix(created.Token, apitoken.Prefix) {
+		t.Fatalf("create response %s, %v; want the secret", w.Body.String(), err)
+	}
+
+	w = serve(http.MethodGet, "/v1/admin/tokens", "")
+	if !strings.Contains(w.Body.String(), created.ID) || strings.Contains(w.Body.String(), created.Token) {
+		t.Errorf("list = %s, want the token without its secret", w.Body.String())
+	}
+
+	if w := serve(http.MethodDelete, "/v1/admin/tokens/"+created.ID, ""); w.Code != http.StatusOK {
+		t.Errorf("revoke = %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodDelete, "/v1/admin/tokens/"+created.ID, ""); w.Code != http.StatusNotFound {
+		t.Errorf("revoke twice = %d, want 404", w.Code)
+	}
+}
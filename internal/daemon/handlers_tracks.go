@@ -1,7 +1,6 @@
 package daemon
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -57,8 +56,7 @@ func (s *Server) handleCreateTrack(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var track domain.Track
-	if err := json.NewDecoder(r.Body).Decode(&track); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+	if !s.decodeRequest(w, r, &track) {
 		return
 	}
 
@@ -105,8 +103,7 @@ func (s *Server) handleUpdateTrack(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var update domain.Track
-	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+	if !s.decodeRequest(w, r, &update) {
 		return
 	}
 
@@ -156,10 +153,9 @@ func (s *Server) handleExportTrack(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		ID string `json:"id"`
+		ID string `json:"id" validate:"required"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+	if !s.decodeRequest(w, r, &req) {
 		return
 	}
 
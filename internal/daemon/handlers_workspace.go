@@ -0,0 +1,99 @@
+package daemon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// Workspace sync handlers
+//
+// Editors keep the daemon's copy of the session files current by pushing
+// only what changed, and pull back only the files whose hashes differ
+// from their own (e.g. after a patch was applied daemon-side).
+
+func (s *Server) handleGetWorkspace(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.sessionService.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, session.ManifestOf(sess.Code))
+}
+
+func (s *Server) handlePullWorkspace(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Have map[string]string `json:"have"` // path -> content hash the editor holds
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	sess, err := s.sessionService.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, session.DiffWorkspace(sess.Code, req.Have))
+}
+
+func (s *Server) handlePushWorkspace(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxRunBodyBytes)
+
+	var req struct {
+		BaseVersion string            `json:"base_version,omitempty"`
+		Files       map[string]string `json:"files"`
+		Deleted     []string          `json:"deleted,omitempty"`
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := validateCodePayload(req.Files); err != nil {
+		if pe := asPayloadError(err); pe != nil {
+			s.jsonError(w, http.StatusRequestEntityTooLarge, pe.Message, err)
+			return
+		}
+		s.jsonError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	id := r.PathValue("id")
+	if !s.requireDriver(w, r, id) {
+		return
+	}
+
+	manifest, err := s.sessionService.PushWorkspace(r.Context(), id, session.WorkspacePush{
+		BaseVersion: req.BaseVersion,
+		Files:       req.Files,
+		Deleted:     req.Deleted,
+		Validate:    validateCodePayload,
+	})
+	if pe := asPayloadError(err); pe != nil {
+		s.jsonError(w, http.StatusRequestEntityTooLarge, pe.Message, err)
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, session.ErrSessionNotFound):
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+		case errors.Is(err, session.ErrSessionNotActive):
+			s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeSessionNotActive, "session is not active", nil)
+		case errors.Is(err, session.ErrWorkspaceConflict):
+			s.jsonErrorCode(w, http.StatusConflict, ErrCodeConflict,
+				"workspace changed since base_version; pull and retry", nil)
+		case errors.Is(err, session.ErrInvalidPath):
+			s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeInvalidPayload, err.Error(), nil)
+		default:
+			s.jsonError(w, http.StatusInternalServerError, "failed to update workspace", err)
+		}
+		return
+	}
+	s.collab.wrote(id, r.Header.Get(ClientHeader))
+	s.events.notify(id)
+
+	s.jsonResponse(w, http.StatusOK, manifest)
+}
@@ -3,6 +3,7 @@ package daemon
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"strings"
 	"sync"
 	"time"
 )
@@ -114,3 +115,19 @@ func (c *IdempotencyCache) Sweep() int {
 	}
 	return removed
 }
+
+// Forget evicts a session's entries, or every entry when sessionIDs is
+// nil, so erased history doesn't linger in cached responses
+func (c *IdempotencyCache) Forget(sessionIDs map[string]bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	removed := 0
+	for k := range c.entries {
+		sessionID, _, _ := strings.Cut(k, "|")
+		if sessionIDs == nil || sessionIDs[sessionID] {
+			delete(c.entries, k)
+			removed++
+		}
+	}
+	return removed
+}
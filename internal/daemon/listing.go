@@ -0,0 +1,203 @@
+package daemon
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/concept"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/patch"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 500
+)
+
+// listParams holds the pagination, sort and filter query parameters shared
+// by the list endpoints.
+type listParams struct {
+	Limit   int
+	Offset  int
+	Sort    string
+	Desc    bool
+	Filters map[string]string
+}
+
+// listFields maps a query field name to the value it reads from an item.
+// Values may be strings, ints, float64s or times.
+type listFields[T any] map[string]func(T) any
+
+var sessionListFields = listFields[*session.Session]{
+	"status":      func(s *session.Session) any { return string(s.Status) },
+	"intent":      func(s *session.Session) any { return string(s.Intent) },
+	"exercise_id": func(s *session.Session) any { return s.ExerciseID },
+	"spec_path":   func(s *session.Session) any { return s.SpecPath },
+	"created_at":  func(s *session.Session) any { return s.CreatedAt },
+	"updated_at":  func(s *session.Session) any { return s.UpdatedAt },
+	"run_count":   func(s *session.Session) any { return s.RunCount },
+	"hint_count":  func(s *session.Session) any { return s.HintCount },
+}
+
+var runHistoryFields = listFields[session.RunHistoryEntry]{
+	"number":      func(e session.RunHistoryEntry) any { return e.Number },
+	"status":      func(e session.RunHistoryEntry) any { return e.Status },
+	"created_at":  func(e session.RunHistoryEntry) any { return e.CreatedAt },
+	"duration_ms": func(e session.RunHistoryEntry) any { return e.DurationMs },
+}
+
+var specListFields = listFields[*domain.ProductSpec]{
+	"name":      func(sp *domain.ProductSpec) any { return sp.Name },
+	"version":   func(sp *domain.ProductSpec) any { return sp.Version },
+	"file_path": func(sp *domain.ProductSpec) any { return sp.FilePath },
+	"percent":   func(sp *domain.ProductSpec) any { return sp.GetProgress().PercentComplete },
+}
+
+var packListFields = listFields[*domain.ExercisePack]{
+	"id":       func(p *domain.ExercisePack) any { return p.ID },
+	"name":     func(p *domain.ExercisePack) any { return p.Name },
+	"language": func(p *domain.ExercisePack) any { return p.Language },
+}
+
+var conceptListFields = listFields[concept.Concept]{
+	"id":       func(c concept.Concept) any { return c.ID },
+	"name":     func(c concept.Concept) any { return c.Name },
+	"language": func(c concept.Concept) any { return c.Language },
+}
+
+var patchLogFields = listFields[patch.LogEntry]{
+	"timestamp":  func(e patch.LogEntry) any { return e.Timestamp },
+	"session_id": func(e patch.LogEntry) any { return e.SessionID },
+	"action":     func(e patch.LogEntry) any { return string(e.Action) },
+	"status":     func(e patch.LogEntry) any { return string(e.Status) },
+	"file":       func(e patch.LogEntry) any { return e.File },
+}
+
+// parseListParams reads limit, offset and sort from q. A leading "-" on sort
+// reverses the order. Any other parameter named in filterable becomes an
+// exact-match (case-insensitive) filter.
+func parseListParams[T any](q url.Values, fields listFields[T], filterable []string, defaultSort string) (listParams, error) {
+	p := listParams{Limit: defaultListLimit, Filters: map[string]string{}}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return p, fmt.Errorf("limit must be a positive integer")
+		}
+		p.Limit = min(n, maxListLimit)
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return p, fmt.Errorf("offset must be a non-negative integer")
+		}
+		p.Offset = n
+	}
+
+	sortBy := q.Get("sort")
+	if sortBy == "" {
+		sortBy = defaultSort
+	}
+	if strings.HasPrefix(sortBy, "-") {
+		p.Desc = true
+		sortBy = sortBy[1:]
+	}
+	if sortBy != "" {
+		if _, ok := fields[sortBy]; !ok {
+			return p, fmt.Errorf("cannot sort by %q", sortBy)
+		}
+	}
+	p.Sort = sortBy
+
+	for _, name := range filterable {
+		if v := q.Get(name); v != "" {
+			p.Filters[name] = v
+		}
+	}
+	return p, nil
+}
+
+// applyList filters, sorts and pages items. It returns the page and the
+// number of items that matched the filters.
+func applyList[T any](items []T, p listParams, fields listFields[T]) ([]T, int) {
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		if matchesFilters(item, p.Filters, fields) {
+			matched = append(matched, item)
+		}
+	}
+
+	if get, ok := fields[p.Sort]; ok {
+		sort.SliceStable(matched, func(i, j int) bool {
+			c := compareValues(get(matched[i]), get(matched[j]))
+			if p.Desc {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+
+	total := len(matched)
+	if p.Offset >= total {
+		return matched[:0], total
+	}
+	end := min(p.Offset+p.Limit, total)
+	return matched[p.Offset:end], total
+}
+
+func matchesFilters[T any](item T, filters map[string]string, fields listFields[T]) bool {
+	for name, want := range filters {
+		get, ok := fields[name]
+		if !ok {
+			continue
+		}
+		if !strings.EqualFold(fmt.Sprint(get(item)), want) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareValues orders two field values of the same kind. Values of
+// different kinds compare by their string form.
+func compareValues(a, b any) int {
+	switch av := a.(type) {
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(strings.ToLower(av), strings.ToLower(bv))
+		}
+	case int:
+		if bv, ok := b.(int); ok {
+			return av - bv
+		}
+	case float64:
+		if bv, ok := b.(float64); ok {
+			switch {
+			case av < bv:
+				return -1
+			case av > bv:
+				return 1
+			}
+			return 0
+		}
+	case time.Time:
+		if bv, ok := b.(time.Time); ok {
+			return av.Compare(bv)
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// pageInfo returns the fields every paged list response carries.
+func pageInfo(p listParams, total int) map[string]interface{} {
+	return map[string]interface{}{
+		"total":  total,
+		"limit":  p.Limit,
+		"offset": p.Offset,
+	}
+}
@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"net/url"
+	"testing"
+)
+
+type listItem struct {
+	name string
+	size int
+}
+
+var listItemFields = listFields[listItem]{
+	"name": func(i listItem) any { return i.name },
+	"size": func(i listItem) any { return i.size },
+}
+
+func TestParseListParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    listParams
+		wantErr bool
+	}{
+		{"defaults", "", listParams{Limit: defaultListLimit, Sort: "name"}, false},
+		{"descending", "sort=-size&limit=5&offset=10", listParams{Limit: 5, Offset: 10, Sort: "size", Desc: true}, false},
+		{"limit capped", "limit=100000", listParams{Limit: maxListLimit, Sort: "name"}, false},
+		{"zero limit", "limit=0", listParams{}, true},
+		{"negative offset", "offset=-1", listParams{}, true},
+		{"unknown sort", "sort=colour", listParams{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+			got, err := parseListParams(q, listItemFields, nil, "name")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Limit != tt.want.Limit || got.Offset != tt.want.Offset ||
+				got.Sort != tt.want.Sort || got.Desc != tt.want.Desc {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyList(t *testing.T) {
+	items := []listItem{{"b", 2}, {"A", 3}, {"c", 1}, {"a", 9}}
+
+	p := listParams{Limit: 2, Sort: "size", Desc: true}
+	page, total := applyList(items, p, listItemFields)
+	if total != 4 || len(page) != 2 || page[0].size != 9 || page[1].size != 3 {
+		t.Errorf("sorted page = %+v (total %d)", page, total)
+	}
+
+	p = listParams{Limit: 10, Sort: "size", Filters: map[string]string{"name": "a"}}
+	page, total = applyList(items, p, listItemFields)
+	if total != 2 || page[0].size != 3 || page[1].size != 9 {
+		t.Errorf("filtered page = %+v (total %d)", page, total)
+	}
+
+	p = listParams{Limit: 10, Offset: 10}
+	page, total = applyList(items, p, listItemFields)
+	if total != 4 || len(page) != 0 {
+		t.Errorf("offset past end = %+v (total %d)", page, total)
+	}
+}
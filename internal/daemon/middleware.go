@@ -3,13 +3,17 @@ package daemon
 import (
 	"context"
 	"crypto/subtle"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/felixgeelhaar/temper/internal/apitoken"
 	"github.com/felixgeelhaar/temper/internal/correlation"
 )
 
@@ -58,6 +62,22 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Flush passes through so SSE handlers can stream behind the middleware
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// canceledRequests counts requests whose client disconnected before the
+// response was written. Mirrored into /v1/metrics by handleMetrics.
+var canceledRequests atomic.Int64
+
 // loggingMiddleware logs HTTP requests with timing and status
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -78,6 +98,19 @@ func loggingMiddleware(next http.Handler) http.Handler {
 		// Get correlation ID for logging
 		correlationID := GetCorrelationID(r.Context())
 
+		// A client that hung up isn't a server error, whatever the
+		// handler wrote after its work was cut short
+		if errors.Is(r.Context().Err(), context.Canceled) {
+			canceledRequests.Add(1)
+			slog.Info("request canceled by client",
+				"correlation_id", correlationID,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"duration_ms", duration.Milliseconds(),
+			)
+			return
+		}
+
 		// Log based on status code
 		if wrapped.statusCode >= 500 {
 			slog.Error("request",
@@ -121,7 +154,7 @@ func corsMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
 			if origin != "" && allowed[origin] {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Request-ID, Authorization")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Request-ID, Authorization, "+ClientHeader)
 				w.Header().Set("Access-Control-Max-Age", "3600")
 				w.Header().Set("Vary", "Origin")
 			}
@@ -137,12 +170,15 @@ func corsMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
 }
 
 // authMiddleware enforces a Bearer token on every request except /v1/health
-// (which must be reachable for liveness probes). Token comparison uses
-// constant-time equality to defeat timing oracles.
-func authMiddleware(token string) func(http.Handler) http.Handler {
+// (which must be reachable for liveness probes) and the web dashboard's
+// static files under /ui, which a browser loads without a header and
+// which hold no data. Token comparison uses constant-time equality to
+// defeat timing oracles. Besides the daemon's own token, a scoped token
+// from tokens is accepted for the requests its scope allows.
+func authMiddleware(token string, tokens *apitoken.Store) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if r.URL.Path == "/v1/health" || r.Method == http.MethodOptions {
+			if r.URL.Path == "/v1/health" || r.Method == http.MethodOptions || isUIPath(r.URL.Path) {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -154,18 +190,35 @@ func authMiddleware(token string) func(http.Handler) http.Handler {
 					"correlation_id", GetCorrelationID(r.Context()),
 					"path", r.URL.Path,
 				)
-				http.Error(w, `{"error_code":"UNAUTHORIZED","message":"missing bearer token"}`, http.StatusUnauthorized)
+				writeError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "missing bearer token", nil)
 				w.Header().Set("Content-Type", "application/json")
 				return
 			}
 
 			provided := strings.TrimPrefix(header, prefix)
-			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scoped, ok := tokens.Lookup(provided)
+			if !ok {
 				slog.Warn("auth: invalid bearer token",
 					"correlation_id", GetCorrelationID(r.Context()),
 					"path", r.URL.Path,
 				)
-				http.Error(w, `{"error_code":"UNAUTHORIZED","message":"invalid bearer token"}`, http.StatusUnauthorized)
+				writeError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "invalid bearer token", nil)
+				return
+			}
+			if !apitoken.Allows(scoped.Scope, r.Method, r.URL.Path) {
+				slog.Warn("auth: token scope denies request",
+					"correlation_id", GetCorrelationID(r.Context()),
+					"token", scoped.ID,
+					"scope", scoped.Scope,
+					"path", r.URL.Path,
+				)
+				writeError(w, http.StatusForbidden, ErrCodeForbidden,
+					fmt.Sprintf("token scope %q does not allow %s %s", scoped.Scope, r.Method, r.URL.Path), nil)
 				return
 			}
 
@@ -174,6 +227,11 @@ func authMiddleware(token string) func(http.Handler) http.Handler {
 	}
 }
 
+// isUIPath reports whether path is one of the web dashboard's files
+func isUIPath(path string) bool {
+	return path == "/ui" || strings.HasPrefix(path, "/ui/")
+}
+
 // hostGuardMiddleware rejects requests whose Host header is not in the
 // allowlist. Defends against DNS-rebinding attacks where a malicious page in
 // the user's browser resolves an attacker-controlled domain to 127.0.0.1 and
@@ -204,7 +262,7 @@ func hostGuardMiddleware(allowedHosts []string) func(http.Handler) http.Handler
 				"host", r.Host,
 				"path", r.URL.Path,
 			)
-			http.Error(w, `{"error_code":"FORBIDDEN_HOST","message":"host not allowed"}`, http.StatusForbidden)
+			writeError(w, http.StatusForbidden, ErrCodeForbiddenHost, "host not allowed", nil)
 		})
 	}
 }
@@ -221,7 +279,7 @@ func recoveryMiddleware(next http.Handler) http.Handler {
 					"method", r.Method,
 					"path", r.URL.Path,
 				)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				writeError(w, http.StatusInternalServerError, ErrCodeInternal, "internal server error", nil)
 			}
 		}()
 		next.ServeHTTP(w, r)
@@ -130,6 +130,23 @@ func TestLoggingMiddleware_CapturesStatusCode(t *testing.T) {
 	}
 }
 
+func TestLoggingMiddleware_CountsClientCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel() // the editor hangs up mid-request
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	before := canceledRequests.Load()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/x/hint", nil).WithContext(ctx)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+
+	if n := canceledRequests.Load() - before; n != 1 {
+		t.Errorf("canceled requests grew by %d, want 1", n)
+	}
+}
+
 func TestLoggingMiddleware_PassesThroughBody(t *testing.T) {
 	expectedBody := "test response body"
 
@@ -148,6 +165,24 @@ func TestLoggingMiddleware_PassesThroughBody(t *testing.T) {
 	}
 }
 
+func TestLoggingMiddleware_Flushes(t *testing.T) {
+	handler := loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("wrapped writer is not an http.Flusher; SSE handlers can't stream")
+		}
+		w.Write([]byte("event: content\n\n"))
+		flusher.Flush()
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/sessions/x/hint", nil))
+
+	if !rec.Flushed {
+		t.Error("Flush did not reach the underlying writer")
+	}
+}
+
 func TestRecoveryMiddleware_NoPanic(t *testing.T) {
 	handler := recoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -363,7 +398,7 @@ func TestCorrelationIDKey_Constant(t *testing.T) {
 }
 
 func TestAuthMiddleware_RejectsMissingHeader(t *testing.T) {
-	mw := authMiddleware("secret-token")
+	mw := authMiddleware("secret-token", nil)
 	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Fatal("handler should not be called when token is missing")
 	}))
@@ -378,7 +413,7 @@ func TestAuthMiddleware_RejectsMissingHeader(t *testing.T) {
 }
 
 func TestAuthMiddleware_RejectsWrongToken(t *testing.T) {
-	mw := authMiddleware("secret-token")
+	mw := authMiddleware("secret-token", nil)
 	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Fatal("handler should not be called for invalid token")
 	}))
@@ -394,7 +429,7 @@ func TestAuthMiddleware_RejectsWrongToken(t *testing.T) {
 }
 
 func TestAuthMiddleware_RejectsMalformedHeader(t *testing.T) {
-	mw := authMiddleware("secret-token")
+	mw := authMiddleware("secret-token", nil)
 	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Fatal("handler should not be called for malformed header")
 	}))
@@ -411,7 +446,7 @@ func TestAuthMiddleware_RejectsMalformedHeader(t *testing.T) {
 
 func TestAuthMiddleware_AcceptsValidToken(t *testing.T) {
 	called := false
-	mw := authMiddleware("secret-token")
+	mw := authMiddleware("secret-token", nil)
 	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		called = true
 		w.WriteHeader(http.StatusOK)
@@ -432,7 +467,7 @@ func TestAuthMiddleware_AcceptsValidToken(t *testing.T) {
 
 func TestAuthMiddleware_BypassesHealth(t *testing.T) {
 	called := false
-	mw := authMiddleware("secret-token")
+	mw := authMiddleware("secret-token", nil)
 	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		called = true
 		w.WriteHeader(http.StatusOK)
@@ -450,6 +485,25 @@ func TestAuthMiddleware_BypassesHealth(t *testing.T) {
 	}
 }
 
+func TestAuthMiddleware_BypassesUIFiles(t *testing.T) {
+	mw := authMiddleware("secret-token", nil)
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for path, want := range map[string]int{
+		"/ui":        http.StatusOK,
+		"/ui/app.js": http.StatusOK,
+		"/uix":       http.StatusUnauthorized,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("%s: status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
+
 func TestHostGuardMiddleware_AllowsAllowlisted(t *testing.T) {
 	called := false
 	mw := hostGuardMiddleware([]string{"127.0.0.1"})
@@ -0,0 +1,100 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/exercise"
+	"github.com/felixgeelhaar/temper/internal/fixture"
+)
+
+// MockOptions configure RunMock
+type MockOptions struct {
+	// Bundle holds the exercise packs to serve
+	Bundle fs.FS
+	// Port to listen on; 0 picks a free one
+	Port int
+	// Token clients must send; empty leaves the API unauthenticated
+	Token string
+	// DataDir keeps sessions and other state; empty uses a temporary
+	// directory removed on exit
+	DataDir string
+	// Ready is called with the bound address once requests are accepted
+	Ready func(addr string)
+}
+
+// RunMock serves the v1 API with canned LLM, runner and sandbox
+// responses until SIGINT or SIGTERM, for editor plugin test suites. It
+// never reads the user's config or touches ~/.temper, so it runs beside a
+// real daemon.
+func RunMock(opts MockOptions) error {
+	dataDir := opts.DataDir
+	if dataDir == "" {
+		dir, err := os.MkdirTemp("", "temper-mockd-*")
+		if err != nil {
+			return fmt.Errorf("create data dir: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(dir) }()
+		dataDir = dir
+	}
+
+	exercisePath := filepath.Join(dataDir, "exercises")
+	if opts.Bundle != nil {
+		if _, err := exercise.ExtractBundle(opts.Bundle, exercisePath); err != nil {
+			return err
+		}
+	}
+
+	cfg := config.DefaultLocalConfig()
+	cfg.Daemon.Bind = "127.0.0.1"
+	cfg.Daemon.Port = opts.Port
+	cfg.Daemon.AuthToken = opts.Token
+	cfg.LLM.DefaultProvider = fixture.ProviderName
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server, err := NewServer(ctx, ServerConfig{
+		Config:       cfg,
+		ExercisePath: exercisePath,
+		SpecsPath:    dataDir,
+		DataDir:      dataDir,
+		Mock:         true,
+	})
+	if err != nil {
+		return fmt.Errorf("create server: %w", err)
+	}
+
+	addr, err := server.Listen()
+	if err != nil {
+		return err
+	}
+	if opts.Ready != nil {
+		opts.Ready(addr)
+	}
+
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		<-sigCh
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("shutdown error", "error", err)
+		}
+	}()
+
+	if err := server.Start(); err != http.ErrServerClosed {
+		return fmt.Errorf("server error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/fixture"
+)
+
+// newMockDaemon serves the v1 API the way `temper mockd` does, over the
+// repository's exercise packs
+func newMockDaemon(t *testing.T) *httptest.Server {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.DefaultLocalConfig()
+	cfg.LLM.DefaultProvider = fixture.ProviderName
+
+	srv, err := NewServer(context.Background(), ServerConfig{
+		Config:       cfg,
+		ExercisePath: "../../exercises",
+		SpecsPath:    dir,
+		DataDir:      dir,
+		Mock:         true,
+	})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	ts := httptest.NewServer(srv.server.Handler)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func mockCall(t *testing.T, ts *httptest.Server, method, path, body string, want int) map[string]any {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var out map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	if resp.StatusCode != want {
+		t.Fatalf("%s %s = %d, want %d: %v", method, path, resp.StatusCode, want, out)
+	}
+	return out
+}
+
+// TestMockDaemon_Contract walks the calls an editor plugin makes and pins
+// the canned answers its tests can rely on
+func TestMockDaemon_Contract(t *testing.T) {
+	ts := newMockDaemon(t)
+
+	mockCall(t, ts, "GET", "/v1/health", "", http.StatusOK)
+	providers := mockCall(t, ts, "GET", "/v1/config/providers", "", http.StatusOK)
+	if !strings.Contains(toJSON(providers), `"mock"`) {
+		t.Errorf("providers = %v, want mock", providers)
+	}
+
+	sess := mockCall(t, ts, "POST", "/v1/sessions", `{"exercise_id": "go-v1/basics/hello-world"}`, http.StatusCreated)
+	id, _ := sess["id"].(string)
+	if id == "" {
+		t.Fatalf("session has no id: %v", sess)
+	}
+
+	code := `{"code": {"main_test.go": "package main\n\nfunc TestHello(t *testing.T) {}\n"}, "format": true, "build": true, "test": true}`
+	run := toJSON(mockCall(t, ts, "POST", "/v1/sessions/"+id+"/runs", code, http.StatusOK))
+	for _, want := range []string{`"test_ok":true`, `"TestHello"`} {
+		if !strings.Contains(run, want) {
+			t.Errorf("passing run missing %s: %s", want, run)
+		}
+	}
+
+	failing := `{"code": {"main_test.go": "// temper:mock-test-fail\nfunc TestHello(t *testing.T) {}\n"}, "test": true}`
+	if run := toJSON(mockCall(t, ts, "POST", "/v1/sessions/"+id+"/runs", failing, http.StatusOK)); !strings.Contains(run, `"test_ok":false`) {
+		t.Errorf("marked run should fail: %s", run)
+	}
+
+	hint := toJSON(mockCall(t, ts, "POST", "/v1/sessions/"+id+"/hint", `{}`, http.StatusOK))
+	if !strings.Contains(hint, fixture.Hint) {
+		t.Errorf("hint = %s, want the canned hint", hint)
+	}
+}
+
+func TestMockDaemon_StreamsCannedHint(t *testing.T) {
+	ts := newMockDaemon(t)
+	sess := mockCall(t, ts, "POST", "/v1/sessions", `{"exercise_id": "go-v1/basics/hello-world"}`, http.StatusCreated)
+
+	resp, err := ts.Client().Post(ts.URL+"/v1/sessions/"+sess["id"].(string)+"/hint", "application/json", strings.NewReader(`{"stream": true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var events []string
+	var content strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	event := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+			events = append(events, event)
+		case strings.HasPrefix(line, "data: ") && event == "content":
+			var chunk string
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &chunk); err != nil {
+				chunk = strings.TrimPrefix(line, "data: ")
+			}
+			content.WriteString(chunk)
+		}
+	}
+
+	if len(events) < 3 || events[0] != "metadata" || events[len(events)-1] != "done" {
+		t.Errorf("events = %v, want metadata, content..., done", events)
+	}
+	if content.String() != fixture.Hint {
+		t.Errorf("streamed content = %q, want %q", content.String(), fixture.Hint)
+	}
+}
+
+func TestMockDaemon_Sandbox(t *testing.T) {
+	ts := newMockDaemon(t)
+	sess := mockCall(t, ts, "POST", "/v1/sessions", `{"exercise_id": "go-v1/basics/hello-world"}`, http.StatusCreated)
+	id := sess["id"].(string)
+
+	mockCall(t, ts, "POST", "/v1/sessions/"+id+"/sandbox", `{}`, http.StatusCreated)
+	mockCall(t, ts, "GET", "/v1/sessions/"+id+"/sandbox", "", http.StatusOK)
+	mockCall(t, ts, "DELETE", "/v1/sessions/"+id+"/sandbox", "", http.StatusOK)
+}
+
+func toJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -3,9 +3,11 @@ package daemon
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"time"
 
+	"github.com/felixgeelhaar/temper/internal/concept"
 	"github.com/felixgeelhaar/temper/internal/domain"
 	"github.com/felixgeelhaar/temper/internal/llm"
 	"github.com/felixgeelhaar/temper/internal/pairing"
@@ -24,10 +26,31 @@ var errNotImplemented = errors.New("mock: not implemented")
 type mockSessionService struct {
 	createFn             func(ctx context.Context, req session.CreateRequest) (*session.Session, error)
 	getFn                func(ctx context.Context, id string) (*session.Session, error)
+	listFn               func(ctx context.Context) ([]*session.Session, error)
 	deleteFn             func(ctx context.Context, id string) error
 	runCodeFn            func(ctx context.Context, sessionID string, req session.RunRequest) (*session.Run, error)
 	updateCodeFn         func(ctx context.Context, id string, code map[string]string) (*session.Session, error)
 	recordInterventionFn func(ctx context.Context, intervention *session.Intervention) error
+	getRunsFn            func(ctx context.Context, sessionID string) ([]*session.Run, error)
+	getInterventionsFn   func(ctx context.Context, sessionID string) ([]*session.Intervention, error)
+	packActivityFn       func(ctx context.Context, pack string) (*session.PackActivity, error)
+	lessonsFn            func(ctx context.Context) ([]session.Lesson, error)
+	explainTestFn        func(ctx context.Context, sessionID, runID, test string) (*domain.TestExplanation, error)
+	submitRootCauseFn    func(ctx context.Context, id string, answers []domain.RootCauseAnswer) (*session.RootCauseResult, error)
+	pushWorkspaceFn      func(ctx context.Context, id string, push session.WorkspacePush) (*session.WorkspaceManifest, error)
+	runsForSpecFn        func(ctx context.Context, specPath string) ([]*session.Run, error)
+	addAuthoringSpecFn   func(ctx context.Context, id, specPath string) (*session.Session, error)
+	searchFn             func(ctx context.Context, q session.SearchQuery) ([]session.SearchHit, error)
+	pruneFn              func(ctx context.Context, policy session.RetentionPolicy, now time.Time, dryRun bool) (*session.PruneReport, error)
+	eraseFn              func(ctx context.Context, scope session.EraseScope, dryRun bool) (*session.EraseReport, error)
+	exerciseVersionFn    func(ctx context.Context, id string) (*session.ExerciseVersionStatus, error)
+	migrateExerciseFn    func(ctx context.Context, id string) (*session.ExerciseMigration, error)
+	pinExerciseFn        func(ctx context.Context, id string) (*session.ExerciseVersionStatus, error)
+	assignFn             func(ctx context.Context, req session.AssignRequest) ([]session.AssignResult, error)
+	assignmentsFn        func(ctx context.Context, cohort string) ([]*session.Session, error)
+	heartbeatFn          func(ctx context.Context, id string) (*session.Session, error)
+	runOutputFn          func(ctx context.Context, sessionID, runID, stream string) (*session.Run, io.ReadSeekCloser, error)
+	openArtifactFn       func(ctx context.Context, sessionID, runID, path string) (*domain.RunArtifact, io.ReadSeekCloser, error)
 }
 
 func (m *mockSessionService) Create(ctx context.Context, req session.CreateRequest) (*session.Session, error) {
@@ -44,6 +67,13 @@ func (m *mockSessionService) Get(ctx context.Context, id string) (*session.Sessi
 	return nil, errNotImplemented
 }
 
+func (m *mockSessionService) List(ctx context.Context) ([]*session.Session, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx)
+	}
+	return nil, errNotImplemented
+}
+
 func (m *mockSessionService) Delete(ctx context.Context, id string) error {
 	if m.deleteFn != nil {
 		return m.deleteFn(ctx, id)
@@ -72,6 +102,146 @@ func (m *mockSessionService) RecordIntervention(ctx context.Context, interventio
 	return errNotImplemented
 }
 
+func (m *mockSessionService) GetRuns(ctx context.Context, sessionID string) ([]*session.Run, error) {
+	if m.getRunsFn != nil {
+		return m.getRunsFn(ctx, sessionID)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) GetInterventions(ctx context.Context, sessionID string) ([]*session.Intervention, error) {
+	if m.getInterventionsFn != nil {
+		return m.getInterventionsFn(ctx, sessionID)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) PackActivity(ctx context.Context, pack string) (*session.PackActivity, error) {
+	if m.packActivityFn != nil {
+		return m.packActivityFn(ctx, pack)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) Lessons(ctx context.Context) ([]session.Lesson, error) {
+	if m.lessonsFn != nil {
+		return m.lessonsFn(ctx)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) ExplainTest(ctx context.Context, sessionID, runID, test string) (*domain.TestExplanation, error) {
+	if m.explainTestFn != nil {
+		return m.explainTestFn(ctx, sessionID, runID, test)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) SubmitRootCause(ctx context.Context, id string, answers []domain.RootCauseAnswer) (*session.RootCauseResult, error) {
+	if m.submitRootCauseFn != nil {
+		return m.submitRootCauseFn(ctx, id, answers)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) ExerciseVersion(ctx context.Context, id string) (*session.ExerciseVersionStatus, error) {
+	if m.exerciseVersionFn != nil {
+		return m.exerciseVersionFn(ctx, id)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) MigrateExercise(ctx context.Context, id string) (*session.ExerciseMigration, error) {
+	if m.migrateExerciseFn != nil {
+		return m.migrateExerciseFn(ctx, id)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) PinExercise(ctx context.Context, id string) (*session.ExerciseVersionStatus, error) {
+	if m.pinExerciseFn != nil {
+		return m.pinExerciseFn(ctx, id)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) Assign(ctx context.Context, req session.AssignRequest) ([]session.AssignResult, error) {
+	if m.assignFn != nil {
+		return m.assignFn(ctx, req)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) Assignments(ctx context.Context, cohort string) ([]*session.Session, error) {
+	if m.assignmentsFn != nil {
+		return m.assignmentsFn(ctx, cohort)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) Heartbeat(ctx context.Context, id string) (*session.Session, error) {
+	if m.heartbeatFn != nil {
+		return m.heartbeatFn(ctx, id)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) RunOutput(ctx context.Context, sessionID, runID, stream string) (*session.Run, io.ReadSeekCloser, error) {
+	if m.runOutputFn != nil {
+		return m.runOutputFn(ctx, sessionID, runID, stream)
+	}
+	return nil, nil, errNotImplemented
+}
+
+func (m *mockSessionService) OpenArtifact(ctx context.Context, sessionID, runID, path string) (*domain.RunArtifact, io.ReadSeekCloser, error) {
+	if m.openArtifactFn != nil {
+		return m.openArtifactFn(ctx, sessionID, runID, path)
+	}
+	return nil, nil, errNotImplemented
+}
+
+func (m *mockSessionService) PushWorkspace(ctx context.Context, id string, push session.WorkspacePush) (*session.WorkspaceManifest, error) {
+	if m.pushWorkspaceFn != nil {
+		return m.pushWorkspaceFn(ctx, id, push)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) AddAuthoringSpec(ctx context.Context, id, specPath string) (*session.Session, error) {
+	if m.addAuthoringSpecFn != nil {
+		return m.addAuthoringSpecFn(ctx, id, specPath)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) RunsForSpec(ctx context.Context, specPath string) ([]*session.Run, error) {
+	if m.runsForSpecFn != nil {
+		return m.runsForSpecFn(ctx, specPath)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) Search(ctx context.Context, q session.SearchQuery) ([]session.SearchHit, error) {
+	if m.searchFn != nil {
+		return m.searchFn(ctx, q)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) Prune(ctx context.Context, policy session.RetentionPolicy, now time.Time, dryRun bool) (*session.PruneReport, error) {
+	if m.pruneFn != nil {
+		return m.pruneFn(ctx, policy, now, dryRun)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSessionService) Erase(ctx context.Context, scope session.EraseScope, dryRun bool) (*session.EraseReport, error) {
+	if m.eraseFn != nil {
+		return m.eraseFn(ctx, scope, dryRun)
+	}
+	return nil, errNotImplemented
+}
+
 var _ session.SessionService = (*mockSessionService)(nil)
 
 // mockPairingService implements pairing.PairingService for testing
@@ -80,6 +250,16 @@ type mockPairingService struct {
 	interveneStreamFn   func(ctx context.Context, req pairing.InterventionRequest) (<-chan pairing.StreamChunk, error)
 	suggestForSectionFn func(ctx context.Context, authCtx pairing.AuthoringContext) ([]domain.AuthoringSuggestion, error)
 	authoringHintFn     func(ctx context.Context, authCtx pairing.AuthoringContext) (*domain.Intervention, error)
+	reviewSpecFn        func(ctx context.Context, spec *domain.ProductSpec) (*domain.SpecReview, error)
+	previewFn           func(ctx context.Context, req pairing.InterventionRequest) (*pairing.PromptPreview, error)
+	summarizePackFn     func(ctx context.Context, activity *session.PackActivity) (*domain.PackSummary, error)
+}
+
+func (m *mockPairingService) Preview(ctx context.Context, req pairing.InterventionRequest) (*pairing.PromptPreview, error) {
+	if m.previewFn != nil {
+		return m.previewFn(ctx, req)
+	}
+	return nil, errNotImplemented
 }
 
 func (m *mockPairingService) Intervene(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
@@ -110,6 +290,20 @@ func (m *mockPairingService) AuthoringHint(ctx context.Context, authCtx pairing.
 	return nil, errNotImplemented
 }
 
+func (m *mockPairingService) ReviewSpec(ctx context.Context, spec *domain.ProductSpec) (*domain.SpecReview, error) {
+	if m.reviewSpecFn != nil {
+		return m.reviewSpecFn(ctx, spec)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockPairingService) SummarizePack(ctx context.Context, activity *session.PackActivity) (*domain.PackSummary, error) {
+	if m.summarizePackFn != nil {
+		return m.summarizePackFn(ctx, activity)
+	}
+	return nil, errNotImplemented
+}
+
 var _ pairing.PairingService = (*mockPairingService)(nil)
 
 // mockPatchService implements patch.PatchService for testing
@@ -119,7 +313,7 @@ type mockPatchService struct {
 	applyPendingFn            func(sessionID uuid.UUID) (file string, content string, err error)
 	rejectPendingFn           func(sessionID uuid.UUID) error
 	getSessionPatchesFn       func(sessionID uuid.UUID) []*domain.Patch
-	getLoggerFn               func() *patch.Logger
+	getLoggerFn               func() patch.AuditLog
 }
 
 func (m *mockPatchService) ExtractFromIntervention(intervention *domain.Intervention, sessionID uuid.UUID, currentCode map[string]string) []*domain.Patch {
@@ -157,7 +351,7 @@ func (m *mockPatchService) GetSessionPatches(sessionID uuid.UUID) []*domain.Patc
 	return nil
 }
 
-func (m *mockPatchService) GetLogger() *patch.Logger {
+func (m *mockPatchService) GetLogger() patch.AuditLog {
 	if m.getLoggerFn != nil {
 		return m.getLoggerFn()
 	}
@@ -176,6 +370,11 @@ type mockSpecService struct {
 	lockFn                   func(ctx context.Context, path string) (*domain.SpecLock, error)
 	getProgressFn            func(ctx context.Context, path string) (*domain.SpecProgress, error)
 	getDriftFn               func(ctx context.Context, path string) (*spec.DriftReport, error)
+	planFn                   func(ctx context.Context, path string) (*domain.SpecPlan, error)
+	diagramFn                func(ctx context.Context, path string, format domain.DiagramFormat, write bool) (*domain.SpecDiagram, error)
+	historyFn                func(ctx context.Context, path string) ([]spec.LockRecord, error)
+	checkFn                  func(ctx context.Context, files []string) (*spec.CheckReport, error)
+	verifyFn                 func(ctx context.Context, path string, requireComplete bool) (*spec.VerifyReport, error)
 	saveFn                   func(ctx context.Context, spec *domain.ProductSpec) error
 	getWorkspaceRootFn       func() string
 }
@@ -236,6 +435,41 @@ func (m *mockSpecService) GetDrift(ctx context.Context, path string) (*spec.Drif
 	return nil, errNotImplemented
 }
 
+func (m *mockSpecService) History(ctx context.Context, path string) ([]spec.LockRecord, error) {
+	if m.historyFn != nil {
+		return m.historyFn(ctx, path)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSpecService) Check(ctx context.Context, files []string) (*spec.CheckReport, error) {
+	if m.checkFn != nil {
+		return m.checkFn(ctx, files)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSpecService) Verify(ctx context.Context, path string, requireComplete bool) (*spec.VerifyReport, error) {
+	if m.verifyFn != nil {
+		return m.verifyFn(ctx, path, requireComplete)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSpecService) Plan(ctx context.Context, path string) (*domain.SpecPlan, error) {
+	if m.planFn != nil {
+		return m.planFn(ctx, path)
+	}
+	return nil, errNotImplemented
+}
+
+func (m *mockSpecService) Diagram(ctx context.Context, path string, format domain.DiagramFormat, write bool) (*domain.SpecDiagram, error) {
+	if m.diagramFn != nil {
+		return m.diagramFn(ctx, path, format, write)
+	}
+	return nil, errNotImplemented
+}
+
 func (m *mockSpecService) Save(ctx context.Context, spec *domain.ProductSpec) error {
 	if m.saveFn != nil {
 		return m.saveFn(ctx, spec)
@@ -541,6 +775,7 @@ func newServerWithMocks() *serverWithMocks {
 		llmRegistry:    registry,
 		runnerExecutor: executor,
 		SandboxManager: sandboxMock,
+		concepts:       concept.Default(),
 	}
 
 	// Register routes (need to set up routes manually for isolated testing)
@@ -0,0 +1,18 @@
+//go:build unix
+
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+// ProcessAlive reports whether a process with the given PID exists
+func ProcessAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// Signal 0 checks for existence without delivering anything
+	return process.Signal(syscall.Signal(0)) == nil
+}
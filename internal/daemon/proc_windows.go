@@ -0,0 +1,16 @@
+//go:build windows
+
+package daemon
+
+import "os"
+
+// ProcessAlive reports whether a process with the given PID exists
+func ProcessAlive(pid int) bool {
+	// FindProcess opens a handle on Windows, so it fails for dead PIDs
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = process.Release()
+	return true
+}
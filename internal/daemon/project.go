@@ -0,0 +1,99 @@
+package daemon
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/runner"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/felixgeelhaar/temper/internal/spec"
+)
+
+// projectResolver layers a project's .temper.yaml over the global config
+// for sessions started in it. The file is read on every use, so edits
+// apply to running sessions without a restart.
+type projectResolver struct{}
+
+// load returns the project config in root, or nil when there is none. A
+// file that became unreadable or invalid after the session started is
+// logged and ignored rather than failing the session's runs and hints.
+func (projectResolver) load(root string) *config.ProjectConfig {
+	if root == "" {
+		return nil
+	}
+	cfg, err := config.LoadProjectConfig(root)
+	if err != nil {
+		slog.Warn("ignoring project config", "workspace_root", root, "error", err)
+		return nil
+	}
+	return cfg
+}
+
+// SpecService returns a spec service for the project's specs_dir
+func (p projectResolver) SpecService(root string) *spec.Service {
+	specsDir := spec.SpecDir
+	if cfg := p.load(root); cfg != nil && cfg.SpecsDir != "" {
+		specsDir = cfg.SpecsDir
+	}
+	return spec.NewServiceIn(root, specsDir)
+}
+
+// RunOverrides returns the runner settings the project overrides
+func (p projectResolver) RunOverrides(root string) runner.Overrides {
+	cfg := p.load(root)
+	if cfg == nil {
+		return runner.Overrides{}
+	}
+	return runner.Overrides{
+		Image:    cfg.Runner.Image,
+		MemoryMB: cfg.Runner.MemoryMB,
+		Timeout:  time.Duration(cfg.Runner.TimeoutSeconds) * time.Second,
+	}
+}
+
+// Commands returns the custom commands the project defines
+func (p projectResolver) Commands(root string) map[string]string {
+	if cfg := p.load(root); cfg != nil {
+		return cfg.Commands
+	}
+	return nil
+}
+
+// specsFor returns the spec service for a session: its project's when it
+// was started in one, otherwise the daemon's, narrowed to the session's
+// scope when the scoped package keeps its own specs
+func (s *Server) specsFor(sess *session.Session) spec.SpecService {
+	specs := s.specServiceConcrete
+	if sess.WorkspaceRoot != "" {
+		specs = s.projects.SpecService(sess.WorkspaceRoot)
+	}
+	if specs == nil {
+		return s.specService
+	}
+	return specs.Scoped(sess.Scope)
+}
+
+// routeToProject points a hint request at the provider and models the
+// session's project routes hints to
+func (s *Server) routeToProject(sess *session.Session, req *pairing.InterventionRequest) {
+	cfg := s.projects.load(sess.WorkspaceRoot)
+	if cfg == nil {
+		return
+	}
+	req.Provider = cfg.LLM.Provider
+	req.LevelModels = buildLevelModelMap(cfg.LLM.LevelModels)
+}
+
+// validWorkspaceRoot reports whether root is an existing directory given
+// as an absolute path
+func validWorkspaceRoot(root string) bool {
+	if !filepath.IsAbs(root) {
+		return false
+	}
+	info, err := os.Stat(root)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+const testProjectConfig = `track: strict
+specs_dir: docs/specs
+llm:
+  provider: ollama
+  level_models:
+    L1: qwen2.5-coder:7b
+runner:
+  memory_mb: 1024
+  timeout_seconds: 90
+`
+
+func writeProjectConfig(t *testing.T, content string) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, config.ProjectConfigFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestHandleCreateSession_WorkspaceRoot(t *testing.T) {
+	root := writeProjectConfig(t, testProjectConfig)
+	m := newServerWithMocks()
+	m.server.cfg = config.DefaultLocalConfig()
+	m.server.cfg.Learning.Tracks = map[string]config.TrackConfig{"strict": {MaxLevel: 1}}
+	var got session.CreateRequest
+	m.sessions.createFn = func(ctx context.Context, req session.CreateRequest) (*session.Session, error) {
+		got = req
+		return &session.Session{ID: uuid.New().String(), WorkspaceRoot: req.WorkspaceRoot}, nil
+	}
+
+	body := `{"intent":"greenfield","workspace_root":"` + root + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if got.WorkspaceRoot != root {
+		t.Errorf("WorkspaceRoot = %q; want %q", got.WorkspaceRoot, root)
+	}
+	if got.Policy == nil || got.Policy.Track != "strict" || got.Policy.MaxLevel != domain.L1CategoryHint {
+		t.Errorf("policy = %+v; want the project's strict track", got.Policy)
+	}
+}
+
+func TestHandleCreateSession_WorkspaceRootInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		root string
+		want string
+	}{
+		{"relative", "some/project", "workspace_root"},
+		{"missing", filepath.Join(t.TempDir(), "gone"), "workspace_root"},
+		{"bad config", writeProjectConfig(t, "specs_dir: ../elsewhere\n"), "specs_dir"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newServerWithMocks()
+			body := `{"intent":"greenfield","workspace_root":"` + tt.root + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/v1/sessions", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			m.server.router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("body should mention %s: %s", tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleHint_ProjectRouting(t *testing.T) {
+	root := writeProjectConfig(t, testProjectConfig)
+	m := newServerWithMocks()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: id, Status: session.StatusActive, Policy: domain.DefaultPolicy(), WorkspaceRoot: root}, nil
+	}
+	var got pairing.InterventionRequest
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		got = req
+		return &domain.Intervention{ID: uuid.New(), Level: domain.L1CategoryHint, Content: "Look at the loop bounds"}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+uuid.New().String()+"/hint", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if got.Provider != "ollama" || got.LevelModels[domain.L1CategoryHint] != "qwen2.5-coder:7b" {
+		t.Errorf("routing = %q, %v; want the project's", got.Provider, got.LevelModels)
+	}
+}
+
+func TestProjectResolver(t *testing.T) {
+	root := writeProjectConfig(t, testProjectConfig)
+	var p projectResolver
+
+	if got := p.SpecService(root).GetWorkspaceRoot(); got != root {
+		t.Errorf("spec service root = %q; want %q", got, root)
+	}
+	overrides := p.RunOverrides(root)
+	if overrides.MemoryMB != 1024 || overrides.Timeout != 90*time.Second || overrides.Image != "" {
+		t.Errorf("RunOverrides() = %+v; want 1024MB, 90s and the default image", overrides)
+	}
+
+	// An unparsable file is ignored once the session is running
+	broken := writeProjectConfig(t, "runner: [")
+	if got := p.RunOverrides(broken); got.MemoryMB != 0 || got.Timeout != 0 {
+		t.Errorf("RunOverrides(broken) = %+v; want none", got)
+	}
+}
@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/felixgeelhaar/temper/internal/analysis"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/sarif"
+)
+
+// sarifReviewRule is the rule review feedback is reported under
+const sarifReviewRule = "temper/review"
+
+// sarifRules are the rules a review log can report: the review itself and
+// one per static check. Every level is a note or warning: the findings
+// teach, they don't gate.
+var sarifRules = []sarif.Rule{
+	{ID: sarifReviewRule, Name: "Review", Description: "Code review feedback from your pairing session", Level: sarif.LevelNote},
+	{ID: "temper/" + analysis.CheckSyntax, Name: "Syntax", Description: "The code does not parse", Level: sarif.LevelWarning},
+	{ID: "temper/" + analysis.CheckUnused, Name: "Unused", Description: "A variable, import or value is never used", Level: sarif.LevelWarning},
+	{ID: "temper/" + analysis.CheckShadow, Name: "Shadow", Description: "A declaration shadows one in an outer scope", Level: sarif.LevelNote},
+	{ID: "temper/" + analysis.CheckPrintf, Name: "Printf", Description: "A format string does not match its arguments", Level: sarif.LevelWarning},
+	{ID: "temper/" + analysis.CheckSelfAssign, Name: "SelfAssign", Description: "A variable is assigned to itself", Level: sarif.LevelWarning},
+	{ID: "temper/" + analysis.CheckUnreachable, Name: "Unreachable", Description: "Code after a return or panic never runs", Level: sarif.LevelNote},
+}
+
+// sarifInformationURI is where SARIF viewers link the tool name to
+const sarifInformationURI = "https://github.com/felixgeelhaar/temper"
+
+// wantsSARIF reports whether a pairing request asked for ?format=sarif.
+// Only reviews can; any other format than json is answered with a 400.
+func (s *Server) wantsSARIF(w http.ResponseWriter, r *http.Request, intent domain.Intent, stream bool) (sarifOut, ok bool) {
+	switch format := r.URL.Query().Get("format"); {
+	case format == "" || format == "json":
+		return false, true
+	case format == "sarif" && intent == domain.IntentReview && !stream:
+		return true, true
+	case format == "sarif" && stream:
+		s.validationError(w, &ValidationError{Fields: []FieldError{
+			{Field: "format", Message: "sarif output can't be streamed"},
+		}})
+	default:
+		s.validationError(w, &ValidationError{Fields: []FieldError{
+			{Field: "format", Message: "must be json, or sarif for reviews"},
+		}})
+	}
+	return false, false
+}
+
+// reviewSARIF reports a review and the static analysis findings of the
+// reviewed code as a SARIF log. The review is placed at its targets, or
+// at the top of the first reviewed file when it has none.
+func reviewSARIF(review *domain.Intervention, code map[string]string) *sarif.Log {
+	var findings []sarif.Finding
+	for _, f := range analysis.Analyze(code) {
+		findings = append(findings, sarif.Finding{
+			RuleID:  "temper/" + f.Check,
+			Message: f.Message,
+			File:    f.File,
+			Line:    f.Line,
+			Column:  f.Column,
+		})
+	}
+
+	targets := review.Targets
+	if len(targets) == 0 {
+		files := make([]string, 0, len(code))
+		for name := range code {
+			files = append(files, name)
+		}
+		sort.Strings(files)
+		if len(files) > 0 {
+			targets = []domain.Target{{File: files[0], StartLine: 1}}
+		}
+	}
+	for _, t := range targets {
+		findings = append(findings, sarif.Finding{
+			RuleID:  sarifReviewRule,
+			Message: review.Content,
+			File:    t.File,
+			Line:    t.StartLine,
+			EndLine: t.EndLine,
+		})
+	}
+
+	return sarif.NewLog("temper", sarifInformationURI, sarifRules, findings)
+}
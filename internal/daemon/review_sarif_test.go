@@ -0,0 +1,98 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/sarif"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+func setupSARIFServer(t *testing.T) (*serverWithMocks, string) {
+	t.Helper()
+	m := newServerWithMocks()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: id, Status: session.StatusActive}, nil
+	}
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		return &domain.Intervention{
+			ID:      uuid.New(),
+			Intent:  req.Intent,
+			Level:   domain.L2LocationConcept,
+			Content: "Think about what happens when the slice is empty",
+			Targets: []domain.Target{{File: "main.go", StartLine: 4, EndLine: 6}},
+		}, nil
+	}
+	return m, uuid.New().String()
+}
+
+func TestReviewSARIF(t *testing.T) {
+	m, sessionID := setupSARIFServer(t)
+
+	code := "package main\n\nfunc main() {\n\tx := 1\n}\n"
+	body, _ := json.Marshal(map[string]any{"code": map[string]string{"main.go": code}})
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+"/review?format=sarif", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var log sarif.Log
+	if err := json.Unmarshal(w.Body.Bytes(), &log); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if log.Version != sarif.Version || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %s", w.Body.String())
+	}
+
+	var review, unused bool
+	for _, r := range log.Runs[0].Results {
+		switch r.RuleID {
+		case sarifReviewRule:
+			region := r.Locations[0].PhysicalLocation.Region
+			review = r.Message.Text != "" && region != nil && region.StartLine == 4 && region.EndLine == 6
+		case "temper/unused":
+			unused = true
+		}
+	}
+	if !review {
+		t.Errorf("expected the review at its target: %s", w.Body.String())
+	}
+	if !unused {
+		t.Errorf("expected the unused variable reported: %s", w.Body.String())
+	}
+}
+
+func TestReviewSARIF_Rejected(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"hint", "/hint?format=sarif", `{}`},
+		{"stream", "/review?format=sarif", `{"stream": true}`},
+		{"unknown format", "/review?format=xml", `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, sessionID := setupSARIFServer(t)
+			req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sessionID+tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			m.server.router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+			}
+		})
+	}
+}
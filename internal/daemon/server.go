@@ -3,42 +3,67 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/felixgeelhaar/temper/internal/achievement"
+	"github.com/felixgeelhaar/temper/internal/apitoken"
 	"github.com/felixgeelhaar/temper/internal/appreciation"
+	"github.com/felixgeelhaar/temper/internal/cards"
+	"github.com/felixgeelhaar/temper/internal/chaos"
+	"github.com/felixgeelhaar/temper/internal/cohort"
+	"github.com/felixgeelhaar/temper/internal/concept"
 	"github.com/felixgeelhaar/temper/internal/config"
 	"github.com/felixgeelhaar/temper/internal/docindex"
 	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/editlog"
 	"github.com/felixgeelhaar/temper/internal/exercise"
+	"github.com/felixgeelhaar/temper/internal/fixture"
+	"github.com/felixgeelhaar/temper/internal/godoc"
 	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/felixgeelhaar/temper/internal/locale"
 	"github.com/felixgeelhaar/temper/internal/metrics"
 	"github.com/felixgeelhaar/temper/internal/pairing"
 	"github.com/felixgeelhaar/temper/internal/patch"
 	"github.com/felixgeelhaar/temper/internal/profile"
+	"github.com/felixgeelhaar/temper/internal/quota"
+	"github.com/felixgeelhaar/temper/internal/redact"
+	"github.com/felixgeelhaar/temper/internal/reminder"
 	"github.com/felixgeelhaar/temper/internal/runner"
 	"github.com/felixgeelhaar/temper/internal/sandbox"
 	"github.com/felixgeelhaar/temper/internal/session"
 	"github.com/felixgeelhaar/temper/internal/spec"
+	"github.com/felixgeelhaar/temper/internal/specimport"
+	"github.com/felixgeelhaar/temper/internal/storage/blob"
+	"github.com/felixgeelhaar/temper/internal/storage/migrate"
 	sqlitestore "github.com/felixgeelhaar/temper/internal/storage/sqlite"
+	"github.com/felixgeelhaar/temper/internal/vault"
+	"github.com/felixgeelhaar/temper/internal/webui"
 	"github.com/google/uuid"
 )
 
 // Server represents the Temper daemon HTTP server
 type Server struct {
-	cfg    *config.LocalConfig
-	server *http.Server
-	router *http.ServeMux
+	cfg      *config.LocalConfig
+	server   *http.Server
+	router   *http.ServeMux
+	listener net.Listener
 
 	// Services (using interfaces for testability)
 	llmRegistry         llm.LLMRegistry
 	exerciseLoader      *exercise.Loader
+	exerciseCache       exerciseCache
 	runnerExecutor      runner.Executor
 	sessionService      session.SessionService
 	pairingService      pairing.PairingService
@@ -55,19 +80,72 @@ type Server struct {
 	// Track store for learning contract presets
 	trackStore *sqlitestore.TrackStore
 
+	// Per-project .temper.yaml settings for sessions with a workspace root
+	projects projectResolver
+
 	// Sandbox manager for persistent containers (using interface for testability)
 	SandboxManager SandboxManager
 
 	// Document index service for external context
 	docindexService *docindex.Service
 
+	// Shared stats exports grouped by cohort, for leaderboards
+	cohortStore *cohort.Store
+
+	// Flashcards generated from sessions
+	cardStore *cards.Store
+
+	// Scoped tokens for integrations, checked besides the daemon's own
+	tokens *apitoken.Store
+
+	// Milestones unlocked by the learner's runs
+	achievements *achievement.Engine
+
+	// Monthly LLM use of providers with a quota
+	quota *quota.Meter
+
+	// Opt-in editor activity per session; nil unless telemetry.edit_events
+	editLog *editlog.Store
+
+	// Practice reminder scheduler; nil when reminders are off
+	reminders *reminder.Scheduler
+
+	// Installs packs from a source and stages their updates
+	packUpdates *exercise.Updater
+
+	// Docker runtime applied to runs and sandboxes (empty = runc)
+	runnerRuntime string
+
+	// Serializes issue tracker syncs so a criterion is reported once
+	trackerSyncMu sync.Mutex
+
 	// Idempotency cache for non-idempotent POSTs (run, sandbox-exec).
 	idempotency *IdempotencyCache
 
+	// Pairing calls in progress, shared with identical requests
+	pairingFlights *pairingFlights
+
 	// In-process metrics registry. Exposed at /v1/metrics in Prometheus
 	// text format. Pairing.ClampViolations() is exported separately and
 	// merged into the response.
 	metrics *metrics.Registry
+
+	// Glossary that interventions are tagged against
+	concepts *concept.Index
+
+	// Learner's resolved locale, e.g. "de"
+	locale string
+
+	// Wakes /v1/sessions/{id}/events streams when a session's cooldown
+	// changes
+	events *sessionEvents
+
+	// Participants and driver lock of sessions shared in mob mode
+	collab *collaboration
+
+	// Fault injection for end-to-end tests; nil unless built with -tags
+	// chaos
+	chaos *chaos.Injector
 }
 
 // SandboxManager defines the interface for sandbox operations
@@ -96,20 +174,76 @@ type ServerConfig struct {
 	ExercisePath string // Primary exercise path
 	SessionsPath string // Path for session storage
 	SpecsPath    string // Path for spec storage (workspace root for .specs/)
+
+	// DataDir replaces ~/.temper for everything the daemon stores
+	DataDir string
+	// Mock serves canned LLM, runner and sandbox responses instead of the
+	// configured providers and Docker (temper mockd)
+	Mock bool
 }
 
 // NewServer creates a new daemon server
 func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 	s := &Server{
-		cfg:         cfg.Config,
-		router:      http.NewServeMux(),
-		idempotency: NewIdempotencyCache(),
-		metrics:     metrics.New(),
+		cfg:            cfg.Config,
+		router:         http.NewServeMux(),
+		idempotency:    NewIdempotencyCache(),
+		pairingFlights: newPairingFlights(),
+		metrics:        metrics.New(),
+		events:         newSessionEvents(),
+		collab:         newCollaboration(),
+		concepts:       concept.Default(),
+		chaos:          newChaosInjector(),
+	}
+
+	// Get temper directory for data storage
+	temperDir := cfg.DataDir
+	if temperDir == "" {
+		var err error
+		temperDir, err = config.TemperDir()
+		if err != nil {
+			return nil, fmt.Errorf("get temper dir: %w", err)
+		}
+	}
+
+	// Data from a newer temper is refused. Data from an older one is
+	// reported until `temper admin migrate` upgrades it; when upgrading
+	// changes nothing, the new version is just recorded.
+	plan, err := migrate.Inspect(temperDir, cfg.Config.Storage)
+	switch {
+	case errors.Is(err, migrate.ErrNewerData):
+		return nil, err
+	case err != nil:
+		slog.Warn("inspect data version", "error", err)
+	case plan.DataPending():
+		slog.Warn("data migrations pending; stop the daemon and run 'temper admin migrate'", "from", plan.From, "to", plan.To)
+	case plan.From < plan.To:
+		if _, err := migrate.Run(temperDir, migrate.Options{Storage: cfg.Config.Storage}, time.Now()); err != nil {
+			slog.Warn("record data version", "error", err)
+		}
+	}
+
+	// Provider quotas are metered before the providers are registered,
+	// since the providers are wrapped with the meter
+	limits, err := quota.FromConfig(cfg.Config.LLM.Providers)
+	if err != nil {
+		return nil, err
+	}
+	if len(limits) > 0 {
+		if s.quota, err = quota.NewMeter(temperDir, limits); err != nil {
+			return nil, fmt.Errorf("create quota meter: %w", err)
+		}
+		s.quota.OnAlert(func(a quota.Alert) {
+			slog.Warn("provider quota", "provider", a.Provider, "level", a.Level, "message", a.Message)
+			s.events.quotaAlert(a)
+		})
 	}
 
 	// Initialize LLM registry
 	registry := llm.NewRegistry()
-	if err := s.setupLLMProviders(registry); err != nil {
+	if cfg.Mock {
+		registry.Register(fixture.ProviderName, s.chaos.Provider(fixture.Provider{}))
+	} else if err := s.setupLLMProviders(registry); err != nil {
 		return nil, fmt.Errorf("setup llm providers: %w", err)
 	}
 	s.llmRegistry = registry
@@ -118,28 +252,24 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 	// Initialize exercise loader
 	s.exerciseLoader = exercise.NewLoader(cfg.ExercisePath)
 
-	// Initialize runner. Docker is mandatory: per-language sandboxing is
-	// only safe with the container boundary, and the multi-language
-	// dispatch table lives entirely in the docker executors. The previous
-	// LocalExecutor fallback was Go-only and silently produced incorrect
-	// results for Python/TS/Java/Rust/C exercises.
-	dockerCfg := runner.DockerConfig{
-		BaseImage:  cfg.Config.Runner.Docker.Image,
-		MemoryMB:   int64(cfg.Config.Runner.Docker.MemoryMB),
-		CPULimit:   cfg.Config.Runner.Docker.CPULimit,
-		NetworkOff: cfg.Config.Runner.Docker.NetworkOff,
-		Timeout:    time.Duration(cfg.Config.Runner.Docker.TimeoutSeconds) * time.Second,
+	// Packs installed from a source are checked for new versions in the
+	// background; a staged update waits for the user to apply it
+	if s.packUpdates, err = exercise.NewUpdater(cfg.ExercisePath, filepath.Join(temperDir, "packs")); err != nil {
+		return nil, fmt.Errorf("create pack updater: %w", err)
 	}
-	executor, err := runner.NewDockerExecutor(dockerCfg)
-	if err != nil {
-		return nil, fmt.Errorf("docker executor unavailable (Docker is required; install Docker Desktop or run `colima start`): %w", err)
+	s.packUpdates.OnStaged(func(u exercise.Update) {
+		slog.Info("pack update ready", "pack", u.Pack, "from", u.FromVersion, "to", u.ToVersion)
+		s.events.packUpdate(u)
+	})
+	if hours := cfg.Config.Packs.UpdateCheckHours; hours > 0 && !cfg.Mock {
+		s.packUpdates.Start(ctx, time.Duration(hours)*time.Hour)
 	}
-	s.runnerExecutor = executor
 
-	// Get temper directory for data storage
-	temperDir, err := config.TemperDir()
-	if err != nil {
-		return nil, fmt.Errorf("get temper dir: %w", err)
+	// Initialize runner
+	if cfg.Mock {
+		s.runnerExecutor = fixture.Executor{}
+	} else if err := s.setupDockerRunner(ctx, cfg.Config); err != nil {
+		return nil, err
 	}
 
 	// Initialize storage backend based on config
@@ -165,7 +295,7 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		}
 		profileStore = jsonProfileStore
 
-	default: // "sqlite" (default)
+	case "", "sqlite":
 		dbPath := cfg.Config.Storage.Path
 		if dbPath == "" {
 			dbPath = filepath.Join(temperDir, "temper.db")
@@ -184,8 +314,9 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		s.trackStore = sqlitestore.NewTrackStore(db)
 
 		// Initialize sandbox manager (optional — requires Docker)
-		sandboxBackend, err := sandbox.NewDockerBackend()
-		if err != nil {
+		if cfg.Mock {
+			s.SandboxManager = fixture.NewSandboxes()
+		} else if sandboxBackend, err := sandbox.NewDockerBackend(); err != nil {
 			slog.Warn("sandbox support disabled: Docker not available", "error", err)
 		} else {
 			sandboxStore := sqlitestore.NewSandboxStore(db)
@@ -197,17 +328,91 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		// Initialize document index service (keyword embedder as default)
 		s.docindexService = docindex.NewService(db.DB, nil)
 		slog.Info("document index service initialized")
+
+	default:
+		// Falling back to a local database would leave a team that means
+		// to share one store with a separate history on each machine
+		return nil, fmt.Errorf("unknown storage driver %q (want sqlite or json)", cfg.Config.Storage.Driver)
+	}
+
+	var cipher *vault.Cipher
+	if cfg.Config.Storage.Encrypt {
+		key, err := vault.LoadOrCreateKey(filepath.Join(temperDir, "storage.key"))
+		if err != nil {
+			return nil, fmt.Errorf("load storage key: %w", err)
+		}
+		if cipher, err = vault.NewCipher(key); err != nil {
+			return nil, fmt.Errorf("storage encryption: %w", err)
+		}
+		sessionStore = session.NewEncryptedStore(sessionStore, cipher)
+		slog.Info("session storage encryption enabled")
 	}
 
-	sessionSvc := session.NewService(sessionStore, s.exerciseLoader, s.runnerExecutor)
+	// Injected store and runner faults surface through the session
+	// service like real ones
+	sessionSvc := session.NewService(s.chaos.Store(sessionStore), s.exerciseLoader, s.chaos.Executor(s.runnerExecutor))
 	s.sessionService = sessionSvc
 	s.sessionServiceConcrete = sessionSvc
 
 	profileSvc := profile.NewService(profileStore)
+	skillModel, err := profile.NewSkillModel(cfg.Config.Learning.SkillModel)
+	if err != nil {
+		return nil, fmt.Errorf("learning_contract.skill_model: %w", err)
+	}
+	profileSvc.SetSkillModel(skillModel)
+
+	// Analytics updates the profile store fails to take are queued on
+	// disk and retried, so a transient error doesn't lose them
+	if err := profileSvc.EnableRetryQueue(temperDir); err != nil {
+		return nil, fmt.Errorf("profile retry queue: %w", err)
+	}
+	profileSvc.StartRetryLoop(ctx)
 	s.profileService = profileSvc
 
 	// Connect profile service to session service for event hooks
 	s.sessionServiceConcrete.SetProfileService(profileSvc)
+	s.sessionServiceConcrete.SetAdaptiveDifficulty(cfg.Config.Learning.AdaptiveDifficulty)
+
+	// Stuck nudges go out on the session's event stream
+	sessionSvc.SetNudgeHandler(s.events.nudge)
+	sessionSvc.StartStuckLoop(ctx, time.Minute)
+
+	// So do sessions going idle, resuming and expiring
+	sessionSvc.SetLifecycleHandler(s.events.lifecycle)
+	sessionSvc.StartActivityLoop(ctx, activityPolicy(cfg.Config.Sessions), time.Minute)
+
+	// Projects' custom commands run once the user approved them
+	sessionSvc.SetCommandAllowlist(runner.CommandAllowlist(cfg.Config.Runner.Commands.Allowlist))
+
+	// Build and test output too long for the run record is kept in files
+	var outputs session.OutputStore = session.NewDirOutputStore(filepath.Join(temperDir, "run-output"))
+	if cipher != nil {
+		outputs = session.NewEncryptedOutputStore(outputs, cipher)
+	}
+	sessionSvc.SetOutputStore(outputs)
+
+	// Pruned sessions and runs are copied to the archive first, if set
+	archive, err := blob.Open(cfg.Config.Storage.Archive)
+	if err != nil {
+		return nil, fmt.Errorf("storage.archive: %w", err)
+	}
+	if archive != nil {
+		sessionSvc.SetArchive(archive)
+		slog.Info("session archive enabled", "driver", cfg.Config.Storage.Archive.Driver)
+	}
+
+	// Prune old sessions and runs daily so storage doesn't grow unbounded
+	sessionSvc.StartPruneLoop(ctx, retentionPolicy(cfg.Config.Retention), 24*time.Hour)
+
+	// Practice reminders, collected by `temper remind watch` and editors
+	windows, err := reminder.FromConfig(cfg.Config.Reminders)
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) > 0 {
+		s.reminders = reminder.NewScheduler(windows, s.exerciseHistory)
+		s.reminders.Start(ctx, time.Minute)
+	}
 
 	// Initialize spec service
 	specsPath := cfg.SpecsPath
@@ -220,18 +425,68 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 
 	// Connect spec service to session service for feature guidance
 	s.sessionServiceConcrete.SetSpecService(specSvc)
+	s.sessionServiceConcrete.SetProjectResolver(s.projects)
 
 	// Initialize pairing service
 	pairingSvc := pairing.NewService(s.llmRegistryConcrete, cfg.Config.LLM.DefaultProvider)
 	if levelMap := buildLevelModelMap(cfg.Config.LLM.LevelModels); len(levelMap) > 0 {
 		pairingSvc.SetLevelModels(levelMap)
 	}
+	if rc := cfg.Config.LLM.Redaction; rc.Enabled {
+		redactor, err := redact.New(redactConfig(rc))
+		if err != nil {
+			return nil, fmt.Errorf("configure redaction: %w", err)
+		}
+		pairingSvc.SetRedactor(redactor, rc.IncludeLocal)
+	}
+	pairingSvc.SetLocalOnlyRules(pairing.LocalOnlyRules{
+		Packs: cfg.Config.LLM.LocalOnly.Packs,
+		Paths: cfg.Config.LLM.LocalOnly.Paths,
+	})
+	pairingSvc.SetConceptIndex(s.concepts)
+	if cfg.Config.Locale != "" {
+		if _, err := locale.Normalize(cfg.Config.Locale); err != nil {
+			slog.Warn("ignoring configured locale", "locale", cfg.Config.Locale, "supported", locale.Supported())
+		}
+	}
+	s.locale = locale.Resolve(cfg.Config.Locale)
+	pairingSvc.SetLocale(s.locale)
+	if cfg.Config.LLM.DocLookup {
+		pairingSvc.SetDocLookup(godoc.New("").Doc)
+	}
 	s.pairingService = pairingSvc
 
+	// Run errors the offline rules can't explain, and failed tests the
+	// learner asks about, go to the LLM
+	sessionSvc.SetErrorExplainer(pairingSvc)
+	sessionSvc.SetTestExplainer(pairingSvc)
+
 	// Initialize appreciation service
 	s.appreciationService = appreciation.NewService()
 
 	// Initialize patch service with logging
+	s.cohortStore = cohort.NewStore(filepath.Join(temperDir, "cohorts"))
+	if s.cardStore, err = cards.NewStore(temperDir); err != nil {
+		return nil, fmt.Errorf("create card store: %w", err)
+	}
+	if s.tokens, err = apitoken.NewStore(temperDir); err != nil {
+		return nil, fmt.Errorf("open token store: %w", err)
+	}
+	if s.achievements, err = achievement.NewEngine(temperDir); err != nil {
+		return nil, fmt.Errorf("create achievement store: %w", err)
+	}
+	sessionSvc.SetRunHandler(s.checkAchievements)
+	if cfg.Config.Telemetry.EditEvents {
+		s.editLog = editlog.NewStore(filepath.Join(temperDir, "edits"))
+		if days := cfg.Config.Retention.SessionsDays; days > 0 {
+			if n, err := s.editLog.Prune(time.Now().AddDate(0, 0, -days)); err != nil {
+				slog.Warn("prune edit events", "error", err)
+			} else if n > 0 {
+				slog.Info("retention prune", "edit_logs", n)
+			}
+		}
+	}
+
 	patchLogDir := filepath.Join(temperDir, "patches")
 	patchService, err := patch.NewServiceWithLogger(patchLogDir)
 	if err != nil {
@@ -243,6 +498,9 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 
 	// Setup routes
 	s.setupRoutes()
+	if cfg.Config.Daemon.UI {
+		s.setupUI()
+	}
 
 	// Build middleware chain.
 	// Order (outermost first): host guard -> CORS -> correlation ID ->
@@ -266,7 +524,7 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 
 	var handler http.Handler = s.router
 	if cfg.Config.Daemon.AuthToken != "" {
-		handler = authMiddleware(cfg.Config.Daemon.AuthToken)(handler)
+		handler = authMiddleware(cfg.Config.Daemon.AuthToken, s.tokens)(handler)
 	} else {
 		slog.Warn("daemon.auth_token is empty: API is unauthenticated. Run `temper init` to generate a token.")
 	}
@@ -287,8 +545,70 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 	return s, nil
 }
 
-// setupLLMProviders initializes configured LLM providers
+// setupDockerRunner creates the Docker executor that runs learners' code
+func (s *Server) setupDockerRunner(ctx context.Context, cfg *config.LocalConfig) error {
+	// Docker is mandatory: per-language sandboxing is only safe with the
+	// container boundary, and the multi-language dispatch table lives
+	// entirely in the docker executors. The previous LocalExecutor
+	// fallback was Go-only and silently produced incorrect results for
+	// Python/TS/Java/Rust/C exercises.
+	dockerRuntime, err := runner.ResolveRuntime(cfg.Runner.Docker.Isolation, cfg.Runner.Docker.Runtime)
+	if err != nil {
+		return err
+	}
+	s.runnerRuntime = dockerRuntime
+
+	dockerCfg := runner.DockerConfig{
+		Runtime:        dockerRuntime,
+		Platform:       cfg.Runner.Docker.Platform,
+		BaseImage:      runner.ImageRef(cfg.Runner.Docker.Image, cfg.Runner.Docker.ImageDigest),
+		DebugImage:     cfg.Runner.Docker.DebugImage,
+		ToolchainImage: cfg.Runner.Docker.ToolchainImage,
+		MemoryMB:       int64(cfg.Runner.Docker.MemoryMB),
+		CPULimit:       cfg.Runner.Docker.CPULimit,
+		NetworkOff:     cfg.Runner.Docker.NetworkOff,
+		Timeout:        time.Duration(cfg.Runner.Docker.TimeoutSeconds) * time.Second,
+		Dependencies: runner.DependencyPolicy{
+			Mode:      cfg.Runner.Docker.Dependencies.Mode,
+			Allowlist: cfg.Runner.Docker.Dependencies.Allowlist,
+			ProxyURL:  cfg.Runner.Docker.Dependencies.ProxyURL,
+		},
+	}
+	executor, err := runner.NewDockerExecutor(dockerCfg)
+	if err != nil {
+		return fmt.Errorf("docker executor unavailable (Docker is required; %s): %w", dockerInstallHint(runtime.GOOS), err)
+	}
+	s.runnerExecutor = executor
+
+	// Pull the runner image in the background so the first run doesn't
+	// stall (or fail opaquely) on a missing image
+	go func() {
+		if err := executor.EnsureImage(ctx); err != nil {
+			slog.Warn("runner image unavailable; run `temper runner pull`", "image", dockerCfg.BaseImage, "error", err)
+		}
+	}()
+	return nil
+}
+
+// dockerInstallHint says how to get Docker on goos. Windows has no host
+// executor to fall back on: exercises only run in the Linux runner
+// container, which needs Docker Desktop's WSL 2 backend there.
+func dockerInstallHint(goos string) string {
+	switch goos {
+	case "windows":
+		return "install Docker Desktop with the WSL 2 backend and start it"
+	case "darwin":
+		return "install Docker Desktop or run `colima start`"
+	default:
+		return "install Docker Engine or Docker Desktop"
+	}
+}
+
+// setupLLMProviders initializes configured LLM providers. Providers with
+// a quota that falls back to the local model at its hard cap use Ollama
+// from the same registry.
 func (s *Server) setupLLMProviders(registry *llm.Registry) error {
+	local := func() (llm.Provider, error) { return registry.Get("ollama") }
 	for name, providerCfg := range s.cfg.LLM.Providers {
 		if !providerCfg.Enabled {
 			continue
@@ -304,7 +624,7 @@ func (s *Server) setupLLMProviders(registry *llm.Registry) error {
 				APIKey: providerCfg.APIKey,
 				Model:  providerCfg.Model,
 			})
-			registry.Register("claude", provider)
+			registry.Register("claude", s.chaos.Provider(s.quota.Wrap(llm.WithTimeout(provider, providerCfg.Timeout), local)))
 			slog.Info("registered LLM provider", "name", "claude", "model", providerCfg.Model)
 
 		case "openai":
@@ -316,7 +636,7 @@ func (s *Server) setupLLMProviders(registry *llm.Registry) error {
 				APIKey: providerCfg.APIKey,
 				Model:  providerCfg.Model,
 			})
-			registry.Register("openai", provider)
+			registry.Register("openai", s.chaos.Provider(s.quota.Wrap(llm.WithTimeout(provider, providerCfg.Timeout), local)))
 			slog.Info("registered LLM provider", "name", "openai", "model", providerCfg.Model)
 
 		case "ollama":
@@ -324,7 +644,7 @@ func (s *Server) setupLLMProviders(registry *llm.Registry) error {
 				BaseURL: providerCfg.URL,
 				Model:   providerCfg.Model,
 			})
-			registry.Register("ollama", provider)
+			registry.Register("ollama", s.chaos.Provider(s.quota.Wrap(llm.WithTimeout(provider, providerCfg.Timeout), local)))
 			slog.Info("registered LLM provider", "name", "ollama", "model", providerCfg.Model)
 		}
 	}
@@ -347,40 +667,129 @@ func (s *Server) setupRoutes() {
 	// Exercises
 	s.router.HandleFunc("GET /v1/exercises", s.handleListExercises)
 	s.router.HandleFunc("GET /v1/exercises/{pack}", s.handleListPackExercises)
+	s.router.HandleFunc("GET /v1/exercises/{org}/{pack}", s.handleListPackExercises)
 	s.router.HandleFunc("GET /v1/exercises/{pack}/{slug...}", s.handleGetExercise)
+	s.router.HandleFunc("GET /v1/solutions/{pack}/{slug...}", s.handleGetSolution)
+
+	// Packs installed from a source, and their updates
+	s.router.HandleFunc("GET /v1/packs", s.handleListPackSources)
+	s.router.HandleFunc("POST /v1/packs", s.handleInstallPack)
+	s.router.HandleFunc("POST /v1/packs/check", s.handleCheckPackUpdates)
+	s.router.HandleFunc("POST /v1/packs/{pack}/update", s.handleApplyPackUpdate)
+	s.router.HandleFunc("POST /v1/packs/{pack}/rollback", s.handleRollbackPack)
+	s.router.HandleFunc("POST /v1/packs/{org}/{pack}/update", s.handleApplyPackUpdate)
+	s.router.HandleFunc("POST /v1/packs/{org}/{pack}/rollback", s.handleRollbackPack)
+
+	// Concept glossary
+	s.router.HandleFunc("GET /v1/concepts", s.handleListConcepts)
+	s.router.HandleFunc("GET /v1/concepts/{id}", s.handleGetConcept)
 
 	// Sessions (to be implemented in session package)
 	s.router.HandleFunc("POST /v1/sessions", s.handleCreateSession)
+	s.router.HandleFunc("GET /v1/sessions", s.handleListSessions)
 	s.router.HandleFunc("GET /v1/sessions/{id}", s.handleGetSession)
 	s.router.HandleFunc("DELETE /v1/sessions/{id}", s.handleDeleteSession)
 
 	// Runs
 	s.router.HandleFunc("POST /v1/sessions/{id}/runs", s.handleCreateRun)
+	s.router.HandleFunc("GET /v1/sessions/{id}/runs", s.handleListRuns)
+	s.router.HandleFunc("POST /v1/sessions/{id}/runs/{run}/explain-test", s.handleExplainTest)
+	s.router.HandleFunc("GET /v1/sessions/{id}/runs/{run}/output", s.handleRunOutput)
+	s.router.HandleFunc("GET /v1/sessions/{id}/runs/{run}/artifacts/{path...}", s.handleRunArtifact)
 	s.router.HandleFunc("POST /v1/sessions/{id}/format", s.handleFormat)
+	s.router.HandleFunc("POST /v1/sessions/{id}/root-cause", s.handleRootCause)
+
+	// Workspace sync
+	s.router.HandleFunc("GET /v1/sessions/{id}/workspace", s.handleGetWorkspace)
+	s.router.HandleFunc("POST /v1/sessions/{id}/workspace/pull", s.handlePullWorkspace)
+	s.router.HandleFunc("PATCH /v1/sessions/{id}/workspace", s.handlePushWorkspace)
 
 	// Pairing
 	s.router.HandleFunc("POST /v1/sessions/{id}/hint", s.handleHint)
 	s.router.HandleFunc("POST /v1/sessions/{id}/review", s.handleReview)
 	s.router.HandleFunc("POST /v1/sessions/{id}/stuck", s.handleStuck)
+	s.router.HandleFunc("POST /v1/sessions/{id}/replay", s.handleReplay)
 	s.router.HandleFunc("POST /v1/sessions/{id}/next", s.handleNext)
 	s.router.HandleFunc("POST /v1/sessions/{id}/explain", s.handleExplain)
+	s.router.HandleFunc("POST /v1/sessions/{id}/explain-symbol", s.handleExplainSymbol)
 	s.router.HandleFunc("POST /v1/sessions/{id}/escalate", s.handleEscalate)
+	s.router.HandleFunc("GET /v1/sessions/{id}/contract", s.handleGetContract)
+	s.router.HandleFunc("GET /v1/sessions/{id}/spec/drift", s.handleGetSessionSpecDrift)
+	s.router.HandleFunc("GET /v1/sessions/{id}/cooldown", s.handleGetCooldown)
+	s.router.HandleFunc("GET /v1/sessions/{id}/events", s.handleSessionEvents)
+	s.router.HandleFunc("POST /v1/sessions/{id}/heartbeat", s.handleHeartbeat)
+	s.router.HandleFunc("GET /v1/sessions/{id}/collab", s.handleGetCollab)
+	s.router.HandleFunc("POST /v1/sessions/{id}/collab/join", s.handleJoinCollab)
+	s.router.HandleFunc("POST /v1/sessions/{id}/collab/leave", s.handleLeaveCollab)
+	s.router.HandleFunc("POST /v1/sessions/{id}/collab/driver", s.handleHandOverDriver)
+	s.router.HandleFunc("GET /v1/sessions/{id}/exercise-version", s.handleGetExerciseVersion)
+	s.router.HandleFunc("POST /v1/sessions/{id}/exercise-version/migrate", s.handleMigrateExercise)
+	s.router.HandleFunc("POST /v1/sessions/{id}/exercise-version/pin", s.handlePinExercise)
+	s.router.HandleFunc("POST /v1/sessions/{id}/edits", s.handleRecordEdits)
+	s.router.HandleFunc("GET /v1/sessions/{id}/edits", s.handleGetEdits)
 
 	// Profile & Analytics
 	s.router.HandleFunc("GET /v1/profile", s.handleGetProfile)
+	s.router.HandleFunc("DELETE /v1/profile", s.handleResetProfile)
+	s.router.HandleFunc("DELETE /v1/profile/history", s.handleEraseHistory)
 	s.router.HandleFunc("GET /v1/analytics/overview", s.handleAnalyticsOverview)
 	s.router.HandleFunc("GET /v1/analytics/skills", s.handleAnalyticsSkills)
 	s.router.HandleFunc("GET /v1/analytics/errors", s.handleAnalyticsErrors)
 	s.router.HandleFunc("GET /v1/analytics/trend", s.handleAnalyticsTrend)
+	s.router.HandleFunc("GET /v1/analytics/exercises", s.handleAnalyticsExercises)
+	s.router.HandleFunc("POST /v1/analytics/packs/{pack}/summary", s.handleSummarizePack)
+	s.router.HandleFunc("POST /v1/analytics/packs/{org}/{pack}/summary", s.handleSummarizePack)
+
+	// History search
+	s.router.HandleFunc("GET /v1/search", s.handleSearch)
+
+	// Practice reminders and spaced-repetition reviews
+	s.router.HandleFunc("GET /v1/reminders", s.handleListReminders)
+	s.router.HandleFunc("POST /v1/reminders/{id}/dismiss", s.handleDismissReminder)
+	s.router.HandleFunc("GET /v1/reviews", s.handleListReviews)
+
+	// Flashcards
+	s.router.HandleFunc("GET /v1/cards", s.handleListCards)
+	s.router.HandleFunc("POST /v1/cards/generate", s.handleGenerateCards)
+	s.router.HandleFunc("POST /v1/cards/{id}/review", s.handleReviewCard)
+	s.router.HandleFunc("GET /v1/cards/export", s.handleExportCards)
+
+	// Achievements
+	s.router.HandleFunc("GET /v1/achievements", s.handleListAchievements)
+
+	// Cohorts
+	s.router.HandleFunc("GET /v1/cohorts", s.handleListCohorts)
+	s.router.HandleFunc("POST /v1/cohorts/{id}/members", s.handleAddCohortMember)
+	s.router.HandleFunc("GET /v1/cohorts/{id}/leaderboard", s.handleCohortLeaderboard)
+	s.router.HandleFunc("POST /v1/cohorts/{id}/assignments", s.handleCreateAssignment)
+	s.router.HandleFunc("GET /v1/cohorts/{id}/assignments", s.handleListAssignments)
+
+	// Admin
+	s.router.HandleFunc("POST /v1/admin/prune", s.handlePrune)
+	s.router.HandleFunc("GET /v1/admin/chaos", s.handleGetChaos)
+	s.router.HandleFunc("PUT /v1/admin/chaos", s.handleSetChaos)
+	s.router.HandleFunc("DELETE /v1/admin/chaos", s.handleClearChaos)
+	s.router.HandleFunc("POST /v1/admin/tokens", s.handleCreateToken)
+	s.router.HandleFunc("GET /v1/admin/tokens", s.handleListTokens)
+	s.router.HandleFunc("DELETE /v1/admin/tokens/{id}", s.handleRevokeToken)
 
 	// Specs (Specular format)
 	s.router.HandleFunc("POST /v1/specs", s.handleCreateSpec)
 	s.router.HandleFunc("GET /v1/specs", s.handleListSpecs)
+	s.router.HandleFunc("POST /v1/specs/import", s.handleImportSpec)
 	s.router.HandleFunc("POST /v1/specs/validate/{path...}", s.handleValidateSpec)
+	s.router.HandleFunc("POST /v1/specs/review/{path...}", s.handleReviewSpec)
+	s.router.HandleFunc("POST /v1/specs/sync/{path...}", s.handleSyncSpec)
 	s.router.HandleFunc("PUT /v1/specs/criteria/{id}", s.handleMarkCriterionSatisfied)
 	s.router.HandleFunc("POST /v1/specs/lock/{path...}", s.handleLockSpec)
 	s.router.HandleFunc("GET /v1/specs/progress/{path...}", s.handleGetSpecProgress)
+	s.router.HandleFunc("GET /v1/specs/features/{id}/progress/{path...}", s.handleGetFeatureProgress)
 	s.router.HandleFunc("GET /v1/specs/drift/{path...}", s.handleGetSpecDrift)
+	s.router.HandleFunc("GET /v1/specs/plan/{path...}", s.handleGetSpecPlan)
+	s.router.HandleFunc("POST /v1/specs/diagram/{path...}", s.handleSpecDiagram)
+	s.router.HandleFunc("GET /v1/specs/history/{path...}", s.handleGetSpecHistory)
+	s.router.HandleFunc("POST /v1/specs/check", s.handleCheckSpecs)
+	s.router.HandleFunc("POST /v1/specs/verify", s.handleVerifySpecs)
 	s.router.HandleFunc("GET /v1/specs/file/{path...}", s.handleGetSpec)
 
 	// Patches
@@ -398,6 +807,8 @@ func (s *Server) setupRoutes() {
 	s.router.HandleFunc("POST /v1/sessions/{id}/authoring/suggest", s.handleAuthoringSuggest)
 	s.router.HandleFunc("POST /v1/sessions/{id}/authoring/apply", s.handleAuthoringApply)
 	s.router.HandleFunc("POST /v1/sessions/{id}/authoring/hint", s.handleAuthoringHint)
+	s.router.HandleFunc("POST /v1/sessions/{id}/authoring/files", s.handleAuthoringAddFile)
+	s.router.HandleFunc("GET /v1/sessions/{id}/authoring/check", s.handleAuthoringCheck)
 
 	// Sandboxes
 	s.router.HandleFunc("POST /v1/sessions/{id}/sandbox", s.handleCreateSandbox)
@@ -427,13 +838,41 @@ func (s *Server) setupRoutes() {
 	s.router.HandleFunc("POST /v1/tracks/import", s.handleImportTrack)
 }
 
+// setupUI serves the read-only web dashboard at /ui
+func (s *Server) setupUI() {
+	s.router.Handle("GET /ui", http.RedirectHandler("/ui/", http.StatusMovedPermanently))
+	s.router.Handle("GET /ui/", http.StripPrefix("/ui", webui.Handler()))
+}
+
 // Start starts the HTTP server
 func (s *Server) Start() error {
+	if s.listener == nil {
+		if _, err := s.Listen(); err != nil {
+			return err
+		}
+	}
 	slog.Info("starting temper daemon",
-		"addr", s.server.Addr,
+		"addr", s.listener.Addr().String(),
 		"llm_providers", s.llmRegistry.List(),
 	)
-	return s.server.ListenAndServe()
+	return s.server.Serve(s.listener)
+}
+
+// Listen binds the configured address and returns the one actually bound.
+// Port 0 picks a free port; so does a configured port another process
+// holds, since clients find the daemon through the discovery file.
+func (s *Server) Listen() (string, error) {
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if errors.Is(err, syscall.EADDRINUSE) {
+		host, _, _ := net.SplitHostPort(s.server.Addr)
+		slog.Warn("port in use, picking a free one", "addr", s.server.Addr)
+		ln, err = net.Listen("tcp", net.JoinHostPort(host, "0"))
+	}
+	if err != nil {
+		return "", fmt.Errorf("listen on %s: %w", s.server.Addr, err)
+	}
+	s.listener = ln
+	return ln.Addr().String(), nil
 }
 
 // Shutdown gracefully shuts down the server
@@ -523,12 +962,37 @@ func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
-	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+	status := map[string]interface{}{
 		"status":        "running",
 		"version":       "0.1.0",
 		"llm_providers": s.llmRegistry.List(),
 		"runner":        s.cfg.Runner.Executor,
-	})
+		// So clients can turn off what won't work rather than fail each request
+		"capabilities": s.capabilities(r.Context()),
+	}
+	// Editors poll this to learn when packs changed
+	if s.exerciseLoader != nil {
+		if version, err := s.exerciseLoader.IndexVersion(); err == nil {
+			status["exercise_index"] = version
+		}
+	}
+	// And this to tell the user a pack update is ready to apply
+	if s.packUpdates != nil {
+		if updates, err := s.packUpdates.Pending(); err == nil {
+			status["pack_updates"] = updates
+		} else {
+			slog.Warn("read pack updates", "error", err)
+		}
+	}
+	// And this to warn when a provider is near or past its quota
+	if s.quota != nil {
+		if quotas, err := s.quota.Statuses(); err == nil {
+			status["quotas"] = quotas
+		} else {
+			slog.Warn("read provider quotas", "error", err)
+		}
+	}
+	s.jsonResponse(w, http.StatusOK, status)
 }
 
 func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
@@ -538,6 +1002,10 @@ func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
 		"learning_contract": s.cfg.Learning,
 		"runner":            s.cfg.Runner,
 		"default_provider":  s.cfg.LLM.DefaultProvider,
+		"locale":            s.locale,
+		"telemetry": map[string]interface{}{
+			"edit_events": s.editLog != nil,
+		},
 	})
 }
 
@@ -558,122 +1026,182 @@ func (s *Server) handleListProviders(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleListExercises(w http.ResponseWriter, r *http.Request) {
-	packs, err := s.exerciseLoader.LoadAllPacks()
+	params, err := parseListParams(r.URL.Query(), packListFields, []string{"language"}, "")
 	if err != nil {
-		s.jsonError(w, http.StatusInternalServerError, "failed to load exercises", err)
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error(), nil)
 		return
 	}
 
-	result := make([]map[string]interface{}, 0, len(packs))
-	for _, pack := range packs {
-		exercises := make([]map[string]interface{}, 0, len(pack.ExerciseIDs))
-		if packExercises, err := s.exerciseLoader.LoadPackExercises(pack.ID); err == nil {
-			for _, ex := range packExercises {
-				exercises = append(exercises, map[string]interface{}{
-					"id":         ex.ID,
-					"title":      ex.Title,
-					"difficulty": ex.Difficulty,
-				})
+	s.serveExerciseContent(w, r, func() (any, bool) {
+		packs, err := s.exerciseLoader.LoadAllPacks()
+		if err != nil {
+			s.jsonError(w, http.StatusInternalServerError, "failed to load exercises", err)
+			return nil, false
+		}
+		packs, total := applyList(packs, params, packListFields)
+
+		result := make([]map[string]interface{}, 0, len(packs))
+		for _, pack := range packs {
+			exercises := make([]map[string]interface{}, 0, len(pack.ExerciseIDs))
+			if packExercises, err := s.exerciseLoader.LoadPackExercises(pack.ID); err == nil {
+				for _, ex := range packExercises {
+					exercises = append(exercises, map[string]interface{}{
+						"id":         ex.ID,
+						"title":      ex.Title,
+						"difficulty": ex.Difficulty,
+					})
+				}
+			} else {
+				slog.Warn("failed to load pack exercises", "pack", pack.ID, "error", err)
 			}
-		} else {
-			slog.Warn("failed to load pack exercises", "pack", pack.ID, "error", err)
-		}
 
-		result = append(result, map[string]interface{}{
-			"id":             pack.ID,
-			"name":           pack.Name,
-			"description":    pack.Description,
-			"language":       pack.Language,
-			"exercise_count": len(pack.ExerciseIDs),
-			"exercises":      exercises,
-		})
-	}
+			result = append(result, map[string]interface{}{
+				"id":             pack.ID,
+				"name":           pack.Name,
+				"description":    pack.Description,
+				"language":       pack.Language,
+				"exercise_count": len(pack.ExerciseIDs),
+				"exercises":      exercises,
+			})
+		}
 
-	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
-		"packs": result,
+		resp := pageInfo(params, total)
+		resp["packs"] = result
+		return resp, true
 	})
 }
 
 func (s *Server) handleListPackExercises(w http.ResponseWriter, r *http.Request) {
-	packID := r.PathValue("pack")
+	packID := packParam(r)
 
-	exercises, err := s.exerciseLoader.LoadPackExercises(packID)
-	if err != nil {
-		s.jsonError(w, http.StatusNotFound, "pack not found", err)
-		return
-	}
+	s.serveExerciseContent(w, r, func() (any, bool) {
+		exercises, err := s.exerciseLoader.LoadPackExercises(packID)
+		if err != nil {
+			s.jsonError(w, http.StatusNotFound, "pack not found", err)
+			return nil, false
+		}
 
-	result := make([]map[string]interface{}, 0, len(exercises))
-	for _, ex := range exercises {
-		result = append(result, map[string]interface{}{
-			"id":         ex.ID,
-			"title":      ex.Title,
-			"difficulty": ex.Difficulty,
-		})
-	}
+		result := make([]map[string]interface{}, 0, len(exercises))
+		for _, ex := range exercises {
+			result = append(result, map[string]interface{}{
+				"id":         ex.ID,
+				"title":      ex.Title,
+				"difficulty": ex.Difficulty,
+			})
+		}
 
-	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
-		"pack_id":   packID,
-		"exercises": result,
+		return map[string]interface{}{
+			"pack_id":   packID,
+			"exercises": result,
+		}, true
 	})
 }
 
+// handleGetExercise serves an exercise by its ID; {pack} holds an org
+// pack's org, and {slug} the rest of its ID
 func (s *Server) handleGetExercise(w http.ResponseWriter, r *http.Request) {
-	packID := r.PathValue("pack")
-	slug := r.PathValue("slug")
+	id := r.PathValue("pack") + "/" + r.PathValue("slug")
+
+	s.serveExerciseContent(w, r, func() (any, bool) {
+		ex, err := s.exerciseLoader.LoadExerciseByID(id)
+		if err != nil {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeExerciseNotFound, "exercise not found", err)
+			return nil, false
+		}
+		return ex, true
+	})
+}
 
-	ex, err := s.exerciseLoader.LoadExercise(packID, slug)
+// sessionExercise loads the exercise a session works on, in the variant
+// it started on, or nil for sessions without one
+func (s *Server) sessionExercise(sess *session.Session) *domain.Exercise {
+	if sess.ExerciseID == "" {
+		return nil
+	}
+	ex, err := s.exerciseLoader.LoadExerciseByID(sess.ExerciseID)
 	if err != nil {
-		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeExerciseNotFound, "exercise not found", err)
-		return
+		return nil
 	}
-
-	s.jsonResponse(w, http.StatusOK, ex)
+	return ex.WithVariant(sess.ExerciseVariant())
 }
 
 // Session handlers
 
 func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		ExerciseID string            `json:"exercise_id,omitempty"` // For training intent
-		SpecPath   string            `json:"spec_path,omitempty"`   // For feature guidance or spec authoring intent
-		DocsPaths  []string          `json:"docs_paths,omitempty"`  // For spec authoring intent
-		Intent     string            `json:"intent,omitempty"`      // Explicit intent (optional)
-		Code       map[string]string `json:"code,omitempty"`        // Initial code (for greenfield/feature)
+		ExerciseID string            `json:"exercise_id,omitempty"`                                                                 // For training intent
+		SpecPath   string            `json:"spec_path,omitempty"`                                                                   // For feature guidance or spec authoring intent
+		SpecPaths  []string          `json:"spec_paths,omitempty"`                                                                  // Further spec files for spec authoring intent
+		DocsPaths  []string          `json:"docs_paths,omitempty"`                                                                  // For spec authoring intent
+		Intent     string            `json:"intent,omitempty" validate:"oneof=training greenfield feature_guidance spec_authoring"` // Explicit intent (optional)
+		Code       map[string]string `json:"code,omitempty"`                                                                        // Initial code (for greenfield/feature)
 		Track      string            `json:"track,omitempty"`
+		TestFirst  *bool             `json:"test_first,omitempty"`  // Hold back implementation hints until a failing test exists
+		HintBudget *int              `json:"hint_budget,omitempty"` // Hint tokens the session can spend; 0 for no budget
+
+		// Project the session is started in; its .temper.yaml is layered
+		// over the global config
+		WorkspaceRoot string `json:"workspace_root,omitempty"`
+
+		// Monorepo directory a feature guidance session works in
+		Scope string `json:"scope,omitempty"`
+
+		// Allowlisted variables, build tags and test flags for the
+		// session's runs, on top of the exercise's
+		BuildEnv domain.BuildEnv `json:"build_env,omitempty"`
+
+		// Start on the exercise as written, without adaptive difficulty
+		AsWritten bool `json:"as_written,omitempty"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+	if !s.decodeRequest(w, r, &req) {
 		return
 	}
 
+	if req.WorkspaceRoot != "" {
+		if !validWorkspaceRoot(req.WorkspaceRoot) {
+			s.validationError(w, &ValidationError{Fields: []FieldError{
+				{Field: "workspace_root", Message: "workspace root must be an absolute path to a directory"},
+			}})
+			return
+		}
+		req.WorkspaceRoot = filepath.Clean(req.WorkspaceRoot)
+		project, err := config.LoadProjectConfig(req.WorkspaceRoot)
+		if err != nil {
+			s.jsonError(w, http.StatusBadRequest, "invalid project config", err)
+			return
+		}
+		if project != nil && req.Track == "" {
+			req.Track = project.Track
+		}
+	}
+
 	// At least one of exercise_id or spec_path should be provided for non-greenfield
-	if req.ExerciseID == "" && req.SpecPath == "" && req.Intent != "greenfield" {
+	if req.ExerciseID == "" && req.SpecPath == "" && len(req.SpecPaths) == 0 && req.Intent != "greenfield" {
 		s.jsonError(w, http.StatusBadRequest, "exercise_id or spec_path is required", nil)
 		return
 	}
 
-	// Get learning policy from track store (SQLite) or config fallback
-	var policy *domain.LearningPolicy
-	if req.Track != "" {
-		// Try SQLite track store first
-		if s.trackStore != nil {
-			if track, err := s.trackStore.Get(req.Track); err == nil {
-				p := track.ToPolicy()
-				policy = &p
-			}
+	policy := s.trackPolicy(req.Track)
+	if req.TestFirst != nil {
+		if policy == nil {
+			p := domain.DefaultPolicy()
+			policy = &p
+		}
+		policy.TestFirst = *req.TestFirst
+	}
+	if req.HintBudget != nil {
+		if *req.HintBudget < 0 {
+			s.validationError(w, &ValidationError{Fields: []FieldError{
+				{Field: "hint_budget", Message: "hint budget must be non-negative"},
+			}})
+			return
 		}
-		// Fallback to config tracks
 		if policy == nil {
-			if track, ok := s.cfg.Learning.Tracks[req.Track]; ok {
-				policy = &domain.LearningPolicy{
-					MaxLevel:        domain.InterventionLevel(track.MaxLevel),
-					CooldownSeconds: track.CooldownSeconds,
-					Track:           req.Track,
-				}
-			}
+			p := domain.DefaultPolicy()
+			policy = &p
 		}
+		policy.Budget.Tokens = *req.HintBudget
 	}
 
 	// Map intent string to SessionIntent
@@ -694,29 +1222,99 @@ func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
 	sess, err := s.sessionService.Create(r.Context(), session.CreateRequest{
 		ExerciseID: req.ExerciseID,
 		SpecPath:   req.SpecPath,
+		SpecPaths:  req.SpecPaths,
 		DocsPaths:  req.DocsPaths,
 		Intent:     intent,
 		Code:       req.Code,
 		Policy:     policy,
+
+		WorkspaceRoot: req.WorkspaceRoot,
+		Scope:         req.Scope,
+		BuildEnv:      req.BuildEnv,
+		AsWritten:     req.AsWritten,
 	})
 	if err != nil {
-		if err == session.ErrExerciseNotFound {
-			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeExerciseNotFound, "exercise not found", err)
-			return
-		}
-		if err == session.ErrSpecRequired {
-			s.jsonError(w, http.StatusBadRequest, "spec_path required for feature_guidance intent", err)
-			return
-		}
-		if err == session.ErrSpecInvalid {
-			s.jsonError(w, http.StatusBadRequest, "spec validation failed", err)
-			return
+		s.createSessionError(w, err)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusCreated, sess)
+}
+
+// trackPolicy returns the learning policy of a track from the track store,
+// falling back to the configured tracks. Nil means the default policy.
+func (s *Server) trackPolicy(name string) *domain.LearningPolicy {
+	if name == "" {
+		return nil
+	}
+	// Try SQLite track store first
+	if s.trackStore != nil {
+		if track, err := s.trackStore.Get(name); err == nil {
+			p := track.ToPolicy()
+			return &p
 		}
+	}
+	// Fallback to config tracks
+	track, ok := s.cfg.Learning.Tracks[name]
+	if !ok {
+		return nil
+	}
+	return &domain.LearningPolicy{
+		MaxLevel:        domain.InterventionLevel(track.MaxLevel),
+		CooldownSeconds: track.CooldownSeconds,
+		Track:           name,
+		TestFirst:       track.TestFirst,
+		Stuck: domain.StuckPolicy{
+			Disabled:    track.Stuck.Disabled,
+			FailingRuns: track.Stuck.FailingRuns,
+			IdleSeconds: track.Stuck.IdleSeconds,
+		},
+		Budget: domain.HintBudget{
+			Tokens: track.Budget.Tokens,
+			Costs:  track.Budget.Costs,
+		},
+	}
+}
+
+// createSessionError maps a session creation error to its response
+func (s *Server) createSessionError(w http.ResponseWriter, err error) {
+	switch {
+	case err == session.ErrExerciseNotFound:
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeExerciseNotFound, "exercise not found", err)
+	case err == session.ErrSpecRequired:
+		s.jsonError(w, http.StatusBadRequest, "spec_path required for feature_guidance intent", err)
+	case err == session.ErrSpecInvalid:
+		s.jsonError(w, http.StatusBadRequest, "spec validation failed", err)
+	case errors.Is(err, session.ErrInvalidScope):
+		s.validationError(w, &ValidationError{Fields: []FieldError{{Field: "scope", Message: err.Error()}}})
+	case errors.Is(err, session.ErrInvalidBuildEnv):
+		s.validationError(w, &ValidationError{Fields: []FieldError{{Field: "build_env", Message: err.Error()}}})
+	case errors.Is(err, session.ErrInvalidDeadline):
+		s.validationError(w, &ValidationError{Fields: []FieldError{{Field: "deadline", Message: err.Error()}}})
+	default:
 		s.jsonError(w, http.StatusInternalServerError, "failed to create session", err)
+	}
+}
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	// Most recently active first unless the caller asks otherwise
+	params, err := parseListParams(r.URL.Query(), sessionListFields,
+		[]string{"status", "intent", "exercise_id", "spec_path"}, "-updated_at")
+	if err != nil {
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error(), nil)
 		return
 	}
 
-	s.jsonResponse(w, http.StatusCreated, sess)
+	sessions, err := s.sessionService.List(r.Context())
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to list sessions", err)
+		return
+	}
+	sessions, total := applyList(sessions, params, sessionListFields)
+
+	resp := pageInfo(params, total)
+	resp["sessions"] = sessions
+	s.jsonResponse(w, http.StatusOK, resp)
 }
 
 func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
@@ -732,7 +1330,11 @@ func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.jsonResponse(w, http.StatusOK, sess)
+	s.jsonResponse(w, http.StatusOK, struct {
+		*session.Session
+		Cooldown session.CooldownState `json:"cooldown"`
+		Budget   session.BudgetState   `json:"budget"`
+	}{sess, sess.Cooldown(), sess.Budget()})
 }
 
 func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
@@ -741,18 +1343,28 @@ func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
 	// Get session before deleting to generate summary
 	sess, err := s.sessionService.Get(r.Context(), id)
 	if err != nil {
-		if err == session.ErrSessionNotFound {
-			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+		if err != session.ErrSessionNotFound {
+			s.jsonError(w, http.StatusInternalServerError, "failed to get session", err)
 			return
 		}
-		s.jsonError(w, http.StatusInternalServerError, "failed to get session", err)
+		// Deleting a deleted session succeeds again, without a summary;
+		// only a session that never existed is 404
+		if err := s.sessionService.Delete(r.Context(), id); err != nil {
+			if err == session.ErrSessionNotFound {
+				s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+				return
+			}
+			s.jsonError(w, http.StatusInternalServerError, "failed to delete session", err)
+			return
+		}
+		s.jsonResponse(w, http.StatusOK, map[string]interface{}{"deleted": true})
 		return
 	}
 
 	// Get spec progress if this is a feature guidance session
 	var specProgress string
 	if sess.SpecPath != "" && s.specService != nil {
-		if progress, err := s.specService.GetProgress(r.Context(), sess.SpecPath); err == nil {
+		if progress, err := s.specsFor(sess).GetProgress(r.Context(), sess.SpecPath); err == nil {
 			specProgress = fmt.Sprintf("%.0f%%", progress.PercentComplete)
 		}
 	}
@@ -765,6 +1377,10 @@ func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
 
 	// Delete the session
 	if err := s.sessionService.Delete(r.Context(), id); err != nil {
+		if err == session.ErrSessionNotFound {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+			return
+		}
 		s.jsonError(w, http.StatusInternalServerError, "failed to delete session", err)
 		return
 	}
@@ -777,12 +1393,51 @@ func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
 	if summary != nil {
 		response["summary"] = summary
 	}
+	if s.editLog != nil {
+		if events, err := s.editLog.Events(id); err == nil && len(events) > 0 {
+			response["edits"] = editlog.Summarize(events)
+		}
+	}
 
 	s.jsonResponse(w, http.StatusOK, response)
 }
 
 // Run handlers
 
+// handleListRuns returns the session's runs oldest first, each with the
+// diff of its code against the run before it
+func (s *Server) handleListRuns(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	params, err := parseListParams(r.URL.Query(), runHistoryFields, []string{"status"}, "number")
+	if err != nil {
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error(), nil)
+		return
+	}
+
+	if _, err := s.sessionService.Get(r.Context(), id); err != nil {
+		if err == session.ErrSessionNotFound {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+			return
+		}
+		s.jsonError(w, http.StatusInternalServerError, "failed to get session", err)
+		return
+	}
+
+	runs, err := s.sessionService.GetRuns(r.Context(), id)
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to list runs", err)
+		return
+	}
+
+	// Diff before filtering so each run is compared with its real predecessor
+	history, total := applyList(session.RunHistory(runs), params, runHistoryFields)
+
+	resp := pageInfo(params, total)
+	resp["runs"] = history
+	s.jsonResponse(w, http.StatusOK, resp)
+}
+
 func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.PathValue("id")
 
@@ -815,12 +1470,28 @@ func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 		Format bool              `json:"format"`
 		Build  bool              `json:"build"`
 		Test   bool              `json:"test"`
+		Debug  bool              `json:"debug"`
+		// Explain adds beginner-friendly error explanations; defaults to
+		// runner.explain_errors
+		Explain *bool `json:"explain,omitempty"`
+		// Stdin runs the program once with this input after the tests
+		Stdin *string `json:"stdin,omitempty"`
+		// Command runs the project's custom command of this name instead
+		// of format, build and test
+		Command string `json:"command,omitempty"`
+		// Language overrides detection for runs without a session
+		Language string `json:"language,omitempty"`
 	}
 
 	if err := json.Unmarshal(bodyBytes, &req); err != nil {
 		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
 		return
 	}
+	if req.Stdin != nil && len(*req.Stdin) > runner.MaxStdinBytes {
+		s.jsonErrorCode(w, http.StatusRequestEntityTooLarge, ErrCodePayloadTooLarge,
+			fmt.Sprintf("stdin exceeds %d bytes", runner.MaxStdinBytes), nil)
+		return
+	}
 
 	if err := validateCodePayload(req.Code); err != nil {
 		if pe := asPayloadError(err); pe != nil {
@@ -831,6 +1502,18 @@ func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Custom commands come from the session's project
+	if req.Command != "" && sessionID == "" {
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeValidationFailed, "custom commands need a session started in a project", nil)
+		return
+	}
+
+	// Running new code saves it to the session, so in a shared session
+	// only the driver may send any
+	if req.Code != nil && !s.requireDriver(w, r, sessionID) {
+		return
+	}
+
 	// Wrap the response writer so successful responses can be cached for
 	// future replay under the same idempotency key.
 	if idemKey != "" && s.idempotency != nil {
@@ -845,11 +1528,19 @@ func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 
 	// If session ID is provided, use session service
 	if sessionID != "" {
+		explain := s.cfg != nil && s.cfg.Runner.ExplainErrors
+		if req.Explain != nil {
+			explain = *req.Explain
+		}
 		run, err := s.sessionService.RunCode(r.Context(), sessionID, session.RunRequest{
-			Code:   req.Code,
-			Format: req.Format,
-			Build:  req.Build,
-			Test:   req.Test,
+			Code:    req.Code,
+			Format:  req.Format,
+			Build:   req.Build,
+			Test:    req.Test,
+			Debug:   req.Debug,
+			Explain: explain,
+			Stdin:   req.Stdin,
+			Command: req.Command,
 		})
 		if err != nil {
 			if err == session.ErrSessionNotFound {
@@ -857,16 +1548,50 @@ func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			if err == session.ErrSessionNotActive {
-				s.jsonError(w, http.StatusBadRequest, "session is not active", nil)
+				s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeSessionNotActive, "session is not active", nil)
 				return
 			}
-			s.jsonError(w, http.StatusInternalServerError, "run failed", err)
-			return
-		}
-
-		// Build response with optional appreciation
-		response := map[string]interface{}{
-			"run": run,
+			if err == session.ErrExerciseOutdated {
+				s.jsonErrorCode(w, http.StatusConflict, ErrCodeExerciseOutdated,
+					"the exercise changed since this session started; migrate the session or pin the old version", nil)
+				return
+			}
+			if err == session.ErrDeadlinePassed {
+				s.jsonErrorCode(w, http.StatusForbidden, ErrCodeDeadlinePassed,
+					"the assignment's deadline has passed and it no longer accepts runs", nil)
+				return
+			}
+			if err == session.ErrStdinUnsupported {
+				s.jsonErrorCode(w, http.StatusUnprocessableEntity, ErrCodeUnprocessable,
+					"this runner cannot run programs with stdin", nil)
+				return
+			}
+			if errors.Is(err, session.ErrUnknownCommand) {
+				s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeValidationFailed,
+					"the session's project defines no command named "+req.Command, nil)
+				return
+			}
+			if errors.Is(err, session.ErrCommandNotAllowed) {
+				s.jsonErrorCode(w, http.StatusForbidden, ErrCodeForbidden,
+					"command is not approved: add it to runner.commands.allowlist in ~/.temper/config.yaml", err)
+				return
+			}
+			if err == session.ErrCommandUnsupported {
+				s.jsonErrorCode(w, http.StatusUnprocessableEntity, ErrCodeUnprocessable,
+					"this runner cannot run custom commands", nil)
+				return
+			}
+			s.jsonError(w, http.StatusInternalServerError, "run failed", err)
+			return
+		}
+		if req.Code != nil {
+			s.collab.wrote(sessionID, r.Header.Get(ClientHeader))
+		}
+		s.events.notify(sessionID)
+
+		// Build response with optional appreciation
+		response := map[string]interface{}{
+			"run": run,
 		}
 
 		// Check for appreciation moments after successful run
@@ -898,7 +1623,10 @@ func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Standalone run (no session)
-	ctx := r.Context()
+	ctx, ok := s.withCodeLanguage(w, r.Context(), req.Language, req.Code)
+	if !ok {
+		return
+	}
 	result := make(map[string]interface{})
 
 	if req.Format {
@@ -919,12 +1647,18 @@ func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 			s.jsonError(w, http.StatusInternalServerError, "build check failed", err)
 			return
 		}
-		result["build"] = map[string]interface{}{
+		build := map[string]interface{}{
 			"ok":     buildResult.OK,
 			"output": buildResult.Output,
 		}
+		result["build"] = build
 
 		if !buildResult.OK {
+			if lang := runner.LanguageFromContext(ctx); lang == "" || lang == runner.LanguageGo {
+				if fixes := runner.QuickFixes(buildResult.Output, req.Code); len(fixes) > 0 {
+					build["quick_fixes"] = fixes
+				}
+			}
 			s.jsonResponse(w, http.StatusOK, result)
 			return
 		}
@@ -943,29 +1677,146 @@ func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if req.Stdin != nil {
+		prog, ok := s.runnerExecutor.(runner.ProgramRunner)
+		if !ok {
+			s.jsonErrorCode(w, http.StatusUnprocessableEntity, ErrCodeUnprocessable,
+				"this runner cannot run programs with stdin", nil)
+			return
+		}
+		programResult, err := prog.RunProgram(ctx, req.Code, *req.Stdin)
+		if err != nil {
+			s.jsonError(w, http.StatusInternalServerError, "program run failed", err)
+			return
+		}
+		result["program"] = map[string]interface{}{
+			"output":    programResult.Output,
+			"exit_code": programResult.ExitCode,
+			"timed_out": programResult.TimedOut,
+		}
+	}
+
 	s.jsonResponse(w, http.StatusOK, result)
 }
 
 func (s *Server) handleFormat(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Code map[string]string `json:"code"`
+		Code      map[string]string `json:"code"`
+		Language  string            `json:"language,omitempty"`  // detected from the files if empty
+		Formatter string            `json:"formatter,omitempty"` // Go only; the session's pack decides if empty
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+	ctx, ok := s.withCodeLanguage(w, r.Context(), req.Language, req.Code)
+	if !ok {
+		return
+	}
+	if req.Formatter == "" {
+		req.Formatter = s.packFormatter(ctx, r.PathValue("id"))
+	}
+	formatter, err := runner.ParseFormatter(req.Formatter)
+	if err != nil {
+		s.jsonError(w, http.StatusBadRequest, err.Error(), err)
 		return
 	}
+	ctx = runner.WithFormatter(ctx, formatter)
 
-	formatted, err := s.runnerExecutor.RunFormatFix(r.Context(), req.Code)
+	formatted, err := s.runnerExecutor.RunFormatFix(ctx, req.Code)
 	if err != nil {
 		s.jsonError(w, http.StatusInternalServerError, "format failed", err)
 		return
 	}
 
-	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+	resp := map[string]interface{}{
 		"ok":        true,
 		"formatted": formatted,
-	})
+		"language":  runner.LanguageFromContext(ctx),
+	}
+	// Which imports the formatter added or removed, so a learner sees
+	// why a missing import stopped failing the build
+	if lang := runner.LanguageFromContext(ctx); lang == "" || lang == runner.LanguageGo {
+		resp["formatter"] = formatter
+		if changes := runner.ImportChanges(req.Code, formatted); len(changes) > 0 {
+			resp["imports"] = changes
+		}
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
+
+// packFormatter returns the formatter the session's exercise pack asks
+// for, or "" when the session has no pack or can't be found
+func (s *Server) packFormatter(ctx context.Context, sessionID string) string {
+	if s.exerciseLoader == nil {
+		return ""
+	}
+	sess, err := s.sessionService.Get(ctx, sessionID)
+	if err != nil || sess.ExerciseID == "" {
+		return ""
+	}
+	packID, _, ok := domain.SplitExerciseID(sess.ExerciseID)
+	if !ok {
+		return ""
+	}
+	pack, err := s.exerciseLoader.LoadPack(packID)
+	if err != nil {
+		return ""
+	}
+	return pack.Formatter
+}
+
+// withCodeLanguage asks the runner to treat code as lang, or as the
+// language most of its files are in when lang is empty. An unsupported
+// lang is a bad request.
+func (s *Server) withCodeLanguage(w http.ResponseWriter, ctx context.Context, lang string, code map[string]string) (context.Context, bool) {
+	if lang == "" {
+		return runner.WithLanguage(ctx, runner.PrimaryLanguage(code)), true
+	}
+	parsed, err := runner.ParseLanguage(lang)
+	if err != nil {
+		s.jsonError(w, http.StatusBadRequest, err.Error(), err)
+		return ctx, false
+	}
+	return runner.WithLanguage(ctx, parsed), true
+}
+
+// handleRootCause accepts the learner's structured root-cause answer for a
+// debugging exercise. Completion also requires green tests on the latest run.
+func (s *Server) handleRootCause(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("id")
+
+	var req struct {
+		Answers []domain.RootCauseAnswer `json:"answers" validate:"required"`
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+	for _, a := range req.Answers {
+		if strings.TrimSpace(a.File) == "" || strings.TrimSpace(a.Explanation) == "" {
+			s.jsonError(w, http.StatusBadRequest, "each answer needs a file and an explanation", nil)
+			return
+		}
+	}
+
+	result, err := s.sessionService.SubmitRootCause(r.Context(), sessionID, req.Answers)
+	if err != nil {
+		switch err {
+		case session.ErrSessionNotFound:
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+		case session.ErrSessionNotActive:
+			s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeSessionNotActive, "session is not active", nil)
+		case session.ErrNotDebugging:
+			s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeWrongSessionKind, "session is not a debugging exercise", nil)
+		case session.ErrExerciseNotFound:
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeExerciseNotFound, "exercise not found", nil)
+		default:
+			s.jsonError(w, http.StatusInternalServerError, "failed to evaluate root cause", err)
+		}
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, result)
 }
 
 // Pairing handlers
@@ -1014,28 +1865,17 @@ func (s *Server) handlePairingWithEscalation(w http.ResponseWriter, r *http.Requ
 		Context       string            `json:"context,omitempty"`
 		RunID         string            `json:"run_id,omitempty"`
 		Stream        bool              `json:"stream,omitempty"`
-		Level         int               `json:"level"`         // Required: 4 or 5
-		Justification string            `json:"justification"` // Required: why escalation is needed
+		Level         int               `json:"level"`                                    // 4 or 5
+		Justification string            `json:"justification" validate:"required,min=20"` // why escalation is needed
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+	if !s.decodeRequest(w, r, &req) {
 		return
 	}
-
-	// Validate escalation request
 	if req.Level != 4 && req.Level != 5 {
-		s.jsonError(w, http.StatusBadRequest, "escalation requires level 4 or 5", nil)
-		return
-	}
-
-	if strings.TrimSpace(req.Justification) == "" {
-		s.jsonError(w, http.StatusBadRequest, "justification required for escalation", nil)
-		return
-	}
-
-	if len(req.Justification) < 20 {
-		s.jsonError(w, http.StatusBadRequest, "please provide a more detailed justification (at least 20 characters)", nil)
+		s.validationError(w, &ValidationError{Fields: []FieldError{
+			{Field: "level", Message: "escalation requires level 4 or 5"},
+		}})
 		return
 	}
 
@@ -1051,39 +1891,34 @@ func (s *Server) handlePairingWithEscalation(w http.ResponseWriter, r *http.Requ
 	}
 
 	if sess.Status != session.StatusActive {
-		s.jsonError(w, http.StatusBadRequest, "session is not active", nil)
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeSessionNotActive, "session is not active", nil)
 		return
 	}
 
 	// Check if user has made sufficient attempts before allowing escalation
-	if sess.HintCount < 2 {
-		s.jsonError(w, http.StatusBadRequest, "please try at least 2 hints before requesting escalation", nil)
+	if sess.HintCount < escalationMinHints {
+		s.jsonError(w, http.StatusBadRequest, fmt.Sprintf("please try at least %d hints before requesting escalation", escalationMinHints), nil)
 		return
 	}
 
 	// Check cooldown for high-level interventions
-	if !sess.CanRequestIntervention(domain.L4PartialSolution) {
+	dryRun := s.dryRun(r)
+	if !dryRun && !sess.CanRequestIntervention(domain.L4PartialSolution) {
 		remaining := sess.CooldownRemaining()
-		s.jsonResponse(w, http.StatusTooManyRequests, map[string]interface{}{
-			"error":              "cooldown active",
-			"cooldown_remaining": remaining.Seconds(),
-			"message":            fmt.Sprintf("Please wait %.0f seconds before requesting escalation", remaining.Seconds()),
-		})
+		s.jsonCooldown(w, pairing.CooldownRationale(sess.Policy, domain.L4PartialSolution, remaining),
+			fmt.Sprintf("Please wait %.0f seconds before requesting escalation", remaining.Seconds()))
 		return
 	}
 
 	// Load exercise for context
-	var ex *domain.Exercise
-	parts := strings.SplitN(sess.ExerciseID, "/", 2)
-	if len(parts) >= 2 {
-		ex, _ = s.exerciseLoader.LoadExercise(parts[0], parts[1])
-	}
+	ex := s.sessionExercise(sess)
 
-	// Use provided code or session's code
+	// Use provided code or session's code, limited to the session's scope
 	code := sess.Code
 	if len(req.Code) > 0 {
 		code = req.Code
 	}
+	code = session.ScopeCode(code, sess.Scope)
 
 	// Create escalation policy that allows higher levels
 	escalationPolicy := sess.Policy
@@ -1093,7 +1928,9 @@ func (s *Server) handlePairingWithEscalation(w http.ResponseWriter, r *http.Requ
 	pairingCtx := pairing.InterventionContext{
 		Exercise: ex,
 		Code:     code,
+		Language: sess.Language,
 	}
+	s.attachFeatureContext(r.Context(), sess, &pairingCtx)
 
 	// Build intervention request with escalation
 	pairingReq := pairing.InterventionRequest{
@@ -1104,7 +1941,9 @@ func (s *Server) handlePairingWithEscalation(w http.ResponseWriter, r *http.Requ
 		Policy:        escalationPolicy,
 		ExplicitLevel: domain.InterventionLevel(req.Level),
 		Justification: req.Justification,
+		BudgetSpent:   sess.BudgetSpent,
 	}
+	s.routeToProject(sess, &pairingReq)
 
 	if req.RunID != "" {
 		runUUID, err := uuid.Parse(req.RunID)
@@ -1115,6 +1954,11 @@ func (s *Server) handlePairingWithEscalation(w http.ResponseWriter, r *http.Requ
 		pairingReq.RunID = &runUUID
 	}
 
+	if dryRun {
+		s.respondPromptPreview(w, r, pairingReq)
+		return
+	}
+
 	// Log the escalation request
 	slog.Info("explicit escalation requested",
 		"session_id", sessionID,
@@ -1129,46 +1973,71 @@ func (s *Server) handlePairingWithEscalation(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Non-streaming: generate intervention
-	intervention, err := s.pairingService.Intervene(r.Context(), pairingReq)
-	if err != nil {
-		slog.Error("escalation intervention failed", "error", err)
-		s.jsonError(w, http.StatusInternalServerError, "failed to generate escalation response", err)
-		return
-	}
+	// Non-streaming: generate intervention, shared with identical
+	// escalations that arrive meanwhile (see pair)
+	outcome, shared, err := s.pairingFlights.do(r.Context(), pairingKey(pairingReq), func(ctx context.Context) (*pairingOutcome, error) {
+		intervention, err := s.pairingService.Intervene(ctx, pairingReq)
+		if err != nil {
+			return nil, err
+		}
 
-	// Record intervention in session
-	sessionIntervention := &session.Intervention{
-		ID:        intervention.ID.String(),
-		SessionID: sess.ID,
-		Intent:    intervention.Intent,
-		Level:     intervention.Level,
-		Type:      intervention.Type,
-		Content:   intervention.Content,
-		CreatedAt: time.Now(),
-	}
-	if req.RunID != "" {
-		sessionIntervention.RunID = &req.RunID
-	}
+		// Record intervention in session
+		sessionIntervention := &session.Intervention{
+			ID:        intervention.ID.String(),
+			SessionID: sess.ID,
+			Intent:    intervention.Intent,
+			Level:     intervention.Level,
+			Type:      intervention.Type,
+			Content:   intervention.Content,
+			CreatedAt: time.Now(),
 
-	if err := s.sessionService.RecordIntervention(r.Context(), sessionIntervention); err != nil {
-		slog.Warn("failed to record escalation", "error", err)
-	}
+			Redactions: intervention.Redactions,
+			Concepts:   intervention.Concepts,
+		}
+		if req.RunID != "" {
+			sessionIntervention.RunID = &req.RunID
+		}
 
-	// Extract patches from L4/L5 interventions
-	var hasPatch bool
-	if intervention.Level >= domain.L4PartialSolution {
-		patches := s.patchService.ExtractFromIntervention(intervention, uuid.MustParse(sess.ID), sess.Code)
-		hasPatch = len(patches) > 0
-		if hasPatch {
-			slog.Info("patches extracted from escalation",
-				"session_id", sess.ID,
-				"patch_count", len(patches),
-			)
+		// Nobody is left to receive the answer, so it mustn't count
+		// against the cooldown or the hint budget
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := s.sessionService.RecordIntervention(ctx, sessionIntervention); err != nil {
+			slog.Warn("failed to record escalation", "error", err)
+		} else {
+			s.events.intervention(*sessionIntervention)
+		}
+
+		// Extract patches from L4/L5 interventions
+		var hasPatch bool
+		if intervention.Level >= domain.L4PartialSolution {
+			patches := s.patchService.ExtractFromIntervention(intervention, uuid.MustParse(sess.ID), sess.Code)
+			hasPatch = len(patches) > 0
+			if hasPatch {
+				slog.Info("patches extracted from escalation",
+					"session_id", sess.ID,
+					"patch_count", len(patches),
+				)
+			}
 		}
+		diffs := suggestionDiffs(intervention, uuid.MustParse(sess.ID), code)
+		return &pairingOutcome{intervention: intervention, hasPatch: hasPatch, diffs: diffs}, nil
+	})
+	if r.Context().Err() != nil {
+		return // the client hung up
+	}
+	if err != nil {
+		slog.Error("escalation intervention failed", "error", err)
+		s.pairingError(w, "failed to generate escalation response", err)
+		return
 	}
+	if shared {
+		s.countSharedPairing(domain.IntentStuck)
+	}
+	intervention, hasPatch := outcome.intervention, outcome.hasPatch
 
-	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+	resp := map[string]interface{}{
 		"id":            intervention.ID.String(),
 		"intent":        intervention.Intent,
 		"level":         intervention.Level,
@@ -1177,11 +2046,30 @@ func (s *Server) handlePairingWithEscalation(w http.ResponseWriter, r *http.Requ
 		"escalated":     true,
 		"justification": req.Justification,
 		"has_patch":     hasPatch,
-	})
+		"redactions":    intervention.Redactions,
+		"concepts":      intervention.Concepts,
+		"rationale":     intervention.Contract,
+	}
+	if len(outcome.diffs) > 0 {
+		resp["diffs"] = outcome.diffs
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
 }
 
 // handlePairing is the common handler for all pairing endpoints
 func (s *Server) handlePairing(w http.ResponseWriter, r *http.Request, intent domain.Intent) {
+	var req pairingRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+	s.pair(w, r, intent, req, nil)
+}
+
+// pair generates, records and returns an intervention for a decoded
+// request. prepare, when set, adds to the pairing context once the code is
+// known; it writes the response and returns false to stop.
+func (s *Server) pair(w http.ResponseWriter, r *http.Request, intent domain.Intent, req pairingRequest,
+	prepare func(*pairing.InterventionContext) bool) {
 	sessionID := r.PathValue("id")
 
 	if s.metrics != nil {
@@ -1190,15 +2078,6 @@ func (s *Server) handlePairing(w http.ResponseWriter, r *http.Request, intent do
 			Inc(map[string]string{"intent": string(intent)})
 	}
 
-	// Parse request
-	var req pairingRequest
-	if r.Body != nil && r.ContentLength > 0 {
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
-			return
-		}
-	}
-
 	// Get session
 	sess, err := s.sessionService.Get(r.Context(), sessionID)
 	if err != nil {
@@ -1211,48 +2090,62 @@ func (s *Server) handlePairing(w http.ResponseWriter, r *http.Request, intent do
 	}
 
 	if sess.Status != session.StatusActive {
-		s.jsonError(w, http.StatusBadRequest, "session is not active", nil)
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeSessionNotActive, "session is not active", nil)
 		return
 	}
 
-	// Check cooldown for L3+ interventions
-	if !sess.CanRequestIntervention(domain.L3ConstrainedSnippet) {
+	asSARIF, ok := s.wantsSARIF(w, r, intent, req.Stream)
+	if !ok {
+		return
+	}
+
+	// Check cooldown for L3+ interventions. A dry run delivers nothing,
+	// so it isn't held back.
+	dryRun := s.dryRun(r)
+	if !dryRun && !sess.CanRequestIntervention(domain.L3ConstrainedSnippet) {
 		remaining := sess.CooldownRemaining()
-		s.jsonResponse(w, http.StatusTooManyRequests, map[string]interface{}{
-			"error":              "cooldown active",
-			"cooldown_remaining": remaining.Seconds(),
-			"message":            fmt.Sprintf("Please wait %.0f seconds before requesting more detailed help", remaining.Seconds()),
-		})
+		s.jsonCooldown(w, pairing.CooldownRationale(sess.Policy, domain.L3ConstrainedSnippet, remaining),
+			fmt.Sprintf("Please wait %.0f seconds before requesting more detailed help", remaining.Seconds()))
 		return
 	}
 
 	// Load exercise for context
-	var ex *domain.Exercise
-	parts := strings.SplitN(sess.ExerciseID, "/", 2)
-	if len(parts) >= 2 {
-		ex, _ = s.exerciseLoader.LoadExercise(parts[0], parts[1])
-	}
+	ex := s.sessionExercise(sess)
 
-	// Use provided code or session's code
+	// Use provided code or session's code, limited to the session's scope
 	code := sess.Code
 	if len(req.Code) > 0 {
 		code = req.Code
 	}
+	code = session.ScopeCode(code, sess.Scope)
 
 	// Build intervention context
 	pairingCtx := pairing.InterventionContext{
 		Exercise: ex,
 		Code:     code,
+		Language: sess.Language,
+	}
+	s.attachFeatureContext(r.Context(), sess, &pairingCtx)
+	if prepare != nil && !prepare(&pairingCtx) {
+		return
+	}
+
+	// Stuck and hint prompts get the debugger's view of the last failure
+	// instead of guessing from stdout alone
+	if intent == domain.IntentStuck || intent == domain.IntentHint {
+		pairingCtx.RunOutput = s.latestDebugOutput(r.Context(), sess.ID, req.RunID)
 	}
 
 	// Build intervention request
 	pairingReq := pairing.InterventionRequest{
-		SessionID: uuid.MustParse(sess.ID),
-		UserID:    uuid.Nil, // Local daemon - no user auth
-		Intent:    intent,
-		Context:   pairingCtx,
-		Policy:    sess.Policy,
+		SessionID:   uuid.MustParse(sess.ID),
+		UserID:      uuid.Nil, // Local daemon - no user auth
+		Intent:      intent,
+		Context:     pairingCtx,
+		Policy:      sess.Policy,
+		BudgetSpent: sess.BudgetSpent,
 	}
+	s.routeToProject(sess, &pairingReq)
 
 	if req.RunID != "" {
 		runUUID, err := uuid.Parse(req.RunID)
@@ -1263,59 +2156,190 @@ func (s *Server) handlePairing(w http.ResponseWriter, r *http.Request, intent do
 		pairingReq.RunID = &runUUID
 	}
 
+	if dryRun {
+		s.respondPromptPreview(w, r, pairingReq)
+		return
+	}
+
 	// Handle streaming vs non-streaming
 	if req.Stream {
 		s.handlePairingStream(w, r, pairingReq, sess)
 		return
 	}
 
-	// Non-streaming: generate intervention
-	intervention, err := s.pairingService.Intervene(r.Context(), pairingReq)
+	// Non-streaming: generate intervention. Identical requests that
+	// arrive meanwhile share this call and its recorded intervention
+	// rather than asking the LLM again. The call runs on while any of
+	// them still waits, and is cancelled when the last one hangs up.
+	outcome, shared, err := s.pairingFlights.do(r.Context(), pairingKey(pairingReq), func(ctx context.Context) (*pairingOutcome, error) {
+		intervention, err := s.pairingService.Intervene(ctx, pairingReq)
+		if err != nil {
+			return nil, err
+		}
+
+		// Record intervention in session
+		sessionIntervention := &session.Intervention{
+			ID:        intervention.ID.String(),
+			SessionID: sess.ID,
+			Intent:    intervention.Intent,
+			Level:     intervention.Level,
+			Type:      intervention.Type,
+			Content:   intervention.Content,
+			CreatedAt: time.Now(),
+
+			Redactions: intervention.Redactions,
+			Concepts:   intervention.Concepts,
+		}
+		if req.RunID != "" {
+			sessionIntervention.RunID = &req.RunID
+		}
+
+		// Nobody is left to receive the answer, so it mustn't count
+		// against the cooldown or the hint budget
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := s.sessionService.RecordIntervention(ctx, sessionIntervention); err != nil {
+			slog.Warn("failed to record intervention", "error", err)
+		} else {
+			s.events.intervention(*sessionIntervention)
+		}
+
+		// Extract patches from L4/L5 interventions
+		var hasPatch bool
+		if intervention.Level >= domain.L4PartialSolution {
+			patches := s.patchService.ExtractFromIntervention(intervention, uuid.MustParse(sess.ID), sess.Code)
+			hasPatch = len(patches) > 0
+			if hasPatch {
+				slog.Info("patches extracted from intervention",
+					"session_id", sess.ID,
+					"patch_count", len(patches),
+				)
+			}
+		}
+		diffs := suggestionDiffs(intervention, uuid.MustParse(sess.ID), code)
+		return &pairingOutcome{intervention: intervention, hasPatch: hasPatch, diffs: diffs}, nil
+	})
+	if r.Context().Err() != nil {
+		return // the client hung up
+	}
 	if err != nil {
 		slog.Error("intervention failed", "error", err)
-		s.jsonError(w, http.StatusInternalServerError, "failed to generate intervention", err)
+		s.pairingError(w, "failed to generate intervention", err)
 		return
 	}
+	if shared {
+		s.countSharedPairing(intent)
+	}
+	intervention, hasPatch := outcome.intervention, outcome.hasPatch
 
-	// Record intervention in session
-	sessionIntervention := &session.Intervention{
-		ID:        intervention.ID.String(),
-		SessionID: sess.ID,
-		Intent:    intervention.Intent,
-		Level:     intervention.Level,
-		Type:      intervention.Type,
-		Content:   intervention.Content,
-		CreatedAt: time.Now(),
+	if asSARIF {
+		s.jsonResponse(w, http.StatusOK, reviewSARIF(intervention, code))
+		return
 	}
-	if req.RunID != "" {
-		sessionIntervention.RunID = &req.RunID
+
+	resp := map[string]interface{}{
+		"id":         intervention.ID.String(),
+		"intent":     intervention.Intent,
+		"level":      intervention.Level,
+		"type":       intervention.Type,
+		"content":    intervention.Content,
+		"has_patch":  hasPatch,
+		"redactions": intervention.Redactions,
+		"concepts":   intervention.Concepts,
+		"rationale":  intervention.Contract,
+	}
+	// Code suggestions as diffs against what was submitted
+	if len(outcome.diffs) > 0 {
+		resp["diffs"] = outcome.diffs
 	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
 
-	if err := s.sessionService.RecordIntervention(r.Context(), sessionIntervention); err != nil {
-		slog.Warn("failed to record intervention", "error", err)
+// countSharedPairing counts a request answered by another's pairing call
+func (s *Server) countSharedPairing(intent domain.Intent) {
+	if s.metrics != nil {
+		s.metrics.Counter("pairing_deduplicated_total",
+			"Pairing requests answered by an identical request's LLM call, by intent.").
+			Inc(map[string]string{"intent": string(intent)})
 	}
+}
 
-	// Extract patches from L4/L5 interventions
-	var hasPatch bool
-	if intervention.Level >= domain.L4PartialSolution {
-		patches := s.patchService.ExtractFromIntervention(intervention, uuid.MustParse(sess.ID), sess.Code)
-		hasPatch = len(patches) > 0
-		if hasPatch {
-			slog.Info("patches extracted from intervention",
-				"session_id", sess.ID,
-				"patch_count", len(patches),
-			)
+// attachFeatureContext adds the spec, focus criterion and failing tests of
+// a feature guidance session to the pairing context. A spec that no longer
+// loads leaves the context as it was.
+func (s *Server) attachFeatureContext(ctx context.Context, sess *session.Session, pairingCtx *pairing.InterventionContext) {
+	if sess.Intent != session.IntentFeatureGuidance || sess.SpecPath == "" || s.specService == nil {
+		return
+	}
+	spec, err := s.specsFor(sess).Load(ctx, sess.SpecPath)
+	if err != nil {
+		return
+	}
+	pairingCtx.SessionIntent = sess.Intent
+	pairingCtx.Spec = spec
+	pairingCtx.FocusCriterion = pairingCtx.GetNextUnsatisfiedCriterion()
+
+	runs, err := s.sessionService.GetRuns(ctx, sess.ID)
+	if err != nil {
+		return
+	}
+	for _, run := range runs {
+		if run.Result != nil {
+			pairingCtx.FailingTests = append(pairingCtx.FailingTests, run.Result.FailedTests()...)
 		}
 	}
+}
 
-	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
-		"id":        intervention.ID.String(),
-		"intent":    intervention.Intent,
-		"level":     intervention.Level,
-		"type":      intervention.Type,
-		"content":   intervention.Content,
-		"has_patch": hasPatch,
-	})
+// latestDebugOutput returns the run output of the given run, or of the most
+// recent run, when that run captured a debugger snapshot or a fuzz crash.
+// Returns nil otherwise so other runs leave the prompt unchanged.
+func (s *Server) latestDebugOutput(ctx context.Context, sessionID, runID string) *domain.RunOutput {
+	runs, err := s.sessionService.GetRuns(ctx, sessionID)
+	if err != nil {
+		return nil
+	}
+	var target *session.Run
+	for _, run := range runs {
+		if runID != "" {
+			if run.ID == runID {
+				target = run
+				break
+			}
+			continue
+		}
+		if target == nil || run.CreatedAt.After(target.CreatedAt) {
+			target = run
+		}
+	}
+	if target == nil || target.Result == nil {
+		return nil
+	}
+	var crash *domain.FuzzCrash
+	if target.Result.Fuzz != nil {
+		crash = target.Result.Fuzz.Crash
+	}
+	if target.Result.Debug == nil && crash == nil {
+		return nil
+	}
+	output := &domain.RunOutput{
+		FormatOK:    target.Result.FormatOK,
+		BuildOK:     target.Result.BuildOK,
+		BuildOutput: target.Result.BuildOutput,
+		TestOK:      target.Result.TestOK,
+		TestOutput:  target.Result.TestOutput,
+		TestResults: target.Result.Tests,
+		Debug:       target.Result.Debug,
+		FuzzCrash:   crash,
+	}
+	for _, t := range target.Result.Tests {
+		if t.Passed {
+			output.TestsPassed++
+		} else {
+			output.TestsFailed++
+		}
+	}
+	return output
 }
 
 // handlePairingStream handles streaming intervention responses via SSE
@@ -1342,14 +2366,27 @@ func (s *Server) handlePairingStream(w http.ResponseWriter, r *http.Request, req
 	var contentBuilder strings.Builder
 	var level domain.InterventionLevel
 	var interventionType domain.InterventionType
+	var redactions []domain.Redaction
 
 	for chunk := range stream {
+		if r.Context().Err() != nil {
+			// The editor disconnected: nothing was delivered, so nothing
+			// is recorded. The pairing service stops the LLM stream.
+			return
+		}
 		switch chunk.Type {
 		case "metadata":
 			if chunk.Metadata != nil {
 				level = chunk.Metadata.Level
 				interventionType = chunk.Metadata.Type
-				writeSSEEvent(w, "metadata", fmt.Sprintf("{\"level\":%d,\"type\":\"%s\"}", level, interventionType))
+				redactions = chunk.Metadata.Redactions
+				metadata, _ := json.Marshal(map[string]interface{}{
+					"level":      level,
+					"type":       interventionType,
+					"redactions": redactions,
+					"rationale":  chunk.Metadata.Contract,
+				})
+				writeSSEEvent(w, "metadata", string(metadata))
 			}
 		case "content":
 			contentBuilder.WriteString(chunk.Content)
@@ -1366,12 +2403,26 @@ func (s *Server) handlePairingStream(w http.ResponseWriter, r *http.Request, req
 				Type:      interventionType,
 				Content:   contentBuilder.String(),
 				CreatedAt: time.Now(),
+
+				Redactions: redactions,
+				Concepts:   chunk.Concepts,
 			}
 			if err := s.sessionService.RecordIntervention(r.Context(), intervention); err != nil {
 				slog.Warn("failed to record intervention", "error", err)
+			} else {
+				s.events.intervention(*intervention)
 			}
 
-			writeSSEEvent(w, "done", fmt.Sprintf("{\"id\":\"%s\"}", intervention.ID))
+			doneEvent := map[string]interface{}{
+				"id":       intervention.ID,
+				"concepts": chunk.Concepts,
+			}
+			suggested := &domain.Intervention{Intent: req.Intent, Level: level, Content: intervention.Content}
+			if diffs := suggestionDiffs(suggested, req.SessionID, req.Context.Code); len(diffs) > 0 {
+				doneEvent["diffs"] = diffs
+			}
+			done, _ := json.Marshal(doneEvent)
+			writeSSEEvent(w, "done", string(done))
 		}
 		flusher.Flush()
 	}
@@ -1379,6 +2430,30 @@ func (s *Server) handlePairingStream(w http.ResponseWriter, r *http.Request, req
 
 // Helper methods
 
+// pairingError writes the response for a failed pairing service call.
+// A local-only session with no local provider is a setup problem the
+// user can fix, so it gets its own message instead of a generic 500, and
+// a provider timeout is a 504 the editor can offer to retry.
+func (s *Server) pairingError(w http.ResponseWriter, message string, err error) {
+	if errors.Is(err, pairing.ErrNoLocalProvider) {
+		s.jsonErrorCode(w, http.StatusServiceUnavailable, ErrCodeLLMUnavailable, pairing.ErrNoLocalProvider.Error(), err)
+		return
+	}
+	if errors.Is(err, llm.ErrTimeout) {
+		s.jsonErrorCode(w, http.StatusGatewayTimeout, ErrCodeLLMTimeout, "the LLM provider did not answer in time; try again", err)
+		return
+	}
+	if errors.Is(err, quota.ErrExhausted) {
+		s.jsonErrorCode(w, http.StatusTooManyRequests, ErrCodeQuotaExhausted, "the LLM provider reached its monthly hard cap", err)
+		return
+	}
+	if errors.Is(err, pairing.ErrHintBudgetExhausted) {
+		s.jsonErrorCode(w, http.StatusForbidden, ErrCodeHintBudgetExhausted, "this session's hint budget is spent", err)
+		return
+	}
+	s.jsonError(w, http.StatusInternalServerError, message, err)
+}
+
 func (s *Server) jsonResponse(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -1395,24 +2470,23 @@ func (s *Server) jsonError(w http.ResponseWriter, status int, message string, er
 // machine-readable error_code. Editor clients should switch on error_code,
 // never on the message text.
 func (s *Server) jsonErrorCode(w http.ResponseWriter, status int, code, message string, err error) {
-	if code == "" {
-		code = defaultErrorCodeForStatus(status)
-	}
+	writeError(w, status, code, message, err)
+}
+
+// jsonCooldown rejects an intervention requested before the cooldown ends.
+// cooldown_remaining (seconds) lets clients show a countdown, and
+// rationale names the contract rule like a delivered intervention does.
+func (s *Server) jsonCooldown(w http.ResponseWriter, rationale domain.LevelRationale, message string) {
 	response := map[string]interface{}{
-		"error":      message,
-		"error_code": code,
-		"status":     status,
+		"error":              "cooldown active",
+		"error_code":         ErrCodeCooldownActive,
+		"status":             http.StatusTooManyRequests,
+		"message":            message,
+		"cooldown_remaining": rationale.CooldownRemaining,
+		"rationale":          rationale,
 	}
-	if err != nil {
-		// If the underlying error already carries a payload-specific code,
-		// surface it as the authoritative code; payload validators set
-		// these.
-		if pe := asPayloadError(err); pe != nil {
-			response["error_code"] = pe.Code
-		}
-		response["details"] = err.Error()
-	}
-	s.jsonResponse(w, status, response)
+	addCorrelationID(w, response)
+	s.jsonResponse(w, http.StatusTooManyRequests, response)
 }
 
 func writeSSEEvent(w http.ResponseWriter, event string, data string) {
@@ -1503,54 +2577,304 @@ func (s *Server) handleAnalyticsErrors(w http.ResponseWriter, r *http.Request) {
 		s.jsonError(w, http.StatusInternalServerError, "failed to get error patterns", err)
 		return
 	}
-	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
-		"patterns": errors,
-	})
-}
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"patterns": errors,
+	})
+}
+
+func (s *Server) handleAnalyticsTrend(w http.ResponseWriter, r *http.Request) {
+	trend, err := s.profileService.GetHintTrend(r.Context())
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to get hint trend", err)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"trend": trend,
+	})
+}
+
+// History search
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		s.jsonError(w, http.StatusBadRequest, "q is required", nil)
+		return
+	}
+
+	kind := r.URL.Query().Get("kind")
+	switch kind {
+	case "", session.SearchKindSession, session.SearchKindRun, session.SearchKindIntervention:
+	default:
+		s.jsonError(w, http.StatusBadRequest, "kind must be session, run or intervention", nil)
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			s.jsonError(w, http.StatusBadRequest, "limit must be a positive integer", err)
+			return
+		}
+		limit = n
+	}
+
+	hits, err := s.sessionService.Search(r.Context(), session.SearchQuery{
+		Text:  query,
+		Kind:  kind,
+		Limit: limit,
+	})
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "search failed", err)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"query": query,
+		"hits":  hits,
+		"count": len(hits),
+	})
+}
+
+// Admin handlers
+
+// retentionPolicy converts the configured retention days into a policy
+func retentionPolicy(cfg config.RetentionConfig) session.RetentionPolicy {
+	return session.RetentionPolicy{
+		SessionMaxAge: time.Duration(cfg.SessionsDays) * 24 * time.Hour,
+		RunMaxAge:     time.Duration(cfg.RunsDays) * 24 * time.Hour,
+	}
+}
+
+// activityPolicy converts the configured idle and expiry times into a
+// policy
+func activityPolicy(cfg config.SessionsConfig) session.ActivityPolicy {
+	return session.ActivityPolicy{
+		IdleAfter:   time.Duration(cfg.IdleMinutes) * time.Minute,
+		ExpireAfter: time.Duration(cfg.ExpireHours) * time.Hour,
+	}
+}
+
+func (s *Server) handlePrune(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DryRun       bool `json:"dry_run"`
+		SessionsDays *int `json:"sessions_days,omitempty" validate:"min=0"` // overrides config
+		RunsDays     *int `json:"runs_days,omitempty" validate:"min=0"`     // overrides config
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	var retention config.RetentionConfig
+	if s.cfg != nil {
+		retention = s.cfg.Retention
+	}
+	if req.SessionsDays != nil {
+		retention.SessionsDays = *req.SessionsDays
+	}
+	if req.RunsDays != nil {
+		retention.RunsDays = *req.RunsDays
+	}
+	if retention.SessionsDays < 0 || retention.RunsDays < 0 {
+		s.jsonError(w, http.StatusBadRequest, "retention days must not be negative", nil)
+		return
+	}
+
+	report, err := s.sessionService.Prune(r.Context(), retentionPolicy(retention), time.Now(), req.DryRun)
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "prune failed", err)
+		return
+	}
+	if s.editLog != nil && !req.DryRun {
+		for _, id := range report.Sessions {
+			if err := s.editLog.Delete(id); err != nil {
+				slog.Warn("delete edit events", "session_id", id, "error", err)
+			}
+		}
+	}
+
+	s.jsonResponse(w, http.StatusOK, report)
+}
+
+// Spec handlers
+
+func (s *Server) handleCreateSpec(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	specObj, err := s.specService.Create(r.Context(), req.Name)
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to create spec", err)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusCreated, specObj)
+}
+
+// handleImportSpec fetches a GitHub issue or Jira ticket and saves it as
+// a spec scaffold
+func (s *Server) handleImportSpec(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		GitHub    string `json:"github"` // owner/repo#123
+		Jira      string `json:"jira"`   // PROJ-42
+		Overwrite bool   `json:"overwrite"`
+	}
+
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	if (req.GitHub == "") == (req.Jira == "") {
+		s.jsonError(w, http.StatusBadRequest, "exactly one of github or jira is required", nil)
+		return
+	}
+
+	system, ref := specimport.SourceGitHub, req.GitHub
+	if req.Jira != "" {
+		system, ref = specimport.SourceJira, req.Jira
+	}
+	issue, err := s.issueTracker(string(system)).Fetch(r.Context(), ref)
+	if err != nil {
+		switch {
+		case errors.Is(err, specimport.ErrInvalidRef):
+			s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error(), nil)
+		case errors.Is(err, specimport.ErrIssueNotFound):
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, err.Error(), nil)
+		case errors.Is(err, specimport.ErrNotConfigured):
+			s.jsonErrorCode(w, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, err.Error(), nil)
+		default:
+			s.jsonErrorCode(w, http.StatusBadGateway, "", "failed to fetch issue", err)
+		}
+		return
+	}
+
+	specObj := specimport.ToSpec(issue)
+	if !req.Overwrite {
+		if _, err := s.specService.Load(r.Context(), specObj.FilePath); err == nil {
+			s.jsonErrorCode(w, http.StatusConflict, ErrCodeConflict,
+				"spec already exists: "+specObj.FilePath+" (set overwrite to replace it)", nil)
+			return
+		}
+	}
+
+	if err := s.specService.Save(r.Context(), specObj); err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to save spec", err)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusCreated, specObj)
+}
+
+// issueTracker returns a client for the tracker named by system, with
+// credentials from the integrations config
+func (s *Server) issueTracker(system string) specimport.Tracker {
+	var integrations config.IntegrationsConfig
+	if s.cfg != nil {
+		integrations = s.cfg.Integrations
+	}
+	if system == string(specimport.SourceJira) {
+		return &specimport.JiraClient{
+			BaseURL: integrations.Jira.URL,
+			Email:   integrations.Jira.Email,
+			Token:   integrations.Jira.APIToken,
+		}
+	}
+	return &specimport.GitHubClient{BaseURL: integrations.GitHub.APIURL, Token: integrations.GitHub.Token}
+}
+
+// handleSyncSpec reports the spec's satisfied criteria to its issue
+func (s *Server) handleSyncSpec(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("path")
+	if path == "" {
+		s.jsonError(w, http.StatusBadRequest, "spec path is required", nil)
+		return
+	}
 
-func (s *Server) handleAnalyticsTrend(w http.ResponseWriter, r *http.Request) {
-	trend, err := s.profileService.GetHintTrend(r.Context())
+	result, err := s.syncSpecTracker(r.Context(), path)
 	if err != nil {
-		s.jsonError(w, http.StatusInternalServerError, "failed to get hint trend", err)
+		switch {
+		case errors.Is(err, spec.ErrSpecNotFound):
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSpecNotFound, "spec not found", nil)
+		case errors.Is(err, specimport.ErrSyncDisabled):
+			s.jsonErrorCode(w, http.StatusConflict, ErrCodeConflict,
+				"tracker sync is not enabled for this spec (set tracker.sync to checklist or comment)", nil)
+		case errors.Is(err, specimport.ErrNotConfigured):
+			s.jsonErrorCode(w, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, err.Error(), nil)
+		case errors.Is(err, errSyncNotRecorded):
+			s.jsonError(w, http.StatusInternalServerError, "issue updated but spec not saved", err)
+		default:
+			s.jsonErrorCode(w, http.StatusBadGateway, "", "failed to update issue", err)
+		}
 		return
 	}
-	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
-		"trend": trend,
-	})
+
+	s.jsonResponse(w, http.StatusOK, result)
 }
 
-// Spec handlers
+// errSyncNotRecorded marks a sync whose tracker update succeeded but
+// whose spec could not be saved
+var errSyncNotRecorded = errors.New("synced criteria not recorded")
 
-func (s *Server) handleCreateSpec(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Name string `json:"name"`
+// syncSpecTracker pushes newly satisfied criteria to the spec's issue and
+// records them in the spec so they are reported once. Syncs are
+// serialized so concurrent criterion updates cannot post duplicates.
+func (s *Server) syncSpecTracker(ctx context.Context, path string) (*specimport.SyncResult, error) {
+	s.trackerSyncMu.Lock()
+	defer s.trackerSyncMu.Unlock()
+
+	specObj, err := s.specService.Load(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if specObj.Tracker == nil {
+		return nil, specimport.ErrSyncDisabled
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
-		return
+	result, syncErr := specimport.SyncCriteria(ctx, s.issueTracker(specObj.Tracker.System), specObj)
+	if result != nil && len(result.Synced()) > 0 {
+		specObj.Tracker.Synced = append(specObj.Tracker.Synced, result.Synced()...)
+		if err := s.specService.Save(ctx, specObj); err != nil {
+			return nil, fmt.Errorf("%w: %v", errSyncNotRecorded, err)
+		}
 	}
+	return result, syncErr
+}
 
-	if req.Name == "" {
-		s.jsonError(w, http.StatusBadRequest, "name is required", nil)
+// syncSpecTrackerAsync runs a tracker sync in the background when the
+// spec has sync enabled; failures are logged, the next sync retries them
+func (s *Server) syncSpecTrackerAsync(path string) {
+	specObj, err := s.specService.Load(context.Background(), path)
+	if err != nil || specObj.Tracker == nil || specObj.Tracker.Sync == "" {
 		return
 	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if _, err := s.syncSpecTracker(ctx, path); err != nil {
+			slog.Warn("tracker sync failed", "spec", path, "issue", specObj.Tracker.Key, "error", err)
+		}
+	}()
+}
 
-	specObj, err := s.specService.Create(r.Context(), req.Name)
+func (s *Server) handleListSpecs(w http.ResponseWriter, r *http.Request) {
+	params, err := parseListParams(r.URL.Query(), specListFields, []string{"name", "version"}, "")
 	if err != nil {
-		s.jsonError(w, http.StatusInternalServerError, "failed to create spec", err)
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error(), nil)
 		return
 	}
 
-	s.jsonResponse(w, http.StatusCreated, specObj)
-}
-
-func (s *Server) handleListSpecs(w http.ResponseWriter, r *http.Request) {
 	specs, err := s.specService.List(r.Context())
 	if err != nil {
 		s.jsonError(w, http.StatusInternalServerError, "failed to list specs", err)
 		return
 	}
+	specs, total := applyList(specs, params, specListFields)
 
 	// Return summary info
 	result := make([]map[string]interface{}, 0, len(specs))
@@ -1568,9 +2892,9 @@ func (s *Server) handleListSpecs(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
-		"specs": result,
-	})
+	resp := pageInfo(params, total)
+	resp["specs"] = result
+	s.jsonResponse(w, http.StatusOK, resp)
 }
 
 func (s *Server) handleGetSpec(w http.ResponseWriter, r *http.Request) {
@@ -1618,21 +2942,55 @@ func (s *Server) handleValidateSpec(w http.ResponseWriter, r *http.Request) {
 	s.jsonResponse(w, status, validation)
 }
 
+// handleReviewSpec asks the LLM to critique a spec. The findings and
+// suggested rewrites are returned for the author; the spec is not changed.
+func (s *Server) handleReviewSpec(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("path")
+	if path == "" {
+		s.jsonError(w, http.StatusBadRequest, "spec path is required", nil)
+		return
+	}
+
+	specObj, err := s.specService.Load(r.Context(), path)
+	if err != nil {
+		if err == spec.ErrSpecNotFound {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSpecNotFound, "spec not found", nil)
+			return
+		}
+		s.jsonError(w, http.StatusInternalServerError, "failed to load spec", err)
+		return
+	}
+
+	review, err := s.pairingService.ReviewSpec(r.Context(), specObj)
+	if err != nil {
+		if errors.Is(err, pairing.ErrUnparseableReview) {
+			s.jsonErrorCode(w, http.StatusBadGateway, ErrCodeLLMUnavailable, "LLM returned an unreadable review; try again", err)
+			return
+		}
+		s.pairingError(w, "failed to review spec", err)
+		return
+	}
+	if review.SpecPath == "" {
+		review.SpecPath = path
+	}
+
+	s.jsonResponse(w, http.StatusOK, review)
+}
+
 func (s *Server) handleMarkCriterionSatisfied(w http.ResponseWriter, r *http.Request) {
 	criterionID := r.PathValue("id")
 
 	var req struct {
-		Path     string `json:"path"`
+		Path     string `json:"path" validate:"required"`
 		Evidence string `json:"evidence"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+	if !s.decodeRequest(w, r, &req) {
 		return
 	}
 
-	if req.Path == "" || criterionID == "" {
-		s.jsonError(w, http.StatusBadRequest, "spec path and criterion id are required", nil)
+	if criterionID == "" {
+		s.jsonError(w, http.StatusBadRequest, "criterion id is required", nil)
 		return
 	}
 
@@ -1650,6 +3008,7 @@ func (s *Server) handleMarkCriterionSatisfied(w http.ResponseWriter, r *http.Req
 		s.jsonError(w, http.StatusInternalServerError, "failed to mark criterion satisfied", err)
 		return
 	}
+	s.syncSpecTrackerAsync(path)
 
 	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
 		"success":      true,
@@ -1722,6 +3081,158 @@ func (s *Server) handleGetSpecDrift(w http.ResponseWriter, r *http.Request) {
 	s.jsonResponse(w, http.StatusOK, drift)
 }
 
+func (s *Server) handleGetSpecPlan(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("path")
+	if path == "" {
+		s.jsonError(w, http.StatusBadRequest, "spec path is required", nil)
+		return
+	}
+
+	plan, err := s.specService.Plan(r.Context(), path)
+	if err != nil {
+		switch {
+		case err == spec.ErrSpecNotFound:
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSpecNotFound, "spec not found", nil)
+		case errors.Is(err, spec.ErrDependencyCycle):
+			s.jsonErrorCode(w, http.StatusUnprocessableEntity, ErrCodeSpecInvalid, err.Error(), nil)
+		default:
+			s.jsonError(w, http.StatusInternalServerError, "failed to plan spec", err)
+		}
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, plan)
+}
+
+func (s *Server) handleSpecDiagram(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("path")
+	if path == "" {
+		s.jsonError(w, http.StatusBadRequest, "spec path is required", nil)
+		return
+	}
+
+	var req struct {
+		Format domain.DiagramFormat `json:"format,omitempty" validate:"oneof=mermaid c4"` // mermaid (default) or c4
+		Write  bool                 `json:"write,omitempty"`                              // also save the diagram next to the spec
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	diagram, err := s.specService.Diagram(r.Context(), path, req.Format, req.Write)
+	if err != nil {
+		switch {
+		case err == spec.ErrSpecNotFound:
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSpecNotFound, "spec not found", nil)
+		case errors.Is(err, spec.ErrUnknownDiagramFormat):
+			s.jsonError(w, http.StatusBadRequest, err.Error(), nil)
+		default:
+			s.jsonError(w, http.StatusInternalServerError, "failed to generate diagram", err)
+		}
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, diagram)
+}
+
+// maxFeatureRuns caps the recent runs listed in a feature progress view
+const maxFeatureRuns = 10
+
+func (s *Server) handleGetFeatureProgress(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("path")
+	if path == "" {
+		s.jsonError(w, http.StatusBadRequest, "spec path is required", nil)
+		return
+	}
+	featureID := r.PathValue("id")
+
+	specObj, err := s.specService.Load(r.Context(), path)
+	if err != nil {
+		if err == spec.ErrSpecNotFound {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSpecNotFound, "spec not found", nil)
+			return
+		}
+		s.jsonError(w, http.StatusInternalServerError, "failed to load spec", err)
+		return
+	}
+
+	progress := specObj.GetFeatureProgress(featureID)
+	if progress == nil {
+		s.jsonErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "feature not found: "+featureID, nil)
+		return
+	}
+	progress.SpecPath = path
+
+	runs, err := s.sessionService.RunsForSpec(r.Context(), path)
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to load runs", err)
+		return
+	}
+	addFeatureRuns(progress, runs)
+
+	// Touched files need git; without it the view is still useful
+	if files, err := spec.TouchedFiles(r.Context(), s.specService.GetWorkspaceRoot(),
+		progress.Feature.ID, progress.Feature.Title); err == nil {
+		progress.TouchedFiles = files
+	}
+
+	s.jsonResponse(w, http.StatusOK, progress)
+}
+
+// addFeatureRuns records the latest outcome of each test referencing the
+// feature and the runs they appeared in. runs must be newest first.
+func addFeatureRuns(progress *domain.FeatureProgress, runs []*session.Run) {
+	seen := make(map[string]bool)
+	for _, run := range runs {
+		if run.Result == nil {
+			continue
+		}
+		summary := domain.FeatureRun{ID: run.ID, SessionID: run.SessionID, CreatedAt: run.CreatedAt}
+		for _, t := range run.Result.Tests {
+			if !progress.Feature.MatchesTest(t.Name) {
+				continue
+			}
+			if t.Passed {
+				summary.Passed++
+			} else {
+				summary.Failed++
+			}
+			if !seen[t.Name] {
+				seen[t.Name] = true
+				progress.Tests = append(progress.Tests, domain.FeatureTest{
+					Name: t.Name, Passed: t.Passed, RunID: run.ID, RunAt: run.CreatedAt, SessionID: run.SessionID,
+				})
+			}
+		}
+		if summary.Passed+summary.Failed > 0 && len(progress.RecentRuns) < maxFeatureRuns {
+			progress.RecentRuns = append(progress.RecentRuns, summary)
+		}
+	}
+}
+
+func (s *Server) handleGetSpecHistory(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("path")
+	if path == "" {
+		s.jsonError(w, http.StatusBadRequest, "spec path is required", nil)
+		return
+	}
+
+	history, err := s.specService.History(r.Context(), path)
+	if err != nil {
+		if err == spec.ErrSpecNotFound {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSpecNotFound, "spec not found", nil)
+			return
+		}
+		s.jsonError(w, http.StatusInternalServerError, "failed to load spec history", err)
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"spec_path": path,
+		"locks":     history,
+	})
+}
+
 // Patch handlers
 
 func (s *Server) handlePatchPreview(w http.ResponseWriter, r *http.Request) {
@@ -1774,18 +3285,22 @@ func (s *Server) handlePatchApply(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !s.requireDriver(w, r, sessionID) {
+		return
+	}
+
 	// Apply the pending patch
 	file, content, err := s.patchService.ApplyPending(sessUUID)
 	if err != nil {
 		switch err {
 		case patch.ErrPatchNotFound:
-			s.jsonError(w, http.StatusNotFound, "no pending patch to apply", nil)
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodePatchNotFound, "no pending patch to apply", nil)
 		case patch.ErrPatchApplied:
-			s.jsonError(w, http.StatusConflict, "patch already applied", nil)
+			s.jsonErrorCode(w, http.StatusConflict, ErrCodePatchResolved, "patch already applied", nil)
 		case patch.ErrPatchRejected:
-			s.jsonError(w, http.StatusConflict, "patch was rejected", nil)
+			s.jsonErrorCode(w, http.StatusConflict, ErrCodePatchResolved, "patch was rejected", nil)
 		case patch.ErrPatchExpired:
-			s.jsonError(w, http.StatusGone, "patch has expired", nil)
+			s.jsonErrorCode(w, http.StatusGone, ErrCodePatchExpired, "patch has expired", nil)
 		default:
 			s.jsonError(w, http.StatusInternalServerError, "failed to apply patch", err)
 		}
@@ -1802,6 +3317,9 @@ func (s *Server) handlePatchApply(w http.ResponseWriter, r *http.Request) {
 	// Update session with new code
 	if _, err := s.sessionService.UpdateCode(r.Context(), sessionID, newCode); err != nil {
 		slog.Warn("failed to update session code after patch apply", "error", err)
+	} else {
+		s.collab.wrote(sessionID, r.Header.Get(ClientHeader))
+		s.events.notify(sessionID)
 	}
 
 	slog.Info("patch applied",
@@ -1827,7 +3345,7 @@ func (s *Server) handlePatchReject(w http.ResponseWriter, r *http.Request) {
 
 	if err := s.patchService.RejectPending(sessUUID); err != nil {
 		if err == patch.ErrPatchNotFound {
-			s.jsonError(w, http.StatusNotFound, "no pending patch to reject", nil)
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodePatchNotFound, "no pending patch to reject", nil)
 			return
 		}
 		s.jsonError(w, http.StatusInternalServerError, "failed to reject patch", err)
@@ -1868,27 +3386,20 @@ func (s *Server) handlePatchLog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse optional query params
-	limitStr := r.URL.Query().Get("limit")
-	sessionID := r.URL.Query().Get("session_id")
-
-	var entries []patch.LogEntry
-	if sessionID != "" {
-		entries = logger.GetSessionEntries(sessionID)
-	} else if limitStr != "" {
-		limit := 50 // default
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
-			limit = l
-		}
-		entries = logger.GetRecentEntries(limit)
-	} else {
-		entries = logger.GetEntries()
+	// Newest first, so ?limit=N keeps returning the N most recent entries
+	params, err := parseListParams(r.URL.Query(), patchLogFields,
+		[]string{"session_id", "action", "status", "file"}, "-timestamp")
+	if err != nil {
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error(), nil)
+		return
 	}
 
-	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
-		"entries": entries,
-		"count":   len(entries),
-	})
+	entries, total := applyList(logger.GetEntries(), params, patchLogFields)
+
+	resp := pageInfo(params, total)
+	resp["entries"] = entries
+	resp["count"] = len(entries)
+	s.jsonResponse(w, http.StatusOK, resp)
 }
 
 func (s *Server) handlePatchStats(w http.ResponseWriter, r *http.Request) {
@@ -1911,8 +3422,7 @@ func (s *Server) handleAuthoringDiscover(w http.ResponseWriter, r *http.Request)
 		Recursive bool     `json:"recursive"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+	if !s.decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -1959,17 +3469,12 @@ func (s *Server) handleAuthoringSuggest(w http.ResponseWriter, r *http.Request)
 	sessionID := r.PathValue("id")
 
 	var req struct {
-		Section string `json:"section"` // goals, features, acceptance_criteria, non_functional
+		Section string `json:"section" validate:"required"` // goals, features, acceptance_criteria, non_functional
 		Context string `json:"context,omitempty"`
+		File    string `json:"file,omitempty"` // spec file to suggest for; defaults to the session's first
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
-		return
-	}
-
-	if req.Section == "" {
-		s.jsonError(w, http.StatusBadRequest, "section is required", nil)
+	if !s.decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -1986,19 +3491,23 @@ func (s *Server) handleAuthoringSuggest(w http.ResponseWriter, r *http.Request)
 
 	// Verify it's an authoring session
 	if sess.Intent != session.IntentSpecAuthoring {
-		s.jsonError(w, http.StatusBadRequest, "session is not a spec authoring session", nil)
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeWrongSessionKind, "session is not a spec authoring session", nil)
 		return
 	}
 
-	// Load spec
-	spec, err := s.specService.Load(r.Context(), sess.SpecPath)
+	// Load the targeted spec and the session's other files
+	spec, siblings, err := s.loadAuthoringSpecs(r.Context(), sess, req.File)
 	if err != nil {
+		if errors.Is(err, errAuthoringFileUnknown) {
+			s.jsonError(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		s.jsonError(w, http.StatusInternalServerError, "failed to load spec", err)
 		return
 	}
 
 	// Discover and load documents
-	workspaceRoot := s.specService.GetWorkspaceRoot()
+	workspaceRoot := s.specsFor(sess).GetWorkspaceRoot()
 	discoverer := docindex.NewDiscoverer(workspaceRoot)
 	docs, err := discoverer.Discover(docindex.DiscoverOptions{
 		Paths:     sess.AuthoringDocs,
@@ -2024,16 +3533,18 @@ func (s *Server) handleAuthoringSuggest(w http.ResponseWriter, r *http.Request)
 		Spec:      spec,
 		Section:   req.Section,
 		Documents: docs,
+		Siblings:  siblings,
 	}
 
 	// Get suggestions from pairing service
 	suggestions, err := s.pairingService.SuggestForSection(r.Context(), ctx)
 	if err != nil {
-		s.jsonError(w, http.StatusInternalServerError, "failed to generate suggestions", err)
+		s.pairingError(w, "failed to generate suggestions", err)
 		return
 	}
 
 	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"file":        spec.FilePath,
 		"section":     req.Section,
 		"suggestions": suggestions,
 	})
@@ -2043,18 +3554,13 @@ func (s *Server) handleAuthoringApply(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.PathValue("id")
 
 	var req struct {
-		Section      string `json:"section"`
+		Section      string `json:"section" validate:"required"`
 		SuggestionID string `json:"suggestion_id"`
 		Value        any    `json:"value,omitempty"` // Direct value to apply (alternative to suggestion_id)
+		File         string `json:"file,omitempty"`  // spec file to apply to; defaults to the session's first
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
-		return
-	}
-
-	if req.Section == "" {
-		s.jsonError(w, http.StatusBadRequest, "section is required", nil)
+	if !s.decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -2071,7 +3577,13 @@ func (s *Server) handleAuthoringApply(w http.ResponseWriter, r *http.Request) {
 
 	// Verify it's an authoring session
 	if sess.Intent != session.IntentSpecAuthoring {
-		s.jsonError(w, http.StatusBadRequest, "session is not a spec authoring session", nil)
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeWrongSessionKind, "session is not a spec authoring session", nil)
+		return
+	}
+
+	file, ok := sess.SpecFile(req.File)
+	if !ok {
+		s.jsonError(w, http.StatusBadRequest, fmt.Sprintf("%s: %s", errAuthoringFileUnknown, req.File), nil)
 		return
 	}
 
@@ -2079,6 +3591,7 @@ func (s *Server) handleAuthoringApply(w http.ResponseWriter, r *http.Request) {
 	// For now, we return the value that should be applied - the editor will update the file
 	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
 		"applied":       true,
+		"file":          file,
 		"section":       req.Section,
 		"suggestion_id": req.SuggestionID,
 		"value":         req.Value,
@@ -2091,10 +3604,10 @@ func (s *Server) handleAuthoringHint(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Section  string `json:"section"`
 		Question string `json:"question"`
+		File     string `json:"file,omitempty"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+	if !s.decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -2111,19 +3624,23 @@ func (s *Server) handleAuthoringHint(w http.ResponseWriter, r *http.Request) {
 
 	// Verify it's an authoring session
 	if sess.Intent != session.IntentSpecAuthoring {
-		s.jsonError(w, http.StatusBadRequest, "session is not a spec authoring session", nil)
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeWrongSessionKind, "session is not a spec authoring session", nil)
 		return
 	}
 
-	// Load spec
-	spec, err := s.specService.Load(r.Context(), sess.SpecPath)
+	// Load the targeted spec and the session's other files
+	spec, siblings, err := s.loadAuthoringSpecs(r.Context(), sess, req.File)
 	if err != nil {
+		if errors.Is(err, errAuthoringFileUnknown) {
+			s.jsonError(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		s.jsonError(w, http.StatusInternalServerError, "failed to load spec", err)
 		return
 	}
 
 	// Discover and load documents
-	workspaceRoot := s.specService.GetWorkspaceRoot()
+	workspaceRoot := s.specsFor(sess).GetWorkspaceRoot()
 	discoverer := docindex.NewDiscoverer(workspaceRoot)
 	docs, err := discoverer.Discover(docindex.DiscoverOptions{
 		Paths:     sess.AuthoringDocs,
@@ -2141,40 +3658,117 @@ func (s *Server) handleAuthoringHint(w http.ResponseWriter, r *http.Request) {
 		Section:   req.Section,
 		Documents: docs,
 		Question:  req.Question,
+		Siblings:  siblings,
 	}
 
 	// Get hint from pairing service
 	hint, err := s.pairingService.AuthoringHint(r.Context(), ctx)
 	if err != nil {
-		s.jsonError(w, http.StatusInternalServerError, "failed to generate hint", err)
+		s.pairingError(w, "failed to generate hint", err)
 		return
 	}
 
 	s.jsonResponse(w, http.StatusOK, hint)
 }
 
+// errAuthoringFileUnknown means a request named a spec file the authoring
+// session does not hold
+var errAuthoringFileUnknown = errors.New("spec file is not part of this authoring session")
 
-// AI Spec Generation handler
+// loadAuthoringSpecs loads the spec file a request targets (the session's
+// first when file is empty) and the session's other spec files
+func (s *Server) loadAuthoringSpecs(ctx context.Context, sess *session.Session, file string) (*domain.ProductSpec, []*domain.ProductSpec, error) {
+	target, ok := sess.SpecFile(file)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", errAuthoringFileUnknown, file)
+	}
+	specs := s.specsFor(sess)
+	spec, err := specs.Load(ctx, target)
+	if err != nil {
+		return nil, nil, err
+	}
 
-func (s *Server) handleGenerateSpec(w http.ResponseWriter, r *http.Request) {
+	var siblings []*domain.ProductSpec
+	for _, path := range sess.SpecFiles() {
+		if path == target {
+			continue
+		}
+		sibling, err := specs.Load(ctx, path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load %s: %w", path, err)
+		}
+		siblings = append(siblings, sibling)
+	}
+	return spec, siblings, nil
+}
+
+func (s *Server) handleAuthoringAddFile(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Name        string   `json:"name"`
-		Description string   `json:"description"`
-		Goals       []string `json:"goals,omitempty"`
-		Context     string   `json:"context,omitempty"`
+		SpecPath string `json:"spec_path" validate:"required"`
+	}
+	if !s.decodeRequest(w, r, &req) {
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+	sess, err := s.sessionService.AddAuthoringSpec(r.Context(), r.PathValue("id"), req.SpecPath)
+	if err != nil {
+		switch {
+		case err == session.ErrSessionNotFound:
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+		case err == session.ErrNotAuthoring:
+			s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeWrongSessionKind, "session is not a spec authoring session", nil)
+		case errors.Is(err, spec.ErrSpecNotFound):
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSpecNotFound, "spec not found: "+req.SpecPath, nil)
+		default:
+			s.jsonError(w, http.StatusInternalServerError, "failed to add spec file", err)
+		}
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, sess)
+}
+
+// handleAuthoringCheck runs cross-file consistency checks over the
+// session's spec files
+func (s *Server) handleAuthoringCheck(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.sessionService.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if err == session.ErrSessionNotFound {
+			s.jsonErrorCode(w, http.StatusNotFound, ErrCodeSessionNotFound, "session not found", nil)
+			return
+		}
+		s.jsonError(w, http.StatusInternalServerError, "failed to get session", err)
+		return
+	}
+	if sess.Intent != session.IntentSpecAuthoring {
+		s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeWrongSessionKind, "session is not a spec authoring session", nil)
 		return
 	}
 
-	if req.Name == "" {
-		s.jsonError(w, http.StatusBadRequest, "name is required", nil)
+	first, others, err := s.loadAuthoringSpecs(r.Context(), sess, "")
+	if err != nil {
+		s.jsonError(w, http.StatusInternalServerError, "failed to load spec", err)
 		return
 	}
-	if req.Description == "" {
-		s.jsonError(w, http.StatusBadRequest, "description is required", nil)
+	specs := append([]*domain.ProductSpec{first}, others...)
+
+	s.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"files":    sess.SpecFiles(),
+		"findings": spec.CheckConsistency(specs),
+	})
+}
+
+// AI Spec Generation handler
+
+func (s *Server) handleGenerateSpec(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name        string   `json:"name" validate:"required"`
+		Description string   `json:"description" validate:"required"`
+		Goals       []string `json:"goals,omitempty"`
+		Context     string   `json:"context,omitempty"`
+	}
+
+	if !s.decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -2305,7 +3899,6 @@ Requirements:
 	})
 }
 
-
 // --- Document Index (External Context) Handlers ---
 
 func (s *Server) handleDocIndexIndex(w http.ResponseWriter, r *http.Request) {
@@ -2317,8 +3910,7 @@ func (s *Server) handleDocIndexIndex(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Path string `json:"path"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+	if !s.decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -2358,16 +3950,10 @@ func (s *Server) handleDocIndexSearch(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Query string `json:"query"`
-		TopK  int    `json:"top_k,omitempty"`
+		Query string `json:"query" validate:"required"`
+		TopK  int    `json:"top_k,omitempty" validate:"min=0"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
-		return
-	}
-
-	if req.Query == "" {
-		s.jsonError(w, http.StatusBadRequest, "query is required", nil)
+	if !s.decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -2422,6 +4008,19 @@ func (s *Server) handleDocIndexReindex(w http.ResponseWriter, r *http.Request) {
 	s.jsonResponse(w, http.StatusOK, result)
 }
 
+// redactConfig converts the YAML redaction settings into a redact.Config
+func redactConfig(rc config.RedactionConfig) redact.Config {
+	patterns := make([]redact.Pattern, len(rc.Patterns))
+	for i, p := range rc.Patterns {
+		patterns[i] = redact.Pattern{Name: p.Name, Regex: p.Regex}
+	}
+	return redact.Config{
+		Detectors:       rc.Detectors,
+		InternalDomains: rc.InternalDomains,
+		Patterns:        patterns,
+	}
+}
+
 // buildLevelModelMap converts the YAML-friendly map[string]string from
 // config into the typed map the pairing service expects. Keys "0".."5"
 // are recognized; unknown keys are ignored with a slog warn.
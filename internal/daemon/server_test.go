@@ -3,6 +3,7 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -24,7 +25,7 @@ func setupTestServer(t *testing.T) (*Server, func()) {
 	}
 
 	// Create subdirectories
-	for _, dir := range []string{"sessions", "exercises", "logs"} {
+	for _, dir := range []string{"sessions", "exercises", "logs", "workspace/.specs"} {
 		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
 			t.Fatalf("create subdir %s: %v", dir, err)
 		}
@@ -41,6 +42,8 @@ func setupTestServer(t *testing.T) (*Server, func()) {
 		Config:       cfg,
 		ExercisePath: filepath.Join(tmpDir, "exercises"),
 		SessionsPath: filepath.Join(tmpDir, "sessions"),
+		// Specs, locks and lock history stay out of the source tree
+		SpecsPath: filepath.Join(tmpDir, "workspace"),
 	})
 	if err != nil {
 		os.RemoveAll(tmpDir)
@@ -54,6 +57,29 @@ func setupTestServer(t *testing.T) (*Server, func()) {
 	return server, cleanup
 }
 
+func TestNewServer_RejectsUnknownStorageDriver(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultLocalConfig()
+	cfg.Daemon.Port = 0
+	cfg.Runner.Executor = "local"
+	cfg.Storage.Driver = "postgres"
+	cfg.Storage.Path = filepath.Join(tmpDir, "temper.db")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := NewServer(ctx, ServerConfig{
+		Config:       cfg,
+		ExercisePath: filepath.Join(tmpDir, "exercises"),
+		SpecsPath:    filepath.Join(tmpDir, "workspace"),
+	})
+	if err == nil || !strings.Contains(err.Error(), `unknown storage driver "postgres"`) {
+		t.Fatalf("NewServer() error = %v, want the unknown driver rejected", err)
+	}
+	if _, err := os.Stat(cfg.Storage.Path); !os.IsNotExist(err) {
+		t.Error("no sqlite database should be created for an unknown driver")
+	}
+}
+
 func TestHealthEndpoint(t *testing.T) {
 	server, cleanup := setupTestServer(t)
 	defer cleanup()
@@ -302,3 +328,42 @@ func TestMiddleware(t *testing.T) {
 		}
 	})
 }
+
+func TestServer_Listen(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = taken.Close() }()
+
+	tests := []struct {
+		name string
+		addr string
+	}{
+		{"auto-assigned port", "127.0.0.1:0"},
+		{"port in use", taken.Addr().String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{server: &http.Server{Addr: tt.addr}}
+			bound, err := s.Listen()
+			if err != nil {
+				t.Fatalf("Listen() error = %v", err)
+			}
+			defer func() { _ = s.listener.Close() }()
+
+			if bound == taken.Addr().String() || strings.HasSuffix(bound, ":0") {
+				t.Errorf("Listen() = %q, want a free port", bound)
+			}
+		})
+	}
+}
+
+func TestDockerInstallHint(t *testing.T) {
+	if hint := dockerInstallHint("windows"); !strings.Contains(hint, "WSL 2") {
+		t.Errorf("windows hint = %q, want the WSL 2 backend named", hint)
+	}
+	if hint := dockerInstallHint("darwin"); !strings.Contains(hint, "colima") {
+		t.Errorf("darwin hint = %q", hint)
+	}
+}
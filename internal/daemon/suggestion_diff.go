@@ -0,0 +1,28 @@
+package daemon
+
+import (
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/patch"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+// suggestionDiffs diffs the code an L3+ intervention suggests against
+// the files the learner submitted with the request, so editors can draw a
+// diff view without diffing themselves. Blocks go to files the way patch
+// extraction assigns them; lower levels carry no code and get nil.
+func suggestionDiffs(iv *domain.Intervention, sessionID uuid.UUID, code map[string]string) []session.FileDiff {
+	if iv.Level < domain.L3ConstrainedSnippet {
+		return nil
+	}
+	var diffs []session.FileDiff
+	for _, p := range patch.NewExtractor().ExtractPatches(iv, sessionID, code) {
+		if strings.TrimSpace(p.Original) == strings.TrimSpace(p.Proposed) {
+			continue
+		}
+		diffs = append(diffs, session.DiffFile(p.File, p.Original, p.Proposed))
+	}
+	return diffs
+}
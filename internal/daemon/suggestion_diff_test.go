@@ -0,0 +1,76 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/session"
+	"github.com/google/uuid"
+)
+
+func TestSuggestionDiffs(t *testing.T) {
+	code := map[string]string{"main.go": "package main\n\nfunc add(a, b int) int {\n\treturn a - b\n}\n"}
+	content := "Try this:\n```go\npackage main\n\nfunc add(a, b int) int {\n\treturn a + b\n}\n```"
+
+	hint := &domain.Intervention{Level: domain.L2LocationConcept, Content: content}
+	if diffs := suggestionDiffs(hint, uuid.New(), code); diffs != nil {
+		t.Errorf("L2 diffs = %+v, want none", diffs)
+	}
+
+	snippet := &domain.Intervention{Level: domain.L3ConstrainedSnippet, Content: content}
+	diffs := suggestionDiffs(snippet, uuid.New(), code)
+	if len(diffs) != 1 || diffs[0].File != "main.go" || diffs[0].Additions != 1 || diffs[0].Deletions != 1 {
+		t.Fatalf("L3 diffs = %+v, want one line changed in main.go", diffs)
+	}
+	var removed, added []string
+	for _, l := range diffs[0].Lines {
+		switch l.Kind {
+		case session.LineRemove:
+			removed = append(removed, l.Text)
+		case session.LineAdd:
+			added = append(added, l.Text)
+		}
+	}
+	if len(removed) != 1 || removed[0] != "\treturn a - b" || len(added) != 1 || added[0] != "\treturn a + b" {
+		t.Errorf("lines removed %q, added %q", removed, added)
+	}
+
+	// Suggesting the code as it already is isn't a change
+	same := &domain.Intervention{Level: domain.L4PartialSolution, Content: "```go\n" + code["main.go"] + "```"}
+	if diffs := suggestionDiffs(same, uuid.New(), code); diffs != nil {
+		t.Errorf("unchanged diffs = %+v, want none", diffs)
+	}
+}
+
+func TestHandleHint_ReturnsDiffs(t *testing.T) {
+	m := newServerWithMocks()
+	m.sessions.getFn = func(ctx context.Context, id string) (*session.Session, error) {
+		return &session.Session{ID: id, Status: session.StatusActive, Policy: domain.DefaultPolicy(),
+			Code: map[string]string{"main.go": "package main\n\nvar x = 1\n"}}, nil
+	}
+	m.pairing.interveneFn = func(ctx context.Context, req pairing.InterventionRequest) (*domain.Intervention, error) {
+		return &domain.Intervention{ID: uuid.New(), Intent: req.Intent, Level: domain.L3ConstrainedSnippet,
+			Content: "```go\npackage main\n\nvar x = 2\n```"}, nil
+	}
+
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/sessions/"+uuid.New().String()+"/hint", strings.NewReader(`{}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Diffs []session.FileDiff `json:"diffs"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Diffs) != 1 || resp.Diffs[0].File != "main.go" || len(resp.Diffs[0].Lines) == 0 || resp.Diffs[0].Patch == "" {
+		t.Errorf("diffs = %+v, want a unified diff with lines for main.go", resp.Diffs)
+	}
+}
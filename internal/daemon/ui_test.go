@@ -0,0 +1,34 @@
+package daemon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUI_Served(t *testing.T) {
+	m := newServerWithMocks()
+	m.server.setupUI()
+
+	req := httptest.NewRequest(http.MethodGet, "/ui", nil)
+	w := httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "/ui/" {
+		t.Fatalf("/ui: expected a redirect to /ui/, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/ui/", nil)
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `src="app.js"`) {
+		t.Fatalf("/ui/: expected the dashboard page, got %d: %.200s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/ui/", nil)
+	w = httptest.NewRecorder()
+	m.server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /ui/: expected %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+}
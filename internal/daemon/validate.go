@@ -0,0 +1,248 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Request bodies declare their constraints with a validate struct tag:
+//
+//	Level int `json:"level" validate:"required,oneof=4 5"`
+//
+// Supported rules:
+//
+//	required   the field must not be empty or zero
+//	min=N      strings and slices: at least N long; numbers: at least N
+//	max=N      strings and slices: at most N long; numbers: at most N
+//	oneof=a b  the value must be one of the space-separated options
+//
+// min, max and oneof only apply to fields that are set, so optional fields
+// can carry them without also being required.
+
+// FieldError describes one invalid field in a request body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field in a request body. writeError
+// returns the list as "fields".
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// decodeRequest decodes the JSON body into dst and validates it. An empty
+// body decodes to the zero value, so required fields still report. On
+// failure it writes a 400 and returns false.
+func (s *Server) decodeRequest(w http.ResponseWriter, r *http.Request, dst any) bool {
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(dst); err != nil && !errors.Is(err, io.EOF) {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) && typeErr.Field != "" {
+				s.validationError(w, &ValidationError{Fields: []FieldError{{
+					Field:   typeErr.Field,
+					Message: fmt.Sprintf("%s must be a %s", typeErr.Field, jsonKind(typeErr.Type)),
+				}}})
+				return false
+			}
+			s.jsonError(w, http.StatusBadRequest, "invalid request body", err)
+			return false
+		}
+	}
+	return s.validRequest(w, dst)
+}
+
+// validRequest validates an already decoded request body. On failure it
+// writes a 400 and returns false.
+func (s *Server) validRequest(w http.ResponseWriter, req any) bool {
+	if err := validateRequest(req); err != nil {
+		s.validationError(w, err)
+		return false
+	}
+	return true
+}
+
+func (s *Server) validationError(w http.ResponseWriter, err *ValidationError) {
+	s.jsonErrorCode(w, http.StatusBadRequest, ErrCodeValidationFailed, err.Error(), err)
+}
+
+// validateRequest checks req, a pointer to a struct, against its validate
+// tags. It returns nil or a *ValidationError naming every invalid field.
+func validateRequest(req any) *ValidationError {
+	var fields []FieldError
+	validateStruct(reflect.Indirect(reflect.ValueOf(req)), "", &fields)
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: fields}
+}
+
+func validateStruct(v reflect.Value, prefix string, out *[]FieldError) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := prefix + jsonName(sf)
+		fv := v.Field(i)
+
+		if tag := sf.Tag.Get("validate"); tag != "" {
+			if msg := checkRules(fv, tag); msg != "" {
+				*out = append(*out, FieldError{Field: name, Message: name + " " + msg})
+				continue
+			}
+		}
+
+		// Descend into nested request objects
+		switch fv.Kind() {
+		case reflect.Struct:
+			validateStruct(fv, name+".", out)
+		case reflect.Pointer:
+			if !fv.IsNil() {
+				validateStruct(fv.Elem(), name+".", out)
+			}
+		case reflect.Slice:
+			for j := 0; j < fv.Len(); j++ {
+				validateStruct(reflect.Indirect(fv.Index(j)), fmt.Sprintf("%s[%d].", name, j), out)
+			}
+		}
+	}
+}
+
+// checkRules returns why v breaks the rules in tag, or "" if it doesn't
+func checkRules(v reflect.Value, tag string) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			if strings.Contains(","+tag+",", ",required,") {
+				return "is required"
+			}
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		if name == "required" {
+			if isBlank(v) {
+				return "is required"
+			}
+			continue
+		}
+		if v.IsZero() {
+			continue
+		}
+		switch name {
+		case "min", "max":
+			limit, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				panic(fmt.Sprintf("validate: bad %s rule %q", name, rule))
+			}
+			if msg := checkBound(v, name, limit); msg != "" {
+				return msg
+			}
+		case "oneof":
+			options := strings.Fields(arg)
+			got := fmt.Sprint(v.Interface())
+			found := false
+			for _, o := range options {
+				if got == o {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return "must be one of " + strings.Join(options, ", ")
+			}
+		default:
+			panic(fmt.Sprintf("validate: unknown rule %q", rule))
+		}
+	}
+	return ""
+}
+
+func checkBound(v reflect.Value, rule string, limit float64) string {
+	var n float64
+	var unit string
+	switch v.Kind() {
+	case reflect.String:
+		n, unit = float64(len([]rune(v.String()))), " characters"
+	case reflect.Slice, reflect.Map:
+		n, unit = float64(v.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		return ""
+	}
+	bound := strconv.FormatFloat(limit, 'f', -1, 64)
+	if rule == "min" && n < limit {
+		if unit != "" {
+			return "must be at least " + bound + unit
+		}
+		return "must be at least " + bound
+	}
+	if rule == "max" && n > limit {
+		if unit != "" {
+			return "must be at most " + bound + unit
+		}
+		return "must be at most " + bound
+	}
+	return ""
+}
+
+// isBlank reports whether a required value is missing. Whitespace-only
+// strings count as missing.
+func isBlank(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+func jsonName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}
+
+// jsonKind names a Go type the way a JSON client would think of it
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	}
+	return "object"
+}
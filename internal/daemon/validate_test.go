@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type validateNested struct {
+	Path string `json:"path" validate:"required"`
+}
+
+type validateSample struct {
+	Name    string           `json:"name" validate:"required"`
+	Mode    string           `json:"mode,omitempty" validate:"oneof=fast slow"`
+	Reason  string           `json:"reason,omitempty" validate:"min=5,max=10"`
+	Count   *int             `json:"count,omitempty" validate:"min=0"`
+	Tags    []string         `json:"tags" validate:"required"`
+	Targets []validateNested `json:"targets,omitempty"`
+}
+
+func TestValidateRequest(t *testing.T) {
+	neg := -1
+	tests := []struct {
+		name   string
+		req    validateSample
+		fields []string
+	}{
+		{"valid", validateSample{Name: "x", Tags: []string{"a"}}, nil},
+		{"missing required", validateSample{Name: "  "}, []string{"name", "tags"}},
+		{"oneof", validateSample{Name: "x", Tags: []string{"a"}, Mode: "medium"}, []string{"mode"}},
+		{"too short", validateSample{Name: "x", Tags: []string{"a"}, Reason: "abc"}, []string{"reason"}},
+		{"too long", validateSample{Name: "x", Tags: []string{"a"}, Reason: "abcdefghijk"}, []string{"reason"}},
+		{"negative pointer", validateSample{Name: "x", Tags: []string{"a"}, Count: &neg}, []string{"count"}},
+		{"nested", validateSample{Name: "x", Tags: []string{"a"}, Targets: []validateNested{{"ok"}, {""}}}, []string{"targets[1].path"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRequest(&tt.req)
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected errors for %v", tt.fields)
+			}
+			if len(err.Fields) != len(tt.fields) {
+				t.Fatalf("got %+v, want fields %v", err.Fields, tt.fields)
+			}
+			for i, f := range err.Fields {
+				if f.Field != tt.fields[i] {
+					t.Errorf("field %d = %q, want %q", i, f.Field, tt.fields[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeRequest_Envelope(t *testing.T) {
+	s := &Server{}
+
+	tests := []struct {
+		name   string
+		body   string
+		fields []string
+	}{
+		{"every invalid field", `{"mode":"medium"}`, []string{"name", "mode", "tags"}},
+		{"empty body", ``, []string{"name", "tags"}},
+		{"wrong type", `{"name": 3}`, []string{"name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			var req validateSample
+			if s.decodeRequest(w, r, &req) {
+				t.Fatal("expected decodeRequest to fail")
+			}
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", w.Code)
+			}
+			var resp struct {
+				ErrorCode string       `json:"error_code"`
+				Fields    []FieldError `json:"fields"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if resp.ErrorCode != ErrCodeValidationFailed {
+				t.Errorf("error_code = %q, want %q", resp.ErrorCode, ErrCodeValidationFailed)
+			}
+			if len(resp.Fields) != len(tt.fields) {
+				t.Fatalf("fields = %+v, want %v", resp.Fields, tt.fields)
+			}
+			for i, f := range resp.Fields {
+				if f.Field != tt.fields[i] {
+					t.Errorf("field %d = %q, want %q", i, f.Field, tt.fields[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeRequest_MalformedJSON(t *testing.T) {
+	s := &Server{}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":`))
+	w := httptest.NewRecorder()
+
+	var req validateSample
+	if s.decodeRequest(w, r, &req) {
+		t.Fatal("expected decodeRequest to fail")
+	}
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid request body") {
+		t.Errorf("got %d %s", w.Code, w.Body.String())
+	}
+}
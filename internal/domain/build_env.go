@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// BuildEnv is what an exercise or session adds to the runner's go build
+// and go test invocations: environment variables, build tags and extra
+// test flags. Only allowlisted settings are accepted, so an exercise can
+// teach build constraints, time zones or feature flags without reaching
+// the toolchain, the module proxy or the sandbox.
+type BuildEnv struct {
+	Env       map[string]string `json:"env,omitempty"`
+	BuildTags []string          `json:"build_tags,omitempty"`
+	TestFlags []string          `json:"test_flags,omitempty"`
+}
+
+// allowedEnv are the environment variables a BuildEnv may set besides the
+// APP_ prefixed ones, which are free for exercises to use as feature flags
+var allowedEnv = map[string]bool{
+	"TZ": true, "LANG": true, "LC_ALL": true,
+	"GODEBUG": true, "GOEXPERIMENT": true, "GOMAXPROCS": true, "GORACE": true,
+	"CGO_ENABLED": true,
+}
+
+// appEnvPrefix marks environment variables owned by the exercise itself
+const appEnvPrefix = "APP_"
+
+// allowedTestFlags are the go test flags a BuildEnv may add. Flags that
+// write files, change the build or set the timeout stay with the runner.
+var allowedTestFlags = map[string]bool{
+	"-v": true, "-race": true, "-short": true, "-failfast": true, "-benchmem": true,
+	"-run": true, "-skip": true, "-bench": true, "-benchtime": true,
+	"-count": true, "-cpu": true, "-parallel": true, "-shuffle": true,
+}
+
+var (
+	envNameRegex  = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	buildTagRegex = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+)
+
+// IsZero reports whether b adds nothing to a run
+func (b BuildEnv) IsZero() bool {
+	return len(b.Env) == 0 && len(b.BuildTags) == 0 && len(b.TestFlags) == 0
+}
+
+// Validate checks every setting against the allowlist
+func (b BuildEnv) Validate() error {
+	for name, value := range b.Env {
+		if !envNameRegex.MatchString(name) {
+			return fmt.Errorf("env %q is not a valid variable name", name)
+		}
+		if !allowedEnv[name] && !strings.HasPrefix(name, appEnvPrefix) {
+			return fmt.Errorf("env %s is not allowed (use %s, or an %s prefixed name)",
+				name, strings.Join(sortedKeys(allowedEnv), ", "), appEnvPrefix)
+		}
+		if strings.ContainsAny(value, "\x00\n") {
+			return fmt.Errorf("env %s has a newline or NUL in its value", name)
+		}
+	}
+	for _, tag := range b.BuildTags {
+		if !buildTagRegex.MatchString(tag) {
+			return fmt.Errorf("build tag %q may only hold letters, digits, _ and .", tag)
+		}
+	}
+	for _, flag := range b.TestFlags {
+		name, value, hasValue := strings.Cut(flag, "=")
+		if !allowedTestFlags[name] {
+			return fmt.Errorf("test flag %s is not allowed (use %s)", name, strings.Join(sortedKeys(allowedTestFlags), " "))
+		}
+		if hasValue && (value == "" || strings.ContainsAny(value, " \t\n\x00")) {
+			return fmt.Errorf("test flag %s needs a value without spaces", name)
+		}
+	}
+	return nil
+}
+
+// Merge returns b with over layered on top: over's variables win, tags
+// are combined without duplicates and test flags are appended
+func (b BuildEnv) Merge(over BuildEnv) BuildEnv {
+	if over.IsZero() {
+		return b
+	}
+	merged := BuildEnv{}
+	if len(b.Env)+len(over.Env) > 0 {
+		merged.Env = make(map[string]string, len(b.Env)+len(over.Env))
+		for k, v := range b.Env {
+			merged.Env[k] = v
+		}
+		for k, v := range over.Env {
+			merged.Env[k] = v
+		}
+	}
+	seen := make(map[string]bool)
+	for _, tag := range append(append([]string(nil), b.BuildTags...), over.BuildTags...) {
+		if !seen[tag] {
+			seen[tag] = true
+			merged.BuildTags = append(merged.BuildTags, tag)
+		}
+	}
+	merged.TestFlags = append(append([]string(nil), b.TestFlags...), over.TestFlags...)
+	return merged
+}
+
+// EnvList returns the variables as sorted NAME=value pairs
+func (b BuildEnv) EnvList() []string {
+	list := make([]string, 0, len(b.Env))
+	for _, name := range sortedKeys(b.Env) {
+		list = append(list, name+"="+b.Env[name])
+	}
+	return list
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildEnv_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     BuildEnv
+		wantErr bool
+	}{
+		{"empty", BuildEnv{}, false},
+		{"allowed", BuildEnv{
+			Env:       map[string]string{"TZ": "America/New_York", "GODEBUG": "http2client=0", "APP_NEW_CHECKOUT": "1"},
+			BuildTags: []string{"integration", "go1.22"},
+			TestFlags: []string{"-race", "-count=1", "-run=TestParse"},
+		}, false},
+		{"toolchain variable", BuildEnv{Env: map[string]string{"GOFLAGS": "-mod=mod"}}, true},
+		{"lowercase variable", BuildEnv{Env: map[string]string{"tz": "UTC"}}, true},
+		{"newline in value", BuildEnv{Env: map[string]string{"TZ": "UTC\nPATH=/tmp"}}, true},
+		{"tag with comma", BuildEnv{BuildTags: []string{"a,b"}}, true},
+		{"tag with space", BuildEnv{BuildTags: []string{"a b"}}, true},
+		{"file writing flag", BuildEnv{TestFlags: []string{"-coverprofile=c.out"}}, true},
+		{"exec flag", BuildEnv{TestFlags: []string{"-exec=sh"}}, true},
+		{"empty value", BuildEnv{TestFlags: []string{"-run="}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.env.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildEnv_Merge(t *testing.T) {
+	base := BuildEnv{
+		Env:       map[string]string{"TZ": "UTC", "APP_A": "1"},
+		BuildTags: []string{"integration"},
+		TestFlags: []string{"-short"},
+	}
+	merged := base.Merge(BuildEnv{
+		Env:       map[string]string{"TZ": "Asia/Tokyo"},
+		BuildTags: []string{"integration", "slow"},
+		TestFlags: []string{"-count=1"},
+	})
+
+	want := BuildEnv{
+		Env:       map[string]string{"TZ": "Asia/Tokyo", "APP_A": "1"},
+		BuildTags: []string{"integration", "slow"},
+		TestFlags: []string{"-short", "-count=1"},
+	}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("Merge() = %+v, want %+v", merged, want)
+	}
+	if base.Env["TZ"] != "UTC" {
+		t.Error("Merge() must not modify the receiver")
+	}
+	if got := merged.EnvList(); !reflect.DeepEqual(got, []string{"APP_A=1", "TZ=Asia/Tokyo"}) {
+		t.Errorf("EnvList() = %v", got)
+	}
+	if !(BuildEnv{}).IsZero() || merged.IsZero() {
+		t.Error("IsZero() is wrong")
+	}
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"path"
+	"strings"
+)
+
+// ExerciseType distinguishes the shape of an exercise
+type ExerciseType string
+
+const (
+	// ExerciseTypeImplement is the default: the learner builds from a starter
+	ExerciseTypeImplement ExerciseType = "implement"
+	// ExerciseTypeDebugging ships intentionally broken starter code; the
+	// learner must find and explain the bug as well as fix it
+	ExerciseTypeDebugging ExerciseType = "debugging"
+)
+
+// DebuggingSpec describes the bugs injected into a debugging exercise.
+// Everything except Narrative is author-only: it must never reach the
+// learner (hence json:"-"), and only reaches the LLM at L4 and above.
+type DebuggingSpec struct {
+	Narrative string        `json:"narrative"` // failure report shown to the learner
+	Bugs      []InjectedBug `json:"-"`         // documented defects in the starter code
+}
+
+// InjectedBug documents a single intentional defect
+type InjectedBug struct {
+	ID        string
+	File      string   // file containing the defect
+	Function  string   // optional: enclosing function
+	RootCause string   // author's explanation of the defect
+	Keywords  []string // any of these in an answer counts as naming the cause
+}
+
+// RootCauseAnswer is the learner's structured explanation of a bug
+type RootCauseAnswer struct {
+	File        string `json:"file"`
+	Function    string `json:"function,omitempty"`
+	Explanation string `json:"explanation"`
+}
+
+// RootCauseVerdict reports which injected bugs an answer set identified
+type RootCauseVerdict struct {
+	Identified bool     `json:"identified"`
+	Found      []string `json:"found"`
+	Missing    int      `json:"missing"`
+}
+
+// IsDebugging returns true for debugging exercises
+func (e *Exercise) IsDebugging() bool {
+	return e.Type == ExerciseTypeDebugging && e.Debugging != nil
+}
+
+// EvaluateRootCause matches the learner's answers against the injected
+// bugs. A bug counts as identified when some answer names its file (and
+// function, if both sides give one) and the explanation mentions one of
+// the bug's keywords. Which bugs are missing is deliberately not exposed.
+func (d *DebuggingSpec) EvaluateRootCause(answers []RootCauseAnswer) RootCauseVerdict {
+	verdict := RootCauseVerdict{Found: []string{}}
+	for _, bug := range d.Bugs {
+		matched := false
+		for _, a := range answers {
+			if bug.matches(a) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			verdict.Found = append(verdict.Found, bug.ID)
+		} else {
+			verdict.Missing++
+		}
+	}
+	verdict.Identified = len(d.Bugs) > 0 && verdict.Missing == 0
+	return verdict
+}
+
+func (b InjectedBug) matches(a RootCauseAnswer) bool {
+	if path.Base(strings.TrimSpace(a.File)) != path.Base(b.File) {
+		return false
+	}
+	if b.Function != "" && a.Function != "" && !strings.EqualFold(strings.TrimSpace(a.Function), b.Function) {
+		return false
+	}
+	explanation := strings.ToLower(a.Explanation)
+	for _, kw := range b.Keywords {
+		if kw != "" && strings.Contains(explanation, strings.ToLower(kw)) {
+			return true
+		}
+	}
+	return false
+}
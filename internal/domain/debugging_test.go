@@ -0,0 +1,74 @@
+package domain
+
+import "testing"
+
+func TestDebuggingSpec_EvaluateRootCause(t *testing.T) {
+	spec := &DebuggingSpec{
+		Bugs: []InjectedBug{
+			{ID: "count", File: "main.go", Function: "PageCount", Keywords: []string{"round up", "integer division"}},
+			{ID: "bounds", File: "page.go", Keywords: []string{"off by one"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		answers    []RootCauseAnswer
+		identified bool
+		found      int
+	}{
+		{
+			name: "all bugs identified",
+			answers: []RootCauseAnswer{
+				{File: "main.go", Function: "PageCount", Explanation: "Integer division drops the last page"},
+				{File: "./page.go", Explanation: "Classic off by one in the bounds check"},
+			},
+			identified: true,
+			found:      2,
+		},
+		{
+			name: "one bug missing",
+			answers: []RootCauseAnswer{
+				{File: "main.go", Explanation: "needs to round up"},
+			},
+			found: 1,
+		},
+		{
+			name: "right file wrong explanation",
+			answers: []RootCauseAnswer{
+				{File: "main.go", Explanation: "something is broken"},
+				{File: "page.go", Explanation: "no idea"},
+			},
+		},
+		{
+			name: "wrong function",
+			answers: []RootCauseAnswer{
+				{File: "main.go", Function: "Page", Explanation: "integer division"},
+			},
+		},
+		{
+			name: "no answers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := spec.EvaluateRootCause(tt.answers)
+			if v.Identified != tt.identified {
+				t.Errorf("Identified = %v; want %v", v.Identified, tt.identified)
+			}
+			if len(v.Found) != tt.found {
+				t.Errorf("Found = %v; want %d bugs", v.Found, tt.found)
+			}
+			if v.Missing != len(spec.Bugs)-tt.found {
+				t.Errorf("Missing = %d; want %d", v.Missing, len(spec.Bugs)-tt.found)
+			}
+		})
+	}
+}
+
+func TestDebuggingSpec_EvaluateRootCause_NoBugs(t *testing.T) {
+	v := (&DebuggingSpec{}).EvaluateRootCause([]RootCauseAnswer{{File: "main.go", Explanation: "x"}})
+	if v.Identified {
+		t.Error("spec without bugs should never be identified")
+	}
+}
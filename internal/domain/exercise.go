@@ -1,5 +1,13 @@
 package domain
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // Exercise represents a structured learning task
 type Exercise struct {
 	ID            string // slug: "go-v1/basics/hello-world"
@@ -13,8 +21,17 @@ type Exercise struct {
 	Rubric        Rubric
 	CheckRecipe   CheckRecipe
 	Tags          []string
-	Prerequisites []string // other exercise IDs
-	Hints         HintSet  // hints organized by level
+	Prerequisites []string          // other exercise IDs
+	Hints         HintSet           // hints organized by level
+	HintLadder    HintLadder        // authored hints served instead of generated ones
+	Solution      map[string]string `json:"-"` // reference solution; for grading and L5 only, never serialized
+	Alternatives  []Alternative     `json:"-"` // other notable solutions, compared with the learner's after completion
+	Type          ExerciseType
+	Debugging     *DebuggingSpec // set for debugging exercises only
+	Version       string         // ContentHash at load time
+
+	Variants map[VariantKind]*ExerciseVariant `json:"-"` // author-written harder/easier versions
+	Variant  VariantKind                      // the variant applied, "" for as written
 }
 
 // Difficulty represents exercise difficulty level
@@ -47,6 +64,40 @@ type CheckRecipe struct {
 	Test      bool     // run go test
 	TestFlags []string // e.g., ["-v", "-race"]
 	Timeout   int      // seconds
+	Debug     bool     // on test failure, rerun under a debugger to capture state
+
+	Env       map[string]string // allowlisted variables, e.g. {"TZ": "Asia/Tokyo"}
+	BuildTags []string          // passed to go build and go test as -tags
+
+	// StdinCases run the program with scripted input alongside the tests,
+	// for exercises on reading from stdin
+	StdinCases []StdinCase
+
+	// Server, when set, runs the program as a server after the tests and
+	// checks it with HTTP probes
+	Server *ServerCheck
+
+	// Fuzz, when set, fuzzes one target for a bounded time once the
+	// tests pass
+	Fuzz *FuzzCheck
+
+	// Artifacts are files runs produce for download: the built program,
+	// a coverage report or generated code
+	Artifacts []ArtifactSpec
+}
+
+// StdinCase is one run of an exercise's program: the input it reads from
+// stdin and the output it must print
+type StdinCase struct {
+	Name           string
+	Input          string
+	ExpectedOutput string
+}
+
+// BuildEnv returns what the recipe adds to go build and go test. The
+// recipe's test flags stay with the check recipe itself.
+func (r CheckRecipe) BuildEnv() BuildEnv {
+	return BuildEnv{Env: r.Env, BuildTags: r.BuildTags}
 }
 
 // HintSet contains hints organized by intervention level
@@ -57,17 +108,78 @@ type HintSet struct {
 	L3 []string // constrained snippets
 }
 
+// HintLadder holds the hints a pack author wrote for L1 to L3. Where a
+// level has one, learners get it as written and the LLM isn't asked;
+// above the ladder's top, hints are generated as usual.
+type HintLadder struct {
+	L1 string
+	L2 string
+	L3 string
+}
+
+// Step returns the authored hint for level, if there is one
+func (l HintLadder) Step(level InterventionLevel) (string, bool) {
+	var hint string
+	switch level {
+	case L1CategoryHint:
+		hint = l.L1
+	case L2LocationConcept:
+		hint = l.L2
+	case L3ConstrainedSnippet:
+		hint = l.L3
+	}
+	return hint, hint != ""
+}
+
+// Alternative is a notable way to solve an exercise other than the
+// reference solution, such as recursion instead of a loop
+type Alternative struct {
+	Name        string
+	Description string
+	Files       map[string]string // filename -> content
+}
+
 // ExercisePack represents a collection of related exercises
 type ExercisePack struct {
-	ID            string
+	ID            string // "go-v1", or "acme/go-tools" for a pack published under an org
 	Name          string
 	Version       string
 	Description   string
 	Language      string
+	GoVersion     string // Go toolchain the pack's exercises need; "" = the runner's default
+	Formatter     string // Go formatter for the format endpoint; "" = goimports
 	DefaultPolicy LearningPolicy
 	ExerciseIDs   []string // ordered list of exercise slugs
 }
 
+// SplitExerciseID splits a fully-qualified exercise ID into its pack ID
+// and its category/slug. A slug always has two elements, so whatever
+// comes before them names the pack, with or without an org:
+// "go-v1/basics/hello-world" is in pack go-v1, and
+// "acme/go-tools/basics/hello-world" in pack acme/go-tools.
+func SplitExerciseID(id string) (packID, slug string, ok bool) {
+	parts := strings.Split(id, "/")
+	if len(parts) < 3 || len(parts) > 4 {
+		return "", "", false
+	}
+	for _, p := range parts {
+		if p == "" {
+			return "", "", false
+		}
+	}
+	n := len(parts) - 2
+	return strings.Join(parts[:n], "/"), strings.Join(parts[n:], "/"), true
+}
+
+// PackName returns a pack ID without its org: "acme/go-tools" is named
+// go-tools
+func PackName(packID string) string {
+	if i := strings.LastIndex(packID, "/"); i >= 0 {
+		return packID[i+1:]
+	}
+	return packID
+}
+
 // GetHintsForLevel returns hints for the specified intervention level
 func (e *Exercise) GetHintsForLevel(level InterventionLevel) []string {
 	switch level {
@@ -95,3 +207,24 @@ func (e *Exercise) AllFiles() map[string]string {
 	}
 	return files
 }
+
+// ContentHash versions the exercise by the files a session copies: any
+// change to its starter or test code yields a new hash. Edits to hints or
+// the description leave it alone.
+func (e *Exercise) ContentHash() string {
+	h := sha256.New()
+	for _, part := range []struct {
+		kind  string
+		files map[string]string
+	}{{"starter", e.StarterCode}, {"test", e.TestCode}} {
+		names := make([]string, 0, len(part.files))
+		for name := range part.files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(h, "%s\x00%s\x00%s\n", part.kind, name, part.files[name])
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
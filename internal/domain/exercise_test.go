@@ -209,3 +209,73 @@ func TestExercisePack_Struct(t *testing.T) {
 		t.Errorf("ExerciseIDs len = %d, want 2", len(pack.ExerciseIDs))
 	}
 }
+
+func TestSplitExerciseID(t *testing.T) {
+	tests := []struct {
+		id, pack, slug string
+		ok             bool
+	}{
+		{"go-v1/basics/hello-world", "go-v1", "basics/hello-world", true},
+		{"acme/go-tools/basics/hello-world", "acme/go-tools", "basics/hello-world", true},
+		{"go-v1/hello", "", "", false},
+		{"a/b/c/d/e", "", "", false},
+		{"go-v1//hello", "", "", false},
+		{"", "", "", false},
+	}
+	for _, tt := range tests {
+		pack, slug, ok := SplitExerciseID(tt.id)
+		if pack != tt.pack || slug != tt.slug || ok != tt.ok {
+			t.Errorf("SplitExerciseID(%q) = %q, %q, %v; want %q, %q, %v", tt.id, pack, slug, ok, tt.pack, tt.slug, tt.ok)
+		}
+	}
+	if got := PackName("acme/go-tools"); got != "go-tools" {
+		t.Errorf("PackName() = %q, want go-tools", got)
+	}
+	if got := PackName("go-v1"); got != "go-v1" {
+		t.Errorf("PackName() = %q, want go-v1", got)
+	}
+}
+
+func TestExercise_ContentHash(t *testing.T) {
+	ex := &Exercise{
+		StarterCode: map[string]string{"main.go": "package main", "util.go": "package main"},
+		TestCode:    map[string]string{"main_test.go": "package main"},
+	}
+	hash := ex.ContentHash()
+	if len(hash) != 16 {
+		t.Fatalf("ContentHash() = %q; want 16 hex characters", hash)
+	}
+
+	ex.Hints = HintSet{L0: []string{"a new hint"}}
+	ex.Description = "reworded"
+	if ex.ContentHash() != hash {
+		t.Error("hints and description should not change the version")
+	}
+
+	// Moving a file between starter and tests is a change
+	moved := &Exercise{
+		StarterCode: map[string]string{"main.go": "package main"},
+		TestCode:    map[string]string{"main_test.go": "package main", "util.go": "package main"},
+	}
+	if moved.ContentHash() == hash {
+		t.Error("moving a file from starter to tests should change the version")
+	}
+
+	ex.TestCode["main_test.go"] = "package main_test"
+	if ex.ContentHash() == hash {
+		t.Error("changing a test should change the version")
+	}
+}
+
+func TestHintLadder_Step(t *testing.T) {
+	ladder := HintLadder{L1: "direction", L3: "shape"}
+	if hint, ok := ladder.Step(L1CategoryHint); !ok || hint != "direction" {
+		t.Errorf("Step(L1) = %q, %v; want direction", hint, ok)
+	}
+	if _, ok := ladder.Step(L2LocationConcept); ok {
+		t.Error("Step(L2) found a hint the author didn't write")
+	}
+	if _, ok := ladder.Step(L4PartialSolution); ok {
+		t.Error("the ladder stops at L3")
+	}
+}
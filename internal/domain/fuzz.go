@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Limits on a FuzzCheck's time budget
+const (
+	DefaultFuzzTime = 10 * time.Second
+	MaxFuzzTime     = time.Minute
+)
+
+var fuzzTargetRegex = regexp.MustCompile(`^Fuzz[A-Za-z0-9_]*$`)
+
+// FuzzCheck runs one fuzz target with go test -fuzz for a bounded time
+// after the tests pass
+type FuzzCheck struct {
+	Target string        // e.g. FuzzParseDuration
+	Time   time.Duration // fuzzing budget
+}
+
+// WithDefaults fills in the time budget left out
+func (c FuzzCheck) WithDefaults() FuzzCheck {
+	if c.Time == 0 {
+		c.Time = DefaultFuzzTime
+	}
+	return c
+}
+
+// Validate checks the target name and the time budget
+func (c FuzzCheck) Validate() error {
+	if !fuzzTargetRegex.MatchString(c.Target) {
+		return fmt.Errorf("fuzz target %q must name a Fuzz function", c.Target)
+	}
+	if c.Time <= 0 || c.Time > MaxFuzzTime {
+		return fmt.Errorf("fuzz time %s must be between 1s and %s", c.Time, MaxFuzzTime)
+	}
+	return nil
+}
+
+// FuzzCrash is an input the fuzzer found that fails the target, as saved
+// to the package's corpus
+type FuzzCrash struct {
+	Target     string `json:"target"`
+	CorpusFile string `json:"corpus_file"` // e.g. testdata/fuzz/FuzzParse/8a3c...
+	Input      string `json:"input"`       // the corpus file's contents
+	Message    string `json:"message"`     // how the target failed
+}
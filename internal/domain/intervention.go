@@ -15,11 +15,49 @@ type Intervention struct {
 	Intent      Intent
 	Level       InterventionLevel
 	Type        InterventionType
-	Content     string   // the actual guidance text
-	Targets     []Target // file/line targets
-	Rationale   string   // selector reasoning, surfaced via --why
+	Content     string      // the actual guidance text
+	Targets     []Target    // file/line targets
+	Rationale   string      // selector reasoning, surfaced via --why
+	Redactions  []Redaction // what was scrubbed from the prompt before it was sent
+	Concepts    []string    // glossary concept IDs the content touches on
 	RequestedAt time.Time
 	DeliveredAt time.Time
+
+	// Contract explains which learning-contract rule set Level
+	Contract LevelRationale
+}
+
+// Contract rules that can decide an intervention's level
+const (
+	RuleContext    = "context"    // the level the request and context called for, within every cap
+	RuleLevelCap   = "level_cap"  // the track's maximum level
+	RuleTopicCap   = "topic_cap"  // the cap one level lower in a topic the learner is strong in
+	RuleTestFirst  = "test_first" // implementation hints held back until a failing test exists
+	RuleEscalation = "escalation" // an explicit, justified request for L4/L5
+	RuleCooldown   = "cooldown"   // detailed help requested again too soon
+	RuleBudget     = "budget"     // the session's hint budget can't pay for more detail
+)
+
+// LevelRationale explains which learning-contract rule set an
+// intervention's level, so clients can answer "why only a hint?"
+type LevelRationale struct {
+	Rule      string            `json:"rule"`
+	Summary   string            `json:"summary"`         // one sentence for the learner
+	Requested InterventionLevel `json:"requested_level"` // before the contract applied
+	Level     InterventionLevel `json:"level"`
+	MaxLevel  InterventionLevel `json:"max_level"`
+	Track     string            `json:"track,omitempty"`
+	Details   string            `json:"details,omitempty"` // the signals behind the requested level
+
+	CooldownRemaining float64 `json:"cooldown_remaining,omitempty"` // seconds, for the cooldown rule
+	BudgetRemaining   *int    `json:"budget_remaining,omitempty"`   // tokens left before this hint, when the session has a budget
+}
+
+// Redaction counts the values of one kind scrubbed from an LLM prompt.
+// The values themselves are never recorded.
+type Redaction struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
 }
 
 // Intent represents what the user is asking for
@@ -110,6 +148,89 @@ type LearningPolicy struct {
 	PatchingEnabled bool              // whether code patches are allowed
 	CooldownSeconds int               // minimum time between L3+ interventions
 	Track           string            // "practice", "interview-prep"
+
+	// TestFirst holds back implementation hints in feature sessions until
+	// a failing test references the feature being built
+	TestFirst bool
+
+	// Stuck tunes when the session nudges a learner who seems stuck
+	Stuck StuckPolicy
+
+	// Budget limits how much help a session can draw on
+	Budget HintBudget
+}
+
+// Stuck detection defaults, used where a StuckPolicy leaves a field zero
+const (
+	DefaultStuckFailingRuns = 5
+	DefaultStuckIdleSeconds = 600
+)
+
+// StuckPolicy tunes automatic stuck detection. Zero values use the
+// defaults; Disabled turns detection off.
+type StuckPolicy struct {
+	Disabled    bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	FailingRuns int  `json:"failing_runs,omitempty" yaml:"failing_runs,omitempty"` // consecutive failing runs with only small changes
+	IdleSeconds int  `json:"idle_seconds,omitempty" yaml:"idle_seconds,omitempty"` // quiet time after a failing run
+}
+
+// WithDefaults fills in the zero fields
+func (p StuckPolicy) WithDefaults() StuckPolicy {
+	if p.FailingRuns <= 0 {
+		p.FailingRuns = DefaultStuckFailingRuns
+	}
+	if p.IdleSeconds <= 0 {
+		p.IdleSeconds = DefaultStuckIdleSeconds
+	}
+	return p
+}
+
+// DefaultHintCosts is what a hint at each level, L0 to L5, takes from a
+// hint budget: clarifying questions are free, full solutions cost most
+var DefaultHintCosts = []int{0, 1, 1, 2, 3, 5}
+
+// HintBudget gives a session a number of tokens to spend on hints.
+// Zero Tokens means no budget: help is limited only by the other rules.
+type HintBudget struct {
+	Tokens int   `json:"tokens,omitempty" yaml:"tokens,omitempty"`
+	Costs  []int `json:"costs,omitempty" yaml:"costs,omitempty"` // per level from L0; DefaultHintCosts when empty
+}
+
+// Enabled reports whether the budget limits the session
+func (b HintBudget) Enabled() bool {
+	return b.Tokens > 0
+}
+
+// Cost returns what a hint at level takes from the budget. Levels past
+// the end of Costs cost as much as the last one.
+func (b HintBudget) Cost(level InterventionLevel) int {
+	costs := b.Costs
+	if len(costs) == 0 {
+		costs = DefaultHintCosts
+	}
+	if int(level) < len(costs) {
+		return costs[level]
+	}
+	return costs[len(costs)-1]
+}
+
+// Affordable returns the highest level at or below level that remaining
+// tokens pay for, and false when not even L0 is affordable
+func (b HintBudget) Affordable(level InterventionLevel, remaining int) (InterventionLevel, bool) {
+	if !b.Enabled() {
+		return level, true
+	}
+	for ; level > L0Clarify; level-- {
+		if b.Cost(level) <= remaining {
+			return level, true
+		}
+	}
+	return L0Clarify, b.Cost(L0Clarify) <= remaining
+}
+
+// Remaining returns the tokens left after spent
+func (b HintBudget) Remaining(spent int) int {
+	return max(b.Tokens-spent, 0)
 }
 
 // DefaultPolicy returns the default learning policy for practice mode
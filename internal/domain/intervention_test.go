@@ -99,3 +99,29 @@ func TestLearningPolicy_ClampLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestHintBudget(t *testing.T) {
+	var none HintBudget
+	if level, ok := none.Affordable(L5FullSolution, 0); !ok || level != L5FullSolution {
+		t.Errorf("no budget should afford anything, got L%d, %v", level, ok)
+	}
+
+	b := HintBudget{Tokens: 5}
+	if b.Cost(L0Clarify) != 0 || b.Cost(L3ConstrainedSnippet) != 2 || b.Cost(L5FullSolution) != 5 {
+		t.Errorf("default costs = %v", DefaultHintCosts)
+	}
+	if level, ok := b.Affordable(L4PartialSolution, 2); !ok || level != L3ConstrainedSnippet {
+		t.Errorf("Affordable(L4, 2) = L%d, %v; want L3", level, ok)
+	}
+	if b.Remaining(7) != 0 {
+		t.Errorf("Remaining(7) = %d, want 0", b.Remaining(7))
+	}
+
+	short := HintBudget{Tokens: 4, Costs: []int{1, 2}}
+	if short.Cost(L4PartialSolution) != 2 {
+		t.Errorf("levels past the costs should cost the last one, got %d", short.Cost(L4PartialSolution))
+	}
+	if _, ok := short.Affordable(L2LocationConcept, 0); ok {
+		t.Error("nothing should be affordable with no tokens and a paid L0")
+	}
+}
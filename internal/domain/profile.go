@@ -132,3 +132,22 @@ func (s *ProfileSignals) NeedsIntervention() bool {
 		s.TimeOnExercise > 10*time.Minute ||
 		len(s.ErrorsEncountered) > 3
 }
+
+// PackSummary synthesizes a learner's reviews and errors across one
+// exercise pack into strengths and gaps
+type PackSummary struct {
+	Pack        string        `json:"pack"`
+	Attempted   int           `json:"attempted"` // exercises with at least one session
+	Completed   int           `json:"completed"`
+	Summary     string        `json:"summary,omitempty"`
+	Strengths   []PackFinding `json:"strengths"`
+	Gaps        []PackFinding `json:"gaps"`
+	GeneratedAt time.Time     `json:"generated_at"`
+}
+
+// PackFinding is one strength or gap and the exercises that show it
+type PackFinding struct {
+	Topic     string   `json:"topic"`
+	Message   string   `json:"message"`
+	Exercises []string `json:"exercises,omitempty"`
+}
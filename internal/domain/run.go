@@ -33,19 +33,44 @@ const (
 
 // RunOutput contains the results of a code execution
 type RunOutput struct {
-	FormatOK    bool          `json:"format_passed"` // gofmt passed
-	FormatDiff  string        `json:"format_output"` // gofmt diff output (if any changes needed)
-	BuildOK     bool          `json:"build_passed"`  // go build passed
-	BuildOutput string        `json:"build_output"`  // build error output
-	BuildErrors []Diagnostic  `json:"build_errors"`  // compilation errors
-	TestOK      bool          `json:"test_passed"`   // all tests passed
-	TestOutput  string        `json:"test_output"`   // test output
-	TestResults []TestResult  `json:"test_results"`  // individual test results
-	TestsPassed int           `json:"tests_passed"`  // count of passing tests
-	TestsFailed int           `json:"tests_failed"`  // count of failing tests
-	Duration    time.Duration `json:"duration"`      // total execution time
-	Logs        string        `json:"logs"`          // full output logs
-	Risks       []RiskNotice  `json:"risks"`         // detected risky patterns
+	FormatOK    bool           `json:"format_passed"`   // gofmt passed
+	FormatDiff  string         `json:"format_output"`   // gofmt diff output (if any changes needed)
+	BuildOK     bool           `json:"build_passed"`    // go build passed
+	BuildOutput string         `json:"build_output"`    // build error output
+	BuildErrors []Diagnostic   `json:"build_errors"`    // compilation errors
+	TestOK      bool           `json:"test_passed"`     // all tests passed
+	TestOutput  string         `json:"test_output"`     // test output
+	TestResults []TestResult   `json:"test_results"`    // individual test results
+	TestsPassed int            `json:"tests_passed"`    // count of passing tests
+	TestsFailed int            `json:"tests_failed"`    // count of failing tests
+	Duration    time.Duration  `json:"duration"`        // total execution time
+	Logs        string         `json:"logs"`            // full output logs
+	Risks       []RiskNotice   `json:"risks"`           // detected risky patterns
+	Debug       *DebugSnapshot `json:"debug,omitempty"` // debugger state at the failure point
+
+	// An input the fuzzer found that fails the exercise's fuzz target
+	FuzzCrash *FuzzCrash `json:"fuzz_crash,omitempty"`
+}
+
+// DebugSnapshot is the program state a debugger captured where a test
+// failed or panicked
+type DebugSnapshot struct {
+	Reason string          `json:"reason"` // "panic" or "test_failure"
+	Frames []StackFrame    `json:"frames"` // innermost first
+	Locals []DebugVariable `json:"locals"` // variables of the innermost learner frame
+}
+
+// StackFrame is a single frame of a captured stack
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// DebugVariable is a local variable or argument and its rendered value
+type DebugVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 // RiskNotice represents a detected risky pattern in code
@@ -88,6 +113,63 @@ type Diagnostic struct {
 	Message  string `json:"message"`
 }
 
+// Kinds of quick fix
+const (
+	QuickFixAddImport     = "add_import"
+	QuickFixRemoveImport  = "remove_import"
+	QuickFixBlankVariable = "blank_variable"
+)
+
+// QuickFix is a mechanical fix for a compile error that an editor can
+// apply in one click: no LLM involved, and nothing the learner has to
+// work out for themselves.
+type QuickFix struct {
+	Kind    string     `json:"kind"`
+	Title   string     `json:"title"` // what the fix does: Add import "strings"
+	File    string     `json:"file"`
+	Line    int        `json:"line"`    // of the error it fixes
+	Message string     `json:"message"` // the compiler's
+	Edits   []TextEdit `json:"edits"`
+}
+
+// TextEdit replaces the text between two positions of a file. Lines and
+// columns start at 1 and columns count bytes, as the Go compiler reports
+// them; an empty range inserts and an empty NewText deletes.
+type TextEdit struct {
+	StartLine   int    `json:"start_line"`
+	StartColumn int    `json:"start_column"`
+	EndLine     int    `json:"end_line"`
+	EndColumn   int    `json:"end_column"`
+	NewText     string `json:"new_text"`
+}
+
+// Where an error explanation came from
+const (
+	ExplanationRule = "rule" // the offline rule table
+	ExplanationLLM  = "llm"  // the LLM, for errors no rule covers
+)
+
+// ErrorExplanation restates a compiler or test error in beginner-friendly
+// language. The raw output stays in the run; this sits alongside it.
+type ErrorExplanation struct {
+	File        string `json:"file,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	Original    string `json:"original"`
+	Explanation string `json:"explanation"`
+	Source      string `json:"source"`         // rule or llm
+	Rule        string `json:"rule,omitempty"` // which rule matched
+}
+
+// TestExplanation is why one test of a run failed, with the learner's
+// functions the explanation followed the test into
+type TestExplanation struct {
+	Test        string    `json:"test"`
+	Package     string    `json:"package,omitempty"`
+	Explanation string    `json:"explanation"`
+	Functions   []string  `json:"functions,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 // TestResult represents the outcome of a single test
 type TestResult struct {
 	Package  string        `json:"package"`
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Artifact kinds
+const (
+	ArtifactBinary   = "binary"   // the program, built with go build -o
+	ArtifactCoverage = "coverage" // test coverage as HTML, from go tool cover
+	ArtifactFile     = "file"     // a file or directory go generate writes
+)
+
+// MaxArtifactBytes bounds what one run collects; files past it are
+// left behind and reported missing
+const MaxArtifactBytes = 64 << 20
+
+var artifactNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ArtifactSpec declares a file an exercise's runs produce for download,
+// for exercises about code generation and tooling
+type ArtifactSpec struct {
+	Name string // e.g. "cli", unique in the exercise
+	Kind string
+	Path string // relative to the workspace, e.g. bin/cli or gen/
+}
+
+// Validate checks the name, the kind and that the path stays in the
+// workspace
+func (a ArtifactSpec) Validate() error {
+	if !artifactNameRegex.MatchString(a.Name) {
+		return fmt.Errorf("artifact name %q must be lowercase letters, digits, - and _", a.Name)
+	}
+	switch a.Kind {
+	case ArtifactBinary, ArtifactCoverage, ArtifactFile:
+	default:
+		return fmt.Errorf("artifact %s: kind %q must be binary, coverage or file", a.Name, a.Kind)
+	}
+	if _, ok := CleanArtifactPath(a.Path); !ok {
+		return fmt.Errorf("artifact %s: path %q must be relative and stay in the workspace", a.Name, a.Path)
+	}
+	return nil
+}
+
+// CleanArtifactPath cleans a workspace-relative path, reporting false for
+// one that is empty, absolute or leaves the workspace
+func CleanArtifactPath(p string) (string, bool) {
+	if p == "" || strings.HasPrefix(p, "/") || strings.Contains(p, `\`) {
+		return "", false
+	}
+	p = path.Clean(p)
+	if p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return "", false
+	}
+	return p, true
+}
+
+// RunArtifact is a file a run collected, downloadable from the run. Not
+// to be confused with Artifact, a learner's workspace.
+type RunArtifact struct {
+	Name string `json:"name"` // the ArtifactSpec it was declared by
+	Kind string `json:"kind"`
+	Path string `json:"path"` // relative to the workspace
+	Size int64  `json:"size"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "testing"
+
+func TestArtifactSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    ArtifactSpec
+		wantErr bool
+	}{
+		{"binary", ArtifactSpec{Name: "cli", Kind: ArtifactBinary, Path: "bin/cli"}, false},
+		{"directory", ArtifactSpec{Name: "models", Kind: ArtifactFile, Path: "gen/"}, false},
+		{"bad name", ArtifactSpec{Name: "My CLI", Kind: ArtifactBinary, Path: "bin/cli"}, true},
+		{"bad kind", ArtifactSpec{Name: "cli", Kind: "docker", Path: "bin/cli"}, true},
+		{"absolute", ArtifactSpec{Name: "cli", Kind: ArtifactBinary, Path: "/usr/bin/cli"}, true},
+		{"escapes", ArtifactSpec{Name: "cli", Kind: ArtifactBinary, Path: "bin/../../cli"}, true},
+		{"workspace", ArtifactSpec{Name: "all", Kind: ArtifactFile, Path: "."}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.spec.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
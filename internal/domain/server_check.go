@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Defaults for a ServerCheck that leaves them out
+const (
+	DefaultServerPort    = 8080
+	DefaultServerStartup = 10 * time.Second
+	maxServerProbes      = 50
+)
+
+// ServerCheck starts the exercise's program as a server inside the
+// sandbox and sends it HTTP probes, for exercises whose program never
+// exits on its own
+type ServerCheck struct {
+	Port    int           // port the program listens on
+	Ready   string        // path polled until the server answers
+	Startup time.Duration // how long to wait for the server to answer
+	Probes  []HTTPProbe
+}
+
+// HTTPProbe is one request sent to a running server and what its
+// response must look like
+type HTTPProbe struct {
+	Name         string
+	Method       string
+	Path         string
+	Headers      map[string]string
+	Body         string
+	ExpectStatus int    // 0 accepts any status
+	ExpectBody   string // substring the body must contain
+	ExpectHeader map[string]string
+}
+
+// ProbeResult is the outcome of one HTTPProbe
+type ProbeResult struct {
+	Name    string `json:"name"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Status  int    `json:"status,omitempty"`
+	Body    string `json:"body,omitempty"` // truncated
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"` // why it failed
+}
+
+var probeMethods = map[string]bool{
+	"GET": true, "HEAD": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "OPTIONS": true,
+}
+
+// WithDefaults fills in the port, ready path, startup timeout and probe
+// methods left out
+func (c ServerCheck) WithDefaults() ServerCheck {
+	if c.Port == 0 {
+		c.Port = DefaultServerPort
+	}
+	if c.Ready == "" {
+		c.Ready = "/"
+	}
+	if c.Startup == 0 {
+		c.Startup = DefaultServerStartup
+	}
+	probes := make([]HTTPProbe, len(c.Probes))
+	for i, p := range c.Probes {
+		if p.Method == "" {
+			p.Method = "GET"
+		}
+		p.Method = strings.ToUpper(p.Method)
+		if p.Name == "" {
+			p.Name = p.Method + " " + p.Path
+		}
+		probes[i] = p
+	}
+	c.Probes = probes
+	return c
+}
+
+// Validate checks the port, paths and methods
+func (c ServerCheck) Validate() error {
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("server port %d is out of range", c.Port)
+	}
+	if !strings.HasPrefix(c.Ready, "/") {
+		return fmt.Errorf("server ready path %q must start with /", c.Ready)
+	}
+	if c.Startup < 0 {
+		return fmt.Errorf("server startup timeout must not be negative")
+	}
+	if len(c.Probes) == 0 {
+		return fmt.Errorf("server check needs at least one probe")
+	}
+	if len(c.Probes) > maxServerProbes {
+		return fmt.Errorf("server check has %d probes; the limit is %d", len(c.Probes), maxServerProbes)
+	}
+	for _, p := range c.Probes {
+		if !probeMethods[strings.ToUpper(p.Method)] && p.Method != "" {
+			return fmt.Errorf("probe %q: method %s is not supported", p.Name, p.Method)
+		}
+		if !strings.HasPrefix(p.Path, "/") {
+			return fmt.Errorf("probe %q: path %q must start with /", p.Name, p.Path)
+		}
+		if p.ExpectStatus != 0 && (p.ExpectStatus < 100 || p.ExpectStatus > 599) {
+			return fmt.Errorf("probe %q: expected status %d is not an HTTP status", p.Name, p.ExpectStatus)
+		}
+	}
+	return nil
+}
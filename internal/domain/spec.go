@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
 
 // ProductSpec represents a Specular specification for feature work
 type ProductSpec struct {
@@ -11,6 +16,8 @@ type ProductSpec struct {
 	NonFunctional      NonFunctionalReqs     `yaml:"non_functional" json:"non_functional"`
 	AcceptanceCriteria []AcceptanceCriterion `yaml:"acceptance_criteria" json:"acceptance_criteria"`
 	Milestones         []Milestone           `yaml:"milestones" json:"milestones"`
+	Source             string                `yaml:"source,omitempty" json:"source,omitempty"` // issue URL for imported specs
+	Tracker            *TrackerLink          `yaml:"tracker,omitempty" json:"tracker,omitempty"`
 	FilePath           string                `yaml:"-" json:"file_path"`
 	CreatedAt          time.Time             `yaml:"-" json:"created_at"`
 	UpdatedAt          time.Time             `yaml:"-" json:"updated_at"`
@@ -24,8 +31,55 @@ type Feature struct {
 	Priority        Priority `yaml:"priority" json:"priority"`
 	API             *APISpec `yaml:"api,omitempty" json:"api,omitempty"`
 	SuccessCriteria []string `yaml:"success_criteria" json:"success_criteria"`
+
+	// DependsOn lists features that must be built first: a feature ID in
+	// this spec, or "other.yaml#feature-id" for a feature in another spec
+	DependsOn []string `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+
+	// Labels carries tracker labels of imported features
+	Labels []string `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+// MatchesTest reports whether a test name references the feature: its
+// ID or title appears in the name, ignoring case and separators.
+// "password-reset" matches TestPasswordReset and Test_password_reset/expiry.
+func (f *Feature) MatchesTest(testName string) bool {
+	name := alnumLower(testName)
+	for _, ref := range []string{f.ID, f.Title} {
+		if r := alnumLower(ref); r != "" && strings.Contains(name, r) {
+			return true
+		}
+	}
+	return false
 }
 
+func alnumLower(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// TrackerLink ties a spec imported from an issue tracker to its issue
+type TrackerLink struct {
+	System string `yaml:"system" json:"system"` // github or jira
+	Key    string `yaml:"key" json:"key"`       // owner/repo#123 or PROJ-42
+
+	// Sync reports satisfied criteria back to the issue: "checklist"
+	// ticks matching checklist items and comments on the rest, "comment"
+	// only comments. Empty leaves the issue alone.
+	Sync   string   `yaml:"sync,omitempty" json:"sync,omitempty"`
+	Synced []string `yaml:"synced,omitempty" json:"synced,omitempty"` // criterion IDs already reported
+}
+
+const (
+	TrackerSyncChecklist = "checklist"
+	TrackerSyncComment   = "comment"
+)
+
 // Priority represents feature importance
 type Priority string
 
@@ -57,6 +111,9 @@ type AcceptanceCriterion struct {
 	Description string `yaml:"description" json:"description"`
 	Satisfied   bool   `yaml:"satisfied" json:"satisfied"`
 	Evidence    string `yaml:"evidence,omitempty" json:"evidence,omitempty"`
+
+	// Features lists the IDs of the features this criterion verifies
+	Features []string `yaml:"features,omitempty" json:"features,omitempty"`
 }
 
 // Milestone represents a delivery checkpoint
@@ -83,6 +140,102 @@ type SpecProgress struct {
 	PendingCriteria   []AcceptanceCriterion `json:"pending_criteria"`
 }
 
+// FeatureProgress is the status of one feature: its criteria, the tests
+// that reference it and the runs they appeared in
+type FeatureProgress struct {
+	SpecPath          string                `json:"spec_path"`
+	Feature           Feature               `json:"feature"`
+	Criteria          []AcceptanceCriterion `json:"criteria"`
+	SatisfiedCriteria int                   `json:"satisfied_criteria"`
+	PercentComplete   float64               `json:"percent_complete"`
+	Tests             []FeatureTest         `json:"tests"`                   // latest outcome of each related test
+	RecentRuns        []FeatureRun          `json:"recent_runs"`             // newest first
+	TouchedFiles      []string              `json:"touched_files,omitempty"` // from commits mentioning the feature
+}
+
+// FeatureTest is the latest outcome of a test that references a feature
+type FeatureTest struct {
+	Name      string    `json:"name"`
+	Passed    bool      `json:"passed"`
+	RunID     string    `json:"run_id"`
+	RunAt     time.Time `json:"run_at"`
+	SessionID string    `json:"session_id"`
+}
+
+// FeatureRun is a run that executed tests referencing a feature
+type FeatureRun struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Passed    int       `json:"passed"` // related tests that passed
+	Failed    int       `json:"failed"`
+}
+
+// SpecFindingKind categorizes a spec review finding
+type SpecFindingKind string
+
+const (
+	FindingAmbiguousCriterion  SpecFindingKind = "ambiguous_criterion"
+	FindingMissingEdgeCase     SpecFindingKind = "missing_edge_case"
+	FindingUntestableGoal      SpecFindingKind = "untestable_goal"
+	FindingConflictingFeatures SpecFindingKind = "conflicting_features"
+
+	// Cross-file findings for specs authored together
+	FindingDuplicateFeature SpecFindingKind = "duplicate_feature"
+	FindingBrokenReference  SpecFindingKind = "broken_reference"
+)
+
+// SpecFinding is a single problem found by a spec review
+type SpecFinding struct {
+	Kind             SpecFindingKind `json:"kind"`
+	Severity         string          `json:"severity"` // high, medium, low
+	Target           string          `json:"target"`   // criterion or feature ID, or e.g. "goals[1]"
+	Message          string          `json:"message"`
+	SuggestedRewrite string          `json:"suggested_rewrite,omitempty"` // never applied automatically
+}
+
+// SpecReview is an LLM critique of a spec
+type SpecReview struct {
+	SpecPath   string        `json:"spec_path"`
+	Summary    string        `json:"summary,omitempty"`
+	Findings   []SpecFinding `json:"findings"`
+	ReviewedAt time.Time     `json:"reviewed_at"`
+}
+
+// SpecPlan orders a spec's features so each comes after its dependencies
+type SpecPlan struct {
+	SpecPath string     `json:"spec_path"`
+	Steps    []PlanStep `json:"steps"`
+}
+
+// PlanStep is one feature in a work plan
+type PlanStep struct {
+	Order     int      `json:"order"`
+	Stage     int      `json:"stage"` // features in the same stage do not depend on each other
+	Spec      string   `json:"spec"`  // spec file defining the feature
+	FeatureID string   `json:"feature_id"`
+	Title     string   `json:"title"`
+	Priority  Priority `json:"priority,omitempty"`
+	DependsOn []string `json:"depends_on,omitempty"` // "spec#feature-id" of each dependency
+	External  bool     `json:"external,omitempty"`   // defined in another spec
+}
+
+// DiagramFormat selects the notation of a spec diagram
+type DiagramFormat string
+
+const (
+	DiagramMermaid DiagramFormat = "mermaid" // Mermaid flowchart
+	DiagramC4      DiagramFormat = "c4"      // Mermaid C4 component diagram
+)
+
+// SpecDiagram is a rendered component diagram of a spec's features
+type SpecDiagram struct {
+	SpecPath string        `json:"spec_path"`
+	Format   DiagramFormat `json:"format"`
+	Diagram  string        `json:"diagram"`
+	Path     string        `json:"path,omitempty"` // file the diagram was written to
+}
+
 // SpecLock represents a canonical hashed snapshot for drift detection
 type SpecLock struct {
 	Version  string                   `json:"version"`
@@ -145,6 +298,43 @@ func (s *ProductSpec) GetFeature(id string) *Feature {
 	return nil
 }
 
+// FeatureCriteria returns the criteria that verify the feature. In a
+// single-feature spec, criteria that name no feature belong to it too.
+func (s *ProductSpec) FeatureCriteria(featureID string) []AcceptanceCriterion {
+	criteria := []AcceptanceCriterion{}
+	for _, ac := range s.AcceptanceCriteria {
+		if slices.Contains(ac.Features, featureID) || (len(ac.Features) == 0 && len(s.Features) == 1) {
+			criteria = append(criteria, ac)
+		}
+	}
+	return criteria
+}
+
+// GetFeatureProgress returns the criteria status of a feature, or nil if
+// the spec has no such feature. Tests and runs are left for the caller.
+func (s *ProductSpec) GetFeatureProgress(featureID string) *FeatureProgress {
+	feature := s.GetFeature(featureID)
+	if feature == nil {
+		return nil
+	}
+	progress := &FeatureProgress{
+		SpecPath:   s.FilePath,
+		Feature:    *feature,
+		Criteria:   s.FeatureCriteria(featureID),
+		Tests:      []FeatureTest{},
+		RecentRuns: []FeatureRun{},
+	}
+	for _, ac := range progress.Criteria {
+		if ac.Satisfied {
+			progress.SatisfiedCriteria++
+		}
+	}
+	if len(progress.Criteria) > 0 {
+		progress.PercentComplete = float64(progress.SatisfiedCriteria) / float64(len(progress.Criteria)) * 100
+	}
+	return progress
+}
+
 // IsComplete returns true if all acceptance criteria are satisfied
 func (s *ProductSpec) IsComplete() bool {
 	for _, ac := range s.AcceptanceCriteria {
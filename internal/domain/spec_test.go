@@ -406,3 +406,24 @@ func TestLockedFeature_Struct(t *testing.T) {
 		t.Errorf("Hash = %q, want sha256:abc123", lf.Hash)
 	}
 }
+
+func TestFeature_MatchesTest(t *testing.T) {
+	f := &Feature{ID: "password-reset", Title: "Reset via email"}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"TestPasswordReset", true},
+		{"Test_password_reset/expired", true},
+		{"TestResetViaEmail", true},
+		{"TestPassword", false},
+		{"TestLogin", false},
+	}
+
+	for _, tc := range tests {
+		if got := f.MatchesTest(tc.name); got != tc.want {
+			t.Errorf("MatchesTest(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -24,6 +24,12 @@ type Track struct {
 
 	// Auto-progress rules
 	AutoProgress AutoProgressRules `json:"auto_progress" yaml:"auto_progress"`
+
+	// Stuck detection
+	Stuck StuckPolicy `json:"stuck" yaml:"stuck,omitempty"`
+
+	// Hint budget per session
+	Budget HintBudget `json:"budget" yaml:"budget,omitempty"`
 }
 
 // AutoProgressRules define when a track should automatically adjust difficulty.
@@ -41,6 +47,8 @@ func (t *Track) ToPolicy() LearningPolicy {
 		CooldownSeconds: t.CooldownSeconds,
 		PatchingEnabled: t.PatchingEnabled,
 		Track:           t.ID,
+		Stuck:           t.Stuck,
+		Budget:          t.Budget,
 	}
 }
 
@@ -58,6 +66,20 @@ func (t *Track) Validate() error {
 	if t.CooldownSeconds < 0 {
 		return fmt.Errorf("cooldown_seconds must be non-negative")
 	}
+	if t.Stuck.FailingRuns < 0 || t.Stuck.IdleSeconds < 0 {
+		return fmt.Errorf("stuck.failing_runs and stuck.idle_seconds must be non-negative")
+	}
+	if t.Budget.Tokens < 0 {
+		return fmt.Errorf("budget.tokens must be non-negative")
+	}
+	if len(t.Budget.Costs) > int(L5FullSolution)+1 {
+		return fmt.Errorf("budget.costs has one entry per level, L0 to L5")
+	}
+	for _, c := range t.Budget.Costs {
+		if c < 0 {
+			return fmt.Errorf("budget.costs must be non-negative")
+		}
+	}
 	return nil
 }
 
@@ -17,6 +17,12 @@ func TestTrack_Validate(t *testing.T) {
 	if err := track.Validate(); err == nil {
 		t.Error("Validate() should fail for invalid max_level")
 	}
+
+	track.MaxLevel = L3ConstrainedSnippet
+	track.Budget = HintBudget{Tokens: 5, Costs: []int{0, -1}}
+	if err := track.Validate(); err == nil {
+		t.Error("Validate() should fail for a negative hint cost")
+	}
 }
 
 func TestTrack_ToPolicy(t *testing.T) {
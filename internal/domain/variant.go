@@ -0,0 +1,132 @@
+package domain
+
+// VariantKind names an author-written version of an exercise for
+// learners well above or below its level
+type VariantKind string
+
+const (
+	// VariantHarder is for learners the exercise would not stretch:
+	// stricter tests, less scaffolding
+	VariantHarder VariantKind = "harder"
+	// VariantEasier is for learners the exercise would swamp: more
+	// scaffolding, extra hints
+	VariantEasier VariantKind = "easier"
+)
+
+// Skill thresholds for picking a variant. They match the ones that
+// tighten the hint ceiling, so a learner who gets the harder variant is
+// also the one who gets fewer hints.
+const (
+	variantHarderAbove = 0.7
+	variantEasierBelow = 0.3
+	variantMinAttempts = 3
+)
+
+// ExerciseVariant is the pack author's change to an exercise for one
+// kind of learner. Starter and test files replace the exercise's files
+// of the same name, and an empty file removes it. Hints are added to the
+// exercise's, and ladder steps that are set replace its steps.
+type ExerciseVariant struct {
+	StarterCode map[string]string
+	TestCode    map[string]string
+	Hints       HintSet
+	HintLadder  HintLadder
+
+	// Difficulty is what completing the variant counts as for the
+	// learner's skill; "" = one level above or below the exercise's
+	Difficulty Difficulty
+}
+
+// ChooseVariant picks the variant for a learner with skill in the
+// exercise's topic, or "" for the exercise as written. It only moves
+// learners who are clearly above or below the exercise: a few attempts
+// are needed before the skill level means anything, and there is no
+// harder advanced exercise or easier beginner one.
+func ChooseVariant(d Difficulty, skill SkillLevel) VariantKind {
+	if skill.Attempts < variantMinAttempts {
+		return ""
+	}
+	switch {
+	case skill.Level > variantHarderAbove && d != DifficultyAdvanced:
+		return VariantHarder
+	case skill.Level < variantEasierBelow && d != DifficultyBeginner:
+		return VariantEasier
+	}
+	return ""
+}
+
+// WithVariant returns a copy of the exercise with the variant applied,
+// or the exercise itself when it has no such variant. The copy is
+// versioned by its own content and has the variant's difficulty.
+func (e *Exercise) WithVariant(kind VariantKind) *Exercise {
+	v := e.Variants[kind]
+	if kind == "" || v == nil {
+		return e
+	}
+
+	out := *e
+	out.Variant = kind
+	out.StarterCode = overlayFiles(e.StarterCode, v.StarterCode)
+	out.TestCode = overlayFiles(e.TestCode, v.TestCode)
+	out.Hints = HintSet{
+		L0: appendHints(e.Hints.L0, v.Hints.L0),
+		L1: appendHints(e.Hints.L1, v.Hints.L1),
+		L2: appendHints(e.Hints.L2, v.Hints.L2),
+		L3: appendHints(e.Hints.L3, v.Hints.L3),
+	}
+	if v.HintLadder.L1 != "" {
+		out.HintLadder.L1 = v.HintLadder.L1
+	}
+	if v.HintLadder.L2 != "" {
+		out.HintLadder.L2 = v.HintLadder.L2
+	}
+	if v.HintLadder.L3 != "" {
+		out.HintLadder.L3 = v.HintLadder.L3
+	}
+	out.Difficulty = v.Difficulty
+	if out.Difficulty == "" {
+		out.Difficulty = e.Difficulty.shift(kind)
+	}
+	out.Version = out.ContentHash()
+	return &out
+}
+
+// shift returns the level a variant of this difficulty defaults to
+func (d Difficulty) shift(kind VariantKind) Difficulty {
+	levels := []Difficulty{DifficultyBeginner, DifficultyIntermediate, DifficultyAdvanced}
+	for i, level := range levels {
+		if level != d {
+			continue
+		}
+		switch {
+		case kind == VariantHarder && i < len(levels)-1:
+			return levels[i+1]
+		case kind == VariantEasier && i > 0:
+			return levels[i-1]
+		}
+	}
+	return d
+}
+
+func overlayFiles(base, overlay map[string]string) map[string]string {
+	files := make(map[string]string, len(base)+len(overlay))
+	for name, content := range base {
+		files[name] = content
+	}
+	for name, content := range overlay {
+		if content == "" {
+			delete(files, name)
+			continue
+		}
+		files[name] = content
+	}
+	return files
+}
+
+func appendHints(base, extra []string) []string {
+	if len(extra) == 0 {
+		return base
+	}
+	hints := make([]string, 0, len(base)+len(extra))
+	return append(append(hints, base...), extra...)
+}
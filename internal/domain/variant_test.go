@@ -0,0 +1,85 @@
+package domain
+
+import "testing"
+
+func TestChooseVariant(t *testing.T) {
+	tests := []struct {
+		name       string
+		difficulty Difficulty
+		skill      SkillLevel
+		want       VariantKind
+	}{
+		{"strong learner", DifficultyIntermediate, SkillLevel{Level: 0.85, Attempts: 5}, VariantHarder},
+		{"struggling learner", DifficultyIntermediate, SkillLevel{Level: 0.1, Attempts: 5}, VariantEasier},
+		{"on level", DifficultyIntermediate, SkillLevel{Level: 0.5, Attempts: 5}, ""},
+		{"too few attempts", DifficultyIntermediate, SkillLevel{Level: 0.9, Attempts: 2}, ""},
+		{"nothing harder than advanced", DifficultyAdvanced, SkillLevel{Level: 0.9, Attempts: 5}, ""},
+		{"nothing easier than beginner", DifficultyBeginner, SkillLevel{Level: 0.1, Attempts: 5}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChooseVariant(tt.difficulty, tt.skill); got != tt.want {
+				t.Errorf("ChooseVariant() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExercise_WithVariant(t *testing.T) {
+	ex := &Exercise{
+		ID:          "go-v1/basics/hello",
+		Difficulty:  DifficultyIntermediate,
+		StarterCode: map[string]string{"main.go": "package main // TODO", "helper.go": "package main"},
+		TestCode:    map[string]string{"main_test.go": "package main"},
+		Hints:       HintSet{L1: []string{"think about fmt"}},
+		HintLadder:  HintLadder{L1: "direction", L2: "location"},
+		Variants: map[VariantKind]*ExerciseVariant{
+			VariantEasier: {
+				Hints:      HintSet{L1: []string{"start with the empty name"}},
+				Difficulty: DifficultyIntermediate,
+			},
+			VariantHarder: {
+				StarterCode: map[string]string{"helper.go": ""},
+				TestCode:    map[string]string{"edge_test.go": "package main"},
+				Hints:       HintSet{L1: []string{"edge cases count"}},
+				HintLadder:  HintLadder{L2: "tighter location"},
+			},
+		},
+	}
+	ex.Version = ex.ContentHash()
+
+	if got := ex.WithVariant(""); got != ex {
+		t.Error("no variant should leave the exercise as written")
+	}
+
+	harder := ex.WithVariant(VariantHarder)
+	if harder.Variant != VariantHarder {
+		t.Errorf("Variant = %q, want harder", harder.Variant)
+	}
+	if _, ok := harder.StarterCode["helper.go"]; ok {
+		t.Error("an empty variant file should remove the exercise's file")
+	}
+	if harder.TestCode["edge_test.go"] == "" || harder.TestCode["main_test.go"] == "" {
+		t.Errorf("TestCode = %v, want the exercise's tests plus the variant's", harder.TestCode)
+	}
+	if len(harder.Hints.L1) != 2 {
+		t.Errorf("Hints.L1 = %v, want the variant's hint appended", harder.Hints.L1)
+	}
+	if harder.HintLadder.L1 != "direction" || harder.HintLadder.L2 != "tighter location" {
+		t.Errorf("HintLadder = %+v, want L1 kept and L2 replaced", harder.HintLadder)
+	}
+	if harder.Difficulty != DifficultyAdvanced {
+		t.Errorf("Difficulty = %q, want one level above the exercise", harder.Difficulty)
+	}
+	if easier := ex.WithVariant(VariantEasier); easier.Difficulty != DifficultyIntermediate {
+		t.Errorf("easier Difficulty = %q, want the variant's own", easier.Difficulty)
+	}
+	if harder.Version == ex.Version {
+		t.Error("the variant should be versioned by its own content")
+	}
+
+	// The exercise as written is untouched
+	if _, ok := ex.StarterCode["helper.go"]; !ok || len(ex.TestCode) != 1 || len(ex.Hints.L1) != 1 || ex.Variant != "" || ex.Difficulty != DifficultyIntermediate {
+		t.Error("WithVariant modified the original exercise")
+	}
+}
@@ -0,0 +1,226 @@
+// Package editlog records coarse editor activity per session so analytics
+// can tell code the learner typed from code they pasted. It is opt-in and
+// local-only: events are appended to files under ~/.temper and never sent
+// anywhere. An event carries sizes, counts and timings but never the text
+// that was typed or pasted, and file paths are cut down to their base name.
+package editlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// Event kinds
+const (
+	KindTyping    = "typing"     // a burst of keystrokes
+	KindPaste     = "paste"      // one paste
+	KindUndoStorm = "undo_storm" // many undos in quick succession
+)
+
+// LargePasteChars is the size from which a paste counts as large
+const LargePasteChars = 200
+
+// MaxBatch bounds the events accepted in one Append
+const MaxBatch = 500
+
+var (
+	ErrInvalidSession = errors.New("invalid session id")
+	ErrBatchTooLarge  = fmt.Errorf("more than %d events in one batch", MaxBatch)
+)
+
+var sessionIDPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,127}$`)
+
+// Event is one coarse edit event reported by an editor
+type Event struct {
+	Kind       string    `json:"kind"`
+	At         time.Time `json:"at"`
+	File       string    `json:"file,omitempty"`        // base name only
+	Chars      int       `json:"chars"`                 // characters inserted (or undone)
+	Lines      int       `json:"lines,omitempty"`       // lines inserted
+	Count      int       `json:"count,omitempty"`       // keystrokes in a burst, undos in a storm
+	DurationMs int64     `json:"duration_ms,omitempty"` // how long the burst or storm lasted
+}
+
+// normalize validates e and strips anything that could identify the
+// learner beyond what the analytics need
+func (e Event) normalize(now time.Time) (Event, error) {
+	switch e.Kind {
+	case KindTyping, KindPaste, KindUndoStorm:
+	default:
+		return Event{}, fmt.Errorf("unknown event kind %q", e.Kind)
+	}
+	if e.Chars < 0 || e.Lines < 0 || e.Count < 0 || e.DurationMs < 0 {
+		return Event{}, fmt.Errorf("%s event has a negative size", e.Kind)
+	}
+	if e.At.IsZero() || e.At.After(now) {
+		e.At = now
+	}
+	e.At = e.At.UTC()
+	if e.File != "" {
+		e.File = filepath.Base(filepath.ToSlash(e.File))
+	}
+	return e, nil
+}
+
+// Summary distinguishes authored from pasted code for one session
+type Summary struct {
+	TypedChars  int     `json:"typed_chars"`
+	PastedChars int     `json:"pasted_chars"`
+	PasteRatio  float64 `json:"paste_ratio"` // pasted / (typed + pasted)
+	Bursts      int     `json:"bursts"`
+	Pastes      int     `json:"pastes"`
+	LargePastes int     `json:"large_pastes"`
+	UndoStorms  int     `json:"undo_storms"`
+	ActiveMs    int64   `json:"active_ms"` // time spent in typing bursts
+}
+
+// Summarize aggregates events
+func Summarize(events []Event) Summary {
+	var s Summary
+	for _, e := range events {
+		switch e.Kind {
+		case KindTyping:
+			s.Bursts++
+			s.TypedChars += e.Chars
+			s.ActiveMs += e.DurationMs
+		case KindPaste:
+			s.Pastes++
+			s.PastedChars += e.Chars
+			if e.Chars >= LargePasteChars {
+				s.LargePastes++
+			}
+		case KindUndoStorm:
+			s.UndoStorms++
+		}
+	}
+	if total := s.TypedChars + s.PastedChars; total > 0 {
+		s.PasteRatio = float64(s.PastedChars) / float64(total)
+	}
+	return s
+}
+
+// Store appends each session's events to <dir>/<session>.jsonl
+type Store struct {
+	dir string
+	now func() time.Time
+}
+
+// NewStore creates a store rooted at dir (usually ~/.temper/edits)
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, now: time.Now}
+}
+
+func (s *Store) path(sessionID string) (string, error) {
+	if !sessionIDPattern.MatchString(sessionID) {
+		return "", ErrInvalidSession
+	}
+	return filepath.Join(s.dir, sessionID+".jsonl"), nil
+}
+
+// Append validates and records events for a session. Either the whole
+// batch is recorded or none of it is.
+func (s *Store) Append(sessionID string, events []Event) (int, error) {
+	path, err := s.path(sessionID)
+	if err != nil {
+		return 0, err
+	}
+	if len(events) > MaxBatch {
+		return 0, ErrBatchTooLarge
+	}
+
+	now := s.now()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i, e := range events {
+		clean, err := e.normalize(now)
+		if err != nil {
+			return 0, fmt.Errorf("event %d: %w", i, err)
+		}
+		if err := enc.Encode(clean); err != nil {
+			return 0, err
+		}
+	}
+	if buf.Len() == 0 {
+		return 0, nil
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return 0, err
+	}
+	return len(events), f.Close()
+}
+
+// Events returns a session's recorded events, oldest first
+func (s *Store) Events(sessionID string) ([]Event, error) {
+	path, err := s.path(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Event{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	events := []Event{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // a torn final line from a crash
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
+
+// Prune removes the logs of sessions with no events since before, so edit
+// logs follow the session retention policy. It returns how many it removed.
+func (s *Store) Prune(before time.Time) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.jsonl"))
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().Before(before) {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Delete removes a session's events
+func (s *Store) Delete(sessionID string) error {
+	path, err := s.path(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package editlog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore_AppendAndEvents(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	n, err := store.Append("sess-1", []Event{
+		{Kind: KindTyping, Chars: 40, Count: 45, DurationMs: 9000, File: "/Users/ada/work/main.go"},
+		{Kind: KindPaste, Chars: 320, Lines: 12, At: now.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Append() = %d; want 2", n)
+	}
+
+	events, err := store.Events("sess-1")
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("len(events) = %d; want 2", len(events))
+	}
+	if events[0].File != "main.go" {
+		t.Errorf("File = %q; want the base name only", events[0].File)
+	}
+	if !events[1].At.Equal(now) {
+		t.Errorf("future timestamp = %v; want clamped to %v", events[1].At, now)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "sess-1.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("file mode = %o; want 600", perm)
+	}
+}
+
+func TestStore_AppendRejectsWholeBatch(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	_, err := store.Append("sess-1", []Event{
+		{Kind: KindTyping, Chars: 10},
+		{Kind: "keylog", Chars: 1},
+	})
+	if err == nil || !strings.Contains(err.Error(), "event 1") {
+		t.Fatalf("Append() error = %v; want unknown kind on event 1", err)
+	}
+	if events, _ := store.Events("sess-1"); len(events) != 0 {
+		t.Errorf("recorded %d events from a rejected batch", len(events))
+	}
+
+	if _, err := store.Append("sess-1", []Event{{Kind: KindPaste, Chars: -5}}); err == nil {
+		t.Error("Append() should reject negative sizes")
+	}
+	if _, err := store.Append("sess-1", make([]Event, MaxBatch+1)); !errors.Is(err, ErrBatchTooLarge) {
+		t.Errorf("Append() error = %v; want ErrBatchTooLarge", err)
+	}
+}
+
+func TestStore_InvalidSession(t *testing.T) {
+	store := NewStore(t.TempDir())
+	for _, id := range []string{"", "../escape", "a/b"} {
+		if _, err := store.Append(id, []Event{{Kind: KindTyping}}); !errors.Is(err, ErrInvalidSession) {
+			t.Errorf("Append(%q) error = %v; want ErrInvalidSession", id, err)
+		}
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if _, err := store.Append("sess-1", []Event{{Kind: KindTyping, Chars: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("sess-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Delete("sess-1"); err != nil {
+		t.Errorf("Delete() of a missing log error = %v", err)
+	}
+	if events, _ := store.Events("sess-1"); len(events) != 0 {
+		t.Errorf("Events() after Delete = %d; want 0", len(events))
+	}
+}
+
+func TestStore_Prune(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	for _, id := range []string{"old", "new"} {
+		if _, err := store.Append(id, []Event{{Kind: KindTyping, Chars: 1}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stale := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "old.jsonl"), stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := store.Prune(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Prune() = %d; want 1", n)
+	}
+	if events, _ := store.Events("new"); len(events) != 1 {
+		t.Error("Prune() removed a recent log")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize([]Event{
+		{Kind: KindTyping, Chars: 100, DurationMs: 20000},
+		{Kind: KindTyping, Chars: 100, DurationMs: 10000},
+		{Kind: KindPaste, Chars: 50},
+		{Kind: KindPaste, Chars: 250},
+		{Kind: KindUndoStorm, Count: 30},
+	})
+
+	if s.TypedChars != 200 || s.PastedChars != 300 {
+		t.Errorf("typed/pasted = %d/%d; want 200/300", s.TypedChars, s.PastedChars)
+	}
+	if s.PasteRatio != 0.6 {
+		t.Errorf("PasteRatio = %v; want 0.6", s.PasteRatio)
+	}
+	if s.Bursts != 2 || s.Pastes != 2 || s.LargePastes != 1 || s.UndoStorms != 1 {
+		t.Errorf("counts = %+v", s)
+	}
+	if s.ActiveMs != 30000 {
+		t.Errorf("ActiveMs = %d; want 30000", s.ActiveMs)
+	}
+	if got := Summarize(nil); got.PasteRatio != 0 {
+		t.Errorf("empty PasteRatio = %v; want 0", got.PasteRatio)
+	}
+}
@@ -0,0 +1,43 @@
+package exercise
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ExtractBundle writes the packs in bundle to dest, so the path-based
+// Loader can read packs embedded in the binary. Files whose content is
+// unchanged are left alone; files missing from the bundle are kept, so
+// packs the user added next to the bundled ones survive.
+func ExtractBundle(bundle fs.FS, dest string) (int, error) {
+	written := 0
+	err := fs.WalkDir(bundle, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, filepath.FromSlash(path))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		data, err := fs.ReadFile(bundle, path)
+		if err != nil {
+			return err
+		}
+		if existing, err := os.ReadFile(target); err == nil && bytes.Equal(existing, data) {
+			return nil
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return err
+		}
+		written++
+		return nil
+	})
+	if err != nil {
+		return written, fmt.Errorf("extract exercise bundle: %w", err)
+	}
+	return written, nil
+}
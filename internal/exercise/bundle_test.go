@@ -0,0 +1,78 @@
+package exercise
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/felixgeelhaar/temper/exercises"
+)
+
+func TestExtractBundle(t *testing.T) {
+	bundle := fstest.MapFS{
+		"go-v1/pack.yaml":               {Data: []byte("id: go-v1\n")},
+		"go-v1/basics/hello-world.yaml": {Data: []byte("id: hello-world\n")},
+	}
+	dest := t.TempDir()
+
+	custom := filepath.Join(dest, "my-pack", "pack.yaml")
+	if err := os.MkdirAll(filepath.Dir(custom), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(custom, []byte("id: my-pack\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := ExtractBundle(bundle, dest)
+	if err != nil {
+		t.Fatalf("ExtractBundle() error = %v", err)
+	}
+	if written != 2 {
+		t.Errorf("written = %d, want 2", written)
+	}
+	if data, err := os.ReadFile(filepath.Join(dest, "go-v1", "basics", "hello-world.yaml")); err != nil || string(data) != "id: hello-world\n" {
+		t.Errorf("extracted exercise = %q, %v", data, err)
+	}
+	if _, err := os.Stat(custom); err != nil {
+		t.Errorf("user pack should survive extraction: %v", err)
+	}
+
+	// Unchanged files are not rewritten
+	if written, err := ExtractBundle(bundle, dest); err != nil || written != 0 {
+		t.Errorf("second ExtractBundle() = %d, %v; want 0, nil", written, err)
+	}
+}
+
+func TestExtractBundle_EmbeddedPacksLoad(t *testing.T) {
+	dest := t.TempDir()
+	if _, err := ExtractBundle(exercises.FS, dest); err != nil {
+		t.Fatalf("ExtractBundle() error = %v", err)
+	}
+
+	packs, err := NewLoader(dest).LoadAllPacks()
+	if err != nil {
+		t.Fatalf("LoadAllPacks() error = %v", err)
+	}
+	if len(packs) == 0 {
+		t.Fatal("embedded bundle should contain packs")
+	}
+}
+
+func TestExtractBundle_EmbeddedPacksLint(t *testing.T) {
+	dest := t.TempDir()
+	if _, err := ExtractBundle(exercises.FS, dest); err != nil {
+		t.Fatalf("ExtractBundle() error = %v", err)
+	}
+
+	loader := NewLoader(dest)
+	packs, err := loader.LoadAllPacks()
+	if err != nil {
+		t.Fatalf("LoadAllPacks() error = %v", err)
+	}
+	for _, pack := range packs {
+		if problems := loader.Lint(pack.ID); len(problems) > 0 {
+			t.Errorf("Lint(%s) = %v", pack.ID, problems)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package exercise
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// commitRegex matches a full commit ID; a source pinned to one never
+// changes
+var commitRegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// gitSchemes are the transports a git+ source may use
+var gitSchemes = []string{"https://", "ssh://", "file://"}
+
+// ParseGitSource splits a git+ URL into the repository git fetches from
+// and the branch, tag or commit it is pinned to, given as @ref after the
+// path: git+https://git.example.com/acme/packs.git@v1.2.0. Without a ref
+// the repository's default branch is followed.
+func ParseGitSource(url string) (repo, ref string, err error) {
+	repo, ok := strings.CutPrefix(url, "git+")
+	if !ok {
+		return "", "", fmt.Errorf("git pack source %q must start with git+", url)
+	}
+	supported := false
+	for _, scheme := range gitSchemes {
+		if strings.HasPrefix(repo, scheme) {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return "", "", fmt.Errorf("git pack source %q must use https, ssh or file", url)
+	}
+
+	// An @ before the path belongs to the user (ssh://git@host/...)
+	if at := strings.LastIndex(repo, "@"); at > strings.LastIndex(repo, "/") {
+		repo, ref = repo[:at], repo[at+1:]
+		if ref == "" || strings.HasPrefix(ref, "-") {
+			return "", "", fmt.Errorf("git pack source %q has an invalid ref", url)
+		}
+	}
+	return repo, ref, nil
+}
+
+// GitFetcher fetches packs from git repositories, so a team can publish
+// packs without a registry. The pack is the repository's contents at the
+// ref, without its history; the revision is the commit fetched.
+type GitFetcher struct{}
+
+// Fetch checks the remote for a new commit first, so an unchanged source
+// isn't cloned again
+func (GitFetcher) Fetch(ctx context.Context, url, revision, dest string) (string, error) {
+	repo, ref, err := ParseGitSource(url)
+	if err != nil {
+		return "", err
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git pack sources need git installed")
+	}
+
+	if revision != "" {
+		if commitRegex.MatchString(ref) {
+			if ref == revision {
+				return "", ErrNotModified
+			}
+		} else if head, err := remoteCommit(ctx, repo, ref); err == nil && head == revision {
+			return "", ErrNotModified
+		}
+	}
+
+	want := ref
+	if want == "" {
+		want = "HEAD"
+	}
+	steps := [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--depth", "1", repo, want},
+		{"checkout", "--quiet", "--detach", "FETCH_HEAD"},
+	}
+	for _, args := range steps {
+		if _, err := git(ctx, dest, args...); err != nil {
+			return "", err
+		}
+	}
+	commit, err := git(ctx, dest, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if err := os.RemoveAll(filepath.Join(dest, ".git")); err != nil {
+		return "", err
+	}
+	if commit == revision {
+		return "", ErrNotModified
+	}
+	return commit, nil
+}
+
+// remoteCommit returns the commit ref points to in repo, peeling
+// annotated tags
+func remoteCommit(ctx context.Context, repo, ref string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	out, err := git(ctx, "", "ls-remote", repo, ref, ref+"^{}")
+	if err != nil {
+		return "", err
+	}
+	commit := ""
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if strings.HasSuffix(fields[1], "^{}") {
+			return fields[0], nil
+		}
+		if commit == "" {
+			commit = fields[0]
+		}
+	}
+	if commit == "" {
+		return "", fmt.Errorf("ref %s not found in %s", ref, repo)
+	}
+	return commit, nil
+}
+
+// git runs git in dir without prompting for credentials, which the
+// daemon has no terminal for; private repositories need a credential
+// helper or an SSH agent
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ALLOW_PROTOCOL=https:ssh:file")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,144 @@
+package exercise
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseGitSource(t *testing.T) {
+	tests := []struct {
+		url, repo, ref string
+		wantErr        bool
+	}{
+		{url: "git+https://git.example.com/acme/packs.git", repo: "https://git.example.com/acme/packs.git"},
+		{url: "git+https://git.example.com/acme/packs.git@v1.2.0", repo: "https://git.example.com/acme/packs.git", ref: "v1.2.0"},
+		{url: "git+ssh://git@git.example.com/acme/packs.git", repo: "ssh://git@git.example.com/acme/packs.git"},
+		{url: "git+ssh://git@git.example.com/acme/packs.git@main", repo: "ssh://git@git.example.com/acme/packs.git", ref: "main"},
+		{url: "git+file:///srv/packs@0123456789abcdef0123456789abcdef01234567", repo: "file:///srv/packs", ref: "0123456789abcdef0123456789abcdef01234567"},
+		{url: "git+ext::sh -c evil", wantErr: true},
+		{url: "https://git.example.com/acme/packs.git", wantErr: true},
+		{url: "git+https://git.example.com/acme/packs.git@", wantErr: true},
+		{url: "git+https://git.example.com/acme/packs.git@--upload-pack=x", wantErr: true},
+	}
+	for _, tt := range tests {
+		repo, ref, err := ParseGitSource(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseGitSource(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if repo != tt.repo || ref != tt.ref {
+			t.Errorf("ParseGitSource(%q) = %q, %q; want %q, %q", tt.url, repo, ref, tt.repo, tt.ref)
+		}
+	}
+}
+
+// gitRepo is a local repository holding the acme-go pack
+type gitRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newGitRepo(t *testing.T) *gitRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	r := &gitRepo{t: t, dir: t.TempDir()}
+	r.git("init", "--quiet", "--initial-branch=main")
+	return r
+}
+
+func (r *gitRepo) git(args ...string) {
+	r.t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = r.dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		r.t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+// commit writes the pack at version, at the repository's root
+func (r *gitRepo) commit(version string) {
+	r.t.Helper()
+	for name, content := range acmePack(version) {
+		path := filepath.Join(r.dir, filepath.FromSlash(name[len("acme-go/"):]))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			r.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+	r.git("add", "-A")
+	r.git("commit", "--quiet", "-m", "Release "+version)
+}
+
+func TestGitSource_FollowsBranchAndPinsTag(t *testing.T) {
+	repo := newGitRepo(t)
+	repo.commit("1.0.0")
+	repo.git("tag", "-a", "v1.0.0", "-m", "v1.0.0")
+
+	ctx := context.Background()
+	following, followingDir := newTestUpdater(t)
+	pinned, pinnedDir := newTestUpdater(t)
+
+	src, err := following.Install(ctx, "git+file://"+repo.dir)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if src.Version != "1.0.0" || !commitRegex.MatchString(src.Revision) {
+		t.Errorf("Install() = %+v", src)
+	}
+	if _, err := os.Stat(filepath.Join(followingDir, "acme-go", ".git")); err == nil {
+		t.Error("installed pack should not keep the repository's history")
+	}
+	if _, err := pinned.Install(ctx, "git+file://"+repo.dir+"@v1.0.0"); err != nil {
+		t.Fatalf("Install() pinned error = %v", err)
+	}
+
+	// No new commit: nothing to stage
+	if staged, err := following.Check(ctx); err != nil || len(staged) != 0 {
+		t.Fatalf("Check() = %v, %v; want nothing staged", staged, err)
+	}
+
+	repo.commit("1.1.0")
+	staged, err := following.Check(ctx)
+	if err != nil || len(staged) != 1 || staged[0].ToVersion != "1.1.0" {
+		t.Fatalf("Check() = %v, %v; want 1.1.0 staged", staged, err)
+	}
+	if staged, err := pinned.Check(ctx); err != nil || len(staged) != 0 {
+		t.Errorf("pinned Check() = %v, %v; the tag didn't move", staged, err)
+	}
+
+	if _, err := following.Apply("acme-go"); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := exerciseTitle(t, followingDir); got != "Hello 1.1.0" {
+		t.Errorf("following title = %q", got)
+	}
+	if got := exerciseTitle(t, pinnedDir); got != "Hello 1.0.0" {
+		t.Errorf("pinned title = %q", got)
+	}
+}
+
+func TestGitSource_RefusesPackFailingLint(t *testing.T) {
+	repo := newGitRepo(t)
+	repo.commit("1.0.0")
+	// The exercise names a difficulty the loader accepts but lint doesn't
+	if err := os.WriteFile(filepath.Join(repo.dir, "basics", "hello.yaml"),
+		[]byte("id: hello\ntitle: Hello\ndifficulty: expert\nstarter:\n  main.go: package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo.git("commit", "--quiet", "-am", "Break it")
+
+	u, packsDir := newTestUpdater(t)
+	if _, err := u.Install(context.Background(), "git+file://"+repo.dir); err == nil {
+		t.Fatal("Install() should refuse a pack that fails lint")
+	}
+	if _, err := os.Stat(filepath.Join(packsDir, "acme-go")); err == nil {
+		t.Error("refused pack was installed")
+	}
+}
@@ -0,0 +1,39 @@
+package exercise
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// IndexVersion returns a version of everything under the base directory.
+// It changes whenever a file is added, removed or modified, so clients
+// can tell whether packs changed without fetching them. Only file names,
+// sizes and modification times are read.
+func (l *Loader) IndexVersion() (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(l.basePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(l.basePath, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", filepath.ToSlash(rel), info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("read exercises directory: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
@@ -0,0 +1,90 @@
+package exercise
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+// Lint checks a pack more strictly than loading it does: the manifest
+// names the pack's directory, a version and a supported language, and
+// every exercise it lists loads, once, with a title, a known difficulty,
+// starter code, prerequisites from the same pack and variants learners
+// can get. It returns every
+// problem found, or none for a pack that is fine to install.
+func (l *Loader) Lint(packID string) []string {
+	pack, err := l.LoadPack(packID)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	if pack.ID != packID {
+		problems = append(problems, fmt.Sprintf("pack id %q doesn't match its directory %q", pack.ID, packID))
+	}
+	if pack.Name == "" {
+		problems = append(problems, "pack has no name")
+	}
+	if pack.Version == "" {
+		problems = append(problems, "pack has no version")
+	}
+	if !runner.Language(pack.Language).IsValid() {
+		problems = append(problems, fmt.Sprintf("pack language %q is not supported", pack.Language))
+	}
+	if len(pack.ExerciseIDs) == 0 {
+		problems = append(problems, "pack lists no exercises")
+	}
+
+	// Prerequisites name an exercise by slug or by its last element
+	listed := make(map[string]bool, len(pack.ExerciseIDs))
+	prereqs := make(map[string]bool, 2*len(pack.ExerciseIDs))
+	for _, id := range pack.ExerciseIDs {
+		slug := strings.TrimPrefix(id, packID+"/")
+		if listed[slug] {
+			problems = append(problems, fmt.Sprintf("exercise %s is listed twice", slug))
+		}
+		listed[slug] = true
+		prereqs[slug] = true
+		prereqs[path.Base(slug)] = true
+	}
+
+	for _, id := range pack.ExerciseIDs {
+		slug := strings.TrimPrefix(id, packID+"/")
+		ex, err := l.LoadExercise(packID, slug)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("exercise %s: %v", slug, err))
+			continue
+		}
+		if ex.Title == "" {
+			problems = append(problems, fmt.Sprintf("exercise %s has no title", slug))
+		}
+		switch ex.Difficulty {
+		case domain.DifficultyBeginner, domain.DifficultyIntermediate, domain.DifficultyAdvanced:
+		default:
+			problems = append(problems, fmt.Sprintf("exercise %s difficulty %q is not beginner, intermediate or advanced", slug, ex.Difficulty))
+		}
+		if len(ex.StarterCode) == 0 {
+			problems = append(problems, fmt.Sprintf("exercise %s has no starter code", slug))
+		}
+		if ex.Variants[domain.VariantHarder] != nil && ex.Difficulty == domain.DifficultyAdvanced {
+			problems = append(problems, fmt.Sprintf("exercise %s is advanced, so its harder variant is never used", slug))
+		}
+		if ex.Variants[domain.VariantEasier] != nil && ex.Difficulty == domain.DifficultyBeginner {
+			problems = append(problems, fmt.Sprintf("exercise %s is beginner, so its easier variant is never used", slug))
+		}
+		for _, kind := range []domain.VariantKind{domain.VariantHarder, domain.VariantEasier} {
+			if ex.Variants[kind] != nil && len(ex.WithVariant(kind).StarterCode) == 0 {
+				problems = append(problems, fmt.Sprintf("exercise %s %s variant has no starter code", slug, kind))
+			}
+		}
+		for _, prereq := range ex.Prerequisites {
+			if !prereqs[prereq] {
+				problems = append(problems, fmt.Sprintf("exercise %s requires %s, which the pack doesn't list", slug, prereq))
+			}
+		}
+	}
+	return problems
+}
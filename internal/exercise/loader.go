@@ -1,22 +1,39 @@
 package exercise
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/felixgeelhaar/temper/internal/domain"
 	"gopkg.in/yaml.v3"
 )
 
+// goVersionRegex matches the Go releases a pack can pin ("1.23", "1.23.4")
+var goVersionRegex = regexp.MustCompile(`^1\.\d+(\.\d+)?$`)
+
+// formatters a pack may format Go code with; "" leaves it to the runner
+var formatters = map[string]bool{"": true, "gofmt": true, "goimports": true, "gofumpt": true}
+
+// ErrPackConflict is returned for a pack whose manifest claims an ID
+// other than the one its directory gives it, which would let it shadow
+// another pack
+var ErrPackConflict = errors.New("pack id conflicts with its directory")
+
 // PackFile represents the YAML structure for an exercise pack
 type PackFile struct {
 	ID              string   `yaml:"id"`
+	Org             string   `yaml:"org,omitempty"` // publisher namespace; the pack's ID becomes org/id
 	Name            string   `yaml:"name"`
 	Version         string   `yaml:"version"`
 	Description     string   `yaml:"description"`
 	Language        string   `yaml:"language"`
+	GoVersion       string   `yaml:"go_version,omitempty"` // toolchain the pack needs, e.g. "1.23"
+	Formatter       string   `yaml:"formatter,omitempty"`  // gofmt, goimports (default) or gofumpt
 	DifficultyRange []string `yaml:"difficulty_range"`
 	DefaultPolicy   struct {
 		MaxLevel        int    `yaml:"max_level"`
@@ -27,6 +44,15 @@ type PackFile struct {
 	Exercises []string `yaml:"exercises"`
 }
 
+// PackID returns the pack's fully-qualified ID, which names its
+// directory under the exercises path
+func (f *PackFile) PackID() string {
+	if f.Org != "" {
+		return f.Org + "/" + f.ID
+	}
+	return f.ID
+}
+
 // ExerciseFile represents the YAML structure for an exercise
 type ExerciseFile struct {
 	ID            string            `yaml:"id"`
@@ -38,11 +64,45 @@ type ExerciseFile struct {
 	Starter       map[string]string `yaml:"starter"`
 	Tests         map[string]string `yaml:"tests"`
 	CheckRecipe   struct {
-		Format    bool     `yaml:"format"`
-		Build     bool     `yaml:"build"`
-		Test      bool     `yaml:"test"`
-		TestFlags []string `yaml:"test_flags"`
-		Timeout   int      `yaml:"timeout"`
+		Format    bool              `yaml:"format"`
+		Build     bool              `yaml:"build"`
+		Test      bool              `yaml:"test"`
+		TestFlags []string          `yaml:"test_flags"`
+		Timeout   int               `yaml:"timeout"`
+		Debug     bool              `yaml:"debug"`
+		Env       map[string]string `yaml:"env"`
+		BuildTags []string          `yaml:"build_tags"`
+		Stdin     []struct {
+			Name           string `yaml:"name"`
+			Input          string `yaml:"input"`
+			ExpectedOutput string `yaml:"expected_output"`
+		} `yaml:"stdin_cases"`
+		Server *struct {
+			Port    int    `yaml:"port"`
+			Ready   string `yaml:"ready"`
+			Startup int    `yaml:"startup_timeout"` // seconds
+			Probes  []struct {
+				Name    string            `yaml:"name"`
+				Method  string            `yaml:"method"`
+				Path    string            `yaml:"path"`
+				Headers map[string]string `yaml:"headers"`
+				Body    string            `yaml:"body"`
+				Expect  struct {
+					Status  int               `yaml:"status"`
+					Body    string            `yaml:"body_contains"`
+					Headers map[string]string `yaml:"headers"`
+				} `yaml:"expect"`
+			} `yaml:"probes"`
+		} `yaml:"server"`
+		Fuzz *struct {
+			Target string `yaml:"target"`
+			Time   int    `yaml:"time"` // seconds
+		} `yaml:"fuzz"`
+		Artifacts []struct {
+			Name string `yaml:"name"`
+			Kind string `yaml:"kind"`
+			Path string `yaml:"path"`
+		} `yaml:"artifacts"`
 	} `yaml:"check_recipe"`
 	Rubric struct {
 		Criteria []struct {
@@ -59,7 +119,44 @@ type ExerciseFile struct {
 		L2 []string `yaml:"L2"`
 		L3 []string `yaml:"L3"`
 	} `yaml:"hints"`
-	Solution map[string]string `yaml:"solution"`
+	HintLadder struct {
+		L1 string `yaml:"L1"`
+		L2 string `yaml:"L2"`
+		L3 string `yaml:"L3"`
+	} `yaml:"hint_ladder"`
+	Solution     map[string]string `yaml:"solution"`
+	Alternatives []struct {
+		Name        string            `yaml:"name"`
+		Description string            `yaml:"description"`
+		Files       map[string]string `yaml:"files"`
+	} `yaml:"alternatives"`
+	Type      string `yaml:"type"`
+	Debugging struct {
+		Narrative string `yaml:"narrative"`
+		Bugs      []struct {
+			ID        string   `yaml:"id"`
+			File      string   `yaml:"file"`
+			Function  string   `yaml:"function"`
+			RootCause string   `yaml:"root_cause"`
+			Keywords  []string `yaml:"keywords"`
+		} `yaml:"bugs"`
+	} `yaml:"debugging"`
+	Variants map[string]struct {
+		Difficulty string            `yaml:"difficulty"`
+		Starter    map[string]string `yaml:"starter"`
+		Tests      map[string]string `yaml:"tests"`
+		Hints      struct {
+			L0 []string `yaml:"L0"`
+			L1 []string `yaml:"L1"`
+			L2 []string `yaml:"L2"`
+			L3 []string `yaml:"L3"`
+		} `yaml:"hints"`
+		HintLadder struct {
+			L1 string `yaml:"L1"`
+			L2 string `yaml:"L2"`
+			L3 string `yaml:"L3"`
+		} `yaml:"hint_ladder"`
+	} `yaml:"variants"`
 }
 
 // Loader handles loading exercises from YAML files
@@ -77,7 +174,9 @@ func (l *Loader) BasePath() string {
 	return l.basePath
 }
 
-// LoadPack loads an exercise pack from a directory
+// LoadPack loads an exercise pack from a directory. A pack published
+// under an org lives in a directory of the org's, and its ID includes
+// the org: acme/go-tools.
 func (l *Loader) LoadPack(packID string) (*domain.ExercisePack, error) {
 	packPath := filepath.Join(l.basePath, packID, "pack.yaml")
 
@@ -90,13 +189,21 @@ func (l *Loader) LoadPack(packID string) (*domain.ExercisePack, error) {
 	if err := yaml.Unmarshal(data, &packFile); err != nil {
 		return nil, fmt.Errorf("parse pack file: %w", err)
 	}
+	if packFile.GoVersion != "" && !goVersionRegex.MatchString(packFile.GoVersion) {
+		return nil, fmt.Errorf("parse pack file: go_version %q is not a Go release like 1.23", packFile.GoVersion)
+	}
+	if !formatters[packFile.Formatter] {
+		return nil, fmt.Errorf("parse pack file: formatter %q is not gofmt, goimports or gofumpt", packFile.Formatter)
+	}
 
 	pack := &domain.ExercisePack{
-		ID:          packFile.ID,
+		ID:          packFile.PackID(),
 		Name:        packFile.Name,
 		Version:     packFile.Version,
 		Description: packFile.Description,
 		Language:    packFile.Language,
+		GoVersion:   packFile.GoVersion,
+		Formatter:   packFile.Formatter,
 		DefaultPolicy: domain.LearningPolicy{
 			MaxLevel:        domain.InterventionLevel(packFile.DefaultPolicy.MaxLevel),
 			PatchingEnabled: packFile.DefaultPolicy.PatchingEnabled,
@@ -113,12 +220,22 @@ func (l *Loader) LoadPack(packID string) (*domain.ExercisePack, error) {
 	return pack, nil
 }
 
+// LoadExerciseByID loads an exercise by its fully-qualified ID
+func (l *Loader) LoadExerciseByID(id string) (*domain.Exercise, error) {
+	packID, slug, ok := domain.SplitExerciseID(id)
+	if !ok {
+		return nil, fmt.Errorf("invalid exercise ID: %s", id)
+	}
+	return l.LoadExercise(packID, slug)
+}
+
 // LoadExercise loads a single exercise from a YAML file. Inherits the
 // language from the parent pack so prompter can adapt per language.
 func (l *Loader) LoadExercise(packID, slug string) (*domain.Exercise, error) {
-	// Build path: basePath/packID/category/exercise.yaml
+	// Build path: basePath/packID/category/exercise.yaml. Slugs have
+	// exactly two elements so an exercise ID tells its pack apart
 	parts := strings.Split(slug, "/")
-	if len(parts) < 2 {
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
 		return nil, fmt.Errorf("invalid exercise slug: %s", slug)
 	}
 
@@ -159,6 +276,9 @@ func (l *Loader) LoadExercise(packID, slug string) (*domain.Exercise, error) {
 			Test:      exFile.CheckRecipe.Test,
 			TestFlags: exFile.CheckRecipe.TestFlags,
 			Timeout:   exFile.CheckRecipe.Timeout,
+			Debug:     exFile.CheckRecipe.Debug,
+			Env:       exFile.CheckRecipe.Env,
+			BuildTags: exFile.CheckRecipe.BuildTags,
 		},
 		Hints: domain.HintSet{
 			L0: exFile.Hints.L0,
@@ -166,6 +286,113 @@ func (l *Loader) LoadExercise(packID, slug string) (*domain.Exercise, error) {
 			L2: exFile.Hints.L2,
 			L3: exFile.Hints.L3,
 		},
+		HintLadder: domain.HintLadder{
+			L1: strings.TrimSpace(exFile.HintLadder.L1),
+			L2: strings.TrimSpace(exFile.HintLadder.L2),
+			L3: strings.TrimSpace(exFile.HintLadder.L3),
+		},
+	}
+	if len(exFile.Solution) > 0 {
+		solution, err := OpenSolution(packID, exFile.Solution)
+		if err != nil {
+			return nil, fmt.Errorf("open solution for %s: %w", exercise.ID, err)
+		}
+		exercise.Solution = solution
+	}
+	for i, alt := range exFile.Alternatives {
+		files, err := OpenSolution(packID, alt.Files)
+		if err != nil {
+			return nil, fmt.Errorf("open alternative %d for %s: %w", i+1, exercise.ID, err)
+		}
+		name := alt.Name
+		if name == "" {
+			name = fmt.Sprintf("alternative %d", i+1)
+		}
+		exercise.Alternatives = append(exercise.Alternatives, domain.Alternative{
+			Name:        name,
+			Description: strings.TrimSpace(alt.Description),
+			Files:       files,
+		})
+	}
+
+	for i, c := range exFile.CheckRecipe.Stdin {
+		name := c.Name
+		if name == "" {
+			name = fmt.Sprintf("case %d", i+1)
+		}
+		exercise.CheckRecipe.StdinCases = append(exercise.CheckRecipe.StdinCases, domain.StdinCase{
+			Name:           name,
+			Input:          c.Input,
+			ExpectedOutput: c.ExpectedOutput,
+		})
+	}
+	if srv := exFile.CheckRecipe.Server; srv != nil {
+		check := domain.ServerCheck{
+			Port:    srv.Port,
+			Ready:   srv.Ready,
+			Startup: time.Duration(srv.Startup) * time.Second,
+		}
+		for _, p := range srv.Probes {
+			check.Probes = append(check.Probes, domain.HTTPProbe{
+				Name:         p.Name,
+				Method:       p.Method,
+				Path:         p.Path,
+				Headers:      p.Headers,
+				Body:         p.Body,
+				ExpectStatus: p.Expect.Status,
+				ExpectBody:   p.Expect.Body,
+				ExpectHeader: p.Expect.Headers,
+			})
+		}
+		check = check.WithDefaults()
+		if err := check.Validate(); err != nil {
+			return nil, fmt.Errorf("exercise %s check_recipe: %w", slug, err)
+		}
+		exercise.CheckRecipe.Server = &check
+	}
+	if fz := exFile.CheckRecipe.Fuzz; fz != nil {
+		check := domain.FuzzCheck{Target: fz.Target, Time: time.Duration(fz.Time) * time.Second}.WithDefaults()
+		if err := check.Validate(); err != nil {
+			return nil, fmt.Errorf("exercise %s check_recipe: %w", slug, err)
+		}
+		exercise.CheckRecipe.Fuzz = &check
+	}
+	names := make(map[string]bool)
+	for _, a := range exFile.CheckRecipe.Artifacts {
+		spec := domain.ArtifactSpec{Name: a.Name, Kind: a.Kind, Path: a.Path}
+		if err := spec.Validate(); err != nil {
+			return nil, fmt.Errorf("exercise %s check_recipe: %w", slug, err)
+		}
+		if names[spec.Name] {
+			return nil, fmt.Errorf("exercise %s check_recipe: duplicate artifact %s", slug, spec.Name)
+		}
+		names[spec.Name] = true
+		spec.Path, _ = domain.CleanArtifactPath(spec.Path)
+		exercise.CheckRecipe.Artifacts = append(exercise.CheckRecipe.Artifacts, spec)
+	}
+	if err := exercise.CheckRecipe.BuildEnv().Validate(); err != nil {
+		return nil, fmt.Errorf("exercise %s check_recipe: %w", slug, err)
+	}
+
+	exercise.Type = domain.ExerciseTypeImplement
+	if exFile.Type != "" {
+		exercise.Type = domain.ExerciseType(exFile.Type)
+	}
+	if exercise.Type == domain.ExerciseTypeDebugging {
+		if len(exFile.Debugging.Bugs) == 0 {
+			return nil, fmt.Errorf("debugging exercise %s documents no bugs", slug)
+		}
+		dbg := &domain.DebuggingSpec{Narrative: exFile.Debugging.Narrative}
+		for _, b := range exFile.Debugging.Bugs {
+			dbg.Bugs = append(dbg.Bugs, domain.InjectedBug{
+				ID:        b.ID,
+				File:      b.File,
+				Function:  b.Function,
+				RootCause: b.RootCause,
+				Keywords:  b.Keywords,
+			})
+		}
+		exercise.Debugging = dbg
 	}
 
 	// Build rubric
@@ -180,35 +407,102 @@ func (l *Loader) LoadExercise(packID, slug string) (*domain.Exercise, error) {
 		}
 	}
 
+	for name, v := range exFile.Variants {
+		kind := domain.VariantKind(name)
+		if kind != domain.VariantHarder && kind != domain.VariantEasier {
+			return nil, fmt.Errorf("exercise %s: unknown variant %q (want harder or easier)", slug, name)
+		}
+		difficulty := domain.Difficulty(v.Difficulty)
+		switch difficulty {
+		case "", domain.DifficultyBeginner, domain.DifficultyIntermediate, domain.DifficultyAdvanced:
+		default:
+			return nil, fmt.Errorf("exercise %s: %s variant has unknown difficulty %q", slug, name, v.Difficulty)
+		}
+		if exercise.Variants == nil {
+			exercise.Variants = make(map[domain.VariantKind]*domain.ExerciseVariant)
+		}
+		exercise.Variants[kind] = &domain.ExerciseVariant{
+			Difficulty:  difficulty,
+			StarterCode: v.Starter,
+			TestCode:    v.Tests,
+			Hints: domain.HintSet{
+				L0: v.Hints.L0,
+				L1: v.Hints.L1,
+				L2: v.Hints.L2,
+				L3: v.Hints.L3,
+			},
+			HintLadder: domain.HintLadder{
+				L1: strings.TrimSpace(v.HintLadder.L1),
+				L2: strings.TrimSpace(v.HintLadder.L2),
+				L3: strings.TrimSpace(v.HintLadder.L3),
+			},
+		}
+	}
+
+	exercise.Version = exercise.ContentHash()
+
 	return exercise, nil
 }
 
-// LoadAllPacks loads all exercise packs from the base directory
+// LoadAllPacks loads all exercise packs from the base directory. Each
+// directory holding a pack.yaml is a pack; a directory without one is an
+// org, holding its packs in turn. A pack whose manifest claims another
+// directory's ID is refused rather than shadowing that pack.
 func (l *Loader) LoadAllPacks() ([]*domain.ExercisePack, error) {
+	ids, err := l.packDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	var packs []*domain.ExercisePack
+	for _, id := range ids {
+		pack, err := l.LoadPack(id)
+		if err != nil {
+			return nil, fmt.Errorf("load pack %s: %w", id, err)
+		}
+		if pack.ID != id {
+			return nil, fmt.Errorf("load pack %s: %w: pack.yaml declares %s; rename the directory or the id", id, ErrPackConflict, pack.ID)
+		}
+		packs = append(packs, pack)
+	}
+
+	return packs, nil
+}
+
+// packDirs returns the IDs of the pack directories under the base
+// directory, org packs as org/pack
+func (l *Loader) packDirs() ([]string, error) {
 	entries, err := os.ReadDir(l.basePath)
 	if err != nil {
 		return nil, fmt.Errorf("read exercises directory: %w", err)
 	}
 
-	var packs []*domain.ExercisePack
+	var ids []string
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
-
-		packPath := filepath.Join(l.basePath, entry.Name(), "pack.yaml")
-		if _, err := os.Stat(packPath); os.IsNotExist(err) {
+		if isPackDir(filepath.Join(l.basePath, entry.Name())) {
+			ids = append(ids, entry.Name())
 			continue
 		}
 
-		pack, err := l.LoadPack(entry.Name())
+		orgPacks, err := os.ReadDir(filepath.Join(l.basePath, entry.Name()))
 		if err != nil {
-			return nil, fmt.Errorf("load pack %s: %w", entry.Name(), err)
+			continue
+		}
+		for _, sub := range orgPacks {
+			if sub.IsDir() && isPackDir(filepath.Join(l.basePath, entry.Name(), sub.Name())) {
+				ids = append(ids, entry.Name()+"/"+sub.Name())
+			}
 		}
-		packs = append(packs, pack)
 	}
+	return ids, nil
+}
 
-	return packs, nil
+func isPackDir(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "pack.yaml"))
+	return err == nil
 }
 
 // LoadPackExercises loads all exercises for a pack
@@ -1,9 +1,12 @@
 package exercise
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
 )
 
 func TestNewLoader(t *testing.T) {
@@ -114,6 +117,50 @@ func TestLoader_LoadPack_InvalidYAML(t *testing.T) {
 	}
 }
 
+func TestLoader_LoadPack_GoVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{"unset", "", false},
+		{"minor", "1.23", false},
+		{"patch", "1.23.4", false},
+		{"prefixed", "go1.23", true},
+		{"garbage", "latest", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			packDir := filepath.Join(tmpDir, "p")
+			if err := os.MkdirAll(packDir, 0755); err != nil {
+				t.Fatalf("failed to create pack dir: %v", err)
+			}
+			packYAML := "id: p\nname: P\nlanguage: go\n"
+			if tt.version != "" {
+				packYAML += "go_version: \"" + tt.version + "\"\n"
+			}
+			if err := os.WriteFile(filepath.Join(packDir, "pack.yaml"), []byte(packYAML), 0644); err != nil {
+				t.Fatalf("failed to write pack.yaml: %v", err)
+			}
+
+			pack, err := NewLoader(tmpDir).LoadPack("p")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("LoadPack() should fail for go_version %q", tt.version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadPack() error = %v", err)
+			}
+			if pack.GoVersion != tt.version {
+				t.Errorf("pack.GoVersion = %q, want %q", pack.GoVersion, tt.version)
+			}
+		})
+	}
+}
+
 func TestLoader_LoadExercise(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -169,6 +216,9 @@ hints:
     - The main function is the entry point
   L3:
     - "fmt.Println(\"Hello\")"
+hint_ladder:
+  L1: |
+    Which package prints to standard output?
 solution:
   main.go: |
     package main
@@ -228,6 +278,9 @@ solution:
 	if len(ex.Hints.L1) != 1 {
 		t.Errorf("len(ex.Hints.L1) = %d, want 1", len(ex.Hints.L1))
 	}
+	if ex.HintLadder.L1 != "Which package prints to standard output?" || ex.HintLadder.L2 != "" {
+		t.Errorf("ex.HintLadder = %+v, want the trimmed L1 step only", ex.HintLadder)
+	}
 }
 
 func TestLoader_LoadExercise_InvalidSlug(t *testing.T) {
@@ -294,6 +347,81 @@ exercises: []
 	}
 }
 
+// writeTestPack writes a pack with one exercise, basics/hello, titled
+// title
+func writeTestPack(t *testing.T, dir, manifest, title string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "basics"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pack.yaml"), []byte(manifest+"language: go\nexercises:\n  - basics/hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "basics", "hello.yaml"), []byte("id: hello\ntitle: "+title+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoader_OrgPacks(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestPack(t, filepath.Join(tmpDir, "go-v1"), "id: go-v1\n", "Local")
+	writeTestPack(t, filepath.Join(tmpDir, "acme", "go-v1"), "id: go-v1\norg: acme\n", "Acme")
+
+	loader := NewLoader(tmpDir)
+	packs, err := loader.LoadAllPacks()
+	if err != nil {
+		t.Fatalf("LoadAllPacks() error = %v", err)
+	}
+	ids := map[string]bool{}
+	for _, p := range packs {
+		ids[p.ID] = true
+	}
+	if len(packs) != 2 || !ids["go-v1"] || !ids["acme/go-v1"] {
+		t.Fatalf("LoadAllPacks() IDs = %v, want go-v1 and acme/go-v1", ids)
+	}
+
+	// The same category/slug in both packs stays apart
+	registry := NewRegistry(loader)
+	if err := registry.Load(); err != nil {
+		t.Fatalf("Registry.Load() error = %v", err)
+	}
+	for id, title := range map[string]string{"go-v1/basics/hello": "Local", "acme/go-v1/basics/hello": "Acme"} {
+		ex, err := registry.GetExercise(id)
+		if err != nil || ex.Title != title {
+			t.Errorf("GetExercise(%s) = %v, %v; want %s", id, ex, err, title)
+			continue
+		}
+		if ex.ID != id {
+			t.Errorf("exercise ID = %q, want %q", ex.ID, id)
+		}
+	}
+	ex, err := loader.LoadExerciseByID("acme/go-v1/basics/hello")
+	if err != nil || ex.PackID != "acme/go-v1" || ex.Language != "go" {
+		t.Errorf("LoadExerciseByID() = %+v, %v", ex, err)
+	}
+}
+
+func TestLoader_LoadAllPacks_Conflict(t *testing.T) {
+	tests := []struct {
+		name, dir, manifest string
+	}{
+		{"copy claims another pack's id", "go-v1-copy", "id: go-v1\n"},
+		{"org pack outside its org", "tools", "id: tools\norg: acme\n"},
+		{"pack in another org", "acme/tools", "id: tools\norg: other\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeTestPack(t, filepath.Join(tmpDir, "go-v1"), "id: go-v1\n", "Local")
+			writeTestPack(t, filepath.Join(tmpDir, filepath.FromSlash(tt.dir)), tt.manifest, "Other")
+
+			if _, err := NewLoader(tmpDir).LoadAllPacks(); !errors.Is(err, ErrPackConflict) {
+				t.Errorf("LoadAllPacks() error = %v, want ErrPackConflict", err)
+			}
+		})
+	}
+}
+
 func TestLoader_LoadAllPacks_EmptyDir(t *testing.T) {
 	tmpDir := t.TempDir()
 	loader := NewLoader(tmpDir)
@@ -372,3 +500,380 @@ func TestLoader_LoadPackExercises_PackNotFound(t *testing.T) {
 		t.Error("LoadPackExercises() should fail for non-existent pack")
 	}
 }
+
+func TestLoader_LoadExercise_Debugging(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "go-v1", "debugging")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := `id: debugging/broken
+title: Broken
+type: debugging
+debugging:
+  narrative: It crashes on empty input
+  bugs:
+    - id: nil-map
+      file: main.go
+      function: Add
+      root_cause: map is never initialized
+      keywords: [nil map, make]
+`
+	missingBugs := `id: debugging/empty
+title: Empty
+type: debugging
+`
+	os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte(valid), 0644)
+	os.WriteFile(filepath.Join(dir, "empty.yaml"), []byte(missingBugs), 0644)
+
+	loader := NewLoader(tmpDir)
+
+	ex, err := loader.LoadExercise("go-v1", "debugging/broken")
+	if err != nil {
+		t.Fatalf("LoadExercise() error = %v", err)
+	}
+	if !ex.IsDebugging() {
+		t.Fatal("exercise should be a debugging exercise")
+	}
+	if ex.Debugging.Narrative != "It crashes on empty input" {
+		t.Errorf("Narrative = %q", ex.Debugging.Narrative)
+	}
+	if len(ex.Debugging.Bugs) != 1 || ex.Debugging.Bugs[0].Function != "Add" {
+		t.Errorf("Bugs = %+v", ex.Debugging.Bugs)
+	}
+
+	if _, err := loader.LoadExercise("go-v1", "debugging/empty"); err == nil {
+		t.Error("LoadExercise() should reject a debugging exercise without bugs")
+	}
+}
+
+func TestLoader_LoadExercise_BuildEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "go-v1", "constraints")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := `id: constraints/tags
+title: Build tags
+check_recipe:
+  build: true
+  test: true
+  env:
+    TZ: Asia/Tokyo
+    APP_FEATURE_X: "on"
+  build_tags: [integration]
+`
+	unsafe := `id: constraints/proxy
+title: Proxy
+check_recipe:
+  env:
+    GOPROXY: https://evil.example
+`
+	os.WriteFile(filepath.Join(dir, "tags.yaml"), []byte(valid), 0644)
+	os.WriteFile(filepath.Join(dir, "proxy.yaml"), []byte(unsafe), 0644)
+
+	loader := NewLoader(tmpDir)
+
+	ex, err := loader.LoadExercise("go-v1", "constraints/tags")
+	if err != nil {
+		t.Fatalf("LoadExercise() error = %v", err)
+	}
+	if ex.CheckRecipe.Env["TZ"] != "Asia/Tokyo" || ex.CheckRecipe.Env["APP_FEATURE_X"] != "on" {
+		t.Errorf("Env = %v", ex.CheckRecipe.Env)
+	}
+	if len(ex.CheckRecipe.BuildTags) != 1 || ex.CheckRecipe.BuildTags[0] != "integration" {
+		t.Errorf("BuildTags = %v", ex.CheckRecipe.BuildTags)
+	}
+
+	if _, err := loader.LoadExercise("go-v1", "constraints/proxy"); err == nil {
+		t.Error("LoadExercise() should reject an env variable outside the allowlist")
+	}
+}
+
+func TestLoader_LoadExercise_Server(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "web-v1", "http")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := `id: http/todos
+title: Todo API
+check_recipe:
+  test: true
+  server:
+    ready: /healthz
+    probes:
+      - path: /todos
+        expect:
+          status: 200
+          body_contains: "[]"
+      - name: create
+        method: post
+        path: /todos
+        body: '{"title":"milk"}'
+        headers: {Content-Type: application/json}
+        expect:
+          status: 201
+`
+	noPath := `id: http/broken
+title: Broken
+check_recipe:
+  server:
+    port: 9000
+    probes:
+      - path: todos
+`
+	os.WriteFile(filepath.Join(dir, "todos.yaml"), []byte(valid), 0644)
+	os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte(noPath), 0644)
+
+	loader := NewLoader(tmpDir)
+
+	ex, err := loader.LoadExercise("web-v1", "http/todos")
+	if err != nil {
+		t.Fatalf("LoadExercise() error = %v", err)
+	}
+	srv := ex.CheckRecipe.Server
+	if srv == nil {
+		t.Fatal("Server = nil")
+	}
+	if srv.Port != domain.DefaultServerPort || srv.Ready != "/healthz" || srv.Startup != domain.DefaultServerStartup {
+		t.Errorf("Server = %+v; want the default port and startup timeout", srv)
+	}
+	if len(srv.Probes) != 2 {
+		t.Fatalf("Probes = %d; want 2", len(srv.Probes))
+	}
+	if p := srv.Probes[0]; p.Name != "GET /todos" || p.ExpectStatus != 200 || p.ExpectBody != "[]" {
+		t.Errorf("probe 0 = %+v", p)
+	}
+	if p := srv.Probes[1]; p.Method != "POST" || p.Headers["Content-Type"] != "application/json" || p.ExpectStatus != 201 {
+		t.Errorf("probe 1 = %+v", p)
+	}
+
+	if _, err := loader.LoadExercise("web-v1", "http/broken"); err == nil {
+		t.Error("LoadExercise() should reject a probe path without a leading /")
+	}
+}
+
+func TestLoader_LoadExercise_Fuzz(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "go-v1", "fuzzing")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := `id: fuzzing/parse
+title: Parse
+check_recipe:
+  test: true
+  fuzz:
+    target: FuzzParse
+`
+	tooLong := `id: fuzzing/forever
+title: Forever
+check_recipe:
+  fuzz:
+    target: FuzzParse
+    time: 600
+`
+	os.WriteFile(filepath.Join(dir, "parse.yaml"), []byte(valid), 0644)
+	os.WriteFile(filepath.Join(dir, "forever.yaml"), []byte(tooLong), 0644)
+
+	loader := NewLoader(tmpDir)
+
+	ex, err := loader.LoadExercise("go-v1", "fuzzing/parse")
+	if err != nil {
+		t.Fatalf("LoadExercise() error = %v", err)
+	}
+	if fz := ex.CheckRecipe.Fuzz; fz == nil || fz.Target != "FuzzParse" || fz.Time != domain.DefaultFuzzTime {
+		t.Errorf("Fuzz = %+v; want FuzzParse with the default time", fz)
+	}
+
+	if _, err := loader.LoadExercise("go-v1", "fuzzing/forever"); err == nil {
+		t.Error("LoadExercise() should reject a fuzz time over the limit")
+	}
+}
+
+func TestLoader_LoadExercise_Variants(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "go-v1", "basics")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := `id: basics/sum
+title: Sum
+difficulty: intermediate
+starter:
+  sum.go: "package sum // TODO"
+tests:
+  sum_test.go: "package sum"
+variants:
+  harder:
+    tests:
+      overflow_test.go: "package sum"
+  easier:
+    difficulty: intermediate
+    starter:
+      sum.go: "package sum\n\nfunc Sum(xs []int) int {\n\t// loop over xs\n}"
+    hints:
+      L1: ["Start with a total of zero"]
+    hint_ladder:
+      L1: "  Keep a running total.  "
+`
+	unknown := `id: basics/product
+title: Product
+variants:
+  expert:
+    tests:
+      big_test.go: "package product"
+`
+	os.WriteFile(filepath.Join(dir, "sum.yaml"), []byte(valid), 0644)
+	os.WriteFile(filepath.Join(dir, "product.yaml"), []byte(unknown), 0644)
+
+	loader := NewLoader(tmpDir)
+
+	ex, err := loader.LoadExercise("go-v1", "basics/sum")
+	if err != nil {
+		t.Fatalf("LoadExercise() error = %v", err)
+	}
+	if len(ex.Variants) != 2 {
+		t.Fatalf("Variants = %v, want harder and easier", ex.Variants)
+	}
+	if harder := ex.Variants[domain.VariantHarder]; harder.TestCode["overflow_test.go"] == "" || harder.Difficulty != "" {
+		t.Errorf("harder variant = %+v, want overflow_test.go and no difficulty of its own", harder)
+	}
+	easier := ex.Variants[domain.VariantEasier]
+	if len(easier.Hints.L1) != 1 || easier.HintLadder.L1 != "Keep a running total." || easier.Difficulty != domain.DifficultyIntermediate {
+		t.Errorf("easier variant = %+v, want its hints and trimmed ladder", easier)
+	}
+	if ex.Version != ex.ContentHash() {
+		t.Error("the exercise as written should be versioned without its variants")
+	}
+
+	if _, err := loader.LoadExercise("go-v1", "basics/product"); err == nil {
+		t.Error("LoadExercise() should reject an unknown variant")
+	}
+}
+
+func TestLoader_LoadExercise_Artifacts(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "go-v1", "tooling")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := `id: tooling/cli
+title: CLI
+check_recipe:
+  build: true
+  artifacts:
+    - name: cli
+      kind: binary
+      path: ./bin/cli
+    - name: models
+      kind: file
+      path: gen/
+`
+	escapes := `id: tooling/escape
+title: Escape
+check_recipe:
+  artifacts:
+    - name: cli
+      kind: binary
+      path: ../cli
+`
+	duplicate := `id: tooling/twice
+title: Twice
+check_recipe:
+  artifacts:
+    - name: cli
+      kind: binary
+      path: bin/cli
+    - name: cli
+      kind: coverage
+      path: coverage.html
+`
+	os.WriteFile(filepath.Join(dir, "cli.yaml"), []byte(valid), 0644)
+	os.WriteFile(filepath.Join(dir, "escape.yaml"), []byte(escapes), 0644)
+	os.WriteFile(filepath.Join(dir, "twice.yaml"), []byte(duplicate), 0644)
+
+	loader := NewLoader(tmpDir)
+
+	ex, err := loader.LoadExercise("go-v1", "tooling/cli")
+	if err != nil {
+		t.Fatalf("LoadExercise() error = %v", err)
+	}
+	want := []domain.ArtifactSpec{
+		{Name: "cli", Kind: domain.ArtifactBinary, Path: "bin/cli"},
+		{Name: "models", Kind: domain.ArtifactFile, Path: "gen"},
+	}
+	if got := ex.CheckRecipe.Artifacts; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Artifacts = %+v, want %+v", got, want)
+	}
+
+	if _, err := loader.LoadExercise("go-v1", "tooling/escape"); err == nil {
+		t.Error("LoadExercise() should reject an artifact outside the workspace")
+	}
+	if _, err := loader.LoadExercise("go-v1", "tooling/twice"); err == nil {
+		t.Error("LoadExercise() should reject two artifacts with one name")
+	}
+}
+
+func TestLoader_IndexVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "go-v1")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "pack.yaml"), []byte("id: go-v1\n"), 0644)
+
+	loader := NewLoader(tmpDir)
+	v1, err := loader.IndexVersion()
+	if err != nil {
+		t.Fatalf("IndexVersion() error = %v", err)
+	}
+	if v, _ := loader.IndexVersion(); v != v1 {
+		t.Errorf("IndexVersion() = %q then %q for the same files", v1, v)
+	}
+
+	os.WriteFile(filepath.Join(dir, "hello.yaml"), []byte("id: basics/hello\n"), 0644)
+	v2, _ := loader.IndexVersion()
+	if v2 == v1 {
+		t.Error("IndexVersion() unchanged after adding an exercise")
+	}
+
+	os.WriteFile(filepath.Join(dir, "hello.yaml"), []byte("id: basics/hello\ntitle: Hello\n"), 0644)
+	if v3, _ := loader.IndexVersion(); v3 == v2 {
+		t.Error("IndexVersion() unchanged after editing an exercise")
+	}
+
+	if _, err := NewLoader(filepath.Join(tmpDir, "missing")).IndexVersion(); err == nil {
+		t.Error("IndexVersion() should fail for a missing directory")
+	}
+}
+
+func TestLoader_LoadPack_Formatter(t *testing.T) {
+	tmpDir := t.TempDir()
+	packDir := filepath.Join(tmpDir, "p")
+	if err := os.MkdirAll(packDir, 0755); err != nil {
+		t.Fatalf("failed to create pack dir: %v", err)
+	}
+	write := func(formatter string) {
+		packYAML := "id: p\nname: P\nlanguage: go\nformatter: " + formatter + "\n"
+		if err := os.WriteFile(filepath.Join(packDir, "pack.yaml"), []byte(packYAML), 0644); err != nil {
+			t.Fatalf("failed to write pack.yaml: %v", err)
+		}
+	}
+
+	write("gofumpt")
+	pack, err := NewLoader(tmpDir).LoadPack("p")
+	if err != nil || pack.Formatter != "gofumpt" {
+		t.Errorf("LoadPack() = %+v, %v; want formatter gofumpt", pack, err)
+	}
+
+	write("prettier")
+	if _, err := NewLoader(tmpDir).LoadPack("p"); err == nil {
+		t.Error("LoadPack() should fail for an unknown formatter")
+	}
+}
@@ -0,0 +1,156 @@
+package exercise
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+
+	"github.com/felixgeelhaar/temper/internal/vault"
+	"gopkg.in/yaml.v3"
+)
+
+// solutionSalt separates solution keys from any other key derived from a
+// pack ID
+const solutionSalt = "temper/reference-solution/v1\x00"
+
+// solutionCipher derives the key from the pack ID alone: reference
+// solutions ship inside packs, so anyone with the pack can open them.
+// Sealing is spoiler protection — a solution isn't readable at a glance or
+// by grepping the pack — not secrecy.
+func solutionCipher(packID string) (*vault.Cipher, error) {
+	key := sha256.Sum256([]byte(solutionSalt + packID))
+	return vault.NewCipher(key[:])
+}
+
+// SealSolution encrypts each file of a reference solution for packID.
+// Files that are already sealed are kept as they are.
+func SealSolution(packID string, files map[string]string) (map[string]string, error) {
+	c, err := solutionCipher(packID)
+	if err != nil {
+		return nil, err
+	}
+	sealed := make(map[string]string, len(files))
+	for name, content := range files {
+		if vault.IsEncrypted(content) {
+			sealed[name] = content
+			continue
+		}
+		if sealed[name], err = c.Encrypt(content); err != nil {
+			return nil, fmt.Errorf("seal %s: %w", name, err)
+		}
+	}
+	return sealed, nil
+}
+
+// OpenSolution decrypts a reference solution sealed for packID. Files an
+// author left in plaintext pass through.
+func OpenSolution(packID string, files map[string]string) (map[string]string, error) {
+	c, err := solutionCipher(packID)
+	if err != nil {
+		return nil, err
+	}
+	opened := make(map[string]string, len(files))
+	for name, content := range files {
+		if opened[name], err = c.Decrypt(content); err != nil {
+			return nil, fmt.Errorf("open %s: %w", name, err)
+		}
+	}
+	return opened, nil
+}
+
+// SealSolutionFile seals the solution and alternatives sections of the
+// exercise YAML at path in place, keeping the rest of the file (comments
+// included). It returns how many files it sealed; zero leaves the file
+// untouched.
+func SealSolutionFile(path, packID string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("read exercise file: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return 0, fmt.Errorf("parse exercise file: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return 0, fmt.Errorf("parse exercise file: not a mapping")
+	}
+
+	c, err := solutionCipher(packID)
+	if err != nil {
+		return 0, err
+	}
+	// The solution, and the files of every alternative
+	var sections []*yaml.Node
+	root := doc.Content[0]
+	if files := mappingValue(root, "solution"); files != nil {
+		sections = append(sections, files)
+	}
+	if alts := mappingValue(root, "alternatives"); alts != nil && alts.Kind == yaml.SequenceNode {
+		for _, alt := range alts.Content {
+			if files := mappingValue(alt, "files"); files != nil {
+				sections = append(sections, files)
+			}
+		}
+	}
+
+	sealed := 0
+	for _, files := range sections {
+		n, err := sealFiles(c, files)
+		if err != nil {
+			return 0, err
+		}
+		sealed += n
+	}
+	if sealed == 0 {
+		return 0, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return 0, fmt.Errorf("write exercise file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return 0, fmt.Errorf("write exercise file: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return 0, fmt.Errorf("write exercise file: %w", err)
+	}
+	return sealed, nil
+}
+
+// mappingValue returns the value under key in a YAML mapping, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// sealFiles encrypts the unsealed values of a filename -> content mapping
+func sealFiles(c *vault.Cipher, files *yaml.Node) (int, error) {
+	if files.Kind != yaml.MappingNode {
+		return 0, nil
+	}
+	sealed := 0
+	for i := 0; i+1 < len(files.Content); i += 2 {
+		name, value := files.Content[i].Value, files.Content[i+1]
+		if value.Kind != yaml.ScalarNode || vault.IsEncrypted(value.Value) {
+			continue
+		}
+		encrypted, err := c.Encrypt(value.Value)
+		if err != nil {
+			return 0, fmt.Errorf("seal %s: %w", name, err)
+		}
+		value.Value, value.Style = encrypted, yaml.DoubleQuotedStyle
+		sealed++
+	}
+	return sealed, nil
+}
@@ -0,0 +1,80 @@
+package exercise
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/vault"
+)
+
+func TestSealSolution_RoundTrip(t *testing.T) {
+	files := map[string]string{"main.go": "package main\n\nfunc main() {}\n"}
+
+	sealed, err := SealSolution("go-v1", files)
+	if err != nil {
+		t.Fatalf("SealSolution() error = %v", err)
+	}
+	if !vault.IsEncrypted(sealed["main.go"]) {
+		t.Fatalf("SealSolution() = %q, want it encrypted", sealed["main.go"])
+	}
+
+	opened, err := OpenSolution("go-v1", sealed)
+	if err != nil || opened["main.go"] != files["main.go"] {
+		t.Errorf("OpenSolution() = %q, %v; want the original", opened["main.go"], err)
+	}
+	if _, err := OpenSolution("python-v1", sealed); err == nil {
+		t.Error("OpenSolution() with another pack's key should fail")
+	}
+
+	// Plaintext solutions still load
+	if opened, err := OpenSolution("go-v1", files); err != nil || opened["main.go"] != files["main.go"] {
+		t.Errorf("OpenSolution(plaintext) = %q, %v", opened["main.go"], err)
+	}
+}
+
+func TestSealSolutionFile(t *testing.T) {
+	dir := t.TempDir()
+	exDir := filepath.Join(dir, "go-v1", "basics")
+	if err := os.MkdirAll(exDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	pack := "id: go-v1\nname: Go\nversion: \"1.0.0\"\nlanguage: go\nexercises:\n  - basics/hello\n"
+	if err := os.WriteFile(filepath.Join(dir, "go-v1", "pack.yaml"), []byte(pack), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(exDir, "hello.yaml")
+	exerciseYAML := `title: Hello
+# the solution is sealed before release
+solution:
+  main.go: |
+    package main
+    func main() { println("Hello") }
+`
+	if err := os.WriteFile(path, []byte(exerciseYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := SealSolutionFile(path, "go-v1")
+	if err != nil || n != 1 {
+		t.Fatalf("SealSolutionFile() = %d, %v; want 1 file sealed", n, err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "println") || !strings.Contains(string(data), "# the solution is sealed") {
+		t.Errorf("sealed file = %s; want the solution encrypted and comments kept", data)
+	}
+
+	// Sealing again is a no-op
+	if n, err := SealSolutionFile(path, "go-v1"); err != nil || n != 0 {
+		t.Errorf("SealSolutionFile() again = %d, %v; want nothing to seal", n, err)
+	}
+
+	ex, err := NewLoader(dir).LoadExercise("go-v1", "basics/hello")
+	if err != nil {
+		t.Fatalf("LoadExercise() error = %v", err)
+	}
+	if !strings.Contains(ex.Solution["main.go"], `println("Hello")`) {
+		t.Errorf("Solution = %q, want it opened on load", ex.Solution["main.go"])
+	}
+}
@@ -0,0 +1,179 @@
+package exercise
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxPackArchiveBytes bounds what a pack archive may unpack to
+const maxPackArchiveBytes = 32 << 20
+
+// ErrNotModified is returned by a Fetcher when the source still serves
+// the revision already fetched
+var ErrNotModified = errors.New("pack not modified")
+
+// Source records where an installed pack came from, so it can be checked
+// for new versions
+type Source struct {
+	Pack        string     `json:"pack"` // directory under the exercises path
+	URL         string     `json:"url"`
+	Version     string     `json:"version"`  // pack.yaml version installed
+	Revision    string     `json:"revision"` // what the source served: an ETag or a digest
+	InstalledAt time.Time  `json:"installed_at"`
+	CheckedAt   *time.Time `json:"checked_at,omitempty"`
+
+	// Staged is a newer version downloaded and waiting to be applied;
+	// Previous is the version an update replaced, kept for rollback
+	Staged   *SourceVersion `json:"staged,omitempty"`
+	Previous *SourceVersion `json:"previous,omitempty"`
+}
+
+// SourceVersion is one version of a source's pack
+type SourceVersion struct {
+	Version  string    `json:"version"`
+	Revision string    `json:"revision"`
+	At       time.Time `json:"at"`
+}
+
+// Fetcher downloads packs from one kind of source
+type Fetcher interface {
+	// Fetch writes the pack at url into dest, an empty directory, and
+	// returns the revision it fetched. It returns ErrNotModified when the
+	// source still serves revision.
+	Fetch(ctx context.Context, url, revision, dest string) (string, error)
+}
+
+// ArchiveFetcher fetches packs published as .tar.gz archives over HTTP,
+// as a registry serves them. The archive holds pack.yaml and the
+// exercises, at its root or in a single top-level directory.
+type ArchiveFetcher struct {
+	Client *http.Client
+}
+
+// Fetch downloads and unpacks the archive. The revision is the response's
+// ETag, or the archive's digest when the registry sends none.
+func (f ArchiveFetcher) Fetch(ctx context.Context, url, revision, dest string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	if revision != "" && !strings.HasPrefix(revision, "sha256:") {
+		req.Header.Set("If-None-Match", revision)
+	}
+	client := f.Client
+	if client == nil {
+		client = &http.Client{Timeout: 2 * time.Minute}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("download pack: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return "", ErrNotModified
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("download pack: %s", resp.Status)
+	}
+
+	h := sha256.New()
+	body := io.TeeReader(io.LimitReader(resp.Body, maxPackArchiveBytes+1), h)
+	if err := unpackArchive(body, dest); err != nil {
+		return "", err
+	}
+	_, _ = io.Copy(io.Discard, body) // the digest covers the whole archive
+
+	fetched := resp.Header.Get("ETag")
+	if fetched == "" {
+		fetched = "sha256:" + hex.EncodeToString(h.Sum(nil))
+	}
+	if fetched == revision {
+		return "", ErrNotModified
+	}
+	return fetched, nil
+}
+
+// unpackArchive writes a gzipped tarball's directories and regular files
+// into dest. Links and entries escaping dest are skipped.
+func unpackArchive(r io.Reader, dest string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("read pack archive: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	var total int64
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read pack archive: %w", err)
+		}
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+		if name == "." || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			continue
+		}
+		target := filepath.Join(dest, filepath.FromSlash(name))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			total += hdr.Size
+			if total > maxPackArchiveBytes {
+				return fmt.Errorf("pack archive unpacks to more than %d MB", maxPackArchiveBytes>>20)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(out, io.LimitReader(tr, hdr.Size))
+			if cerr := out.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return fmt.Errorf("unpack %s: %w", name, err)
+			}
+		}
+	}
+}
+
+// packRoot returns the directory holding pack.yaml in a fetched pack:
+// dir itself, or its only subdirectory
+func packRoot(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, "pack.yaml")); err == nil {
+		return dir, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		sub := filepath.Join(dir, entries[0].Name())
+		if _, err := os.Stat(filepath.Join(sub, "pack.yaml")); err == nil {
+			return sub, nil
+		}
+	}
+	return "", fmt.Errorf("no pack.yaml in the fetched pack")
+}
@@ -0,0 +1,514 @@
+package exercise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/storage/local"
+	"gopkg.in/yaml.v3"
+)
+
+const collectionSources = "sources"
+
+// packIDRegex matches the pack IDs and orgs an installed pack may have;
+// they name its directory
+var packIDRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+var (
+	ErrSourceNotFound = errors.New("pack was not installed from a source")
+	ErrPackExists     = errors.New("a pack with this ID is already installed")
+	ErrNoUpdate       = errors.New("no update staged for this pack")
+	ErrNoRollback     = errors.New("no previous version to roll back to")
+)
+
+// Update is a newer version of an installed pack, staged and waiting for
+// the user to apply it
+type Update struct {
+	Pack        string    `json:"pack"`
+	URL         string    `json:"url"`
+	FromVersion string    `json:"from_version"`
+	ToVersion   string    `json:"to_version"`
+	StagedAt    time.Time `json:"staged_at"`
+}
+
+// Updater installs packs from their sources and checks them for new
+// versions. A new version is downloaded into a staging area and only
+// replaces the installed one when the user applies it; the version it
+// replaces is kept so the update can be rolled back.
+type Updater struct {
+	packsDir string // the exercises path packs are installed into
+	stateDir string // sources, staged and previous versions
+	sources  *local.Store
+	fetchers map[string]Fetcher
+
+	mu       sync.Mutex
+	onStaged func(Update)
+}
+
+// NewUpdater creates an updater installing into packsDir and keeping its
+// state under stateDir (usually ~/.temper/packs)
+func NewUpdater(packsDir, stateDir string) (*Updater, error) {
+	sources, err := local.NewStore(stateDir)
+	if err != nil {
+		return nil, err
+	}
+	return &Updater{
+		packsDir: packsDir,
+		stateDir: stateDir,
+		sources:  sources,
+		fetchers: map[string]Fetcher{
+			"archive": ArchiveFetcher{},
+			"git":     GitFetcher{},
+		},
+	}, nil
+}
+
+// SetFetcher sets the fetcher for a kind of source
+func (u *Updater) SetFetcher(kind string, f Fetcher) {
+	u.fetchers[kind] = f
+}
+
+// OnStaged sets a function called with each update staged by Check
+func (u *Updater) OnStaged(fn func(Update)) {
+	u.onStaged = fn
+}
+
+// sourceKind returns the kind of fetcher a source URL needs
+func sourceKind(url string) (string, error) {
+	switch {
+	case strings.HasPrefix(url, "git+"):
+		return "git", nil
+	case strings.HasPrefix(url, "https://"), strings.HasPrefix(url, "http://"):
+		return "archive", nil
+	}
+	return "", fmt.Errorf("unsupported pack source %q; expected an http(s) archive URL or a git+ URL", url)
+}
+
+// sourceKey names the record of a pack's source; an org pack's ID has a
+// slash, which '~' stands in for as no pack ID can contain it
+func sourceKey(pack string) string {
+	return strings.ReplaceAll(pack, "/", "~")
+}
+
+// Sources returns every installed source, by pack ID
+func (u *Updater) Sources() ([]*Source, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.listSources()
+}
+
+func (u *Updater) listSources() ([]*Source, error) {
+	ids, err := u.sources.List(collectionSources)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+	sources := make([]*Source, 0, len(ids))
+	for _, id := range ids {
+		var src Source
+		if err := u.sources.Load(collectionSources, id, &src); err != nil {
+			slog.Warn("unreadable pack source", "pack", id, "error", err)
+			continue
+		}
+		sources = append(sources, &src)
+	}
+	return sources, nil
+}
+
+func (u *Updater) loadSource(pack string) (*Source, error) {
+	var src Source
+	if err := u.sources.Load(collectionSources, sourceKey(pack), &src); err != nil {
+		if errors.Is(err, local.ErrNotFound) {
+			return nil, ErrSourceNotFound
+		}
+		return nil, err
+	}
+	return &src, nil
+}
+
+// Pending returns the updates staged and not applied yet
+func (u *Updater) Pending() ([]Update, error) {
+	sources, err := u.Sources()
+	if err != nil {
+		return nil, err
+	}
+	updates := []Update{}
+	for _, src := range sources {
+		if src.Staged != nil {
+			updates = append(updates, stagedUpdate(src))
+		}
+	}
+	return updates, nil
+}
+
+func stagedUpdate(src *Source) Update {
+	return Update{
+		Pack:        src.Pack,
+		URL:         src.URL,
+		FromVersion: src.Version,
+		ToVersion:   src.Staged.Version,
+		StagedAt:    src.Staged.At,
+	}
+}
+
+// Install fetches the pack at url and installs it under its ID, in its
+// org's directory for an org pack. A pack already installed under that
+// ID, from a source or not, is left alone.
+func (u *Updater) Install(ctx context.Context, url string) (*Source, error) {
+	fetched, err := u.fetch(ctx, url, "")
+	if err != nil {
+		return nil, err
+	}
+	defer fetched.cleanup()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	dest := filepath.Join(u.packsDir, filepath.FromSlash(fetched.pack))
+	if _, err := os.Stat(dest); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrPackExists, fetched.pack)
+	}
+	if org, _, ok := strings.Cut(fetched.pack, "/"); ok && isPackDir(filepath.Join(u.packsDir, org)) {
+		return nil, fmt.Errorf("%w: org %s is installed as a pack", ErrPackConflict, org)
+	}
+	if err := os.MkdirAll(u.packsDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := movePack(fetched.root, dest); err != nil {
+		return nil, fmt.Errorf("install pack: %w", err)
+	}
+
+	src := &Source{
+		Pack:        fetched.pack,
+		URL:         url,
+		Version:     fetched.version,
+		Revision:    fetched.revision,
+		InstalledAt: time.Now(),
+	}
+	if err := u.sources.Save(collectionSources, sourceKey(src.Pack), src); err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+// Check fetches every source's latest version and stages the ones that
+// changed. A source that fails is logged and skipped; the error returned
+// joins every failure.
+func (u *Updater) Check(ctx context.Context) ([]Update, error) {
+	sources, err := u.Sources()
+	if err != nil {
+		return nil, err
+	}
+
+	var staged []Update
+	var errs []error
+	for _, src := range sources {
+		update, err := u.checkSource(ctx, src)
+		if err != nil {
+			slog.Warn("pack update check failed", "pack", src.Pack, "url", src.URL, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", src.Pack, err))
+			continue
+		}
+		if update != nil {
+			staged = append(staged, *update)
+			if u.onStaged != nil {
+				u.onStaged(*update)
+			}
+		}
+	}
+	return staged, errors.Join(errs...)
+}
+
+// checkSource stages the source's latest version if it differs from the
+// installed one and from the one already staged
+func (u *Updater) checkSource(ctx context.Context, src *Source) (*Update, error) {
+	known := src.Revision
+	if src.Staged != nil {
+		known = src.Staged.Revision
+	}
+	fetched, err := u.fetch(ctx, src.URL, known)
+	now := time.Now()
+	if errors.Is(err, ErrNotModified) {
+		return nil, u.updateSource(src.Pack, func(s *Source) { s.CheckedAt = &now })
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer fetched.cleanup()
+	if fetched.pack != src.Pack {
+		return nil, fmt.Errorf("source now serves pack %q", fetched.pack)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	current, err := u.loadSource(src.Pack)
+	if err != nil {
+		return nil, err
+	}
+	current.CheckedAt = &now
+	if fetched.revision == current.Revision {
+		// Back to what is installed: nothing to apply any more
+		if current.Staged != nil {
+			_ = os.RemoveAll(filepath.Join(u.stateDir, "staging", src.Pack))
+			current.Staged = nil
+		}
+		return nil, u.sources.Save(collectionSources, sourceKey(current.Pack), current)
+	}
+
+	staging := filepath.Join(u.stateDir, "staging", src.Pack)
+	if err := os.RemoveAll(staging); err != nil {
+		return nil, err
+	}
+	if err := movePack(fetched.root, staging); err != nil {
+		return nil, fmt.Errorf("stage pack: %w", err)
+	}
+	current.Staged = &SourceVersion{Version: fetched.version, Revision: fetched.revision, At: now}
+	if err := u.sources.Save(collectionSources, sourceKey(current.Pack), current); err != nil {
+		return nil, err
+	}
+	update := stagedUpdate(current)
+	return &update, nil
+}
+
+// Apply switches a pack to its staged version, keeping the installed one
+// for Rollback
+func (u *Updater) Apply(pack string) (*Source, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	src, err := u.loadSource(pack)
+	if err != nil {
+		return nil, err
+	}
+	if src.Staged == nil {
+		return nil, ErrNoUpdate
+	}
+
+	staging := filepath.Join(u.stateDir, "staging", pack)
+	previous := filepath.Join(u.stateDir, "previous", pack)
+	if err := os.RemoveAll(previous); err != nil {
+		return nil, err
+	}
+	if err := u.swap(staging, previous, pack); err != nil {
+		return nil, fmt.Errorf("apply update: %w", err)
+	}
+
+	src.Previous = &SourceVersion{Version: src.Version, Revision: src.Revision, At: time.Now()}
+	src.Version, src.Revision = src.Staged.Version, src.Staged.Revision
+	src.Staged = nil
+	if err := u.sources.Save(collectionSources, sourceKey(pack), src); err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+// Rollback switches a pack back to the version its last update replaced.
+// The version rolled back from is kept in turn, so the update can be
+// applied again by rolling back once more.
+func (u *Updater) Rollback(pack string) (*Source, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	src, err := u.loadSource(pack)
+	if err != nil {
+		return nil, err
+	}
+	if src.Previous == nil {
+		return nil, ErrNoRollback
+	}
+
+	previous := filepath.Join(u.stateDir, "previous", pack)
+	swapped := previous + ".swap"
+	if err := os.RemoveAll(swapped); err != nil {
+		return nil, err
+	}
+	if err := movePack(previous, swapped); err != nil {
+		return nil, fmt.Errorf("roll back: %w", err)
+	}
+	if err := u.swap(swapped, previous, pack); err != nil {
+		_ = movePack(swapped, previous)
+		return nil, fmt.Errorf("roll back: %w", err)
+	}
+
+	rolledBack := &SourceVersion{Version: src.Version, Revision: src.Revision, At: time.Now()}
+	src.Version, src.Revision = src.Previous.Version, src.Previous.Revision
+	src.Previous = rolledBack
+	if err := u.sources.Save(collectionSources, sourceKey(pack), src); err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+// swap installs the pack in next, moving the installed one to keep. If
+// the install fails, the installed pack is put back.
+func (u *Updater) swap(next, keep, pack string) error {
+	installed := filepath.Join(u.packsDir, filepath.FromSlash(pack))
+	if err := os.MkdirAll(filepath.Dir(keep), 0755); err != nil {
+		return err
+	}
+	if err := movePack(installed, keep); err != nil {
+		return err
+	}
+	if err := movePack(next, installed); err != nil {
+		_ = movePack(keep, installed)
+		return err
+	}
+	return nil
+}
+
+func (u *Updater) updateSource(pack string, fn func(*Source)) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	src, err := u.loadSource(pack)
+	if err != nil {
+		return err
+	}
+	fn(src)
+	return u.sources.Save(collectionSources, sourceKey(pack), src)
+}
+
+// Start checks for updates every interval until ctx is done
+func (u *Updater) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if staged, err := u.Check(ctx); len(staged) > 0 || err != nil {
+					slog.Info("pack update check", "staged", len(staged), "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// fetchedPack is a pack downloaded and validated, not installed yet
+type fetchedPack struct {
+	dir      string // temporary directory to remove
+	root     string // holds pack.yaml
+	pack     string
+	version  string
+	revision string
+}
+
+func (f *fetchedPack) cleanup() {
+	_ = os.RemoveAll(f.dir)
+}
+
+// fetch downloads the pack at url into a temporary directory and lints
+// it; a pack with problems is refused
+func (u *Updater) fetch(ctx context.Context, url, revision string) (*fetchedPack, error) {
+	kind, err := sourceKind(url)
+	if err != nil {
+		return nil, err
+	}
+	fetcher, ok := u.fetchers[kind]
+	if !ok {
+		return nil, fmt.Errorf("no fetcher for %s pack sources", kind)
+	}
+
+	tmp := filepath.Join(u.stateDir, "tmp")
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp(tmp, "fetch-")
+	if err != nil {
+		return nil, err
+	}
+	fetched := &fetchedPack{dir: dir}
+	valid := false
+	defer func() {
+		if !valid {
+			fetched.cleanup()
+		}
+	}()
+
+	// Fetched into a subdirectory so the pack can be renamed to its ID
+	// next to it for loading
+	download := filepath.Join(dir, "download")
+	if err := os.Mkdir(download, 0755); err != nil {
+		return nil, err
+	}
+	if fetched.revision, err = fetcher.Fetch(ctx, url, revision, download); err != nil {
+		return nil, err
+	}
+	root, err := packRoot(download)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "pack.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	var packFile PackFile
+	if err := yaml.Unmarshal(data, &packFile); err != nil {
+		return nil, fmt.Errorf("parse pack file: %w", err)
+	}
+	if !packIDRegex.MatchString(packFile.ID) {
+		return nil, fmt.Errorf("pack id %q must be lowercase letters, digits, '.', '_' or '-'", packFile.ID)
+	}
+	if packFile.Org != "" && !packIDRegex.MatchString(packFile.Org) {
+		return nil, fmt.Errorf("pack org %q must be lowercase letters, digits, '.', '_' or '-'", packFile.Org)
+	}
+
+	fetched.pack, fetched.version = packFile.PackID(), packFile.Version
+	fetched.root = filepath.Join(dir, filepath.FromSlash(fetched.pack))
+	if err := movePack(root, fetched.root); err != nil {
+		return nil, err
+	}
+	if problems := NewLoader(dir).Lint(fetched.pack); len(problems) > 0 {
+		return nil, fmt.Errorf("invalid pack: %s", strings.Join(problems, "; "))
+	}
+	valid = true
+	return fetched, nil
+}
+
+// movePack moves a pack directory, copying it when the two paths are on
+// different file systems
+func movePack(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+	err := filepath.WalkDir(from, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+	if err != nil {
+		_ = os.RemoveAll(to)
+		return err
+	}
+	return os.RemoveAll(from)
+}
@@ -0,0 +1,276 @@
+package exercise
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// packArchive builds a .tar.gz holding files
+func packArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func acmePack(version string) map[string]string {
+	return map[string]string{
+		"acme-go/pack.yaml":         "id: acme-go\nname: Acme Go\nversion: " + version + "\nlanguage: go\nexercises:\n  - basics/hello\n",
+		"acme-go/basics/hello.yaml": "id: hello\ntitle: Hello " + version + "\ndifficulty: beginner\nstarter:\n  main.go: package main\n",
+	}
+}
+
+// packRegistry serves one archive at /acme-go.tar.gz, with an ETag of
+// its version
+type packRegistry struct {
+	mu      sync.Mutex
+	version string
+	archive []byte
+}
+
+func (r *packRegistry) publish(t *testing.T, version string, files map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.version, r.archive = version, packArchive(t, files)
+}
+
+func (r *packRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	etag := `"` + r.version + `"`
+	if req.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	_, _ = w.Write(r.archive)
+}
+
+func newTestUpdater(t *testing.T) (*Updater, string) {
+	t.Helper()
+	packsDir := t.TempDir()
+	u, err := NewUpdater(packsDir, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u, packsDir
+}
+
+func exerciseTitle(t *testing.T, packsDir string) string {
+	t.Helper()
+	ex, err := NewLoader(packsDir).LoadExercise("acme-go", "basics/hello")
+	if err != nil {
+		t.Fatalf("LoadExercise() error = %v", err)
+	}
+	return ex.Title
+}
+
+func TestUpdater_StageApplyRollback(t *testing.T) {
+	registry := &packRegistry{}
+	registry.publish(t, "1.0.0", acmePack("1.0.0"))
+	srv := httptest.NewServer(registry)
+	defer srv.Close()
+
+	u, packsDir := newTestUpdater(t)
+	var notified []Update
+	u.OnStaged(func(up Update) { notified = append(notified, up) })
+	ctx := context.Background()
+
+	src, err := u.Install(ctx, srv.URL+"/acme-go.tar.gz")
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if src.Pack != "acme-go" || src.Version != "1.0.0" || src.Revision != `"1.0.0"` {
+		t.Errorf("Install() = %+v", src)
+	}
+	if got := exerciseTitle(t, packsDir); got != "Hello 1.0.0" {
+		t.Errorf("installed title = %q", got)
+	}
+
+	// Nothing new yet
+	if staged, err := u.Check(ctx); err != nil || len(staged) != 0 {
+		t.Fatalf("Check() = %v, %v; want nothing staged", staged, err)
+	}
+
+	registry.publish(t, "1.1.0", acmePack("1.1.0"))
+	staged, err := u.Check(ctx)
+	if err != nil || len(staged) != 1 {
+		t.Fatalf("Check() = %v, %v; want one update", staged, err)
+	}
+	if staged[0].FromVersion != "1.0.0" || staged[0].ToVersion != "1.1.0" {
+		t.Errorf("staged = %+v", staged[0])
+	}
+	if len(notified) != 1 {
+		t.Errorf("OnStaged called %d times, want 1", len(notified))
+	}
+	// Staged, not applied: the installed pack is unchanged
+	if got := exerciseTitle(t, packsDir); got != "Hello 1.0.0" {
+		t.Errorf("title before apply = %q", got)
+	}
+	// Checking again doesn't stage or announce it twice
+	if staged, err := u.Check(ctx); err != nil || len(staged) != 0 || len(notified) != 1 {
+		t.Errorf("second Check() = %v, %v; notified %d", staged, err, len(notified))
+	}
+	if pending, err := u.Pending(); err != nil || len(pending) != 1 {
+		t.Errorf("Pending() = %v, %v", pending, err)
+	}
+
+	src, err = u.Apply("acme-go")
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if src.Version != "1.1.0" || src.Staged != nil || src.Previous == nil || src.Previous.Version != "1.0.0" {
+		t.Errorf("Apply() = %+v", src)
+	}
+	if got := exerciseTitle(t, packsDir); got != "Hello 1.1.0" {
+		t.Errorf("title after apply = %q", got)
+	}
+	if _, err := u.Apply("acme-go"); !errors.Is(err, ErrNoUpdate) {
+		t.Errorf("second Apply() error = %v, want ErrNoUpdate", err)
+	}
+
+	src, err = u.Rollback("acme-go")
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if src.Version != "1.0.0" || src.Previous.Version != "1.1.0" {
+		t.Errorf("Rollback() = %+v", src)
+	}
+	if got := exerciseTitle(t, packsDir); got != "Hello 1.0.0" {
+		t.Errorf("title after rollback = %q", got)
+	}
+}
+
+func TestUpdater_RejectsInvalidUpdate(t *testing.T) {
+	registry := &packRegistry{}
+	registry.publish(t, "1.0.0", acmePack("1.0.0"))
+	srv := httptest.NewServer(registry)
+	defer srv.Close()
+
+	u, _ := newTestUpdater(t)
+	ctx := context.Background()
+	if _, err := u.Install(ctx, srv.URL+"/acme-go.tar.gz"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The pack lists an exercise it doesn't ship
+	broken := acmePack("2.0.0")
+	delete(broken, "acme-go/basics/hello.yaml")
+	registry.publish(t, "2.0.0", broken)
+	if staged, err := u.Check(ctx); err == nil || len(staged) != 0 {
+		t.Errorf("Check() = %v, %v; want the broken update refused", staged, err)
+	}
+	if pending, _ := u.Pending(); len(pending) != 0 {
+		t.Errorf("Pending() = %v, want none", pending)
+	}
+}
+
+func TestUpdater_InstallKeepsExistingPack(t *testing.T) {
+	registry := &packRegistry{}
+	registry.publish(t, "1.0.0", acmePack("1.0.0"))
+	srv := httptest.NewServer(registry)
+	defer srv.Close()
+
+	u, packsDir := newTestUpdater(t)
+	if err := os.MkdirAll(filepath.Join(packsDir, "acme-go"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Install(context.Background(), srv.URL+"/acme-go.tar.gz"); !errors.Is(err, ErrPackExists) {
+		t.Errorf("Install() error = %v, want ErrPackExists", err)
+	}
+	if _, err := u.Install(context.Background(), "ftp://example.com/pack.tar.gz"); err == nil {
+		t.Error("Install() should refuse an unsupported source")
+	}
+}
+
+func TestUnpackArchive_SkipsEscapingEntries(t *testing.T) {
+	dest := t.TempDir()
+	archive := packArchive(t, map[string]string{
+		"pack.yaml":        "id: p\n",
+		"../outside.yaml":  "nope",
+		"/etc/passwd.yaml": "nope",
+	})
+	if err := unpackArchive(bytes.NewReader(archive), dest); err != nil {
+		t.Fatalf("unpackArchive() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "pack.yaml")); err != nil {
+		t.Errorf("pack.yaml not unpacked: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dest), "outside.yaml")); err == nil {
+		t.Error("entry escaping dest was unpacked")
+	}
+}
+
+func TestUpdater_OrgPack(t *testing.T) {
+	orgPack := func(version string) map[string]string {
+		return map[string]string{
+			"pack.yaml":         "id: go\norg: acme\nname: Acme Go\nversion: " + version + "\nlanguage: go\nexercises:\n  - basics/hello\n",
+			"basics/hello.yaml": "id: hello\ntitle: Hello " + version + "\ndifficulty: beginner\nstarter:\n  main.go: package main\n",
+		}
+	}
+	registry := &packRegistry{}
+	registry.publish(t, "1.0.0", orgPack("1.0.0"))
+	srv := httptest.NewServer(registry)
+	defer srv.Close()
+
+	u, packsDir := newTestUpdater(t)
+	ctx := context.Background()
+	src, err := u.Install(ctx, srv.URL+"/acme-go.tar.gz")
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if src.Pack != "acme/go" {
+		t.Errorf("Install() pack = %q, want acme/go", src.Pack)
+	}
+	if _, err := os.Stat(filepath.Join(packsDir, "acme", "go", "pack.yaml")); err != nil {
+		t.Errorf("org pack not installed in its org's directory: %v", err)
+	}
+
+	registry.publish(t, "1.1.0", orgPack("1.1.0"))
+	if staged, err := u.Check(ctx); err != nil || len(staged) != 1 || staged[0].Pack != "acme/go" {
+		t.Fatalf("Check() = %v, %v", staged, err)
+	}
+	if _, err := u.Apply("acme/go"); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	ex, err := NewLoader(packsDir).LoadExerciseByID("acme/go/basics/hello")
+	if err != nil || ex.Title != "Hello 1.1.0" {
+		t.Errorf("LoadExerciseByID() = %v, %v", ex, err)
+	}
+
+	// An org can't be installed over a pack of the same name
+	other, otherDir := newTestUpdater(t)
+	if err := os.MkdirAll(filepath.Join(otherDir, "acme"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(otherDir, "acme", "pack.yaml"), []byte("id: acme\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Install(ctx, srv.URL+"/acme-go.tar.gz"); !errors.Is(err, ErrPackConflict) {
+		t.Errorf("Install() over a pack named like the org: error = %v, want ErrPackConflict", err)
+	}
+}
@@ -0,0 +1,95 @@
+package fixture
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+// Markers a plugin test puts anywhere in the code to get a failing run
+const (
+	FailBuild = "temper:mock-build-fail"
+	FailTests = "temper:mock-test-fail"
+)
+
+// TestDuration is how long every mock test run reports it took
+const TestDuration = 10 * time.Millisecond
+
+var testFunc = regexp.MustCompile(`(?m)^func (Test\w*)\(`)
+
+// Executor runs nothing. Code is always formatted, builds unless it
+// contains FailBuild, and its Test functions pass unless it contains
+// FailTests, in which case the first one fails.
+type Executor struct{}
+
+func (Executor) RunFormat(ctx context.Context, code map[string]string) (*runner.FormatResult, error) {
+	return &runner.FormatResult{OK: true}, nil
+}
+
+func (Executor) RunFormatFix(ctx context.Context, code map[string]string) (map[string]string, error) {
+	return code, nil
+}
+
+func (Executor) RunBuild(ctx context.Context, code map[string]string) (*runner.BuildResult, error) {
+	if file, ok := marked(code, FailBuild); ok {
+		return &runner.BuildResult{Output: fmt.Sprintf("./%s:1:1: undefined: mockBuildFailure\n", file)}, nil
+	}
+	return &runner.BuildResult{OK: true}, nil
+}
+
+func (Executor) RunTests(ctx context.Context, code map[string]string, flags []string) (*runner.TestResult, error) {
+	_, fail := marked(code, FailTests)
+
+	var names []string
+	for _, file := range sortedFiles(code) {
+		for _, m := range testFunc.FindAllStringSubmatch(code[file], -1) {
+			names = append(names, m[1])
+		}
+	}
+	if len(names) == 0 {
+		names = []string{"TestMock"}
+	}
+
+	// go test -json, which is what the run parser reads
+	var out strings.Builder
+	enc := json.NewEncoder(&out)
+	elapsed := TestDuration.Seconds() / float64(len(names))
+	for i, name := range names {
+		action := "pass"
+		if fail && i == 0 {
+			action = "fail"
+		}
+		_ = enc.Encode(runner.TestEvent{Action: "run", Package: "mock", Test: name})
+		if action == "fail" {
+			_ = enc.Encode(runner.TestEvent{Action: "output", Package: "mock", Test: name, Output: "    mock_test.go:1: got 0, want 1\n"})
+		}
+		_ = enc.Encode(runner.TestEvent{Action: action, Package: "mock", Test: name, Elapsed: elapsed})
+	}
+
+	return &runner.TestResult{OK: !fail, Output: out.String(), Duration: TestDuration}, nil
+}
+
+// marked reports the first file, in name order, that contains marker
+func marked(code map[string]string, marker string) (string, bool) {
+	for _, file := range sortedFiles(code) {
+		if strings.Contains(code[file], marker) {
+			return file, true
+		}
+	}
+	return "", false
+}
+
+func sortedFiles(code map[string]string) []string {
+	files := make([]string, 0, len(code))
+	for f := range code {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
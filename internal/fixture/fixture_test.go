@@ -0,0 +1,129 @@
+package fixture
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/felixgeelhaar/temper/internal/pairing"
+	"github.com/felixgeelhaar/temper/internal/sandbox"
+)
+
+func TestReply(t *testing.T) {
+	p := pairing.NewPrompter()
+
+	explain := Reply(&llm.Request{
+		System:   p.ExplainErrorsSystemPrompt(),
+		Messages: []llm.Message{{Role: llm.RoleUser, Content: p.BuildExplainErrorsPrompt([]string{"a", "b", "c"})}},
+	})
+	if got, err := pairing.ParseExplanations(explain, 3); err != nil || len(got) != 3 {
+		t.Errorf("explanations = %q (%v), want 3 parseable", explain, err)
+	}
+
+	review := Reply(&llm.Request{SystemBlocks: []llm.SystemContentBlock{{Text: p.SpecReviewSystemPrompt()}}})
+	if _, _, err := pairing.ParseSpecReview(review); err != nil {
+		t.Errorf("spec review %q does not parse: %v", review, err)
+	}
+
+	if got := Reply(&llm.Request{System: "You are a pairing partner."}); got != Hint {
+		t.Errorf("Reply() = %q, want Hint", got)
+	}
+}
+
+func TestProvider_StreamMatchesGenerate(t *testing.T) {
+	req := &llm.Request{System: "hint"}
+	resp, err := Provider{}.Generate(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream, err := Provider{}.GenerateStream(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	done := false
+	for chunk := range stream {
+		sb.WriteString(chunk.Content)
+		done = done || chunk.Done
+	}
+	if !done || sb.String() != resp.Content {
+		t.Errorf("stream = %q (done %v), want %q", sb.String(), done, resp.Content)
+	}
+}
+
+func TestExecutor(t *testing.T) {
+	ctx := context.Background()
+	code := map[string]string{
+		"a_test.go": "func TestA(t *testing.T) {}\nfunc TestB(t *testing.T) {}\n",
+	}
+
+	build, _ := Executor{}.RunBuild(ctx, code)
+	tests, _ := Executor{}.RunTests(ctx, code, nil)
+	if !build.OK || !tests.OK || strings.Count(tests.Output, `"Action":"pass"`) != 2 {
+		t.Errorf("plain code: build %v, tests %v\n%s", build.OK, tests.OK, tests.Output)
+	}
+
+	code["a_test.go"] += "// " + FailTests + "\n"
+	tests, _ = Executor{}.RunTests(ctx, code, nil)
+	if tests.OK || !strings.Contains(tests.Output, `"Action":"fail","Package":"mock","Test":"TestA"`) {
+		t.Errorf("marked tests should fail TestA:\n%s", tests.Output)
+	}
+
+	code["main.go"] = "// " + FailBuild
+	if build, _ := (Executor{}).RunBuild(ctx, code); build.OK || !strings.Contains(build.Output, "main.go") {
+		t.Errorf("marked build = %+v, want a failure in main.go", build)
+	}
+}
+
+func TestSandboxes_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	m := NewSandboxes()
+
+	sb, err := m.Create(ctx, "s1", sandbox.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Create(ctx, "s1", sandbox.Config{}); err != sandbox.ErrSessionHasSandbox {
+		t.Errorf("second Create() error = %v, want ErrSessionHasSandbox", err)
+	}
+
+	if err := m.AttachCode(ctx, sb.ID, map[string]string{"main.go": "package main"}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := m.Execute(ctx, sb.ID, []string{"go", "test"}, 0)
+	if err != nil || !strings.Contains(res.Stdout, "$ go test\nmain.go") {
+		t.Errorf("Execute() = %+v, %v", res, err)
+	}
+
+	if err := m.Pause(ctx, sb.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Execute(ctx, sb.ID, []string{"ls"}, 0); err != sandbox.ErrSandboxNotReady {
+		t.Errorf("Execute() on paused sandbox error = %v, want ErrSandboxNotReady", err)
+	}
+	if err := m.Resume(ctx, sb.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Destroy(ctx, sb.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.GetBySession(ctx, "s1"); err != sandbox.ErrSandboxNotFound {
+		t.Errorf("GetBySession() after Destroy error = %v, want ErrSandboxNotFound", err)
+	}
+}
+
+func TestTestEventsAreGoTestJSON(t *testing.T) {
+	res, _ := Executor{}.RunTests(context.Background(), nil, nil)
+	for _, line := range strings.Split(strings.TrimSpace(res.Output), "\n") {
+		var ev map[string]any
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("line %q is not JSON: %v", line, err)
+		}
+	}
+	if !strings.Contains(res.Output, `"Test":"TestMock"`) {
+		t.Errorf("code without tests should report TestMock:\n%s", res.Output)
+	}
+}
@@ -0,0 +1,122 @@
+// Package fixture provides deterministic stand-ins for the daemon's LLM
+// provider, runner and sandbox manager. `temper mockd` serves the v1 API
+// over them so editor plugin test suites need neither LLM keys nor Docker,
+// and the same request always gets the same response.
+package fixture
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+// ProviderName is the name the mock provider registers under
+const ProviderName = "mock"
+
+// Model is the model the mock provider reports
+const Model = "mock-1"
+
+// Canned replies. Hint is plain prose so it passes the clamp at every level.
+const (
+	Hint             = "Look at what the failing test expects and compare it with what your function returns for that input."
+	Explanation      = "This error points at the line shown in the output; start reading there."
+	SpecReviewResult = `{"summary": "The spec reads clearly.", "findings": []}`
+	PackSummary      = `{"summary": "Steady progress across the pack.", "strengths": [], "gaps": []}`
+	TestExplanation  = "The test expected a different result than your function returned; follow its call into your code from the line the output names."
+)
+
+var explainCount = regexp.MustCompile(`Explain these (\d+) errors`)
+
+// Provider answers every request with a canned reply chosen from the
+// request. Streams send the reply one word per chunk.
+type Provider struct{}
+
+func (Provider) Name() string {
+	return ProviderName
+}
+
+// DefaultModel reports Model
+func (Provider) DefaultModel() string {
+	return Model
+}
+
+func (Provider) SupportsStreaming() bool {
+	return true
+}
+
+func (Provider) Generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	content := Reply(req)
+	return &llm.Response{
+		Content:      content,
+		FinishReason: "stop",
+		Usage: llm.Usage{
+			InputTokens:  len(strings.Fields(requestText(req))),
+			OutputTokens: len(strings.Fields(content)),
+		},
+	}, nil
+}
+
+func (Provider) GenerateStream(ctx context.Context, req *llm.Request) (<-chan llm.StreamChunk, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	words := strings.SplitAfter(Reply(req), " ")
+	ch := make(chan llm.StreamChunk, len(words)+1)
+	for _, w := range words {
+		ch <- llm.StreamChunk{Content: w}
+	}
+	ch <- llm.StreamChunk{Done: true}
+	close(ch)
+	return ch, nil
+}
+
+// Reply returns the canned reply to req: a JSON array for error
+// explanations, JSON objects for spec reviews and pack summaries, and Hint
+// otherwise
+func Reply(req *llm.Request) string {
+	system := req.System
+	for _, b := range req.SystemBlocks {
+		system += "\n" + b.Text
+	}
+
+	switch {
+	case strings.Contains(system, "Output ONLY a JSON array of strings"):
+		n := 1
+		for _, m := range req.Messages {
+			if match := explainCount.FindStringSubmatch(m.Content); match != nil {
+				n, _ = strconv.Atoi(match[1])
+			}
+		}
+		explanations := make([]string, n)
+		for i := range explanations {
+			explanations[i] = Explanation
+		}
+		out, _ := json.Marshal(explanations)
+		return string(out)
+	case strings.Contains(system, "critical reviewer of product specifications"):
+		return SpecReviewResult
+	case strings.Contains(system, "learner's work across an exercise pack"):
+		return PackSummary
+	case strings.Contains(system, "explaining why a test failed"):
+		return TestExplanation
+	default:
+		return Hint
+	}
+}
+
+func requestText(req *llm.Request) string {
+	var sb strings.Builder
+	sb.WriteString(req.System)
+	for _, m := range req.Messages {
+		sb.WriteString(" ")
+		sb.WriteString(m.Content)
+	}
+	return sb.String()
+}
@@ -0,0 +1,211 @@
+package fixture
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/sandbox"
+	"github.com/google/uuid"
+)
+
+// Sandboxes keeps sandboxes in memory with the same lifecycle rules as the
+// Docker-backed manager. Execute echoes the command and lists the attached
+// files instead of running anything.
+type Sandboxes struct {
+	mu        sync.Mutex
+	sandboxes map[string]*sandbox.Sandbox
+	code      map[string]map[string]string
+}
+
+// NewSandboxes returns an empty in-memory sandbox manager
+func NewSandboxes() *Sandboxes {
+	return &Sandboxes{
+		sandboxes: make(map[string]*sandbox.Sandbox),
+		code:      make(map[string]map[string]string),
+	}
+}
+
+func (m *Sandboxes) Create(ctx context.Context, sessionID string, cfg sandbox.Config) (*sandbox.Sandbox, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	active := 0
+	for _, sb := range m.sandboxes {
+		if !sb.IsActive() {
+			continue
+		}
+		if sb.SessionID == sessionID {
+			return nil, sandbox.ErrSessionHasSandbox
+		}
+		active++
+	}
+	if active >= sandbox.MaxConcurrentSandboxes {
+		return nil, sandbox.ErrMaxSandboxes
+	}
+
+	if cfg.Image == "" {
+		cfg = sandbox.DefaultConfig()
+	}
+	if cfg.IdleTTL == 0 {
+		cfg.IdleTTL = sandbox.DefaultIdleTTL
+	}
+
+	now := time.Now()
+	sb := &sandbox.Sandbox{
+		ID:          uuid.New().String(),
+		SessionID:   sessionID,
+		ContainerID: "mock",
+		Language:    cfg.Language,
+		Image:       cfg.Image,
+		Status:      sandbox.StatusReady,
+		MemoryMB:    cfg.MemoryMB,
+		CPULimit:    cfg.CPULimit,
+		NetworkOff:  cfg.NetworkOff,
+		ExpiresAt:   now.Add(cfg.IdleTTL),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	m.sandboxes[sb.ID] = sb
+	copied := *sb
+	return &copied, nil
+}
+
+func (m *Sandboxes) Get(ctx context.Context, id string) (*sandbox.Sandbox, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sb, ok := m.sandboxes[id]
+	if !ok {
+		return nil, sandbox.ErrSandboxNotFound
+	}
+	copied := *sb
+	return &copied, nil
+}
+
+func (m *Sandboxes) GetBySession(ctx context.Context, sessionID string) (*sandbox.Sandbox, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, sb := range m.sandboxes {
+		if sb.SessionID == sessionID && sb.IsActive() {
+			copied := *sb
+			return &copied, nil
+		}
+	}
+	return nil, sandbox.ErrSandboxNotFound
+}
+
+func (m *Sandboxes) AttachCode(ctx context.Context, sandboxID string, code map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sb, err := m.ready(sandboxID)
+	if err != nil {
+		return err
+	}
+	files := make(map[string]string, len(code))
+	for k, v := range code {
+		files[k] = v
+	}
+	m.code[sb.ID] = files
+	sb.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *Sandboxes) Execute(ctx context.Context, sandboxID string, cmd []string, timeout time.Duration) (*sandbox.ExecResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sb, err := m.ready(sandboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(m.code[sb.ID]))
+	for f := range m.code[sb.ID] {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+
+	now := time.Now()
+	sb.LastExecAt = &now
+	sb.UpdatedAt = now
+	return &sandbox.ExecResult{
+		Stdout:   fmt.Sprintf("$ %s\n%s", strings.Join(cmd, " "), strings.Join(append(files, ""), "\n")),
+		Duration: TestDuration,
+	}, nil
+}
+
+func (m *Sandboxes) Pause(ctx context.Context, id string) error {
+	return m.setStatus(id, sandbox.StatusReady, sandbox.StatusPaused)
+}
+
+func (m *Sandboxes) Resume(ctx context.Context, id string) error {
+	return m.setStatus(id, sandbox.StatusPaused, sandbox.StatusReady)
+}
+
+func (m *Sandboxes) Destroy(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sb, ok := m.sandboxes[id]
+	if !ok {
+		return sandbox.ErrSandboxNotFound
+	}
+	sb.Status = sandbox.StatusDestroyed
+	sb.UpdatedAt = time.Now()
+	delete(m.code, id)
+	return nil
+}
+
+// Cleanup destroys expired sandboxes
+func (m *Sandboxes) Cleanup(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for id, sb := range m.sandboxes {
+		if sb.IsActive() && sb.IsExpired() {
+			sb.Status = sandbox.StatusDestroyed
+			delete(m.code, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// StartCleanupLoop does nothing; mock sandboxes hold no resources
+func (m *Sandboxes) StartCleanupLoop(ctx context.Context, interval time.Duration) {}
+
+func (m *Sandboxes) Close(ctx context.Context) error {
+	return nil
+}
+
+// ready returns the sandbox if it can take code and commands. Callers
+// hold mu.
+func (m *Sandboxes) ready(id string) (*sandbox.Sandbox, error) {
+	sb, ok := m.sandboxes[id]
+	if !ok {
+		return nil, sandbox.ErrSandboxNotFound
+	}
+	if sb.IsExpired() {
+		return nil, sandbox.ErrSandboxExpired
+	}
+	if sb.Status != sandbox.StatusReady {
+		return nil, sandbox.ErrSandboxNotReady
+	}
+	return sb, nil
+}
+
+func (m *Sandboxes) setStatus(id string, from, to sandbox.Status) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sb, ok := m.sandboxes[id]
+	if !ok {
+		return sandbox.ErrSandboxNotFound
+	}
+	if sb.Status != from {
+		return sandbox.ErrSandboxNotReady
+	}
+	sb.Status = to
+	sb.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,230 @@
+// Package godoc looks up standard library documentation in the local Go
+// installation, so explanations can quote real signatures and doc
+// comments. It reads GOROOT's sources and never touches the network.
+package godoc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned for symbols the standard library doesn't have
+var ErrNotFound = errors.New("not found in the standard library")
+
+// MaxLength caps the documentation returned for one symbol
+const MaxLength = 4000
+
+// Lookup finds documentation in GOROOT. Parsed packages are cached.
+type Lookup struct {
+	ctx build.Context
+
+	mu   sync.Mutex
+	pkgs map[string]*pkgDoc
+}
+
+type pkgDoc struct {
+	fset *token.FileSet
+	pkg  *doc.Package
+}
+
+// New returns a Lookup reading the standard library under goroot, or
+// under the GOROOT the binary runs with when goroot is empty
+func New(goroot string) *Lookup {
+	ctx := build.Default
+	if goroot != "" {
+		ctx.GOROOT = goroot
+	}
+	return &Lookup{ctx: ctx, pkgs: make(map[string]*pkgDoc)}
+}
+
+// Doc returns the documentation of a package, or of one of its exported
+// symbols: "strings", "strings.Builder", "strings.Builder.WriteString",
+// "net/http.Client.Do". Types list their methods.
+func (l *Lookup) Doc(symbol string) (string, error) {
+	path, name, member := split(symbol)
+	if path == "" {
+		return "", fmt.Errorf("%q: want a package path, optionally followed by .Name or .Type.Method", symbol)
+	}
+	p, err := l.load(path)
+	if err != nil {
+		return "", err
+	}
+
+	var out string
+	switch {
+	case name == "":
+		out = fmt.Sprintf("package %s // import %q\n\n%s", p.pkg.Name, path, p.pkg.Doc)
+	default:
+		out, err = p.symbol(name, member)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", symbol, err)
+		}
+	}
+	if len(out) > MaxLength {
+		out = out[:MaxLength] + "\n..."
+	}
+	return out, nil
+}
+
+// split parses an import path followed by up to two dotted names. The
+// path ends at the first dot after its last slash.
+func split(symbol string) (path, name, member string) {
+	symbol = strings.TrimSpace(symbol)
+	slash := strings.LastIndex(symbol, "/")
+	dot := strings.Index(symbol[slash+1:], ".")
+	if dot < 0 {
+		return symbol, "", ""
+	}
+	path = symbol[:slash+1+dot]
+	name, member, _ = strings.Cut(symbol[slash+2+dot:], ".")
+	return path, name, member
+}
+
+func (l *Lookup) load(path string) (*pkgDoc, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if p, ok := l.pkgs[path]; ok {
+		return p, nil
+	}
+
+	if strings.Contains(path, "..") || strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("package %q: %w", path, ErrNotFound)
+	}
+	dir := filepath.Join(l.ctx.GOROOT, "src", filepath.FromSlash(path))
+	bp, err := l.ctx.ImportDir(dir, 0)
+	if err != nil || !bp.Goroot || bp.Name == "main" {
+		return nil, fmt.Errorf("package %q: %w", path, ErrNotFound)
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+		files = append(files, f)
+	}
+	pkg, err := doc.NewFromFiles(fset, files, path)
+	if err != nil {
+		return nil, fmt.Errorf("read docs of %s: %w", path, err)
+	}
+
+	p := &pkgDoc{fset: fset, pkg: pkg}
+	l.pkgs[path] = p
+	return p, nil
+}
+
+// symbol renders a function, type, method, constant or variable
+func (p *pkgDoc) symbol(name, member string) (string, error) {
+	for _, f := range p.pkg.Funcs {
+		if f.Name == name && member == "" {
+			return p.funcDoc(f), nil
+		}
+	}
+	for _, t := range p.pkg.Types {
+		if t.Name == name {
+			if member != "" {
+				return p.member(t, member)
+			}
+			return p.typeDoc(t), nil
+		}
+		// Constructors and typed constants are grouped under their type
+		for _, f := range t.Funcs {
+			if f.Name == name && member == "" {
+				return p.funcDoc(f), nil
+			}
+		}
+		if v := findValue(append(t.Consts, t.Vars...), name); v != nil && member == "" {
+			return p.valueDoc(v), nil
+		}
+	}
+	if v := findValue(append(p.pkg.Consts, p.pkg.Vars...), name); v != nil && member == "" {
+		return p.valueDoc(v), nil
+	}
+	return "", ErrNotFound
+}
+
+func (p *pkgDoc) member(t *doc.Type, name string) (string, error) {
+	for _, m := range t.Methods {
+		if m.Name == name {
+			return p.funcDoc(m), nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+func findValue(values []*doc.Value, name string) *doc.Value {
+	for _, v := range values {
+		for _, n := range v.Names {
+			if n == name {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+func (p *pkgDoc) funcDoc(f *doc.Func) string {
+	decl := *f.Decl
+	decl.Body = nil
+	decl.Doc = nil
+	return p.print(&decl) + "\n\n" + f.Doc
+}
+
+func (p *pkgDoc) typeDoc(t *doc.Type) string {
+	decl := *t.Decl
+	decl.Doc = nil
+	var sb strings.Builder
+	sb.WriteString(p.print(&decl))
+	sb.WriteString("\n\n")
+	sb.WriteString(t.Doc)
+
+	var funcs []string
+	for _, f := range t.Funcs {
+		funcs = append(funcs, p.signature(f))
+	}
+	for _, m := range t.Methods {
+		funcs = append(funcs, p.signature(m))
+	}
+	sort.Strings(funcs)
+	if len(funcs) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(strings.Join(funcs, "\n"))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func (p *pkgDoc) valueDoc(v *doc.Value) string {
+	decl := *v.Decl
+	decl.Doc = nil
+	return p.print(&decl) + "\n\n" + v.Doc
+}
+
+func (p *pkgDoc) signature(f *doc.Func) string {
+	decl := *f.Decl
+	decl.Body = nil
+	decl.Doc = nil
+	return p.print(&decl)
+}
+
+func (p *pkgDoc) print(node ast.Node) string {
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, p.fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
@@ -0,0 +1,63 @@
+package godoc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		symbol, path, name, member string
+	}{
+		{"strings", "strings", "", ""},
+		{"strings.Builder", "strings", "Builder", ""},
+		{"strings.Builder.WriteString", "strings", "Builder", "WriteString"},
+		{"net/http.Client.Do", "net/http", "Client", "Do"},
+		{"encoding/json", "encoding/json", "", ""},
+	}
+	for _, tt := range tests {
+		path, name, member := split(tt.symbol)
+		if path != tt.path || name != tt.name || member != tt.member {
+			t.Errorf("split(%q) = %q, %q, %q; want %q, %q, %q", tt.symbol, path, name, member, tt.path, tt.name, tt.member)
+		}
+	}
+}
+
+func TestLookup_Doc(t *testing.T) {
+	l := New("")
+	if _, err := l.Doc("strings"); err != nil {
+		t.Skipf("no standard library sources: %v", err)
+	}
+
+	tests := []struct {
+		symbol string
+		want   []string
+	}{
+		{"strings", []string{"package strings", "UTF-8"}},
+		{"strings.TrimSpace", []string{"func TrimSpace(s string) string", "leading and trailing white space"}},
+		{"strings.Builder", []string{"type Builder struct", "func (b *Builder) WriteString(s string) (int, error)"}},
+		{"strings.Builder.Len", []string{"func (b *Builder) Len() int"}},
+		{"net/http.NewRequest", []string{"func NewRequest(method, url string, body io.Reader) (*Request, error)"}},
+		{"net/http.MethodGet", []string{`MethodGet`, `"GET"`}},
+		{"io.EOF", []string{"var EOF = errors.New"}},
+	}
+	for _, tt := range tests {
+		got, err := l.Doc(tt.symbol)
+		if err != nil {
+			t.Errorf("Doc(%q) error = %v", tt.symbol, err)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("Doc(%q) missing %q:\n%s", tt.symbol, want, got)
+			}
+		}
+	}
+
+	for _, symbol := range []string{"strings.Reverse", "strings.Builder.Reverse", "github.com/x/y.Z", "../etc.Passwd"} {
+		if _, err := l.Doc(symbol); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Doc(%q) error = %v; want ErrNotFound", symbol, err)
+		}
+	}
+}
@@ -49,10 +49,20 @@ func (p *ClaudeProvider) Name() string {
 	return "claude"
 }
 
+// DefaultModel returns the model used when a request names none
+func (p *ClaudeProvider) DefaultModel() string {
+	return p.model
+}
+
 func (p *ClaudeProvider) SupportsStreaming() bool {
 	return true
 }
 
+// SupportsTools reports that Claude takes tools (tool_use blocks)
+func (p *ClaudeProvider) SupportsTools() bool {
+	return true
+}
+
 type claudeRequest struct {
 	Model       string          `json:"model"`
 	MaxTokens   int             `json:"max_tokens"`
@@ -64,6 +74,7 @@ type claudeRequest struct {
 	Temperature float64         `json:"temperature,omitempty"`
 	StopSeqs    []string        `json:"stop_sequences,omitempty"`
 	Stream      bool            `json:"stream,omitempty"`
+	Tools       []claudeTool    `json:"tools,omitempty"`
 }
 
 // claudeSystemBlock is the array form of Anthropic's system field.
@@ -81,18 +92,15 @@ type claudeCacheControl struct {
 
 type claudeMessage struct {
 	Role    string `json:"role"`
-	Content string `json:"content"`
+	Content any    `json:"content"` // a string, or content blocks for tool use
 }
 
 type claudeResponse struct {
-	ID      string `json:"id"`
-	Type    string `json:"type"`
-	Role    string `json:"role"`
-	Content []struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
-	} `json:"content"`
-	StopReason string `json:"stop_reason"`
+	ID         string                `json:"id"`
+	Type       string                `json:"type"`
+	Role       string                `json:"role"`
+	Content    []claudeResponseBlock `json:"content"`
+	StopReason string                `json:"stop_reason"`
 	Usage      struct {
 		InputTokens  int `json:"input_tokens"`
 		OutputTokens int `json:"output_tokens"`
@@ -229,7 +237,7 @@ func (p *ClaudeProvider) buildRequest(req *Request, stream bool) *claudeRequest
 		}
 		messages = append(messages, claudeMessage{
 			Role:    string(m.Role),
-			Content: m.Content,
+			Content: claudeContent(m),
 		})
 	}
 
@@ -269,6 +277,7 @@ func (p *ClaudeProvider) buildRequest(req *Request, stream bool) *claudeRequest
 		Temperature: req.Temperature,
 		StopSeqs:    req.StopSeqs,
 		Stream:      stream,
+		Tools:       claudeTools(req.Tools),
 	}
 }
 
@@ -289,14 +298,19 @@ func applyCorrelationHeader(httpReq *http.Request, llmReq *Request) {
 
 func (p *ClaudeProvider) parseResponse(resp *claudeResponse) *Response {
 	var content string
+	var calls []ToolCall
 	for _, c := range resp.Content {
-		if c.Type == "text" {
+		switch c.Type {
+		case "text":
 			content += c.Text
+		case "tool_use":
+			calls = append(calls, ToolCall{ID: c.ID, Name: c.Name, Input: c.Input})
 		}
 	}
 
 	return &Response{
 		Content:      content,
+		ToolCalls:    calls,
 		FinishReason: resp.StopReason,
 		Usage: Usage{
 			InputTokens:  resp.Usage.InputTokens,
@@ -304,3 +318,65 @@ func (p *ClaudeProvider) parseResponse(resp *claudeResponse) *Response {
 		},
 	}
 }
+
+// claudeResponseBlock is a text or tool_use block of a response
+type claudeResponseBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text"`
+	ID    string          `json:"id"`    // tool_use
+	Name  string          `json:"name"`  // tool_use
+	Input json.RawMessage `json:"input"` // tool_use
+}
+
+type claudeTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// claudeContentBlock is a text, tool_use or tool_result block of a
+// message's content
+type claudeContentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+}
+
+func claudeTools(tools []Tool) []claudeTool {
+	if len(tools) == 0 {
+		return nil
+	}
+	out := make([]claudeTool, 0, len(tools))
+	for _, t := range tools {
+		out = append(out, claudeTool{Name: t.Name, Description: t.Description, InputSchema: t.InputSchema})
+	}
+	return out
+}
+
+// claudeContent returns a message's content: the plain string, or blocks
+// when the message carries tool calls or results
+func claudeContent(m Message) any {
+	if len(m.ToolCalls) == 0 && len(m.ToolResults) == 0 {
+		return m.Content
+	}
+	var blocks []claudeContentBlock
+	if m.Content != "" {
+		blocks = append(blocks, claudeContentBlock{Type: "text", Text: m.Content})
+	}
+	for _, c := range m.ToolCalls {
+		input := c.Input
+		if len(input) == 0 {
+			input = json.RawMessage("{}")
+		}
+		blocks = append(blocks, claudeContentBlock{Type: "tool_use", ID: c.ID, Name: c.Name, Input: input})
+	}
+	for _, r := range m.ToolResults {
+		blocks = append(blocks, claudeContentBlock{Type: "tool_result", ToolUseID: r.CallID, Content: r.Content, IsError: r.IsError})
+	}
+	return blocks
+}
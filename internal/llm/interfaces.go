@@ -21,3 +21,18 @@ type LLMRegistry interface {
 
 // Ensure Registry implements LLMRegistry
 var _ LLMRegistry = (*Registry)(nil)
+
+// ModelReporter is implemented by providers that can name the model a
+// request without one goes to
+type ModelReporter interface {
+	DefaultModel() string
+}
+
+// DefaultModel returns the provider's default model, or "" when it
+// doesn't report one
+func DefaultModel(p Provider) string {
+	if m, ok := p.(ModelReporter); ok {
+		return m.DefaultModel()
+	}
+	return ""
+}
@@ -45,6 +45,11 @@ func (p *OllamaProvider) Name() string {
 	return "ollama"
 }
 
+// DefaultModel returns the model used when a request names none
+func (p *OllamaProvider) DefaultModel() string {
+	return p.model
+}
+
 func (p *OllamaProvider) SupportsStreaming() bool {
 	return true
 }
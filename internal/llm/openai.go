@@ -49,10 +49,20 @@ func (p *OpenAIProvider) Name() string {
 	return "openai"
 }
 
+// DefaultModel returns the model used when a request names none
+func (p *OpenAIProvider) DefaultModel() string {
+	return p.model
+}
+
 func (p *OpenAIProvider) SupportsStreaming() bool {
 	return true
 }
 
+// SupportsTools reports that OpenAI takes tools (function calling)
+func (p *OpenAIProvider) SupportsTools() bool {
+	return true
+}
+
 type openaiRequest struct {
 	Model       string          `json:"model"`
 	Messages    []openaiMessage `json:"messages"`
@@ -60,23 +70,49 @@ type openaiRequest struct {
 	Temperature float64         `json:"temperature,omitempty"`
 	Stop        []string        `json:"stop,omitempty"`
 	Stream      bool            `json:"stream,omitempty"`
+	Tools       []openaiTool    `json:"tools,omitempty"`
 }
 
 type openaiMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string           `json:"role"`
+	Content    string           `json:"content"`
+	ToolCalls  []openaiToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"` // role "tool"
+}
+
+type openaiChoice struct {
+	Message      openaiResponseMessage `json:"message"`
+	FinishReason string                `json:"finish_reason"`
+}
+
+type openaiResponseMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []openaiToolCall `json:"tool_calls"`
+}
+
+type openaiTool struct {
+	Type     string `json:"type"` // "function"
+	Function struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		Parameters  map[string]any `json:"parameters"`
+	} `json:"function"`
+}
+
+type openaiToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"` // "function"
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"` // JSON, as a string
+	} `json:"function"`
 }
 
 type openaiResponse struct {
-	ID      string `json:"id"`
-	Choices []struct {
-		Message struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason"`
-	} `json:"choices"`
-	Usage struct {
+	ID      string         `json:"id"`
+	Choices []openaiChoice `json:"choices"`
+	Usage   struct {
 		PromptTokens     int `json:"prompt_tokens"`
 		CompletionTokens int `json:"completion_tokens"`
 	} `json:"usage"`
@@ -217,10 +253,33 @@ func (p *OpenAIProvider) buildRequest(req *Request, stream bool) *openaiRequest
 	}
 
 	for _, m := range req.Messages {
-		messages = append(messages, openaiMessage{
+		// Tool results are messages of their own, one per call
+		if len(m.ToolResults) > 0 {
+			for _, r := range m.ToolResults {
+				messages = append(messages, openaiMessage{Role: "tool", Content: r.Content, ToolCallID: r.CallID})
+			}
+			continue
+		}
+		msg := openaiMessage{
 			Role:    string(m.Role),
 			Content: m.Content,
-		})
+		}
+		for _, c := range m.ToolCalls {
+			call := openaiToolCall{ID: c.ID, Type: "function"}
+			call.Function.Name = c.Name
+			call.Function.Arguments = string(c.Input)
+			msg.ToolCalls = append(msg.ToolCalls, call)
+		}
+		messages = append(messages, msg)
+	}
+
+	var tools []openaiTool
+	for _, t := range req.Tools {
+		tool := openaiTool{Type: "function"}
+		tool.Function.Name = t.Name
+		tool.Function.Description = t.Description
+		tool.Function.Parameters = t.InputSchema
+		tools = append(tools, tool)
 	}
 
 	return &openaiRequest{
@@ -230,6 +289,7 @@ func (p *OpenAIProvider) buildRequest(req *Request, stream bool) *openaiRequest
 		Temperature: req.Temperature,
 		Stop:        req.StopSeqs,
 		Stream:      stream,
+		Tools:       tools,
 	}
 }
 
@@ -243,8 +303,14 @@ func (p *OpenAIProvider) parseResponse(resp *openaiResponse) *Response {
 		return &Response{}
 	}
 
+	var calls []ToolCall
+	for _, c := range resp.Choices[0].Message.ToolCalls {
+		calls = append(calls, ToolCall{ID: c.ID, Name: c.Function.Name, Input: json.RawMessage(c.Function.Arguments)})
+	}
+
 	return &Response{
 		Content:      resp.Choices[0].Message.Content,
+		ToolCalls:    calls,
 		FinishReason: resp.Choices[0].FinishReason,
 		Usage: Usage{
 			InputTokens:  resp.Usage.PromptTokens,
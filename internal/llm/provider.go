@@ -50,6 +50,10 @@ type Request struct {
 	// LLM API so failures can be traced editor → daemon → provider. When
 	// non-empty, providers attach it as the X-Request-ID HTTP header.
 	CorrelationID string
+
+	// Tools the model may call. Only providers implementing ToolUser send
+	// them; see GenerateWithTools.
+	Tools []Tool
 }
 
 // SystemContentBlock is a chunk of the system prompt with an optional
@@ -90,6 +94,11 @@ func flattenSystemBlocks(req *Request) string {
 type Message struct {
 	Role    Role
 	Content string
+
+	// ToolCalls are the calls an assistant message made; ToolResults
+	// answer them in the user message that follows
+	ToolCalls   []ToolCall
+	ToolResults []ToolResult
 }
 
 // Role represents the role of a message sender
@@ -106,6 +115,7 @@ type Response struct {
 	Content      string
 	FinishReason string
 	Usage        Usage
+	ToolCalls    []ToolCall // calls the model wants answered before it goes on
 }
 
 // Usage tracks token usage
@@ -867,10 +867,7 @@ func TestClaudeProvider_ParseResponse(t *testing.T) {
 		ID:   "msg-123",
 		Type: "message",
 		Role: "assistant",
-		Content: []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
-		}{
+		Content: []claudeResponseBlock{
 			{Type: "text", Text: "Hello "},
 			{Type: "text", Text: "World!"},
 		},
@@ -899,10 +896,7 @@ func TestClaudeProvider_ParseResponse_EmptyContent(t *testing.T) {
 	p := NewClaudeProvider(ClaudeConfig{APIKey: "test"})
 
 	resp := &claudeResponse{
-		Content: []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
-		}{},
+		Content: []claudeResponseBlock{},
 	}
 
 	got := p.parseResponse(resp)
@@ -1142,18 +1136,9 @@ func TestOpenAIProvider_ParseResponse(t *testing.T) {
 
 	resp := &openaiResponse{
 		ID: "chatcmpl-test",
-		Choices: []struct {
-			Message struct {
-				Role    string `json:"role"`
-				Content string `json:"content"`
-			} `json:"message"`
-			FinishReason string `json:"finish_reason"`
-		}{
+		Choices: []openaiChoice{
 			{
-				Message: struct {
-					Role    string `json:"role"`
-					Content string `json:"content"`
-				}{Role: "assistant", Content: "Hello!"},
+				Message:      openaiResponseMessage{Role: "assistant", Content: "Hello!"},
 				FinishReason: "stop",
 			},
 		},
@@ -1183,14 +1168,8 @@ func TestOpenAIProvider_ParseResponse_EmptyChoices(t *testing.T) {
 	p := NewOpenAIProvider(OpenAIConfig{APIKey: "test"})
 
 	resp := &openaiResponse{
-		ID: "chatcmpl-test",
-		Choices: []struct {
-			Message struct {
-				Role    string `json:"role"`
-				Content string `json:"content"`
-			} `json:"message"`
-			FinishReason string `json:"finish_reason"`
-		}{},
+		ID:      "chatcmpl-test",
+		Choices: []openaiChoice{},
 	}
 
 	got := p.parseResponse(resp)
@@ -1221,10 +1200,7 @@ func TestClaudeProvider_Generate_HTTPSuccess(t *testing.T) {
 			ID:   "msg_test",
 			Type: "message",
 			Role: "assistant",
-			Content: []struct {
-				Type string `json:"type"`
-				Text string `json:"text"`
-			}{
+			Content: []claudeResponseBlock{
 				{Type: "text", Text: "Hello from Claude!"},
 			},
 			StopReason: "end_turn",
@@ -137,10 +137,20 @@ func (p *ResilientProvider) Name() string {
 	return p.provider.Name()
 }
 
+// DefaultModel reports the wrapped provider's default model
+func (p *ResilientProvider) DefaultModel() string {
+	return DefaultModel(p.provider)
+}
+
 func (p *ResilientProvider) SupportsStreaming() bool {
 	return p.provider.SupportsStreaming()
 }
 
+// SupportsTools reports whether the wrapped provider takes tools
+func (p *ResilientProvider) SupportsTools() bool {
+	return SupportsTools(p.provider)
+}
+
 func (p *ResilientProvider) Generate(ctx context.Context, req *Request) (*Response, error) {
 	// Apply rate limiting
 	if p.rateLimit != nil {
@@ -0,0 +1,100 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ErrTimeout is returned when a call runs past its provider's timeout.
+// A caller that gave up itself gets context.Canceled instead.
+var ErrTimeout = errors.New("LLM request timed out")
+
+var timeoutCounter atomic.Int64
+
+// Timeouts returns the cumulative count of LLM calls cut off by a provider
+// timeout since process start. Wired into the metrics endpoint.
+func Timeouts() int64 {
+	return timeoutCounter.Load()
+}
+
+// TimeoutProvider bounds every call to the wrapped provider. Streams are
+// bounded as a whole, from the request to the last chunk.
+type TimeoutProvider struct {
+	provider Provider
+	timeout  time.Duration
+}
+
+// WithTimeout wraps provider so each call gives up after timeout. A zero
+// or negative timeout returns provider unchanged; the HTTP clients' own
+// limits still apply either way.
+func WithTimeout(provider Provider, timeout time.Duration) Provider {
+	if timeout <= 0 {
+		return provider
+	}
+	return &TimeoutProvider{provider: provider, timeout: timeout}
+}
+
+func (p *TimeoutProvider) Name() string {
+	return p.provider.Name()
+}
+
+// DefaultModel reports the wrapped provider's default model
+func (p *TimeoutProvider) DefaultModel() string {
+	return DefaultModel(p.provider)
+}
+
+func (p *TimeoutProvider) SupportsStreaming() bool {
+	return p.provider.SupportsStreaming()
+}
+
+// SupportsTools reports whether the wrapped provider takes tools
+func (p *TimeoutProvider) SupportsTools() bool {
+	return SupportsTools(p.provider)
+}
+
+func (p *TimeoutProvider) Generate(ctx context.Context, req *Request) (*Response, error) {
+	callCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	resp, err := p.provider.Generate(callCtx, req)
+	if err != nil {
+		return nil, p.timedOut(ctx, callCtx, err)
+	}
+	return resp, nil
+}
+
+func (p *TimeoutProvider) GenerateStream(ctx context.Context, req *Request) (<-chan StreamChunk, error) {
+	callCtx, cancel := context.WithTimeout(ctx, p.timeout)
+
+	stream, err := p.provider.GenerateStream(callCtx, req)
+	if err != nil {
+		cancel()
+		return nil, p.timedOut(ctx, callCtx, err)
+	}
+
+	out := make(chan StreamChunk, cap(stream))
+	go func() {
+		defer cancel()
+		defer close(out)
+		for chunk := range stream {
+			if chunk.Error != nil {
+				chunk.Error = p.timedOut(ctx, callCtx, chunk.Error)
+			}
+			out <- chunk
+		}
+	}()
+	return out, nil
+}
+
+// timedOut marks err as a timeout when the call's own deadline, not the
+// caller, ended it
+func (p *TimeoutProvider) timedOut(parent, callCtx context.Context, err error) error {
+	if parent.Err() != nil || !errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	timeoutCounter.Add(1)
+	return fmt.Errorf("%w: %s after %s: %v", ErrTimeout, p.provider.Name(), p.timeout, err)
+}
@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// slowProvider answers only once its context is done
+type slowProvider struct{ mockProvider }
+
+func (p *slowProvider) Generate(ctx context.Context, req *Request) (*Response, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (p *slowProvider) GenerateStream(ctx context.Context, req *Request) (<-chan StreamChunk, error) {
+	ch := make(chan StreamChunk, 1)
+	go func() {
+		defer close(ch)
+		ch <- StreamChunk{Content: "partial"}
+		<-ctx.Done()
+		ch <- StreamChunk{Error: ctx.Err()}
+	}()
+	return ch, nil
+}
+
+func TestWithTimeout_Generate(t *testing.T) {
+	p := WithTimeout(&slowProvider{mockProvider{name: "slow"}}, 20*time.Millisecond)
+	before := Timeouts()
+
+	_, err := p.Generate(context.Background(), &Request{})
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+	if Timeouts()-before != 1 {
+		t.Errorf("Timeouts() grew by %d, want 1", Timeouts()-before)
+	}
+}
+
+func TestWithTimeout_CallerCancelIsNotATimeout(t *testing.T) {
+	p := WithTimeout(&slowProvider{mockProvider{name: "slow"}}, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := p.Generate(ctx, &Request{})
+	if errors.Is(err, ErrTimeout) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWithTimeout_Stream(t *testing.T) {
+	p := WithTimeout(&slowProvider{mockProvider{name: "slow"}}, 20*time.Millisecond)
+
+	stream, err := p.GenerateStream(context.Background(), &Request{})
+	if err != nil {
+		t.Fatalf("GenerateStream() error = %v", err)
+	}
+	var chunks []StreamChunk
+	for c := range stream {
+		chunks = append(chunks, c)
+	}
+	if len(chunks) != 2 || chunks[0].Content != "partial" || !errors.Is(chunks[1].Error, ErrTimeout) {
+		t.Errorf("unexpected chunks: %+v", chunks)
+	}
+}
+
+func TestWithTimeout_Disabled(t *testing.T) {
+	base := &mockProvider{name: "base"}
+	if p := WithTimeout(base, 0); p != Provider(base) {
+		t.Error("a zero timeout should leave the provider unwrapped")
+	}
+}
@@ -0,0 +1,110 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Tool is a function the model may call while answering. InputSchema is
+// the JSON Schema of the call's input object.
+type Tool struct {
+	Name        string
+	Description string
+	InputSchema map[string]any
+}
+
+// ToolCall is the model asking for a tool to be run
+type ToolCall struct {
+	ID    string
+	Name  string
+	Input json.RawMessage
+}
+
+// ToolResult answers a ToolCall
+type ToolResult struct {
+	CallID  string
+	Content string
+	IsError bool
+}
+
+// ToolUser is implemented by providers that can offer tools to the model.
+// Providers without it ignore Request.Tools.
+type ToolUser interface {
+	SupportsTools() bool
+}
+
+// SupportsTools reports whether p passes tools to the model
+func SupportsTools(p Provider) bool {
+	t, ok := p.(ToolUser)
+	return ok && t.SupportsTools()
+}
+
+// ToolHandler is a tool together with the function that answers its calls
+type ToolHandler struct {
+	Tool
+	Call func(ctx context.Context, input json.RawMessage) (string, error)
+}
+
+// MaxToolRounds bounds how often GenerateWithTools answers tool calls
+// before asking the model for a final answer without tools
+const MaxToolRounds = 3
+
+// GenerateWithTools runs req with the handlers' tools and answers every
+// call the model makes, until it replies without one. After MaxToolRounds
+// the tools are withdrawn so the model has to answer. Providers that don't
+// support tools get req unchanged. Usage covers every round.
+func GenerateWithTools(ctx context.Context, p Provider, req *Request, handlers []ToolHandler) (*Response, error) {
+	if len(handlers) == 0 || !SupportsTools(p) {
+		return p.Generate(ctx, req)
+	}
+
+	byName := make(map[string]ToolHandler, len(handlers))
+	round := *req
+	round.Messages = append([]Message(nil), req.Messages...)
+	for _, h := range handlers {
+		byName[h.Name] = h
+		round.Tools = append(round.Tools, h.Tool)
+	}
+
+	var usage Usage
+	for i := 0; ; i++ {
+		if i == MaxToolRounds {
+			round.Tools = nil
+		}
+		resp, err := p.Generate(ctx, &round)
+		if err != nil {
+			return nil, err
+		}
+		usage.InputTokens += resp.Usage.InputTokens
+		usage.OutputTokens += resp.Usage.OutputTokens
+		if len(resp.ToolCalls) == 0 || round.Tools == nil {
+			resp.Usage = usage
+			resp.ToolCalls = nil
+			return resp, nil
+		}
+
+		results := make([]ToolResult, 0, len(resp.ToolCalls))
+		for _, call := range resp.ToolCalls {
+			results = append(results, runTool(ctx, byName, call))
+		}
+		round.Messages = append(round.Messages,
+			Message{Role: RoleAssistant, Content: resp.Content, ToolCalls: resp.ToolCalls},
+			Message{Role: RoleUser, ToolResults: results},
+		)
+	}
+}
+
+// runTool answers one call. Failures go back to the model as error
+// results rather than failing the request: it can answer without them.
+func runTool(ctx context.Context, handlers map[string]ToolHandler, call ToolCall) ToolResult {
+	h, ok := handlers[call.Name]
+	if !ok {
+		return ToolResult{CallID: call.ID, Content: fmt.Sprintf("unknown tool %q", call.Name), IsError: true}
+	}
+	out, err := h.Call(ctx, call.Input)
+	if err != nil {
+		return ToolResult{CallID: call.ID, Content: err.Error(), IsError: true}
+	}
+	return ToolResult{CallID: call.ID, Content: out}
+}
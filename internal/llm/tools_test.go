@@ -0,0 +1,202 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// toolProvider replies with its scripted responses in turn and records
+// the requests it got
+type toolProvider struct {
+	mockProvider
+	replies []*Response
+	reqs    []Request
+}
+
+func (p *toolProvider) SupportsTools() bool { return true }
+
+func (p *toolProvider) Generate(ctx context.Context, req *Request) (*Response, error) {
+	p.reqs = append(p.reqs, *req)
+	resp := p.replies[0]
+	if len(p.replies) > 1 {
+		p.replies = p.replies[1:]
+	}
+	copied := *resp
+	return &copied, nil
+}
+
+func echoTool() ToolHandler {
+	return ToolHandler{
+		Tool: Tool{Name: "echo", Description: "echoes", InputSchema: map[string]any{"type": "object"}},
+		Call: func(_ context.Context, input json.RawMessage) (string, error) {
+			if string(input) == `"fail"` {
+				return "", errors.New("boom")
+			}
+			return "echo " + string(input), nil
+		},
+	}
+}
+
+func TestGenerateWithTools(t *testing.T) {
+	p := &toolProvider{replies: []*Response{
+		{
+			Content: "Looking it up.",
+			ToolCalls: []ToolCall{
+				{ID: "1", Name: "echo", Input: json.RawMessage(`"hi"`)},
+				{ID: "2", Name: "echo", Input: json.RawMessage(`"fail"`)},
+				{ID: "3", Name: "missing"},
+			},
+			Usage: Usage{InputTokens: 10, OutputTokens: 2},
+		},
+		{Content: "Done.", Usage: Usage{InputTokens: 20, OutputTokens: 3}},
+	}}
+	req := &Request{Messages: []Message{{Role: RoleUser, Content: "question"}}}
+
+	resp, err := GenerateWithTools(context.Background(), p, req, []ToolHandler{echoTool()})
+	if err != nil {
+		t.Fatalf("GenerateWithTools() error = %v", err)
+	}
+	if resp.Content != "Done." {
+		t.Errorf("Content = %q; want Done.", resp.Content)
+	}
+	if resp.Usage.InputTokens != 30 || resp.Usage.OutputTokens != 5 {
+		t.Errorf("Usage = %+v; want the sum of both rounds", resp.Usage)
+	}
+	if len(p.reqs) != 2 {
+		t.Fatalf("rounds = %d; want 2", len(p.reqs))
+	}
+	if len(p.reqs[0].Tools) != 1 || p.reqs[0].Tools[0].Name != "echo" {
+		t.Errorf("first round Tools = %+v; want echo", p.reqs[0].Tools)
+	}
+	if len(req.Messages) != 1 || req.Tools != nil {
+		t.Error("GenerateWithTools() modified the caller's request")
+	}
+
+	msgs := p.reqs[1].Messages
+	if len(msgs) != 3 {
+		t.Fatalf("second round messages = %d; want 3", len(msgs))
+	}
+	if msgs[1].Role != RoleAssistant || len(msgs[1].ToolCalls) != 3 {
+		t.Errorf("messages[1] = %+v; want the assistant's tool calls", msgs[1])
+	}
+	want := []ToolResult{
+		{CallID: "1", Content: `echo "hi"`},
+		{CallID: "2", Content: "boom", IsError: true},
+		{CallID: "3", Content: `unknown tool "missing"`, IsError: true},
+	}
+	got := msgs[2].ToolResults
+	if msgs[2].Role != RoleUser || len(got) != len(want) {
+		t.Fatalf("messages[2] = %+v; want the tool results", msgs[2])
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ToolResults[%d] = %+v; want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGenerateWithTools_RoundLimit(t *testing.T) {
+	// The model asks for the tool every time; the last round goes
+	// without tools and its reply is final
+	p := &toolProvider{replies: []*Response{
+		{Content: "again", ToolCalls: []ToolCall{{ID: "1", Name: "echo", Input: json.RawMessage(`1`)}}},
+	}}
+
+	resp, err := GenerateWithTools(context.Background(), p, &Request{}, []ToolHandler{echoTool()})
+	if err != nil {
+		t.Fatalf("GenerateWithTools() error = %v", err)
+	}
+	if len(p.reqs) != MaxToolRounds+1 {
+		t.Errorf("rounds = %d; want %d", len(p.reqs), MaxToolRounds+1)
+	}
+	if last := p.reqs[len(p.reqs)-1]; last.Tools != nil {
+		t.Errorf("last round Tools = %+v; want none", last.Tools)
+	}
+	if resp.ToolCalls != nil {
+		t.Errorf("ToolCalls = %+v; want none in the final reply", resp.ToolCalls)
+	}
+}
+
+func TestGenerateWithTools_Unsupported(t *testing.T) {
+	p := &mockProvider{name: "plain", response: &Response{Content: "answer"}}
+
+	resp, err := GenerateWithTools(context.Background(), p, &Request{}, []ToolHandler{echoTool()})
+	if err != nil {
+		t.Fatalf("GenerateWithTools() error = %v", err)
+	}
+	if resp.Content != "answer" {
+		t.Errorf("Content = %q; want answer", resp.Content)
+	}
+	if SupportsTools(p) {
+		t.Error("SupportsTools() = true for a provider without tools")
+	}
+}
+
+// toolConversation is a request in the middle of a tool round
+func toolConversation() *Request {
+	return &Request{
+		Tools: []Tool{{Name: "echo", Description: "echoes", InputSchema: map[string]any{"type": "object"}}},
+		Messages: []Message{
+			{Role: RoleUser, Content: "question"},
+			{Role: RoleAssistant, Content: "Looking it up.", ToolCalls: []ToolCall{{ID: "c1", Name: "echo", Input: json.RawMessage(`{"x":1}`)}}},
+			{Role: RoleUser, ToolResults: []ToolResult{{CallID: "c1", Content: "result", IsError: true}}},
+		},
+	}
+}
+
+func TestClaudeProvider_BuildRequest_Tools(t *testing.T) {
+	p := NewClaudeProvider(ClaudeConfig{APIKey: "k"})
+	body, err := json.Marshal(p.buildRequest(toolConversation(), false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`"tools":[{"name":"echo","description":"echoes","input_schema":{"type":"object"}}]`,
+		`{"role":"user","content":"question"}`,
+		`{"role":"assistant","content":[{"type":"text","text":"Looking it up."},{"type":"tool_use","id":"c1","name":"echo","input":{"x":1}}]}`,
+		`{"role":"user","content":[{"type":"tool_result","tool_use_id":"c1","content":"result","is_error":true}]}`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("request missing %s:\n%s", want, body)
+		}
+	}
+
+	resp := p.parseResponse(&claudeResponse{Content: []claudeResponseBlock{
+		{Type: "text", Text: "Checking."},
+		{Type: "tool_use", ID: "c2", Name: "echo", Input: json.RawMessage(`{"x":2}`)},
+	}})
+	if resp.Content != "Checking." || len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "c2" || string(resp.ToolCalls[0].Input) != `{"x":2}` {
+		t.Errorf("parseResponse() = %+v", resp)
+	}
+}
+
+func TestOpenAIProvider_BuildRequest_Tools(t *testing.T) {
+	p := NewOpenAIProvider(OpenAIConfig{APIKey: "k"})
+	body, err := json.Marshal(p.buildRequest(toolConversation(), false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`"tools":[{"type":"function","function":{"name":"echo","description":"echoes","parameters":{"type":"object"}}}]`,
+		`{"role":"assistant","content":"Looking it up.","tool_calls":[{"id":"c1","type":"function","function":{"name":"echo","arguments":"{\"x\":1}"}}]}`,
+		`{"role":"tool","content":"result","tool_call_id":"c1"}`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("request missing %s:\n%s", want, body)
+		}
+	}
+
+	var resp openaiResponse
+	if err := json.Unmarshal([]byte(`{"choices":[{"message":{"role":"assistant","content":"","tool_calls":[{"id":"c2","type":"function","function":{"name":"echo","arguments":"{\"x\":2}"}}]},"finish_reason":"tool_calls"}]}`), &resp); err != nil {
+		t.Fatal(err)
+	}
+	got := p.parseResponse(&resp)
+	if len(got.ToolCalls) != 1 || got.ToolCalls[0].Name != "echo" || string(got.ToolCalls[0].Input) != `{"x":2}` {
+		t.Errorf("parseResponse() = %+v", got)
+	}
+}
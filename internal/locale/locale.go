@@ -0,0 +1,99 @@
+// Package locale resolves the learner's locale and translates the CLI's
+// own output. Interventions are localized by the LLM; this package only
+// names the language for the prompt.
+package locale
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Default is the locale used when none is configured or detected
+const Default = "en"
+
+// Env overrides the configured locale, e.g. TEMPER_LOCALE=de
+const Env = "TEMPER_LOCALE"
+
+// ErrUnsupported is returned for locales Temper has no language name for
+var ErrUnsupported = errors.New("unsupported locale")
+
+// names maps supported locale tags to the English name of their language,
+// which is what the prompt asks the LLM to write in
+var names = map[string]string{
+	"en":    "English",
+	"de":    "German",
+	"es":    "Spanish",
+	"fr":    "French",
+	"it":    "Italian",
+	"nl":    "Dutch",
+	"pl":    "Polish",
+	"pt":    "Portuguese",
+	"pt-BR": "Brazilian Portuguese",
+	"sv":    "Swedish",
+	"tr":    "Turkish",
+	"uk":    "Ukrainian",
+	"ja":    "Japanese",
+	"ko":    "Korean",
+	"zh":    "Simplified Chinese",
+	"zh-TW": "Traditional Chinese",
+}
+
+// Normalize canonicalizes a locale tag: "pt_br" becomes "pt-BR", and a
+// regional tag Temper doesn't know ("de-AT") falls back to its language
+// ("de"). POSIX suffixes like ".UTF-8" are dropped.
+func Normalize(tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	lang, region, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	lang = strings.ToLower(lang)
+	if region != "" {
+		if full := lang + "-" + strings.ToUpper(region); names[full] != "" {
+			return full, nil
+		}
+	}
+	if names[lang] == "" {
+		return "", fmt.Errorf("%w: %q", ErrUnsupported, tag)
+	}
+	return lang, nil
+}
+
+// Name returns the English name of a locale's language, or "" when the
+// locale isn't supported
+func Name(tag string) string {
+	tag, err := Normalize(tag)
+	if err != nil {
+		return ""
+	}
+	return names[tag]
+}
+
+// Supported returns the supported locale tags, sorted
+func Supported() []string {
+	tags := make([]string, 0, len(names))
+	for tag := range names {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Resolve picks the learner's locale: $TEMPER_LOCALE, then the configured
+// locale, then the system's LC_ALL, LC_MESSAGES and LANG. Unsupported
+// values are skipped; with none left it returns Default.
+func Resolve(configured string) string {
+	candidates := []string{os.Getenv(Env), configured, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+	for _, c := range candidates {
+		if c == "" || c == "C" || c == "POSIX" {
+			continue
+		}
+		if tag, err := Normalize(c); err == nil {
+			return tag
+		}
+	}
+	return Default
+}
@@ -0,0 +1,96 @@
+package locale
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "de", want: "de"},
+		{in: "DE", want: "de"},
+		{in: "pt_br", want: "pt-BR"},
+		{in: "pt-PT", want: "pt"},
+		{in: "de_AT.UTF-8", want: "de"},
+		{in: "zh-TW", want: "zh-TW"},
+		{in: "xx", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Normalize(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsupported) {
+					t.Errorf("Normalize(%q) error = %v; want ErrUnsupported", tt.in, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Normalize(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "fr_FR.UTF-8")
+	t.Setenv(Env, "")
+
+	if got := Resolve(""); got != "fr" {
+		t.Errorf("system locale: got %q; want fr", got)
+	}
+	if got := Resolve("de"); got != "de" {
+		t.Errorf("configured: got %q; want de", got)
+	}
+	if got := Resolve("klingon"); got != "fr" {
+		t.Errorf("unsupported configured: got %q; want the system's fr", got)
+	}
+
+	t.Setenv(Env, "es")
+	if got := Resolve("de"); got != "es" {
+		t.Errorf("env override: got %q; want es", got)
+	}
+
+	t.Setenv(Env, "")
+	t.Setenv("LANG", "C.UTF-8")
+	if got := Resolve(""); got != Default {
+		t.Errorf("C locale: got %q; want %q", got, Default)
+	}
+}
+
+func TestPrinter_T(t *testing.T) {
+	if got := NewPrinter("de").T("stats.title"); got != "Lernstatistik" {
+		t.Errorf("de: got %q", got)
+	}
+	if got := NewPrinter("pt-BR").T("stats.attempts", 3); got != "3 tentativas" {
+		t.Errorf("pt-BR falls back to pt: got %q", got)
+	}
+	if got := NewPrinter("ja").T("stats.title"); got != "Learning Statistics" {
+		t.Errorf("untranslated falls back to English: got %q", got)
+	}
+	if got := NewPrinter("xx").Locale(); got != Default {
+		t.Errorf("unsupported locale: got %q; want %q", got, Default)
+	}
+	if got := NewPrinter("en").T("no.such.message"); got != "no.such.message" {
+		t.Errorf("unknown ID: got %q", got)
+	}
+}
+
+func TestMessages_HaveEnglish(t *testing.T) {
+	for id, translations := range messages {
+		if translations[Default] == "" {
+			t.Errorf("message %q has no English text", id)
+		}
+		for tag := range translations {
+			if _, ok := names[tag]; !ok {
+				t.Errorf("message %q has a translation for unsupported locale %q", id, tag)
+			}
+		}
+	}
+}
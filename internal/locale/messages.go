@@ -0,0 +1,136 @@
+package locale
+
+import (
+	"fmt"
+	"strings"
+)
+
+// messages holds the CLI strings that have translations, by message ID
+// and locale. English is the fallback for every message, so a message
+// only needs the locales someone has translated.
+var messages = map[string]map[string]string{
+	"stats.title": {
+		"en": "Learning Statistics",
+		"de": "Lernstatistik",
+		"es": "Estadísticas de aprendizaje",
+		"fr": "Statistiques d'apprentissage",
+		"pt": "Estatísticas de aprendizagem",
+	},
+	"stats.sessions": {
+		"en": "Total Sessions",
+		"de": "Sitzungen",
+		"es": "Sesiones",
+		"fr": "Sessions",
+		"pt": "Sessões",
+	},
+	"stats.completed": {
+		"en": "Completed",
+		"de": "Abgeschlossen",
+		"es": "Completadas",
+		"fr": "Terminées",
+		"pt": "Concluídas",
+	},
+	"stats.exercises": {
+		"en": "Total Exercises",
+		"de": "Übungen",
+		"es": "Ejercicios",
+		"fr": "Exercices",
+		"pt": "Exercícios",
+	},
+	"stats.runs": {
+		"en": "Total Runs",
+		"de": "Ausführungen",
+		"es": "Ejecuciones",
+		"fr": "Exécutions",
+		"pt": "Execuções",
+	},
+	"stats.hints": {
+		"en": "Total Hints",
+		"de": "Hinweise",
+		"es": "Pistas",
+		"fr": "Indices",
+		"pt": "Dicas",
+	},
+	"stats.hint_dependency": {
+		"en": "Hint Dependency",
+		"de": "Hinweisabhängigkeit",
+		"es": "Dependencia de pistas",
+		"fr": "Dépendance aux indices",
+		"pt": "Dependência de dicas",
+	},
+	"stats.time_to_green": {
+		"en": "Avg Time to Green",
+		"de": "Ø Zeit bis grün",
+		"es": "Tiempo medio hasta verde",
+		"fr": "Temps moyen avant vert",
+		"pt": "Tempo médio até verde",
+	},
+	"stats.topics": {
+		"en": "Most Practiced Topics",
+		"de": "Meistgeübte Themen",
+		"es": "Temas más practicados",
+		"fr": "Sujets les plus pratiqués",
+		"pt": "Tópicos mais praticados",
+	},
+	"stats.attempts": {
+		"en": "%d attempts",
+		"de": "%d Versuche",
+		"es": "%d intentos",
+		"fr": "%d tentatives",
+		"pt": "%d tentativas",
+	},
+	"stats.no_skills": {
+		"en": "No skills tracked yet. Start practicing!",
+		"de": "Noch keine Fähigkeiten erfasst. Fang an zu üben!",
+		"es": "Todavía no hay habilidades registradas. ¡Empieza a practicar!",
+		"fr": "Aucune compétence suivie pour l'instant. Commencez à pratiquer !",
+		"pt": "Nenhuma habilidade registrada ainda. Comece a praticar!",
+	},
+	"stats.skills_title": {
+		"en": "Skills by Topic",
+		"de": "Fähigkeiten nach Thema",
+		"es": "Habilidades por tema",
+		"fr": "Compétences par sujet",
+		"pt": "Habilidades por tópico",
+	},
+}
+
+// Printer translates CLI messages into one locale
+type Printer struct {
+	locale string
+}
+
+// NewPrinter returns a Printer for the given locale. Unsupported locales
+// print English.
+func NewPrinter(tag string) *Printer {
+	tag, err := Normalize(tag)
+	if err != nil {
+		tag = Default
+	}
+	return &Printer{locale: tag}
+}
+
+// Locale returns the printer's locale tag
+func (p *Printer) Locale() string {
+	return p.locale
+}
+
+// T returns the message with the given ID in the printer's locale,
+// formatted with args. It falls back from a regional locale to its
+// language, then to English, then to the ID itself.
+func (p *Printer) T(id string, args ...interface{}) string {
+	msg := id
+	if translations, ok := messages[id]; ok {
+		lang, _, _ := strings.Cut(p.locale, "-")
+		for _, tag := range []string{p.locale, lang, Default} {
+			if s, ok := translations[tag]; ok {
+				msg = s
+				break
+			}
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
@@ -134,9 +134,11 @@ type InterventionInput struct {
 }
 
 type InterventionOutput struct {
-	Level   int    `json:"level"`
-	Type    string `json:"type"`
-	Content string `json:"content"`
+	Level     int                   `json:"level"`
+	Type      string                `json:"type"`
+	Content   string                `json:"content"`
+	Concepts  []string              `json:"concepts,omitempty"`
+	Rationale domain.LevelRationale `json:"rationale"`
 }
 
 type RunInput struct {
@@ -252,9 +254,10 @@ func (s *Server) handleIntervention(ctx context.Context, input InterventionInput
 
 	// Load exercise for context
 	var ex *domain.Exercise
-	parts := strings.SplitN(sess.ExerciseID, "/", 2)
-	if len(parts) >= 2 {
-		ex, _ = s.exerciseLoader.LoadExercise(parts[0], parts[1])
+	if sess.ExerciseID != "" {
+		if loaded, err := s.exerciseLoader.LoadExerciseByID(sess.ExerciseID); err == nil {
+			ex = loaded.WithVariant(sess.ExerciseVariant())
+		}
 	}
 
 	// Use provided code or session's code
@@ -271,8 +274,10 @@ func (s *Server) handleIntervention(ctx context.Context, input InterventionInput
 		Context: pairing.InterventionContext{
 			Exercise: ex,
 			Code:     code,
+			Language: sess.Language,
 		},
-		Policy: sess.Policy,
+		Policy:      sess.Policy,
+		BudgetSpent: sess.BudgetSpent,
 	}
 
 	// Generate intervention
@@ -290,14 +295,17 @@ func (s *Server) handleIntervention(ctx context.Context, input InterventionInput
 		Type:      intervention.Type,
 		Content:   intervention.Content,
 		CreatedAt: time.Now(),
+		Concepts:  intervention.Concepts,
 	}
 	// Record intervention - log but don't fail on error
 	_ = s.sessionService.RecordIntervention(ctx, sessionIntervention)
 
 	return InterventionOutput{
-		Level:   int(intervention.Level),
-		Type:    string(intervention.Type),
-		Content: intervention.Content,
+		Level:     int(intervention.Level),
+		Type:      string(intervention.Type),
+		Content:   intervention.Content,
+		Concepts:  intervention.Concepts,
+		Rationale: intervention.Contract,
 	}, nil
 }
 
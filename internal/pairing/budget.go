@@ -0,0 +1,31 @@
+package pairing
+
+import (
+	"errors"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// ErrHintBudgetExhausted is returned when the session's hint budget can't
+// pay for even the cheapest hint
+var ErrHintBudgetExhausted = errors.New("hint budget exhausted")
+
+// budgetRemaining returns the tokens the request's session has left
+func budgetRemaining(req InterventionRequest) int {
+	return req.Policy.Budget.Remaining(req.BudgetSpent)
+}
+
+// checkBudget refuses a request the budget can't pay for at any level
+func checkBudget(req InterventionRequest) error {
+	if _, ok := req.Policy.Budget.Affordable(domain.L0Clarify, budgetRemaining(req)); !ok {
+		return ErrHintBudgetExhausted
+	}
+	return nil
+}
+
+// applyBudget lowers level to the most detailed one the remaining budget
+// pays for
+func applyBudget(level domain.InterventionLevel, req InterventionRequest) domain.InterventionLevel {
+	affordable, _ := req.Policy.Budget.Affordable(level, budgetRemaining(req))
+	return affordable
+}
@@ -1,6 +1,7 @@
 package pairing
 
 import (
+	"github.com/felixgeelhaar/temper/internal/analysis"
 	"github.com/felixgeelhaar/temper/internal/domain"
 	"github.com/felixgeelhaar/temper/internal/session"
 )
@@ -11,6 +12,10 @@ type AuthoringContext struct {
 	Section   string              // Current section: goals, features, acceptance_criteria, non_functional
 	Documents []domain.Document   // Discovered project documents
 	Question  string              // Optional user question for hints
+
+	// Siblings are the session's other spec files; suggestions must not
+	// duplicate their features
+	Siblings []*domain.ProductSpec
 }
 
 // HasDocuments returns true if there are documents available
@@ -71,6 +76,10 @@ type InterventionContext struct {
 	Code     map[string]string
 	Profile  *domain.LearningProfile
 
+	// Language is the one detected from the learner's files, for sessions
+	// without an exercise to say
+	Language string
+
 	// Run output signals
 	RunOutput *domain.RunOutput
 
@@ -78,12 +87,19 @@ type InterventionContext struct {
 	CurrentFile string
 	CursorLine  int
 
+	// Symbol is the identifier an explain-symbol request is about
+	Symbol *analysis.Symbol
+
 	// Session context
 	SessionIntent session.SessionIntent
 
 	// Spec context (for feature guidance sessions)
 	Spec           *domain.ProductSpec
 	FocusCriterion *domain.AcceptanceCriterion
+
+	// FailingTests names the tests that failed in any run of the session;
+	// test-first sessions use them to link tests to spec features
+	FailingTests []string
 }
 
 // HasSpec returns true if this context has spec information
@@ -0,0 +1,106 @@
+package pairing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/profile"
+)
+
+// decideLevel applies the learning contract to a request: the selector's
+// level (or the explicit escalation level), the policy and topic caps,
+// test-first and the hint budget. It returns the level, the feature test-first applied to, and
+// the rule that had the last word.
+func (s *Service) decideLevel(req InterventionRequest) (domain.InterventionLevel, *domain.Feature, domain.LevelRationale) {
+	r := domain.LevelRationale{
+		MaxLevel: req.Policy.MaxLevel,
+		Track:    req.Policy.Track,
+	}
+
+	var level domain.InterventionLevel
+	if req.ExplicitLevel > 0 {
+		level = req.ExplicitLevel
+		r.Requested = level
+		r.Rule = domain.RuleEscalation
+	} else {
+		r.Requested = s.selector.SelectLevel(req.Intent, req.Context, req.Policy)
+		// Apply policy clamp, including the per-topic adjustment when the
+		// learner's exercise + profile expose a topic skill.
+		level = applyPolicyClamp(r.Requested, req.Policy, req.Context)
+		switch {
+		case level == r.Requested:
+			r.Rule = domain.RuleContext
+		case level == req.Policy.ClampLevel(r.Requested):
+			r.Rule = domain.RuleLevelCap
+		default:
+			r.Rule = domain.RuleTopicCap
+		}
+	}
+
+	// Test-first holds back implementation hints, escalations included
+	capped, feature := applyTestFirst(level, req)
+	if capped < level {
+		r.Rule = domain.RuleTestFirst
+	}
+
+	// The budget has the last word: it pays for what the other rules allow
+	if req.Policy.Budget.Enabled() {
+		remaining := budgetRemaining(req)
+		r.BudgetRemaining = &remaining
+		if budgeted := applyBudget(capped, req); budgeted < capped {
+			capped = budgeted
+			r.Rule = domain.RuleBudget
+		}
+	}
+	r.Level = capped
+	r.Summary = contractSummary(r, req, feature)
+	return capped, feature, r
+}
+
+// contractSummary says in one sentence why the learner got this level
+func contractSummary(r domain.LevelRationale, req InterventionRequest, feature *domain.Feature) string {
+	track := r.Track
+	if track == "" {
+		track = "current"
+	}
+
+	switch r.Rule {
+	case domain.RuleEscalation:
+		return fmt.Sprintf("You asked for L%d (%s) with a justification, so the usual cap was lifted for this answer.", r.Level, r.Level)
+	case domain.RuleLevelCap:
+		return fmt.Sprintf("Your request called for L%d (%s), but the %s track caps help at L%d (%s).",
+			r.Requested, r.Requested, track, r.MaxLevel, r.MaxLevel)
+	case domain.RuleTopicCap:
+		topic := "this topic"
+		if req.Context.Exercise != nil {
+			topic = profile.ExtractTopic(req.Context.Exercise.ID)
+		}
+		return fmt.Sprintf("You're strong in %s, so help there stops one level below the track cap, at L%d (%s).", topic, r.Level, r.Level)
+	case domain.RuleTestFirst:
+		return fmt.Sprintf("This session is test-first: until a failing test references %s, help stops at L%d (%s).", feature.ID, r.Level, r.Level)
+	case domain.RuleBudget:
+		return fmt.Sprintf("Your request called for more detail, but with %d hint tokens left the budget pays for L%d (%s).", *r.BudgetRemaining, r.Level, r.Level)
+	}
+	return fmt.Sprintf("L%d (%s) is what this %s request calls for at this point; the %s track allows up to L%d (%s).",
+		r.Level, r.Level, req.Intent, track, r.MaxLevel, r.MaxLevel)
+}
+
+// CooldownRationale explains an intervention refused because detailed
+// help was requested again before the policy's cooldown ended
+func CooldownRationale(policy domain.LearningPolicy, requested domain.InterventionLevel, remaining time.Duration) domain.LevelRationale {
+	track := policy.Track
+	if track == "" {
+		track = "current"
+	}
+	return domain.LevelRationale{
+		Rule:      domain.RuleCooldown,
+		Requested: requested,
+		Level:     requested,
+		MaxLevel:  policy.MaxLevel,
+		Track:     policy.Track,
+		Summary: fmt.Sprintf("The %s track waits %ds between requests for L%d (%s) or more detailed help; %.0fs to go.",
+			track, policy.CooldownSeconds, requested, requested, remaining.Seconds()),
+		CooldownRemaining: remaining.Seconds(),
+	}
+}
@@ -0,0 +1,131 @@
+package pairing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+func TestDecideLevel_Rules(t *testing.T) {
+	service := NewService(llm.NewRegistry(), "")
+
+	strong := &domain.LearningProfile{TopicSkills: map[string]domain.SkillLevel{"go/basics": {Level: 0.9}}}
+	exercise := &domain.Exercise{ID: "go-v1/basics/hello"}
+
+	tests := []struct {
+		name  string
+		req   InterventionRequest
+		rule  string
+		level domain.InterventionLevel
+	}{
+		{
+			name:  "context",
+			req:   InterventionRequest{Intent: domain.IntentHint, Policy: domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet, Track: "practice"}},
+			rule:  domain.RuleContext,
+			level: domain.L1CategoryHint,
+		},
+		{
+			name:  "level cap",
+			req:   InterventionRequest{Intent: domain.IntentStuck, Policy: domain.LearningPolicy{MaxLevel: domain.L1CategoryHint, Track: "interview_prep"}},
+			rule:  domain.RuleLevelCap,
+			level: domain.L1CategoryHint,
+		},
+		{
+			name: "topic cap",
+			req: InterventionRequest{
+				Intent:  domain.IntentStuck,
+				Policy:  domain.LearningPolicy{MaxLevel: domain.L2LocationConcept},
+				Context: InterventionContext{Exercise: exercise, Profile: strong},
+			},
+			rule:  domain.RuleTopicCap,
+			level: domain.L1CategoryHint,
+		},
+		{
+			name:  "escalation",
+			req:   InterventionRequest{Intent: domain.IntentStuck, ExplicitLevel: domain.L4PartialSolution, Policy: domain.LearningPolicy{MaxLevel: domain.L4PartialSolution}},
+			rule:  domain.RuleEscalation,
+			level: domain.L4PartialSolution,
+		},
+		{
+			name:  "test first",
+			req:   testFirstRequest(),
+			rule:  domain.RuleTestFirst,
+			level: TestFirstCeiling,
+		},
+		{
+			name: "budget",
+			req: InterventionRequest{
+				Intent:        domain.IntentStuck,
+				ExplicitLevel: domain.L4PartialSolution,
+				Policy:        domain.LearningPolicy{MaxLevel: domain.L4PartialSolution, Budget: domain.HintBudget{Tokens: 5}},
+				BudgetSpent:   3,
+			},
+			rule:  domain.RuleBudget,
+			level: domain.L3ConstrainedSnippet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, _, r := service.decideLevel(tt.req)
+			if level != tt.level || r.Level != tt.level || r.Rule != tt.rule {
+				t.Errorf("decideLevel = L%d, %+v; want L%d by %s", level, r, tt.level, tt.rule)
+			}
+			if r.Summary == "" {
+				t.Error("rationale should carry a summary")
+			}
+		})
+	}
+}
+
+func TestContractSummary_NamesTheRule(t *testing.T) {
+	service := NewService(llm.NewRegistry(), "")
+
+	_, _, r := service.decideLevel(InterventionRequest{
+		Intent: domain.IntentStuck,
+		Policy: domain.LearningPolicy{MaxLevel: domain.L1CategoryHint, Track: "interview_prep"},
+	})
+	mustContain(t, r.Summary, "L2", "interview_prep track caps help at L1")
+
+	_, _, r = service.decideLevel(testFirstRequest())
+	mustContain(t, r.Summary, "test-first", "password-reset")
+}
+
+func TestCompose_HintBudgetExhausted(t *testing.T) {
+	service := NewService(llm.NewRegistry(), "")
+	req := InterventionRequest{
+		Intent:      domain.IntentHint,
+		Policy:      domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet, Budget: domain.HintBudget{Tokens: 3, Costs: []int{1}}},
+		BudgetSpent: 3,
+	}
+	if _, err := service.compose(req, forLLM); !errors.Is(err, ErrHintBudgetExhausted) {
+		t.Errorf("compose() error = %v, want ErrHintBudgetExhausted", err)
+	}
+
+	// L0 is free by default, so a spent budget still allows clarifying questions
+	req.Policy.Budget.Costs = nil
+	c, err := service.compose(req, forLLM)
+	if err != nil {
+		t.Fatalf("compose() error = %v", err)
+	}
+	if c.level != domain.L0Clarify || c.contract.Rule != domain.RuleBudget || *c.contract.BudgetRemaining != 0 {
+		t.Errorf("compose() = L%d, %+v; want L0 by budget", c.level, c.contract)
+	}
+	mustContain(t, c.contract.Summary, "0 hint tokens left")
+}
+
+func TestCooldownRationale(t *testing.T) {
+	policy := domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet, CooldownSeconds: 60, Track: "practice"}
+	r := CooldownRationale(policy, domain.L3ConstrainedSnippet, 42*time.Second)
+
+	if r.Rule != domain.RuleCooldown || r.CooldownRemaining != 42 {
+		t.Errorf("unexpected rationale: %+v", r)
+	}
+	if !strings.Contains(r.Summary, "60s") || !strings.Contains(r.Summary, "42s to go") {
+		t.Errorf("summary should give the cooldown and time left: %s", r.Summary)
+	}
+}
@@ -0,0 +1,66 @@
+package pairing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+// DocLookup returns the documentation of a Go standard library symbol,
+// e.g. "strings.Builder" or "net/http.Client.Do"
+type DocLookup func(symbol string) (string, error)
+
+// docToolDirective tells the model when to use the go_doc tool. It is a
+// separate system block so the cached level prompt stays the same.
+const docToolDirective = `You can call the go_doc tool to read the documentation of a Go standard library package or symbol. Call it before naming a standard library function, method or type whose exact signature or behavior you are not sure of, and describe it as the documentation does.`
+
+// SetDocLookup lets the model look up Go standard library documentation
+// while writing interventions, on providers that support tool calls
+func (s *Service) SetDocLookup(fn DocLookup) {
+	s.docLookup = fn
+}
+
+// tools returns the tools offered to the model for a request. Doc
+// lookups only make sense for Go, or when the language isn't known.
+func (s *Service) tools(ctx InterventionContext) []llm.ToolHandler {
+	if s.docLookup == nil {
+		return nil
+	}
+	if lang := contextLanguage(ctx); lang != "" && lang != "go" {
+		return nil
+	}
+	return []llm.ToolHandler{s.docTool()}
+}
+
+func (s *Service) docTool() llm.ToolHandler {
+	return llm.ToolHandler{
+		Tool: llm.Tool{
+			Name:        "go_doc",
+			Description: "Returns the documentation of a Go standard library package or symbol: its declaration and doc comment. Types include their method signatures.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"symbol": map[string]any{
+						"type":        "string",
+						"description": `Import path, optionally followed by .Name or .Type.Method, e.g. "strings", "strings.Builder", "net/http.Client.Do"`,
+					},
+				},
+				"required": []string{"symbol"},
+			},
+		},
+		Call: func(_ context.Context, input json.RawMessage) (string, error) {
+			var in struct {
+				Symbol string `json:"symbol"`
+			}
+			if err := json.Unmarshal(input, &in); err != nil {
+				return "", err
+			}
+			if in.Symbol == "" {
+				return "", errors.New("symbol is required")
+			}
+			return s.docLookup(in.Symbol)
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package pairing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/google/uuid"
+)
+
+// toolMockProvider asks for go_doc once, then answers
+type toolMockProvider struct {
+	mockProvider
+	reqs []llm.Request
+}
+
+func (m *toolMockProvider) SupportsTools() bool { return true }
+
+func (m *toolMockProvider) Generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	m.reqs = append(m.reqs, *req)
+	if len(m.reqs) == 1 {
+		return &llm.Response{ToolCalls: []llm.ToolCall{
+			{ID: "call_1", Name: "go_doc", Input: json.RawMessage(`{"symbol":"strings.Builder"}`)},
+		}}, nil
+	}
+	return &llm.Response{Content: "Look at what strings.Builder offers for appending."}, nil
+}
+
+func docInterventionRequest(language string) InterventionRequest {
+	return InterventionRequest{
+		SessionID: uuid.New(),
+		UserID:    uuid.New(),
+		Intent:    domain.IntentHint,
+		Context: InterventionContext{
+			Exercise: &domain.Exercise{ID: "go-v1/basics/concat", Language: language},
+			Code:     map[string]string{"main.go": "package main"},
+		},
+		Policy: domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet},
+	}
+}
+
+func TestService_Intervene_DocLookup(t *testing.T) {
+	mock := &toolMockProvider{mockProvider: mockProvider{name: "test"}}
+	registry := llm.NewRegistry()
+	registry.Register("test", mock)
+	registry.SetDefault("test")
+	service := NewService(registry, "test")
+
+	var looked []string
+	service.SetDocLookup(func(symbol string) (string, error) {
+		looked = append(looked, symbol)
+		return "type Builder struct{ ... }", nil
+	})
+
+	intervention, err := service.Intervene(context.Background(), docInterventionRequest("go"))
+	if err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+	if intervention.Content != "Look at what strings.Builder offers for appending." {
+		t.Errorf("Content = %q; want the reply after the lookup", intervention.Content)
+	}
+	if len(looked) != 1 || looked[0] != "strings.Builder" {
+		t.Errorf("looked up %v; want [strings.Builder]", looked)
+	}
+	if len(mock.reqs) != 2 {
+		t.Fatalf("provider calls = %d; want 2", len(mock.reqs))
+	}
+
+	first := mock.reqs[0]
+	if len(first.Tools) != 1 || first.Tools[0].Name != "go_doc" {
+		t.Errorf("Tools = %+v; want go_doc", first.Tools)
+	}
+	last := first.SystemBlocks[len(first.SystemBlocks)-1]
+	if !strings.Contains(last.Text, "go_doc") || last.CacheControl {
+		t.Errorf("last system block = %+v; want the uncached go_doc directive", last)
+	}
+	results := mock.reqs[1].Messages[2].ToolResults
+	if len(results) != 1 || results[0].CallID != "call_1" || results[0].Content != "type Builder struct{ ... }" {
+		t.Errorf("ToolResults = %+v; want the documentation", results)
+	}
+}
+
+func TestService_Tools(t *testing.T) {
+	service := NewService(llm.NewRegistry(), "")
+	if tools := service.tools(InterventionContext{}); tools != nil {
+		t.Errorf("tools() = %+v without a lookup; want none", tools)
+	}
+
+	service.SetDocLookup(func(string) (string, error) { return "", errors.New("not found") })
+	if tools := service.tools(InterventionContext{Exercise: &domain.Exercise{Language: "python"}}); tools != nil {
+		t.Errorf("tools() = %+v for python; want none", tools)
+	}
+	if tools := service.tools(InterventionContext{Language: "python"}); tools != nil {
+		t.Errorf("tools() = %+v for python files; want none", tools)
+	}
+	tools := service.tools(InterventionContext{})
+	if len(tools) != 1 {
+		t.Fatalf("tools() = %+v without an exercise; want go_doc", tools)
+	}
+
+	if _, err := tools[0].Call(context.Background(), json.RawMessage(`{}`)); err == nil {
+		t.Error("Call() without a symbol succeeded")
+	}
+	if _, err := tools[0].Call(context.Background(), json.RawMessage(`{"symbol":"strings.Nope"}`)); err == nil || err.Error() != "not found" {
+		t.Errorf("Call() error = %v; want the lookup's error", err)
+	}
+}
@@ -0,0 +1,102 @@
+package pairing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/correlation"
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+// ErrUnparseableExplanations is returned when the LLM's explanations are
+// not one string per error, as the prompt asked
+var ErrUnparseableExplanations = errors.New("error explanations are not a JSON array of strings")
+
+// ExplainErrors restates compiler and runtime errors the offline rule
+// table doesn't cover in beginner-friendly language, one explanation per
+// error in the same order. packID and paths select the local provider when
+// local-only rules match the session.
+func (s *Service) ExplainErrors(ctx context.Context, packID string, paths []string, errs []string) ([]string, error) {
+	if len(errs) == 0 {
+		return nil, nil
+	}
+
+	provider, err := s.provider(s.localOnly.Matches(packID, paths))
+	if err != nil {
+		return nil, fmt.Errorf("get LLM provider: %w", err)
+	}
+	prompt, _ := s.redactPrompt(provider, s.prompter.BuildExplainErrorsPrompt(errs))
+
+	system := s.localize(s.prompter.ExplainErrorsSystemPrompt())
+	var explanations []string
+	err = s.generateStructured(ctx, provider, &llm.Request{
+		Messages: []llm.Message{
+			{Role: llm.RoleUser, Content: prompt},
+		},
+		System: system,
+		SystemBlocks: []llm.SystemContentBlock{
+			{Text: system, CacheControl: true},
+		},
+		CorrelationID: correlation.FromContext(ctx),
+		MaxTokens:     1024,
+		Temperature:   0.2,
+	}, fmt.Sprintf("Respond again with ONLY a JSON array of exactly %d strings, one per error, in the order given. No markdown fences or other text.", len(errs)),
+		func(content string) (err error) {
+			explanations, err = ParseExplanations(content, len(errs))
+			return err
+		})
+	if err != nil && !errors.Is(err, ErrUnparseableExplanations) {
+		return nil, fmt.Errorf("generate explanations: %w", err)
+	}
+	return explanations, err
+}
+
+// ExplainErrorsSystemPrompt returns the system prompt for error explanations
+func (p *Prompter) ExplainErrorsSystemPrompt() string {
+	return `You explain compiler, test and runtime errors to programming beginners who find raw tool output intimidating.
+
+For each error, write one or two short sentences in plain language: what the error means and where in their code to look. Avoid jargon, or explain it when you must use it.
+
+Never write code, never name the exact change that fixes it, and never solve the exercise: the learner fixes the error themselves.
+
+Output ONLY a JSON array of strings, one per error, in the order given. No markdown fences or other text.`
+}
+
+// BuildExplainErrorsPrompt lists the errors to explain
+func (p *Prompter) BuildExplainErrorsPrompt(errs []string) string {
+	f := newFence()
+	var sb strings.Builder
+	sb.WriteString(f.securityPreamble())
+	fmt.Fprintf(&sb, "## Errors\n\nExplain these %d errors:\n\n", len(errs))
+	for i, e := range errs {
+		sb.WriteString(f.wrap(fmt.Sprintf("ERROR-%d", i+1), e))
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
+
+// ParseExplanations reads the LLM's JSON array of explanations, which must
+// have exactly n entries
+func ParseExplanations(content string, n int) ([]string, error) {
+	var explanations []string
+	if err := json.Unmarshal([]byte(content), &explanations); err != nil {
+		start := strings.Index(content, "[")
+		end := strings.LastIndex(content, "]")
+		if start < 0 || end <= start {
+			return nil, ErrUnparseableExplanations
+		}
+		if err := json.Unmarshal([]byte(content[start:end+1]), &explanations); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnparseableExplanations, err)
+		}
+	}
+	if len(explanations) != n {
+		return nil, fmt.Errorf("%w: got %d for %d errors", ErrUnparseableExplanations, len(explanations), n)
+	}
+	for i := range explanations {
+		explanations[i] = strings.TrimSpace(explanations[i])
+	}
+	return explanations, nil
+}
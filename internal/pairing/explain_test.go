@@ -0,0 +1,51 @@
+package pairing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+func TestParseExplanations(t *testing.T) {
+	got, err := ParseExplanations("```json\n[\" The panic message was custom. \", \"Second.\"]\n```", 2)
+	if err != nil {
+		t.Fatalf("ParseExplanations() error = %v", err)
+	}
+	if got[0] != "The panic message was custom." || got[1] != "Second." {
+		t.Errorf("got %q", got)
+	}
+
+	if _, err := ParseExplanations(`["only one"]`, 2); !errors.Is(err, ErrUnparseableExplanations) {
+		t.Errorf("count mismatch: error = %v, want ErrUnparseableExplanations", err)
+	}
+	if _, err := ParseExplanations("It means the code is wrong.", 1); !errors.Is(err, ErrUnparseableExplanations) {
+		t.Errorf("prose: error = %v, want ErrUnparseableExplanations", err)
+	}
+}
+
+func TestService_ExplainErrors(t *testing.T) {
+	mock := &mockProvider{
+		name:     "test",
+		response: &llm.Response{Content: `["Your code panicked on purpose with the message boom."]`},
+	}
+	service := createTestService(mock)
+
+	got, err := service.ExplainErrors(context.Background(), "go-v1", []string{"main.go"}, []string{"panic: boom"})
+	if err != nil {
+		t.Fatalf("ExplainErrors() error = %v", err)
+	}
+	if len(got) != 1 || !strings.Contains(got[0], "boom") {
+		t.Errorf("got %q", got)
+	}
+
+	prompt := sentPrompt(mock.lastReq)
+	if !strings.Contains(prompt, "UNTRUSTED-ERROR-1") || !strings.Contains(prompt, "panic: boom") {
+		t.Errorf("prompt should fence each error:\n%s", prompt)
+	}
+	if !strings.Contains(mock.lastReq.System, "Never write code") {
+		t.Error("system prompt should forbid code")
+	}
+}
@@ -0,0 +1,57 @@
+package pairing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/google/uuid"
+)
+
+// authoredHint returns the hint the exercise's author wrote for level.
+// Only hint and stuck requests get one; reviews and explanations are
+// about the learner's code, which an authored text can't know.
+func authoredHint(req InterventionRequest, level domain.InterventionLevel) (string, bool) {
+	if req.Context.Exercise == nil {
+		return "", false
+	}
+	if req.Intent != domain.IntentHint && req.Intent != domain.IntentStuck {
+		return "", false
+	}
+	return req.Context.Exercise.HintLadder.Step(level)
+}
+
+// authoredIntervention delivers an authored hint as written, without
+// asking the LLM
+func (s *Service) authoredIntervention(req InterventionRequest, c composedPrompt, hint string) *domain.Intervention {
+	iv := &domain.Intervention{
+		ID:          uuid.New(),
+		SessionID:   req.SessionID,
+		UserID:      req.UserID,
+		RunID:       req.RunID,
+		Intent:      req.Intent,
+		Level:       c.level,
+		Type:        c.interventionType,
+		Content:     hint,
+		Targets:     s.extractTargets(req.Context),
+		Rationale:   fmt.Sprintf("Authored hint from %s at L%d (intent=%s)", req.Context.Exercise.ID, c.level, req.Intent),
+		Concepts:    s.concepts.Tag(hint),
+		RequestedAt: time.Now(),
+		DeliveredAt: time.Now(),
+		Contract:    c.contract,
+	}
+	iv.Contract.Details = iv.Rationale
+	return iv
+}
+
+// authoredStream streams an authored hint the way a generated one would
+// arrive: metadata, the content in one piece, then done
+func (s *Service) authoredStream(req InterventionRequest, c composedPrompt, hint string) <-chan StreamChunk {
+	iv := s.authoredIntervention(req, c, hint)
+	out := make(chan StreamChunk, 3)
+	out <- StreamChunk{Type: "metadata", Metadata: &InterventionMetadata{Level: iv.Level, Type: iv.Type, Contract: iv.Contract}}
+	out <- StreamChunk{Type: "content", Content: hint}
+	out <- StreamChunk{Type: "done", Concepts: iv.Concepts}
+	close(out)
+	return out
+}
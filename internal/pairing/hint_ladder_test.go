@@ -0,0 +1,64 @@
+package pairing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/google/uuid"
+)
+
+func TestService_AuthoredHints(t *testing.T) {
+	registry := llm.NewRegistry()
+	registry.Register("failing", &failingProvider{err: errors.New("LLM asked")})
+	if err := registry.SetDefault("failing"); err != nil {
+		t.Fatal(err)
+	}
+	service := NewService(registry, "failing")
+
+	exercise := &domain.Exercise{
+		ID:         "go-v1/basics/hello-world",
+		HintLadder: domain.HintLadder{L1: "Think about string formatting.", L2: "fmt.Sprintf takes a %s verb."},
+	}
+	req := func(intent domain.Intent, level domain.InterventionLevel) InterventionRequest {
+		return InterventionRequest{
+			SessionID:     uuid.New(),
+			Intent:        intent,
+			Context:       InterventionContext{Exercise: exercise},
+			Policy:        domain.LearningPolicy{MaxLevel: domain.L4PartialSolution},
+			ExplicitLevel: level,
+		}
+	}
+
+	got, err := service.Intervene(context.Background(), req(domain.IntentHint, domain.L2LocationConcept))
+	if err != nil {
+		t.Fatalf("Intervene() error = %v; the authored hint needs no LLM", err)
+	}
+	if got.Content != exercise.HintLadder.L2 || got.Level != domain.L2LocationConcept || got.Contract.Details == "" {
+		t.Errorf("Intervene() = %+v; want the authored L2 hint with its rationale", got)
+	}
+
+	// Above the ladder, and for reviews, the LLM is asked
+	if _, err := service.Intervene(context.Background(), req(domain.IntentStuck, domain.L3ConstrainedSnippet)); err == nil {
+		t.Error("L3 has no authored hint; the LLM should have been asked")
+	}
+	if _, err := service.Intervene(context.Background(), req(domain.IntentReview, domain.L1CategoryHint)); err == nil {
+		t.Error("reviews should not get authored hints")
+	}
+
+	stream, err := service.IntervenStream(context.Background(), req(domain.IntentStuck, domain.L1CategoryHint))
+	if err != nil {
+		t.Fatalf("IntervenStream() error = %v", err)
+	}
+	var types []string
+	var content string
+	for chunk := range stream {
+		types = append(types, chunk.Type)
+		content += chunk.Content
+	}
+	if len(types) != 3 || types[0] != "metadata" || types[2] != "done" || content != exercise.HintLadder.L1 {
+		t.Errorf("stream = %v %q; want metadata, the L1 hint, done", types, content)
+	}
+}
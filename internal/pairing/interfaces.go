@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/session"
 )
 
 // PairingService defines the interface for pairing engine operations
@@ -20,6 +21,15 @@ type PairingService interface {
 
 	// AuthoringHint generates a hint for spec authoring based on a question
 	AuthoringHint(ctx context.Context, authCtx AuthoringContext) (*domain.Intervention, error)
+
+	// ReviewSpec critiques a spec and suggests rewrites without applying them
+	ReviewSpec(ctx context.Context, spec *domain.ProductSpec) (*domain.SpecReview, error)
+
+	// SummarizePack synthesizes a learner's reviews and errors on a pack into strengths and gaps
+	SummarizePack(ctx context.Context, activity *session.PackActivity) (*domain.PackSummary, error)
+
+	// Preview builds the prompt an intervention would send without calling the LLM
+	Preview(ctx context.Context, req InterventionRequest) (*PromptPreview, error)
 }
 
 // Ensure Service implements PairingService
@@ -1,10 +1,13 @@
 package pairing
 
 import (
+	"context"
 	"strings"
 	"testing"
 
 	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/google/uuid"
 )
 
 func TestSystemPromptForLanguage_NamesLanguage(t *testing.T) {
@@ -80,6 +83,16 @@ func TestExerciseLanguage_Helper(t *testing.T) {
 	}
 }
 
+func TestContextLanguage(t *testing.T) {
+	if got := contextLanguage(InterventionContext{Language: "rust"}); got != "rust" {
+		t.Errorf("detected language → got %q, want rust", got)
+	}
+	ctx := InterventionContext{Exercise: &domain.Exercise{Language: "python"}, Language: "go"}
+	if got := contextLanguage(ctx); got != "python" {
+		t.Errorf("the exercise's language should win, got %q", got)
+	}
+}
+
 func TestLanguageIdiomExample_PerLanguage(t *testing.T) {
 	// Each language gets a distinct idiom example so the L3 prompt is
 	// not generic. Verify the per-language tail differs.
@@ -92,3 +105,36 @@ func TestLanguageIdiomExample_PerLanguage(t *testing.T) {
 		seen[ex] = l
 	}
 }
+
+func TestLocaleDirective(t *testing.T) {
+	p := NewPrompter()
+	if out := p.LocaleDirective("de"); !strings.Contains(out, "Write all of your prose in German") {
+		t.Errorf("German directive missing, got: %s", out)
+	}
+	for _, tag := range []string{"", "en", "en-GB", "xx"} {
+		if out := p.LocaleDirective(tag); out != "" {
+			t.Errorf("LocaleDirective(%q) = %q; want none", tag, out)
+		}
+	}
+}
+
+func TestService_Intervene_Locale(t *testing.T) {
+	mock := &mockProvider{name: "test", response: &llm.Response{Content: "Denk an leere Eingaben."}}
+	service := createTestService(mock)
+	service.SetLocale("pt-BR")
+
+	_, err := service.Intervene(context.Background(), InterventionRequest{
+		SessionID: uuid.New(),
+		Intent:    domain.IntentHint,
+		Policy:    domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet},
+	})
+	if err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+	if !strings.Contains(mock.lastReq.System, "Brazilian Portuguese") {
+		t.Errorf("system prompt should ask for Brazilian Portuguese, got: %s", mock.lastReq.System)
+	}
+	if mock.lastReq.SystemBlocks[0].Text != mock.lastReq.System {
+		t.Error("cached system block should carry the locale directive too")
+	}
+}
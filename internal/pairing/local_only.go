@@ -0,0 +1,164 @@
+package pairing
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+// localProvider is the registry name of the provider that runs on this
+// machine. Prompts sent to it never leave the host.
+const localProvider = "ollama"
+
+// ErrNoLocalProvider is returned when a local-only rule matches but no
+// local LLM provider is registered
+var ErrNoLocalProvider = errors.New("session is restricted to local LLM providers but none is configured (enable llm.providers.ollama)")
+
+// ErrProviderNotLocal is returned when a request names a cloud provider
+// for a session local-only rules restrict
+var ErrProviderNotLocal = errors.New("session is restricted to local LLM providers")
+
+// LocalOnlyRules restrict matching sessions to the local provider,
+// whatever the default provider is. Keeps work code off cloud LLMs when
+// the same daemon is used for personal practice.
+type LocalOnlyRules struct {
+	Packs []string // exercise pack IDs, e.g. "acme-internal"
+	Paths []string // globs over spec, document and code file paths; "**" spans directories
+}
+
+// Empty reports whether no rule is configured
+func (r LocalOnlyRules) Empty() bool {
+	return len(r.Packs) == 0 && len(r.Paths) == 0
+}
+
+// Matches reports whether a session with the given pack and file paths
+// falls under the rules
+func (r LocalOnlyRules) Matches(packID string, paths []string) bool {
+	for _, p := range r.Packs {
+		if packID != "" && p == packID {
+			return true
+		}
+	}
+	for _, pattern := range r.Paths {
+		for _, name := range paths {
+			if matchGlob(pattern, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash-separated path against a glob where "**"
+// matches any number of path segments and other segments use path.Match
+// syntax. Patterns without a slash match the base name at any depth.
+func matchGlob(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// SetLocalOnlyRules configures which sessions must stay on the local
+// provider
+func (s *Service) SetLocalOnlyRules(r LocalOnlyRules) {
+	s.localOnly = r
+}
+
+// localOnlyFor reports whether an intervention context is restricted to
+// the local provider
+func (s *Service) localOnlyFor(ctx InterventionContext) bool {
+	if s.localOnly.Empty() {
+		return false
+	}
+	var packID string
+	if ctx.Exercise != nil {
+		packID = ctx.Exercise.PackID
+	}
+	paths := make([]string, 0, len(ctx.Code)+1)
+	if ctx.Spec != nil && ctx.Spec.FilePath != "" {
+		paths = append(paths, ctx.Spec.FilePath)
+	}
+	for name := range ctx.Code {
+		paths = append(paths, name)
+	}
+	return s.localOnly.Matches(packID, paths)
+}
+
+// localOnlyForAuthoring reports whether an authoring context is
+// restricted to the local provider
+func (s *Service) localOnlyForAuthoring(ctx AuthoringContext) bool {
+	if s.localOnly.Empty() {
+		return false
+	}
+	paths := make([]string, 0, len(ctx.Documents)+1)
+	if ctx.Spec != nil && ctx.Spec.FilePath != "" {
+		paths = append(paths, ctx.Spec.FilePath)
+	}
+	for _, doc := range ctx.Documents {
+		paths = append(paths, doc.Path)
+	}
+	return s.localOnly.Matches("", paths)
+}
+
+// provider returns the default provider, or the local one when localOnly
+// is set and the default would send the prompt off the machine
+func (s *Service) provider(localOnly bool) (llm.Provider, error) {
+	if !localOnly {
+		return s.llmRegistry.Default()
+	}
+	if p, err := s.llmRegistry.Default(); err == nil && isLocalProvider(p) {
+		return p, nil
+	}
+	p, err := s.llmRegistry.Get(localProvider)
+	if err != nil {
+		return nil, ErrNoLocalProvider
+	}
+	return p, nil
+}
+
+// providerFor returns the provider a request names, or the one provider
+// picks when it names none. Local-only rules hold either way.
+func (s *Service) providerFor(req InterventionRequest) (llm.Provider, error) {
+	localOnly := s.localOnlyFor(req.Context)
+	if req.Provider == "" {
+		return s.provider(localOnly)
+	}
+	p, err := s.llmRegistry.Get(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if localOnly && !isLocalProvider(p) {
+		return nil, fmt.Errorf("%w: %s runs off this machine", ErrProviderNotLocal, req.Provider)
+	}
+	return p, nil
+}
+
+func isLocalProvider(p llm.Provider) bool {
+	return p.Name() == localProvider
+}
@@ -0,0 +1,111 @@
+package pairing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.secret.go", "internal/billing/keys.secret.go", true},
+		{"*.secret.go", "main.go", false},
+		{"/home/me/work/**", "/home/me/work/api/spec.yaml", true},
+		{"/home/me/work/**", "/home/me/play/spec.yaml", false},
+		{"internal/billing/**", "internal/billing/invoice.go", true},
+		{"internal/billing/**", "internal/billing", true},
+		{"**/billing/*.go", "svc/internal/billing/invoice.go", true},
+		{"**/billing/*.go", "svc/internal/billing/sub/invoice.go", false},
+		{"cmd/*/main.go", "cmd/temper/main.go", true},
+	}
+	for _, tc := range cases {
+		if got := matchGlob(tc.pattern, tc.name); got != tc.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLocalOnlyRules_Matches(t *testing.T) {
+	rules := LocalOnlyRules{Packs: []string{"acme-internal"}, Paths: []string{"*.secret.go"}}
+
+	if !rules.Matches("acme-internal", nil) {
+		t.Error("pack rule did not match")
+	}
+	if !rules.Matches("go-v1", []string{"main.go", "keys.secret.go"}) {
+		t.Error("path rule did not match")
+	}
+	if rules.Matches("go-v1", []string{"main.go"}) {
+		t.Error("unrelated session matched")
+	}
+	if (LocalOnlyRules{}).Matches("acme-internal", []string{"x.go"}) {
+		t.Error("empty rules matched")
+	}
+}
+
+func localOnlyRegistry(providers ...*mockProvider) *llm.Registry {
+	registry := llm.NewRegistry()
+	for _, p := range providers {
+		registry.Register(p.name, p)
+	}
+	registry.SetDefault(providers[0].name)
+	return registry
+}
+
+func localOnlyRequest(packID string) InterventionRequest {
+	return InterventionRequest{
+		Intent: domain.IntentHint,
+		Context: InterventionContext{
+			Exercise: &domain.Exercise{ID: packID + "/basics/hello", PackID: packID},
+			Code:     map[string]string{"main.go": "package main"},
+		},
+		Policy: domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet},
+	}
+}
+
+func TestService_Intervene_LocalOnlyRoutesToOllama(t *testing.T) {
+	cloud := &mockProvider{name: "claude", response: &llm.Response{Content: "cloud"}}
+	local := &mockProvider{name: "ollama", response: &llm.Response{Content: "local"}}
+	service := NewService(localOnlyRegistry(cloud, local), "claude")
+	service.SetLevelModels(map[domain.InterventionLevel]string{domain.L1CategoryHint: "claude-haiku"})
+	service.SetLocalOnlyRules(LocalOnlyRules{Packs: []string{"acme-internal"}})
+
+	if _, err := service.Intervene(context.Background(), localOnlyRequest("acme-internal")); err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+	if cloud.lastReq != nil {
+		t.Error("local-only session reached the cloud provider")
+	}
+	if local.lastReq == nil {
+		t.Fatal("local-only session did not reach the local provider")
+	}
+	if local.lastReq.Model != "" {
+		t.Errorf("Model = %q, want provider default", local.lastReq.Model)
+	}
+
+	if _, err := service.Intervene(context.Background(), localOnlyRequest("go-v1")); err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+	if cloud.lastReq == nil {
+		t.Error("unrestricted session did not use the default provider")
+	}
+}
+
+func TestService_Intervene_LocalOnlyWithoutLocalProvider(t *testing.T) {
+	cloud := &mockProvider{name: "claude", response: &llm.Response{Content: "cloud"}}
+	service := NewService(localOnlyRegistry(cloud), "claude")
+	service.SetLocalOnlyRules(LocalOnlyRules{Packs: []string{"acme-internal"}})
+
+	_, err := service.Intervene(context.Background(), localOnlyRequest("acme-internal"))
+	if !errors.Is(err, ErrNoLocalProvider) {
+		t.Fatalf("Intervene() error = %v, want ErrNoLocalProvider", err)
+	}
+	if cloud.lastReq != nil {
+		t.Error("local-only session reached the cloud provider")
+	}
+}
@@ -0,0 +1,214 @@
+package pairing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/correlation"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// ErrUnparseablePackSummary is returned when the LLM's pack summary is not
+// the JSON shape the prompt asked for
+var ErrUnparseablePackSummary = errors.New("pack summary response is not valid JSON")
+
+// ErrNoPackActivity is returned when the learner has no sessions on a pack
+var ErrNoPackActivity = errors.New("no sessions on this pack")
+
+const (
+	maxSummaryReviewChars = 1200 // per review; long reviews repeat themselves
+	maxSummaryErrors      = 5    // most frequent error signatures per exercise
+)
+
+// SummarizePack asks the LLM to synthesize the reviews and errors of a
+// learner's sessions on one pack into strengths and gaps. Attempted and
+// completed counts come from the activity, not the model.
+func (s *Service) SummarizePack(ctx context.Context, activity *session.PackActivity) (*domain.PackSummary, error) {
+	if activity == nil || len(activity.Exercises) == 0 {
+		return nil, ErrNoPackActivity
+	}
+
+	provider, err := s.provider(s.localOnly.Matches(activity.Pack, nil))
+	if err != nil {
+		return nil, fmt.Errorf("get LLM provider: %w", err)
+	}
+	prompt, _ := s.redactPrompt(provider, s.prompter.BuildPackSummaryPrompt(activity))
+
+	known := make(map[string]bool, len(activity.Exercises))
+	for _, ex := range activity.Exercises {
+		known[ex.ExerciseID] = true
+	}
+
+	system := s.localize(s.prompter.PackSummarySystemPrompt())
+	summary := &domain.PackSummary{
+		Pack:      activity.Pack,
+		Attempted: len(activity.Exercises),
+		Completed: activity.Completed(),
+	}
+	err = s.generateStructured(ctx, provider, &llm.Request{
+		Messages: []llm.Message{
+			{Role: llm.RoleUser, Content: prompt},
+		},
+		System: system,
+		SystemBlocks: []llm.SystemContentBlock{
+			{Text: system, CacheControl: true},
+		},
+		CorrelationID: correlation.FromContext(ctx),
+		MaxTokens:     2048,
+		Temperature:   0.2,
+	}, `Respond again with ONLY the JSON object {"summary": ..., "strengths": [...], "gaps": [...]} described above. No markdown fences or other text.`,
+		func(content string) (err error) {
+			summary.Summary, summary.Strengths, summary.Gaps, err = ParsePackSummary(content, known)
+			return err
+		})
+	if err != nil {
+		if errors.Is(err, ErrUnparseablePackSummary) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("generate pack summary: %w", err)
+	}
+
+	summary.GeneratedAt = time.Now()
+	return summary, nil
+}
+
+// PackSummarySystemPrompt returns the system prompt for pack summaries
+func (p *Prompter) PackSummarySystemPrompt() string {
+	return `You summarize a learner's work across an exercise pack into strengths and gaps, for the learner's weekly report and their instructor.
+
+You see, per exercise, whether it was completed, how many runs passed, the errors and failing tests that recurred, the code reviews the learner received, and, for completed exercises, how the learner's solution compares with the author's and which idioms they haven't tried. Individual reviews are too granular; look for what recurs across exercises.
+
+- A strength is a skill the learner shows repeatedly: exercises completed with few failing runs, or reviews that praise the same thing.
+- A gap is a skill the learner keeps struggling with: the same error or review criticism in several exercises, or exercises abandoned after many failing runs.
+- Idioms the learner never reaches for across several exercises are worth naming as a gap; one exercise solved differently from the reference is not.
+
+Name the topic in a few words (e.g. "error handling", "slices", "table-driven tests"), say what the evidence shows in one or two sentences, and cite the exercise IDs it comes from. Report only what the evidence supports; short lists are fine. Never write code or solve an exercise.
+
+Output ONLY valid JSON, no markdown fences or explanation.`
+}
+
+// BuildPackSummaryPrompt renders the learner's activity on a pack
+func (p *Prompter) BuildPackSummaryPrompt(activity *session.PackActivity) string {
+	var sb strings.Builder
+	f := newFence()
+	sb.WriteString(f.securityPreamble())
+	fmt.Fprintf(&sb, "## Activity on pack %s\n\n", activity.Pack)
+
+	for _, ex := range activity.Exercises {
+		var body strings.Builder
+		status := "not completed"
+		if ex.Completed {
+			status = "completed"
+		}
+		fmt.Fprintf(&body, "Exercise: %s (%s)\n", ex.ExerciseID, status)
+		fmt.Fprintf(&body, "Sessions: %d, runs: %d (%d passed), hints: %d\n", ex.Sessions, ex.Runs, ex.PassedRuns, ex.Hints)
+		if len(ex.Errors) > 0 {
+			body.WriteString("Recurring errors:\n")
+			for _, sig := range mostFrequent(ex.Errors, maxSummaryErrors) {
+				fmt.Fprintf(&body, "- %s (%d runs)\n", sig, ex.Errors[sig])
+			}
+		}
+		if len(ex.FailedTests) > 0 {
+			body.WriteString("Failing tests:\n")
+			for _, name := range mostFrequent(ex.FailedTests, maxSummaryErrors) {
+				fmt.Fprintf(&body, "- %s (%d runs)\n", name, ex.FailedTests[name])
+			}
+		}
+		if a := ex.Approach; a != nil {
+			if a.Reference != nil {
+				fmt.Fprintf(&body, "Similarity to the reference solution: %.0f%%\n", a.Reference.Similarity*100)
+			}
+			if untried := a.Untried(); len(untried) > 0 {
+				fmt.Fprintf(&body, "Idioms the reference or alternatives use that the learner didn't: %s\n", strings.Join(untried, ", "))
+			}
+		}
+		for i, review := range ex.Reviews {
+			if runes := []rune(review); len(runes) > maxSummaryReviewChars {
+				review = string(runes[:maxSummaryReviewChars]) + "…"
+			}
+			fmt.Fprintf(&body, "Review %d:\n%s\n", i+1, review)
+		}
+		sb.WriteString(f.wrap("EXERCISE", body.String()))
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString("## Task\n\n")
+	sb.WriteString(`Summarize the learner's strengths and gaps across the pack as a JSON object of this shape:
+{
+  "summary": "one or two sentences on how the pack went",
+  "strengths": [
+    {"topic": "short topic name", "message": "what the evidence shows", "exercises": ["pack/category/slug"]}
+  ],
+  "gaps": [
+    {"topic": "short topic name", "message": "what the evidence shows", "exercises": ["pack/category/slug"]}
+  ]
+}`)
+	return sb.String()
+}
+
+// ParsePackSummary extracts the summary, strengths and gaps from the LLM's
+// JSON response. Findings without a message are dropped, and so are
+// exercise IDs not in known, which the model made up.
+func ParsePackSummary(content string, known map[string]bool) (string, []domain.PackFinding, []domain.PackFinding, error) {
+	var raw struct {
+		Summary   string               `json:"summary"`
+		Strengths []domain.PackFinding `json:"strengths"`
+		Gaps      []domain.PackFinding `json:"gaps"`
+	}
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		start := strings.Index(content, "{")
+		end := strings.LastIndex(content, "}")
+		if start < 0 || end <= start {
+			return "", nil, nil, ErrUnparseablePackSummary
+		}
+		if err := json.Unmarshal([]byte(content[start:end+1]), &raw); err != nil {
+			return "", nil, nil, fmt.Errorf("%w: %v", ErrUnparseablePackSummary, err)
+		}
+	}
+
+	clean := func(findings []domain.PackFinding) []domain.PackFinding {
+		out := make([]domain.PackFinding, 0, len(findings))
+		for _, f := range findings {
+			f.Message = strings.TrimSpace(f.Message)
+			if f.Message == "" {
+				continue
+			}
+			f.Topic = strings.TrimSpace(f.Topic)
+			exercises := f.Exercises[:0:0]
+			for _, id := range f.Exercises {
+				if known[id] {
+					exercises = append(exercises, id)
+				}
+			}
+			f.Exercises = exercises
+			out = append(out, f)
+		}
+		return out
+	}
+	return strings.TrimSpace(raw.Summary), clean(raw.Strengths), clean(raw.Gaps), nil
+}
+
+// mostFrequent returns up to n keys by descending count, ties by name
+func mostFrequent(counts map[string]int, n int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
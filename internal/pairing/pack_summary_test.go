@@ -0,0 +1,85 @@
+package pairing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func TestParsePackSummary(t *testing.T) {
+	content := "```json\n" + `{
+  "summary": "Solid on basics.",
+  "strengths": [
+    {"topic": "slices", "message": "Completed every slice exercise first try.", "exercises": ["go-v1/basics/slices", "go-v1/basics/invented"]}
+  ],
+  "gaps": [
+    {"topic": "errors", "message": "  "},
+    {"topic": " nil maps ", "message": "Wrote to nil maps in two exercises.", "exercises": ["go-v1/basics/maps"]}
+  ]
+}` + "\n```"
+	known := map[string]bool{"go-v1/basics/slices": true, "go-v1/basics/maps": true}
+
+	summary, strengths, gaps, err := ParsePackSummary(content, known)
+	if err != nil {
+		t.Fatalf("ParsePackSummary() error = %v", err)
+	}
+	if summary != "Solid on basics." {
+		t.Errorf("summary = %q", summary)
+	}
+	if len(strengths) != 1 || len(strengths[0].Exercises) != 1 {
+		t.Errorf("strengths = %+v, want the invented exercise dropped", strengths)
+	}
+	if len(gaps) != 1 || gaps[0].Topic != "nil maps" {
+		t.Errorf("gaps = %+v, want the empty finding dropped and the topic trimmed", gaps)
+	}
+
+	if _, _, _, err := ParsePackSummary("Doing great!", known); !errors.Is(err, ErrUnparseablePackSummary) {
+		t.Errorf("error = %v, want ErrUnparseablePackSummary", err)
+	}
+}
+
+func TestService_SummarizePack(t *testing.T) {
+	mock := &mockProvider{
+		name: "test",
+		response: &llm.Response{
+			Content: `{"summary": "ok", "strengths": [], "gaps": [{"topic": "maps", "message": "Nil map writes.", "exercises": ["go-v1/basics/maps"]}]}`,
+		},
+	}
+	service := createTestService(mock)
+
+	activity := &session.PackActivity{
+		Pack: "go-v1",
+		Exercises: []session.ExerciseActivity{
+			{ExerciseID: "go-v1/basics/hello", Sessions: 1, Completed: true, Runs: 1, PassedRuns: 1},
+			{
+				ExerciseID: "go-v1/basics/maps", Sessions: 2, Runs: 4,
+				Errors:      map[string]int{"panic: assignment to entry in nil map": 3},
+				FailedTests: map[string]int{"TestCount": 3},
+				Reviews:     []string{"Initialize the map before writing to it."},
+			},
+		},
+	}
+
+	summary, err := service.SummarizePack(context.Background(), activity)
+	if err != nil {
+		t.Fatalf("SummarizePack() error = %v", err)
+	}
+	if summary.Attempted != 2 || summary.Completed != 1 || len(summary.Gaps) != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+
+	prompt := sentPrompt(mock.lastReq)
+	for _, want := range []string{"nil map (3 runs)", "TestCount (3 runs)", "Initialize the map", "go-v1/basics/hello (completed)", "UNTRUSTED-EXERCISE"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+
+	if _, err := service.SummarizePack(context.Background(), &session.PackActivity{Pack: "go-v1"}); !errors.Is(err, ErrNoPackActivity) {
+		t.Errorf("empty activity error = %v, want ErrNoPackActivity", err)
+	}
+}
@@ -0,0 +1,154 @@
+package pairing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/felixgeelhaar/temper/internal/analysis"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+// maxInterventionTokens caps the length of a generated intervention
+const maxInterventionTokens = 1024
+
+// composedPrompt is everything an intervention sends to the LLM, decided
+// before a provider is picked
+type composedPrompt struct {
+	level            domain.InterventionLevel
+	testFirst        *domain.Feature
+	contract         domain.LevelRationale
+	interventionType domain.InterventionType
+	prompt           string
+	system           string
+	solution         map[string]string // reference solution the prompt carries, if any
+}
+
+// composeFor says where a composed prompt goes, which decides how much
+// of the reference solution it may carry
+type composeFor int
+
+const (
+	// forLLM prompts are answered in full before the learner sees the
+	// answer, so quoted solution code can be withheld
+	forLLM composeFor = iota
+	// forStream prompts are answered straight to the learner
+	forStream
+	// forPreview prompts are shown to the learner themselves
+	forPreview
+)
+
+// compose picks the level (explicit for escalations, otherwise the
+// selector's choice, then the policy caps, test-first and the hint budget)
+// and type, and builds the prompts for them. It fails with
+// ErrHintBudgetExhausted when the budget pays for no level at all.
+func (s *Service) compose(req InterventionRequest, target composeFor) (composedPrompt, error) {
+	if err := checkBudget(req); err != nil {
+		return composedPrompt{}, err
+	}
+	level, testFirst, contract := s.decideLevel(req)
+	interventionType := s.selector.SelectType(req.Intent, level)
+	solution := referenceSolution(req, level, target == forStream)
+
+	prompt := s.prompter.BuildPrompt(PromptRequest{
+		Intent:         req.Intent,
+		Level:          level,
+		Type:           interventionType,
+		Exercise:       req.Context.Exercise,
+		Code:           req.Context.Code,
+		Output:         req.Context.RunOutput,
+		Profile:        req.Context.Profile,
+		Findings:       analysis.Analyze(req.Context.Code),
+		Symbol:         req.Context.Symbol,
+		Spec:           req.Context.Spec,
+		FocusCriterion: req.Context.FocusCriterion,
+		TestFirst:      testFirst,
+		Solution:       solution,
+		// A preview below L5 shows where the solution goes, not its code
+		SolutionWithheld: target == forPreview && level < domain.L5FullSolution,
+	})
+
+	return composedPrompt{
+		level:            level,
+		testFirst:        testFirst,
+		contract:         contract,
+		interventionType: interventionType,
+		prompt:           prompt,
+		system:           s.localize(s.prompter.SystemPromptForLanguage(level, contextLanguage(req.Context))),
+		solution:         solution,
+	}, nil
+}
+
+// PromptPreview is the request an intervention would send, built without
+// calling the LLM
+type PromptPreview struct {
+	Intent   domain.Intent            `json:"intent"`
+	Level    domain.InterventionLevel `json:"level"`
+	Type     domain.InterventionType  `json:"type"`
+	Provider string                   `json:"provider,omitempty"` // empty when no provider is available
+	Model    string                   `json:"model,omitempty"`
+	// Offline is set when no provider is available and the intervention
+	// would come from the exercise's YAML hints instead
+	Offline    bool                  `json:"offline,omitempty"`
+	System     string                `json:"system"`
+	Prompt     string                `json:"prompt"` // after redaction, as sent
+	Redactions []domain.Redaction    `json:"redactions,omitempty"`
+	Tokens     TokenEstimate         `json:"estimated_tokens"`
+	Contract   domain.LevelRationale `json:"rationale"`
+}
+
+// TokenEstimate approximates request size at ~4 characters per token;
+// providers tokenize differently, so treat it as a guide
+type TokenEstimate struct {
+	System    int `json:"system"`
+	Prompt    int `json:"prompt"`
+	Input     int `json:"input"`
+	MaxOutput int `json:"max_output"`
+}
+
+// Preview builds the prompt, provider and model an intervention would
+// use, without calling the LLM
+func (s *Service) Preview(ctx context.Context, req InterventionRequest) (*PromptPreview, error) {
+	c, err := s.compose(req, forPreview)
+	if err != nil {
+		return nil, err
+	}
+	preview := &PromptPreview{
+		Intent:   req.Intent,
+		Level:    c.level,
+		Type:     c.interventionType,
+		System:   c.system,
+		Prompt:   c.prompt,
+		Contract: c.contract,
+	}
+
+	provider, err := s.providerFor(req)
+	switch {
+	case errors.Is(err, ErrNoLocalProvider), err != nil && req.Provider != "":
+		return nil, err
+	case err != nil:
+		preview.Offline = true
+	default:
+		preview.Provider = provider.Name()
+		preview.Model = s.modelFor(req, provider, c.level)
+		if preview.Model == "" {
+			preview.Model = llm.DefaultModel(provider)
+		}
+		preview.Prompt, preview.Redactions = s.redactPrompt(provider, c.prompt)
+	}
+	preview.Contract.Details = buildRationale(c.level, req, preview.Model, testFirstNote(c.testFirst))
+
+	preview.Tokens = TokenEstimate{
+		System:    estimateTokens(preview.System),
+		Prompt:    estimateTokens(preview.Prompt),
+		MaxOutput: maxInterventionTokens,
+	}
+	preview.Tokens.Input = preview.Tokens.System + preview.Tokens.Prompt
+	return preview, nil
+}
+
+// estimateTokens uses the same rough 4-characters-per-token rule as the
+// document budget
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
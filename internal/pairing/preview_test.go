@@ -0,0 +1,125 @@
+package pairing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/google/uuid"
+)
+
+func TestService_Preview(t *testing.T) {
+	mock := &mockProvider{name: "test"}
+	service := createTestService(mock)
+
+	req := InterventionRequest{
+		SessionID: uuid.New(),
+		Intent:    domain.IntentHint,
+		Context: InterventionContext{
+			Code: map[string]string{"main.go": "package main\n\nfunc Sum(a, b int) int { return 0 }\n"},
+		},
+		Policy: domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet},
+	}
+
+	preview, err := service.Preview(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if mock.lastReq != nil {
+		t.Error("Preview() must not call the LLM")
+	}
+	if preview.Provider != "test" || preview.Offline {
+		t.Errorf("Provider = %q, Offline = %v", preview.Provider, preview.Offline)
+	}
+	if !strings.Contains(preview.Prompt, "func Sum") || preview.System == "" {
+		t.Errorf("prompt should carry the code and a system prompt: %+v", preview)
+	}
+	if preview.Tokens.Input != preview.Tokens.System+preview.Tokens.Prompt || preview.Tokens.Prompt == 0 {
+		t.Errorf("unexpected token estimate: %+v", preview.Tokens)
+	}
+	if preview.Tokens.MaxOutput != maxInterventionTokens {
+		t.Errorf("MaxOutput = %d, want %d", preview.Tokens.MaxOutput, maxInterventionTokens)
+	}
+}
+
+func TestService_Preview_NoProvider(t *testing.T) {
+	service := NewService(llm.NewRegistry(), "nonexistent")
+
+	preview, err := service.Preview(context.Background(), InterventionRequest{
+		Intent: domain.IntentHint,
+		Policy: domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet},
+	})
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if !preview.Offline || preview.Provider != "" || preview.Model != "" {
+		t.Errorf("expected an offline preview, got %+v", preview)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	for text, want := range map[string]int{"": 0, "a": 1, "abcd": 1, "abcde": 2} {
+		if got := estimateTokens(text); got != want {
+			t.Errorf("estimateTokens(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestService_Intervene_GroundsPromptInFindings(t *testing.T) {
+	mock := &mockProvider{name: "test", response: &llm.Response{Content: "Look at line 4."}}
+	service := createTestService(mock)
+
+	_, err := service.Intervene(context.Background(), InterventionRequest{
+		SessionID: uuid.New(),
+		Intent:    domain.IntentHint,
+		Context: InterventionContext{
+			Code: map[string]string{"main.go": "package main\n\nfunc Sum(xs []int) int {\n\tunused := 0\n\treturn 0\n}\n"},
+		},
+		Policy: domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet},
+	})
+	if err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+	prompt := mock.lastReq.Messages[0].Content
+	if !strings.Contains(prompt, "main.go:4:2 [unused]") || !strings.Contains(prompt, "declared and not used: unused") {
+		t.Errorf("prompt should list the unused variable finding:\n%s", prompt)
+	}
+}
+
+func TestService_Preview_WithholdsSolution(t *testing.T) {
+	service := createTestService(&mockProvider{name: "test"})
+	ex := &domain.Exercise{ID: "go-v1/basics/hello", Solution: map[string]string{"main.go": referenceMain}}
+	req := InterventionRequest{
+		SessionID:     uuid.New(),
+		Intent:        domain.IntentReview,
+		Context:       InterventionContext{Exercise: ex, Code: map[string]string{"main.go": "package main\n"}},
+		Policy:        domain.LearningPolicy{MaxLevel: domain.L5FullSolution},
+		ExplicitLevel: domain.L2LocationConcept,
+	}
+
+	preview, err := service.Preview(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if strings.Contains(preview.Prompt, "REFERENCE_SOLUTION") || strings.Contains(preview.Prompt, "Sprintf") {
+		t.Errorf("a review preview below L5 shows the reference solution:\n%s", preview.Prompt)
+	}
+	if !strings.Contains(preview.Prompt, solutionWithheld) {
+		t.Error("the preview should mark where the solution goes")
+	}
+	if preview.Tokens.Prompt != estimateTokens(preview.Prompt) {
+		t.Errorf("Tokens.Prompt = %d, want the estimate of the withheld prompt", preview.Tokens.Prompt)
+	}
+
+	// At L5 the learner gets the solution anyway
+	req.ExplicitLevel = domain.L5FullSolution
+	preview, err = service.Preview(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if !strings.Contains(preview.Prompt, "Sprintf") {
+		t.Error("an L5 preview should carry the reference solution")
+	}
+}
@@ -2,9 +2,12 @@ package pairing
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/felixgeelhaar/temper/internal/analysis"
 	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/locale"
 )
 
 // Prompter builds prompts for the LLM
@@ -25,9 +28,28 @@ type PromptRequest struct {
 	Output   *domain.RunOutput
 	Profile  *domain.LearningProfile
 
+	// Findings are static analysis facts about Code that the hint can
+	// cite instead of guessing
+	Findings []analysis.Finding
+
+	// Symbol scopes an explanation to one declaration and its usages
+	Symbol *analysis.Symbol
+
 	// Spec context for feature guidance sessions
 	Spec           *domain.ProductSpec
 	FocusCriterion *domain.AcceptanceCriterion
+
+	// TestFirst is the feature whose implementation hints are held back
+	// until a failing test references it
+	TestFirst *domain.Feature
+
+	// Solution is the exercise's reference solution, set only for L5 and
+	// for reviews that grade against it
+	Solution map[string]string
+
+	// SolutionWithheld leaves the solution's code out of the prompt,
+	// keeping only its file names, for prompts the learner gets to see
+	SolutionWithheld bool
 }
 
 // SystemPrompt returns the system prompt for a given level. Language is
@@ -111,6 +133,18 @@ func languagePhrase(language string) string {
 	}
 }
 
+// LocaleDirective returns the system prompt addendum that asks for output
+// in the learner's language. English and unsupported locales need none.
+func (p *Prompter) LocaleDirective(tag string) string {
+	name := locale.Name(tag)
+	if name == "" || name == locale.Name(locale.Default) {
+		return ""
+	}
+	return "\n\nLANGUAGE: The learner reads " + name + ". Write all of your prose in " + name + ". " +
+		"Keep code, identifiers, keywords and quoted compiler or test output exactly as they are, " +
+		"and keep any required output format (such as JSON keys) unchanged."
+}
+
 // languageCommentMarker returns the canonical single-line comment
 // marker so L3 placeholder examples render correctly per language.
 func languageCommentMarker(language string) string {
@@ -208,9 +242,23 @@ func (p *Prompter) BuildPrompt(req PromptRequest) string {
 				}
 			}
 		}
+		if req.Output.Debug != nil {
+			sb.WriteString(p.buildDebugSnapshot(f, req.Output.Debug))
+		}
+		if req.Output.FuzzCrash != nil {
+			sb.WriteString(p.buildFuzzCrash(f, req.Output.FuzzCrash))
+		}
 		sb.WriteString("\n")
 	}
 
+	if len(req.Findings) > 0 {
+		sb.WriteString(p.buildFindings(f, req.Findings))
+	}
+
+	if req.Symbol != nil {
+		sb.WriteString(p.buildSymbolContext(f, req.Symbol))
+	}
+
 	// Exercise hints (author-controlled — fence)
 	if req.Exercise != nil {
 		hints := req.Exercise.GetHintsForLevel(req.Level)
@@ -225,6 +273,16 @@ func (p *Prompter) BuildPrompt(req PromptRequest) string {
 		}
 	}
 
+	// Reference solution (author-controlled — fence)
+	if len(req.Solution) > 0 {
+		sb.WriteString(p.buildReferenceSolution(f, req.Solution, req.Level, req.SolutionWithheld))
+	}
+
+	// Debugging exercises (author-controlled — fence)
+	if req.Exercise != nil && req.Exercise.IsDebugging() {
+		sb.WriteString(p.buildDebuggingContext(f, req.Exercise.Debugging, req.Level))
+	}
+
 	// Spec context (mixed author/user-controlled — fence inside builder)
 	if req.Spec != nil {
 		sb.WriteString(p.buildSpecContext(f, req.Spec, req.FocusCriterion))
@@ -234,10 +292,149 @@ func (p *Prompter) BuildPrompt(req PromptRequest) string {
 	sb.WriteString("## Your Task\n\n")
 	sb.WriteString(p.taskInstruction(req.Intent, req.Level, req.Type))
 
+	if req.Exercise != nil && req.Exercise.IsDebugging() {
+		sb.WriteString(p.debuggingTaskAddendum(req.Level))
+	}
+
 	if req.Spec != nil {
 		sb.WriteString(p.specTaskAddendum(req.Spec, req.FocusCriterion))
 	}
 
+	if req.TestFirst != nil {
+		sb.WriteString(p.testFirstAddendum(req.TestFirst, req.FocusCriterion))
+	}
+
+	if req.Symbol != nil {
+		sb.WriteString(p.symbolAddendum(req.Symbol))
+	}
+
+	return sb.String()
+}
+
+// buildFindings lists static analysis findings one per line. Messages
+// quote the learner's identifiers, so they are fenced.
+func (p *Prompter) buildFindings(f *fence, findings []analysis.Finding) string {
+	var sb strings.Builder
+	sb.WriteString("## Static Analysis\n\n")
+	sb.WriteString("Facts from parsing and type-checking the current code. When one bears on the learner's question, " +
+		"refer to it by file and line rather than speculating about the code.\n\n")
+	for _, finding := range findings {
+		sb.WriteString(fmt.Sprintf("- %s:%d:%d [%s] ", f.sanitize(finding.File), finding.Line, finding.Column, finding.Check))
+		sb.WriteString(f.wrap("ANALYSIS_FINDING", finding.Message))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// buildReferenceSolution gives the model the author's solution. At L5 it
+// may draw on it; below, it's only a yardstick for the review, and any
+// code quoted from it is withheld before the learner sees the answer.
+// withheld replaces each file's code with a marker.
+func (p *Prompter) buildReferenceSolution(f *fence, solution map[string]string, level domain.InterventionLevel, withheld bool) string {
+	var sb strings.Builder
+	sb.WriteString("## Reference Solution\n\n")
+	if level >= domain.L5FullSolution {
+		sb.WriteString("The exercise author's solution. You may base the full solution on it.\n\n")
+	} else {
+		sb.WriteString("The exercise author's solution, for judging the learner's code only. " +
+			"Do not quote it or reproduce its code; point out where the learner's approach differs.\n\n")
+	}
+	names := make([]string, 0, len(solution))
+	for name := range solution {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("### %s\n", f.sanitize(name)))
+		if withheld {
+			sb.WriteString(solutionWithheld)
+		} else {
+			sb.WriteString(f.wrap("REFERENCE_SOLUTION", solution[name]))
+		}
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
+
+// maxDebugFrames bounds how much of a captured stack goes into a prompt
+const maxDebugFrames = 8
+
+// buildDebugSnapshot renders the debugger's stop point. Variable values
+// come from the learner's program and are fenced like any other output.
+func (p *Prompter) buildDebugSnapshot(f *fence, snap *domain.DebugSnapshot) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\nDebugger snapshot (stopped on %s):\n", f.sanitize(snap.Reason)))
+	for i, frame := range snap.Frames {
+		if i == maxDebugFrames {
+			sb.WriteString(fmt.Sprintf("  ... %d more frames\n", len(snap.Frames)-maxDebugFrames))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("  %d. %s at %s:%d\n", i, f.sanitize(frame.Function), f.sanitize(frame.File), frame.Line))
+	}
+	if len(snap.Locals) > 0 {
+		var vars strings.Builder
+		for _, v := range snap.Locals {
+			vars.WriteString(fmt.Sprintf("%s = %s\n", v.Name, p.truncate(v.Value, 200)))
+		}
+		sb.WriteString("Locals at the failure point:\n")
+		sb.WriteString(f.wrap("DEBUG_LOCALS", vars.String()))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// buildFuzzCrash describes the input the fuzzer found, so hints can steer
+// the learner toward the property it breaks
+func (p *Prompter) buildFuzzCrash(f *fence, crash *domain.FuzzCrash) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\nThe tests pass, but fuzzing %s found a failing input (saved as %s):\n",
+		f.sanitize(crash.Target), f.sanitize(crash.CorpusFile)))
+	sb.WriteString(f.wrap("FUZZ_INPUT", p.truncate(crash.Input, 500)))
+	sb.WriteString("\n")
+	if crash.Message != "" {
+		sb.WriteString("Failure:\n")
+		sb.WriteString(f.wrap("FUZZ_FAILURE", p.truncate(crash.Message, 300)))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// buildDebuggingContext describes a debugging exercise. The documented
+// root causes are only included from L4 up so lower levels cannot leak
+// them even if the model ignores its instructions.
+func (p *Prompter) buildDebuggingContext(f *fence, dbg *domain.DebuggingSpec, level domain.InterventionLevel) string {
+	var sb strings.Builder
+	sb.WriteString("## Debugging Exercise\n\n")
+	sb.WriteString("The starter code contains intentionally injected bugs. ")
+	sb.WriteString("The learner must find them, explain the root cause, and make the tests pass.\n\n")
+	if dbg.Narrative != "" {
+		sb.WriteString("Failure report given to the learner:\n")
+		sb.WriteString(f.wrap("FAILURE_NARRATIVE", dbg.Narrative))
+		sb.WriteString("\n\n")
+	}
+	if level >= domain.L4PartialSolution {
+		sb.WriteString("Documented root causes:\n")
+		for _, bug := range dbg.Bugs {
+			sb.WriteString(fmt.Sprintf("- %s: ", f.sanitize(bug.File)))
+			sb.WriteString(f.wrap("ROOT_CAUSE", bug.RootCause))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// debuggingTaskAddendum steers the model toward coaching debugging
+// strategy rather than pointing at the defect.
+func (p *Prompter) debuggingTaskAddendum(level domain.InterventionLevel) string {
+	var sb strings.Builder
+	sb.WriteString("\n\n### Debugging Strategy\n")
+	sb.WriteString("Coach the process: reproduce the failure, isolate it (narrow inputs, add assertions or logging, bisect), then fix and verify.\n")
+	sb.WriteString("Ask which step the learner is on and help with that step.\n")
+	if level < domain.L4PartialSolution {
+		sb.WriteString("Do NOT name, locate, or describe the bug itself. The learner must identify the root cause.\n")
+	}
 	return sb.String()
 }
 
@@ -492,6 +689,7 @@ func (p *Prompter) BuildAuthoringPrompt(ctx AuthoringContext) string {
 		}
 	}
 	sb.WriteString("\n")
+	sb.WriteString(p.siblingSpecsSection(ctx.Siblings))
 
 	// Document context
 	sb.WriteString("## Project Documentation\n\n")
@@ -506,6 +704,28 @@ func (p *Prompter) BuildAuthoringPrompt(ctx AuthoringContext) string {
 	return sb.String()
 }
 
+// siblingSpecsSection lists the features of the other spec files in a
+// multi-file authoring session so suggestions stay in their own file
+func (p *Prompter) siblingSpecsSection(siblings []*domain.ProductSpec) string {
+	if len(siblings) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("## Other Specs in This Session\n")
+	sb.WriteString("These files cover other parts of the same epic. Do not suggest their features again; reference them with depends_on (\"file.yaml#feature-id\") instead.\n")
+	for _, sib := range siblings {
+		fmt.Fprintf(&sb, "### %s (%s)\n", sib.FilePath, sib.Name)
+		if len(sib.Features) == 0 {
+			sb.WriteString("(no features yet)\n")
+		}
+		for _, f := range sib.Features {
+			fmt.Fprintf(&sb, "- %s: %s\n", f.ID, f.Title)
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
 // sectionInstructions returns specific instructions for each section type
 func (p *Prompter) sectionInstructions(section string) string {
 	switch section {
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/felixgeelhaar/temper/internal/analysis"
 	"github.com/felixgeelhaar/temper/internal/domain"
 )
 
@@ -885,3 +886,127 @@ func TestPrompter_ParseSuggestions(t *testing.T) {
 		})
 	}
 }
+
+func TestPrompter_BuildPrompt_DebuggingExercise(t *testing.T) {
+	p := NewPrompter()
+	ex := &domain.Exercise{
+		Title: "Broken pagination",
+		Type:  domain.ExerciseTypeDebugging,
+		Debugging: &domain.DebuggingSpec{
+			Narrative: "The last page goes missing",
+			Bugs: []domain.InjectedBug{
+				{ID: "count", File: "main.go", RootCause: "integer division truncates the page count"},
+			},
+		},
+	}
+
+	low := p.BuildPrompt(PromptRequest{Intent: domain.IntentHint, Level: domain.L2LocationConcept, Type: domain.TypeNudge, Exercise: ex})
+	for _, want := range []string{"## Debugging Exercise", "The last page goes missing", "reproduce the failure", "Do NOT name, locate, or describe the bug"} {
+		if !strings.Contains(low, want) {
+			t.Errorf("L2 prompt missing %q", want)
+		}
+	}
+	if strings.Contains(low, "integer division truncates") {
+		t.Error("L2 prompt must not contain the documented root cause")
+	}
+
+	high := p.BuildPrompt(PromptRequest{Intent: domain.IntentStuck, Level: domain.L4PartialSolution, Type: domain.TypeSnippet, Exercise: ex})
+	if !strings.Contains(high, "integer division truncates") {
+		t.Error("L4 prompt should include the documented root cause")
+	}
+	if strings.Contains(high, "Do NOT name, locate, or describe the bug") {
+		t.Error("L4 prompt should not forbid discussing the bug")
+	}
+}
+
+func TestPrompter_BuildPrompt_DebugSnapshot(t *testing.T) {
+	p := NewPrompter()
+	prompt := p.BuildPrompt(PromptRequest{
+		Intent: domain.IntentStuck,
+		Level:  domain.L2LocationConcept,
+		Type:   domain.TypeNudge,
+		Output: &domain.RunOutput{
+			BuildOK:     true,
+			TestsFailed: 1,
+			Debug: &domain.DebugSnapshot{
+				Reason: "panic",
+				Frames: []domain.StackFrame{{Function: "main.Divide", File: "main.go", Line: 4}},
+				Locals: []domain.DebugVariable{{Name: "b", Value: "0"}},
+			},
+		},
+	})
+
+	for _, want := range []string{"Debugger snapshot (stopped on panic)", "main.Divide at main.go:4", "DEBUG_LOCALS", "b = 0"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+}
+
+func TestPrompter_BuildPrompt_FuzzCrash(t *testing.T) {
+	p := NewPrompter()
+	prompt := p.BuildPrompt(PromptRequest{
+		Intent: domain.IntentHint,
+		Level:  domain.L1CategoryHint,
+		Type:   domain.TypeHint,
+		Output: &domain.RunOutput{
+			BuildOK: true,
+			FuzzCrash: &domain.FuzzCrash{
+				Target:     "FuzzParse",
+				CorpusFile: "testdata/fuzz/FuzzParse/8a3c",
+				Input:      "go test fuzz v1\nstring(\"-\")\n",
+				Message:    "parse_test.go:12: Parse(\"-\") panicked: index out of range",
+			},
+		},
+	})
+
+	for _, want := range []string{"fuzzing FuzzParse found a failing input", "testdata/fuzz/FuzzParse/8a3c", "FUZZ_INPUT", "FUZZ_FAILURE", "index out of range"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+}
+
+func TestPrompter_BuildPrompt_Findings(t *testing.T) {
+	p := NewPrompter()
+	prompt := p.BuildPrompt(PromptRequest{
+		Intent: domain.IntentHint,
+		Level:  domain.L1CategoryHint,
+		Type:   domain.TypeHint,
+		Findings: []analysis.Finding{
+			{File: "main.go", Line: 12, Column: 2, Check: analysis.CheckUnused, Message: "declared and not used: x"},
+		},
+	})
+
+	for _, want := range []string{"## Static Analysis", "main.go:12:2 [unused]", "ANALYSIS_FINDING", "declared and not used: x"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+	if empty := p.BuildPrompt(PromptRequest{Intent: domain.IntentHint}); strings.Contains(empty, "Static Analysis") {
+		t.Error("prompt without findings should have no analysis section")
+	}
+}
+
+func TestPrompter_BuildPrompt_Symbol(t *testing.T) {
+	p := NewPrompter()
+	prompt := p.BuildPrompt(PromptRequest{
+		Intent: domain.IntentExplain,
+		Level:  domain.L2LocationConcept,
+		Type:   domain.TypeExplain,
+		Symbol: &analysis.Symbol{
+			Name:        "double",
+			Kind:        analysis.KindFunc,
+			Type:        "func(n int) int",
+			Declaration: &analysis.Location{File: "main.go", Line: 3, Source: "func double(n int) int {\n\treturn n * 2\n}"},
+			Usages:      []analysis.Location{{File: "main.go", Line: 8, Source: "_ = double(3)"}},
+		},
+	})
+
+	for _, want := range []string{"## Symbol in Question", "Kind: func", "Declared at main.go:3", "SYMBOL_DECLARATION",
+		"main.go:8: ", "SYMBOL_USAGE", "### Symbol Scope", "Stay on this symbol"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+}
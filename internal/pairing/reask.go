@@ -0,0 +1,50 @@
+package pairing
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+// maxReasks bounds how often a malformed structured response is sent back
+// to the model for correction
+const maxReasks = 2
+
+var reaskCounter atomic.Int64
+
+// StructuredReasks returns the cumulative count of re-asks after malformed
+// structured output since process start. Wired into the metrics endpoint.
+func StructuredReasks() int64 {
+	return reaskCounter.Load()
+}
+
+// generateStructured calls the provider and hands the reply to parse. A
+// reply parse rejects is sent back with the parse error and instruction,
+// up to maxReasks times, so a stray fence or a miscounted array costs a
+// retry instead of failing the request. Provider errors are returned as
+// they are; when every reply is malformed the last parse error is.
+func (s *Service) generateStructured(ctx context.Context, provider llm.Provider, req *llm.Request, instruction string, parse func(content string) error) error {
+	messages := append([]llm.Message(nil), req.Messages...)
+	for attempt := 0; ; attempt++ {
+		retry := *req
+		retry.Messages = messages
+		resp, err := provider.Generate(ctx, &retry)
+		if err != nil {
+			return err
+		}
+
+		parseErr := parse(resp.Content)
+		if parseErr == nil || attempt == maxReasks {
+			return parseErr
+		}
+
+		reaskCounter.Add(1)
+		messages = append(messages,
+			llm.Message{Role: llm.RoleAssistant, Content: resp.Content},
+			llm.Message{Role: llm.RoleUser, Content: fmt.Sprintf(
+				"Your previous response could not be read (%v). %s", parseErr, instruction)},
+		)
+	}
+}
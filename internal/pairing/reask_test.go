@@ -0,0 +1,115 @@
+package pairing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+// scriptedProvider replies with its responses in order and records every
+// request
+type scriptedProvider struct {
+	mockProvider
+	replies  []string
+	requests []*llm.Request
+}
+
+func (p *scriptedProvider) Generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	p.requests = append(p.requests, req)
+	if len(p.replies) == 0 {
+		return nil, errors.New("no more replies")
+	}
+	reply := p.replies[0]
+	p.replies = p.replies[1:]
+	return &llm.Response{Content: reply}, nil
+}
+
+func newScriptedService(replies ...string) (*Service, *scriptedProvider) {
+	provider := &scriptedProvider{mockProvider: mockProvider{name: "test"}, replies: replies}
+	registry := llm.NewRegistry()
+	registry.Register("test", provider)
+	registry.SetDefault("test")
+	return NewService(registry, "test"), provider
+}
+
+func TestExplainErrors_ReasksMalformedOutput(t *testing.T) {
+	service, provider := newScriptedService(
+		"Here you go: the first error means...",
+		`["only one"]`,
+		`["first", "second"]`,
+	)
+	before := StructuredReasks()
+
+	got, err := service.ExplainErrors(context.Background(), "", nil, []string{"e1", "e2"})
+	if err != nil {
+		t.Fatalf("ExplainErrors() error = %v", err)
+	}
+	if len(got) != 2 || got[1] != "second" {
+		t.Errorf("got %v", got)
+	}
+	if len(provider.requests) != 3 {
+		t.Fatalf("expected 3 calls, got %d", len(provider.requests))
+	}
+	if n := StructuredReasks() - before; n != 2 {
+		t.Errorf("StructuredReasks() grew by %d, want 2", n)
+	}
+
+	// The re-ask carries the bad reply and says what was wrong with it
+	last := provider.requests[2].Messages
+	if len(last) != 5 || last[3].Role != llm.RoleAssistant || last[3].Content != `["only one"]` {
+		t.Fatalf("unexpected re-ask conversation: %+v", last)
+	}
+	if !strings.Contains(last[4].Content, "got 1 for 2 errors") || !strings.Contains(last[4].Content, "exactly 2 strings") {
+		t.Errorf("corrective instruction missing the reason: %q", last[4].Content)
+	}
+	if len(provider.requests[0].Messages) != 1 {
+		t.Error("the first request must not be changed by later re-asks")
+	}
+}
+
+func TestExplainErrors_GivesUpAfterMaxReasks(t *testing.T) {
+	replies := make([]string, maxReasks+2)
+	for i := range replies {
+		replies[i] = "not json"
+	}
+	service, provider := newScriptedService(replies...)
+
+	_, err := service.ExplainErrors(context.Background(), "", nil, []string{"e1"})
+	if !errors.Is(err, ErrUnparseableExplanations) {
+		t.Fatalf("expected ErrUnparseableExplanations, got %v", err)
+	}
+	if len(provider.requests) != maxReasks+1 {
+		t.Errorf("expected %d calls, got %d", maxReasks+1, len(provider.requests))
+	}
+}
+
+func TestReviewSpec_ReasksMalformedOutput(t *testing.T) {
+	service, provider := newScriptedService(
+		"```json\n{\"summary\": \"cut off",
+		`{"summary": "Solid.", "findings": []}`,
+	)
+
+	review, err := service.ReviewSpec(context.Background(), &domain.ProductSpec{Name: "todo"})
+	if err != nil {
+		t.Fatalf("ReviewSpec() error = %v", err)
+	}
+	if review.Summary != "Solid." || len(provider.requests) != 2 {
+		t.Errorf("summary %q after %d calls", review.Summary, len(provider.requests))
+	}
+}
+
+func TestGenerateStructured_ProviderErrorNotReasked(t *testing.T) {
+	service, provider := newScriptedService()
+
+	err := service.generateStructured(context.Background(), provider, &llm.Request{}, "", func(string) error {
+		t.Error("parse must not run without a reply")
+		return nil
+	})
+	if err == nil || len(provider.requests) != 1 {
+		t.Errorf("expected the provider error after one call, got %v after %d", err, len(provider.requests))
+	}
+}
@@ -0,0 +1,101 @@
+package pairing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/felixgeelhaar/temper/internal/redact"
+	"github.com/google/uuid"
+)
+
+const leakyCode = `package main
+
+// contact: jane@example.com
+const token = "sk-ant-REDACTED"
+`
+
+func redactionRequest() InterventionRequest {
+	return InterventionRequest{
+		SessionID: uuid.New(),
+		Intent:    domain.IntentHint,
+		Context: InterventionContext{
+			Code: map[string]string{"main.go": leakyCode},
+		},
+		Policy: domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet},
+	}
+}
+
+func newRedactor(t *testing.T) *redact.Redactor {
+	t.Helper()
+	r, err := redact.New(redact.Config{})
+	if err != nil {
+		t.Fatalf("redact.New() error = %v", err)
+	}
+	return r
+}
+
+func sentPrompt(req *llm.Request) string {
+	var b strings.Builder
+	for _, m := range req.Messages {
+		b.WriteString(m.Content)
+	}
+	return b.String()
+}
+
+func TestService_Intervene_RedactsPrompt(t *testing.T) {
+	mock := &mockProvider{
+		name:     "claude",
+		response: &llm.Response{Content: "Look at the loop bounds."},
+	}
+	service := createTestService(mock)
+	service.SetRedactor(newRedactor(t), false)
+
+	intervention, err := service.Intervene(context.Background(), redactionRequest())
+	if err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+
+	prompt := sentPrompt(mock.lastReq)
+	if strings.Contains(prompt, "jane@example.com") || strings.Contains(prompt, "sk-ant-api03") {
+		t.Errorf("prompt still contains secrets:\n%s", prompt)
+	}
+
+	kinds := map[string]int{}
+	for _, r := range intervention.Redactions {
+		kinds[r.Kind] = r.Count
+	}
+	if kinds["api_key"] == 0 || kinds["email"] == 0 {
+		t.Errorf("Redactions = %+v, want api_key and email", intervention.Redactions)
+	}
+}
+
+func TestService_Intervene_SkipsLocalProvider(t *testing.T) {
+	mock := &mockProvider{
+		name:     "ollama",
+		response: &llm.Response{Content: "Look at the loop bounds."},
+	}
+	service := createTestService(mock)
+	service.SetRedactor(newRedactor(t), false)
+
+	intervention, err := service.Intervene(context.Background(), redactionRequest())
+	if err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+	if !strings.Contains(sentPrompt(mock.lastReq), "jane@example.com") {
+		t.Error("prompt to local provider was redacted without include_local")
+	}
+	if len(intervention.Redactions) != 0 {
+		t.Errorf("Redactions = %+v, want none", intervention.Redactions)
+	}
+
+	service.SetRedactor(newRedactor(t), true)
+	if _, err := service.Intervene(context.Background(), redactionRequest()); err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+	if strings.Contains(sentPrompt(mock.lastReq), "jane@example.com") {
+		t.Error("prompt to local provider not redacted with include_local")
+	}
+}
@@ -70,3 +70,26 @@ func TestFallbackModelLabel(t *testing.T) {
 		t.Errorf("non-empty must round-trip, got %q", got)
 	}
 }
+
+func TestService_ModelFor_RequestLevelModels(t *testing.T) {
+	claude := &mockProvider{name: "claude"}
+	ollama := &mockProvider{name: "ollama"}
+	s := NewService(localOnlyRegistry(claude, ollama), "claude")
+	s.SetLevelModels(map[domain.InterventionLevel]string{domain.L1CategoryHint: "haiku"})
+
+	if got := s.modelFor(InterventionRequest{}, claude, domain.L1CategoryHint); got != "haiku" {
+		t.Errorf("default provider → got %q, want haiku", got)
+	}
+	if got := s.modelFor(InterventionRequest{}, ollama, domain.L1CategoryHint); got != "" {
+		t.Errorf("other provider → got %q, want its default", got)
+	}
+
+	// A project routing hints to ollama names ollama's models
+	req := InterventionRequest{Provider: "ollama", LevelModels: map[domain.InterventionLevel]string{domain.L1CategoryHint: "qwen2.5-coder"}}
+	if got := s.modelFor(req, ollama, domain.L1CategoryHint); got != "qwen2.5-coder" {
+		t.Errorf("project level model → got %q, want qwen2.5-coder", got)
+	}
+	if got := s.modelFor(req, ollama, domain.L4PartialSolution); got != "" {
+		t.Errorf("unrouted level → got %q, want provider default", got)
+	}
+}
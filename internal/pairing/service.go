@@ -7,10 +7,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/felixgeelhaar/temper/internal/concept"
 	"github.com/felixgeelhaar/temper/internal/correlation"
 	"github.com/felixgeelhaar/temper/internal/domain"
 	"github.com/felixgeelhaar/temper/internal/llm"
 	"github.com/felixgeelhaar/temper/internal/profile"
+	"github.com/felixgeelhaar/temper/internal/redact"
 	"github.com/google/uuid"
 )
 
@@ -22,6 +24,21 @@ type Service struct {
 	selector        *Selector
 	prompter        *Prompter
 	clampValidator  *ClampValidator
+
+	// Optional prompt redaction before cloud LLM calls
+	redactor    *redact.Redactor
+	redactLocal bool
+
+	localOnly LocalOnlyRules
+
+	// Optional glossary for tagging interventions with concepts
+	concepts *concept.Index
+
+	// Locale the learner reads; prose is generated in its language
+	locale string
+
+	// Optional Go doc lookup offered to the model as a tool
+	docLookup DocLookup
 }
 
 // NewService creates a new pairing service
@@ -41,6 +58,47 @@ func (s *Service) SetLevelModels(m map[domain.InterventionLevel]string) {
 	s.levelModels = m
 }
 
+// SetRedactor scrubs prompts before they are sent. Prompts to the local
+// Ollama provider are left alone unless includeLocal is set, since they
+// never leave the machine.
+func (s *Service) SetRedactor(r *redact.Redactor, includeLocal bool) {
+	s.redactor = r
+	s.redactLocal = includeLocal
+}
+
+// SetConceptIndex tags interventions with the glossary concepts their
+// content mentions
+func (s *Service) SetConceptIndex(idx *concept.Index) {
+	s.concepts = idx
+}
+
+// SetLocale sets the learner's locale, e.g. "de" or "pt-BR". Hints,
+// reviews and explanations are then written in its language.
+func (s *Service) SetLocale(tag string) {
+	s.locale = tag
+}
+
+// localize appends the locale directive to a system prompt
+func (s *Service) localize(system string) string {
+	return system + s.prompter.LocaleDirective(s.locale)
+}
+
+// redactPrompt applies the configured redactor for the given provider
+func (s *Service) redactPrompt(provider llm.Provider, prompt string) (string, []domain.Redaction) {
+	if s.redactor == nil || (isLocalProvider(provider) && !s.redactLocal) {
+		return prompt, nil
+	}
+	redacted, findings := s.redactor.Redact(prompt)
+	if len(findings) == 0 {
+		return prompt, nil
+	}
+	redactions := make([]domain.Redaction, len(findings))
+	for i, f := range findings {
+		redactions[i] = domain.Redaction{Kind: f.Kind, Count: f.Count}
+	}
+	return redacted, redactions
+}
+
 // modelForLevel returns the configured model for a level, or empty when
 // no override is set. Empty signals to the provider that its default
 // should be used.
@@ -51,6 +109,19 @@ func (s *Service) modelForLevel(level domain.InterventionLevel) string {
 	return s.levelModels[level]
 }
 
+// modelFor returns the model to ask provider for at level. A request's
+// own level models (a project's routing) name models of whichever
+// provider serves it; the configured ones name the default provider's.
+func (s *Service) modelFor(req InterventionRequest, provider llm.Provider, level domain.InterventionLevel) string {
+	if model := req.LevelModels[level]; model != "" {
+		return model
+	}
+	if provider.Name() != s.llmRegistry.DefaultName() {
+		return ""
+	}
+	return s.modelForLevel(level)
+}
+
 // InterventionRequest contains data for requesting an intervention
 type InterventionRequest struct {
 	SessionID     uuid.UUID
@@ -59,8 +130,11 @@ type InterventionRequest struct {
 	Context       InterventionContext
 	Policy        domain.LearningPolicy
 	RunID         *uuid.UUID
-	ExplicitLevel domain.InterventionLevel // Explicit level request (for escalation)
-	Justification string                   // Required justification for L4/L5 escalation
+	ExplicitLevel domain.InterventionLevel            // Explicit level request (for escalation)
+	Justification string                              // Required justification for L4/L5 escalation
+	Provider      string                              // Registered provider to use instead of the default (replay, project routing)
+	LevelModels   map[domain.InterventionLevel]string // Models per level overriding the configured ones (project routing)
+	BudgetSpent   int                                 // Hint budget tokens the session has used
 }
 
 // InterventionContext is defined in context.go with spec support
@@ -75,6 +149,15 @@ func exerciseLanguage(ex *domain.Exercise) string {
 	return ex.Language
 }
 
+// contextLanguage returns the language a request's code is in: its
+// exercise's, or else the one detected from the learner's files
+func contextLanguage(ctx InterventionContext) string {
+	if lang := exerciseLanguage(ctx.Exercise); lang != "" {
+		return lang
+	}
+	return ctx.Language
+}
+
 // applyPolicyClamp applies the global MaxLevel ceiling plus an optional
 // per-topic adjustment based on the learner's profile. Falls back to the
 // plain ClampLevel path when no exercise or profile is available so older
@@ -99,56 +182,49 @@ func fallbackModelLabel(m string) string {
 
 // Intervene generates an intervention based on the request
 func (s *Service) Intervene(ctx context.Context, req InterventionRequest) (*domain.Intervention, error) {
-	var level domain.InterventionLevel
-
-	// Use explicit level if provided (for escalation requests)
-	if req.ExplicitLevel > 0 {
-		level = req.ExplicitLevel
-	} else {
-		// Select intervention level based on context
-		level = s.selector.SelectLevel(req.Intent, req.Context, req.Policy)
-		// Apply policy clamp, including the per-topic adjustment when the
-		// learner's exercise + profile expose a topic skill.
-		level = applyPolicyClamp(level, req.Policy, req.Context)
-	}
-
-	// Select intervention type
-	interventionType := s.selector.SelectType(req.Intent, level)
-
-	// Build prompt for LLM
-	prompt := s.prompter.BuildPrompt(PromptRequest{
-		Intent:         req.Intent,
-		Level:          level,
-		Type:           interventionType,
-		Exercise:       req.Context.Exercise,
-		Code:           req.Context.Code,
-		Output:         req.Context.RunOutput,
-		Profile:        req.Context.Profile,
-		Spec:           req.Context.Spec,
-		FocusCriterion: req.Context.FocusCriterion,
-	})
+	c, err := s.compose(req, forLLM)
+	if err != nil {
+		return nil, err
+	}
+	// A hint the pack author wrote for this level beats a generated one
+	if hint, ok := authoredHint(req, c.level); ok {
+		return s.authoredIntervention(req, c, hint), nil
+	}
+	level, testFirst, contract := c.level, c.testFirst, c.contract
+	interventionType, prompt, systemPrompt := c.interventionType, c.prompt, c.system
 
-	systemPrompt := s.prompter.SystemPromptForLanguage(level, exerciseLanguage(req.Context.Exercise))
 	systemBlocks := []llm.SystemContentBlock{
 		// Stable per (provider, level, language) — cache it. Hint requests
 		// within a session reuse the same level system prompt repeatedly.
 		{Text: systemPrompt, CacheControl: true},
 	}
 
-	chosenModel := s.modelForLevel(level)
-
 	// Get LLM provider. If none is available (no API key, all disabled),
 	// fall back to the offline path so the user still gets useful guidance.
-	provider, err := s.llmRegistry.Default()
+	// A local-only session without a local provider is a configuration
+	// error the user has to see, and so is a named provider that can't
+	// be used.
+	provider, err := s.providerFor(req)
+	if errors.Is(err, ErrNoLocalProvider) || (err != nil && req.Provider != "") {
+		return nil, err
+	}
 	if err != nil {
 		if fallback := s.offlineIntervention(req, level, interventionType, "no LLM provider available"); fallback != nil {
+			fallback.Contract = contract
 			return fallback, nil
 		}
 		return nil, fmt.Errorf("get LLM provider: %w", err)
 	}
+	chosenModel := s.modelFor(req, provider, level)
+	prompt, redactions := s.redactPrompt(provider, prompt)
+
+	tools := s.tools(req.Context)
+	if len(tools) > 0 && llm.SupportsTools(provider) {
+		systemBlocks = append(systemBlocks, llm.SystemContentBlock{Text: docToolDirective})
+	}
 
 	// Generate intervention content
-	llmResp, err := provider.Generate(ctx, &llm.Request{
+	llmResp, err := llm.GenerateWithTools(ctx, provider, &llm.Request{
 		Model: chosenModel,
 		Messages: []llm.Message{
 			{Role: llm.RoleUser, Content: prompt},
@@ -156,19 +232,28 @@ func (s *Service) Intervene(ctx context.Context, req InterventionRequest) (*doma
 		System:        systemPrompt,
 		SystemBlocks:  systemBlocks,
 		CorrelationID: correlation.FromContext(ctx),
-		MaxTokens:     1024,
+		MaxTokens:     maxInterventionTokens,
 		Temperature:   0.7,
-	})
+	}, tools)
 	if err != nil {
 		// LLM failed (network, circuit breaker open, rate limit, etc.).
 		// Serve a YAML hint when one is available rather than fail hard.
-		if fallback := s.offlineIntervention(req, level, interventionType, fmt.Sprintf("LLM error: %v", err)); fallback != nil {
-			return fallback, nil
+		if req.Provider == "" {
+			if fallback := s.offlineIntervention(req, level, interventionType, fmt.Sprintf("LLM error: %v", err)); fallback != nil {
+				fallback.Contract = contract
+				return fallback, nil
+			}
 		}
 		return nil, fmt.Errorf("generate intervention: %w", err)
 	}
 
 	content, clampRationale := s.enforceClamp(ctx, provider, level, prompt, systemPrompt, llmResp.Content)
+	if level < domain.L5FullSolution && len(c.solution) > 0 {
+		var withheld bool
+		if content, withheld = withholdSolution(content, c.solution); withheld {
+			clampRationale += "; reference solution withheld"
+		}
+	}
 
 	// Build intervention
 	intervention := &domain.Intervention{
@@ -181,10 +266,14 @@ func (s *Service) Intervene(ctx context.Context, req InterventionRequest) (*doma
 		Type:        interventionType,
 		Content:     content,
 		Targets:     s.extractTargets(req.Context),
-		Rationale:   buildRationale(level, req, chosenModel, clampRationale),
+		Rationale:   buildRationale(level, req, chosenModel, clampRationale+testFirstNote(testFirst)),
+		Redactions:  redactions,
+		Concepts:    s.concepts.Tag(content),
 		RequestedAt: time.Now(),
 		DeliveredAt: time.Now(),
+		Contract:    contract,
 	}
+	intervention.Contract.Details = intervention.Rationale
 
 	return intervention, nil
 }
@@ -280,6 +369,7 @@ func (s *Service) offlineIntervention(
 		Targets:   s.extractTargets(req.Context),
 		Rationale: fmt.Sprintf("Offline fallback at L%d (intent=%s); reason: %s",
 			level, req.Intent, reason),
+		Concepts:    s.concepts.Tag(hints[0]),
 		RequestedAt: time.Now(),
 		DeliveredAt: time.Now(),
 	}
@@ -333,30 +423,27 @@ func (s *Service) enforceClamp(
 
 // IntervenStream generates an intervention with streaming response
 func (s *Service) IntervenStream(ctx context.Context, req InterventionRequest) (<-chan StreamChunk, error) {
-	level := s.selector.SelectLevel(req.Intent, req.Context, req.Policy)
-	level = req.Policy.ClampLevel(level)
-	interventionType := s.selector.SelectType(req.Intent, level)
-
-	prompt := s.prompter.BuildPrompt(PromptRequest{
-		Intent:         req.Intent,
-		Level:          level,
-		Type:           interventionType,
-		Exercise:       req.Context.Exercise,
-		Code:           req.Context.Code,
-		Output:         req.Context.RunOutput,
-		Profile:        req.Context.Profile,
-		Spec:           req.Context.Spec,
-		FocusCriterion: req.Context.FocusCriterion,
-	})
+	c, err := s.compose(req, forStream)
+	if err != nil {
+		return nil, err
+	}
+	if hint, ok := authoredHint(req, c.level); ok {
+		return s.authoredStream(req, c, hint), nil
+	}
+	level, testFirst, contract := c.level, c.testFirst, c.contract
+	interventionType, prompt := c.interventionType, c.prompt
 
-	provider, err := s.llmRegistry.Default()
+	provider, err := s.providerFor(req)
 	if err != nil {
 		return nil, fmt.Errorf("get LLM provider: %w", err)
 	}
+	model := s.modelFor(req, provider, level)
+	prompt, redactions := s.redactPrompt(provider, prompt)
+	contract.Details = buildRationale(level, req, model, testFirstNote(testFirst))
 
-	streamSystem := s.prompter.SystemPromptForLanguage(level, exerciseLanguage(req.Context.Exercise))
+	streamSystem := c.system
 	llmStream, err := provider.GenerateStream(ctx, &llm.Request{
-		Model: s.modelForLevel(level),
+		Model: model,
 		Messages: []llm.Message{
 			{Role: llm.RoleUser, Content: prompt},
 		},
@@ -365,7 +452,7 @@ func (s *Service) IntervenStream(ctx context.Context, req InterventionRequest) (
 			{Text: streamSystem, CacheControl: true},
 		},
 		CorrelationID: correlation.FromContext(ctx),
-		MaxTokens:     1024,
+		MaxTokens:     maxInterventionTokens,
 		Temperature:   0.7,
 	})
 	if err != nil {
@@ -377,26 +464,49 @@ func (s *Service) IntervenStream(ctx context.Context, req InterventionRequest) (
 	go func() {
 		defer close(outCh)
 
+		// A caller that went away stops reading; stop forwarding and
+		// drain the provider so its goroutine can finish too
+		send := func(chunk StreamChunk) bool {
+			select {
+			case outCh <- chunk:
+				return true
+			case <-ctx.Done():
+				go func() {
+					for range llmStream {
+					}
+				}()
+				return false
+			}
+		}
+
 		// Send metadata first
-		outCh <- StreamChunk{
+		if !send(StreamChunk{
 			Type: "metadata",
 			Metadata: &InterventionMetadata{
-				Level: level,
-				Type:  interventionType,
+				Level:      level,
+				Type:       interventionType,
+				Redactions: redactions,
+				Contract:   contract,
 			},
+		}) {
+			return
 		}
 
-		// Stream content
+		// Stream content, keeping it to tag concepts once it's complete
+		var content strings.Builder
 		for chunk := range llmStream {
 			if chunk.Error != nil {
-				outCh <- StreamChunk{Type: "error", Error: chunk.Error}
+				send(StreamChunk{Type: "error", Error: chunk.Error})
 				return
 			}
 			if chunk.Done {
-				outCh <- StreamChunk{Type: "done"}
+				send(StreamChunk{Type: "done", Concepts: s.concepts.Tag(content.String())})
+				return
+			}
+			content.WriteString(chunk.Content)
+			if !send(StreamChunk{Type: "content", Content: chunk.Content}) {
 				return
 			}
-			outCh <- StreamChunk{Type: "content", Content: chunk.Content}
 		}
 	}()
 
@@ -408,13 +518,16 @@ type StreamChunk struct {
 	Type     string
 	Content  string
 	Metadata *InterventionMetadata
+	Concepts []string // on the done chunk: concepts the whole content touches on
 	Error    error
 }
 
 // InterventionMetadata contains intervention metadata
 type InterventionMetadata struct {
-	Level domain.InterventionLevel
-	Type  domain.InterventionType
+	Level      domain.InterventionLevel
+	Type       domain.InterventionType
+	Redactions []domain.Redaction
+	Contract   domain.LevelRationale
 }
 
 func (s *Service) extractTargets(ctx InterventionContext) []domain.Target {
@@ -441,10 +554,11 @@ func (s *Service) SuggestForSection(ctx context.Context, authCtx AuthoringContex
 	prompt := s.prompter.BuildAuthoringPrompt(authCtx)
 
 	// Get LLM provider
-	provider, err := s.llmRegistry.Default()
+	provider, err := s.provider(s.localOnlyForAuthoring(authCtx))
 	if err != nil {
 		return nil, fmt.Errorf("get LLM provider: %w", err)
 	}
+	prompt, _ = s.redactPrompt(provider, prompt)
 
 	// Generate suggestions
 	llmResp, err := provider.Generate(ctx, &llm.Request{
@@ -474,10 +588,11 @@ func (s *Service) AuthoringHint(ctx context.Context, authCtx AuthoringContext) (
 	prompt := s.prompter.BuildAuthoringHintPrompt(authCtx)
 
 	// Get LLM provider
-	provider, err := s.llmRegistry.Default()
+	provider, err := s.provider(s.localOnlyForAuthoring(authCtx))
 	if err != nil {
 		return nil, fmt.Errorf("get LLM provider: %w", err)
 	}
+	prompt, redactions := s.redactPrompt(provider, prompt)
 
 	// Generate hint
 	llmResp, err := provider.Generate(ctx, &llm.Request{
@@ -501,6 +616,7 @@ func (s *Service) AuthoringHint(ctx context.Context, authCtx AuthoringContext) (
 		Level:       domain.L3ConstrainedSnippet,
 		Type:        domain.TypeExplain,
 		Content:     llmResp.Content,
+		Redactions:  redactions,
 		RequestedAt: time.Now(),
 		DeliveredAt: time.Now(),
 	}, nil
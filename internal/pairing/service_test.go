@@ -3,8 +3,10 @@ package pairing
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 
+	"github.com/felixgeelhaar/temper/internal/concept"
 	"github.com/felixgeelhaar/temper/internal/domain"
 	"github.com/felixgeelhaar/temper/internal/llm"
 	"github.com/google/uuid"
@@ -94,11 +96,13 @@ type mockProvider struct {
 	err       error
 	streaming bool
 	stream    []llm.StreamChunk
+	lastReq   *llm.Request
 }
 
 func (m *mockProvider) Name() string { return m.name }
 
 func (m *mockProvider) Generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	m.lastReq = req
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -331,6 +335,38 @@ func TestService_Intervene_WithRunID(t *testing.T) {
 	}
 }
 
+func TestService_Intervene_TagsConcepts(t *testing.T) {
+	mock := &mockProvider{
+		name: "test",
+		response: &llm.Response{
+			Content: "Which goroutine closes the channel once the last value is sent?",
+		},
+	}
+	req := InterventionRequest{
+		SessionID: uuid.New(),
+		Intent:    domain.IntentHint,
+		Policy:    domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet},
+	}
+
+	service := createTestService(mock)
+	intervention, err := service.Intervene(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+	if intervention.Concepts != nil {
+		t.Errorf("Concepts without an index = %v; want none", intervention.Concepts)
+	}
+
+	service.SetConceptIndex(concept.Default())
+	intervention, err = service.Intervene(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+	if want := []string{"goroutines", "channels"}; !reflect.DeepEqual(intervention.Concepts, want) {
+		t.Errorf("Concepts = %v; want %v", intervention.Concepts, want)
+	}
+}
+
 func TestService_Intervene_AllIntents(t *testing.T) {
 	intents := []domain.Intent{
 		domain.IntentHint,
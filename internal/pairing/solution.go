@@ -0,0 +1,108 @@
+package pairing
+
+import (
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// solutionWithheld replaces reference solution code that turns up in an
+// intervention below L5
+const solutionWithheld = "[reference solution withheld below L5]"
+
+// solutionRunLength is how many significant lines in a row an
+// intervention must share with the reference solution to count as
+// quoting it. Shorter runs are boilerplate every solution shares.
+const solutionRunLength = 3
+
+// referenceSolution returns the exercise's reference solution when the
+// prompt may carry it: at L5, where the learner gets a full solution
+// anyway, and for reviews, which grade the code against it. A stream
+// reaches the learner before it can be checked for quoted solution code,
+// so below L5 streams go without.
+func referenceSolution(req InterventionRequest, level domain.InterventionLevel, streamed bool) map[string]string {
+	if req.Context.Exercise == nil || len(req.Context.Exercise.Solution) == 0 {
+		return nil
+	}
+	if level >= domain.L5FullSolution || (req.Intent == domain.IntentReview && !streamed) {
+		return req.Context.Exercise.Solution
+	}
+	return nil
+}
+
+// withholdSolution replaces every run of solutionRunLength or more lines
+// that content shares with the solution by a marker, so below L5 the
+// reference never reaches the learner verbatim. Lines are compared
+// trimmed, ignoring blank lines and lone braces.
+func withholdSolution(content string, solution map[string]string) (string, bool) {
+	runs := map[string]bool{}
+	for _, file := range solution {
+		lines := significantLines(strings.Split(file, "\n"))
+		for i := 0; i+solutionRunLength <= len(lines); i++ {
+			runs[joinRun(lines[i:i+solutionRunLength])] = true
+		}
+	}
+	if len(runs) == 0 {
+		return content, false
+	}
+
+	lines := strings.Split(content, "\n")
+	var idx []int // indexes of the significant lines in content
+	var sig []string
+	for i, line := range lines {
+		if t := significantLine(line); t != "" {
+			idx = append(idx, i)
+			sig = append(sig, t)
+		}
+	}
+	withheld := make([]bool, len(lines))
+	found := false
+	for i := 0; i+solutionRunLength <= len(sig); i++ {
+		if !runs[joinRun(sig[i:i+solutionRunLength])] {
+			continue
+		}
+		for j := idx[i]; j <= idx[i+solutionRunLength-1]; j++ {
+			withheld[j] = true
+		}
+		found = true
+	}
+	if !found {
+		return content, false
+	}
+
+	var out []string
+	for i, line := range lines {
+		switch {
+		case !withheld[i]:
+			out = append(out, line)
+		case i == 0 || !withheld[i-1]:
+			out = append(out, solutionWithheld)
+		}
+	}
+	return strings.Join(out, "\n"), true
+}
+
+func significantLines(lines []string) []string {
+	var sig []string
+	for _, line := range lines {
+		if t := significantLine(line); t != "" {
+			sig = append(sig, t)
+		}
+	}
+	return sig
+}
+
+// significantLine returns line trimmed, or "" when it carries nothing
+// that identifies a solution
+func significantLine(line string) string {
+	t := strings.TrimSpace(line)
+	switch t {
+	case "{", "}", "(", ")", "[", "]", "},", "});", "})", "end":
+		return ""
+	}
+	return t
+}
+
+func joinRun(lines []string) string {
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,110 @@
+package pairing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/google/uuid"
+)
+
+const referenceMain = `package main
+
+import "fmt"
+
+func Hello(name string) string {
+	if name == "" {
+		name = "World"
+	}
+	return fmt.Sprintf("Hello, %s!", name)
+}
+`
+
+func TestWithholdSolution(t *testing.T) {
+	solution := map[string]string{"main.go": referenceMain}
+
+	quoted := "Compare with this:\n\n    if name == \"\" {\n        name = \"World\"\n    }\n    return fmt.Sprintf(\"Hello, %s!\", name)\n\nSee the difference?"
+	got, withheld := withholdSolution(quoted, solution)
+	if !withheld {
+		t.Fatalf("withholdSolution() kept %q", got)
+	}
+	if strings.Contains(got, "Sprintf") || strings.Count(got, solutionWithheld) != 1 {
+		t.Errorf("withholdSolution() = %q, want the quoted run replaced by one marker", got)
+	}
+	if !strings.HasPrefix(got, "Compare with this:") || !strings.HasSuffix(got, "See the difference?") {
+		t.Errorf("withholdSolution() = %q, want the prose around the quote kept", got)
+	}
+
+	// A line or two in common is boilerplate, not a quote
+	prose := "Your package main and import \"fmt\" are fine.\nimport \"fmt\"\nCheck the empty name case."
+	if got, withheld := withholdSolution(prose, solution); withheld || got != prose {
+		t.Errorf("withholdSolution() = %q, %v; want prose untouched", got, withheld)
+	}
+}
+
+func TestReferenceSolution(t *testing.T) {
+	ex := &domain.Exercise{ID: "go-v1/basics/hello", Solution: map[string]string{"main.go": referenceMain}}
+	req := func(intent domain.Intent) InterventionRequest {
+		return InterventionRequest{Intent: intent, Context: InterventionContext{Exercise: ex}}
+	}
+
+	tests := []struct {
+		name     string
+		intent   domain.Intent
+		level    domain.InterventionLevel
+		streamed bool
+		want     bool
+	}{
+		{"L5 hint", domain.IntentHint, domain.L5FullSolution, false, true},
+		{"L5 streamed", domain.IntentStuck, domain.L5FullSolution, true, true},
+		{"review grades against it", domain.IntentReview, domain.L2LocationConcept, false, true},
+		{"streamed review goes without", domain.IntentReview, domain.L2LocationConcept, true, false},
+		{"hint below L5", domain.IntentHint, domain.L4PartialSolution, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := referenceSolution(req(tt.intent), tt.level, tt.streamed) != nil; got != tt.want {
+				t.Errorf("referenceSolution() given = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_Intervene_WithholdsSolution(t *testing.T) {
+	mock := &mockProvider{
+		name: "test",
+		response: &llm.Response{
+			Content: "The empty-name case is missing. The reference does:\n" +
+				"if name == \"\" {\nname = \"World\"\n}\nreturn fmt.Sprintf(\"Hello, %s!\", name)",
+			FinishReason: "stop",
+		},
+	}
+	service := createTestService(mock)
+
+	req := InterventionRequest{
+		SessionID: uuid.New(),
+		Intent:    domain.IntentReview,
+		Context: InterventionContext{
+			Exercise: &domain.Exercise{ID: "go-v1/basics/hello", Solution: map[string]string{"main.go": referenceMain}},
+			Code:     map[string]string{"main.go": "package main\n"},
+		},
+		Policy:        domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet},
+		ExplicitLevel: domain.L2LocationConcept,
+	}
+
+	iv, err := service.Intervene(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+	if !strings.Contains(mock.lastReq.Messages[0].Content, "REFERENCE_SOLUTION") {
+		t.Error("a review prompt should carry the reference solution to grade against")
+	}
+	if strings.Contains(iv.Content, "Sprintf") || !strings.Contains(iv.Content, solutionWithheld) {
+		t.Errorf("Content = %q, want the quoted solution withheld", iv.Content)
+	}
+	if !strings.Contains(iv.Rationale, "reference solution withheld") {
+		t.Errorf("Rationale = %q, want a note that the solution was withheld", iv.Rationale)
+	}
+}
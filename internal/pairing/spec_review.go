@@ -0,0 +1,187 @@
+package pairing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/correlation"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+// ErrUnparseableReview is returned when the LLM's review is not the JSON
+// shape the prompt asked for
+var ErrUnparseableReview = errors.New("spec review response is not valid JSON")
+
+// ReviewSpec asks the LLM to critique a spec. Findings carry suggested
+// rewrites for the author to consider; nothing is applied to the spec.
+func (s *Service) ReviewSpec(ctx context.Context, spec *domain.ProductSpec) (*domain.SpecReview, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("no spec to review")
+	}
+
+	prompt := s.prompter.BuildSpecReviewPrompt(spec)
+
+	provider, err := s.provider(s.localOnlyForAuthoring(AuthoringContext{Spec: spec}))
+	if err != nil {
+		return nil, fmt.Errorf("get LLM provider: %w", err)
+	}
+	prompt, _ = s.redactPrompt(provider, prompt)
+
+	system := s.localize(s.prompter.SpecReviewSystemPrompt())
+	var summary string
+	var findings []domain.SpecFinding
+	err = s.generateStructured(ctx, provider, &llm.Request{
+		Messages: []llm.Message{
+			{Role: llm.RoleUser, Content: prompt},
+		},
+		System: system,
+		SystemBlocks: []llm.SystemContentBlock{
+			{Text: system, CacheControl: true},
+		},
+		CorrelationID: correlation.FromContext(ctx),
+		MaxTokens:     2048,
+		Temperature:   0.2,
+	}, `Respond again with ONLY the JSON object {"summary": ..., "findings": [...]} described above. No markdown fences or other text.`,
+		func(content string) (err error) {
+			summary, findings, err = ParseSpecReview(content)
+			return err
+		})
+	if err != nil {
+		if errors.Is(err, ErrUnparseableReview) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("generate review: %w", err)
+	}
+
+	return &domain.SpecReview{
+		SpecPath:   spec.FilePath,
+		Summary:    summary,
+		Findings:   findings,
+		ReviewedAt: time.Now(),
+	}, nil
+}
+
+// SpecReviewSystemPrompt returns the system prompt for spec reviews
+func (p *Prompter) SpecReviewSystemPrompt() string {
+	return `You are a critical reviewer of product specifications. You do not write the spec; you find the problems that would make it hard to build or verify.
+
+Look for:
+- ambiguous_criterion: an acceptance criterion two engineers could read differently, or that lacks a concrete input, action or observable outcome
+- missing_edge_case: empty input, limits, failures, concurrency, permissions or other cases the spec never states
+- untestable_goal: a goal with no measurable outcome
+- conflicting_features: features or criteria that contradict each other
+
+Be specific and cite the ID (or "goals[N]" for goals) of what you criticize. Report only real problems; an empty findings list is a valid answer. Suggested rewrites must keep the author's intent.
+
+Output ONLY valid JSON, no markdown fences or explanation.`
+}
+
+// BuildSpecReviewPrompt renders the spec for review
+func (p *Prompter) BuildSpecReviewPrompt(spec *domain.ProductSpec) string {
+	var sb strings.Builder
+	f := newFence()
+	sb.WriteString(f.securityPreamble())
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Name: %s\nVersion: %s\n", spec.Name, spec.Version)
+
+	body.WriteString("\nGoals:\n")
+	for i, g := range spec.Goals {
+		fmt.Fprintf(&body, "- goals[%d]: %s\n", i, g)
+	}
+
+	body.WriteString("\nFeatures:\n")
+	for _, feat := range spec.Features {
+		fmt.Fprintf(&body, "- [%s] %s (%s): %s\n", feat.ID, feat.Title, feat.Priority, feat.Description)
+		for _, c := range feat.SuccessCriteria {
+			fmt.Fprintf(&body, "    success: %s\n", c)
+		}
+		if feat.API != nil {
+			fmt.Fprintf(&body, "    api: %s %s\n", feat.API.Method, feat.API.Path)
+		}
+	}
+
+	body.WriteString("\nAcceptance criteria:\n")
+	for _, ac := range spec.AcceptanceCriteria {
+		fmt.Fprintf(&body, "- [%s] %s\n", ac.ID, ac.Description)
+	}
+
+	nf := spec.NonFunctional
+	if len(nf.Performance)+len(nf.Security)+len(nf.Scalability)+len(nf.Availability) > 0 {
+		body.WriteString("\nNon-functional requirements:\n")
+		for _, r := range nf.Performance {
+			fmt.Fprintf(&body, "- performance: %s\n", r)
+		}
+		for _, r := range nf.Security {
+			fmt.Fprintf(&body, "- security: %s\n", r)
+		}
+		for _, r := range nf.Scalability {
+			fmt.Fprintf(&body, "- scalability: %s\n", r)
+		}
+		for _, r := range nf.Availability {
+			fmt.Fprintf(&body, "- availability: %s\n", r)
+		}
+	}
+
+	sb.WriteString("## Spec Under Review\n\n")
+	sb.WriteString(f.wrap("SPEC", body.String()))
+	sb.WriteString("\n\n## Task\n\n")
+	sb.WriteString(`Review the spec and respond with a JSON object of this shape:
+{
+  "summary": "one or two sentences on the spec's overall quality",
+  "findings": [
+    {
+      "kind": "ambiguous_criterion|missing_edge_case|untestable_goal|conflicting_features",
+      "severity": "high|medium|low",
+      "target": "ac-001",
+      "message": "what is wrong and why it matters",
+      "suggested_rewrite": "replacement text for the target, if one helps"
+    }
+  ]
+}`)
+	return sb.String()
+}
+
+// ParseSpecReview extracts the summary and findings from the LLM's JSON
+// response. Findings of unknown kinds or without a message are dropped.
+func ParseSpecReview(content string) (string, []domain.SpecFinding, error) {
+	var raw struct {
+		Summary  string               `json:"summary"`
+		Findings []domain.SpecFinding `json:"findings"`
+	}
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		start := strings.Index(content, "{")
+		end := strings.LastIndex(content, "}")
+		if start < 0 || end <= start {
+			return "", nil, ErrUnparseableReview
+		}
+		if err := json.Unmarshal([]byte(content[start:end+1]), &raw); err != nil {
+			return "", nil, fmt.Errorf("%w: %v", ErrUnparseableReview, err)
+		}
+	}
+
+	findings := make([]domain.SpecFinding, 0, len(raw.Findings))
+	for _, f := range raw.Findings {
+		switch f.Kind {
+		case domain.FindingAmbiguousCriterion, domain.FindingMissingEdgeCase,
+			domain.FindingUntestableGoal, domain.FindingConflictingFeatures:
+		default:
+			continue
+		}
+		if strings.TrimSpace(f.Message) == "" {
+			continue
+		}
+		switch f.Severity {
+		case "high", "medium", "low":
+		default:
+			f.Severity = "medium"
+		}
+		findings = append(findings, f)
+	}
+	return strings.TrimSpace(raw.Summary), findings, nil
+}
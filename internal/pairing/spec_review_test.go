@@ -0,0 +1,83 @@
+package pairing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+func TestParseSpecReview(t *testing.T) {
+	content := "Here is the review:\n```json\n" + `{
+  "summary": "Mostly clear.",
+  "findings": [
+    {"kind": "ambiguous_criterion", "severity": "high", "target": "ac-1", "message": "What counts as fast?", "suggested_rewrite": "Responds within 200ms"},
+    {"kind": "style", "severity": "low", "target": "goals[0]", "message": "Wordy"},
+    {"kind": "missing_edge_case", "severity": "urgent", "target": "login", "message": "No lockout after failed attempts"},
+    {"kind": "untestable_goal", "target": "goals[1]", "message": ""}
+  ]
+}` + "\n```"
+
+	summary, findings, err := ParseSpecReview(content)
+	if err != nil {
+		t.Fatalf("ParseSpecReview() error = %v", err)
+	}
+	if summary != "Mostly clear." {
+		t.Errorf("summary = %q", summary)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want 2 (unknown kind and empty message dropped): %+v", len(findings), findings)
+	}
+	if findings[0].SuggestedRewrite != "Responds within 200ms" {
+		t.Errorf("SuggestedRewrite = %q", findings[0].SuggestedRewrite)
+	}
+	if findings[1].Severity != "medium" {
+		t.Errorf("unknown severity normalized to %q, want medium", findings[1].Severity)
+	}
+}
+
+func TestParseSpecReview_NotJSON(t *testing.T) {
+	if _, _, err := ParseSpecReview("The spec looks fine to me."); !errors.Is(err, ErrUnparseableReview) {
+		t.Errorf("error = %v, want ErrUnparseableReview", err)
+	}
+}
+
+func TestService_ReviewSpec(t *testing.T) {
+	mock := &mockProvider{
+		name: "test",
+		response: &llm.Response{
+			Content: `{"summary": "ok", "findings": [{"kind": "conflicting_features", "severity": "medium", "target": "export", "message": "Contradicts offline-only"}]}`,
+		},
+	}
+	service := createTestService(mock)
+
+	spec := &domain.ProductSpec{
+		Name:     "Auth",
+		Goals:    []string{"Be secure"},
+		FilePath: ".specs/auth.yaml",
+		AcceptanceCriteria: []domain.AcceptanceCriterion{
+			{ID: "ac-1", Description: "Login is fast"},
+		},
+	}
+
+	review, err := service.ReviewSpec(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("ReviewSpec() error = %v", err)
+	}
+	if review.SpecPath != spec.FilePath || len(review.Findings) != 1 {
+		t.Errorf("review = %+v", review)
+	}
+
+	prompt := sentPrompt(mock.lastReq)
+	for _, want := range []string{"[ac-1] Login is fast", "goals[0]: Be secure", "UNTRUSTED-SPEC"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+	if spec.AcceptanceCriteria[0].Description != "Login is fast" {
+		t.Error("review modified the spec")
+	}
+}
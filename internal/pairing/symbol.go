@@ -0,0 +1,54 @@
+package pairing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/analysis"
+)
+
+// buildSymbolContext renders the symbol the learner asked about: its
+// declaration and the lines that use it. All of it is the learner's code,
+// so it is fenced.
+func (p *Prompter) buildSymbolContext(f *fence, sym *analysis.Symbol) string {
+	var sb strings.Builder
+	sb.WriteString("## Symbol in Question\n\n")
+	fmt.Fprintf(&sb, "- Name: %s\n- Kind: %s\n", f.sanitize(sym.Name), sym.Kind)
+	if sym.Type != "" {
+		fmt.Fprintf(&sb, "- Type: %s\n", f.sanitize(sym.Type))
+	}
+	if sym.Package != "" {
+		fmt.Fprintf(&sb, "- Package: %s\n", f.sanitize(sym.Package))
+	}
+
+	if decl := sym.Declaration; decl != nil {
+		fmt.Fprintf(&sb, "\nDeclared at %s:%d:\n", f.sanitize(decl.File), decl.Line)
+		sb.WriteString(f.wrap("SYMBOL_DECLARATION", decl.Source))
+		sb.WriteString("\n")
+	}
+
+	if len(sym.Usages) > 0 {
+		sb.WriteString("\nUsed at:\n")
+		for _, use := range sym.Usages {
+			fmt.Fprintf(&sb, "- %s:%d: ", f.sanitize(use.File), use.Line)
+			sb.WriteString(f.wrap("SYMBOL_USAGE", use.Source))
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// symbolAddendum scopes the answer to the symbol
+func (p *Prompter) symbolAddendum(sym *analysis.Symbol) string {
+	var sb strings.Builder
+	sb.WriteString("\n\n### Symbol Scope\n")
+	fmt.Fprintf(&sb, "The learner selected %s and wants to understand it. ", sym.Name)
+	if sym.Declaration != nil {
+		sb.WriteString("Explain what its declaration says and what role it plays at the places it is used. ")
+	} else {
+		sb.WriteString("It is declared outside the learner's code; explain what it does and how the learner's code uses it. ")
+	}
+	sb.WriteString("Stay on this symbol: don't review the rest of the code or point at unrelated problems.\n")
+	return sb.String()
+}
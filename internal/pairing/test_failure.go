@@ -0,0 +1,107 @@
+package pairing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/analysis"
+	"github.com/felixgeelhaar/temper/internal/correlation"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+// maxTestOutput bounds how much of a test's output goes into the prompt
+const maxTestOutput = 2000
+
+// ExplainTestFailure explains why one failed test failed, from its output
+// and the code it exercises. Only that test and the functions it reaches
+// are in the prompt, not the whole run. packID and paths select the local
+// provider when local-only rules match the session.
+func (s *Service) ExplainTestFailure(ctx context.Context, packID string, paths []string, test domain.TestResult, scope *analysis.TestScope) (string, error) {
+	provider, err := s.provider(s.localOnly.Matches(packID, paths))
+	if err != nil {
+		return "", fmt.Errorf("get LLM provider: %w", err)
+	}
+	prompt, _ := s.redactPrompt(provider, s.prompter.BuildTestFailurePrompt(test, scope))
+
+	system := s.localize(s.prompter.TestFailureSystemPrompt())
+	resp, err := provider.Generate(ctx, &llm.Request{
+		Messages: []llm.Message{
+			{Role: llm.RoleUser, Content: prompt},
+		},
+		System: system,
+		SystemBlocks: []llm.SystemContentBlock{
+			{Text: system, CacheControl: true},
+		},
+		CorrelationID: correlation.FromContext(ctx),
+		MaxTokens:     maxInterventionTokens,
+		Temperature:   0.3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("generate test explanation: %w", err)
+	}
+
+	// Hold the answer to the explanation level: it may point at code but
+	// not write the fix
+	content := resp.Content
+	if s.clampValidator != nil && s.clampValidator.Validate(domain.L2LocationConcept, content) != nil {
+		content = s.clampValidator.Sanitize(domain.L2LocationConcept, content)
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// TestFailureSystemPrompt returns the system prompt for test failure
+// explanations
+func (p *Prompter) TestFailureSystemPrompt() string {
+	return `You are a programming tutor explaining why a test failed to the learner who wrote the code under test.
+
+Read the failure output, find the assertion that reported it, and follow the test's calls into the learner's functions. Explain in a short paragraph what the test expected, what the code produced instead, and which function and line the difference most likely comes from. Teach the idea behind the mismatch (an off-by-one bound, a nil map, a missing case) so the learner can recognize it next time.
+
+Never write the corrected code or name the exact change that fixes it: the learner fixes the test themselves. Stick to this one test; ignore anything else that may be wrong.`
+}
+
+// BuildTestFailurePrompt renders the failed test, its output and the code
+// it reaches
+func (p *Prompter) BuildTestFailurePrompt(test domain.TestResult, scope *analysis.TestScope) string {
+	f := newFence()
+	var sb strings.Builder
+	sb.WriteString(f.securityPreamble())
+
+	fmt.Fprintf(&sb, "## Failed Test: %s\n\n", f.sanitize(test.Name))
+	if test.Package != "" {
+		fmt.Fprintf(&sb, "Package: %s\n\n", f.sanitize(test.Package))
+	}
+	sb.WriteString("### Output\n\n")
+	sb.WriteString(f.wrap("TEST_OUTPUT", p.truncate(test.Output, maxTestOutput)))
+	sb.WriteString("\n\n")
+
+	if scope == nil {
+		sb.WriteString("The test's source could not be located in the submitted code; explain from the output alone.\n")
+		return sb.String()
+	}
+
+	if len(scope.Assertions) > 0 {
+		sb.WriteString("### Lines the Output Points At\n\n")
+		for _, a := range scope.Assertions {
+			fmt.Fprintf(&sb, "- %s:%d: ", f.sanitize(a.File), a.Line)
+			sb.WriteString(f.wrap("ASSERTION", a.Source))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	fmt.Fprintf(&sb, "### Test Source (%s:%d)\n\n", f.sanitize(scope.Test.File), scope.Test.Line)
+	sb.WriteString(f.wrap("TEST_SOURCE", scope.Test.Source))
+	sb.WriteString("\n\n")
+
+	if len(scope.Callees) > 0 {
+		sb.WriteString("### Code Under Test\n\nFunctions the test reaches, nearest first:\n\n")
+		for _, fn := range scope.Callees {
+			fmt.Fprintf(&sb, "#### %s (%s:%d)\n", f.sanitize(fn.Name), f.sanitize(fn.File), fn.Line)
+			sb.WriteString(f.wrap("USER_CODE", fn.Source))
+			sb.WriteString("\n\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,53 @@
+package pairing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/analysis"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+func TestPrompter_BuildTestFailurePrompt(t *testing.T) {
+	p := NewPrompter()
+	test := domain.TestResult{Name: "TestSum", Package: "sum", Output: "sum_test.go:7: got 1"}
+	scope := &analysis.TestScope{
+		Test:       analysis.Function{Name: "TestSum", Location: analysis.Location{File: "sum_test.go", Line: 5, Source: "func TestSum(t *testing.T) {}"}},
+		Assertions: []analysis.Location{{File: "sum_test.go", Line: 7, Source: `t.Errorf("got %d", got)`}},
+		Callees:    []analysis.Function{{Name: "Sum", Location: analysis.Location{File: "sum.go", Line: 3, Source: "func Sum(xs []int) int {}"}}},
+	}
+
+	prompt := p.BuildTestFailurePrompt(test, scope)
+	for _, want := range []string{"## Failed Test: TestSum", "TEST_OUTPUT", "sum_test.go:7: ", "ASSERTION",
+		"Test Source (sum_test.go:5)", "#### Sum (sum.go:3)", "func Sum(xs []int) int"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+
+	if bare := p.BuildTestFailurePrompt(test, nil); !strings.Contains(bare, "explain from the output alone") {
+		t.Error("a test without source should be explained from its output")
+	}
+}
+
+func TestService_ExplainTestFailure(t *testing.T) {
+	mock := &mockProvider{
+		name:     "test",
+		response: &llm.Response{Content: " The loop stops before the last element. "},
+	}
+	service := createTestService(mock)
+
+	got, err := service.ExplainTestFailure(context.Background(), "go-v1", []string{"sum.go"},
+		domain.TestResult{Name: "TestSum", Output: "got 1"}, nil)
+	if err != nil {
+		t.Fatalf("ExplainTestFailure() error = %v", err)
+	}
+	if got != "The loop stops before the last element." {
+		t.Errorf("got %q", got)
+	}
+	if !strings.Contains(mock.lastReq.System, "explaining why a test failed") {
+		t.Errorf("system prompt = %q", mock.lastReq.System)
+	}
+}
@@ -0,0 +1,74 @@
+package pairing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// TestFirstCeiling is the highest level a test-first session gets while
+// the feature has no failing test: where to look and which concept
+// applies, but no code
+const TestFirstCeiling = domain.L2LocationConcept
+
+// HeldBackFeature returns the feature whose implementation hints are
+// held back: the session is test-first, guides a feature, and no failing
+// test from its runs references that feature yet. Returns nil when hints
+// are not restricted.
+func HeldBackFeature(req InterventionRequest) *domain.Feature {
+	if !req.Policy.TestFirst || !req.Context.IsFeatureGuidance() {
+		return nil
+	}
+	feature := req.Context.GetCurrentFeature()
+	if feature == nil {
+		return nil
+	}
+	for _, name := range req.Context.FailingTests {
+		if feature.MatchesTest(name) {
+			return nil
+		}
+	}
+	return feature
+}
+
+// applyTestFirst caps the level when test-first holds back the feature's
+// implementation hints. Returns the feature it applied to, or nil.
+func applyTestFirst(level domain.InterventionLevel, req InterventionRequest) (domain.InterventionLevel, *domain.Feature) {
+	feature := HeldBackFeature(req)
+	if feature == nil {
+		return level, nil
+	}
+	return min(level, TestFirstCeiling), feature
+}
+
+// testFirstNote explains the cap in the intervention rationale
+func testFirstNote(feature *domain.Feature) string {
+	if feature == nil {
+		return ""
+	}
+	return fmt.Sprintf("; test-first: capped at L%d until a failing test references %s", TestFirstCeiling, feature.ID)
+}
+
+// testFirstAddendum steers the answer toward writing the failing test
+func (p *Prompter) testFirstAddendum(feature *domain.Feature, focus *domain.AcceptanceCriterion) string {
+	var sb strings.Builder
+	sb.WriteString("\n\n### Test First\n")
+	fmt.Fprintf(&sb, "This session practices test-driven development. No failing test references the feature %q yet, ", feature.Title)
+	sb.WriteString("so do not explain how to implement it. Help the learner write a test that fails until the feature works")
+	if focus != nil && !focus.Satisfied {
+		fmt.Fprintf(&sb, ", starting with: %s", focus.Description)
+	}
+	sb.WriteString(".\n")
+	fmt.Fprintf(&sb, "Suggest a test name containing %q so the test is linked to the feature.\n", testNameHint(feature))
+	return sb.String()
+}
+
+// testNameHint renders the feature ID as a Go test name fragment
+func testNameHint(feature *domain.Feature) string {
+	var sb strings.Builder
+	for _, part := range strings.FieldsFunc(feature.ID, func(r rune) bool { return r == '-' || r == '_' || r == ' ' }) {
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return "Test" + sb.String()
+}
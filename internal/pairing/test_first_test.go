@@ -0,0 +1,107 @@
+package pairing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/llm"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func testFirstRequest(failing ...string) InterventionRequest {
+	spec := &domain.ProductSpec{
+		Name: "Accounts",
+		Features: []domain.Feature{
+			{ID: "password-reset", Title: "Password reset", Priority: domain.PriorityHigh},
+		},
+		AcceptanceCriteria: []domain.AcceptanceCriterion{
+			{ID: "ac-001", Description: "Reset links expire after one hour"},
+		},
+	}
+	return InterventionRequest{
+		SessionID:     uuid.New(),
+		Intent:        domain.IntentStuck,
+		ExplicitLevel: domain.L4PartialSolution,
+		Justification: "Tried for an hour",
+		Policy:        domain.LearningPolicy{MaxLevel: domain.L3ConstrainedSnippet, TestFirst: true},
+		Context: InterventionContext{
+			SessionIntent:  session.IntentFeatureGuidance,
+			Spec:           spec,
+			FocusCriterion: &spec.AcceptanceCriteria[0],
+			FailingTests:   failing,
+		},
+	}
+}
+
+func TestHeldBackFeature(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*InterventionRequest)
+		wantNil bool
+	}{
+		{"no failing test", func(*InterventionRequest) {}, false},
+		{"unrelated failing test", func(r *InterventionRequest) { r.Context.FailingTests = []string{"TestLogin"} }, false},
+		{"failing test references feature", func(r *InterventionRequest) {
+			r.Context.FailingTests = []string{"TestPasswordReset/expired_link"}
+		}, true},
+		{"policy off", func(r *InterventionRequest) { r.Policy.TestFirst = false }, true},
+		{"not a feature session", func(r *InterventionRequest) { r.Context.SessionIntent = session.IntentTraining }, true},
+		{"all criteria satisfied", func(r *InterventionRequest) { r.Context.FocusCriterion = nil }, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := testFirstRequest()
+			tc.mutate(&req)
+			got := HeldBackFeature(req)
+			if (got == nil) != tc.wantNil {
+				t.Errorf("HeldBackFeature() = %v, wantNil %v", got, tc.wantNil)
+			}
+		})
+	}
+}
+
+func TestService_Intervene_TestFirstCapsEscalation(t *testing.T) {
+	mock := &mockProvider{
+		name:     "test",
+		response: &llm.Response{Content: "Start with a test that requests a reset link and checks its expiry."},
+	}
+	service := createTestService(mock)
+
+	intervention, err := service.Intervene(context.Background(), testFirstRequest())
+	if err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+	if intervention.Level != TestFirstCeiling {
+		t.Errorf("Level = %d, want %d", intervention.Level, TestFirstCeiling)
+	}
+	if !strings.Contains(intervention.Rationale, "test-first") {
+		t.Errorf("rationale should mention test-first, got: %s", intervention.Rationale)
+	}
+	prompt := mock.lastReq.Messages[len(mock.lastReq.Messages)-1].Content
+	for _, want := range []string{"### Test First", "TestPasswordReset", "Reset links expire after one hour"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+}
+
+func TestService_Intervene_TestFirstLiftedByFailingTest(t *testing.T) {
+	mock := &mockProvider{
+		name:     "test",
+		response: &llm.Response{Content: "Compare the token's issue time against the expiry window."},
+	}
+	service := createTestService(mock)
+
+	intervention, err := service.Intervene(context.Background(), testFirstRequest("TestPasswordReset_Expiry"))
+	if err != nil {
+		t.Fatalf("Intervene() error = %v", err)
+	}
+	if intervention.Level != domain.L4PartialSolution {
+		t.Errorf("Level = %d, want %d", intervention.Level, domain.L4PartialSolution)
+	}
+}
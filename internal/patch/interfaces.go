@@ -23,9 +23,26 @@ type PatchService interface {
 	// GetSessionPatches returns all patches for a session
 	GetSessionPatches(sessionID uuid.UUID) []*domain.Patch
 
-	// GetLogger returns the logger (may be nil if logging is disabled)
-	GetLogger() *Logger
+	// GetLogger returns the audit log (may be nil if logging is disabled)
+	GetLogger() AuditLog
+}
+
+// AuditLog is where patch actions are recorded. Logger keeps them in a
+// file; a shared daemon can keep them in its database instead.
+type AuditLog interface {
+	Log(action LogAction, patch *domain.Patch) error
+	GetEntries() []LogEntry
+	GetRecentEntries(n int) []LogEntry
+	GetSessionEntries(sessionID string) []LogEntry
+	GetStats() LogStats
+
+	// Forget removes the entries of the given sessions, or every entry
+	// when sessionIDs is nil, and returns how many it removed
+	Forget(sessionIDs map[string]bool) (int, error)
 }
 
 // Ensure Service implements PatchService
 var _ PatchService = (*Service)(nil)
+
+// Ensure Logger implements AuditLog
+var _ AuditLog = (*Logger)(nil)
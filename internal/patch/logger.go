@@ -136,6 +136,38 @@ func (l *Logger) GetSessionEntries(sessionID string) []LogEntry {
 	return entries
 }
 
+// Forget removes the entries of the given sessions, or every entry when
+// sessionIDs is nil, rewriting the log. It returns how many it removed.
+func (l *Logger) Forget(sessionIDs map[string]bool) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	kept := make([]LogEntry, 0, len(l.entries))
+	for _, e := range l.entries {
+		if sessionIDs != nil && !sessionIDs[e.SessionID] {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(l.entries) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	var buf []byte
+	for _, e := range kept {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return 0, err
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+	if err := os.WriteFile(l.logPath, buf, 0644); err != nil {
+		return 0, err
+	}
+	l.entries = kept
+	return removed, nil
+}
+
 // GetStats returns summary statistics
 func (l *Logger) GetStats() LogStats {
 	l.mu.Lock()
@@ -3,6 +3,7 @@ package patch
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/felixgeelhaar/temper/internal/domain"
@@ -318,3 +319,31 @@ func TestNewLineReader(t *testing.T) {
 		}
 	}
 }
+
+func TestLogger_Forget(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger, _ := NewLogger(tmpDir)
+
+	erased, kept := uuid.New(), uuid.New()
+	for _, sessionID := range []uuid.UUID{erased, kept, erased} {
+		_ = logger.Log(LogActionCreated, &domain.Patch{ID: uuid.New(), SessionID: sessionID, InterventionID: uuid.New()})
+	}
+
+	removed, err := logger.Forget(map[string]bool{erased.String(): true})
+	if err != nil || removed != 2 {
+		t.Fatalf("Forget() = %d, %v; want 2 removed", removed, err)
+	}
+
+	entries := logger.GetEntries()
+	if len(entries) != 1 || entries[0].SessionID != kept.String() {
+		t.Errorf("entries = %+v; want only the kept session's", entries)
+	}
+	data, _ := os.ReadFile(filepath.Join(tmpDir, "patches.log"))
+	if strings.Count(string(data), "\n") != 1 || !strings.Contains(string(data), kept.String()) {
+		t.Errorf("log file = %q; want the kept entry only", data)
+	}
+
+	if removed, _ := logger.Forget(nil); removed != 1 {
+		t.Errorf("Forget(nil) removed %d; want every entry", removed)
+	}
+}
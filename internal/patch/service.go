@@ -20,7 +20,7 @@ var (
 // Service manages patch lifecycle
 type Service struct {
 	extractor *Extractor
-	logger    *Logger
+	logger    AuditLog
 	mu        sync.RWMutex
 	patches   map[uuid.UUID]*domain.Patch   // patchID -> patch
 	sessions  map[uuid.UUID][]*domain.Patch // sessionID -> patches
@@ -53,13 +53,13 @@ func NewServiceWithLogger(logDir string) (*Service, error) {
 	}, nil
 }
 
-// SetLogger sets the logger for the service
-func (s *Service) SetLogger(logger *Logger) {
+// SetLogger sets the audit log for the service
+func (s *Service) SetLogger(logger AuditLog) {
 	s.logger = logger
 }
 
-// GetLogger returns the logger (may be nil if logging is disabled)
-func (s *Service) GetLogger() *Logger {
+// GetLogger returns the audit log (may be nil if logging is disabled)
+func (s *Service) GetLogger() AuditLog {
 	return s.logger
 }
 
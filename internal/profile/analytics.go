@@ -2,8 +2,13 @@ package profile
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"sort"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // AnalyticsOverview provides aggregate statistics
@@ -29,6 +34,7 @@ type TopicStat struct {
 
 // SkillBreakdown provides detailed skill analytics
 type SkillBreakdown struct {
+	Model       string                    `json:"model"` // skill model that computed the levels
 	Skills      map[string]SkillAnalytics `json:"skills"`
 	Progression []ProgressPoint           `json:"progression"`
 }
@@ -104,6 +110,7 @@ func (s *Service) GetSkillBreakdown(ctx context.Context) (*SkillBreakdown, error
 	}
 
 	breakdown := &SkillBreakdown{
+		Model:       s.model.Name(),
 		Skills:      make(map[string]SkillAnalytics),
 		Progression: []ProgressPoint{},
 	}
@@ -289,3 +296,202 @@ func formatDuration(d time.Duration) string {
 	}
 	return d.Round(time.Minute).String()
 }
+
+// SnapshotVersion is the format version of a SkillSnapshot
+const SnapshotVersion = 1
+
+// MinCompareGroup is the fewest learners a distribution is shown for, so
+// no single learner's numbers can be read off a comparison
+const MinCompareGroup = 3
+
+// ErrInvalidSnapshot is returned for a snapshot that cannot be compared
+var ErrInvalidSnapshot = errors.New("invalid skill snapshot")
+
+// SkillSnapshot is an anonymized copy of a learner's skill levels to share
+// with a study group. It carries no profile ID, exercise IDs, session IDs,
+// error text or timestamps finer than a day.
+type SkillSnapshot struct {
+	Version        int                `json:"version"`
+	ID             string             `json:"id"` // random, to spot the same file passed twice
+	TakenOn        string             `json:"taken_on"`
+	Skills         map[string]float64 `json:"skills"` // topic -> level, 0.0-1.0
+	Completed      int                `json:"exercises_completed"`
+	HintDependency float64            `json:"hint_dependency"`
+}
+
+// NewSnapshot takes an anonymized snapshot of a profile. Topics never
+// attempted are left out.
+func NewSnapshot(p *StoredProfile, now time.Time) *SkillSnapshot {
+	snap := &SkillSnapshot{
+		Version: SnapshotVersion,
+		ID:      uuid.NewString(),
+		TakenOn: now.Format(time.DateOnly),
+		Skills:  make(map[string]float64),
+	}
+	for topic, skill := range p.TopicSkills {
+		if skill.Attempts > 0 {
+			snap.Skills[topic] = roundLevel(skill.Level)
+		}
+	}
+
+	completed := make(map[string]bool)
+	for _, a := range p.ExerciseHistory {
+		if a.Success && a.CompletedAt != nil {
+			completed[a.ExerciseID] = true
+		}
+	}
+	snap.Completed = len(completed)
+
+	if p.TotalRuns > 0 {
+		snap.HintDependency = roundLevel(min(1.0, float64(p.HintRequests)/float64(p.TotalRuns)))
+	}
+	return snap
+}
+
+// Validate checks a snapshot read from a file
+func (s *SkillSnapshot) Validate() error {
+	if s.Version != SnapshotVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, s.Version)
+	}
+	if s.ID == "" {
+		return fmt.Errorf("%w: missing id", ErrInvalidSnapshot)
+	}
+	for topic, level := range s.Skills {
+		if level < 0 || level > 1 || math.IsNaN(level) {
+			return fmt.Errorf("%w: level of %s out of range", ErrInvalidSnapshot, topic)
+		}
+	}
+	if s.Completed < 0 || s.HintDependency < 0 || s.HintDependency > 1 {
+		return fmt.Errorf("%w: totals out of range", ErrInvalidSnapshot)
+	}
+	return nil
+}
+
+// Distribution summarizes one measure across a group. You and Percentile
+// are set when a learner was compared with the group.
+type Distribution struct {
+	Learners   int      `json:"learners"`
+	P25        float64  `json:"p25"`
+	Median     float64  `json:"median"`
+	P75        float64  `json:"p75"`
+	You        *float64 `json:"you,omitempty"`
+	Percentile *int     `json:"percentile,omitempty"` // share of the group at or below you, 0-100
+}
+
+// TopicDistribution is the distribution of one topic's skill level
+type TopicDistribution struct {
+	Topic string `json:"topic"`
+	Distribution
+}
+
+// SnapshotComparison is how a study group's skills are distributed
+type SnapshotComparison struct {
+	Learners       int                 `json:"learners"`
+	Completed      Distribution        `json:"exercises_completed"`
+	HintDependency Distribution        `json:"hint_dependency"`
+	Topics         []TopicDistribution `json:"topics"`
+	Withheld       int                 `json:"withheld_topics"` // practiced by too few learners to show
+}
+
+// CompareSnapshots aggregates a group's snapshots into distributions and,
+// when you is not nil, places you within each. Snapshots passed more than
+// once count once. Topics fewer than MinCompareGroup learners practiced are
+// withheld, and a group smaller than that is an error.
+func CompareSnapshots(snaps []SkillSnapshot, you *SkillSnapshot) (*SnapshotComparison, error) {
+	seen := make(map[string]bool)
+	var group []SkillSnapshot
+	for _, s := range snaps {
+		if err := s.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[s.ID] || (you != nil && s.ID == you.ID) {
+			continue
+		}
+		seen[s.ID] = true
+		group = append(group, s)
+	}
+	if len(group) < MinCompareGroup {
+		return nil, fmt.Errorf("%w: need snapshots from at least %d learners, got %d", ErrInvalidSnapshot, MinCompareGroup, len(group))
+	}
+
+	cmp := &SnapshotComparison{Learners: len(group), Topics: []TopicDistribution{}}
+
+	completed := make([]float64, len(group))
+	hints := make([]float64, len(group))
+	levels := make(map[string][]float64)
+	for i, s := range group {
+		completed[i] = float64(s.Completed)
+		hints[i] = s.HintDependency
+		for topic, level := range s.Skills {
+			levels[topic] = append(levels[topic], level)
+		}
+	}
+
+	var yourCompleted, yourHints *float64
+	if you != nil {
+		c, h := float64(you.Completed), you.HintDependency
+		yourCompleted, yourHints = &c, &h
+	}
+	cmp.Completed = distribution(completed, yourCompleted)
+	cmp.HintDependency = distribution(hints, yourHints)
+
+	for topic, values := range levels {
+		if len(values) < MinCompareGroup {
+			cmp.Withheld++
+			continue
+		}
+		var yours *float64
+		if you != nil {
+			if level, ok := you.Skills[topic]; ok {
+				yours = &level
+			}
+		}
+		cmp.Topics = append(cmp.Topics, TopicDistribution{Topic: topic, Distribution: distribution(values, yours)})
+	}
+	sort.Slice(cmp.Topics, func(i, j int) bool {
+		a, b := cmp.Topics[i], cmp.Topics[j]
+		if a.Learners != b.Learners {
+			return a.Learners > b.Learners
+		}
+		return a.Topic < b.Topic
+	})
+
+	return cmp, nil
+}
+
+// distribution summarizes values, placing you among them when not nil
+func distribution(values []float64, you *float64) Distribution {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	d := Distribution{
+		Learners: len(sorted),
+		P25:      roundLevel(quantile(sorted, 0.25)),
+		Median:   roundLevel(quantile(sorted, 0.5)),
+		P75:      roundLevel(quantile(sorted, 0.75)),
+	}
+	if you != nil {
+		atOrBelow := sort.Search(len(sorted), func(i int) bool { return sorted[i] > *you })
+		pct := atOrBelow * 100 / len(sorted)
+		d.You, d.Percentile = you, &pct
+	}
+	return d
+}
+
+// quantile interpolates the q-th quantile of sorted values
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := q * float64(len(sorted)-1)
+	lo := int(pos)
+	if lo+1 >= len(sorted) {
+		return sorted[lo]
+	}
+	return sorted[lo] + (pos-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
+
+// roundLevel keeps two decimals, which is all a comparison needs
+func roundLevel(f float64) float64 {
+	return math.Round(f*100) / 100
+}
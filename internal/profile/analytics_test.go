@@ -2,6 +2,9 @@ package profile
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -146,3 +149,91 @@ func TestFormatDuration(t *testing.T) {
 		t.Error("formatDuration() should format hours")
 	}
 }
+
+func TestNewSnapshot(t *testing.T) {
+	now := time.Date(2026, 5, 20, 14, 30, 0, 0, time.UTC)
+	done := now.Add(-time.Hour)
+	p := &StoredProfile{
+		ID: "secret-profile-id",
+		TopicSkills: map[string]StoredSkill{
+			"go/basics": {Level: 0.6666, Attempts: 3},
+			"go/errors": {Level: 0, Attempts: 0},
+		},
+		ExerciseHistory: []ExerciseAttempt{
+			{ExerciseID: "go-v1/basics/hello", SessionID: "secret-session", CompletedAt: &done, Success: true},
+			{ExerciseID: "go-v1/basics/hello", CompletedAt: &done, Success: true},
+			{ExerciseID: "go-v1/basics/maps"},
+		},
+		ErrorPatterns: map[string]int{"secret error": 2},
+		TotalRuns:     8,
+		HintRequests:  2,
+	}
+
+	snap := NewSnapshot(p, now)
+
+	if snap.Version != SnapshotVersion || snap.ID == "" || snap.TakenOn != "2026-05-20" {
+		t.Errorf("header = %d %q %q", snap.Version, snap.ID, snap.TakenOn)
+	}
+	if len(snap.Skills) != 1 || snap.Skills["go/basics"] != 0.67 {
+		t.Errorf("Skills = %v, want only go/basics rounded to 0.67", snap.Skills)
+	}
+	if snap.Completed != 1 || snap.HintDependency != 0.25 {
+		t.Errorf("Completed = %d, HintDependency = %v; want 1, 0.25", snap.Completed, snap.HintDependency)
+	}
+
+	data, _ := json.Marshal(snap)
+	for _, leak := range []string{"secret", "hello", "14:30"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("snapshot contains %q: %s", leak, data)
+		}
+	}
+}
+
+func TestCompareSnapshots(t *testing.T) {
+	snap := func(id string, completed int, skills map[string]float64) SkillSnapshot {
+		return SkillSnapshot{Version: SnapshotVersion, ID: id, Completed: completed, Skills: skills}
+	}
+	group := []SkillSnapshot{
+		snap("a", 2, map[string]float64{"go/basics": 0.2, "go/errors": 0.9}),
+		snap("b", 4, map[string]float64{"go/basics": 0.4}),
+		snap("c", 6, map[string]float64{"go/basics": 0.6}),
+		snap("d", 8, map[string]float64{"go/basics": 0.8, "go/errors": 0.1}),
+		snap("d", 8, map[string]float64{"go/basics": 0.8, "go/errors": 0.1}), // same file twice
+	}
+	you := snap("you", 5, map[string]float64{"go/basics": 0.6})
+
+	cmp, err := CompareSnapshots(group, &you)
+	if err != nil {
+		t.Fatalf("CompareSnapshots() error = %v", err)
+	}
+
+	if cmp.Learners != 4 || cmp.Withheld != 1 || len(cmp.Topics) != 1 {
+		t.Fatalf("got %d learners, %d withheld, topics %+v; want 4, 1 and go/basics only", cmp.Learners, cmp.Withheld, cmp.Topics)
+	}
+	basics := cmp.Topics[0]
+	if basics.Topic != "go/basics" || basics.P25 != 0.35 || basics.Median != 0.5 || basics.P75 != 0.65 {
+		t.Errorf("go/basics = %+v, want quartiles 0.35, 0.5, 0.65", basics.Distribution)
+	}
+	if basics.Percentile == nil || *basics.Percentile != 75 {
+		t.Errorf("go/basics percentile = %v, want 75", basics.Percentile)
+	}
+	if cmp.Completed.Median != 5 || cmp.Completed.Percentile == nil || *cmp.Completed.Percentile != 50 {
+		t.Errorf("Completed = %+v, want median 5 at the 50th percentile", cmp.Completed)
+	}
+}
+
+func TestCompareSnapshots_TooFewLearners(t *testing.T) {
+	group := []SkillSnapshot{
+		{Version: SnapshotVersion, ID: "a"},
+		{Version: SnapshotVersion, ID: "b"},
+		{Version: SnapshotVersion, ID: "a"},
+	}
+	if _, err := CompareSnapshots(group, nil); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("CompareSnapshots() error = %v, want ErrInvalidSnapshot", err)
+	}
+
+	group = append(group, SkillSnapshot{Version: 2, ID: "c"})
+	if _, err := CompareSnapshots(group, nil); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("CompareSnapshots() error = %v, want an unsupported version", err)
+	}
+}
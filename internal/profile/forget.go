@@ -0,0 +1,114 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ForgetRequest describes history being erased, so the profile can drop
+// what it learned from it
+type ForgetRequest struct {
+	Sessions []SessionInfo
+	Runs     map[string][]RunInfo // by session ID
+
+	// Topic drops the topic's skills as well; skill levels can't be
+	// unwound session by session, so other erasures leave them alone
+	Topic string
+	// Since and Until drop the hint trend points in [Since, Until); zero
+	// leaves that end open. Both zero keeps the trend.
+	Since, Until time.Time
+}
+
+// InTopic reports whether a topic such as "go/basics" falls under topic,
+// which is either the same topic or its language ("go")
+func InTopic(t, topic string) bool {
+	return t == topic || strings.HasPrefix(t, topic+"/")
+}
+
+// Forget subtracts erased sessions and their runs from the profile's
+// counters, history and error patterns. Queued updates are applied
+// first, so what they add is subtracted too.
+func (s *Service) Forget(ctx context.Context, req ForgetRequest) error {
+	if _, err := s.retryQueued(); err != nil {
+		return fmt.Errorf("apply queued profile updates: %w", err)
+	}
+
+	profile, err := s.store.GetDefault()
+	if err != nil {
+		return err
+	}
+
+	forgotten := make(map[string]bool, len(req.Sessions))
+	for _, sess := range req.Sessions {
+		forgotten[sess.ID] = true
+		profile.TotalSessions--
+		if sess.Status == "completed" {
+			profile.CompletedSessions--
+			profile.TotalExercises--
+		}
+		profile.HintRequests -= sess.HintCount
+
+		for _, run := range req.Runs[sess.ID] {
+			profile.TotalRuns--
+			if run.Success {
+				continue
+			}
+			for _, p := range ExtractErrorPatterns(run.BuildOutput, run.TestOutput) {
+				if profile.ErrorPatterns[p]--; profile.ErrorPatterns[p] <= 0 {
+					delete(profile.ErrorPatterns, p)
+				}
+			}
+		}
+	}
+	profile.TotalSessions = max(profile.TotalSessions, 0)
+	profile.CompletedSessions = max(profile.CompletedSessions, 0)
+	profile.TotalExercises = max(profile.TotalExercises, 0)
+	profile.HintRequests = max(profile.HintRequests, 0)
+	profile.TotalRuns = max(profile.TotalRuns, 0)
+
+	history := profile.ExerciseHistory[:0]
+	for _, attempt := range profile.ExerciseHistory {
+		if !forgotten[attempt.SessionID] {
+			history = append(history, attempt)
+		}
+	}
+	profile.ExerciseHistory = history
+
+	if req.Topic != "" {
+		for topic := range profile.TopicSkills {
+			if InTopic(topic, req.Topic) {
+				delete(profile.TopicSkills, topic)
+			}
+		}
+	}
+
+	if !req.Since.IsZero() || !req.Until.IsZero() {
+		trend := profile.HintDependencyTrend[:0]
+		for _, point := range profile.HintDependencyTrend {
+			inRange := !point.Timestamp.Before(req.Since) && (req.Until.IsZero() || point.Timestamp.Before(req.Until))
+			if !inRange {
+				trend = append(trend, point)
+			}
+		}
+		profile.HintDependencyTrend = trend
+	}
+
+	return s.store.Save(profile)
+}
+
+// Reset deletes the profile, and any updates queued for it; the next
+// read starts a fresh one
+func (s *Service) Reset(ctx context.Context) error {
+	if s.queue != nil {
+		if err := s.queue.clear(); err != nil {
+			return fmt.Errorf("clear queued profile updates: %w", err)
+		}
+	}
+	if err := s.store.Delete(defaultProfileID); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
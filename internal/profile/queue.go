@@ -0,0 +1,238 @@
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/storage/local"
+)
+
+// collectionQueue holds profile updates waiting to be retried
+const collectionQueue = "profile-queue"
+
+// Kinds of profile update, one per session event
+const (
+	updateSessionStart    = "session_start"
+	updateSessionComplete = "session_complete"
+	updateRunComplete     = "run_complete"
+	updateHintDelivered   = "hint_delivered"
+)
+
+// Retries back off from retryBackoffMin, doubling up to retryBackoffMax
+// while the store keeps failing
+const (
+	retryBackoffMin = time.Second
+	retryBackoffMax = 5 * time.Minute
+)
+
+// update is a profile change from a session event. It carries the time
+// of the event so a retried update lands as if it had never failed.
+type update struct {
+	Kind     string      `json:"kind"`
+	Session  SessionInfo `json:"session"`
+	Run      *RunInfo    `json:"run,omitempty"`
+	At       time.Time   `json:"at"`
+	Attempts int         `json:"attempts,omitempty"`
+}
+
+// updateQueue keeps updates the profile store failed to take on disk,
+// in their own store so the profile store being down doesn't take the
+// queue with it. Updates are applied in the order they happened: while
+// any are queued, new ones queue behind them.
+type updateQueue struct {
+	mu      sync.Mutex // held while applying, so updates don't overtake each other
+	store   *local.Store
+	pending int
+	seq     int
+	wake    chan struct{}
+}
+
+// EnableRetryQueue queues profile updates the store fails to take under
+// basePath instead of dropping them; StartRetryLoop applies them once
+// the store is back. Updates queued by an earlier run are picked up.
+func (s *Service) EnableRetryQueue(basePath string) error {
+	store, err := local.NewStore(basePath)
+	if err != nil {
+		return err
+	}
+	ids, err := store.List(collectionQueue)
+	if err != nil {
+		return fmt.Errorf("list queued profile updates: %w", err)
+	}
+	s.queue = &updateQueue{store: store, pending: len(ids), wake: make(chan struct{}, 1)}
+	return nil
+}
+
+// QueuedUpdates returns how many profile updates are waiting to be retried
+func (s *Service) QueuedUpdates() int {
+	if s.queue == nil {
+		return 0
+	}
+	s.queue.mu.Lock()
+	defer s.queue.mu.Unlock()
+	return s.queue.pending
+}
+
+// submit applies an update, queueing it when the store fails or earlier
+// updates are still queued. It only fails when the update is lost.
+func (s *Service) submit(u update) error {
+	q := s.queue
+	if q == nil {
+		return s.apply(u)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.pending > 0 {
+		return q.push(u)
+	}
+	err := s.apply(u)
+	if err == nil {
+		return nil
+	}
+	if qerr := q.push(u); qerr != nil {
+		return fmt.Errorf("%w (queue for retry: %v)", err, qerr)
+	}
+	slog.Warn("profile update queued for retry", "kind", u.Kind, "session", u.Session.ID, "error", err)
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// push queues an update behind the others; the caller holds q.mu
+func (q *updateQueue) push(u update) error {
+	// IDs sort in the order the updates happened
+	q.seq++
+	id := fmt.Sprintf("%020d-%06d", u.At.UnixNano(), q.seq%1_000_000)
+	if err := q.store.Save(collectionQueue, id, u); err != nil {
+		return err
+	}
+	q.pending++
+	return nil
+}
+
+// retryQueued applies queued updates oldest first and returns how many
+// it applied. It stops at the first that fails, so later updates don't
+// overtake it.
+func (s *Service) retryQueued() (int, error) {
+	q := s.queue
+	if q == nil {
+		return 0, nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ids, err := q.store.List(collectionQueue)
+	if err != nil {
+		return 0, err
+	}
+	q.pending = len(ids)
+
+	applied := 0
+	for _, id := range ids {
+		var u update
+		if err := q.store.Load(collectionQueue, id, &u); err != nil {
+			if !corrupt(err) {
+				return applied, err
+			}
+			// Left half-written by a crash; retrying won't help
+			slog.Warn("dropping unreadable profile update", "id", id, "error", err)
+		} else if err := s.apply(u); err != nil {
+			u.Attempts++
+			if serr := q.store.Save(collectionQueue, id, u); serr != nil {
+				slog.Debug("count profile update attempt", "id", id, "error", serr)
+			}
+			return applied, err
+		} else {
+			applied++
+		}
+		if err := q.store.Delete(collectionQueue, id); err != nil && !errors.Is(err, local.ErrNotFound) {
+			return applied, err
+		}
+		q.pending--
+	}
+	return applied, nil
+}
+
+// clear drops every queued update
+func (q *updateQueue) clear() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ids, err := q.store.List(collectionQueue)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := q.store.Delete(collectionQueue, id); err != nil && !errors.Is(err, local.ErrNotFound) {
+			return err
+		}
+	}
+	q.pending = 0
+	return nil
+}
+
+// StartRetryLoop retries queued profile updates until ctx is done,
+// backing off while the store keeps failing. Updates left by an earlier
+// run are retried right away.
+func (s *Service) StartRetryLoop(ctx context.Context) {
+	q := s.queue
+	if q == nil {
+		return
+	}
+
+	go func() {
+		backoff := retryBackoffMin
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+		armed := true
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.wake:
+				// A new failure; give the store a moment before retrying
+				if !armed {
+					timer.Reset(backoff)
+					armed = true
+				}
+				continue
+			case <-timer.C:
+				armed = false
+			}
+
+			applied, err := s.retryQueued()
+			if applied > 0 {
+				slog.Info("queued profile updates applied", "updates", applied)
+			}
+			if err != nil {
+				slog.Warn("retry profile updates", "pending", s.QueuedUpdates(), "retry_in", backoff, "error", err)
+				timer.Reset(backoff)
+				armed = true
+				backoff = min(backoff*2, retryBackoffMax)
+				continue
+			}
+			backoff = retryBackoffMin
+		}
+	}()
+}
+
+// corrupt reports whether a queued update failed to load because its
+// file is damaged rather than because the disk is unavailable
+func corrupt(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
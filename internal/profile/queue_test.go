@@ -0,0 +1,143 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// flakyStore fails every call while down, like a locked or full disk
+type flakyStore struct {
+	ProfileStore
+	down bool
+}
+
+var errStoreDown = errors.New("store unavailable")
+
+func (s *flakyStore) GetDefault() (*StoredProfile, error) {
+	if s.down {
+		return nil, errStoreDown
+	}
+	return s.ProfileStore.GetDefault()
+}
+
+func (s *flakyStore) Save(p *StoredProfile) error {
+	if s.down {
+		return errStoreDown
+	}
+	return s.ProfileStore.Save(p)
+}
+
+func setupQueuedService(t *testing.T) (*Service, *flakyStore, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := NewStore(filepath.Join(dir, "profiles"))
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	flaky := &flakyStore{ProfileStore: store}
+	service := NewService(flaky)
+	if err := service.EnableRetryQueue(dir); err != nil {
+		t.Fatalf("EnableRetryQueue() error = %v", err)
+	}
+	return service, flaky, dir
+}
+
+func TestService_QueuesFailedUpdates(t *testing.T) {
+	ctx := context.Background()
+	service, store, dir := setupQueuedService(t)
+	sess := SessionInfo{ID: "s1", ExerciseID: "go-v1/basics/hello", CreatedAt: time.Now().Add(-time.Hour)}
+
+	store.down = true
+	if err := service.OnSessionStart(ctx, sess); err != nil {
+		t.Fatalf("OnSessionStart() error = %v; a failed write should be queued", err)
+	}
+	if err := service.OnRunComplete(ctx, sess, RunInfo{Success: true, Duration: time.Second}); err != nil {
+		t.Fatalf("OnRunComplete() error = %v", err)
+	}
+	sess.Status = "completed"
+	if err := service.OnSessionComplete(ctx, sess); err != nil {
+		t.Fatalf("OnSessionComplete() error = %v", err)
+	}
+	completedBy := time.Now()
+	if n := service.QueuedUpdates(); n != 3 {
+		t.Fatalf("QueuedUpdates() = %d, want 3", n)
+	}
+
+	// Still down: nothing is applied or lost
+	if applied, err := service.retryQueued(); applied != 0 || !errors.Is(err, errStoreDown) {
+		t.Fatalf("retryQueued() = %d, %v; want the store error", applied, err)
+	}
+
+	// The queue survives a restart
+	store.down = false
+	restarted := NewService(store)
+	if err := restarted.EnableRetryQueue(dir); err != nil {
+		t.Fatalf("EnableRetryQueue() error = %v", err)
+	}
+	if n := restarted.QueuedUpdates(); n != 3 {
+		t.Fatalf("QueuedUpdates() after restart = %d, want 3", n)
+	}
+
+	// Updates arriving meanwhile wait behind the queued ones
+	if err := restarted.OnHintDelivered(ctx, sess); err != nil {
+		t.Fatalf("OnHintDelivered() error = %v", err)
+	}
+	if applied, err := restarted.retryQueued(); applied != 4 || err != nil {
+		t.Fatalf("retryQueued() = %d, %v; want 4 applied", applied, err)
+	}
+	if n := restarted.QueuedUpdates(); n != 0 {
+		t.Errorf("QueuedUpdates() = %d after the retry, want 0", n)
+	}
+
+	profile, err := restarted.GetProfile(ctx)
+	if err != nil {
+		t.Fatalf("GetProfile() error = %v", err)
+	}
+	if profile.TotalSessions != 1 || profile.TotalRuns != 1 || profile.CompletedSessions != 1 || profile.HintRequests != 1 {
+		t.Errorf("profile = %+v, want every update applied once", profile)
+	}
+	got := profile.ExerciseHistory[0].CompletedAt
+	if got == nil || got.After(completedBy) {
+		t.Errorf("CompletedAt = %v, want the time the session completed, not the retry", got)
+	}
+}
+
+func TestService_RetryDropsCorruptUpdates(t *testing.T) {
+	service, store, dir := setupQueuedService(t)
+	store.down = true
+	if err := service.OnHintDelivered(context.Background(), SessionInfo{ID: "s1"}); err != nil {
+		t.Fatalf("OnHintDelivered() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, collectionQueue, "00000000000000000000-000000.json"), []byte(`{"kind":`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store.down = false
+	if applied, err := service.retryQueued(); applied != 1 || err != nil {
+		t.Errorf("retryQueued() = %d, %v; want the readable update applied", applied, err)
+	}
+	if n := service.QueuedUpdates(); n != 0 {
+		t.Errorf("QueuedUpdates() = %d, want 0", n)
+	}
+}
+
+func TestService_ResetClearsQueue(t *testing.T) {
+	ctx := context.Background()
+	service, store, _ := setupQueuedService(t)
+	store.down = true
+	if err := service.OnSessionStart(ctx, SessionInfo{ID: "s1"}); err != nil {
+		t.Fatalf("OnSessionStart() error = %v", err)
+	}
+
+	store.down = false
+	if err := service.Reset(ctx); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if n := service.QueuedUpdates(); n != 0 {
+		t.Errorf("QueuedUpdates() = %d after Reset, want 0", n)
+	}
+}
@@ -0,0 +1,119 @@
+package profile
+
+import (
+	"sort"
+	"time"
+)
+
+// reviewIntervals is the spaced-repetition ladder. Each exercise finished
+// green without a hint moves one step up; a hinted or failed attempt drops
+// it back to the first step.
+var reviewIntervals = []time.Duration{
+	1 * 24 * time.Hour,
+	3 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+	14 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+	60 * 24 * time.Hour,
+}
+
+// ReviewInterval returns the wait before the next review after step
+// consecutive clean reviews, clamped to the top of the ladder. Flashcards
+// share the ladder with exercises.
+func ReviewInterval(step int) time.Duration {
+	return reviewIntervals[min(max(step, 0), len(reviewIntervals)-1)]
+}
+
+// ReviewItem is an exercise in the spaced-repetition queue
+type ReviewItem struct {
+	ExerciseID    string    `json:"exercise_id"`
+	LastPracticed time.Time `json:"last_practiced"`
+	DueAt         time.Time `json:"due_at"`
+	IntervalDays  int       `json:"interval_days"`
+}
+
+// ReviewQueue schedules every attempted exercise for review, soonest first
+func ReviewQueue(history []ExerciseAttempt) []ReviewItem {
+	byExercise := make(map[string][]ExerciseAttempt)
+	for _, a := range history {
+		if a.ExerciseID == "" || (!a.Success && a.CompletedAt == nil) {
+			continue // still in progress
+		}
+		byExercise[a.ExerciseID] = append(byExercise[a.ExerciseID], a)
+	}
+
+	queue := make([]ReviewItem, 0, len(byExercise))
+	for id, attempts := range byExercise {
+		sort.Slice(attempts, func(i, j int) bool {
+			return attemptTime(attempts[i]).Before(attemptTime(attempts[j]))
+		})
+
+		step := -1
+		for _, a := range attempts {
+			if a.Success && a.HintCount == 0 {
+				step = min(step+1, len(reviewIntervals)-1)
+			} else {
+				step = 0
+			}
+		}
+		step = max(step, 0)
+
+		last := attemptTime(attempts[len(attempts)-1])
+		interval := reviewIntervals[step]
+		queue = append(queue, ReviewItem{
+			ExerciseID:    id,
+			LastPracticed: last,
+			DueAt:         last.Add(interval),
+			IntervalDays:  int(interval / (24 * time.Hour)),
+		})
+	}
+
+	sort.Slice(queue, func(i, j int) bool {
+		if queue[i].DueAt.Equal(queue[j].DueAt) {
+			return queue[i].ExerciseID < queue[j].ExerciseID
+		}
+		return queue[i].DueAt.Before(queue[j].DueAt)
+	})
+	return queue
+}
+
+// DueReviews returns the queued exercises due at or before now
+func DueReviews(history []ExerciseAttempt, now time.Time) []ReviewItem {
+	due := []ReviewItem{}
+	for _, item := range ReviewQueue(history) {
+		if item.DueAt.After(now) {
+			break
+		}
+		due = append(due, item)
+	}
+	return due
+}
+
+// PracticeStreak counts consecutive days, ending today or yesterday, with
+// at least one exercise attempt, and reports whether today is one of them
+func PracticeStreak(history []ExerciseAttempt, now time.Time) (days int, today bool) {
+	practiced := make(map[string]bool)
+	for _, a := range history {
+		practiced[attemptTime(a).In(now.Location()).Format(time.DateOnly)] = true
+		practiced[a.StartedAt.In(now.Location()).Format(time.DateOnly)] = true
+	}
+
+	day := now
+	today = practiced[day.Format(time.DateOnly)]
+	if !today {
+		day = day.AddDate(0, 0, -1)
+	}
+	for practiced[day.Format(time.DateOnly)] {
+		days++
+		day = day.AddDate(0, 0, -1)
+	}
+	return days, today
+}
+
+// attemptTime is when an attempt finished, or started if it never did
+func attemptTime(a ExerciseAttempt) time.Time {
+	if a.CompletedAt != nil {
+		return *a.CompletedAt
+	}
+	return a.StartedAt
+}
@@ -0,0 +1,72 @@
+package profile
+
+import (
+	"testing"
+	"time"
+)
+
+func attempt(id string, at time.Time, success bool, hints int) ExerciseAttempt {
+	return ExerciseAttempt{ExerciseID: id, StartedAt: at, CompletedAt: &at, Success: success, HintCount: hints}
+}
+
+func TestReviewQueue_Intervals(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2026, 1, 1+n, 12, 0, 0, 0, time.UTC) }
+
+	queue := ReviewQueue([]ExerciseAttempt{
+		attempt("clean", day(0), true, 0),   // step 0: 1 day
+		attempt("clean", day(1), true, 0),   // step 1: 3 days
+		attempt("hinted", day(0), true, 0),  // step 0
+		attempt("hinted", day(2), true, 2),  // back to 1 day
+		attempt("failed", day(0), false, 0), // 1 day
+		{ExerciseID: "in-progress", StartedAt: day(0)},
+	})
+
+	got := map[string]ReviewItem{}
+	for _, item := range queue {
+		got[item.ExerciseID] = item
+	}
+	if len(got) != 3 {
+		t.Fatalf("queue = %+v; in-progress attempts should be left out", queue)
+	}
+	if got["clean"].IntervalDays != 3 || !got["clean"].DueAt.Equal(day(4)) {
+		t.Errorf("clean = %+v", got["clean"])
+	}
+	if got["hinted"].IntervalDays != 1 || !got["hinted"].DueAt.Equal(day(3)) {
+		t.Errorf("hinted = %+v", got["hinted"])
+	}
+	if queue[0].ExerciseID != "failed" {
+		t.Errorf("queue should be soonest first, got %s", queue[0].ExerciseID)
+	}
+
+	due := DueReviews([]ExerciseAttempt{attempt("clean", day(0), true, 0), attempt("later", day(5), true, 0)}, day(2))
+	if len(due) != 1 || due[0].ExerciseID != "clean" {
+		t.Errorf("DueReviews = %+v", due)
+	}
+}
+
+func TestPracticeStreak(t *testing.T) {
+	now := time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC)
+	daysAgo := func(n int) ExerciseAttempt {
+		return ExerciseAttempt{ExerciseID: "x", StartedAt: now.AddDate(0, 0, -n)}
+	}
+
+	tests := []struct {
+		name    string
+		history []ExerciseAttempt
+		days    int
+		today   bool
+	}{
+		{"none", nil, 0, false},
+		{"today and before", []ExerciseAttempt{daysAgo(0), daysAgo(1), daysAgo(2), daysAgo(4)}, 3, true},
+		{"not yet today", []ExerciseAttempt{daysAgo(1), daysAgo(2)}, 2, false},
+		{"broken", []ExerciseAttempt{daysAgo(2)}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, today := PracticeStreak(tt.history, now)
+			if days != tt.days || today != tt.today {
+				t.Errorf("PracticeStreak = %d, %v; want %d, %v", days, today, tt.days, tt.today)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package profile
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"time"
 )
@@ -9,11 +10,23 @@ import (
 // Service handles profile business logic
 type Service struct {
 	store ProfileStore
+	model SkillModel
+	queue *updateQueue // nil applies updates directly
 }
 
-// NewService creates a new profile service
+// NewService creates a new profile service using the heuristic skill model
 func NewService(store ProfileStore) *Service {
-	return &Service{store: store}
+	return &Service{store: store, model: HeuristicModel{}}
+}
+
+// SetSkillModel replaces the model used to update topic skills
+func (s *Service) SetSkillModel(m SkillModel) {
+	s.model = m
+}
+
+// SkillModel returns the model used to update topic skills
+func (s *Service) SkillModel() SkillModel {
+	return s.model
 }
 
 // GetProfile returns the default learning profile
@@ -25,10 +38,15 @@ func (s *Service) GetProfile(ctx context.Context) (*StoredProfile, error) {
 type SessionInfo struct {
 	ID         string
 	ExerciseID string
+	Difficulty string // exercise difficulty, if known
 	RunCount   int
 	HintCount  int
 	Status     string // "active", "completed", "abandoned"
 	CreatedAt  time.Time
+
+	// BudgetUnused is the fraction of the session's hint budget left,
+	// 0 without a budget
+	BudgetUnused float64
 }
 
 // RunInfo contains run data needed for profile updates
@@ -41,6 +59,43 @@ type RunInfo struct {
 
 // OnSessionStart records the start of a new exercise session
 func (s *Service) OnSessionStart(ctx context.Context, sess SessionInfo) error {
+	return s.submit(update{Kind: updateSessionStart, Session: sess, At: time.Now()})
+}
+
+// OnSessionComplete updates the profile when a session is completed
+func (s *Service) OnSessionComplete(ctx context.Context, sess SessionInfo) error {
+	return s.submit(update{Kind: updateSessionComplete, Session: sess, At: time.Now()})
+}
+
+// OnRunComplete updates the profile when a code run completes
+func (s *Service) OnRunComplete(ctx context.Context, sess SessionInfo, run RunInfo) error {
+	return s.submit(update{Kind: updateRunComplete, Session: sess, Run: &run, At: time.Now()})
+}
+
+// OnHintDelivered updates the profile when a hint is delivered
+func (s *Service) OnHintDelivered(ctx context.Context, sess SessionInfo) error {
+	return s.submit(update{Kind: updateHintDelivered, Session: sess, At: time.Now()})
+}
+
+// apply makes the profile change an update describes
+func (s *Service) apply(u update) error {
+	switch u.Kind {
+	case updateSessionStart:
+		return s.applySessionStart(u.Session)
+	case updateSessionComplete:
+		return s.applySessionComplete(u.Session, u.At)
+	case updateRunComplete:
+		if u.Run == nil {
+			return fmt.Errorf("%s update without a run", u.Kind)
+		}
+		return s.applyRunComplete(*u.Run)
+	case updateHintDelivered:
+		return s.applyHintDelivered()
+	}
+	return fmt.Errorf("unknown profile update %q", u.Kind)
+}
+
+func (s *Service) applySessionStart(sess SessionInfo) error {
 	profile, err := s.store.GetDefault()
 	if err != nil {
 		return err
@@ -65,8 +120,7 @@ func (s *Service) OnSessionStart(ctx context.Context, sess SessionInfo) error {
 	return s.store.Save(profile)
 }
 
-// OnSessionComplete updates the profile when a session is completed
-func (s *Service) OnSessionComplete(ctx context.Context, sess SessionInfo) error {
+func (s *Service) applySessionComplete(sess SessionInfo, at time.Time) error {
 	profile, err := s.store.GetDefault()
 	if err != nil {
 		return err
@@ -80,43 +134,32 @@ func (s *Service) OnSessionComplete(ctx context.Context, sess SessionInfo) error
 	// Update exercise history entry
 	for i := len(profile.ExerciseHistory) - 1; i >= 0; i-- {
 		if profile.ExerciseHistory[i].SessionID == sess.ID {
-			now := time.Now()
-			profile.ExerciseHistory[i].CompletedAt = &now
+			completedAt := at
+			profile.ExerciseHistory[i].CompletedAt = &completedAt
 			profile.ExerciseHistory[i].RunCount = sess.RunCount
 			profile.ExerciseHistory[i].HintCount = sess.HintCount
 			profile.ExerciseHistory[i].Success = sess.Status == "completed"
 
 			// Calculate time to complete
-			elapsed := now.Sub(profile.ExerciseHistory[i].StartedAt)
+			elapsed := at.Sub(profile.ExerciseHistory[i].StartedAt)
 			profile.ExerciseHistory[i].TimeToCompleteMs = elapsed.Milliseconds()
 			break
 		}
 	}
 
 	// Update topic skill
-	topic := ExtractTopic(sess.ExerciseID)
-	skill := profile.TopicSkills[topic]
-	skill.Attempts++
-	skill.LastSeen = time.Now()
-
 	if sess.Status == "completed" {
-		// Increase skill on completion
-		skill.Level = min(1.0, skill.Level+0.05)
 		profile.TotalExercises++
-	} else {
-		// Slight decrease on abandonment
-		skill.Level = max(0.0, skill.Level-0.01)
 	}
-	profile.TopicSkills[topic] = skill
+	s.updateSkill(profile, sess, at)
 
 	// Update hint dependency trend (weekly snapshot)
-	s.updateHintTrend(profile)
+	s.updateHintTrend(profile, at)
 
 	return s.store.Save(profile)
 }
 
-// OnRunComplete updates the profile when a code run completes
-func (s *Service) OnRunComplete(ctx context.Context, sess SessionInfo, run RunInfo) error {
+func (s *Service) applyRunComplete(run RunInfo) error {
 	profile, err := s.store.GetDefault()
 	if err != nil {
 		return err
@@ -148,8 +191,7 @@ func (s *Service) OnRunComplete(ctx context.Context, sess SessionInfo, run RunIn
 	return s.store.Save(profile)
 }
 
-// OnHintDelivered updates the profile when a hint is delivered
-func (s *Service) OnHintDelivered(ctx context.Context, sess SessionInfo) error {
+func (s *Service) applyHintDelivered() error {
 	profile, err := s.store.GetDefault()
 	if err != nil {
 		return err
@@ -160,8 +202,26 @@ func (s *Service) OnHintDelivered(ctx context.Context, sess SessionInfo) error {
 	return s.store.Save(profile)
 }
 
+// updateSkill records a finished session against its topic with the
+// configured skill model
+func (s *Service) updateSkill(profile *StoredProfile, sess SessionInfo, at time.Time) {
+	topic := ExtractTopic(sess.ExerciseID)
+	skill := profile.TopicSkills[topic]
+	skill.Attempts++
+	skill.LastSeen = at
+
+	profile.TopicSkills[topic] = s.model.Update(skill, Outcome{
+		Completed:    sess.Status == "completed",
+		RunCount:     sess.RunCount,
+		HintCount:    sess.HintCount,
+		BudgetUnused: sess.BudgetUnused,
+		Difficulty:   sess.Difficulty,
+		At:           at,
+	})
+}
+
 // updateHintTrend adds a new data point to the hint dependency trend
-func (s *Service) updateHintTrend(profile *StoredProfile) {
+func (s *Service) updateHintTrend(profile *StoredProfile, at time.Time) {
 	// Only add a new point every ~10 runs
 	if profile.TotalRuns%10 != 0 && profile.TotalRuns > 0 {
 		return
@@ -173,7 +233,7 @@ func (s *Service) updateHintTrend(profile *StoredProfile) {
 	}
 
 	point := HintDependencyPoint{
-		Timestamp:  time.Now(),
+		Timestamp:  at,
 		Dependency: dependency,
 		RunWindow:  10,
 	}
@@ -209,14 +269,7 @@ func (s *Service) RebuildFromSessions(ctx context.Context, sessions []SessionInf
 		profile.HintRequests += sess.HintCount
 
 		// Update topic skills
-		topic := ExtractTopic(sess.ExerciseID)
-		skill := profile.TopicSkills[topic]
-		skill.Attempts++
-		skill.LastSeen = sess.CreatedAt
-		if sess.Status == "completed" {
-			skill.Level = min(1.0, skill.Level+0.05)
-		}
-		profile.TopicSkills[topic] = skill
+		s.updateSkill(profile, sess, sess.CreatedAt)
 
 		// Add to history
 		attempt := ExerciseAttempt{
@@ -244,7 +297,7 @@ func (s *Service) RebuildFromSessions(ctx context.Context, sessions []SessionInf
 	}
 
 	// Update hint trend
-	s.updateHintTrend(profile)
+	s.updateHintTrend(profile, time.Now())
 
 	slog.Info("profile rebuilt from sessions",
 		"sessions", profile.TotalSessions,
@@ -286,3 +286,67 @@ func TestService_UpdateHintTrend(t *testing.T) {
 		t.Error("HintDependencyTrend should have entries after 10 runs and session complete")
 	}
 }
+
+func TestService_Forget(t *testing.T) {
+	service := setupService(t)
+	ctx := context.Background()
+	jan := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	erased := SessionInfo{ID: "erased", ExerciseID: "go-v1/basics/hello", HintCount: 3, Status: "completed", CreatedAt: jan}
+	kept := SessionInfo{ID: "kept", ExerciseID: "go-v1/maps/count", Status: "completed", CreatedAt: jan}
+	failed := RunInfo{BuildOutput: "undefined: someVariable"}
+	if err := service.RebuildFromSessions(ctx, []SessionInfo{erased, kept}, map[string][]RunInfo{"erased": {failed}}); err != nil {
+		t.Fatal(err)
+	}
+	profile, _ := service.GetProfile(ctx)
+	profile.HintDependencyTrend = []HintDependencyPoint{{Timestamp: jan}, {Timestamp: jan.AddDate(0, 2, 0)}}
+	service.store.Save(profile)
+
+	err := service.Forget(ctx, ForgetRequest{
+		Sessions: []SessionInfo{erased},
+		Runs:     map[string][]RunInfo{"erased": {failed}},
+		Topic:    "go/basics",
+		Since:    jan.AddDate(0, -1, 0),
+		Until:    jan.AddDate(0, 1, 0),
+	})
+	if err != nil {
+		t.Fatalf("Forget() error = %v", err)
+	}
+
+	profile, _ = service.GetProfile(ctx)
+	if profile.TotalSessions != 1 || profile.CompletedSessions != 1 || profile.HintRequests != 0 || profile.TotalRuns != 0 {
+		t.Errorf("counters = %d sessions, %d completed, %d hints, %d runs; want only the kept session",
+			profile.TotalSessions, profile.CompletedSessions, profile.HintRequests, profile.TotalRuns)
+	}
+	if len(profile.ErrorPatterns) != 0 {
+		t.Errorf("ErrorPatterns = %v; want the erased run's gone", profile.ErrorPatterns)
+	}
+	if len(profile.ExerciseHistory) != 1 || profile.ExerciseHistory[0].SessionID != "kept" {
+		t.Errorf("history = %+v; want only the kept session", profile.ExerciseHistory)
+	}
+	if _, ok := profile.TopicSkills["go/basics"]; ok {
+		t.Error("go/basics skill should be dropped")
+	}
+	if _, ok := profile.TopicSkills["go/maps"]; !ok {
+		t.Error("go/maps skill should be kept")
+	}
+	if len(profile.HintDependencyTrend) != 1 || !profile.HintDependencyTrend[0].Timestamp.Equal(jan.AddDate(0, 2, 0)) {
+		t.Errorf("trend = %+v; want the point outside the range", profile.HintDependencyTrend)
+	}
+}
+
+func TestService_Reset(t *testing.T) {
+	service := setupService(t)
+	ctx := context.Background()
+	service.OnSessionStart(ctx, SessionInfo{ID: "s1", ExerciseID: "go-v1/basics/hello"})
+
+	if err := service.Reset(ctx); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if err := service.Reset(ctx); err != nil {
+		t.Fatalf("Reset() twice error = %v", err)
+	}
+	if profile, _ := service.GetProfile(ctx); profile.TotalSessions != 0 {
+		t.Errorf("TotalSessions = %d after reset; want 0", profile.TotalSessions)
+	}
+}
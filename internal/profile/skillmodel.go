@@ -0,0 +1,140 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Skill model names accepted in learning_contract.skill_model
+const (
+	SkillModelHeuristic = "heuristic"
+	SkillModelElo       = "elo"
+)
+
+var ErrUnknownSkillModel = errors.New("unknown skill model")
+
+// Outcome is one finished exercise session as a skill model sees it
+type Outcome struct {
+	Completed  bool
+	RunCount   int
+	HintCount  int
+	Difficulty string // beginner, intermediate or advanced; empty = unknown
+	At         time.Time
+
+	// BudgetUnused is the fraction of the hint budget left, 0 when the
+	// session had none; finishing with budget to spare scores higher
+	BudgetUnused float64
+}
+
+// SkillModel estimates topic mastery from session outcomes. The service
+// counts attempts and updates LastSeen before calling Update; the model
+// sets Level (always 0.0-1.0, whatever the model's internal scale) and
+// may set Confidence.
+type SkillModel interface {
+	// Name identifies the model in config and analytics
+	Name() string
+
+	// Update returns the skill after one more outcome in its topic
+	Update(skill StoredSkill, outcome Outcome) StoredSkill
+}
+
+// NewSkillModel returns the model with the given name. An empty name
+// selects the heuristic model.
+func NewSkillModel(name string) (SkillModel, error) {
+	switch name {
+	case "", SkillModelHeuristic:
+		return HeuristicModel{}, nil
+	case SkillModelElo:
+		return NewEloModel(), nil
+	}
+	return nil, fmt.Errorf("%w: %q (valid: %s, %s)", ErrUnknownSkillModel, name, SkillModelHeuristic, SkillModelElo)
+}
+
+// HeuristicModel is the original fixed-step model: a completion adds 0.05,
+// up to 0.05 more for unused hint budget, and an abandoned session takes
+// away 0.01
+type HeuristicModel struct{}
+
+// Name implements SkillModel
+func (HeuristicModel) Name() string { return SkillModelHeuristic }
+
+// Update implements SkillModel
+func (HeuristicModel) Update(skill StoredSkill, outcome Outcome) StoredSkill {
+	if outcome.Completed {
+		skill.Level = min(1.0, skill.Level+0.05+0.05*outcome.BudgetUnused)
+	} else {
+		skill.Level = max(0.0, skill.Level-0.01)
+	}
+	return skill
+}
+
+// EloModel treats each topic as a one-parameter IRT (Rasch) ability on the
+// logit scale and updates it Elo-style: every outcome moves the ability by
+// K times the gap between the score and the predicted chance of success
+// against the exercise's difficulty. Level is the predicted chance of
+// finishing an intermediate exercise unaided.
+type EloModel struct {
+	K      float64 // step size for the first attempt; decays with attempts
+	MinK   float64 // floor for the decayed step
+	Prior  float64 // ability before the first attempt, in logits
+	Spread float64 // difficulty gap between adjacent levels, in logits
+}
+
+// NewEloModel returns an EloModel with the default parameters
+func NewEloModel() EloModel {
+	return EloModel{K: 1.0, MinK: 0.2, Prior: -1.0, Spread: 1.0}
+}
+
+// Name implements SkillModel
+func (EloModel) Name() string { return SkillModelElo }
+
+// Update implements SkillModel
+func (m EloModel) Update(skill StoredSkill, outcome Outcome) StoredSkill {
+	attempts := max(skill.Attempts, 1)
+
+	theta := m.Prior
+	if attempts > 1 {
+		theta = logit(skill.Level)
+	}
+
+	expected := sigmoid(theta - m.difficulty(outcome.Difficulty))
+	k := max(m.K/math.Sqrt(float64(attempts)), m.MinK)
+	theta += k * (score(outcome) - expected)
+
+	skill.Level = sigmoid(theta)
+	skill.Confidence = float64(attempts) / float64(attempts+5)
+	return skill
+}
+
+func (m EloModel) difficulty(d string) float64 {
+	switch d {
+	case "beginner":
+		return -m.Spread
+	case "advanced":
+		return m.Spread
+	}
+	return 0
+}
+
+// score grades an outcome: 1 for finishing unaided, less for each hint,
+// 0 for abandoning. Unused hint budget wins back up to half of what the
+// hints cost.
+func score(o Outcome) float64 {
+	if !o.Completed {
+		return 0
+	}
+	s := 1 / (1 + 0.5*float64(o.HintCount))
+	return s + (1-s)*0.5*o.BudgetUnused
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+// logit is the inverse of sigmoid, clamped so 0 and 1 stay finite
+func logit(p float64) float64 {
+	p = min(max(p, 0.01), 0.99)
+	return math.Log(p / (1 - p))
+}
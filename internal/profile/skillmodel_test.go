@@ -0,0 +1,100 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewSkillModel(t *testing.T) {
+	for name, want := range map[string]string{"": SkillModelHeuristic, "heuristic": SkillModelHeuristic, "elo": SkillModelElo} {
+		m, err := NewSkillModel(name)
+		if err != nil || m.Name() != want {
+			t.Errorf("NewSkillModel(%q) = %v, %v; want %s", name, m, err, want)
+		}
+	}
+	if _, err := NewSkillModel("bkt"); !errors.Is(err, ErrUnknownSkillModel) {
+		t.Errorf("unknown model error = %v", err)
+	}
+}
+
+func TestHeuristicModel(t *testing.T) {
+	m := HeuristicModel{}
+	skill := m.Update(StoredSkill{Level: 0.98}, Outcome{Completed: true})
+	if skill.Level != 1.0 {
+		t.Errorf("level should cap at 1.0, got %v", skill.Level)
+	}
+	skill = m.Update(StoredSkill{}, Outcome{})
+	if skill.Level != 0 {
+		t.Errorf("level should floor at 0, got %v", skill.Level)
+	}
+}
+
+func TestEloModel(t *testing.T) {
+	m := NewEloModel()
+
+	// Repeated unaided successes converge upward; the first step is the largest
+	var skill StoredSkill
+	prev, firstGain := 0.0, 0.0
+	for i := 1; i <= 10; i++ {
+		skill.Attempts = i
+		before := skill.Level
+		skill = m.Update(skill, Outcome{Completed: true, Difficulty: "intermediate"})
+		if skill.Level <= prev || skill.Level >= 1 {
+			t.Fatalf("attempt %d: level %v should rise and stay below 1", i, skill.Level)
+		}
+		if i == 1 {
+			firstGain = skill.Level - sigmoid(m.Prior)
+		} else if gain := skill.Level - before; gain > firstGain {
+			t.Errorf("attempt %d gained %v, more than the first attempt's %v", i, gain, firstGain)
+		}
+		prev = skill.Level
+	}
+	if skill.Confidence <= 0.5 {
+		t.Errorf("confidence after 10 attempts = %v", skill.Confidence)
+	}
+
+	start := StoredSkill{Level: 0.5, Attempts: 3}
+	unaided := m.Update(start, Outcome{Completed: true})
+	hinted := m.Update(start, Outcome{Completed: true, HintCount: 2})
+	abandoned := m.Update(start, Outcome{})
+	if !(unaided.Level > hinted.Level && abandoned.Level < start.Level) {
+		t.Errorf("unaided %v, hinted %v, abandoned %v", unaided.Level, hinted.Level, abandoned.Level)
+	}
+
+	frugal := m.Update(start, Outcome{Completed: true, HintCount: 2, BudgetUnused: 0.6})
+	if !(frugal.Level > hinted.Level && frugal.Level < unaided.Level) {
+		t.Errorf("hinted with budget left %v should fall between hinted %v and unaided %v", frugal.Level, hinted.Level, unaided.Level)
+	}
+
+	easy := m.Update(start, Outcome{Completed: true, Difficulty: "beginner"})
+	hard := m.Update(start, Outcome{Completed: true, Difficulty: "advanced"})
+	if easy.Level >= hard.Level {
+		t.Errorf("an advanced success (%v) should count for more than a beginner one (%v)", hard.Level, easy.Level)
+	}
+}
+
+func TestService_SkillModel(t *testing.T) {
+	service := setupService(t)
+	service.SetSkillModel(NewEloModel())
+	ctx := context.Background()
+
+	sess := SessionInfo{ID: "s1", ExerciseID: "go-v1/basics/hello", Difficulty: "beginner", Status: "completed", CreatedAt: time.Now()}
+	service.OnSessionStart(ctx, sess)
+	if err := service.OnSessionComplete(ctx, sess); err != nil {
+		t.Fatalf("OnSessionComplete() error = %v", err)
+	}
+
+	breakdown, err := service.GetSkillBreakdown(ctx)
+	if err != nil {
+		t.Fatalf("GetSkillBreakdown() error = %v", err)
+	}
+	if breakdown.Model != SkillModelElo {
+		t.Errorf("Model = %q", breakdown.Model)
+	}
+	skill := breakdown.Skills[ExtractTopic(sess.ExerciseID)]
+	if skill.Level <= sigmoid(NewEloModel().Prior) || skill.Confidence == 0 {
+		t.Errorf("elo skill after one success = %+v", skill)
+	}
+}
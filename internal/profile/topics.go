@@ -2,29 +2,30 @@ package profile
 
 import (
 	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
 )
 
 // ExtractTopic derives a topic from an exercise ID
-// Exercise ID format: "pack/category/slug" or "pack/slug"
+// Exercise ID format: "[org/]pack/category/slug" or "pack/slug"
 // Examples:
 //   - "go-v1/basics/hello-world" -> "go/basics"
 //   - "go-v1/interfaces/stringer" -> "go/interfaces"
 //   - "python-v1/testing/pytest-basics" -> "python/testing"
+//   - "acme/go-tools/basics/hello-world" -> "go/basics"
 func ExtractTopic(exerciseID string) string {
+	if pack, slug, ok := domain.SplitExerciseID(exerciseID); ok {
+		category, _, _ := strings.Cut(slug, "/")
+		return extractLanguage(domain.PackName(pack)) + "/" + category
+	}
+
 	parts := strings.Split(exerciseID, "/")
 	if len(parts) < 2 {
 		return "general"
 	}
 
 	// Extract language from pack (e.g., "go-v1" -> "go")
-	language := extractLanguage(parts[0])
-
-	// Use category if available, otherwise use pack
-	if len(parts) >= 3 {
-		return language + "/" + parts[1]
-	}
-
-	return language
+	return extractLanguage(parts[0])
 }
 
 // extractLanguage extracts the language from a pack ID
@@ -44,11 +45,13 @@ func extractLanguage(packID string) string {
 // Examples:
 //   - "go-v1/basics/hello-world" -> "basics"
 //   - "go-v1/advanced/concurrency" -> "advanced"
+//   - "acme/go-tools/basics/hello-world" -> "basics"
 func ExtractCategory(exerciseID string) string {
-	parts := strings.Split(exerciseID, "/")
-	if len(parts) >= 3 {
-		return parts[1]
+	if _, slug, ok := domain.SplitExerciseID(exerciseID); ok {
+		category, _, _ := strings.Cut(slug, "/")
+		return category
 	}
+	parts := strings.Split(exerciseID, "/")
 	if len(parts) >= 2 {
 		return parts[0]
 	}
@@ -58,7 +61,11 @@ func ExtractCategory(exerciseID string) string {
 // ExtractPack extracts the pack ID from an exercise ID
 // Examples:
 //   - "go-v1/basics/hello-world" -> "go-v1"
+//   - "acme/go-tools/basics/hello-world" -> "acme/go-tools"
 func ExtractPack(exerciseID string) string {
+	if pack, _, ok := domain.SplitExerciseID(exerciseID); ok {
+		return pack
+	}
 	parts := strings.Split(exerciseID, "/")
 	if len(parts) >= 1 {
 		return parts[0]
@@ -11,6 +11,7 @@ func TestExtractTopic(t *testing.T) {
 		{"go-v1/interfaces/stringer", "go/interfaces"},
 		{"python-v1/testing/pytest-basics", "python/testing"},
 		{"typescript-v1/advanced/generics", "typescript/advanced"},
+		{"acme/go-tools/basics/hello-world", "go/basics"},
 		{"go-v1/hello", "go"},
 		{"single", "general"},
 		{"", "general"},
@@ -55,6 +56,7 @@ func TestExtractCategory(t *testing.T) {
 	}{
 		{"go-v1/basics/hello-world", "basics"},
 		{"go-v1/advanced/concurrency", "advanced"},
+		{"acme/go-tools/basics/hello-world", "basics"},
 		{"pack/slug", "pack"},
 		{"single", "unknown"},
 		{"", "unknown"},
@@ -77,6 +79,7 @@ func TestExtractPack(t *testing.T) {
 	}{
 		{"go-v1/basics/hello-world", "go-v1"},
 		{"python-v1/testing/pytest", "python-v1"},
+		{"acme/go-tools/basics/hello-world", "acme/go-tools"},
 		{"single", "single"},
 		{"", ""},
 	}
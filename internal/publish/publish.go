@@ -0,0 +1,237 @@
+// Package publish renders a learner's progress as a static HTML page to
+// share or embed in a portfolio. The page is self-contained: no scripts,
+// no external assets and nothing fetched when it is viewed. It shows
+// skill levels, practice streaks and pack completion, never code, hints
+// or session IDs.
+package publish
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/profile"
+)
+
+//go:embed page.html
+var templates embed.FS
+
+var pageTemplate = template.Must(template.New("page.html").
+	Funcs(template.FuncMap{"percent": percent}).
+	ParseFS(templates, "page.html"))
+
+// maxRadarTopics bounds the radar's axes; the most practiced topics win
+const maxRadarTopics = 8
+
+// minRadarTopics is the fewest axes a radar reads as a shape with
+const minRadarTopics = 3
+
+// radarRadius is the radar's radius in SVG units, around (0, 0)
+const radarRadius = 100
+
+// Pack is an exercise pack and the full IDs of its exercises
+type Pack struct {
+	ID        string
+	Name      string
+	Exercises []string
+}
+
+// Page is what the progress page shows
+type Page struct {
+	Name          string // "" for an anonymous page
+	GeneratedAt   time.Time
+	Skills        []Skill
+	CurrentStreak int // days
+	BestStreak    int // days
+	Completed     int // exercises completed
+	Packs         []PackProgress
+}
+
+// Skill is one topic's level, 0.0-1.0
+type Skill struct {
+	Topic    string
+	Level    float64
+	Attempts int
+}
+
+// PackProgress is how much of a pack was completed
+type PackProgress struct {
+	ID        string
+	Name      string
+	Completed int
+	Total     int
+}
+
+// Done reports whether every exercise of the pack was completed
+func (p PackProgress) Done() bool {
+	return p.Total > 0 && p.Completed == p.Total
+}
+
+// Percent is the share of the pack completed, 0-100
+func (p PackProgress) Percent() int {
+	if p.Total == 0 {
+		return 0
+	}
+	return p.Completed * 100 / p.Total
+}
+
+// Build collects the page from a profile and the installed packs. Packs
+// without a completed exercise are left out.
+func Build(prof *profile.StoredProfile, packs []Pack, name string, now time.Time) *Page {
+	page := &Page{Name: name, GeneratedAt: now}
+
+	for topic, skill := range prof.TopicSkills {
+		page.Skills = append(page.Skills, Skill{Topic: topic, Level: skill.Level, Attempts: skill.Attempts})
+	}
+	sort.Slice(page.Skills, func(i, j int) bool {
+		a, b := page.Skills[i], page.Skills[j]
+		if a.Attempts != b.Attempts {
+			return a.Attempts > b.Attempts
+		}
+		return a.Topic < b.Topic
+	})
+
+	completed := make(map[string]bool)
+	for _, a := range prof.ExerciseHistory {
+		if a.Success && a.CompletedAt != nil {
+			completed[a.ExerciseID] = true
+		}
+	}
+	page.Completed = len(completed)
+
+	for _, pack := range packs {
+		p := PackProgress{ID: pack.ID, Name: pack.Name, Total: len(pack.Exercises)}
+		for _, id := range pack.Exercises {
+			if completed[id] {
+				p.Completed++
+			}
+		}
+		if p.Completed > 0 {
+			page.Packs = append(page.Packs, p)
+		}
+	}
+	sort.SliceStable(page.Packs, func(i, j int) bool { return page.Packs[i].Done() && !page.Packs[j].Done() })
+
+	page.CurrentStreak, _ = profile.PracticeStreak(prof.ExerciseHistory, now)
+	page.BestStreak = max(page.CurrentStreak, bestStreak(prof.ExerciseHistory, now.Location()))
+	return page
+}
+
+// bestStreak is the longest run of consecutive days with an attempt
+func bestStreak(history []profile.ExerciseAttempt, loc *time.Location) int {
+	days := make(map[string]bool)
+	for _, a := range history {
+		days[a.StartedAt.In(loc).Format(time.DateOnly)] = true
+		if a.CompletedAt != nil {
+			days[a.CompletedAt.In(loc).Format(time.DateOnly)] = true
+		}
+	}
+
+	best := 0
+	for day := range days {
+		start, err := time.ParseInLocation(time.DateOnly, day, loc)
+		if err != nil || days[start.AddDate(0, 0, -1).Format(time.DateOnly)] {
+			continue // not the first day of a run
+		}
+		n := 0
+		for d := start; days[d.Format(time.DateOnly)]; d = d.AddDate(0, 0, 1) {
+			n++
+		}
+		best = max(best, n)
+	}
+	return best
+}
+
+// Render writes the page as a complete HTML document, or with fragment
+// as a <div> to paste into another page
+func Render(w io.Writer, page *Page, fragment bool) error {
+	data := struct {
+		*Page
+		Fragment bool
+		Radar    *radar
+	}{page, fragment, newRadar(page.Skills)}
+	if err := pageTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("render progress page: %w", err)
+	}
+	return nil
+}
+
+// radar is the skill radar's geometry in SVG units
+type radar struct {
+	Rings   []string // polygon points of the 25%, 50%, 75% and 100% rings
+	Shape   string   // polygon points of the skill levels
+	Spokes  []point
+	Labels  []radarLabel
+	Summary string // text alternative
+}
+
+type point struct{ X, Y float64 }
+
+type radarLabel struct {
+	point
+	Text   string
+	Anchor string // SVG text-anchor
+}
+
+// newRadar lays out the most practiced skills, or returns nil when there
+// are too few to draw one
+func newRadar(skills []Skill) *radar {
+	if len(skills) > maxRadarTopics {
+		skills = skills[:maxRadarTopics]
+	}
+	if len(skills) < minRadarTopics {
+		return nil
+	}
+
+	at := func(i int, r float64) point {
+		angle := 2*math.Pi*float64(i)/float64(len(skills)) - math.Pi/2
+		return point{X: round(r * math.Cos(angle)), Y: round(r * math.Sin(angle))}
+	}
+	polygon := func(r func(i int) float64) string {
+		s := ""
+		for i := range skills {
+			p := at(i, r(i))
+			if i > 0 {
+				s += " "
+			}
+			s += fmt.Sprintf("%g,%g", p.X, p.Y)
+		}
+		return s
+	}
+
+	r := &radar{}
+	for _, f := range []float64{0.25, 0.5, 0.75, 1} {
+		r.Rings = append(r.Rings, polygon(func(int) float64 { return f * radarRadius }))
+	}
+	r.Shape = polygon(func(i int) float64 { return math.Max(0.02, skills[i].Level) * radarRadius })
+
+	for i, s := range skills {
+		r.Spokes = append(r.Spokes, at(i, radarRadius))
+		label := radarLabel{point: at(i, radarRadius+12), Text: s.Topic, Anchor: "middle"}
+		switch {
+		case label.X > 1:
+			label.Anchor = "start"
+		case label.X < -1:
+			label.Anchor = "end"
+		}
+		r.Labels = append(r.Labels, label)
+
+		if i > 0 {
+			r.Summary += ", "
+		}
+		r.Summary += fmt.Sprintf("%s %d%%", s.Topic, percent(s.Level))
+	}
+	return r
+}
+
+func round(f float64) float64 {
+	return math.Round(f*10) / 10
+}
+
+func percent(level float64) int {
+	return int(math.Round(level * 100))
+}
@@ -0,0 +1,98 @@
+package publish
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/profile"
+)
+
+func testProfile(now time.Time) *profile.StoredProfile {
+	day := func(n int) *time.Time {
+		t := now.AddDate(0, 0, -n)
+		return &t
+	}
+	return &profile.StoredProfile{
+		ID: "c0ffee-profile-id",
+		TopicSkills: map[string]profile.StoredSkill{
+			"go/basics":      {Level: 0.8, Attempts: 6},
+			"go/errors":      {Level: 0.4, Attempts: 3},
+			"go/concurrency": {Level: 0.1, Attempts: 1},
+		},
+		ExerciseHistory: []profile.ExerciseAttempt{
+			// A three day run a week ago, then today and yesterday
+			{ExerciseID: "go-v1/basics/hello", SessionID: "secret-session", StartedAt: *day(9), CompletedAt: day(9), Success: true},
+			{ExerciseID: "go-v1/basics/maps", StartedAt: *day(8), CompletedAt: day(8), Success: true},
+			{ExerciseID: "go-v1/errors/wrap", StartedAt: *day(7)},
+			{ExerciseID: "go-v1/errors/wrap", StartedAt: *day(1), CompletedAt: day(1), Success: true},
+			{ExerciseID: "go-v1/concurrency/chan", StartedAt: *day(0)},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	packs := []Pack{
+		{ID: "go-v1-errors", Name: "Errors", Exercises: []string{"go-v1/errors/wrap", "go-v1/errors/is"}},
+		{ID: "go-v1-basics", Name: "Basics", Exercises: []string{"go-v1/basics/hello", "go-v1/basics/maps"}},
+		{ID: "py-v1", Name: "Python", Exercises: []string{"py-v1/basics/hello"}},
+	}
+
+	page := Build(testProfile(now), packs, "ada", now)
+
+	if page.Completed != 3 {
+		t.Errorf("Completed = %d, want 3", page.Completed)
+	}
+	if page.CurrentStreak != 2 || page.BestStreak != 3 {
+		t.Errorf("streaks = %d current, %d best; want 2, 3", page.CurrentStreak, page.BestStreak)
+	}
+	if len(page.Skills) != 3 || page.Skills[0].Topic != "go/basics" {
+		t.Errorf("Skills = %+v, want the most practiced first", page.Skills)
+	}
+	if len(page.Packs) != 2 || page.Packs[0].Name != "Basics" || !page.Packs[0].Done() || page.Packs[1].Percent() != 50 {
+		t.Errorf("Packs = %+v, want Basics done, then Errors half done", page.Packs)
+	}
+}
+
+func TestRender(t *testing.T) {
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	page := Build(testProfile(now), nil, "<ada>", now)
+
+	var buf bytes.Buffer
+	if err := Render(&buf, page, false); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{"<!doctype html>", "&lt;ada&gt;'s learning progress", `class="shape"`, "go/basics 80%"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("page is missing %q", want)
+		}
+	}
+	for _, leak := range []string{"<script", "secret-session", "c0ffee-profile-id", "http"} {
+		if strings.Contains(html, leak) {
+			t.Errorf("page contains %q", leak)
+		}
+	}
+
+	buf.Reset()
+	if err := Render(&buf, page, true); err != nil {
+		t.Fatalf("Render() fragment error = %v", err)
+	}
+	if html := buf.String(); strings.Contains(html, "<html") || !strings.HasPrefix(strings.TrimSpace(html), `<div class="temper-progress">`) {
+		t.Errorf("fragment should be a single div, got %.80q", html)
+	}
+}
+
+func TestRender_FewSkillsHasNoRadar(t *testing.T) {
+	page := &Page{Skills: []Skill{{Topic: "go/basics", Level: 0.5}}}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, page, true); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "<svg") || !strings.Contains(buf.String(), "go/basics") {
+		t.Errorf("expected skill bars without a radar, got %s", buf.String())
+	}
+}
@@ -0,0 +1,125 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+// charsPerToken estimates tokens for streamed replies, which don't report
+// usage
+const charsPerToken = 4
+
+// Wrap meters the provider's calls when it has a cap. Past its hard cap,
+// calls fail with ErrExhausted or, when the provider's quota says local,
+// go to the provider fallback returns. Providers without a cap are
+// returned unchanged.
+func (m *Meter) Wrap(p llm.Provider, fallback func() (llm.Provider, error)) llm.Provider {
+	if m == nil || !m.Capped(p.Name()) {
+		return p
+	}
+	return &provider{Provider: p, meter: m, fallback: fallback}
+}
+
+type provider struct {
+	llm.Provider
+	meter    *Meter
+	fallback func() (llm.Provider, error)
+}
+
+// DefaultModel reports the wrapped provider's default model
+func (p *provider) DefaultModel() string {
+	return llm.DefaultModel(p.Provider)
+}
+
+// SupportsTools reports whether the wrapped provider takes tools
+func (p *provider) SupportsTools() bool {
+	return llm.SupportsTools(p.Provider)
+}
+
+func (p *provider) Generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	local, req, err := p.overCap(req)
+	if err != nil {
+		return nil, err
+	}
+	if local != nil {
+		return local.Generate(ctx, req)
+	}
+
+	resp, err := p.Provider.Generate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.record(resp.Usage.InputTokens, resp.Usage.OutputTokens)
+	return resp, nil
+}
+
+func (p *provider) GenerateStream(ctx context.Context, req *llm.Request) (<-chan llm.StreamChunk, error) {
+	local, req, err := p.overCap(req)
+	if err != nil {
+		return nil, err
+	}
+	if local != nil {
+		return local.GenerateStream(ctx, req)
+	}
+
+	stream, err := p.Provider.GenerateStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan llm.StreamChunk, cap(stream))
+	go func() {
+		defer close(out)
+		output := 0
+		for chunk := range stream {
+			output += len(chunk.Content)
+			out <- chunk
+		}
+		p.record(promptChars(req)/charsPerToken, output/charsPerToken)
+	}()
+	return out, nil
+}
+
+// overCap returns the provider to use instead past the hard cap, with the
+// request it takes, or ErrExhausted when the calls are blocked
+func (p *provider) overCap(req *llm.Request) (llm.Provider, *llm.Request, error) {
+	if p.meter.Level(p.Name()) != LevelHard {
+		return nil, req, nil
+	}
+	exhausted := fmt.Errorf("%w: %s reached its monthly hard cap", ErrExhausted, p.Name())
+	if p.meter.Limits(p.Name()).AtHardCap != AtHardCapLocal || p.fallback == nil {
+		return nil, req, exhausted
+	}
+	local, err := p.fallback()
+	if err != nil {
+		return nil, req, fmt.Errorf("%w; no local model to fall back to: %v", exhausted, err)
+	}
+	// The model named is the cloud provider's; the local one uses its own
+	downgraded := *req
+	downgraded.Model = ""
+	return local, &downgraded, nil
+}
+
+func (p *provider) record(inputTokens, outputTokens int) {
+	if err := p.meter.Record(p.Name(), inputTokens, outputTokens); err != nil {
+		slog.Warn("record provider usage", "provider", p.Name(), "error", err)
+	}
+}
+
+// promptChars is the length of everything sent with the request
+func promptChars(req *llm.Request) int {
+	n := len(req.System)
+	if len(req.SystemBlocks) > 0 {
+		n = 0
+		for _, block := range req.SystemBlocks {
+			n += len(block.Text)
+		}
+	}
+	for _, msg := range req.Messages {
+		n += len(msg.Content)
+	}
+	return n
+}
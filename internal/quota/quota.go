@@ -0,0 +1,270 @@
+// Package quota meters each LLM provider's token use and spend per
+// calendar month against the caps in config. Crossing a soft cap raises
+// an alert once a month; at a hard cap the provider's calls are refused
+// or sent to the local model instead.
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/storage/local"
+)
+
+const collectionUsage = "usage"
+
+// ErrExhausted is returned for a call to a provider past its hard cap
+var ErrExhausted = errors.New("provider quota exhausted")
+
+// What happens to a provider's calls at its hard cap
+const (
+	AtHardCapBlock = "block"
+	AtHardCapLocal = "local"
+)
+
+// Levels of use against the caps, in increasing order
+const (
+	LevelOK   = "ok"
+	LevelSoft = "soft"
+	LevelHard = "hard"
+)
+
+// Limits are a provider's monthly caps. A zero cap is off.
+type Limits struct {
+	SoftTokens  int     `json:"soft_tokens,omitempty"`
+	HardTokens  int     `json:"hard_tokens,omitempty"`
+	SoftCost    float64 `json:"soft_cost,omitempty"`
+	HardCost    float64 `json:"hard_cost,omitempty"`
+	InputPrice  float64 `json:"-"` // USD per million input tokens
+	OutputPrice float64 `json:"-"` // USD per million output tokens
+	AtHardCap   string  `json:"at_hard_cap"`
+}
+
+// FromConfig reads the quotas of the configured providers. Providers
+// without a cap are left out.
+func FromConfig(providers map[string]*config.ProviderConfig) (map[string]Limits, error) {
+	limits := make(map[string]Limits)
+	for name, p := range providers {
+		if p == nil {
+			continue
+		}
+		q := p.Quota
+		if q.SoftTokens < 0 || q.HardTokens < 0 || q.SoftCost < 0 || q.HardCost < 0 || q.InputPrice < 0 || q.OutputPrice < 0 {
+			return nil, fmt.Errorf("llm.providers.%s.quota: caps and prices can't be negative", name)
+		}
+		switch q.AtHardCap {
+		case "":
+			q.AtHardCap = AtHardCapBlock
+		case AtHardCapBlock, AtHardCapLocal:
+		default:
+			return nil, fmt.Errorf("llm.providers.%s.quota.at_hard_cap: %q is not block or local", name, q.AtHardCap)
+		}
+		if (q.SoftCost > 0 || q.HardCost > 0) && q.InputPrice == 0 && q.OutputPrice == 0 {
+			return nil, fmt.Errorf("llm.providers.%s.quota: cost caps need input_price or output_price", name)
+		}
+		l := Limits{
+			SoftTokens:  q.SoftTokens,
+			HardTokens:  q.HardTokens,
+			SoftCost:    q.SoftCost,
+			HardCost:    q.HardCost,
+			InputPrice:  q.InputPrice,
+			OutputPrice: q.OutputPrice,
+			AtHardCap:   q.AtHardCap,
+		}
+		if l.capped() {
+			limits[name] = l
+		}
+	}
+	return limits, nil
+}
+
+func (l Limits) capped() bool {
+	return l.SoftTokens > 0 || l.HardTokens > 0 || l.SoftCost > 0 || l.HardCost > 0
+}
+
+// level is where use stands against the caps
+func (l Limits) level(u Usage) string {
+	tokens := u.InputTokens + u.OutputTokens
+	switch {
+	case l.HardTokens > 0 && tokens >= l.HardTokens, l.HardCost > 0 && u.Cost >= l.HardCost:
+		return LevelHard
+	case l.SoftTokens > 0 && tokens >= l.SoftTokens, l.SoftCost > 0 && u.Cost >= l.SoftCost:
+		return LevelSoft
+	}
+	return LevelOK
+}
+
+// Usage is a provider's use in one month
+type Usage struct {
+	Provider     string  `json:"provider"`
+	Month        string  `json:"month"` // e.g. "2026-10"
+	Calls        int     `json:"calls"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	Cost         float64 `json:"cost"` // USD at the configured prices
+
+	// Alerted is the highest level an alert was raised for this month
+	Alerted string `json:"alerted,omitempty"`
+}
+
+// Status is a provider's use this month against its caps
+type Status struct {
+	Usage
+	Limits Limits `json:"limits"`
+	Level  string `json:"level"`
+}
+
+// Alert is raised the first time in a month a provider reaches its soft
+// or hard cap
+type Alert struct {
+	Provider string    `json:"provider"`
+	Month    string    `json:"month"`
+	Level    string    `json:"level"`
+	Message  string    `json:"message"`
+	At       time.Time `json:"at"`
+}
+
+// Meter keeps each provider's monthly use, one JSON file per provider and
+// month
+type Meter struct {
+	mu      sync.Mutex
+	store   *local.Store
+	limits  map[string]Limits
+	onAlert func(Alert)
+	now     func() time.Time
+}
+
+// NewMeter creates a meter storing use under basePath (usually ~/.temper)
+func NewMeter(basePath string, limits map[string]Limits) (*Meter, error) {
+	store, err := local.NewStore(basePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Meter{store: store, limits: limits, now: time.Now}, nil
+}
+
+// OnAlert sets the function alerts are passed to
+func (m *Meter) OnAlert(fn func(Alert)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onAlert = fn
+}
+
+// Capped reports whether the provider has a cap to meter against
+func (m *Meter) Capped(provider string) bool {
+	_, ok := m.limits[provider]
+	return ok
+}
+
+// Record adds a call's tokens to the provider's use this month and raises
+// an alert when that reaches a cap for the first time
+func (m *Meter) Record(provider string, inputTokens, outputTokens int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	u, err := m.load(provider, now)
+	if err != nil {
+		return err
+	}
+	l := m.limits[provider]
+	u.Calls++
+	u.InputTokens += inputTokens
+	u.OutputTokens += outputTokens
+	u.Cost = math.Round((float64(u.InputTokens)*l.InputPrice+float64(u.OutputTokens)*l.OutputPrice)/1e6*1e4) / 1e4
+
+	var alert *Alert
+	if level := l.level(u); level != LevelOK && rank(level) > rank(u.Alerted) {
+		u.Alerted = level
+		alert = &Alert{Provider: provider, Month: u.Month, Level: level, Message: alertMessage(u, l, level), At: now}
+	}
+	if err := m.store.Save(collectionUsage, usageID(provider, u.Month), u); err != nil {
+		return err
+	}
+	if alert != nil && m.onAlert != nil {
+		m.onAlert(*alert)
+	}
+	return nil
+}
+
+// Level returns where the provider's use this month stands
+func (m *Meter) Level(provider string) string {
+	l, ok := m.limits[provider]
+	if !ok {
+		return LevelOK
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, err := m.load(provider, m.now())
+	if err != nil {
+		return LevelOK
+	}
+	return l.level(u)
+}
+
+// Limits returns the provider's caps
+func (m *Meter) Limits(provider string) Limits {
+	return m.limits[provider]
+}
+
+// Statuses returns this month's use of every capped provider, by name
+func (m *Meter) Statuses() ([]Status, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	statuses := make([]Status, 0, len(m.limits))
+	for provider, l := range m.limits {
+		u, err := m.load(provider, now)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, Status{Usage: u, Limits: l, Level: l.level(u)})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Provider < statuses[j].Provider })
+	return statuses, nil
+}
+
+// load reads the provider's use in now's month; m.mu must be held
+func (m *Meter) load(provider string, now time.Time) (Usage, error) {
+	month := now.Format("2006-01")
+	u := Usage{Provider: provider, Month: month}
+	err := m.store.Load(collectionUsage, usageID(provider, month), &u)
+	if err != nil && !errors.Is(err, local.ErrNotFound) {
+		return Usage{}, err
+	}
+	return u, nil
+}
+
+func usageID(provider, month string) string {
+	return provider + "-" + month
+}
+
+func rank(level string) int {
+	switch level {
+	case LevelSoft:
+		return 1
+	case LevelHard:
+		return 2
+	}
+	return 0
+}
+
+func alertMessage(u Usage, l Limits, level string) string {
+	used := fmt.Sprintf("%d tokens", u.InputTokens+u.OutputTokens)
+	if l.InputPrice > 0 || l.OutputPrice > 0 {
+		used += fmt.Sprintf(" ($%.2f)", u.Cost)
+	}
+	if level == LevelSoft {
+		return fmt.Sprintf("%s has used %s this month and passed its soft cap", u.Provider, used)
+	}
+	if l.AtHardCap == AtHardCapLocal {
+		return fmt.Sprintf("%s has used %s this month and reached its hard cap; hints now use the local model", u.Provider, used)
+	}
+	return fmt.Sprintf("%s has used %s this month and reached its hard cap; it is blocked until next month", u.Provider, used)
+}
@@ -0,0 +1,153 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/llm"
+)
+
+type fakeProvider struct {
+	name  string
+	usage llm.Usage
+	got   *llm.Request
+}
+
+func (p *fakeProvider) Name() string            { return p.name }
+func (p *fakeProvider) SupportsStreaming() bool { return true }
+
+func (p *fakeProvider) Generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	p.got = req
+	return &llm.Response{Content: "hint from " + p.name, Usage: p.usage}, nil
+}
+
+func (p *fakeProvider) GenerateStream(ctx context.Context, req *llm.Request) (<-chan llm.StreamChunk, error) {
+	p.got = req
+	ch := make(chan llm.StreamChunk, 2)
+	ch <- llm.StreamChunk{Content: strings.Repeat("x", 40)}
+	ch <- llm.StreamChunk{Done: true}
+	close(ch)
+	return ch, nil
+}
+
+func newTestMeter(t *testing.T, limits map[string]Limits) *Meter {
+	t.Helper()
+	m, err := NewMeter(t.TempDir(), limits)
+	if err != nil {
+		t.Fatalf("NewMeter() error = %v", err)
+	}
+	m.now = func() time.Time { return time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) }
+	return m
+}
+
+func TestFromConfig(t *testing.T) {
+	limits, err := FromConfig(map[string]*config.ProviderConfig{
+		"claude": {Quota: config.QuotaConfig{SoftCost: 5, HardCost: 10, InputPrice: 3, OutputPrice: 15, AtHardCap: "local"}},
+		"openai": {Quota: config.QuotaConfig{HardTokens: 1000}},
+		"ollama": {},
+	})
+	if err != nil {
+		t.Fatalf("FromConfig() error = %v", err)
+	}
+	if len(limits) != 2 || limits["claude"].AtHardCap != AtHardCapLocal || limits["openai"].AtHardCap != AtHardCapBlock {
+		t.Errorf("limits = %+v, want claude local and openai block", limits)
+	}
+
+	for name, q := range map[string]config.QuotaConfig{
+		"negative":        {HardTokens: -1},
+		"unknown action":  {HardTokens: 10, AtHardCap: "downgrade"},
+		"cost, no prices": {HardCost: 10},
+	} {
+		if _, err := FromConfig(map[string]*config.ProviderConfig{"claude": {Quota: q}}); err == nil {
+			t.Errorf("%s: FromConfig() should fail", name)
+		}
+	}
+}
+
+func TestMeter_Record(t *testing.T) {
+	m := newTestMeter(t, map[string]Limits{
+		"claude": {SoftCost: 0.5, HardCost: 0.9, InputPrice: 3, OutputPrice: 15, AtHardCap: AtHardCapBlock},
+	})
+	var alerts []Alert
+	m.OnAlert(func(a Alert) { alerts = append(alerts, a) })
+
+	// 100k input at $3/M plus 20k output at $15/M is $0.60
+	for i := 0; i < 2; i++ {
+		if err := m.Record("claude", 50_000, 10_000); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	if m.Level("claude") != LevelSoft || len(alerts) != 1 || alerts[0].Level != LevelSoft {
+		t.Fatalf("level %s, alerts %+v; want one soft alert", m.Level("claude"), alerts)
+	}
+
+	if err := m.Record("claude", 50_000, 10_000); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if len(alerts) != 2 || alerts[1].Level != LevelHard || !strings.Contains(alerts[1].Message, "$0.90") {
+		t.Errorf("alerts = %+v, want a hard cap alert", alerts)
+	}
+	if err := m.Record("claude", 10, 10); err != nil || len(alerts) != 2 {
+		t.Errorf("alert raised again: %+v", alerts)
+	}
+
+	statuses, err := m.Statuses()
+	if err != nil {
+		t.Fatalf("Statuses() error = %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Month != "2026-10" || statuses[0].Calls != 4 || statuses[0].Level != LevelHard {
+		t.Errorf("Statuses() = %+v", statuses)
+	}
+
+	// A new month starts from zero
+	m.now = func() time.Time { return time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC) }
+	if m.Level("claude") != LevelOK {
+		t.Errorf("Level() in a new month = %s, want ok", m.Level("claude"))
+	}
+}
+
+func TestWrap(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMeter(t, map[string]Limits{
+		"claude": {HardTokens: 100, AtHardCap: AtHardCapBlock},
+		"openai": {HardTokens: 100, AtHardCap: AtHardCapLocal},
+	})
+	ollama := &fakeProvider{name: "ollama"}
+	toOllama := func() (llm.Provider, error) { return ollama, nil }
+
+	if p := m.Wrap(ollama, nil); p != llm.Provider(ollama) {
+		t.Error("an uncapped provider should be returned unchanged")
+	}
+
+	claude := m.Wrap(&fakeProvider{name: "claude", usage: llm.Usage{InputTokens: 80, OutputTokens: 30}}, toOllama)
+	if _, err := claude.Generate(ctx, &llm.Request{}); err != nil {
+		t.Fatalf("Generate() under the cap error = %v", err)
+	}
+	if _, err := claude.Generate(ctx, &llm.Request{}); !errors.Is(err, ErrExhausted) {
+		t.Errorf("Generate() past the cap error = %v, want ErrExhausted", err)
+	}
+
+	openai := m.Wrap(&fakeProvider{name: "openai"}, toOllama)
+	stream, err := openai.GenerateStream(ctx, &llm.Request{Messages: []llm.Message{{Content: strings.Repeat("y", 400)}}})
+	if err != nil {
+		t.Fatalf("GenerateStream() error = %v", err)
+	}
+	for range stream {
+	}
+	// ~100 prompt and ~10 reply tokens, estimated from their length
+	if m.Level("openai") != LevelHard {
+		t.Fatalf("a streamed reply should be metered once the stream closes")
+	}
+
+	resp, err := openai.Generate(ctx, &llm.Request{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("Generate() past the cap error = %v", err)
+	}
+	if resp.Content != "hint from ollama" || ollama.got.Model != "" {
+		t.Errorf("got %q with model %q, want the local model's answer", resp.Content, ollama.got.Model)
+	}
+}
@@ -0,0 +1,167 @@
+// Package redact scrubs secrets and identifying data from text before it
+// leaves the machine. Each detector replaces matches with a
+// [REDACTED:<kind>] marker. Only counts are reported, never the redacted
+// values, so the report itself is safe to store.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Built-in detectors
+const (
+	DetectorAPIKeys       = "api_keys"
+	DetectorEmails        = "emails"
+	DetectorInternalHosts = "internal_hosts"
+)
+
+// DefaultDetectors are enabled when Config.Detectors is empty
+var DefaultDetectors = []string{DetectorAPIKeys, DetectorEmails, DetectorInternalHosts}
+
+// Config selects detectors and adds custom patterns
+type Config struct {
+	Detectors       []string  // built-in detectors to run; empty = DefaultDetectors
+	InternalDomains []string  // hostnames under these domains are redacted, e.g. "corp.example.com"
+	Patterns        []Pattern // additional regexes
+}
+
+// Pattern is a custom redaction rule
+type Pattern struct {
+	Name  string // reported kind
+	Regex string
+}
+
+// Finding counts the redactions of one kind
+type Finding struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+// rule replaces matches of re. When group > 0 only that submatch is
+// replaced, so "password = hunter2" keeps its key name.
+type rule struct {
+	kind  string
+	re    *regexp.Regexp
+	group int
+}
+
+// Redactor applies a fixed set of rules. Safe for concurrent use.
+type Redactor struct {
+	rules []rule
+}
+
+var apiKeyRules = []rule{
+	{kind: "private_key", re: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{kind: "api_key", re: regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_\-]{20,}`)},
+	{kind: "api_key", re: regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_\-]{20,}`)},
+	{kind: "api_key", re: regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`)},
+	{kind: "api_key", re: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{kind: "api_key", re: regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{40,}\b`)},
+	{kind: "api_key", re: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{kind: "api_key", re: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{kind: "api_key", re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)}, // JWT
+	{kind: "secret", re: regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret|token|passw(?:or)?d|pwd)["']?\s*[:=]\s*["']([^"'\s]{8,})["']`), group: 1},
+}
+
+var emailRule = rule{
+	kind: "email",
+	re:   regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}\b`),
+}
+
+// New compiles a redactor from cfg
+func New(cfg Config) (*Redactor, error) {
+	detectors := cfg.Detectors
+	if len(detectors) == 0 {
+		detectors = DefaultDetectors
+	}
+
+	r := &Redactor{}
+	for _, d := range detectors {
+		switch d {
+		case DetectorAPIKeys:
+			r.rules = append(r.rules, apiKeyRules...)
+		case DetectorEmails:
+			r.rules = append(r.rules, emailRule)
+		case DetectorInternalHosts:
+			if re := internalHostRegex(cfg.InternalDomains); re != nil {
+				r.rules = append(r.rules, rule{kind: "internal_host", re: re})
+			}
+		default:
+			return nil, fmt.Errorf("unknown redaction detector %q", d)
+		}
+	}
+
+	for _, p := range cfg.Patterns {
+		if p.Name == "" {
+			return nil, fmt.Errorf("redaction pattern %q needs a name", p.Regex)
+		}
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("redaction pattern %s: %w", p.Name, err)
+		}
+		r.rules = append(r.rules, rule{kind: p.Name, re: re})
+	}
+
+	return r, nil
+}
+
+// internalHostRegex matches the given domains and any of their subdomains.
+// Without configured domains nothing is matched: guessing at suffixes like
+// ".internal" would also hit field accesses in code.
+func internalHostRegex(domains []string) *regexp.Regexp {
+	var alts []string
+	for _, d := range domains {
+		d = strings.Trim(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d != "" {
+			alts = append(alts, regexp.QuoteMeta(d))
+		}
+	}
+	if len(alts) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)*(?:` + strings.Join(alts, "|") + `)\b`)
+}
+
+// Redact returns text with every match replaced and a count per kind
+func (r *Redactor) Redact(text string) (string, []Finding) {
+	counts := map[string]int{}
+	for _, ru := range r.rules {
+		marker := "[REDACTED:" + ru.kind + "]"
+		if ru.group == 0 {
+			text = ru.re.ReplaceAllStringFunc(text, func(string) string {
+				counts[ru.kind]++
+				return marker
+			})
+			continue
+		}
+		text = replaceGroup(text, ru.re, ru.group, marker, func() { counts[ru.kind]++ })
+	}
+
+	findings := make([]Finding, 0, len(counts))
+	for kind, n := range counts {
+		findings = append(findings, Finding{Kind: kind, Count: n})
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Kind < findings[j].Kind })
+	return text, findings
+}
+
+// replaceGroup replaces only the given submatch of each match
+func replaceGroup(text string, re *regexp.Regexp, group int, marker string, hit func()) string {
+	var sb strings.Builder
+	last := 0
+	for _, m := range re.FindAllStringSubmatchIndex(text, -1) {
+		start, end := m[2*group], m[2*group+1]
+		if start < 0 || strings.HasPrefix(text[start:end], "[REDACTED:") {
+			continue // already scrubbed by an earlier rule
+		}
+		sb.WriteString(text[last:start])
+		sb.WriteString(marker)
+		last = end
+		hit()
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
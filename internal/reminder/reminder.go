@@ -0,0 +1,195 @@
+// Package reminder nudges the learner to practice. The daemon has no way to
+// show a desktop notification itself, so the scheduler queues reminders and
+// the CLI notifier (temper remind watch) or an editor plugin polls for them.
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/profile"
+)
+
+const (
+	// maxPending bounds the queue when nobody is collecting reminders
+	maxPending = 10
+	// pendingTTL drops reminders nobody dismissed, so a notifier started
+	// the next morning doesn't replay yesterday's
+	pendingTTL = 12 * time.Hour
+)
+
+// Reminder is one queued nudge
+type Reminder struct {
+	ID         string               `json:"id"`
+	Title      string               `json:"title"`
+	Message    string               `json:"message"`
+	Streak     int                  `json:"streak"`
+	DueReviews []profile.ReviewItem `json:"due_reviews"`
+	CreatedAt  time.Time            `json:"created_at"`
+}
+
+// HistorySource returns the learner's exercise attempts
+type HistorySource func(ctx context.Context) ([]profile.ExerciseAttempt, error)
+
+// Scheduler checks the practice windows and queues reminders
+type Scheduler struct {
+	windows []Window
+	history HistorySource
+
+	mu      sync.Mutex
+	pending []Reminder
+	fired   map[string]bool // window occurrences already reminded
+}
+
+// NewScheduler creates a scheduler for the given windows
+func NewScheduler(windows []Window, history HistorySource) *Scheduler {
+	return &Scheduler{
+		windows: windows,
+		history: history,
+		fired:   make(map[string]bool),
+	}
+}
+
+// Check queues a reminder if now falls in a practice window that hasn't
+// had one yet and there is something to practice. It returns the new
+// reminder, or nil.
+func (s *Scheduler) Check(ctx context.Context, now time.Time) (*Reminder, error) {
+	key := ""
+	for _, w := range s.windows {
+		if k, ok := w.occurrence(now); ok {
+			key = k
+			break
+		}
+	}
+	if key == "" {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	done := s.fired[key]
+	s.mu.Unlock()
+	if done {
+		return nil, nil
+	}
+
+	history, err := s.history(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load history: %w", err)
+	}
+	streak, practicedToday := profile.PracticeStreak(history, now)
+	due := profile.DueReviews(history, now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fired[key] = true
+	if practicedToday && len(due) == 0 {
+		return nil, nil
+	}
+
+	r := Reminder{
+		ID:         key,
+		Title:      "Time to practice",
+		Streak:     streak,
+		DueReviews: due,
+		CreatedAt:  now,
+	}
+	r.Message = message(streak, practicedToday, len(due))
+
+	s.pending = append(s.pending, r)
+	if len(s.pending) > maxPending {
+		s.pending = s.pending[len(s.pending)-maxPending:]
+	}
+	return &r, nil
+}
+
+func message(streak int, practicedToday bool, due int) string {
+	var parts []string
+	switch {
+	case practicedToday:
+	case streak > 0:
+		parts = append(parts, fmt.Sprintf("Keep your %d-day streak going.", streak))
+	default:
+		parts = append(parts, "A short session today keeps the skills fresh.")
+	}
+	switch due {
+	case 0:
+	case 1:
+		parts = append(parts, "1 exercise is due for review.")
+	default:
+		parts = append(parts, fmt.Sprintf("%d exercises are due for review.", due))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Pending returns the reminders not yet dismissed
+func (s *Scheduler) Pending(now time.Time) []Reminder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	live := s.pending[:0]
+	for _, r := range s.pending {
+		if now.Sub(r.CreatedAt) < pendingTTL {
+			live = append(live, r)
+		}
+	}
+	s.pending = live
+	return append([]Reminder{}, live...)
+}
+
+// Dismiss removes a reminder once a notifier has shown it
+func (s *Scheduler) Dismiss(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, r := range s.pending {
+		if r.ID == id {
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Start checks the windows every interval until ctx is done
+func (s *Scheduler) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				r, err := s.Check(ctx, now)
+				if err != nil {
+					slog.Warn("reminder check failed", "error", err)
+				} else if r != nil {
+					slog.Info("practice reminder queued", "id", r.ID, "due_reviews", len(r.DueReviews))
+				}
+			}
+		}
+	}()
+}
+
+// FromConfig builds the scheduler's windows. It returns nil windows when
+// reminders are off.
+func FromConfig(cfg config.RemindersConfig) ([]Window, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	windows := make([]Window, 0, len(cfg.Windows))
+	for i, wc := range cfg.Windows {
+		w, err := ParseWindow(wc)
+		if err != nil {
+			return nil, fmt.Errorf("reminders.windows[%d]: %w", i, err)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
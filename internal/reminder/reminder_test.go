@@ -0,0 +1,150 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/profile"
+)
+
+func at(day, clock string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", day+" "+clock, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func mustWindow(t *testing.T, cfg config.PracticeWindow) Window {
+	t.Helper()
+	w, err := ParseWindow(cfg)
+	if err != nil {
+		t.Fatalf("ParseWindow: %v", err)
+	}
+	return w
+}
+
+func TestParseWindow_Errors(t *testing.T) {
+	for _, cfg := range []config.PracticeWindow{
+		{Start: "6pm", End: "20:00"},
+		{Start: "18:00", End: "25:00"},
+		{Start: "18:00", End: "18:00"},
+		{Start: "18:00", End: "20:00", Days: []string{"someday"}},
+	} {
+		if _, err := ParseWindow(cfg); err == nil {
+			t.Errorf("ParseWindow(%+v) should fail", cfg)
+		}
+	}
+}
+
+func TestWindow_Occurrence(t *testing.T) {
+	// 2026-10-16 is a Friday
+	evening := mustWindow(t, config.PracticeWindow{Start: "18:00", End: "20:00", Days: []string{"Friday"}})
+	late := mustWindow(t, config.PracticeWindow{Start: "23:00", End: "01:00"})
+
+	tests := []struct {
+		name string
+		w    Window
+		t    time.Time
+		key  string
+	}{
+		{"inside", evening, at("2026-10-16", "18:30"), "2026-10-16-1800"},
+		{"before", evening, at("2026-10-16", "17:59"), ""},
+		{"end is exclusive", evening, at("2026-10-16", "20:00"), ""},
+		{"wrong day", evening, at("2026-10-17", "18:30"), ""},
+		{"past midnight, evening side", late, at("2026-10-16", "23:30"), "2026-10-16-2300"},
+		{"past midnight, morning side", late, at("2026-10-17", "00:30"), "2026-10-16-2300"},
+		{"past midnight, outside", late, at("2026-10-17", "12:00"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ok := tt.w.occurrence(tt.t)
+			if ok != (tt.key != "") || key != tt.key {
+				t.Errorf("occurrence = %q, %v; want %q", key, ok, tt.key)
+			}
+		})
+	}
+}
+
+func TestScheduler_Check(t *testing.T) {
+	w := mustWindow(t, config.PracticeWindow{Start: "18:00", End: "20:00"})
+	now := at("2026-10-16", "18:05")
+
+	var history []profile.ExerciseAttempt
+	s := NewScheduler([]Window{w}, func(ctx context.Context) ([]profile.ExerciseAttempt, error) {
+		return history, nil
+	})
+
+	// Practiced yesterday, nothing today: remind, once per window
+	yesterday := now.AddDate(0, 0, -1)
+	history = []profile.ExerciseAttempt{{ExerciseID: "go-v1/basics/hello", StartedAt: yesterday, CompletedAt: &yesterday, Success: true}}
+
+	r, err := s.Check(context.Background(), now)
+	if err != nil || r == nil {
+		t.Fatalf("Check = %v, %v; want a reminder", r, err)
+	}
+	if r.Streak != 1 || !strings.Contains(r.Message, "1-day streak") {
+		t.Errorf("unexpected reminder: %+v", r)
+	}
+	if len(r.DueReviews) != 1 {
+		t.Errorf("hello was done yesterday and is due for review: %+v", r.DueReviews)
+	}
+	if again, _ := s.Check(context.Background(), now.Add(10*time.Minute)); again != nil {
+		t.Error("a window should only remind once")
+	}
+
+	if pending := s.Pending(now); len(pending) != 1 {
+		t.Fatalf("Pending = %d, want 1", len(pending))
+	}
+	if !s.Dismiss(r.ID) || len(s.Pending(now)) != 0 {
+		t.Error("Dismiss should remove the reminder")
+	}
+	if s.Dismiss(r.ID) {
+		t.Error("second Dismiss should report nothing removed")
+	}
+}
+
+func TestScheduler_NothingToDo(t *testing.T) {
+	w := mustWindow(t, config.PracticeWindow{Start: "18:00", End: "20:00"})
+	now := at("2026-10-16", "18:05")
+	today := now.Add(-time.Hour)
+
+	s := NewScheduler([]Window{w}, func(ctx context.Context) ([]profile.ExerciseAttempt, error) {
+		return []profile.ExerciseAttempt{{ExerciseID: "go-v1/basics/hello", StartedAt: today, CompletedAt: &today, Success: true}}, nil
+	})
+
+	if r, _ := s.Check(context.Background(), now); r != nil {
+		t.Errorf("practiced today with nothing due should not remind: %+v", r)
+	}
+	if r, _ := s.Check(context.Background(), at("2026-10-16", "21:00")); r != nil {
+		t.Error("outside the window should not remind")
+	}
+}
+
+func TestPending_Expires(t *testing.T) {
+	w := mustWindow(t, config.PracticeWindow{Start: "18:00", End: "20:00"})
+	now := at("2026-10-16", "18:05")
+	s := NewScheduler([]Window{w}, func(ctx context.Context) ([]profile.ExerciseAttempt, error) {
+		return nil, nil
+	})
+
+	if r, _ := s.Check(context.Background(), now); r == nil {
+		t.Fatal("expected a reminder with no practice at all")
+	}
+	if pending := s.Pending(now.Add(pendingTTL)); len(pending) != 0 {
+		t.Errorf("stale reminders should expire, got %d", len(pending))
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	if w, err := FromConfig(config.RemindersConfig{Windows: []config.PracticeWindow{{Start: "x"}}}); err != nil || w != nil {
+		t.Errorf("disabled reminders should not parse windows: %v, %v", w, err)
+	}
+	_, err := FromConfig(config.RemindersConfig{Enabled: true, Windows: []config.PracticeWindow{{Start: "x", End: "10:00"}}})
+	if err == nil || !strings.Contains(err.Error(), "reminders.windows[0]") {
+		t.Errorf("expected a window error naming the entry, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package reminder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday,
+	"wed": time.Wednesday, "thu": time.Thursday, "fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Window is a parsed practice window. Times are minutes after local
+// midnight; a window whose end is before its start runs past midnight.
+type Window struct {
+	days       map[time.Weekday]bool // empty = every day
+	start, end int
+}
+
+// ParseWindow validates a configured window
+func ParseWindow(cfg config.PracticeWindow) (Window, error) {
+	w := Window{days: make(map[time.Weekday]bool)}
+
+	var err error
+	if w.start, err = parseClock(cfg.Start); err != nil {
+		return w, fmt.Errorf("start: %w", err)
+	}
+	if w.end, err = parseClock(cfg.End); err != nil {
+		return w, fmt.Errorf("end: %w", err)
+	}
+	if w.start == w.end {
+		return w, fmt.Errorf("start and end are both %s", cfg.Start)
+	}
+
+	for _, d := range cfg.Days {
+		name := strings.ToLower(strings.TrimSpace(d))
+		if len(name) > 3 {
+			name = name[:3] // "monday" → "mon"
+		}
+		day, ok := weekdays[name]
+		if !ok {
+			return w, fmt.Errorf("unknown day %q (want mon..sun)", d)
+		}
+		w.days[day] = true
+	}
+	return w, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// occurrence reports whether t falls in the window and, if so, a key
+// naming this occurrence: the date and time the window opened
+func (w Window) occurrence(t time.Time) (string, bool) {
+	minute := t.Hour()*60 + t.Minute()
+	opened := t
+
+	switch {
+	case w.start < w.end:
+		if minute < w.start || minute >= w.end {
+			return "", false
+		}
+	case minute >= w.start:
+		// evening part of a window running past midnight
+	case minute < w.end:
+		opened = t.AddDate(0, 0, -1) // opened yesterday evening
+	default:
+		return "", false
+	}
+
+	if len(w.days) > 0 && !w.days[opened.Weekday()] {
+		return "", false
+	}
+	return fmt.Sprintf("%s-%02d%02d", opened.Format(time.DateOnly), w.start/60, w.start%60), true
+}
@@ -0,0 +1,128 @@
+// Package replay re-runs a recorded session's pairing requests and diffs
+// the new answers against the recorded ones. Replaying with another
+// provider, or on a build with changed prompts, shows what the change
+// does to real learner sessions before it ships.
+package replay
+
+import (
+	"sort"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+// Step is one recorded intervention and the code the learner had when
+// asking for it
+type Step struct {
+	Intervention *session.Intervention
+	RunID        string // run the code comes from; empty for the session's code
+	Code         map[string]string
+}
+
+// Plan orders a session's interventions oldest first and pairs each with
+// its code: the run it referenced, else the latest run before it, else
+// the session's current code
+func Plan(sess *session.Session, runs []*session.Run, interventions []*session.Intervention) []Step {
+	ordered := append([]*session.Run(nil), runs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+	})
+	byID := make(map[string]*session.Run, len(ordered))
+	for _, run := range ordered {
+		byID[run.ID] = run
+	}
+
+	recorded := append([]*session.Intervention(nil), interventions...)
+	sort.SliceStable(recorded, func(i, j int) bool {
+		return recorded[i].CreatedAt.Before(recorded[j].CreatedAt)
+	})
+
+	steps := make([]Step, 0, len(recorded))
+	for _, in := range recorded {
+		var run *session.Run
+		if in.RunID != nil {
+			run = byID[*in.RunID]
+		}
+		if run == nil {
+			for _, r := range ordered {
+				if r.CreatedAt.After(in.CreatedAt) {
+					break
+				}
+				run = r
+			}
+		}
+
+		step := Step{Intervention: in, Code: sess.Code}
+		if run != nil {
+			step.RunID, step.Code = run.ID, run.Code
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// Answer is an intervention's level, type and content
+type Answer struct {
+	Level   domain.InterventionLevel `json:"level"`
+	Type    domain.InterventionType  `json:"type"`
+	Content string                   `json:"content"`
+}
+
+// Result compares one recorded intervention with its replay
+type Result struct {
+	InterventionID string            `json:"intervention_id"`
+	Intent         domain.Intent     `json:"intent"`
+	RunID          string            `json:"run_id,omitempty"`
+	Recorded       Answer            `json:"recorded"`
+	Replayed       *Answer           `json:"replayed,omitempty"`
+	Error          string            `json:"error,omitempty"` // why the replay produced no answer
+	Changed        bool              `json:"changed"`
+	Diff           *session.FileDiff `json:"diff,omitempty"` // recorded → replayed content; nil when equal
+}
+
+// Report is the outcome of replaying a session
+type Report struct {
+	SessionID string   `json:"session_id"`
+	Provider  string   `json:"provider"`
+	Results   []Result `json:"results"`
+	Changed   int      `json:"changed"`
+	Failed    int      `json:"failed"`
+}
+
+// Compare records the replayed intervention for a step, or the error that
+// kept it from being generated
+func Compare(step Step, replayed *domain.Intervention, err error) Result {
+	in := step.Intervention
+	res := Result{
+		InterventionID: in.ID,
+		Intent:         in.Intent,
+		RunID:          step.RunID,
+		Recorded:       Answer{Level: in.Level, Type: in.Type, Content: in.Content},
+	}
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	res.Replayed = &Answer{Level: replayed.Level, Type: replayed.Type, Content: replayed.Content}
+	diff := session.DiffCode(
+		map[string]string{"content": in.Content},
+		map[string]string{"content": replayed.Content},
+	)
+	if len(diff.Files) > 0 {
+		res.Diff = &diff.Files[0]
+	}
+	res.Changed = res.Diff != nil || *res.Replayed != res.Recorded
+	return res
+}
+
+// Add appends a result and updates the totals
+func (r *Report) Add(res Result) {
+	r.Results = append(r.Results, res)
+	switch {
+	case res.Error != "":
+		r.Failed++
+	case res.Changed:
+		r.Changed++
+	}
+}
@@ -0,0 +1,72 @@
+package replay
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/session"
+)
+
+func TestPlan(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	sess := &session.Session{ID: "s", Code: map[string]string{"main.go": "current"}}
+	runs := []*session.Run{
+		{ID: "r2", CreatedAt: t0.Add(2 * time.Minute), Code: map[string]string{"main.go": "second"}},
+		{ID: "r1", CreatedAt: t0.Add(time.Minute), Code: map[string]string{"main.go": "first"}},
+	}
+	r1 := "r1"
+	interventions := []*session.Intervention{
+		{ID: "late", CreatedAt: t0.Add(3 * time.Minute)},               // latest run before it: r2
+		{ID: "early", CreatedAt: t0},                                   // no run yet: session code
+		{ID: "pinned", CreatedAt: t0.Add(3 * time.Minute), RunID: &r1}, // names r1
+	}
+
+	steps := Plan(sess, runs, interventions)
+
+	want := []struct{ id, run, code string }{
+		{"early", "", "current"},
+		{"late", "r2", "second"},
+		{"pinned", "r1", "first"},
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("got %d steps, want %d", len(steps), len(want))
+	}
+	for i, w := range want {
+		got := steps[i]
+		if got.Intervention.ID != w.id || got.RunID != w.run || got.Code["main.go"] != w.code {
+			t.Errorf("step %d = %s/%s/%s, want %s/%s/%s", i, got.Intervention.ID, got.RunID, got.Code["main.go"], w.id, w.run, w.code)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	step := Step{Intervention: &session.Intervention{
+		ID: "i", Intent: domain.IntentHint, Level: domain.L1CategoryHint, Type: domain.TypeHint,
+		Content: "Check the loop bounds.",
+	}}
+
+	same := Compare(step, &domain.Intervention{Level: domain.L1CategoryHint, Type: domain.TypeHint, Content: "Check the loop bounds."}, nil)
+	if same.Changed || same.Diff != nil {
+		t.Errorf("identical replay marked changed: %+v", same)
+	}
+
+	changed := Compare(step, &domain.Intervention{Level: domain.L1CategoryHint, Type: domain.TypeHint, Content: "Look at the loop condition."}, nil)
+	if !changed.Changed || changed.Diff == nil || changed.Diff.Additions != 1 || changed.Diff.Deletions != 1 {
+		t.Errorf("changed replay = %+v", changed)
+	}
+
+	failed := Compare(step, nil, errors.New("provider down"))
+	if failed.Error != "provider down" || failed.Replayed != nil {
+		t.Errorf("failed replay = %+v", failed)
+	}
+
+	var report Report
+	report.Add(same)
+	report.Add(changed)
+	report.Add(failed)
+	if report.Changed != 1 || report.Failed != 1 || len(report.Results) != 3 {
+		t.Errorf("report totals = changed %d, failed %d", report.Changed, report.Failed)
+	}
+}
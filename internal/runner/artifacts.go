@@ -0,0 +1,221 @@
+package runner
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// coverProfile is where the coverage artifact's profile is written,
+// outside the workspace so it isn't collected itself
+const coverProfile = "/tmp/temper-cover.out"
+
+// ArtifactRunner is implemented by executors that can collect the files
+// a run produces
+type ArtifactRunner interface {
+	// RunArtifacts builds the binaries, coverage reports and generated
+	// files the specs declare and copies them out of the sandbox
+	RunArtifacts(ctx context.Context, code map[string]string, specs []domain.ArtifactSpec) (*ArtifactResult, error)
+}
+
+// ArtifactResult contains the files an artifact run collected
+type ArtifactResult struct {
+	OK      bool // every step succeeded; files that were produced are collected either way
+	Output  string
+	Files   []ArtifactFile
+	Missing []string // declared paths the run didn't produce, or that didn't fit the size limit
+}
+
+// ArtifactFile is one collected file. A directory artifact collects one
+// per file in it.
+type ArtifactFile struct {
+	Name string // the spec it was declared by
+	Kind string
+	Path string // relative to the workspace
+	Data []byte
+}
+
+// collectedFile is a regular file copied out of a container
+type collectedFile struct {
+	path string // relative to the workspace
+	data []byte
+}
+
+// RunArtifacts runs the steps the specs need in one container, then
+// collects what they wrote. Only Go code has artifacts.
+func (e *DockerExecutor) RunArtifacts(ctx context.Context, code map[string]string, specs []domain.ArtifactSpec) (*ArtifactResult, error) {
+	if _, ok := languageFor(ctx); ok {
+		return nil, fmt.Errorf("artifacts are only collected for Go code")
+	}
+	for _, spec := range specs {
+		if err := spec.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
+	defer cancel()
+
+	codeWithMod := make(map[string]string, len(code)+1)
+	for k, v := range code {
+		codeWithMod[k] = v
+	}
+	if _, ok := codeWithMod["go.mod"]; !ok {
+		codeWithMod["go.mod"] = defaultGoMod(GoVersionFromContext(ctx))
+	}
+
+	opts, violations := e.dependencyOptions(code, e.buildOptions(ctx))
+	if len(violations) > 0 {
+		return &ArtifactResult{Output: violationMessage(violations)}, nil
+	}
+
+	paths := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		p, _ := domain.CleanArtifactPath(spec.Path)
+		paths = append(paths, p)
+	}
+	cmd := []string{"sh", "-c", artifactScript(ctx, specs)}
+	output, exitCode, files, err := e.runAndCollect(execCtx, codeWithMod, cmd, opts, paths)
+	if err != nil {
+		return nil, err
+	}
+	return matchArtifacts(specs, files, output, exitCode), nil
+}
+
+// artifactScript returns the shell script producing the specs' files.
+// Each step runs even if one before it failed, so one broken artifact
+// doesn't cost the others.
+func artifactScript(ctx context.Context, specs []domain.ArtifactSpec) string {
+	flags := strings.Join(quoteAll(buildFlags(ctx)), " ")
+	pattern := shellQuote(packagePattern(ctx))
+
+	var steps []string
+	generated, covered := false, false
+	for _, spec := range specs {
+		if spec.Kind == domain.ArtifactFile && !generated {
+			// Generated files come first: the build may need them
+			steps = append([]string{fmt.Sprintf("go generate %s %s", flags, pattern)}, steps...)
+			generated = true
+		}
+	}
+	for _, spec := range specs {
+		out, _ := domain.CleanArtifactPath(spec.Path)
+		mkdir := "mkdir -p " + shellQuote(path.Dir(out))
+		switch spec.Kind {
+		case domain.ArtifactBinary:
+			steps = append(steps, fmt.Sprintf("%s && go build %s -o %s %s", mkdir, flags, shellQuote(out), shellQuote(debugPackage(ctx))))
+		case domain.ArtifactCoverage:
+			if !covered {
+				steps = append(steps, fmt.Sprintf("go test %s -coverprofile=%s %s", flags, coverProfile, pattern))
+				covered = true
+			}
+			steps = append(steps, fmt.Sprintf("%s && go tool cover -html=%s -o %s", mkdir, coverProfile, shellQuote(out)))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("status=0\n")
+	for _, step := range steps {
+		fmt.Fprintf(&b, "%s || status=1\n", step)
+	}
+	b.WriteString("exit $status")
+	return b.String()
+}
+
+// matchArtifacts assigns the collected files to the specs they were
+// declared by, keeping within MaxArtifactBytes
+func matchArtifacts(specs []domain.ArtifactSpec, files []collectedFile, output string, exitCode int) *ArtifactResult {
+	result := &ArtifactResult{OK: exitCode == 0, Output: output}
+	var total int64
+	for _, spec := range specs {
+		root, _ := domain.CleanArtifactPath(spec.Path)
+		found := false
+		for _, f := range files {
+			if f.path != root && !strings.HasPrefix(f.path, root+"/") {
+				continue
+			}
+			found = true
+			if total+int64(len(f.data)) > domain.MaxArtifactBytes {
+				result.Missing = append(result.Missing, f.path)
+				continue
+			}
+			total += int64(len(f.data))
+			result.Files = append(result.Files, ArtifactFile{Name: spec.Name, Kind: spec.Kind, Path: f.path, Data: f.data})
+		}
+		if !found {
+			result.Missing = append(result.Missing, root)
+		}
+	}
+	return result
+}
+
+// copyFilesFromContainer copies the regular files at the given workspace
+// paths, directories included, out of a stopped container. A path that
+// can't be copied, usually because the run didn't produce it, is skipped.
+func (e *DockerExecutor) copyFilesFromContainer(ctx context.Context, containerID string, paths []string) ([]collectedFile, error) {
+	var files []collectedFile
+	var total int64
+	for _, p := range paths {
+		reader, _, err := e.client.CopyFromContainer(ctx, containerID, "/workspace/"+p)
+		if err != nil {
+			slog.Debug("artifact not collected", "path", p, "error", err)
+			continue
+		}
+		// Entries are named from the path's last element on
+		base := path.Dir(p)
+		tr := tar.NewReader(reader)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				reader.Close()
+				return nil, err
+			}
+			if hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+			name, ok := domain.CleanArtifactPath(path.Join(base, hdr.Name))
+			if !ok {
+				continue
+			}
+			// Read one byte past the limit, so matchArtifacts sees the
+			// file doesn't fit without holding all of it
+			remaining := domain.MaxArtifactBytes - total
+			if remaining < 0 {
+				remaining = 0
+			}
+			data, err := io.ReadAll(io.LimitReader(tr, remaining+1))
+			if err != nil {
+				reader.Close()
+				return nil, err
+			}
+			if int64(len(data)) <= remaining {
+				total += int64(len(data))
+			}
+			files = append(files, collectedFile{path: name, data: data})
+		}
+		reader.Close()
+	}
+	return files, nil
+}
+
+// shellQuote quotes s for sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func quoteAll(args []string) []string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	return quoted
+}
@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestArtifactScript(t *testing.T) {
+	ctx := WithBuildEnv(context.Background(), domain.BuildEnv{BuildTags: []string{"tools"}})
+	script := artifactScript(ctx, []domain.ArtifactSpec{
+		{Name: "cli", Kind: domain.ArtifactBinary, Path: "bin/cli"},
+		{Name: "coverage", Kind: domain.ArtifactCoverage, Path: "report/coverage.html"},
+		{Name: "models", Kind: domain.ArtifactFile, Path: "gen"},
+	})
+
+	want := []string{
+		"status=0",
+		`go generate '-tags=tools' './...' || status=1`,
+		`mkdir -p 'bin' && go build '-tags=tools' -o 'bin/cli' '.' || status=1`,
+		`go test '-tags=tools' -coverprofile=/tmp/temper-cover.out './...' || status=1`,
+		`mkdir -p 'report' && go tool cover -html=/tmp/temper-cover.out -o 'report/coverage.html' || status=1`,
+		"exit $status",
+	}
+	if got := strings.Split(script, "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("script:\n%s\nwant:\n%s", script, strings.Join(want, "\n"))
+	}
+}
+
+func TestMatchArtifacts(t *testing.T) {
+	specs := []domain.ArtifactSpec{
+		{Name: "cli", Kind: domain.ArtifactBinary, Path: "bin/cli"},
+		{Name: "models", Kind: domain.ArtifactFile, Path: "gen"},
+		{Name: "coverage", Kind: domain.ArtifactCoverage, Path: "coverage.html"},
+	}
+	files := []collectedFile{
+		{path: "bin/cli", data: []byte("bin")},
+		{path: "gen/user.go", data: []byte("package gen")},
+		{path: "gen/order.go", data: []byte("package gen")},
+		{path: "generated.go", data: []byte("not under gen/")},
+	}
+
+	result := matchArtifacts(specs, files, "", 1)
+	if result.OK {
+		t.Error("OK = true for a failed step")
+	}
+	var got []string
+	for _, f := range result.Files {
+		got = append(got, f.Name+":"+f.Path)
+	}
+	if want := "cli:bin/cli models:gen/user.go models:gen/order.go"; strings.Join(got, " ") != want {
+		t.Errorf("files = %v, want %s", got, want)
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != "coverage.html" {
+		t.Errorf("missing = %v", result.Missing)
+	}
+}
+
+func TestMatchArtifacts_SizeLimit(t *testing.T) {
+	specs := []domain.ArtifactSpec{{Name: "cli", Kind: domain.ArtifactBinary, Path: "bin/cli"}}
+	files := []collectedFile{{path: "bin/cli", data: make([]byte, domain.MaxArtifactBytes+1)}}
+
+	result := matchArtifacts(specs, files, "", 0)
+	if len(result.Files) != 0 || len(result.Missing) != 1 {
+		t.Errorf("files = %d, missing = %v; want the oversized binary left out", len(result.Files), result.Missing)
+	}
+}
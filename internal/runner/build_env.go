@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+type buildEnvKey struct{}
+
+// WithBuildEnv returns a context asking the executor to build and test
+// with the given variables, build tags and test flags. The caller
+// validates env; an empty one leaves ctx unchanged.
+func WithBuildEnv(ctx context.Context, env domain.BuildEnv) context.Context {
+	if env.IsZero() {
+		return ctx
+	}
+	return context.WithValue(ctx, buildEnvKey{}, env)
+}
+
+// BuildEnvFromContext returns the build environment set with WithBuildEnv
+func BuildEnvFromContext(ctx context.Context) domain.BuildEnv {
+	if ctx == nil {
+		return domain.BuildEnv{}
+	}
+	env, _ := ctx.Value(buildEnvKey{}).(domain.BuildEnv)
+	return env
+}
+
+// buildFlags returns the go build flags for the build environment on ctx:
+// -tags when it sets build tags, nothing otherwise
+func buildFlags(ctx context.Context) []string {
+	if tags := BuildEnvFromContext(ctx).BuildTags; len(tags) > 0 {
+		return []string{"-tags=" + strings.Join(tags, ",")}
+	}
+	return nil
+}
+
+// goTestCommand returns the go test arguments after "go": JSON output,
+// the build environment's tags, the scoped packages, then flags followed
+// by the build environment's test flags
+func goTestCommand(ctx context.Context, flags []string) []string {
+	args := append([]string{"test", "-json"}, buildFlags(ctx)...)
+	args = append(args, packagePattern(ctx))
+	args = append(args, flags...)
+	return append(args, BuildEnvFromContext(ctx).TestFlags...)
+}
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestGoTestCommand(t *testing.T) {
+	ctx := context.Background()
+	if WithBuildEnv(ctx, domain.BuildEnv{}) != ctx {
+		t.Error("WithBuildEnv with nothing set should return ctx unchanged")
+	}
+	if got := goTestCommand(ctx, []string{"-v"}); !reflect.DeepEqual(got, []string{"test", "-json", "./...", "-v"}) {
+		t.Errorf("goTestCommand() = %v", got)
+	}
+
+	ctx = WithScope(WithBuildEnv(ctx, domain.BuildEnv{
+		BuildTags: []string{"integration", "slow"},
+		TestFlags: []string{"-count=1"},
+	}), "svc")
+	want := []string{"test", "-json", "-tags=integration,slow", "./svc/...", "-v", "-count=1"}
+	if got := goTestCommand(ctx, []string{"-v"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("goTestCommand(build env) = %v; want %v", got, want)
+	}
+}
+
+func TestGoExecutor_BuildEnv(t *testing.T) {
+	code := map[string]string{
+		"main.go": "package main\n\nfunc main() {}\n",
+		"flag_on.go": `//go:build feature
+
+package main
+
+const feature = true
+`,
+		"flag_off.go": `//go:build !feature
+
+package main
+
+const feature = false
+`,
+		"main_test.go": `package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEnv(t *testing.T) {
+	if !feature {
+		t.Error("built without the feature tag")
+	}
+	if os.Getenv("APP_GREETING") != "hej" {
+		t.Errorf("APP_GREETING = %q", os.Getenv("APP_GREETING"))
+	}
+}
+`,
+	}
+	exec := NewGoExecutor(false)
+
+	result, err := exec.Test(context.Background(), code, nil)
+	if err != nil {
+		t.Fatalf("Test() error = %v", err)
+	}
+	if result.OK {
+		t.Fatal("Test() without the build environment should fail")
+	}
+
+	ctx := WithBuildEnv(context.Background(), domain.BuildEnv{
+		Env:       map[string]string{"APP_GREETING": "hej"},
+		BuildTags: []string{"feature"},
+	})
+	result, err = exec.Test(ctx, code, nil)
+	if err != nil {
+		t.Fatalf("Test() error = %v", err)
+	}
+	if !result.OK {
+		t.Errorf("Test() with the build environment failed: %s", result.Output)
+	}
+	build, err := exec.Build(ctx, code)
+	if err != nil || !build.OK {
+		t.Errorf("Build() = %+v, %v", build, err)
+	}
+}
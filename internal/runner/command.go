@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// shellSyntax are the characters that would make a command line a shell
+// script. Commands run as plain argv, so they're refused rather than
+// passed through literally.
+const shellSyntax = "|&;<>()$`\\\"'*?"
+
+// ParseCommand splits a custom command line into its arguments. Commands
+// don't go through a shell: pipes, redirects, quotes, globs and variables
+// are refused.
+func ParseCommand(line string) ([]string, error) {
+	argv := strings.Fields(line)
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	for _, arg := range argv {
+		if i := strings.IndexAny(arg, shellSyntax); i >= 0 {
+			return nil, fmt.Errorf("command %q uses shell syntax %q; commands run without a shell", line, arg[i])
+		}
+	}
+	return argv, nil
+}
+
+// CommandAllowlist is the commands a user approved to run. An entry
+// allows every command whose arguments start with the entry's: "go vet"
+// allows "go vet ./...", and "make check" allows only that target.
+type CommandAllowlist []string
+
+// Allows reports whether argv starts with the arguments of an entry
+func (a CommandAllowlist) Allows(argv []string) bool {
+	for _, entry := range a {
+		prefix := strings.Fields(entry)
+		if len(prefix) == 0 || len(prefix) > len(argv) {
+			continue
+		}
+		match := true
+		for i, arg := range prefix {
+			if argv[i] != arg {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// CommandRunner is implemented by executors that can run a custom command
+// in the sandbox
+type CommandRunner interface {
+	RunCommand(ctx context.Context, code map[string]string, argv []string) (*CommandResult, error)
+}
+
+// CommandResult contains the result of a custom command
+type CommandResult struct {
+	OK       bool
+	Output   string
+	ExitCode int
+	Duration time.Duration
+}
+
+// RunCommand runs argv in the workspace, in the image and with the
+// limits the code's language runs with. Go code gets a go.mod and its
+// build environment, as for a build.
+func (e *DockerExecutor) RunCommand(ctx context.Context, code map[string]string, argv []string) (*CommandResult, error) {
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
+	defer cancel()
+
+	files, opts := code, e.buildOptions(ctx)
+	if cfg, ok := languageFor(ctx); ok {
+		files, opts = withInitFiles(cfg, code), e.languageOptions(ctx, cfg)
+	} else {
+		files = make(map[string]string, len(code)+1)
+		for k, v := range code {
+			files[k] = v
+		}
+		if _, ok := files["go.mod"]; !ok {
+			files["go.mod"] = defaultGoMod(GoVersionFromContext(ctx))
+		}
+		var violations []string
+		if opts, violations = e.dependencyOptions(code, opts); len(violations) > 0 {
+			return &CommandResult{ExitCode: -1, Output: violationMessage(violations)}, nil
+		}
+	}
+
+	start := time.Now()
+	output, exitCode, err := e.runInContainerWith(execCtx, files, argv, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &CommandResult{
+		OK:       exitCode == 0,
+		Output:   output,
+		ExitCode: exitCode,
+		Duration: time.Since(start),
+	}, nil
+}
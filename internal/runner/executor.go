@@ -4,17 +4,24 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/client"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 // Executor defines the interface for code execution
@@ -22,7 +29,8 @@ type Executor interface {
 	// RunFormat runs gofmt and returns diff
 	RunFormat(ctx context.Context, code map[string]string) (*FormatResult, error)
 
-	// RunFormatFix runs gofmt and returns formatted code
+	// RunFormatFix runs the context's Formatter (goimports by default)
+	// and returns formatted code
 	RunFormatFix(ctx context.Context, code map[string]string) (map[string]string, error)
 
 	// RunBuild runs go build
@@ -40,17 +48,33 @@ type FormatResult struct {
 
 // BuildResult contains the result of go build
 type BuildResult struct {
-	OK     bool
-	Output string
+	OK         bool
+	Output     string
+	Violations []string // imports rejected by the dependency policy
 }
 
 // TestResult contains the result of go test
 type TestResult struct {
-	OK       bool
-	Output   string
-	Duration time.Duration
+	OK         bool
+	Output     string
+	Duration   time.Duration
+	Violations []string // imports rejected by the dependency policy
+}
+
+// DebugRunner is implemented by executors that can rerun tests under a
+// debugger. It is optional: callers type-assert and skip debugging when
+// the executor does not support it.
+type DebugRunner interface {
+	// RunTestsDebug runs the tests under Delve and captures program state
+	// at the first failure or panic
+	RunTestsDebug(ctx context.Context, code map[string]string, flags []string) (*DebugResult, error)
 }
 
+// DebugResult contains the result of a debugger run
+type DebugResult struct {
+	Output   string
+	Snapshot *domain.DebugSnapshot // nil if the debugger never stopped
+}
 
 // Helper functions
 func createTempCodeDir(code map[string]string) (string, error) {
@@ -101,23 +125,37 @@ func sanitizeRelativePath(path string) (string, error) {
 	return cleaned, nil
 }
 
+// RunnerLabel marks the containers a DockerExecutor creates, so ones
+// orphaned by a crashed daemon can be found
+const RunnerLabel = "temper.runner"
+
 // DockerExecutor executes code in Docker containers
 type DockerExecutor struct {
-	client     *client.Client
-	baseImage  string
-	memoryMB   int64
-	cpuLimit   float64
-	networkOff bool
-	timeout    time.Duration
+	client       *client.Client
+	baseImage    string
+	debugImage   string
+	toolchain    string // image template for packs pinning another Go version
+	runtime      string
+	platform     *ocispec.Platform // nil = Docker's default
+	dependencies DependencyPolicy
+	memoryMB     int64
+	cpuLimit     float64
+	networkOff   bool
+	timeout      time.Duration
 }
 
 // DockerConfig holds Docker executor configuration
 type DockerConfig struct {
-	BaseImage  string
-	MemoryMB   int64
-	CPULimit   float64
-	NetworkOff bool
-	Timeout    time.Duration
+	BaseImage      string
+	DebugImage     string // image with dlv on PATH; defaults to BaseImage
+	ToolchainImage string // template for packs pinning another Go version; defaults to DefaultToolchainImage
+	Runtime        string // Docker runtime (e.g. runsc); empty uses the daemon default
+	Platform       string // e.g. linux/arm64; empty uses the Docker daemon's native platform
+	Dependencies   DependencyPolicy
+	MemoryMB       int64
+	CPULimit       float64
+	NetworkOff     bool
+	Timeout        time.Duration
 }
 
 // DefaultDockerConfig returns sensible defaults for Docker execution
@@ -145,6 +183,20 @@ func NewDockerExecutor(cfg DockerConfig) (*DockerExecutor, error) {
 	if cfg.Timeout == 0 {
 		cfg.Timeout = 120 * time.Second
 	}
+	if cfg.DebugImage == "" {
+		cfg.DebugImage = cfg.BaseImage
+	}
+	if err := cfg.Dependencies.Validate(); err != nil {
+		return nil, err
+	}
+	var platform *ocispec.Platform
+	if cfg.Platform != "" {
+		p, err := ParsePlatform(cfg.Platform)
+		if err != nil {
+			return nil, err
+		}
+		platform = p
+	}
 
 	// Try to create client with environment settings first
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
@@ -185,16 +237,91 @@ func NewDockerExecutor(cfg DockerConfig) (*DockerExecutor, error) {
 		}
 	}
 
+	// A hardened runtime is a security boundary: refuse to start rather
+	// than silently fall back to runc when it isn't installed.
+	if cfg.Runtime != "" {
+		if err := checkRuntime(cli, cfg.Runtime); err != nil {
+			cli.Close()
+			return nil, err
+		}
+	}
+
+	// Run images built for the machine rather than whatever variant
+	// happens to be pulled: amd64 images on Apple Silicon run under
+	// emulation and are several times slower
+	if platform == nil {
+		platform = daemonPlatform(cli)
+	}
+
 	return &DockerExecutor{
-		client:     cli,
-		baseImage:  cfg.BaseImage,
-		memoryMB:   cfg.MemoryMB,
-		cpuLimit:   cfg.CPULimit,
-		networkOff: cfg.NetworkOff,
-		timeout:    cfg.Timeout,
+		client:       cli,
+		baseImage:    cfg.BaseImage,
+		debugImage:   cfg.DebugImage,
+		toolchain:    cfg.ToolchainImage,
+		runtime:      cfg.Runtime,
+		platform:     platform,
+		dependencies: cfg.Dependencies,
+		memoryMB:     cfg.MemoryMB,
+		cpuLimit:     cfg.CPULimit,
+		networkOff:   cfg.NetworkOff,
+		timeout:      cfg.Timeout,
 	}, nil
 }
 
+// checkRuntime verifies the Docker daemon has the named runtime registered
+func checkRuntime(cli *client.Client, runtime string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info, err := cli.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("inspect docker runtimes: %w", err)
+	}
+	return runtimeRegistered(runtime, info.Runtimes)
+}
+
+// runtimeRegistered checks runtime against the ones the Docker daemon
+// reports, naming them and what to do when it's missing
+func runtimeRegistered(runtime string, registered map[string]system.RuntimeWithStatus) error {
+	if _, ok := registered[runtime]; ok {
+		return nil
+	}
+	names := make([]string, 0, len(registered))
+	for name := range registered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("runner.docker: runtime %q is not registered with the Docker daemon (it has %s): %s",
+		runtime, strings.Join(names, ", "), runtimeHint(runtime))
+}
+
+// runtimeError wraps a container create or start failure. Under a
+// hardened runtime it names the runtime, since a runtime that is
+// registered but can't run on this host fails only here.
+func (e *DockerExecutor) runtimeError(msg string, err error) error {
+	switch e.runtime {
+	case "":
+		return fmt.Errorf("%s: %w", msg, err)
+	case firecrackerRuntime:
+		return fmt.Errorf("%s with runtime %q (%s): %w", msg, e.runtime, runtimeHint(e.runtime), err)
+	}
+	return fmt.Errorf("%s with runtime %q: %w", msg, e.runtime, err)
+}
+
+// daemonPlatform returns the Docker daemon's native platform, or nil when
+// it can't be asked
+func daemonPlatform(cli *client.Client) *ocispec.Platform {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info, err := cli.Info(ctx)
+	if err != nil {
+		slog.Warn("inspect docker platform", "error", err)
+		return nil
+	}
+	return NativePlatform(info.Architecture)
+}
+
 // Close closes the Docker client
 func (e *DockerExecutor) Close() error {
 	if e.client != nil {
@@ -203,29 +330,92 @@ func (e *DockerExecutor) Close() error {
 	return nil
 }
 
+// Ping checks the Docker daemon is still reachable
+func (e *DockerExecutor) Ping(ctx context.Context) error {
+	if e.client == nil {
+		return errors.New("no docker client")
+	}
+	_, err := e.client.Ping(ctx)
+	return err
+}
+
+// RemoveOrphans force-removes runner containers left behind by a daemon
+// that died mid-run. Only call it when no daemon is executing code.
+func (e *DockerExecutor) RemoveOrphans(ctx context.Context) (int, error) {
+	if e.client == nil {
+		return 0, nil
+	}
+	containers, err := e.client.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", RunnerLabel+"=true")),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list runner containers: %w", err)
+	}
+
+	removed := 0
+	for _, c := range containers {
+		if err := e.client.ContainerRemove(ctx, c.ID, container.RemoveOptions{Force: true}); err != nil {
+			slog.Warn("remove orphaned runner container", "id", c.ID, "error", err)
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
+
 // EnsureImage pulls the base image if not present
 func (e *DockerExecutor) EnsureImage(ctx context.Context) error {
-	// Check if image exists locally
-	_, err := e.client.ImageInspect(ctx, e.baseImage)
-	if err == nil {
-		return nil // Image exists
+	_, err := e.ensureImage(ctx, e.baseImage)
+	return err
+}
+
+// ensureImage makes sure ref is present for the executor's platform,
+// pulling it when it's missing or only the wrong architecture is local.
+// It returns the platform to create containers with: nil when the image
+// has no variant for the platform and has to run under emulation.
+func (e *DockerExecutor) ensureImage(ctx context.Context, ref string) (*ocispec.Platform, error) {
+	local, inspectErr := e.client.ImageInspect(ctx, ref)
+	if inspectErr == nil && e.matchesPlatform(local) {
+		return e.platform, nil
 	}
 
-	slog.Info("pulling Docker image", "image", e.baseImage)
-	reader, err := e.client.ImagePull(ctx, e.baseImage, image.PullOptions{})
+	slog.Info("pulling Docker image", "image", ref, "platform", PlatformString(e.platform))
+	reader, err := e.client.ImagePull(ctx, ref, image.PullOptions{Platform: PlatformString(e.platform)})
 	if err != nil {
-		return fmt.Errorf("failed to pull image %s: %w", e.baseImage, err)
+		if inspectErr == nil {
+			slog.Warn("runner image has no variant for this platform; running it under emulation",
+				"image", ref, "image_arch", local.Architecture, "platform", PlatformString(e.platform))
+			return nil, nil
+		}
+		return nil, fmt.Errorf("runner image %s is missing and could not be pulled (try `temper runner pull`): %w", ref, err)
 	}
 	defer reader.Close()
 
 	// Wait for pull to complete
 	_, _ = io.Copy(io.Discard, reader)
-	return nil
+
+	if pulled, err := e.client.ImageInspect(ctx, ref); err == nil && !e.matchesPlatform(pulled) {
+		slog.Warn("runner image has no variant for this platform; running it under emulation",
+			"image", ref, "image_arch", pulled.Architecture, "platform", PlatformString(e.platform))
+		return nil, nil
+	}
+	return e.platform, nil
+}
+
+// matchesPlatform reports whether a local image runs natively on the
+// executor's platform
+func (e *DockerExecutor) matchesPlatform(img image.InspectResponse) bool {
+	return e.platform == nil || NormalizeArch(img.Architecture) == e.platform.Architecture
 }
 
 func (e *DockerExecutor) RunFormat(ctx context.Context, code map[string]string) (*FormatResult, error) {
+	if cfg, ok := languageFor(ctx); ok {
+		return e.runLanguageFormat(ctx, cfg, code)
+	}
+
 	// Create execution context with timeout
-	execCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
 	defer cancel()
 
 	// Build the command to format all Go files
@@ -254,6 +444,10 @@ func (e *DockerExecutor) RunFormat(ctx context.Context, code map[string]string)
 }
 
 func (e *DockerExecutor) RunFormatFix(ctx context.Context, code map[string]string) (map[string]string, error) {
+	if cfg, ok := languageFor(ctx); ok {
+		return e.runLanguageFormatFix(ctx, cfg, code)
+	}
+
 	// Create a copy of the code map for the result
 	result := make(map[string]string)
 	for filename, content := range code {
@@ -261,20 +455,21 @@ func (e *DockerExecutor) RunFormatFix(ctx context.Context, code map[string]strin
 	}
 
 	// Create execution context with timeout
-	execCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
 	defer cancel()
 
 	// Format each Go file individually to get the formatted output
+	formatter := FormatterFromContext(ctx)
 	for filename := range code {
 		if !strings.HasSuffix(filename, ".go") {
 			continue
 		}
 
-		// Run gofmt on the file and capture output
-		cmd := []string{"gofmt", "/workspace/" + filename}
+		// Run the formatter on the file and capture output
+		cmd := formatCommand(formatter, "/workspace/"+filename)
 		output, exitCode, err := e.runInContainer(execCtx, code, cmd)
 		if err != nil || exitCode != 0 {
-			// If gofmt fails (syntax error), keep original
+			// If formatting fails (syntax error), keep original
 			continue
 		}
 		result[filename] = output
@@ -284,8 +479,12 @@ func (e *DockerExecutor) RunFormatFix(ctx context.Context, code map[string]strin
 }
 
 func (e *DockerExecutor) RunBuild(ctx context.Context, code map[string]string) (*BuildResult, error) {
+	if cfg, ok := languageFor(ctx); ok {
+		return e.runLanguageBuild(ctx, cfg, code)
+	}
+
 	// Create execution context with timeout
-	execCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
 	defer cancel()
 
 	// Add go.mod if not present
@@ -294,12 +493,17 @@ func (e *DockerExecutor) RunBuild(ctx context.Context, code map[string]string) (
 		codeWithMod[k] = v
 	}
 	if _, ok := codeWithMod["go.mod"]; !ok {
-		codeWithMod["go.mod"] = "module exercise\n\ngo 1.22\n"
+		codeWithMod["go.mod"] = defaultGoMod(GoVersionFromContext(ctx))
+	}
+
+	opts, violations := e.dependencyOptions(code, e.buildOptions(ctx))
+	if len(violations) > 0 {
+		return &BuildResult{OK: false, Output: violationMessage(violations), Violations: violations}, nil
 	}
 
 	// Run go build
-	cmd := []string{"go", "build", "./..."}
-	output, exitCode, err := e.runInContainer(execCtx, codeWithMod, cmd)
+	cmd := append(append([]string{"go", "build"}, buildFlags(ctx)...), packagePattern(ctx))
+	output, exitCode, err := e.runInContainerWith(execCtx, codeWithMod, cmd, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -311,8 +515,13 @@ func (e *DockerExecutor) RunBuild(ctx context.Context, code map[string]string) (
 }
 
 func (e *DockerExecutor) RunTests(ctx context.Context, code map[string]string, flags []string) (*TestResult, error) {
+	// Flags are go test's; other languages run their own test command
+	if cfg, ok := languageFor(ctx); ok {
+		return e.runLanguageTests(ctx, cfg, code)
+	}
+
 	// Create execution context with timeout
-	execCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
 	defer cancel()
 
 	// Add go.mod if not present
@@ -321,13 +530,18 @@ func (e *DockerExecutor) RunTests(ctx context.Context, code map[string]string, f
 		codeWithMod[k] = v
 	}
 	if _, ok := codeWithMod["go.mod"]; !ok {
-		codeWithMod["go.mod"] = "module exercise\n\ngo 1.22\n"
+		codeWithMod["go.mod"] = defaultGoMod(GoVersionFromContext(ctx))
+	}
+
+	opts, violations := e.dependencyOptions(code, e.buildOptions(ctx))
+	if len(violations) > 0 {
+		return &TestResult{OK: false, Output: violationMessage(violations), Violations: violations}, nil
 	}
 
 	// Run go test with JSON output
 	start := time.Now()
-	cmd := append([]string{"go", "test", "-json", "./..."}, flags...)
-	output, exitCode, err := e.runInContainer(execCtx, codeWithMod, cmd)
+	cmd := append([]string{"go"}, goTestCommand(ctx, flags)...)
+	output, exitCode, err := e.runInContainerWith(execCtx, codeWithMod, cmd, opts)
 	duration := time.Since(start)
 
 	if err != nil {
@@ -341,35 +555,221 @@ func (e *DockerExecutor) RunTests(ctx context.Context, code map[string]string, f
 	}, nil
 }
 
+// delveInit is the Delve script used by RunTestsDebug. Delve already stops
+// on unrecovered panics; the breakpoint catches t.Error/t.Fatal failures.
+const delveInit = `break testing.(*common).Fail
+continue
+stack -full 20
+exit
+`
+
+// RunTestsDebug runs the tests under Delve and captures the stack and
+// locals at the first failing assertion or panic. The debug image must
+// have dlv on PATH.
+func (e *DockerExecutor) RunTestsDebug(ctx context.Context, code map[string]string, flags []string) (*DebugResult, error) {
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
+	defer cancel()
+
+	codeWithMod := make(map[string]string)
+	for k, v := range code {
+		codeWithMod[k] = v
+	}
+	if _, ok := codeWithMod["go.mod"]; !ok {
+		codeWithMod["go.mod"] = defaultGoMod(GoVersionFromContext(ctx))
+	}
+	codeWithMod["_dlv.init"] = delveInit
+
+	// Under dlv the flags go to the test binary, not to go test, so only
+	// the ones the binary understands are forwarded (-v -> -test.v).
+	var testArgs []string
+	for _, f := range append(append([]string(nil), flags...), BuildEnvFromContext(ctx).TestFlags...) {
+		if f == "-v" || strings.HasPrefix(f, "-run=") {
+			testArgs = append(testArgs, "-test."+strings.TrimPrefix(f, "-"))
+		}
+	}
+	script := "command -v dlv >/dev/null || { echo 'dlv not found in runner image' >&2; exit 127; }; " +
+		"exec dlv test " + debugPackage(ctx) + " --allow-non-terminal-interactive=true --init _dlv.init"
+	if tags := buildFlags(ctx); len(tags) > 0 {
+		script += " --build-flags=" + tags[0]
+	}
+	if len(testArgs) > 0 {
+		script += " -- " + strings.Join(testArgs, " ")
+	}
+
+	opts, violations := e.dependencyOptions(code, containerOptions{
+		image: e.debugImage,
+		// ptrace is required for the debugger to attach to the test
+		// binary. Docker's default seccomp profile allows it with the
+		// capability, so the syscall filter stays on.
+		capAdd: []string{"SYS_PTRACE"},
+		env:    BuildEnvFromContext(ctx).EnvList(),
+	})
+	if len(violations) > 0 {
+		return nil, fmt.Errorf("debug run: %s", violationMessage(violations))
+	}
+
+	output, exitCode, err := e.runInContainerWith(execCtx, codeWithMod, []string{"sh", "-c", script}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if exitCode == 127 {
+		return nil, fmt.Errorf("debug run: dlv not available in image %s", e.debugImage)
+	}
+
+	return &DebugResult{
+		Output:   output,
+		Snapshot: NewParser().ParseDelveOutput(output),
+	}, nil
+}
+
+// containerOptions overrides the defaults used by runInContainer
+type containerOptions struct {
+	image  string
+	capAdd []string
+	env    []string
+	tidy   bool // run `go mod tidy` before cmd
+
+	// resolve, when set, is the environment of a networked `go mod tidy`
+	// step run in its own container before cmd. Its go.mod and go.sum
+	// replace the code's, and cmd reads the modules it downloaded from
+	// the read-only module cache with the network as configured.
+	resolve []string
+}
+
 // runInContainer executes a command in a Docker container with the given code
 func (e *DockerExecutor) runInContainer(ctx context.Context, code map[string]string, cmd []string) (string, int, error) {
+	return e.runInContainerWith(ctx, code, cmd, containerOptions{image: e.imageFor(ctx)})
+}
+
+// buildOptions returns the container options for go build and go test:
+// the image for ctx and the variables of its build environment
+func (e *DockerExecutor) buildOptions(ctx context.Context) containerOptions {
+	return containerOptions{image: e.imageFor(ctx), env: BuildEnvFromContext(ctx).EnvList()}
+}
+
+// imageFor returns the image to run code in, honoring an image override
+// and a Go version pinned on ctx by the exercise pack
+func (e *DockerExecutor) imageFor(ctx context.Context) string {
+	base := e.baseImage
+	if o := OverridesFromContext(ctx); o.Image != "" {
+		base = o.Image
+	}
+	return ToolchainImage(base, e.toolchain, GoVersionFromContext(ctx))
+}
+
+// timeoutFor returns the run timeout, honoring an override on ctx
+func (e *DockerExecutor) timeoutFor(ctx context.Context) time.Duration {
+	if o := OverridesFromContext(ctx); o.Timeout > 0 {
+		return o.Timeout
+	}
+	return e.timeout
+}
+
+// memoryFor returns the container memory limit in MB, honoring an
+// override on ctx
+func (e *DockerExecutor) memoryFor(ctx context.Context) int64 {
+	if o := OverridesFromContext(ctx); o.MemoryMB > 0 {
+		return o.MemoryMB
+	}
+	return e.memoryMB
+}
+
+func (e *DockerExecutor) runInContainerWith(ctx context.Context, code map[string]string, cmd []string, opts containerOptions) (string, int, error) {
+	output, exitCode, _, err := e.runAndCollect(ctx, code, cmd, opts, nil)
+	return output, exitCode, err
+}
+
+// runAndCollect runs cmd like runInContainerWith, then copies the files
+// at the workspace-relative paths collect out of the container
+func (e *DockerExecutor) runAndCollect(ctx context.Context, code map[string]string, cmd []string, opts containerOptions, collect []string) (string, int, []collectedFile, error) {
+	if opts.resolve != nil {
+		resolved, output, err := e.resolveModules(ctx, code, opts)
+		if err != nil || resolved == nil {
+			return output, 1, nil, err
+		}
+		code = resolved
+	}
+	return e.runContainer(ctx, code, cmd, opts, collect, false)
+}
+
+// resolveModules runs `go mod tidy` with the network on and the module
+// cache writable, so the run after it needs neither. Only go.mod, go.sum
+// and the Go sources reach the container; tidy reads imports without
+// building or running anything. It returns the code with the tidied
+// go.mod and go.sum, or nil and tidy's output if tidy failed.
+func (e *DockerExecutor) resolveModules(ctx context.Context, code map[string]string, opts containerOptions) (map[string]string, string, error) {
+	sources := make(map[string]string)
+	for name, content := range code {
+		if name == "go.mod" || name == "go.sum" || strings.HasSuffix(name, ".go") {
+			sources[name] = content
+		}
+	}
+	step := containerOptions{image: opts.image, env: opts.resolve}
+	output, exitCode, files, err := e.runContainer(ctx, sources, []string{"go", "mod", "tidy"}, step, []string{"go.mod", "go.sum"}, true)
+	if err != nil {
+		return nil, "", fmt.Errorf("resolve modules: %w", err)
+	}
+	if exitCode != 0 {
+		return nil, output, nil
+	}
+
+	resolved := make(map[string]string, len(code)+1)
+	for name, content := range code {
+		resolved[name] = content
+	}
+	for _, f := range files {
+		resolved[f.path] = string(f.data)
+	}
+	return resolved, output, nil
+}
+
+// runContainer runs cmd in a fresh container. resolving marks the module
+// resolve step: it alone gets the network and a writable module cache.
+func (e *DockerExecutor) runContainer(ctx context.Context, code map[string]string, cmd []string, opts containerOptions, collect []string, resolving bool) (string, int, []collectedFile, error) {
 	// Ensure image is available
-	if err := e.EnsureImage(ctx); err != nil {
-		return "", -1, err
+	platform, err := e.ensureImage(ctx, opts.image)
+	if err != nil {
+		return "", -1, nil, err
+	}
+
+	if opts.tidy {
+		cmd = append([]string{"sh", "-c", `go mod tidy && exec "$@"`, "sh"}, cmd...)
 	}
 
 	// Create container configuration
 	containerConfig := &container.Config{
-		Image:           e.baseImage,
+		Image:           opts.image,
 		Cmd:             cmd,
+		Env:             opts.env,
 		WorkingDir:      "/workspace",
-		NetworkDisabled: e.networkOff,
+		NetworkDisabled: e.networkOff && !resolving,
 		Tty:             false,
+		Labels:          map[string]string{RunnerLabel: "true"},
 	}
 
 	// Host configuration with resource limits
 	hostConfig := &container.HostConfig{
 		Resources: container.Resources{
-			Memory:   e.memoryMB * 1024 * 1024,
+			Memory:   e.memoryFor(ctx) * 1024 * 1024,
 			NanoCPUs: int64(e.cpuLimit * 1e9),
 		},
 		AutoRemove: false, // We'll remove it manually after getting output
+		Runtime:    e.runtime,
+		CapAdd:     opts.capAdd,
+	}
+	if resolving || opts.resolve != nil {
+		hostConfig.Mounts = []mount.Mount{{
+			Type:     mount.TypeVolume,
+			Source:   moduleCacheVolume,
+			Target:   moduleCachePath,
+			ReadOnly: !resolving,
+		}}
 	}
 
 	// Create container
-	resp, err := e.client.ContainerCreate(ctx, containerConfig, hostConfig, nil, nil, "")
+	resp, err := e.client.ContainerCreate(ctx, containerConfig, hostConfig, nil, platform, "")
 	if err != nil {
-		return "", -1, fmt.Errorf("failed to create container: %w", err)
+		return "", -1, nil, e.runtimeError("failed to create container", err)
 	}
 	containerID := resp.ID
 
@@ -382,12 +782,12 @@ func (e *DockerExecutor) runInContainer(ctx context.Context, code map[string]str
 
 	// Copy code files to container
 	if err := e.copyFilesToContainer(ctx, containerID, code); err != nil {
-		return "", -1, fmt.Errorf("failed to copy files to container: %w", err)
+		return "", -1, nil, fmt.Errorf("failed to copy files to container: %w", err)
 	}
 
 	// Start container
 	if err := e.client.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
-		return "", -1, fmt.Errorf("failed to start container: %w", err)
+		return "", -1, nil, e.runtimeError("failed to start container", err)
 	}
 
 	// Wait for container to finish
@@ -397,7 +797,7 @@ func (e *DockerExecutor) runInContainer(ctx context.Context, code map[string]str
 	select {
 	case err := <-errCh:
 		if err != nil {
-			return "", -1, fmt.Errorf("error waiting for container: %w", err)
+			return "", -1, nil, fmt.Errorf("error waiting for container: %w", err)
 		}
 	case status := <-statusCh:
 		exitCode = int(status.StatusCode)
@@ -406,7 +806,14 @@ func (e *DockerExecutor) runInContainer(ctx context.Context, code map[string]str
 		killCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		_ = e.client.ContainerKill(killCtx, containerID, "KILL")
-		return "", -1, ctx.Err()
+		return "", -1, nil, ctx.Err()
+	}
+
+	var files []collectedFile
+	if len(collect) > 0 {
+		if files, err = e.copyFilesFromContainer(ctx, containerID, collect); err != nil {
+			return "", exitCode, nil, fmt.Errorf("failed to copy artifacts from container: %w", err)
+		}
 	}
 
 	// Get container logs (stdout + stderr)
@@ -416,17 +823,17 @@ func (e *DockerExecutor) runInContainer(ctx context.Context, code map[string]str
 	}
 	logs, err := e.client.ContainerLogs(ctx, containerID, logOptions)
 	if err != nil {
-		return "", exitCode, fmt.Errorf("failed to get container logs: %w", err)
+		return "", exitCode, nil, fmt.Errorf("failed to get container logs: %w", err)
 	}
 	defer logs.Close()
 
 	// Read logs and strip Docker multiplexing headers
 	output, err := demuxDockerOutput(logs)
 	if err != nil {
-		return "", exitCode, fmt.Errorf("failed to read container output: %w", err)
+		return "", exitCode, nil, fmt.Errorf("failed to read container output: %w", err)
 	}
 
-	return output, exitCode, nil
+	return output, exitCode, files, nil
 }
 
 // copyFilesToContainer copies code files to the container
@@ -457,9 +864,10 @@ func (e *DockerExecutor) copyFilesToContainer(ctx context.Context, containerID s
 	return e.client.CopyToContainer(ctx, containerID, "/workspace", &buf, container.CopyToContainerOptions{})
 }
 
-// demuxDockerOutput removes Docker multiplexing headers from log output
+// demuxDockerOutput removes Docker multiplexing headers from log output,
+// holding at most MaxOutputBytes of it
 func demuxDockerOutput(reader io.Reader) (string, error) {
-	var result bytes.Buffer
+	result := newOutputBuffer(MaxOutputBytes)
 	header := make([]byte, 8)
 
 	for {
@@ -469,8 +877,7 @@ func demuxDockerOutput(reader io.Reader) (string, error) {
 		}
 		if err != nil {
 			// If we can't read a full header, just read the rest directly
-			remaining, _ := io.ReadAll(reader)
-			result.Write(remaining)
+			_, _ = io.Copy(result, reader)
 			break
 		}
 
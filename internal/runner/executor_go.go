@@ -99,8 +99,10 @@ func (e *GoExecutor) Build(ctx context.Context, code map[string]string) (*BuildR
 		os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(modContent), 0644)
 	}
 
-	cmd := exec.CommandContext(ctx, "go", "build", "./...")
+	args := append(append([]string{"build"}, buildFlags(ctx)...), packagePattern(ctx))
+	cmd := exec.CommandContext(ctx, "go", args...)
 	cmd.Dir = tmpDir
+	cmd.Env = append(os.Environ(), BuildEnvFromContext(ctx).EnvList()...)
 	output, err := cmd.CombinedOutput()
 
 	if err != nil {
@@ -131,9 +133,9 @@ func (e *GoExecutor) Test(ctx context.Context, code map[string]string, flags []s
 	}
 
 	start := time.Now()
-	args := append([]string{"test", "-json", "./..."}, flags...)
-	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd := exec.CommandContext(ctx, "go", goTestCommand(ctx, flags)...)
 	cmd.Dir = tmpDir
+	cmd.Env = append(os.Environ(), BuildEnvFromContext(ctx).EnvList()...)
 	output, _ := cmd.CombinedOutput()
 	duration := time.Since(start)
 
@@ -0,0 +1,211 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// maxExplanations caps how many errors a run explains; past the first few
+// a beginner is better served fixing those and running again
+const maxExplanations = 10
+
+var (
+	// file.go:line:col: message, as the compiler and vet print it. Test
+	// log lines (file_test.go:12: got 3) have no column and are left alone.
+	compileErrorRegex = regexp.MustCompile(`^(?:\./)?(\S+\.go):(\d+):(\d+):\s*(.+)$`)
+	runtimeErrorRegex = regexp.MustCompile(`^(?:panic|fatal error): (.+?)(?: \[recovered\])?$`)
+)
+
+// errorRule rewrites one kind of Go error message for a beginner
+type errorRule struct {
+	name    string
+	re      *regexp.Regexp
+	explain func(m []string) string
+}
+
+// errorRules is the offline translation table, most specific first. The
+// explanations say what the error means and where to look; they never
+// spell out the fix for the learner's exercise.
+var errorRules = []errorRule{
+	{"unused_variable", regexp.MustCompile(`^declared and not used: (\w+)$|^(\w+) declared (?:and|but) not used$`), func(m []string) string {
+		return fmt.Sprintf("You created the variable %q but never read it. Go refuses to compile unused variables; use it somewhere or remove it.", first(m[1], m[2]))
+	}},
+	{"unused_import", regexp.MustCompile(`^"([^"]+)" imported and not used`), func(m []string) string {
+		return fmt.Sprintf("The package %q is imported but nothing in this file uses it. Go refuses to compile unused imports; use it or delete the import line.", m[1])
+	}},
+	{"undefined", regexp.MustCompile(`^undefined: (\S+)$`), func(m []string) string {
+		return fmt.Sprintf("Go doesn't know anything called %q here. Check the spelling and capitalization, that it's declared before use, and that it's in scope (a variable declared inside { } only exists inside them).", m[1])
+	}},
+	{"no_field_or_method", regexp.MustCompile(`^(\S+) undefined \(type (.+?) has no field or method (\w+)(?:, but does have (?:field|method) (\w+))?\)$`), func(m []string) string {
+		msg := fmt.Sprintf("Values of type %s don't have a field or method named %q.", m[2], m[3])
+		if m[4] != "" {
+			msg += fmt.Sprintf(" There is one called %q: Go names are case-sensitive, and only capitalized names are visible outside their package.", m[4])
+		} else {
+			msg += " Check the spelling, and whether you meant a different variable or type."
+		}
+		return msg
+	}},
+	{"missing_return", regexp.MustCompile(`^missing return$`), func(m []string) string {
+		return "This function promises to return a value, but there's a way to reach its end without a return statement. Every path through the function needs one, including after loops and in every branch of an if/else."
+	}},
+	{"type_mismatch", regexp.MustCompile(`^cannot use (.+?) \((?:variable|constant|value|untyped \w+ constant)(?: \d+)? of (?:type )?(.+?)\) as (.+?) value in (.+)$`), func(m []string) string {
+		return fmt.Sprintf("%s has type %s, but the %s needs a %s. Go never converts between types on its own; you need a value of the right type or an explicit conversion.", m[1], m[2], m[4], m[3])
+	}},
+	{"mismatched_types", regexp.MustCompile(`^invalid operation: (.+?) \(mismatched types (.+?) and (.+?)\)$`), func(m []string) string {
+		return fmt.Sprintf("%s combines values of type %s and %s. Go only allows operators between values of the same type, so one side has to be converted first.", m[1], m[2], m[3])
+	}},
+	{"assignment_mismatch", regexp.MustCompile(`^assignment mismatch: (\d+) variables? but (.+?) returns? (\d+) values?$`), func(m []string) string {
+		return fmt.Sprintf("%s gives back %s values but there are %s variables on the left. You need one variable per returned value; use _ for any you want to ignore.", m[2], m[3], m[1])
+	}},
+	{"argument_count", regexp.MustCompile(`^(too many|not enough) arguments in call to (.+)$`), func(m []string) string {
+		return fmt.Sprintf("The call to %s passes %s arguments. Compare it with the function's declaration: the number and order of arguments must match its parameters.", m[2], m[1])
+	}},
+	{"no_new_variables", regexp.MustCompile(`^no new variables on left side of :=$`), func(m []string) string {
+		return ":= declares new variables, but every variable on the left already exists. Use = to assign to existing variables."
+	}},
+	{"redeclared", regexp.MustCompile(`^(\S+) redeclared in this block`), func(m []string) string {
+		return fmt.Sprintf("%q is declared twice in the same scope. Each name can only be declared once per block; rename one or use = to assign instead of declaring again.", m[1])
+	}},
+	{"value_not_used", regexp.MustCompile(`^(.+?) \((?:variable|value|constant|untyped \w+ constant)(?: \d+)? of (?:type )?(.+?)\) is not used$`), func(m []string) string {
+		return fmt.Sprintf("%s computes a value that is thrown away. Go wants results to be used: assign it, return it or pass it somewhere.", m[1])
+	}},
+	{"string_immutable", regexp.MustCompile(`^cannot assign to (\S+\[.+\]) \((?:neither addressable nor a map index expression|value of type byte)\)$`), func(m []string) string {
+		return fmt.Sprintf("%s can't be assigned to: strings in Go can't be changed in place. Build a new string, or work on a []byte or []rune copy.", m[1])
+	}},
+	{"missing_method", regexp.MustCompile(`^(?:cannot use .+ as .+ value in .+: )?(\S+) does not implement (\S+) \((.+)\)$`), func(m []string) string {
+		return fmt.Sprintf("%s can't be used as a %s because it doesn't have every method the interface needs (%s). Compare the method names, parameters and receivers with the interface.", m[1], m[2], m[3])
+	}},
+	{"non_bool_condition", regexp.MustCompile(`^non-boolean condition in (\w+) statement$`), func(m []string) string {
+		return fmt.Sprintf("The condition of this %s isn't true/false. Go doesn't treat numbers, strings or nil as booleans; write a comparison such as x != 0.", m[1])
+	}},
+	{"outside_function", regexp.MustCompile(`^syntax error: non-declaration statement outside function body$`), func(m []string) string {
+		return "There's a statement outside of any function. At the top level Go only allows declarations (func, var, const, type, import); check for a missing or extra closing brace above this line."
+	}},
+	{"syntax_error", regexp.MustCompile(`^syntax error: unexpected (.+?)(?:,? (?:expected|in|at|after) (.+))?$`), func(m []string) string {
+		msg := fmt.Sprintf("Go got confused reading the code here: it didn't expect %s.", m[1])
+		if m[2] != "" {
+			msg += fmt.Sprintf(" It was looking for %s.", m[2])
+		}
+		return msg + " The real mistake is often just before this spot: a missing brace, parenthesis or comma."
+	}},
+	{"index_out_of_range", regexp.MustCompile(`^runtime error: index out of range \[(-?\d+)\] with length (\d+)$`), func(m []string) string {
+		n, _ := strconv.Atoi(m[2])
+		if n == 0 {
+			return fmt.Sprintf("The program asked for element %s of something empty. Check the length before indexing, and where the slice or array should have been filled.", m[1])
+		}
+		return fmt.Sprintf("The program asked for element %s of something with %d elements. Valid indexes run from 0 to %d; check loop bounds and off-by-one errors.", m[1], n, n-1)
+	}},
+	{"slice_out_of_range", regexp.MustCompile(`^runtime error: slice bounds out of range (.+)$`), func(m []string) string {
+		return fmt.Sprintf("A slice expression used bounds outside what the slice holds (%s). Both bounds must be within 0 and the length, with low ≤ high.", m[1])
+	}},
+	{"nil_pointer", regexp.MustCompile(`^runtime error: invalid memory address or nil pointer dereference`), func(m []string) string {
+		return "The program used a pointer, map, interface or function that was nil, as if it pointed at something. Find what was never set (often a struct field or a returned value whose error wasn't checked)."
+	}},
+	{"nil_map", regexp.MustCompile(`^assignment to entry in nil map$`), func(m []string) string {
+		return "The program wrote to a map that was never created. A declared map is nil until it's made with make(...) or a map literal."
+	}},
+	{"divide_by_zero", regexp.MustCompile(`^runtime error: integer divide by zero$`), func(m []string) string {
+		return "The program divided an integer by zero. Check where the divisor comes from and what should happen when it's 0."
+	}},
+	{"deadlock", regexp.MustCompile(`^all goroutines are asleep - deadlock!$`), func(m []string) string {
+		return "Every goroutine is waiting on something (a channel, lock or WaitGroup) that will never happen. Look for a send with no receiver, a receive with no sender, or a channel that is never closed."
+	}},
+	{"closed_channel", regexp.MustCompile(`^(send on closed channel|close of closed channel|close of nil channel)$`), func(m []string) string {
+		return fmt.Sprintf("The program did a %s. Only the sender should close a channel, and only once, after its last send.", m[1])
+	}},
+	{"concurrent_map", regexp.MustCompile(`^concurrent map (?:writes|read and map write)$`), func(m []string) string {
+		return "Two goroutines used the same map at once. Plain maps aren't safe for concurrent use; guard it with a sync.Mutex or give it a single owner."
+	}},
+	{"interface_conversion", regexp.MustCompile(`^interface conversion: (.+?) is (.+?), not (.+)$`), func(m []string) string {
+		return fmt.Sprintf("A type assertion expected %s, but the value inside the %s was %s. Use the two-value form v, ok := x.(T) to check before converting.", m[3], m[1], m[2])
+	}},
+	{"test_timeout", regexp.MustCompile(`^test timed out after (.+)$`), func(m []string) string {
+		return fmt.Sprintf("The tests didn't finish within %s. Something is probably looping forever or waiting on a channel or lock that never gets released.", m[1])
+	}},
+}
+
+// ExplainOutput pulls compiler errors and runtime panics out of a run's
+// build and test output and rewrites each in beginner-friendly language.
+// Errors no rule covers come back with an empty Explanation so a caller
+// can try another source.
+func ExplainOutput(buildOutput, testOutput string) []domain.ErrorExplanation {
+	var explanations []domain.ErrorExplanation
+	seen := make(map[string]bool)
+
+	for _, line := range append(outputLines(buildOutput), outputLines(testOutput)...) {
+		if len(explanations) == maxExplanations {
+			break
+		}
+
+		var e domain.ErrorExplanation
+		var message string
+		if m := compileErrorRegex.FindStringSubmatch(line); m != nil {
+			e.File = m[1]
+			e.Line, _ = strconv.Atoi(m[2])
+			message = m[4]
+		} else if m := runtimeErrorRegex.FindStringSubmatch(line); m != nil {
+			message = m[1]
+		} else {
+			continue
+		}
+		if message == "too many errors" {
+			continue
+		}
+
+		e.Original = line
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+
+		for _, rule := range errorRules {
+			if m := rule.re.FindStringSubmatch(message); m != nil {
+				e.Explanation = rule.explain(m)
+				e.Source = domain.ExplanationRule
+				e.Rule = rule.name
+				break
+			}
+		}
+		explanations = append(explanations, e)
+	}
+	return explanations
+}
+
+// outputLines splits output into lines, unwrapping go test -json events
+func outputLines(output string) []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "{") {
+			var event TestEvent
+			if err := json.Unmarshal([]byte(line), &event); err == nil {
+				for _, l := range strings.Split(event.Output, "\n") {
+					if l = strings.TrimSpace(l); l != "" {
+						lines = append(lines, l)
+					}
+				}
+				continue
+			}
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
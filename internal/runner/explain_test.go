@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestExplainOutput_Rules(t *testing.T) {
+	tests := []struct {
+		line string
+		rule string
+		want string // substring of the explanation
+	}{
+		{"./main.go:5:2: declared and not used: count", "unused_variable", `"count"`},
+		{"main.go:5:2: count declared but not used", "unused_variable", `"count"`},
+		{`main.go:4:2: "strings" imported and not used`, "unused_import", `"strings"`},
+		{"main.go:9:9: undefined: Reverse", "undefined", `"Reverse"`},
+		{"main.go:9:4: u.name undefined (type User has no field or method name, but does have field Name)", "no_field_or_method", `"Name"`},
+		{"main.go:12:1: missing return", "missing_return", "return statement"},
+		{"main.go:7:10: cannot use n (variable of type int) as string value in return statement", "type_mismatch", "return statement needs a string"},
+		{"main.go:7:10: invalid operation: a + b (mismatched types int and float64)", "mismatched_types", "type int and float64"},
+		{"main.go:7:7: assignment mismatch: 1 variable but strconv.Atoi returns 2 values", "assignment_mismatch", "use _"},
+		{"main.go:7:12: not enough arguments in call to add", "argument_count", "add"},
+		{"main.go:7:4: no new variables on left side of :=", "no_new_variables", "Use ="},
+		{"main.go:6:2: x redeclared in this block", "redeclared", `"x"`},
+		{"main.go:6:2: a + b (value of type int) is not used", "value_not_used", "thrown away"},
+		{"main.go:6:2: cannot assign to s[0] (neither addressable nor a map index expression)", "string_immutable", "strings"},
+		{"main.go:3:1: syntax error: non-declaration statement outside function body", "outside_function", "brace"},
+		{"main.go:8:2: syntax error: unexpected newline in composite literal; possibly missing comma or }", "syntax_error", "newline"},
+		{"panic: runtime error: index out of range [5] with length 3 [recovered]", "index_out_of_range", "0 to 2"},
+		{"panic: runtime error: invalid memory address or nil pointer dereference", "nil_pointer", "nil"},
+		{"panic: assignment to entry in nil map", "nil_map", "make"},
+		{"fatal error: all goroutines are asleep - deadlock!", "deadlock", "waiting"},
+		{"panic: test timed out after 10m0s", "test_timeout", "10m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got := ExplainOutput(tt.line+"\n", "")
+			if len(got) != 1 {
+				t.Fatalf("got %d explanations, want 1", len(got))
+			}
+			e := got[0]
+			if e.Rule != tt.rule || e.Source != domain.ExplanationRule {
+				t.Errorf("rule = %q (%s), want %q", e.Rule, e.Source, tt.rule)
+			}
+			if !strings.Contains(e.Explanation, tt.want) {
+				t.Errorf("explanation %q should mention %q", e.Explanation, tt.want)
+			}
+			if e.Original != tt.line {
+				t.Errorf("original = %q", e.Original)
+			}
+		})
+	}
+}
+
+func TestExplainOutput_TestJSON(t *testing.T) {
+	testOutput := `{"Action":"run","Test":"TestSum"}
+{"Action":"output","Test":"TestSum","Output":"    sum_test.go:12: Sum() = 3, want 4\n"}
+{"Action":"output","Test":"TestSum","Output":"panic: runtime error: integer divide by zero [recovered]\n"}
+{"Action":"output","Test":"TestSum","Output":"panic: my own message\n"}
+{"Action":"fail","Test":"TestSum"}
+`
+	got := ExplainOutput("", testOutput)
+	if len(got) != 2 {
+		t.Fatalf("got %+v, want the two panics (test log lines aren't errors)", got)
+	}
+	if got[0].Rule != "divide_by_zero" {
+		t.Errorf("rule = %q, want divide_by_zero", got[0].Rule)
+	}
+	if got[1].Explanation != "" || got[1].Original != "panic: my own message" {
+		t.Errorf("unknown panic should come back unexplained, got %+v", got[1])
+	}
+}
+
+func TestExplainOutput_FileAndLimit(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 15; i++ {
+		sb.WriteString("./pkg/main.go:5:2: undefined: x\n") // duplicates collapse
+		sb.WriteString("./pkg/main.go:")
+		sb.WriteString(strings.Repeat("1", i+1))
+		sb.WriteString(":2: undefined: y\n")
+	}
+	sb.WriteString("./pkg/main.go:9:2: too many errors\n")
+
+	got := ExplainOutput(sb.String(), "")
+	if len(got) != maxExplanations {
+		t.Fatalf("got %d explanations, want %d", len(got), maxExplanations)
+	}
+	if got[0].File != "pkg/main.go" || got[0].Line != 5 {
+		t.Errorf("location = %s:%d, want pkg/main.go:5", got[0].File, got[0].Line)
+	}
+}
@@ -0,0 +1,132 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Formatter is the tool Go code is formatted with
+type Formatter string
+
+const (
+	FormatterGofmt     Formatter = "gofmt"
+	FormatterGoimports Formatter = "goimports" // gofmt, plus adding missing imports and removing unused ones
+	FormatterGofumpt   Formatter = "gofumpt"   // goimports, then gofumpt's stricter rules
+)
+
+// DefaultFormatter organizes imports: beginners' builds fail on missing
+// imports more than on anything gofmt fixes
+const DefaultFormatter = FormatterGoimports
+
+// ParseFormatter converts a string to a Formatter; "" is the default
+func ParseFormatter(s string) (Formatter, error) {
+	switch f := Formatter(s); f {
+	case "":
+		return DefaultFormatter, nil
+	case FormatterGofmt, FormatterGoimports, FormatterGofumpt:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported formatter: %s (use gofmt, goimports or gofumpt)", s)
+	}
+}
+
+type formatterKey struct{}
+
+// WithFormatter returns a context asking the executor to format Go code
+// with f. An empty formatter leaves ctx unchanged.
+func WithFormatter(ctx context.Context, f Formatter) context.Context {
+	if f == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, formatterKey{}, f)
+}
+
+// FormatterFromContext returns the formatter requested with
+// WithFormatter, or DefaultFormatter if none is set
+func FormatterFromContext(ctx context.Context) Formatter {
+	if f, ok := ctx.Value(formatterKey{}).(Formatter); ok {
+		return f
+	}
+	return DefaultFormatter
+}
+
+// formatCommand prints the file at path formatted with f. Runner images
+// without goimports or gofumpt fall back to gofmt, so a custom image
+// formats as before; the import changes reported then are simply none.
+func formatCommand(f Formatter, path string) []string {
+	const imports = `if command -v goimports >/dev/null 2>&1; then goimports "$1"; else gofmt "$1"; fi`
+	switch f {
+	case FormatterGoimports:
+		return []string{"sh", "-c", imports, "sh", path}
+	case FormatterGofumpt:
+		return []string{"sh", "-c", "set -o pipefail; (" + imports + `) | if command -v gofumpt >/dev/null 2>&1; then gofumpt; else gofmt; fi`, "sh", path}
+	default:
+		return []string{"gofmt", path}
+	}
+}
+
+// ImportChange is an import the formatter added to or removed from a file
+type ImportChange struct {
+	File   string `json:"file"`
+	Path   string `json:"path"`
+	Change string `json:"change"` // "added" or "removed"
+}
+
+// ImportChanges compares the imports of each Go file before and after
+// formatting. Files that don't parse on either side are skipped.
+func ImportChanges(before, after map[string]string) []ImportChange {
+	var changes []ImportChange
+	for file, formatted := range after {
+		if !strings.HasSuffix(file, ".go") {
+			continue
+		}
+		old, ok := importPaths(file, before[file])
+		if !ok {
+			continue
+		}
+		updated, ok := importPaths(file, formatted)
+		if !ok {
+			continue
+		}
+		for path := range updated {
+			if !old[path] {
+				changes = append(changes, ImportChange{File: file, Path: path, Change: "added"})
+			}
+		}
+		for path := range old {
+			if !updated[path] {
+				changes = append(changes, ImportChange{File: file, Path: path, Change: "removed"})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Change != b.Change {
+			return a.Change < b.Change
+		}
+		return a.Path < b.Path
+	})
+	return changes
+}
+
+func importPaths(file, src string) (map[string]bool, bool) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, src, parser.ImportsOnly)
+	if err != nil {
+		return nil, false
+	}
+	paths := make(map[string]bool, len(f.Imports))
+	for _, imp := range f.Imports {
+		if path, err := strconv.Unquote(imp.Path.Value); err == nil {
+			paths[path] = true
+		}
+	}
+	return paths, true
+}
@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFormatter(t *testing.T) {
+	if f, err := ParseFormatter(""); err != nil || f != FormatterGoimports {
+		t.Errorf("ParseFormatter(\"\") = %q, %v; want goimports", f, err)
+	}
+	if f, err := ParseFormatter("gofumpt"); err != nil || f != FormatterGofumpt {
+		t.Errorf("ParseFormatter(gofumpt) = %q, %v", f, err)
+	}
+	if _, err := ParseFormatter("prettier"); err == nil {
+		t.Error("ParseFormatter(prettier) should fail")
+	}
+}
+
+func TestWithFormatter(t *testing.T) {
+	ctx := context.Background()
+	if got := FormatterFromContext(ctx); got != DefaultFormatter {
+		t.Errorf("FormatterFromContext() = %q, want the default", got)
+	}
+	if got := FormatterFromContext(WithFormatter(ctx, FormatterGofmt)); got != FormatterGofmt {
+		t.Errorf("FormatterFromContext() = %q, want gofmt", got)
+	}
+}
+
+func TestFormatCommand(t *testing.T) {
+	if got := formatCommand(FormatterGofmt, "/workspace/main.go"); !reflect.DeepEqual(got, []string{"gofmt", "/workspace/main.go"}) {
+		t.Errorf("gofmt command = %v", got)
+	}
+	got := formatCommand(FormatterGofumpt, "/workspace/main.go")
+	if got[len(got)-1] != "/workspace/main.go" || !strings.Contains(got[2], "goimports") || !strings.Contains(got[2], "gofumpt") {
+		t.Errorf("gofumpt command = %v, want goimports piped into gofumpt on the file", got)
+	}
+}
+
+func TestImportChanges(t *testing.T) {
+	before := map[string]string{
+		"main.go":  "package main\n\nimport \"os\"\n\nfunc main() { fmt.Println(strings.ToUpper(\"hi\")) }\n",
+		"bad.go":   "package main\n\nfunc {\n",
+		"notes.md": "import \"fmt\"",
+	}
+	after := map[string]string{
+		"main.go":  "package main\n\nimport (\n\t\"fmt\"\n\t\"strings\"\n)\n\nfunc main() { fmt.Println(strings.ToUpper(\"hi\")) }\n",
+		"bad.go":   before["bad.go"],
+		"notes.md": before["notes.md"],
+	}
+	want := []ImportChange{
+		{File: "main.go", Path: "fmt", Change: "added"},
+		{File: "main.go", Path: "strings", Change: "added"},
+		{File: "main.go", Path: "os", Change: "removed"},
+	}
+	if got := ImportChanges(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("ImportChanges() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+const fuzzInputMarker = "--- fuzz input "
+
+// fuzzScript runs go test -fuzz in the package directory given as $1 and,
+// when the fuzzer saves a failing input, prints it after the output
+const fuzzScript = `dir=$1; shift
+out=$(go test "$@" 2>&1); status=$?
+printf '%s\n' "$out"
+if [ $status -ne 0 ]; then
+  f=$(printf '%s\n' "$out" | sed -n 's/.*Failing input written to \(testdata\/fuzz\/[^ ]*\).*/\1/p' | head -n 1)
+  if [ -n "$f" ] && [ -f "$dir/$f" ]; then
+    echo "` + fuzzInputMarker + `$f ---"
+    head -c 4096 "$dir/$f"
+  fi
+fi
+exit $status`
+
+var fuzzInputRegex = regexp.MustCompile(`(?m)^` + fuzzInputMarker + `(\S+) ---\n`)
+
+// FuzzRunner is implemented by executors that can fuzz a target
+type FuzzRunner interface {
+	// RunFuzz runs go test -fuzz on the package in scope for the check's
+	// time budget and captures the failing input if the fuzzer finds one
+	RunFuzz(ctx context.Context, code map[string]string, check domain.FuzzCheck) (*FuzzResult, error)
+}
+
+// FuzzResult contains the result of a fuzz run
+type FuzzResult struct {
+	OK     bool
+	Output string
+	Crash  *domain.FuzzCrash // nil unless the fuzzer saved a failing input
+}
+
+// RunFuzz fuzzes the check's target. The run timeout is on top of the
+// fuzzing budget, so the budget isn't eaten by the build.
+func (e *DockerExecutor) RunFuzz(ctx context.Context, code map[string]string, check domain.FuzzCheck) (*FuzzResult, error) {
+	check = check.WithDefaults()
+	if err := check.Validate(); err != nil {
+		return nil, err
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx)+check.Time)
+	defer cancel()
+
+	codeWithMod := make(map[string]string, len(code)+1)
+	for k, v := range code {
+		codeWithMod[k] = v
+	}
+	if _, ok := codeWithMod["go.mod"]; !ok {
+		codeWithMod["go.mod"] = defaultGoMod(GoVersionFromContext(ctx))
+	}
+
+	opts, violations := e.dependencyOptions(code, e.buildOptions(ctx))
+	if len(violations) > 0 {
+		return &FuzzResult{Output: violationMessage(violations)}, nil
+	}
+
+	pkg := debugPackage(ctx)
+	cmd := append([]string{"sh", "-c", fuzzScript, "sh", pkg}, fuzzArgs(ctx, check)...)
+	output, exitCode, err := e.runInContainerWith(execCtx, codeWithMod, cmd, opts)
+	if err != nil {
+		return nil, err
+	}
+	return parseFuzzOutput(check.Target, output, exitCode), nil
+}
+
+// fuzzArgs returns the go test arguments for fuzzing check's target in
+// the package in scope. Only the target runs: the tests already passed.
+func fuzzArgs(ctx context.Context, check domain.FuzzCheck) []string {
+	args := []string{"-run=^$", "-fuzz=^" + check.Target + "$", "-fuzztime=" + check.Time.String()}
+	args = append(args, buildFlags(ctx)...)
+	return append(args, debugPackage(ctx))
+}
+
+// parseFuzzOutput reads the output of fuzzScript
+func parseFuzzOutput(target, output string, exitCode int) *FuzzResult {
+	result := &FuzzResult{OK: exitCode == 0, Output: output}
+	if result.OK {
+		return result
+	}
+	loc := fuzzInputRegex.FindStringSubmatchIndex(output)
+	if loc == nil {
+		return result
+	}
+	result.Output = output[:loc[0]]
+	result.Crash = &domain.FuzzCrash{
+		Target:     target,
+		CorpusFile: output[loc[2]:loc[3]],
+		Input:      output[loc[1]:],
+		Message:    fuzzFailure(result.Output),
+	}
+	return result
+}
+
+// fuzzFailure returns the lines that say how the target failed: those
+// after its --- FAIL line, up to where go test reports the saved input
+func fuzzFailure(output string) string {
+	var lines []string
+	failing := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "--- FAIL:"):
+			failing = true
+		case !failing || line == "":
+		case strings.HasPrefix(line, "Failing input written to"):
+			return strings.Join(lines, "\n")
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestFuzzScript(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the fuzzer")
+	}
+	code := map[string]string{
+		"go.mod": "module fuzzme\n\ngo 1.22\n",
+		"limit.go": `package limit
+
+func Truncate(s string) string { return s }
+`,
+		"limit_test.go": `package limit
+
+import "testing"
+
+func FuzzTruncate(f *testing.F) {
+	f.Add("a")
+	f.Fuzz(func(t *testing.T, s string) {
+		if len(Truncate(s)) > 3 {
+			t.Fatalf("Truncate(%q) is longer than 3 bytes", s)
+		}
+	})
+}
+`,
+	}
+	dir, err := createTempCodeDir(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeTempDir(dir)
+
+	check := domain.FuzzCheck{Target: "FuzzTruncate", Time: 30 * time.Second}
+	args := append([]string{"-c", fuzzScript, "sh", "."}, fuzzArgs(context.Background(), check)...)
+	cmd := exec.Command("sh", args...)
+	cmd.Dir = dir
+	out, _ := cmd.CombinedOutput()
+
+	result := parseFuzzOutput(check.Target, string(out), cmd.ProcessState.ExitCode())
+	if result.OK {
+		t.Fatalf("fuzzing should find an input over 3 bytes:\n%s", out)
+	}
+	crash := result.Crash
+	if crash == nil {
+		t.Fatalf("no crash captured:\n%s", out)
+	}
+	if !strings.HasPrefix(crash.CorpusFile, "testdata/fuzz/FuzzTruncate/") {
+		t.Errorf("CorpusFile = %q", crash.CorpusFile)
+	}
+	if !strings.HasPrefix(crash.Input, "go test fuzz v1\nstring(") {
+		t.Errorf("Input = %q; want the corpus file", crash.Input)
+	}
+	if !strings.Contains(crash.Message, "longer than 3 bytes") {
+		t.Errorf("Message = %q; want the failure", crash.Message)
+	}
+	if strings.Contains(result.Output, fuzzInputMarker) {
+		t.Error("Output should stop before the captured input")
+	}
+}
+
+func TestParseFuzzOutput(t *testing.T) {
+	if r := parseFuzzOutput("FuzzX", "fuzz: elapsed: 10s\nPASS\n", 0); !r.OK || r.Crash != nil {
+		t.Errorf("passing run = %+v", r)
+	}
+	// A failing seed corpus entry saves nothing
+	r := parseFuzzOutput("FuzzX", "--- FAIL: FuzzX (0.00s)\n    --- FAIL: FuzzX/seed#0 (0.00s)\nFAIL\n", 1)
+	if r.OK || r.Crash != nil {
+		t.Errorf("seed failure = %+v; want a failure without a crash", r)
+	}
+}
@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ImageRef returns the image reference to run. When a digest is pinned it
+// is appended so Docker resolves exactly that image regardless of what the
+// tag currently points at.
+func ImageRef(image, digest string) string {
+	digest = strings.TrimSpace(digest)
+	if digest == "" {
+		return image
+	}
+	if !strings.HasPrefix(digest, "sha256:") {
+		digest = "sha256:" + digest
+	}
+	// Replace an existing pin rather than stacking two
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	return image + "@" + digest
+}
+
+var (
+	imageTagVersionRegex = regexp.MustCompile(`^(\d+\.\d+(?:\.\d+)?)`)
+	goVersionRegex       = regexp.MustCompile(`go version go(\d+\.\d+(?:\.\d+)?)`)
+)
+
+// ExpectedGoVersion infers the Go toolchain version an image should ship
+// from its tag ("golang:1.23-alpine" -> "1.23"). Returns "" when the tag
+// does not start with a version.
+func ExpectedGoVersion(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	slash := strings.LastIndex(image, "/")
+	colon := strings.LastIndex(image, ":")
+	if colon <= slash {
+		return ""
+	}
+	return imageTagVersionRegex.FindString(image[colon+1:])
+}
+
+// ParseGoVersion extracts the version from `go version` output
+// ("go version go1.23.4 linux/arm64" -> "1.23.4")
+func ParseGoVersion(output string) string {
+	m := goVersionRegex.FindStringSubmatch(output)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// GoVersionMatches reports whether actual satisfies expected. Expected may
+// be a prefix: "1.23" matches "1.23.4" but not "1.230".
+func GoVersionMatches(actual, expected string) bool {
+	if expected == "" {
+		return true
+	}
+	return actual == expected || strings.HasPrefix(actual, expected+".")
+}
@@ -0,0 +1,62 @@
+package runner
+
+import "testing"
+
+func TestImageRef(t *testing.T) {
+	tests := []struct {
+		image, digest, want string
+	}{
+		{"golang:1.23-alpine", "", "golang:1.23-alpine"},
+		{"golang:1.23-alpine", "sha256:abc", "golang:1.23-alpine@sha256:abc"},
+		{"golang:1.23-alpine", "abc", "golang:1.23-alpine@sha256:abc"},
+		{"golang:1.23-alpine@sha256:old", "sha256:new", "golang:1.23-alpine@sha256:new"},
+	}
+	for _, tt := range tests {
+		if got := ImageRef(tt.image, tt.digest); got != tt.want {
+			t.Errorf("ImageRef(%q, %q) = %q; want %q", tt.image, tt.digest, got, tt.want)
+		}
+	}
+}
+
+func TestExpectedGoVersion(t *testing.T) {
+	tests := map[string]string{
+		"golang:1.23-alpine":               "1.23",
+		"golang:1.22.5":                    "1.22.5",
+		"registry:5000/golang:1.23":        "1.23",
+		"registry:5000/temper-runner":      "",
+		"temper-runner-sandbox:latest":     "",
+		"golang:1.23-alpine@sha256:abc123": "1.23",
+	}
+	for image, want := range tests {
+		if got := ExpectedGoVersion(image); got != want {
+			t.Errorf("ExpectedGoVersion(%q) = %q; want %q", image, got, want)
+		}
+	}
+}
+
+func TestParseGoVersion(t *testing.T) {
+	if got := ParseGoVersion("go version go1.23.4 linux/arm64\n"); got != "1.23.4" {
+		t.Errorf("ParseGoVersion() = %q; want 1.23.4", got)
+	}
+	if got := ParseGoVersion("sh: go: not found"); got != "" {
+		t.Errorf("ParseGoVersion() = %q; want empty", got)
+	}
+}
+
+func TestGoVersionMatches(t *testing.T) {
+	tests := []struct {
+		actual, expected string
+		want             bool
+	}{
+		{"1.23.4", "1.23", true},
+		{"1.23", "1.23", true},
+		{"1.230.1", "1.23", false},
+		{"1.22.5", "1.23", false},
+		{"1.22.5", "", true},
+	}
+	for _, tt := range tests {
+		if got := GoVersionMatches(tt.actual, tt.expected); got != tt.want {
+			t.Errorf("GoVersionMatches(%q, %q) = %v; want %v", tt.actual, tt.expected, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package runner
+
+import "fmt"
+
+// Isolation selects how strongly learner code is isolated from the host
+type Isolation string
+
+const (
+	// IsolationDocker uses Docker's default runtime (runc)
+	IsolationDocker Isolation = "docker"
+	// IsolationGVisor runs containers under gVisor's user-space kernel
+	IsolationGVisor Isolation = "gvisor"
+	// IsolationFirecracker runs each container in a Firecracker micro-VM
+	// via Kata Containers. Kata 2.x only runs Firecracker through
+	// containerd with the devmapper snapshotter, so the Docker daemon
+	// must be backed by such a setup.
+	IsolationFirecracker Isolation = "firecracker"
+)
+
+// firecrackerRuntime is the runtime Kata registers for Firecracker
+const firecrackerRuntime = "kata-fc"
+
+// defaultRuntimes maps an isolation level to the Docker runtime name it is
+// conventionally registered under in /etc/docker/daemon.json
+var defaultRuntimes = map[Isolation]string{
+	IsolationDocker:      "",
+	IsolationGVisor:      "runsc",
+	IsolationFirecracker: firecrackerRuntime,
+}
+
+// ResolveRuntime returns the Docker runtime for an isolation level. An
+// explicit runtime name overrides the conventional one, for hosts that
+// register runsc or kata under a different name.
+func ResolveRuntime(isolation, runtime string) (string, error) {
+	if isolation == "" {
+		isolation = string(IsolationDocker)
+	}
+	name, ok := defaultRuntimes[Isolation(isolation)]
+	if !ok {
+		return "", fmt.Errorf("unknown runner isolation %q (want docker, gvisor or firecracker)", isolation)
+	}
+	if runtime != "" {
+		return runtime, nil
+	}
+	return name, nil
+}
+
+// runtimeHint explains how to get a runtime working, for errors about it
+func runtimeHint(runtime string) string {
+	if runtime == firecrackerRuntime {
+		return "Kata's Firecracker runtime needs containerd with the devmapper snapshotter and " +
+			"does not start under a plain dockerd; use runner.docker.isolation: gvisor on such hosts"
+	}
+	return "register it in /etc/docker/daemon.json, or set runner.docker.runtime to the name the host uses"
+}
@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/system"
+)
+
+func TestResolveRuntime(t *testing.T) {
+	tests := []struct {
+		isolation, runtime string
+		want               string
+		wantErr            bool
+	}{
+		{"", "", "", false},
+		{"docker", "", "", false},
+		{"gvisor", "", "runsc", false},
+		{"firecracker", "", "kata-fc", false},
+		{"gvisor", "runsc-kvm", "runsc-kvm", false},
+		{"vmware", "", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ResolveRuntime(tt.isolation, tt.runtime)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ResolveRuntime(%q, %q) error = %v; wantErr %v", tt.isolation, tt.runtime, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveRuntime(%q, %q) = %q; want %q", tt.isolation, tt.runtime, got, tt.want)
+		}
+	}
+}
+
+func TestRuntimeRegistered(t *testing.T) {
+	registered := map[string]system.RuntimeWithStatus{"runc": {}, "runsc": {}}
+
+	if err := runtimeRegistered("runsc", registered); err != nil {
+		t.Errorf("runtimeRegistered(runsc) error = %v", err)
+	}
+	err := runtimeRegistered("kata-fc", registered)
+	if err == nil {
+		t.Fatal("runtimeRegistered(kata-fc) should fail when the daemon lacks it")
+	}
+	for _, want := range []string{"runner.docker", `"kata-fc"`, "runc, runsc", "containerd"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err, want)
+		}
+	}
+}
@@ -42,83 +42,122 @@ func ParseLanguage(s string) (Language, error) {
 	return lang, nil
 }
 
+type languageKey struct{}
+
+// WithLanguage returns a context asking the executor to format, build and
+// test code in lang rather than Go. An empty language leaves ctx unchanged.
+func WithLanguage(ctx context.Context, lang Language) context.Context {
+	if lang == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// LanguageFromContext returns the language requested with WithLanguage,
+// or "" if none is set.
+func LanguageFromContext(ctx context.Context) Language {
+	if ctx == nil {
+		return ""
+	}
+	if lang, ok := ctx.Value(languageKey{}).(Language); ok {
+		return lang
+	}
+	return ""
+}
+
 // LanguageConfig contains language-specific configuration
 type LanguageConfig struct {
-	DockerImage    string
-	FormatCommand  []string
-	BuildCommand   []string
-	TestCommand    []string
-	FileExtensions []string
-	InitFiles      map[string]string // e.g., go.mod, package.json
+	DockerImage      string
+	FormatCommand    []string
+	FormatFixCommand []string // formats the file named after it in place
+	BuildCommand     []string
+	TestCommand      []string
+	FileExtensions   []string
+	InitFiles        map[string]string // e.g., go.mod, package.json
+
+	// SourceArgs is set for languages whose build command takes the
+	// source files as arguments; tests then run on what the build made
+	SourceArgs bool
 }
 
 // DefaultLanguageConfigs returns default configurations for all supported languages
 func DefaultLanguageConfigs() map[Language]LanguageConfig {
 	return map[Language]LanguageConfig{
 		LanguageGo: {
-			DockerImage:    "golang:1.23-alpine",
-			FormatCommand:  []string{"gofmt", "-d"},
-			BuildCommand:   []string{"go", "build", "./..."},
-			TestCommand:    []string{"go", "test", "-json", "./..."},
-			FileExtensions: []string{".go"},
+			DockerImage:      "golang:1.23-alpine",
+			FormatCommand:    []string{"gofmt", "-d"},
+			FormatFixCommand: []string{"gofmt", "-w"},
+			BuildCommand:     []string{"go", "build", "./..."},
+			TestCommand:      []string{"go", "test", "-json", "./..."},
+			FileExtensions:   []string{".go"},
 			InitFiles: map[string]string{
 				"go.mod": "module exercise\n\ngo 1.22\n",
 			},
 		},
 		LanguagePython: {
-			DockerImage:    "python:3.12-alpine",
-			FormatCommand:  []string{"python", "-m", "ruff", "format", "--check", "--diff"},
-			BuildCommand:   []string{"python", "-m", "py_compile"},
-			TestCommand:    []string{"python", "-m", "pytest", "--tb=short", "-v"},
-			FileExtensions: []string{".py"},
+			DockerImage:      "python:3.12-alpine",
+			FormatCommand:    []string{"python", "-m", "ruff", "format", "--check", "--diff"},
+			FormatFixCommand: []string{"python", "-m", "ruff", "format"},
+			BuildCommand:     []string{"python", "-m", "py_compile"},
+			TestCommand:      []string{"python", "-m", "pytest", "--tb=short", "-v"},
+			FileExtensions:   []string{".py"},
 			InitFiles: map[string]string{
 				"requirements.txt": "pytest>=8.0\nruff>=0.1\n",
 			},
+			SourceArgs: true,
 		},
 		LanguageTypeScript: {
-			DockerImage:    "node:22-alpine",
-			FormatCommand:  []string{"npx", "prettier", "--check"},
-			BuildCommand:   []string{"npx", "tsc", "--noEmit"},
-			TestCommand:    []string{"npx", "vitest", "run", "--reporter=json"},
-			FileExtensions: []string{".ts", ".tsx"},
+			DockerImage:      "node:22-alpine",
+			FormatCommand:    []string{"npx", "prettier", "--check"},
+			FormatFixCommand: []string{"npx", "prettier", "--write"},
+			BuildCommand:     []string{"npx", "tsc", "--noEmit"},
+			TestCommand:      []string{"npx", "vitest", "run", "--reporter=json"},
+			FileExtensions:   []string{".ts", ".tsx"},
 			InitFiles: map[string]string{
 				"package.json":  `{"type":"module","devDependencies":{"typescript":"^5.0","vitest":"^1.0","prettier":"^3.0"}}`,
 				"tsconfig.json": `{"compilerOptions":{"target":"ES2022","module":"ESNext","strict":true,"moduleResolution":"bundler"}}`,
 			},
 		},
 		LanguageRust: {
-			DockerImage:    "rust:1.75-alpine",
-			FormatCommand:  []string{"rustfmt", "--check"},
-			BuildCommand:   []string{"cargo", "build"},
-			TestCommand:    []string{"cargo", "test", "--", "--format=json", "-Z", "unstable-options"},
-			FileExtensions: []string{".rs"},
+			DockerImage:      "rust:1.75-alpine",
+			FormatCommand:    []string{"rustfmt", "--check"},
+			FormatFixCommand: []string{"rustfmt"},
+			BuildCommand:     []string{"cargo", "build"},
+			TestCommand:      []string{"cargo", "test", "--", "--format=json", "-Z", "unstable-options"},
+			FileExtensions:   []string{".rs"},
 			InitFiles: map[string]string{
 				"Cargo.toml": "[package]\nname = \"exercise\"\nversion = \"0.1.0\"\nedition = \"2021\"\n",
 			},
 		},
 		LanguageJava: {
-			DockerImage:    "eclipse-temurin:21-alpine",
-			FormatCommand:  []string{"google-java-format", "--dry-run", "--set-exit-if-changed"},
-			BuildCommand:   []string{"javac", "-d", "out"},
-			TestCommand:    []string{"java", "-jar", "junit-platform-console-standalone.jar", "--class-path", "out", "--scan-classpath"},
-			FileExtensions: []string{".java"},
-			InitFiles:      map[string]string{},
+			DockerImage:      "eclipse-temurin:21-alpine",
+			FormatCommand:    []string{"google-java-format", "--dry-run", "--set-exit-if-changed"},
+			FormatFixCommand: []string{"google-java-format", "--replace"},
+			BuildCommand:     []string{"javac", "-d", "out"},
+			TestCommand:      []string{"java", "-jar", "junit-platform-console-standalone.jar", "--class-path", "out", "--scan-classpath"},
+			FileExtensions:   []string{".java"},
+			InitFiles:        map[string]string{},
+			SourceArgs:       true,
 		},
 		LanguageC: {
-			DockerImage:    "gcc:13-alpine",
-			FormatCommand:  []string{"clang-format", "--dry-run", "-Werror"},
-			BuildCommand:   []string{"gcc", "-Wall", "-Wextra", "-o", "exercise"},
-			TestCommand:    []string{"./exercise"},
-			FileExtensions: []string{".c", ".h"},
-			InitFiles:      map[string]string{},
+			DockerImage:      "gcc:13-alpine",
+			FormatCommand:    []string{"clang-format", "--dry-run", "-Werror"},
+			FormatFixCommand: []string{"clang-format", "-i"},
+			BuildCommand:     []string{"gcc", "-Wall", "-Wextra", "-o", "exercise"},
+			TestCommand:      []string{"./exercise"},
+			FileExtensions:   []string{".c", ".h"},
+			InitFiles:        map[string]string{},
+			SourceArgs:       true,
 		},
 		LanguageCPP: {
-			DockerImage:    "gcc:13-alpine",
-			FormatCommand:  []string{"clang-format", "--dry-run", "-Werror"},
-			BuildCommand:   []string{"g++", "-std=c++17", "-Wall", "-Wextra", "-o", "exercise"},
-			TestCommand:    []string{"./exercise"},
-			FileExtensions: []string{".cpp", ".hpp", ".cc", ".h"},
-			InitFiles:      map[string]string{},
+			DockerImage:      "gcc:13-alpine",
+			FormatCommand:    []string{"clang-format", "--dry-run", "-Werror"},
+			FormatFixCommand: []string{"clang-format", "-i"},
+			BuildCommand:     []string{"g++", "-std=c++17", "-Wall", "-Wextra", "-o", "exercise"},
+			TestCommand:      []string{"./exercise"},
+			FileExtensions:   []string{".cpp", ".hpp", ".cc", ".h"},
+			InitFiles:        map[string]string{},
+			SourceArgs:       true,
 		},
 	}
 }
@@ -0,0 +1,161 @@
+package runner
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// languageFor returns the configuration of the language asked for on ctx
+// with WithLanguage. Go, and no language at all, use the executor's own
+// Go toolchain instead.
+func languageFor(ctx context.Context) (LanguageConfig, bool) {
+	lang := LanguageFromContext(ctx)
+	if lang == "" || lang == LanguageGo {
+		return LanguageConfig{}, false
+	}
+	cfg, ok := DefaultLanguageConfigs()[lang]
+	return cfg, ok
+}
+
+// languageOptions returns the container options for code in another
+// language: its own image unless the project overrides it
+func (e *DockerExecutor) languageOptions(ctx context.Context, cfg LanguageConfig) containerOptions {
+	image := cfg.DockerImage
+	if o := OverridesFromContext(ctx); o.Image != "" {
+		image = o.Image
+	}
+	return containerOptions{image: image, env: BuildEnvFromContext(ctx).EnvList()}
+}
+
+// sourceFiles returns the container paths of the files in code with one
+// of cfg's extensions, sorted so commands are the same on every run
+func sourceFiles(cfg LanguageConfig, code map[string]string) []string {
+	var files []string
+	for name := range code {
+		for _, ext := range cfg.FileExtensions {
+			if strings.HasSuffix(name, ext) {
+				files = append(files, "/workspace/"+name)
+				break
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// languageBuildCommand returns the build command for files, which are
+// passed to it, headers left out, when the language takes them
+func languageBuildCommand(cfg LanguageConfig, files []string) []string {
+	cmd := append([]string(nil), cfg.BuildCommand...)
+	if !cfg.SourceArgs {
+		return cmd
+	}
+	for _, f := range files {
+		if ext := filepath.Ext(f); ext != ".h" && ext != ".hpp" {
+			cmd = append(cmd, f)
+		}
+	}
+	return cmd
+}
+
+// languageTestCommand returns the test command for files. Languages that
+// build from source files test what the build made, so both run in one
+// container.
+func languageTestCommand(cfg LanguageConfig, files []string) []string {
+	if !cfg.SourceArgs {
+		return append([]string(nil), cfg.TestCommand...)
+	}
+	script := shellJoin(languageBuildCommand(cfg, files)) + " && " + shellJoin(cfg.TestCommand)
+	return []string{"sh", "-c", script}
+}
+
+// shellJoin quotes args for sh
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// withInitFiles adds the project files the language needs, like
+// package.json, when the code doesn't bring its own
+func withInitFiles(cfg LanguageConfig, code map[string]string) map[string]string {
+	out := make(map[string]string, len(code)+len(cfg.InitFiles))
+	for name, content := range cfg.InitFiles {
+		out[name] = content
+	}
+	for name, content := range code {
+		out[name] = content
+	}
+	return out
+}
+
+func (e *DockerExecutor) runLanguageFormat(ctx context.Context, cfg LanguageConfig, code map[string]string) (*FormatResult, error) {
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
+	defer cancel()
+
+	files := sourceFiles(cfg, code)
+	if len(files) == 0 {
+		return &FormatResult{OK: true}, nil
+	}
+	cmd := append(append([]string(nil), cfg.FormatCommand...), files...)
+	output, exitCode, err := e.runInContainerWith(execCtx, code, cmd, e.languageOptions(ctx, cfg))
+	if err != nil {
+		return nil, err
+	}
+	return &FormatResult{OK: exitCode == 0, Diff: output}, nil
+}
+
+func (e *DockerExecutor) runLanguageFormatFix(ctx context.Context, cfg LanguageConfig, code map[string]string) (map[string]string, error) {
+	result := make(map[string]string, len(code))
+	for name, content := range code {
+		result[name] = content
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
+	defer cancel()
+
+	// Format each file in place and print it; the formatter's own output
+	// would get mixed into the file
+	script := shellJoin(cfg.FormatFixCommand) + ` "$1" >/dev/null 2>&1 && cat "$1"`
+	for _, file := range sourceFiles(cfg, code) {
+		cmd := []string{"sh", "-c", script, "sh", file}
+		output, exitCode, err := e.runInContainerWith(execCtx, code, cmd, e.languageOptions(ctx, cfg))
+		if err != nil || exitCode != 0 {
+			// A file that doesn't parse keeps its original
+			continue
+		}
+		result[strings.TrimPrefix(file, "/workspace/")] = output
+	}
+	return result, nil
+}
+
+func (e *DockerExecutor) runLanguageBuild(ctx context.Context, cfg LanguageConfig, code map[string]string) (*BuildResult, error) {
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
+	defer cancel()
+
+	cmd := languageBuildCommand(cfg, sourceFiles(cfg, code))
+	output, exitCode, err := e.runInContainerWith(execCtx, withInitFiles(cfg, code), cmd, e.languageOptions(ctx, cfg))
+	if err != nil {
+		return nil, err
+	}
+	return &BuildResult{OK: exitCode == 0, Output: output}, nil
+}
+
+func (e *DockerExecutor) runLanguageTests(ctx context.Context, cfg LanguageConfig, code map[string]string) (*TestResult, error) {
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
+	defer cancel()
+
+	start := time.Now()
+	cmd := languageTestCommand(cfg, sourceFiles(cfg, code))
+	output, exitCode, err := e.runInContainerWith(execCtx, withInitFiles(cfg, code), cmd, e.languageOptions(ctx, cfg))
+	duration := time.Since(start)
+	if err != nil {
+		return nil, err
+	}
+	return &TestResult{OK: exitCode == 0, Output: output, Duration: duration}, nil
+}
@@ -1,6 +1,8 @@
 package runner
 
 import (
+	"context"
+	"reflect"
 	"testing"
 )
 
@@ -185,3 +187,47 @@ func TestCPPExecutor_Language(t *testing.T) {
 		t.Errorf("expected C++, got %s", exec.Language())
 	}
 }
+
+func TestWithLanguage(t *testing.T) {
+	ctx := context.Background()
+	if got := LanguageFromContext(WithLanguage(ctx, "")); got != "" {
+		t.Errorf("empty language = %q; want none", got)
+	}
+	if got := LanguageFromContext(WithLanguage(ctx, LanguageRust)); got != LanguageRust {
+		t.Errorf("LanguageFromContext() = %q; want rust", got)
+	}
+	if _, ok := languageFor(WithLanguage(ctx, LanguageGo)); ok {
+		t.Error("Go should use the executor's own toolchain")
+	}
+	if cfg, ok := languageFor(WithLanguage(ctx, LanguagePython)); !ok || cfg.DockerImage != "python:3.12-alpine" {
+		t.Errorf("languageFor(python) = %+v, %v; want the python image", cfg, ok)
+	}
+}
+
+func TestLanguageCommands(t *testing.T) {
+	configs := DefaultLanguageConfigs()
+	code := map[string]string{"main.c": "", "util.h": "", "util.c": "", "README.md": ""}
+
+	c := configs[LanguageC]
+	files := sourceFiles(c, code)
+	if want := []string{"/workspace/main.c", "/workspace/util.c", "/workspace/util.h"}; !reflect.DeepEqual(files, want) {
+		t.Fatalf("sourceFiles() = %v; want %v", files, want)
+	}
+	build := languageBuildCommand(c, files)
+	if want := []string{"gcc", "-Wall", "-Wextra", "-o", "exercise", "/workspace/main.c", "/workspace/util.c"}; !reflect.DeepEqual(build, want) {
+		t.Errorf("build = %v; want %v", build, want)
+	}
+	test := languageTestCommand(c, files)
+	if want := []string{"sh", "-c", shellJoin(build) + " && './exercise'"}; !reflect.DeepEqual(test, want) {
+		t.Errorf("test = %v; want %v", test, want)
+	}
+
+	rust := configs[LanguageRust]
+	if got := languageBuildCommand(rust, []string{"/workspace/main.rs"}); !reflect.DeepEqual(got, rust.BuildCommand) {
+		t.Errorf("rust build = %v; cargo doesn't take files", got)
+	}
+
+	if got := shellJoin([]string{"echo", "it's"}); got != `'echo' 'it'\''s'` {
+		t.Errorf("shellJoin() = %s", got)
+	}
+}
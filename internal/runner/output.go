@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// MaxOutputBytes is the most container output a run holds in memory. A
+// test that prints in a loop can't take the daemon down: past the limit
+// the start and the end of the output are kept and the middle dropped.
+const MaxOutputBytes = 8 << 20
+
+// outputBuffer collects output up to a limit, keeping its head and a
+// rolling tail once the limit is passed. A quarter of the limit goes to
+// the tail: the failure summary go test prints last is usually there.
+type outputBuffer struct {
+	limit   int
+	head    bytes.Buffer
+	tail    []byte
+	dropped int64
+}
+
+func newOutputBuffer(limit int) *outputBuffer {
+	return &outputBuffer{limit: limit}
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	tailLimit := b.limit / 4
+	headLimit := b.limit - tailLimit
+	if room := headLimit - b.head.Len(); room > 0 {
+		if room > len(p) {
+			room = len(p)
+		}
+		b.head.Write(p[:room])
+		p = p[room:]
+	}
+	if len(p) == 0 {
+		return n, nil
+	}
+
+	b.tail = append(b.tail, p...)
+	if over := len(b.tail) - tailLimit; over > 0 {
+		b.dropped += int64(over)
+		b.tail = append(b.tail[:0], b.tail[over:]...)
+	}
+	return n, nil
+}
+
+func (b *outputBuffer) String() string {
+	if b.dropped == 0 {
+		return b.head.String() + string(b.tail)
+	}
+	return fmt.Sprintf("%s\n... [%d bytes of output omitted] ...\n%s", b.head.String(), b.dropped, b.tail)
+}
@@ -0,0 +1,35 @@
+package runner
+
+import (
+	"context"
+	"time"
+)
+
+// Overrides adjust the Docker executor's configured image and limits for
+// one run, e.g. from a project's .temper.yaml. Zero fields keep the
+// executor's own settings.
+type Overrides struct {
+	Image    string
+	MemoryMB int64
+	Timeout  time.Duration
+}
+
+type overridesKey struct{}
+
+// WithOverrides returns a context asking the executor to apply o. Empty
+// overrides leave ctx unchanged.
+func WithOverrides(ctx context.Context, o Overrides) context.Context {
+	if o == (Overrides{}) {
+		return ctx
+	}
+	return context.WithValue(ctx, overridesKey{}, o)
+}
+
+// OverridesFromContext returns the overrides set with WithOverrides
+func OverridesFromContext(ctx context.Context) Overrides {
+	if ctx == nil {
+		return Overrides{}
+	}
+	o, _ := ctx.Value(overridesKey{}).(Overrides)
+	return o
+}
@@ -0,0 +1,37 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDockerExecutor_Overrides(t *testing.T) {
+	e := &DockerExecutor{baseImage: "golang:1.22-alpine", memoryMB: 256, timeout: 30 * time.Second}
+
+	ctx := context.Background()
+	if e.imageFor(ctx) != "golang:1.22-alpine" || e.memoryFor(ctx) != 256 || e.timeoutFor(ctx) != 30*time.Second {
+		t.Errorf("without overrides got %s, %dMB, %v; want the executor's settings",
+			e.imageFor(ctx), e.memoryFor(ctx), e.timeoutFor(ctx))
+	}
+	if WithOverrides(ctx, Overrides{}) != ctx {
+		t.Error("WithOverrides with nothing set should return ctx unchanged")
+	}
+
+	ctx = WithOverrides(ctx, Overrides{Image: "registry.local/go:1.23", MemoryMB: 1024, Timeout: 2 * time.Minute})
+	if got := e.imageFor(ctx); got != "registry.local/go:1.23" {
+		t.Errorf("imageFor() = %q; want the project image", got)
+	}
+	if got := e.memoryFor(ctx); got != 1024 {
+		t.Errorf("memoryFor() = %d; want 1024", got)
+	}
+	if got := e.timeoutFor(ctx); got != 2*time.Minute {
+		t.Errorf("timeoutFor() = %v; want 2m", got)
+	}
+
+	// A pack's pinned Go version still wins over the project image when
+	// the image doesn't ship it
+	if got := e.imageFor(WithGoVersion(ctx, "1.24")); got != "golang:1.24-alpine" {
+		t.Errorf("imageFor(pinned 1.24) = %q; want the toolchain image", got)
+	}
+}
@@ -139,3 +139,76 @@ func (p *Parser) ParseFormatDiff(output string) (hasChanges bool, diff string) {
 	}
 	return true, output
 }
+
+var (
+	delveStopRegex  = regexp.MustCompile(`^> \[([^\]]+)\]`)
+	delveFrameRegex = regexp.MustCompile(`^\s*\d+\s+0x[0-9a-fA-F]+ in (.+)$`)
+	delveAtRegex    = regexp.MustCompile(`^\s+at (.+):(\d+)$`)
+	delveVarRegex   = regexp.MustCompile(`^\s+([A-Za-z_][A-Za-z0-9_.]*) = (.*)$`)
+)
+
+// ParseDelveOutput extracts a snapshot from the output of a Delve session
+// that ran "stack -full" at the stop point. Returns nil when Delve never
+// stopped (the tests passed under the debugger).
+func (p *Parser) ParseDelveOutput(output string) *domain.DebugSnapshot {
+	var snap *domain.DebugSnapshot
+	var frameLocals [][]domain.DebugVariable
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := delveStopRegex.FindStringSubmatch(line); m != nil && snap == nil {
+			reason := "test_failure"
+			if strings.Contains(m[1], "panic") {
+				reason = "panic"
+			}
+			snap = &domain.DebugSnapshot{Reason: reason}
+			continue
+		}
+		if snap == nil {
+			continue
+		}
+
+		if m := delveFrameRegex.FindStringSubmatch(line); m != nil {
+			snap.Frames = append(snap.Frames, domain.StackFrame{Function: m[1]})
+			frameLocals = append(frameLocals, nil)
+			continue
+		}
+		if len(snap.Frames) == 0 {
+			continue
+		}
+		last := len(snap.Frames) - 1
+		if m := delveAtRegex.FindStringSubmatch(line); m != nil {
+			snap.Frames[last].File = m[1]
+			snap.Frames[last].Line, _ = strconv.Atoi(m[2])
+			continue
+		}
+		if m := delveVarRegex.FindStringSubmatch(line); m != nil {
+			frameLocals[last] = append(frameLocals[last], domain.DebugVariable{Name: m[1], Value: m[2]})
+		}
+	}
+
+	if snap == nil || len(snap.Frames) == 0 {
+		return nil
+	}
+
+	// The stop point is inside testing or runtime; the interesting locals
+	// belong to the innermost frame of the learner's own code.
+	for i, f := range snap.Frames {
+		if isLearnerFrame(f.Function) {
+			snap.Locals = frameLocals[i]
+			break
+		}
+	}
+	return snap
+}
+
+func isLearnerFrame(function string) bool {
+	for _, prefix := range []string{"runtime.", "testing.", "reflect.", "sync."} {
+		if strings.HasPrefix(function, prefix) {
+			return false
+		}
+	}
+	return true
+}
@@ -353,3 +353,64 @@ func TestParser_ParseBuildErrors_MultilineMessage(t *testing.T) {
 		t.Errorf("expected 1 diagnostic, got %d", len(diagnostics))
 	}
 }
+
+func TestParser_ParseDelveOutput(t *testing.T) {
+	output := `Breakpoint 1 set at 0x4f1a2b for testing.(*common).Fail() /usr/local/go/src/testing/testing.go:945
+> [Breakpoint 1] testing.(*common).Fail() /usr/local/go/src/testing/testing.go:945 (hits goroutine(6):1 total:1) (PC: 0x4f1a2b)
+0  0x00000000004f1a2b in testing.(*common).Fail
+   at /usr/local/go/src/testing/testing.go:945
+       c = ("*testing.common")(0xc000138000)
+1  0x00000000004f3c11 in testing.(*common).Errorf
+   at /usr/local/go/src/testing/testing.go:1070
+       format = "PageCount(%d, %d) = %d; want %d"
+2  0x00000000005291d3 in exercise.TestPageCount
+   at ./main_test.go:18
+       t = ("*testing.T")(0xc000138000)
+       got = 1
+       tc = struct { total int; perPage int; want int } {total: 11, perPage: 10, want: 2}
+3  0x00000000004f5a6f in testing.tRunner
+   at /usr/local/go/src/testing/testing.go:1690
+`
+	p := NewParser()
+	snap := p.ParseDelveOutput(output)
+	if snap == nil {
+		t.Fatal("ParseDelveOutput() = nil; want snapshot")
+	}
+	if snap.Reason != "test_failure" {
+		t.Errorf("Reason = %q; want test_failure", snap.Reason)
+	}
+	if len(snap.Frames) != 4 {
+		t.Fatalf("len(Frames) = %d; want 4", len(snap.Frames))
+	}
+	if f := snap.Frames[2]; f.Function != "exercise.TestPageCount" || f.File != "./main_test.go" || f.Line != 18 {
+		t.Errorf("Frames[2] = %+v", f)
+	}
+	if len(snap.Locals) != 3 || snap.Locals[1].Name != "got" || snap.Locals[1].Value != "1" {
+		t.Errorf("Locals = %+v; want learner frame locals", snap.Locals)
+	}
+}
+
+func TestParser_ParseDelveOutput_Panic(t *testing.T) {
+	output := `> [unrecovered-panic] runtime.fatalpanic() /usr/local/go/src/runtime/panic.go:1217 (hits goroutine(7):1 total:1) (PC: 0x43a1f4)
+0  0x000000000043a1f4 in runtime.fatalpanic
+   at /usr/local/go/src/runtime/panic.go:1217
+1  0x0000000000529100 in exercise.Divide
+   at ./main.go:4
+       a = 1
+       b = 0
+`
+	snap := NewParser().ParseDelveOutput(output)
+	if snap == nil || snap.Reason != "panic" {
+		t.Fatalf("ParseDelveOutput() = %+v; want panic snapshot", snap)
+	}
+	if len(snap.Locals) != 2 || snap.Locals[1].Value != "0" {
+		t.Errorf("Locals = %+v", snap.Locals)
+	}
+}
+
+func TestParser_ParseDelveOutput_NoStop(t *testing.T) {
+	output := "Breakpoint 1 set at 0x4f1a2b\nProcess 42 has exited with status 0\n"
+	if snap := NewParser().ParseDelveOutput(output); snap != nil {
+		t.Errorf("ParseDelveOutput() = %+v; want nil", snap)
+	}
+}
@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// NormalizeArch maps the architecture names uname and Docker report
+// ("x86_64", "aarch64") to the OCI names images use ("amd64", "arm64")
+func NormalizeArch(arch string) string {
+	switch strings.ToLower(strings.TrimSpace(arch)) {
+	case "x86_64", "x86-64", "amd64":
+		return "amd64"
+	case "aarch64", "arm64", "armv8", "armv8l":
+		return "arm64"
+	case "armv7l", "armv7", "armhf", "arm":
+		return "arm"
+	default:
+		return strings.ToLower(strings.TrimSpace(arch))
+	}
+}
+
+// ParsePlatform parses a platform such as "linux/arm64" or "linux/arm/v7".
+// The OS defaults to linux when only an architecture is given.
+func ParsePlatform(s string) (*ocispec.Platform, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if len(parts) == 1 {
+		parts = append([]string{"linux"}, parts...)
+	}
+	if len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid platform %q: want os/arch, e.g. linux/arm64", s)
+	}
+	p := &ocispec.Platform{OS: parts[0], Architecture: NormalizeArch(parts[1])}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+// NativePlatform returns the Linux platform for a Docker daemon reporting
+// the given architecture, or nil when it's unknown
+func NativePlatform(daemonArch string) *ocispec.Platform {
+	arch := NormalizeArch(daemonArch)
+	if arch == "" {
+		return nil
+	}
+	return &ocispec.Platform{OS: "linux", Architecture: arch}
+}
+
+// PlatformString formats a platform the way docker --platform takes it
+func PlatformString(p *ocispec.Platform) string {
+	if p == nil {
+		return ""
+	}
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// Emulated reports whether an image built for imageArch runs under
+// emulation on a Docker daemon of daemonArch. Unknown architectures are
+// not reported.
+func Emulated(daemonArch, imageArch string) bool {
+	d, i := NormalizeArch(daemonArch), NormalizeArch(imageArch)
+	return d != "" && i != "" && d != i
+}
@@ -0,0 +1,73 @@
+package runner
+
+import "testing"
+
+func TestNormalizeArch(t *testing.T) {
+	tests := map[string]string{
+		"x86_64":  "amd64",
+		"amd64":   "amd64",
+		"aarch64": "arm64",
+		"arm64":   "arm64",
+		"armv7l":  "arm",
+		"s390x":   "s390x",
+		"":        "",
+	}
+	for arch, want := range tests {
+		if got := NormalizeArch(arch); got != want {
+			t.Errorf("NormalizeArch(%q) = %q; want %q", arch, got, want)
+		}
+	}
+}
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{in: "linux/arm64", want: "linux/arm64"},
+		{in: "linux/aarch64", want: "linux/arm64"},
+		{in: "amd64", want: "linux/amd64"},
+		{in: "linux/arm/v7", want: "linux/arm/v7"},
+		{in: "linux/", wantErr: true},
+		{in: "linux/arm/v7/x", wantErr: true},
+	}
+	for _, tt := range tests {
+		p, err := ParsePlatform(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParsePlatform(%q) = %v; want error", tt.in, PlatformString(p))
+			}
+			continue
+		}
+		if err != nil || PlatformString(p) != tt.want {
+			t.Errorf("ParsePlatform(%q) = %q, %v; want %q", tt.in, PlatformString(p), err, tt.want)
+		}
+	}
+}
+
+func TestNativePlatform(t *testing.T) {
+	if got := PlatformString(NativePlatform("aarch64")); got != "linux/arm64" {
+		t.Errorf("NativePlatform(aarch64) = %q; want linux/arm64", got)
+	}
+	if got := NativePlatform(""); got != nil {
+		t.Errorf("NativePlatform(\"\") = %v; want nil", got)
+	}
+}
+
+func TestEmulated(t *testing.T) {
+	tests := []struct {
+		daemon, image string
+		want          bool
+	}{
+		{"aarch64", "amd64", true},
+		{"aarch64", "arm64", false},
+		{"x86_64", "amd64", false},
+		{"", "amd64", false},
+		{"x86_64", "", false},
+	}
+	for _, tt := range tests {
+		if got := Emulated(tt.daemon, tt.image); got != tt.want {
+			t.Errorf("Emulated(%q, %q) = %v; want %v", tt.daemon, tt.image, got, tt.want)
+		}
+	}
+}
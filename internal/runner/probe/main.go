@@ -0,0 +1,155 @@
+// Command probe checks a learner's HTTP server from inside the sandbox.
+// The runner embeds this file and builds it next to the learner's code,
+// so it may only import the standard library.
+//
+// It reads a JSON config naming the server's base URL, the path to poll
+// until the server answers and the probes to send, then prints the
+// results as one JSON line after a marker the runner looks for.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Marker precedes the results line in the output
+const Marker = "temper-probes: "
+
+const (
+	requestTimeout = 5 * time.Second
+	maxBody        = 1 << 20
+	keptBody       = 4 << 10
+)
+
+type config struct {
+	BaseURL   string  `json:"base_url"`
+	Ready     string  `json:"ready"`
+	StartupMS int     `json:"startup_ms"`
+	Probes    []probe `json:"probes"`
+}
+
+type probe struct {
+	Name          string            `json:"name"`
+	Method        string            `json:"method"`
+	Path          string            `json:"path"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Body          string            `json:"body,omitempty"`
+	ExpectStatus  int               `json:"expect_status,omitempty"`
+	ExpectBody    string            `json:"expect_body,omitempty"`
+	ExpectHeaders map[string]string `json:"expect_headers,omitempty"`
+}
+
+type result struct {
+	Name    string `json:"name"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Status  int    `json:"status,omitempty"`
+	Body    string `json:"body,omitempty"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+type report struct {
+	Started bool     `json:"started"`
+	Probes  []result `json:"probes"`
+}
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: probe CONFIG")
+		os.Exit(2)
+	}
+	data, err := os.ReadFile(os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		fmt.Fprintln(os.Stderr, "parse config:", err)
+		os.Exit(2)
+	}
+
+	client := &http.Client{Timeout: requestTimeout}
+	rep := report{Started: waitReady(client, cfg)}
+	for _, p := range cfg.Probes {
+		if !rep.Started {
+			rep.Probes = append(rep.Probes, result{Name: p.Name, Method: p.Method, Path: p.Path,
+				Message: "server did not start"})
+			continue
+		}
+		rep.Probes = append(rep.Probes, send(client, cfg.BaseURL, p))
+	}
+
+	out, _ := json.Marshal(rep)
+	fmt.Println(Marker + string(out))
+}
+
+// waitReady polls the ready path until the server answers with any
+// status or the startup timeout passes
+func waitReady(client *http.Client, cfg config) bool {
+	deadline := time.Now().Add(time.Duration(cfg.StartupMS) * time.Millisecond)
+	for {
+		resp, err := client.Get(cfg.BaseURL + cfg.Ready)
+		if err == nil {
+			_ = resp.Body.Close()
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func send(client *http.Client, baseURL string, p probe) result {
+	res := result{Name: p.Name, Method: p.Method, Path: p.Path}
+
+	var body io.Reader
+	if p.Body != "" {
+		body = strings.NewReader(p.Body)
+	}
+	req, err := http.NewRequest(p.Method, baseURL+p.Path, body)
+	if err != nil {
+		res.Message = err.Error()
+		return res
+	}
+	for k, v := range p.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		res.Message = err.Error()
+		return res
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	res.Status = resp.StatusCode
+	res.Body = string(data)
+	if len(res.Body) > keptBody {
+		res.Body = res.Body[:keptBody]
+	}
+
+	switch {
+	case p.ExpectStatus != 0 && resp.StatusCode != p.ExpectStatus:
+		res.Message = fmt.Sprintf("status %d, want %d", resp.StatusCode, p.ExpectStatus)
+	case p.ExpectBody != "" && !strings.Contains(string(data), p.ExpectBody):
+		res.Message = fmt.Sprintf("body does not contain %q", p.ExpectBody)
+	default:
+		res.Passed = true
+		for k, want := range p.ExpectHeaders {
+			if got := resp.Header.Get(k); got != want {
+				res.Passed = false
+				res.Message = fmt.Sprintf("header %s is %q, want %q", k, got, want)
+				break
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// MaxStdinBytes caps the input a program run may be fed
+const MaxStdinBytes = 64 * 1024
+
+// stdinFile holds a program's input inside the container. The leading dot
+// keeps it out of the go tool's package patterns.
+const stdinFile = ".temper-stdin"
+
+// ProgramRunner is implemented by executors that can run the learner's
+// program with scripted standard input, for exercises that read from
+// stdin (bufio.Scanner, CLI prompts)
+type ProgramRunner interface {
+	// RunProgram builds and runs the program, feeding it stdin, and
+	// returns what it printed once it exits or the run times out
+	RunProgram(ctx context.Context, code map[string]string, stdin string) (*ProgramResult, error)
+}
+
+// ProgramResult contains the result of a program run
+type ProgramResult struct {
+	Output   string // stdout and stderr, interleaved
+	ExitCode int
+	TimedOut bool
+}
+
+// RunProgram runs the package in scope with stdin redirected from a file,
+// so no terminal or attached stream is needed
+func (e *DockerExecutor) RunProgram(ctx context.Context, code map[string]string, stdin string) (*ProgramResult, error) {
+	if len(stdin) > MaxStdinBytes {
+		return nil, fmt.Errorf("stdin is %d bytes; the limit is %d", len(stdin), MaxStdinBytes)
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
+	defer cancel()
+
+	codeWithMod := make(map[string]string, len(code)+2)
+	for k, v := range code {
+		codeWithMod[k] = v
+	}
+	if _, ok := codeWithMod["go.mod"]; !ok {
+		codeWithMod["go.mod"] = defaultGoMod(GoVersionFromContext(ctx))
+	}
+	codeWithMod[stdinFile] = stdin
+
+	opts, violations := e.dependencyOptions(code, e.buildOptions(ctx))
+	if len(violations) > 0 {
+		return &ProgramResult{Output: violationMessage(violations), ExitCode: 1}, nil
+	}
+
+	script := `exec go run "$@" < ` + stdinFile
+	cmd := append([]string{"sh", "-c", script, "sh"}, buildFlags(ctx)...)
+	cmd = append(cmd, debugPackage(ctx))
+	output, exitCode, err := e.runInContainerWith(execCtx, codeWithMod, cmd, opts)
+	if err != nil {
+		// A program still waiting for input is killed at the timeout;
+		// that is a result, not an executor failure
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return &ProgramResult{Output: output, ExitCode: -1, TimedOut: true}, nil
+		}
+		return nil, err
+	}
+
+	return &ProgramResult{Output: output, ExitCode: exitCode}, nil
+}
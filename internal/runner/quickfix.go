@@ -0,0 +1,309 @@
+package runner
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+var (
+	unusedImportRegex   = regexp.MustCompile(`^"([^"]+)" imported (?:as \w+ )?and not used`)
+	unusedVariableRegex = regexp.MustCompile(`^declared and not used: (\w+)$|^(\w+) declared (?:and|but) not used$`)
+	undefinedRegex      = regexp.MustCompile(`^undefined: (\w+)$`)
+)
+
+// stdImports are the standard library packages an undefined name is
+// taken to mean, keyed by package name. Only names no learner would
+// give their own variable make the list.
+var stdImports = map[string]string{
+	"atomic":   "sync/atomic",
+	"bufio":    "bufio",
+	"bytes":    "bytes",
+	"context":  "context",
+	"errors":   "errors",
+	"filepath": "path/filepath",
+	"fmt":      "fmt",
+	"http":     "net/http",
+	"io":       "io",
+	"json":     "encoding/json",
+	"maps":     "maps",
+	"math":     "math",
+	"os":       "os",
+	"rand":     "math/rand",
+	"regexp":   "regexp",
+	"slices":   "slices",
+	"sort":     "sort",
+	"strconv":  "strconv",
+	"strings":  "strings",
+	"sync":     "sync",
+	"testing":  "testing",
+	"time":     "time",
+	"unicode":  "unicode",
+	"utf8":     "unicode/utf8",
+}
+
+// quickFixFile is a file of the run, parsed once for all of its errors
+type quickFixFile struct {
+	fset *token.FileSet
+	file *ast.File
+	src  string
+}
+
+// QuickFixes returns the mechanical fixes for compile errors in Go build
+// output: adding a missing standard library import, removing an unused
+// one and replacing an unused variable with _. Errors in files that
+// aren't in code, or that don't parse, get none.
+func QuickFixes(buildOutput string, code map[string]string) []domain.QuickFix {
+	var fixes []domain.QuickFix
+	seen := make(map[string]bool)
+	files := make(map[string]*quickFixFile)
+
+	for _, line := range outputLines(buildOutput) {
+		m := compileErrorRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name, message := m[1], m[4]
+		src, ok := code[name]
+		if !ok {
+			continue
+		}
+		f, ok := files[name]
+		if !ok {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, name, src, parser.SkipObjectResolution)
+			if err != nil {
+				file = nil
+			}
+			f = &quickFixFile{fset: fset, file: file, src: src}
+			files[name] = f
+		}
+		if f.file == nil {
+			continue
+		}
+
+		lineNum, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		fix, ok := f.quickFix(message, lineNum, col)
+		if !ok {
+			continue
+		}
+		// One import is added once, however often the name is undefined
+		key := name + "\x00" + fix.Title
+		if fix.Kind != domain.QuickFixAddImport {
+			key += "\x00" + m[2]
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		fix.File, fix.Line, fix.Message = name, lineNum, message
+		fixes = append(fixes, fix)
+	}
+	return fixes
+}
+
+func (f *quickFixFile) quickFix(message string, line, col int) (domain.QuickFix, bool) {
+	if m := unusedImportRegex.FindStringSubmatch(message); m != nil {
+		return f.removeImport(m[1], line)
+	}
+	if m := unusedVariableRegex.FindStringSubmatch(message); m != nil {
+		return f.blankVariable(first(m[1], m[2]), line, col)
+	}
+	if m := undefinedRegex.FindStringSubmatch(message); m != nil {
+		return f.addImport(m[1])
+	}
+	return domain.QuickFix{}, false
+}
+
+// addImport imports the standard library package name, when name is one
+// and the file uses it as a package
+func (f *quickFixFile) addImport(name string) (domain.QuickFix, bool) {
+	path, ok := stdImports[name]
+	if !ok || !f.usesPackage(name) {
+		return domain.QuickFix{}, false
+	}
+	fix := domain.QuickFix{
+		Kind:  domain.QuickFixAddImport,
+		Title: fmt.Sprintf("Add import %q", path),
+	}
+
+	var decl *ast.GenDecl
+	for _, d := range f.file.Decls {
+		if gd, ok := d.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			decl = gd
+			break
+		}
+	}
+	switch {
+	case decl == nil:
+		fix.Edits = []domain.TextEdit{f.insert(f.file.Name.End(), fmt.Sprintf("\n\nimport %q", path))}
+	case decl.Lparen.IsValid():
+		fix.Edits = []domain.TextEdit{f.insert(decl.Lparen+1, fmt.Sprintf("\n\t%q", path))}
+	default:
+		fix.Edits = []domain.TextEdit{f.insert(decl.End(), fmt.Sprintf("\nimport %q", path))}
+	}
+	return fix, true
+}
+
+// usesPackage reports whether the file selects from name, as in
+// strings.ToUpper, so an undefined variable isn't taken for a package
+func (f *quickFixFile) usesPackage(name string) bool {
+	used := false
+	ast.Inspect(f.file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Name == name {
+				used = true
+			}
+		}
+		return !used
+	})
+	return used
+}
+
+// removeImport deletes the import of path reported on line, and the whole
+// import declaration when it imports nothing else
+func (f *quickFixFile) removeImport(path string, line int) (domain.QuickFix, bool) {
+	for _, d := range f.file.Decls {
+		decl, ok := d.(*ast.GenDecl)
+		if !ok || decl.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			imp := spec.(*ast.ImportSpec)
+			if p, _ := strconv.Unquote(imp.Path.Value); p != path || f.line(imp.Pos()) != line {
+				continue
+			}
+			var from, to int
+			if decl.Lparen.IsValid() && len(decl.Specs) > 1 {
+				from, to = f.line(imp.Pos()), f.line(imp.End())
+			} else {
+				from, to = f.line(decl.Pos()), f.line(decl.End())
+			}
+			return domain.QuickFix{
+				Kind:  domain.QuickFixRemoveImport,
+				Title: fmt.Sprintf("Remove unused import %q", path),
+				Edits: []domain.TextEdit{f.deleteLines(from, to)},
+			}, true
+		}
+	}
+	return domain.QuickFix{}, false
+}
+
+// blankVariable replaces the unused variable name declared at line:col
+// with _, turning := into = or dropping the declaration where a blank
+// alone wouldn't compile
+func (f *quickFixFile) blankVariable(name string, line, col int) (domain.QuickFix, bool) {
+	fix := domain.QuickFix{
+		Kind:  domain.QuickFixBlankVariable,
+		Title: fmt.Sprintf("Replace unused variable %s with _", name),
+	}
+	at := func(e ast.Expr) bool {
+		id, ok := e.(*ast.Ident)
+		if !ok || id.Name != name {
+			return false
+		}
+		p := f.fset.Position(id.Pos())
+		return p.Line == line && (col == 0 || p.Column == col)
+	}
+
+	ast.Inspect(f.file, func(n ast.Node) bool {
+		if fix.Edits != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.TypeSwitchStmt:
+			// switch v := x.(type) becomes switch x.(type)
+			if assign, ok := n.Assign.(*ast.AssignStmt); ok && len(assign.Lhs) == 1 && at(assign.Lhs[0]) {
+				fix.Edits = []domain.TextEdit{f.replace(assign.Lhs[0].Pos(), assign.Rhs[0].Pos(), "")}
+				return false
+			}
+		case *ast.RangeStmt:
+			if n.Tok != token.DEFINE {
+				break
+			}
+			key, value := at(n.Key), n.Value != nil && at(n.Value)
+			switch {
+			case key && (n.Value == nil || isBlank(n.Value)), value && isBlank(n.Key):
+				// for i := range xs becomes for range xs
+				fix.Edits = []domain.TextEdit{f.replace(n.Key.Pos(), n.Range, "")}
+			case key:
+				fix.Edits = []domain.TextEdit{f.replace(n.Key.Pos(), n.Key.End(), "_")}
+			case value:
+				fix.Edits = []domain.TextEdit{f.replace(n.Value.Pos(), n.Value.End(), "_")}
+			}
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE {
+				break
+			}
+			for _, lhs := range n.Lhs {
+				if !at(lhs) {
+					continue
+				}
+				fix.Edits = []domain.TextEdit{f.replace(lhs.Pos(), lhs.End(), "_")}
+				// With nothing else declared, _ := x doesn't compile
+				others := true
+				for _, other := range n.Lhs {
+					if other != lhs && !isBlank(other) {
+						others = false
+					}
+				}
+				if others {
+					fix.Edits = append(fix.Edits, f.replace(n.TokPos, n.TokPos+2, "="))
+				}
+				return false
+			}
+		case *ast.ValueSpec:
+			for _, id := range n.Names {
+				if at(id) {
+					fix.Edits = []domain.TextEdit{f.replace(id.Pos(), id.End(), "_")}
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return fix, fix.Edits != nil
+}
+
+func isBlank(e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == "_"
+}
+
+func (f *quickFixFile) line(p token.Pos) int {
+	return f.fset.Position(p).Line
+}
+
+func (f *quickFixFile) replace(from, to token.Pos, text string) domain.TextEdit {
+	start, end := f.fset.Position(from), f.fset.Position(to)
+	return domain.TextEdit{
+		StartLine:   start.Line,
+		StartColumn: start.Column,
+		EndLine:     end.Line,
+		EndColumn:   end.Column,
+		NewText:     text,
+	}
+}
+
+func (f *quickFixFile) insert(at token.Pos, text string) domain.TextEdit {
+	return f.replace(at, at, text)
+}
+
+// deleteLines deletes lines from through to, with their line breaks
+func (f *quickFixFile) deleteLines(from, to int) domain.TextEdit {
+	edit := domain.TextEdit{StartLine: from, StartColumn: 1, EndLine: to + 1, EndColumn: 1}
+	lines := strings.Split(f.src, "\n")
+	if to >= len(lines) {
+		// The last line has no line break to delete
+		edit.EndLine, edit.EndColumn = to, len(lines[to-1])+1
+	}
+	return edit
+}
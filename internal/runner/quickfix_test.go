@@ -0,0 +1,148 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// applyEdits applies non-overlapping edits to src, last first
+func applyEdits(t *testing.T, src string, edits []domain.TextEdit) string {
+	t.Helper()
+	offset := func(line, col int) int {
+		lines := strings.SplitAfter(src, "\n")
+		off := 0
+		for i := 0; i < line-1; i++ {
+			off += len(lines[i])
+		}
+		return off + col - 1
+	}
+	for i := len(edits) - 1; i >= 0; i-- {
+		e := edits[i]
+		start, end := offset(e.StartLine, e.StartColumn), offset(e.EndLine, e.EndColumn)
+		src = src[:start] + e.NewText + src[end:]
+	}
+	return src
+}
+
+func TestQuickFixes(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		output string
+		kind   string
+		want   string
+	}{
+		{
+			name:   "add import to group",
+			src:    "package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() { fmt.Println(strings.ToUpper(\"a\")) }\n",
+			output: "./main.go:7:27: undefined: strings",
+			kind:   domain.QuickFixAddImport,
+			want:   "package main\n\nimport (\n\t\"strings\"\n\t\"fmt\"\n)\n\nfunc main() { fmt.Println(strings.ToUpper(\"a\")) }\n",
+		},
+		{
+			name:   "add import after single import",
+			src:    "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(strconv.Itoa(1)) }\n",
+			output: "main.go:5:27: undefined: strconv",
+			kind:   domain.QuickFixAddImport,
+			want:   "package main\n\nimport \"fmt\"\nimport \"strconv\"\n\nfunc main() { fmt.Println(strconv.Itoa(1)) }\n",
+		},
+		{
+			name:   "add first import",
+			src:    "package main\n\nfunc main() { utf8.RuneLen('a') }\n",
+			output: "main.go:3:15: undefined: utf8",
+			kind:   domain.QuickFixAddImport,
+			want:   "package main\n\nimport \"unicode/utf8\"\n\nfunc main() { utf8.RuneLen('a') }\n",
+		},
+		{
+			name:   "remove import from group",
+			src:    "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() { fmt.Println() }\n",
+			output: "main.go:5:2: \"os\" imported and not used",
+			kind:   domain.QuickFixRemoveImport,
+			want:   "package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() { fmt.Println() }\n",
+		},
+		{
+			name:   "remove only import",
+			src:    "package main\n\nimport \"os\"\n\nfunc main() {}\n",
+			output: "main.go:3:8: \"os\" imported and not used",
+			kind:   domain.QuickFixRemoveImport,
+			want:   "package main\n\n\nfunc main() {}\n",
+		},
+		{
+			name:   "blank one of several",
+			src:    "package main\n\nfunc main() {\n\tn, err := f()\n\t_ = err\n}\n",
+			output: "main.go:4:2: declared and not used: n",
+			kind:   domain.QuickFixBlankVariable,
+			want:   "package main\n\nfunc main() {\n\t_, err := f()\n\t_ = err\n}\n",
+		},
+		{
+			name:   "blank the only variable",
+			src:    "package main\n\nfunc main() {\n\tn := f()\n}\n",
+			output: "main.go:4:2: declared and not used: n",
+			kind:   domain.QuickFixBlankVariable,
+			want:   "package main\n\nfunc main() {\n\t_ = f()\n}\n",
+		},
+		{
+			name:   "range key",
+			src:    "package main\n\nfunc main() {\n\tfor i := range xs {\n\t}\n}\n",
+			output: "main.go:4:6: declared and not used: i",
+			kind:   domain.QuickFixBlankVariable,
+			want:   "package main\n\nfunc main() {\n\tfor range xs {\n\t}\n}\n",
+		},
+		{
+			name:   "range value",
+			src:    "package main\n\nfunc main() {\n\tfor i, v := range xs {\n\t\tprintln(i)\n\t}\n}\n",
+			output: "main.go:4:9: v declared and not used",
+			kind:   domain.QuickFixBlankVariable,
+			want:   "package main\n\nfunc main() {\n\tfor i, _ := range xs {\n\t\tprintln(i)\n\t}\n}\n",
+		},
+		{
+			name:   "type switch",
+			src:    "package main\n\nfunc f(x any) {\n\tswitch v := x.(type) {\n\t}\n}\n",
+			output: "main.go:4:9: declared and not used: v",
+			kind:   domain.QuickFixBlankVariable,
+			want:   "package main\n\nfunc f(x any) {\n\tswitch x.(type) {\n\t}\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixes := QuickFixes(tt.output+"\n", map[string]string{"main.go": tt.src})
+			if len(fixes) != 1 {
+				t.Fatalf("got %d fixes, want 1", len(fixes))
+			}
+			fix := fixes[0]
+			if fix.Kind != tt.kind || fix.File != "main.go" || fix.Title == "" {
+				t.Errorf("fix = %+v", fix)
+			}
+			if got := applyEdits(t, tt.src, fix.Edits); got != tt.want {
+				t.Errorf("fixed source:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuickFixes_None(t *testing.T) {
+	code := map[string]string{
+		"main.go": "package main\n\nfunc main() {\n\tsort := 1\n\tprintln(sort, Reverse())\n}\n",
+	}
+	output := strings.Join([]string{
+		"main.go:5:16: undefined: Reverse", // not a package
+		"main.go:9:1: missing return",      // no mechanical fix
+		"other.go:3:8: \"os\" imported and not used",
+	}, "\n")
+	if fixes := QuickFixes(output, code); len(fixes) != 0 {
+		t.Errorf("fixes = %+v, want none", fixes)
+	}
+}
+
+func TestQuickFixes_OneImportPerFile(t *testing.T) {
+	code := map[string]string{
+		"main.go": "package main\n\nfunc main() {\n\tstrings.ToUpper(\"a\")\n\tstrings.ToLower(\"b\")\n}\n",
+	}
+	output := "main.go:4:2: undefined: strings\nmain.go:5:2: undefined: strings\n"
+	if fixes := QuickFixes(output, code); len(fixes) != 1 {
+		t.Errorf("got %d fixes, want 1", len(fixes))
+	}
+}
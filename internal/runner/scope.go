@@ -0,0 +1,47 @@
+package runner
+
+import (
+	"context"
+	"path"
+)
+
+type scopeKey struct{}
+
+// WithScope returns a context asking the executor to build and test only
+// the packages under dir, a slash-separated path relative to the code
+// root, e.g. a monorepo service. An empty dir leaves ctx unchanged.
+func WithScope(ctx context.Context, dir string) context.Context {
+	if dir == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, scopeKey{}, dir)
+}
+
+// ScopeFromContext returns the directory set with WithScope, or "" when
+// the whole code tree is built and tested
+func ScopeFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	dir, _ := ctx.Value(scopeKey{}).(string)
+	return dir
+}
+
+// packagePattern returns the go package pattern covering the scope on
+// ctx: ./... without one, ./dir/... with one
+func packagePattern(ctx context.Context) string {
+	dir := ScopeFromContext(ctx)
+	if dir == "" {
+		return "./..."
+	}
+	return "./" + path.Join(dir, "...")
+}
+
+// debugPackage returns the package Delve debugs: the scope's own package,
+// or the root one
+func debugPackage(ctx context.Context) string {
+	if dir := ScopeFromContext(ctx); dir != "" {
+		return "./" + dir
+	}
+	return "."
+}
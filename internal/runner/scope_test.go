@@ -0,0 +1,27 @@
+package runner
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPackagePattern(t *testing.T) {
+	ctx := context.Background()
+	if got := packagePattern(ctx); got != "./..." {
+		t.Errorf("packagePattern() = %q; want ./...", got)
+	}
+	if got := debugPackage(ctx); got != "." {
+		t.Errorf("debugPackage() = %q; want .", got)
+	}
+	if WithScope(ctx, "") != ctx {
+		t.Error("WithScope with no directory should return ctx unchanged")
+	}
+
+	ctx = WithScope(ctx, "services/auth")
+	if got := packagePattern(ctx); got != "./services/auth/..." {
+		t.Errorf("packagePattern(scoped) = %q; want ./services/auth/...", got)
+	}
+	if got := debugPackage(ctx); got != "./services/auth" {
+		t.Errorf("debugPackage(scoped) = %q; want ./services/auth", got)
+	}
+}
@@ -0,0 +1,172 @@
+package runner
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// probeSource is the probe program, built inside the sandbox next to the
+// learner's code. The leading underscore keeps it out of ./...
+//
+//go:embed probe/main.go
+var probeSource string
+
+const (
+	probeFile       = "_temperprobe/main.go"
+	probeConfigFile = "_temperprobe/config.json"
+	probeMarker     = "temper-probes: "
+	serverLogMarker = "--- server log ---"
+)
+
+// serverScript builds the probe and the program, starts the program in
+// the background and probes it. Exit 2 means the program didn't build.
+const serverScript = `go build -o /tmp/temper-probe ./` + probeFile + ` || exit 3
+go build -o /tmp/temper-server "$@" || exit 2
+/tmp/temper-server > /tmp/temper-server.log 2>&1 &
+/tmp/temper-probe ` + probeConfigFile + `
+echo "` + serverLogMarker + `"
+tail -c 16384 /tmp/temper-server.log`
+
+// ServerRunner is implemented by executors that can run the learner's
+// program as a server and check it with HTTP probes
+type ServerRunner interface {
+	// RunServer builds and starts the program, waits for it to answer,
+	// sends the probes and tears it down
+	RunServer(ctx context.Context, code map[string]string, check domain.ServerCheck) (*ServerResult, error)
+}
+
+// ServerResult contains the result of a server run
+type ServerResult struct {
+	BuildOK bool
+	Started bool
+	Probes  []domain.ProbeResult
+	Output  string // build errors, or what the server logged
+}
+
+// Passed reports whether the server started and every probe passed
+func (r *ServerResult) Passed() bool {
+	if !r.Started {
+		return false
+	}
+	for _, p := range r.Probes {
+		if !p.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// RunServer runs the package in scope as a server in one container, with
+// the probe program next to it on the loopback interface
+func (e *DockerExecutor) RunServer(ctx context.Context, code map[string]string, check domain.ServerCheck) (*ServerResult, error) {
+	execCtx, cancel := context.WithTimeout(ctx, e.timeoutFor(ctx))
+	defer cancel()
+
+	config, err := probeConfig(check)
+	if err != nil {
+		return nil, err
+	}
+
+	codeWithMod := make(map[string]string, len(code)+3)
+	for k, v := range code {
+		codeWithMod[k] = v
+	}
+	if _, ok := codeWithMod["go.mod"]; !ok {
+		codeWithMod["go.mod"] = defaultGoMod(GoVersionFromContext(ctx))
+	}
+	codeWithMod[probeFile] = probeSource
+	codeWithMod[probeConfigFile] = config
+
+	opts, violations := e.dependencyOptions(code, e.buildOptions(ctx))
+	if len(violations) > 0 {
+		return &ServerResult{Output: violationMessage(violations)}, nil
+	}
+
+	cmd := append([]string{"sh", "-c", serverScript, "sh"}, buildFlags(ctx)...)
+	cmd = append(cmd, debugPackage(ctx))
+	output, exitCode, err := e.runInContainerWith(execCtx, codeWithMod, cmd, opts)
+	if err != nil {
+		return nil, err
+	}
+	switch exitCode {
+	case 0:
+	case 2:
+		return &ServerResult{Output: output}, nil
+	default:
+		return nil, fmt.Errorf("server run: probe failed (exit %d): %s", exitCode, output)
+	}
+	return parseServerOutput(output)
+}
+
+// probeConfig returns the probe program's config for check
+func probeConfig(check domain.ServerCheck) (string, error) {
+	type probe struct {
+		Name          string            `json:"name"`
+		Method        string            `json:"method"`
+		Path          string            `json:"path"`
+		Headers       map[string]string `json:"headers,omitempty"`
+		Body          string            `json:"body,omitempty"`
+		ExpectStatus  int               `json:"expect_status,omitempty"`
+		ExpectBody    string            `json:"expect_body,omitempty"`
+		ExpectHeaders map[string]string `json:"expect_headers,omitempty"`
+	}
+	check = check.WithDefaults()
+	cfg := struct {
+		BaseURL   string  `json:"base_url"`
+		Ready     string  `json:"ready"`
+		StartupMS int64   `json:"startup_ms"`
+		Probes    []probe `json:"probes"`
+	}{
+		BaseURL:   fmt.Sprintf("http://127.0.0.1:%d", check.Port),
+		Ready:     check.Ready,
+		StartupMS: check.Startup.Milliseconds(),
+	}
+	for _, p := range check.Probes {
+		cfg.Probes = append(cfg.Probes, probe{
+			Name:          p.Name,
+			Method:        p.Method,
+			Path:          p.Path,
+			Headers:       p.Headers,
+			Body:          p.Body,
+			ExpectStatus:  p.ExpectStatus,
+			ExpectBody:    p.ExpectBody,
+			ExpectHeaders: p.ExpectHeader,
+		})
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("probe config: %w", err)
+	}
+	return string(data), nil
+}
+
+// parseServerOutput reads the probe results and the server log from the
+// output of serverScript. Only the first results line counts: the server
+// log comes after it and is the learner's to write.
+func parseServerOutput(output string) (*ServerResult, error) {
+	for _, line := range strings.Split(output, "\n") {
+		rest, ok := strings.CutPrefix(line, probeMarker)
+		if !ok {
+			continue
+		}
+		var report struct {
+			Started bool                 `json:"started"`
+			Probes  []domain.ProbeResult `json:"probes"`
+		}
+		if err := json.Unmarshal([]byte(rest), &report); err != nil {
+			return nil, fmt.Errorf("parse probe results: %w", err)
+		}
+		result := &ServerResult{BuildOK: true, Started: report.Started, Probes: report.Probes}
+		if _, log, ok := strings.Cut(output, serverLogMarker+"\n"); ok {
+			result.Output = log
+		}
+		return result, nil
+	}
+	return nil, errors.New("server run: no probe results in output")
+}
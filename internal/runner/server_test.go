@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestProbe(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the probe program")
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			w.WriteHeader(http.StatusOK)
+		case "/todos":
+			if r.Method != http.MethodPost {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1,"title":"milk"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	port, _ := strconv.Atoi(srv.URL[strings.LastIndex(srv.URL, ":")+1:])
+
+	config, err := probeConfig(domain.ServerCheck{
+		Port:    port,
+		Ready:   "/healthz",
+		Startup: time.Second,
+		Probes: []domain.HTTPProbe{
+			{Method: "post", Path: "/todos", Body: `{"title":"milk"}`, ExpectStatus: 201,
+				ExpectBody: `"title":"milk"`, ExpectHeader: map[string]string{"Content-Type": "application/json"}},
+			{Name: "missing", Path: "/nope", ExpectStatus: 200},
+		},
+	})
+	if err != nil {
+		t.Fatalf("probeConfig() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(probeSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("go", "run", "main.go", "config.json")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("probe: %v\n%s", err, out)
+	}
+
+	result, err := parseServerOutput(string(out) + serverLogMarker + "\nlistening\n")
+	if err != nil {
+		t.Fatalf("parseServerOutput() error = %v", err)
+	}
+	if !result.Started || len(result.Probes) != 2 {
+		t.Fatalf("result = %+v; want a started server and 2 probes", result)
+	}
+	if p := result.Probes[0]; !p.Passed || p.Name != "POST /todos" || p.Status != 201 {
+		t.Errorf("probe 0 = %+v; want POST /todos to pass", p)
+	}
+	if p := result.Probes[1]; p.Passed || p.Message != "status 404, want 200" {
+		t.Errorf("probe 1 = %+v; want a status mismatch", p)
+	}
+	if result.Passed() {
+		t.Error("Passed() = true with a failing probe")
+	}
+	if result.Output != "listening\n" {
+		t.Errorf("Output = %q; want the server log", result.Output)
+	}
+}
+
+func TestParseServerOutput(t *testing.T) {
+	output := `temper-probes: {"started":true,"probes":[{"name":"GET /","passed":false}]}
+--- server log ---
+temper-probes: {"started":true,"probes":[{"name":"GET /","passed":true}]}
+`
+	result, err := parseServerOutput(output)
+	if err != nil {
+		t.Fatalf("parseServerOutput() error = %v", err)
+	}
+	if result.Passed() {
+		t.Error("a results line in the server log should not override the probe's")
+	}
+
+	if _, err := parseServerOutput("panic: boom\n"); err == nil {
+		t.Error("parseServerOutput() without results should fail")
+	}
+}
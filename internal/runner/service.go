@@ -3,6 +3,7 @@ package runner
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -76,6 +77,9 @@ func (s *Service) Execute(ctx context.Context, req ExecuteRequest) (*domain.RunO
 	if req.Recipe.Timeout > 0 {
 		timeout = time.Duration(req.Recipe.Timeout) * time.Second
 	}
+	if req.Recipe.Fuzz != nil {
+		timeout += req.Recipe.Fuzz.Time
+	}
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -145,6 +149,18 @@ func (s *Service) Execute(ctx context.Context, req ExecuteRequest) (*domain.RunO
 		}
 		output.Duration = testResult.Duration
 		output.Logs = testResult.Output
+
+		if req.Recipe.Debug && !testResult.OK {
+			output.Debug = s.debugSnapshot(ctx, req.Code, req.Recipe.TestFlags)
+		}
+
+		if req.Recipe.Fuzz != nil && testResult.OK {
+			if fuzz := s.fuzz(ctx, req.Code, *req.Recipe.Fuzz); fuzz != nil && !fuzz.OK {
+				output.TestOK = false
+				output.FuzzCrash = fuzz.Crash
+				output.Logs += fuzz.Output
+			}
+		}
 	}
 
 	// Run risk detection on the code
@@ -153,6 +169,37 @@ func (s *Service) Execute(ctx context.Context, req ExecuteRequest) (*domain.RunO
 	return output, nil
 }
 
+// debugSnapshot reruns failing tests under the debugger when the executor
+// supports it. Debugging is best-effort: failures are logged, not returned.
+func (s *Service) debugSnapshot(ctx context.Context, code map[string]string, flags []string) *domain.DebugSnapshot {
+	dbg, ok := s.executor.(DebugRunner)
+	if !ok {
+		return nil
+	}
+	result, err := dbg.RunTestsDebug(ctx, code, flags)
+	if err != nil {
+		slog.Warn("debug run failed", "error", err)
+		return nil
+	}
+	return result.Snapshot
+}
+
+// fuzz runs the recipe's fuzz target when the executor supports it.
+// Like debugging it is best-effort: an executor error is logged and the
+// run keeps its passing tests.
+func (s *Service) fuzz(ctx context.Context, code map[string]string, check domain.FuzzCheck) *FuzzResult {
+	fz, ok := s.executor.(FuzzRunner)
+	if !ok {
+		return nil
+	}
+	result, err := fz.RunFuzz(ctx, code, check)
+	if err != nil {
+		slog.Warn("fuzz run failed", "error", err)
+		return nil
+	}
+	return result
+}
+
 // Cancel cancels a running execution
 func (s *Service) Cancel(runID uuid.UUID) error {
 	s.mu.Lock()
@@ -1354,3 +1354,47 @@ not json line
 		})
 	}
 }
+
+// debugMockExecutor adds DebugRunner support to mockExecutor
+type debugMockExecutor struct {
+	mockExecutor
+	debugCalls int
+	snapshot   *domain.DebugSnapshot
+}
+
+func (m *debugMockExecutor) RunTestsDebug(ctx context.Context, code map[string]string, flags []string) (*DebugResult, error) {
+	m.debugCalls++
+	return &DebugResult{Snapshot: m.snapshot}, nil
+}
+
+func TestService_Execute_DebugOnFailure(t *testing.T) {
+	snap := &domain.DebugSnapshot{Reason: "panic", Frames: []domain.StackFrame{{Function: "main.Divide", File: "main.go", Line: 4}}}
+	executor := &debugMockExecutor{
+		mockExecutor: mockExecutor{testResult: &TestResult{OK: false}},
+		snapshot:     snap,
+	}
+	svc := NewService(DefaultConfig(), executor)
+
+	output, err := svc.Execute(context.Background(), ExecuteRequest{
+		RunID:  uuid.New(),
+		Code:   map[string]string{"main.go": "package main"},
+		Recipe: domain.CheckRecipe{Build: true, Test: true, Debug: true},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if output.Debug != snap {
+		t.Errorf("Debug = %v; want captured snapshot", output.Debug)
+	}
+
+	// Passing tests never trigger a debug rerun
+	executor.testResult = &TestResult{OK: true}
+	output, _ = svc.Execute(context.Background(), ExecuteRequest{
+		RunID:  uuid.New(),
+		Code:   map[string]string{"main.go": "package main"},
+		Recipe: domain.CheckRecipe{Build: true, Test: true, Debug: true},
+	})
+	if output.Debug != nil || executor.debugCalls != 1 {
+		t.Errorf("debug rerun on passing tests: calls = %d", executor.debugCalls)
+	}
+}
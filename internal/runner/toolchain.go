@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"context"
+	"strings"
+)
+
+// DefaultToolchainImage is the image template used for packs that pin a
+// Go version the base image doesn't ship. {version} is replaced with the
+// pinned version.
+const DefaultToolchainImage = "golang:{version}-alpine"
+
+// defaultGoDirective is the go.mod directive used when neither the code
+// nor the pack says which Go version to build for
+const defaultGoDirective = "1.22"
+
+type goVersionKey struct{}
+
+// WithGoVersion returns a context asking the executor to build and test
+// with the given Go toolchain. An empty version leaves ctx unchanged.
+func WithGoVersion(ctx context.Context, version string) context.Context {
+	if version == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, goVersionKey{}, version)
+}
+
+// GoVersionFromContext returns the Go version requested with
+// WithGoVersion, or "" if none is set.
+func GoVersionFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if v, ok := ctx.Value(goVersionKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// ToolchainImage picks the image to run code pinned to version. The base
+// image is kept when it already ships that version, so digest pins and
+// its module cache still apply; otherwise the template is filled in.
+func ToolchainImage(baseImage, template, version string) string {
+	if version == "" {
+		return baseImage
+	}
+	if base := ExpectedGoVersion(baseImage); base != "" && GoVersionMatches(base, version) {
+		return baseImage
+	}
+	if template == "" {
+		template = DefaultToolchainImage
+	}
+	return strings.ReplaceAll(template, "{version}", version)
+}
+
+// defaultGoMod returns the go.mod added to code that doesn't bring one
+func defaultGoMod(version string) string {
+	if version == "" {
+		version = defaultGoDirective
+	}
+	return "module exercise\n\ngo " + version + "\n"
+}
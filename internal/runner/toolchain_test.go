@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGoVersionContext(t *testing.T) {
+	if got := GoVersionFromContext(context.Background()); got != "" {
+		t.Errorf("empty context = %q; want empty", got)
+	}
+	ctx := WithGoVersion(context.Background(), "1.23")
+	if got := GoVersionFromContext(ctx); got != "1.23" {
+		t.Errorf("GoVersionFromContext = %q; want 1.23", got)
+	}
+	if got := WithGoVersion(context.Background(), ""); got != context.Background() {
+		t.Error("WithGoVersion with an empty version should return ctx unchanged")
+	}
+}
+
+func TestToolchainImage(t *testing.T) {
+	tests := []struct {
+		base, template, version, want string
+	}{
+		{"golang:1.22-alpine", "", "", "golang:1.22-alpine"},
+		{"golang:1.23-alpine", "", "1.23", "golang:1.23-alpine"},
+		{"golang:1.23.4-alpine@sha256:abc", "", "1.23", "golang:1.23.4-alpine@sha256:abc"},
+		{"golang:1.22-alpine", "", "1.23", "golang:1.23-alpine"},
+		{"golang:1.22-alpine", "registry.local/temper-go:{version}", "1.24", "registry.local/temper-go:1.24"},
+		{"temper-runner:latest", "", "1.23", "golang:1.23-alpine"},
+	}
+	for _, tt := range tests {
+		if got := ToolchainImage(tt.base, tt.template, tt.version); got != tt.want {
+			t.Errorf("ToolchainImage(%q, %q, %q) = %q; want %q", tt.base, tt.template, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultGoMod(t *testing.T) {
+	if got := defaultGoMod(""); got != "module exercise\n\ngo 1.22\n" {
+		t.Errorf("defaultGoMod(\"\") = %q", got)
+	}
+	if got := defaultGoMod("1.23"); got != "module exercise\n\ngo 1.23\n" {
+		t.Errorf("defaultGoMod(1.23) = %q", got)
+	}
+}
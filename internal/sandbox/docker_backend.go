@@ -62,6 +62,7 @@ func (b *DockerBackend) CreateContainer(ctx context.Context, cfg Config) (string
 			Memory:   int64(cfg.MemoryMB) * 1024 * 1024,
 			NanoCPUs: int64(cfg.CPULimit * 1e9),
 		},
+		Runtime: cfg.Runtime,
 	}
 
 	resp, err := b.client.ContainerCreate(ctx, containerCfg, hostCfg, nil, nil, "")
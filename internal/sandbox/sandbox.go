@@ -58,7 +58,8 @@ type Config struct {
 	MemoryMB   int           `json:"memory_mb"`
 	CPULimit   float64       `json:"cpu_limit"`
 	NetworkOff bool          `json:"network_off"`
-	IdleTTL    time.Duration `json:"idle_ttl"` // How long to keep idle sandbox alive
+	IdleTTL    time.Duration `json:"idle_ttl"`          // How long to keep idle sandbox alive
+	Runtime    string        `json:"runtime,omitempty"` // Docker runtime (e.g. runsc); set by the daemon, not clients
 }
 
 // DefaultConfig returns sensible defaults for a Go sandbox.
@@ -0,0 +1,177 @@
+// Package sarif writes findings as SARIF 2.1.0 logs, the format GitHub
+// code scanning and most static analysis dashboards ingest. Only the
+// subset of the format Temper's findings need is modelled.
+package sarif
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// Version and Schema identify the SARIF revision the log conforms to
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// Result levels
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+// Rule describes a kind of finding
+type Rule struct {
+	ID          string
+	Name        string
+	Description string
+	Level       string // default level of the rule's results
+}
+
+// Finding is one result to report. Line and Column are 1-based; zero
+// means unknown.
+type Finding struct {
+	RuleID  string
+	Message string
+	File    string
+	Line    int
+	Column  int
+	EndLine int
+}
+
+// Log is a SARIF log
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is the output of one tool invocation
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool names the producer of a run
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver is the tool component that produced the results
+type Driver struct {
+	Name           string                `json:"name"`
+	InformationURI string                `json:"informationUri,omitempty"`
+	Rules          []ReportingDescriptor `json:"rules"`
+}
+
+// ReportingDescriptor is a rule as SARIF spells it
+type ReportingDescriptor struct {
+	ID                   string                  `json:"id"`
+	Name                 string                  `json:"name,omitempty"`
+	ShortDescription     *Message                `json:"shortDescription,omitempty"`
+	DefaultConfiguration *ReportingConfiguration `json:"defaultConfiguration,omitempty"`
+}
+
+// ReportingConfiguration holds a rule's default level
+type ReportingConfiguration struct {
+	Level string `json:"level"`
+}
+
+// Result is one finding
+type Result struct {
+	RuleID    string     `json:"ruleId"`
+	RuleIndex int        `json:"ruleIndex"`
+	Level     string     `json:"level"`
+	Message   Message    `json:"message"`
+	Locations []Location `json:"locations"`
+}
+
+// Message is plain text shown to the reader
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Location points a result at a file region
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is a file and a region in it
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+// ArtifactLocation is a file URI, relative to the repository root
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is a span of lines and columns
+type Region struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+}
+
+// NewLog returns a single-run log of the findings, reported by tool with
+// the given rules. Findings of unknown rules are dropped, and results are
+// ordered by file and position so logs diff cleanly.
+func NewLog(tool, informationURI string, rules []Rule, findings []Finding) *Log {
+	driver := Driver{Name: tool, InformationURI: informationURI, Rules: []ReportingDescriptor{}}
+	index := make(map[string]int, len(rules))
+	for i, r := range rules {
+		index[r.ID] = i
+		driver.Rules = append(driver.Rules, ReportingDescriptor{
+			ID:                   r.ID,
+			Name:                 r.Name,
+			ShortDescription:     &Message{Text: r.Description},
+			DefaultConfiguration: &ReportingConfiguration{Level: r.Level},
+		})
+	}
+
+	sorted := append([]Finding(nil), findings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	results := []Result{}
+	for _, f := range sorted {
+		i, ok := index[f.RuleID]
+		if !ok {
+			continue
+		}
+		results = append(results, Result{
+			RuleID:    f.RuleID,
+			RuleIndex: i,
+			Level:     rules[i].Level,
+			Message:   Message{Text: f.Message},
+			Locations: []Location{location(f)},
+		})
+	}
+
+	return &Log{
+		Schema:  Schema,
+		Version: Version,
+		Runs:    []Run{{Tool: Tool{Driver: driver}, Results: results}},
+	}
+}
+
+func location(f Finding) Location {
+	loc := PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: filepath.ToSlash(f.File)}}
+	if f.Line > 0 {
+		loc.Region = &Region{StartLine: f.Line, StartColumn: f.Column}
+		if f.EndLine > f.Line {
+			loc.Region.EndLine = f.EndLine
+		}
+	}
+	return Location{PhysicalLocation: loc}
+}
@@ -0,0 +1,57 @@
+package sarif
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewLog(t *testing.T) {
+	rules := []Rule{
+		{ID: "temper/unused", Name: "Unused", Description: "Declared but never used", Level: LevelWarning},
+		{ID: "temper/review", Name: "Review", Description: "Code review feedback", Level: LevelNote},
+	}
+	log := NewLog("temper", "https://example.com", rules, []Finding{
+		{RuleID: "temper/review", Message: "Consider the empty input", File: "main.go", Line: 3, EndLine: 9},
+		{RuleID: "temper/unused", Message: "x is unused", File: "main.go", Line: 2, Column: 5},
+		{RuleID: "temper/unknown", Message: "dropped", File: "main.go", Line: 1},
+		{RuleID: "temper/review", Message: "Whole file", File: "sub/util.go"},
+	})
+
+	if log.Version != Version || log.Schema != Schema || len(log.Runs) != 1 {
+		t.Fatalf("log header = %q %q with %d runs", log.Version, log.Schema, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[1].DefaultConfiguration.Level != LevelNote {
+		t.Errorf("rules = %+v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("results = %+v; want the unknown rule dropped", run.Results)
+	}
+
+	first := run.Results[0]
+	if first.RuleID != "temper/unused" || first.RuleIndex != 0 || first.Level != LevelWarning {
+		t.Errorf("first result = %+v; want the unused finding, ordered by line", first)
+	}
+	if r := first.Locations[0].PhysicalLocation.Region; r == nil || r.StartLine != 2 || r.StartColumn != 5 || r.EndLine != 0 {
+		t.Errorf("first region = %+v", r)
+	}
+	if r := run.Results[1].Locations[0].PhysicalLocation.Region; r.EndLine != 9 {
+		t.Errorf("second region = %+v; want it to end on line 9", r)
+	}
+	last := run.Results[2].Locations[0].PhysicalLocation
+	if last.ArtifactLocation.URI != "sub/util.go" {
+		t.Errorf("uri = %q", last.ArtifactLocation.URI)
+	}
+	if last.Region != nil {
+		t.Errorf("a finding without a line should have no region: %+v", last.Region)
+	}
+
+	data, err := json.Marshal(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil || raw["$schema"] != Schema {
+		t.Errorf("marshalled log = %s", data)
+	}
+}
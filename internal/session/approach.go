@@ -0,0 +1,29 @@
+package session
+
+import "github.com/felixgeelhaar/temper/internal/analysis"
+
+// compareApproach sets a finished session's code against its exercise's
+// reference solution and authored alternatives. Only Go can be compared;
+// sessions without an exercise, or with nothing to compare on either
+// side, get nil.
+func (s *Service) compareApproach(session *Session) *analysis.ApproachReport {
+	ex, err := s.loader.LoadExerciseByID(session.ExerciseID)
+	if err != nil || (len(ex.Solution) == 0 && len(ex.Alternatives) == 0) {
+		return nil
+	}
+
+	report := &analysis.ApproachReport{Idioms: analysis.Idioms(session.Code)}
+	if c, ok := analysis.CompareApproach("reference", session.Code, ex.Solution); ok {
+		report.Reference = &c
+	}
+	for _, alt := range ex.Alternatives {
+		if c, ok := analysis.CompareApproach(alt.Name, session.Code, alt.Files); ok {
+			c.Description = alt.Description
+			report.Alternatives = append(report.Alternatives, c)
+		}
+	}
+	if report.Reference == nil && len(report.Alternatives) == 0 {
+		return nil
+	}
+	return report
+}
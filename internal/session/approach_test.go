@@ -0,0 +1,86 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestService_Complete_ComparesApproach(t *testing.T) {
+	service, store, tmpDir := setupTestService(t)
+	ctx := context.Background()
+
+	exerciseFile := filepath.Join(tmpDir, "exercises", "test-pack", "basics", "hello.yaml")
+	f, err := os.OpenFile(exerciseFile, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteString(`solution:
+  main.go: |
+    package main
+    import "strings"
+    func Join(words []string) string { return strings.Join(words, " ") }
+alternatives:
+  - name: builder
+    description: Build the string by hand
+    files:
+      main.go: |
+        package main
+        import "strings"
+        func Join(words []string) string {
+          var b strings.Builder
+          for i, w := range words {
+            if i > 0 {
+              b.WriteString(" ")
+            }
+            b.WriteString(w)
+          }
+          return b.String()
+        }
+`)
+	_ = f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := service.UpdateCode(ctx, session.ID, map[string]string{"main.go": `package main
+
+func Join(words []string) string {
+	out := ""
+	for i, w := range words {
+		if i > 0 {
+			out += " "
+		}
+		out += w
+	}
+	return out
+}
+`}); err != nil {
+		t.Fatalf("UpdateCode() error = %v", err)
+	}
+	if err := service.Complete(ctx, session.ID); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	loaded, _ := store.Get(session.ID)
+	a := loaded.Approach
+	if a == nil || a.Reference == nil || len(a.Alternatives) != 1 {
+		t.Fatalf("Approach = %+v, want the reference and one alternative compared", a)
+	}
+	if a.Alternatives[0].Similarity <= a.Reference.Similarity {
+		t.Errorf("similarity to builder %v should beat the one-line reference %v", a.Alternatives[0].Similarity, a.Reference.Similarity)
+	}
+	if a.Reference.Untried[0] != "strings.Join" || a.Alternatives[0].Description == "" {
+		t.Errorf("Approach = %+v, want strings.Join untried and the alternative's description", a)
+	}
+
+	activity, err := service.PackActivity(ctx, "test-pack")
+	if err != nil || len(activity.Exercises) != 1 || activity.Exercises[0].Approach == nil {
+		t.Errorf("PackActivity() = %+v, %v; want the approach reported", activity, err)
+	}
+}
@@ -0,0 +1,92 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+var ErrArtifactNotFound = errors.New("artifact not found")
+
+// ArtifactRun is the outcome of collecting the exercise's artifacts
+type ArtifactRun struct {
+	OK      bool                 `json:"ok"` // every build, coverage and generate step succeeded
+	Output  string               `json:"output,omitempty"`
+	Files   []domain.RunArtifact `json:"files,omitempty"`
+	Missing []string             `json:"missing,omitempty"` // declared paths the run didn't produce
+}
+
+// runArtifacts collects the files the exercise declares once the build
+// passes, and keeps them with the run's output. Without an output store
+// or an executor that can collect, the stage is skipped.
+func (s *Service) runArtifacts(ctx context.Context, session *Session, run *Run) (*ArtifactRun, error) {
+	recipe := s.checkRecipe(session)
+	if recipe == nil || len(recipe.Artifacts) == 0 {
+		return nil, nil
+	}
+	if lang := runner.LanguageFromContext(ctx); lang != "" && lang != runner.LanguageGo {
+		return nil, nil
+	}
+	ar, ok := s.executor.(runner.ArtifactRunner)
+	if !ok || s.outputs == nil {
+		slog.Warn("cannot collect artifacts; skipping artifact stage", "exercise", session.ExerciseID)
+		return nil, nil
+	}
+
+	collected, err := ar.RunArtifacts(ctx, run.Code, recipe.Artifacts)
+	if err != nil {
+		return nil, fmt.Errorf("artifact run: %w", err)
+	}
+	artifacts := &ArtifactRun{OK: collected.OK, Output: collected.Output, Missing: collected.Missing}
+	for _, f := range collected.Files {
+		if err := s.outputs.PutArtifact(run.SessionID, run.ID, f.Path, f.Data); err != nil {
+			return nil, fmt.Errorf("save artifact %s: %w", f.Path, err)
+		}
+		artifacts.Files = append(artifacts.Files, domain.RunArtifact{
+			Name: f.Name,
+			Kind: f.Kind,
+			Path: f.Path,
+			Size: int64(len(f.Data)),
+		})
+	}
+	return artifacts, nil
+}
+
+// OpenArtifact opens a file a run collected, by its workspace-relative
+// path
+func (s *Service) OpenArtifact(ctx context.Context, sessionID, runID, path string) (*domain.RunArtifact, io.ReadSeekCloser, error) {
+	if _, err := s.getLive(sessionID); err != nil {
+		return nil, nil, err
+	}
+	run, err := s.store.GetRun(sessionID, runID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil, ErrRunNotFound
+		}
+		return nil, nil, err
+	}
+	if run.Result == nil || run.Result.Artifacts == nil || s.outputs == nil {
+		return nil, nil, ErrArtifactNotFound
+	}
+
+	for i := range run.Result.Artifacts.Files {
+		artifact := run.Result.Artifacts.Files[i]
+		if artifact.Path != path {
+			continue
+		}
+		f, err := s.outputs.OpenArtifact(sessionID, runID, path)
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil, ErrArtifactNotFound
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("open artifact: %w", err)
+		}
+		return &artifact, f, nil
+	}
+	return nil, nil, ErrArtifactNotFound
+}
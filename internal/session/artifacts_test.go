@@ -0,0 +1,98 @@
+package session
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+// artifactExecutor builds a binary for every artifact run
+type artifactExecutor struct {
+	mockExecutor
+	specs [][]domain.ArtifactSpec
+}
+
+func (a *artifactExecutor) RunArtifacts(ctx context.Context, code map[string]string, specs []domain.ArtifactSpec) (*runner.ArtifactResult, error) {
+	a.specs = append(a.specs, specs)
+	return &runner.ArtifactResult{
+		OK:      true,
+		Files:   []runner.ArtifactFile{{Name: "cli", Kind: domain.ArtifactBinary, Path: "bin/cli", Data: []byte("\x7fELF")}},
+		Missing: []string{"coverage.html"},
+	}, nil
+}
+
+func TestService_RunCode_Artifacts(t *testing.T) {
+	service, _, tmpDir := setupTestService(t)
+	service.SetOutputStore(NewDirOutputStore(t.TempDir()))
+	ctx := context.Background()
+
+	exerciseYAML := `id: basics/hello
+title: Hello World
+check_recipe:
+  build: true
+  test: true
+  artifacts:
+    - name: cli
+      kind: binary
+      path: bin/cli
+    - name: coverage
+      kind: coverage
+      path: coverage.html
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "exercises", "test-pack", "basics", "hello.yaml"), []byte(exerciseYAML), 0644); err != nil {
+		t.Fatalf("failed to write hello.yaml: %v", err)
+	}
+	sess, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	exec := &artifactExecutor{}
+	service.executor = exec
+
+	// A failed build has nothing to collect
+	exec.buildResult = &runner.BuildResult{OK: false}
+	run, err := service.RunCode(ctx, sess.ID, RunRequest{Build: true, Test: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if run.Result.Artifacts != nil || len(exec.specs) != 0 {
+		t.Errorf("artifacts = %+v; want none after a failed build", run.Result.Artifacts)
+	}
+
+	exec.buildResult = &runner.BuildResult{OK: true}
+	run, err = service.RunCode(ctx, sess.ID, RunRequest{Build: true, Test: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if len(exec.specs) != 1 || len(exec.specs[0]) != 2 {
+		t.Fatalf("specs = %+v; want both artifacts", exec.specs)
+	}
+	artifacts := run.Result.Artifacts
+	if artifacts == nil || !artifacts.OK || len(artifacts.Files) != 1 || len(artifacts.Missing) != 1 {
+		t.Fatalf("artifacts = %+v", artifacts)
+	}
+	if f := artifacts.Files[0]; f.Name != "cli" || f.Path != "bin/cli" || f.Size != 4 {
+		t.Errorf("file = %+v", f)
+	}
+
+	artifact, file, err := service.OpenArtifact(ctx, sess.ID, run.ID, "bin/cli")
+	if err != nil {
+		t.Fatalf("OpenArtifact() error = %v", err)
+	}
+	defer file.Close()
+	if data, _ := io.ReadAll(file); string(data) != "\x7fELF" || artifact.Kind != domain.ArtifactBinary {
+		t.Errorf("artifact %+v = %q", artifact, data)
+	}
+
+	for _, path := range []string{"coverage.html", "../bin/cli", "main.go"} {
+		if _, _, err := service.OpenArtifact(ctx, sess.ID, run.ID, path); err != ErrArtifactNotFound {
+			t.Errorf("OpenArtifact(%q) error = %v, want ErrArtifactNotFound", path, err)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrDeadlinePassed is returned for runs on an assignment whose
+	// deadline, grace included, has passed and whose late policy locks it
+	ErrDeadlinePassed  = errors.New("assignment deadline passed")
+	ErrInvalidDeadline = errors.New("invalid assignment deadline")
+)
+
+// Assignment records that an instructor assigned a session's exercise or
+// spec to a member of a cohort
+type Assignment struct {
+	Cohort     string    `json:"cohort"`
+	Learner    string    `json:"learner"`        // the member's profile ID
+	Name       string    `json:"name,omitempty"` // display name, when the member shared one
+	AssignedAt time.Time `json:"assigned_at"`
+	Deadline   *Deadline `json:"deadline,omitempty"`
+}
+
+// Late policies decide what happens to runs after a deadline
+const (
+	LateFlag = "flag" // runs go through and are marked late
+	LateLock = "lock" // runs are refused
+)
+
+// Deadline is when assigned work is due. Runs within the grace period
+// after Due still count as on time.
+type Deadline struct {
+	Due          time.Time `json:"due"`
+	GraceSeconds int       `json:"grace_seconds,omitempty"`
+	LatePolicy   string    `json:"late_policy,omitempty"` // LateFlag (the default) or LateLock
+}
+
+// Deadline states, as seen by the learner
+const (
+	DeadlineOpen   = "open"
+	DeadlineGrace  = "grace"  // past due, within the grace period
+	DeadlineLate   = "late"   // runs are flagged late
+	DeadlineLocked = "locked" // runs are refused
+)
+
+// Validate checks the deadline's grace and late policy
+func (d *Deadline) Validate() error {
+	if d == nil {
+		return nil
+	}
+	if d.Due.IsZero() {
+		return errors.New("due time is required")
+	}
+	if d.GraceSeconds < 0 {
+		return errors.New("grace_seconds cannot be negative")
+	}
+	if d.LatePolicy != "" && d.LatePolicy != LateFlag && d.LatePolicy != LateLock {
+		return fmt.Errorf("late_policy must be %q or %q", LateFlag, LateLock)
+	}
+	return nil
+}
+
+// Cutoff is the last moment a run counts as on time
+func (d *Deadline) Cutoff() time.Time {
+	return d.Due.Add(time.Duration(d.GraceSeconds) * time.Second)
+}
+
+// Passed reports whether t is after the cutoff. A nil deadline never
+// passes.
+func (d *Deadline) Passed(t time.Time) bool {
+	return d != nil && t.After(d.Cutoff())
+}
+
+// Locks reports whether runs are refused once the deadline passed
+func (d *Deadline) Locks() bool {
+	return d != nil && d.LatePolicy == LateLock
+}
+
+// State returns the deadline's state at now
+func (d *Deadline) State(now time.Time) string {
+	switch {
+	case !now.After(d.Due):
+		return DeadlineOpen
+	case !d.Passed(now):
+		return DeadlineGrace
+	case d.Locks():
+		return DeadlineLocked
+	default:
+		return DeadlineLate
+	}
+}
+
+// deadline returns the deadline of an assigned session, or nil
+func (s *Session) deadline() *Deadline {
+	if s.Assignment == nil {
+		return nil
+	}
+	return s.Assignment.Deadline
+}
+
+// Learner is a cohort member work is assigned to
+type Learner struct {
+	ID   string // profile ID
+	Name string
+}
+
+// AssignRequest assigns the work described by CreateRequest (an exercise
+// or a spec, with its track and settings) to every learner
+type AssignRequest struct {
+	CreateRequest
+	Cohort   string
+	Learners []Learner
+	Deadline *Deadline // optional
+}
+
+// Outcomes of assigning work to one learner
+const (
+	AssignCreated  = "created"
+	AssignExisting = "existing" // the learner already has a session for the work
+	AssignFailed   = "failed"
+)
+
+// AssignResult is the outcome of assigning work to one learner
+type AssignResult struct {
+	Learner   string `json:"learner"`
+	Name      string `json:"name,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Assign creates a session for each learner. Learners who already have a
+// live session for the same work keep it, so assigning twice is safe;
+// a deadline given again replaces theirs, which is how one is extended.
+// Failures are reported per learner; only when every learner failed is
+// the first error returned, since the request itself is then at fault
+// (an unknown exercise, say).
+func (s *Service) Assign(ctx context.Context, req AssignRequest) ([]AssignResult, error) {
+	if err := req.Deadline.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDeadline, err)
+	}
+
+	assigned, err := s.Assignments(ctx, req.Cohort)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]*Session)
+	for _, sess := range assigned {
+		if sameWork(sess, req.CreateRequest) {
+			existing[sess.Assignment.Learner] = sess
+		}
+	}
+
+	now := time.Now()
+	results := make([]AssignResult, 0, len(req.Learners))
+	var firstErr error
+	failed := 0
+	for _, l := range req.Learners {
+		result := AssignResult{Learner: l.ID, Name: l.Name}
+		if sess, ok := existing[l.ID]; ok {
+			result.SessionID = sess.ID
+			result.Status = AssignExisting
+			if req.Deadline != nil {
+				deadline := *req.Deadline
+				sess.Assignment.Deadline = &deadline
+				if err := s.store.Save(sess); err != nil {
+					result.Status = AssignFailed
+					result.Error = fmt.Sprintf("save session: %v", err)
+				}
+			}
+			results = append(results, result)
+			continue
+		}
+
+		create := req.CreateRequest
+		create.Assignment = &Assignment{Cohort: req.Cohort, Learner: l.ID, Name: l.Name, AssignedAt: now}
+		if req.Deadline != nil {
+			deadline := *req.Deadline
+			create.Assignment.Deadline = &deadline
+		}
+		sess, err := s.Create(ctx, create)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+			result.Status = AssignFailed
+			result.Error = err.Error()
+		} else {
+			result.SessionID = sess.ID
+			result.Status = AssignCreated
+		}
+		results = append(results, result)
+	}
+
+	if failed > 0 && failed == len(req.Learners) {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// Assignments returns the sessions assigned to members of a cohort,
+// finished ones included. Deleted sessions are left out.
+func (s *Service) Assignments(ctx context.Context, cohort string) ([]*Session, error) {
+	ids, err := s.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	sessions := []*Session{}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sess, err := s.store.Get(id)
+		if err != nil || sess.Assignment == nil || sess.Assignment.Cohort != cohort || sess.Status == StatusDeleted {
+			continue
+		}
+		sessions = append(sessions, sess)
+	}
+	return sessions, nil
+}
+
+// sameWork reports whether sess works on what req asks for
+func sameWork(sess *Session, req CreateRequest) bool {
+	if req.ExerciseID != "" {
+		return sess.ExerciseID == req.ExerciseID
+	}
+	return req.SpecPath != "" && sess.SpecPath == req.SpecPath
+}
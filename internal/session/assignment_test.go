@@ -0,0 +1,170 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestService_Assign(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	ctx := context.Background()
+	learners := []Learner{{ID: "p1", Name: "ada"}, {ID: "p2"}}
+
+	results, err := service.Assign(ctx, AssignRequest{
+		CreateRequest: CreateRequest{ExerciseID: "test-pack/basics/hello"},
+		Cohort:        "go-101",
+		Learners:      learners,
+	})
+	if err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Assign() returned %d results; want 2", len(results))
+	}
+	for _, r := range results {
+		if r.Status != AssignCreated || r.SessionID == "" {
+			t.Errorf("result = %+v; want a created session", r)
+		}
+	}
+
+	sess, err := service.Get(ctx, results[0].SessionID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if a := sess.Assignment; a == nil || a.Cohort != "go-101" || a.Learner != "p1" || a.Name != "ada" || a.AssignedAt.IsZero() {
+		t.Errorf("Assignment = %+v; want go-101/p1", sess.Assignment)
+	}
+
+	// Assigning again keeps the sessions and adds the new learner's
+	results, err = service.Assign(ctx, AssignRequest{
+		CreateRequest: CreateRequest{ExerciseID: "test-pack/basics/hello"},
+		Cohort:        "go-101",
+		Learners:      append(learners, Learner{ID: "p3"}),
+	})
+	if err != nil {
+		t.Fatalf("Assign() again error = %v", err)
+	}
+	if results[0].Status != AssignExisting || results[1].Status != AssignExisting || results[2].Status != AssignCreated {
+		t.Errorf("results = %+v; want existing, existing, created", results)
+	}
+
+	assigned, err := service.Assignments(ctx, "go-101")
+	if err != nil {
+		t.Fatalf("Assignments() error = %v", err)
+	}
+	if len(assigned) != 3 {
+		t.Errorf("Assignments() = %d sessions; want 3", len(assigned))
+	}
+	if other, _ := service.Assignments(ctx, "go-102"); len(other) != 0 {
+		t.Errorf("Assignments(go-102) = %d sessions; want none", len(other))
+	}
+
+	// A request every learner fails on is an error
+	_, err = service.Assign(ctx, AssignRequest{
+		CreateRequest: CreateRequest{ExerciseID: "test-pack/basics/missing"},
+		Cohort:        "go-101",
+		Learners:      learners,
+	})
+	if err != ErrExerciseNotFound {
+		t.Errorf("Assign() unknown exercise error = %v; want ErrExerciseNotFound", err)
+	}
+}
+
+func TestDeadline_State(t *testing.T) {
+	due := time.Date(2026, 3, 8, 17, 0, 0, 0, time.UTC)
+	flag := &Deadline{Due: due, GraceSeconds: 3600}
+	lock := &Deadline{Due: due, LatePolicy: LateLock}
+
+	tests := []struct {
+		deadline *Deadline
+		at       time.Time
+		want     string
+	}{
+		{flag, due, DeadlineOpen},
+		{flag, due.Add(30 * time.Minute), DeadlineGrace},
+		{flag, due.Add(2 * time.Hour), DeadlineLate},
+		{lock, due.Add(time.Second), DeadlineLocked},
+	}
+	for _, tt := range tests {
+		if got := tt.deadline.State(tt.at); got != tt.want {
+			t.Errorf("State(%v) = %q; want %q", tt.at, got, tt.want)
+		}
+	}
+
+	var none *Deadline
+	if none.Passed(due) || none.Locks() || none.Validate() != nil {
+		t.Error("a nil deadline should never pass or lock")
+	}
+	for _, bad := range []*Deadline{{}, {Due: due, GraceSeconds: -1}, {Due: due, LatePolicy: "drop"}} {
+		if bad.Validate() == nil {
+			t.Errorf("Validate(%+v) = nil; want an error", bad)
+		}
+	}
+}
+
+func TestService_RunCode_Deadline(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	ctx := context.Background()
+	past := &Deadline{Due: time.Now().Add(-time.Hour)}
+
+	results, err := service.Assign(ctx, AssignRequest{
+		CreateRequest: CreateRequest{ExerciseID: "test-pack/basics/hello"},
+		Cohort:        "go-101",
+		Learners:      []Learner{{ID: "p1"}},
+		Deadline:      &Deadline{Due: time.Now().Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	id := results[0].SessionID
+
+	run, err := service.RunCode(ctx, id, RunRequest{Build: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if run.Result.Late {
+		t.Error("a run before the deadline was flagged late")
+	}
+
+	// Assigning again moves the deadline into the past
+	if _, err := service.Assign(ctx, AssignRequest{
+		CreateRequest: CreateRequest{ExerciseID: "test-pack/basics/hello"},
+		Cohort:        "go-101",
+		Learners:      []Learner{{ID: "p1"}},
+		Deadline:      past,
+	}); err != nil {
+		t.Fatalf("Assign() again error = %v", err)
+	}
+	run, err = service.RunCode(ctx, id, RunRequest{Build: true})
+	if err != nil {
+		t.Fatalf("RunCode() late error = %v", err)
+	}
+	if !run.Result.Late {
+		t.Error("a run after the deadline was not flagged late")
+	}
+
+	past.LatePolicy = LateLock
+	if _, err := service.Assign(ctx, AssignRequest{
+		CreateRequest: CreateRequest{ExerciseID: "test-pack/basics/hello"},
+		Cohort:        "go-101",
+		Learners:      []Learner{{ID: "p1"}},
+		Deadline:      past,
+	}); err != nil {
+		t.Fatalf("Assign() lock error = %v", err)
+	}
+	if _, err := service.RunCode(ctx, id, RunRequest{Build: true}); err != ErrDeadlinePassed {
+		t.Errorf("RunCode() locked error = %v; want ErrDeadlinePassed", err)
+	}
+
+	_, err = service.Assign(ctx, AssignRequest{
+		CreateRequest: CreateRequest{ExerciseID: "test-pack/basics/hello"},
+		Cohort:        "go-101",
+		Learners:      []Learner{{ID: "p1"}},
+		Deadline:      &Deadline{Due: time.Now(), GraceSeconds: -1},
+	})
+	if !errors.Is(err, ErrInvalidDeadline) {
+		t.Errorf("Assign() invalid deadline error = %v; want ErrInvalidDeadline", err)
+	}
+}
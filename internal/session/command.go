@@ -0,0 +1,85 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+var (
+	ErrUnknownCommand     = errors.New("unknown command")
+	ErrCommandNotAllowed  = errors.New("command not in the allowlist")
+	ErrCommandUnsupported = errors.New("executor cannot run custom commands")
+)
+
+// CommandRun is the outcome of a project's custom command
+type CommandRun struct {
+	Name     string        `json:"name"`
+	Command  string        `json:"command"`
+	OK       bool          `json:"ok"`
+	ExitCode int           `json:"exit_code"`
+	Output   string        `json:"output,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// SetCommandAllowlist sets the custom commands the user approved. A
+// project can name any command in its .temper.yaml; only those the
+// allowlist allows run.
+func (s *Service) SetCommandAllowlist(a runner.CommandAllowlist) {
+	s.commands = a
+}
+
+// runCommand runs the project's command called name in place of format,
+// build and test, and saves the run. Command runs don't count toward the
+// profile or stuck detection: they check whatever the project wants,
+// not the exercise.
+func (s *Service) runCommand(ctx context.Context, session *Session, run *Run, result *RunResult, name string) (*Run, error) {
+	var line string
+	if session.WorkspaceRoot != "" && s.projects != nil {
+		line = s.projects.Commands(session.WorkspaceRoot)[name]
+	}
+	if line == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCommand, name)
+	}
+	argv, err := runner.ParseCommand(line)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCommandNotAllowed, err)
+	}
+	if !s.commands.Allows(argv) {
+		return nil, fmt.Errorf("%w: %s", ErrCommandNotAllowed, strings.Join(argv, " "))
+	}
+	cr, ok := s.executor.(runner.CommandRunner)
+	if !ok {
+		return nil, ErrCommandUnsupported
+	}
+
+	out, err := cr.RunCommand(ctx, run.Code, argv)
+	if err != nil {
+		return nil, fmt.Errorf("command run: %w", err)
+	}
+	result.Command = &CommandRun{
+		Name:     name,
+		Command:  strings.Join(argv, " "),
+		OK:       out.OK,
+		ExitCode: out.ExitCode,
+		Output:   out.Output,
+		Duration: out.Duration,
+	}
+	result.Duration = out.Duration
+	run.Result = result
+	s.spillOutputs(run)
+
+	session.RecordRun()
+	session.UpdateCode(run.Code)
+	if err := s.store.Save(session); err != nil {
+		return nil, fmt.Errorf("save session: %w", err)
+	}
+	if err := s.store.SaveRun(run); err != nil {
+		return nil, fmt.Errorf("save run: %w", err)
+	}
+	return run, nil
+}
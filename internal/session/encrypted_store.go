@@ -0,0 +1,282 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/vault"
+)
+
+// encryptedCodeKey holds the sealed file map, so file names are hidden
+// along with their contents
+const encryptedCodeKey = "\x00encrypted"
+
+// EncryptedStore wraps a SessionStore and encrypts session code, run code,
+// run output and intervention content before they reach disk. Everything
+// else (IDs, status, timestamps, test names and outcomes) stays in the
+// clear so listing, pruning and stats work without the key. Records
+// written before encryption was enabled are read as-is.
+type EncryptedStore struct {
+	SessionStore
+	cipher *vault.Cipher
+}
+
+// NewEncryptedStore wraps inner with at-rest encryption
+func NewEncryptedStore(inner SessionStore, cipher *vault.Cipher) *EncryptedStore {
+	return &EncryptedStore{SessionStore: inner, cipher: cipher}
+}
+
+var _ SessionStore = (*EncryptedStore)(nil)
+
+// Save encrypts the session's code and persists it
+func (s *EncryptedStore) Save(session *Session) error {
+	code, err := s.sealCode(session.Code)
+	if err != nil {
+		return err
+	}
+	sealed := *session
+	sealed.Code = code
+	return s.SessionStore.Save(&sealed)
+}
+
+// Get loads and decrypts a session
+func (s *EncryptedStore) Get(id string) (*Session, error) {
+	session, err := s.SessionStore.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if session.Code, err = s.openCode(session.Code); err != nil {
+		return nil, fmt.Errorf("session %s: %w", id, err)
+	}
+	return session, nil
+}
+
+// ListActive loads and decrypts all active sessions
+func (s *EncryptedStore) ListActive() ([]*Session, error) {
+	sessions, err := s.SessionStore.ListActive()
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		if session.Code, err = s.openCode(session.Code); err != nil {
+			return nil, fmt.Errorf("session %s: %w", session.ID, err)
+		}
+	}
+	return sessions, nil
+}
+
+// SaveRun encrypts the run's code and persists it
+func (s *EncryptedStore) SaveRun(run *Run) error {
+	code, err := s.sealCode(run.Code)
+	if err != nil {
+		return err
+	}
+	sealed := *run
+	sealed.Code = code
+	if run.Result != nil {
+		result := copyRunResult(run.Result)
+		for _, field := range runOutputs(result) {
+			if *field, err = s.sealText(*field); err != nil {
+				return err
+			}
+		}
+		sealed.Result = result
+	}
+	return s.SessionStore.SaveRun(&sealed)
+}
+
+// GetRun loads and decrypts a run
+func (s *EncryptedStore) GetRun(sessionID, runID string) (*Run, error) {
+	run, err := s.SessionStore.GetRun(sessionID, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run.Code, err = s.openCode(run.Code); err != nil {
+		return nil, fmt.Errorf("run %s: %w", runID, err)
+	}
+	if run.Result != nil {
+		for _, field := range runOutputs(run.Result) {
+			if *field, err = s.cipher.Decrypt(*field); err != nil {
+				return nil, fmt.Errorf("run %s: %w", runID, err)
+			}
+		}
+	}
+	return run, nil
+}
+
+// SaveIntervention encrypts the LLM transcript and persists it. Concepts
+// and redactions stay readable, like the level and type: they name topics
+// and kinds of secret, not what was said.
+func (s *EncryptedStore) SaveIntervention(intervention *Intervention) error {
+	content, err := s.cipher.Encrypt(intervention.Content)
+	if err != nil {
+		return err
+	}
+	sealed := *intervention
+	sealed.Content = content
+	return s.SessionStore.SaveIntervention(&sealed)
+}
+
+// GetIntervention loads and decrypts an intervention
+func (s *EncryptedStore) GetIntervention(sessionID, interventionID string) (*Intervention, error) {
+	intervention, err := s.SessionStore.GetIntervention(sessionID, interventionID)
+	if err != nil {
+		return nil, err
+	}
+	if intervention.Content, err = s.cipher.Decrypt(intervention.Content); err != nil {
+		return nil, fmt.Errorf("intervention %s: %w", interventionID, err)
+	}
+	return intervention, nil
+}
+
+func (s *EncryptedStore) sealCode(code map[string]string) (map[string]string, error) {
+	if code == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(code)
+	if err != nil {
+		return nil, fmt.Errorf("encode code: %w", err)
+	}
+	sealed, err := s.cipher.Encrypt(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{encryptedCodeKey: sealed}, nil
+}
+
+func (s *EncryptedStore) openCode(code map[string]string) (map[string]string, error) {
+	sealed, ok := code[encryptedCodeKey]
+	if !ok || len(code) != 1 {
+		return code, nil // plaintext from before encryption was enabled
+	}
+	data, err := s.cipher.Decrypt(sealed)
+	if err != nil {
+		return nil, err
+	}
+	var opened map[string]string
+	if err := json.Unmarshal([]byte(data), &opened); err != nil {
+		return nil, fmt.Errorf("decode code: %w", err)
+	}
+	return opened, nil
+}
+
+// sealText encrypts a text field, leaving empty ones empty so they are
+// still omitted from the record
+func (s *EncryptedStore) sealText(text string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	return s.cipher.Encrypt(text)
+}
+
+// copyRunResult copies a run result deeply enough that its output can be
+// sealed without touching the caller's
+func copyRunResult(r *RunResult) *RunResult {
+	c := *r
+	c.Tests = append([]domain.TestResult(nil), r.Tests...)
+	c.Programs = append([]ProgramRun(nil), r.Programs...)
+	if r.Server != nil {
+		server := *r.Server
+		c.Server = &server
+	}
+	if r.Fuzz != nil {
+		fuzz := *r.Fuzz
+		c.Fuzz = &fuzz
+	}
+	if r.Command != nil {
+		command := *r.Command
+		c.Command = &command
+	}
+	if r.Artifacts != nil {
+		artifacts := *r.Artifacts
+		c.Artifacts = &artifacts
+	}
+	return &c
+}
+
+// runOutputs returns the fields of a run result that hold the learner's
+// program output or input
+func runOutputs(r *RunResult) []*string {
+	fields := []*string{&r.FormatDiff, &r.BuildOutput, &r.TestOutput}
+	for i := range r.Tests {
+		fields = append(fields, &r.Tests[i].Output)
+	}
+	for i := range r.Programs {
+		fields = append(fields, &r.Programs[i].Input, &r.Programs[i].Output)
+	}
+	if r.Server != nil {
+		fields = append(fields, &r.Server.Output)
+	}
+	if r.Fuzz != nil {
+		fields = append(fields, &r.Fuzz.Output)
+	}
+	if r.Command != nil {
+		fields = append(fields, &r.Command.Output)
+	}
+	if r.Artifacts != nil {
+		fields = append(fields, &r.Artifacts.Output)
+	}
+	return fields
+}
+
+// EncryptedOutputStore wraps an OutputStore and encrypts the full run
+// output and artifacts it keeps. Files written before encryption was
+// enabled are read as-is.
+type EncryptedOutputStore struct {
+	OutputStore
+	cipher *vault.Cipher
+}
+
+// NewEncryptedOutputStore wraps inner with at-rest encryption
+func NewEncryptedOutputStore(inner OutputStore, cipher *vault.Cipher) *EncryptedOutputStore {
+	return &EncryptedOutputStore{OutputStore: inner, cipher: cipher}
+}
+
+var _ OutputStore = (*EncryptedOutputStore)(nil)
+
+// Put encrypts and writes the output of a run's stream
+func (o *EncryptedOutputStore) Put(sessionID, runID, stream string, data []byte) error {
+	sealed, err := o.cipher.Encrypt(string(data))
+	if err != nil {
+		return err
+	}
+	return o.OutputStore.Put(sessionID, runID, stream, []byte(sealed))
+}
+
+// Open opens and decrypts the output of a run's stream
+func (o *EncryptedOutputStore) Open(sessionID, runID, stream string) (io.ReadSeekCloser, error) {
+	return o.open(o.OutputStore.Open(sessionID, runID, stream))
+}
+
+// PutArtifact encrypts and writes an artifact
+func (o *EncryptedOutputStore) PutArtifact(sessionID, runID, path string, data []byte) error {
+	sealed, err := o.cipher.Encrypt(string(data))
+	if err != nil {
+		return err
+	}
+	return o.OutputStore.PutArtifact(sessionID, runID, path, []byte(sealed))
+}
+
+// OpenArtifact opens and decrypts an artifact
+func (o *EncryptedOutputStore) OpenArtifact(sessionID, runID, path string) (io.ReadSeekCloser, error) {
+	return o.open(o.OutputStore.OpenArtifact(sessionID, runID, path))
+}
+
+func (o *EncryptedOutputStore) open(f io.ReadSeekCloser, err error) (io.ReadSeekCloser, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := o.cipher.Decrypt(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return nopSeekCloser{strings.NewReader(plain)}, nil
+}
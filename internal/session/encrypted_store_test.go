@@ -0,0 +1,176 @@
+package session
+
+import (
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/vault"
+)
+
+func newEncryptedTestStore(t *testing.T) (*EncryptedStore, *Store, string) {
+	t.Helper()
+	dir := t.TempDir()
+	inner, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := make([]byte, vault.KeySize)
+	rand.Read(key)
+	cipher, err := vault.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewEncryptedStore(inner, cipher), inner, dir
+}
+
+// assertNoPlaintext fails if secret appears in any file under dir
+func assertNoPlaintext(t *testing.T, dir, secret string) {
+	t.Helper()
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, _ := os.ReadFile(path)
+		if strings.Contains(string(data), secret) {
+			t.Errorf("%s contains plaintext %q", path, secret)
+		}
+		return nil
+	})
+}
+
+func TestEncryptedStore_Session(t *testing.T) {
+	store, inner, dir := newEncryptedTestStore(t)
+
+	code := map[string]string{"proprietary.go": "package acme // trade secret"}
+	sess := NewSession("test", code, domain.DefaultPolicy())
+	if err := store.Save(sess); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if sess.Code["proprietary.go"] != code["proprietary.go"] {
+		t.Error("Save() must not modify the caller's session")
+	}
+	assertNoPlaintext(t, dir, "trade secret")
+	assertNoPlaintext(t, dir, "proprietary.go")
+
+	raw, _ := inner.Get(sess.ID)
+	if _, ok := raw.Code[encryptedCodeKey]; !ok {
+		t.Error("inner store should hold sealed code")
+	}
+
+	loaded, err := store.Get(sess.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if loaded.Code["proprietary.go"] != code["proprietary.go"] {
+		t.Errorf("Get() code = %v; want %v", loaded.Code, code)
+	}
+
+	active, err := store.ListActive()
+	if err != nil || len(active) != 1 || active[0].Code["proprietary.go"] != code["proprietary.go"] {
+		t.Errorf("ListActive() = %v, %v", active, err)
+	}
+}
+
+func TestEncryptedStore_RunAndIntervention(t *testing.T) {
+	store, _, dir := newEncryptedTestStore(t)
+
+	sess := NewSession("test", map[string]string{}, domain.DefaultPolicy())
+	store.Save(sess)
+
+	result := &RunResult{
+		BuildOK:    true,
+		TestOutput: "--- FAIL: TestSecretAlgo",
+		Tests:      []domain.TestResult{{Name: "TestLoop", Output: "secretAlgo returned 3"}},
+		Command:    &CommandRun{Name: "lint", Output: "secretAlgo: unused"},
+	}
+	store.SaveRun(&Run{ID: "run-1", SessionID: sess.ID, Code: map[string]string{"main.go": "func secretAlgo()"}, Result: result})
+	store.SaveIntervention(&Intervention{ID: "iv-1", SessionID: sess.ID, Content: "consider secretAlgo's loop bound", Concepts: []string{"loops"}})
+	assertNoPlaintext(t, dir, "secretAlgo")
+	assertNoPlaintext(t, dir, "TestSecretAlgo")
+	if result.Tests[0].Output != "secretAlgo returned 3" || result.Command.Output != "secretAlgo: unused" {
+		t.Error("SaveRun() must not modify the caller's run result")
+	}
+
+	run, err := store.GetRun(sess.ID, "run-1")
+	if err != nil || run.Code["main.go"] != "func secretAlgo()" {
+		t.Errorf("GetRun() = %+v, %v", run, err)
+	}
+	if run.Result.TestOutput != result.TestOutput || run.Result.Tests[0].Output != result.Tests[0].Output ||
+		run.Result.Command.Output != result.Command.Output || !run.Result.BuildOK || run.Result.BuildOutput != "" {
+		t.Errorf("GetRun() result = %+v", run.Result)
+	}
+	iv, err := store.GetIntervention(sess.ID, "iv-1")
+	if err != nil || iv.Content != "consider secretAlgo's loop bound" || len(iv.Concepts) != 1 || iv.Concepts[0] != "loops" {
+		t.Errorf("GetIntervention() = %+v, %v", iv, err)
+	}
+}
+
+func TestEncryptedStore_ReadsPlaintext(t *testing.T) {
+	store, inner, _ := newEncryptedTestStore(t)
+
+	// Written before encryption was enabled
+	sess := NewSession("test", map[string]string{"main.go": "package main"}, domain.DefaultPolicy())
+	inner.Save(sess)
+	inner.SaveIntervention(&Intervention{ID: "iv-1", SessionID: sess.ID, Content: "old hint"})
+
+	loaded, err := store.Get(sess.ID)
+	if err != nil || loaded.Code["main.go"] != "package main" {
+		t.Errorf("Get() = %+v, %v", loaded, err)
+	}
+	iv, err := store.GetIntervention(sess.ID, "iv-1")
+	if err != nil || iv.Content != "old hint" {
+		t.Errorf("GetIntervention() = %+v, %v", iv, err)
+	}
+}
+
+func TestEncryptedOutputStore(t *testing.T) {
+	dir := t.TempDir()
+	key := make([]byte, vault.KeySize)
+	rand.Read(key)
+	cipher, err := vault.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner := NewDirOutputStore(dir)
+	outputs := NewEncryptedOutputStore(inner, cipher)
+
+	if err := outputs.Put("s1", "r1", StreamTest, []byte("secretAlgo panicked")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := outputs.PutArtifact("s1", "r1", "bin/app", []byte("secretAlgo binary")); err != nil {
+		t.Fatalf("PutArtifact() error = %v", err)
+	}
+	inner.Put("s1", "r2", StreamBuild, []byte("written before encryption"))
+	assertNoPlaintext(t, filepath.Join(dir, "s1", "r1"), "secretAlgo")
+
+	for _, tt := range []struct {
+		name string
+		open func() (io.ReadSeekCloser, error)
+		want string
+	}{
+		{"output", func() (io.ReadSeekCloser, error) { return outputs.Open("s1", "r1", StreamTest) }, "secretAlgo panicked"},
+		{"artifact", func() (io.ReadSeekCloser, error) { return outputs.OpenArtifact("s1", "r1", "bin/app") }, "secretAlgo binary"},
+		{"plaintext", func() (io.ReadSeekCloser, error) { return outputs.Open("s1", "r2", StreamBuild) }, "written before encryption"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.open()
+			if err != nil {
+				t.Fatalf("open error = %v", err)
+			}
+			defer f.Close()
+			data, _ := io.ReadAll(f)
+			if string(data) != tt.want {
+				t.Errorf("read %q, want %q", data, tt.want)
+			}
+		})
+	}
+
+	if _, err := outputs.Open("s1", "r1", StreamBuild); err != ErrNotFound {
+		t.Errorf("Open() missing stream error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,164 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/profile"
+)
+
+// EraseScope selects the history to erase: every session, or those on a
+// topic, created in a date range, or both
+type EraseScope struct {
+	All   bool
+	Topic string    // e.g. "go/basics", or a whole language such as "go"
+	Since time.Time // sessions created at or after; zero is no lower bound
+	Until time.Time // sessions created before; zero is no upper bound
+}
+
+// Empty reports whether the scope selects nothing
+func (sc EraseScope) Empty() bool {
+	return !sc.All && sc.Topic == "" && sc.Since.IsZero() && sc.Until.IsZero()
+}
+
+// Matches reports whether a session falls in the scope
+func (sc EraseScope) Matches(sess *Session) bool {
+	if sc.All {
+		return true
+	}
+	if sc.Empty() {
+		return false
+	}
+	if sc.Topic != "" && !profile.InTopic(profile.ExtractTopic(sess.ExerciseID), sc.Topic) {
+		return false
+	}
+	if !sc.Since.IsZero() && sess.CreatedAt.Before(sc.Since) {
+		return false
+	}
+	if !sc.Until.IsZero() && !sess.CreatedAt.Before(sc.Until) {
+		return false
+	}
+	return true
+}
+
+// EraseReport summarizes what an erase removed, or would remove on a dry
+// run
+type EraseReport struct {
+	DryRun        bool     `json:"dry_run"`
+	Sessions      []string `json:"sessions"` // IDs of erased sessions
+	Runs          int      `json:"runs"`
+	Interventions int      `json:"interventions"`
+	// Exercises no session is left for, so anything kept per exercise
+	// can go too
+	Exercises []string `json:"exercises"`
+}
+
+// Erase permanently deletes the sessions in scope, tombstones included,
+// with their runs and interventions, and takes them out of the learning
+// profile. Erasing everything resets the profile. With dryRun set nothing
+// is deleted.
+func (s *Service) Erase(ctx context.Context, scope EraseScope, dryRun bool) (*EraseReport, error) {
+	report := &EraseReport{DryRun: dryRun, Sessions: []string{}, Exercises: []string{}}
+	if scope.Empty() {
+		return report, nil
+	}
+
+	ids, err := s.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	var erased []*Session
+	kept := make(map[string]bool)
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sess, err := s.store.Get(id)
+		if err != nil {
+			continue
+		}
+		if !scope.Matches(sess) {
+			kept[sess.ExerciseID] = true
+			continue
+		}
+		erased = append(erased, sess)
+	}
+
+	forget := profile.ForgetRequest{
+		Runs:  make(map[string][]profile.RunInfo),
+		Topic: scope.Topic,
+		Since: scope.Since,
+		Until: scope.Until,
+	}
+	exercises := make(map[string]bool)
+	for _, sess := range erased {
+		runIDs, err := s.store.ListRuns(sess.ID)
+		if err != nil {
+			return report, fmt.Errorf("list runs for %s: %w", sess.ID, err)
+		}
+		for _, runID := range runIDs {
+			run, err := s.store.GetRun(sess.ID, runID)
+			if err != nil || run.Result == nil {
+				continue
+			}
+			forget.Runs[sess.ID] = append(forget.Runs[sess.ID], profile.RunInfo{
+				Success:     run.Result.BuildOK && run.Result.TestOK,
+				BuildOutput: run.Result.BuildOutput,
+				TestOutput:  run.Result.TestOutput,
+			})
+		}
+		interventionIDs, err := s.store.ListInterventions(sess.ID)
+		if err != nil {
+			return report, fmt.Errorf("list interventions for %s: %w", sess.ID, err)
+		}
+
+		if !dryRun {
+			if err := s.store.Delete(sess.ID); err != nil {
+				return report, fmt.Errorf("delete session %s: %w", sess.ID, err)
+			}
+			s.deleteOutputs(sess.ID, "")
+		}
+		report.Sessions = append(report.Sessions, sess.ID)
+		report.Runs += len(runIDs)
+		report.Interventions += len(interventionIDs)
+		forget.Sessions = append(forget.Sessions, profile.SessionInfo{
+			ID:         sess.ID,
+			ExerciseID: sess.ExerciseID,
+			RunCount:   sess.RunCount,
+			HintCount:  sess.HintCount,
+			Status:     string(sess.Status),
+			CreatedAt:  sess.CreatedAt,
+		})
+		if sess.ExerciseID != "" && !kept[sess.ExerciseID] && !exercises[sess.ExerciseID] {
+			exercises[sess.ExerciseID] = true
+			report.Exercises = append(report.Exercises, sess.ExerciseID)
+		}
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	s.nudgeMu.Lock()
+	for _, id := range report.Sessions {
+		delete(s.nudgedAt, id)
+	}
+	s.nudgeMu.Unlock()
+
+	if s.profileService != nil {
+		if scope.All {
+			err = s.profileService.Reset(ctx)
+		} else {
+			err = s.profileService.Forget(ctx, forget)
+		}
+		if err != nil {
+			return report, fmt.Errorf("update profile: %w", err)
+		}
+	}
+
+	slog.Info("history erased", "sessions", len(report.Sessions), "runs", report.Runs, "interventions", report.Interventions)
+	return report, nil
+}
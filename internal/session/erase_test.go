@@ -0,0 +1,105 @@
+package session
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/profile"
+)
+
+func TestEraseScope_Matches(t *testing.T) {
+	jan := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	basics := &Session{ExerciseID: "go-v1/basics/hello", CreatedAt: jan}
+	python := &Session{ExerciseID: "python-v1/testing/pytest", CreatedAt: jan}
+
+	tests := []struct {
+		name  string
+		scope EraseScope
+		sess  *Session
+		want  bool
+	}{
+		{"empty scope", EraseScope{}, basics, false},
+		{"all", EraseScope{All: true}, python, true},
+		{"topic", EraseScope{Topic: "go/basics"}, basics, true},
+		{"language", EraseScope{Topic: "go"}, basics, true},
+		{"other topic", EraseScope{Topic: "go"}, python, false},
+		{"in range", EraseScope{Since: jan.AddDate(0, 0, -1), Until: jan.AddDate(0, 0, 1)}, basics, true},
+		{"before range", EraseScope{Since: jan.AddDate(0, 0, 1)}, basics, false},
+		{"until is exclusive", EraseScope{Until: jan}, basics, false},
+		{"topic and range", EraseScope{Topic: "python", Since: jan.AddDate(0, 0, -1)}, basics, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scope.Matches(tt.sess); got != tt.want {
+				t.Errorf("Matches() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_Erase(t *testing.T) {
+	service, store, tmpDir := setupTestService(t)
+	profileStore, err := profile.NewStore(filepath.Join(tmpDir, "profiles"))
+	if err != nil {
+		t.Fatalf("profile.NewStore() error = %v", err)
+	}
+	profileSvc := profile.NewService(profileStore)
+	service.SetProfileService(profileSvc)
+	ctx := context.Background()
+
+	newSession := func(exerciseID string, hints int) *Session {
+		sess := NewSession(exerciseID, map[string]string{}, domain.DefaultPolicy())
+		sess.HintCount = hints
+		store.Save(sess)
+		profileSvc.OnSessionStart(ctx, profile.SessionInfo{ID: sess.ID, ExerciseID: exerciseID, CreatedAt: sess.CreatedAt})
+		return sess
+	}
+	basics := newSession("go-v1/basics/hello", 2)
+	store.SaveRun(&Run{ID: "fail", SessionID: basics.ID, CreatedAt: time.Now(), Result: &RunResult{BuildOutput: "undefined: foo"}})
+	store.SaveIntervention(&Intervention{ID: "hint", SessionID: basics.ID})
+	profileSvc.OnRunComplete(ctx, profile.SessionInfo{ID: basics.ID}, profile.RunInfo{BuildOutput: "undefined: foo"})
+	kept := newSession("python-v1/testing/pytest", 0)
+
+	report, err := service.Erase(ctx, EraseScope{Topic: "go"}, true)
+	if err != nil {
+		t.Fatalf("Erase(dry run) error = %v", err)
+	}
+	if len(report.Sessions) != 1 || report.Runs != 1 || report.Interventions != 1 {
+		t.Errorf("dry run report = %+v; want 1 session, 1 run, 1 intervention", report)
+	}
+	if !store.Exists(basics.ID) {
+		t.Fatal("dry run erased a session")
+	}
+
+	report, err = service.Erase(ctx, EraseScope{Topic: "go"}, false)
+	if err != nil {
+		t.Fatalf("Erase() error = %v", err)
+	}
+	if len(report.Exercises) != 1 || report.Exercises[0] != "go-v1/basics/hello" {
+		t.Errorf("Exercises = %v; want the erased exercise", report.Exercises)
+	}
+	if store.Exists(basics.ID) || !store.Exists(kept.ID) {
+		t.Error("only the go session should be erased")
+	}
+
+	p, _ := profileSvc.GetProfile(ctx)
+	if p.TotalSessions != 1 || p.TotalRuns != 0 || len(p.ErrorPatterns) != 0 {
+		t.Errorf("profile = %d sessions, %d runs, patterns %v; want the erased session gone", p.TotalSessions, p.TotalRuns, p.ErrorPatterns)
+	}
+	if len(p.ExerciseHistory) != 1 || p.ExerciseHistory[0].SessionID != kept.ID {
+		t.Errorf("history = %+v; want only the kept session", p.ExerciseHistory)
+	}
+
+	if _, err := service.Erase(ctx, EraseScope{All: true}, false); err != nil {
+		t.Fatalf("Erase(all) error = %v", err)
+	}
+	if ids, _ := store.List(); len(ids) != 0 {
+		t.Errorf("sessions left after erasing everything: %v", ids)
+	}
+	if p, _ := profileSvc.GetProfile(ctx); p.TotalSessions != 0 || len(p.ExerciseHistory) != 0 {
+		t.Errorf("profile not reset: %+v", p)
+	}
+}
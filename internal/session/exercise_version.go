@@ -0,0 +1,252 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// ErrExerciseOutdated is returned when the exercise changed on disk since
+// the session started and the learner has neither migrated nor pinned
+var ErrExerciseOutdated = errors.New("exercise changed since the session started")
+
+// ExerciseBaseline is the exercise version a training session started
+// from. Keeping its starter and test files lets a later pack update be
+// re-based onto the learner's work instead of replacing it.
+type ExerciseBaseline struct {
+	Version string            `json:"version"`
+	Starter map[string]string `json:"starter"`
+	Tests   map[string]string `json:"tests"`
+	// Pinned keeps the session on this version after the pack changed
+	Pinned bool `json:"pinned,omitempty"`
+	// Variant is the exercise variant the session started on, "" for
+	// the exercise as written
+	Variant domain.VariantKind `json:"variant,omitempty"`
+}
+
+// newExerciseBaseline snapshots an exercise's files
+func newExerciseBaseline(ex *domain.Exercise) *ExerciseBaseline {
+	return &ExerciseBaseline{
+		Version: ex.Version,
+		Starter: copyFiles(ex.StarterCode),
+		Tests:   copyFiles(ex.TestCode),
+		Variant: ex.Variant,
+	}
+}
+
+// ExerciseVersionStatus compares a session's exercise version with the one
+// on disk
+type ExerciseVersionStatus struct {
+	SessionVersion string `json:"session_version,omitempty"` // empty for sessions started before versioning
+	LatestVersion  string `json:"latest_version"`
+	Outdated       bool   `json:"outdated"`
+	Pinned         bool   `json:"pinned"`
+	// ChangedFiles lists the starter and test files the update touches
+	ChangedFiles []string `json:"changed_files"`
+}
+
+// ExerciseMigration reports how an exercise update was re-based onto a
+// session
+type ExerciseMigration struct {
+	From    string   `json:"from,omitempty"`
+	To      string   `json:"to"`
+	Updated []string `json:"updated"` // replaced with the new version
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	// Kept lists files the learner changed that the update also changed;
+	// the learner's copy stays
+	Kept    []string `json:"kept"`
+	Session *Session `json:"session"`
+}
+
+// ExerciseVersion reports whether the session's exercise changed since it
+// started
+func (s *Service) ExerciseVersion(ctx context.Context, id string) (*ExerciseVersionStatus, error) {
+	session, err := s.getLive(id)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	ex, err := s.sessionExercise(session)
+	if err != nil {
+		return nil, err
+	}
+	return versionStatus(session, ex), nil
+}
+
+// PinExercise keeps the session on the exercise version it started from.
+// Runs stop reporting the mismatch; the session's files are untouched.
+func (s *Service) PinExercise(ctx context.Context, id string) (*ExerciseVersionStatus, error) {
+	session, err := s.getLive(id)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.Status != StatusActive {
+		return nil, ErrSessionNotActive
+	}
+	ex, err := s.sessionExercise(session)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.ExerciseBaseline == nil {
+		// Started before versioning: what the session holds is all we know
+		session.ExerciseBaseline = &ExerciseBaseline{}
+	}
+	session.ExerciseBaseline.Pinned = true
+	session.UpdatedAt = time.Now()
+	if err := s.store.Save(session); err != nil {
+		return nil, fmt.Errorf("save session: %w", err)
+	}
+	return versionStatus(session, ex), nil
+}
+
+// MigrateExercise re-bases the session onto the exercise on disk. Tests
+// always take the new version. Starter files the learner hasn't touched
+// take the new version too; ones they edited keep their edits.
+func (s *Service) MigrateExercise(ctx context.Context, id string) (*ExerciseMigration, error) {
+	session, err := s.getLive(id)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.Status != StatusActive {
+		return nil, ErrSessionNotActive
+	}
+	ex, err := s.sessionExercise(session)
+	if err != nil {
+		return nil, err
+	}
+
+	base := session.ExerciseBaseline
+	if base == nil {
+		base = &ExerciseBaseline{}
+	}
+	m := &ExerciseMigration{
+		From:    base.Version,
+		To:      ex.Version,
+		Updated: []string{},
+		Added:   []string{},
+		Removed: []string{},
+		Kept:    []string{},
+	}
+
+	code := copyFiles(session.Code)
+	rebase := func(old, latest map[string]string, keepEdits bool) {
+		for name, content := range latest {
+			current, exists := code[name]
+			oldContent, inOld := old[name]
+			upstreamChanged := !inOld || content != oldContent
+			switch {
+			case exists && current == content:
+			case !exists:
+				// A starter file the learner deleted stays deleted unless
+				// the update changed it
+				if keepEdits && !upstreamChanged {
+					continue
+				}
+				code[name] = content
+				m.Added = append(m.Added, name)
+			case keepEdits && (!inOld || current != oldContent):
+				if upstreamChanged {
+					m.Kept = append(m.Kept, name)
+				}
+			default:
+				code[name] = content
+				m.Updated = append(m.Updated, name)
+			}
+		}
+		for name, oldContent := range old {
+			if _, stays := latest[name]; stays {
+				continue
+			}
+			if current, exists := code[name]; exists && (!keepEdits || current == oldContent) {
+				delete(code, name)
+				m.Removed = append(m.Removed, name)
+			}
+		}
+	}
+	rebase(base.Starter, ex.StarterCode, true)
+	rebase(base.Tests, ex.TestCode, false)
+	for _, files := range [][]string{m.Updated, m.Added, m.Removed, m.Kept} {
+		sort.Strings(files)
+	}
+
+	session.UpdateCode(code)
+	session.ExerciseBaseline = newExerciseBaseline(ex)
+	if err := s.store.Save(session); err != nil {
+		return nil, fmt.Errorf("save session: %w", err)
+	}
+	m.Session = session
+	return m, nil
+}
+
+// checkExerciseVersion fails runs against an exercise that changed under
+// the session, so old code isn't silently graded by new tests. Pinned
+// sessions, unversioned ones and exercises that no longer load pass.
+func (s *Service) checkExerciseVersion(session *Session) error {
+	base := session.ExerciseBaseline
+	if base == nil || base.Pinned || base.Version == "" {
+		return nil
+	}
+	ex, err := s.sessionExercise(session)
+	if err != nil {
+		return nil
+	}
+	if ex.Version != base.Version {
+		return ErrExerciseOutdated
+	}
+	return nil
+}
+
+// sessionExercise loads the exercise a training session works on
+func (s *Service) sessionExercise(session *Session) (*domain.Exercise, error) {
+	ex, err := s.loader.LoadExerciseByID(session.ExerciseID)
+	if err != nil {
+		return nil, ErrExerciseNotFound
+	}
+	return ex.WithVariant(session.ExerciseVariant()), nil
+}
+
+func versionStatus(session *Session, ex *domain.Exercise) *ExerciseVersionStatus {
+	status := &ExerciseVersionStatus{LatestVersion: ex.Version, ChangedFiles: []string{}}
+	base := session.ExerciseBaseline
+	if base == nil {
+		return status
+	}
+	status.SessionVersion = base.Version
+	status.Pinned = base.Pinned
+	if base.Version == "" || base.Version == ex.Version {
+		return status
+	}
+
+	status.Outdated = true
+	changed := map[string]bool{}
+	for _, pair := range [][2]map[string]string{{base.Starter, ex.StarterCode}, {base.Tests, ex.TestCode}} {
+		for name, content := range pair[1] {
+			if old, ok := pair[0][name]; !ok || old != content {
+				changed[name] = true
+			}
+		}
+		for name := range pair[0] {
+			if _, ok := pair[1][name]; !ok {
+				changed[name] = true
+			}
+		}
+	}
+	for name := range changed {
+		status.ChangedFiles = append(status.ChangedFiles, name)
+	}
+	sort.Strings(status.ChangedFiles)
+	return status
+}
+
+func copyFiles(files map[string]string) map[string]string {
+	out := make(map[string]string, len(files))
+	for k, v := range files {
+		out[k] = v
+	}
+	return out
+}
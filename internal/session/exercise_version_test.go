@@ -0,0 +1,231 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/profile"
+)
+
+// writeHello replaces the test pack's hello exercise
+func writeHello(t *testing.T, tmpDir, yaml string) {
+	t.Helper()
+	path := filepath.Join(tmpDir, "exercises", "test-pack", "basics", "hello.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatalf("failed to write exercise: %v", err)
+	}
+}
+
+const helloV1 = `id: basics/hello
+title: Hello World
+difficulty: beginner
+starter:
+  main.go: "package main\n\nfunc main() {}\n"
+  util.go: "package main\n"
+  old.go: "package main\n"
+tests:
+  main_test.go: "package main\n"
+`
+
+const helloV2 = `id: basics/hello
+title: Hello World
+difficulty: beginner
+starter:
+  main.go: "package main\n\nfunc main() { run() }\n"
+  util.go: "package main\n\nfunc run() {}\n"
+  helper.go: "package main\n"
+tests:
+  main_test.go: "package main\n\nimport \"testing\"\n"
+`
+
+func TestService_MigrateExercise(t *testing.T) {
+	service, _, tmpDir := setupTestService(t)
+	ctx := context.Background()
+	writeHello(t, tmpDir, helloV1)
+
+	sess, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if sess.ExerciseBaseline == nil || sess.ExerciseBaseline.Version == "" {
+		t.Fatal("training session should record the exercise version it started from")
+	}
+
+	code := map[string]string{}
+	for k, v := range sess.Code {
+		code[k] = v
+	}
+	code["main.go"] = "package main\n\nfunc main() { println(\"mine\") }\n"
+	if _, err := service.UpdateCode(ctx, sess.ID, code); err != nil {
+		t.Fatal(err)
+	}
+
+	writeHello(t, tmpDir, helloV2)
+
+	if _, err := service.RunCode(ctx, sess.ID, RunRequest{Test: true}); !errors.Is(err, ErrExerciseOutdated) {
+		t.Fatalf("RunCode() error = %v; want ErrExerciseOutdated", err)
+	}
+
+	status, err := service.ExerciseVersion(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("ExerciseVersion() error = %v", err)
+	}
+	wantChanged := []string{"helper.go", "main.go", "main_test.go", "old.go", "util.go"}
+	if !status.Outdated || !reflect.DeepEqual(status.ChangedFiles, wantChanged) {
+		t.Errorf("status = %+v; want outdated with %v changed", status, wantChanged)
+	}
+
+	m, err := service.MigrateExercise(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("MigrateExercise() error = %v", err)
+	}
+	for _, tt := range []struct {
+		name      string
+		got, want []string
+	}{
+		{"updated", m.Updated, []string{"main_test.go", "util.go"}},
+		{"added", m.Added, []string{"helper.go"}},
+		{"removed", m.Removed, []string{"old.go"}},
+		{"kept", m.Kept, []string{"main.go"}},
+	} {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s = %v; want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if m.Session.Code["main.go"] != code["main.go"] {
+		t.Error("migration should keep the learner's edits")
+	}
+	if m.Session.Code["main_test.go"] != "package main\n\nimport \"testing\"\n" {
+		t.Error("migration should take the new tests")
+	}
+
+	if _, err := service.RunCode(ctx, sess.ID, RunRequest{Test: true}); err != nil {
+		t.Errorf("RunCode() after migrating error = %v", err)
+	}
+}
+
+func TestService_PinExercise(t *testing.T) {
+	service, _, tmpDir := setupTestService(t)
+	ctx := context.Background()
+	writeHello(t, tmpDir, helloV1)
+
+	sess, _ := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	writeHello(t, tmpDir, helloV2)
+
+	status, err := service.PinExercise(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("PinExercise() error = %v", err)
+	}
+	if !status.Pinned || !status.Outdated {
+		t.Errorf("status = %+v; want pinned and outdated", status)
+	}
+	if _, err := service.RunCode(ctx, sess.ID, RunRequest{Test: true}); err != nil {
+		t.Errorf("RunCode() on a pinned session error = %v", err)
+	}
+
+	got, _ := service.Get(ctx, sess.ID)
+	if got.Code["main_test.go"] != "package main\n" {
+		t.Error("pinning should leave the session's files alone")
+	}
+}
+
+func TestService_ExerciseVersion_Unversioned(t *testing.T) {
+	service, store, _ := setupTestService(t)
+	ctx := context.Background()
+
+	sess, _ := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	sess.ExerciseBaseline = nil // started before versioning
+	if err := store.Save(sess); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := service.ExerciseVersion(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("ExerciseVersion() error = %v", err)
+	}
+	if status.Outdated || status.LatestVersion == "" {
+		t.Errorf("status = %+v; unversioned sessions can't be outdated", status)
+	}
+	if _, err := service.RunCode(ctx, sess.ID, RunRequest{Test: true}); err != nil {
+		t.Errorf("RunCode() error = %v", err)
+	}
+}
+
+const helloVariants = `id: basics/hello
+title: Hello World
+difficulty: intermediate
+starter:
+  main.go: "package main\n\nfunc main() {}\n"
+  helper.go: "package main\n"
+tests:
+  main_test.go: "package main\n"
+variants:
+  harder:
+    starter:
+      helper.go: ""
+    tests:
+      edge_test.go: "package main\n"
+  easier:
+    hints:
+      L1: ["Start from main"]
+`
+
+func TestService_Create_AdaptiveDifficulty(t *testing.T) {
+	service, _, tmpDir := setupTestService(t)
+	ctx := context.Background()
+	writeHello(t, tmpDir, helloVariants)
+
+	profileStore, err := profile.NewStore(filepath.Join(tmpDir, "profiles"))
+	if err != nil {
+		t.Fatalf("profile.NewStore() error = %v", err)
+	}
+	stored, _ := profileStore.GetDefault()
+	stored.TopicSkills["test/basics"] = profile.StoredSkill{Level: 0.9, Attempts: 5}
+	if err := profileStore.Save(stored); err != nil {
+		t.Fatal(err)
+	}
+	service.SetProfileService(profile.NewService(profileStore))
+
+	// Off unless configured
+	sess, _ := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if sess.ExerciseVariant() != "" {
+		t.Errorf("ExerciseVariant() = %q with adaptive difficulty off", sess.ExerciseVariant())
+	}
+
+	service.SetAdaptiveDifficulty(true)
+	sess, err = service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if sess.ExerciseVariant() != domain.VariantHarder {
+		t.Fatalf("ExerciseVariant() = %q, want harder for a strong learner", sess.ExerciseVariant())
+	}
+	if _, ok := sess.Code["helper.go"]; ok || sess.Code["edge_test.go"] == "" {
+		t.Errorf("Code = %v, want the harder variant's files", sess.Code)
+	}
+	if got := service.exerciseDifficulty(sess); got != string(domain.DifficultyAdvanced) {
+		t.Errorf("exerciseDifficulty() = %q, want the harder variant credited as advanced", got)
+	}
+
+	// The variant is the session's version, not an outdated exercise
+	status, err := service.ExerciseVersion(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("ExerciseVersion() error = %v", err)
+	}
+	if status.Outdated {
+		t.Errorf("status = %+v; a variant session shouldn't be outdated", status)
+	}
+
+	asWritten, _ := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello", AsWritten: true})
+	if asWritten.ExerciseVariant() != "" || asWritten.Code["helper.go"] == "" {
+		t.Error("AsWritten should start on the exercise as written")
+	}
+	if got := service.exerciseDifficulty(asWritten); got != string(domain.DifficultyIntermediate) {
+		t.Errorf("exerciseDifficulty() = %q, want the exercise's own", got)
+	}
+}
@@ -0,0 +1,43 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+// FuzzRun is the outcome of fuzzing the exercise's target
+type FuzzRun struct {
+	Target string            `json:"target"`
+	OK     bool              `json:"ok"`
+	Output string            `json:"output,omitempty"`
+	Crash  *domain.FuzzCrash `json:"crash,omitempty"` // failing input the fuzzer saved
+}
+
+// runFuzz fuzzes the exercise's target once the tests pass. Executors
+// that can't fuzz skip the stage.
+func (s *Service) runFuzz(ctx context.Context, session *Session, code map[string]string, result *RunResult) (*FuzzRun, error) {
+	recipe := s.checkRecipe(session)
+	if recipe == nil || recipe.Fuzz == nil || !result.TestOK {
+		return nil, nil
+	}
+	fz, ok := s.executor.(runner.FuzzRunner)
+	if !ok {
+		slog.Warn("executor cannot fuzz; skipping fuzz stage", "exercise", session.ExerciseID)
+		return nil, nil
+	}
+
+	fuzz, err := fz.RunFuzz(ctx, code, *recipe.Fuzz)
+	if err != nil {
+		return nil, fmt.Errorf("fuzz run: %w", err)
+	}
+	return &FuzzRun{
+		Target: recipe.Fuzz.Target,
+		OK:     fuzz.OK,
+		Output: fuzz.Output,
+		Crash:  fuzz.Crash,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+// fuzzExecutor reports a crash for every fuzz run
+type fuzzExecutor struct {
+	mockExecutor
+	checks []domain.FuzzCheck
+}
+
+func (f *fuzzExecutor) RunFuzz(ctx context.Context, code map[string]string, check domain.FuzzCheck) (*runner.FuzzResult, error) {
+	f.checks = append(f.checks, check)
+	return &runner.FuzzResult{
+		Output: "--- FAIL: " + check.Target,
+		Crash: &domain.FuzzCrash{
+			Target:     check.Target,
+			CorpusFile: "testdata/fuzz/" + check.Target + "/8a3c",
+			Input:      "go test fuzz v1\nstring(\"-\")\n",
+		},
+	}, nil
+}
+
+func TestService_RunCode_Fuzz(t *testing.T) {
+	service, _, tmpDir := setupTestService(t)
+	ctx := context.Background()
+
+	exerciseYAML := `id: basics/hello
+title: Hello World
+check_recipe:
+  test: true
+  fuzz:
+    target: FuzzParse
+    time: 5
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "exercises", "test-pack", "basics", "hello.yaml"), []byte(exerciseYAML), 0644); err != nil {
+		t.Fatalf("failed to write hello.yaml: %v", err)
+	}
+	session, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	exec := &fuzzExecutor{}
+	service.executor = exec
+
+	// Failing tests come first: nothing is fuzzed
+	exec.testResult = &runner.TestResult{OK: false}
+	run, err := service.RunCode(ctx, session.ID, RunRequest{Test: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if run.Result.Fuzz != nil || len(exec.checks) != 0 {
+		t.Errorf("fuzz = %+v; want no fuzzing while tests fail", run.Result.Fuzz)
+	}
+
+	exec.testResult = &runner.TestResult{OK: true}
+	run, err = service.RunCode(ctx, session.ID, RunRequest{Test: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if len(exec.checks) != 1 || exec.checks[0].Target != "FuzzParse" || exec.checks[0].Time != 5*time.Second {
+		t.Errorf("fuzz checks = %+v", exec.checks)
+	}
+	fuzz := run.Result.Fuzz
+	if fuzz == nil || fuzz.OK || fuzz.Crash == nil || fuzz.Crash.CorpusFile != "testdata/fuzz/FuzzParse/8a3c" {
+		t.Fatalf("fuzz = %+v; want the crash", fuzz)
+	}
+	if run.Result.TestOK {
+		t.Error("TestOK = true; want a fuzz crash to fail the tests")
+	}
+}
@@ -0,0 +1,161 @@
+package session
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Lifecycle events
+const (
+	LifecycleIdle    = "idle"    // nothing happened in the session for a while
+	LifecycleResumed = "resumed" // the learner is back
+	LifecycleExpired = "expired" // quiet for so long the session was abandoned
+)
+
+// LifecycleEvent is a change in whether a session is being worked on
+type LifecycleEvent struct {
+	SessionID string    `json:"session_id"`
+	Event     string    `json:"event"`
+	At        time.Time `json:"at"`
+}
+
+// ActivityPolicy decides when a quiet session is idle and when it is
+// given up on. A zero duration turns that step off.
+type ActivityPolicy struct {
+	IdleAfter   time.Duration
+	ExpireAfter time.Duration
+}
+
+// Enabled reports whether the policy does anything
+func (p ActivityPolicy) Enabled() bool {
+	return p.IdleAfter > 0 || p.ExpireAfter > 0
+}
+
+// lastActivity is when the session last saw a heartbeat, a run, a hint or
+// any other change
+func (s *Session) lastActivity() time.Time {
+	if s.LastSeenAt != nil && s.LastSeenAt.After(s.UpdatedAt) {
+		return *s.LastSeenAt
+	}
+	return s.UpdatedAt
+}
+
+// resume ends an idle spell that lasted until at, keeping the time the
+// cooldown was paused. It reports whether the session was idle.
+func (s *Session) resume(at time.Time) bool {
+	if s.IdleSince == nil {
+		return false
+	}
+	s.CooldownPaused = s.cooldownPaused(at)
+	s.IdleSince = nil
+	return true
+}
+
+// SetLifecycleHandler sets the function that receives lifecycle events,
+// e.g. to push them to the session's event stream
+func (s *Service) SetLifecycleHandler(fn func(LifecycleEvent)) {
+	s.onLifecycle = fn
+}
+
+func (s *Service) lifecycle(sessionID, event string, at time.Time) {
+	slog.Debug("session lifecycle", "session_id", sessionID, "event", event)
+	if s.onLifecycle != nil {
+		s.onLifecycle(LifecycleEvent{SessionID: sessionID, Event: event, At: at})
+	}
+}
+
+// Heartbeat records that an editor has the session open. An idle session
+// resumes, and its cooldown counts down again. Heartbeats don't count as
+// changes: UpdatedAt, which stuck detection and retention go by, stays.
+func (s *Service) Heartbeat(ctx context.Context, id string) (*Session, error) {
+	session, err := s.getLive(id)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.Status != StatusActive {
+		return nil, ErrSessionNotActive
+	}
+
+	now := time.Now()
+	session.LastSeenAt = &now
+	resumed := session.resume(now)
+	if err := s.store.Save(session); err != nil {
+		return nil, err
+	}
+	if resumed {
+		s.lifecycle(session.ID, LifecycleResumed, now)
+	}
+	return session, nil
+}
+
+// CheckActivity applies the policy to every active session: a session
+// quiet for IdleAfter goes idle, one quiet for ExpireAfter is abandoned,
+// and an idle one with activity since (a run from the CLI, say) resumes.
+func (s *Service) CheckActivity(ctx context.Context, policy ActivityPolicy, now time.Time) {
+	ids, err := s.store.List()
+	if err != nil {
+		slog.Warn("session activity: list sessions", "error", err)
+		return
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		session, err := s.store.Get(id)
+		if err != nil || session.Status != StatusActive {
+			continue
+		}
+
+		last := session.lastActivity()
+		quiet := now.Sub(last)
+		var event string
+		switch {
+		case policy.ExpireAfter > 0 && quiet >= policy.ExpireAfter:
+			// Abandoned without touching UpdatedAt, so retention still
+			// counts from the last real activity
+			session.Status = StatusAbandoned
+			session.ExpiredAt = &now
+			session.IdleSince = nil
+			event = LifecycleExpired
+		case policy.IdleAfter > 0 && quiet >= policy.IdleAfter:
+			if session.IdleSince != nil {
+				continue
+			}
+			session.IdleSince = &now
+			event = LifecycleIdle
+		case session.IdleSince != nil && last.After(*session.IdleSince):
+			session.resume(last)
+			event = LifecycleResumed
+		default:
+			continue
+		}
+
+		if err := s.store.Save(session); err != nil {
+			slog.Warn("session activity: save session", "session_id", id, "error", err)
+			continue
+		}
+		s.lifecycle(session.ID, event, now)
+	}
+}
+
+// StartActivityLoop applies the policy on every interval until ctx is
+// cancelled
+func (s *Service) StartActivityLoop(ctx context.Context, policy ActivityPolicy, interval time.Duration) {
+	if !policy.Enabled() {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.CheckActivity(ctx, policy, time.Now())
+			}
+		}
+	}()
+}
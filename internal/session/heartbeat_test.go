@@ -0,0 +1,112 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestService_CheckActivity(t *testing.T) {
+	service, store, _ := setupTestService(t)
+	ctx := context.Background()
+	policy := ActivityPolicy{IdleAfter: 10 * time.Minute, ExpireAfter: 24 * time.Hour}
+	now := time.Now()
+
+	var events []string
+	service.SetLifecycleHandler(func(ev LifecycleEvent) { events = append(events, ev.Event) })
+
+	sess := NewSession("test-pack/basics/hello", map[string]string{}, domain.DefaultPolicy())
+	sess.UpdatedAt = now
+	store.Save(sess)
+
+	service.CheckActivity(ctx, policy, now.Add(5*time.Minute))
+	if len(events) != 0 {
+		t.Fatalf("events = %v; want none while active", events)
+	}
+
+	service.CheckActivity(ctx, policy, now.Add(15*time.Minute))
+	service.CheckActivity(ctx, policy, now.Add(16*time.Minute))
+	got, _ := store.Get(sess.ID)
+	if got.IdleSince == nil || len(events) != 1 || events[0] != LifecycleIdle {
+		t.Fatalf("events = %v, idle since %v; want idle once", events, got.IdleSince)
+	}
+	if !got.UpdatedAt.Equal(now) {
+		t.Error("going idle should keep UpdatedAt")
+	}
+
+	// A run from the CLI sends no heartbeat, but resumes the session
+	got.UpdatedAt = now.Add(20 * time.Minute)
+	store.Save(got)
+	service.CheckActivity(ctx, policy, now.Add(21*time.Minute))
+	got, _ = store.Get(sess.ID)
+	if got.IdleSince != nil || events[len(events)-1] != LifecycleResumed {
+		t.Fatalf("events = %v; want resumed", events)
+	}
+
+	service.CheckActivity(ctx, policy, now.Add(48*time.Hour))
+	got, _ = store.Get(sess.ID)
+	if got.Status != StatusAbandoned || got.ExpiredAt == nil || events[len(events)-1] != LifecycleExpired {
+		t.Fatalf("status %q, events %v; want expired", got.Status, events)
+	}
+}
+
+func TestService_Heartbeat(t *testing.T) {
+	service, store, _ := setupTestService(t)
+	ctx := context.Background()
+
+	var events []string
+	service.SetLifecycleHandler(func(ev LifecycleEvent) { events = append(events, ev.Event) })
+
+	idle := time.Now().Add(-time.Minute)
+	sess := NewSession("test-pack/basics/hello", map[string]string{}, domain.DefaultPolicy())
+	sess.IdleSince = &idle
+	store.Save(sess)
+
+	got, err := service.Heartbeat(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if got.IdleSince != nil || got.LastSeenAt == nil || len(events) != 1 || events[0] != LifecycleResumed {
+		t.Errorf("session %+v, events %v; want resumed", got, events)
+	}
+
+	got.Abandon()
+	store.Save(got)
+	if _, err := service.Heartbeat(ctx, sess.ID); err != ErrSessionNotActive {
+		t.Errorf("Heartbeat() on abandoned session = %v; want ErrSessionNotActive", err)
+	}
+	if _, err := service.Heartbeat(ctx, "nope"); err != ErrSessionNotFound {
+		t.Errorf("Heartbeat() on unknown session = %v; want ErrSessionNotFound", err)
+	}
+}
+
+func TestSession_CooldownPausedWhileIdle(t *testing.T) {
+	sess := NewSession("test-pack/basics/hello", map[string]string{}, domain.DefaultPolicy())
+	sess.Policy.CooldownSeconds = 60
+	hint := time.Now().Add(-50 * time.Second)
+	sess.LastInterventionAt = &hint
+
+	// Idle 40s after the hint: 20s were left, and they stay left
+	idle := hint.Add(40 * time.Second)
+	sess.IdleSince = &idle
+	state := sess.Cooldown()
+	if !state.Active || !state.Paused || state.EndsAt != nil {
+		t.Fatalf("cooldown = %+v; want active and paused", state)
+	}
+	if r := state.RemainingSeconds; r < 19 || r > 21 {
+		t.Errorf("remaining = %.1fs; want 20s", r)
+	}
+
+	sess.resume(time.Now())
+	state = sess.Cooldown()
+	if state.Paused || state.EndsAt == nil || state.RemainingSeconds < 19 {
+		t.Errorf("cooldown = %+v; want 20s left after resuming", state)
+	}
+
+	sess.RecordIntervention()
+	if sess.CooldownPaused != 0 {
+		t.Error("a new hint starts a new cooldown")
+	}
+}
@@ -0,0 +1,384 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Run statuses in the run history
+const (
+	RunPending     = "pending"
+	RunBuildFailed = "build_failed"
+	RunTestsFailed = "tests_failed"
+	RunPassed      = "passed"
+
+	// A custom command run passes or fails as a whole
+	RunCommandFailed = "command_failed"
+)
+
+const (
+	diffContext  = 3         // unchanged lines around each hunk
+	maxDiffCells = 4_000_000 // lines(a) × lines(b) above which a file diff is a full replacement
+
+	fileAdded    = "added"
+	fileRemoved  = "removed"
+	fileModified = "modified"
+)
+
+// Status summarizes the run's outcome
+func (r *Run) Status() string {
+	switch {
+	case r.Result == nil:
+		return RunPending
+	case r.Result.Command != nil:
+		if !r.Result.Command.OK {
+			return RunCommandFailed
+		}
+		return RunPassed
+	case !r.Result.BuildOK:
+		return RunBuildFailed
+	case !r.Result.TestOK:
+		return RunTestsFailed
+	}
+	return RunPassed
+}
+
+// RunHistoryEntry is one run in a session's history, with the change to
+// the code since the run before it
+type RunHistoryEntry struct {
+	ID            string    `json:"id"`
+	Number        int       `json:"number"` // 1 for the session's first run
+	Status        string    `json:"status"`
+	DurationMs    int64     `json:"duration_ms"`
+	TestsPassed   int       `json:"tests_passed"`
+	TestsFailed   int       `json:"tests_failed"`
+	CreatedAt     time.Time `json:"created_at"`
+	PreviousRunID string    `json:"previous_run_id,omitempty"`
+	Diff          CodeDiff  `json:"diff"`
+}
+
+// CodeDiff is the change between two code snapshots
+type CodeDiff struct {
+	Files     []FileDiff `json:"files"`
+	Additions int        `json:"additions"`
+	Deletions int        `json:"deletions"`
+}
+
+// FileDiff is the change to one file, as a unified diff
+type FileDiff struct {
+	File      string `json:"file"`
+	Change    string `json:"change"` // added, removed or modified
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Patch     string `json:"patch"`
+
+	// Lines are the patch's hunks line by line, for editors that draw
+	// their own diff view. Only DiffFile fills them in.
+	Lines []DiffLine `json:"lines,omitempty"`
+}
+
+// Kinds of DiffLine
+const (
+	LineContext = "context"
+	LineAdd     = "add"
+	LineRemove  = "remove"
+)
+
+// DiffLine is one line of a hunk, numbered in the old and new file
+type DiffLine struct {
+	Kind    string `json:"kind"`               // context, add or remove
+	OldLine int    `json:"old_line,omitempty"` // 0 for added lines
+	NewLine int    `json:"new_line,omitempty"` // 0 for removed lines
+	Text    string `json:"text"`
+}
+
+// RunHistory orders runs oldest first and diffs each run's code against
+// the run before it. The first run is diffed against no code at all.
+func RunHistory(runs []*Run) []RunHistoryEntry {
+	ordered := append([]*Run(nil), runs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+	})
+
+	history := make([]RunHistoryEntry, 0, len(ordered))
+	var prev *Run
+	for i, run := range ordered {
+		entry := RunHistoryEntry{
+			ID:        run.ID,
+			Number:    i + 1,
+			Status:    run.Status(),
+			CreatedAt: run.CreatedAt,
+		}
+		if run.Result != nil {
+			entry.DurationMs = run.Result.Duration.Milliseconds()
+			for _, t := range run.Result.Tests {
+				if t.Passed {
+					entry.TestsPassed++
+				} else {
+					entry.TestsFailed++
+				}
+			}
+		}
+
+		var before map[string]string
+		if prev != nil {
+			entry.PreviousRunID = prev.ID
+			before = prev.Code
+		}
+		entry.Diff = DiffCode(before, run.Code)
+
+		history = append(history, entry)
+		prev = run
+	}
+	return history
+}
+
+// DiffCode compares two code snapshots file by file, in file name order.
+// Unchanged files are left out.
+func DiffCode(before, after map[string]string) CodeDiff {
+	names := make(map[string]bool, len(before)+len(after))
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	diff := CodeDiff{Files: []FileDiff{}}
+	for _, name := range sorted {
+		old, hadOld := before[name]
+		cur, hasCur := after[name]
+		if hadOld && hasCur && old == cur {
+			continue
+		}
+
+		fd := FileDiff{File: name, Change: fileModified}
+		switch {
+		case !hadOld:
+			fd.Change = fileAdded
+		case !hasCur:
+			fd.Change = fileRemoved
+		}
+		fd.Patch, _, fd.Additions, fd.Deletions = unifiedDiff(name, fd.Change, old, cur)
+
+		diff.Files = append(diff.Files, fd)
+		diff.Additions += fd.Additions
+		diff.Deletions += fd.Deletions
+	}
+	return diff
+}
+
+// DiffFile compares two versions of one file, with the hunks line by
+// line as well as a unified diff. An empty before is an added file.
+func DiffFile(name, before, after string) FileDiff {
+	fd := FileDiff{File: name, Change: fileModified}
+	if before == "" {
+		fd.Change = fileAdded
+	}
+	fd.Patch, fd.Lines, fd.Additions, fd.Deletions = unifiedDiff(name, fd.Change, before, after)
+	return fd
+}
+
+// lineOp is one line of an edit script: ' ' kept, '-' deleted, '+' added
+type lineOp struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff renders the change to one file with diffContext lines of
+// context around each hunk, as text and line by line
+func unifiedDiff(name, change, old, cur string) (string, []DiffLine, int, int) {
+	a, b := splitLines(old), splitLines(cur)
+	ops := diffLines(a, b)
+
+	var sb strings.Builder
+	from, to := "a/"+name, "b/"+name
+	switch change {
+	case fileAdded:
+		from = "/dev/null"
+	case fileRemoved:
+		to = "/dev/null"
+	}
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", from, to)
+
+	var lines []DiffLine
+	adds, dels := 0, 0
+	for _, op := range ops {
+		switch op.kind {
+		case '+':
+			adds++
+		case '-':
+			dels++
+		}
+	}
+
+	// Line numbers in the old and new file before each op
+	oldAt, newAt := make([]int, len(ops)), make([]int, len(ops))
+	o, n := 1, 1
+	for k, op := range ops {
+		oldAt[k], newAt[k] = o, n
+		if op.kind != '+' {
+			o++
+		}
+		if op.kind != '-' {
+			n++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		start := max(i-diffContext, 0)
+		end := hunkEnd(ops, i)
+
+		oldCount, newCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(oldAt[start], oldCount), hunkRange(newAt[start], newCount))
+		for k, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.text)
+			sb.WriteByte('\n')
+
+			line := DiffLine{Kind: LineContext, OldLine: oldAt[start+k], NewLine: newAt[start+k], Text: op.text}
+			switch op.kind {
+			case '+':
+				line.Kind, line.OldLine = LineAdd, 0
+			case '-':
+				line.Kind, line.NewLine = LineRemove, 0
+			}
+			lines = append(lines, line)
+		}
+		i = end
+	}
+	return sb.String(), lines, adds, dels
+}
+
+// hunkEnd returns the index just past the hunk starting at the change at
+// i: changes separated by no more than 2×diffContext kept lines are merged
+func hunkEnd(ops []lineOp, i int) int {
+	end := i
+	for end < len(ops) {
+		if ops[end].kind != ' ' {
+			end++
+			continue
+		}
+		run := end
+		for run < len(ops) && ops[run].kind == ' ' {
+			run++
+		}
+		if run == len(ops) || run-end > 2*diffContext {
+			return min(end+diffContext, len(ops))
+		}
+		end = run
+	}
+	return end
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns an edit script turning a into b from their longest
+// common subsequence. Very large files fall back to replacing every line.
+func diffLines(a, b []string) []lineOp {
+	// Trim the common prefix and suffix; edits are usually small
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]lineOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, lineOp{' ', line})
+	}
+	ops = append(ops, lcsScript(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, lineOp{' ', line})
+	}
+	return ops
+}
+
+func lcsScript(a, b []string) []lineOp {
+	n, m := len(a), len(b)
+	ops := make([]lineOp, 0, n+m)
+	if n*m > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, lineOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, lineOp{'+', line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, lineOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, lineOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, lineOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, lineOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, lineOp{'+', b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,123 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestDiffCode(t *testing.T) {
+	before := map[string]string{
+		"main.go":    "package main\n\nfunc main() {\n\tprintln(1)\n}\n",
+		"old.go":     "package main\n",
+		"same.go":    "package main\n",
+		"helpers.go": "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n",
+	}
+	after := map[string]string{
+		"main.go":    "package main\n\nfunc main() {\n\tprintln(2)\n}\n",
+		"new.go":     "package main\n\nvar x = 1\n",
+		"same.go":    "package main\n",
+		"helpers.go": "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nM\nn\n",
+	}
+
+	diff := DiffCode(before, after)
+
+	var files []string
+	for _, f := range diff.Files {
+		files = append(files, f.File+":"+f.Change)
+	}
+	if got := strings.Join(files, ","); got != "helpers.go:modified,main.go:modified,new.go:added,old.go:removed" {
+		t.Fatalf("files = %s", got)
+	}
+	if diff.Additions != 6 || diff.Deletions != 4 {
+		t.Errorf("additions/deletions = %d/%d, want 6/4", diff.Additions, diff.Deletions)
+	}
+
+	main := diff.Files[1].Patch
+	want := "--- a/main.go\n+++ b/main.go\n@@ -1,5 +1,5 @@\n package main\n \n func main() {\n-\tprintln(1)\n+\tprintln(2)\n }\n"
+	if main != want {
+		t.Errorf("main.go patch:\n%s\nwant:\n%s", main, want)
+	}
+
+	// Changes far apart get separate hunks
+	if hunks := strings.Count(diff.Files[0].Patch, "@@ -"); hunks != 2 {
+		t.Errorf("helpers.go should have 2 hunks, got %d:\n%s", hunks, diff.Files[0].Patch)
+	}
+	if !strings.Contains(diff.Files[0].Patch, "@@ -10,5 +10,5 @@") {
+		t.Errorf("second hunk header wrong:\n%s", diff.Files[0].Patch)
+	}
+
+	if added := diff.Files[2].Patch; !strings.HasPrefix(added, "--- /dev/null\n+++ b/new.go\n@@ -0,0 +1,3 @@\n") {
+		t.Errorf("added file patch:\n%s", added)
+	}
+	if removed := diff.Files[3].Patch; !strings.HasPrefix(removed, "--- a/old.go\n+++ /dev/null\n@@ -1 +0,0 @@\n-package main") {
+		t.Errorf("removed file patch:\n%s", removed)
+	}
+}
+
+func TestDiffFile(t *testing.T) {
+	fd := DiffFile("main.go", "package main\n\nfunc main() {\n\tprintln(1)\n}\n", "package main\n\nfunc main() {\n\tprintln(2)\n\tprintln(3)\n}\n")
+
+	if fd.Change != fileModified || fd.Additions != 2 || fd.Deletions != 1 {
+		t.Fatalf("diff = %+v, want modified with 2 additions and 1 deletion", fd)
+	}
+	want := []DiffLine{
+		{LineContext, 1, 1, "package main"},
+		{LineContext, 2, 2, ""},
+		{LineContext, 3, 3, "func main() {"},
+		{LineRemove, 4, 0, "\tprintln(1)"},
+		{LineAdd, 0, 4, "\tprintln(2)"},
+		{LineAdd, 0, 5, "\tprintln(3)"},
+		{LineContext, 5, 6, "}"},
+	}
+	if len(fd.Lines) != len(want) {
+		t.Fatalf("lines = %+v, want %d", fd.Lines, len(want))
+	}
+	for i := range want {
+		if fd.Lines[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, fd.Lines[i], want[i])
+		}
+	}
+
+	if added := DiffFile("new.go", "", "package main\n"); added.Change != fileAdded || len(added.Lines) != 1 || added.Lines[0].NewLine != 1 {
+		t.Errorf("added file = %+v", added)
+	}
+}
+
+func TestRunHistory(t *testing.T) {
+	now := time.Now()
+	runs := []*Run{
+		{ID: "r2", CreatedAt: now.Add(time.Minute), Code: map[string]string{"main.go": "v2\n"},
+			Result: &RunResult{BuildOK: true, TestOK: true, Duration: 1500 * time.Millisecond,
+				Tests: []domain.TestResult{{Name: "TestA", Passed: true}, {Name: "TestB", Passed: true}}}},
+		{ID: "r1", CreatedAt: now, Code: map[string]string{"main.go": "v1\n"},
+			Result: &RunResult{BuildOK: true, TestOK: false,
+				Tests: []domain.TestResult{{Name: "TestA", Passed: true}, {Name: "TestB", Passed: false}}}},
+		{ID: "r3", CreatedAt: now.Add(2 * time.Minute), Code: map[string]string{"main.go": "v2\n"}},
+	}
+
+	history := RunHistory(runs)
+	if len(history) != 3 || history[0].ID != "r1" || history[2].ID != "r3" {
+		t.Fatalf("history out of order: %+v", history)
+	}
+
+	first, second, third := history[0], history[1], history[2]
+	if first.Status != RunTestsFailed || first.TestsFailed != 1 || first.PreviousRunID != "" || first.Diff.Additions != 1 {
+		t.Errorf("first run = %+v", first)
+	}
+	if second.Status != RunPassed || second.DurationMs != 1500 || second.PreviousRunID != "r1" || second.Number != 2 {
+		t.Errorf("second run = %+v", second)
+	}
+	if second.Diff.Additions != 1 || second.Diff.Deletions != 1 {
+		t.Errorf("second run diff = %+v", second.Diff)
+	}
+	if third.Status != RunPending || len(third.Diff.Files) != 0 {
+		t.Errorf("unchanged pending run = %+v", third)
+	}
+
+	if (&Run{Result: &RunResult{}}).Status() != RunBuildFailed {
+		t.Error("a run that didn't build should be build_failed")
+	}
+}
@@ -2,6 +2,10 @@ package session
 
 import (
 	"context"
+	"io"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
 )
 
 // SessionService defines the interface for session management operations
@@ -13,6 +17,9 @@ type SessionService interface {
 	// Get retrieves a session by ID
 	Get(ctx context.Context, id string) (*Session, error)
 
+	// List returns all active sessions
+	List(ctx context.Context) ([]*Session, error)
+
 	// Delete removes a session
 	Delete(ctx context.Context, id string) error
 
@@ -22,8 +29,68 @@ type SessionService interface {
 	// UpdateCode updates the code in a session
 	UpdateCode(ctx context.Context, id string, code map[string]string) (*Session, error)
 
+	// PushWorkspace applies an incremental update to the session's files
+	PushWorkspace(ctx context.Context, id string, push WorkspacePush) (*WorkspaceManifest, error)
+
+	// GetRuns returns all runs for a session
+	GetRuns(ctx context.Context, sessionID string) ([]*Run, error)
+
+	// AddAuthoringSpec adds a spec file to an authoring session
+	AddAuthoringSpec(ctx context.Context, id, specPath string) (*Session, error)
+
+	// RunsForSpec returns the runs of every session working on a spec
+	RunsForSpec(ctx context.Context, specPath string) ([]*Run, error)
+
+	// Search finds sessions, runs and interventions matching a query
+	Search(ctx context.Context, q SearchQuery) ([]SearchHit, error)
+
+	// Prune deletes sessions and runs older than the retention policy allows
+	Prune(ctx context.Context, policy RetentionPolicy, now time.Time, dryRun bool) (*PruneReport, error)
+
+	// Erase permanently deletes the sessions in scope and takes them out of the profile
+	Erase(ctx context.Context, scope EraseScope, dryRun bool) (*EraseReport, error)
+
 	// RecordIntervention records an intervention in a session
 	RecordIntervention(ctx context.Context, intervention *Intervention) error
+
+	// GetInterventions returns all interventions recorded in a session
+	GetInterventions(ctx context.Context, sessionID string) ([]*Intervention, error)
+
+	// PackActivity collects the learner's runs and interventions on a pack's exercises
+	PackActivity(ctx context.Context, pack string) (*PackActivity, error)
+
+	// ExplainTest explains why a test failed in a run, once per run and test
+	ExplainTest(ctx context.Context, sessionID, runID, test string) (*domain.TestExplanation, error)
+
+	// RunOutput opens the full build, test or command output of a run
+	RunOutput(ctx context.Context, sessionID, runID, stream string) (*Run, io.ReadSeekCloser, error)
+
+	// OpenArtifact opens a file a run collected
+	OpenArtifact(ctx context.Context, sessionID, runID, path string) (*domain.RunArtifact, io.ReadSeekCloser, error)
+
+	// Lessons collects explained errors and explanations across all sessions
+	Lessons(ctx context.Context) ([]Lesson, error)
+
+	// SubmitRootCause evaluates a root-cause answer for a debugging exercise
+	SubmitRootCause(ctx context.Context, id string, answers []domain.RootCauseAnswer) (*RootCauseResult, error)
+
+	// ExerciseVersion compares the session's exercise version with the one on disk
+	ExerciseVersion(ctx context.Context, id string) (*ExerciseVersionStatus, error)
+
+	// MigrateExercise re-bases the session onto the exercise on disk
+	MigrateExercise(ctx context.Context, id string) (*ExerciseMigration, error)
+
+	// PinExercise keeps the session on the exercise version it started from
+	PinExercise(ctx context.Context, id string) (*ExerciseVersionStatus, error)
+
+	// Assign creates a session for each learner of a cohort, reporting per learner
+	Assign(ctx context.Context, req AssignRequest) ([]AssignResult, error)
+
+	// Assignments returns the sessions assigned to a cohort's members
+	Assignments(ctx context.Context, cohort string) ([]*Session, error)
+
+	// Heartbeat records that an editor has the session open, resuming it if idle
+	Heartbeat(ctx context.Context, id string) (*Session, error)
 }
 
 // Ensure Service implements SessionService
@@ -35,6 +102,9 @@ type SessionStore interface {
 	Save(session *Session) error
 	Get(id string) (*Session, error)
 	Delete(id string) error
+	// Tombstone soft-deletes a session. Tombstoning one twice is a no-op;
+	// a missing session is ErrNotFound.
+	Tombstone(id string, at time.Time) error
 	List() ([]string, error)
 	ListActive() ([]*Session, error)
 	Exists(id string) bool
@@ -42,6 +112,7 @@ type SessionStore interface {
 	SaveRun(run *Run) error
 	GetRun(sessionID, runID string) (*Run, error)
 	ListRuns(sessionID string) ([]string, error)
+	DeleteRun(sessionID, runID string) error
 
 	SaveIntervention(intervention *Intervention) error
 	GetIntervention(sessionID, interventionID string) (*Intervention, error)
@@ -0,0 +1,64 @@
+package session
+
+import (
+	"context"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// Lesson is something a session explained to the learner: an error
+// explained after a run, or an explanation the learner asked for
+type Lesson struct {
+	SessionID  string                   `json:"session_id"`
+	ExerciseID string                   `json:"exercise_id,omitempty"`
+	RunID      string                   `json:"run_id,omitempty"`
+	Error      *domain.ErrorExplanation `json:"error,omitempty"`
+	Explained  string                   `json:"explained,omitempty"` // content of an explain intervention
+}
+
+// Lessons collects the explained errors and explain interventions of
+// every stored session, for turning into flashcards
+func (s *Service) Lessons(ctx context.Context) ([]Lesson, error) {
+	ids, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	lessons := []Lesson{}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sess, err := s.store.Get(id)
+		if err != nil {
+			continue
+		}
+
+		runIDs, _ := s.store.ListRuns(sess.ID)
+		for _, runID := range runIDs {
+			run, err := s.store.GetRun(sess.ID, runID)
+			if err != nil || run.Result == nil {
+				continue
+			}
+			for i := range run.Result.Explanations {
+				lessons = append(lessons, Lesson{
+					SessionID: sess.ID, ExerciseID: sess.ExerciseID, RunID: run.ID,
+					Error: &run.Result.Explanations[i],
+				})
+			}
+		}
+
+		interventionIDs, _ := s.store.ListInterventions(sess.ID)
+		for _, ivID := range interventionIDs {
+			iv, err := s.store.GetIntervention(sess.ID, ivID)
+			if err != nil || iv.Intent != domain.IntentExplain {
+				continue
+			}
+			lessons = append(lessons, Lesson{
+				SessionID: sess.ID, ExerciseID: sess.ExerciseID,
+				Explained: iv.Content,
+			})
+		}
+	}
+	return lessons, nil
+}
@@ -0,0 +1,250 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// InlineOutputBytes is the most build or test output a run record keeps.
+// Longer output is saved whole to the output store, and the record keeps
+// its start and end.
+const InlineOutputBytes = 64 << 10
+
+// Run output streams
+const (
+	StreamBuild   = "build"
+	StreamTest    = "test"
+	StreamCommand = "command" // a custom command's
+)
+
+var ErrInvalidStream = errors.New("invalid output stream")
+
+// SpilledOutput is output too long to keep in the run record
+type SpilledOutput struct {
+	Stream string `json:"stream"`
+	Size   int    `json:"size"`   // bytes of the full output
+	Stored bool   `json:"stored"` // false when it couldn't be saved: only the excerpt is left
+}
+
+// OutputStore keeps the full output of runs whose output is too long to
+// keep inline, and the artifacts runs collect
+type OutputStore interface {
+	Put(sessionID, runID, stream string, data []byte) error
+	Open(sessionID, runID, stream string) (io.ReadSeekCloser, error)
+	PutArtifact(sessionID, runID, path string, data []byte) error
+	OpenArtifact(sessionID, runID, path string) (io.ReadSeekCloser, error)
+	DeleteRun(sessionID, runID string) error
+	DeleteSession(sessionID string) error
+}
+
+// DirOutputStore keeps run output as files under a directory, at
+// <session>/<run>/<stream>.log, and artifacts at
+// <session>/<run>/artifacts/<path>
+type DirOutputStore struct {
+	dir string
+}
+
+// NewDirOutputStore creates an output store in dir
+func NewDirOutputStore(dir string) *DirOutputStore {
+	return &DirOutputStore{dir: dir}
+}
+
+func (d *DirOutputStore) path(parts ...string) string {
+	for i, p := range parts {
+		parts[i] = filepath.Base(p)
+	}
+	return filepath.Join(append([]string{d.dir}, parts...)...)
+}
+
+// Put writes the output of a run's stream
+func (d *DirOutputStore) Put(sessionID, runID, stream string, data []byte) error {
+	dir := d.path(sessionID, runID)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, stream+".log"), data, 0600)
+}
+
+// Open opens the output of a run's stream; ErrNotFound if there is none
+func (d *DirOutputStore) Open(sessionID, runID, stream string) (io.ReadSeekCloser, error) {
+	f, err := os.Open(filepath.Join(d.path(sessionID, runID), stream+".log"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// PutArtifact writes an artifact at its workspace-relative path
+func (d *DirOutputStore) PutArtifact(sessionID, runID, path string, data []byte) error {
+	file, err := d.artifactPath(sessionID, runID, path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("create artifact dir: %w", err)
+	}
+	return os.WriteFile(file, data, 0600)
+}
+
+// OpenArtifact opens an artifact; ErrNotFound if there is none
+func (d *DirOutputStore) OpenArtifact(sessionID, runID, path string) (io.ReadSeekCloser, error) {
+	file, err := d.artifactPath(sessionID, runID, path)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (d *DirOutputStore) artifactPath(sessionID, runID, path string) (string, error) {
+	clean, ok := domain.CleanArtifactPath(path)
+	if !ok {
+		return "", fmt.Errorf("invalid artifact path %q", path)
+	}
+	return filepath.Join(d.path(sessionID, runID), "artifacts", filepath.FromSlash(clean)), nil
+}
+
+// DeleteRun deletes the output and artifacts of a run
+func (d *DirOutputStore) DeleteRun(sessionID, runID string) error {
+	return os.RemoveAll(d.path(sessionID, runID))
+}
+
+// DeleteSession deletes the output of all of a session's runs
+func (d *DirOutputStore) DeleteSession(sessionID string) error {
+	return os.RemoveAll(d.path(sessionID))
+}
+
+// SetOutputStore sets where output too long for the run record is kept.
+// Without one, long output is cut down to its start and end.
+func (s *Service) SetOutputStore(o OutputStore) {
+	s.outputs = o
+}
+
+// spillOutputs moves build, test and command output longer than
+// InlineOutputBytes out of the run record, before it is saved
+func (s *Service) spillOutputs(run *Run) {
+	outputs := map[string]*string{
+		StreamBuild: &run.Result.BuildOutput,
+		StreamTest:  &run.Result.TestOutput,
+	}
+	if run.Result.Command != nil {
+		outputs[StreamCommand] = &run.Result.Command.Output
+	}
+	for _, stream := range []string{StreamBuild, StreamTest, StreamCommand} {
+		output, ok := outputs[stream]
+		if !ok || len(*output) <= InlineOutputBytes {
+			continue
+		}
+		spilled := SpilledOutput{Stream: stream, Size: len(*output)}
+		if s.outputs != nil {
+			if err := s.outputs.Put(run.SessionID, run.ID, stream, []byte(*output)); err != nil {
+				slog.Warn("save run output", "session_id", run.SessionID, "run_id", run.ID, "stream", stream, "error", err)
+			} else {
+				spilled.Stored = true
+			}
+		}
+		*output = outputExcerpt(run, *output, spilled)
+		run.Result.SpilledOutput = append(run.Result.SpilledOutput, spilled)
+	}
+}
+
+// outputExcerpt keeps the first three quarters of InlineOutputBytes and
+// the last quarter of out, cut at line breaks
+func outputExcerpt(run *Run, out string, spilled SpilledOutput) string {
+	head := out[:InlineOutputBytes*3/4]
+	if i := strings.LastIndexByte(head, '\n'); i > 0 {
+		head = head[:i+1]
+	}
+	tail := out[len(out)-InlineOutputBytes/4:]
+	if i := strings.IndexByte(tail, '\n'); i >= 0 && i < len(tail)-1 {
+		tail = tail[i+1:]
+	}
+
+	omitted := len(out) - len(head) - len(tail)
+	where := "the rest was not saved"
+	if spilled.Stored {
+		where = fmt.Sprintf("full output at /v1/sessions/%s/runs/%s/output?stream=%s", run.SessionID, run.ID, spilled.Stream)
+	}
+	return fmt.Sprintf("%s... [%d bytes omitted; %s] ...\n%s", head, omitted, where, tail)
+}
+
+// RunOutput opens the full build, test or command output of a run: the stored
+// file when the output was too long for the record, else the record's own
+func (s *Service) RunOutput(ctx context.Context, sessionID, runID, stream string) (*Run, io.ReadSeekCloser, error) {
+	switch stream {
+	case StreamBuild, StreamTest, StreamCommand:
+	default:
+		return nil, nil, ErrInvalidStream
+	}
+	if _, err := s.getLive(sessionID); err != nil {
+		return nil, nil, err
+	}
+	run, err := s.store.GetRun(sessionID, runID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil, ErrRunNotFound
+		}
+		return nil, nil, err
+	}
+	if run.Result == nil {
+		return run, nopSeekCloser{strings.NewReader("")}, nil
+	}
+
+	for _, spilled := range run.Result.SpilledOutput {
+		if spilled.Stream != stream || !spilled.Stored || s.outputs == nil {
+			continue
+		}
+		f, err := s.outputs.Open(sessionID, runID, stream)
+		if err == nil {
+			return run, f, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, nil, fmt.Errorf("open run output: %w", err)
+		}
+	}
+
+	var output string
+	switch {
+	case stream == StreamBuild:
+		output = run.Result.BuildOutput
+	case stream == StreamTest:
+		output = run.Result.TestOutput
+	case run.Result.Command != nil:
+		output = run.Result.Command.Output
+	}
+	return run, nopSeekCloser{strings.NewReader(output)}, nil
+}
+
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+// deleteOutputs deletes the stored output of a session's runs, or of one
+// run when runID is set. Failures are logged: the files are only orphaned.
+func (s *Service) deleteOutputs(sessionID, runID string) {
+	if s.outputs == nil {
+		return
+	}
+	var err error
+	if runID == "" {
+		err = s.outputs.DeleteSession(sessionID)
+	} else {
+		err = s.outputs.DeleteRun(sessionID, runID)
+	}
+	if err != nil {
+		slog.Warn("delete run output", "session_id", sessionID, "run_id", runID, "error", err)
+	}
+}
@@ -0,0 +1,113 @@
+package session
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+func TestService_RunCode_SpillsLongOutput(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	service.SetOutputStore(NewDirOutputStore(t.TempDir()))
+	ctx := context.Background()
+
+	long := strings.Repeat("=== RUN   TestLoop\nlooping\n", InlineOutputBytes/8) + "FAIL\n"
+	service.executor.(*mockExecutor).buildResult = &runner.BuildResult{OK: false, Output: long}
+
+	sess, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	run, err := service.RunCode(ctx, sess.ID, RunRequest{Build: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+
+	spilled := run.Result.SpilledOutput
+	if len(spilled) != 1 || spilled[0].Stream != StreamBuild || spilled[0].Size != len(long) || !spilled[0].Stored {
+		t.Fatalf("spilled output = %+v", spilled)
+	}
+	inline := run.Result.BuildOutput
+	if len(inline) > InlineOutputBytes+200 {
+		t.Errorf("inline output is %d bytes, want about %d", len(inline), InlineOutputBytes)
+	}
+	if !strings.HasPrefix(inline, "=== RUN") || !strings.HasSuffix(inline, "looping\nFAIL\n") || !strings.Contains(inline, "/output?stream=build") {
+		t.Errorf("inline output doesn't keep the start and end: %q...%q", inline[:40], inline[len(inline)-120:])
+	}
+
+	_, output, err := service.RunOutput(ctx, sess.ID, run.ID, StreamBuild)
+	if err != nil {
+		t.Fatalf("RunOutput() error = %v", err)
+	}
+	defer output.Close()
+	full, _ := io.ReadAll(output)
+	if string(full) != long {
+		t.Errorf("RunOutput() returned %d bytes, want the full %d", len(full), len(long))
+	}
+
+	// Output that fit in the record is served from it
+	_, output, err = service.RunOutput(ctx, sess.ID, run.ID, StreamTest)
+	if err != nil {
+		t.Fatalf("RunOutput(test) error = %v", err)
+	}
+	if data, _ := io.ReadAll(output); len(data) != 0 {
+		t.Errorf("test output = %q, want empty", data)
+	}
+
+	if _, _, err := service.RunOutput(ctx, sess.ID, run.ID, "stderr"); err != ErrInvalidStream {
+		t.Errorf("RunOutput(stderr) error = %v, want ErrInvalidStream", err)
+	}
+	if _, _, err := service.RunOutput(ctx, sess.ID, "missing", StreamBuild); err != ErrRunNotFound {
+		t.Errorf("RunOutput(missing run) error = %v, want ErrRunNotFound", err)
+	}
+}
+
+func TestService_RunCode_LongOutputWithoutStore(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	ctx := context.Background()
+	long := strings.Repeat("x\n", InlineOutputBytes)
+	service.executor.(*mockExecutor).buildResult = &runner.BuildResult{OK: false, Output: long}
+
+	sess, _ := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	run, err := service.RunCode(ctx, sess.ID, RunRequest{Build: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if spilled := run.Result.SpilledOutput; len(spilled) != 1 || spilled[0].Stored {
+		t.Errorf("spilled output = %+v, want one not stored", spilled)
+	}
+	if !strings.Contains(run.Result.BuildOutput, "the rest was not saved") {
+		t.Error("excerpt doesn't say the rest was lost")
+	}
+}
+
+func TestDirOutputStore_Delete(t *testing.T) {
+	store := NewDirOutputStore(t.TempDir())
+	for _, run := range []string{"run-1", "run-2"} {
+		if err := store.Put("sess", run, StreamTest, []byte("out")); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	if err := store.DeleteRun("sess", "run-1"); err != nil {
+		t.Fatalf("DeleteRun() error = %v", err)
+	}
+	if _, err := store.Open("sess", "run-1", StreamTest); err != ErrNotFound {
+		t.Errorf("Open(deleted run) error = %v, want ErrNotFound", err)
+	}
+	f, err := store.Open("sess", "run-2", StreamTest)
+	if err != nil {
+		t.Fatalf("Open(run-2) error = %v", err)
+	}
+	f.Close()
+
+	if err := store.DeleteSession("sess"); err != nil {
+		t.Fatalf("DeleteSession() error = %v", err)
+	}
+	if _, err := store.Open("sess", "run-2", StreamTest); err != ErrNotFound {
+		t.Errorf("Open(deleted session) error = %v, want ErrNotFound", err)
+	}
+}
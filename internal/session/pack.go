@@ -0,0 +1,139 @@
+package session
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/analysis"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/profile"
+)
+
+// ExerciseActivity is what the learner did on one exercise, across all of
+// its sessions
+type ExerciseActivity struct {
+	ExerciseID  string         `json:"exercise_id"`
+	Sessions    int            `json:"sessions"`
+	Completed   bool           `json:"completed"`
+	Runs        int            `json:"runs"`
+	PassedRuns  int            `json:"passed_runs"`
+	Hints       int            `json:"hints"`                  // interventions other than reviews
+	Errors      map[string]int `json:"errors,omitempty"`       // error signature → runs it appeared in
+	FailedTests map[string]int `json:"failed_tests,omitempty"` // test name → runs it failed in
+	Reviews     []string       `json:"reviews,omitempty"`      // review content, oldest first
+
+	// Approach is how the latest completed session's code compares with
+	// the exercise's reference solution and alternatives
+	Approach *analysis.ApproachReport `json:"approach,omitempty"`
+}
+
+// PackActivity is the learner's activity on one exercise pack
+type PackActivity struct {
+	Pack      string             `json:"pack"`
+	Exercises []ExerciseActivity `json:"exercises"` // by exercise ID
+}
+
+// Completed counts the exercises finished in at least one session
+func (a *PackActivity) Completed() int {
+	n := 0
+	for _, ex := range a.Exercises {
+		if ex.Completed {
+			n++
+		}
+	}
+	return n
+}
+
+// PackActivity collects the runs and interventions of every session on a
+// pack's exercises. Deleted sessions count, as they do for analytics.
+func (s *Service) PackActivity(ctx context.Context, pack string) (*PackActivity, error) {
+	ids, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	byExercise := make(map[string]*ExerciseActivity)
+	reviewed := make(map[string][]*Intervention)
+	approachAt := make(map[string]time.Time) // when each exercise's Approach was completed
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sess, err := s.store.Get(id)
+		if err != nil {
+			continue
+		}
+		if id, _, _ := domain.SplitExerciseID(sess.ExerciseID); id != pack {
+			continue
+		}
+
+		ex := byExercise[sess.ExerciseID]
+		if ex == nil {
+			ex = &ExerciseActivity{ExerciseID: sess.ExerciseID}
+			byExercise[sess.ExerciseID] = ex
+		}
+		ex.Sessions++
+		if sess.Status == StatusCompleted {
+			ex.Completed = true
+			if sess.Approach != nil && sess.UpdatedAt.After(approachAt[sess.ExerciseID]) {
+				ex.Approach, approachAt[sess.ExerciseID] = sess.Approach, sess.UpdatedAt
+			}
+		}
+
+		runIDs, _ := s.store.ListRuns(sess.ID)
+		for _, runID := range runIDs {
+			run, err := s.store.GetRun(sess.ID, runID)
+			if err != nil || run.Result == nil {
+				continue
+			}
+			ex.Runs++
+			if run.Status() == RunPassed {
+				ex.PassedRuns++
+			}
+			for _, sig := range profile.ExtractErrorPatterns(run.Result.BuildOutput, run.Result.TestOutput) {
+				ex.Errors = increment(ex.Errors, sig)
+			}
+			for _, name := range run.Result.FailedTests() {
+				ex.FailedTests = increment(ex.FailedTests, name)
+			}
+		}
+
+		interventionIDs, _ := s.store.ListInterventions(sess.ID)
+		for _, ivID := range interventionIDs {
+			iv, err := s.store.GetIntervention(sess.ID, ivID)
+			if err != nil {
+				continue
+			}
+			if iv.Intent == domain.IntentReview {
+				reviewed[sess.ExerciseID] = append(reviewed[sess.ExerciseID], iv)
+			} else {
+				ex.Hints++
+			}
+		}
+	}
+
+	activity := &PackActivity{Pack: pack, Exercises: make([]ExerciseActivity, 0, len(byExercise))}
+	for id, ex := range byExercise {
+		reviews := reviewed[id]
+		sort.SliceStable(reviews, func(i, j int) bool {
+			return reviews[i].CreatedAt.Before(reviews[j].CreatedAt)
+		})
+		for _, iv := range reviews {
+			ex.Reviews = append(ex.Reviews, iv.Content)
+		}
+		activity.Exercises = append(activity.Exercises, *ex)
+	}
+	sort.Slice(activity.Exercises, func(i, j int) bool {
+		return activity.Exercises[i].ExerciseID < activity.Exercises[j].ExerciseID
+	})
+	return activity, nil
+}
+
+func increment(counts map[string]int, key string) map[string]int {
+	if counts == nil {
+		counts = make(map[string]int)
+	}
+	counts[key]++
+	return counts
+}
@@ -0,0 +1,55 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestService_PackActivity(t *testing.T) {
+	service, store, _ := setupTestService(t)
+	ctx := context.Background()
+
+	hello, _ := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	maps := NewSession("test-pack/basics/maps", nil, domain.DefaultPolicy())
+	other := NewSession("other-pack/basics/hello", nil, domain.DefaultPolicy())
+	store.Save(maps)
+	store.Save(other)
+
+	store.SaveRun(&Run{ID: "run-1", SessionID: maps.ID, CreatedAt: time.Now(), Result: &RunResult{
+		BuildOK: true, TestOutput: "panic: assignment to entry in nil map",
+		Tests: []domain.TestResult{{Name: "TestCount", Passed: false}},
+	}})
+	store.SaveRun(&Run{ID: "run-2", SessionID: maps.ID, CreatedAt: time.Now(), Result: &RunResult{BuildOK: true, TestOK: true}})
+	store.SaveRun(&Run{ID: "run-3", SessionID: other.ID, CreatedAt: time.Now(), Result: &RunResult{BuildOK: true, TestOK: true}})
+	store.SaveIntervention(&Intervention{ID: "iv-2", SessionID: maps.ID, Intent: domain.IntentReview, Content: "second", CreatedAt: time.Now()})
+	store.SaveIntervention(&Intervention{ID: "iv-1", SessionID: maps.ID, Intent: domain.IntentReview, Content: "first", CreatedAt: time.Now().Add(-time.Minute)})
+	store.SaveIntervention(&Intervention{ID: "iv-3", SessionID: maps.ID, Intent: domain.IntentHint, Content: "hint", CreatedAt: time.Now()})
+	if err := service.Complete(ctx, hello.ID); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	activity, err := service.PackActivity(ctx, "test-pack")
+	if err != nil {
+		t.Fatalf("PackActivity() error = %v", err)
+	}
+	if len(activity.Exercises) != 2 || activity.Completed() != 1 {
+		t.Fatalf("activity = %+v, want 2 exercises with 1 completed", activity)
+	}
+
+	ex := activity.Exercises[1]
+	if ex.ExerciseID != "test-pack/basics/maps" {
+		t.Fatalf("exercises not sorted by ID: %+v", activity.Exercises)
+	}
+	if ex.Runs != 2 || ex.PassedRuns != 1 || ex.Hints != 1 {
+		t.Errorf("runs = %d, passed = %d, hints = %d; want 2, 1, 1", ex.Runs, ex.PassedRuns, ex.Hints)
+	}
+	if len(ex.Errors) == 0 || ex.FailedTests["TestCount"] != 1 {
+		t.Errorf("errors = %v, failed tests = %v", ex.Errors, ex.FailedTests)
+	}
+	if len(ex.Reviews) != 2 || ex.Reviews[0] != "first" {
+		t.Errorf("reviews = %q, want oldest first", ex.Reviews)
+	}
+}
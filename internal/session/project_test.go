@@ -0,0 +1,70 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/runner"
+	"github.com/felixgeelhaar/temper/internal/spec"
+)
+
+// fakeProjects keeps every project's specs in docs/specs
+type fakeProjects struct {
+	overrides runner.Overrides
+	commands  map[string]string
+}
+
+func (f fakeProjects) SpecService(root string) *spec.Service {
+	return spec.NewServiceIn(root, "docs/specs")
+}
+
+func (f fakeProjects) RunOverrides(root string) runner.Overrides {
+	return f.overrides
+}
+
+func (f fakeProjects) Commands(root string) map[string]string {
+	return f.commands
+}
+
+func TestService_ProjectResolver(t *testing.T) {
+	service, _, tmpDir := setupTestService(t)
+	service.SetSpecService(spec.NewService(tmpDir))
+	service.SetProjectResolver(fakeProjects{overrides: runner.Overrides{MemoryMB: 2048, Timeout: time.Minute}})
+	ctx := context.Background()
+
+	root := t.TempDir()
+	specsDir := filepath.Join(root, "docs", "specs")
+	if err := os.MkdirAll(specsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(specsDir, "auth.yaml"), []byte("name: Auth\nversion: 1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	req := CreateRequest{Intent: IntentSpecAuthoring, SpecPath: "auth.yaml"}
+	if _, err := service.Create(ctx, req); err == nil {
+		t.Fatal("Create() without a workspace root should not find the project's spec")
+	}
+	req.WorkspaceRoot = root
+	sess, err := service.Create(ctx, req)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if sess.WorkspaceRoot != root {
+		t.Errorf("WorkspaceRoot = %q; want %q", sess.WorkspaceRoot, root)
+	}
+
+	greenfield, err := service.Create(ctx, CreateRequest{Intent: IntentGreenfield, WorkspaceRoot: root})
+	if err != nil {
+		t.Fatalf("Create(greenfield) error = %v", err)
+	}
+	if _, err := service.RunCode(ctx, greenfield.ID, RunRequest{Build: true}); err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if got := service.executor.(*mockExecutor).overrides; got.MemoryMB != 2048 || got.Timeout != time.Minute {
+		t.Errorf("run overrides = %+v; want the project's", got)
+	}
+}
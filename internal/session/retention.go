@@ -0,0 +1,220 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// RetentionPolicy bounds how long history is kept. A zero duration keeps
+// that kind of record forever.
+type RetentionPolicy struct {
+	SessionMaxAge time.Duration // sessions idle longer than this are deleted with their runs and interventions
+	RunMaxAge     time.Duration // older runs are deleted; each session keeps its latest run
+}
+
+// Enabled reports whether the policy prunes anything
+func (p RetentionPolicy) Enabled() bool {
+	return p.SessionMaxAge > 0 || p.RunMaxAge > 0
+}
+
+// PruneReport summarizes what a prune removed, or would remove on a dry run
+type PruneReport struct {
+	DryRun   bool     `json:"dry_run"`
+	Sessions []string `json:"sessions"` // IDs of deleted sessions
+	Runs     int      `json:"runs"`     // runs deleted from surviving sessions
+}
+
+// Archive keeps copies of the records retention prunes, e.g. in an
+// S3 bucket
+type Archive interface {
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// SetArchive makes pruning copy sessions and runs to archive before
+// deleting them. A record whose copy fails is kept.
+func (s *Service) SetArchive(a Archive) {
+	s.archive = a
+}
+
+// SessionArchive is what a pruned session is archived as, under
+// sessions/<id>.json
+type SessionArchive struct {
+	ArchivedAt    time.Time       `json:"archived_at"`
+	Session       *Session        `json:"session"`
+	Runs          []*Run          `json:"runs"`
+	Interventions []*Intervention `json:"interventions"`
+}
+
+// Prune deletes sessions and runs older than the policy allows. Sessions
+// age from their last update, so a long-running but recently used
+// session survives. With dryRun set nothing is deleted.
+func (s *Service) Prune(ctx context.Context, policy RetentionPolicy, now time.Time, dryRun bool) (*PruneReport, error) {
+	report := &PruneReport{DryRun: dryRun, Sessions: []string{}}
+	if !policy.Enabled() {
+		return report, nil
+	}
+
+	ids, err := s.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		sess, err := s.store.Get(id)
+		if err != nil {
+			continue
+		}
+
+		if policy.SessionMaxAge > 0 && now.Sub(sess.UpdatedAt) > policy.SessionMaxAge {
+			if !dryRun {
+				if err := s.archiveSession(ctx, sess, now); err != nil {
+					return report, fmt.Errorf("archive session %s: %w", id, err)
+				}
+				if err := s.store.Delete(id); err != nil {
+					return report, fmt.Errorf("delete session %s: %w", id, err)
+				}
+				s.deleteOutputs(id, "")
+			}
+			report.Sessions = append(report.Sessions, id)
+			continue
+		}
+
+		if policy.RunMaxAge > 0 {
+			n, err := s.pruneRuns(ctx, id, now.Add(-policy.RunMaxAge), dryRun)
+			report.Runs += n
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// pruneRuns deletes a session's runs created before cutoff, keeping the
+// latest run so completion checks still have a result to look at
+func (s *Service) pruneRuns(ctx context.Context, sessionID string, cutoff time.Time, dryRun bool) (int, error) {
+	runIDs, err := s.store.ListRuns(sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("list runs for %s: %w", sessionID, err)
+	}
+
+	runs := make([]*Run, 0, len(runIDs))
+	var latest *Run
+	for _, runID := range runIDs {
+		run, err := s.store.GetRun(sessionID, runID)
+		if err != nil {
+			continue
+		}
+		runs = append(runs, run)
+		if latest == nil || run.CreatedAt.After(latest.CreatedAt) {
+			latest = run
+		}
+	}
+
+	pruned := 0
+	for _, run := range runs {
+		if run == latest || !run.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if !dryRun {
+			if err := s.archiveRecord(ctx, "runs/"+sessionID+"/"+run.ID+".json", run); err != nil {
+				return pruned, fmt.Errorf("archive run %s: %w", run.ID, err)
+			}
+			if err := s.store.DeleteRun(sessionID, run.ID); err != nil {
+				return pruned, fmt.Errorf("delete run %s: %w", run.ID, err)
+			}
+			s.deleteOutputs(sessionID, run.ID)
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// archiveSession copies a session with its runs and interventions to
+// the archive, if there is one
+func (s *Service) archiveSession(ctx context.Context, sess *Session, now time.Time) error {
+	if s.archive == nil {
+		return nil
+	}
+
+	record := SessionArchive{ArchivedAt: now, Session: sess, Runs: []*Run{}, Interventions: []*Intervention{}}
+	runIDs, err := s.store.ListRuns(sess.ID)
+	if err != nil {
+		return fmt.Errorf("list runs: %w", err)
+	}
+	for _, runID := range runIDs {
+		run, err := s.store.GetRun(sess.ID, runID)
+		if err != nil {
+			return fmt.Errorf("get run %s: %w", runID, err)
+		}
+		record.Runs = append(record.Runs, run)
+	}
+	ivIDs, err := s.store.ListInterventions(sess.ID)
+	if err != nil {
+		return fmt.Errorf("list interventions: %w", err)
+	}
+	for _, ivID := range ivIDs {
+		iv, err := s.store.GetIntervention(sess.ID, ivID)
+		if err != nil {
+			return fmt.Errorf("get intervention %s: %w", ivID, err)
+		}
+		record.Interventions = append(record.Interventions, iv)
+	}
+	return s.archiveRecord(ctx, "sessions/"+sess.ID+".json", record)
+}
+
+// archiveRecord writes v to the archive as JSON, if there is one
+func (s *Service) archiveRecord(ctx context.Context, key string, v any) error {
+	if s.archive == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.archive.Put(ctx, key, data)
+}
+
+// StartPruneLoop applies the policy once at startup and then on every
+// interval until ctx is cancelled, logging what each prune removed
+func (s *Service) StartPruneLoop(ctx context.Context, policy RetentionPolicy, interval time.Duration) {
+	if !policy.Enabled() {
+		return
+	}
+
+	prune := func() {
+		report, err := s.Prune(ctx, policy, time.Now(), false)
+		if err != nil {
+			slog.Warn("retention prune error", "error", err)
+		}
+		if report != nil && (len(report.Sessions) > 0 || report.Runs > 0) {
+			slog.Info("retention prune removed history",
+				"sessions", len(report.Sessions), "session_ids", report.Sessions,
+				"runs", report.Runs,
+				"sessions_max_age", policy.SessionMaxAge, "runs_max_age", policy.RunMaxAge)
+		}
+	}
+
+	go func() {
+		prune()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				prune()
+			}
+		}
+	}()
+}
@@ -0,0 +1,130 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestService_Prune(t *testing.T) {
+	service, store, _ := setupTestService(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	stale := NewSession("test-pack/basics/hello", map[string]string{}, domain.DefaultPolicy())
+	stale.UpdatedAt = now.Add(-200 * 24 * time.Hour)
+	store.Save(stale)
+	store.SaveRun(&Run{ID: "stale-run", SessionID: stale.ID, CreatedAt: stale.UpdatedAt})
+
+	recent := NewSession("test-pack/basics/hello", map[string]string{}, domain.DefaultPolicy())
+	store.Save(recent)
+	store.SaveRun(&Run{ID: "old-1", SessionID: recent.ID, CreatedAt: now.Add(-60 * 24 * time.Hour)})
+	store.SaveRun(&Run{ID: "old-2", SessionID: recent.ID, CreatedAt: now.Add(-45 * 24 * time.Hour)})
+	store.SaveRun(&Run{ID: "new", SessionID: recent.ID, CreatedAt: now.Add(-time.Hour)})
+
+	// Only old runs: the latest run survives even past the cutoff
+	lonely := NewSession("test-pack/basics/hello", map[string]string{}, domain.DefaultPolicy())
+	store.Save(lonely)
+	store.SaveRun(&Run{ID: "only", SessionID: lonely.ID, CreatedAt: now.Add(-90 * 24 * time.Hour)})
+
+	policy := RetentionPolicy{SessionMaxAge: 180 * 24 * time.Hour, RunMaxAge: 30 * 24 * time.Hour}
+
+	report, err := service.Prune(ctx, policy, now, true)
+	if err != nil {
+		t.Fatalf("Prune(dry run) error = %v", err)
+	}
+	if len(report.Sessions) != 1 || report.Sessions[0] != stale.ID || report.Runs != 2 {
+		t.Errorf("dry run report = %+v; want 1 session, 2 runs", report)
+	}
+	if !store.Exists(stale.ID) {
+		t.Fatal("dry run deleted a session")
+	}
+
+	report, err = service.Prune(ctx, policy, now, false)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(report.Sessions) != 1 || report.Runs != 2 {
+		t.Errorf("report = %+v; want 1 session, 2 runs", report)
+	}
+	if store.Exists(stale.ID) {
+		t.Error("stale session should be deleted")
+	}
+	if ids, _ := store.ListRuns(recent.ID); len(ids) != 1 || ids[0] != "new" {
+		t.Errorf("recent session runs = %v; want [new]", ids)
+	}
+	if ids, _ := store.ListRuns(lonely.ID); len(ids) != 1 {
+		t.Errorf("latest run should be kept, got %v", ids)
+	}
+}
+
+func TestService_Prune_Disabled(t *testing.T) {
+	service, store, _ := setupTestService(t)
+
+	old := NewSession("test-pack/basics/hello", map[string]string{}, domain.DefaultPolicy())
+	old.UpdatedAt = time.Now().Add(-1000 * 24 * time.Hour)
+	store.Save(old)
+
+	report, err := service.Prune(context.Background(), RetentionPolicy{}, time.Now(), false)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(report.Sessions) != 0 || !store.Exists(old.ID) {
+		t.Error("zero policy must keep everything")
+	}
+}
+
+// memArchive records what retention archived, failing while err is set
+type memArchive struct {
+	objects map[string][]byte
+	err     error
+}
+
+func (a *memArchive) Put(ctx context.Context, key string, data []byte) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.objects[key] = data
+	return nil
+}
+
+func TestService_Prune_Archive(t *testing.T) {
+	service, store, _ := setupTestService(t)
+	ctx := context.Background()
+	now := time.Now()
+	archive := &memArchive{objects: map[string][]byte{}, err: errors.New("bucket unreachable")}
+	service.SetArchive(archive)
+
+	stale := NewSession("test-pack/basics/hello", map[string]string{}, domain.DefaultPolicy())
+	stale.UpdatedAt = now.Add(-200 * 24 * time.Hour)
+	store.Save(stale)
+	store.SaveRun(&Run{ID: "stale-run", SessionID: stale.ID, CreatedAt: stale.UpdatedAt})
+
+	policy := RetentionPolicy{SessionMaxAge: 180 * 24 * time.Hour}
+	if _, err := service.Prune(ctx, policy, now, false); err == nil {
+		t.Fatal("Prune() should fail when the archive does")
+	}
+	if !store.Exists(stale.ID) {
+		t.Fatal("a session that failed to archive must be kept")
+	}
+
+	archive.err = nil
+	if _, err := service.Prune(ctx, policy, now, false); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if store.Exists(stale.ID) {
+		t.Error("stale session should be deleted once archived")
+	}
+
+	var record SessionArchive
+	if err := json.Unmarshal(archive.objects["sessions/"+stale.ID+".json"], &record); err != nil {
+		t.Fatalf("archived session: %v (objects %v)", err, archive.objects)
+	}
+	if record.Session.ID != stale.ID || len(record.Runs) != 1 || record.Runs[0].ID != "stale-run" {
+		t.Errorf("archived %+v, want the session with its run", record)
+	}
+}
@@ -0,0 +1,48 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rootModuleFiles stay in a scoped session's context so hints still see
+// the module path and its dependencies
+var rootModuleFiles = map[string]bool{"go.mod": true, "go.sum": true, "go.work": true}
+
+// cleanScope validates a session scope, a directory relative to the
+// workspace root, and returns it without a trailing slash
+func cleanScope(scope string, intent SessionIntent) (string, error) {
+	if scope == "" {
+		return "", nil
+	}
+	if intent != IntentFeatureGuidance {
+		return "", fmt.Errorf("%w: only feature guidance sessions can be scoped", ErrInvalidScope)
+	}
+	scope = strings.TrimSuffix(scope, "/")
+	if !validWorkspacePath(scope) {
+		return "", fmt.Errorf("%w: %q is not a directory inside the workspace", ErrInvalidScope, scope)
+	}
+	return scope, nil
+}
+
+// InScope reports whether the workspace file name lies under scope. Every
+// file is in scope when scope is empty.
+func InScope(name, scope string) bool {
+	return scope == "" || strings.HasPrefix(name, scope+"/")
+}
+
+// ScopeCode returns the files of code under scope, plus the root module
+// files, for building a scoped session's context. code is returned as is
+// when scope is empty.
+func ScopeCode(code map[string]string, scope string) map[string]string {
+	if scope == "" {
+		return code
+	}
+	scoped := make(map[string]string)
+	for name, content := range code {
+		if InScope(name, scope) || rootModuleFiles[name] {
+			scoped[name] = content
+		}
+	}
+	return scoped
+}
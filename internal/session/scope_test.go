@@ -0,0 +1,61 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestScopeCode(t *testing.T) {
+	code := map[string]string{
+		"go.mod":                    "module example.com/mono",
+		"services/auth/login.go":    "package auth",
+		"services/auth/db/store.go": "package db",
+		"services/authz/policy.go":  "package authz",
+		"services/billing/pay.go":   "package billing",
+	}
+
+	scoped := ScopeCode(code, "services/auth")
+	for _, name := range []string{"go.mod", "services/auth/login.go", "services/auth/db/store.go"} {
+		if _, ok := scoped[name]; !ok {
+			t.Errorf("%s should be in scope", name)
+		}
+	}
+	if len(scoped) != 3 {
+		t.Errorf("ScopeCode() kept %d files; want 3 (services/authz is a different directory)", len(scoped))
+	}
+	if got := ScopeCode(code, ""); len(got) != len(code) {
+		t.Errorf("unscoped ScopeCode() kept %d files; want all %d", len(got), len(code))
+	}
+}
+
+func TestService_Create_Scope(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	ctx := context.Background()
+
+	sess, err := service.Create(ctx, CreateRequest{Intent: IntentFeatureGuidance, SpecPath: "auth.yaml", Scope: "services/auth/"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if sess.Scope != "services/auth" {
+		t.Errorf("Scope = %q; want services/auth", sess.Scope)
+	}
+
+	if _, err := service.RunCode(ctx, sess.ID, RunRequest{Build: true}); err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if got := service.executor.(*mockExecutor).scope; got != "services/auth" {
+		t.Errorf("run scope = %q; want services/auth", got)
+	}
+
+	invalid := []CreateRequest{
+		{Intent: IntentFeatureGuidance, SpecPath: "auth.yaml", Scope: "../elsewhere"},
+		{Intent: IntentFeatureGuidance, SpecPath: "auth.yaml", Scope: "/abs"},
+		{Intent: IntentGreenfield, Scope: "services/auth"},
+	}
+	for _, req := range invalid {
+		if _, err := service.Create(ctx, req); !errors.Is(err, ErrInvalidScope) {
+			t.Errorf("Create(%q, %s) error = %v; want ErrInvalidScope", req.Scope, req.Intent, err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package session
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Search hit kinds
+const (
+	SearchKindSession      = "session"
+	SearchKindRun          = "run"
+	SearchKindIntervention = "intervention"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+	snippetRadius      = 60
+)
+
+// SearchQuery selects history entries to search
+type SearchQuery struct {
+	Text  string // terms must all appear; "quoted phrases" match verbatim
+	Kind  string // optional: restrict to one SearchKind*
+	Limit int
+}
+
+// SearchHit is a single match in the learning history
+type SearchHit struct {
+	Kind       string    `json:"kind"`
+	SessionID  string    `json:"session_id"`
+	ExerciseID string    `json:"exercise_id,omitempty"`
+	ID         string    `json:"id"`    // run or intervention ID; session ID for sessions
+	Field      string    `json:"field"` // where the match was found, e.g. "test_output"
+	Snippet    string    `json:"snippet"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Search scans stored sessions, runs and interventions for the query and
+// returns the newest hits first. Each entry yields at most one hit, from
+// the first field that matches.
+func (s *Service) Search(ctx context.Context, q SearchQuery) ([]SearchHit, error) {
+	terms := parseSearchTerms(q.Text)
+	if len(terms) == 0 {
+		return []SearchHit{}, nil
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	ids, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	hits := []SearchHit{}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sess, err := s.getLive(id)
+		if err != nil {
+			continue
+		}
+
+		if q.Kind == "" || q.Kind == SearchKindSession {
+			if field, snippet, ok := matchFields(terms, sessionFields(sess)); ok {
+				hits = append(hits, SearchHit{
+					Kind: SearchKindSession, SessionID: sess.ID, ExerciseID: sess.ExerciseID,
+					ID: sess.ID, Field: field, Snippet: snippet, CreatedAt: sess.CreatedAt,
+				})
+			}
+		}
+
+		if q.Kind == "" || q.Kind == SearchKindRun {
+			runIDs, _ := s.store.ListRuns(sess.ID)
+			for _, runID := range runIDs {
+				run, err := s.store.GetRun(sess.ID, runID)
+				if err != nil || run.Result == nil {
+					continue
+				}
+				if field, snippet, ok := matchFields(terms, runFields(run.Result)); ok {
+					hits = append(hits, SearchHit{
+						Kind: SearchKindRun, SessionID: sess.ID, ExerciseID: sess.ExerciseID,
+						ID: run.ID, Field: field, Snippet: snippet, CreatedAt: run.CreatedAt,
+					})
+				}
+			}
+		}
+
+		if q.Kind == "" || q.Kind == SearchKindIntervention {
+			interventionIDs, _ := s.store.ListInterventions(sess.ID)
+			for _, interventionID := range interventionIDs {
+				iv, err := s.store.GetIntervention(sess.ID, interventionID)
+				if err != nil {
+					continue
+				}
+				if field, snippet, ok := matchFields(terms, [][2]string{{"content", iv.Content}}); ok {
+					hits = append(hits, SearchHit{
+						Kind: SearchKindIntervention, SessionID: sess.ID, ExerciseID: sess.ExerciseID,
+						ID: iv.ID, Field: field, Snippet: snippet, CreatedAt: iv.CreatedAt,
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].CreatedAt.After(hits[j].CreatedAt)
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+func sessionFields(sess *Session) [][2]string {
+	fields := [][2]string{
+		{"exercise_id", sess.ExerciseID},
+		{"spec_path", sess.SpecPath},
+	}
+	names := make([]string, 0, len(sess.Code))
+	for name := range sess.Code {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fields = append(fields, [2]string{"code:" + name, sess.Code[name]})
+	}
+	return fields
+}
+
+func runFields(result *RunResult) [][2]string {
+	return [][2]string{
+		{"build_output", result.BuildOutput},
+		{"test_output", result.TestOutput},
+		{"format_diff", result.FormatDiff},
+	}
+}
+
+// parseSearchTerms lowercases the query and splits it into terms, keeping
+// double-quoted phrases together
+func parseSearchTerms(text string) []string {
+	var terms []string
+	for i, part := range strings.Split(strings.ToLower(text), `"`) {
+		if i%2 == 1 {
+			if phrase := strings.TrimSpace(part); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+		terms = append(terms, strings.Fields(part)...)
+	}
+	return terms
+}
+
+// matchFields returns the first field containing every term, with a
+// snippet around the first term
+func matchFields(terms []string, fields [][2]string) (string, string, bool) {
+	for _, f := range fields {
+		lower := strings.ToLower(f[1])
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(lower, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return f[0], snippet(f[1], strings.Index(lower, terms[0]), len(terms[0])), true
+		}
+	}
+	return "", "", false
+}
+
+// snippet returns a single-line excerpt of text around [start, start+n)
+func snippet(text string, start, n int) string {
+	start = min(start, len(text))
+	from := max(start-snippetRadius, 0)
+	to := min(start+n+snippetRadius, len(text))
+	for from > 0 && !utf8.RuneStart(text[from]) {
+		from--
+	}
+	for to < len(text) && !utf8.RuneStart(text[to]) {
+		to++
+	}
+
+	out := strings.Join(strings.Fields(text[from:to]), " ")
+	if from > 0 {
+		out = "…" + out
+	}
+	if to < len(text) {
+		out += "…"
+	}
+	return out
+}
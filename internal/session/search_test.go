@@ -0,0 +1,95 @@
+package session
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestParseSearchTerms(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"nil map", []string{"nil", "map"}},
+		{`"nil map" panic`, []string{"nil map", "panic"}},
+		{`  Assignment  "to entry" `, []string{"assignment", "to entry"}},
+		{`""`, nil},
+	}
+	for _, tt := range tests {
+		if got := parseSearchTerms(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSearchTerms(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("a", 100) + " panic: assignment to entry in nil map\n" + strings.Repeat("b", 100)
+	start := strings.Index(text, "nil map")
+	got := snippet(text, start, len("nil map"))
+	if !strings.Contains(got, "nil map") || !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("snippet() = %q", got)
+	}
+	if strings.Contains(got, "\n") {
+		t.Error("snippet should be a single line")
+	}
+}
+
+func TestService_Search(t *testing.T) {
+	service, store, _ := setupTestService(t)
+	ctx := context.Background()
+
+	sess, _ := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+
+	older := time.Now().Add(-time.Hour)
+	store.SaveRun(&Run{
+		ID: "run-1", SessionID: sess.ID, CreatedAt: older,
+		Result: &RunResult{TestOutput: "panic: assignment to entry in nil map"},
+	})
+	store.SaveRun(&Run{
+		ID: "run-2", SessionID: sess.ID, CreatedAt: time.Now(),
+		Result: &RunResult{TestOutput: "ok"},
+	})
+	store.SaveIntervention(&Intervention{
+		ID: "iv-1", SessionID: sess.ID, Intent: domain.IntentHint, CreatedAt: time.Now(),
+		Content: "Maps must be initialized with make before you write to a nil map.",
+	})
+
+	hits, err := service.Search(ctx, SearchQuery{Text: `"nil map"`})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("Search() returned %d hits; want 2: %+v", len(hits), hits)
+	}
+	if hits[0].Kind != SearchKindIntervention || hits[1].Kind != SearchKindRun {
+		t.Errorf("hits should be newest first, got %s then %s", hits[0].Kind, hits[1].Kind)
+	}
+	if hits[1].ID != "run-1" || hits[1].Field != "test_output" {
+		t.Errorf("run hit = %+v", hits[1])
+	}
+
+	hits, _ = service.Search(ctx, SearchQuery{Text: "nil map", Kind: SearchKindRun})
+	if len(hits) != 1 || hits[0].Kind != SearchKindRun {
+		t.Errorf("kind filter returned %+v", hits)
+	}
+
+	hits, _ = service.Search(ctx, SearchQuery{Text: "hello", Kind: SearchKindSession})
+	if len(hits) != 1 || hits[0].Field != "exercise_id" {
+		t.Errorf("session search returned %+v", hits)
+	}
+
+	hits, _ = service.Search(ctx, SearchQuery{Text: "nil map", Limit: 1})
+	if len(hits) != 1 {
+		t.Errorf("limit ignored: %d hits", len(hits))
+	}
+
+	hits, _ = service.Search(ctx, SearchQuery{Text: "no such thing"})
+	if len(hits) != 0 {
+		t.Errorf("unexpected hits: %+v", hits)
+	}
+}
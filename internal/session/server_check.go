@@ -0,0 +1,48 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+// ServerRun is the outcome of running the program as a server and
+// probing it over HTTP
+type ServerRun struct {
+	Started bool                 `json:"started"`
+	Probes  []domain.ProbeResult `json:"probes,omitempty"`
+	Output  string               `json:"output,omitempty"` // build errors, or the server's log
+	OK      bool                 `json:"ok"`
+}
+
+// runServer runs the program as a server and sends it the exercise's
+// probes, when the run tests and the exercise declares a server check.
+// Executors that can't run servers skip the check.
+func (s *Service) runServer(ctx context.Context, session *Session, code map[string]string, req RunRequest) (*ServerRun, error) {
+	if !req.Test {
+		return nil, nil
+	}
+	recipe := s.checkRecipe(session)
+	if recipe == nil || recipe.Server == nil {
+		return nil, nil
+	}
+	srv, ok := s.executor.(runner.ServerRunner)
+	if !ok {
+		slog.Warn("executor cannot run servers; skipping server check", "exercise", session.ExerciseID)
+		return nil, nil
+	}
+
+	result, err := srv.RunServer(ctx, code, *recipe.Server)
+	if err != nil {
+		return nil, fmt.Errorf("server run: %w", err)
+	}
+	return &ServerRun{
+		Started: result.Started,
+		Probes:  result.Probes,
+		Output:  result.Output,
+		OK:      result.Passed(),
+	}, nil
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+// serverExecutor answers every server check with the configured probes
+type serverExecutor struct {
+	mockExecutor
+	check  domain.ServerCheck
+	result runner.ServerResult
+}
+
+func (s *serverExecutor) RunServer(ctx context.Context, code map[string]string, check domain.ServerCheck) (*runner.ServerResult, error) {
+	s.check = check
+	result := s.result
+	return &result, nil
+}
+
+func TestService_RunCode_Server(t *testing.T) {
+	service, _, tmpDir := setupTestService(t)
+	ctx := context.Background()
+
+	exerciseYAML := `id: basics/hello
+title: Hello World
+check_recipe:
+  test: true
+  server:
+    port: 9000
+    probes:
+      - path: /hello
+        expect:
+          status: 200
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "exercises", "test-pack", "basics", "hello.yaml"), []byte(exerciseYAML), 0644); err != nil {
+		t.Fatalf("failed to write hello.yaml: %v", err)
+	}
+	session, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Without server support the check is skipped
+	run, err := service.RunCode(ctx, session.ID, RunRequest{Test: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if run.Result.Server != nil || !run.Result.TestOK {
+		t.Errorf("server = %+v, test ok = %v; want the check skipped", run.Result.Server, run.Result.TestOK)
+	}
+
+	exec := &serverExecutor{result: runner.ServerResult{
+		BuildOK: true,
+		Started: true,
+		Probes:  []domain.ProbeResult{{Name: "GET /hello", Status: 404, Message: "status 404, want 200"}},
+	}}
+	service.executor = exec
+
+	// Runs that don't test don't start the server
+	if run, err = service.RunCode(ctx, session.ID, RunRequest{Build: true}); err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if run.Result.Server != nil {
+		t.Errorf("server = %+v; want no server check without tests", run.Result.Server)
+	}
+
+	run, err = service.RunCode(ctx, session.ID, RunRequest{Test: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if exec.check.Port != 9000 || len(exec.check.Probes) != 1 {
+		t.Errorf("server check = %+v", exec.check)
+	}
+	if run.Result.Server == nil || run.Result.Server.OK || len(run.Result.Server.Probes) != 1 {
+		t.Fatalf("server = %+v; want one failing probe", run.Result.Server)
+	}
+	if run.Result.TestOK {
+		t.Error("TestOK = true; want a failing probe to fail the tests")
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/felixgeelhaar/temper/internal/domain"
@@ -17,13 +20,19 @@ import (
 )
 
 var (
-	ErrSessionNotFound  = errors.New("session not found")
-	ErrExerciseNotFound = errors.New("exercise not found")
-	ErrCooldownActive   = errors.New("intervention cooldown active")
-	ErrSessionNotActive = errors.New("session is not active")
-	ErrSpecRequired     = errors.New("spec path required for feature guidance intent")
-	ErrSpecInvalid      = errors.New("spec validation failed")
-	ErrDocsRequired     = errors.New("docs paths required for spec authoring intent")
+	ErrSessionNotFound   = errors.New("session not found")
+	ErrExerciseNotFound  = errors.New("exercise not found")
+	ErrCooldownActive    = errors.New("intervention cooldown active")
+	ErrSessionNotActive  = errors.New("session is not active")
+	ErrSpecRequired      = errors.New("spec path required for feature guidance intent")
+	ErrSpecInvalid       = errors.New("spec validation failed")
+	ErrDocsRequired      = errors.New("docs paths required for spec authoring intent")
+	ErrNotAuthoring      = errors.New("session is not a spec authoring session")
+	ErrNotDebugging      = errors.New("session exercise is not a debugging exercise")
+	ErrWorkspaceConflict = errors.New("workspace changed since base version")
+	ErrInvalidPath       = errors.New("invalid workspace path")
+	ErrInvalidScope      = errors.New("invalid session scope")
+	ErrInvalidBuildEnv   = errors.New("invalid session build environment")
 )
 
 // Service manages pairing sessions
@@ -32,8 +41,26 @@ type Service struct {
 	loader         *exercise.Loader
 	executor       runner.Executor
 	riskDetector   *risk.Detector
+	parser         *runner.Parser
 	profileService *profile.Service // Optional: tracks learning progress
 	specService    *spec.Service    // Optional: spec management for feature guidance
+	explainer      ErrorExplainer   // Optional: explains errors the offline rules don't cover
+	testExplainer  TestExplainer    // Optional: explains failed tests on request
+	projects       ProjectResolver  // Optional: per-project settings by workspace root
+	archive        Archive          // Optional: keeps copies of what retention prunes
+	outputs        OutputStore      // Optional: keeps run output too long for the run record
+
+	commands runner.CommandAllowlist // custom project commands the user approved
+
+	adaptive bool // start exercises in the variant that suits the learner's skill
+
+	workspaceMu sync.Mutex // serializes workspace pushes so base versions compare-and-swap
+
+	onNudge     func(Nudge)          // Optional: receives stuck nudges
+	onLifecycle func(LifecycleEvent) // Optional: receives idle, resumed and expired events
+	onRun       RunHandler           // Optional: told about every saved run
+	nudgeMu     sync.Mutex
+	nudgedAt    map[string]time.Time // session ID → last nudge
 }
 
 // NewService creates a new session service
@@ -43,6 +70,8 @@ func NewService(store SessionStore, loader *exercise.Loader, executor runner.Exe
 		loader:       loader,
 		executor:     executor,
 		riskDetector: risk.NewDetector(),
+		parser:       runner.NewParser(),
+		nudgedAt:     make(map[string]time.Time),
 	}
 }
 
@@ -51,19 +80,101 @@ func (s *Service) SetProfileService(ps *profile.Service) {
 	s.profileService = ps
 }
 
+// SetAdaptiveDifficulty sets whether training sessions start with the
+// pack author's harder or easier variant when the learner's profile puts
+// them clearly above or below the exercise. It needs a profile service.
+func (s *Service) SetAdaptiveDifficulty(on bool) {
+	s.adaptive = on
+}
+
 // SetSpecService sets the spec service for feature guidance sessions
 func (s *Service) SetSpecService(ss *spec.Service) {
 	s.specService = ss
 }
 
+// ProjectResolver maps a session's workspace root to that project's
+// settings, e.g. from its .temper.yaml
+type ProjectResolver interface {
+	// SpecService returns the spec service for the project, or nil to use
+	// the default one
+	SpecService(workspaceRoot string) *spec.Service
+	// RunOverrides returns the runner settings the project overrides
+	RunOverrides(workspaceRoot string) runner.Overrides
+	// Commands returns the custom commands the project defines, by name
+	Commands(workspaceRoot string) map[string]string
+}
+
+// SetProjectResolver sets how sessions started in a project pick up its
+// settings
+func (s *Service) SetProjectResolver(r ProjectResolver) {
+	s.projects = r
+}
+
+// specsFor returns the spec service for sessions in workspaceRoot,
+// narrowed to scope when the scoped package keeps its own specs
+func (s *Service) specsFor(workspaceRoot, scope string) *spec.Service {
+	specs := s.specService
+	if workspaceRoot != "" && s.projects != nil {
+		if ss := s.projects.SpecService(workspaceRoot); ss != nil {
+			specs = ss
+		}
+	}
+	if specs == nil {
+		return nil
+	}
+	return specs.Scoped(scope)
+}
+
+// RunHandler is told about each run once it is saved, e.g. to check it
+// for achievements
+type RunHandler func(ctx context.Context, sess *Session, run *Run)
+
+// SetRunHandler sets the function told about every saved run
+func (s *Service) SetRunHandler(fn RunHandler) {
+	s.onRun = fn
+}
+
+// ErrorExplainer restates run errors in beginner-friendly language when
+// the offline rule table has nothing for them. packID and paths identify
+// the session's code so local-only routing can apply.
+type ErrorExplainer interface {
+	ExplainErrors(ctx context.Context, packID string, paths []string, errs []string) ([]string, error)
+}
+
+// SetErrorExplainer sets the fallback for run errors no offline rule covers
+func (s *Service) SetErrorExplainer(e ErrorExplainer) {
+	s.explainer = e
+}
+
 // CreateRequest contains data for creating a session
 type CreateRequest struct {
 	ExerciseID string            // For training intent
 	SpecPath   string            // For feature guidance or spec authoring intent
+	SpecPaths  []string          // For spec authoring intent: further spec files in the same session
 	DocsPaths  []string          // For spec authoring intent (paths to search for docs)
 	Intent     SessionIntent     // Explicit intent (optional, inferred if empty)
 	Code       map[string]string // Initial code (for greenfield/feature)
 	Policy     *domain.LearningPolicy
+
+	// WorkspaceRoot is the project the session is started in; its
+	// .temper.yaml settings then apply to the session
+	WorkspaceRoot string
+
+	// Scope limits a feature guidance session to one directory of a
+	// monorepo, e.g. services/auth
+	Scope string
+
+	// BuildEnv adds allowlisted variables, build tags and test flags to
+	// the session's runs, on top of the exercise's
+	BuildEnv domain.BuildEnv
+
+	// Assignment creates the session for a cohort member rather than for
+	// the local learner
+	Assignment *Assignment
+
+	// AsWritten starts a training session on the exercise as written,
+	// without adaptive difficulty picking a variant
+	AsWritten bool
 }
 
 // Create starts a new pairing session
@@ -77,7 +188,16 @@ func (s *Service) Create(ctx context.Context, req CreateRequest) (*Session, erro
 		policy = *req.Policy
 	}
 
+	scope, err := cleanScope(req.Scope, intent)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.BuildEnv.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBuildEnv, err)
+	}
+
 	var session *Session
+	specs := s.specsFor(req.WorkspaceRoot, scope)
 
 	switch intent {
 	case IntentTraining:
@@ -85,7 +205,8 @@ func (s *Service) Create(ctx context.Context, req CreateRequest) (*Session, erro
 		if req.ExerciseID == "" {
 			return nil, fmt.Errorf("exercise ID required for training intent")
 		}
-		sess, err := s.createTrainingSession(ctx, req.ExerciseID, policy)
+		adapt := s.adaptive && !req.AsWritten && req.Assignment == nil
+		sess, err := s.createTrainingSession(ctx, req.ExerciseID, policy, adapt)
 		if err != nil {
 			return nil, err
 		}
@@ -96,7 +217,7 @@ func (s *Service) Create(ctx context.Context, req CreateRequest) (*Session, erro
 		if req.SpecPath == "" {
 			return nil, ErrSpecRequired
 		}
-		sess, err := s.createFeatureSession(ctx, req.SpecPath, req.Code, policy)
+		sess, err := s.createFeatureSession(ctx, specs, req.SpecPath, req.Code, policy)
 		if err != nil {
 			return nil, err
 		}
@@ -107,11 +228,15 @@ func (s *Service) Create(ctx context.Context, req CreateRequest) (*Session, erro
 		session = NewGreenfieldSession(req.Code, policy)
 
 	case IntentSpecAuthoring:
-		// Spec authoring requires a spec and docs paths
-		if req.SpecPath == "" {
+		// Spec authoring requires at least one spec; docs paths default
+		specPaths := req.SpecPaths
+		if req.SpecPath != "" {
+			specPaths = append([]string{req.SpecPath}, specPaths...)
+		}
+		if len(specPaths) == 0 {
 			return nil, ErrSpecRequired
 		}
-		sess, err := s.createAuthoringSession(ctx, req.SpecPath, req.DocsPaths, policy)
+		sess, err := s.createAuthoringSession(ctx, specs, specPaths, req.DocsPaths, policy)
 		if err != nil {
 			return nil, err
 		}
@@ -120,14 +245,23 @@ func (s *Service) Create(ctx context.Context, req CreateRequest) (*Session, erro
 	default:
 		return nil, fmt.Errorf("unknown intent: %s", intent)
 	}
+	session.WorkspaceRoot = req.WorkspaceRoot
+	session.Scope = scope
+	if !req.BuildEnv.IsZero() {
+		buildEnv := req.BuildEnv
+		session.BuildEnv = &buildEnv
+	}
+	session.Assignment = req.Assignment
+	session.detectLanguages()
 
 	// Persist
 	if err := s.store.Save(session); err != nil {
 		return nil, fmt.Errorf("save session: %w", err)
 	}
 
-	// Notify profile service of session start
-	if s.profileService != nil {
+	// Notify profile service of session start. Assigned sessions are
+	// someone else's work, not the local learner's.
+	if s.profileService != nil && session.Assignment == nil {
 		if err := s.profileService.OnSessionStart(ctx, profile.SessionInfo{
 			ID:         session.ID,
 			ExerciseID: session.ExerciseID,
@@ -158,22 +292,23 @@ func (s *Service) inferIntent(req CreateRequest) SessionIntent {
 	return IntentGreenfield
 }
 
-// createTrainingSession creates a session for an exercise
-func (s *Service) createTrainingSession(ctx context.Context, exerciseID string, policy domain.LearningPolicy) (*Session, error) {
-	// Parse exercise ID (pack/category/slug)
-	parts := splitExerciseID(exerciseID)
-	if len(parts) < 2 {
-		return nil, fmt.Errorf("invalid exercise ID format: %s", exerciseID)
+// createTrainingSession creates a session for an exercise. With adapt, a
+// learner clearly above or below the exercise gets its matching variant.
+func (s *Service) createTrainingSession(ctx context.Context, exerciseID string, policy domain.LearningPolicy, adapt bool) (*Session, error) {
+	// Parse exercise ID ([org/]pack/category/slug)
+	packID, slug, ok := domain.SplitExerciseID(exerciseID)
+	if !ok {
+		return nil, ErrExerciseNotFound
 	}
 
-	packID := parts[0]
-	slug := joinPath(parts[1:]...)
-
 	// Load exercise
 	ex, err := s.loader.LoadExercise(packID, slug)
 	if err != nil {
 		return nil, ErrExerciseNotFound
 	}
+	if adapt {
+		ex = ex.WithVariant(s.chooseVariant(ctx, ex))
+	}
 
 	// Combine starter and test code
 	code := make(map[string]string)
@@ -184,14 +319,35 @@ func (s *Service) createTrainingSession(ctx context.Context, exerciseID string,
 		code[k] = v
 	}
 
-	return NewSession(exerciseID, code, policy), nil
+	session := NewSession(exerciseID, code, policy)
+	session.Language = ex.Language
+	session.ExerciseBaseline = newExerciseBaseline(ex)
+	return session, nil
+}
+
+// chooseVariant picks the variant of ex for the local learner from their
+// skill in its topic, or "" for the exercise as written
+func (s *Service) chooseVariant(ctx context.Context, ex *domain.Exercise) domain.VariantKind {
+	if s.profileService == nil || len(ex.Variants) == 0 {
+		return ""
+	}
+	stored, err := s.profileService.GetProfile(ctx)
+	if err != nil {
+		slog.Warn("failed to load profile for adaptive difficulty", "error", err)
+		return ""
+	}
+	skill := stored.TopicSkills[profile.ExtractTopic(ex.ID)]
+	return domain.ChooseVariant(ex.Difficulty, domain.SkillLevel{
+		Level:    skill.Level,
+		Attempts: skill.Attempts,
+	})
 }
 
 // createFeatureSession creates a session for feature guidance with spec
-func (s *Service) createFeatureSession(ctx context.Context, specPath string, code map[string]string, policy domain.LearningPolicy) (*Session, error) {
+func (s *Service) createFeatureSession(ctx context.Context, specs *spec.Service, specPath string, code map[string]string, policy domain.LearningPolicy) (*Session, error) {
 	// Validate spec if spec service is available
-	if s.specService != nil {
-		validation, err := s.specService.Validate(ctx, specPath)
+	if specs != nil {
+		validation, err := specs.Validate(ctx, specPath)
 		if err != nil {
 			return nil, fmt.Errorf("load spec: %w", err)
 		}
@@ -208,12 +364,13 @@ func (s *Service) createFeatureSession(ctx context.Context, specPath string, cod
 }
 
 // createAuthoringSession creates a session for spec authoring with docs
-func (s *Service) createAuthoringSession(ctx context.Context, specPath string, docsPaths []string, policy domain.LearningPolicy) (*Session, error) {
-	// Load spec to verify it exists (we don't validate since it's being authored)
-	if s.specService != nil {
-		_, err := s.specService.Load(ctx, specPath)
-		if err != nil {
-			return nil, fmt.Errorf("load spec: %w", err)
+func (s *Service) createAuthoringSession(ctx context.Context, specs *spec.Service, specPaths []string, docsPaths []string, policy domain.LearningPolicy) (*Session, error) {
+	// Load specs to verify they exist (we don't validate since they're being authored)
+	if specs != nil {
+		for _, specPath := range specPaths {
+			if _, err := specs.Load(ctx, specPath); err != nil {
+				return nil, fmt.Errorf("load spec %s: %w", specPath, err)
+			}
 		}
 	}
 
@@ -229,7 +386,35 @@ func (s *Service) createAuthoringSession(ctx context.Context, specPath string, d
 	}
 	authoringPolicy.CooldownSeconds = 30 // Faster iteration for authoring
 
-	return NewAuthoringSession(specPath, docsPaths, authoringPolicy), nil
+	session := NewAuthoringSession(specPaths[0], docsPaths, authoringPolicy)
+	for _, specPath := range specPaths[1:] {
+		session.AddSpecFile(specPath)
+	}
+	return session, nil
+}
+
+// AddAuthoringSpec adds a spec file to an authoring session, e.g. one
+// split out of the session's epic. Adding a file twice is a no-op.
+func (s *Service) AddAuthoringSpec(ctx context.Context, id, specPath string) (*Session, error) {
+	session, err := s.getLive(id)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.Intent != IntentSpecAuthoring {
+		return nil, ErrNotAuthoring
+	}
+	if specs := s.specsFor(session.WorkspaceRoot, session.Scope); specs != nil {
+		if _, err := specs.Load(ctx, specPath); err != nil {
+			return nil, fmt.Errorf("load spec %s: %w", specPath, err)
+		}
+	}
+
+	if session.AddSpecFile(specPath) {
+		if err := s.store.Save(session); err != nil {
+			return nil, fmt.Errorf("save session: %w", err)
+		}
+	}
+	return session, nil
 }
 
 // SaveSession persists a session directly. Used for administrative updates
@@ -238,8 +423,13 @@ func (s *Service) SaveSession(sess *Session) error {
 	return s.store.Save(sess)
 }
 
-// Get retrieves a session by ID
+// Get retrieves a session by ID. Deleted sessions are not found.
 func (s *Service) Get(ctx context.Context, id string) (*Session, error) {
+	return s.getLive(id)
+}
+
+// getLive loads a session, hiding tombstones behind ErrSessionNotFound
+func (s *Service) getLive(id string) (*Session, error) {
 	session, err := s.store.Get(id)
 	if err != nil {
 		if errors.Is(err, ErrNotFound) {
@@ -247,12 +437,23 @@ func (s *Service) Get(ctx context.Context, id string) (*Session, error) {
 		}
 		return nil, err
 	}
+	if session.Status == StatusDeleted {
+		return nil, ErrSessionNotFound
+	}
 	return session, nil
 }
 
-// Delete removes a session
+// Delete soft-deletes a session: it disappears from the API, while its
+// runs and interventions stay for analytics until retention prunes them.
+// Deleting a deleted session succeeds; an unknown one is ErrSessionNotFound.
 func (s *Service) Delete(ctx context.Context, id string) error {
-	return s.store.Delete(id)
+	if err := s.store.Tombstone(id, time.Now()); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return nil
 }
 
 // List returns all active sessions
@@ -262,7 +463,7 @@ func (s *Service) List(ctx context.Context) ([]*Session, error) {
 
 // UpdateCode updates the code in a session
 func (s *Service) UpdateCode(ctx context.Context, id string, code map[string]string) (*Session, error) {
-	session, err := s.store.Get(id)
+	session, err := s.getLive(id)
 	if err != nil {
 		return nil, ErrSessionNotFound
 	}
@@ -286,11 +487,24 @@ type RunRequest struct {
 	Format bool
 	Build  bool
 	Test   bool
+	Debug  bool // rerun failing tests under the debugger
+
+	// Explain rewrites compiler and test errors in beginner-friendly
+	// language alongside the raw output
+	Explain bool
+
+	// Stdin, when set, runs the program once with this input after the
+	// tests, for exercises that read from standard input
+	Stdin *string
+
+	// Command, when set, runs the project's custom command of this name
+	// instead of format, build and test
+	Command string
 }
 
 // RunCode executes code in a session
 func (s *Service) RunCode(ctx context.Context, sessionID string, req RunRequest) (*Run, error) {
-	session, err := s.store.Get(sessionID)
+	session, err := s.getLive(sessionID)
 	if err != nil {
 		return nil, ErrSessionNotFound
 	}
@@ -299,21 +513,47 @@ func (s *Service) RunCode(ctx context.Context, sessionID string, req RunRequest)
 		return nil, ErrSessionNotActive
 	}
 
+	if err := s.checkExerciseVersion(session); err != nil {
+		return nil, err
+	}
+
+	// Past an assignment's deadline runs are refused or flagged late
+	now := time.Now()
+	deadline := session.deadline()
+	if deadline.Passed(now) && deadline.Locks() {
+		return nil, ErrDeadlinePassed
+	}
+
+	// Build and test with the toolchain the exercise pack pins
+	ctx = runner.WithGoVersion(ctx, s.packGoVersion(session))
+	if session.WorkspaceRoot != "" && s.projects != nil {
+		ctx = runner.WithOverrides(ctx, s.projects.RunOverrides(session.WorkspaceRoot))
+	}
+	// Only build and test the packages a monorepo session is scoped to
+	ctx = runner.WithScope(ctx, session.Scope)
+	ctx = runner.WithBuildEnv(ctx, s.buildEnv(session))
+
 	// Use provided code or session's current code
 	code := req.Code
 	if code == nil {
 		code = session.Code
 	}
+	// Build and test it with the toolchain of its language
+	ctx = runner.WithLanguage(ctx, session.runLanguage(code))
 
 	// Create run record
 	run := &Run{
 		ID:        uuid.New().String(),
 		SessionID: sessionID,
 		Code:      code,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
 	}
 
-	result := &RunResult{}
+	result := &RunResult{Late: deadline.Passed(now)}
+
+	if req.Command != "" {
+		return s.runCommand(ctx, session, run, result, req.Command)
+	}
 
 	// Execute format check
 	if req.Format {
@@ -333,12 +573,20 @@ func (s *Service) RunCode(ctx context.Context, sessionID string, req RunRequest)
 		}
 		result.BuildOK = buildResult.OK
 		result.BuildOutput = buildResult.Output
+		result.DependencyViolations = buildResult.Violations
+		if !buildResult.OK {
+			result.QuickFixes = s.quickFixes(ctx, session, code, result)
+		}
 
 		// Skip tests if build failed
 		if !buildResult.OK {
 			// Still run risk detection even on build failure
 			result.Risks = s.riskDetector.Analyze(code)
+			if req.Explain {
+				result.Explanations = s.explainErrors(ctx, session, code, result)
+			}
 			run.Result = result
+			s.spillOutputs(run)
 			session.RecordRun()
 
 			if err := s.store.Save(session); err != nil {
@@ -347,6 +595,10 @@ func (s *Service) RunCode(ctx context.Context, sessionID string, req RunRequest)
 			if err := s.store.SaveRun(run); err != nil {
 				return nil, fmt.Errorf("save run: %w", err)
 			}
+			s.checkStuck(ctx, session, time.Now())
+			if s.onRun != nil {
+				s.onRun(ctx, session, run)
+			}
 
 			return run, nil
 		}
@@ -360,13 +612,70 @@ func (s *Service) RunCode(ctx context.Context, sessionID string, req RunRequest)
 		}
 		result.TestOK = testResult.OK
 		result.TestOutput = testResult.Output
+		result.Tests = s.parser.ParseTestOutput(testResult.Output)
+		for i := range result.Tests {
+			result.Tests[i].Output = "" // already in TestOutput
+		}
 		result.Duration = testResult.Duration
+		if len(testResult.Violations) > 0 {
+			result.DependencyViolations = testResult.Violations
+		}
+
+		if !testResult.OK && (req.Debug || s.recipeWantsDebug(session)) {
+			result.Debug = s.debugSnapshot(ctx, code)
+		}
+	}
+
+	programs, err := s.runPrograms(ctx, session, code, req)
+	if err != nil {
+		return nil, err
+	}
+	result.Programs = programs
+	for _, p := range programs {
+		// A failing stdin case fails the tests like any other test
+		if p.Expected != nil && !p.OK {
+			result.TestOK = false
+		}
+	}
+
+	server, err := s.runServer(ctx, session, code, req)
+	if err != nil {
+		return nil, err
+	}
+	result.Server = server
+	// So does a server that didn't start or failed a probe
+	if server != nil && !server.OK {
+		result.TestOK = false
+	}
+
+	if req.Test {
+		fuzz, err := s.runFuzz(ctx, session, code, result)
+		if err != nil {
+			return nil, err
+		}
+		result.Fuzz = fuzz
+		if fuzz != nil && !fuzz.OK {
+			result.TestOK = false
+		}
+	}
+
+	// A failed build already returned, so there is something to collect
+	if req.Build || req.Test {
+		artifacts, err := s.runArtifacts(ctx, session, run)
+		if err != nil {
+			return nil, err
+		}
+		result.Artifacts = artifacts
 	}
 
 	// Run risk detection on the code
 	result.Risks = s.riskDetector.Analyze(code)
+	if req.Explain && !(result.BuildOK && result.TestOK) {
+		result.Explanations = s.explainErrors(ctx, session, code, result)
+	}
 
 	run.Result = result
+	s.spillOutputs(run)
 
 	// Update session
 	session.RecordRun()
@@ -397,18 +706,144 @@ func (s *Service) RunCode(ctx context.Context, sessionID string, req RunRequest)
 			slog.Warn("failed to record run in profile", "error", err)
 		}
 	}
+	s.checkStuck(ctx, session, time.Now())
+	if s.onRun != nil {
+		s.onRun(ctx, session, run)
+	}
 
 	return run, nil
 }
 
+// recipeWantsDebug reports whether the session's exercise enables debug
+// runs in its check recipe
+func (s *Service) recipeWantsDebug(session *Session) bool {
+	ex, err := s.loader.LoadExerciseByID(session.ExerciseID)
+	if err != nil {
+		return false
+	}
+	return ex.CheckRecipe.Debug
+}
+
+// buildEnv returns the variables, build tags and test flags the session's
+// runs use: the exercise's check recipe with the session's own on top
+func (s *Service) buildEnv(session *Session) domain.BuildEnv {
+	var env domain.BuildEnv
+	if session.ExerciseID != "" {
+		if ex, err := s.loader.LoadExerciseByID(session.ExerciseID); err == nil {
+			env = ex.CheckRecipe.BuildEnv()
+		}
+	}
+	if session.BuildEnv != nil {
+		env = env.Merge(*session.BuildEnv)
+	}
+	return env
+}
+
+// packGoVersion returns the Go version the session's exercise pack pins,
+// or "" to use the runner's default toolchain
+func (s *Service) packGoVersion(session *Session) string {
+	packID, _, ok := domain.SplitExerciseID(session.ExerciseID)
+	if !ok {
+		return ""
+	}
+	pack, err := s.loader.LoadPack(packID)
+	if err != nil {
+		return ""
+	}
+	return pack.GoVersion
+}
+
+// exerciseDifficulty returns the difficulty of the session's exercise for
+// the skill model, or "" for sessions without a known exercise. A
+// session on a variant is credited at the variant's difficulty.
+func (s *Service) exerciseDifficulty(session *Session) string {
+	ex, err := s.sessionExercise(session)
+	if err != nil {
+		return ""
+	}
+	return string(ex.Difficulty)
+}
+
+// explainErrors translates the run's errors with the offline rules, then
+// asks the explainer about the rest. Errors nobody could explain are left
+// out; the raw output still has them.
+// quickFixes returns the mechanical fixes for a Go run's compile errors.
+// A fix points at the line and names what's missing, as much as an L2
+// hint does, so sessions whose policy stops below L2 go without.
+func (s *Service) quickFixes(ctx context.Context, session *Session, code map[string]string, result *RunResult) []domain.QuickFix {
+	if session.Policy.MaxLevel < domain.L2LocationConcept {
+		return nil
+	}
+	if lang := runner.LanguageFromContext(ctx); lang != "" && lang != runner.LanguageGo {
+		return nil
+	}
+	return runner.QuickFixes(result.BuildOutput, code)
+}
+
+func (s *Service) explainErrors(ctx context.Context, session *Session, code map[string]string, result *RunResult) []domain.ErrorExplanation {
+	explanations := runner.ExplainOutput(result.BuildOutput, result.TestOutput)
+
+	var pending []int
+	for i, e := range explanations {
+		if e.Explanation == "" {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) > 0 && s.explainer != nil {
+		errs := make([]string, len(pending))
+		for j, i := range pending {
+			errs[j] = explanations[i].Original
+		}
+		paths := make([]string, 0, len(code))
+		for name := range code {
+			paths = append(paths, name)
+		}
+		packID, _, _ := domain.SplitExerciseID(session.ExerciseID)
+
+		texts, err := s.explainer.ExplainErrors(ctx, packID, paths, errs)
+		if err != nil {
+			slog.Warn("explain run errors", "session_id", session.ID, "error", err)
+		} else {
+			for j, i := range pending {
+				explanations[i].Explanation = texts[j]
+				explanations[i].Source = domain.ExplanationLLM
+			}
+		}
+	}
+
+	explained := make([]domain.ErrorExplanation, 0, len(explanations))
+	for _, e := range explanations {
+		if e.Explanation != "" {
+			explained = append(explained, e)
+		}
+	}
+	return explained
+}
+
+// debugSnapshot reruns the tests under the debugger if the executor
+// supports it. A failed debug run never fails the run itself.
+func (s *Service) debugSnapshot(ctx context.Context, code map[string]string) *domain.DebugSnapshot {
+	dbg, ok := s.executor.(runner.DebugRunner)
+	if !ok {
+		return nil
+	}
+	result, err := dbg.RunTestsDebug(ctx, code, []string{"-v"})
+	if err != nil {
+		slog.Warn("debug run failed", "error", err)
+		return nil
+	}
+	return result.Snapshot
+}
+
 // Complete marks a session as completed
 func (s *Service) Complete(ctx context.Context, id string) error {
-	session, err := s.store.Get(id)
+	session, err := s.getLive(id)
 	if err != nil {
 		return ErrSessionNotFound
 	}
 
 	session.Complete()
+	session.Approach = s.compareApproach(session)
 
 	if err := s.store.Save(session); err != nil {
 		return err
@@ -417,12 +852,14 @@ func (s *Service) Complete(ctx context.Context, id string) error {
 	// Notify profile service of session completion
 	if s.profileService != nil {
 		if err := s.profileService.OnSessionComplete(ctx, profile.SessionInfo{
-			ID:         session.ID,
-			ExerciseID: session.ExerciseID,
-			RunCount:   session.RunCount,
-			HintCount:  session.HintCount,
-			Status:     string(session.Status),
-			CreatedAt:  session.CreatedAt,
+			ID:           session.ID,
+			ExerciseID:   session.ExerciseID,
+			Difficulty:   s.exerciseDifficulty(session),
+			RunCount:     session.RunCount,
+			HintCount:    session.HintCount,
+			BudgetUnused: session.BudgetUnused(),
+			Status:       string(session.Status),
+			CreatedAt:    session.CreatedAt,
 		}); err != nil {
 			slog.Warn("failed to record session completion in profile", "error", err)
 		}
@@ -431,6 +868,60 @@ func (s *Service) Complete(ctx context.Context, id string) error {
 	return nil
 }
 
+// RootCauseResult is the outcome of a root-cause submission
+type RootCauseResult struct {
+	Verdict     domain.RootCauseVerdict `json:"verdict"`
+	TestsPassed bool                    `json:"tests_passed"`
+	Completed   bool                    `json:"completed"`
+}
+
+// SubmitRootCause evaluates the learner's root-cause answers for a
+// debugging exercise. The session completes only when every injected bug
+// is identified and the most recent run had green tests.
+func (s *Service) SubmitRootCause(ctx context.Context, id string, answers []domain.RootCauseAnswer) (*RootCauseResult, error) {
+	session, err := s.getLive(id)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.Status != StatusActive {
+		return nil, ErrSessionNotActive
+	}
+
+	if session.ExerciseID == "" {
+		return nil, ErrNotDebugging
+	}
+	ex, err := s.loader.LoadExerciseByID(session.ExerciseID)
+	if err != nil {
+		return nil, ErrExerciseNotFound
+	}
+	if !ex.IsDebugging() {
+		return nil, ErrNotDebugging
+	}
+
+	result := &RootCauseResult{Verdict: ex.Debugging.EvaluateRootCause(answers)}
+
+	runs, err := s.GetRuns(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var latest *Run
+	for _, run := range runs {
+		if latest == nil || run.CreatedAt.After(latest.CreatedAt) {
+			latest = run
+		}
+	}
+	result.TestsPassed = latest != nil && latest.Result != nil && latest.Result.TestOK
+
+	if result.Verdict.Identified && result.TestsPassed {
+		if err := s.Complete(ctx, id); err != nil {
+			return nil, err
+		}
+		result.Completed = true
+	}
+
+	return result, nil
+}
+
 // GetRuns returns all runs for a session
 func (s *Service) GetRuns(ctx context.Context, sessionID string) ([]*Run, error) {
 	ids, err := s.store.ListRuns(sessionID)
@@ -450,9 +941,40 @@ func (s *Service) GetRuns(ctx context.Context, sessionID string) ([]*Run, error)
 	return runs, nil
 }
 
+// RunsForSpec returns the runs of the stored sessions whose spec path is
+// specPath, newest first
+func (s *Service) RunsForSpec(ctx context.Context, specPath string) ([]*Run, error) {
+	ids, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	want := filepath.Clean(specPath)
+	runs := []*Run{}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sess, err := s.store.Get(id)
+		if err != nil || sess.SpecPath == "" || filepath.Clean(sess.SpecPath) != want {
+			continue
+		}
+		sessionRuns, err := s.GetRuns(ctx, sess.ID)
+		if err != nil {
+			continue
+		}
+		runs = append(runs, sessionRuns...)
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].CreatedAt.After(runs[j].CreatedAt)
+	})
+	return runs, nil
+}
+
 // RecordIntervention records an intervention in a session
 func (s *Service) RecordIntervention(ctx context.Context, intervention *Intervention) error {
-	session, err := s.store.Get(intervention.SessionID)
+	session, err := s.getLive(intervention.SessionID)
 	if err != nil {
 		return ErrSessionNotFound
 	}
@@ -465,6 +987,7 @@ func (s *Service) RecordIntervention(ctx context.Context, intervention *Interven
 	}
 
 	session.RecordIntervention()
+	session.SpendBudget(intervention.Level)
 
 	if err := s.store.Save(session); err != nil {
 		return fmt.Errorf("save session: %w", err)
@@ -509,35 +1032,3 @@ func (s *Service) GetInterventions(ctx context.Context, sessionID string) ([]*In
 
 	return interventions, nil
 }
-
-// Helper functions
-
-func splitExerciseID(id string) []string {
-	var parts []string
-	current := ""
-	for _, c := range id {
-		if c == '/' {
-			if current != "" {
-				parts = append(parts, current)
-				current = ""
-			}
-		} else {
-			current += string(c)
-		}
-	}
-	if current != "" {
-		parts = append(parts, current)
-	}
-	return parts
-}
-
-func joinPath(parts ...string) string {
-	result := ""
-	for i, p := range parts {
-		if i > 0 {
-			result += "/"
-		}
-		result += p
-	}
-	return result
-}
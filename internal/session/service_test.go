@@ -2,6 +2,7 @@ package session
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -20,6 +21,11 @@ type mockExecutor struct {
 	buildErr     error
 	testResult   *runner.TestResult
 	testErr      error
+	goVersion    string           // toolchain the last build was asked for
+	overrides    runner.Overrides // project overrides the last build was asked for
+	scope        string           // package scope the last build was asked for
+	buildEnv     domain.BuildEnv  // build environment the last build was asked for
+	language     runner.Language  // language the last build was asked for
 }
 
 func (m *mockExecutor) RunFormat(ctx context.Context, code map[string]string) (*runner.FormatResult, error) {
@@ -37,6 +43,11 @@ func (m *mockExecutor) RunFormatFix(ctx context.Context, code map[string]string)
 }
 
 func (m *mockExecutor) RunBuild(ctx context.Context, code map[string]string) (*runner.BuildResult, error) {
+	m.goVersion = runner.GoVersionFromContext(ctx)
+	m.overrides = runner.OverridesFromContext(ctx)
+	m.scope = runner.ScopeFromContext(ctx)
+	m.buildEnv = runner.BuildEnvFromContext(ctx)
+	m.language = runner.LanguageFromContext(ctx)
 	if m.buildErr != nil {
 		return nil, m.buildErr
 	}
@@ -164,6 +175,33 @@ func TestService_Create_Training(t *testing.T) {
 	}
 }
 
+func TestService_Create_Training_OrgPack(t *testing.T) {
+	service, _, tmpDir := setupTestService(t)
+	ctx := context.Background()
+
+	// acme's test-pack has the same category/slug as the local one
+	packDir := filepath.Join(tmpDir, "exercises", "acme", "test-pack")
+	os.MkdirAll(filepath.Join(packDir, "basics"), 0755)
+	os.WriteFile(filepath.Join(packDir, "pack.yaml"), []byte("id: test-pack\norg: acme\nname: Acme\nlanguage: go\nexercises:\n  - basics/hello\n"), 0644)
+	os.WriteFile(filepath.Join(packDir, "basics", "hello.yaml"), []byte("id: hello\ntitle: Acme Hello\nstarter:\n  main.go: package acme\n"), 0644)
+
+	local, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	org, err := service.Create(ctx, CreateRequest{ExerciseID: "acme/test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() org pack error = %v", err)
+	}
+	if org.Code["main.go"] != "package acme" || local.Code["main.go"] == org.Code["main.go"] {
+		t.Errorf("org pack session code = %q, local = %q", org.Code["main.go"], local.Code["main.go"])
+	}
+
+	if _, err := service.Create(ctx, CreateRequest{ExerciseID: "a/b/test-pack/basics/hello"}); err == nil {
+		t.Error("Create() should refuse an ID with more than an org and a pack")
+	}
+}
+
 func TestService_Create_Training_NoExercise(t *testing.T) {
 	service, _, _ := setupTestService(t)
 	ctx := context.Background()
@@ -196,6 +234,54 @@ func TestService_Create_Greenfield(t *testing.T) {
 	}
 }
 
+func TestService_Create_DetectsLanguage(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	ctx := context.Background()
+
+	session, err := service.Create(ctx, CreateRequest{
+		Intent: IntentGreenfield,
+		Code: map[string]string{
+			"app.py":       "print('hi')",
+			"test_app.py":  "def test_app(): pass",
+			"tools/gen.go": "package tools",
+			"README.md":    "# app",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if session.Language != "python" {
+		t.Errorf("Language = %q; want python", session.Language)
+	}
+	if len(session.Languages) != 3 || session.Languages["tools/gen.go"] != "go" {
+		t.Errorf("Languages = %v; want the three source files", session.Languages)
+	}
+
+	// Runs go by the code they're given
+	if _, err := service.RunCode(ctx, session.ID, RunRequest{Build: true}); err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if got := service.executor.(*mockExecutor).language; got != runner.LanguagePython {
+		t.Errorf("run language = %q; want python", got)
+	}
+	if _, err := service.RunCode(ctx, session.ID, RunRequest{Code: map[string]string{"main.rs": "fn main() {}"}, Build: true}); err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if got := service.executor.(*mockExecutor).language; got != runner.LanguageRust {
+		t.Errorf("run language = %q; want rust", got)
+	}
+
+	// Training sessions keep their pack's language
+	training, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	training.UpdateCode(map[string]string{"a.py": "", "b.py": ""})
+	if training.Language != "go" {
+		t.Errorf("training Language = %q; want the pack's go", training.Language)
+	}
+}
+
 func TestService_Get(t *testing.T) {
 	service, _, _ := setupTestService(t)
 	ctx := context.Background()
@@ -235,14 +321,27 @@ func TestService_Delete(t *testing.T) {
 		ExerciseID: "test-pack/basics/hello",
 	})
 
-	// Delete it
-	if err := service.Delete(ctx, session.ID); err != nil {
-		t.Fatalf("Delete() error = %v", err)
+	// Delete it, twice: deletes are idempotent
+	for i := 0; i < 2; i++ {
+		if err := service.Delete(ctx, session.ID); err != nil {
+			t.Fatalf("Delete() #%d error = %v", i+1, err)
+		}
+	}
+
+	// Gone from the API, kept as a tombstone
+	if _, err := service.Get(ctx, session.ID); err != ErrSessionNotFound {
+		t.Errorf("Get() after delete error = %v; want ErrSessionNotFound", err)
+	}
+	tombstone, err := store.Get(session.ID)
+	if err != nil {
+		t.Fatalf("store.Get() error = %v; tombstone should remain", err)
+	}
+	if tombstone.Status != StatusDeleted || tombstone.DeletedAt == nil {
+		t.Errorf("tombstone status = %q, deleted_at = %v", tombstone.Status, tombstone.DeletedAt)
 	}
 
-	// Verify deleted
-	if store.Exists(session.ID) {
-		t.Error("Session should be deleted")
+	if err := service.Delete(ctx, "no-such-session"); err != ErrSessionNotFound {
+		t.Errorf("Delete() unknown session error = %v; want ErrSessionNotFound", err)
 	}
 }
 
@@ -359,6 +458,83 @@ func TestService_RunCode(t *testing.T) {
 	}
 }
 
+func TestService_RunCode_PackGoVersion(t *testing.T) {
+	service, _, tmpDir := setupTestService(t)
+	ctx := context.Background()
+
+	packYAML := `id: test-pack
+name: Test Pack
+language: go
+go_version: "1.23"
+exercises:
+  - basics/hello
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "exercises", "test-pack", "pack.yaml"), []byte(packYAML), 0644); err != nil {
+		t.Fatalf("failed to write pack.yaml: %v", err)
+	}
+
+	session, _ := service.Create(ctx, CreateRequest{
+		ExerciseID: "test-pack/basics/hello",
+	})
+	if _, err := service.RunCode(ctx, session.ID, RunRequest{Build: true}); err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+
+	if got := service.executor.(*mockExecutor).goVersion; got != "1.23" {
+		t.Errorf("executor toolchain = %q; want 1.23", got)
+	}
+}
+
+func TestService_RunCode_BuildEnv(t *testing.T) {
+	service, _, tmpDir := setupTestService(t)
+	ctx := context.Background()
+
+	exerciseYAML := `id: basics/hello
+title: Hello World
+check_recipe:
+  build: true
+  env:
+    TZ: UTC
+    APP_MODE: classic
+  build_tags: [integration]
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "exercises", "test-pack", "basics", "hello.yaml"), []byte(exerciseYAML), 0644); err != nil {
+		t.Fatalf("failed to write hello.yaml: %v", err)
+	}
+
+	if _, err := service.Create(ctx, CreateRequest{
+		ExerciseID: "test-pack/basics/hello",
+		BuildEnv:   domain.BuildEnv{Env: map[string]string{"PATH": "/tmp"}},
+	}); !errors.Is(err, ErrInvalidBuildEnv) {
+		t.Errorf("Create() error = %v; want ErrInvalidBuildEnv", err)
+	}
+
+	session, err := service.Create(ctx, CreateRequest{
+		ExerciseID: "test-pack/basics/hello",
+		BuildEnv: domain.BuildEnv{
+			Env:       map[string]string{"TZ": "Asia/Tokyo"},
+			TestFlags: []string{"-count=1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := service.RunCode(ctx, session.ID, RunRequest{Build: true}); err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+
+	got := service.executor.(*mockExecutor).buildEnv
+	if got.Env["TZ"] != "Asia/Tokyo" || got.Env["APP_MODE"] != "classic" {
+		t.Errorf("executor env = %v; want the session's TZ over the exercise's", got.Env)
+	}
+	if len(got.BuildTags) != 1 || got.BuildTags[0] != "integration" {
+		t.Errorf("executor build tags = %v", got.BuildTags)
+	}
+	if len(got.TestFlags) != 1 || got.TestFlags[0] != "-count=1" {
+		t.Errorf("executor test flags = %v", got.TestFlags)
+	}
+}
+
 func TestService_RunCode_NotFound(t *testing.T) {
 	service, _, _ := setupTestService(t)
 	ctx := context.Background()
@@ -494,51 +670,6 @@ func TestService_GetInterventions(t *testing.T) {
 	}
 }
 
-func TestSplitExerciseID(t *testing.T) {
-	tests := []struct {
-		exerciseID string
-		wantParts  []string
-	}{
-		{"go-v1/basics/hello", []string{"go-v1", "basics", "hello"}},
-		{"pack/category/exercise", []string{"pack", "category", "exercise"}},
-		{"single", []string{"single"}},
-		{"", []string{}},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.exerciseID, func(t *testing.T) {
-			parts := splitExerciseID(tt.exerciseID)
-			if len(parts) != len(tt.wantParts) {
-				t.Errorf("splitExerciseID() returned %d parts; want %d", len(parts), len(tt.wantParts))
-				return
-			}
-			for i, p := range parts {
-				if p != tt.wantParts[i] {
-					t.Errorf("parts[%d] = %q; want %q", i, p, tt.wantParts[i])
-				}
-			}
-		})
-	}
-}
-
-func TestJoinPath(t *testing.T) {
-	tests := []struct {
-		parts []string
-		want  string
-	}{
-		{[]string{"a", "b", "c"}, "a/b/c"},
-		{[]string{"single"}, "single"},
-		{[]string{}, ""},
-	}
-
-	for _, tt := range tests {
-		got := joinPath(tt.parts...)
-		if got != tt.want {
-			t.Errorf("joinPath(%v) = %q; want %q", tt.parts, got, tt.want)
-		}
-	}
-}
-
 func TestService_Create_FeatureGuidance_NoSpec(t *testing.T) {
 	service, _, _ := setupTestService(t)
 	ctx := context.Background()
@@ -778,3 +909,209 @@ func TestService_Complete_NotFound(t *testing.T) {
 		t.Errorf("Complete() error = %v; want ErrSessionNotFound", err)
 	}
 }
+
+func writeDebuggingExercise(t *testing.T, tmpDir string) {
+	t.Helper()
+	exerciseYAML := `id: basics/broken
+title: Broken
+type: debugging
+debugging:
+  narrative: The last page is missing
+  bugs:
+    - id: page-count
+      file: main.go
+      function: PageCount
+      root_cause: integer division truncates
+      keywords: [round up, integer division]
+starter:
+  main.go: |
+    package main
+    func main() {}
+`
+	path := filepath.Join(tmpDir, "exercises", "test-pack", "basics", "broken.yaml")
+	if err := os.WriteFile(path, []byte(exerciseYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestService_SubmitRootCause(t *testing.T) {
+	service, store, tmpDir := setupTestService(t)
+	writeDebuggingExercise(t, tmpDir)
+	ctx := context.Background()
+
+	session, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/broken"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	answer := []domain.RootCauseAnswer{{File: "main.go", Explanation: "Integer division drops the remainder"}}
+
+	// Correct answer without a green run does not complete the session
+	result, err := service.SubmitRootCause(ctx, session.ID, answer)
+	if err != nil {
+		t.Fatalf("SubmitRootCause() error = %v", err)
+	}
+	if !result.Verdict.Identified || result.TestsPassed || result.Completed {
+		t.Errorf("result = %+v; want identified but not completed", result)
+	}
+
+	if _, err := service.RunCode(ctx, session.ID, RunRequest{Test: true}); err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+
+	result, err = service.SubmitRootCause(ctx, session.ID, answer)
+	if err != nil {
+		t.Fatalf("SubmitRootCause() error = %v", err)
+	}
+	if !result.Completed {
+		t.Errorf("result = %+v; want completed", result)
+	}
+
+	loaded, _ := store.Get(session.ID)
+	if loaded.Status != StatusCompleted {
+		t.Error("Completed status should be persisted")
+	}
+}
+
+func TestService_SubmitRootCause_WrongAnswer(t *testing.T) {
+	service, _, tmpDir := setupTestService(t)
+	writeDebuggingExercise(t, tmpDir)
+	ctx := context.Background()
+
+	session, _ := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/broken"})
+	service.RunCode(ctx, session.ID, RunRequest{Test: true})
+
+	result, err := service.SubmitRootCause(ctx, session.ID, []domain.RootCauseAnswer{
+		{File: "main.go", Explanation: "the loop is wrong"},
+	})
+	if err != nil {
+		t.Fatalf("SubmitRootCause() error = %v", err)
+	}
+	if result.Verdict.Identified || result.Completed {
+		t.Errorf("result = %+v; want not identified", result)
+	}
+	if result.Verdict.Missing != 1 {
+		t.Errorf("Missing = %d; want 1", result.Verdict.Missing)
+	}
+}
+
+func TestService_SubmitRootCause_NotDebugging(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	ctx := context.Background()
+
+	session, _ := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+
+	_, err := service.SubmitRootCause(ctx, session.ID, []domain.RootCauseAnswer{{File: "main.go", Explanation: "x"}})
+	if err != ErrNotDebugging {
+		t.Errorf("SubmitRootCause() error = %v; want ErrNotDebugging", err)
+	}
+}
+
+func TestService_RunsForSpec(t *testing.T) {
+	service, store, _ := setupTestService(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	store.Save(&Session{ID: "s-auth", SpecPath: ".specs/auth.yaml", CreatedAt: now})
+	store.Save(&Session{ID: "s-billing", SpecPath: ".specs/billing.yaml", CreatedAt: now})
+	store.SaveRun(&Run{ID: "run-old", SessionID: "s-auth", CreatedAt: now.Add(-time.Hour)})
+	store.SaveRun(&Run{ID: "run-new", SessionID: "s-auth", CreatedAt: now})
+	store.SaveRun(&Run{ID: "run-other", SessionID: "s-billing", CreatedAt: now})
+
+	runs, err := service.RunsForSpec(ctx, "./.specs/auth.yaml")
+	if err != nil {
+		t.Fatalf("RunsForSpec() error = %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("RunsForSpec() returned %d runs, want 2", len(runs))
+	}
+	if runs[0].ID != "run-new" || runs[1].ID != "run-old" {
+		t.Errorf("runs = [%s %s], want newest first", runs[0].ID, runs[1].ID)
+	}
+}
+
+type fakeExplainer struct {
+	packID string
+	errs   []string
+}
+
+func (f *fakeExplainer) ExplainErrors(ctx context.Context, packID string, paths []string, errs []string) ([]string, error) {
+	f.packID, f.errs = packID, errs
+	out := make([]string, len(errs))
+	for i := range errs {
+		out[i] = "explained by the LLM"
+	}
+	return out, nil
+}
+
+func TestService_RunCode_QuickFixes(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	ctx := context.Background()
+	service.executor.(*mockExecutor).buildResult = &runner.BuildResult{
+		OK:     false,
+		Output: "./main.go:3:15: undefined: strings\n",
+	}
+	code := map[string]string{"main.go": "package main\n\nfunc main() { strings.ToUpper(\"a\") }\n"}
+
+	sess, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	run, err := service.RunCode(ctx, sess.ID, RunRequest{Code: code, Build: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if got := run.Result.QuickFixes; len(got) != 1 || got[0].Kind != domain.QuickFixAddImport {
+		t.Fatalf("quick fixes = %+v, want the strings import", got)
+	}
+
+	// Below L2 a fix says more than the policy allows
+	policy := domain.DefaultPolicy()
+	policy.MaxLevel = domain.L1CategoryHint
+	sess, _ = service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello", Policy: &policy})
+	run, _ = service.RunCode(ctx, sess.ID, RunRequest{Code: code, Build: true})
+	if len(run.Result.QuickFixes) != 0 {
+		t.Errorf("quick fixes = %+v, want none below L2", run.Result.QuickFixes)
+	}
+}
+
+func TestService_RunCode_Explain(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	ctx := context.Background()
+	service.executor.(*mockExecutor).buildResult = &runner.BuildResult{
+		OK:     false,
+		Output: "./main.go:5:2: declared and not used: x\n./main.go:7:2: some brand new compiler error\n",
+	}
+
+	sess, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Without an explainer, only the rule table applies
+	run, err := service.RunCode(ctx, sess.ID, RunRequest{Build: true, Explain: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if got := run.Result.Explanations; len(got) != 1 || got[0].Rule != "unused_variable" {
+		t.Fatalf("explanations = %+v, want the unused variable only", got)
+	}
+	if run.Result.BuildOutput == "" {
+		t.Error("raw build output should be kept")
+	}
+
+	explainer := &fakeExplainer{}
+	service.SetErrorExplainer(explainer)
+	run, _ = service.RunCode(ctx, sess.ID, RunRequest{Build: true, Explain: true})
+	if got := run.Result.Explanations; len(got) != 2 || got[1].Source != domain.ExplanationLLM {
+		t.Fatalf("explanations = %+v, want the second from the LLM", got)
+	}
+	if explainer.packID != "test-pack" || len(explainer.errs) != 1 {
+		t.Errorf("explainer got pack %q, errors %q", explainer.packID, explainer.errs)
+	}
+
+	run, _ = service.RunCode(ctx, sess.ID, RunRequest{Build: true})
+	if len(run.Result.Explanations) != 0 {
+		t.Error("explanations are opt-in")
+	}
+}
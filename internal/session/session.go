@@ -1,9 +1,12 @@
 package session
 
 import (
+	"path/filepath"
 	"time"
 
+	"github.com/felixgeelhaar/temper/internal/analysis"
 	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/runner"
 	"github.com/google/uuid"
 )
 
@@ -29,19 +32,59 @@ type Session struct {
 	Intent   SessionIntent `json:"intent"`
 	SpecPath string        `json:"spec_path,omitempty"`
 
+	// Language is what the session's code is built and tested as: the
+	// exercise pack's language, or else the one most files are in.
+	// Languages has each file's, for editors and prompts.
+	Language  string            `json:"language,omitempty"`
+	Languages map[string]string `json:"languages,omitempty"`
+
+	// WorkspaceRoot is the project the session was started in. Its
+	// .temper.yaml, if any, is layered over the global config.
+	WorkspaceRoot string `json:"workspace_root,omitempty"`
+
+	// Scope is the directory of a monorepo a feature guidance session
+	// works in. Runs, hint context and the session's specs stay inside it.
+	Scope string `json:"scope,omitempty"`
+
+	// BuildEnv adds variables, build tags and test flags to the session's
+	// runs, on top of those of its exercise
+	BuildEnv *domain.BuildEnv `json:"build_env,omitempty"`
+
+	// Assignment is set on sessions an instructor created for a cohort
+	// member
+	Assignment *Assignment `json:"assignment,omitempty"`
+
 	// Authoring-specific fields (for spec_authoring intent)
 	AuthoringDocs    []string `json:"authoring_docs,omitempty"`    // paths to discovered docs
 	AuthoringSection string   `json:"authoring_section,omitempty"` // current section being authored
+	AuthoringSpecs   []string `json:"authoring_specs,omitempty"`   // every spec file in the session, SpecPath first
+
+	// Exercise version the session started from (training intent)
+	ExerciseBaseline *ExerciseBaseline `json:"exercise_baseline,omitempty"`
+
+	// Approach compares the finished code with the exercise's reference
+	// solution and alternatives; set on completion
+	Approach *analysis.ApproachReport `json:"approach,omitempty"`
 
 	// Statistics
 	RunCount           int        `json:"run_count"`
 	HintCount          int        `json:"hint_count"`
+	BudgetSpent        int        `json:"budget_spent,omitempty"` // hint budget tokens used so far
 	LastRunAt          *time.Time `json:"last_run_at,omitempty"`
 	LastInterventionAt *time.Time `json:"last_intervention_at,omitempty"`
 
+	// Activity. Editors send heartbeats while the session is open; a
+	// session quiet for long enough is idle, and its cooldown stops
+	// counting down until the learner is back.
+	LastSeenAt     *time.Time    `json:"last_seen_at,omitempty"`    // latest heartbeat
+	IdleSince      *time.Time    `json:"idle_since,omitempty"`      // set while idle
+	CooldownPaused time.Duration `json:"cooldown_paused,omitempty"` // idle time since the last hint
+	ExpiredAt      *time.Time    `json:"expired_at,omitempty"`      // set when expiry abandoned the session
+
 	// Timestamps
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // set on tombstones
 }
 
 // Status represents the session state
@@ -51,6 +94,9 @@ const (
 	StatusActive    Status = "active"
 	StatusCompleted Status = "completed"
 	StatusAbandoned Status = "abandoned"
+	// StatusDeleted marks a tombstone: the session is gone from the API
+	// but kept so its runs and analytics still resolve
+	StatusDeleted Status = "deleted"
 )
 
 // Run represents a code execution within a session
@@ -64,14 +110,63 @@ type Run struct {
 
 // RunResult contains the outcome of a run
 type RunResult struct {
-	FormatOK    bool                `json:"format_ok"`
-	FormatDiff  string              `json:"format_diff,omitempty"`
-	BuildOK     bool                `json:"build_ok"`
-	BuildOutput string              `json:"build_output,omitempty"`
-	TestOK      bool                `json:"test_ok"`
-	TestOutput  string              `json:"test_output,omitempty"`
-	Duration    time.Duration       `json:"duration"`
-	Risks       []domain.RiskNotice `json:"risks,omitempty"`
+	FormatOK    bool                  `json:"format_ok"`
+	FormatDiff  string                `json:"format_diff,omitempty"`
+	BuildOK     bool                  `json:"build_ok"`
+	BuildOutput string                `json:"build_output,omitempty"`
+	TestOK      bool                  `json:"test_ok"`
+	TestOutput  string                `json:"test_output,omitempty"`
+	Tests       []domain.TestResult   `json:"tests,omitempty"` // per-test outcomes parsed from TestOutput
+	Duration    time.Duration         `json:"duration"`
+	Risks       []domain.RiskNotice   `json:"risks,omitempty"`
+	Debug       *domain.DebugSnapshot `json:"debug,omitempty"`
+
+	// Runs of the program with scripted stdin: the run's own input and
+	// the exercise's stdin cases
+	Programs []ProgramRun `json:"programs,omitempty"`
+
+	// The program run as a server and checked with the exercise's probes
+	Server *ServerRun `json:"server,omitempty"`
+
+	// The exercise's fuzz target, fuzzed once the tests passed
+	Fuzz *FuzzRun `json:"fuzz,omitempty"`
+
+	// The project's custom command, when the run asked for one instead
+	// of format, build and test
+	Command *CommandRun `json:"command,omitempty"`
+
+	// Files the exercise declares as artifacts, downloadable from the run
+	Artifacts *ArtifactRun `json:"artifacts,omitempty"`
+
+	// Imports rejected by the runner's dependency allowlist
+	DependencyViolations []string `json:"dependency_violations,omitempty"`
+
+	// Build or test output too long to keep here: the fields above hold
+	// its start and end, and the output endpoint serves it whole
+	SpilledOutput []SpilledOutput `json:"spilled_output,omitempty"`
+
+	// One-click fixes for the mechanical compile errors above
+	QuickFixes []domain.QuickFix `json:"quick_fixes,omitempty"`
+
+	// Beginner-friendly rewrites of the errors above, when requested
+	Explanations []domain.ErrorExplanation `json:"explanations,omitempty"`
+
+	// Explanations of failed tests, kept so asking again is free
+	TestExplanations []domain.TestExplanation `json:"test_explanations,omitempty"`
+
+	// Set on runs of an assignment after its deadline, grace included
+	Late bool `json:"late,omitempty"`
+}
+
+// FailedTests returns the names of the tests that failed in this run
+func (r *RunResult) FailedTests() []string {
+	var names []string
+	for _, t := range r.Tests {
+		if !t.Passed {
+			names = append(names, t.Name)
+		}
+	}
+	return names
 }
 
 // Intervention represents an AI intervention within a session
@@ -84,6 +179,12 @@ type Intervention struct {
 	Type      domain.InterventionType  `json:"type"`
 	Content   string                   `json:"content"`
 	CreatedAt time.Time                `json:"created_at"`
+
+	// Redactions applied to the prompt before it reached the LLM
+	Redactions []domain.Redaction `json:"redactions,omitempty"`
+
+	// Glossary concept IDs the content touches on
+	Concepts []string `json:"concepts,omitempty"`
 }
 
 // NewSession creates a new session for an exercise (training intent)
@@ -146,6 +247,42 @@ func NewAuthoringSession(specPath string, docPaths []string, policy domain.Learn
 	}
 }
 
+// SpecFiles returns the spec files an authoring session works on
+func (s *Session) SpecFiles() []string {
+	if len(s.AuthoringSpecs) > 0 {
+		return s.AuthoringSpecs
+	}
+	if s.SpecPath != "" {
+		return []string{s.SpecPath}
+	}
+	return nil
+}
+
+// SpecFile resolves the spec file a request targets: the named file if
+// the session holds it, or SpecPath when file is empty
+func (s *Session) SpecFile(file string) (string, bool) {
+	if file == "" {
+		return s.SpecPath, s.SpecPath != ""
+	}
+	for _, path := range s.SpecFiles() {
+		if filepath.Clean(path) == filepath.Clean(file) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// AddSpecFile adds a spec file to an authoring session; it reports false
+// if the session already holds it
+func (s *Session) AddSpecFile(path string) bool {
+	if _, ok := s.SpecFile(path); ok {
+		return false
+	}
+	s.AuthoringSpecs = append(s.SpecFiles(), path)
+	s.UpdatedAt = time.Now()
+	return true
+}
+
 // SetAuthoringSection updates the current section being authored
 func (s *Session) SetAuthoringSection(section string) {
 	s.AuthoringSection = section
@@ -157,12 +294,47 @@ func (s *Session) IsAuthoring() bool {
 	return s.Intent == IntentSpecAuthoring
 }
 
+// ExerciseVariant returns the exercise variant the session started on,
+// or "" for the exercise as written
+func (s *Session) ExerciseVariant() domain.VariantKind {
+	if s.ExerciseBaseline == nil {
+		return ""
+	}
+	return s.ExerciseBaseline.Variant
+}
+
 // UpdateCode updates the session's code
 func (s *Session) UpdateCode(code map[string]string) {
 	s.Code = code
+	s.detectLanguages()
 	s.UpdatedAt = time.Now()
 }
 
+// detectLanguages records the language of each file and, unless the
+// exercise pack set it, the session's language
+func (s *Session) detectLanguages() {
+	s.Languages = nil
+	for path, lang := range runner.DetectLanguages(s.Code) {
+		if s.Languages == nil {
+			s.Languages = make(map[string]string)
+		}
+		s.Languages[path] = string(lang)
+	}
+	if s.ExerciseID == "" {
+		s.Language = string(runner.PrimaryLanguage(s.Code))
+	}
+}
+
+// runLanguage returns the language to run code in for the session.
+// Sessions without an exercise go by the code itself, which may not be
+// saved to the session yet.
+func (s *Session) runLanguage(code map[string]string) runner.Language {
+	if s.ExerciseID != "" {
+		return runner.Language(s.Language)
+	}
+	return runner.PrimaryLanguage(code)
+}
+
 // RecordRun records that a run was executed
 func (s *Session) RecordRun() {
 	now := time.Now()
@@ -176,9 +348,59 @@ func (s *Session) RecordIntervention() {
 	now := time.Now()
 	s.HintCount++
 	s.LastInterventionAt = &now
+	s.CooldownPaused = 0
 	s.UpdatedAt = now
 }
 
+// SpendBudget takes the cost of a hint at level from the session's hint
+// budget. Sessions without a budget spend nothing.
+func (s *Session) SpendBudget(level domain.InterventionLevel) {
+	if s.Policy.Budget.Enabled() {
+		s.BudgetSpent += s.Policy.Budget.Cost(level)
+	}
+}
+
+// BudgetState is the session's hint budget as editors show it. NextLevel
+// is the highest level the remaining tokens pay for.
+type BudgetState struct {
+	Enabled   bool                     `json:"enabled"`
+	Tokens    int                      `json:"tokens,omitempty"`
+	Spent     int                      `json:"spent,omitempty"`
+	Remaining int                      `json:"remaining,omitempty"`
+	Costs     []int                    `json:"costs,omitempty"` // per level from L0
+	Exhausted bool                     `json:"exhausted,omitempty"`
+	NextLevel domain.InterventionLevel `json:"next_level"`
+}
+
+// Budget returns the session's current hint budget state
+func (s *Session) Budget() BudgetState {
+	budget := s.Policy.Budget
+	state := BudgetState{Enabled: budget.Enabled(), NextLevel: s.Policy.MaxLevel}
+	if !state.Enabled {
+		return state
+	}
+	state.Tokens = budget.Tokens
+	state.Spent = s.BudgetSpent
+	state.Remaining = budget.Remaining(s.BudgetSpent)
+	for level := domain.L0Clarify; level <= domain.L5FullSolution; level++ {
+		state.Costs = append(state.Costs, budget.Cost(level))
+	}
+	next, ok := budget.Affordable(s.Policy.MaxLevel, state.Remaining)
+	state.NextLevel = next
+	state.Exhausted = !ok
+	return state
+}
+
+// BudgetUnused returns the fraction of the hint budget left, 0 for a
+// session without a budget
+func (s *Session) BudgetUnused() float64 {
+	budget := s.Policy.Budget
+	if !budget.Enabled() {
+		return 0
+	}
+	return float64(budget.Remaining(s.BudgetSpent)) / float64(budget.Tokens)
+}
+
 // Complete marks the session as completed
 func (s *Session) Complete() {
 	s.Status = StatusCompleted
@@ -214,7 +436,7 @@ func (s *Session) CooldownRemaining() time.Duration {
 	}
 
 	cooldown := time.Duration(s.Policy.CooldownSeconds) * time.Second
-	elapsed := time.Since(*s.LastInterventionAt)
+	elapsed := time.Since(*s.LastInterventionAt) - s.cooldownPaused(time.Now())
 
 	if elapsed >= cooldown {
 		return 0
@@ -222,3 +444,52 @@ func (s *Session) CooldownRemaining() time.Duration {
 
 	return cooldown - elapsed
 }
+
+// cooldownPaused is how much of the time since the last hint the session
+// spent idle, up to now
+func (s *Session) cooldownPaused(now time.Time) time.Duration {
+	paused := s.CooldownPaused
+	if s.IdleSince != nil && s.LastInterventionAt != nil {
+		since := *s.IdleSince
+		if since.Before(*s.LastInterventionAt) {
+			since = *s.LastInterventionAt
+		}
+		if now.After(since) {
+			paused += now.Sub(since)
+		}
+	}
+	return paused
+}
+
+// CooldownState is the session's cooldown as editors show it. While the
+// cooldown is active the daemon refuses pairing requests; NextLevel is the
+// highest level that can be requested once it ends. A paused cooldown
+// has no end until the idle session is resumed.
+type CooldownState struct {
+	Active           bool                     `json:"active"`
+	Paused           bool                     `json:"paused,omitempty"`
+	RemainingSeconds float64                  `json:"remaining_seconds"`
+	EndsAt           *time.Time               `json:"ends_at,omitempty"`
+	CooldownSeconds  int                      `json:"cooldown_seconds"`
+	NextLevel        domain.InterventionLevel `json:"next_level"`
+}
+
+// Cooldown returns the session's current cooldown state
+func (s *Session) Cooldown() CooldownState {
+	state := CooldownState{
+		CooldownSeconds: s.Policy.CooldownSeconds,
+		NextLevel:       s.Policy.MaxLevel,
+	}
+	remaining := s.CooldownRemaining()
+	if remaining > 0 {
+		state.Active = true
+		state.RemainingSeconds = remaining.Seconds()
+		if s.IdleSince != nil {
+			state.Paused = true
+			return state
+		}
+		ends := s.LastInterventionAt.Add(time.Duration(s.Policy.CooldownSeconds)*time.Second + s.CooldownPaused)
+		state.EndsAt = &ends
+	}
+	return state
+}
@@ -105,6 +105,31 @@ func TestSession_AuthoringHelpers(t *testing.T) {
 	}
 }
 
+func TestSession_SpecFiles(t *testing.T) {
+	s := NewAuthoringSession(".specs/epic.yaml", []string{}, domain.DefaultPolicy())
+
+	if got, ok := s.SpecFile(""); !ok || got != ".specs/epic.yaml" {
+		t.Errorf("SpecFile(\"\") = %q, %v; want the session's spec", got, ok)
+	}
+	if !s.AddSpecFile(".specs/billing.yaml") {
+		t.Error("AddSpecFile() should add a new file")
+	}
+	if s.AddSpecFile("./.specs/billing.yaml") {
+		t.Error("AddSpecFile() should ignore a file already held")
+	}
+
+	want := []string{".specs/epic.yaml", ".specs/billing.yaml"}
+	if got := s.SpecFiles(); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("SpecFiles() = %v; want %v", got, want)
+	}
+	if got, ok := s.SpecFile("./.specs/billing.yaml"); !ok || got != ".specs/billing.yaml" {
+		t.Errorf("SpecFile() = %q, %v; want the billing spec", got, ok)
+	}
+	if _, ok := s.SpecFile(".specs/other.yaml"); ok {
+		t.Error("SpecFile() should reject a file outside the session")
+	}
+}
+
 func TestSession_RecordRun(t *testing.T) {
 	s := NewSession("test", map[string]string{}, domain.DefaultPolicy())
 
@@ -136,6 +161,28 @@ func TestSession_RecordIntervention(t *testing.T) {
 	}
 }
 
+func TestSession_Budget(t *testing.T) {
+	s := NewSession("test", map[string]string{}, domain.DefaultPolicy())
+	s.SpendBudget(domain.L3ConstrainedSnippet)
+	if state := s.Budget(); state.Enabled || s.BudgetSpent != 0 || s.BudgetUnused() != 0 {
+		t.Errorf("session without a budget: %+v, spent %d", state, s.BudgetSpent)
+	}
+
+	policy := domain.DefaultPolicy()
+	policy.Budget = domain.HintBudget{Tokens: 4}
+	s = NewSession("test", map[string]string{}, policy)
+	s.SpendBudget(domain.L3ConstrainedSnippet)
+	s.SpendBudget(domain.L1CategoryHint)
+
+	state := s.Budget()
+	if state.Spent != 3 || state.Remaining != 1 || state.NextLevel != domain.L2LocationConcept || state.Exhausted {
+		t.Errorf("Budget() = %+v; want 3 spent, 1 left, next L2", state)
+	}
+	if s.BudgetUnused() != 0.25 {
+		t.Errorf("BudgetUnused() = %v; want 0.25", s.BudgetUnused())
+	}
+}
+
 func TestSession_Complete(t *testing.T) {
 	s := NewSession("test", map[string]string{}, domain.DefaultPolicy())
 
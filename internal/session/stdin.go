@@ -0,0 +1,115 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+// ErrStdinUnsupported is returned when a run asks for stdin but the
+// executor cannot run programs
+var ErrStdinUnsupported = errors.New("executor cannot run programs with stdin")
+
+// ProgramRun is one run of the learner's program with scripted stdin:
+// either the input sent with the run, or a stdin case of the exercise
+type ProgramRun struct {
+	Name     string  `json:"name,omitempty"`
+	Input    string  `json:"input"`
+	Output   string  `json:"output"`
+	ExitCode int     `json:"exit_code"`
+	TimedOut bool    `json:"timed_out,omitempty"`
+	Expected *string `json:"expected,omitempty"` // set for stdin cases
+	OK       bool    `json:"ok"`
+}
+
+// runPrograms runs the program with the run's own input, then, when the
+// run tests, with each stdin case of the exercise. A case passes when the
+// program exits cleanly and its output matches, ignoring trailing
+// whitespace on each line.
+func (s *Service) runPrograms(ctx context.Context, session *Session, code map[string]string, req RunRequest) ([]ProgramRun, error) {
+	var cases []domain.StdinCase
+	if req.Test {
+		cases = s.stdinCases(session)
+	}
+	if req.Stdin == nil && len(cases) == 0 {
+		return nil, nil
+	}
+
+	prog, ok := s.executor.(runner.ProgramRunner)
+	if !ok {
+		if req.Stdin != nil {
+			return nil, ErrStdinUnsupported
+		}
+		slog.Warn("executor cannot run programs; skipping stdin cases", "exercise", session.ExerciseID)
+		return nil, nil
+	}
+
+	var runs []ProgramRun
+	if req.Stdin != nil {
+		result, err := prog.RunProgram(ctx, code, *req.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("program run: %w", err)
+		}
+		runs = append(runs, ProgramRun{
+			Input:    *req.Stdin,
+			Output:   result.Output,
+			ExitCode: result.ExitCode,
+			TimedOut: result.TimedOut,
+			OK:       result.ExitCode == 0,
+		})
+	}
+	for _, c := range cases {
+		result, err := prog.RunProgram(ctx, code, c.Input)
+		if err != nil {
+			return nil, fmt.Errorf("stdin case %s: %w", c.Name, err)
+		}
+		expected := c.ExpectedOutput
+		runs = append(runs, ProgramRun{
+			Name:     c.Name,
+			Input:    c.Input,
+			Output:   result.Output,
+			ExitCode: result.ExitCode,
+			TimedOut: result.TimedOut,
+			Expected: &expected,
+			OK:       result.ExitCode == 0 && sameOutput(result.Output, expected),
+		})
+	}
+	return runs, nil
+}
+
+// stdinCases returns the stdin cases of the session's exercise
+func (s *Service) stdinCases(session *Session) []domain.StdinCase {
+	if recipe := s.checkRecipe(session); recipe != nil {
+		return recipe.StdinCases
+	}
+	return nil
+}
+
+// checkRecipe returns the check recipe of the session's exercise, or nil
+// for sessions without a known exercise
+func (s *Service) checkRecipe(session *Session) *domain.CheckRecipe {
+	ex, err := s.loader.LoadExerciseByID(session.ExerciseID)
+	if err != nil {
+		return nil
+	}
+	return &ex.CheckRecipe
+}
+
+// sameOutput compares program output line by line, ignoring trailing
+// whitespace and trailing blank lines
+func sameOutput(got, want string) bool {
+	return normalizeOutput(got) == normalizeOutput(want)
+}
+
+func normalizeOutput(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
@@ -0,0 +1,88 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/runner"
+)
+
+// programExecutor echoes stdin back, upper-cased, as the program's output
+type programExecutor struct {
+	mockExecutor
+	inputs []string
+}
+
+func (p *programExecutor) RunProgram(ctx context.Context, code map[string]string, stdin string) (*runner.ProgramResult, error) {
+	p.inputs = append(p.inputs, stdin)
+	return &runner.ProgramResult{Output: strings.ToUpper(stdin)}, nil
+}
+
+func TestService_RunCode_Stdin(t *testing.T) {
+	service, _, tmpDir := setupTestService(t)
+	ctx := context.Background()
+
+	exerciseYAML := `id: basics/hello
+title: Hello World
+check_recipe:
+  test: true
+  stdin_cases:
+    - name: greets
+      input: "ada\n"
+      expected_output: "ADA   \n\n"
+    - input: "bob\n"
+      expected_output: "Robert\n"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "exercises", "test-pack", "basics", "hello.yaml"), []byte(exerciseYAML), 0644); err != nil {
+		t.Fatalf("failed to write hello.yaml: %v", err)
+	}
+	session, err := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// The mock executor cannot run programs: input sent with the run is an
+	// error, while the exercise's cases are skipped
+	stdin := "hi\n"
+	if _, err := service.RunCode(ctx, session.ID, RunRequest{Stdin: &stdin}); !errors.Is(err, ErrStdinUnsupported) {
+		t.Errorf("RunCode() error = %v; want ErrStdinUnsupported", err)
+	}
+	run, err := service.RunCode(ctx, session.ID, RunRequest{Test: true})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+	if len(run.Result.Programs) != 0 || !run.Result.TestOK {
+		t.Errorf("programs = %v, test ok = %v; want no programs and passing tests", run.Result.Programs, run.Result.TestOK)
+	}
+
+	exec := &programExecutor{}
+	service.executor = exec
+	run, err = service.RunCode(ctx, session.ID, RunRequest{Test: true, Stdin: &stdin})
+	if err != nil {
+		t.Fatalf("RunCode() error = %v", err)
+	}
+
+	if got := strings.Join(exec.inputs, "|"); got != "hi\n|ada\n|bob\n" {
+		t.Errorf("program inputs = %q", got)
+	}
+	programs := run.Result.Programs
+	if len(programs) != 3 {
+		t.Fatalf("programs = %d; want 3", len(programs))
+	}
+	if programs[0].Expected != nil || !programs[0].OK || programs[0].Output != "HI\n" {
+		t.Errorf("ad-hoc program = %+v", programs[0])
+	}
+	if programs[1].Name != "greets" || !programs[1].OK {
+		t.Errorf("case greets = %+v; want it to pass despite trailing whitespace", programs[1])
+	}
+	if programs[2].Name != "case 2" || programs[2].OK {
+		t.Errorf("case 2 = %+v; want it to fail", programs[2])
+	}
+	if run.Result.TestOK {
+		t.Error("TestOK = true; want a failing stdin case to fail the tests")
+	}
+}
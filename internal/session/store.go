@@ -3,6 +3,7 @@ package session
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/felixgeelhaar/temper/internal/storage/local"
 )
@@ -48,7 +49,7 @@ func (s *Store) Get(id string) (*Session, error) {
 	return &session, nil
 }
 
-// Delete removes a session
+// Delete removes a session along with its runs and interventions
 func (s *Store) Delete(id string) error {
 	if err := s.store.Delete(collectionSessions, id); err != nil {
 		if errors.Is(err, local.ErrNotFound) {
@@ -56,7 +57,22 @@ func (s *Store) Delete(id string) error {
 		}
 		return err
 	}
-	return nil
+	return s.store.RemoveDirs(collectionSessions, id)
+}
+
+// Tombstone marks a session deleted, keeping its runs and interventions
+func (s *Store) Tombstone(id string, at time.Time) error {
+	session, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if session.Status == StatusDeleted {
+		return nil
+	}
+	session.Status = StatusDeleted
+	session.DeletedAt = &at
+	session.UpdatedAt = at
+	return s.Save(session)
 }
 
 // List returns all session IDs
@@ -107,6 +123,17 @@ func (s *Store) ListRuns(sessionID string) ([]string, error) {
 	return s.store.ListDir(collectionSessions, sessionID, subdirRuns)
 }
 
+// DeleteRun removes a run from a session
+func (s *Store) DeleteRun(sessionID, runID string) error {
+	if err := s.store.DeleteDir(collectionSessions, sessionID, subdirRuns, runID); err != nil {
+		if errors.Is(err, local.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
 // SaveIntervention persists an intervention within a session
 func (s *Store) SaveIntervention(intervention *Intervention) error {
 	return s.store.SaveDir(collectionSessions, intervention.SessionID, subdirInterventions, intervention.ID, intervention)
@@ -2,6 +2,7 @@ package session
 
 import (
 	"testing"
+	"time"
 
 	"github.com/felixgeelhaar/temper/internal/domain"
 )
@@ -85,6 +86,34 @@ func TestStore_Delete_NotFound(t *testing.T) {
 	}
 }
 
+func TestStore_Tombstone(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewStore(tmpDir)
+
+	session := NewSession("test", map[string]string{}, domain.DefaultPolicy())
+	store.Save(session)
+
+	at := time.Now()
+	if err := store.Tombstone(session.ID, at); err != nil {
+		t.Fatalf("Tombstone() error = %v", err)
+	}
+	if err := store.Tombstone(session.ID, at.Add(time.Hour)); err != nil {
+		t.Fatalf("Tombstone() twice error = %v", err)
+	}
+
+	got, err := store.Get(session.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status != StatusDeleted || got.DeletedAt == nil || !got.DeletedAt.Equal(at) {
+		t.Errorf("tombstone status = %q, deleted_at = %v; want deleted at %v", got.Status, got.DeletedAt, at)
+	}
+
+	if err := store.Tombstone("nonexistent", at); err != ErrNotFound {
+		t.Errorf("Tombstone() unknown error = %v; want ErrNotFound", err)
+	}
+}
+
 func TestStore_List(t *testing.T) {
 	tmpDir := t.TempDir()
 	store, _ := NewStore(tmpDir)
@@ -217,6 +246,41 @@ func TestStore_ListRuns(t *testing.T) {
 	}
 }
 
+func TestStore_DeleteRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewStore(tmpDir)
+
+	session := NewSession("test", map[string]string{}, domain.DefaultPolicy())
+	store.Save(session)
+	store.SaveRun(&Run{ID: "run-1", SessionID: session.ID})
+
+	if err := store.DeleteRun(session.ID, "run-1"); err != nil {
+		t.Fatalf("DeleteRun() error = %v", err)
+	}
+	if _, err := store.GetRun(session.ID, "run-1"); err != ErrNotFound {
+		t.Errorf("GetRun() after delete error = %v; want ErrNotFound", err)
+	}
+	if err := store.DeleteRun(session.ID, "run-1"); err != ErrNotFound {
+		t.Errorf("DeleteRun() twice error = %v; want ErrNotFound", err)
+	}
+}
+
+func TestStore_Delete_RemovesRuns(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewStore(tmpDir)
+
+	session := NewSession("test", map[string]string{}, domain.DefaultPolicy())
+	store.Save(session)
+	store.SaveRun(&Run{ID: "run-1", SessionID: session.ID})
+
+	if err := store.Delete(session.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if ids, _ := store.ListRuns(session.ID); len(ids) != 0 {
+		t.Errorf("runs left behind after Delete(): %v", ids)
+	}
+}
+
 func TestStore_SaveIntervention_GetIntervention(t *testing.T) {
 	tmpDir := t.TempDir()
 	store, _ := NewStore(tmpDir)
@@ -229,6 +293,7 @@ func TestStore_SaveIntervention_GetIntervention(t *testing.T) {
 		SessionID: session.ID,
 		Level:     domain.L1CategoryHint,
 		Content:   "Try using a loop",
+		Concepts:  []string{"loops"},
 	}
 
 	// Save
@@ -248,6 +313,9 @@ func TestStore_SaveIntervention_GetIntervention(t *testing.T) {
 	if loaded.Content != intervention.Content {
 		t.Errorf("Intervention Content = %q; want %q", loaded.Content, intervention.Content)
 	}
+	if len(loaded.Concepts) != 1 || loaded.Concepts[0] != "loops" {
+		t.Errorf("Intervention Concepts = %v; want [loops]", loaded.Concepts)
+	}
 }
 
 func TestStore_GetIntervention_NotFound(t *testing.T) {
@@ -0,0 +1,182 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Stuck nudge reasons
+const (
+	NudgeRepeatedFailures = "repeated_failures"
+	NudgeIdleAfterFailure = "idle_after_failure"
+)
+
+const (
+	// stuckDiffLines is the most lines a run may add and delete, together,
+	// and still count as a near-identical retry of the run before it
+	stuckDiffLines = 4
+
+	// stuckIdleHorizon bounds the idle check: a learner quiet for this long
+	// past the threshold has probably walked away, and a nudge on their
+	// return would be stale
+	stuckIdleHorizon = time.Hour
+)
+
+// Nudge is a gentle prompt for a learner who seems stuck. It suggests a
+// way forward and never contains code or a solution.
+type Nudge struct {
+	SessionID   string    `json:"session_id"`
+	Reason      string    `json:"reason"` // repeated_failures or idle_after_failure
+	Message     string    `json:"message"`
+	RunID       string    `json:"run_id"`       // the latest failing run
+	FailingRuns int       `json:"failing_runs"` // consecutive failing runs up to it
+	At          time.Time `json:"at"`
+
+	since time.Time // the earliest run the pattern rests on
+}
+
+// DetectStuck looks for signs the learner is going in circles: the
+// policy's number of consecutive failing runs with only small changes
+// between them, or a long quiet spell after a failing run. It returns nil
+// when nothing looks wrong or detection is off for the session.
+func DetectStuck(sess *Session, runs []*Run, now time.Time) *Nudge {
+	policy := sess.Policy.Stuck.WithDefaults()
+	if policy.Disabled || sess.Status != StatusActive || len(runs) == 0 {
+		return nil
+	}
+
+	history := RunHistory(runs)
+	last := history[len(history)-1]
+	if !failed(last.Status) {
+		return nil
+	}
+
+	failing := 0
+	for i := len(history) - 1; i >= 0 && failed(history[i].Status); i-- {
+		failing++
+	}
+
+	nudge := &Nudge{
+		SessionID:   sess.ID,
+		RunID:       last.ID,
+		FailingRuns: failing,
+		At:          now,
+	}
+
+	// Quiet after the latest failure is the newer sign, so it goes first
+	idle := now.Sub(sess.UpdatedAt)
+	threshold := time.Duration(policy.IdleSeconds) * time.Second
+	if idle >= threshold && idle < threshold+stuckIdleHorizon {
+		nudge.Reason = NudgeIdleAfterFailure
+		nudge.Message = fmt.Sprintf("The last run failed and it's been quiet for %s. "+
+			"If you're stuck, try explaining the problem in a sentence or two, or ask for a hint.", idle.Round(time.Minute))
+		nudge.since = last.CreatedAt
+		return nudge
+	}
+
+	if window := history[max(len(history)-policy.FailingRuns, 0):]; failing >= policy.FailingRuns && smallChanges(window[1:]) {
+		nudge.Reason = NudgeRepeatedFailures
+		nudge.Message = fmt.Sprintf("The last %d runs failed with only small changes between them. "+
+			"Rather than another tweak, try re-reading the first failure message and saying what you expect the code to do. "+
+			"A hint is there if you want one.", len(window))
+		nudge.since = window[0].CreatedAt
+		return nudge
+	}
+	return nil
+}
+
+func failed(status string) bool {
+	return status == RunBuildFailed || status == RunTestsFailed
+}
+
+// smallChanges reports whether every run changed only a few lines
+func smallChanges(entries []RunHistoryEntry) bool {
+	for _, e := range entries {
+		if e.Diff.Additions+e.Diff.Deletions > stuckDiffLines {
+			return false
+		}
+	}
+	return true
+}
+
+// SetNudgeHandler sets the function that receives stuck nudges, e.g. to
+// push them to the session's event stream
+func (s *Service) SetNudgeHandler(fn func(Nudge)) {
+	s.onNudge = fn
+}
+
+// checkStuck runs the detector on a session and hands any new nudge to
+// the handler. A pattern is nudged once: a new nudge needs runs newer
+// than the previous one.
+func (s *Service) checkStuck(ctx context.Context, sess *Session, now time.Time) *Nudge {
+	if s.onNudge == nil || sess.Policy.Stuck.Disabled || sess.Status != StatusActive {
+		return nil
+	}
+	runs, err := s.GetRuns(ctx, sess.ID)
+	if err != nil {
+		slog.Warn("stuck detection: get runs", "session_id", sess.ID, "error", err)
+		return nil
+	}
+	nudge := DetectStuck(sess, runs, now)
+	if nudge == nil {
+		return nil
+	}
+
+	s.nudgeMu.Lock()
+	if last, ok := s.nudgedAt[sess.ID]; ok && !last.Before(nudge.since) {
+		s.nudgeMu.Unlock()
+		return nil
+	}
+	s.nudgedAt[sess.ID] = now
+	s.nudgeMu.Unlock()
+
+	s.onNudge(*nudge)
+	return nudge
+}
+
+// CheckIdle runs stuck detection on active sessions that have been quiet
+// since a run, so learners who stop after a failure get nudged too
+func (s *Service) CheckIdle(ctx context.Context, now time.Time) {
+	if s.onNudge == nil {
+		return
+	}
+	ids, err := s.store.List()
+	if err != nil {
+		slog.Warn("stuck detection: list sessions", "error", err)
+		return
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		sess, err := s.store.Get(id)
+		if err != nil || sess.Status != StatusActive || sess.LastRunAt == nil {
+			continue
+		}
+		threshold := time.Duration(sess.Policy.Stuck.WithDefaults().IdleSeconds) * time.Second
+		if idle := now.Sub(sess.UpdatedAt); idle < threshold || idle >= threshold+stuckIdleHorizon {
+			continue
+		}
+		s.checkStuck(ctx, sess, now)
+	}
+}
+
+// StartStuckLoop checks for idle, stuck learners on every interval until
+// ctx is cancelled
+func (s *Service) StartStuckLoop(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.CheckIdle(ctx, time.Now())
+			}
+		}
+	}()
+}
@@ -0,0 +1,145 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// failingRuns returns n failing runs a minute apart ending at end, each
+// changing one line of the one before
+func failingRuns(sessionID string, n int, end time.Time) []*Run {
+	runs := make([]*Run, n)
+	for i := range runs {
+		runs[i] = &Run{
+			ID:        fmt.Sprintf("run-%d", i+1),
+			SessionID: sessionID,
+			Code:      map[string]string{"main.go": fmt.Sprintf("package main\n\nvar x = %d\n", i)},
+			Result:    &RunResult{BuildOK: true},
+			CreatedAt: end.Add(time.Duration(i-n+1) * time.Minute),
+		}
+	}
+	return runs
+}
+
+func TestDetectStuck(t *testing.T) {
+	now := time.Now()
+	newSess := func(stuck domain.StuckPolicy) *Session {
+		policy := domain.DefaultPolicy()
+		policy.Stuck = stuck
+		sess := NewSession("test-pack/basics/hello", map[string]string{}, policy)
+		sess.UpdatedAt = now
+		return sess
+	}
+
+	t.Run("repeated small failures", func(t *testing.T) {
+		sess := newSess(domain.StuckPolicy{})
+		nudge := DetectStuck(sess, failingRuns(sess.ID, 5, now), now)
+		if nudge == nil || nudge.Reason != NudgeRepeatedFailures {
+			t.Fatalf("nudge = %+v; want repeated_failures", nudge)
+		}
+		if nudge.RunID != "run-5" || nudge.FailingRuns != 5 {
+			t.Errorf("run = %s, failing = %d; want run-5, 5", nudge.RunID, nudge.FailingRuns)
+		}
+	})
+
+	t.Run("too few failures", func(t *testing.T) {
+		sess := newSess(domain.StuckPolicy{})
+		if nudge := DetectStuck(sess, failingRuns(sess.ID, 4, now), now); nudge != nil {
+			t.Errorf("nudge = %+v; want nil", nudge)
+		}
+	})
+
+	t.Run("track threshold", func(t *testing.T) {
+		sess := newSess(domain.StuckPolicy{FailingRuns: 3})
+		if nudge := DetectStuck(sess, failingRuns(sess.ID, 3, now), now); nudge == nil {
+			t.Error("want a nudge after 3 failures")
+		}
+	})
+
+	t.Run("large changes are progress", func(t *testing.T) {
+		sess := newSess(domain.StuckPolicy{})
+		runs := failingRuns(sess.ID, 5, now)
+		runs[3].Code = map[string]string{"main.go": "package main\n\nfunc a() {}\nfunc b() {}\nfunc c() {}\nfunc d() {}\n"}
+		if nudge := DetectStuck(sess, runs, now); nudge != nil {
+			t.Errorf("nudge = %+v; want nil", nudge)
+		}
+	})
+
+	t.Run("last run passed", func(t *testing.T) {
+		sess := newSess(domain.StuckPolicy{})
+		runs := failingRuns(sess.ID, 6, now)
+		runs[5].Result = &RunResult{BuildOK: true, TestOK: true}
+		if nudge := DetectStuck(sess, runs, now); nudge != nil {
+			t.Errorf("nudge = %+v; want nil", nudge)
+		}
+	})
+
+	t.Run("idle after failure", func(t *testing.T) {
+		sess := newSess(domain.StuckPolicy{})
+		later := now.Add(15 * time.Minute)
+		nudge := DetectStuck(sess, failingRuns(sess.ID, 1, now), later)
+		if nudge == nil || nudge.Reason != NudgeIdleAfterFailure {
+			t.Fatalf("nudge = %+v; want idle_after_failure", nudge)
+		}
+
+		if nudge := DetectStuck(sess, failingRuns(sess.ID, 1, now), now.Add(5*time.Minute)); nudge != nil {
+			t.Errorf("before threshold: nudge = %+v; want nil", nudge)
+		}
+		if nudge := DetectStuck(sess, failingRuns(sess.ID, 1, now), now.Add(3*time.Hour)); nudge != nil {
+			t.Errorf("past horizon: nudge = %+v; want nil", nudge)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		sess := newSess(domain.StuckPolicy{Disabled: true})
+		if nudge := DetectStuck(sess, failingRuns(sess.ID, 10, now), now.Add(15*time.Minute)); nudge != nil {
+			t.Errorf("nudge = %+v; want nil", nudge)
+		}
+	})
+}
+
+func TestService_CheckStuck_NudgesOncePerPattern(t *testing.T) {
+	service, store, _ := setupTestService(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	var nudges []Nudge
+	service.SetNudgeHandler(func(n Nudge) { nudges = append(nudges, n) })
+
+	sess := NewSession("test-pack/basics/hello", map[string]string{}, domain.DefaultPolicy())
+	sess.UpdatedAt = now
+	sess.LastRunAt = &now
+	store.Save(sess)
+	for _, run := range failingRuns(sess.ID, 5, now) {
+		store.SaveRun(run)
+	}
+
+	if service.checkStuck(ctx, sess, now) == nil {
+		t.Fatal("want a nudge after 5 failures")
+	}
+	if service.checkStuck(ctx, sess, now.Add(time.Second)) != nil {
+		t.Error("the same failures were nudged twice")
+	}
+
+	// Already nudged about the latest run: going quiet doesn't nudge again
+	service.CheckIdle(ctx, now.Add(15*time.Minute))
+	if len(nudges) != 1 {
+		t.Fatalf("nudges = %+v; want just the first", nudges)
+	}
+
+	// Quiet after a newer failure: a new nudge
+	next := now.Add(2 * time.Minute)
+	store.SaveRun(&Run{ID: "run-6", SessionID: sess.ID, Code: map[string]string{"main.go": "package main\n"},
+		Result: &RunResult{}, CreatedAt: next})
+	sess.UpdatedAt = next
+	sess.LastRunAt = &next
+	store.Save(sess)
+	service.CheckIdle(ctx, next.Add(15*time.Minute))
+	if len(nudges) != 2 || nudges[1].Reason != NudgeIdleAfterFailure {
+		t.Errorf("nudges = %+v; want repeated_failures then idle_after_failure", nudges)
+	}
+}
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/analysis"
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+var (
+	ErrRunNotFound     = errors.New("run not found")
+	ErrTestNotFailed   = errors.New("test did not fail in this run")
+	ErrNoTestExplainer = errors.New("no test explainer configured")
+)
+
+// TestExplainer explains one failed test from its output and the code it
+// exercises. packID and paths identify the session's code so local-only
+// routing can apply.
+type TestExplainer interface {
+	ExplainTestFailure(ctx context.Context, packID string, paths []string, test domain.TestResult, scope *analysis.TestScope) (string, error)
+}
+
+// SetTestExplainer sets what explains failed tests on request
+func (s *Service) SetTestExplainer(e TestExplainer) {
+	s.testExplainer = e
+}
+
+// ExplainTest explains why a test failed in a run. The test's source and
+// the functions it calls are found in the run's code, so the explanation
+// follows the test rather than the whole run. Explanations are kept on
+// the run and returned as they are when asked again.
+func (s *Service) ExplainTest(ctx context.Context, sessionID, runID, test string) (*domain.TestExplanation, error) {
+	sess, err := s.getLive(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	run, err := s.store.GetRun(sessionID, runID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrRunNotFound
+		}
+		return nil, err
+	}
+	if run.Result == nil {
+		return nil, ErrTestNotFailed
+	}
+
+	for i := range run.Result.TestExplanations {
+		if cached := run.Result.TestExplanations[i]; cached.Test == test {
+			return &cached, nil
+		}
+	}
+
+	var failed *domain.TestResult
+	for i := range run.Result.Tests {
+		if t := run.Result.Tests[i]; t.Name == test && !t.Passed {
+			failed = &run.Result.Tests[i]
+			break
+		}
+	}
+	if failed == nil {
+		return nil, ErrTestNotFailed
+	}
+	if s.testExplainer == nil {
+		return nil, ErrNoTestExplainer
+	}
+
+	// A test the analysis can't find is still explained from its output
+	scope, err := analysis.ScopeTest(run.Code, failed.Name, failed.Output)
+	if err != nil {
+		scope = nil
+	}
+
+	paths := make([]string, 0, len(run.Code))
+	for name := range run.Code {
+		paths = append(paths, name)
+	}
+	packID, _, _ := domain.SplitExerciseID(sess.ExerciseID)
+
+	text, err := s.testExplainer.ExplainTestFailure(ctx, packID, paths, *failed, scope)
+	if err != nil {
+		return nil, fmt.Errorf("explain test: %w", err)
+	}
+
+	explanation := domain.TestExplanation{
+		Test:        failed.Name,
+		Package:     failed.Package,
+		Explanation: text,
+		CreatedAt:   time.Now(),
+	}
+	if scope != nil {
+		for _, fn := range scope.Callees {
+			explanation.Functions = append(explanation.Functions, fn.Name)
+		}
+	}
+
+	run.Result.TestExplanations = append(run.Result.TestExplanations, explanation)
+	if err := s.store.SaveRun(run); err != nil {
+		return nil, fmt.Errorf("save run: %w", err)
+	}
+	return &explanation, nil
+}
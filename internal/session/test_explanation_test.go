@@ -0,0 +1,74 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/analysis"
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+type fakeTestExplainer struct {
+	calls int
+	test  domain.TestResult
+	scope *analysis.TestScope
+}
+
+func (f *fakeTestExplainer) ExplainTestFailure(ctx context.Context, packID string, paths []string, test domain.TestResult, scope *analysis.TestScope) (string, error) {
+	f.calls++
+	f.test, f.scope = test, scope
+	return "Sum stops one element early.", nil
+}
+
+func TestService_ExplainTest(t *testing.T) {
+	service, store, _ := setupTestService(t)
+	ctx := context.Background()
+
+	sess, _ := service.Create(ctx, CreateRequest{ExerciseID: "test-pack/basics/hello"})
+	store.SaveRun(&Run{ID: "run-1", SessionID: sess.ID, CreatedAt: time.Now(),
+		Code: map[string]string{
+			"sum.go":      "package sum\n\nfunc Sum(xs []int) int {\n\tt := 0\n\tfor i := 0; i < len(xs)-1; i++ {\n\t\tt += xs[i]\n\t}\n\treturn t\n}\n",
+			"sum_test.go": "package sum\n\nimport \"testing\"\n\nfunc TestSum(t *testing.T) {\n\tif got := Sum([]int{1, 2}); got != 3 {\n\t\tt.Errorf(\"got %d\", got)\n\t}\n}\n",
+		},
+		Result: &RunResult{Tests: []domain.TestResult{
+			{Name: "TestSum", Passed: false, Output: "    sum_test.go:7: got 1\n"},
+			{Name: "TestOther", Passed: true},
+		}},
+	})
+
+	if _, err := service.ExplainTest(ctx, sess.ID, "run-1", "TestSum"); !errors.Is(err, ErrNoTestExplainer) {
+		t.Fatalf("without an explainer: error = %v, want ErrNoTestExplainer", err)
+	}
+
+	explainer := &fakeTestExplainer{}
+	service.SetTestExplainer(explainer)
+	got, err := service.ExplainTest(ctx, sess.ID, "run-1", "TestSum")
+	if err != nil {
+		t.Fatalf("ExplainTest() error = %v", err)
+	}
+	if got.Explanation != "Sum stops one element early." || len(got.Functions) != 1 || got.Functions[0] != "Sum" {
+		t.Errorf("explanation = %+v", got)
+	}
+	if explainer.scope == nil || len(explainer.scope.Assertions) != 1 || explainer.scope.Assertions[0].Line != 7 {
+		t.Errorf("scope = %+v, want the assertion on line 7", explainer.scope)
+	}
+
+	// Cached on the run
+	again, err := service.ExplainTest(ctx, sess.ID, "run-1", "TestSum")
+	if err != nil || again.Explanation != got.Explanation || explainer.calls != 1 {
+		t.Errorf("second call: %+v, %v, explainer calls = %d, want the cached answer", again, err, explainer.calls)
+	}
+	run, _ := store.GetRun(sess.ID, "run-1")
+	if len(run.Result.TestExplanations) != 1 {
+		t.Errorf("run keeps %d explanations, want 1", len(run.Result.TestExplanations))
+	}
+
+	if _, err := service.ExplainTest(ctx, sess.ID, "run-1", "TestOther"); !errors.Is(err, ErrTestNotFailed) {
+		t.Errorf("passing test: error = %v, want ErrTestNotFailed", err)
+	}
+	if _, err := service.ExplainTest(ctx, sess.ID, "run-9", "TestSum"); !errors.Is(err, ErrRunNotFound) {
+		t.Errorf("unknown run: error = %v, want ErrRunNotFound", err)
+	}
+}
@@ -0,0 +1,150 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// WorkspaceManifest describes a session's file set by content hash so
+// editors can work out what changed without transferring any content
+type WorkspaceManifest struct {
+	Version string            `json:"version"` // digest of the whole file set
+	Files   map[string]string `json:"files"`   // path -> content hash
+}
+
+// WorkspacePush is an incremental update to a session's file set
+type WorkspacePush struct {
+	// BaseVersion is the manifest version the editor last synced with.
+	// When set and stale the push is rejected with ErrWorkspaceConflict.
+	BaseVersion string
+	Files       map[string]string // created or modified files
+	Deleted     []string          // removed paths
+
+	// Validate, when set, checks the session's files with the push
+	// applied. An error rejects the push and is returned as is, so many
+	// small pushes can't grow a session past limits a single one obeys.
+	Validate func(code map[string]string) error
+}
+
+// WorkspaceDelta carries the files an editor is missing
+type WorkspaceDelta struct {
+	Version string            `json:"version"`
+	Files   map[string]string `json:"files"`   // path -> content, only changed files
+	Deleted []string          `json:"deleted"` // paths the editor has but the daemon does not
+}
+
+// HashContent returns the content hash used in workspace manifests
+func HashContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// ManifestOf builds the manifest for a file set
+func ManifestOf(code map[string]string) WorkspaceManifest {
+	files := make(map[string]string, len(code))
+	for name, content := range code {
+		files[name] = HashContent(content)
+	}
+	return WorkspaceManifest{Version: manifestVersion(files), Files: files}
+}
+
+// manifestVersion hashes the sorted path/hash pairs so any change to the
+// file set yields a new version
+func manifestVersion(files map[string]string) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s\x00%s\n", name, files[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// DiffWorkspace returns the files in code whose hash differs from (or is
+// missing in) have, plus the paths in have that code no longer contains
+func DiffWorkspace(code map[string]string, have map[string]string) WorkspaceDelta {
+	manifest := ManifestOf(code)
+	delta := WorkspaceDelta{
+		Version: manifest.Version,
+		Files:   map[string]string{},
+		Deleted: []string{},
+	}
+	for name, hash := range manifest.Files {
+		if have[name] != hash {
+			delta.Files[name] = code[name]
+		}
+	}
+	for name := range have {
+		if _, ok := code[name]; !ok {
+			delta.Deleted = append(delta.Deleted, name)
+		}
+	}
+	sort.Strings(delta.Deleted)
+	return delta
+}
+
+// validWorkspacePath rejects paths that could escape the workspace once
+// files are written into a container or onto disk
+func validWorkspacePath(name string) bool {
+	if name == "" || strings.Contains(name, "\\") || path.IsAbs(name) {
+		return false
+	}
+	clean := path.Clean(name)
+	return clean == name && clean != "." && clean != ".." && !strings.HasPrefix(clean, "../")
+}
+
+// PushWorkspace applies an incremental update to the session's file set
+// and returns the new manifest. The daemon's copy stays authoritative:
+// runs, patches and context building all read it.
+func (s *Service) PushWorkspace(ctx context.Context, id string, push WorkspacePush) (*WorkspaceManifest, error) {
+	s.workspaceMu.Lock()
+	defer s.workspaceMu.Unlock()
+
+	session, err := s.getLive(id)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.Status != StatusActive {
+		return nil, ErrSessionNotActive
+	}
+
+	if push.BaseVersion != "" && push.BaseVersion != ManifestOf(session.Code).Version {
+		return nil, ErrWorkspaceConflict
+	}
+
+	code := make(map[string]string, len(session.Code)+len(push.Files))
+	for name, content := range session.Code {
+		code[name] = content
+	}
+	for name, content := range push.Files {
+		if !validWorkspacePath(name) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPath, name)
+		}
+		code[name] = content
+	}
+	for _, name := range push.Deleted {
+		delete(code, name)
+	}
+	if push.Validate != nil {
+		if err := push.Validate(code); err != nil {
+			return nil, err
+		}
+	}
+
+	session.UpdateCode(code)
+	if err := s.store.Save(session); err != nil {
+		return nil, fmt.Errorf("save session: %w", err)
+	}
+
+	manifest := ManifestOf(code)
+	return &manifest, nil
+}
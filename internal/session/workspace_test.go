@@ -0,0 +1,168 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestManifestOf_VersionTracksContent(t *testing.T) {
+	a := ManifestOf(map[string]string{"main.go": "package main", "util.go": "package main"})
+	b := ManifestOf(map[string]string{"util.go": "package main", "main.go": "package main"})
+	if a.Version != b.Version {
+		t.Error("version should not depend on map order")
+	}
+
+	c := ManifestOf(map[string]string{"main.go": "package main", "util.go": "package util"})
+	if a.Version == c.Version {
+		t.Error("version should change when content changes")
+	}
+	if a.Files["main.go"] != HashContent("package main") {
+		t.Error("manifest should hash each file")
+	}
+}
+
+func TestDiffWorkspace(t *testing.T) {
+	code := map[string]string{"main.go": "package main", "new.go": "package main"}
+	have := map[string]string{
+		"main.go": HashContent("package main"),
+		"old.go":  HashContent("package main"),
+	}
+
+	delta := DiffWorkspace(code, have)
+	if len(delta.Files) != 1 || delta.Files["new.go"] == "" {
+		t.Errorf("Files = %v; want only new.go", delta.Files)
+	}
+	if len(delta.Deleted) != 1 || delta.Deleted[0] != "old.go" {
+		t.Errorf("Deleted = %v; want [old.go]", delta.Deleted)
+	}
+	if delta.Version != ManifestOf(code).Version {
+		t.Error("delta should carry the daemon's current version")
+	}
+}
+
+func TestValidWorkspacePath(t *testing.T) {
+	valid := []string{"main.go", "pkg/util.go", "go.mod"}
+	invalid := []string{"", "/etc/passwd", "../main.go", "pkg/../../x.go", "./main.go", "a\\b.go", ".."}
+	for _, p := range valid {
+		if !validWorkspacePath(p) {
+			t.Errorf("validWorkspacePath(%q) = false; want true", p)
+		}
+	}
+	for _, p := range invalid {
+		if validWorkspacePath(p) {
+			t.Errorf("validWorkspacePath(%q) = true; want false", p)
+		}
+	}
+}
+
+func TestService_PushWorkspace(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	ctx := context.Background()
+
+	session, _ := service.Create(ctx, CreateRequest{
+		ExerciseID: "test-pack/basics/hello",
+	})
+	base := ManifestOf(session.Code)
+
+	manifest, err := service.PushWorkspace(ctx, session.ID, WorkspacePush{
+		BaseVersion: base.Version,
+		Files:       map[string]string{"extra.go": "package main"},
+	})
+	if err != nil {
+		t.Fatalf("PushWorkspace() error = %v", err)
+	}
+	if _, ok := manifest.Files["extra.go"]; !ok {
+		t.Error("pushed file missing from manifest")
+	}
+	if len(manifest.Files) != len(base.Files)+1 {
+		t.Errorf("manifest has %d files; want existing files kept plus extra.go", len(manifest.Files))
+	}
+
+	// The old base is now stale
+	_, err = service.PushWorkspace(ctx, session.ID, WorkspacePush{
+		BaseVersion: base.Version,
+		Deleted:     []string{"extra.go"},
+	})
+	if err != ErrWorkspaceConflict {
+		t.Errorf("PushWorkspace() error = %v; want ErrWorkspaceConflict", err)
+	}
+
+	manifest, err = service.PushWorkspace(ctx, session.ID, WorkspacePush{
+		BaseVersion: manifest.Version,
+		Deleted:     []string{"extra.go"},
+	})
+	if err != nil {
+		t.Fatalf("PushWorkspace() error = %v", err)
+	}
+	if manifest.Version != base.Version {
+		t.Error("deleting the pushed file should restore the original version")
+	}
+
+	updated, _ := service.Get(ctx, session.ID)
+	if _, ok := updated.Code["extra.go"]; ok {
+		t.Error("deleted file still stored on the session")
+	}
+}
+
+func TestService_PushWorkspace_InvalidPath(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	ctx := context.Background()
+
+	session, _ := service.Create(ctx, CreateRequest{
+		ExerciseID: "test-pack/basics/hello",
+	})
+
+	_, err := service.PushWorkspace(ctx, session.ID, WorkspacePush{
+		Files: map[string]string{"../escape.go": "package main"},
+	})
+	if !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("PushWorkspace() error = %v; want ErrInvalidPath", err)
+	}
+}
+
+func TestService_PushWorkspace_ValidatesMergedFiles(t *testing.T) {
+	service, _, _ := setupTestService(t)
+	ctx := context.Background()
+
+	session, _ := service.Create(ctx, CreateRequest{
+		ExerciseID: "test-pack/basics/hello",
+	})
+
+	errTooMany := errors.New("too many files")
+	maxFiles := func(code map[string]string) error {
+		if len(code) > 2 {
+			return errTooMany
+		}
+		return nil
+	}
+
+	// Each push adds one file; the second takes the session past the limit
+	if _, err := service.PushWorkspace(ctx, session.ID, WorkspacePush{
+		Files:    map[string]string{"a.go": "package main"},
+		Validate: maxFiles,
+	}); err != nil {
+		t.Fatalf("PushWorkspace() error = %v", err)
+	}
+	_, err := service.PushWorkspace(ctx, session.ID, WorkspacePush{
+		Files:    map[string]string{"b.go": "package main"},
+		Validate: maxFiles,
+	})
+	if !errors.Is(err, errTooMany) {
+		t.Fatalf("PushWorkspace() error = %v; want the validation error", err)
+	}
+
+	updated, _ := service.Get(ctx, session.ID)
+	if _, ok := updated.Code["b.go"]; ok {
+		t.Error("a rejected push was saved")
+	}
+}
+
+func TestService_PushWorkspace_NotFound(t *testing.T) {
+	service, _, _ := setupTestService(t)
+
+	_, err := service.PushWorkspace(context.Background(), "nonexistent", WorkspacePush{})
+	if err != ErrSessionNotFound {
+		t.Errorf("PushWorkspace() error = %v; want ErrSessionNotFound", err)
+	}
+}
@@ -0,0 +1,118 @@
+package spec
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// Check issue kinds
+const (
+	// CheckDrift marks a locked spec that changed without being re-locked
+	CheckDrift = "drift"
+
+	// CheckUnverifiedCriterion marks a criterion claimed satisfied
+	// without evidence
+	CheckUnverifiedCriterion = "unverified_criterion"
+)
+
+// CheckIssue is one reason a commit should not go ahead
+type CheckIssue struct {
+	SpecPath    string `json:"spec_path"`
+	Kind        string `json:"kind"`
+	CriterionID string `json:"criterion_id,omitempty"`
+	Detail      string `json:"detail"`
+}
+
+// CheckReport is the result of a pre-commit check
+type CheckReport struct {
+	OK     bool         `json:"ok"`
+	Issues []CheckIssue `json:"issues"`
+}
+
+// Check is the fast check behind the git hooks. Every spec that was ever
+// locked must still match its latest lock. Specs among files, which are
+// relative to the workspace root, must not claim criteria satisfied
+// without evidence; with no files, every spec is checked.
+func (s *Service) Check(ctx context.Context, files []string) (*CheckReport, error) {
+	specs, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	committed := make(map[string]bool, len(files))
+	for _, f := range files {
+		committed[filepath.ToSlash(filepath.Clean(f))] = true
+	}
+
+	report := &CheckReport{Issues: []CheckIssue{}}
+	for _, spec := range specs {
+		path := filepath.ToSlash(spec.FilePath)
+
+		drift, err := s.lockedDrift(spec)
+		if err != nil {
+			return nil, err
+		}
+		if drift != nil && drift.HasDrift {
+			report.Issues = append(report.Issues, CheckIssue{
+				SpecPath: path,
+				Kind:     CheckDrift,
+				Detail:   describeDrift(drift) + "; run `temper spec lock " + path + "`",
+			})
+		}
+
+		if len(files) > 0 && !committed[path] {
+			continue
+		}
+		for _, ac := range spec.AcceptanceCriteria {
+			if ac.Satisfied && strings.TrimSpace(ac.Evidence) == "" {
+				report.Issues = append(report.Issues, CheckIssue{
+					SpecPath:    path,
+					Kind:        CheckUnverifiedCriterion,
+					CriterionID: ac.ID,
+					Detail:      "marked satisfied without evidence; add the evidence or set satisfied back to false",
+				})
+			}
+		}
+	}
+
+	report.OK = len(report.Issues) == 0
+	return report, nil
+}
+
+// lockedDrift compares the spec with its latest lock. It returns nil
+// for a spec that was never locked.
+func (s *Service) lockedDrift(spec *domain.ProductSpec) (*DriftReport, error) {
+	history, err := s.store.LoadLockHistory(spec.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, nil
+	}
+	return CalculateDrift(spec, &history[len(history)-1].Lock), nil
+}
+
+// describeDrift summarizes a drift report in one line
+func describeDrift(d *DriftReport) string {
+	var parts []string
+	if d.VersionChanged {
+		parts = append(parts, fmt.Sprintf("version %s -> %s", d.OldVersion, d.NewVersion))
+	}
+	if len(d.AddedFeatures) > 0 {
+		parts = append(parts, "added "+strings.Join(d.AddedFeatures, ", "))
+	}
+	if len(d.RemovedFeatures) > 0 {
+		parts = append(parts, "removed "+strings.Join(d.RemovedFeatures, ", "))
+	}
+	if len(d.ModifiedFeatures) > 0 {
+		parts = append(parts, "modified "+strings.Join(d.ModifiedFeatures, ", "))
+	}
+	if len(parts) == 0 {
+		return "changed since it was locked"
+	}
+	return "changed since it was locked (" + strings.Join(parts, "; ") + ")"
+}
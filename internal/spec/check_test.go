@@ -0,0 +1,86 @@
+package spec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func checkTestSpec(path string) *domain.ProductSpec {
+	return &domain.ProductSpec{
+		Name:     "Auth",
+		Version:  "1.0.0",
+		FilePath: path,
+		Goals:    []string{"Implement user authentication"},
+		Features: []domain.Feature{
+			{ID: "login", Title: "Login", Description: "User can log in", SuccessCriteria: []string{"User can log in"}},
+		},
+		AcceptanceCriteria: []domain.AcceptanceCriterion{
+			{ID: "ac-1", Description: "User can log in with valid credentials"},
+		},
+	}
+}
+
+func TestService_Check(t *testing.T) {
+	service := setupTestService(t)
+	ctx := context.Background()
+
+	spec := checkTestSpec("auth.yaml")
+	if err := service.Save(ctx, spec); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	report, err := service.Check(ctx, nil)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !report.OK {
+		t.Fatalf("unlocked spec without claims should pass: %+v", report.Issues)
+	}
+
+	if _, err := service.Lock(ctx, "auth.yaml"); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	spec.Features[0].Description = "User can log in with a passkey"
+	spec.AcceptanceCriteria[0].Satisfied = true
+	if err := service.Save(ctx, spec); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	report, err = service.Check(ctx, nil)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if report.OK || len(report.Issues) != 2 {
+		t.Fatalf("Check() = %+v; want drift and an unverified criterion", report)
+	}
+	if report.Issues[0].Kind != CheckDrift || report.Issues[0].SpecPath != spec.FilePath {
+		t.Errorf("first issue = %+v; want drift of %s", report.Issues[0], spec.FilePath)
+	}
+	if got := report.Issues[1]; got.Kind != CheckUnverifiedCriterion || got.CriterionID != "ac-1" {
+		t.Errorf("second issue = %+v; want unverified ac-1", got)
+	}
+
+	// Criteria are only checked in the committed specs; drift always is
+	report, err = service.Check(ctx, []string{"main.go"})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Kind != CheckDrift {
+		t.Errorf("Check(main.go) = %+v; want only the drift", report.Issues)
+	}
+
+	if _, err := service.Lock(ctx, "auth.yaml"); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if err := service.MarkCriterionSatisfied(ctx, "auth.yaml", "ac-1", "TestLogin passes"); err != nil {
+		t.Fatalf("MarkCriterionSatisfied() error = %v", err)
+	}
+	report, err = service.Check(ctx, []string{spec.FilePath})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !report.OK {
+		t.Errorf("re-locked spec with evidence should pass: %+v", report.Issues)
+	}
+}
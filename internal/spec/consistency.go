@@ -0,0 +1,104 @@
+package spec
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// CheckConsistency finds problems between specs that are authored
+// together, such as an epic split into several files:
+//   - a feature ID declared in more than one file
+//   - features with the same title in different files
+//   - depends_on references into a file of the set that lacks the feature
+//
+// Targets are "file#feature-id". Problems within one file are left to
+// Validate.
+func CheckConsistency(specs []*domain.ProductSpec) []domain.SpecFinding {
+	findings := []domain.SpecFinding{}
+
+	byPath := make(map[string]*domain.ProductSpec, len(specs))
+	for _, s := range specs {
+		byPath[filepath.Clean(s.FilePath)] = s
+	}
+
+	idFiles := make(map[string][]string)
+	titleFiles := make(map[string][]string)
+	var ids, titles []string
+	for _, s := range specs {
+		for _, feat := range s.Features {
+			if feat.ID != "" {
+				if _, ok := idFiles[feat.ID]; !ok {
+					ids = append(ids, feat.ID)
+				}
+				idFiles[feat.ID] = append(idFiles[feat.ID], s.FilePath)
+			}
+			if title := normalizeTitle(feat.Title); title != "" {
+				if _, ok := titleFiles[title]; !ok {
+					titles = append(titles, title)
+				}
+				titleFiles[title] = appendUnique(titleFiles[title], s.FilePath)
+			}
+		}
+	}
+
+	for _, id := range ids {
+		if files := idFiles[id]; len(files) > 1 {
+			findings = append(findings, domain.SpecFinding{
+				Kind:     domain.FindingDuplicateFeature,
+				Severity: "medium",
+				Target:   featureKey(files[1], id),
+				Message: fmt.Sprintf("feature %s is declared in %s; cross-file depends_on references to it are ambiguous",
+					id, strings.Join(files, " and ")),
+			})
+		}
+	}
+	for _, title := range titles {
+		if files := titleFiles[title]; len(files) > 1 {
+			findings = append(findings, domain.SpecFinding{
+				Kind:     domain.FindingConflictingFeatures,
+				Severity: "low",
+				Target:   files[1],
+				Message:  fmt.Sprintf("a feature titled %q appears in %s; keep it in one spec", title, strings.Join(files, " and ")),
+			})
+		}
+	}
+
+	for _, s := range specs {
+		for _, feat := range s.Features {
+			for _, ref := range feat.DependsOn {
+				specPath, featureID := splitDependencyRef(ref)
+				if specPath == "" {
+					continue
+				}
+				target, ok := byPath[filepath.Clean(specPath)]
+				if !ok || target.GetFeature(featureID) != nil {
+					continue
+				}
+				findings = append(findings, domain.SpecFinding{
+					Kind:     domain.FindingBrokenReference,
+					Severity: "high",
+					Target:   featureKey(s.FilePath, feat.ID),
+					Message:  fmt.Sprintf("depends on %s, but %s has no feature %s", ref, target.FilePath, featureID),
+				})
+			}
+		}
+	}
+
+	return findings
+}
+
+func normalizeTitle(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
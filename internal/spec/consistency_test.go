@@ -0,0 +1,55 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	epic := &domain.ProductSpec{
+		FilePath: ".specs/epic.yaml",
+		Features: []domain.Feature{
+			{ID: "checkout", Title: "Checkout", DependsOn: []string{
+				"./.specs/billing.yaml#invoices", // missing from billing
+				".specs/billing.yaml#payments",
+				"other.yaml#anything", // outside the set; Plan reports it
+			}},
+			{ID: "cart", Title: "Shopping cart"},
+		},
+	}
+	billing := &domain.ProductSpec{
+		FilePath: ".specs/billing.yaml",
+		Features: []domain.Feature{
+			{ID: "payments", Title: "Payments"},
+			{ID: "cart", Title: "Cart totals"},
+			{ID: "refunds", Title: "shopping  Cart"},
+		},
+	}
+
+	findings := CheckConsistency([]*domain.ProductSpec{epic, billing})
+
+	want := map[domain.SpecFindingKind]string{
+		domain.FindingDuplicateFeature:    ".specs/billing.yaml#cart",
+		domain.FindingConflictingFeatures: ".specs/billing.yaml",
+		domain.FindingBrokenReference:     ".specs/epic.yaml#checkout",
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("got %d findings, want %d: %+v", len(findings), len(want), findings)
+	}
+	for _, f := range findings {
+		if target, ok := want[f.Kind]; !ok || f.Target != target {
+			t.Errorf("unexpected finding %+v", f)
+		}
+	}
+}
+
+func TestCheckConsistency_SingleFile(t *testing.T) {
+	spec := &domain.ProductSpec{
+		FilePath: "a.yaml",
+		Features: []domain.Feature{{ID: "f-1", Title: "One", DependsOn: []string{"f-2"}}},
+	}
+	if findings := CheckConsistency([]*domain.ProductSpec{spec}); len(findings) != 0 {
+		t.Errorf("CheckConsistency() = %+v, want none", findings)
+	}
+}
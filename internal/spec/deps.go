@@ -0,0 +1,247 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// ErrDependencyCycle is returned when features depend on each other in a loop
+var ErrDependencyCycle = errors.New("feature dependency cycle")
+
+// specLoader loads another spec referenced by a cross-spec dependency
+type specLoader func(path string) (*domain.ProductSpec, error)
+
+type depNode struct {
+	key      string // "spec#feature-id"
+	spec     string
+	feature  domain.Feature
+	deps     []string // keys of resolved dependencies
+	external bool
+	index    int // discovery order, for stable output
+}
+
+// label names the node in messages: the bare ID for features of the spec
+// being examined, the qualified key for features from other specs
+func (n *depNode) label() string {
+	if n.external {
+		return n.key
+	}
+	return n.feature.ID
+}
+
+// depGraph holds a spec's features plus every feature they transitively
+// depend on in other specs
+type depGraph struct {
+	nodes    map[string]*depNode
+	keys     []string
+	problems []string // unresolvable references
+}
+
+func featureKey(specPath, featureID string) string {
+	return specPath + "#" + featureID
+}
+
+// splitDependencyRef splits "other.yaml#feature-id" into its spec path and
+// feature ID. A bare ID refers to the spec declaring the dependency.
+func splitDependencyRef(ref string) (string, string) {
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return "", ref
+}
+
+// buildDependencyGraph resolves depends_on references starting from root.
+// With a nil load, references into other specs are ignored.
+func buildDependencyGraph(root *domain.ProductSpec, load specLoader) *depGraph {
+	g := &depGraph{nodes: make(map[string]*depNode)}
+	specs := map[string]*domain.ProductSpec{root.FilePath: root}
+	byRef := map[string]*domain.ProductSpec{}
+
+	var queue []*depNode
+	add := func(spec *domain.ProductSpec, feat domain.Feature, external bool) *depNode {
+		n := &depNode{
+			key:      featureKey(spec.FilePath, feat.ID),
+			spec:     spec.FilePath,
+			feature:  feat,
+			external: external,
+			index:    len(g.keys),
+		}
+		g.nodes[n.key] = n
+		g.keys = append(g.keys, n.key)
+		queue = append(queue, n)
+		return n
+	}
+	resolve := func(path string) (*domain.ProductSpec, error) {
+		if spec, ok := byRef[path]; ok {
+			return spec, nil
+		}
+		spec, err := load(path)
+		if err != nil {
+			return nil, err
+		}
+		if known, ok := specs[spec.FilePath]; ok {
+			spec = known
+		} else {
+			specs[spec.FilePath] = spec
+		}
+		byRef[path] = spec
+		return spec, nil
+	}
+
+	for _, feat := range root.Features {
+		if _, dup := g.nodes[featureKey(root.FilePath, feat.ID)]; !dup && feat.ID != "" {
+			add(root, feat, false)
+		}
+	}
+
+	for i := 0; i < len(queue); i++ {
+		n := queue[i]
+		for _, ref := range n.feature.DependsOn {
+			specPath, featureID := splitDependencyRef(ref)
+			target := specs[n.spec]
+			if specPath != "" {
+				if load == nil {
+					continue
+				}
+				spec, err := resolve(specPath)
+				if err != nil {
+					g.problems = append(g.problems,
+						fmt.Sprintf("feature %s depends on %s: spec %s not found", n.label(), ref, specPath))
+					continue
+				}
+				target = spec
+			}
+
+			key := featureKey(target.FilePath, featureID)
+			if _, ok := g.nodes[key]; !ok {
+				feat := target.GetFeature(featureID)
+				if feat == nil {
+					g.problems = append(g.problems,
+						fmt.Sprintf("feature %s depends on unknown feature: %s", n.label(), ref))
+					continue
+				}
+				add(target, *feat, true)
+			}
+			n.deps = append(n.deps, key)
+		}
+	}
+
+	return g
+}
+
+// findCycle returns the features of one dependency cycle, first feature
+// repeated at the end, or nil when the graph is acyclic
+func (g *depGraph) findCycle() []string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int, len(g.nodes))
+	var stack []string
+
+	var visit func(key string) []string
+	visit = func(key string) []string {
+		state[key] = inProgress
+		stack = append(stack, key)
+		for _, dep := range g.nodes[key].deps {
+			switch state[dep] {
+			case inProgress:
+				start := 0
+				for stack[start] != dep {
+					start++
+				}
+				cycle := make([]string, 0, len(stack)-start+1)
+				for _, k := range stack[start:] {
+					cycle = append(cycle, g.nodes[k].label())
+				}
+				return append(cycle, g.nodes[dep].label())
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[key] = done
+		return nil
+	}
+
+	for _, key := range g.keys {
+		if state[key] == unvisited {
+			if cycle := visit(key); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// plan orders the graph in stages: a feature's stage is one past its
+// deepest dependency. Within a stage, higher priority comes first, then
+// declaration order. The graph must be acyclic.
+func (g *depGraph) plan(specPath string) *domain.SpecPlan {
+	stage := make(map[string]int, len(g.nodes))
+	var depth func(key string) int
+	depth = func(key string) int {
+		if s, ok := stage[key]; ok {
+			return s
+		}
+		s := 0
+		for _, dep := range g.nodes[key].deps {
+			s = max(s, depth(dep)+1)
+		}
+		stage[key] = s
+		return s
+	}
+	for _, key := range g.keys {
+		depth(key)
+	}
+
+	nodes := make([]*depNode, 0, len(g.keys))
+	for _, key := range g.keys {
+		nodes = append(nodes, g.nodes[key])
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if stage[a.key] != stage[b.key] {
+			return stage[a.key] < stage[b.key]
+		}
+		if pa, pb := priorityRank(a.feature.Priority), priorityRank(b.feature.Priority); pa != pb {
+			return pa < pb
+		}
+		return a.index < b.index
+	})
+
+	plan := &domain.SpecPlan{SpecPath: specPath, Steps: make([]domain.PlanStep, 0, len(nodes))}
+	for i, n := range nodes {
+		plan.Steps = append(plan.Steps, domain.PlanStep{
+			Order:     i + 1,
+			Stage:     stage[n.key],
+			Spec:      n.spec,
+			FeatureID: n.feature.ID,
+			Title:     n.feature.Title,
+			Priority:  n.feature.Priority,
+			DependsOn: n.deps,
+			External:  n.external,
+		})
+	}
+	return plan
+}
+
+func priorityRank(p domain.Priority) int {
+	switch p {
+	case domain.PriorityHigh:
+		return 0
+	case domain.PriorityMedium:
+		return 1
+	case domain.PriorityLow:
+		return 2
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,166 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func depSpec(path string, features ...domain.Feature) *domain.ProductSpec {
+	return &domain.ProductSpec{Name: path, Version: "1.0.0", FilePath: path, Features: features}
+}
+
+func feature(id string, priority domain.Priority, deps ...string) domain.Feature {
+	return domain.Feature{ID: id, Title: strings.ToUpper(id), Priority: priority, DependsOn: deps}
+}
+
+func stepIDs(plan *domain.SpecPlan) []string {
+	ids := make([]string, len(plan.Steps))
+	for i, s := range plan.Steps {
+		ids[i] = s.FeatureID
+	}
+	return ids
+}
+
+func TestDependencyGraph_Plan(t *testing.T) {
+	spec := depSpec(".specs/app.yaml",
+		feature("dashboard", domain.PriorityHigh, "auth", "reports"),
+		feature("reports", domain.PriorityLow, "auth"),
+		feature("auth", domain.PriorityMedium),
+		feature("theme", domain.PriorityHigh),
+	)
+
+	plan := buildDependencyGraph(spec, nil).plan(spec.FilePath)
+
+	want := []string{"theme", "auth", "reports", "dashboard"}
+	if got := stepIDs(plan); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("order = %v, want %v", got, want)
+	}
+	stages := map[string]int{}
+	for _, s := range plan.Steps {
+		stages[s.FeatureID] = s.Stage
+	}
+	if stages["theme"] != 0 || stages["auth"] != 0 || stages["reports"] != 1 || stages["dashboard"] != 2 {
+		t.Errorf("stages = %v", stages)
+	}
+}
+
+func TestDependencyGraph_Cycle(t *testing.T) {
+	spec := depSpec(".specs/app.yaml",
+		feature("a", "", "b"),
+		feature("b", "", "c"),
+		feature("c", "", "a"),
+		feature("d", ""),
+	)
+
+	cycle := buildDependencyGraph(spec, nil).findCycle()
+	if strings.Join(cycle, " -> ") != "a -> b -> c -> a" {
+		t.Errorf("cycle = %v", cycle)
+	}
+
+	acyclic := depSpec(".specs/app.yaml", feature("a", "", "b"), feature("b", ""))
+	if cycle := buildDependencyGraph(acyclic, nil).findCycle(); cycle != nil {
+		t.Errorf("unexpected cycle %v", cycle)
+	}
+}
+
+func TestValidator_Dependencies(t *testing.T) {
+	v := NewValidator()
+
+	spec := depSpec(".specs/app.yaml", feature("self", "", "self"), feature("orphan", "", "missing"))
+
+	validation := v.Validate(spec)
+	if validation.Valid {
+		t.Fatal("spec with a self-dependency and unknown reference should be invalid")
+	}
+	errs := strings.Join(validation.Errors, "\n")
+	for _, want := range []string{"feature dependency cycle: self -> self", "feature orphan depends on unknown feature: missing"} {
+		if !strings.Contains(errs, want) {
+			t.Errorf("errors missing %q:\n%s", want, errs)
+		}
+	}
+}
+
+func TestService_Plan_CrossSpec(t *testing.T) {
+	service := setupTestService(t)
+	ctx := context.Background()
+
+	billing := depSpec(".specs/billing.yaml",
+		feature("invoices", domain.PriorityHigh, "accounts"),
+		feature("accounts", domain.PriorityHigh),
+		feature("refunds", domain.PriorityLow),
+	)
+	app := depSpec(".specs/app.yaml",
+		feature("checkout", domain.PriorityHigh, "billing.yaml#invoices"),
+		feature("cart", domain.PriorityMedium),
+	)
+	for _, s := range []*domain.ProductSpec{billing, app} {
+		if err := service.Save(ctx, s); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	plan, err := service.Plan(ctx, "app.yaml")
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	want := []string{"accounts", "cart", "invoices", "checkout"}
+	if got := stepIDs(plan); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("order = %v, want %v", got, want)
+	}
+	for _, s := range plan.Steps {
+		if external := s.Spec == ".specs/billing.yaml"; s.External != external {
+			t.Errorf("%s: External = %v, Spec = %s", s.FeatureID, s.External, s.Spec)
+		}
+	}
+	if deps := plan.Steps[3].DependsOn; len(deps) != 1 || deps[0] != ".specs/billing.yaml#invoices" {
+		t.Errorf("checkout DependsOn = %v", deps)
+	}
+}
+
+func TestService_Plan_CrossSpecCycle(t *testing.T) {
+	service := setupTestService(t)
+	ctx := context.Background()
+
+	for _, s := range []*domain.ProductSpec{
+		depSpec(".specs/a.yaml", feature("x", "", "b.yaml#y")),
+		depSpec(".specs/b.yaml", feature("y", "", "a.yaml#x")),
+	} {
+		if err := service.Save(ctx, s); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	if _, err := service.Plan(ctx, "a.yaml"); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("Plan() error = %v, want ErrDependencyCycle", err)
+	}
+
+	validation, err := service.Validate(ctx, "a.yaml")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !strings.Contains(strings.Join(validation.Errors, "\n"), "feature dependency cycle") {
+		t.Errorf("Validate() errors = %v, want cross-spec cycle", validation.Errors)
+	}
+}
+
+func TestService_Plan_UnknownSpec(t *testing.T) {
+	service := setupTestService(t)
+	ctx := context.Background()
+
+	if err := service.Save(ctx, depSpec(".specs/app.yaml", feature("a", "", "gone.yaml#b"))); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	validation, err := service.Validate(ctx, "app.yaml")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !strings.Contains(strings.Join(validation.Errors, "\n"), "spec gone.yaml not found") {
+		t.Errorf("Validate() errors = %v", validation.Errors)
+	}
+}
@@ -0,0 +1,138 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// ErrUnknownDiagramFormat is returned for a format other than mermaid or c4
+var ErrUnknownDiagramFormat = errors.New("unknown diagram format")
+
+// DiagramExtension returns the file extension a diagram is written with
+func DiagramExtension(format domain.DiagramFormat) string {
+	if format == domain.DiagramC4 {
+		return ".c4.mmd"
+	}
+	return ".mmd"
+}
+
+// renderDiagram draws the graph's features as components and their
+// dependencies as edges. Features from other specs are grouped by file.
+func renderDiagram(spec *domain.ProductSpec, g *depGraph, format domain.DiagramFormat) (string, error) {
+	ids := make(map[string]string, len(g.keys))
+	for i, key := range g.keys {
+		ids[key] = fmt.Sprintf("f%d", i)
+	}
+
+	// Group nodes by spec file, the root spec first
+	var files []string
+	byFile := make(map[string][]*depNode)
+	for _, key := range g.keys {
+		n := g.nodes[key]
+		if _, ok := byFile[n.spec]; !ok {
+			files = append(files, n.spec)
+		}
+		byFile[n.spec] = append(byFile[n.spec], n)
+	}
+
+	var sb strings.Builder
+	switch format {
+	case domain.DiagramMermaid, "":
+		sb.WriteString("flowchart LR\n")
+		fmt.Fprintf(&sb, "  %%%% %s: an arrow points from a feature to the feature it depends on\n", spec.Name)
+		for i, file := range files {
+			fmt.Fprintf(&sb, "  subgraph s%d[\"%s\"]\n", i, mermaidText(file))
+			for _, n := range byFile[file] {
+				fmt.Fprintf(&sb, "    %s[\"%s\"]\n", ids[n.key], mermaidText(nodeLabel(n.feature, "<br/>")))
+				if class := priorityClass(n.feature.Priority); class != "" {
+					fmt.Fprintf(&sb, "    class %s %s\n", ids[n.key], class)
+				}
+			}
+			sb.WriteString("  end\n")
+		}
+		for _, key := range g.keys {
+			for _, dep := range g.nodes[key].deps {
+				fmt.Fprintf(&sb, "  %s --> %s\n", ids[key], ids[dep])
+			}
+		}
+		sb.WriteString("  classDef high stroke-width:3px\n")
+		sb.WriteString("  classDef low stroke-dasharray:4 2\n")
+
+	case domain.DiagramC4:
+		sb.WriteString("C4Component\n")
+		fmt.Fprintf(&sb, "  title Components of %s\n", c4Text(spec.Name))
+		for i, file := range files {
+			fmt.Fprintf(&sb, "  Container_Boundary(s%d, \"%s\") {\n", i, c4Text(file))
+			for _, n := range byFile[file] {
+				technology := ""
+				if n.feature.API != nil {
+					technology = strings.TrimSpace(n.feature.API.Method + " " + n.feature.API.Path)
+				}
+				fmt.Fprintf(&sb, "    Component(%s, \"%s\", \"%s\", \"%s\")\n",
+					ids[n.key], c4Text(n.feature.Title), c4Text(technology), c4Text(firstSentence(n.feature.Description)))
+			}
+			sb.WriteString("  }\n")
+		}
+		for _, key := range g.keys {
+			for _, dep := range g.nodes[key].deps {
+				fmt.Fprintf(&sb, "  Rel(%s, %s, \"depends on\")\n", ids[key], ids[dep])
+			}
+		}
+
+	default:
+		return "", fmt.Errorf("%w: %q (want %s or %s)", ErrUnknownDiagramFormat, format, domain.DiagramMermaid, domain.DiagramC4)
+	}
+	return sb.String(), nil
+}
+
+// diagramPath names the diagram file next to the spec:
+// .specs/auth.yaml -> .specs/auth.mmd
+func diagramPath(specPath string, format domain.DiagramFormat) string {
+	return strings.TrimSuffix(specPath, filepath.Ext(specPath)) + DiagramExtension(format)
+}
+
+// nodeLabel is the feature title, with the API endpoint on a second line
+func nodeLabel(f domain.Feature, lineBreak string) string {
+	label := f.Title
+	if label == "" {
+		label = f.ID
+	}
+	if f.API != nil && f.API.Path != "" {
+		label += lineBreak + strings.TrimSpace(f.API.Method+" "+f.API.Path)
+	}
+	return label
+}
+
+func priorityClass(p domain.Priority) string {
+	switch p {
+	case domain.PriorityHigh:
+		return "high"
+	case domain.PriorityLow:
+		return "low"
+	default:
+		return ""
+	}
+}
+
+func firstSentence(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if i := strings.Index(s, ". "); i >= 0 {
+		return s[:i+1]
+	}
+	return s
+}
+
+// mermaidText escapes a string for a quoted flowchart label
+func mermaidText(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+// c4Text makes a string safe inside a C4 macro argument, which has no
+// escape for double quotes
+func c4Text(s string) string {
+	return strings.ReplaceAll(s, `"`, "'")
+}
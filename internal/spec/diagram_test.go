@@ -0,0 +1,107 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestRenderDiagram_Mermaid(t *testing.T) {
+	spec := depSpec(".specs/app.yaml",
+		feature("dashboard", domain.PriorityHigh, "auth"),
+		feature("auth", domain.PriorityLow),
+	)
+	spec.Features[1].API = &domain.APISpec{Method: "POST", Path: "/login"}
+
+	text, err := renderDiagram(spec, buildDependencyGraph(spec, nil), domain.DiagramMermaid)
+	if err != nil {
+		t.Fatalf("renderDiagram() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"flowchart LR",
+		`subgraph s0[".specs/app.yaml"]`,
+		`f0["DASHBOARD"]`,
+		`f1["AUTH<br/>POST /login"]`,
+		"class f0 high",
+		"class f1 low",
+		"f0 --> f1",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("diagram missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestRenderDiagram_C4(t *testing.T) {
+	spec := depSpec(".specs/app.yaml", feature("checkout", "", "cart"), feature("cart", ""))
+	spec.Features[1].Description = `Holds "items". Persists across sessions.`
+
+	text, err := renderDiagram(spec, buildDependencyGraph(spec, nil), domain.DiagramC4)
+	if err != nil {
+		t.Fatalf("renderDiagram() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"C4Component",
+		`Container_Boundary(s0, ".specs/app.yaml")`,
+		`Component(f1, "CART", "", "Holds 'items'.")`,
+		`Rel(f0, f1, "depends on")`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("diagram missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestRenderDiagram_UnknownFormat(t *testing.T) {
+	spec := depSpec(".specs/app.yaml", feature("a", ""))
+	if _, err := renderDiagram(spec, buildDependencyGraph(spec, nil), "plantuml"); !errors.Is(err, ErrUnknownDiagramFormat) {
+		t.Errorf("renderDiagram() error = %v, want ErrUnknownDiagramFormat", err)
+	}
+}
+
+func TestService_Diagram(t *testing.T) {
+	service := setupTestService(t)
+	ctx := context.Background()
+
+	for _, s := range []*domain.ProductSpec{
+		depSpec(".specs/billing.yaml", feature("invoices", "")),
+		depSpec(".specs/app.yaml", feature("checkout", "", "billing.yaml#invoices")),
+	} {
+		if err := service.Save(ctx, s); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	diagram, err := service.Diagram(ctx, "app.yaml", "", true)
+	if err != nil {
+		t.Fatalf("Diagram() error = %v", err)
+	}
+	if diagram.Format != domain.DiagramMermaid {
+		t.Errorf("Format = %q, want mermaid", diagram.Format)
+	}
+	if !strings.Contains(diagram.Diagram, `subgraph s1[".specs/billing.yaml"]`) {
+		t.Errorf("external spec should get its own subgraph:\n%s", diagram.Diagram)
+	}
+	if diagram.Path != ".specs/app.mmd" {
+		t.Fatalf("Path = %q, want .specs/app.mmd", diagram.Path)
+	}
+
+	written, err := os.ReadFile(filepath.Join(service.store.basePath, diagram.Path))
+	if err != nil {
+		t.Fatalf("read written diagram: %v", err)
+	}
+	if string(written) != diagram.Diagram {
+		t.Error("written file differs from returned diagram")
+	}
+
+	if _, err := service.Diagram(ctx, "missing.yaml", "", false); !errors.Is(err, ErrSpecNotFound) {
+		t.Errorf("Diagram() on missing spec error = %v, want ErrSpecNotFound", err)
+	}
+}
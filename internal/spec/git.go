@@ -0,0 +1,61 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// ErrNoGit is returned when git is not installed or dir is not inside a
+// git work tree
+var ErrNoGit = errors.New("git repository not available")
+
+// maxTouchedCommits bounds the history scanned for touched files
+const maxTouchedCommits = 200
+
+// TouchedFiles lists the files changed by recent commits in dir whose
+// message mentions any of the terms, case-insensitively. Files are
+// relative to the repository root and sorted.
+func TouchedFiles(ctx context.Context, dir string, terms ...string) ([]string, error) {
+	if dir == "" {
+		return nil, ErrNoGit
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, ErrNoGit
+	}
+	if err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		return nil, ErrNoGit
+	}
+
+	args := []string{"-C", dir, "log", "-i", "--fixed-strings", "--name-only", "--format=",
+		fmt.Sprintf("--max-count=%d", maxTouchedCommits)}
+	grepped := false
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			args = append(args, "--grep="+term)
+			grepped = true
+		}
+	}
+	if !grepped {
+		return []string{}, nil
+	}
+
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	files := []string{}
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !seen[line] {
+			seen[line] = true
+			files = append(files, line)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
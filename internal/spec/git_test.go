@@ -0,0 +1,56 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTouchedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(file, msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(msg), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", file)
+		git("commit", "-q", "-m", msg)
+	}
+
+	git("init", "-q")
+	commit("reset.go", "Add password-reset token store")
+	commit("mail.go", "Send reset emails (Password-Reset)")
+	commit("login.go", "Add login form")
+
+	files, err := TouchedFiles(context.Background(), dir, "password-reset")
+	if err != nil {
+		t.Fatalf("TouchedFiles() error = %v", err)
+	}
+	if want := []string{"mail.go", "reset.go"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("TouchedFiles() = %v, want %v", files, want)
+	}
+}
+
+func TestTouchedFiles_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	_, err := TouchedFiles(context.Background(), t.TempDir(), "f-1")
+	if !errors.Is(err, ErrNoGit) {
+		t.Errorf("TouchedFiles() error = %v, want ErrNoGit", err)
+	}
+}
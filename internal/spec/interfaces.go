@@ -33,6 +33,23 @@ type SpecService interface {
 	// GetDrift returns detailed drift information
 	GetDrift(ctx context.Context, path string) (*DriftReport, error)
 
+	// History returns the spec's lock history, newest first
+	History(ctx context.Context, path string) ([]LockRecord, error)
+
+	// Check reports drift from the specs' locks and criteria the given
+	// files claim without evidence
+	Check(ctx context.Context, files []string) (*CheckReport, error)
+
+	// Verify checks a spec's validity, drift and criteria for CI
+	Verify(ctx context.Context, path string, requireComplete bool) (*VerifyReport, error)
+
+	// Plan returns the spec's features in dependency order
+	Plan(ctx context.Context, path string) (*domain.SpecPlan, error)
+
+	// Diagram renders a component diagram of the spec's features, and
+	// optionally writes it next to the spec
+	Diagram(ctx context.Context, path string, format domain.DiagramFormat, write bool) (*domain.SpecDiagram, error)
+
 	// Save persists changes to a spec
 	Save(ctx context.Context, spec *domain.ProductSpec) error
 
@@ -147,6 +147,57 @@ func CalculateDrift(spec *domain.ProductSpec, lock *domain.SpecLock) *DriftRepor
 	return report
 }
 
+// LockRecord is one entry in a spec's lock history
+type LockRecord struct {
+	SpecPath string          `json:"spec_path"`
+	Lock     domain.SpecLock `json:"lock"`
+	// Changes compares the lock with the previous one for the same spec;
+	// every feature counts as added in the first record
+	Changes DriftReport `json:"changes"`
+}
+
+// DiffLocks reports how next differs from prev. A nil prev treats every
+// feature in next as added.
+func DiffLocks(prev, next *domain.SpecLock) *DriftReport {
+	report := &DriftReport{
+		AddedFeatures:    []string{},
+		RemovedFeatures:  []string{},
+		ModifiedFeatures: []string{},
+	}
+	if prev == nil {
+		prev = &domain.SpecLock{}
+	}
+
+	if prev.Version != next.Version {
+		report.VersionChanged = true
+		report.OldVersion = prev.Version
+		report.NewVersion = next.Version
+	}
+
+	for id, locked := range prev.Features {
+		current, exists := next.Features[id]
+		switch {
+		case !exists:
+			report.RemovedFeatures = append(report.RemovedFeatures, id)
+		case current.Hash != locked.Hash:
+			report.ModifiedFeatures = append(report.ModifiedFeatures, id)
+		}
+	}
+	for id := range next.Features {
+		if _, exists := prev.Features[id]; !exists {
+			report.AddedFeatures = append(report.AddedFeatures, id)
+		}
+	}
+
+	sort.Strings(report.AddedFeatures)
+	sort.Strings(report.RemovedFeatures)
+	sort.Strings(report.ModifiedFeatures)
+
+	report.HasDrift = report.VersionChanged || prev.SpecHash != next.SpecHash ||
+		len(report.AddedFeatures)+len(report.RemovedFeatures)+len(report.ModifiedFeatures) > 0
+	return report
+}
+
 // DriftReport contains detailed drift information
 type DriftReport struct {
 	HasDrift         bool     `json:"has_drift"`
@@ -281,3 +281,43 @@ func TestCalculateDrift_AllDriftTypes(t *testing.T) {
 		t.Errorf("ModifiedFeatures = %v; want [feat-1]", report.ModifiedFeatures)
 	}
 }
+
+func TestDiffLocks(t *testing.T) {
+	prev := &domain.SpecLock{
+		Version:  "1.0.0",
+		SpecHash: "a",
+		Features: map[string]domain.LockedFeature{
+			"login":  {Hash: "1"},
+			"logout": {Hash: "2"},
+		},
+	}
+	next := &domain.SpecLock{
+		Version:  "1.1.0",
+		SpecHash: "b",
+		Features: map[string]domain.LockedFeature{
+			"login":  {Hash: "1b"},
+			"signup": {Hash: "3"},
+		},
+	}
+
+	report := DiffLocks(prev, next)
+	if !report.HasDrift || !report.VersionChanged || report.OldVersion != "1.0.0" || report.NewVersion != "1.1.0" {
+		t.Errorf("report = %+v", report)
+	}
+	if len(report.AddedFeatures) != 1 || report.AddedFeatures[0] != "signup" {
+		t.Errorf("AddedFeatures = %v", report.AddedFeatures)
+	}
+	if len(report.RemovedFeatures) != 1 || report.RemovedFeatures[0] != "logout" {
+		t.Errorf("RemovedFeatures = %v", report.RemovedFeatures)
+	}
+	if len(report.ModifiedFeatures) != 1 || report.ModifiedFeatures[0] != "login" {
+		t.Errorf("ModifiedFeatures = %v", report.ModifiedFeatures)
+	}
+
+	if first := DiffLocks(nil, next); len(first.AddedFeatures) != 2 || first.NewVersion != "1.1.0" {
+		t.Errorf("first lock diff = %+v", first)
+	}
+	if same := DiffLocks(next, next); same.HasDrift {
+		t.Errorf("identical locks reported drift: %+v", same)
+	}
+}
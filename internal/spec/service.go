@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/felixgeelhaar/temper/internal/domain"
@@ -22,10 +26,34 @@ type Service struct {
 
 // NewService creates a new spec service
 func NewService(basePath string) *Service {
+	return NewServiceIn(basePath, SpecDir)
+}
+
+// NewServiceIn creates a spec service for a workspace that keeps its specs
+// in specDir rather than .specs/
+func NewServiceIn(basePath, specDir string) *Service {
+	store := NewFileStoreIn(basePath, specDir)
+	validator := NewValidator()
+	validator.loadSpec = store.Load
 	return &Service{
-		store:     NewFileStore(basePath),
-		validator: NewValidator(),
+		store:     store,
+		validator: validator,
+	}
+}
+
+// Scoped returns the spec service for the monorepo package in dir, a
+// directory relative to the workspace root, when that package keeps its
+// own spec directory; its specs, lock and drift are then its own.
+// Otherwise s is returned.
+func (s *Service) Scoped(dir string) *Service {
+	if dir == "" {
+		return s
+	}
+	root := filepath.Join(s.store.BasePath(), filepath.FromSlash(dir))
+	if info, err := os.Stat(filepath.Join(root, s.store.specDir)); err != nil || !info.IsDir() {
+		return s
 	}
+	return NewServiceIn(root, s.store.specDir)
 }
 
 // GetWorkspaceRoot returns the workspace root path
@@ -137,9 +165,48 @@ func (s *Service) Lock(ctx context.Context, path string) (*domain.SpecLock, erro
 		return nil, fmt.Errorf("save lock: %w", err)
 	}
 
+	if err := s.recordLock(spec.FilePath, lock); err != nil {
+		return nil, fmt.Errorf("record lock history: %w", err)
+	}
+
 	return lock, nil
 }
 
+// recordLock appends the lock to the spec's history with a summary of
+// what changed since the spec was last locked
+func (s *Service) recordLock(specPath string, lock *domain.SpecLock) error {
+	history, err := s.store.LoadLockHistory(specPath)
+	if err != nil {
+		return err
+	}
+	var prev *domain.SpecLock
+	if len(history) > 0 {
+		prev = &history[len(history)-1].Lock
+	}
+
+	return s.store.AppendLockRecord(&LockRecord{
+		SpecPath: specPath,
+		Lock:     *lock,
+		Changes:  *DiffLocks(prev, lock),
+	})
+}
+
+// History returns the spec's lock history, newest first
+func (s *Service) History(ctx context.Context, path string) ([]LockRecord, error) {
+	history, err := s.store.LoadLockHistory(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		// Distinguish "never locked" from "no such spec"
+		if _, err := s.store.Load(path); err != nil {
+			return nil, err
+		}
+	}
+	slices.Reverse(history)
+	return history, nil
+}
+
 // VerifyLock checks if the spec matches its lock
 func (s *Service) VerifyLock(ctx context.Context, path string) (bool, []string, error) {
 	spec, err := s.store.Load(path)
@@ -186,6 +253,50 @@ func (s *Service) GetDrift(ctx context.Context, path string) (*DriftReport, erro
 	return CalculateDrift(spec, lock), nil
 }
 
+// Plan orders the spec's features, and the features they depend on in
+// other specs, so that each comes after its dependencies
+func (s *Service) Plan(ctx context.Context, path string) (*domain.SpecPlan, error) {
+	spec, err := s.store.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := buildDependencyGraph(spec, s.store.Load)
+	if cycle := graph.findCycle(); cycle != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> "))
+	}
+	return graph.plan(spec.FilePath), nil
+}
+
+// Diagram renders the spec's features and their dependencies, including
+// features of other specs they depend on. With write, the diagram is also
+// saved next to the spec (auth.yaml -> auth.mmd, or auth.c4.mmd for C4).
+func (s *Service) Diagram(ctx context.Context, path string, format domain.DiagramFormat, write bool) (*domain.SpecDiagram, error) {
+	if format == "" {
+		format = domain.DiagramMermaid
+	}
+	spec, err := s.store.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := buildDependencyGraph(spec, s.store.Load)
+	text, err := renderDiagram(spec, graph, format)
+	if err != nil {
+		return nil, err
+	}
+
+	diagram := &domain.SpecDiagram{SpecPath: spec.FilePath, Format: format, Diagram: text}
+	if write {
+		out, err := s.store.WriteFile(diagramPath(spec.FilePath, format), []byte(text))
+		if err != nil {
+			return nil, fmt.Errorf("write diagram: %w", err)
+		}
+		diagram.Path = out
+	}
+	return diagram, nil
+}
+
 // AddFeature adds a new feature to a spec
 func (s *Service) AddFeature(ctx context.Context, path, title, description string, priority domain.Priority) error {
 	spec, err := s.store.Load(path)
@@ -320,6 +320,71 @@ func TestService_Lock(t *testing.T) {
 	}
 }
 
+func TestService_History(t *testing.T) {
+	service := setupTestService(t)
+	ctx := context.Background()
+
+	spec := &domain.ProductSpec{
+		Name:     "Test Spec",
+		Version:  "1.0.0",
+		FilePath: "history.yaml",
+		Goals:    []string{"Implement user authentication"},
+		Features: []domain.Feature{
+			{ID: "login", Title: "Login", Description: "User can log in", SuccessCriteria: []string{"User can log in"}},
+		},
+		AcceptanceCriteria: []domain.AcceptanceCriterion{
+			{ID: "ac-1", Description: "User can log in with valid credentials"},
+		},
+	}
+	if err := service.Save(ctx, spec); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	history, err := service.History(ctx, "history.yaml")
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(history) != 0 {
+		t.Fatalf("unlocked spec has %d history entries", len(history))
+	}
+
+	if _, err := service.Lock(ctx, "history.yaml"); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	spec.Version = "1.1.0"
+	spec.Features = append(spec.Features, domain.Feature{
+		ID: "logout", Title: "Logout", Description: "User can log out", SuccessCriteria: []string{"Session ends"},
+	})
+	if err := service.Save(ctx, spec); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := service.Lock(ctx, "history.yaml"); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	history, err = service.History(ctx, ".specs/history.yaml")
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("History() has %d entries, want 2", len(history))
+	}
+
+	latest, first := history[0], history[1]
+	if latest.Lock.Version != "1.1.0" || len(latest.Changes.AddedFeatures) != 1 || latest.Changes.AddedFeatures[0] != "logout" {
+		t.Errorf("latest = %+v", latest)
+	}
+	if len(first.Changes.AddedFeatures) != 1 || first.Changes.AddedFeatures[0] != "login" {
+		t.Errorf("first = %+v", first)
+	}
+
+	// A spec that never existed is not found rather than empty
+	if _, err := service.History(ctx, "missing.yaml"); err != ErrSpecNotFound {
+		t.Errorf("History(missing) error = %v, want ErrSpecNotFound", err)
+	}
+}
+
 func TestService_Lock_InvalidSpec(t *testing.T) {
 	service := setupTestService(t)
 	ctx := context.Background()
@@ -713,3 +778,37 @@ func TestService_UpdateFromLock(t *testing.T) {
 		t.Error("UpdatedAt should be set")
 	}
 }
+
+func TestService_Scoped(t *testing.T) {
+	service := setupTestService(t)
+	ctx := context.Background()
+	root := service.GetWorkspaceRoot()
+
+	if got := service.Scoped("services/billing"); got != service {
+		t.Error("a package without its own specs should use the workspace's")
+	}
+
+	auth := filepath.Join(root, "services", "auth")
+	if err := os.MkdirAll(filepath.Join(auth, SpecDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	scoped := service.Scoped("services/auth")
+	if scoped.GetWorkspaceRoot() != auth {
+		t.Fatalf("scoped root = %q; want %q", scoped.GetWorkspaceRoot(), auth)
+	}
+
+	// The workspace's lock doesn't leak into the package's drift
+	spec := &domain.ProductSpec{Name: "Auth", Version: "1.0.0", FilePath: "auth.yaml",
+		Features: []domain.Feature{{ID: "login", Title: "Login"}}}
+	service.Save(ctx, spec)
+	service.Lock(ctx, spec.FilePath)
+	scoped.Save(ctx, spec)
+
+	drift, err := scoped.GetDrift(ctx, "auth.yaml")
+	if err != nil {
+		t.Fatalf("GetDrift() error = %v", err)
+	}
+	if !drift.HasDrift || len(drift.AddedFeatures) != 1 {
+		t.Errorf("scoped drift = %+v; want the unlocked feature reported", drift)
+	}
+}
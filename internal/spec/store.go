@@ -18,6 +18,8 @@ const (
 	SpecDir = ".specs"
 	// LockFile is the name of the lock file
 	LockFile = "spec.lock"
+	// HistoryDir holds each spec's lock history, under SpecDir
+	HistoryDir = "history"
 )
 
 var (
@@ -28,11 +30,21 @@ var (
 // FileStore manages specs in the .specs/ directory
 type FileStore struct {
 	basePath string // workspace root
+	specDir  string // spec directory within the workspace
 }
 
 // NewFileStore creates a new file store for a workspace
 func NewFileStore(basePath string) *FileStore {
-	return &FileStore{basePath: basePath}
+	return NewFileStoreIn(basePath, SpecDir)
+}
+
+// NewFileStoreIn creates a file store keeping specs in specDir, relative
+// to the workspace root, instead of .specs/
+func NewFileStoreIn(basePath, specDir string) *FileStore {
+	if specDir == "" {
+		specDir = SpecDir
+	}
+	return &FileStore{basePath: basePath, specDir: filepath.Clean(specDir)}
 }
 
 // BasePath returns the workspace root path
@@ -111,9 +123,25 @@ func (s *FileStore) Save(spec *domain.ProductSpec) error {
 	return nil
 }
 
+// WriteFile writes a generated artifact, such as a diagram, under the
+// spec directory and returns its workspace-relative path
+func (s *FileStore) WriteFile(path string, content []byte) (string, error) {
+	fullPath, cleanedPath, err := s.resolveSpecPath(path)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return "", fmt.Errorf("create spec directory: %w", err)
+	}
+	if err := os.WriteFile(fullPath, content, 0644); err != nil {
+		return "", err
+	}
+	return cleanedPath, nil
+}
+
 // List returns all specs in the .specs/ directory
 func (s *FileStore) List() ([]*domain.ProductSpec, error) {
-	specsDir := filepath.Join(s.basePath, SpecDir)
+	specsDir := filepath.Join(s.basePath, s.specDir)
 
 	// Check if .specs/ exists
 	if _, err := os.Stat(specsDir); os.IsNotExist(err) {
@@ -182,7 +210,7 @@ func (s *FileStore) Delete(path string) error {
 
 // LoadLock reads the spec.lock file
 func (s *FileStore) LoadLock() (*domain.SpecLock, error) {
-	lockPath := filepath.Join(s.basePath, SpecDir, LockFile)
+	lockPath := filepath.Join(s.basePath, s.specDir, LockFile)
 
 	content, err := os.ReadFile(lockPath)
 	if err != nil {
@@ -202,7 +230,7 @@ func (s *FileStore) LoadLock() (*domain.SpecLock, error) {
 
 // SaveLock writes the spec.lock file
 func (s *FileStore) SaveLock(lock *domain.SpecLock) error {
-	specsDir := filepath.Join(s.basePath, SpecDir)
+	specsDir := filepath.Join(s.basePath, s.specDir)
 	if err := os.MkdirAll(specsDir, 0755); err != nil {
 		return fmt.Errorf("create specs directory: %w", err)
 	}
@@ -221,9 +249,77 @@ func (s *FileStore) SaveLock(lock *domain.SpecLock) error {
 	return nil
 }
 
+// historyPath returns the lock history file for a spec, mirroring the
+// spec's location under .specs/history/
+func (s *FileStore) historyPath(specPath string) (string, string, error) {
+	_, rel, err := s.resolveSpecPath(specPath)
+	if err != nil {
+		return "", "", err
+	}
+	within := strings.TrimPrefix(rel, s.specDir+string(filepath.Separator))
+	return filepath.Join(s.basePath, s.specDir, HistoryDir, within+".jsonl"), rel, nil
+}
+
+// AppendLockRecord adds a record to the spec's lock history
+func (s *FileStore) AppendLockRecord(record *LockRecord) error {
+	path, _, err := s.historyPath(record.SpecPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create history directory: %w", err)
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal lock record: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open history file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write history file: %w", err)
+	}
+	return nil
+}
+
+// LoadLockHistory returns a spec's lock records, oldest first. A spec
+// that was never locked has an empty history.
+func (s *FileStore) LoadLockHistory(specPath string) ([]LockRecord, error) {
+	path, _, err := s.historyPath(specPath)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []LockRecord{}, nil
+		}
+		return nil, fmt.Errorf("read history file: %w", err)
+	}
+
+	records := []LockRecord{}
+	for i, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var record LockRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, fmt.Errorf("parse history line %d: %w", i+1, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
 // EnsureSpecDir creates the .specs/ directory if it doesn't exist
 func (s *FileStore) EnsureSpecDir() error {
-	specsDir := filepath.Join(s.basePath, SpecDir)
+	specsDir := filepath.Join(s.basePath, s.specDir)
 	return os.MkdirAll(specsDir, 0755)
 }
 
@@ -260,8 +356,8 @@ func (s *FileStore) resolveSpecPath(relativePath string) (string, string, error)
 
 	cleaned = strings.TrimPrefix(cleaned, "."+sep)
 	rel := cleaned
-	if rel != SpecDir && !strings.HasPrefix(rel, SpecDir+sep) {
-		rel = filepath.Join(SpecDir, rel)
+	if rel != s.specDir && !strings.HasPrefix(rel, s.specDir+sep) {
+		rel = filepath.Join(s.specDir, rel)
 	}
 
 	fullPath := filepath.Join(s.basePath, rel)
@@ -2,6 +2,8 @@ package spec
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/felixgeelhaar/temper/internal/domain"
@@ -72,3 +74,28 @@ func TestFileStore_SaveNormalizesPath(t *testing.T) {
 		t.Fatalf("Load() name = %q, want %q", loaded.Name, spec.Name)
 	}
 }
+
+func TestFileStoreIn_CustomSpecDir(t *testing.T) {
+	root := t.TempDir()
+	store := NewFileStoreIn(root, "docs/specs")
+
+	spec := &domain.ProductSpec{Name: "Spec", Version: "1.0.0", FilePath: "auth.yaml"}
+	if err := store.Save(spec); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "docs", "specs", "auth.yaml")); err != nil {
+		t.Fatalf("spec not saved under docs/specs: %v", err)
+	}
+
+	for _, path := range []string{"auth.yaml", "docs/specs/auth.yaml"} {
+		if _, err := store.Load(path); err != nil {
+			t.Errorf("Load(%q) error = %v", path, err)
+		}
+	}
+	if specs, err := store.List(); err != nil || len(specs) != 1 {
+		t.Errorf("List() = %d specs, %v; want 1", len(specs), err)
+	}
+	if _, err := NewFileStore(root).Load("auth.yaml"); err == nil {
+		t.Error("default store should not see specs outside .specs/")
+	}
+}
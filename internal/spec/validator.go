@@ -9,7 +9,11 @@ import (
 )
 
 // Validator checks spec completeness and consistency
-type Validator struct{}
+type Validator struct {
+	// loadSpec resolves cross-spec depends_on references; when nil they
+	// are not checked
+	loadSpec specLoader
+}
 
 // NewValidator creates a new validator
 func NewValidator() *Validator {
@@ -39,6 +43,9 @@ func (v *Validator) Validate(spec *domain.ProductSpec) *domain.SpecValidation {
 	// Check milestones
 	v.checkMilestones(spec, validation)
 
+	// Check feature dependencies
+	v.checkDependencies(spec, validation)
+
 	// Identify potential ambiguities
 	v.identifyAmbiguities(spec, validation)
 
@@ -184,6 +191,15 @@ func (v *Validator) checkAcceptanceCriteria(spec *domain.ProductSpec, validation
 			validation.Warnings = append(validation.Warnings,
 				fmt.Sprintf("acceptance criterion %s may not be verifiable: %q", ac.ID, ac.Description))
 		}
+
+		// Check that linked features exist
+		for _, featID := range ac.Features {
+			if spec.GetFeature(featID) == nil {
+				validation.Errors = append(validation.Errors,
+					fmt.Sprintf("acceptance criterion %s references unknown feature: %s", ac.ID, featID))
+				validation.Valid = false
+			}
+		}
 	}
 }
 
@@ -235,6 +251,21 @@ func (v *Validator) checkMilestones(spec *domain.ProductSpec, validation *domain
 	}
 }
 
+func (v *Validator) checkDependencies(spec *domain.ProductSpec, validation *domain.SpecValidation) {
+	graph := buildDependencyGraph(spec, v.loadSpec)
+
+	for _, problem := range graph.problems {
+		validation.Errors = append(validation.Errors, problem)
+		validation.Valid = false
+	}
+
+	if cycle := graph.findCycle(); cycle != nil {
+		validation.Errors = append(validation.Errors,
+			fmt.Sprintf("feature dependency cycle: %s", strings.Join(cycle, " -> ")))
+		validation.Valid = false
+	}
+}
+
 func (v *Validator) identifyAmbiguities(spec *domain.ProductSpec, validation *domain.SpecValidation) {
 	// Check for ambiguous language patterns
 	ambiguousPatterns := []string{
@@ -193,6 +193,24 @@ func TestValidator_Validate_MilestoneUnknownFeature(t *testing.T) {
 	}
 }
 
+func TestValidator_Validate_CriterionUnknownFeature(t *testing.T) {
+	v := NewValidator()
+	spec := &domain.ProductSpec{
+		Name:     "Test",
+		Goals:    []string{"Goal"},
+		Features: []domain.Feature{{ID: "feat-1", Title: "Feature"}},
+		AcceptanceCriteria: []domain.AcceptanceCriterion{
+			{ID: "ac-1", Description: "Criterion", Features: []string{"feat-2"}},
+		},
+	}
+
+	result := v.Validate(spec)
+
+	if result.Valid {
+		t.Error("Validate() should be invalid for criterion referencing unknown feature")
+	}
+}
+
 func TestValidator_Validate_VagueGoal(t *testing.T) {
 	v := NewValidator()
 	spec := &domain.ProductSpec{
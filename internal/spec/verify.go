@@ -0,0 +1,96 @@
+package spec
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Criterion verification states
+const (
+	// CriterionVerified is satisfied, with evidence
+	CriterionVerified = "verified"
+
+	// CriterionUnverified is claimed satisfied without evidence
+	CriterionUnverified = "unverified"
+
+	// CriterionPending is not satisfied yet
+	CriterionPending = "pending"
+)
+
+// CriterionResult is the verification state of one acceptance criterion
+type CriterionResult struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	Evidence    string `json:"evidence,omitempty"`
+}
+
+// VerifyReport is the result of verifying a spec for CI
+type VerifyReport struct {
+	SpecPath string `json:"spec_path"`
+	Passed   bool   `json:"passed"`
+
+	// Failures says why the spec did not pass, one line each
+	Failures []string `json:"failures"`
+
+	Valid    bool              `json:"valid"`
+	Errors   []string          `json:"errors"`
+	Locked   bool              `json:"locked"`
+	Drift    *DriftReport      `json:"drift,omitempty"`
+	Criteria []CriterionResult `json:"criteria"`
+}
+
+// Verify checks a spec the way a pipeline should: it must validate, match
+// its latest lock if it was ever locked, and claim no criterion satisfied
+// without evidence. With requireComplete every criterion must also be
+// satisfied.
+func (s *Service) Verify(ctx context.Context, path string, requireComplete bool) (*VerifyReport, error) {
+	spec, err := s.store.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	validation := s.validator.Validate(spec)
+	report := &VerifyReport{
+		SpecPath: filepath.ToSlash(spec.FilePath),
+		Failures: []string{},
+		Valid:    validation.Valid,
+		Errors:   validation.Errors,
+		Criteria: []CriterionResult{},
+	}
+	if report.Errors == nil {
+		report.Errors = []string{}
+	}
+	if !validation.Valid {
+		report.Failures = append(report.Failures, "spec is invalid: "+strings.Join(validation.Errors, "; "))
+	}
+
+	drift, err := s.lockedDrift(spec)
+	if err != nil {
+		return nil, err
+	}
+	report.Locked = drift != nil
+	report.Drift = drift
+	if drift != nil && drift.HasDrift {
+		report.Failures = append(report.Failures, "spec "+describeDrift(drift))
+	}
+
+	for _, ac := range spec.AcceptanceCriteria {
+		result := CriterionResult{ID: ac.ID, Description: ac.Description, Status: CriterionPending, Evidence: ac.Evidence}
+		switch {
+		case ac.Satisfied && strings.TrimSpace(ac.Evidence) != "":
+			result.Status = CriterionVerified
+		case ac.Satisfied:
+			result.Status = CriterionUnverified
+			report.Failures = append(report.Failures, fmt.Sprintf("criterion %s is marked satisfied without evidence", ac.ID))
+		case requireComplete:
+			report.Failures = append(report.Failures, fmt.Sprintf("criterion %s is not satisfied", ac.ID))
+		}
+		report.Criteria = append(report.Criteria, result)
+	}
+
+	report.Passed = len(report.Failures) == 0
+	return report, nil
+}
@@ -0,0 +1,65 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestService_Verify(t *testing.T) {
+	service := setupTestService(t)
+	ctx := context.Background()
+
+	spec := checkTestSpec("auth.yaml")
+	spec.AcceptanceCriteria = append(spec.AcceptanceCriteria,
+		domain.AcceptanceCriterion{ID: "ac-2", Description: "User sees an error with invalid credentials"})
+	spec.AcceptanceCriteria[0].Satisfied = true
+	spec.AcceptanceCriteria[0].Evidence = "TestLogin passes"
+	if err := service.Save(ctx, spec); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	report, err := service.Verify(ctx, "auth.yaml", false)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !report.Passed || report.Locked || !report.Valid {
+		t.Fatalf("Verify() = %+v; want an unlocked, valid spec to pass", report)
+	}
+	if report.Criteria[0].Status != CriterionVerified || report.Criteria[1].Status != CriterionPending {
+		t.Errorf("criteria = %+v; want verified and pending", report.Criteria)
+	}
+
+	report, err = service.Verify(ctx, "auth.yaml", true)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if report.Passed || len(report.Failures) != 1 {
+		t.Errorf("Verify(requireComplete) = %+v; want the pending criterion to fail", report)
+	}
+
+	if _, err := service.Lock(ctx, "auth.yaml"); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	spec.Version = "1.1.0"
+	spec.AcceptanceCriteria[1].Satisfied = true
+	if err := service.Save(ctx, spec); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	report, err = service.Verify(ctx, "auth.yaml", false)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if report.Passed || !report.Locked || report.Drift == nil || !report.Drift.HasDrift {
+		t.Errorf("Verify() = %+v; want drift from the lock", report)
+	}
+	if len(report.Failures) != 2 || report.Criteria[1].Status != CriterionUnverified {
+		t.Errorf("failures = %v, criteria = %+v; want drift and the unverified ac-2", report.Failures, report.Criteria)
+	}
+
+	if _, err := service.Verify(ctx, "missing.yaml", false); !errors.Is(err, ErrSpecNotFound) {
+		t.Errorf("Verify(missing) error = %v; want ErrSpecNotFound", err)
+	}
+}
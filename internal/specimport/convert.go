@@ -0,0 +1,208 @@
+package specimport
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+var (
+	// "- [ ] text", "* [x] text"
+	checklistPattern = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.+)$`)
+	// "- text", "* text", "1. text", Jira "# text"
+	bulletPattern = regexp.MustCompile(`^\s*(?:[-*+#]+|[0-9]+[.)])\s+(.+)$`)
+	// "## Heading" in Markdown, "h2. Heading" in Jira markup
+	markdownHeading = regexp.MustCompile(`^\s*#{1,6}\s+(.+?)\s*#*\s*$`)
+	jiraHeading     = regexp.MustCompile(`^\s*h[1-6]\.\s+(.+?)\s*$`)
+	// A line that is only bold or ends in a colon: "**Acceptance criteria**", "Acceptance criteria:"
+	labelLinePattern = regexp.MustCompile(`^\s*(?:\*{1,2}(.+?):?\*{1,2}|([A-Za-z][^:]{0,60}):)\s*$`)
+	htmlComment      = regexp.MustCompile(`(?s)<!--.*?-->`)
+	slugPattern      = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// ToSpec builds a spec scaffold from an issue. The issue becomes a single
+// feature; checklist items, and bullets under an "Acceptance criteria"
+// heading, become acceptance criteria (checked items start satisfied) and
+// the feature's success criteria. Priority labels set the feature
+// priority; other labels are kept on the feature.
+func ToSpec(issue *Issue) *domain.ProductSpec {
+	summary, criteria := parseBody(issue.Body, issue.Source)
+	priority, labels := mapLabels(issue.Labels)
+
+	goal := summary
+	if goal == "" {
+		goal = issue.Title
+	}
+
+	feature := domain.Feature{
+		ID:              slugify(issue.Title),
+		Title:           issue.Title,
+		Description:     summary,
+		Priority:        priority,
+		SuccessCriteria: make([]string, 0, len(criteria)),
+		Labels:          labels,
+	}
+	if feature.ID == "" {
+		feature.ID = slugify(issue.Key)
+	}
+
+	acs := make([]domain.AcceptanceCriterion, 0, len(criteria))
+	for i, c := range criteria {
+		ac := domain.AcceptanceCriterion{
+			ID:          fmt.Sprintf("ac-%03d", i+1),
+			Description: c.text,
+			Satisfied:   c.checked,
+		}
+		if c.checked {
+			ac.Evidence = "checked in " + issue.Key
+		}
+		acs = append(acs, ac)
+		feature.SuccessCriteria = append(feature.SuccessCriteria, c.text)
+	}
+
+	return &domain.ProductSpec{
+		Name:     issue.Title,
+		Version:  "0.1.0",
+		Goals:    []string{goal},
+		Features: []domain.Feature{feature},
+		NonFunctional: domain.NonFunctionalReqs{
+			Performance: []string{},
+			Security:    []string{},
+			Scalability: []string{},
+		},
+		AcceptanceCriteria: acs,
+		Milestones:         []domain.Milestone{},
+		Source:             issue.URL,
+		Tracker:            &domain.TrackerLink{System: string(issue.Source), Key: issue.Key},
+		FilePath:           SpecFileName(issue),
+	}
+}
+
+// SpecFileName names the spec file after the issue key and title, e.g.
+// "acme-api-123-add-login.yaml"
+func SpecFileName(issue *Issue) string {
+	name := slugify(issue.Key + " " + issue.Title)
+	if name == "" {
+		name = "imported"
+	}
+	return name + ".yaml"
+}
+
+type criterion struct {
+	text    string
+	checked bool
+}
+
+// parseBody returns the first prose paragraph and the acceptance criteria
+// found in a Markdown or, for Jira, wiki markup body
+func parseBody(body string, source Source) (string, []criterion) {
+	body = htmlComment.ReplaceAllString(strings.ReplaceAll(body, "\r\n", "\n"), "")
+
+	var (
+		summary    []string
+		summaryEnd bool
+		inCriteria bool
+		criteria   []criterion
+	)
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if m := checklistPattern.FindStringSubmatch(line); m != nil {
+			criteria = append(criteria, criterion{text: strings.TrimSpace(m[2]), checked: m[1] != " "})
+			summaryEnd = summaryEnd || len(summary) > 0
+			continue
+		}
+		if heading, ok := sectionHeading(line, source); ok {
+			inCriteria = isCriteriaHeading(heading)
+			summaryEnd = summaryEnd || len(summary) > 0
+			continue
+		}
+		if m := bulletPattern.FindStringSubmatch(line); m != nil {
+			if inCriteria {
+				criteria = append(criteria, criterion{text: strings.TrimSpace(m[1])})
+			}
+			summaryEnd = summaryEnd || len(summary) > 0
+			continue
+		}
+		if trimmed == "" {
+			summaryEnd = summaryEnd || len(summary) > 0
+			continue
+		}
+		if !summaryEnd && !inCriteria {
+			summary = append(summary, trimmed)
+		}
+	}
+	return strings.Join(summary, " "), criteria
+}
+
+// sectionHeading recognizes headings and heading-like label lines. In
+// Jira markup "# " starts a numbered list item, not a heading.
+func sectionHeading(line string, source Source) (string, bool) {
+	heading := markdownHeading
+	if source == SourceJira {
+		heading = jiraHeading
+	}
+	if m := heading.FindStringSubmatch(line); m != nil {
+		return m[1], true
+	}
+	if m := labelLinePattern.FindStringSubmatch(line); m != nil {
+		if m[1] != "" {
+			return m[1], true
+		}
+		return m[2], true
+	}
+	return "", false
+}
+
+func isCriteriaHeading(heading string) bool {
+	h := strings.ToLower(strings.Trim(heading, "*_: "))
+	return strings.Contains(h, "acceptance") || h == "criteria" || h == "definition of done"
+}
+
+// mapLabels picks the priority out of the labels and returns the rest.
+// Recognizes "high", "priority:high", "priority/high", "P1" and the like.
+func mapLabels(labels []string) (domain.Priority, []string) {
+	priority := domain.PriorityMedium
+	found := false
+	var rest []string
+	for _, label := range labels {
+		if p, ok := labelPriority(label); ok {
+			if !found {
+				priority, found = p, true
+			}
+			continue
+		}
+		rest = append(rest, label)
+	}
+	return priority, rest
+}
+
+func labelPriority(label string) (domain.Priority, bool) {
+	l := strings.ToLower(strings.TrimSpace(label))
+	for _, prefix := range []string{"priority:", "priority/", "priority-", "priority ", "prio:", "prio/"} {
+		if strings.HasPrefix(l, prefix) {
+			l = strings.TrimSpace(strings.TrimPrefix(l, prefix))
+			break
+		}
+	}
+	switch l {
+	case "p0", "p1", "critical", "urgent", "blocker", "highest", "high":
+		return domain.PriorityHigh, true
+	case "p2", "medium", "normal":
+		return domain.PriorityMedium, true
+	case "p3", "p4", "low", "lowest", "minor", "trivial":
+		return domain.PriorityLow, true
+	}
+	return "", false
+}
+
+func slugify(s string) string {
+	s = slugPattern.ReplaceAllString(strings.ToLower(s), "-")
+	s = strings.Trim(s, "-")
+	if len(s) > 60 {
+		s = strings.TrimRight(s[:60], "-")
+	}
+	return s
+}
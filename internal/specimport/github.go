@@ -0,0 +1,155 @@
+package specimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultGitHubAPIURL is the public GitHub REST API
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+var githubRefPattern = regexp.MustCompile(`^([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+)#([0-9]+)$`)
+
+// GitHubClient fetches issues from the GitHub REST API. The token is
+// optional for public repositories.
+type GitHubClient struct {
+	BaseURL string // defaults to DefaultGitHubAPIURL; set for GitHub Enterprise
+	Token   string
+	HTTP    *http.Client
+}
+
+// ParseGitHubRef splits "owner/repo#123" into its parts
+func ParseGitHubRef(ref string) (owner, repo string, number int, err error) {
+	m := githubRefPattern.FindStringSubmatch(strings.TrimSpace(ref))
+	if m == nil {
+		return "", "", 0, fmt.Errorf("%w: %q (want owner/repo#123)", ErrInvalidRef, ref)
+	}
+	number, err = strconv.Atoi(m[3])
+	if err != nil || number <= 0 {
+		return "", "", 0, fmt.Errorf("%w: %q (want owner/repo#123)", ErrInvalidRef, ref)
+	}
+	return m[1], m[2], number, nil
+}
+
+// Fetch reads the issue named by ref ("owner/repo#123")
+func (c *GitHubClient) Fetch(ctx context.Context, ref string) (*Issue, error) {
+	owner, repo, number, err := ParseGitHubRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.issueURL(owner, repo, number), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.authorize(req)
+
+	client := c.HTTP
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch issue: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := checkStatus(resp, ref); err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Title   string `json:"title"`
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+		Labels  []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		PullRequest json.RawMessage `json:"pull_request"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode issue: %w", err)
+	}
+	if len(raw.PullRequest) > 0 {
+		return nil, fmt.Errorf("%w: %s is a pull request", ErrInvalidRef, ref)
+	}
+
+	issue := &Issue{
+		Source: SourceGitHub,
+		Key:    fmt.Sprintf("%s/%s#%d", owner, repo, number),
+		URL:    raw.HTMLURL,
+		Title:  strings.TrimSpace(raw.Title),
+		Body:   raw.Body,
+	}
+	for _, l := range raw.Labels {
+		issue.Labels = append(issue.Labels, l.Name)
+	}
+	return issue, nil
+}
+
+// UpdateBody replaces the issue description
+func (c *GitHubClient) UpdateBody(ctx context.Context, ref, body string) error {
+	owner, repo, number, err := ParseGitHubRef(ref)
+	if err != nil {
+		return err
+	}
+	resp, err := sendJSON(ctx, c.HTTP, http.MethodPatch, c.issueURL(owner, repo, number),
+		map[string]string{"body": body}, c.authorize)
+	if err != nil {
+		return fmt.Errorf("update issue: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return checkWrite(resp, ref)
+}
+
+// Comment adds a comment to the issue
+func (c *GitHubClient) Comment(ctx context.Context, ref, text string) error {
+	owner, repo, number, err := ParseGitHubRef(ref)
+	if err != nil {
+		return err
+	}
+	resp, err := sendJSON(ctx, c.HTTP, http.MethodPost, c.issueURL(owner, repo, number)+"/comments",
+		map[string]string{"body": text}, c.authorize)
+	if err != nil {
+		return fmt.Errorf("comment on issue: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return checkWrite(resp, ref)
+}
+
+func (c *GitHubClient) issueURL(owner, repo string, number int) string {
+	base := strings.TrimRight(c.BaseURL, "/")
+	if base == "" {
+		base = DefaultGitHubAPIURL
+	}
+	return fmt.Sprintf("%s/repos/%s/%s/issues/%d", base, owner, repo, number)
+}
+
+func (c *GitHubClient) authorize(req *http.Request) {
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+}
+
+// checkStatus maps tracker HTTP errors onto the package errors
+func checkStatus(resp *http.Response, ref string) error {
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return fmt.Errorf("%w: %s", ErrIssueNotFound, ref)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%w (status %d)", ErrUnauthorized, resp.StatusCode)
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("fetch %s: status=%d body=%s", ref, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+}
@@ -0,0 +1,76 @@
+// Package specimport turns issue tracker tickets into Specular spec
+// scaffolds. Fetchers read a GitHub issue or Jira ticket; ToSpec maps its
+// title, description, acceptance criteria checklist and labels onto a
+// domain.ProductSpec for the author to refine. SyncCriteria reports
+// criteria satisfied in Temper back to the issue.
+package specimport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrInvalidRef    = errors.New("invalid issue reference")
+	ErrIssueNotFound = errors.New("issue not found")
+	ErrUnauthorized  = errors.New("issue tracker rejected the credentials")
+	ErrNotConfigured = errors.New("issue tracker not configured")
+)
+
+// Source identifies the tracker an issue came from
+type Source string
+
+const (
+	SourceGitHub Source = "github"
+	SourceJira   Source = "jira"
+)
+
+// Issue is a tracker ticket reduced to the fields a spec is built from
+type Issue struct {
+	Source Source
+	Key    string // "owner/repo#123" or "PROJ-42"
+	URL    string // human-facing link, recorded as the spec's source
+	Title  string
+	Body   string // Markdown (GitHub) or wiki markup (Jira)
+	Labels []string
+}
+
+// defaultHTTPClient bounds tracker calls so a hung API cannot stall the
+// request that triggered the import
+func defaultHTTPClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// sendJSON sends payload as a JSON request body. auth sets credentials on
+// the request; the caller closes the response body.
+func sendJSON(ctx context.Context, client *http.Client, method, url string, payload any, auth func(*http.Request)) (*http.Response, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	auth(req)
+
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	return client.Do(req)
+}
+
+// checkWrite maps a write's HTTP status onto the package errors
+func checkWrite(resp *http.Response, ref string) error {
+	if resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return checkStatus(resp, ref)
+}
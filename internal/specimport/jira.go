@@ -0,0 +1,134 @@
+package specimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var jiraKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]+-[0-9]+$`)
+
+// JiraClient fetches tickets from the Jira REST API v2, which returns
+// descriptions as wiki markup. Jira Cloud authenticates with the account
+// email and an API token.
+type JiraClient struct {
+	BaseURL string // e.g. https://acme.atlassian.net
+	Email   string
+	Token   string
+	HTTP    *http.Client
+}
+
+// Fetch reads the ticket with the given key ("PROJ-42")
+func (c *JiraClient) Fetch(ctx context.Context, key string) (*Issue, error) {
+	key, base, err := c.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,description,labels,priority", base, key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	c.authorize(req)
+
+	client := c.HTTP
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch ticket: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := checkStatus(resp, key); err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary     string   `json:"summary"`
+			Description string   `json:"description"`
+			Labels      []string `json:"labels"`
+			Priority    *struct {
+				Name string `json:"name"`
+			} `json:"priority"`
+		} `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode ticket: %w", err)
+	}
+	if raw.Key != "" {
+		key = raw.Key
+	}
+
+	issue := &Issue{
+		Source: SourceJira,
+		Key:    key,
+		URL:    base + "/browse/" + key,
+		Title:  strings.TrimSpace(raw.Fields.Summary),
+		Body:   raw.Fields.Description,
+		Labels: raw.Fields.Labels,
+	}
+	// Jira priority is a field, not a label; fold it in so ToSpec maps
+	// both trackers the same way
+	if raw.Fields.Priority != nil && raw.Fields.Priority.Name != "" {
+		issue.Labels = append(issue.Labels, "priority:"+strings.ToLower(raw.Fields.Priority.Name))
+	}
+	return issue, nil
+}
+
+// UpdateBody replaces the ticket description
+func (c *JiraClient) UpdateBody(ctx context.Context, key, body string) error {
+	key, base, err := c.resolve(key)
+	if err != nil {
+		return err
+	}
+	payload := map[string]any{"fields": map[string]string{"description": body}}
+	resp, err := sendJSON(ctx, c.HTTP, http.MethodPut, base+"/rest/api/2/issue/"+key, payload, c.authorize)
+	if err != nil {
+		return fmt.Errorf("update ticket: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return checkWrite(resp, key)
+}
+
+// Comment adds a comment to the ticket
+func (c *JiraClient) Comment(ctx context.Context, key, text string) error {
+	key, base, err := c.resolve(key)
+	if err != nil {
+		return err
+	}
+	resp, err := sendJSON(ctx, c.HTTP, http.MethodPost, base+"/rest/api/2/issue/"+key+"/comment",
+		map[string]string{"body": text}, c.authorize)
+	if err != nil {
+		return fmt.Errorf("comment on ticket: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return checkWrite(resp, key)
+}
+
+// resolve normalizes the key and checks the client is configured
+func (c *JiraClient) resolve(key string) (string, string, error) {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	if !jiraKeyPattern.MatchString(key) {
+		return "", "", fmt.Errorf("%w: %q (want PROJ-123)", ErrInvalidRef, key)
+	}
+	base := strings.TrimRight(c.BaseURL, "/")
+	if base == "" {
+		return "", "", fmt.Errorf("%w: set integrations.jira.url", ErrNotConfigured)
+	}
+	return key, base, nil
+}
+
+func (c *JiraClient) authorize(req *http.Request) {
+	if c.Token != "" {
+		req.SetBasicAuth(c.Email, c.Token)
+	}
+}
@@ -0,0 +1,337 @@
+package specimport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+func TestParseGitHubRef(t *testing.T) {
+	owner, repo, number, err := ParseGitHubRef("acme/api-server#123")
+	if err != nil {
+		t.Fatalf("ParseGitHubRef() error = %v", err)
+	}
+	if owner != "acme" || repo != "api-server" || number != 123 {
+		t.Errorf("got %s/%s#%d, want acme/api-server#123", owner, repo, number)
+	}
+
+	for _, bad := range []string{"", "acme/api", "acme#1", "acme/api#x", "acme/api#0", "https://github.com/acme/api/issues/1"} {
+		if _, _, _, err := ParseGitHubRef(bad); !errors.Is(err, ErrInvalidRef) {
+			t.Errorf("ParseGitHubRef(%q) error = %v, want ErrInvalidRef", bad, err)
+		}
+	}
+}
+
+func TestToSpec_GitHubChecklist(t *testing.T) {
+	issue := &Issue{
+		Source: SourceGitHub,
+		Key:    "acme/api#42",
+		URL:    "https://github.com/acme/api/issues/42",
+		Title:  "Add password reset",
+		Body: `<!-- template: feature -->
+Users who forget their password should be able to reset it
+by email.
+
+## Acceptance criteria
+- [ ] Reset link expires after 30 minutes
+- [x] Link is single use
+- Unknown emails get the same response as known ones
+
+## Notes
+- not a criterion
+`,
+		Labels: []string{"enhancement", "priority:high", "auth"},
+	}
+
+	spec := ToSpec(issue)
+
+	if spec.Name != "Add password reset" {
+		t.Errorf("Name = %q", spec.Name)
+	}
+	if spec.Source != issue.URL {
+		t.Errorf("Source = %q, want %q", spec.Source, issue.URL)
+	}
+	if spec.FilePath != "acme-api-42-add-password-reset.yaml" {
+		t.Errorf("FilePath = %q", spec.FilePath)
+	}
+	wantGoal := "Users who forget their password should be able to reset it by email."
+	if len(spec.Goals) != 1 || spec.Goals[0] != wantGoal {
+		t.Errorf("Goals = %q, want [%q]", spec.Goals, wantGoal)
+	}
+
+	wantCriteria := []domain.AcceptanceCriterion{
+		{ID: "ac-001", Description: "Reset link expires after 30 minutes"},
+		{ID: "ac-002", Description: "Link is single use", Satisfied: true, Evidence: "checked in acme/api#42"},
+		{ID: "ac-003", Description: "Unknown emails get the same response as known ones"},
+	}
+	if !reflect.DeepEqual(spec.AcceptanceCriteria, wantCriteria) {
+		t.Errorf("AcceptanceCriteria = %+v, want %+v", spec.AcceptanceCriteria, wantCriteria)
+	}
+
+	if len(spec.Features) != 1 {
+		t.Fatalf("Features = %d, want 1", len(spec.Features))
+	}
+	feat := spec.Features[0]
+	if feat.ID != "add-password-reset" || feat.Priority != domain.PriorityHigh {
+		t.Errorf("feature = %s (%s), want add-password-reset (high)", feat.ID, feat.Priority)
+	}
+	if !reflect.DeepEqual(feat.Labels, []string{"enhancement", "auth"}) {
+		t.Errorf("Labels = %v, want [enhancement auth]", feat.Labels)
+	}
+	if len(feat.SuccessCriteria) != 3 {
+		t.Errorf("SuccessCriteria = %v, want the 3 criteria", feat.SuccessCriteria)
+	}
+}
+
+func TestToSpec_JiraMarkup(t *testing.T) {
+	issue := &Issue{
+		Source: SourceJira,
+		Key:    "SHOP-7",
+		Title:  "Checkout with saved cards",
+		Body: "h2. Summary\r\nReturning customers pay with a stored card.\r\n\r\n" +
+			"h2. Acceptance Criteria\r\n# Saved cards are listed at checkout\r\n# CVC is asked again\r\n",
+		Labels: []string{"payments", "priority:low"},
+	}
+
+	spec := ToSpec(issue)
+
+	if spec.Goals[0] != "Returning customers pay with a stored card." {
+		t.Errorf("Goals = %q", spec.Goals)
+	}
+	if len(spec.AcceptanceCriteria) != 2 || spec.AcceptanceCriteria[1].Description != "CVC is asked again" {
+		t.Errorf("AcceptanceCriteria = %+v", spec.AcceptanceCriteria)
+	}
+	if spec.Features[0].Priority != domain.PriorityLow {
+		t.Errorf("Priority = %s, want low", spec.Features[0].Priority)
+	}
+}
+
+func TestToSpec_EmptyBody(t *testing.T) {
+	spec := ToSpec(&Issue{Source: SourceGitHub, Key: "a/b#1", Title: "Dark mode"})
+
+	if spec.Goals[0] != "Dark mode" {
+		t.Errorf("Goals = %q, want the title", spec.Goals)
+	}
+	if len(spec.AcceptanceCriteria) != 0 {
+		t.Errorf("AcceptanceCriteria = %+v, want none", spec.AcceptanceCriteria)
+	}
+	if spec.Features[0].Priority != domain.PriorityMedium {
+		t.Errorf("Priority = %s, want medium default", spec.Features[0].Priority)
+	}
+}
+
+func TestMapLabels(t *testing.T) {
+	tests := []struct {
+		label string
+		want  domain.Priority
+	}{
+		{"P0", domain.PriorityHigh},
+		{"priority/critical", domain.PriorityHigh},
+		{"Priority: Medium", domain.PriorityMedium},
+		{"p3", domain.PriorityLow},
+		{"bug", domain.PriorityMedium},
+	}
+	for _, tt := range tests {
+		if got, _ := mapLabels([]string{tt.label}); got != tt.want {
+			t.Errorf("mapLabels(%q) = %s, want %s", tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestGitHubClient_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/api/issues/42" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q", got)
+		}
+		_, _ = w.Write([]byte(`{"title":"Add password reset","body":"- [ ] works","html_url":"https://github.com/acme/api/issues/42","labels":[{"name":"P1"}]}`))
+	}))
+	defer srv.Close()
+
+	c := &GitHubClient{BaseURL: srv.URL, Token: "tok"}
+	issue, err := c.Fetch(context.Background(), "acme/api#42")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if issue.Title != "Add password reset" || issue.Key != "acme/api#42" || !reflect.DeepEqual(issue.Labels, []string{"P1"}) {
+		t.Errorf("issue = %+v", issue)
+	}
+
+	if _, err := c.Fetch(context.Background(), "acme/api#43"); !errors.Is(err, ErrIssueNotFound) {
+		t.Errorf("Fetch(missing) error = %v, want ErrIssueNotFound", err)
+	}
+}
+
+func TestGitHubClient_RejectsPullRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"title":"PR","pull_request":{"url":"x"}}`))
+	}))
+	defer srv.Close()
+
+	c := &GitHubClient{BaseURL: srv.URL}
+	if _, err := c.Fetch(context.Background(), "acme/api#1"); !errors.Is(err, ErrInvalidRef) {
+		t.Errorf("Fetch(pull request) error = %v, want ErrInvalidRef", err)
+	}
+}
+
+func TestJiraClient_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "dev@acme.test" || pass != "tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"key":"SHOP-7","fields":{"summary":"Saved cards","description":"Pay faster.","labels":["payments"],"priority":{"name":"High"}}}`))
+	}))
+	defer srv.Close()
+
+	c := &JiraClient{BaseURL: srv.URL, Email: "dev@acme.test", Token: "tok"}
+	issue, err := c.Fetch(context.Background(), "shop-7")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if issue.Key != "SHOP-7" || issue.URL != srv.URL+"/browse/SHOP-7" {
+		t.Errorf("issue = %+v", issue)
+	}
+	if !reflect.DeepEqual(issue.Labels, []string{"payments", "priority:high"}) {
+		t.Errorf("Labels = %v", issue.Labels)
+	}
+
+	c.Token = "wrong"
+	if _, err := c.Fetch(context.Background(), "SHOP-7"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Fetch(bad token) error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestJiraClient_NotConfigured(t *testing.T) {
+	c := &JiraClient{}
+	if _, err := c.Fetch(context.Background(), "SHOP-7"); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("Fetch() error = %v, want ErrNotConfigured", err)
+	}
+}
+
+type fakeTracker struct {
+	body     string
+	updated  string
+	comments []string
+}
+
+func (f *fakeTracker) Fetch(ctx context.Context, key string) (*Issue, error) {
+	return &Issue{Key: key, Body: f.body}, nil
+}
+
+func (f *fakeTracker) UpdateBody(ctx context.Context, key, body string) error {
+	f.updated = body
+	return nil
+}
+
+func (f *fakeTracker) Comment(ctx context.Context, key, text string) error {
+	f.comments = append(f.comments, text)
+	return nil
+}
+
+func syncSpec(mode string, synced ...string) *domain.ProductSpec {
+	return &domain.ProductSpec{
+		Name:    "Password reset",
+		Tracker: &domain.TrackerLink{System: "github", Key: "acme/api#42", Sync: mode, Synced: synced},
+		AcceptanceCriteria: []domain.AcceptanceCriterion{
+			{ID: "ac-001", Description: "Reset link expires after 30 minutes", Satisfied: true},
+			{ID: "ac-002", Description: "Link is single use", Satisfied: true, Evidence: "TestSingleUse"},
+			{ID: "ac-003", Description: "Rate limited", Satisfied: false},
+			{ID: "ac-004", Description: "Audit log entry", Satisfied: true},
+		},
+	}
+}
+
+func TestSyncCriteria_Checklist(t *testing.T) {
+	tracker := &fakeTracker{body: "Intro\r\n\r\n- [ ] Reset link expires after 30 minutes\r\n- [x] link is  single use\r\n- [ ] Rate limited\r\n"}
+
+	result, err := SyncCriteria(context.Background(), tracker, syncSpec(domain.TrackerSyncChecklist))
+	if err != nil {
+		t.Fatalf("SyncCriteria() error = %v", err)
+	}
+
+	wantBody := "Intro\r\n\r\n- [x] Reset link expires after 30 minutes\r\n- [x] link is  single use\r\n- [ ] Rate limited\r\n"
+	if tracker.updated != wantBody {
+		t.Errorf("updated body = %q, want %q", tracker.updated, wantBody)
+	}
+	if !reflect.DeepEqual(result.Checked, []string{"ac-001", "ac-002"}) {
+		t.Errorf("Checked = %v", result.Checked)
+	}
+	if !reflect.DeepEqual(result.Commented, []string{"ac-004"}) {
+		t.Errorf("Commented = %v, want [ac-004] (no checklist item)", result.Commented)
+	}
+	if len(tracker.comments) != 1 || !strings.Contains(tracker.comments[0], "ac-004: Audit log entry") {
+		t.Errorf("comments = %q", tracker.comments)
+	}
+}
+
+func TestSyncCriteria_CommentSkipsSynced(t *testing.T) {
+	tracker := &fakeTracker{}
+
+	result, err := SyncCriteria(context.Background(), tracker, syncSpec(domain.TrackerSyncComment, "ac-001", "ac-004"))
+	if err != nil {
+		t.Fatalf("SyncCriteria() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Synced(), []string{"ac-002"}) {
+		t.Errorf("Synced() = %v, want [ac-002]", result.Synced())
+	}
+	if tracker.updated != "" {
+		t.Error("comment mode must not edit the issue body")
+	}
+	if len(tracker.comments) != 1 || !strings.Contains(tracker.comments[0], "- [x] ac-002: Link is single use (TestSingleUse)") {
+		t.Errorf("comments = %q", tracker.comments)
+	}
+
+	// Nothing new: no tracker calls
+	tracker.comments = nil
+	if _, err := SyncCriteria(context.Background(), tracker, syncSpec(domain.TrackerSyncComment, "ac-001", "ac-002", "ac-004")); err != nil {
+		t.Fatalf("SyncCriteria() error = %v", err)
+	}
+	if len(tracker.comments) != 0 {
+		t.Errorf("comments = %q, want none", tracker.comments)
+	}
+}
+
+func TestSyncCriteria_Disabled(t *testing.T) {
+	for _, spec := range []*domain.ProductSpec{{}, syncSpec("")} {
+		if _, err := SyncCriteria(context.Background(), &fakeTracker{}, spec); !errors.Is(err, ErrSyncDisabled) {
+			t.Errorf("SyncCriteria() error = %v, want ErrSyncDisabled", err)
+		}
+	}
+	if _, err := SyncCriteria(context.Background(), &fakeTracker{}, syncSpec("labels")); err == nil {
+		t.Error("SyncCriteria() with unknown mode should fail")
+	}
+}
+
+func TestGitHubClient_Comment(t *testing.T) {
+	var got struct {
+		Body string `json:"body"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/acme/api/issues/42/comments" {
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := &GitHubClient{BaseURL: srv.URL, Token: "tok"}
+	if err := c.Comment(context.Background(), "acme/api#42", "done"); err != nil {
+		t.Fatalf("Comment() error = %v", err)
+	}
+	if got.Body != "done" {
+		t.Errorf("comment body = %q", got.Body)
+	}
+}
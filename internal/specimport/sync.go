@@ -0,0 +1,149 @@
+package specimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/domain"
+)
+
+// ErrSyncDisabled is returned when a spec has no tracker link or its sync
+// mode is unset
+var ErrSyncDisabled = errors.New("tracker sync is not enabled for this spec")
+
+// Tracker is an issue tracker that accepts progress updates
+type Tracker interface {
+	Fetch(ctx context.Context, key string) (*Issue, error)
+	UpdateBody(ctx context.Context, key, body string) error
+	Comment(ctx context.Context, key, text string) error
+}
+
+var (
+	_ Tracker = (*GitHubClient)(nil)
+	_ Tracker = (*JiraClient)(nil)
+)
+
+// SyncResult reports what a sync sent to the tracker
+type SyncResult struct {
+	System    string   `json:"system"`
+	Key       string   `json:"key"`
+	Checked   []string `json:"checked"`   // criteria ticked in the issue's checklist
+	Commented []string `json:"commented"` // criteria reported in a comment
+}
+
+// Synced returns the IDs of every criterion the sync reported
+func (r *SyncResult) Synced() []string {
+	return append(slices.Clone(r.Checked), r.Commented...)
+}
+
+// SyncCriteria reports satisfied criteria that are not yet in
+// spec.Tracker.Synced. In checklist mode, unchecked checklist items in the
+// issue whose text matches a criterion are ticked; criteria without a
+// matching item, and all criteria in comment mode, are listed in one
+// comment. The spec is not modified; the caller records result.Synced().
+func SyncCriteria(ctx context.Context, t Tracker, spec *domain.ProductSpec) (*SyncResult, error) {
+	link := spec.Tracker
+	if link == nil || link.Key == "" {
+		return nil, ErrSyncDisabled
+	}
+	switch link.Sync {
+	case domain.TrackerSyncChecklist, domain.TrackerSyncComment:
+	case "":
+		return nil, ErrSyncDisabled
+	default:
+		return nil, fmt.Errorf("unknown tracker sync mode %q (want %s or %s)",
+			link.Sync, domain.TrackerSyncChecklist, domain.TrackerSyncComment)
+	}
+
+	result := &SyncResult{System: link.System, Key: link.Key, Checked: []string{}, Commented: []string{}}
+
+	var pending []domain.AcceptanceCriterion
+	for _, ac := range spec.AcceptanceCriteria {
+		if ac.Satisfied && !slices.Contains(link.Synced, ac.ID) {
+			pending = append(pending, ac)
+		}
+	}
+	if len(pending) == 0 {
+		return result, nil
+	}
+
+	if link.Sync == domain.TrackerSyncChecklist {
+		issue, err := t.Fetch(ctx, link.Key)
+		if err != nil {
+			return nil, err
+		}
+		body, checked := tickChecklist(issue.Body, pending)
+		if body != issue.Body {
+			if err := t.UpdateBody(ctx, link.Key, body); err != nil {
+				return nil, err
+			}
+		}
+		result.Checked = checked
+
+		remaining := pending[:0:0]
+		for _, ac := range pending {
+			if !slices.Contains(checked, ac.ID) {
+				remaining = append(remaining, ac)
+			}
+		}
+		pending = remaining
+	}
+
+	if len(pending) > 0 {
+		if err := t.Comment(ctx, link.Key, progressComment(spec.Name, link.System, pending)); err != nil {
+			return result, err
+		}
+		for _, ac := range pending {
+			result.Commented = append(result.Commented, ac.ID)
+		}
+	}
+	return result, nil
+}
+
+// tickChecklist checks the checklist items matching the criteria and
+// returns the new body with the IDs of the criteria found. Items already
+// checked count as found.
+func tickChecklist(body string, criteria []domain.AcceptanceCriterion) (string, []string) {
+	lines := strings.Split(body, "\n")
+	found := []string{}
+	for _, ac := range criteria {
+		want := normalizeItem(ac.Description)
+		for i, line := range lines {
+			m := checklistPattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+			if m == nil || normalizeItem(m[2]) != want {
+				continue
+			}
+			if m[1] == " " {
+				lines[i] = strings.Replace(line, "[ ]", "[x]", 1)
+			}
+			found = append(found, ac.ID)
+			break
+		}
+	}
+	return strings.Join(lines, "\n"), found
+}
+
+func normalizeItem(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// progressComment lists newly satisfied criteria in the tracker's markup
+func progressComment(specName, system string, criteria []domain.AcceptanceCriterion) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Temper verified acceptance criteria of %q:\n\n", specName)
+	for _, ac := range criteria {
+		line := ac.ID + ": " + ac.Description
+		if ac.Evidence != "" {
+			line += " (" + ac.Evidence + ")"
+		}
+		if system == string(SourceJira) {
+			sb.WriteString("* (/) " + line + "\n")
+		} else {
+			sb.WriteString("- [x] " + line + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,63 @@
+// Package blob stores opaque objects by key, in a local directory or an
+// S3-compatible bucket. Retention archives pruned sessions here, so a
+// shared daemon can keep its history in central, durable storage.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+)
+
+// ErrNotFound is returned when no object has the key
+var ErrNotFound = errors.New("object not found")
+
+// ErrInvalidKey is returned for keys that are empty, absolute or step
+// out of the store with ".."
+var ErrInvalidKey = errors.New("invalid object key")
+
+// Store keeps objects by key. Keys are slash-separated paths such as
+// "sessions/<id>.json".
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	// List returns the keys starting with prefix, in lexical order
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Open returns the store the config selects, or nil when archiving is
+// off
+func Open(cfg config.ArchiveConfig) (Store, error) {
+	switch cfg.Driver {
+	case "":
+		return nil, nil
+	case "dir":
+		if cfg.Path == "" {
+			return nil, errors.New("dir archive needs a path")
+		}
+		return NewDir(cfg.Path, cfg.Prefix)
+	case "s3":
+		return NewS3(S3Config{
+			Endpoint:        cfg.Endpoint,
+			Region:          cfg.Region,
+			Bucket:          cfg.Bucket,
+			Prefix:          cfg.Prefix,
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+		})
+	}
+	return nil, fmt.Errorf("unknown archive driver %q (want dir or s3)", cfg.Driver)
+}
+
+// checkKey rejects keys that could escape the store
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
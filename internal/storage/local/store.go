@@ -172,6 +172,34 @@ func (s *Store) LoadDir(collection, id, subdir, filename string, data interface{
 	return nil
 }
 
+// DeleteDir removes a file from a subdirectory within a collection
+func (s *Store) DeleteDir(collection, id, subdir, filename string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.basePath, collection, id, subdir, filename+".json")
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("remove file: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveDirs removes every subdirectory belonging to a record
+func (s *Store) RemoveDirs(collection, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.RemoveAll(filepath.Join(s.basePath, collection, id)); err != nil {
+		return fmt.Errorf("remove directory: %w", err)
+	}
+
+	return nil
+}
+
 // ListDir lists all files in a subdirectory
 func (s *Store) ListDir(collection, id, subdir string) ([]string, error) {
 	s.mu.RLock()
@@ -0,0 +1,123 @@
+package migrate
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BackupDir holds backup archives, relative to the data directory
+const BackupDir = "backups"
+
+// skipBackup are entries of the data directory a backup leaves out:
+// earlier backups, logs and the running daemon's files
+var skipBackup = map[string]bool{
+	BackupDir:     true,
+	"logs":        true,
+	"temperd.pid": true,
+}
+
+// Backup archives the data directory to backups/migrate-<time>.tar.gz
+// and returns the archive's path. extra names files kept outside the
+// directory, such as a database at a custom path; they are stored under
+// external/.
+func Backup(dir string, now time.Time, extra ...string) (string, error) {
+	if err := os.MkdirAll(filepath.Join(dir, BackupDir), 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, BackupDir, "migrate-"+now.UTC().Format("20060102-150405")+".tar.gz")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeBackup(f, dir, extra); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+func writeBackup(w io.Writer, dir string, extra []string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if skipBackup[filepath.ToSlash(rel)] {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil // directories are implied by their files; skip sockets and links
+		}
+		return addFile(tw, path, filepath.ToSlash(rel))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, path := range extra {
+		if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			continue // already in the archive
+		}
+		// A SQLite database comes with its write-ahead log
+		for _, p := range []string{path, path + "-wal", path + "-shm"} {
+			if !exists(p) {
+				continue
+			}
+			if err := addFile(tw, p, "external/"+filepath.Base(p)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func addFile(tw *tar.Writer, path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("back up %s: %w", name, err)
+	}
+	return nil
+}
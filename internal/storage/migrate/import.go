@@ -0,0 +1,182 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/felixgeelhaar/temper/internal/profile"
+	"github.com/felixgeelhaar/temper/internal/session"
+	sqlitestore "github.com/felixgeelhaar/temper/internal/storage/sqlite"
+)
+
+// Installs from before the SQLite move kept sessions and the profile as
+// JSON under sessions/ and profiles/. A daemon on the SQLite driver
+// never reads those, so their history would silently disappear;
+// import-json-sessions copies what temper.db doesn't have yet. The JSON
+// files are left in place.
+
+// jsonStores opens the JSON stores that exist, without creating any
+func (e *env) jsonStores() (*session.Store, *profile.Store, error) {
+	var sessions *session.Store
+	var profiles *profile.Store
+	if exists(filepath.Join(e.dir, "sessions")) {
+		s, err := session.NewStore(filepath.Join(e.dir, "sessions"))
+		if err != nil {
+			return nil, nil, err
+		}
+		sessions = s
+	}
+	if exists(filepath.Join(e.dir, "profiles")) {
+		p, err := profile.NewStore(filepath.Join(e.dir, "profiles"))
+		if err != nil {
+			return nil, nil, err
+		}
+		profiles = p
+	}
+	return sessions, profiles, nil
+}
+
+// toImport lists the JSON sessions db doesn't have, and whether the
+// profile needs importing. A nil db has nothing yet.
+func (e *env) toImport(db *sqlitestore.DB) (ids []string, importProfile bool, err error) {
+	sessions, profiles, err := e.jsonStores()
+	if err != nil {
+		return nil, false, err
+	}
+	if sessions != nil {
+		all, err := sessions.List()
+		if err != nil {
+			return nil, false, fmt.Errorf("list json sessions: %w", err)
+		}
+		for _, id := range all {
+			if db == nil || !sqlitestore.NewSessionStore(db).Exists(id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	if profiles != nil && profiles.Exists("default") {
+		importProfile = db == nil || !sqlitestore.NewProfileStore(db).Exists("default")
+	}
+	return ids, importProfile, nil
+}
+
+func planImportJSON(e *env) ([]string, error) {
+	if !e.sqlite() {
+		return nil, nil
+	}
+	db, err := e.existingDB()
+	if err != nil {
+		return nil, err
+	}
+	// Before its schema exists the database has no sessions to compare
+	if db != nil {
+		if pending, err := db.Pending(); err != nil {
+			return nil, err
+		} else if len(pending) > 0 && pending[0] == 1 {
+			db = nil
+		}
+	}
+	ids, importProfile, err := e.toImport(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	if len(ids) > 0 {
+		changes = append(changes, fmt.Sprintf("import %d sessions with their runs and hints from sessions/ into %s", len(ids), filepath.Base(e.dbPath())))
+	}
+	if importProfile {
+		changes = append(changes, "import the learning profile from profiles/default.json")
+	}
+	return changes, nil
+}
+
+func applyImportJSON(e *env) error {
+	// Don't create a database just to find there's nothing to import
+	if changes, err := planImportJSON(e); err != nil || len(changes) == 0 {
+		return err
+	}
+	db, err := e.openDB()
+	if err != nil {
+		return err
+	}
+	ids, importProfile, err := e.toImport(db)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 && !importProfile {
+		return nil
+	}
+
+	from, profiles, err := e.jsonStores()
+	if err != nil {
+		return err
+	}
+	to := sqlitestore.NewSessionStore(db)
+	for _, id := range ids {
+		if err := copySession(from, to, id); err != nil {
+			return fmt.Errorf("import session %s: %w", id, err)
+		}
+	}
+
+	if importProfile {
+		p, err := profiles.Get("default")
+		if err != nil {
+			return fmt.Errorf("read json profile: %w", err)
+		}
+		if err := sqlitestore.NewProfileStore(db).Save(p); err != nil {
+			return fmt.Errorf("import profile: %w", err)
+		}
+	}
+	return nil
+}
+
+// copySession copies a session with its runs and interventions. The
+// session goes first, since runs and interventions reference it.
+// Sealed fields are copied as they are, so encrypted sessions stay
+// encrypted.
+func copySession(from session.SessionStore, to session.SessionStore, id string) error {
+	sess, err := from.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := to.Save(sess); err != nil {
+		return err
+	}
+
+	runIDs, err := from.ListRuns(id)
+	if err != nil {
+		return err
+	}
+	for _, runID := range runIDs {
+		run, err := from.GetRun(id, runID)
+		if err != nil {
+			return err
+		}
+		if err := to.SaveRun(run); err != nil {
+			return fmt.Errorf("run %s: %w", runID, err)
+		}
+	}
+
+	ivIDs, err := from.ListInterventions(id)
+	if err != nil {
+		return err
+	}
+	for _, ivID := range ivIDs {
+		iv, err := from.GetIntervention(id, ivID)
+		if err != nil {
+			return err
+		}
+		if err := to.SaveIntervention(iv); err != nil {
+			return fmt.Errorf("intervention %s: %w", ivID, err)
+		}
+	}
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, os.ErrNotExist)
+}
@@ -0,0 +1,289 @@
+// Package migrate versions the data under ~/.temper and upgrades it in
+// order. The version lives in data-version.json; each migration moves
+// the data up one version, and the SQLite schema is brought up to date
+// before any of them runs. An install older than this build is migrated
+// by `temper admin migrate`; data written by a newer build is refused,
+// so an older binary doesn't misread it.
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	sqlitestore "github.com/felixgeelhaar/temper/internal/storage/sqlite"
+)
+
+// MarkerFile holds the data version, relative to the data directory
+const MarkerFile = "data-version.json"
+
+// ErrNewerData is returned when the data was written by a newer temper
+var ErrNewerData = errors.New("data written by a newer temper")
+
+// Marker records the version the data is at
+type Marker struct {
+	Version    int       `json:"version"`
+	MigratedAt time.Time `json:"migrated_at"`
+}
+
+// Step is one pending migration and what it would change
+type Step struct {
+	Version int      `json:"version"`
+	Name    string   `json:"name"`
+	Changes []string `json:"changes"` // empty when the data needs nothing
+}
+
+// Plan is what migrating the data involves
+type Plan struct {
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+	Steps []Step `json:"steps"`
+
+	// SchemaPending lists the SQLite schema migrations not yet applied
+	SchemaPending []int `json:"schema_pending"`
+}
+
+// Pending reports whether there is anything to migrate
+func (p *Plan) Pending() bool {
+	return p.From < p.To || len(p.SchemaPending) > 0
+}
+
+// DataPending reports whether any pending migration changes data,
+// rather than only recording the new version
+func (p *Plan) DataPending() bool {
+	for _, step := range p.Steps {
+		if len(step.Changes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Changes reports whether migrating changes anything on disk besides
+// the version marker
+func (p *Plan) Changes() bool {
+	return p.DataPending() || len(p.SchemaPending) > 0
+}
+
+// Options controls a migration run
+type Options struct {
+	Storage config.StorageConfig
+	DryRun  bool // report the plan without changing anything
+	Backup  bool // archive the data directory before changing it
+}
+
+// Report is what a migration run did, or would do on a dry run
+type Report struct {
+	Plan
+	DryRun bool   `json:"dry_run"`
+	Backup string `json:"backup,omitempty"` // path of the backup archive
+}
+
+// migration moves the data from version-1 to version. plan describes
+// what apply would change without changing it.
+type migration struct {
+	version int
+	name    string
+	plan    func(e *env) ([]string, error)
+	apply   func(e *env) error
+}
+
+// migrations run in order; append new ones, never reorder or remove
+var migrations = []migration{
+	{1, "import-json-sessions", planImportJSON, applyImportJSON},
+}
+
+// Current is the data version this build writes
+func Current() int {
+	return migrations[len(migrations)-1].version
+}
+
+// env is what migrations work on
+type env struct {
+	dir     string
+	storage config.StorageConfig
+	db      *sqlitestore.DB // opened on first use; nil with the json driver
+	current bool            // whether db's schema is up to date
+}
+
+func (e *env) sqlite() bool {
+	return e.storage.Driver == "" || e.storage.Driver == "sqlite"
+}
+
+func (e *env) dbPath() string {
+	if e.storage.Path != "" {
+		return e.storage.Path
+	}
+	return filepath.Join(e.dir, "temper.db")
+}
+
+// openDB opens the SQLite database with its schema up to date
+func (e *env) openDB() (*sqlitestore.DB, error) {
+	if e.db == nil {
+		db, err := sqlitestore.Open(e.dbPath())
+		if err != nil {
+			return nil, err
+		}
+		e.db = db
+	}
+	if !e.current {
+		if err := e.db.Migrate(); err != nil {
+			return nil, fmt.Errorf("run schema migrations: %w", err)
+		}
+		e.current = true
+	}
+	return e.db, nil
+}
+
+// existingDB opens the database only if it exists, without migrating
+// it, for planning
+func (e *env) existingDB() (*sqlitestore.DB, error) {
+	if e.db != nil {
+		return e.db, nil
+	}
+	if _, err := os.Stat(e.dbPath()); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	db, err := sqlitestore.Open(e.dbPath())
+	if err != nil {
+		return nil, err
+	}
+	e.db = db
+	return db, nil
+}
+
+func (e *env) close() {
+	if e.db != nil {
+		_ = e.db.Close()
+		e.db = nil
+		e.current = false
+	}
+}
+
+// ReadMarker returns the version the data in dir is at; data without a
+// marker predates versioning and is at version 0
+func ReadMarker(dir string) (Marker, error) {
+	var m Marker
+	data, err := os.ReadFile(filepath.Join(dir, MarkerFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("parse %s: %w", MarkerFile, err)
+	}
+	return m, nil
+}
+
+func writeMarker(dir string, m Marker) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, MarkerFile+".tmp")
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, MarkerFile))
+}
+
+// Inspect returns what migrating the data in dir involves, changing
+// nothing
+func Inspect(dir string, storage config.StorageConfig) (*Plan, error) {
+	e := &env{dir: dir, storage: storage}
+	defer e.close()
+	return inspect(e)
+}
+
+func inspect(e *env) (*Plan, error) {
+	marker, err := ReadMarker(e.dir)
+	if err != nil {
+		return nil, err
+	}
+	if marker.Version > Current() {
+		return nil, fmt.Errorf("%w: %s is at version %d, this build reads up to %d; upgrade temper",
+			ErrNewerData, e.dir, marker.Version, Current())
+	}
+
+	plan := &Plan{From: marker.Version, To: Current(), Steps: []Step{}, SchemaPending: []int{}}
+	if e.sqlite() {
+		db, err := e.existingDB()
+		if err != nil {
+			return nil, fmt.Errorf("open sqlite: %w", err)
+		}
+		if db != nil {
+			if plan.SchemaPending, err = db.Pending(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, m := range migrations {
+		if m.version <= marker.Version {
+			continue
+		}
+		changes, err := m.plan(e)
+		if err != nil {
+			return nil, fmt.Errorf("plan migration %d (%s): %w", m.version, m.name, err)
+		}
+		plan.Steps = append(plan.Steps, Step{Version: m.version, Name: m.name, Changes: changes})
+	}
+	return plan, nil
+}
+
+// Run migrates the data in dir to the current version. With a backup
+// requested, the data directory is archived first whenever data would
+// change. The marker is advanced after each migration, so a run that
+// fails part way resumes where it stopped.
+func Run(dir string, opts Options, now time.Time) (*Report, error) {
+	e := &env{dir: dir, storage: opts.Storage}
+	defer e.close()
+
+	plan, err := inspect(e)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Plan: *plan, DryRun: opts.DryRun}
+	if opts.DryRun || !plan.Pending() {
+		return report, nil
+	}
+
+	if opts.Backup && plan.Changes() {
+		e.close() // so the database file is quiet while it's copied
+		var extra []string
+		if e.sqlite() {
+			extra = append(extra, e.dbPath())
+		}
+		if report.Backup, err = Backup(dir, now, extra...); err != nil {
+			return report, fmt.Errorf("backup: %w", err)
+		}
+	}
+
+	if e.sqlite() && len(plan.SchemaPending) > 0 {
+		if _, err := e.openDB(); err != nil {
+			return report, err
+		}
+	}
+
+	for _, m := range migrations {
+		if m.version <= plan.From {
+			continue
+		}
+		if err := m.apply(e); err != nil {
+			return report, fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+		}
+		if err := writeMarker(dir, Marker{Version: m.version, MigratedAt: now}); err != nil {
+			return report, fmt.Errorf("record version %d: %w", m.version, err)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,160 @@
+package migrate
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/felixgeelhaar/temper/internal/config"
+	"github.com/felixgeelhaar/temper/internal/domain"
+	"github.com/felixgeelhaar/temper/internal/profile"
+	"github.com/felixgeelhaar/temper/internal/session"
+	sqlitestore "github.com/felixgeelhaar/temper/internal/storage/sqlite"
+)
+
+var now = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+// legacyInstall writes JSON sessions and a profile the way installs from
+// before the SQLite move did
+func legacyInstall(t *testing.T) (string, *session.Session) {
+	t.Helper()
+	dir := t.TempDir()
+
+	sessions, err := session.NewStore(filepath.Join(dir, "sessions"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := session.NewSession("go-v1/basics/hello", map[string]string{"main.go": "package main"}, domain.DefaultPolicy())
+	if err := sessions.Save(sess); err != nil {
+		t.Fatal(err)
+	}
+	if err := sessions.SaveRun(&session.Run{ID: "run-1", SessionID: sess.ID, Code: sess.Code, CreatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := profile.NewStore(filepath.Join(dir, "profiles"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := profiles.GetDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.TotalRuns = 7
+	if err := profiles.Save(p); err != nil {
+		t.Fatal(err)
+	}
+	return dir, sess
+}
+
+func TestRun_FreshInstall(t *testing.T) {
+	dir := t.TempDir()
+
+	report, err := Run(dir, Options{Backup: true}, now)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Changes() || report.Backup != "" {
+		t.Errorf("report = %+v, want no changes and no backup", report)
+	}
+	if m, _ := ReadMarker(dir); m.Version != Current() {
+		t.Errorf("marker version = %d, want %d", m.Version, Current())
+	}
+	if exists(filepath.Join(dir, "temper.db")) {
+		t.Error("migrating a fresh install should not create the database")
+	}
+}
+
+func TestRun_ImportsJSONSessions(t *testing.T) {
+	dir, sess := legacyInstall(t)
+
+	report, err := Run(dir, Options{DryRun: true}, now)
+	if err != nil {
+		t.Fatalf("Run(dry run) error = %v", err)
+	}
+	if report.From != 0 || len(report.Steps) != 1 || len(report.Steps[0].Changes) != 2 {
+		t.Fatalf("dry run report = %+v, want the session and profile import", report)
+	}
+	if exists(filepath.Join(dir, "temper.db")) || exists(filepath.Join(dir, MarkerFile)) {
+		t.Fatal("a dry run must not change anything")
+	}
+
+	report, err = Run(dir, Options{Backup: true}, now)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Backup == "" || !backupHas(t, report.Backup, "sessions/sessions/"+sess.ID+".json") {
+		t.Errorf("backup %q should hold the JSON sessions", report.Backup)
+	}
+
+	db, err := sqlitestore.Open(filepath.Join(dir, "temper.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	store := sqlitestore.NewSessionStore(db)
+	if got, err := store.Get(sess.ID); err != nil || got.Code["main.go"] != "package main" {
+		t.Errorf("imported session = %+v, %v", got, err)
+	}
+	if runs, _ := store.ListRuns(sess.ID); len(runs) != 1 {
+		t.Errorf("imported runs = %v, want 1", runs)
+	}
+	if p, err := sqlitestore.NewProfileStore(db).Get("default"); err != nil || p.TotalRuns != 7 {
+		t.Errorf("imported profile = %+v, %v", p, err)
+	}
+
+	// Up to date: nothing more to do
+	plan, err := Inspect(dir, config.StorageConfig{})
+	if err != nil || plan.Pending() {
+		t.Errorf("Inspect() after Run = %+v, %v; want nothing pending", plan, err)
+	}
+}
+
+func TestRun_JSONDriverLeavesSessions(t *testing.T) {
+	dir, _ := legacyInstall(t)
+
+	report, err := Run(dir, Options{Storage: config.StorageConfig{Driver: "json"}, Backup: true}, now)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Changes() || exists(filepath.Join(dir, "temper.db")) {
+		t.Errorf("the json driver reads sessions/ itself; report = %+v", report)
+	}
+}
+
+func TestInspect_NewerData(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeMarker(dir, Marker{Version: Current() + 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Inspect(dir, config.StorageConfig{}); !errors.Is(err, ErrNewerData) {
+		t.Errorf("Inspect() error = %v, want ErrNewerData", err)
+	}
+}
+
+func backupHas(t *testing.T, path, name string) bool {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			return false
+		}
+		if hdr.Name == name {
+			return true
+		}
+	}
+}
@@ -0,0 +1,3 @@
+-- 005_intervention_redactions.sql: What was scrubbed from each prompt
+
+ALTER TABLE interventions ADD COLUMN redactions TEXT NOT NULL DEFAULT '[]';  -- JSON []domain.Redaction
@@ -0,0 +1,3 @@
+-- 006_authoring_specs.sql: Spec files edited together in one authoring session
+
+ALTER TABLE sessions ADD COLUMN authoring_specs TEXT NOT NULL DEFAULT '[]';  -- JSON []string
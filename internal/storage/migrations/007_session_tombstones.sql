@@ -0,0 +1,4 @@
+-- 007_session_tombstones.sql: Deleted sessions stay as tombstones so runs,
+-- interventions and analytics that reference them keep resolving
+
+ALTER TABLE sessions ADD COLUMN deleted_at DATETIME;
@@ -0,0 +1,3 @@
+-- 008_track_stuck.sql: Per-track stuck detection settings
+
+ALTER TABLE tracks ADD COLUMN stuck TEXT NOT NULL DEFAULT '{}';  -- JSON domain.StuckPolicy
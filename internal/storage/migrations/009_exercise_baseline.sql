@@ -0,0 +1,4 @@
+-- 009_exercise_baseline.sql: The exercise version a training session started
+-- from, so a pack update can be detected and migrated
+
+ALTER TABLE sessions ADD COLUMN exercise_baseline TEXT NOT NULL DEFAULT 'null';  -- JSON session.ExerciseBaseline
@@ -0,0 +1,4 @@
+-- 010_hint_budget.sql: Per-session hint budgets
+
+ALTER TABLE tracks ADD COLUMN budget TEXT NOT NULL DEFAULT '{}';  -- JSON domain.HintBudget
+ALTER TABLE sessions ADD COLUMN budget_spent INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- 011_workspace_root.sql: Project a session was started in
+
+ALTER TABLE sessions ADD COLUMN workspace_root TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- 012_session_scope.sql: Monorepo directory a session is scoped to
+
+ALTER TABLE sessions ADD COLUMN scope TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- 013_session_build_env.sql: Variables, build tags and test flags a session
+-- adds to its runs
+
+ALTER TABLE sessions ADD COLUMN build_env TEXT NOT NULL DEFAULT 'null';  -- JSON domain.BuildEnv
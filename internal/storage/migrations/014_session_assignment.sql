@@ -0,0 +1,4 @@
+-- 014_session_assignment.sql: Cohort member a session was assigned to by
+-- an instructor
+
+ALTER TABLE sessions ADD COLUMN assignment TEXT NOT NULL DEFAULT 'null';  -- JSON session.Assignment
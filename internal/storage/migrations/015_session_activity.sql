@@ -0,0 +1,6 @@
+-- 015_session_activity.sql: Heartbeats, idleness and expiry of sessions
+
+ALTER TABLE sessions ADD COLUMN last_seen_at DATETIME;
+ALTER TABLE sessions ADD COLUMN idle_since DATETIME;
+ALTER TABLE sessions ADD COLUMN cooldown_paused INTEGER NOT NULL DEFAULT 0;  -- nanoseconds
+ALTER TABLE sessions ADD COLUMN expired_at DATETIME;
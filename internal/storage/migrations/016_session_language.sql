@@ -0,0 +1,5 @@
+-- 016_session_language.sql: Language a session's code is built and tested
+-- as, and each file's
+
+ALTER TABLE sessions ADD COLUMN language TEXT NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN languages TEXT NOT NULL DEFAULT 'null';  -- JSON map of file to language
@@ -0,0 +1,4 @@
+-- 017_session_approach.sql: How a finished solution compares with the
+-- exercise's reference solution and alternatives
+
+ALTER TABLE sessions ADD COLUMN approach TEXT NOT NULL DEFAULT 'null';  -- JSON analysis.ApproachReport
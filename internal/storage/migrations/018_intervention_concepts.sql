@@ -0,0 +1,3 @@
+-- 018_intervention_concepts.sql: Concepts each intervention touched on
+
+ALTER TABLE interventions ADD COLUMN concepts TEXT NOT NULL DEFAULT '[]';  -- JSON []string
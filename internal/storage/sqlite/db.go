@@ -2,6 +2,7 @@ package sqlite
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log/slog"
@@ -125,6 +126,38 @@ func (db *DB) Version() (int, error) {
 	return version, err
 }
 
+// Pending returns the versions of the migrations Migrate would apply,
+// without changing the database
+func (db *DB) Pending() ([]int, error) {
+	current := 0
+	var table string
+	err := db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name='schema_migrations'").Scan(&table)
+	switch {
+	case err == nil:
+		if current, err = db.Version(); err != nil {
+			return nil, fmt.Errorf("get current version: %w", err)
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("find schema_migrations: %w", err)
+	}
+
+	entries, err := fs.ReadDir(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+	var pending []int
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+		if version, err := parseVersion(e.Name()); err == nil && version > current {
+			pending = append(pending, version)
+		}
+	}
+	sort.Ints(pending)
+	return pending, nil
+}
+
 // parseVersion extracts the version number from a migration filename like "001_initial.sql".
 func parseVersion(name string) (int, error) {
 	parts := strings.SplitN(name, "_", 2)
@@ -40,17 +40,24 @@ func TestMigrate(t *testing.T) {
 	}
 	defer db.Close()
 
+	if pending, err := db.Pending(); err != nil || len(pending) != 18 || pending[0] != 1 {
+		t.Fatalf("Pending() on a new database = %v, %v; want all 18", pending, err)
+	}
+
 	if err := db.Migrate(); err != nil {
 		t.Fatalf("Migrate() error = %v", err)
 	}
+	if pending, err := db.Pending(); err != nil || len(pending) != 0 {
+		t.Errorf("Pending() after Migrate = %v, %v; want none", pending, err)
+	}
 
 	// Verify version
 	version, err := db.Version()
 	if err != nil {
 		t.Fatalf("Version() error = %v", err)
 	}
-	if version != 4 {
-		t.Errorf("Version() = %d; want 4", version)
+	if version != 18 {
+		t.Errorf("Version() = %d; want 18", version)
 	}
 
 	// Verify tables exist
@@ -81,8 +88,8 @@ func TestMigrate_Idempotent(t *testing.T) {
 	}
 
 	version, _ := db.Version()
-	if version != 4 {
-		t.Errorf("Version() = %d; want 4", version)
+	if version != 18 {
+		t.Errorf("Version() = %d; want 18", version)
 	}
 }
 
@@ -35,27 +35,62 @@ func (s *SessionStore) Save(sess *session.Session) error {
 	if err != nil {
 		return fmt.Errorf("marshal authoring_docs: %w", err)
 	}
+	authoringSpecs, err := json.Marshal(sess.AuthoringSpecs)
+	if err != nil {
+		return fmt.Errorf("marshal authoring_specs: %w", err)
+	}
+	baseline, err := json.Marshal(sess.ExerciseBaseline)
+	if err != nil {
+		return fmt.Errorf("marshal exercise_baseline: %w", err)
+	}
+	buildEnv, err := json.Marshal(sess.BuildEnv)
+	if err != nil {
+		return fmt.Errorf("marshal build_env: %w", err)
+	}
+	assignment, err := json.Marshal(sess.Assignment)
+	if err != nil {
+		return fmt.Errorf("marshal assignment: %w", err)
+	}
+	languages, err := json.Marshal(sess.Languages)
+	if err != nil {
+		return fmt.Errorf("marshal languages: %w", err)
+	}
+	approach, err := json.Marshal(sess.Approach)
+	if err != nil {
+		return fmt.Errorf("marshal approach: %w", err)
+	}
 
 	_, err = s.db.Exec(`
-		INSERT INTO sessions (id, exercise_id, intent, spec_path, status, code, policy,
-			authoring_docs, authoring_section,
-			run_count, hint_count, last_run_at, last_intervention_at,
-			created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO sessions (id, exercise_id, intent, spec_path, workspace_root, scope, build_env, assignment, status, code, policy,
+			language, languages, approach,
+			authoring_docs, authoring_section, authoring_specs, exercise_baseline,
+			run_count, hint_count, budget_spent, last_run_at, last_intervention_at,
+			last_seen_at, idle_since, cooldown_paused, expired_at,
+			created_at, updated_at, deleted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			exercise_id=excluded.exercise_id, intent=excluded.intent,
-			spec_path=excluded.spec_path, status=excluded.status,
+			spec_path=excluded.spec_path, workspace_root=excluded.workspace_root, scope=excluded.scope,
+			build_env=excluded.build_env, assignment=excluded.assignment,
+			status=excluded.status,
 			code=excluded.code, policy=excluded.policy,
+			language=excluded.language, languages=excluded.languages, approach=excluded.approach,
 			authoring_docs=excluded.authoring_docs, authoring_section=excluded.authoring_section,
+			authoring_specs=excluded.authoring_specs, exercise_baseline=excluded.exercise_baseline,
 			run_count=excluded.run_count, hint_count=excluded.hint_count,
+			budget_spent=excluded.budget_spent,
 			last_run_at=excluded.last_run_at, last_intervention_at=excluded.last_intervention_at,
-			updated_at=excluded.updated_at`,
-		sess.ID, sess.ExerciseID, string(sess.Intent), sess.SpecPath,
+			last_seen_at=excluded.last_seen_at, idle_since=excluded.idle_since,
+			cooldown_paused=excluded.cooldown_paused, expired_at=excluded.expired_at,
+			updated_at=excluded.updated_at, deleted_at=excluded.deleted_at`,
+		sess.ID, sess.ExerciseID, string(sess.Intent), sess.SpecPath, sess.WorkspaceRoot, sess.Scope, string(buildEnv), string(assignment),
 		string(sess.Status), string(code), string(policy),
-		string(authoringDocs), sess.AuthoringSection,
-		sess.RunCount, sess.HintCount,
+		sess.Language, string(languages), string(approach),
+		string(authoringDocs), sess.AuthoringSection, string(authoringSpecs), string(baseline),
+		sess.RunCount, sess.HintCount, sess.BudgetSpent,
 		nullTime(sess.LastRunAt), nullTime(sess.LastInterventionAt),
-		sess.CreatedAt, sess.UpdatedAt,
+		nullTime(sess.LastSeenAt), nullTime(sess.IdleSince), int64(sess.CooldownPaused), nullTime(sess.ExpiredAt),
+		sess.CreatedAt, sess.UpdatedAt, nullTime(sess.DeletedAt),
 	)
 	if err != nil {
 		return fmt.Errorf("upsert session: %w", err)
@@ -66,10 +101,12 @@ func (s *SessionStore) Save(sess *session.Session) error {
 // Get retrieves a session by ID.
 func (s *SessionStore) Get(id string) (*session.Session, error) {
 	row := s.db.QueryRow(`
-		SELECT id, exercise_id, intent, spec_path, status, code, policy,
-			authoring_docs, authoring_section,
-			run_count, hint_count, last_run_at, last_intervention_at,
-			created_at, updated_at
+		SELECT id, exercise_id, intent, spec_path, workspace_root, scope, build_env, assignment, status, code, policy,
+			language, languages, approach,
+			authoring_docs, authoring_section, authoring_specs, exercise_baseline,
+			run_count, hint_count, budget_spent, last_run_at, last_intervention_at,
+			last_seen_at, idle_since, cooldown_paused, expired_at,
+			created_at, updated_at, deleted_at
 		FROM sessions WHERE id = ?`, id)
 	return scanSession(row)
 }
@@ -87,6 +124,21 @@ func (s *SessionStore) Delete(id string) error {
 	return nil
 }
 
+// Tombstone marks a session deleted, keeping its runs and interventions.
+func (s *SessionStore) Tombstone(id string, at time.Time) error {
+	result, err := s.db.Exec(`
+		UPDATE sessions SET status = ?, deleted_at = ?, updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL`,
+		string(session.StatusDeleted), at, at, id)
+	if err != nil {
+		return fmt.Errorf("tombstone session: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 && !s.Exists(id) {
+		return session.ErrNotFound
+	}
+	return nil
+}
+
 // List returns all session IDs.
 func (s *SessionStore) List() ([]string, error) {
 	rows, err := s.db.Query("SELECT id FROM sessions ORDER BY created_at DESC")
@@ -109,10 +161,12 @@ func (s *SessionStore) List() ([]string, error) {
 // ListActive returns all active sessions.
 func (s *SessionStore) ListActive() ([]*session.Session, error) {
 	rows, err := s.db.Query(`
-		SELECT id, exercise_id, intent, spec_path, status, code, policy,
-			authoring_docs, authoring_section,
-			run_count, hint_count, last_run_at, last_intervention_at,
-			created_at, updated_at
+		SELECT id, exercise_id, intent, spec_path, workspace_root, scope, build_env, assignment, status, code, policy,
+			language, languages, approach,
+			authoring_docs, authoring_section, authoring_specs, exercise_baseline,
+			run_count, hint_count, budget_spent, last_run_at, last_intervention_at,
+			last_seen_at, idle_since, cooldown_paused, expired_at,
+			created_at, updated_at, deleted_at
 		FROM sessions WHERE status = 'active' ORDER BY created_at DESC`)
 	if err != nil {
 		return nil, fmt.Errorf("list active sessions: %w", err)
@@ -214,6 +268,19 @@ func (s *SessionStore) ListRuns(sessionID string) ([]string, error) {
 	return ids, rows.Err()
 }
 
+// DeleteRun removes a single run.
+func (s *SessionStore) DeleteRun(sessionID, runID string) error {
+	result, err := s.db.Exec("DELETE FROM runs WHERE id = ? AND session_id = ?", runID, sessionID)
+	if err != nil {
+		return fmt.Errorf("delete run: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return session.ErrNotFound
+	}
+	return nil
+}
+
 // SaveIntervention persists an intervention within a session.
 func (s *SessionStore) SaveIntervention(intervention *session.Intervention) error {
 	var runID *string
@@ -221,14 +288,24 @@ func (s *SessionStore) SaveIntervention(intervention *session.Intervention) erro
 		runID = intervention.RunID
 	}
 
-	_, err := s.db.Exec(`
-		INSERT INTO interventions (id, session_id, run_id, intent, level, type, content, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	redactions, err := json.Marshal(intervention.Redactions)
+	if err != nil {
+		return fmt.Errorf("marshal redactions: %w", err)
+	}
+	concepts, err := json.Marshal(intervention.Concepts)
+	if err != nil {
+		return fmt.Errorf("marshal concepts: %w", err)
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO interventions (id, session_id, run_id, intent, level, type, content, redactions, concepts, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
-			content=excluded.content`,
+			content=excluded.content, redactions=excluded.redactions, concepts=excluded.concepts`,
 		intervention.ID, intervention.SessionID, runID,
 		string(intervention.Intent), int(intervention.Level),
-		string(intervention.Type), intervention.Content, intervention.CreatedAt,
+		string(intervention.Type), intervention.Content, string(redactions), string(concepts),
+		intervention.CreatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("upsert intervention: %w", err)
@@ -239,17 +316,18 @@ func (s *SessionStore) SaveIntervention(intervention *session.Intervention) erro
 // GetIntervention retrieves an intervention by ID.
 func (s *SessionStore) GetIntervention(sessionID, interventionID string) (*session.Intervention, error) {
 	row := s.db.QueryRow(`
-		SELECT id, session_id, run_id, intent, level, type, content, created_at
+		SELECT id, session_id, run_id, intent, level, type, content, redactions, concepts, created_at
 		FROM interventions WHERE id = ? AND session_id = ?`, interventionID, sessionID)
 
 	var intervention session.Intervention
 	var runID sql.NullString
 	var level int
+	var redactions, concepts string
 
 	if err := row.Scan(
 		&intervention.ID, &intervention.SessionID, &runID,
 		&intervention.Intent, &level,
-		&intervention.Type, &intervention.Content, &intervention.CreatedAt,
+		&intervention.Type, &intervention.Content, &redactions, &concepts, &intervention.CreatedAt,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, session.ErrNotFound
@@ -261,6 +339,12 @@ func (s *SessionStore) GetIntervention(sessionID, interventionID string) (*sessi
 	if runID.Valid {
 		intervention.RunID = &runID.String
 	}
+	if err := json.Unmarshal([]byte(redactions), &intervention.Redactions); err != nil {
+		return nil, fmt.Errorf("unmarshal redactions: %w", err)
+	}
+	if err := json.Unmarshal([]byte(concepts), &intervention.Concepts); err != nil {
+		return nil, fmt.Errorf("unmarshal concepts: %w", err)
+	}
 	return &intervention, nil
 }
 
@@ -286,16 +370,19 @@ func (s *SessionStore) ListInterventions(sessionID string) ([]string, error) {
 // scanSession scans a single session from a *sql.Row.
 func scanSession(row *sql.Row) (*session.Session, error) {
 	var sess session.Session
-	var codeJSON, policyJSON, authoringDocsJSON string
+	var codeJSON, policyJSON, authoringDocsJSON, authoringSpecsJSON, baselineJSON, buildEnvJSON, assignmentJSON, languagesJSON, approachJSON string
 	var intentStr, statusStr string
-	var lastRunAt, lastInterventionAt sql.NullTime
+	var lastRunAt, lastInterventionAt, lastSeenAt, idleSince, expiredAt, deletedAt sql.NullTime
+	var cooldownPaused int64
 
 	err := row.Scan(
-		&sess.ID, &sess.ExerciseID, &intentStr, &sess.SpecPath,
+		&sess.ID, &sess.ExerciseID, &intentStr, &sess.SpecPath, &sess.WorkspaceRoot, &sess.Scope, &buildEnvJSON, &assignmentJSON,
 		&statusStr, &codeJSON, &policyJSON,
-		&authoringDocsJSON, &sess.AuthoringSection,
-		&sess.RunCount, &sess.HintCount, &lastRunAt, &lastInterventionAt,
-		&sess.CreatedAt, &sess.UpdatedAt,
+		&sess.Language, &languagesJSON, &approachJSON,
+		&authoringDocsJSON, &sess.AuthoringSection, &authoringSpecsJSON, &baselineJSON,
+		&sess.RunCount, &sess.HintCount, &sess.BudgetSpent, &lastRunAt, &lastInterventionAt,
+		&lastSeenAt, &idleSince, &cooldownPaused, &expiredAt,
+		&sess.CreatedAt, &sess.UpdatedAt, &deletedAt,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -316,6 +403,24 @@ func scanSession(row *sql.Row) (*session.Session, error) {
 	if err := json.Unmarshal([]byte(authoringDocsJSON), &sess.AuthoringDocs); err != nil {
 		return nil, fmt.Errorf("unmarshal authoring_docs: %w", err)
 	}
+	if err := json.Unmarshal([]byte(authoringSpecsJSON), &sess.AuthoringSpecs); err != nil {
+		return nil, fmt.Errorf("unmarshal authoring_specs: %w", err)
+	}
+	if err := json.Unmarshal([]byte(baselineJSON), &sess.ExerciseBaseline); err != nil {
+		return nil, fmt.Errorf("unmarshal exercise_baseline: %w", err)
+	}
+	if err := json.Unmarshal([]byte(buildEnvJSON), &sess.BuildEnv); err != nil {
+		return nil, fmt.Errorf("unmarshal build_env: %w", err)
+	}
+	if err := json.Unmarshal([]byte(assignmentJSON), &sess.Assignment); err != nil {
+		return nil, fmt.Errorf("unmarshal assignment: %w", err)
+	}
+	if err := json.Unmarshal([]byte(languagesJSON), &sess.Languages); err != nil {
+		return nil, fmt.Errorf("unmarshal languages: %w", err)
+	}
+	if err := json.Unmarshal([]byte(approachJSON), &sess.Approach); err != nil {
+		return nil, fmt.Errorf("unmarshal approach: %w", err)
+	}
 
 	if lastRunAt.Valid {
 		sess.LastRunAt = &lastRunAt.Time
@@ -323,6 +428,19 @@ func scanSession(row *sql.Row) (*session.Session, error) {
 	if lastInterventionAt.Valid {
 		sess.LastInterventionAt = &lastInterventionAt.Time
 	}
+	if lastSeenAt.Valid {
+		sess.LastSeenAt = &lastSeenAt.Time
+	}
+	if idleSince.Valid {
+		sess.IdleSince = &idleSince.Time
+	}
+	if expiredAt.Valid {
+		sess.ExpiredAt = &expiredAt.Time
+	}
+	sess.CooldownPaused = time.Duration(cooldownPaused)
+	if deletedAt.Valid {
+		sess.DeletedAt = &deletedAt.Time
+	}
 
 	return &sess, nil
 }
@@ -330,16 +448,19 @@ func scanSession(row *sql.Row) (*session.Session, error) {
 // scanSessionRow scans a session from *sql.Rows (for list queries).
 func scanSessionRow(rows *sql.Rows) (*session.Session, error) {
 	var sess session.Session
-	var codeJSON, policyJSON, authoringDocsJSON string
+	var codeJSON, policyJSON, authoringDocsJSON, authoringSpecsJSON, baselineJSON, buildEnvJSON, assignmentJSON, languagesJSON, approachJSON string
 	var intentStr, statusStr string
-	var lastRunAt, lastInterventionAt sql.NullTime
+	var lastRunAt, lastInterventionAt, lastSeenAt, idleSince, expiredAt, deletedAt sql.NullTime
+	var cooldownPaused int64
 
 	err := rows.Scan(
-		&sess.ID, &sess.ExerciseID, &intentStr, &sess.SpecPath,
+		&sess.ID, &sess.ExerciseID, &intentStr, &sess.SpecPath, &sess.WorkspaceRoot, &sess.Scope, &buildEnvJSON, &assignmentJSON,
 		&statusStr, &codeJSON, &policyJSON,
-		&authoringDocsJSON, &sess.AuthoringSection,
-		&sess.RunCount, &sess.HintCount, &lastRunAt, &lastInterventionAt,
-		&sess.CreatedAt, &sess.UpdatedAt,
+		&sess.Language, &languagesJSON, &approachJSON,
+		&authoringDocsJSON, &sess.AuthoringSection, &authoringSpecsJSON, &baselineJSON,
+		&sess.RunCount, &sess.HintCount, &sess.BudgetSpent, &lastRunAt, &lastInterventionAt,
+		&lastSeenAt, &idleSince, &cooldownPaused, &expiredAt,
+		&sess.CreatedAt, &sess.UpdatedAt, &deletedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("scan session row: %w", err)
@@ -357,6 +478,24 @@ func scanSessionRow(rows *sql.Rows) (*session.Session, error) {
 	if err := json.Unmarshal([]byte(authoringDocsJSON), &sess.AuthoringDocs); err != nil {
 		return nil, fmt.Errorf("unmarshal authoring_docs: %w", err)
 	}
+	if err := json.Unmarshal([]byte(authoringSpecsJSON), &sess.AuthoringSpecs); err != nil {
+		return nil, fmt.Errorf("unmarshal authoring_specs: %w", err)
+	}
+	if err := json.Unmarshal([]byte(baselineJSON), &sess.ExerciseBaseline); err != nil {
+		return nil, fmt.Errorf("unmarshal exercise_baseline: %w", err)
+	}
+	if err := json.Unmarshal([]byte(buildEnvJSON), &sess.BuildEnv); err != nil {
+		return nil, fmt.Errorf("unmarshal build_env: %w", err)
+	}
+	if err := json.Unmarshal([]byte(assignmentJSON), &sess.Assignment); err != nil {
+		return nil, fmt.Errorf("unmarshal assignment: %w", err)
+	}
+	if err := json.Unmarshal([]byte(languagesJSON), &sess.Languages); err != nil {
+		return nil, fmt.Errorf("unmarshal languages: %w", err)
+	}
+	if err := json.Unmarshal([]byte(approachJSON), &sess.Approach); err != nil {
+		return nil, fmt.Errorf("unmarshal approach: %w", err)
+	}
 
 	if lastRunAt.Valid {
 		sess.LastRunAt = &lastRunAt.Time
@@ -364,6 +503,19 @@ func scanSessionRow(rows *sql.Rows) (*session.Session, error) {
 	if lastInterventionAt.Valid {
 		sess.LastInterventionAt = &lastInterventionAt.Time
 	}
+	if lastSeenAt.Valid {
+		sess.LastSeenAt = &lastSeenAt.Time
+	}
+	if idleSince.Valid {
+		sess.IdleSince = &idleSince.Time
+	}
+	if expiredAt.Valid {
+		sess.ExpiredAt = &expiredAt.Time
+	}
+	sess.CooldownPaused = time.Duration(cooldownPaused)
+	if deletedAt.Valid {
+		sess.DeletedAt = &deletedAt.Time
+	}
 
 	return &sess, nil
 }
@@ -1,9 +1,11 @@
 package sqlite
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/felixgeelhaar/temper/internal/analysis"
 	"github.com/felixgeelhaar/temper/internal/domain"
 	"github.com/felixgeelhaar/temper/internal/session"
 )
@@ -12,7 +14,21 @@ func TestSessionStore_Save_Get(t *testing.T) {
 	db := openTestDB(t)
 	store := NewSessionStore(db)
 
-	sess := session.NewSession("go-v1/basics/hello-world", map[string]string{"main.go": "package main"}, domain.DefaultPolicy())
+	policy := domain.DefaultPolicy()
+	policy.Budget = domain.HintBudget{Tokens: 5}
+	sess := session.NewSession("go-v1/basics/hello-world", map[string]string{"main.go": "package main"}, policy)
+	sess.BudgetSpent = 2
+	sess.WorkspaceRoot = "/home/dev/project"
+	sess.Scope = "services/auth"
+	sess.BuildEnv = &domain.BuildEnv{Env: map[string]string{"TZ": "Asia/Tokyo"}, BuildTags: []string{"integration"}}
+	sess.Assignment = &session.Assignment{Cohort: "cs101", Learner: "p-1", Name: "Ada"}
+	sess.Language = "python"
+	sess.Languages = map[string]string{"main.py": "python"}
+	sess.Approach = &analysis.ApproachReport{Idioms: []string{"range loop"}, Reference: &analysis.Comparison{Name: "reference", Similarity: 0.8}}
+	idle := time.Now().Add(-time.Minute)
+	sess.IdleSince = &idle
+	sess.LastSeenAt = &idle
+	sess.CooldownPaused = 30 * time.Second
 
 	if err := store.Save(sess); err != nil {
 		t.Fatalf("Save() error = %v", err)
@@ -41,6 +57,27 @@ func TestSessionStore_Save_Get(t *testing.T) {
 	if loaded.Policy.MaxLevel != domain.L3ConstrainedSnippet {
 		t.Errorf("Policy.MaxLevel = %d; want %d", loaded.Policy.MaxLevel, domain.L3ConstrainedSnippet)
 	}
+	if loaded.Policy.Budget.Tokens != 5 || loaded.BudgetSpent != 2 {
+		t.Errorf("budget = %d tokens, %d spent; want 5, 2", loaded.Policy.Budget.Tokens, loaded.BudgetSpent)
+	}
+	if loaded.WorkspaceRoot != "/home/dev/project" || loaded.Scope != "services/auth" {
+		t.Errorf("WorkspaceRoot, Scope = %q, %q; want /home/dev/project, services/auth", loaded.WorkspaceRoot, loaded.Scope)
+	}
+	if loaded.BuildEnv == nil || loaded.BuildEnv.Env["TZ"] != "Asia/Tokyo" || len(loaded.BuildEnv.BuildTags) != 1 {
+		t.Errorf("BuildEnv = %+v; want TZ and the integration tag", loaded.BuildEnv)
+	}
+	if loaded.Assignment == nil || loaded.Assignment.Cohort != "cs101" || loaded.Assignment.Learner != "p-1" {
+		t.Errorf("Assignment = %+v; want cs101/p-1", loaded.Assignment)
+	}
+	if loaded.Language != "python" || loaded.Languages["main.py"] != "python" {
+		t.Errorf("Language = %q, Languages = %v; want python", loaded.Language, loaded.Languages)
+	}
+	if loaded.Approach == nil || loaded.Approach.Reference == nil || loaded.Approach.Reference.Similarity != 0.8 {
+		t.Errorf("Approach = %+v; want the reference comparison", loaded.Approach)
+	}
+	if loaded.IdleSince == nil || loaded.LastSeenAt == nil || loaded.CooldownPaused != 30*time.Second || loaded.ExpiredAt != nil {
+		t.Errorf("activity = %v, %v, %v, %v; want idle with 30s paused", loaded.LastSeenAt, loaded.IdleSince, loaded.CooldownPaused, loaded.ExpiredAt)
+	}
 }
 
 func TestSessionStore_Get_NotFound(t *testing.T) {
@@ -80,6 +117,41 @@ func TestSessionStore_Delete_NotFound(t *testing.T) {
 	}
 }
 
+func TestSessionStore_Tombstone(t *testing.T) {
+	db := openTestDB(t)
+	store := NewSessionStore(db)
+
+	sess := session.NewSession("test", map[string]string{}, domain.DefaultPolicy())
+	store.Save(sess)
+	store.SaveRun(&session.Run{ID: "run-1", SessionID: sess.ID, Code: map[string]string{}, CreatedAt: time.Now()})
+
+	at := time.Now().Truncate(time.Second)
+	if err := store.Tombstone(sess.ID, at); err != nil {
+		t.Fatalf("Tombstone() error = %v", err)
+	}
+	if err := store.Tombstone(sess.ID, at.Add(time.Hour)); err != nil {
+		t.Fatalf("Tombstone() twice error = %v", err)
+	}
+
+	got, err := store.Get(sess.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status != session.StatusDeleted || got.DeletedAt == nil || !got.DeletedAt.Equal(at) {
+		t.Errorf("tombstone status = %q, deleted_at = %v; want deleted at %v", got.Status, got.DeletedAt, at)
+	}
+	if runs, _ := store.ListRuns(sess.ID); len(runs) != 1 {
+		t.Errorf("ListRuns() = %v; runs should survive a tombstone", runs)
+	}
+	if active, _ := store.ListActive(); len(active) != 0 {
+		t.Errorf("ListActive() = %d sessions; tombstones are not active", len(active))
+	}
+
+	if err := store.Tombstone("nonexistent", at); err != session.ErrNotFound {
+		t.Errorf("Tombstone() unknown error = %v; want ErrNotFound", err)
+	}
+}
+
 func TestSessionStore_List(t *testing.T) {
 	db := openTestDB(t)
 	store := NewSessionStore(db)
@@ -212,6 +284,25 @@ func TestSessionStore_ListRuns(t *testing.T) {
 	}
 }
 
+func TestSessionStore_DeleteRun(t *testing.T) {
+	db := openTestDB(t)
+	store := NewSessionStore(db)
+
+	sess := session.NewSession("test", map[string]string{}, domain.DefaultPolicy())
+	store.Save(sess)
+	store.SaveRun(&session.Run{ID: "run-1", SessionID: sess.ID, Code: map[string]string{}, CreatedAt: time.Now()})
+
+	if err := store.DeleteRun(sess.ID, "run-1"); err != nil {
+		t.Fatalf("DeleteRun() error = %v", err)
+	}
+	if ids, _ := store.ListRuns(sess.ID); len(ids) != 0 {
+		t.Errorf("ListRuns() after delete = %v; want none", ids)
+	}
+	if err := store.DeleteRun(sess.ID, "run-1"); err != session.ErrNotFound {
+		t.Errorf("DeleteRun() twice error = %v; want ErrNotFound", err)
+	}
+}
+
 func TestSessionStore_SaveIntervention_GetIntervention(t *testing.T) {
 	db := openTestDB(t)
 	store := NewSessionStore(db)
@@ -249,6 +340,93 @@ func TestSessionStore_SaveIntervention_GetIntervention(t *testing.T) {
 	}
 }
 
+func TestSessionStore_AuthoringSpecs(t *testing.T) {
+	db := openTestDB(t)
+	store := NewSessionStore(db)
+
+	sess := session.NewAuthoringSession(".specs/epic.yaml", []string{"docs/"}, domain.DefaultPolicy())
+	sess.AddSpecFile(".specs/billing.yaml")
+	if err := store.Save(sess); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := store.Get(sess.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(loaded.AuthoringSpecs) != 2 || loaded.AuthoringSpecs[1] != ".specs/billing.yaml" {
+		t.Errorf("AuthoringSpecs = %v; want both spec files", loaded.AuthoringSpecs)
+	}
+}
+
+func TestSessionStore_ExerciseBaseline(t *testing.T) {
+	db := openTestDB(t)
+	store := NewSessionStore(db)
+
+	plain := session.NewSession("go-v1/basics/hello", map[string]string{}, domain.DefaultPolicy())
+	versioned := session.NewSession("go-v1/basics/hello", map[string]string{}, domain.DefaultPolicy())
+	versioned.ExerciseBaseline = &session.ExerciseBaseline{
+		Version: "abc123",
+		Starter: map[string]string{"main.go": "package main\n"},
+		Tests:   map[string]string{"main_test.go": "package main\n"},
+		Pinned:  true,
+	}
+	for _, sess := range []*session.Session{plain, versioned} {
+		if err := store.Save(sess); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	loaded, err := store.Get(plain.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if loaded.ExerciseBaseline != nil {
+		t.Errorf("ExerciseBaseline = %+v; want nil", loaded.ExerciseBaseline)
+	}
+	loaded, err = store.Get(versioned.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if b := loaded.ExerciseBaseline; b == nil || b.Version != "abc123" || !b.Pinned || b.Tests["main_test.go"] == "" {
+		t.Errorf("ExerciseBaseline = %+v; want the saved baseline", b)
+	}
+}
+
+func TestSessionStore_Intervention_RedactionsAndConcepts(t *testing.T) {
+	db := openTestDB(t)
+	store := NewSessionStore(db)
+
+	sess := session.NewSession("test", map[string]string{}, domain.DefaultPolicy())
+	store.Save(sess)
+
+	intervention := &session.Intervention{
+		ID:         "int-1",
+		SessionID:  sess.ID,
+		Intent:     domain.IntentHint,
+		Level:      domain.L1CategoryHint,
+		Type:       domain.TypeHint,
+		Content:    "Try using a loop",
+		CreatedAt:  time.Now(),
+		Redactions: []domain.Redaction{{Kind: "emails", Count: 2}},
+		Concepts:   []string{"goroutines", "channels"},
+	}
+	if err := store.SaveIntervention(intervention); err != nil {
+		t.Fatalf("SaveIntervention() error = %v", err)
+	}
+
+	loaded, err := store.GetIntervention(sess.ID, intervention.ID)
+	if err != nil {
+		t.Fatalf("GetIntervention() error = %v", err)
+	}
+	if len(loaded.Redactions) != 1 || loaded.Redactions[0] != intervention.Redactions[0] {
+		t.Errorf("Redactions = %+v; want %+v", loaded.Redactions, intervention.Redactions)
+	}
+	if !reflect.DeepEqual(loaded.Concepts, intervention.Concepts) {
+		t.Errorf("Concepts = %v; want %v", loaded.Concepts, intervention.Concepts)
+	}
+}
+
 func TestSessionStore_GetIntervention_NotFound(t *testing.T) {
 	db := openTestDB(t)
 	store := NewSessionStore(db)
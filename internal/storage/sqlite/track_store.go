@@ -26,6 +26,14 @@ func (s *TrackStore) Save(track *domain.Track) error {
 	if err != nil {
 		return fmt.Errorf("marshal auto_progress: %w", err)
 	}
+	stuck, err := json.Marshal(track.Stuck)
+	if err != nil {
+		return fmt.Errorf("marshal stuck: %w", err)
+	}
+	budget, err := json.Marshal(track.Budget)
+	if err != nil {
+		return fmt.Errorf("marshal budget: %w", err)
+	}
 
 	now := time.Now()
 	if track.CreatedAt.IsZero() {
@@ -35,18 +43,19 @@ func (s *TrackStore) Save(track *domain.Track) error {
 
 	_, err = s.db.Exec(`
 		INSERT INTO tracks (id, name, description, preset, max_level, cooldown_seconds,
-			patching_enabled, auto_progress, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			patching_enabled, auto_progress, stuck, budget, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			name=excluded.name, description=excluded.description,
 			preset=excluded.preset, max_level=excluded.max_level,
 			cooldown_seconds=excluded.cooldown_seconds,
 			patching_enabled=excluded.patching_enabled,
 			auto_progress=excluded.auto_progress,
+			stuck=excluded.stuck, budget=excluded.budget,
 			updated_at=excluded.updated_at`,
 		track.ID, track.Name, track.Description, track.Preset,
 		int(track.MaxLevel), track.CooldownSeconds,
-		boolToInt(track.PatchingEnabled), string(autoProgress),
+		boolToInt(track.PatchingEnabled), string(autoProgress), string(stuck), string(budget),
 		track.CreatedAt, track.UpdatedAt,
 	)
 	if err != nil {
@@ -59,7 +68,7 @@ func (s *TrackStore) Save(track *domain.Track) error {
 func (s *TrackStore) Get(id string) (*domain.Track, error) {
 	row := s.db.QueryRow(`
 		SELECT id, name, description, preset, max_level, cooldown_seconds,
-			patching_enabled, auto_progress, created_at, updated_at
+			patching_enabled, auto_progress, stuck, budget, created_at, updated_at
 		FROM tracks WHERE id = ?`, id)
 	return scanTrack(row)
 }
@@ -81,7 +90,7 @@ func (s *TrackStore) Delete(id string) error {
 func (s *TrackStore) List() ([]*domain.Track, error) {
 	rows, err := s.db.Query(`
 		SELECT id, name, description, preset, max_level, cooldown_seconds,
-			patching_enabled, auto_progress, created_at, updated_at
+			patching_enabled, auto_progress, stuck, budget, created_at, updated_at
 		FROM tracks ORDER BY created_at`)
 	if err != nil {
 		return nil, fmt.Errorf("list tracks: %w", err)
@@ -103,7 +112,7 @@ func (s *TrackStore) List() ([]*domain.Track, error) {
 func (s *TrackStore) ListByPreset(preset string) ([]*domain.Track, error) {
 	rows, err := s.db.Query(`
 		SELECT id, name, description, preset, max_level, cooldown_seconds,
-			patching_enabled, auto_progress, created_at, updated_at
+			patching_enabled, auto_progress, stuck, budget, created_at, updated_at
 		FROM tracks WHERE preset = ? ORDER BY created_at`, preset)
 	if err != nil {
 		return nil, fmt.Errorf("list tracks by preset: %w", err)
@@ -136,12 +145,12 @@ func scanTrack(row *sql.Row) (*domain.Track, error) {
 	var track domain.Track
 	var maxLevel int
 	var patchingEnabled int
-	var autoProgressJSON string
+	var autoProgressJSON, stuckJSON, budgetJSON string
 
 	err := row.Scan(
 		&track.ID, &track.Name, &track.Description, &track.Preset,
 		&maxLevel, &track.CooldownSeconds,
-		&patchingEnabled, &autoProgressJSON,
+		&patchingEnabled, &autoProgressJSON, &stuckJSON, &budgetJSON,
 		&track.CreatedAt, &track.UpdatedAt,
 	)
 	if err != nil {
@@ -157,6 +166,12 @@ func scanTrack(row *sql.Row) (*domain.Track, error) {
 	if err := json.Unmarshal([]byte(autoProgressJSON), &track.AutoProgress); err != nil {
 		return nil, fmt.Errorf("unmarshal auto_progress: %w", err)
 	}
+	if err := json.Unmarshal([]byte(stuckJSON), &track.Stuck); err != nil {
+		return nil, fmt.Errorf("unmarshal stuck: %w", err)
+	}
+	if err := json.Unmarshal([]byte(budgetJSON), &track.Budget); err != nil {
+		return nil, fmt.Errorf("unmarshal budget: %w", err)
+	}
 
 	return &track, nil
 }
@@ -166,12 +181,12 @@ func scanTrackRow(rows *sql.Rows) (*domain.Track, error) {
 	var track domain.Track
 	var maxLevel int
 	var patchingEnabled int
-	var autoProgressJSON string
+	var autoProgressJSON, stuckJSON, budgetJSON string
 
 	err := rows.Scan(
 		&track.ID, &track.Name, &track.Description, &track.Preset,
 		&maxLevel, &track.CooldownSeconds,
-		&patchingEnabled, &autoProgressJSON,
+		&patchingEnabled, &autoProgressJSON, &stuckJSON, &budgetJSON,
 		&track.CreatedAt, &track.UpdatedAt,
 	)
 	if err != nil {
@@ -184,6 +199,12 @@ func scanTrackRow(rows *sql.Rows) (*domain.Track, error) {
 	if err := json.Unmarshal([]byte(autoProgressJSON), &track.AutoProgress); err != nil {
 		return nil, fmt.Errorf("unmarshal auto_progress: %w", err)
 	}
+	if err := json.Unmarshal([]byte(stuckJSON), &track.Stuck); err != nil {
+		return nil, fmt.Errorf("unmarshal stuck: %w", err)
+	}
+	if err := json.Unmarshal([]byte(budgetJSON), &track.Budget); err != nil {
+		return nil, fmt.Errorf("unmarshal budget: %w", err)
+	}
 
 	return &track, nil
 }
@@ -59,6 +59,8 @@ func TestTrackStore_Save_Get(t *testing.T) {
 			DemoteAfterFailures: 4,
 			MinSkillForPromote:  0.6,
 		},
+		Stuck:  domain.StuckPolicy{FailingRuns: 3, IdleSeconds: 300},
+		Budget: domain.HintBudget{Tokens: 6, Costs: []int{0, 1, 2}},
 	}
 
 	if err := store.Save(track); err != nil {
@@ -91,6 +93,12 @@ func TestTrackStore_Save_Get(t *testing.T) {
 	if loaded.AutoProgress.MinSkillForPromote != 0.6 {
 		t.Errorf("AutoProgress.MinSkillForPromote = %f; want 0.6", loaded.AutoProgress.MinSkillForPromote)
 	}
+	if loaded.Stuck.FailingRuns != 3 || loaded.Stuck.IdleSeconds != 300 {
+		t.Errorf("Stuck = %+v; want 3 runs, 300s", loaded.Stuck)
+	}
+	if loaded.Budget.Tokens != 6 || len(loaded.Budget.Costs) != 3 {
+		t.Errorf("Budget = %+v; want 6 tokens, 3 costs", loaded.Budget)
+	}
 }
 
 func TestTrackStore_Get_NotFound(t *testing.T) {
@@ -0,0 +1,179 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	keychainService = "temper"
+	keychainAccount = "storage-key"
+)
+
+// errNoKeychain means no usable OS keychain was found
+var errNoKeychain = errors.New("no OS keychain available")
+
+// Swapped out in tests so they never touch the real keychain
+var (
+	keychainGetFn = keychainGet
+	keychainSetFn = keychainSet
+)
+
+// LoadOrCreateKey returns the storage key, generating and saving one on
+// first use. The OS keychain is preferred; fallbackPath (typically
+// ~/.temper/storage.key) is used only when no keychain is available.
+func LoadOrCreateKey(fallbackPath string) ([]byte, error) {
+	key, err := keychainGetFn()
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, errNoKeychain) && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	keychainUsable := !errors.Is(err, errNoKeychain)
+
+	// A key file from before the keychain was reachable still wins, so
+	// data encrypted with it stays readable
+	if key, err := readKeyFile(fallbackPath); err == nil {
+		return key, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	key = make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+
+	if keychainUsable {
+		err := keychainSetFn(key)
+		if err == nil {
+			// security -i can report success for a command that failed,
+			// so only a key that reads back counts as stored
+			var stored []byte
+			if stored, err = keychainGetFn(); err == nil && bytes.Equal(stored, key) {
+				return key, nil
+			}
+			if err == nil {
+				err = errors.New("stored key did not read back")
+			}
+		}
+		slog.Warn("could not store key in OS keychain; using key file", "error", err)
+	} else {
+		slog.Warn("no OS keychain available; storing encryption key in a file", "path", fallbackPath)
+	}
+
+	if err := writeKeyFile(fallbackPath, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// keychainGet reads the key from the OS keychain. Returns os.ErrNotExist
+// only when the keychain works and says it holds no key yet. Any other
+// failure, such as a locked keychain or a denied prompt, is an error:
+// taking it for a missing key would replace the key and lose everything
+// encrypted with it.
+func keychainGet() ([]byte, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", keychainAccount, "-w")
+	case "linux":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return nil, errNoKeychain
+		}
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "account", keychainAccount)
+	default:
+		return nil, errNoKeychain
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	encoded := strings.TrimSpace(string(out))
+	if keychainNotFound(runtime.GOOS, err, encoded, stderr.String()) {
+		return nil, os.ErrNotExist
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read key from OS keychain: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return decodeKey(encoded)
+}
+
+// securityItemNotFound is the exit status of security when the keychain
+// has no such item (errSecItemNotFound)
+const securityItemNotFound = 44
+
+// keychainNotFound reports whether a lookup's result means the keychain
+// has no key, as opposed to the lookup failing. security exits 44;
+// secret-tool prints nothing at all, exiting 0 or 1, and writes to
+// stderr only when something went wrong.
+func keychainNotFound(goos string, err error, out, stderr string) bool {
+	var exitErr *exec.ExitError
+	if goos == "darwin" {
+		return errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound
+	}
+	if out != "" || strings.TrimSpace(stderr) != "" {
+		return false
+	}
+	return err == nil || (errors.As(err, &exitErr) && exitErr.ExitCode() == 1)
+}
+
+func keychainSet(key []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(key)
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// In interactive mode security reads the command from stdin, so
+		// the key never shows up in argv where other users can see it.
+		// Without -U an existing key is never overwritten.
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -s %s -a %s -w %s\n", keychainService, keychainAccount, encoded))
+	case "linux":
+		cmd = exec.Command("secret-tool", "store", "--label=Temper storage key", "service", keychainService, "account", keychainAccount)
+		cmd.Stdin = strings.NewReader(encoded)
+	default:
+		return errNoKeychain
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func readKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeKey(strings.TrimSpace(string(data)))
+}
+
+func writeKeyFile(path string, key []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create key dir: %w", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(key) + "\n"
+	if err := os.WriteFile(path, []byte(encoded), 0600); err != nil {
+		return fmt.Errorf("write key file: %w", err)
+	}
+	return nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
@@ -0,0 +1,82 @@
+// Package vault encrypts data at rest with AES-256-GCM. The key lives in
+// the OS keychain (macOS Keychain, or the Secret Service on Linux via
+// secret-tool) and falls back to a 0600 key file when no keychain is
+// reachable. Like the rest of the daemon it shells out to platform tools
+// rather than linking a keychain library.
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the AES-256 key length in bytes
+const KeySize = 32
+
+// prefix marks an encrypted value. Values without it are plaintext written
+// before encryption was enabled and are returned unchanged.
+const prefix = "enc:v1:"
+
+var (
+	ErrInvalidKey = errors.New("vault: key must be 32 bytes")
+	ErrDecrypt    = errors.New("vault: cannot decrypt value (wrong key or corrupted data)")
+)
+
+// Cipher seals and opens strings. Safe for concurrent use.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher from a 32-byte key
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt seals plaintext under a fresh random nonce
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("vault: nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt. Plaintext values pass
+// through so existing data stays readable after encryption is turned on.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, prefix))
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted reports whether value was produced by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
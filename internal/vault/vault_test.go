@@ -0,0 +1,190 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c, err := NewCipher(testKey(t))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	for _, plaintext := range []string{"", "package main", strings.Repeat("秘密", 1000)} {
+		sealed, err := c.Encrypt(plaintext)
+		if err != nil {
+			t.Fatalf("Encrypt() error = %v", err)
+		}
+		if !IsEncrypted(sealed) {
+			t.Errorf("Encrypt() output not marked as encrypted: %q", sealed)
+		}
+		if plaintext != "" && strings.Contains(sealed, plaintext) {
+			t.Error("ciphertext contains the plaintext")
+		}
+		opened, err := c.Decrypt(sealed)
+		if err != nil {
+			t.Fatalf("Decrypt() error = %v", err)
+		}
+		if opened != plaintext {
+			t.Errorf("Decrypt() = %q; want %q", opened, plaintext)
+		}
+	}
+}
+
+func TestCipher_NonceIsRandom(t *testing.T) {
+	c, _ := NewCipher(testKey(t))
+	a, _ := c.Encrypt("same")
+	b, _ := c.Encrypt("same")
+	if a == b {
+		t.Error("encrypting twice should not produce identical output")
+	}
+}
+
+func TestCipher_PlaintextPassesThrough(t *testing.T) {
+	c, _ := NewCipher(testKey(t))
+	got, err := c.Decrypt("package main")
+	if err != nil || got != "package main" {
+		t.Errorf("Decrypt(plaintext) = %q, %v; want passthrough", got, err)
+	}
+}
+
+func TestCipher_WrongKey(t *testing.T) {
+	a, _ := NewCipher(testKey(t))
+	b, _ := NewCipher(testKey(t))
+
+	sealed, _ := a.Encrypt("secret")
+	if _, err := b.Decrypt(sealed); err != ErrDecrypt {
+		t.Errorf("Decrypt() with wrong key error = %v; want ErrDecrypt", err)
+	}
+	if _, err := a.Decrypt(prefix + "not-base64!"); err != ErrDecrypt {
+		t.Errorf("Decrypt() of garbage error = %v; want ErrDecrypt", err)
+	}
+}
+
+func TestNewCipher_InvalidKey(t *testing.T) {
+	if _, err := NewCipher([]byte("short")); err != ErrInvalidKey {
+		t.Errorf("NewCipher() error = %v; want ErrInvalidKey", err)
+	}
+}
+
+func stubKeychain(t *testing.T, get func() ([]byte, error), set func([]byte) error) {
+	t.Helper()
+	origGet, origSet := keychainGetFn, keychainSetFn
+	keychainGetFn, keychainSetFn = get, set
+	t.Cleanup(func() { keychainGetFn, keychainSetFn = origGet, origSet })
+}
+
+func TestLoadOrCreateKey_FileFallback(t *testing.T) {
+	stubKeychain(t,
+		func() ([]byte, error) { return nil, errNoKeychain },
+		func([]byte) error { t.Fatal("keychainSet called without a keychain"); return nil },
+	)
+	path := filepath.Join(t.TempDir(), "storage.key")
+
+	first, err := LoadOrCreateKey(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateKey() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("key file not written: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("key file mode = %v; want 0600", info.Mode().Perm())
+	}
+
+	second, err := LoadOrCreateKey(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateKey() second call error = %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("key should be stable across loads")
+	}
+}
+
+func TestLoadOrCreateKey_Keychain(t *testing.T) {
+	var stored []byte
+	stubKeychain(t,
+		func() ([]byte, error) {
+			if stored == nil {
+				return nil, os.ErrNotExist
+			}
+			return stored, nil
+		},
+		func(key []byte) error { stored = key; return nil },
+	)
+	path := filepath.Join(t.TempDir(), "storage.key")
+
+	key, err := LoadOrCreateKey(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateKey() error = %v", err)
+	}
+	if !bytes.Equal(key, stored) {
+		t.Error("generated key should be saved to the keychain")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("no key file should be written when the keychain works")
+	}
+}
+
+func TestLoadOrCreateKey_KeychainFailure(t *testing.T) {
+	stubKeychain(t,
+		func() ([]byte, error) {
+			return nil, errors.New("read key from OS keychain: exit status 51: user interaction is not allowed")
+		},
+		func([]byte) error { t.Fatal("keychainSet called after a failed lookup"); return nil },
+	)
+	path := filepath.Join(t.TempDir(), "storage.key")
+
+	if _, err := LoadOrCreateKey(path); err == nil {
+		t.Fatal("LoadOrCreateKey() should fail rather than replace a key it couldn't read")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("no key file should be written after a failed lookup")
+	}
+}
+
+func TestKeychainNotFound(t *testing.T) {
+	exit := func(code int) error {
+		return exec.Command("sh", "-c", fmt.Sprintf("exit %d", code)).Run()
+	}
+	tests := []struct {
+		name   string
+		goos   string
+		err    error
+		out    string
+		stderr string
+		want   bool
+	}{
+		{"security item not found", "darwin", exit(44), "", "", true},
+		{"security keychain locked", "darwin", exit(51), "", "user interaction is not allowed", false},
+		{"secret-tool empty", "linux", nil, "", "", true},
+		{"secret-tool no match", "linux", exit(1), "", "", true},
+		{"secret-tool no secret service", "linux", exit(1), "", "Cannot autolaunch D-Bus without X11 $DISPLAY", false},
+		{"secret-tool killed", "linux", exit(137), "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keychainNotFound(tt.goos, tt.err, tt.out, tt.stderr); got != tt.want {
+				t.Errorf("keychainNotFound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
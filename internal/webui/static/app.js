@@ -0,0 +1,210 @@
+// Temper dashboard: reads the daemon's API and renders it. Read-only.
+'use strict';
+
+const REFRESH_MS = 15000;
+const TOKEN_KEY = 'temper.token';
+
+class Unauthorized extends Error {}
+
+async function api(path) {
+  const headers = {};
+  const token = sessionStorage.getItem(TOKEN_KEY);
+  if (token) headers.Authorization = 'Bearer ' + token;
+
+  const res = await fetch('/v1' + path, { headers });
+  if (res.status === 401) throw new Unauthorized();
+  if (!res.ok) throw new Error(path + ': ' + res.status);
+  return res.json();
+}
+
+// Optional sections render as unavailable instead of failing the page
+async function optional(path) {
+  try {
+    return await api(path);
+  } catch (err) {
+    if (err instanceof Unauthorized) throw err;
+    return null;
+  }
+}
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs || {})) {
+    if (key === 'class') node.className = value;
+    else node.setAttribute(key, value);
+  }
+  for (const child of children) {
+    node.append(child instanceof Node ? child : String(child));
+  }
+  return node;
+}
+
+function svg(tag, attrs) {
+  const node = document.createElementNS('http://www.w3.org/2000/svg', tag);
+  for (const [key, value] of Object.entries(attrs || {})) node.setAttribute(key, value);
+  return node;
+}
+
+function percent(fraction) {
+  return Math.round((fraction || 0) * 100) + '%';
+}
+
+function ago(iso) {
+  if (!iso) return '—';
+  const seconds = Math.max(0, (Date.now() - new Date(iso).getTime()) / 1000);
+  if (seconds < 60) return 'just now';
+  if (seconds < 3600) return Math.floor(seconds / 60) + 'm ago';
+  if (seconds < 86400) return Math.floor(seconds / 3600) + 'h ago';
+  return Math.floor(seconds / 86400) + 'd ago';
+}
+
+function renderStatus(status) {
+  const node = document.getElementById('daemon-status');
+  node.className = 'ok';
+  const providers = (status.llm_providers || []).join(', ') || 'no providers';
+  node.textContent = 'Daemon ' + status.status + ' · v' + status.version + ' · runner ' + status.runner + ' · ' + providers;
+}
+
+function renderStats(data) {
+  const target = document.getElementById('stats');
+  if (!data) {
+    target.replaceChildren(el('p', { class: 'muted' }, 'Stats are unavailable.'));
+    return;
+  }
+  const o = data.overview || {};
+  const cards = [
+    ['Sessions', o.total_sessions || 0],
+    ['Completed', o.completed_sessions || 0],
+    ['Runs', o.total_runs || 0],
+    ['Hints', o.total_hints || 0],
+    ['Completion rate', percent(o.completion_rate)],
+    ['Hint dependency', percent(o.hint_dependency)],
+    ['Avg. time to green', o.avg_time_to_green || '—'],
+  ];
+  target.replaceChildren(...cards.map(([label, value]) =>
+    el('div', { class: 'card' }, el('div', { class: 'value' }, value), el('div', { class: 'label' }, label))));
+}
+
+function renderTrend(data) {
+  const target = document.getElementById('trend-chart');
+  const points = (data && data.trend) || [];
+  if (points.length < 2) {
+    target.replaceChildren(el('p', { class: 'muted' }, 'Not enough runs yet.'));
+    return;
+  }
+  const width = 400, height = 120, pad = 4;
+  const step = (width - 2 * pad) / (points.length - 1);
+  const coords = points.map((p, i) =>
+    (pad + i * step).toFixed(1) + ',' + (height - pad - Math.min(1, p.dependency) * (height - 2 * pad)).toFixed(1));
+
+  const chart = svg('svg', { viewBox: '0 0 ' + width + ' ' + height, role: 'img',
+    'aria-label': 'Hint dependency from ' + percent(points[0].dependency) + ' to ' + percent(points[points.length - 1].dependency) });
+  chart.append(svg('line', { class: 'axis', x1: pad, y1: height - pad, x2: width - pad, y2: height - pad }));
+  chart.append(svg('polyline', { class: 'line', points: coords.join(' ') }));
+  target.replaceChildren(chart);
+}
+
+function bar(name, fraction, label, done) {
+  const fill = el('div', { class: done ? 'fill done' : 'fill' });
+  fill.style.width = percent(Math.min(1, fraction));
+  return el('div', { class: 'bar' },
+    el('span', { class: 'name', title: name }, name),
+    el('div', { class: 'track' }, fill),
+    el('span', { class: 'muted' }, label));
+}
+
+function renderSkills(data) {
+  const target = document.getElementById('skills-chart');
+  const skills = Object.values((data && data.skills) || {}).sort((a, b) => b.level - a.level).slice(0, 10);
+  if (skills.length === 0) {
+    target.replaceChildren(el('p', { class: 'muted' }, 'No skills tracked yet.'));
+    return;
+  }
+  target.replaceChildren(...skills.map((s) => bar(s.topic, s.level, percent(s.level), s.level > 0.7)));
+}
+
+function renderSessions(data) {
+  const target = document.getElementById('sessions');
+  const sessions = (data && data.sessions) || [];
+  if (sessions.length === 0) {
+    target.replaceChildren(el('tr', {}, el('td', { colspan: 6, class: 'muted' }, 'No active sessions.')));
+    return;
+  }
+  target.replaceChildren(...sessions.map((s) => {
+    const last = s.last_run_at || s.updated_at;
+    return el('tr', {},
+      el('td', {}, el('code', {}, s.id.slice(0, 8))),
+      el('td', {}, s.exercise_id || s.spec_path || '—'),
+      el('td', {}, s.intent),
+      el('td', {}, s.run_count),
+      el('td', {}, s.hint_count),
+      el('td', { title: last }, ago(last)));
+  }));
+}
+
+function renderSpecs(data) {
+  const target = document.getElementById('specs');
+  if (!data) {
+    target.replaceChildren(el('p', { class: 'muted' }, 'Specs are unavailable.'));
+    return;
+  }
+  const specs = data.specs || [];
+  if (specs.length === 0) {
+    target.replaceChildren(el('p', { class: 'muted' }, 'No specs in this workspace.'));
+    return;
+  }
+  target.replaceChildren(...specs.map((sp) => {
+    const p = sp.progress || {};
+    const name = sp.name + (sp.version ? ' ' + sp.version : '');
+    return bar(name, (p.percent || 0) / 100, p.satisfied + '/' + p.total, p.total > 0 && p.satisfied === p.total);
+  }));
+}
+
+function showTokenForm() {
+  document.getElementById('dashboard').hidden = true;
+  document.getElementById('token-form').hidden = false;
+  const status = document.getElementById('daemon-status');
+  status.className = 'warn';
+  status.textContent = 'Token required';
+  document.getElementById('token').focus();
+}
+
+async function refresh() {
+  try {
+    const status = await api('/status');
+    const [overview, trend, skills, sessions, specs] = await Promise.all([
+      optional('/analytics/overview'),
+      optional('/analytics/trend'),
+      optional('/analytics/skills'),
+      optional('/sessions?status=active'),
+      optional('/specs'),
+    ]);
+    renderStatus(status);
+    renderStats(overview);
+    renderTrend(trend);
+    renderSkills(skills);
+    renderSessions(sessions);
+    renderSpecs(specs);
+    document.getElementById('token-form').hidden = true;
+    document.getElementById('dashboard').hidden = false;
+  } catch (err) {
+    if (err instanceof Unauthorized) {
+      showTokenForm();
+      return;
+    }
+    const status = document.getElementById('daemon-status');
+    status.className = 'error';
+    status.textContent = 'Daemon unreachable. Start it with: temper start';
+  }
+}
+
+document.getElementById('token-form').addEventListener('submit', (event) => {
+  event.preventDefault();
+  sessionStorage.setItem(TOKEN_KEY, document.getElementById('token').value.trim());
+  refresh();
+});
+
+refresh();
+setInterval(() => {
+  if (document.getElementById('token-form').hidden) refresh();
+}, REFRESH_MS);
@@ -0,0 +1,38 @@
+// Package webui is the read-only dashboard the daemon serves at /ui. Its
+// pages are static files embedded in the binary; they read the daemon's
+// API from the browser, so there is no build step and no server-side
+// rendering.
+package webui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// contentSecurityPolicy keeps the pages to their own scripts and styles
+// and the daemon's API
+const contentSecurityPolicy = "default-src 'none'; script-src 'self'; style-src 'self'; " +
+	"img-src 'self' data:; connect-src 'self'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+// Handler serves the dashboard's files relative to its root, so it is
+// mounted with http.StripPrefix. The files hold no data and need no
+// token; the pages send one with their API requests.
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // the directory is embedded above
+	}
+	fileServer := http.FileServer(http.FS(files))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", contentSecurityPolicy)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		// The files change with the binary; revalidate rather than guess
+		w.Header().Set("Cache-Control", "no-cache")
+		fileServer.ServeHTTP(w, r)
+	})
+}
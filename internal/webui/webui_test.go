@@ -0,0 +1,37 @@
+package webui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	h := Handler()
+
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+	}{
+		{"/", http.StatusOK, "text/html"},
+		{"/app.js", http.StatusOK, "javascript"},
+		{"/app.css", http.StatusOK, "text/css"},
+		{"/missing.js", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.path, rec.Code, tt.status)
+			continue
+		}
+		if !strings.Contains(rec.Header().Get("Content-Type"), tt.contentType) {
+			t.Errorf("%s: Content-Type = %q, want %s", tt.path, rec.Header().Get("Content-Type"), tt.contentType)
+		}
+		if csp := rec.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "script-src 'self'") {
+			t.Errorf("%s: Content-Security-Policy = %q", tt.path, csp)
+		}
+	}
+}